@@ -23,11 +23,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/pprof"
 	"os"
+	"reflect"
 	"strings"
-
-	// Installs pprof profiling debug endpoints at /debug/pprof.
-	_ "net/http/pprof"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
@@ -38,6 +38,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	kubeclientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/component-base/logs"
@@ -49,15 +50,36 @@ import (
 	corev1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/autoenablepolicy"
+	"sigs.k8s.io/kubefed/pkg/controller/backfill"
+	"sigs.k8s.io/kubefed/pkg/controller/blueprint"
+	"sigs.k8s.io/kubefed/pkg/controller/clusterjoinrequest"
+	"sigs.k8s.io/kubefed/pkg/controller/clusterrecords"
 	"sigs.k8s.io/kubefed/pkg/controller/dnsendpoint"
+	"sigs.k8s.io/kubefed/pkg/controller/endpointmirror"
+	"sigs.k8s.io/kubefed/pkg/controller/federatedapplication"
 	"sigs.k8s.io/kubefed/pkg/controller/federatedtypeconfig"
 	"sigs.k8s.io/kubefed/pkg/controller/ingressdns"
 	"sigs.k8s.io/kubefed/pkg/controller/kubefedcluster"
+	"sigs.k8s.io/kubefed/pkg/controller/namespaceprofile"
+	"sigs.k8s.io/kubefed/pkg/controller/namespaceprojection"
+	"sigs.k8s.io/kubefed/pkg/controller/namespacesameness"
+	"sigs.k8s.io/kubefed/pkg/controller/probe"
+	"sigs.k8s.io/kubefed/pkg/controller/pullsecret"
+	"sigs.k8s.io/kubefed/pkg/controller/quarantine"
 	"sigs.k8s.io/kubefed/pkg/controller/schedulingmanager"
 	"sigs.k8s.io/kubefed/pkg/controller/servicedns"
+	"sigs.k8s.io/kubefed/pkg/controller/serviceimport"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/secretprovider"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook"
+	"sigs.k8s.io/kubefed/pkg/dashboard"
 	"sigs.k8s.io/kubefed/pkg/features"
+	"sigs.k8s.io/kubefed/pkg/logging"
 	kubefedmetrics "sigs.k8s.io/kubefed/pkg/metrics"
+	"sigs.k8s.io/kubefed/pkg/placementapi"
+	"sigs.k8s.io/kubefed/pkg/simulation"
+	"sigs.k8s.io/kubefed/pkg/tracing"
 	"sigs.k8s.io/kubefed/pkg/version"
 )
 
@@ -67,7 +89,19 @@ const (
 )
 
 var (
-	kubeconfig, kubeFedConfig, masterURL, metricsAddr, healthzAddr string
+	kubeconfig, kubeFedConfig, masterURL, metricsAddr, healthzAddr, debugAddr, debugCertFile, debugKeyFile, placementAPIAddr, placementAPICertFile, placementAPIKeyFile, dashboardAddr, tracingEndpoint string
+
+	tracingSampleRatio float64
+
+	csiSecretsDir, vaultAddr, vaultRole, vaultAuthPath, vaultCAFile string
+
+	// The outbound integrations that are configured but disabled by
+	// the compliance mode.
+	disabledIntegrations []string
+
+	dashboardRefreshInterval, propagationProbeInterval time.Duration
+
+	simulatedClusters int
 )
 
 // NewControllerManagerCommand creates a *cobra.Command object with default parameters
@@ -100,6 +134,23 @@ member clusters and do the necessary reconciliation`,
 	opts.AddFlags(cmd.Flags())
 	cmd.Flags().StringVar(&healthzAddr, "healthz-addr", healthzDefaultBindAddress, "The address the healthz endpoint binds to.")
 	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", metricsDefaultBindAddress, "The address the metric endpoint binds to.")
+	cmd.Flags().StringVar(&debugAddr, "debug-addr", "", "The address the pprof, queue and informer sync debug endpoints bind to. The endpoints are disabled if empty.")
+	cmd.Flags().StringVar(&debugCertFile, "debug-tls-cert-file", "", "The path of the certificate the debug endpoints are served with. The endpoints may only bind to a loopback address if not provided.")
+	cmd.Flags().StringVar(&debugKeyFile, "debug-tls-private-key-file", "", "The path of the private key matching --debug-tls-cert-file.")
+	cmd.Flags().StringVar(&placementAPIAddr, "placement-api-addr", "", "The address the placement API binds to. The API is disabled if empty.")
+	cmd.Flags().StringVar(&placementAPICertFile, "placement-api-tls-cert-file", "", "The path of the certificate the placement API is served with. The API may only bind to a loopback address if not provided.")
+	cmd.Flags().StringVar(&placementAPIKeyFile, "placement-api-tls-private-key-file", "", "The path of the private key matching --placement-api-tls-cert-file.")
+	cmd.Flags().StringVar(&dashboardAddr, "dashboard-addr", "", "The address the read-only dashboard summary endpoints bind to. The endpoints are disabled if empty.")
+	cmd.Flags().DurationVar(&dashboardRefreshInterval, "dashboard-refresh-interval", 30*time.Second, "How often the dashboard summary is collected.")
+	cmd.Flags().DurationVar(&propagationProbeInterval, "propagation-probe-interval", time.Minute, "How often the propagation probe is updated when the PropagationProbe feature is enabled.")
+	cmd.Flags().StringVar(&tracingEndpoint, "tracing-endpoint", "", "The base URL of an OTLP/HTTP receiver to export reconcile traces to, e.g. http://otel-collector:4318. Tracing is disabled if empty.")
+	cmd.Flags().Float64Var(&tracingSampleRatio, "tracing-sample-ratio", 1, "The fraction of reconciles that are traced when tracing is enabled.")
+	cmd.Flags().StringVar(&csiSecretsDir, "csi-secrets-dir", secretprovider.DefaultCSIDirectory, "The directory the Secrets Store CSI driver mounts the tokens of member clusters referencing the csi secret provider in.")
+	cmd.Flags().StringVar(&vaultAddr, "vault-addr", "", "The address of the Vault server the tokens of member clusters referencing the vault secret provider are read from. The vault secret provider is disabled if empty.")
+	cmd.Flags().StringVar(&vaultRole, "vault-role", "", "The role to log in to Vault as with the Kubernetes auth method. The token in the VAULT_TOKEN environment variable is used if empty.")
+	cmd.Flags().StringVar(&vaultAuthPath, "vault-auth-path", secretprovider.DefaultVaultAuthPath, "The path the Kubernetes auth method of Vault is mounted at.")
+	cmd.Flags().StringVar(&vaultCAFile, "vault-ca-file", "", "The path of the CA certificate used to verify the Vault server.")
+	cmd.Flags().IntVar(&simulatedClusters, "simulated-clusters", 0, "The number of member clusters to simulate with in-process API servers. For development only: the etcd and kube-apiserver binaries must be available via KUBEBUILDER_ASSETS.")
 	cmd.Flags().BoolVar(&verFlag, "version", false, "Prints the Version info of controller-manager.")
 	cmd.Flags().StringVar(&kubeFedConfig, "kubefed-config", "", "Path to a KubeFedConfig yaml file. Test only.")
 	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
@@ -108,6 +159,70 @@ member clusters and do the necessary reconciliation`,
 	return cmd
 }
 
+// startOutboundIntegrations starts the configured integrations that
+// call services other than the API servers of the host and member
+// clusters. In the restricted compliance mode the integrations are not
+// started and are recorded as disabled instead.
+func startOutboundIntegrations(stopChan <-chan struct{}) error {
+	if len(tracingEndpoint) > 0 {
+		if util.RestrictedCompliance() {
+			klog.Warningf("Tracing is disabled in the restricted compliance mode")
+			disabledIntegrations = append(disabledIntegrations, "tracing")
+		} else {
+			err := tracing.Start(tracing.Config{
+				Endpoint:    tracingEndpoint,
+				ServiceName: "kubefed-controller-manager",
+				SampleRatio: tracingSampleRatio,
+			}, stopChan)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return registerSecretProviders()
+}
+
+// registerSecretProviders registers the external secret providers
+// that member clusters may reference for their tokens.
+func registerSecretProviders() error {
+	secretprovider.Register("csi", secretprovider.NewCSIProvider(csiSecretsDir))
+	if len(vaultAddr) == 0 {
+		return nil
+	}
+	if util.RestrictedCompliance() {
+		klog.Warningf("The vault secret provider is disabled in the restricted compliance mode")
+		disabledIntegrations = append(disabledIntegrations, "vault")
+		return nil
+	}
+	provider, err := secretprovider.NewVaultProvider(secretprovider.VaultConfig{
+		Address:  vaultAddr,
+		Role:     vaultRole,
+		AuthPath: vaultAuthPath,
+		CAFile:   vaultCAFile,
+	})
+	if err != nil {
+		return err
+	}
+	secretprovider.Register("vault", provider)
+	return nil
+}
+
+// newPropagationNotifier returns the notifier posting to the given
+// notification sinks. In the restricted compliance mode the sinks are
+// not called and notifications are recorded as disabled instead.
+func newPropagationNotifier(sinks []corev1b1.NotificationSink) *util.PropagationNotifier {
+	if len(sinks) > 0 && util.RestrictedCompliance() {
+		klog.Warningf("Notification sinks are disabled in the restricted compliance mode")
+		disabledIntegrations = append(disabledIntegrations, "notifications")
+		return nil
+	}
+	notifier, err := util.NewPropagationNotifier(sinks)
+	if err != nil {
+		klog.Fatalf("Error configuring notification sinks: %v", err)
+	}
+	return notifier
+}
+
 // Run runs the controller-manager with options. This should never exit.
 func Run(opts *options.Options, stopChan <-chan struct{}) error {
 	logs.InitLogs()
@@ -124,6 +239,10 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 		panic(err)
 	}
 
+	if len(debugAddr) > 0 {
+		go serveDebug(debugAddr, debugCertFile, debugKeyFile, kubeclientset.NewForConfigOrDie(rest.AddUserAgent(opts.Config.KubeConfig, "debug-endpoints")))
+	}
+
 	runningInCluster := len(masterURL) == 0 && len(kubeconfig) == 0
 	if runningInCluster && len(opts.Config.KubeFedNamespace) == 0 {
 		// For in-cluster deployment set the namespace associated
@@ -142,6 +261,25 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 
 	setOptionsByKubeFedConfig(opts)
 
+	// Outbound integrations are only started once the compliance mode
+	// is known.
+	if err := startOutboundIntegrations(stopChan); err != nil {
+		return err
+	}
+
+	if len(placementAPIAddr) > 0 {
+		server := placementapi.NewServer(opts.Config.KubeConfig, opts.Config.KubeFedNamespace)
+		go server.Serve(placementAPIAddr, placementAPICertFile, placementAPIKeyFile, stopChan)
+	}
+
+	if simulatedClusters > 0 {
+		clusters, err := simulation.StartClusters(opts.Config.KubeConfig, opts.Config.KubeFedNamespace, simulatedClusters)
+		if err != nil {
+			klog.Fatalf("Error starting simulated clusters: %v", err)
+		}
+		defer clusters.Stop()
+	}
+
 	if err := utilfeature.DefaultMutableFeatureGate.SetFromMap(opts.FeatureGates); err != nil {
 		klog.Fatalf("Invalid Feature Gate: %v", err)
 	}
@@ -154,6 +292,21 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 		klog.Info("KubeFed will target all namespaces")
 	}
 
+	if len(dashboardAddr) > 0 {
+		server, err := dashboard.NewServer(opts.Config, dashboardRefreshInterval)
+		if err != nil {
+			klog.Fatalf("Error creating the dashboard server: %v", err)
+		}
+		go server.Run(dashboardAddr, stopChan)
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.ControlPlaneInstances) {
+		if err := registerKubeFedInstance(opts.Config); err != nil {
+			klog.Fatalf("Error registering KubeFedInstance: %v", err)
+		}
+		opts.Config.InstanceName = opts.Config.KubeFedNamespace
+	}
+
 	elector, err := leaderelection.NewKubeFedLeaderElector(opts, startControllers)
 	if err != nil {
 		panic(err)
@@ -177,10 +330,39 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 }
 
 func startControllers(opts *options.Options, stopChan <-chan struct{}) {
+	recordControllerStatus(opts.Config)
+
+	// Faults are only injected by the clients for member clusters
+	// that are created after the injector is started.
+	if utilfeature.DefaultFeatureGate.Enabled(features.FaultInjection) {
+		if err := util.StartFaultInjector(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting fault injector: %v", err)
+		}
+	}
+
+	// The discovery, transport and schema caches must be created before the
+	// cluster controller, which maintains them, and the controllers
+	// that create clients for member clusters.
+	if utilfeature.DefaultFeatureGate.Enabled(features.DiscoveryCache) {
+		opts.Config.DiscoveryCache = util.NewClusterDiscoveryCache()
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.SharedClusterTransport) {
+		opts.Config.ClusterTransports = util.NewClusterTransportCache()
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.SchemaAwareComparison) {
+		opts.Config.SchemaCache = util.NewClusterSchemaCache()
+	}
+
 	if err := kubefedcluster.StartClusterController(opts.Config, opts.ClusterHealthCheckConfig, stopChan); err != nil {
 		klog.Fatalf("Error starting cluster controller: %v", err)
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.ClusterJoinRequests) {
+		if err := clusterjoinrequest.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting cluster join request controller: %v", err)
+		}
+	}
+
 	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerPreferences) {
 		if _, err := schedulingmanager.StartSchedulingManager(opts.Config, stopChan); err != nil {
 			klog.Fatalf("Error starting scheduling manager: %v", err)
@@ -197,6 +379,18 @@ func startControllers(opts *options.Options, stopChan <-chan struct{}) {
 		}
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.MultiClusterServices) {
+		if err := serviceimport.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting service import controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.CrossClusterEndpoints) {
+		if err := endpointmirror.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting endpoint mirror controller: %v", err)
+		}
+	}
+
 	if utilfeature.DefaultFeatureGate.Enabled(features.FederatedIngress) {
 		if err := ingressdns.StartController(opts.Config, stopChan); err != nil {
 			klog.Fatalf("Error starting ingress dns controller: %v", err)
@@ -207,11 +401,89 @@ func startControllers(opts *options.Options, stopChan <-chan struct{}) {
 		}
 	}
 
+	// The monitor must be started before the sync controllers so
+	// that they report the outcome of their applies to it.
+	if utilfeature.DefaultFeatureGate.Enabled(features.ClusterQuarantine) {
+		monitor, err := quarantine.StartMonitor(opts.Config, stopChan)
+		if err != nil {
+			klog.Fatalf("Error starting cluster quarantine monitor: %v", err)
+		}
+		opts.Config.ApplyObserver = monitor
+	}
+
 	if utilfeature.DefaultFeatureGate.Enabled(features.PushReconciler) {
 		if err := federatedtypeconfig.StartController(opts.Config, stopChan); err != nil {
 			klog.Fatalf("Error starting federated type config controller: %v", err)
 		}
 	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.FederatedApplications) {
+		if err := federatedapplication.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting federated application controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.ClusterBackfill) {
+		if err := backfill.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting cluster backfill controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.PullSecretReplication) {
+		if err := pullsecret.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting pull secret replication controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.NamespaceProfiles) {
+		if err := namespaceprofile.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting namespace profile controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.Blueprints) {
+		if err := blueprint.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting blueprint controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.NamespaceSameness) {
+		if opts.Config.LimitedScope() {
+			klog.Warningf("Namespace sameness is not verified by a namespace-scoped control plane")
+		} else if err := namespacesameness.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting namespace sameness controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.NamespaceProjection) {
+		if opts.Config.LimitedScope() {
+			klog.Warningf("Federated resources are not projected into namespaces by a namespace-scoped control plane")
+		} else if err := namespaceprojection.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting namespace projection controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.StaleClusterRecordCleanup) {
+		if err := clusterrecords.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting stale cluster record cleanup controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.AutoEnablePolicies) {
+		if opts.Config.LimitedScope() {
+			klog.Warningf("AutoEnablePolicies are not honored by a namespace-scoped control plane")
+		} else if err := autoenablepolicy.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting auto-enable policy controller: %v", err)
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.PropagationProbe) {
+		if opts.Config.LimitedScope() {
+			klog.Warningf("The propagation probe is not supported by a namespace-scoped control plane")
+		} else if err := probe.StartController(opts.Config, propagationProbeInterval, stopChan); err != nil {
+			klog.Fatalf("Error starting propagation probe controller: %v", err)
+		}
+	}
 }
 
 func getKubeFedConfig(opts *options.Options) *corev1b1.KubeFedConfig {
@@ -321,6 +593,102 @@ func deleteKubeFedConfig(config *rest.Config, fedConfig *corev1b1.KubeFedConfig)
 	}
 }
 
+// recordControllerStatus records the version and the compliance
+// posture of the controller manager in the status of the KubeFedConfig.
+// kubefedctl compares the version with its own to detect version skew
+// with the control plane. A failure to record the status should not
+// prevent the controllers from running.
+func recordControllerStatus(config *util.ControllerConfig) {
+	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, "kubefedconfig")
+	qualifiedName := util.QualifiedName{
+		Namespace: config.KubeFedNamespace,
+		Name:      util.KubeFedConfigName,
+	}
+	fedConfig := &corev1b1.KubeFedConfig{}
+	err := client.Get(context.TODO(), fedConfig, qualifiedName.Namespace, qualifiedName.Name)
+	if err != nil {
+		klog.Errorf("Error retrieving KubeFedConfig %q to record the controller status: %v", qualifiedName, err)
+		return
+	}
+
+	controllerVersion := version.Get().Version
+	compliance := util.NewComplianceStatus(disabledIntegrations)
+	if fedConfig.Status.ControllerVersion == controllerVersion && complianceStatusEqual(fedConfig.Status.Compliance, compliance) {
+		return
+	}
+	fedConfig.Status.ControllerVersion = controllerVersion
+	fedConfig.Status.Compliance = compliance
+	err = client.UpdateStatus(context.TODO(), fedConfig)
+	if err != nil {
+		klog.Errorf("Error recording the controller status in KubeFedConfig %q: %v", qualifiedName, err)
+		return
+	}
+	klog.Infof("Recorded controller version %q and compliance mode %q in KubeFedConfig %q", controllerVersion, compliance.Mode, qualifiedName)
+}
+
+// complianceStatusEqual returns whether the given compliance postures
+// are equal regardless of when they were recorded.
+func complianceStatusEqual(a, b *corev1b1.ComplianceStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	a, b = a.DeepCopy(), b.DeepCopy()
+	a.LastUpdateTime, b.LastUpdateTime = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// registerKubeFedInstance claims the target namespace of the control
+// plane by registering a KubeFedInstance named for its KubeFed
+// namespace. Registration fails if the target namespace overlaps with
+// that of another control plane of the host cluster.
+func registerKubeFedInstance(config *util.ControllerConfig) error {
+	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, "kubefedinstance")
+	instances := &corev1b1.KubeFedInstanceList{}
+	err := client.List(context.TODO(), instances, "")
+	if err != nil {
+		return fmt.Errorf("Failed to list KubeFedInstances: %v", err)
+	}
+
+	instance := &corev1b1.KubeFedInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: config.KubeFedNamespace,
+		},
+		Spec: corev1b1.KubeFedInstanceSpec{
+			KubeFedNamespace: config.KubeFedNamespace,
+			TargetNamespace:  config.TargetNamespace,
+		},
+	}
+	// The admission webhook performs the same check, but its failure
+	// policy may be configured to ignore failures.
+	if errs := validation.ValidateKubeFedInstance(instance, instances.Items); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	for i := range instances.Items {
+		existing := &instances.Items[i]
+		if existing.Name != instance.Name {
+			continue
+		}
+		if existing.Spec == instance.Spec {
+			return nil
+		}
+		existing.Spec = instance.Spec
+		err = client.Update(context.TODO(), existing)
+		if err != nil {
+			return fmt.Errorf("Failed to update KubeFedInstance %q: %v", instance.Name, err)
+		}
+		klog.Infof("Updated KubeFedInstance %q", instance.Name)
+		return nil
+	}
+
+	err = client.Create(context.TODO(), instance)
+	if err != nil {
+		return fmt.Errorf("Failed to create KubeFedInstance %q: %v", instance.Name, err)
+	}
+	klog.Infof("Registered KubeFedInstance %q", instance.Name)
+	return nil
+}
+
 func setOptionsByKubeFedConfig(opts *options.Options) {
 	fedConfig := getKubeFedConfig(opts)
 	if fedConfig == nil {
@@ -379,9 +747,69 @@ func setOptionsByKubeFedConfig(opts *options.Options) {
 	opts.ClusterHealthCheckConfig.Period = spec.ClusterHealthCheck.Period.Duration
 	opts.ClusterHealthCheckConfig.Timeout = spec.ClusterHealthCheck.Timeout.Duration
 	opts.ClusterHealthCheckConfig.FailureThreshold = *spec.ClusterHealthCheck.FailureThreshold
+	if spec.ClusterHealthCheck.EdgeFailureThreshold != nil {
+		opts.ClusterHealthCheckConfig.EdgeFailureThreshold = *spec.ClusterHealthCheck.EdgeFailureThreshold
+	}
 	opts.ClusterHealthCheckConfig.SuccessThreshold = *spec.ClusterHealthCheck.SuccessThreshold
+	if spec.ClusterHealthCheck.Deadline != nil {
+		opts.ClusterHealthCheckConfig.Deadline = spec.ClusterHealthCheck.Deadline.Duration
+	}
+	if spec.ClusterHealthCheck.Workers != nil {
+		opts.ClusterHealthCheckConfig.Workers = *spec.ClusterHealthCheck.Workers
+	}
+	if spec.ClusterHealthCheck.JitterPercentage != nil {
+		opts.ClusterHealthCheckConfig.JitterPercentage = *spec.ClusterHealthCheck.JitterPercentage
+	}
+
+	// The compliance mode is set first since it determines which
+	// outbound integrations may be configured.
+	complianceMode := corev1b1.ComplianceModeStandard
+	if spec.Compliance != nil && spec.Compliance.Mode != nil {
+		complianceMode = *spec.Compliance.Mode
+	}
+	util.SetComplianceMode(complianceMode)
+	klog.Infof("KubeFed will run in the %q compliance mode", complianceMode)
 
 	opts.Config.SkipAdoptingResources = *spec.SyncController.AdoptResources == corev1b1.AdoptResourcesDisabled
+	opts.Config.ClusterApplyLimiter = util.NewClusterApplyLimiter(spec.SyncController.ClusterApplyRateLimit)
+	if spec.SyncController.ClusterOperationTimeout != nil {
+		opts.Config.ClusterOperationTimeout = spec.SyncController.ClusterOperationTimeout.Duration
+	}
+	if spec.SyncController.DebounceWindow != nil {
+		opts.Config.DebounceWindow = spec.SyncController.DebounceWindow.Duration
+	}
+	if spec.SyncController.DeletionHold != nil {
+		opts.Config.DeletionHold = spec.SyncController.DeletionHold.Duration
+	}
+	opts.Config.ImpersonateServiceAccount = spec.SyncController.ImpersonateServiceAccount
+	opts.Config.PropagatedMetadata = spec.SyncController.PropagatedMetadata
+	opts.Config.PropagationNotifier = newPropagationNotifier(spec.SyncController.NotificationSinks)
+	opts.Config.Quarantine = spec.SyncController.Quarantine
+	if spec.SyncController.RevisionHistoryLimit != nil {
+		opts.Config.RevisionHistoryLimit = int(*spec.SyncController.RevisionHistoryLimit)
+	}
+	if spec.SyncController.SlowClusterThreshold != nil {
+		opts.Config.ClusterLatency = util.NewClusterLatencyTracker(spec.SyncController.SlowClusterThreshold.Duration)
+	}
+	if spec.StatusController != nil {
+		opts.Config.StatusCollection = spec.StatusController.AdaptiveCollection
+	}
+	opts.Config.NamespaceSameness = spec.NamespaceSameness
+
+	logFormat := corev1b1.LogFormatText
+	if spec.Logging != nil && spec.Logging.Format != nil {
+		logFormat = *spec.Logging.Format
+	}
+	if err := logging.SetFormat(logging.Format(logFormat)); err != nil {
+		klog.Fatalf("Error: %v", err)
+	}
+
+	// A failure to configure the webhooks should not prevent the
+	// controllers from running.
+	kubeClient := kubeclientset.NewForConfigOrDie(rest.AddUserAgent(opts.Config.KubeConfig, "kubefedconfig"))
+	if err := webhook.ApplyConfig(kubeClient, spec.Scope, opts.Config.KubeFedNamespace, spec.Webhook); err != nil {
+		klog.Errorf("Error configuring admission webhooks: %v", err)
+	}
 
 	var featureGates = make(map[string]bool)
 	for _, v := range fedConfig.Spec.FeatureGates {
@@ -403,12 +831,31 @@ func PrintFlags(flags *pflag.FlagSet) {
 }
 
 func serveHealthz(address string) {
-	http.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
 	})
 
-	klog.Fatal(http.ListenAndServe(address, nil))
+	klog.Fatal(http.ListenAndServe(address, mux))
+}
+
+// serveDebug serves pprof profiles along with the queue and informer
+// sync status of the running controllers. Requests are authenticated
+// and authorized by the API server of the host cluster.
+func serveDebug(address, certFile, keyFile string, client kubeclientset.Interface) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	util.RegisterDebugHandlers(mux)
+
+	err := util.ServeDebug(address, certFile, keyFile, util.AuthorizeDebugRequests(client, mux))
+	if err != nil && err != http.ErrServerClosed {
+		klog.Fatalf("Error serving the debug endpoints: %v", err)
+	}
 }
 
 func serveMetrics(address string, stop <-chan struct{}) {
@@ -39,6 +39,7 @@ import (
 
 	"sigs.k8s.io/kubefed/cmd/controller-manager/app"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl"
+	"sigs.k8s.io/kubefed/pkg/operator"
 	"sigs.k8s.io/kubefed/pkg/webhook"
 )
 
@@ -83,11 +84,13 @@ func NewHyperFedCommand() (*cobra.Command, []func() *cobra.Command) {
 	controller := func() *cobra.Command { return app.NewControllerManagerCommand(stopChan) }
 	kubefedctlCmd := func() *cobra.Command { return kubefedctl.NewKubeFedCtlCommand(os.Stdout) }
 	webhookCmd := func() *cobra.Command { return webhook.NewWebhookCommand(stopChan) }
+	operatorCmd := func() *cobra.Command { return operator.NewOperatorCommand(stopChan) }
 
 	commandFns := []func() *cobra.Command{
 		controller,
 		kubefedctlCmd,
 		webhookCmd,
+		operatorCmd,
 	}
 
 	makeSymlinksFlag := false
@@ -5,6 +5,8 @@ go 1.13
 require (
 	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/ghodss/yaml v1.0.0
+	github.com/go-logr/logr v0.1.0
+	github.com/googleapis/gnostic v0.3.1
 	github.com/json-iterator/go v1.1.9
 	github.com/onsi/ginkgo v1.12.0
 	github.com/onsi/gomega v1.9.0
@@ -18,6 +18,8 @@ package typeconfig
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 )
 
 // Interface defines how to interact with a FederatedTypeConfig
@@ -26,9 +28,15 @@ type Interface interface {
 	GetTargetType() metav1.APIResource
 	GetNamespaced() bool
 	GetPropagationEnabled() bool
+	GetPropagationCreateOnly() bool
+	GetConflictResolution() *v1beta1.ConflictResolution
 	GetFederatedType() metav1.APIResource
 	GetStatusType() *metav1.APIResource
 	GetStatusEnabled() bool
+	GetStatusFields() []string
+	GetHealthInterpreter() *v1beta1.HealthInterpreter
+	GetDispatchMutators() []v1beta1.DispatchMutatorConfig
+	GetPropagationWebhooks() []v1beta1.PropagationWebhook
 	GetFederatedNamespaced() bool
 	IsNamespace() bool
 }
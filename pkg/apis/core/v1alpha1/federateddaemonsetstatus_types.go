@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DaemonSetNodeCounts are the node counts of a DaemonSet in a member
+// cluster.
+type DaemonSetNodeCounts struct {
+	// The number of nodes that should be running the daemon pod.
+	// +optional
+	DesiredNumberScheduled int32 `json:"desiredNumberScheduled,omitempty"`
+	// The number of nodes that are running at least one daemon pod.
+	// +optional
+	CurrentNumberScheduled int32 `json:"currentNumberScheduled,omitempty"`
+	// The number of nodes that are running the updated daemon pod.
+	// +optional
+	UpdatedNumberScheduled int32 `json:"updatedNumberScheduled,omitempty"`
+	// The number of nodes that have the daemon pod running and ready.
+	// +optional
+	NumberReady int32 `json:"numberReady,omitempty"`
+	// The number of nodes that have the daemon pod running and
+	// available.
+	// +optional
+	NumberAvailable int32 `json:"numberAvailable,omitempty"`
+}
+
+// FederatedDaemonSetClusterStatus is the observed status of the resource for a named cluster
+type FederatedDaemonSetClusterStatus struct {
+	ClusterName string              `json:"clusterName"`
+	Status      DaemonSetNodeCounts `json:"status"`
+	// Health of the daemonset in the cluster, if the
+	// HealthInterpretation feature is enabled.
+	// +optional
+	Health string `json:"health,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=federateddaemonsetstatuses
+
+type FederatedDaemonSetStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	ClusterStatus []FederatedDaemonSetClusterStatus `json:"clusterStatus,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FederatedDaemonSetStatusList contains a list of FederatedDaemonSetStatus
+type FederatedDaemonSetStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FederatedDaemonSetStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FederatedDaemonSetStatus{}, &FederatedDaemonSetStatusList{})
+}
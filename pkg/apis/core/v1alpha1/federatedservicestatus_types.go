@@ -25,6 +25,10 @@ import (
 type FederatedServiceClusterStatus struct {
 	ClusterName string               `json:"clusterName"`
 	Status      corev1.ServiceStatus `json:"status"`
+	// Health of the service in the cluster, if the
+	// HealthInterpretation feature is enabled.
+	// +optional
+	Health string `json:"health,omitempty"`
 }
 
 // +kubebuilder:object:root=true
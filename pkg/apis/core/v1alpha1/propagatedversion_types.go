@@ -41,6 +41,16 @@ type ClusterObjectVersion struct {
 	// The last version produced for the resource by a KubeFed
 	// operation.
 	Version string `json:"version"`
+	// The name of the resource in the cluster, qualified by its
+	// namespace, if it differs from the name of the federated
+	// resource.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+	// Whether the version of the resource in the cluster is pinned.
+	// The resource in a pinned cluster is neither updated nor removed
+	// and its version is retained when the resource changes.
+	// +optional
+	Pinned bool `json:"pinned,omitempty"`
 }
 
 // +kubebuilder:object:root=true
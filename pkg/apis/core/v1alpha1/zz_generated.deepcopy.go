@@ -112,6 +112,99 @@ func (in *ClusterPropagatedVersionSpec) DeepCopy() *ClusterPropagatedVersionSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetNodeCounts) DeepCopyInto(out *DaemonSetNodeCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetNodeCounts.
+func (in *DaemonSetNodeCounts) DeepCopy() *DaemonSetNodeCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetNodeCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedDaemonSetClusterStatus) DeepCopyInto(out *FederatedDaemonSetClusterStatus) {
+	*out = *in
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedDaemonSetClusterStatus.
+func (in *FederatedDaemonSetClusterStatus) DeepCopy() *FederatedDaemonSetClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedDaemonSetClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedDaemonSetStatus) DeepCopyInto(out *FederatedDaemonSetStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.ClusterStatus != nil {
+		in, out := &in.ClusterStatus, &out.ClusterStatus
+		*out = make([]FederatedDaemonSetClusterStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedDaemonSetStatus.
+func (in *FederatedDaemonSetStatus) DeepCopy() *FederatedDaemonSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedDaemonSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedDaemonSetStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedDaemonSetStatusList) DeepCopyInto(out *FederatedDaemonSetStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FederatedDaemonSetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedDaemonSetStatusList.
+func (in *FederatedDaemonSetStatusList) DeepCopy() *FederatedDaemonSetStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedDaemonSetStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedDaemonSetStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FederatedServiceClusterStatus) DeepCopyInto(out *FederatedServiceClusterStatus) {
 	*out = *in
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AutoEnablePolicySpec defines the CRDs whose propagation is enabled
+// by an AutoEnablePolicy.
+type AutoEnablePolicySpec struct {
+	// GroupSuffixes selects the CRDs whose API group is one of the
+	// given suffixes or a subdomain of one (e.g. mycompany.io selects
+	// the CRDs of both mycompany.io and apps.mycompany.io).
+	// +optional
+	GroupSuffixes []string `json:"groupSuffixes,omitempty"`
+
+	// Selector selects the CRDs by their labels. A CRD must match both
+	// the group suffixes and the selector if both are specified.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// The name of the API group to use for the generated federated
+	// types. Defaults to types.kubefed.io.
+	// +optional
+	FederatedGroup string `json:"federatedGroup,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=autoenablepolicies
+
+// AutoEnablePolicy enables propagation of the CRDs of the host cluster
+// that it selects as they are created, as if `kubefedctl enable` had
+// been run for each of them. AutoEnablePolicies are only honored when
+// the AutoEnablePolicies feature gate is enabled.
+type AutoEnablePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AutoEnablePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// AutoEnablePolicyList contains a list of AutoEnablePolicy
+type AutoEnablePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AutoEnablePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AutoEnablePolicy{}, &AutoEnablePolicyList{})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// BlueprintSpec defines a parameterized set of federated resources.
+type BlueprintSpec struct {
+	// Parameters that instances of the blueprint provide.
+	// +optional
+	Parameters []BlueprintParameter `json:"parameters,omitempty"`
+
+	// Federated resources created in the namespace of each instance of
+	// the blueprint. Each resource must provide its apiVersion, kind
+	// and name, and its type must be a namespaced federated type
+	// enabled for propagation. String values, including the name, may
+	// reference parameters as $(name), which are replaced with the
+	// values given by the instance.
+	Resources []runtime.RawExtension `json:"resources"`
+}
+
+// BlueprintParameter declares a parameter of a Blueprint and the
+// values it accepts.
+type BlueprintParameter struct {
+	// Name of the parameter.
+	Name string `json:"name"`
+
+	// Description of the parameter for the users of the blueprint.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Type of the parameter. Supported options are `string`
+	// (default), `integer` and `boolean`. A string value of a resource
+	// that only references an integer or boolean parameter is replaced
+	// with a value of that type.
+	// +optional
+	Type BlueprintParameterType `json:"type,omitempty"`
+
+	// Value of the parameter for instances that do not provide one. A
+	// parameter without a default must be provided.
+	// +optional
+	Default *string `json:"default,omitempty"`
+
+	// Values the parameter is limited to.
+	// +optional
+	Enum []string `json:"enum,omitempty"`
+
+	// Regular expression that a string parameter must match.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+
+	// Minimum value of an integer parameter.
+	// +optional
+	Minimum *int64 `json:"minimum,omitempty"`
+
+	// Maximum value of an integer parameter.
+	// +optional
+	Maximum *int64 `json:"maximum,omitempty"`
+}
+
+type BlueprintParameterType string
+
+const (
+	BlueprintParameterTypeString  BlueprintParameterType = "string"
+	BlueprintParameterTypeInteger BlueprintParameterType = "integer"
+	BlueprintParameterTypeBoolean BlueprintParameterType = "boolean"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=blueprints
+
+// Blueprint defines a parameterized set of federated resources that
+// BlueprintInstances of any namespace instantiate, so that platform
+// teams can offer multi-cluster application patterns whose inputs are
+// validated. Blueprints are only honored when the Blueprints feature
+// gate is enabled.
+type Blueprint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BlueprintSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// BlueprintList contains a list of Blueprint
+type BlueprintList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Blueprint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Blueprint{}, &BlueprintList{})
+}
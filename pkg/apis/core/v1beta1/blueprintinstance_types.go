@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BlueprintInstanceSpec defines the Blueprint an instance is created
+// from and the values of its parameters.
+type BlueprintInstanceSpec struct {
+	// BlueprintRef references the Blueprint in the KubeFed system
+	// namespace that the instance is created from.
+	BlueprintRef BlueprintReference `json:"blueprintRef"`
+
+	// Values of the parameters of the blueprint, keyed by parameter
+	// name.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// BlueprintReference references a Blueprint by name.
+type BlueprintReference struct {
+	// Name of the Blueprint.
+	Name string `json:"name"`
+}
+
+// BlueprintInstanceStatus defines the observed state of
+// BlueprintInstance
+type BlueprintInstanceStatus struct {
+	// ObservedGeneration is the generation of the instance last
+	// applied to its resources.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Ready indicates whether all resources of the instance have been
+	// created from the current blueprint and parameters.
+	Ready bool `json:"ready"`
+	// Message describes why the instance is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Resources lists the federated resources created for the
+	// instance.
+	// +optional
+	Resources []BlueprintInstanceResource `json:"resources,omitempty"`
+}
+
+// BlueprintInstanceResource references a federated resource created
+// for a BlueprintInstance in its namespace.
+type BlueprintInstanceResource struct {
+	// Kind of the federated resource, e.g. FederatedDeployment.
+	Kind string `json:"kind"`
+	// Name of the federated resource.
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name=blueprint,type=string,JSONPath=.spec.blueprintRef.name
+// +kubebuilder:printcolumn:name=ready,type=boolean,JSONPath=.status.ready
+// +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:resource:path=blueprintinstances
+// +kubebuilder:subresource:status
+
+// BlueprintInstance creates the federated resources of a Blueprint in
+// its namespace with the given parameters. Deleting the instance
+// deletes its resources.
+type BlueprintInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BlueprintInstanceSpec `json:"spec"`
+	// +optional
+	Status BlueprintInstanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BlueprintInstanceList contains a list of BlueprintInstance
+type BlueprintInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BlueprintInstance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BlueprintInstance{}, &BlueprintInstanceList{})
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterAllowlistSpec defines the clusters that the federated
+// resources of a set of namespaces may be propagated to.
+type ClusterAllowlistSpec struct {
+	// Names of the namespaces whose federated resources may be
+	// propagated to the clusters of the allowlist.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Whether cluster-scoped federated resources may be propagated to
+	// the clusters of the allowlist.
+	// +optional
+	ClusterScoped bool `json:"clusterScoped,omitempty"`
+
+	// Names of the KubeFedClusters that resources may be propagated to.
+	Clusters []string `json:"clusters"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clusterallowlists
+
+// ClusterAllowlist approves the propagation of the federated resources
+// of a set of namespaces to an explicit list of member clusters. When
+// the ClusterAllowlists feature gate is enabled, a federated resource
+// is only propagated to the clusters listed by the ClusterAllowlists
+// in the KubeFed namespace that apply to its namespace, regardless of
+// its placement.
+type ClusterAllowlist struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterAllowlistSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterAllowlistList contains a list of ClusterAllowlist
+type ClusterAllowlistList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterAllowlist `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterAllowlist{}, &ClusterAllowlistList{})
+}
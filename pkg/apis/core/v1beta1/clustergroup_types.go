@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterGroupSpec defines the desired state of ClusterGroup. Exactly
+// one of clusters or clusterSelector must be provided.
+type ClusterGroupSpec struct {
+	// Names of the KubeFedClusters that are members of the group.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Selector for the KubeFedClusters that are members of the
+	// group. An empty selector selects all clusters.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clustergroups
+
+// ClusterGroup defines a named set of member clusters that can be
+// referenced from the placement of federated resources via
+// spec.placement.clusterGroups.
+type ClusterGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterGroupSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterGroupList contains a list of ClusterGroup
+type ClusterGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterGroup{}, &ClusterGroupList{})
+}
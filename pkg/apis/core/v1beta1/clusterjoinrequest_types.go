@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterJoinRequestSpec describes a member cluster requesting to join
+// the KubeFed control plane.
+type ClusterJoinRequestSpec struct {
+	// Name of the KubeFedCluster to create for the member cluster.
+	ClusterName string `json:"clusterName"`
+	// The API endpoint of the member cluster. This can be a hostname,
+	// hostname:port, IP or IP:port.
+	APIEndpoint string `json:"apiEndpoint"`
+	// CABundle contains the certificate authority information.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// Token of a service account in the member cluster that is
+	// authorized to be used by the control plane. It is moved to a
+	// secret in the KubeFed namespace when the cluster is joined.
+	// +optional
+	ServiceAccountToken []byte `json:"serviceAccountToken,omitempty"`
+	// Labels to apply to the KubeFedCluster.
+	// +optional
+	ClusterLabels map[string]string `json:"clusterLabels,omitempty"`
+}
+
+// ClusterJoinRequestStatus defines the observed state of ClusterJoinRequest
+type ClusterJoinRequestStatus struct {
+	// Conditions describe the approval of the request and whether the
+	// cluster has been joined.
+	// +optional
+	Conditions []ClusterJoinRequestCondition `json:"conditions,omitempty"`
+}
+
+type ClusterJoinRequestConditionType string
+
+const (
+	// The request was approved and the cluster may be joined.
+	ClusterJoinRequestApproved ClusterJoinRequestConditionType = "Approved"
+	// The request was denied and the cluster will not be joined.
+	ClusterJoinRequestDenied ClusterJoinRequestConditionType = "Denied"
+	// A KubeFedCluster was created for the cluster.
+	ClusterJoinRequestJoined ClusterJoinRequestConditionType = "Joined"
+)
+
+// ClusterJoinRequestCondition describes the state of a join request.
+type ClusterJoinRequestCondition struct {
+	// Type of the condition, Approved, Denied or Joined.
+	Type ClusterJoinRequestConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status apiv1.ConditionStatus `json:"status"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clusterjoinrequests
+// +kubebuilder:subresource:status
+
+// ClusterJoinRequest is created by a member cluster to request that it
+// be joined to the KubeFed control plane. A KubeFedCluster is created
+// for the member cluster once the request is approved.
+type ClusterJoinRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterJoinRequestSpec `json:"spec"`
+	// +optional
+	Status ClusterJoinRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterJoinRequestList contains a list of ClusterJoinRequest
+type ClusterJoinRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterJoinRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterJoinRequest{}, &ClusterJoinRequestList{})
+}
+
+// GetCondition returns the condition of the given type, or nil if the
+// request does not have one.
+func (r *ClusterJoinRequest) GetCondition(conditionType ClusterJoinRequestConditionType) *ClusterJoinRequestCondition {
+	for i := range r.Status.Conditions {
+		if r.Status.Conditions[i].Type == conditionType {
+			return &r.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue returns whether the request has a condition of the
+// given type with status True.
+func (r *ClusterJoinRequest) IsConditionTrue(conditionType ClusterJoinRequestConditionType) bool {
+	condition := r.GetCondition(conditionType)
+	return condition != nil && condition.Status == apiv1.ConditionTrue
+}
+
+// SetCondition adds or replaces the condition of the given type.
+func (r *ClusterJoinRequest) SetCondition(conditionType ClusterJoinRequestConditionType, status apiv1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	condition := r.GetCondition(conditionType)
+	if condition == nil {
+		r.Status.Conditions = append(r.Status.Conditions, ClusterJoinRequestCondition{Type: conditionType})
+		condition = &r.Status.Conditions[len(r.Status.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.LastTransitionTime = &now
+	}
+	condition.Status = status
+	condition.Reason = reason
+	condition.Message = message
+}
@@ -35,10 +35,28 @@ const (
 	DefaultLeaderElectionRetryPeriod   = 5 * time.Second
 	DefaultLeaderElectionResourceLock  = v1beta1.ConfigMapsResourceLock
 
-	DefaultClusterHealthCheckPeriod           = 10 * time.Second
-	DefaultClusterHealthCheckFailureThreshold = 3
-	DefaultClusterHealthCheckSuccessThreshold = 1
-	DefaultClusterHealthCheckTimeout          = 3 * time.Second
+	DefaultClusterHealthCheckPeriod               = 10 * time.Second
+	DefaultClusterHealthCheckFailureThreshold     = 3
+	DefaultClusterHealthCheckEdgeFailureThreshold = 30
+	DefaultClusterHealthCheckSuccessThreshold     = 1
+	DefaultClusterHealthCheckTimeout              = 3 * time.Second
+	DefaultClusterHealthCheckDeadline             = 30 * time.Second
+	DefaultClusterHealthCheckWorkers              = 10
+	DefaultClusterHealthCheckJitterPercentage     = 10
+
+	DefaultLogFormat = v1beta1.LogFormatText
+
+	DefaultWebhookFailurePolicy = v1beta1.WebhookFailurePolicyFail
+
+	DefaultComplianceMode = v1beta1.ComplianceModeStandard
+
+	DefaultQuarantineFailurePercentage = 50
+	DefaultQuarantineMinimumOperations = 20
+	DefaultQuarantineWindow            = 5 * time.Minute
+
+	DefaultStatusCollectionMinInterval       = 10 * time.Second
+	DefaultStatusCollectionMaxInterval       = 5 * time.Minute
+	DefaultStatusCollectionStableCollections = 3
 )
 
 func SetDefaultKubeFedConfig(fedConfig *v1beta1.KubeFedConfig) {
@@ -80,7 +98,11 @@ func SetDefaultKubeFedConfig(fedConfig *v1beta1.KubeFedConfig) {
 	healthCheck := spec.ClusterHealthCheck
 	setDuration(&healthCheck.Period, DefaultClusterHealthCheckPeriod)
 	setDuration(&healthCheck.Timeout, DefaultClusterHealthCheckTimeout)
+	setDuration(&healthCheck.Deadline, DefaultClusterHealthCheckDeadline)
+	setInt64(&healthCheck.Workers, DefaultClusterHealthCheckWorkers)
+	setInt64(&healthCheck.JitterPercentage, DefaultClusterHealthCheckJitterPercentage)
 	setInt64(&healthCheck.FailureThreshold, DefaultClusterHealthCheckFailureThreshold)
+	setInt64(&healthCheck.EdgeFailureThreshold, DefaultClusterHealthCheckEdgeFailureThreshold)
 	setInt64(&healthCheck.SuccessThreshold, DefaultClusterHealthCheckSuccessThreshold)
 
 	if spec.SyncController == nil {
@@ -91,6 +113,55 @@ func SetDefaultKubeFedConfig(fedConfig *v1beta1.KubeFedConfig) {
 		spec.SyncController.AdoptResources = new(v1beta1.ResourceAdoption)
 		*spec.SyncController.AdoptResources = v1beta1.AdoptResourcesEnabled
 	}
+
+	if spec.SyncController.Quarantine == nil {
+		spec.SyncController.Quarantine = &v1beta1.QuarantineConfig{}
+	}
+
+	quarantine := spec.SyncController.Quarantine
+	setInt32(&quarantine.FailurePercentage, DefaultQuarantineFailurePercentage)
+	setInt32(&quarantine.MinimumOperations, DefaultQuarantineMinimumOperations)
+	setDuration(&quarantine.Window, DefaultQuarantineWindow)
+
+	if spec.StatusController == nil {
+		spec.StatusController = &v1beta1.StatusControllerConfig{}
+	}
+
+	if spec.StatusController.AdaptiveCollection == nil {
+		spec.StatusController.AdaptiveCollection = &v1beta1.AdaptiveStatusCollectionConfig{}
+	}
+
+	collection := spec.StatusController.AdaptiveCollection
+	setDuration(&collection.MinInterval, DefaultStatusCollectionMinInterval)
+	setDuration(&collection.MaxInterval, DefaultStatusCollectionMaxInterval)
+	setInt32(&collection.StableCollections, DefaultStatusCollectionStableCollections)
+
+	if spec.Logging == nil {
+		spec.Logging = &v1beta1.LoggingConfig{}
+	}
+
+	if spec.Logging.Format == nil {
+		spec.Logging.Format = new(v1beta1.LogFormat)
+		*spec.Logging.Format = DefaultLogFormat
+	}
+
+	if spec.Webhook == nil {
+		spec.Webhook = &v1beta1.WebhookConfig{}
+	}
+
+	if spec.Webhook.FailurePolicy == nil {
+		spec.Webhook.FailurePolicy = new(v1beta1.WebhookFailurePolicy)
+		*spec.Webhook.FailurePolicy = DefaultWebhookFailurePolicy
+	}
+
+	if spec.Compliance == nil {
+		spec.Compliance = &v1beta1.ComplianceConfig{}
+	}
+
+	if spec.Compliance.Mode == nil {
+		spec.Compliance.Mode = new(v1beta1.ComplianceMode)
+		*spec.Compliance.Mode = DefaultComplianceMode
+	}
 }
 
 func setDefaultKubeFedFeatureGates(fgc []v1beta1.FeatureGatesConfig) []v1beta1.FeatureGatesConfig {
@@ -140,3 +211,10 @@ func setInt64(target **int64, defaultValue int64) {
 		**target = defaultValue
 	}
 }
+
+func setInt32(target **int32, defaultValue int32) {
+	if *target == nil {
+		*target = new(int32)
+		**target = defaultValue
+	}
+}
@@ -106,6 +106,13 @@ func TestSetDefaultKubeFedConfig(t *testing.T) {
 	SetDefaultKubeFedConfig(modifiedFailureThresholdKFC)
 	successCases["spec.clusterHealthCheck.failureThreshold is preserved"] = KubeFedConfigComparison{failureThresholdKFC, modifiedFailureThresholdKFC}
 
+	edgeFailureThresholdKFC := defaultKubeFedConfig()
+	edgeFailureThreshold := int64(DefaultClusterHealthCheckEdgeFailureThreshold + 10)
+	edgeFailureThresholdKFC.Spec.ClusterHealthCheck.EdgeFailureThreshold = &edgeFailureThreshold
+	modifiedEdgeFailureThresholdKFC := edgeFailureThresholdKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedEdgeFailureThresholdKFC)
+	successCases["spec.clusterHealthCheck.edgeFailureThreshold is preserved"] = KubeFedConfigComparison{edgeFailureThresholdKFC, modifiedEdgeFailureThresholdKFC}
+
 	successThresholdKFC := defaultKubeFedConfig()
 	successThreshold := int64(DefaultClusterHealthCheckSuccessThreshold + 3)
 	successThresholdKFC.Spec.ClusterHealthCheck.SuccessThreshold = &successThreshold
@@ -119,6 +126,26 @@ func TestSetDefaultKubeFedConfig(t *testing.T) {
 	SetDefaultKubeFedConfig(modifiedTimeoutKFC)
 	successCases["spec.clusterHealthCheck.timeout is preserved"] = KubeFedConfigComparison{timeoutKFC, modifiedTimeoutKFC}
 
+	deadlineKFC := defaultKubeFedConfig()
+	deadlineKFC.Spec.ClusterHealthCheck.Deadline.Duration = DefaultClusterHealthCheckDeadline + 7*time.Second
+	modifiedDeadlineKFC := deadlineKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedDeadlineKFC)
+	successCases["spec.clusterHealthCheck.deadline is preserved"] = KubeFedConfigComparison{deadlineKFC, modifiedDeadlineKFC}
+
+	workersKFC := defaultKubeFedConfig()
+	workers := int64(DefaultClusterHealthCheckWorkers + 4)
+	workersKFC.Spec.ClusterHealthCheck.Workers = &workers
+	modifiedWorkersKFC := workersKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedWorkersKFC)
+	successCases["spec.clusterHealthCheck.workers is preserved"] = KubeFedConfigComparison{workersKFC, modifiedWorkersKFC}
+
+	jitterPercentageKFC := defaultKubeFedConfig()
+	jitterPercentage := int64(0)
+	jitterPercentageKFC.Spec.ClusterHealthCheck.JitterPercentage = &jitterPercentage
+	modifiedJitterPercentageKFC := jitterPercentageKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedJitterPercentageKFC)
+	successCases["spec.clusterHealthCheck.jitterPercentage is preserved"] = KubeFedConfigComparison{jitterPercentageKFC, modifiedJitterPercentageKFC}
+
 	// SyncController
 	adoptResourcesKFC := defaultKubeFedConfig()
 	*adoptResourcesKFC.Spec.SyncController.AdoptResources = v1beta1.AdoptResourcesDisabled
@@ -126,6 +153,50 @@ func TestSetDefaultKubeFedConfig(t *testing.T) {
 	SetDefaultKubeFedConfig(modifiedAdoptResourcesKFC)
 	successCases["spec.leaderElect.adoptResources is preserved"] = KubeFedConfigComparison{adoptResourcesKFC, modifiedAdoptResourcesKFC}
 
+	quarantineKFC := defaultKubeFedConfig()
+	*quarantineKFC.Spec.SyncController.Quarantine.FailurePercentage = DefaultQuarantineFailurePercentage + 25
+	quarantineKFC.Spec.SyncController.Quarantine.ReleaseAfter = &metav1.Duration{Duration: time.Hour}
+	modifiedQuarantineKFC := quarantineKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedQuarantineKFC)
+	successCases["spec.syncController.quarantine is preserved"] = KubeFedConfigComparison{quarantineKFC, modifiedQuarantineKFC}
+
+	// StatusController
+	collectionKFC := defaultKubeFedConfig()
+	collectionKFC.Spec.StatusController.AdaptiveCollection.MaxInterval.Duration = DefaultStatusCollectionMaxInterval * 2
+	*collectionKFC.Spec.StatusController.AdaptiveCollection.StableCollections = DefaultStatusCollectionStableCollections + 2
+	modifiedCollectionKFC := collectionKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedCollectionKFC)
+	successCases["spec.statusController.adaptiveCollection is preserved"] = KubeFedConfigComparison{collectionKFC, modifiedCollectionKFC}
+
+	// Logging
+	logFormatKFC := defaultKubeFedConfig()
+	*logFormatKFC.Spec.Logging.Format = v1beta1.LogFormatJSON
+	modifiedLogFormatKFC := logFormatKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedLogFormatKFC)
+	successCases["spec.logging.format is preserved"] = KubeFedConfigComparison{logFormatKFC, modifiedLogFormatKFC}
+
+	// Webhook
+	failurePolicyKFC := defaultKubeFedConfig()
+	*failurePolicyKFC.Spec.Webhook.FailurePolicy = v1beta1.WebhookFailurePolicyIgnore
+	modifiedFailurePolicyKFC := failurePolicyKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedFailurePolicyKFC)
+	successCases["spec.webhook.failurePolicy is preserved"] = KubeFedConfigComparison{failurePolicyKFC, modifiedFailurePolicyKFC}
+
+	namespaceSelectorKFC := defaultKubeFedConfig()
+	namespaceSelectorKFC.Spec.Webhook.NamespaceSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"name": "kube-federation-system"},
+	}
+	modifiedNamespaceSelectorKFC := namespaceSelectorKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedNamespaceSelectorKFC)
+	successCases["spec.webhook.namespaceSelector is preserved"] = KubeFedConfigComparison{namespaceSelectorKFC, modifiedNamespaceSelectorKFC}
+
+	// Compliance
+	complianceModeKFC := defaultKubeFedConfig()
+	*complianceModeKFC.Spec.Compliance.Mode = v1beta1.ComplianceModeRestricted
+	modifiedComplianceModeKFC := complianceModeKFC.DeepCopyObject().(*v1beta1.KubeFedConfig)
+	SetDefaultKubeFedConfig(modifiedComplianceModeKFC)
+	successCases["spec.compliance.mode is preserved"] = KubeFedConfigComparison{complianceModeKFC, modifiedComplianceModeKFC}
+
 	for k, v := range successCases {
 		if !reflect.DeepEqual(v.original, v.modified) {
 			t.Errorf("[%s] expected success: original=%+v, modified=%+v", k, *v.original, *v.modified)
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FaultOperation is an operation of the client for member clusters.
+type FaultOperation string
+
+const (
+	FaultOperationCreate       FaultOperation = "create"
+	FaultOperationGet          FaultOperation = "get"
+	FaultOperationUpdate       FaultOperation = "update"
+	FaultOperationDelete       FaultOperation = "delete"
+	FaultOperationList         FaultOperation = "list"
+	FaultOperationUpdateStatus FaultOperation = "updateStatus"
+)
+
+// FaultInjectionSpec defines the faults injected into the requests the
+// control plane makes to member clusters. At most one of clusters or
+// clusterSelector may be provided. If neither is provided the faults
+// are injected for all clusters.
+type FaultInjectionSpec struct {
+	// Names of the KubeFedClusters the faults are injected for.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Selector for the KubeFedClusters the faults are injected for.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// Operations the faults are injected into. Faults are injected
+	// into all operations if empty.
+	// +optional
+	Operations []FaultOperation `json:"operations,omitempty"`
+
+	// Latency added to each request before it is made.
+	// +optional
+	Latency *metav1.Duration `json:"latency,omitempty"`
+
+	// Percentage of requests, between 0 and 100, that fail without
+	// being made.
+	// +optional
+	ErrorPercentage int32 `json:"errorPercentage,omitempty"`
+
+	// HTTP status code, between 400 and 599, of the errors returned
+	// for failed requests. Defaults to 503.
+	// +optional
+	ErrorCode int32 `json:"errorCode,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=faultinjections
+
+// FaultInjection injects latency and errors into the requests the
+// control plane makes to member clusters so that the resilience of
+// propagation and failover can be tested without breaking clusters.
+// FaultInjections are only honored when the FaultInjection feature
+// gate is enabled.
+type FaultInjection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FaultInjectionSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// FaultInjectionList contains a list of FaultInjection
+type FaultInjectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FaultInjection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FaultInjection{}, &FaultInjectionList{})
+}
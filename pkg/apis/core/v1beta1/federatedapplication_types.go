@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FederatedApplicationSpec defines a set of federated resources in the
+// namespace of the application that are managed together.
+type FederatedApplicationSpec struct {
+	// Selector selects the federated resources of the application by
+	// label.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Resources lists federated resources of the application in
+	// addition to those matching the selector.
+	// +optional
+	Resources []FederatedApplicationResource `json:"resources,omitempty"`
+	// Placement, if provided, replaces the placement of each of the
+	// resources of the application.
+	// +optional
+	Placement *FederatedApplicationPlacement `json:"placement,omitempty"`
+	// RolloutStrategy, if provided, replaces the strategy of each of
+	// the federated deployments of the application.
+	// +optional
+	RolloutStrategy *appsv1.DeploymentStrategy `json:"rolloutStrategy,omitempty"`
+	// Paused indicates whether propagation of the resources of the
+	// application to member clusters is suspended.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// FederatedApplicationResource references a federated resource in the
+// namespace of the application.
+type FederatedApplicationResource struct {
+	// Kind of the federated resource, e.g. FederatedDeployment.
+	Kind string `json:"kind"`
+	// Name of the federated resource.
+	Name string `json:"name"`
+}
+
+// FederatedApplicationPlacement is the placement shared by the
+// resources of an application. Only one of clusters, clusterGroups
+// and clusterSelector is used, in that order of precedence.
+type FederatedApplicationPlacement struct {
+	// Names of the clusters to place the resources in.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+	// Names of the ClusterGroups whose members to place the resources
+	// in.
+	// +optional
+	ClusterGroups []string `json:"clusterGroups,omitempty"`
+	// Selector of the clusters to place the resources in.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+// FederatedApplicationStatus defines the observed state of
+// FederatedApplication
+type FederatedApplicationStatus struct {
+	// ObservedGeneration is the generation of the application last
+	// applied to its resources.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ResourceCount is the number of resources of the application.
+	ResourceCount int32 `json:"resourceCount"`
+	// PropagatedResourceCount is the number of resources of the
+	// application that have been propagated to all of their clusters.
+	PropagatedResourceCount int32 `json:"propagatedResourceCount"`
+	// ReadyResourceCount is the number of resources of the
+	// application that are healthy in all of their clusters.
+	// +optional
+	ReadyResourceCount int32 `json:"readyResourceCount,omitempty"`
+	// Resources describes the state of each resource of the
+	// application.
+	// +optional
+	Resources []FederatedApplicationResourceStatus `json:"resources,omitempty"`
+}
+
+// FederatedApplicationResourceStatus describes the state of a resource
+// of an application.
+type FederatedApplicationResourceStatus struct {
+	// Kind of the federated resource.
+	Kind string `json:"kind"`
+	// Name of the federated resource.
+	Name string `json:"name"`
+	// Found indicates whether the resource exists.
+	Found bool `json:"found"`
+	// Propagated indicates whether the resource has been propagated
+	// to all of its clusters.
+	Propagated bool `json:"propagated"`
+	// Reason propagation is incomplete, as reported by the
+	// propagation condition of the resource.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Health of the resource across its clusters (Healthy,
+	// Progressing or Degraded) as reported by the ready condition of
+	// the resource. Empty if the health of the resource is not
+	// interpreted.
+	// +optional
+	Health string `json:"health,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name=resources,type=integer,JSONPath=.status.resourceCount
+// +kubebuilder:printcolumn:name=propagated,type=integer,JSONPath=.status.propagatedResourceCount
+// +kubebuilder:printcolumn:name=ready,type=integer,JSONPath=.status.readyResourceCount
+// +kubebuilder:printcolumn:name=paused,type=boolean,JSONPath=.spec.paused
+// +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:resource:path=federatedapplications
+// +kubebuilder:subresource:status
+
+// FederatedApplication groups the federated resources of an
+// application so that their placement, rollout strategy and
+// propagation can be managed together. Deleting the application
+// deletes its resources.
+type FederatedApplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FederatedApplicationSpec `json:"spec"`
+	// +optional
+	Status FederatedApplicationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FederatedApplicationList contains a list of FederatedApplication
+type FederatedApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FederatedApplication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FederatedApplication{}, &FederatedApplicationList{})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FederatedTemplateSpec defines a template of target resources that
+// federated resources can reference with spec.templateRef instead of
+// embedding a template of their own.
+type FederatedTemplateSpec struct {
+	// Template of the target resources. String values may reference
+	// parameters as $(name), which are replaced with the values given
+	// by the referencing federated resource.
+	Template runtime.RawExtension `json:"template"`
+
+	// Parameters referenced by the template.
+	// +optional
+	Parameters []FederatedTemplateParameter `json:"parameters,omitempty"`
+}
+
+// FederatedTemplateParameter declares a parameter of a
+// FederatedTemplate.
+type FederatedTemplateParameter struct {
+	// Name of the parameter.
+	Name string `json:"name"`
+
+	// Value of the parameter for federated resources that do not
+	// provide one. A parameter without a default must be provided.
+	// +optional
+	Default *string `json:"default,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=federatedtemplates
+
+// FederatedTemplate is a template shared by federated resources of any
+// namespace, so that many nearly identical federated resources only
+// store their parameters. FederatedTemplates are only honored when the
+// FederatedTemplates feature gate is enabled.
+type FederatedTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FederatedTemplateSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// FederatedTemplateList contains a list of FederatedTemplate
+type FederatedTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FederatedTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FederatedTemplate{}, &FederatedTemplateList{})
+}
@@ -34,6 +34,21 @@ type FederatedTypeConfigSpec struct {
 	TargetType APIResource `json:"targetType"`
 	// Whether or not propagation to member clusters should be enabled.
 	Propagation PropagationMode `json:"propagation"`
+	// Configures the deletion of the resources of the type from member
+	// clusters while propagation is disabled. Resources are left in
+	// member clusters if not provided.
+	// +optional
+	ResourceDeletion *ResourceDeletion `json:"resourceDeletion,omitempty"`
+	// Whether resources are only created in member clusters or are also
+	// updated to match their federated resource. Defaults to
+	// CreateAndUpdate.
+	// +optional
+	PropagationMode *ResourcePropagationMode `json:"propagationMode,omitempty"`
+	// How an update of a resource in a member cluster is resolved when
+	// the resource was changed in the cluster since it was read. The
+	// update is retried by a later reconciliation if not provided.
+	// +optional
+	ConflictResolution *ConflictResolution `json:"conflictResolution,omitempty"`
 	// Configuration for the federated type that defines (via
 	// template, placement and overrides fields) how the target type
 	// should appear in multiple cluster.
@@ -47,8 +62,208 @@ type FederatedTypeConfigSpec struct {
 	// Whether or not Status object should be populated.
 	// +optional
 	StatusCollection *StatusCollectionMode `json:"statusCollection,omitempty"`
+	// Paths of the status fields of the target type to collect from
+	// member clusters, with the components of a path separated by dots
+	// (e.g. `availableReplicas` or `loadBalancer.ingress`). The entire
+	// status is collected if not provided.
+	// +optional
+	StatusFields []string `json:"statusFields,omitempty"`
+	// Expressions interpreting the health of resources in member
+	// clusters. The built-in interpreter of the target type is used if
+	// not provided. Only used when the HealthInterpretation feature is
+	// enabled.
+	// +optional
+	HealthInterpreter *HealthInterpreter `json:"healthInterpreter,omitempty"`
+	// Ordered list of built-in mutators applied to the resource rendered
+	// from the template for each member cluster before overrides are
+	// applied and the resource is propagated.
+	// +optional
+	DispatchMutators []DispatchMutatorConfig `json:"dispatchMutators,omitempty"`
+	// Webhooks called in order with the resource to be created or
+	// updated in each member cluster, after overrides are applied,
+	// that may deny or mutate the resource.
+	// +optional
+	PropagationWebhooks []PropagationWebhook `json:"propagationWebhooks,omitempty"`
+}
+
+// ResourceDeletion configures the deletion of the resources of a type
+// from member clusters once propagation of the type is disabled. The
+// resources are deleted in the background at a limited rate so that
+// types with many resources do not overwhelm member clusters.
+type ResourceDeletion struct {
+	// The maximum number of resources deleted per second across all
+	// member clusters. Defaults to 10.
+	// +optional
+	DeletesPerSecond *int32 `json:"deletesPerSecond,omitempty"`
+	// Whether the deletion is paused. A paused deletion continues
+	// with the remaining resources once it is no longer paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// HealthInterpreter interprets the health of a resource in a member
+// cluster with expressions over the resource, which is bound to
+// `self`. The expressions use a subset of the Common Expression
+// Language: field selection, `has()`, the `exists()` and `all()`
+// macros on lists, comparisons, `!`, `&&`, `||` and literals (e.g.
+// `self.status.conditions.exists(c, c.type == 'Ready' && c.status ==
+// 'True')`). A resource that is neither degraded nor healthy is
+// progressing.
+type HealthInterpreter struct {
+	// Expression that is true if the resource is healthy.
+	Healthy string `json:"healthy"`
+	// Expression that is true if the resource is degraded. Evaluated
+	// before the healthy expression.
+	// +optional
+	Degraded string `json:"degraded,omitempty"`
+}
+
+// ConflictResolution configures how conflicting updates of resources
+// in member clusters are resolved.
+type ConflictResolution struct {
+	// The strategy resolving a conflict. Supported options are
+	// `RetryWithRebase`, `PreferFederated` and `PreferLocalFields`.
+	Strategy ConflictStrategy `json:"strategy"`
+	// Paths of the fields whose values in member clusters are kept
+	// when resolving a conflict with `PreferLocalFields`, with the
+	// components of a path separated by dots (e.g. `spec.replicas`).
+	// +optional
+	LocalFields []string `json:"localFields,omitempty"`
+}
+
+type ConflictStrategy string
+
+const (
+	// The update is computed again from the changed resource, keeping
+	// the fields that are retained from member clusters, and retried.
+	ConflictStrategyRetryWithRebase ConflictStrategy = "RetryWithRebase"
+	// The update is retried unchanged against the changed resource,
+	// overwriting any concurrent change.
+	ConflictStrategyPreferFederated ConflictStrategy = "PreferFederated"
+	// The update is computed again from the changed resource as with
+	// RetryWithRebase, additionally keeping the values of the local
+	// fields from the changed resource.
+	ConflictStrategyPreferLocalFields ConflictStrategy = "PreferLocalFields"
+)
+
+// DispatchMutatorConfig configures a built-in mutator of resources
+// propagated to member clusters. Exactly one of imageRewrite,
+// labelInjection, nodeSelectorInjection, resourceRequestScaling or
+// subjectRewrite must be provided.
+type DispatchMutatorConfig struct {
+	// Selector for the member clusters the mutator applies to. The
+	// mutator applies to all clusters if not provided.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// Rewrites the images of the containers of a pod template.
+	// +optional
+	ImageRewrite *ImageRewriteMutator `json:"imageRewrite,omitempty"`
+	// Adds labels to the resource.
+	// +optional
+	LabelInjection *LabelInjectionMutator `json:"labelInjection,omitempty"`
+	// Adds entries to the node selector of a pod template.
+	// +optional
+	NodeSelectorInjection *NodeSelectorInjectionMutator `json:"nodeSelectorInjection,omitempty"`
+	// Scales the resource requests of the containers of a pod template.
+	// +optional
+	ResourceRequestScaling *ResourceRequestScalingMutator `json:"resourceRequestScaling,omitempty"`
+	// Rewrites the subjects of a role binding or cluster role binding.
+	// +optional
+	SubjectRewrite *SubjectRewriteMutator `json:"subjectRewrite,omitempty"`
+}
+
+// ImageRewriteMutator replaces a prefix of container images, e.g. to
+// pull images from a registry mirror local to a cluster.
+type ImageRewriteMutator struct {
+	// The image prefix to replace (e.g. docker.io/).
+	From string `json:"from"`
+	// The prefix replacing from (e.g. registry.eu.example.com/).
+	To string `json:"to"`
+}
+
+// LabelInjectionMutator adds labels to a resource, overwriting labels
+// with the same key.
+type LabelInjectionMutator struct {
+	// The labels to add.
+	Labels map[string]string `json:"labels"`
+	// Whether to also add the labels to the pod template of the
+	// resource.
+	// +optional
+	PodTemplate bool `json:"podTemplate,omitempty"`
+}
+
+// NodeSelectorInjectionMutator adds entries to the node selector of a
+// pod template, overwriting entries with the same key.
+type NodeSelectorInjectionMutator struct {
+	// The node selector entries to add.
+	NodeSelector map[string]string `json:"nodeSelector"`
+}
+
+// ResourceRequestScalingMutator scales the cpu and memory requests of
+// the containers of a pod template. Combined with a cluster selector
+// matching a class of clusters, it allows requests to be sized per
+// class of cluster. Scaled requests are capped at the container limits.
+type ResourceRequestScalingMutator struct {
+	// The percentage of the requested cpu and memory to request.
+	Percent int32 `json:"percent"`
+}
+
+// SubjectRewriteMutator renames the user and group subjects of a
+// RoleBinding or ClusterRoleBinding. Identities are rarely named the
+// same by the identity providers of different clusters; combined with
+// a cluster selector, it allows a binding to refer to the naming of
+// the identity provider of each cluster. Other resources are left
+// unchanged.
+type SubjectRewriteMutator struct {
+	// The mappings applied to the subjects. A subject is renamed by
+	// the first mapping matching its kind and name.
+	Mappings []SubjectMapping `json:"mappings"`
+}
+
+// SubjectMapping renames a subject of a role binding.
+type SubjectMapping struct {
+	// The kind of the subject. Supported options are `User` and
+	// `Group`.
+	Kind string `json:"kind"`
+	// The name of the subject in the template (e.g. platform-admins).
+	From string `json:"from"`
+	// The name replacing from (e.g. oidc:platform-admins).
+	To string `json:"to"`
 }
 
+// PropagationWebhook configures an external service that reviews the
+// resources of a type before they are created or updated in member
+// clusters.
+type PropagationWebhook struct {
+	// Name of the webhook, which identifies it in events.
+	Name string `json:"name"`
+	// HTTPS URL that reviews are posted to.
+	URL string `json:"url"`
+	// PEM encoded certificate authorities used to verify the serving
+	// certificate of the webhook. The system trust roots are used if
+	// not provided.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// Selector for the member clusters the webhook applies to. The
+	// webhook applies to all clusters if not provided.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// Seconds to wait for the webhook to respond. Defaults to 10.
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// Whether propagation fails or proceeds if the webhook cannot be
+	// called or does not respond with a review. Defaults to Fail.
+	// +optional
+	FailurePolicy PropagationWebhookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+type PropagationWebhookFailurePolicy string
+
+const (
+	PropagationWebhookFail   PropagationWebhookFailurePolicy = "Fail"
+	PropagationWebhookIgnore PropagationWebhookFailurePolicy = "Ignore"
+)
+
 // APIResource defines how to configure the dynamic client for an API resource.
 type APIResource struct {
 	// metav1.GroupVersion is not used since the json annotation of
@@ -77,6 +292,18 @@ const (
 	PropagationDisabled PropagationMode = "Disabled"
 )
 
+// ResourcePropagationMode defines the operations used to propagate
+// resources to member clusters.
+type ResourcePropagationMode string
+
+const (
+	// Resources are created and kept up to date in member clusters.
+	PropagationModeCreateAndUpdate ResourcePropagationMode = "CreateAndUpdate"
+	// Resources are created in member clusters but never updated
+	// afterwards, leaving their management to the member clusters.
+	PropagationModeCreateOnly ResourcePropagationMode = "CreateOnly"
+)
+
 // StatusCollectionMode defines the state of status collection.
 type StatusCollectionMode string
 
@@ -104,11 +331,86 @@ type FederatedTypeConfigStatus struct {
 	// StatusController tracks the status of the status controller.
 	// +optional
 	StatusController *ControllerStatus `json:"statusController,omitempty"`
+	// The number of federated resources of the type. Only recorded
+	// while the sync controller is running.
+	// +optional
+	ResourceCount *int64 `json:"resourceCount,omitempty"`
+	// The number of federated resources of the type whose current
+	// generation has been propagated to all selected clusters. Only
+	// recorded while the sync controller is running.
+	// +optional
+	PropagatedResourceCount *int64 `json:"propagatedResourceCount,omitempty"`
+	// Clusters describes the state of the informer of the sync
+	// controller for the target type in each ready member cluster.
+	// +optional
+	Clusters []FederatedTypeConfigClusterStatus `json:"clusters,omitempty"`
+	// ResourceDeletion reports the progress of the deletion of the
+	// resources of the type from member clusters. Only recorded while
+	// propagation is disabled and resourceDeletion is configured.
+	// +optional
+	ResourceDeletion *ResourceDeletionStatus `json:"resourceDeletion,omitempty"`
+}
+
+// ResourceDeletionPhase is the phase of the deletion of the resources
+// of a type from member clusters.
+type ResourceDeletionPhase string
+
+const (
+	ResourceDeletionRunning  ResourceDeletionPhase = "Running"
+	ResourceDeletionPaused   ResourceDeletionPhase = "Paused"
+	ResourceDeletionComplete ResourceDeletionPhase = "Complete"
+)
+
+// ResourceDeletionStatus describes the progress of the deletion of the
+// resources of a type from member clusters.
+type ResourceDeletionStatus struct {
+	// Phase is the current phase of the deletion.
+	Phase ResourceDeletionPhase `json:"phase"`
+	// Total is the number of resources to delete, including those
+	// already deleted.
+	Total int64 `json:"total"`
+	// Deleted is the number of resources that have been deleted.
+	Deleted int64 `json:"deleted"`
+	// Failed is the number of resources whose deletion failed in the
+	// most recent pass over the member clusters. Their deletion is
+	// retried by the next pass.
+	// +optional
+	Failed int64 `json:"failed,omitempty"`
+	// PendingClusters are the member clusters that were not ready
+	// during the most recent pass. Their resources are deleted once
+	// they are ready.
+	// +optional
+	PendingClusters []string `json:"pendingClusters,omitempty"`
+	// StartTime is the time the deletion started.
+	StartTime metav1.Time `json:"startTime"`
+	// EstimatedCompletionTime is the time the deletion is expected to
+	// complete given the rate of deletion so far.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+	// CompletionTime is the time the deletion completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// FederatedTypeConfigClusterStatus describes the state of the informer
+// for the target type in a member cluster.
+type FederatedTypeConfigClusterStatus struct {
+	// Name of the member cluster.
+	Name string `json:"name"`
+	// Whether the informer for the target type in the cluster has
+	// synced. Resources are not propagated to a cluster until its
+	// informer has synced.
+	Synced bool `json:"synced"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=federatedtypeconfigs,shortName=ftc
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name=propagation,type=string,JSONPath=.spec.propagation
+// +kubebuilder:printcolumn:name=resources,type=integer,JSONPath=.status.resourceCount
+// +kubebuilder:printcolumn:name=propagated,type=integer,JSONPath=.status.propagatedResourceCount
+// +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name=deletion,type=string,JSONPath=.status.resourceDeletion.phase,priority=1
 
 // FederatedTypeConfig programs KubeFed to know about a single API type - the
 // "target type" - that a user wants to federate. For each target type, there is
@@ -203,6 +505,14 @@ func (f *FederatedTypeConfig) GetPropagationEnabled() bool {
 	return f.Spec.Propagation == PropagationEnabled
 }
 
+func (f *FederatedTypeConfig) GetPropagationCreateOnly() bool {
+	return f.Spec.PropagationMode != nil && *f.Spec.PropagationMode == PropagationModeCreateOnly
+}
+
+func (f *FederatedTypeConfig) GetConflictResolution() *ConflictResolution {
+	return f.Spec.ConflictResolution
+}
+
 func (f *FederatedTypeConfig) GetFederatedType() metav1.APIResource {
 	return apiResourceToMeta(f.Spec.FederatedType, f.GetFederatedNamespaced())
 }
@@ -218,7 +528,23 @@ func (f *FederatedTypeConfig) GetStatusType() *metav1.APIResource {
 func (f *FederatedTypeConfig) GetStatusEnabled() bool {
 	return f.Spec.StatusCollection != nil &&
 		*f.Spec.StatusCollection == StatusCollectionEnabled &&
-		f.Name == "services"
+		(f.Name == "services" || f.Name == "daemonsets.apps")
+}
+
+func (f *FederatedTypeConfig) GetStatusFields() []string {
+	return f.Spec.StatusFields
+}
+
+func (f *FederatedTypeConfig) GetHealthInterpreter() *HealthInterpreter {
+	return f.Spec.HealthInterpreter
+}
+
+func (f *FederatedTypeConfig) GetDispatchMutators() []DispatchMutatorConfig {
+	return f.Spec.DispatchMutators
+}
+
+func (f *FederatedTypeConfig) GetPropagationWebhooks() []PropagationWebhook {
+	return f.Spec.PropagationWebhooks
 }
 
 // TODO(font): This method should be removed from the interface i.e. remove
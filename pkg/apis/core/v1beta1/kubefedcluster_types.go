@@ -31,6 +31,18 @@ const (
 	TLSValidityPeriod TLSValidation = "ValidityPeriod"
 )
 
+type ConnectivityProfile string
+
+const (
+	// The cluster is expected to be continuously reachable.
+	ConnectivityProfileStandard ConnectivityProfile = "Standard"
+	// The cluster is expected to be intermittently reachable. Health
+	// checks tolerate longer outages, desired state is delivered when
+	// the cluster reconnects and replicas are not scheduled away from
+	// the cluster while it is unreachable.
+	ConnectivityProfileEdge ConnectivityProfile = "Edge"
+)
+
 // KubeFedClusterSpec defines the desired state of KubeFedCluster
 type KubeFedClusterSpec struct {
 	// The API endpoint of the member cluster. This can be a hostname,
@@ -43,22 +55,139 @@ type KubeFedClusterSpec struct {
 
 	// Name of the secret containing the token required to access the
 	// member cluster. The secret needs to exist in the same namespace
-	// as the control plane and should have a "token" key.
-	SecretRef LocalSecretReference `json:"secretRef"`
+	// as the control plane and should have a "token" key. The token
+	// may instead be held by an external secret provider. Must be
+	// empty if UseServiceAccount is set.
+	// +optional
+	SecretRef LocalSecretReference `json:"secretRef,omitempty"`
 
 	// DisabledTLSValidations defines a list of checks to ignore when validating
 	// the TLS connection to the member cluster.  This can be any of *, SubjectName, or ValidityPeriod.
 	// If * is specified, it is expected to be the only option in list.
 	// +optional
 	DisabledTLSValidations []TLSValidation `json:"disabledTLSValidations,omitempty"`
+
+	// ConnectivityProfile describes how reliably the member cluster
+	// can be reached. This can be Standard or Edge. Defaults to
+	// Standard.
+	// +optional
+	ConnectivityProfile ConnectivityProfile `json:"connectivityProfile,omitempty"`
+
+	// Network describes the address ranges of the member cluster. The
+	// ranges can be referenced from the ipBlock rules of federated
+	// network policies.
+	// +optional
+	Network *ClusterNetwork `json:"network,omitempty"`
+
+	// HostCluster indicates that the member cluster is the cluster
+	// hosting the control plane. Resources are then propagated and
+	// the health of the cluster is checked over the connection of the
+	// control plane to its own api server instead of the api
+	// endpoint, which may not be reachable from within the cluster.
+	// +optional
+	HostCluster bool `json:"hostCluster,omitempty"`
+
+	// UseServiceAccount indicates that the member cluster, which must
+	// be the host cluster, is accessed with the credentials of the
+	// service account of the control plane instead of the token of a
+	// secret.
+	// +optional
+	UseServiceAccount bool `json:"useServiceAccount,omitempty"`
+
+	// CostWeight is the relative cost of running a replica in the
+	// member cluster. ReplicaSchedulingPreferences with CostOptimized
+	// distribution schedule replicas to the clusters with the lowest
+	// cost first. It may be set manually or maintained by an external
+	// exporter of cost signals. Defaults to 0.
+	// +optional
+	CostWeight int64 `json:"costWeight,omitempty"`
+
+	// OperationTimeout is the duration after which a request to the
+	// member cluster is cancelled. Overrides the
+	// clusterOperationTimeout of the sync controller configuration.
+	// +optional
+	OperationTimeout *metav1.Duration `json:"operationTimeout,omitempty"`
+
+	// ApplyRateLimit limits the rate at which resources are created
+	// and updated in the member cluster. Overrides the
+	// clusterApplyRateLimit of the sync controller configuration.
+	// +optional
+	ApplyRateLimit *ApplyRateLimit `json:"applyRateLimit,omitempty"`
+
+	// Unschedulable indicates that the member cluster is cordoned.
+	// No new placements are made to the cluster, while resources
+	// already propagated to the cluster remain in place.
+	// +optional
+	Unschedulable bool `json:"unschedulable,omitempty"`
+
+	// FaultDomain describes where the member cluster runs. Clusters
+	// sharing a fault domain are expected to fail together, so
+	// ReplicaSchedulingPreferences with a failoverDomain do not fail
+	// replicas over to clusters in the fault domain of a failed
+	// cluster.
+	// +optional
+	FaultDomain *ClusterFaultDomain `json:"faultDomain,omitempty"`
+}
+
+// ClusterFaultDomain describes the infrastructure a member cluster
+// runs on. The fault domains are nested: a region is qualified by its
+// provider and a datacenter by its region.
+type ClusterFaultDomain struct {
+	// Provider is the name of the infrastructure provider of the
+	// cluster, e.g. 'aws' or 'on-prem'.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// Region is the name of the region of the cluster within its
+	// provider, e.g. 'us-east1'.
+	// +optional
+	Region string `json:"region,omitempty"`
+	// Datacenter is the name of the datacenter or availability zone
+	// of the cluster within its region, e.g. 'us-east1-b'. Requires
+	// region to be set.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// ClusterNetwork describes the address ranges of a member cluster.
+type ClusterNetwork struct {
+	// The CIDR from which pod IPs are allocated.
+	// +optional
+	PodCIDR string `json:"podCIDR,omitempty"`
+	// The CIDR from which service cluster IPs are allocated.
+	// +optional
+	ServiceCIDR string `json:"serviceCIDR,omitempty"`
 }
 
 // LocalSecretReference is a reference to a secret within the enclosing
-// namespace.
+// namespace or to credentials held by an external secret provider.
 type LocalSecretReference struct {
 	// Name of a secret within the enclosing
-	// namespace
-	Name string `json:"name"`
+	// namespace. Must be empty if External is set.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// External references credentials held by an external secret
+	// provider, so that the credentials of the member cluster are
+	// never stored in the host cluster.
+	// +optional
+	External *ExternalSecretReference `json:"external,omitempty"`
+}
+
+// ExternalSecretReference is a reference to the credentials of a
+// member cluster held by an external secret provider.
+type ExternalSecretReference struct {
+	// Provider is the name of the secret provider the credentials are
+	// retrieved from, e.g. "csi" for secrets mounted by the Secrets
+	// Store CSI driver or "vault" for a HashiCorp Vault server.
+	Provider string `json:"provider"`
+
+	// Path of the credentials within the provider, e.g. the name of a
+	// mounted file or the path of a Vault secret.
+	Path string `json:"path"`
+
+	// Key of the token within the credentials. Defaults to "token".
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // KubeFedClusterStatus contains information about the current status of a
@@ -72,11 +201,136 @@ type KubeFedClusterStatus struct {
 	// Region is the name of the region in which all of the nodes in the cluster exist.  e.g. 'us-east1'.
 	// +optional
 	Region *string `json:"region,omitempty"`
+	// LastSyncTime is the last time the health of the cluster was
+	// successfully checked as of the last update of the status. The
+	// heartbeat lease of the cluster records the time of the most
+	// recent successful check while the cluster is ready.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Inventory describes the nodes and APIs of the cluster as of the
+	// last refresh by the cluster controller.
+	// +optional
+	Inventory *ClusterInventory `json:"inventory,omitempty"`
+	// Backfill reports the progress of the propagation of existing
+	// federated resources to the cluster after it joined.
+	// +optional
+	Backfill *BackfillProgress `json:"backfill,omitempty"`
+	// Quarantine, if set, indicates that resources are not being
+	// propagated to the cluster because it rejected too many of them.
+	// +optional
+	Quarantine *ClusterQuarantine `json:"quarantine,omitempty"`
+	// DiscoveryCache describes the cached API discovery of the
+	// cluster. Only set if the DiscoveryCache feature is enabled.
+	// +optional
+	DiscoveryCache *ClusterDiscoveryCache `json:"discoveryCache,omitempty"`
+}
+
+// ClusterDiscoveryCache describes the API discovery of a member
+// cluster cached by the controller manager.
+type ClusterDiscoveryCache struct {
+	// LastRefreshTime is the time the cached discovery was last
+	// fetched from the cluster.
+	LastRefreshTime metav1.Time `json:"lastRefreshTime"`
+}
+
+// ClusterQuarantine describes why and since when a cluster is
+// quarantined.
+type ClusterQuarantine struct {
+	// Reason is a brief CamelCase reason for the quarantine.
+	Reason string `json:"reason"`
+	// Message is a human-readable description of the quarantine.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the cluster was quarantined.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// ClusterInventory describes the capacity and installed APIs of a
+// member cluster.
+type ClusterInventory struct {
+	// NodeCount is the number of nodes in the cluster.
+	NodeCount int32 `json:"nodeCount"`
+	// KubeletVersions are the distinct kubelet versions reported by
+	// the nodes of the cluster.
+	// +optional
+	KubeletVersions []string `json:"kubeletVersions,omitempty"`
+	// Allocatable is the sum of the resources of the nodes of the
+	// cluster that are available for scheduling.
+	// +optional
+	Allocatable apiv1.ResourceList `json:"allocatable,omitempty"`
+	// CRDs are the names of the CustomResourceDefinitions installed in
+	// the cluster, e.g. 'certificates.cert-manager.io'.
+	// +optional
+	CRDs []string `json:"crds,omitempty"`
+	// APIVersions are the group versions served by the cluster, e.g.
+	// 'apps/v1'.
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+	// CloudProvider is the name of the cloud provider of the cluster
+	// as indicated by the provider ID of its nodes, e.g. 'aws' or 'gce'.
+	// +optional
+	CloudProvider string `json:"cloudProvider,omitempty"`
+	// Region is the name of the region indicated by the labels of the
+	// nodes of the cluster.
+	// +optional
+	Region string `json:"region,omitempty"`
+	// LastRefreshTime is the time the inventory was last refreshed.
+	LastRefreshTime metav1.Time `json:"lastRefreshTime"`
+}
+
+// BackfillPhase is a phase of the backfill of a newly joined cluster.
+// Resources of the types of a phase are only created in the cluster
+// once the resources of the preceding phases have been propagated.
+type BackfillPhase string
+
+const (
+	BackfillNamespaces    BackfillPhase = "Namespaces"
+	BackfillRBAC          BackfillPhase = "RBAC"
+	BackfillCRDs          BackfillPhase = "CRDs"
+	BackfillConfiguration BackfillPhase = "Configuration"
+	BackfillWorkloads     BackfillPhase = "Workloads"
+	BackfillComplete      BackfillPhase = "Complete"
+)
+
+// BackfillProgress describes the progress of the backfill of a newly
+// joined cluster.
+type BackfillProgress struct {
+	// Phase is the current phase of the backfill.
+	Phase BackfillPhase `json:"phase"`
+	// Phases reports the number of resources of each phase placed in
+	// the cluster and the number that have been propagated.
+	// +optional
+	Phases []BackfillPhaseProgress `json:"phases,omitempty"`
+	// StartTime is the time the backfill started.
+	StartTime metav1.Time `json:"startTime"`
+	// PhaseStartTime is the time the current phase started.
+	PhaseStartTime metav1.Time `json:"phaseStartTime"`
+	// EstimatedCompletionTime is the time the backfill is expected to
+	// complete given the rate of propagation so far.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+	// CompletionTime is the time the backfill completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// BackfillPhaseProgress describes the progress of a phase of a backfill.
+type BackfillPhaseProgress struct {
+	// Phase is the name of the phase.
+	Phase BackfillPhase `json:"phase"`
+	// Total is the number of resources of the phase placed in the
+	// cluster.
+	Total int32 `json:"total"`
+	// Propagated is the number of resources of the phase that have
+	// been propagated to the cluster.
+	Propagated int32 `json:"propagated"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
 // +kubebuilder:printcolumn:name=ready,type=string,JSONPath=.status.conditions[?(@.type=='Ready')].status
+// +kubebuilder:printcolumn:name=last-sync,type=date,JSONPath=.status.lastSyncTime
+// +kubebuilder:printcolumn:name=discovery-cache,type=date,JSONPath=.status.discoveryCache.lastRefreshTime,priority=1
 // +kubebuilder:resource:path=kubefedclusters
 // +kubebuilder:subresource:status
 
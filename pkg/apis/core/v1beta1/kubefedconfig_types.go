@@ -37,6 +37,16 @@ type KubeFedConfigSpec struct {
 	ClusterHealthCheck *ClusterHealthCheckConfig `json:"clusterHealthCheck,omitempty"`
 	// +optional
 	SyncController *SyncControllerConfig `json:"syncController,omitempty"`
+	// +optional
+	StatusController *StatusControllerConfig `json:"statusController,omitempty"`
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
+	// +optional
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+	// +optional
+	Compliance *ComplianceConfig `json:"compliance,omitempty"`
+	// +optional
+	NamespaceSameness *NamespaceSamenessConfig `json:"namespaceSameness,omitempty"`
 }
 
 type DurationConfig struct {
@@ -96,12 +106,31 @@ type ClusterHealthCheckConfig struct {
 	// Minimum consecutive failures for the cluster health to be considered failed after having succeeded.
 	// +optional
 	FailureThreshold *int64 `json:"failureThreshold,omitempty"`
+	// Minimum consecutive failures for the health of a cluster with
+	// the Edge connectivity profile to be considered failed after
+	// having succeeded.
+	// +optional
+	EdgeFailureThreshold *int64 `json:"edgeFailureThreshold,omitempty"`
 	// Minimum consecutive successes for the cluster health to be considered successful after having failed.
 	// +optional
 	SuccessThreshold *int64 `json:"successThreshold,omitempty"`
 	// Duration after which the cluster health check times out.
 	// +optional
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Duration after which a health check that has not completed,
+	// including the update of the status of the cluster, no longer
+	// occupies a worker. The cluster is not checked again until the
+	// check completes.
+	// +optional
+	Deadline *metav1.Duration `json:"deadline,omitempty"`
+	// Maximum number of clusters whose health is checked concurrently.
+	// +optional
+	Workers *int64 `json:"workers,omitempty"`
+	// Maximum percentage of the period by which the health checks of a
+	// cluster are randomly delayed to avoid checking all clusters at
+	// the same time.
+	// +optional
+	JitterPercentage *int64 `json:"jitterPercentage,omitempty"`
 }
 
 type SyncControllerConfig struct {
@@ -109,6 +138,194 @@ type SyncControllerConfig struct {
 	// "Enabled".
 	// +optional
 	AdoptResources *ResourceAdoption `json:"adoptResources,omitempty"`
+	// The rate at which resources may be applied to a member cluster.
+	// May be overridden for a cluster by the applyRateLimit of its
+	// KubeFedCluster. Applies are not limited if not provided.
+	// +optional
+	ClusterApplyRateLimit *ApplyRateLimit `json:"clusterApplyRateLimit,omitempty"`
+	// The duration after which a request to a member cluster is
+	// cancelled. May be overridden for a cluster by the
+	// operationTimeout of its KubeFedCluster. Requests are not
+	// cancelled if not provided.
+	// +optional
+	ClusterOperationTimeout *metav1.Duration `json:"clusterOperationTimeout,omitempty"`
+	// The duration for which the propagation of a change to a
+	// federated resource is delayed so that successive changes made
+	// within it are propagated together. Changes are propagated
+	// immediately if not provided.
+	// +optional
+	DebounceWindow *metav1.Duration `json:"debounceWindow,omitempty"`
+	// The duration for which the removal of resources from member
+	// clusters is held after their federated resource is deleted.
+	// During the hold the deletion can be cancelled with `kubefedctl
+	// undelete`. Resources are removed immediately if not provided.
+	// +optional
+	DeletionHold *metav1.Duration `json:"deletionHold,omitempty"`
+	// The name of a service account that is impersonated in the
+	// namespace of each namespaced resource when the resource is
+	// applied to member clusters, so that the audit logs and
+	// admission policies of member clusters can distinguish the
+	// namespaces, and thus the tenants, resources are applied for.
+	// Resources are applied with the credentials of the control
+	// plane if not provided.
+	// +optional
+	ImpersonateServiceAccount string `json:"impersonateServiceAccount,omitempty"`
+	// Endpoints that are notified when the propagation state of a
+	// federated resource transitions, e.g. when propagation to a
+	// cluster fails. No notifications are sent if not provided.
+	// +optional
+	NotificationSinks []NotificationSink `json:"notificationSinks,omitempty"`
+	// Labels and annotations added to every resource propagated to
+	// member clusters. No metadata is added if not provided.
+	// +optional
+	PropagatedMetadata *PropagatedMetadataConfig `json:"propagatedMetadata,omitempty"`
+	// Configuration of the quarantine of member clusters that reject
+	// a high fraction of the resources propagated to them. Only used
+	// if the ClusterQuarantine feature is enabled.
+	// +optional
+	Quarantine *QuarantineConfig `json:"quarantine,omitempty"`
+	// The number of revisions of each federated resource that are
+	// kept so that the resource can be rolled back. Only used if the
+	// RevisionHistory feature is enabled. Defaults to 10.
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// The average request latency above which a member cluster is
+	// considered slow. Resources are propagated to slow clusters
+	// separately so that they do not delay propagation to the other
+	// clusters. Slow clusters are not isolated if not provided.
+	// +optional
+	SlowClusterThreshold *metav1.Duration `json:"slowClusterThreshold,omitempty"`
+}
+
+// ApplyRateLimit limits the rate at which resources are created and
+// updated in a member cluster with a token bucket, so that a flood of
+// changes does not overwhelm the API server or admission webhooks of
+// the cluster.
+type ApplyRateLimit struct {
+	// The average number of resources that may be created or updated
+	// in the cluster per second.
+	ObjectsPerSecond int64 `json:"objectsPerSecond"`
+	// The number of resources that may be created or updated at once
+	// after a period of inactivity. Defaults to objectsPerSecond.
+	// +optional
+	Burst int64 `json:"burst,omitempty"`
+}
+
+// QuarantineConfig defines when member clusters are quarantined.
+// Resources are not propagated to a quarantined cluster until the
+// quarantine is released.
+type QuarantineConfig struct {
+	// Percentage of the creates and updates of resources in a member
+	// cluster within the window that must fail for the cluster to be
+	// quarantined. Defaults to 50.
+	// +optional
+	FailurePercentage *int32 `json:"failurePercentage,omitempty"`
+	// Minimum number of creates and updates of resources in a member
+	// cluster within the window for the cluster to be quarantined.
+	// Defaults to 20.
+	// +optional
+	MinimumOperations *int32 `json:"minimumOperations,omitempty"`
+	// The duration over which failures are counted. Defaults to 5m.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+	// The duration after which a quarantined cluster is automatically
+	// released. Quarantined clusters must be released manually if not
+	// provided.
+	// +optional
+	ReleaseAfter *metav1.Duration `json:"releaseAfter,omitempty"`
+}
+
+type StatusControllerConfig struct {
+	// Configuration of the adaptation of the interval at which the
+	// status of a resource is collected to how often its status
+	// changes. Only used if the AdaptiveStatusCollection feature is
+	// enabled.
+	// +optional
+	AdaptiveCollection *AdaptiveStatusCollectionConfig `json:"adaptiveCollection,omitempty"`
+}
+
+// AdaptiveStatusCollectionConfig defines how the interval at which
+// the status of a resource is collected from member clusters adapts to
+// how often its status changes. The interval of a resource whose status
+// changed is reset to the minimum interval, and is doubled, up to the
+// maximum interval, whenever its status is found unchanged for the
+// configured number of consecutive collections.
+type AdaptiveStatusCollectionConfig struct {
+	// The interval at which the status of a resource whose status
+	// recently changed is collected. Defaults to 10s.
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+	// The interval at which the status of a resource whose status
+	// has not changed for a long time is collected. Defaults to 5m.
+	// +optional
+	MaxInterval *metav1.Duration `json:"maxInterval,omitempty"`
+	// The number of consecutive collections that found the status of
+	// a resource unchanged after which its interval is doubled.
+	// Defaults to 3.
+	// +optional
+	StableCollections *int32 `json:"stableCollections,omitempty"`
+}
+
+// NotificationSink configures an external service, such as a Slack
+// incoming webhook, that is notified when the propagation state of a
+// federated resource transitions.
+type NotificationSink struct {
+	// Name of the sink, which identifies it in logs and metrics.
+	Name string `json:"name"`
+	// HTTPS URL that notifications are posted to.
+	URL string `json:"url"`
+	// PEM encoded certificate authorities used to verify the serving
+	// certificate of the sink. The system trust roots are used if
+	// not provided.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// The transitions the sink is notified of. The sink is notified
+	// of all transitions if not provided.
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty"`
+	// Go template that renders the JSON body posted to the sink from
+	// the notification, e.g. to format a Slack message. The
+	// notification itself is posted if not provided.
+	// +optional
+	PayloadTemplate string `json:"payloadTemplate,omitempty"`
+	// Seconds to wait for the sink to respond. Defaults to 10.
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+type NotificationEvent string
+
+const (
+	// The federated resource was propagated to all selected clusters.
+	NotificationAllClustersReady NotificationEvent = "AllClustersReady"
+	// Propagation of the federated resource to a cluster failed.
+	NotificationClusterFailed NotificationEvent = "ClusterFailed"
+	// The resource in a cluster drifted from the federated resource
+	// and was not updated.
+	NotificationDriftDetected NotificationEvent = "DriftDetected"
+)
+
+// PropagatedMetadataConfig defines the standard labels and annotations
+// added to propagated resources so that tooling in member clusters can
+// identify them.
+type PropagatedMetadataConfig struct {
+	// Whether to label resources with the name of the member cluster
+	// they are propagated to (kubefed.io/cluster-name).
+	// +optional
+	ClusterNameLabel bool `json:"clusterNameLabel,omitempty"`
+	// Whether to annotate resources with the UID of the federated
+	// resource they are propagated from
+	// (kubefed.io/federated-resource-uid).
+	// +optional
+	FederatedResourceUIDAnnotation bool `json:"federatedResourceUIDAnnotation,omitempty"`
+	// Whether to annotate resources with the time they were last
+	// created or updated by KubeFed (kubefed.io/propagated-at).
+	// +optional
+	PropagationTimestampAnnotation bool `json:"propagationTimestampAnnotation,omitempty"`
+	// Keys of the labels of federated resources that are copied to
+	// the resources propagated from them (e.g. a team label).
+	// +optional
+	PassthroughLabels []string `json:"passthroughLabels,omitempty"`
 }
 
 type ResourceAdoption string
@@ -118,14 +335,127 @@ const (
 	AdoptResourcesDisabled ResourceAdoption = "Disabled"
 )
 
+type LoggingConfig struct {
+	// The format of log entries written by controllers. Supported
+	// options are `text` (default) and `json`.
+	// +optional
+	Format *LogFormat `json:"format,omitempty"`
+}
+
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
+type WebhookConfig struct {
+	// How the API server handles a failure to call the KubeFed
+	// admission webhooks. Supported options are `Fail` (default)
+	// and `Ignore`.
+	// +optional
+	FailurePolicy *WebhookFailurePolicy `json:"failurePolicy,omitempty"`
+	// Selects the namespaces whose KubeFed resources are subject to
+	// the admission webhooks. The selector installed with the webhooks
+	// is left unchanged if unset.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+type WebhookFailurePolicy string
+
+const (
+	WebhookFailurePolicyFail   WebhookFailurePolicy = "Fail"
+	WebhookFailurePolicyIgnore WebhookFailurePolicy = "Ignore"
+)
+
+type ComplianceConfig struct {
+	// The compliance mode of the control plane. Supported options
+	// are `Standard` (default) and `Restricted`. In the `Restricted`
+	// mode, connections to member clusters require TLS 1.2 or later
+	// with approved cipher suites, TLS validations of member clusters
+	// may not be disabled, and the controller manager makes no
+	// outbound calls other than to the API servers of the host and
+	// member clusters.
+	// +optional
+	Mode *ComplianceMode `json:"mode,omitempty"`
+}
+
+type ComplianceMode string
+
+const (
+	ComplianceModeStandard   ComplianceMode = "Standard"
+	ComplianceModeRestricted ComplianceMode = "Restricted"
+)
+
+// NamespaceSamenessConfig configures the verification that federated
+// namespaces are the same in every member cluster their resources are
+// placed in.
+type NamespaceSamenessConfig struct {
+	// Whether deviations are only reported or are also repaired.
+	// Supported options are `Report` (default) and `Repair`.
+	// +optional
+	Policy *NamespaceSamenessPolicy `json:"policy,omitempty"`
+	// How often federated namespaces are verified. Defaults to 5m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+type NamespaceSamenessPolicy string
+
+const (
+	// Deviations are reported by events and metrics.
+	NamespaceSamenessReport NamespaceSamenessPolicy = "Report"
+	// Deviations are reported and repaired in member clusters.
+	NamespaceSamenessRepair NamespaceSamenessPolicy = "Repair"
+)
+
+// KubeFedConfigStatus defines the observed state of KubeFedConfig
+type KubeFedConfigStatus struct {
+	// The version of the controller manager that was most recently
+	// started with this configuration. kubefedctl compares it with its
+	// own version to detect incompatible operations.
+	// +optional
+	ControllerVersion string `json:"controllerVersion,omitempty"`
+	// The compliance posture enforced by the controller manager that
+	// was most recently started with this configuration.
+	// +optional
+	Compliance *ComplianceStatus `json:"compliance,omitempty"`
+}
+
+// ComplianceStatus describes the compliance posture enforced by the
+// controller manager.
+type ComplianceStatus struct {
+	// The compliance mode in effect.
+	Mode ComplianceMode `json:"mode"`
+	// The minimum TLS version of connections to member clusters, if
+	// restricted.
+	// +optional
+	MinTLSVersion string `json:"minTLSVersion,omitempty"`
+	// The cipher suites connections to member clusters are restricted
+	// to, if any.
+	// +optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+	// The outbound integrations that are configured but disabled by
+	// the compliance mode, e.g. `tracing`.
+	// +optional
+	DisabledIntegrations []string `json:"disabledIntegrations,omitempty"`
+	// The time the posture was recorded.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=kubefedconfigs
+// +kubebuilder:subresource:status
 
 type KubeFedConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec KubeFedConfigSpec `json:"spec"`
+	// +optional
+	Status KubeFedConfigStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
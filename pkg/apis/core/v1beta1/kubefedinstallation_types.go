@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubeFedInstallationSpec describes the desired installation of a
+// KubeFed control plane.
+type KubeFedInstallationSpec struct {
+	// Version of KubeFed to install, e.g. `v0.4.0`. It selects the
+	// manifests of the release that are applied.
+	Version string `json:"version"`
+	// The scope of the control plane, `Cluster` (default) or
+	// `Namespaced`.
+	// +optional
+	Scope apiextv1b1.ResourceScope `json:"scope,omitempty"`
+	// KubeFedNamespace is the namespace the control plane is installed
+	// in.
+	KubeFedNamespace string `json:"kubefedNamespace"`
+	// ImageRepository replaces the repository of the images of the
+	// release, e.g. to install from a mirror.
+	// +optional
+	ImageRepository string `json:"imageRepository,omitempty"`
+	// Configuration of the admission webhooks, written to the
+	// KubeFedConfig of the control plane.
+	// +optional
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+	// Compute resources of the controller manager.
+	// +optional
+	ControllerManagerResources *apiv1.ResourceRequirements `json:"controllerManagerResources,omitempty"`
+	// Compute resources of the admission webhook.
+	// +optional
+	WebhookResources *apiv1.ResourceRequirements `json:"webhookResources,omitempty"`
+}
+
+// KubeFedInstallationStatus defines the observed state of
+// KubeFedInstallation
+type KubeFedInstallationStatus struct {
+	// The generation of the installation that was most recently
+	// applied.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The version of KubeFed whose control plane is installed and
+	// ready.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Conditions describe whether the installation was applied and
+	// whether the control plane is ready.
+	// +optional
+	Conditions []KubeFedInstallationCondition `json:"conditions,omitempty"`
+}
+
+type KubeFedInstallationConditionType string
+
+const (
+	// The manifests of the desired version were applied.
+	KubeFedInstallationApplied KubeFedInstallationConditionType = "Applied"
+	// The controller manager and admission webhook of the desired
+	// version are available.
+	KubeFedInstallationReady KubeFedInstallationConditionType = "Ready"
+)
+
+// KubeFedInstallationCondition describes the state of an installation.
+type KubeFedInstallationCondition struct {
+	// Type of the condition, Applied or Ready.
+	Type KubeFedInstallationConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status apiv1.ConditionStatus `json:"status"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name=version,type=string,JSONPath=.spec.version
+// +kubebuilder:printcolumn:name=installed,type=string,JSONPath=.status.version
+// +kubebuilder:printcolumn:name=kubefed-namespace,type=string,JSONPath=.spec.kubefedNamespace
+// +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:resource:path=kubefedinstallations,scope=Cluster
+// +kubebuilder:subresource:status
+
+// KubeFedInstallation describes a KubeFed control plane that the
+// KubeFed operator installs and upgrades in the host cluster.
+type KubeFedInstallation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KubeFedInstallationSpec `json:"spec"`
+	// +optional
+	Status KubeFedInstallationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubeFedInstallationList contains a list of KubeFedInstallation
+type KubeFedInstallationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubeFedInstallation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KubeFedInstallation{}, &KubeFedInstallationList{})
+}
+
+// GetCondition returns the condition of the given type, or nil if the
+// installation does not have one.
+func (i *KubeFedInstallation) GetCondition(conditionType KubeFedInstallationConditionType) *KubeFedInstallationCondition {
+	for j := range i.Status.Conditions {
+		if i.Status.Conditions[j].Type == conditionType {
+			return &i.Status.Conditions[j]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or replaces the condition of the given type.
+func (i *KubeFedInstallation) SetCondition(conditionType KubeFedInstallationConditionType, status apiv1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	condition := i.GetCondition(conditionType)
+	if condition == nil {
+		i.Status.Conditions = append(i.Status.Conditions, KubeFedInstallationCondition{Type: conditionType})
+		condition = &i.Status.Conditions[len(i.Status.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.LastTransitionTime = &now
+	}
+	condition.Status = status
+	condition.Reason = reason
+	condition.Message = message
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubeFedInstanceSpec defines the namespaces claimed by a KubeFed
+// control plane.
+type KubeFedInstanceSpec struct {
+	// KubeFedNamespace is the namespace in which the control plane
+	// is deployed.
+	KubeFedNamespace string `json:"kubefedNamespace"`
+	// TargetNamespace is the namespace containing the federated
+	// resources managed by the control plane. All namespaces are
+	// targeted if empty.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name=kubefed-namespace,type=string,JSONPath=.spec.kubefedNamespace
+// +kubebuilder:printcolumn:name=target-namespace,type=string,JSONPath=.spec.targetNamespace
+// +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:resource:path=kubefedinstances,scope=Cluster
+
+// KubeFedInstance registers a KubeFed control plane running in a host
+// cluster. The target namespaces of the control planes of a host
+// cluster may not overlap, which allows several namespace-scoped
+// control planes to be operated on behalf of different tenants.
+type KubeFedInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KubeFedInstanceSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubeFedInstanceList contains a list of KubeFedInstance
+type KubeFedInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubeFedInstance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KubeFedInstance{}, &KubeFedInstanceList{})
+}
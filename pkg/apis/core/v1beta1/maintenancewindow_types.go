@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaintenanceWindowSpec defines a recurring window during which
+// resources in a member cluster may be updated.
+type MaintenanceWindowSpec struct {
+	// Name of the KubeFedCluster the window applies to.
+	ClusterName string `json:"clusterName"`
+
+	// Cron expression with the fields minute, hour, day of month,
+	// month and day of week for the times at which the window opens
+	// (e.g. "0 2 * * 6" for 02:00 every Saturday).
+	Schedule string `json:"schedule"`
+
+	// How long the window stays open once it opens.
+	Duration metav1.Duration `json:"duration"`
+
+	// IANA name of the time zone the schedule is interpreted in
+	// (e.g. Europe/Berlin). UTC if omitted.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=maintenancewindows
+
+// MaintenanceWindow defines a recurring window during which the
+// resources propagated to a member cluster may be updated. Once a
+// cluster has one or more MaintenanceWindows, updates to its resources
+// are deferred while none of its windows is open, unless the federated
+// resource is annotated as critical. MaintenanceWindows are only
+// honored when the MaintenanceWindows feature gate is enabled.
+type MaintenanceWindow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MaintenanceWindowSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// MaintenanceWindowList contains a list of MaintenanceWindow
+type MaintenanceWindowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaintenanceWindow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MaintenanceWindow{}, &MaintenanceWindowList{})
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NamespaceProfileSpec defines the baseline resources of the federated
+// namespaces with a profile.
+type NamespaceProfileSpec struct {
+	// Resources propagated to every federated namespace labeled with
+	// kubefed.io/namespace-profile set to the name of the profile
+	// (e.g. LimitRanges, ResourceQuotas, NetworkPolicies and
+	// RoleBindings). Each resource must provide its apiVersion, kind
+	// and name, and its type must be enabled for propagation. The
+	// namespace of the resources is that of the federated namespace.
+	Resources []runtime.RawExtension `json:"resources"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=namespaceprofiles
+
+// NamespaceProfile defines a bundle of resources that is propagated to
+// every federated namespace with the profile, so that namespaces share
+// a consistent baseline across the fleet. NamespaceProfiles are only
+// honored when the NamespaceProfiles feature gate is enabled.
+type NamespaceProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NamespaceProfileSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceProfileList contains a list of NamespaceProfile
+type NamespaceProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceProfile{}, &NamespaceProfileList{})
+}
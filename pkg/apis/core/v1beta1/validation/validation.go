@@ -17,16 +17,25 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apimachineryval "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	valutil "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/rest"
@@ -35,6 +44,8 @@ import (
 	"sigs.k8s.io/kubefed/pkg/apis/core/common"
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
 	"sigs.k8s.io/kubefed/pkg/features"
 )
 
@@ -62,15 +73,250 @@ func ValidateFederatedTypeConfigName(obj *v1beta1.FederatedTypeConfig) field.Err
 func ValidateFederatedTypeConfigSpec(spec *v1beta1.FederatedTypeConfigSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := ValidateAPIResource(&spec.TargetType, fldPath.Child("targetType"))
 	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("propagation"), string(spec.Propagation), []string{string(v1beta1.PropagationEnabled), string(v1beta1.PropagationDisabled)})...)
+	if spec.ResourceDeletion != nil {
+		allErrs = append(allErrs, validateResourceDeletion(spec, fldPath.Child("resourceDeletion"))...)
+	}
+	if spec.PropagationMode != nil {
+		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("propagationMode"), string(*spec.PropagationMode), []string{string(v1beta1.PropagationModeCreateAndUpdate), string(v1beta1.PropagationModeCreateOnly)})...)
+	}
 	allErrs = append(allErrs, ValidateFederatedAPIResource(&spec.FederatedType, fldPath.Child("federatedType"))...)
 	if spec.StatusType != nil {
 		allErrs = append(allErrs, ValidateStatusAPIResource(spec.StatusType, fldPath.Child("statusType"))...)
 	}
 
+	if spec.ConflictResolution != nil {
+		allErrs = append(allErrs, validateConflictResolution(spec.ConflictResolution, fldPath.Child("conflictResolution"))...)
+	}
+
 	if spec.StatusCollection != nil {
 		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("statusCollection"), string(*spec.StatusCollection), []string{string(v1beta1.StatusCollectionEnabled), string(v1beta1.StatusCollectionDisabled)})...)
 	}
 
+	for i, path := range spec.StatusFields {
+		if len(path) == 0 || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("statusFields").Index(i), path,
+				"must be a non-empty path of fields separated by dots"))
+		}
+	}
+
+	if spec.HealthInterpreter != nil {
+		allErrs = append(allErrs, validateHealthInterpreter(spec.HealthInterpreter, fldPath.Child("healthInterpreter"))...)
+	}
+
+	for i := range spec.DispatchMutators {
+		allErrs = append(allErrs, validateDispatchMutator(&spec.DispatchMutators[i], fldPath.Child("dispatchMutators").Index(i))...)
+	}
+
+	webhookNames := sets.NewString()
+	for i := range spec.PropagationWebhooks {
+		webhook := &spec.PropagationWebhooks[i]
+		webhookPath := fldPath.Child("propagationWebhooks").Index(i)
+		allErrs = append(allErrs, validatePropagationWebhook(webhook, webhookPath)...)
+		if webhookNames.Has(webhook.Name) {
+			allErrs = append(allErrs, field.Duplicate(webhookPath.Child("name"), webhook.Name))
+		}
+		webhookNames.Insert(webhook.Name)
+	}
+
+	return allErrs
+}
+
+func validateResourceDeletion(spec *v1beta1.FederatedTypeConfigSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// Deleting namespaces would delete the resources of all types in
+	// them, including those whose propagation is still enabled.
+	if spec.TargetType.Group == "" && spec.TargetType.PluralName == common.NamespaceName {
+		allErrs = append(allErrs, field.Forbidden(path, "may not be set for namespaces"))
+	}
+	if deletesPerSecond := spec.ResourceDeletion.DeletesPerSecond; deletesPerSecond != nil && *deletesPerSecond < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("deletesPerSecond"), *deletesPerSecond, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateHealthInterpreter(interpreter *v1beta1.HealthInterpreter, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(interpreter.Healthy) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("healthy"), ""))
+	} else if err := health.ValidateExpression(interpreter.Healthy); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("healthy"), interpreter.Healthy, err.Error()))
+	}
+	if len(interpreter.Degraded) > 0 {
+		if err := health.ValidateExpression(interpreter.Degraded); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("degraded"), interpreter.Degraded, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateConflictResolution(resolution *v1beta1.ConflictResolution, path *field.Path) field.ErrorList {
+	allErrs := validateEnumStrings(path.Child("strategy"), string(resolution.Strategy), []string{
+		string(v1beta1.ConflictStrategyRetryWithRebase),
+		string(v1beta1.ConflictStrategyPreferFederated),
+		string(v1beta1.ConflictStrategyPreferLocalFields),
+	})
+
+	localFieldsPath := path.Child("localFields")
+	if resolution.Strategy == v1beta1.ConflictStrategyPreferLocalFields && len(resolution.LocalFields) == 0 {
+		allErrs = append(allErrs, field.Required(localFieldsPath, "must be provided for the PreferLocalFields strategy"))
+	}
+	if resolution.Strategy != v1beta1.ConflictStrategyPreferLocalFields && len(resolution.LocalFields) > 0 {
+		allErrs = append(allErrs, field.Forbidden(localFieldsPath, "may only be provided for the PreferLocalFields strategy"))
+	}
+	for i, path := range resolution.LocalFields {
+		if len(path) == 0 || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+			allErrs = append(allErrs, field.Invalid(localFieldsPath.Index(i), path,
+				"must be a non-empty path of fields separated by dots"))
+		} else if path == "metadata" || strings.HasPrefix(path, "metadata.") {
+			allErrs = append(allErrs, field.Invalid(localFieldsPath.Index(i), path,
+				"fields of the metadata may not be kept"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateNotificationSink(sink *v1beta1.NotificationSink, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(sink.Name) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+	}
+
+	if len(sink.URL) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("url"), ""))
+	} else if sinkURL, err := url.Parse(sink.URL); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("url"), sink.URL, err.Error()))
+	} else if sinkURL.Scheme != "https" || len(sinkURL.Host) == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("url"), sink.URL, "must be an https URL"))
+	}
+
+	for i, event := range sink.Events {
+		allErrs = append(allErrs, validateEnumStrings(path.Child("events").Index(i), string(event),
+			[]string{string(v1beta1.NotificationAllClustersReady), string(v1beta1.NotificationClusterFailed),
+				string(v1beta1.NotificationDriftDetected)})...)
+	}
+
+	if len(sink.PayloadTemplate) > 0 {
+		if _, err := util.ParseNotificationTemplate(sink.PayloadTemplate); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("payloadTemplate"), sink.PayloadTemplate, err.Error()))
+		}
+	}
+
+	if sink.TimeoutSeconds != nil && (*sink.TimeoutSeconds < 1 || *sink.TimeoutSeconds > 30) {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeoutSeconds"), *sink.TimeoutSeconds, "must be between 1 and 30"))
+	}
+
+	return allErrs
+}
+
+func validatePropagationWebhook(webhook *v1beta1.PropagationWebhook, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(webhook.Name) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+	}
+
+	if len(webhook.URL) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("url"), ""))
+	} else if webhookURL, err := url.Parse(webhook.URL); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("url"), webhook.URL, err.Error()))
+	} else if webhookURL.Scheme != "https" || len(webhookURL.Host) == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("url"), webhook.URL, "must be an https URL"))
+	}
+
+	if webhook.ClusterSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(webhook.ClusterSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("clusterSelector"), webhook.ClusterSelector, err.Error()))
+		}
+	}
+
+	if webhook.TimeoutSeconds != nil && (*webhook.TimeoutSeconds < 1 || *webhook.TimeoutSeconds > 30) {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeoutSeconds"), *webhook.TimeoutSeconds, "must be between 1 and 30"))
+	}
+
+	if len(webhook.FailurePolicy) > 0 {
+		allErrs = append(allErrs, validateEnumStrings(path.Child("failurePolicy"), string(webhook.FailurePolicy),
+			[]string{string(v1beta1.PropagationWebhookFail), string(v1beta1.PropagationWebhookIgnore)})...)
+	}
+
+	return allErrs
+}
+
+func validateDispatchMutator(mutator *v1beta1.DispatchMutatorConfig, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if mutator.ClusterSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(mutator.ClusterSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("clusterSelector"), mutator.ClusterSelector, err.Error()))
+		}
+	}
+
+	mutatorCount := 0
+	if mutator.ImageRewrite != nil {
+		mutatorCount++
+		if len(mutator.ImageRewrite.From) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("imageRewrite", "from"), ""))
+		}
+	}
+	if mutator.LabelInjection != nil {
+		mutatorCount++
+		labelsPath := path.Child("labelInjection", "labels")
+		if len(mutator.LabelInjection.Labels) == 0 {
+			allErrs = append(allErrs, field.Required(labelsPath, ""))
+		}
+		allErrs = append(allErrs, metav1validation.ValidateLabels(mutator.LabelInjection.Labels, labelsPath)...)
+	}
+	if mutator.NodeSelectorInjection != nil {
+		mutatorCount++
+		nodeSelectorPath := path.Child("nodeSelectorInjection", "nodeSelector")
+		if len(mutator.NodeSelectorInjection.NodeSelector) == 0 {
+			allErrs = append(allErrs, field.Required(nodeSelectorPath, ""))
+		}
+		allErrs = append(allErrs, metav1validation.ValidateLabels(mutator.NodeSelectorInjection.NodeSelector, nodeSelectorPath)...)
+	}
+	if mutator.ResourceRequestScaling != nil {
+		mutatorCount++
+		if mutator.ResourceRequestScaling.Percent <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("resourceRequestScaling", "percent"), mutator.ResourceRequestScaling.Percent, "must be greater than 0"))
+		}
+	}
+	if mutator.SubjectRewrite != nil {
+		mutatorCount++
+		allErrs = append(allErrs, validateSubjectRewrite(mutator.SubjectRewrite, path.Child("subjectRewrite"))...)
+	}
+
+	if mutatorCount != 1 {
+		allErrs = append(allErrs, field.Invalid(path, mutatorCount,
+			"exactly one of imageRewrite, labelInjection, nodeSelectorInjection, resourceRequestScaling or subjectRewrite must be provided"))
+	}
+	return allErrs
+}
+
+func validateSubjectRewrite(rewrite *v1beta1.SubjectRewriteMutator, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	mappingsPath := path.Child("mappings")
+	if len(rewrite.Mappings) == 0 {
+		allErrs = append(allErrs, field.Required(mappingsPath, ""))
+	}
+	subjects := sets.NewString()
+	for i, mapping := range rewrite.Mappings {
+		mappingPath := mappingsPath.Index(i)
+		allErrs = append(allErrs, validateEnumStrings(mappingPath.Child("kind"), mapping.Kind, []string{rbacv1.UserKind, rbacv1.GroupKind})...)
+		if len(mapping.From) == 0 {
+			allErrs = append(allErrs, field.Required(mappingPath.Child("from"), ""))
+		}
+		if len(mapping.To) == 0 {
+			allErrs = append(allErrs, field.Required(mappingPath.Child("to"), ""))
+		}
+		subject := mapping.Kind + "/" + mapping.From
+		if subjects.Has(subject) {
+			allErrs = append(allErrs, field.Duplicate(mappingPath.Child("from"), mapping.From))
+		}
+		subjects.Insert(subject)
+	}
+
 	return allErrs
 }
 
@@ -146,6 +392,13 @@ func ValidateFederatedTypeConfigStatus(status *v1beta1.FederatedTypeConfigStatus
 	if status.StatusController != nil {
 		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("statusController"), string(*status.StatusController), []string{string(v1beta1.ControllerStatusRunning), string(v1beta1.ControllerStatusNotRunning)})...)
 	}
+	if status.ResourceDeletion != nil {
+		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("resourceDeletion", "phase"), string(status.ResourceDeletion.Phase), []string{
+			string(v1beta1.ResourceDeletionRunning),
+			string(v1beta1.ResourceDeletionPaused),
+			string(v1beta1.ResourceDeletionComplete),
+		})...)
+	}
 	return allErrs
 }
 
@@ -161,11 +414,74 @@ func ValidateKubeFedCluster(obj *v1beta1.KubeFedCluster, statusSubResource bool)
 
 func validateKubeFedClusterSpec(spec *v1beta1.KubeFedClusterSpec, path *field.Path) field.ErrorList {
 	allErrs := validateAPIEndpoint(spec.APIEndpoint, path.Child("apiEndpoint"))
-	allErrs = append(allErrs, validateLocalSecretReference(&spec.SecretRef, path.Child("secretRef"))...)
+	if spec.UseServiceAccount {
+		if !spec.HostCluster {
+			allErrs = append(allErrs, field.Invalid(path.Child("useServiceAccount"), spec.UseServiceAccount, "may only be set for the host cluster"))
+		}
+		if spec.SecretRef.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("secretRef", "name"), "may not be set if useServiceAccount is set"))
+		}
+		if spec.SecretRef.External != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("secretRef", "external"), "may not be set if useServiceAccount is set"))
+		}
+	} else {
+		allErrs = append(allErrs, validateLocalSecretReference(&spec.SecretRef, path.Child("secretRef"))...)
+	}
 	allErrs = append(allErrs, validateDisabledTLSValidations(spec.DisabledTLSValidations, path.Child("disabledTLSValidations"))...)
+	if spec.ConnectivityProfile != "" {
+		allErrs = append(allErrs, validateEnumStrings(path.Child("connectivityProfile"), string(spec.ConnectivityProfile),
+			[]string{string(v1beta1.ConnectivityProfileStandard), string(v1beta1.ConnectivityProfileEdge)})...)
+	}
+	if spec.Network != nil {
+		networkPath := path.Child("network")
+		allErrs = append(allErrs, validateCIDR(spec.Network.PodCIDR, networkPath.Child("podCIDR"))...)
+		allErrs = append(allErrs, validateCIDR(spec.Network.ServiceCIDR, networkPath.Child("serviceCIDR"))...)
+	}
+	if spec.CostWeight < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("costWeight"), spec.CostWeight, "must be non-negative"))
+	}
+	if spec.OperationTimeout != nil {
+		allErrs = append(allErrs, validateDurationGreaterThan0(path.Child("operationTimeout"), spec.OperationTimeout)...)
+	}
+	if spec.ApplyRateLimit != nil {
+		allErrs = append(allErrs, validateApplyRateLimit(path.Child("applyRateLimit"), spec.ApplyRateLimit)...)
+	}
+	if spec.FaultDomain != nil {
+		allErrs = append(allErrs, validateClusterFaultDomain(spec.FaultDomain, path.Child("faultDomain"))...)
+	}
 	return allErrs
 }
 
+func validateClusterFaultDomain(faultDomain *v1beta1.ClusterFaultDomain, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if faultDomain.Provider == "" && faultDomain.Region == "" && faultDomain.Datacenter == "" {
+		return append(allErrs, field.Required(path, "at least one of provider, region or datacenter must be set"))
+	}
+	for _, attribute := range []struct{ name, value string }{
+		{"provider", faultDomain.Provider},
+		{"region", faultDomain.Region},
+		{"datacenter", faultDomain.Datacenter},
+	} {
+		if errs := valutil.IsValidLabelValue(attribute.value); errs != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child(attribute.name), attribute.value, strings.Join(errs, ",")))
+		}
+	}
+	if faultDomain.Datacenter != "" && faultDomain.Region == "" {
+		allErrs = append(allErrs, field.Required(path.Child("region"), "must be set if datacenter is set"))
+	}
+	return allErrs
+}
+
+func validateCIDR(cidr string, path *field.Path) field.ErrorList {
+	if cidr == "" {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return field.ErrorList{field.Invalid(path, cidr, err.Error())}
+	}
+	return nil
+}
+
 func validateKubeFedClusterStatus(status *v1beta1.KubeFedClusterStatus, path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -205,6 +521,13 @@ func validateAPIEndpoint(endpoint string, path *field.Path) field.ErrorList {
 
 func validateLocalSecretReference(secretRef *v1beta1.LocalSecretReference, path *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	if secretRef.External != nil {
+		if secretRef.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("name"), "may not be set if external is set"))
+		}
+		allErrs = append(allErrs, validateExternalSecretReference(secretRef.External, path.Child("external"))...)
+		return allErrs
+	}
 	if secretRef.Name == "" {
 		allErrs = append(allErrs, field.Required(path.Child("name"), ""))
 	} else if errs := valutil.IsDNS1123Subdomain(secretRef.Name); errs != nil {
@@ -213,6 +536,17 @@ func validateLocalSecretReference(secretRef *v1beta1.LocalSecretReference, path
 	return allErrs
 }
 
+func validateExternalSecretReference(secretRef *v1beta1.ExternalSecretReference, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if secretRef.Provider == "" {
+		allErrs = append(allErrs, field.Required(path.Child("provider"), ""))
+	}
+	if secretRef.Path == "" {
+		allErrs = append(allErrs, field.Required(path.Child("path"), ""))
+	}
+	return allErrs
+}
+
 func validateDisabledTLSValidations(disabledTLSValidations []v1beta1.TLSValidation, path *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -246,6 +580,668 @@ func validateClusterCondition(cc *v1beta1.ClusterCondition, path *field.Path) fi
 	return allErrs
 }
 
+func ValidateClusterGroup(obj *v1beta1.ClusterGroup) field.ErrorList {
+	return validateClusterGroupSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateClusterGroupSpec(spec *v1beta1.ClusterGroupSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	clustersPath := path.Child("clusters")
+	selectorPath := path.Child("clusterSelector")
+	if spec.Clusters != nil && spec.ClusterSelector != nil {
+		allErrs = append(allErrs, field.Invalid(path, spec, "only one of clusters or clusterSelector may be specified"))
+	} else if spec.Clusters == nil && spec.ClusterSelector == nil {
+		allErrs = append(allErrs, field.Required(path, "one of clusters or clusterSelector must be specified"))
+	}
+
+	existingNames := make(map[string]bool)
+	for i, name := range spec.Clusters {
+		if existingNames[name] {
+			allErrs = append(allErrs, field.Duplicate(clustersPath.Index(i), name))
+			continue
+		}
+		existingNames[name] = true
+		if errs := valutil.IsDNS1123Subdomain(name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(clustersPath.Index(i), name, strings.Join(errs, ",")))
+		}
+	}
+
+	if spec.ClusterSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.ClusterSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(selectorPath, spec.ClusterSelector, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func ValidateClusterAllowlist(obj *v1beta1.ClusterAllowlist) field.ErrorList {
+	return validateClusterAllowlistSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateClusterAllowlistSpec(spec *v1beta1.ClusterAllowlistSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(spec.Namespaces) == 0 && !spec.ClusterScoped {
+		allErrs = append(allErrs, field.Required(path, "one of namespaces or clusterScoped must be specified"))
+	}
+
+	namespacesPath := path.Child("namespaces")
+	existingNamespaces := make(map[string]bool)
+	for i, namespace := range spec.Namespaces {
+		if existingNamespaces[namespace] {
+			allErrs = append(allErrs, field.Duplicate(namespacesPath.Index(i), namespace))
+			continue
+		}
+		existingNamespaces[namespace] = true
+		if errs := valutil.IsDNS1123Label(namespace); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(namespacesPath.Index(i), namespace, strings.Join(errs, ",")))
+		}
+	}
+
+	clustersPath := path.Child("clusters")
+	if len(spec.Clusters) == 0 {
+		allErrs = append(allErrs, field.Required(clustersPath, ""))
+	}
+	existingNames := make(map[string]bool)
+	for i, name := range spec.Clusters {
+		if existingNames[name] {
+			allErrs = append(allErrs, field.Duplicate(clustersPath.Index(i), name))
+			continue
+		}
+		existingNames[name] = true
+		if errs := valutil.IsDNS1123Subdomain(name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(clustersPath.Index(i), name, strings.Join(errs, ",")))
+		}
+	}
+
+	return allErrs
+}
+
+func ValidateFaultInjection(obj *v1beta1.FaultInjection) field.ErrorList {
+	return validateFaultInjectionSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateFaultInjectionSpec(spec *v1beta1.FaultInjectionSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Clusters != nil && spec.ClusterSelector != nil {
+		allErrs = append(allErrs, field.Invalid(path, spec, "only one of clusters or clusterSelector may be specified"))
+	}
+	clustersPath := path.Child("clusters")
+	for i, name := range spec.Clusters {
+		if errs := valutil.IsDNS1123Subdomain(name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(clustersPath.Index(i), name, strings.Join(errs, ",")))
+		}
+	}
+	if spec.ClusterSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.ClusterSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("clusterSelector"), spec.ClusterSelector, err.Error()))
+		}
+	}
+
+	operations := []string{
+		string(v1beta1.FaultOperationCreate),
+		string(v1beta1.FaultOperationGet),
+		string(v1beta1.FaultOperationUpdate),
+		string(v1beta1.FaultOperationDelete),
+		string(v1beta1.FaultOperationList),
+		string(v1beta1.FaultOperationUpdateStatus),
+	}
+	for i, operation := range spec.Operations {
+		if !sets.NewString(operations...).Has(string(operation)) {
+			allErrs = append(allErrs, field.NotSupported(path.Child("operations").Index(i), operation, operations))
+		}
+	}
+
+	if spec.Latency != nil && spec.Latency.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("latency"), spec.Latency.Duration.String(), "must be greater than or equal to 0"))
+	}
+	if spec.ErrorPercentage < 0 || spec.ErrorPercentage > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("errorPercentage"), spec.ErrorPercentage, "must be between 0 and 100"))
+	}
+	if spec.ErrorCode != 0 && (spec.ErrorCode < 400 || spec.ErrorCode > 599) {
+		allErrs = append(allErrs, field.Invalid(path.Child("errorCode"), spec.ErrorCode, "must be between 400 and 599"))
+	}
+	if (spec.Latency == nil || spec.Latency.Duration == 0) && spec.ErrorPercentage == 0 {
+		allErrs = append(allErrs, field.Required(path, "one of latency or errorPercentage must be specified"))
+	}
+
+	return allErrs
+}
+
+func ValidateFederatedTemplate(obj *v1beta1.FederatedTemplate) field.ErrorList {
+	return validateFederatedTemplateSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateFederatedTemplateSpec(spec *v1beta1.FederatedTemplateSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	template := make(map[string]interface{})
+	if len(spec.Template.Raw) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("template"), ""))
+	} else if err := json.Unmarshal(spec.Template.Raw, &template); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("template"), string(spec.Template.Raw), "must be an object"))
+	}
+
+	names := sets.NewString()
+	for i, parameter := range spec.Parameters {
+		namePath := path.Child("parameters").Index(i).Child("name")
+		if errs := valutil.IsCIdentifier(parameter.Name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(namePath, parameter.Name, strings.Join(errs, ",")))
+		}
+		if names.Has(parameter.Name) {
+			allErrs = append(allErrs, field.Duplicate(namePath, parameter.Name))
+		}
+		names.Insert(parameter.Name)
+	}
+
+	return allErrs
+}
+
+func ValidateNamespaceProfile(obj *v1beta1.NamespaceProfile) field.ErrorList {
+	return validateNamespaceProfileSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateNamespaceProfileSpec(spec *v1beta1.NamespaceProfileSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	resourcesPath := path.Child("resources")
+	if len(spec.Resources) == 0 {
+		allErrs = append(allErrs, field.Required(resourcesPath, ""))
+	}
+	existingResources := sets.NewString()
+	for i, raw := range spec.Resources {
+		resourcePath := resourcesPath.Index(i)
+		resource := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(raw.Raw, resource); err != nil {
+			allErrs = append(allErrs, field.Invalid(resourcePath, string(raw.Raw), "must be an object"))
+			continue
+		}
+		if resource.APIVersion == "" {
+			allErrs = append(allErrs, field.Required(resourcePath.Child("apiVersion"), ""))
+		}
+		if resource.Kind == "" {
+			allErrs = append(allErrs, field.Required(resourcePath.Child("kind"), ""))
+		}
+		if resource.Name == "" {
+			allErrs = append(allErrs, field.Required(resourcePath.Child("metadata", "name"), ""))
+		}
+		if resource.Namespace != "" {
+			allErrs = append(allErrs, field.Forbidden(resourcePath.Child("metadata", "namespace"),
+				"the namespace of a resource is that of the federated namespace it is propagated to"))
+		}
+		key := fmt.Sprintf("%s/%s", resource.Kind, resource.Name)
+		if existingResources.Has(key) {
+			allErrs = append(allErrs, field.Duplicate(resourcePath, key))
+		}
+		existingResources.Insert(key)
+	}
+
+	return allErrs
+}
+
+func ValidateBlueprint(obj *v1beta1.Blueprint) field.ErrorList {
+	return validateBlueprintSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateBlueprintSpec(spec *v1beta1.BlueprintSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i := range spec.Parameters {
+		parameter := &spec.Parameters[i]
+		parameterPath := path.Child("parameters").Index(i)
+		if errs := valutil.IsCIdentifier(parameter.Name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(parameterPath.Child("name"), parameter.Name, strings.Join(errs, ",")))
+		}
+		if names.Has(parameter.Name) {
+			allErrs = append(allErrs, field.Duplicate(parameterPath.Child("name"), parameter.Name))
+		}
+		names.Insert(parameter.Name)
+		allErrs = append(allErrs, validateBlueprintParameter(parameter, parameterPath)...)
+	}
+
+	resourcesPath := path.Child("resources")
+	if len(spec.Resources) == 0 {
+		allErrs = append(allErrs, field.Required(resourcesPath, ""))
+	}
+	existingResources := sets.NewString()
+	for i, raw := range spec.Resources {
+		resourcePath := resourcesPath.Index(i)
+		resource := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(raw.Raw, resource); err != nil {
+			allErrs = append(allErrs, field.Invalid(resourcePath, string(raw.Raw), "must be an object"))
+			continue
+		}
+		if resource.APIVersion == "" {
+			allErrs = append(allErrs, field.Required(resourcePath.Child("apiVersion"), ""))
+		}
+		if resource.Kind == "" {
+			allErrs = append(allErrs, field.Required(resourcePath.Child("kind"), ""))
+		}
+		if resource.Name == "" {
+			allErrs = append(allErrs, field.Required(resourcePath.Child("metadata", "name"), ""))
+		}
+		if resource.Namespace != "" {
+			allErrs = append(allErrs, field.Forbidden(resourcePath.Child("metadata", "namespace"),
+				"the namespace of a resource is that of the instance it is created for"))
+		}
+		key := fmt.Sprintf("%s/%s", resource.Kind, resource.Name)
+		if existingResources.Has(key) {
+			allErrs = append(allErrs, field.Duplicate(resourcePath, key))
+		}
+		existingResources.Insert(key)
+	}
+
+	return allErrs
+}
+
+func validateBlueprintParameter(parameter *v1beta1.BlueprintParameter, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if parameter.Type != "" {
+		allErrs = validateEnumStrings(path.Child("type"), string(parameter.Type), []string{
+			string(v1beta1.BlueprintParameterTypeString),
+			string(v1beta1.BlueprintParameterTypeInteger),
+			string(v1beta1.BlueprintParameterTypeBoolean),
+		})
+		if len(allErrs) > 0 {
+			return allErrs
+		}
+	}
+
+	integer := parameter.Type == v1beta1.BlueprintParameterTypeInteger
+	if len(parameter.Pattern) > 0 {
+		if parameter.Type != "" && parameter.Type != v1beta1.BlueprintParameterTypeString {
+			allErrs = append(allErrs, field.Forbidden(path.Child("pattern"), "only supported for string parameters"))
+		} else if _, err := regexp.Compile(parameter.Pattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("pattern"), parameter.Pattern, err.Error()))
+		}
+	}
+	if parameter.Minimum != nil && !integer {
+		allErrs = append(allErrs, field.Forbidden(path.Child("minimum"), "only supported for integer parameters"))
+	}
+	if parameter.Maximum != nil && !integer {
+		allErrs = append(allErrs, field.Forbidden(path.Child("maximum"), "only supported for integer parameters"))
+	}
+	if parameter.Minimum != nil && parameter.Maximum != nil && *parameter.Minimum > *parameter.Maximum {
+		allErrs = append(allErrs, field.Invalid(path.Child("maximum"), *parameter.Maximum, "must be greater than or equal to minimum"))
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	for i, value := range parameter.Enum {
+		allErrs = append(allErrs, validateBlueprintParameterValue(parameter, value, false, path.Child("enum").Index(i))...)
+	}
+	if parameter.Default != nil {
+		allErrs = append(allErrs, validateBlueprintParameterValue(parameter, *parameter.Default, true, path.Child("default"))...)
+	}
+	return allErrs
+}
+
+// validateBlueprintParameterValue validates a value of the given
+// parameter against its type and constraints. The enum of the
+// parameter is only checked if checkEnum is true.
+func validateBlueprintParameterValue(parameter *v1beta1.BlueprintParameter, value string, checkEnum bool, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch parameter.Type {
+	case v1beta1.BlueprintParameterTypeInteger:
+		integer, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return append(allErrs, field.Invalid(path, value, "must be an integer"))
+		}
+		if parameter.Minimum != nil && integer < *parameter.Minimum {
+			allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("must be greater than or equal to %d", *parameter.Minimum)))
+		}
+		if parameter.Maximum != nil && integer > *parameter.Maximum {
+			allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("must be less than or equal to %d", *parameter.Maximum)))
+		}
+	case v1beta1.BlueprintParameterTypeBoolean:
+		if value != "true" && value != "false" {
+			return append(allErrs, field.Invalid(path, value, "must be true or false"))
+		}
+	default:
+		if len(parameter.Pattern) > 0 {
+			if matched, err := regexp.MatchString(parameter.Pattern, value); err == nil && !matched {
+				allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("must match %q", parameter.Pattern)))
+			}
+		}
+	}
+	if checkEnum && len(parameter.Enum) > 0 && !sets.NewString(parameter.Enum...).Has(value) {
+		allErrs = append(allErrs, field.NotSupported(path, value, parameter.Enum))
+	}
+	return allErrs
+}
+
+func ValidateBlueprintInstance(obj *v1beta1.BlueprintInstance) field.ErrorList {
+	return validateBlueprintInstanceSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateBlueprintInstanceSpec(spec *v1beta1.BlueprintInstanceSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	namePath := path.Child("blueprintRef", "name")
+	if len(spec.BlueprintRef.Name) == 0 {
+		allErrs = append(allErrs, field.Required(namePath, ""))
+	} else if errs := valutil.IsDNS1123Subdomain(spec.BlueprintRef.Name); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(namePath, spec.BlueprintRef.Name, strings.Join(errs, ",")))
+	}
+
+	return allErrs
+}
+
+// ValidateBlueprintInstanceParameters validates the parameters of the
+// given BlueprintInstance against those declared by its Blueprint.
+func ValidateBlueprintInstanceParameters(obj *v1beta1.BlueprintInstance, blueprint *v1beta1.Blueprint) field.ErrorList {
+	allErrs := field.ErrorList{}
+	path := field.NewPath("spec", "parameters")
+
+	declared := sets.NewString()
+	for i := range blueprint.Spec.Parameters {
+		parameter := &blueprint.Spec.Parameters[i]
+		declared.Insert(parameter.Name)
+		value, ok := obj.Spec.Parameters[parameter.Name]
+		if !ok {
+			if parameter.Default == nil {
+				allErrs = append(allErrs, field.Required(path.Key(parameter.Name),
+					fmt.Sprintf("required by Blueprint %q", blueprint.Name)))
+			}
+			continue
+		}
+		allErrs = append(allErrs, validateBlueprintParameterValue(parameter, value, true, path.Key(parameter.Name))...)
+	}
+
+	undeclared := []string{}
+	for name := range obj.Spec.Parameters {
+		if !declared.Has(name) {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		allErrs = append(allErrs, field.Forbidden(path.Key(name),
+			fmt.Sprintf("not declared by Blueprint %q", blueprint.Name)))
+	}
+
+	return allErrs
+}
+
+func ValidateMaintenanceWindow(obj *v1beta1.MaintenanceWindow) field.ErrorList {
+	return validateMaintenanceWindowSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateMaintenanceWindowSpec(spec *v1beta1.MaintenanceWindowSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.ClusterName == "" {
+		allErrs = append(allErrs, field.Required(path.Child("clusterName"), ""))
+	}
+	if _, err := util.ParseCronSchedule(spec.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("schedule"), spec.Schedule, err.Error()))
+	}
+	if spec.Duration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("duration"), spec.Duration.Duration.String(), "must be greater than 0"))
+	}
+	if spec.TimeZone != "" {
+		if _, err := time.LoadLocation(spec.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), spec.TimeZone, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func ValidateAutoEnablePolicy(obj *v1beta1.AutoEnablePolicy) field.ErrorList {
+	return validateAutoEnablePolicySpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateAutoEnablePolicySpec(spec *v1beta1.AutoEnablePolicySpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(spec.GroupSuffixes) == 0 && spec.Selector == nil {
+		allErrs = append(allErrs, field.Required(path, "one of groupSuffixes or selector must be specified"))
+	}
+
+	suffixesPath := path.Child("groupSuffixes")
+	existingSuffixes := make(map[string]bool)
+	for i, suffix := range spec.GroupSuffixes {
+		if existingSuffixes[suffix] {
+			allErrs = append(allErrs, field.Duplicate(suffixesPath.Index(i), suffix))
+			continue
+		}
+		existingSuffixes[suffix] = true
+		if errs := valutil.IsDNS1123Subdomain(suffix); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(suffixesPath.Index(i), suffix, strings.Join(errs, ",")))
+		} else if util.IsKubeFedGroup(suffix) {
+			allErrs = append(allErrs, field.Forbidden(suffixesPath.Index(i), "the types of KubeFed cannot be enabled automatically"))
+		}
+	}
+
+	if spec.Selector != nil {
+		allErrs = append(allErrs, validateLabelSelector(spec.Selector, path.Child("selector"))...)
+	}
+
+	if spec.FederatedGroup != "" {
+		if errs := valutil.IsDNS1123Subdomain(spec.FederatedGroup); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("federatedGroup"), spec.FederatedGroup, strings.Join(errs, ",")))
+		}
+	}
+
+	return allErrs
+}
+
+func ValidateFederatedApplication(obj *v1beta1.FederatedApplication) field.ErrorList {
+	return validateFederatedApplicationSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateFederatedApplicationSpec(spec *v1beta1.FederatedApplicationSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Selector == nil && len(spec.Resources) == 0 {
+		allErrs = append(allErrs, field.Required(path, "one of selector or resources must be specified"))
+	}
+	if spec.Selector != nil {
+		allErrs = append(allErrs, validateLabelSelector(spec.Selector, path.Child("selector"))...)
+	}
+
+	resourcesPath := path.Child("resources")
+	existingResources := make(map[v1beta1.FederatedApplicationResource]bool)
+	for i, resource := range spec.Resources {
+		resourcePath := resourcesPath.Index(i)
+		if existingResources[resource] {
+			allErrs = append(allErrs, field.Duplicate(resourcePath, resource))
+			continue
+		}
+		existingResources[resource] = true
+		if len(resource.Kind) == 0 {
+			allErrs = append(allErrs, field.Required(resourcePath.Child("kind"), ""))
+		}
+		if errs := valutil.IsDNS1123Subdomain(resource.Name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(resourcePath.Child("name"), resource.Name, strings.Join(errs, ",")))
+		}
+	}
+
+	if placement := spec.Placement; placement != nil {
+		placementPath := path.Child("placement")
+		for i, name := range placement.Clusters {
+			if errs := valutil.IsDNS1123Subdomain(name); len(errs) > 0 {
+				allErrs = append(allErrs, field.Invalid(placementPath.Child("clusters").Index(i), name, strings.Join(errs, ",")))
+			}
+		}
+		if placement.ClusterSelector != nil {
+			allErrs = append(allErrs, validateLabelSelector(placement.ClusterSelector, placementPath.Child("clusterSelector"))...)
+		}
+	}
+
+	if strategy := spec.RolloutStrategy; strategy != nil {
+		strategyPath := path.Child("rolloutStrategy")
+		allErrs = append(allErrs, validateEnumStrings(strategyPath.Child("type"), string(strategy.Type),
+			[]string{string(appsv1.RecreateDeploymentStrategyType), string(appsv1.RollingUpdateDeploymentStrategyType)})...)
+		if strategy.RollingUpdate != nil && strategy.Type != appsv1.RollingUpdateDeploymentStrategyType {
+			allErrs = append(allErrs, field.Forbidden(strategyPath.Child("rollingUpdate"), "may only be specified when type is RollingUpdate"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateLabelSelector(selector *metav1.LabelSelector, path *field.Path) field.ErrorList {
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(path, selector, err.Error())}
+	}
+	return nil
+}
+
+func ValidateClusterJoinRequest(obj *v1beta1.ClusterJoinRequest, statusSubResource bool) field.ErrorList {
+	if statusSubResource {
+		return validateClusterJoinRequestStatus(&obj.Status, field.NewPath("status"))
+	}
+	return validateClusterJoinRequestSpec(&obj.Spec, field.NewPath("spec"))
+}
+
+func validateClusterJoinRequestSpec(spec *v1beta1.ClusterJoinRequestSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	clusterNamePath := path.Child("clusterName")
+	if spec.ClusterName == "" {
+		allErrs = append(allErrs, field.Required(clusterNamePath, ""))
+	} else if errs := valutil.IsDNS1123Subdomain(spec.ClusterName); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(clusterNamePath, spec.ClusterName, strings.Join(errs, ",")))
+	}
+
+	allErrs = append(allErrs, validateAPIEndpoint(spec.APIEndpoint, path.Child("apiEndpoint"))...)
+	allErrs = append(allErrs, metav1validation.ValidateLabels(spec.ClusterLabels, path.Child("clusterLabels"))...)
+
+	return allErrs
+}
+
+func validateClusterJoinRequestStatus(status *v1beta1.ClusterJoinRequestStatus, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	conditionTypes := make(map[v1beta1.ClusterJoinRequestConditionType]corev1.ConditionStatus)
+	for i, condition := range status.Conditions {
+		conditionPath := path.Child("conditions").Index(i)
+		allErrs = append(allErrs, validateEnumStrings(conditionPath.Child("type"), string(condition.Type),
+			[]string{string(v1beta1.ClusterJoinRequestApproved), string(v1beta1.ClusterJoinRequestDenied), string(v1beta1.ClusterJoinRequestJoined)})...)
+		allErrs = append(allErrs, validateEnumStrings(conditionPath.Child("status"), string(condition.Status),
+			[]string{string(corev1.ConditionTrue), string(corev1.ConditionFalse), string(corev1.ConditionUnknown)})...)
+		if _, ok := conditionTypes[condition.Type]; ok {
+			allErrs = append(allErrs, field.Duplicate(conditionPath.Child("type"), condition.Type))
+		}
+		conditionTypes[condition.Type] = condition.Status
+	}
+
+	if conditionTypes[v1beta1.ClusterJoinRequestApproved] == corev1.ConditionTrue &&
+		conditionTypes[v1beta1.ClusterJoinRequestDenied] == corev1.ConditionTrue {
+		allErrs = append(allErrs, field.Invalid(path.Child("conditions"), status.Conditions, "a request may not be both approved and denied"))
+	}
+
+	return allErrs
+}
+
+// ValidateKubeFedInstance validates the given instance and ensures
+// that its target namespace does not overlap with that of any of the
+// given existing instances.
+func ValidateKubeFedInstance(obj *v1beta1.KubeFedInstance, instances []v1beta1.KubeFedInstance) field.ErrorList {
+	allErrs := field.ErrorList{}
+	path := field.NewPath("spec")
+
+	kubeFedNamespacePath := path.Child("kubefedNamespace")
+	if obj.Spec.KubeFedNamespace == "" {
+		allErrs = append(allErrs, field.Required(kubeFedNamespacePath, ""))
+	} else if errs := valutil.IsDNS1123Label(obj.Spec.KubeFedNamespace); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(kubeFedNamespacePath, obj.Spec.KubeFedNamespace, strings.Join(errs, ",")))
+	}
+	targetNamespacePath := path.Child("targetNamespace")
+	if obj.Spec.TargetNamespace != "" {
+		if errs := valutil.IsDNS1123Label(obj.Spec.TargetNamespace); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(targetNamespacePath, obj.Spec.TargetNamespace, strings.Join(errs, ",")))
+		}
+	}
+
+	for _, instance := range instances {
+		if instance.Name == obj.Name {
+			continue
+		}
+		if obj.Spec.TargetNamespace == "" || instance.Spec.TargetNamespace == "" || obj.Spec.TargetNamespace == instance.Spec.TargetNamespace {
+			allErrs = append(allErrs, field.Forbidden(targetNamespacePath,
+				fmt.Sprintf("overlaps with the target namespace of KubeFedInstance %q", instance.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+// installationVersionRegexp matches the versions of KubeFed releases,
+// which name the manifests of an installation.
+var installationVersionRegexp = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.]+)?$`)
+
+// ValidateKubeFedInstallation validates an installation of a control
+// plane against the other installations of the host cluster, whose
+// control planes may not overlap.
+func ValidateKubeFedInstallation(obj *v1beta1.KubeFedInstallation, installations []v1beta1.KubeFedInstallation) field.ErrorList {
+	allErrs := field.ErrorList{}
+	spec := obj.Spec
+	path := field.NewPath("spec")
+
+	if spec.Version == "" {
+		allErrs = append(allErrs, field.Required(path.Child("version"), ""))
+	} else if !installationVersionRegexp.MatchString(spec.Version) {
+		allErrs = append(allErrs, field.Invalid(path.Child("version"), spec.Version, "must be the version of a KubeFed release, e.g. v0.4.0"))
+	}
+
+	if len(spec.Scope) > 0 {
+		allErrs = append(allErrs, validateEnumStrings(path.Child("scope"), string(spec.Scope),
+			[]string{string(apiextv1b1.ClusterScoped), string(apiextv1b1.NamespaceScoped)})...)
+	}
+
+	kubeFedNamespacePath := path.Child("kubefedNamespace")
+	if spec.KubeFedNamespace == "" {
+		allErrs = append(allErrs, field.Required(kubeFedNamespacePath, ""))
+	} else if errs := valutil.IsDNS1123Label(spec.KubeFedNamespace); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(kubeFedNamespacePath, spec.KubeFedNamespace, strings.Join(errs, ",")))
+	}
+
+	if strings.ContainsAny(spec.ImageRepository, ":@") {
+		allErrs = append(allErrs, field.Invalid(path.Child("imageRepository"), spec.ImageRepository, "must not include a tag or digest"))
+	}
+
+	if spec.Webhook != nil {
+		allErrs = append(allErrs, validateWebhookConfig(spec.Webhook, path.Child("webhook"))...)
+	}
+
+	for _, installation := range installations {
+		if installation.Name == obj.Name {
+			continue
+		}
+		if installation.Spec.KubeFedNamespace == spec.KubeFedNamespace {
+			allErrs = append(allErrs, field.Forbidden(kubeFedNamespacePath,
+				fmt.Sprintf("is the namespace of KubeFedInstallation %q", installation.Name)))
+		} else if spec.Scope != apiextv1b1.NamespaceScoped || installation.Spec.Scope != apiextv1b1.NamespaceScoped {
+			allErrs = append(allErrs, field.Forbidden(path.Child("scope"),
+				fmt.Sprintf("only namespace-scoped control planes may be installed alongside KubeFedInstallation %q", installation.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateWebhookConfig(webhook *v1beta1.WebhookConfig, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if webhook.FailurePolicy != nil {
+		allErrs = append(allErrs, validateEnumStrings(path.Child("failurePolicy"), string(*webhook.FailurePolicy),
+			[]string{string(v1beta1.WebhookFailurePolicyFail), string(v1beta1.WebhookFailurePolicyIgnore)})...)
+	}
+	if webhook.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(webhook.NamespaceSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("namespaceSelector"), webhook.NamespaceSelector, err.Error()))
+		}
+	}
+	return allErrs
+}
+
 func ValidateKubeFedConfig(kubeFedConfig, oldKubeFedConfig *v1beta1.KubeFedConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -313,7 +1309,38 @@ func ValidateKubeFedConfig(kubeFedConfig, oldKubeFedConfig *v1beta1.KubeFedConfi
 
 			allErrs = append(allErrs, validateEnumStrings(gatesPath.Child("name"), string(gate.Name),
 				[]string{string(features.PushReconciler), string(features.SchedulerPreferences),
-					string(features.CrossClusterServiceDiscovery), string(features.FederatedIngress)})...)
+					string(features.CrossClusterServiceDiscovery), string(features.FederatedIngress),
+					string(features.MultiClusterServices),
+					string(features.CrossClusterEndpoints),
+					string(features.PlacementDecisions),
+					string(features.ClusterJoinRequests),
+					string(features.DispatchJournal),
+					string(features.FederatedApplications),
+					string(features.ClusterBackfill),
+					string(features.ClusterQuarantine),
+					string(features.ControlPlaneInstances),
+					string(features.PullSecretReplication),
+					string(features.AdaptiveStatusCollection),
+					string(features.StatusCompanionObjects),
+					string(features.DependencyValidation),
+					string(features.FaultInjection),
+					string(features.PropagationProbe),
+					string(features.DifferentialPropagation),
+					string(features.FederatedTemplates),
+					string(features.DiscoveryCache),
+					string(features.SharedClusterTransport),
+					string(features.NamespaceProfiles),
+					string(features.MaintenanceWindows),
+					string(features.SchemaAwareComparison),
+					string(features.Blueprints),
+					string(features.NamespaceSameness),
+					string(features.ClusterAllowlists),
+					string(features.ResumableStatusWatches),
+					string(features.StaleClusterRecordCleanup),
+					string(features.NamespaceProjection),
+					string(features.AutoEnablePolicies),
+					string(features.RevisionHistory),
+					string(features.HealthInterpretation)})...)
 
 			allErrs = append(allErrs, validateEnumStrings(gatesPath.Child("configuration"), string(gate.Configuration),
 				[]string{string(v1beta1.ConfigurationEnabled), string(v1beta1.ConfigurationDisabled)})...)
@@ -327,8 +1354,22 @@ func ValidateKubeFedConfig(kubeFedConfig, oldKubeFedConfig *v1beta1.KubeFedConfi
 	} else {
 		allErrs = append(allErrs, validateDurationGreaterThan0(healthPath.Child("period"), health.Period)...)
 		allErrs = append(allErrs, validateIntPtrGreaterThan0(healthPath.Child("failureThreshold"), health.FailureThreshold)...)
+		if health.EdgeFailureThreshold != nil {
+			allErrs = append(allErrs, validateGreaterThan0(healthPath.Child("edgeFailureThreshold"), *health.EdgeFailureThreshold)...)
+		}
 		allErrs = append(allErrs, validateIntPtrGreaterThan0(healthPath.Child("successThreshold"), health.SuccessThreshold)...)
 		allErrs = append(allErrs, validateDurationGreaterThan0(healthPath.Child("timeout"), health.Timeout)...)
+		if health.Deadline != nil {
+			allErrs = append(allErrs, validateDurationGreaterThan0(healthPath.Child("deadline"), health.Deadline)...)
+		}
+		if health.Workers != nil {
+			allErrs = append(allErrs, validateGreaterThan0(healthPath.Child("workers"), *health.Workers)...)
+		}
+		if health.JitterPercentage != nil {
+			if percentage := *health.JitterPercentage; percentage < 0 || percentage > 100 {
+				allErrs = append(allErrs, field.Invalid(healthPath.Child("jitterPercentage"), percentage, "must be between 0 and 100"))
+			}
+		}
 	}
 
 	sync := spec.SyncController
@@ -342,10 +1383,130 @@ func ValidateKubeFedConfig(kubeFedConfig, oldKubeFedConfig *v1beta1.KubeFedConfi
 		allErrs = append(allErrs, validateEnumStrings(adoptPath, string(*sync.AdoptResources),
 			[]string{string(v1beta1.AdoptResourcesEnabled), string(v1beta1.AdoptResourcesDisabled)})...)
 	}
+	if sync != nil && sync.ClusterApplyRateLimit != nil {
+		allErrs = append(allErrs, validateApplyRateLimit(syncPath.Child("clusterApplyRateLimit"), sync.ClusterApplyRateLimit)...)
+	}
+	if sync != nil && sync.ClusterOperationTimeout != nil {
+		allErrs = append(allErrs, validateDurationGreaterThan0(syncPath.Child("clusterOperationTimeout"), sync.ClusterOperationTimeout)...)
+	}
+	if sync != nil && sync.DebounceWindow != nil {
+		allErrs = append(allErrs, validateDurationGreaterThan0(syncPath.Child("debounceWindow"), sync.DebounceWindow)...)
+	}
+	if sync != nil && sync.DeletionHold != nil {
+		allErrs = append(allErrs, validateDurationGreaterThan0(syncPath.Child("deletionHold"), sync.DeletionHold)...)
+	}
+	if sync != nil && sync.ImpersonateServiceAccount != "" {
+		for _, msg := range valutil.IsDNS1123Subdomain(sync.ImpersonateServiceAccount) {
+			allErrs = append(allErrs, field.Invalid(syncPath.Child("impersonateServiceAccount"), sync.ImpersonateServiceAccount, msg))
+		}
+	}
+	if sync != nil {
+		sinksPath := syncPath.Child("notificationSinks")
+		existingNames := sets.NewString()
+		for i := range sync.NotificationSinks {
+			sink := &sync.NotificationSinks[i]
+			if existingNames.Has(sink.Name) {
+				allErrs = append(allErrs, field.Duplicate(sinksPath.Index(i).Child("name"), sink.Name))
+			}
+			existingNames.Insert(sink.Name)
+			allErrs = append(allErrs, validateNotificationSink(sink, sinksPath.Index(i))...)
+		}
+	}
+	if sync != nil && sync.PropagatedMetadata != nil {
+		passthroughPath := syncPath.Child("propagatedMetadata", "passthroughLabels")
+		for i, key := range sync.PropagatedMetadata.PassthroughLabels {
+			for _, msg := range valutil.IsQualifiedName(key) {
+				allErrs = append(allErrs, field.Invalid(passthroughPath.Index(i), key, msg))
+			}
+		}
+	}
+	if sync != nil && sync.Quarantine != nil {
+		quarantine := sync.Quarantine
+		quarantinePath := syncPath.Child("quarantine")
+		percentagePath := quarantinePath.Child("failurePercentage")
+		if quarantine.FailurePercentage == nil {
+			allErrs = append(allErrs, field.Required(percentagePath, ""))
+		} else if percentage := *quarantine.FailurePercentage; percentage < 1 || percentage > 100 {
+			allErrs = append(allErrs, field.Invalid(percentagePath, percentage, "must be between 1 and 100"))
+		}
+		operationsPath := quarantinePath.Child("minimumOperations")
+		if quarantine.MinimumOperations == nil {
+			allErrs = append(allErrs, field.Required(operationsPath, ""))
+		} else {
+			allErrs = append(allErrs, validateGreaterThan0(operationsPath, int64(*quarantine.MinimumOperations))...)
+		}
+		allErrs = append(allErrs, validateDurationGreaterThan0(quarantinePath.Child("window"), quarantine.Window)...)
+		if quarantine.ReleaseAfter != nil {
+			allErrs = append(allErrs, validateDurationGreaterThan0(quarantinePath.Child("releaseAfter"), quarantine.ReleaseAfter)...)
+		}
+	}
+	if sync != nil && sync.RevisionHistoryLimit != nil && *sync.RevisionHistoryLimit < 1 {
+		allErrs = append(allErrs, field.Invalid(syncPath.Child("revisionHistoryLimit"), *sync.RevisionHistoryLimit, "must be greater than 0"))
+	}
+	if sync != nil && sync.SlowClusterThreshold != nil {
+		allErrs = append(allErrs, validateDurationGreaterThan0(syncPath.Child("slowClusterThreshold"), sync.SlowClusterThreshold)...)
+	}
+
+	if spec.StatusController != nil && spec.StatusController.AdaptiveCollection != nil {
+		collection := spec.StatusController.AdaptiveCollection
+		collectionPath := specPath.Child("statusController", "adaptiveCollection")
+		minIntervalPath := collectionPath.Child("minInterval")
+		maxIntervalPath := collectionPath.Child("maxInterval")
+		allErrs = append(allErrs, validateDurationGreaterThan0(minIntervalPath, collection.MinInterval)...)
+		allErrs = append(allErrs, validateDurationGreaterThan0(maxIntervalPath, collection.MaxInterval)...)
+		if collection.MinInterval != nil && collection.MaxInterval != nil &&
+			collection.MaxInterval.Duration < collection.MinInterval.Duration {
+			allErrs = append(allErrs, field.Invalid(maxIntervalPath, collection.MaxInterval.Duration.String(),
+				"must be greater than or equal to minInterval"))
+		}
+		stablePath := collectionPath.Child("stableCollections")
+		if collection.StableCollections == nil {
+			allErrs = append(allErrs, field.Required(stablePath, ""))
+		} else {
+			allErrs = append(allErrs, validateGreaterThan0(stablePath, int64(*collection.StableCollections))...)
+		}
+	}
+
+	// Logging configuration is optional to remain compatible with
+	// configurations created before it was introduced.
+	logging := spec.Logging
+	if logging != nil && logging.Format != nil {
+		allErrs = append(allErrs, validateEnumStrings(specPath.Child("logging", "format"), string(*logging.Format),
+			[]string{string(v1beta1.LogFormatText), string(v1beta1.LogFormatJSON)})...)
+	}
+
+	if spec.Webhook != nil {
+		allErrs = append(allErrs, validateWebhookConfig(spec.Webhook, specPath.Child("webhook"))...)
+	}
+
+	compliance := spec.Compliance
+	if compliance != nil && compliance.Mode != nil {
+		allErrs = append(allErrs, validateEnumStrings(specPath.Child("compliance", "mode"), string(*compliance.Mode),
+			[]string{string(v1beta1.ComplianceModeStandard), string(v1beta1.ComplianceModeRestricted)})...)
+	}
+
+	if sameness := spec.NamespaceSameness; sameness != nil {
+		samenessPath := specPath.Child("namespaceSameness")
+		if sameness.Policy != nil {
+			allErrs = append(allErrs, validateEnumStrings(samenessPath.Child("policy"), string(*sameness.Policy),
+				[]string{string(v1beta1.NamespaceSamenessReport), string(v1beta1.NamespaceSamenessRepair)})...)
+		}
+		if sameness.Interval != nil && sameness.Interval.Duration < time.Minute {
+			allErrs = append(allErrs, field.Invalid(samenessPath.Child("interval"), sameness.Interval.Duration.String(), "must be at least 1m"))
+		}
+	}
 
 	return allErrs
 }
 
+func validateApplyRateLimit(path *field.Path, limit *v1beta1.ApplyRateLimit) field.ErrorList {
+	errs := validateGreaterThan0(path.Child("objectsPerSecond"), limit.ObjectsPerSecond)
+	if limit.Burst < 0 {
+		errs = append(errs, field.Invalid(path.Child("burst"), limit.Burst, "must be non-negative"))
+	}
+	return errs
+}
+
 func validateDurationGreaterThan0(path *field.Path, duration *metav1.Duration) field.ErrorList {
 	errs := field.ErrorList{}
 	if duration == nil {
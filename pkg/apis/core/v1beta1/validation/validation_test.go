@@ -22,9 +22,12 @@ import (
 	"testing"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/common"
@@ -115,11 +118,128 @@ func TestValidateFederatedTypeConfigSpec(t *testing.T) {
 	invalidPropagation.Spec.Propagation = "InvalidPropagationMode"
 	errorCases["spec.propagation: Unsupported value"] = invalidPropagation
 
+	invalidPropagationMode := validFederatedTypeConfig()
+	var invalidResourcePropagationMode v1beta1.ResourcePropagationMode = "InvalidPropagationMode"
+	invalidPropagationMode.Spec.PropagationMode = &invalidResourcePropagationMode
+	errorCases["spec.propagationMode: Unsupported value"] = invalidPropagationMode
+
+	invalidConflictStrategy := validFederatedTypeConfig()
+	invalidConflictStrategy.Spec.ConflictResolution = &v1beta1.ConflictResolution{Strategy: "PreferCluster"}
+	errorCases["spec.conflictResolution.strategy: Unsupported value"] = invalidConflictStrategy
+
+	localFieldsRequired := validFederatedTypeConfig()
+	localFieldsRequired.Spec.ConflictResolution = &v1beta1.ConflictResolution{Strategy: v1beta1.ConflictStrategyPreferLocalFields}
+	errorCases["spec.conflictResolution.localFields: Required value"] = localFieldsRequired
+
+	localFieldsForbidden := validFederatedTypeConfig()
+	localFieldsForbidden.Spec.ConflictResolution = &v1beta1.ConflictResolution{
+		Strategy:    v1beta1.ConflictStrategyRetryWithRebase,
+		LocalFields: []string{"spec.replicas"},
+	}
+	errorCases["spec.conflictResolution.localFields: Forbidden"] = localFieldsForbidden
+
+	invalidLocalField := validFederatedTypeConfig()
+	invalidLocalField.Spec.ConflictResolution = &v1beta1.ConflictResolution{
+		Strategy:    v1beta1.ConflictStrategyPreferLocalFields,
+		LocalFields: []string{"spec.replicas", "metadata.name"},
+	}
+	errorCases["spec.conflictResolution.localFields[1]: Invalid value"] = invalidLocalField
+
 	invalidStatusCollection := validFederatedTypeConfig()
 	var invalidStatusCollectionMode v1beta1.StatusCollectionMode = "InvalidStatusCollectionMode"
 	invalidStatusCollection.Spec.StatusCollection = &invalidStatusCollectionMode
 	errorCases["spec.statusCollection: Unsupported value"] = invalidStatusCollection
 
+	emptyStatusField := validFederatedTypeConfig()
+	emptyStatusField.Spec.StatusFields = []string{"availableReplicas", ""}
+	errorCases["spec.statusFields[1]: Invalid value"] = emptyStatusField
+
+	invalidStatusFieldPath := validFederatedTypeConfig()
+	invalidStatusFieldPath.Spec.StatusFields = []string{"loadBalancer..ingress"}
+	errorCases["spec.statusFields[0]: Invalid value"] = invalidStatusFieldPath
+
+	noHealthyExpression := validFederatedTypeConfig()
+	noHealthyExpression.Spec.HealthInterpreter = &v1beta1.HealthInterpreter{Degraded: "self.status.phase == 'Failed'"}
+	errorCases["spec.healthInterpreter.healthy: Required value"] = noHealthyExpression
+
+	invalidDegradedExpression := validFederatedTypeConfig()
+	invalidDegradedExpression.Spec.HealthInterpreter = &v1beta1.HealthInterpreter{
+		Healthy:  "self.status.phase == 'Running'",
+		Degraded: "status.phase == 'Failed'",
+	}
+	errorCases["spec.healthInterpreter.degraded: Invalid value"] = invalidDegradedExpression
+
+	noMutator := validFederatedTypeConfig()
+	noMutator.Spec.DispatchMutators = []v1beta1.DispatchMutatorConfig{{}}
+	errorCases["spec.dispatchMutators[0]: Invalid value: 0: exactly one of"] = noMutator
+
+	multipleMutators := validFederatedTypeConfig()
+	multipleMutators.Spec.DispatchMutators[0].NodeSelectorInjection = &v1beta1.NodeSelectorInjectionMutator{
+		NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+	}
+	errorCases["spec.dispatchMutators[0]: Invalid value: 2: exactly one of"] = multipleMutators
+
+	imageFromRequired := validFederatedTypeConfig()
+	imageFromRequired.Spec.DispatchMutators[0].ImageRewrite.From = ""
+	errorCases["spec.dispatchMutators[0].imageRewrite.from: Required value"] = imageFromRequired
+
+	invalidLabel := validFederatedTypeConfig()
+	invalidLabel.Spec.DispatchMutators[1].LabelInjection.Labels = map[string]string{"team": "-invalid-"}
+	errorCases["spec.dispatchMutators[1].labelInjection.labels: Invalid value"] = invalidLabel
+
+	invalidMutatorSelector := validFederatedTypeConfig()
+	invalidMutatorSelector.Spec.DispatchMutators[2].ClusterSelector.MatchExpressions[0].Operator = "Unknown"
+	errorCases["spec.dispatchMutators[2].clusterSelector: Invalid value"] = invalidMutatorSelector
+
+	invalidPercent := validFederatedTypeConfig()
+	invalidPercent.Spec.DispatchMutators[2].ResourceRequestScaling.Percent = 0
+	errorCases["spec.dispatchMutators[2].resourceRequestScaling.percent: Invalid value"] = invalidPercent
+
+	invalidSubjectKind := validFederatedTypeConfig()
+	invalidSubjectKind.Spec.DispatchMutators[3].SubjectRewrite.Mappings[0].Kind = "ServiceAccount"
+	errorCases["spec.dispatchMutators[3].subjectRewrite.mappings[0].kind: Unsupported value"] = invalidSubjectKind
+
+	subjectToRequired := validFederatedTypeConfig()
+	subjectToRequired.Spec.DispatchMutators[3].SubjectRewrite.Mappings[1].To = ""
+	errorCases["spec.dispatchMutators[3].subjectRewrite.mappings[1].to: Required value"] = subjectToRequired
+
+	duplicateSubject := validFederatedTypeConfig()
+	duplicateSubject.Spec.DispatchMutators[3].SubjectRewrite.Mappings[1].Kind = "Group"
+	errorCases["spec.dispatchMutators[3].subjectRewrite.mappings[1].from: Duplicate value"] = duplicateSubject
+
+	webhookNameRequired := validFederatedTypeConfig()
+	webhookNameRequired.Spec.PropagationWebhooks[0].Name = ""
+	errorCases["spec.propagationWebhooks[0].name: Required value"] = webhookNameRequired
+
+	duplicateWebhookName := validFederatedTypeConfig()
+	duplicateWebhookName.Spec.PropagationWebhooks[1].Name = "change-window"
+	errorCases["spec.propagationWebhooks[1].name: Duplicate value"] = duplicateWebhookName
+
+	insecureWebhookURL := validFederatedTypeConfig()
+	insecureWebhookURL.Spec.PropagationWebhooks[0].URL = "http://change-window.example.com/review"
+	errorCases["spec.propagationWebhooks[0].url: Invalid value"] = insecureWebhookURL
+
+	invalidWebhookTimeout := validFederatedTypeConfig()
+	invalidWebhookTimeout.Spec.PropagationWebhooks[1].TimeoutSeconds = new(int32)
+	errorCases["spec.propagationWebhooks[1].timeoutSeconds: Invalid value"] = invalidWebhookTimeout
+
+	invalidFailurePolicy := validFederatedTypeConfig()
+	invalidFailurePolicy.Spec.PropagationWebhooks[1].FailurePolicy = "Retry"
+	errorCases["spec.propagationWebhooks[1].failurePolicy: Unsupported value"] = invalidFailurePolicy
+
+	invalidDeletesPerSecond := validFederatedTypeConfig()
+	invalidDeletesPerSecond.Spec.ResourceDeletion = &v1beta1.ResourceDeletion{DeletesPerSecond: new(int32)}
+	errorCases["spec.resourceDeletion.deletesPerSecond: Invalid value"] = invalidDeletesPerSecond
+
+	namespaceResourceDeletion := federatedTypeConfig(&metav1.APIResource{
+		Version:    "v1",
+		Kind:       "Namespace",
+		Name:       "namespaces",
+		Namespaced: false,
+	})
+	namespaceResourceDeletion.Spec.ResourceDeletion = &v1beta1.ResourceDeletion{}
+	errorCases["spec.resourceDeletion: Forbidden"] = namespaceResourceDeletion
+
 	for k, v := range errorCases {
 		errs := ValidateFederatedTypeConfigSpec(&v.Spec, field.NewPath("spec"))
 		if len(errs) == 0 {
@@ -302,6 +422,7 @@ func federatedTypeConfig(apiResource *metav1.APIResource) *v1beta1.FederatedType
 
 	kind := apiResource.Kind
 	pluralName := apiResource.Name
+	propagationMode := v1beta1.PropagationModeCreateAndUpdate
 	statusCollection := v1beta1.StatusCollectionEnabled
 	statusController := v1beta1.ControllerStatusNotRunning
 	ftc.Spec.StatusType = &v1beta1.APIResource{
@@ -311,7 +432,64 @@ func federatedTypeConfig(apiResource *metav1.APIResource) *v1beta1.FederatedType
 		PluralName: fmt.Sprintf("federated%sstatus", pluralName),
 		Scope:      enable.FederatedNamespacedToScope(*apiResource),
 	}
+	ftc.Spec.PropagationMode = &propagationMode
+	ftc.Spec.ConflictResolution = &v1beta1.ConflictResolution{
+		Strategy:    v1beta1.ConflictStrategyPreferLocalFields,
+		LocalFields: []string{"spec.replicas"},
+	}
 	ftc.Spec.StatusCollection = &statusCollection
+	ftc.Spec.DispatchMutators = []v1beta1.DispatchMutatorConfig{
+		{
+			ImageRewrite: &v1beta1.ImageRewriteMutator{
+				From: "docker.io/",
+				To:   "registry.example.com/",
+			},
+		},
+		{
+			LabelInjection: &v1beta1.LabelInjectionMutator{
+				Labels:      map[string]string{"team": "payments"},
+				PodTemplate: true,
+			},
+		},
+		{
+			ClusterSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      "kubefed.io/cluster-class",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"small"},
+					},
+				},
+			},
+			ResourceRequestScaling: &v1beta1.ResourceRequestScalingMutator{
+				Percent: 50,
+			},
+		},
+		{
+			SubjectRewrite: &v1beta1.SubjectRewriteMutator{
+				Mappings: []v1beta1.SubjectMapping{
+					{Kind: "Group", From: "platform-admins", To: "oidc:platform-admins"},
+					{Kind: "User", From: "platform-admins", To: "oidc:jane"},
+				},
+			},
+		},
+	}
+	timeoutSeconds := int32(5)
+	ftc.Spec.PropagationWebhooks = []v1beta1.PropagationWebhook{
+		{
+			Name: "change-window",
+			URL:  "https://change-window.example.com/review",
+		},
+		{
+			Name:           "image-scan",
+			URL:            "https://image-scan.example.com/review",
+			TimeoutSeconds: &timeoutSeconds,
+			FailurePolicy:  v1beta1.PropagationWebhookIgnore,
+			ClusterSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "production"},
+			},
+		},
+	}
 	ftc.Status = v1beta1.FederatedTypeConfigStatus{
 		ObservedGeneration:    1,
 		PropagationController: v1beta1.ControllerStatusRunning,
@@ -360,6 +538,81 @@ func TestValidateKubeFedCluster(t *testing.T) {
 		false,
 	}
 
+	invalidKFCConnectivityProfile := testcommon.ValidKubeFedCluster()
+	invalidKFCConnectivityProfile.Spec.ConnectivityProfile = "Satellite"
+	errorCases["connectivityProfile: Unsupported value"] = KFCAndStatusSubResource{
+		invalidKFCConnectivityProfile,
+		false,
+	}
+
+	invalidKFCPodCIDR := testcommon.ValidKubeFedCluster()
+	invalidKFCPodCIDR.Spec.Network = &v1beta1.ClusterNetwork{
+		PodCIDR:     "10.0.0.0",
+		ServiceCIDR: "10.96.0.0/12",
+	}
+	errorCases["network.podCIDR: Invalid value"] = KFCAndStatusSubResource{
+		invalidKFCPodCIDR,
+		false,
+	}
+
+	invalidKFCCostWeight := testcommon.ValidKubeFedCluster()
+	invalidKFCCostWeight.Spec.CostWeight = -1
+	errorCases["costWeight: Invalid value"] = KFCAndStatusSubResource{
+		invalidKFCCostWeight,
+		false,
+	}
+
+	invalidKFCOperationTimeout := testcommon.ValidKubeFedCluster()
+	invalidKFCOperationTimeout.Spec.OperationTimeout = &metav1.Duration{}
+	errorCases["operationTimeout: Invalid value"] = KFCAndStatusSubResource{
+		invalidKFCOperationTimeout,
+		false,
+	}
+
+	invalidKFCApplyRateLimit := testcommon.ValidKubeFedCluster()
+	invalidKFCApplyRateLimit.Spec.ApplyRateLimit = &v1beta1.ApplyRateLimit{ObjectsPerSecond: 10, Burst: -1}
+	errorCases["applyRateLimit.burst: Invalid value"] = KFCAndStatusSubResource{
+		invalidKFCApplyRateLimit,
+		false,
+	}
+
+	invalidKFCEmptyFaultDomain := testcommon.ValidKubeFedCluster()
+	invalidKFCEmptyFaultDomain.Spec.FaultDomain = &v1beta1.ClusterFaultDomain{}
+	errorCases["faultDomain: Required value"] = KFCAndStatusSubResource{
+		invalidKFCEmptyFaultDomain,
+		false,
+	}
+
+	invalidKFCFaultDomainRegion := testcommon.ValidKubeFedCluster()
+	invalidKFCFaultDomainRegion.Spec.FaultDomain = &v1beta1.ClusterFaultDomain{Provider: "aws", Datacenter: "us-east-1a"}
+	errorCases["faultDomain.region: Required value"] = KFCAndStatusSubResource{
+		invalidKFCFaultDomainRegion,
+		false,
+	}
+
+	invalidKFCFaultDomainProvider := testcommon.ValidKubeFedCluster()
+	invalidKFCFaultDomainProvider.Spec.FaultDomain = &v1beta1.ClusterFaultDomain{Provider: "on prem"}
+	errorCases["faultDomain.provider: Invalid value"] = KFCAndStatusSubResource{
+		invalidKFCFaultDomainProvider,
+		false,
+	}
+
+	invalidKFCUseServiceAccount := testcommon.ValidKubeFedCluster()
+	invalidKFCUseServiceAccount.Spec.UseServiceAccount = true
+	invalidKFCUseServiceAccount.Spec.SecretRef.Name = ""
+	errorCases["useServiceAccount: Invalid value"] = KFCAndStatusSubResource{
+		invalidKFCUseServiceAccount,
+		false,
+	}
+
+	invalidKFCSecretRefWithServiceAccount := testcommon.ValidKubeFedCluster()
+	invalidKFCSecretRefWithServiceAccount.Spec.UseServiceAccount = true
+	invalidKFCSecretRefWithServiceAccount.Spec.HostCluster = true
+	errorCases["secretRef.name: Forbidden"] = KFCAndStatusSubResource{
+		invalidKFCSecretRefWithServiceAccount,
+		false,
+	}
+
 	invalidKFCStatus := testcommon.ValidKubeFedCluster()
 	invalidKFCStatus.Status.Conditions[1].Type = ""
 	errorCases["conditions[1].type: Required value"] = KFCAndStatusSubResource{
@@ -518,6 +771,53 @@ func TestValidateLocalSecretReference(t *testing.T) {
 	}
 }
 
+func TestValidateExternalSecretReference(t *testing.T) {
+	testCases := []struct {
+		secretRef      v1beta1.LocalSecretReference
+		expectedErrMsg string
+	}{
+		{
+			secretRef: v1beta1.LocalSecretReference{
+				External: &v1beta1.ExternalSecretReference{Provider: "vault", Path: "secret/data/clusters/cluster1"},
+			},
+		},
+		{
+			secretRef: v1beta1.LocalSecretReference{
+				Name:     "validation-test-cluster1",
+				External: &v1beta1.ExternalSecretReference{Provider: "csi", Path: "cluster1-token"},
+			},
+			expectedErrMsg: "name: Forbidden",
+		},
+		{
+			secretRef: v1beta1.LocalSecretReference{
+				External: &v1beta1.ExternalSecretReference{Path: "cluster1-token"},
+			},
+			expectedErrMsg: "external.provider: Required value",
+		},
+		{
+			secretRef: v1beta1.LocalSecretReference{
+				External: &v1beta1.ExternalSecretReference{Provider: "csi"},
+			},
+			expectedErrMsg: "external.path: Required value",
+		},
+	}
+
+	for _, test := range testCases {
+		errs := validateLocalSecretReference(&test.secretRef, field.NewPath("secretRef"))
+		if test.expectedErrMsg == "" {
+			if len(errs) > 0 {
+				t.Errorf("unexpected error: %v", errs)
+			}
+			continue
+		}
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", test.expectedErrMsg)
+		} else if !strings.Contains(errs[0].Error(), test.expectedErrMsg) {
+			t.Errorf("unexpected error: %v, expected: %q", errs[0].Error(), test.expectedErrMsg)
+		}
+	}
+}
+
 func TestDisabledTLSValidations(t *testing.T) {
 	testCases := []struct {
 		disabledTLSValidations []v1beta1.TLSValidation
@@ -647,134 +947,1069 @@ func TestValidateClusterCondition(t *testing.T) {
 	}
 }
 
-func TestValidateKubeFedConfig(t *testing.T) {
-	errs := ValidateKubeFedConfig(testcommon.ValidKubeFedConfig(), testcommon.ValidKubeFedConfig())
-	if len(errs) != 0 {
-		t.Errorf("expected success: %v", errs)
+func TestValidateClusterGroup(t *testing.T) {
+	successCases := []*v1beta1.ClusterGroup{
+		validClusterGroup(),
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "selected",
+			},
+			Spec: v1beta1.ClusterGroupSpec{
+				ClusterSelector: &metav1.LabelSelector{},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "empty",
+			},
+			Spec: v1beta1.ClusterGroupSpec{
+				Clusters: []string{},
+			},
+		},
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateClusterGroup(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
 	}
 
-	errorCases := map[string]*v1beta1.KubeFedConfig{}
-
-	invalidScope := testcommon.ValidKubeFedConfig()
-	invalidScope.Spec.Scope = "NeitherClusterOrNamespaceScoped"
-	errorCases["spec.scope: Unsupported value"] = invalidScope
+	errorCases := map[string]*v1beta1.ClusterGroup{}
 
-	immutableScope := testcommon.ValidKubeFedConfig()
-	immutableScope.Spec.Scope = apiextv1b1.NamespaceScoped
-	errorCases[`spec.scope: Invalid value: "Namespaced": field is immutable`] = immutableScope
+	noMembers := validClusterGroup()
+	noMembers.Spec.Clusters = nil
+	errorCases["spec: Required value"] = noMembers
 
-	invalidControllerDurationNil := testcommon.ValidKubeFedConfig()
-	invalidControllerDurationNil.Spec.ControllerDuration = nil
-	errorCases["spec.controllerDuration: Required value"] = invalidControllerDurationNil
+	bothMembers := validClusterGroup()
+	bothMembers.Spec.ClusterSelector = &metav1.LabelSelector{}
+	errorCases["only one of clusters or clusterSelector may be specified"] = bothMembers
 
-	invalidAvailableDelayNil := testcommon.ValidKubeFedConfig()
-	invalidAvailableDelayNil.Spec.ControllerDuration.AvailableDelay = nil
-	errorCases["spec.controllerDuration.availableDelay: Required value"] = invalidAvailableDelayNil
+	duplicateCluster := validClusterGroup()
+	duplicateCluster.Spec.Clusters = append(duplicateCluster.Spec.Clusters, "cluster1")
+	errorCases["spec.clusters[2]: Duplicate value"] = duplicateCluster
 
-	invalidAvailableDelayGreaterThan0 := testcommon.ValidKubeFedConfig()
-	invalidAvailableDelayGreaterThan0.Spec.ControllerDuration.AvailableDelay.Duration = 0
-	errorCases["spec.controllerDuration.availableDelay: Invalid value"] = invalidAvailableDelayGreaterThan0
+	invalidCluster := validClusterGroup()
+	invalidCluster.Spec.Clusters[0] = "Invalid_Name"
+	errorCases["spec.clusters[0]: Invalid value"] = invalidCluster
 
-	invalidUnavailableDelayNil := testcommon.ValidKubeFedConfig()
-	invalidUnavailableDelayNil.Spec.ControllerDuration.UnavailableDelay = nil
-	errorCases["spec.controllerDuration.unavailableDelay: Required value"] = invalidUnavailableDelayNil
+	invalidSelector := validClusterGroup()
+	invalidSelector.Spec.Clusters = nil
+	invalidSelector.Spec.ClusterSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "foo",
+				Operator: "InvalidOperator",
+			},
+		},
+	}
+	errorCases["spec.clusterSelector: Invalid value"] = invalidSelector
 
-	invalidUnavailableDelayGreaterThan0 := testcommon.ValidKubeFedConfig()
-	invalidUnavailableDelayGreaterThan0.Spec.ControllerDuration.UnavailableDelay.Duration = 0
-	errorCases["spec.controllerDuration.unavailableDelay: Invalid value"] = invalidUnavailableDelayGreaterThan0
+	for k, v := range errorCases {
+		errs := ValidateClusterGroup(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
 
-	invalidLeaderElectNil := testcommon.ValidKubeFedConfig()
-	invalidLeaderElectNil.Spec.LeaderElect = nil
-	errorCases["spec.leaderElect: Required value"] = invalidLeaderElectNil
+func TestValidateClusterAllowlist(t *testing.T) {
+	successCases := []*v1beta1.ClusterAllowlist{
+		validClusterAllowlist(),
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "platform",
+			},
+			Spec: v1beta1.ClusterAllowlistSpec{
+				ClusterScoped: true,
+				Clusters:      []string{"cluster1"},
+			},
+		},
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateClusterAllowlist(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
 
-	invalidLeaseDurationNil := testcommon.ValidKubeFedConfig()
-	invalidLeaseDurationNil.Spec.LeaderElect.LeaseDuration = nil
-	errorCases["spec.leaderElect.leaseDuration: Required value"] = invalidLeaseDurationNil
+	errorCases := map[string]*v1beta1.ClusterAllowlist{}
 
-	invalidLeaseDurationGreaterThan0 := testcommon.ValidKubeFedConfig()
-	invalidLeaseDurationGreaterThan0.Spec.LeaderElect.LeaseDuration.Duration = 0
-	errorCases["spec.leaderElect.leaseDuration: Invalid value"] = invalidLeaseDurationGreaterThan0
+	noNamespaces := validClusterAllowlist()
+	noNamespaces.Spec.Namespaces = nil
+	errorCases["spec: Required value"] = noNamespaces
 
-	invalidRenewDeadlineNil := testcommon.ValidKubeFedConfig()
-	invalidRenewDeadlineNil.Spec.LeaderElect.RenewDeadline = nil
-	errorCases["spec.leaderElect.renewDeadline: Required value"] = invalidRenewDeadlineNil
+	duplicateNamespace := validClusterAllowlist()
+	duplicateNamespace.Spec.Namespaces = append(duplicateNamespace.Spec.Namespaces, "payments")
+	errorCases["spec.namespaces[2]: Duplicate value"] = duplicateNamespace
 
-	invalidRenewDeadlineGreaterThan0 := testcommon.ValidKubeFedConfig()
-	invalidRenewDeadlineGreaterThan0.Spec.LeaderElect.RenewDeadline.Duration = 0
-	errorCases["spec.leaderElect.renewDeadline: Invalid value"] = invalidRenewDeadlineGreaterThan0
+	invalidNamespace := validClusterAllowlist()
+	invalidNamespace.Spec.Namespaces[0] = "Invalid_Name"
+	errorCases["spec.namespaces[0]: Invalid value"] = invalidNamespace
 
-	// spec.leaderElect.leaderDuration must be greater than renewDeadline
-	invalidElectorLeaseDurationGreater := testcommon.ValidKubeFedConfig()
-	invalidElectorLeaseDurationGreater.Spec.LeaderElect.LeaseDuration.Duration = 1
-	invalidElectorLeaseDurationGreater.Spec.LeaderElect.RenewDeadline.Duration = 2
-	errorCases["spec.leaderElect.leaseDuration: Invalid value"] = invalidElectorLeaseDurationGreater
+	noClusters := validClusterAllowlist()
+	noClusters.Spec.Clusters = nil
+	errorCases["spec.clusters: Required value"] = noClusters
 
-	invalidRetryPeriodNil := testcommon.ValidKubeFedConfig()
-	invalidRetryPeriodNil.Spec.LeaderElect.RetryPeriod = nil
-	errorCases["spec.leaderElect.retryPeriod: Required value"] = invalidRetryPeriodNil
+	duplicateCluster := validClusterAllowlist()
+	duplicateCluster.Spec.Clusters = append(duplicateCluster.Spec.Clusters, "prod-east")
+	errorCases["spec.clusters[2]: Duplicate value"] = duplicateCluster
 
-	invalidRetryPeriodGreaterThan0 := testcommon.ValidKubeFedConfig()
-	invalidRetryPeriodGreaterThan0.Spec.LeaderElect.RetryPeriod.Duration = 0
-	errorCases["spec.leaderElect.retryPeriod: Invalid value"] = invalidRetryPeriodGreaterThan0
+	invalidCluster := validClusterAllowlist()
+	invalidCluster.Spec.Clusters[0] = "Invalid_Name"
+	errorCases["spec.clusters[0]: Invalid value"] = invalidCluster
 
-	// spec.leaderElect.renewDeadline must be greater than retryPeriod*JitterFactor(1.2)
-	invalidElectorDuration := testcommon.ValidKubeFedConfig()
-	invalidElectorDuration.Spec.LeaderElect.RenewDeadline.Duration = 12
-	invalidElectorDuration.Spec.LeaderElect.RetryPeriod.Duration = 10
-	errorCases["spec.leaderElect.renewDeadline: Invalid value"] = invalidElectorDuration
+	for k, v := range errorCases {
+		errs := ValidateClusterAllowlist(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
 
-	invalidElectorResourceLockNil := testcommon.ValidKubeFedConfig()
-	invalidElectorResourceLockNil.Spec.LeaderElect.ResourceLock = nil
-	errorCases["spec.leaderElect.resourceLock: Required value"] = invalidElectorResourceLockNil
+func validClusterAllowlist() *v1beta1.ClusterAllowlist {
+	return &v1beta1.ClusterAllowlist{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "payments",
+		},
+		Spec: v1beta1.ClusterAllowlistSpec{
+			Namespaces: []string{"payments", "payments-batch"},
+			Clusters:   []string{"prod-east", "prod-west"},
+		},
+	}
+}
 
-	invalidElectorResourceLock := testcommon.ValidKubeFedConfig()
-	invalidElectorResourceLockType := v1beta1.ResourceLockType("NeitherConfigmapsOrEndpoints")
-	invalidElectorResourceLock.Spec.LeaderElect.ResourceLock = &invalidElectorResourceLockType
-	errorCases["spec.leaderElect.resourceLock: Unsupported value"] = invalidElectorResourceLock
+func TestValidateFaultInjection(t *testing.T) {
+	successCases := []*v1beta1.FaultInjection{
+		validFaultInjection(),
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "slow-edge",
+			},
+			Spec: v1beta1.FaultInjectionSpec{
+				ClusterSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"kubefed.io/cluster-class": "edge"},
+				},
+				Latency: &metav1.Duration{Duration: 2 * time.Second},
+			},
+		},
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateFaultInjection(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
 
-	invalidFeatureGateNil := testcommon.ValidKubeFedConfig()
-	invalidFeatureGateNil.Spec.FeatureGates = nil
-	errorCases["spec.featureGates: Required value"] = invalidFeatureGateNil
+	errorCases := map[string]*v1beta1.FaultInjection{}
 
-	invalidFeatureGateName := testcommon.ValidKubeFedConfig()
-	invalidFeatureGateName.Spec.FeatureGates[0].Name = "BadFeatureName"
-	errorCases["spec.featureGates.name: Unsupported value"] = invalidFeatureGateName
+	noFault := validFaultInjection()
+	noFault.Spec.ErrorPercentage = 0
+	errorCases["spec: Required value"] = noFault
 
-	invalidDupFeatureGates := testcommon.ValidKubeFedConfig()
-	dupFeature := v1beta1.FeatureGatesConfig{
-		Name:          string(features.PushReconciler),
-		Configuration: v1beta1.ConfigurationEnabled,
-	}
-	invalidDupFeatureGates.Spec.FeatureGates = append(invalidDupFeatureGates.Spec.FeatureGates, dupFeature)
-	errorCases["spec.featureGates.name: Duplicate value"] = invalidDupFeatureGates
+	bothClusters := validFaultInjection()
+	bothClusters.Spec.ClusterSelector = &metav1.LabelSelector{}
+	errorCases["only one of clusters or clusterSelector may be specified"] = bothClusters
 
-	invalidFeatureGateConf := testcommon.ValidKubeFedConfig()
-	invalidFeatureGateConf.Spec.FeatureGates[0].Configuration = v1beta1.ConfigurationMode("NeitherEnableOrDisable")
-	errorCases["spec.featureGates.configuration: Unsupported value"] = invalidFeatureGateConf
+	invalidCluster := validFaultInjection()
+	invalidCluster.Spec.Clusters[0] = "Invalid_Name"
+	errorCases["spec.clusters[0]: Invalid value"] = invalidCluster
 
-	invalidClusterHealthCheckNil := testcommon.ValidKubeFedConfig()
-	invalidClusterHealthCheckNil.Spec.ClusterHealthCheck = nil
-	errorCases["spec.clusterHealthCheck: Required value"] = invalidClusterHealthCheckNil
+	invalidOperation := validFaultInjection()
+	invalidOperation.Spec.Operations = []v1beta1.FaultOperation{"patch"}
+	errorCases["spec.operations[0]: Unsupported value"] = invalidOperation
 
-	zeroInt := int64(0)
-	zeroIntPtr := &zeroInt
+	negativeLatency := validFaultInjection()
+	negativeLatency.Spec.Latency = &metav1.Duration{Duration: -time.Second}
+	errorCases["spec.latency: Invalid value"] = negativeLatency
 
-	invalidPeriodNil := testcommon.ValidKubeFedConfig()
-	invalidPeriodNil.Spec.ClusterHealthCheck.Period = nil
-	errorCases["spec.clusterHealthCheck.period: Required value"] = invalidPeriodNil
+	invalidPercentage := validFaultInjection()
+	invalidPercentage.Spec.ErrorPercentage = 101
+	errorCases["spec.errorPercentage: Invalid value"] = invalidPercentage
 
-	invalidPeriodGreaterThan0 := testcommon.ValidKubeFedConfig()
-	invalidPeriodGreaterThan0.Spec.ClusterHealthCheck.Period.Duration = 0
-	errorCases["spec.clusterHealthCheck.period: Invalid value"] = invalidPeriodGreaterThan0
+	invalidCode := validFaultInjection()
+	invalidCode.Spec.ErrorCode = 200
+	errorCases["spec.errorCode: Invalid value"] = invalidCode
 
-	invalidFailureThresholdNil := testcommon.ValidKubeFedConfig()
-	invalidFailureThresholdNil.Spec.ClusterHealthCheck.FailureThreshold = nil
+	for k, v := range errorCases {
+		errs := ValidateFaultInjection(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func validFaultInjection() *v1beta1.FaultInjection {
+	return &v1beta1.FaultInjection{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "flaky-updates",
+		},
+		Spec: v1beta1.FaultInjectionSpec{
+			Clusters:        []string{"cluster1"},
+			Operations:      []v1beta1.FaultOperation{v1beta1.FaultOperationUpdate},
+			ErrorPercentage: 50,
+			ErrorCode:       500,
+		},
+	}
+}
+
+func TestValidateFederatedTemplate(t *testing.T) {
+	successCases := []*v1beta1.FederatedTemplate{
+		validFederatedTemplate(),
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateFederatedTemplate(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]*v1beta1.FederatedTemplate{}
+
+	noTemplate := validFederatedTemplate()
+	noTemplate.Spec.Template.Raw = nil
+	errorCases["spec.template: Required value"] = noTemplate
+
+	invalidTemplate := validFederatedTemplate()
+	invalidTemplate.Spec.Template.Raw = []byte(`["data"]`)
+	errorCases["spec.template: Invalid value"] = invalidTemplate
+
+	invalidName := validFederatedTemplate()
+	invalidName.Spec.Parameters[0].Name = "tenant-name"
+	errorCases["spec.parameters[0].name: Invalid value"] = invalidName
+
+	duplicateName := validFederatedTemplate()
+	duplicateName.Spec.Parameters[1].Name = "tenant"
+	errorCases["spec.parameters[1].name: Duplicate value"] = duplicateName
+
+	for k, v := range errorCases {
+		errs := ValidateFederatedTemplate(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func TestValidateNamespaceProfile(t *testing.T) {
+	successCases := []*v1beta1.NamespaceProfile{
+		validNamespaceProfile(),
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateNamespaceProfile(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]*v1beta1.NamespaceProfile{}
+
+	noResources := validNamespaceProfile()
+	noResources.Spec.Resources = nil
+	errorCases["spec.resources: Required value"] = noResources
+
+	invalidResource := validNamespaceProfile()
+	invalidResource.Spec.Resources[0].Raw = []byte(`["data"]`)
+	errorCases["spec.resources[0]: Invalid value"] = invalidResource
+
+	noKind := validNamespaceProfile()
+	noKind.Spec.Resources[0].Raw = []byte(`{"apiVersion":"v1","metadata":{"name":"limits"}}`)
+	errorCases["spec.resources[0].kind: Required value"] = noKind
+
+	noName := validNamespaceProfile()
+	noName.Spec.Resources[0].Raw = []byte(`{"apiVersion":"v1","kind":"LimitRange"}`)
+	errorCases["spec.resources[0].metadata.name: Required value"] = noName
+
+	namespaced := validNamespaceProfile()
+	namespaced.Spec.Resources[0].Raw = []byte(`{"apiVersion":"v1","kind":"LimitRange","metadata":{"name":"limits","namespace":"tenant"}}`)
+	errorCases["spec.resources[0].metadata.namespace: Forbidden"] = namespaced
+
+	duplicateResource := validNamespaceProfile()
+	duplicateResource.Spec.Resources[1] = duplicateResource.Spec.Resources[0]
+	errorCases["spec.resources[1]: Duplicate value"] = duplicateResource
+
+	for k, v := range errorCases {
+		errs := ValidateNamespaceProfile(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func TestValidateBlueprint(t *testing.T) {
+	successCases := []*v1beta1.Blueprint{
+		validBlueprint(),
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateBlueprint(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]*v1beta1.Blueprint{}
+
+	invalidName := validBlueprint()
+	invalidName.Spec.Parameters[0].Name = "app-name"
+	errorCases["spec.parameters[0].name: Invalid value"] = invalidName
+
+	duplicateName := validBlueprint()
+	duplicateName.Spec.Parameters[1].Name = "appName"
+	errorCases["spec.parameters[1].name: Duplicate value"] = duplicateName
+
+	invalidType := validBlueprint()
+	invalidType.Spec.Parameters[1].Type = "number"
+	errorCases["spec.parameters[1].type: Unsupported value"] = invalidType
+
+	invalidPattern := validBlueprint()
+	invalidPattern.Spec.Parameters[0].Pattern = "["
+	errorCases["spec.parameters[0].pattern: Invalid value"] = invalidPattern
+
+	integerPattern := validBlueprint()
+	integerPattern.Spec.Parameters[1].Pattern = "^[0-9]$"
+	errorCases["spec.parameters[1].pattern: Forbidden"] = integerPattern
+
+	stringMinimum := validBlueprint()
+	stringMinimum.Spec.Parameters[0].Minimum = stringMinimum.Spec.Parameters[1].Minimum
+	errorCases["spec.parameters[0].minimum: Forbidden"] = stringMinimum
+
+	invertedBounds := validBlueprint()
+	maximum := int64(0)
+	invertedBounds.Spec.Parameters[1].Maximum = &maximum
+	errorCases["spec.parameters[1].maximum: Invalid value"] = invertedBounds
+
+	invalidDefault := validBlueprint()
+	many := "many"
+	invalidDefault.Spec.Parameters[1].Default = &many
+	errorCases["spec.parameters[1].default: Invalid value"] = invalidDefault
+
+	invalidEnum := validBlueprint()
+	invalidEnum.Spec.Parameters[2].Enum = []string{"true", "yes"}
+	errorCases["spec.parameters[2].enum[1]: Invalid value"] = invalidEnum
+
+	noResources := validBlueprint()
+	noResources.Spec.Resources = nil
+	errorCases["spec.resources: Required value"] = noResources
+
+	noName := validBlueprint()
+	noName.Spec.Resources[0].Raw = []byte(`{"apiVersion":"types.kubefed.io/v1beta1","kind":"FederatedDeployment"}`)
+	errorCases["spec.resources[0].metadata.name: Required value"] = noName
+
+	namespaced := validBlueprint()
+	namespaced.Spec.Resources[0].Raw = []byte(`{"apiVersion":"types.kubefed.io/v1beta1","kind":"FederatedDeployment","metadata":{"name":"web","namespace":"team-a"}}`)
+	errorCases["spec.resources[0].metadata.namespace: Forbidden"] = namespaced
+
+	duplicateResource := validBlueprint()
+	duplicateResource.Spec.Resources[1] = duplicateResource.Spec.Resources[0]
+	errorCases["spec.resources[1]: Duplicate value"] = duplicateResource
+
+	for k, v := range errorCases {
+		errs := ValidateBlueprint(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func TestValidateBlueprintInstance(t *testing.T) {
+	if errs := ValidateBlueprintInstance(validBlueprintInstance()); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	noBlueprint := validBlueprintInstance()
+	noBlueprint.Spec.BlueprintRef.Name = ""
+	errs := ValidateBlueprintInstance(noBlueprint)
+	if expected := "spec.blueprintRef.name: Required value"; len(errs) == 0 || !strings.Contains(errs[0].Error(), expected) {
+		t.Errorf("unexpected errors: %v, expected: %q", errs, expected)
+	}
+}
+
+func TestValidateBlueprintInstanceParameters(t *testing.T) {
+	blueprint := validBlueprint()
+	if errs := ValidateBlueprintInstanceParameters(validBlueprintInstance(), blueprint); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string]map[string]string{
+		"spec.parameters[appName]: Required value":        {"replicas": "2"},
+		"spec.parameters[appName]: Invalid value":         {"appName": "Web"},
+		"spec.parameters[replicas]: Invalid value":        {"appName": "web", "replicas": "two"},
+		"spec.parameters[replicas]: Invalid value: \"0\"": {"appName": "web", "replicas": "0"},
+		"spec.parameters[public]: Unsupported value":      {"appName": "web", "public": "false"},
+		"spec.parameters[region]: Forbidden":              {"appName": "web", "public": "true", "region": "eu"},
+	}
+	for k, parameters := range errorCases {
+		instance := validBlueprintInstance()
+		instance.Spec.Parameters = parameters
+		errs := ValidateBlueprintInstanceParameters(instance, blueprint)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	successCases := []*v1beta1.MaintenanceWindow{
+		validMaintenanceWindow(),
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateMaintenanceWindow(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]*v1beta1.MaintenanceWindow{}
+
+	noClusterName := validMaintenanceWindow()
+	noClusterName.Spec.ClusterName = ""
+	errorCases["spec.clusterName: Required value"] = noClusterName
+
+	invalidSchedule := validMaintenanceWindow()
+	invalidSchedule.Spec.Schedule = "0 2 * *"
+	errorCases["spec.schedule: Invalid value"] = invalidSchedule
+
+	outOfRangeSchedule := validMaintenanceWindow()
+	outOfRangeSchedule.Spec.Schedule = "0 24 * * 6"
+	errorCases["spec.schedule: Invalid value"] = outOfRangeSchedule
+
+	zeroDuration := validMaintenanceWindow()
+	zeroDuration.Spec.Duration.Duration = 0
+	errorCases["spec.duration: Invalid value"] = zeroDuration
+
+	invalidTimeZone := validMaintenanceWindow()
+	invalidTimeZone.Spec.TimeZone = "Europe/Nowhere"
+	errorCases["spec.timeZone: Invalid value"] = invalidTimeZone
+
+	for k, v := range errorCases {
+		errs := ValidateMaintenanceWindow(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func validMaintenanceWindow() *v1beta1.MaintenanceWindow {
+	return &v1beta1.MaintenanceWindow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1-weekend",
+		},
+		Spec: v1beta1.MaintenanceWindowSpec{
+			ClusterName: "cluster1",
+			Schedule:    "0 2 * * 6",
+			Duration:    metav1.Duration{Duration: 4 * time.Hour},
+			TimeZone:    "Europe/Berlin",
+		},
+	}
+}
+
+func TestValidateAutoEnablePolicy(t *testing.T) {
+	selectorOnly := validAutoEnablePolicy()
+	selectorOnly.Spec.GroupSuffixes = nil
+	selectorOnly.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"federate": "true"}}
+
+	successCases := []*v1beta1.AutoEnablePolicy{
+		validAutoEnablePolicy(),
+		selectorOnly,
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateAutoEnablePolicy(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]*v1beta1.AutoEnablePolicy{}
+
+	noSelection := validAutoEnablePolicy()
+	noSelection.Spec.GroupSuffixes = nil
+	errorCases["spec: Required value"] = noSelection
+
+	duplicateSuffix := validAutoEnablePolicy()
+	duplicateSuffix.Spec.GroupSuffixes = []string{"mycompany.io", "mycompany.io"}
+	errorCases["spec.groupSuffixes[1]: Duplicate value"] = duplicateSuffix
+
+	invalidSuffix := validAutoEnablePolicy()
+	invalidSuffix.Spec.GroupSuffixes = []string{"MyCompany.io"}
+	errorCases["spec.groupSuffixes[0]: Invalid value"] = invalidSuffix
+
+	kubeFedSuffix := validAutoEnablePolicy()
+	kubeFedSuffix.Spec.GroupSuffixes = []string{"types.kubefed.io"}
+	errorCases["spec.groupSuffixes[0]: Forbidden"] = kubeFedSuffix
+
+	invalidSelector := validAutoEnablePolicy()
+	invalidSelector.Spec.Selector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "federate", Operator: "Unknown"}},
+	}
+	errorCases["spec.selector: Invalid value"] = invalidSelector
+
+	invalidFederatedGroup := validAutoEnablePolicy()
+	invalidFederatedGroup.Spec.FederatedGroup = "types_mycompany"
+	errorCases["spec.federatedGroup: Invalid value"] = invalidFederatedGroup
+
+	for k, v := range errorCases {
+		errs := ValidateAutoEnablePolicy(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func validAutoEnablePolicy() *v1beta1.AutoEnablePolicy {
+	return &v1beta1.AutoEnablePolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycompany",
+		},
+		Spec: v1beta1.AutoEnablePolicySpec{
+			GroupSuffixes:  []string{"mycompany.io"},
+			FederatedGroup: "types.mycompany.io",
+		},
+	}
+}
+
+func validNamespaceProfile() *v1beta1.NamespaceProfile {
+	return &v1beta1.NamespaceProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "restricted",
+		},
+		Spec: v1beta1.NamespaceProfileSpec{
+			Resources: []runtime.RawExtension{
+				{Raw: []byte(`{"apiVersion":"v1","kind":"LimitRange","metadata":{"name":"limits"},"spec":{"limits":[{"type":"Container","default":{"cpu":"500m"}}]}}`)},
+				{Raw: []byte(`{"apiVersion":"v1","kind":"ResourceQuota","metadata":{"name":"quota"},"spec":{"hard":{"pods":"10"}}}`)},
+			},
+		},
+	}
+}
+
+func validBlueprint() *v1beta1.Blueprint {
+	replicas := "1"
+	minimum := int64(1)
+	return &v1beta1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web-app",
+		},
+		Spec: v1beta1.BlueprintSpec{
+			Parameters: []v1beta1.BlueprintParameter{
+				{
+					Name:    "appName",
+					Pattern: "^[a-z][a-z0-9-]*$",
+				},
+				{
+					Name:    "replicas",
+					Type:    v1beta1.BlueprintParameterTypeInteger,
+					Default: &replicas,
+					Minimum: &minimum,
+				},
+				{
+					Name: "public",
+					Type: v1beta1.BlueprintParameterTypeBoolean,
+					Enum: []string{"true"},
+				},
+			},
+			Resources: []runtime.RawExtension{
+				{Raw: []byte(`{"apiVersion":"types.kubefed.io/v1beta1","kind":"FederatedDeployment","metadata":{"name":"$(appName)"},"spec":{"template":{"spec":{"replicas":"$(replicas)"}}}}`)},
+				{Raw: []byte(`{"apiVersion":"types.kubefed.io/v1beta1","kind":"FederatedService","metadata":{"name":"$(appName)"}}`)},
+			},
+		},
+	}
+}
+
+func validBlueprintInstance() *v1beta1.BlueprintInstance {
+	return &v1beta1.BlueprintInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "team-a",
+		},
+		Spec: v1beta1.BlueprintInstanceSpec{
+			BlueprintRef: v1beta1.BlueprintReference{
+				Name: "web-app",
+			},
+			Parameters: map[string]string{
+				"appName":  "web",
+				"replicas": "3",
+				"public":   "true",
+			},
+		},
+	}
+}
+
+func validFederatedTemplate() *v1beta1.FederatedTemplate {
+	level := "info"
+	return &v1beta1.FederatedTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-settings",
+		},
+		Spec: v1beta1.FederatedTemplateSpec{
+			Template: runtime.RawExtension{
+				Raw: []byte(`{"data":{"tenant":"$(tenant)","level":"$(level)"}}`),
+			},
+			Parameters: []v1beta1.FederatedTemplateParameter{
+				{Name: "tenant"},
+				{Name: "level", Default: &level},
+			},
+		},
+	}
+}
+
+func validClusterGroup() *v1beta1.ClusterGroup {
+	return &v1beta1.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "static",
+		},
+		Spec: v1beta1.ClusterGroupSpec{
+			Clusters: []string{"cluster1", "cluster2"},
+		},
+	}
+}
+
+func TestValidateFederatedApplication(t *testing.T) {
+	successCases := []*v1beta1.FederatedApplication{
+		validFederatedApplication(),
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "listed",
+			},
+			Spec: v1beta1.FederatedApplicationSpec{
+				Resources: []v1beta1.FederatedApplicationResource{
+					{Kind: "FederatedDeployment", Name: "web"},
+					{Kind: "FederatedService", Name: "web"},
+				},
+				RolloutStrategy: &appsv1.DeploymentStrategy{
+					Type: appsv1.RecreateDeploymentStrategyType,
+				},
+			},
+		},
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateFederatedApplication(successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	invalidSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "foo",
+				Operator: "InvalidOperator",
+			},
+		},
+	}
+
+	errorCases := map[string]*v1beta1.FederatedApplication{}
+
+	noResources := validFederatedApplication()
+	noResources.Spec.Selector = nil
+	errorCases["spec: Required value"] = noResources
+
+	invalidResourceSelector := validFederatedApplication()
+	invalidResourceSelector.Spec.Selector = invalidSelector
+	errorCases["spec.selector: Invalid value"] = invalidResourceSelector
+
+	duplicateResource := validFederatedApplication()
+	duplicateResource.Spec.Resources = []v1beta1.FederatedApplicationResource{
+		{Kind: "FederatedDeployment", Name: "web"},
+		{Kind: "FederatedDeployment", Name: "web"},
+	}
+	errorCases["spec.resources[1]: Duplicate value"] = duplicateResource
+
+	noKind := validFederatedApplication()
+	noKind.Spec.Resources = []v1beta1.FederatedApplicationResource{{Name: "web"}}
+	errorCases["spec.resources[0].kind: Required value"] = noKind
+
+	invalidName := validFederatedApplication()
+	invalidName.Spec.Resources = []v1beta1.FederatedApplicationResource{{Kind: "FederatedDeployment", Name: "Invalid_Name"}}
+	errorCases["spec.resources[0].name: Invalid value"] = invalidName
+
+	invalidCluster := validFederatedApplication()
+	invalidCluster.Spec.Placement.Clusters = []string{"Invalid_Name"}
+	errorCases["spec.placement.clusters[0]: Invalid value"] = invalidCluster
+
+	invalidClusterSelector := validFederatedApplication()
+	invalidClusterSelector.Spec.Placement.ClusterSelector = invalidSelector
+	errorCases["spec.placement.clusterSelector: Invalid value"] = invalidClusterSelector
+
+	invalidStrategyType := validFederatedApplication()
+	invalidStrategyType.Spec.RolloutStrategy.Type = "Invalid"
+	errorCases["spec.rolloutStrategy.type: Unsupported value"] = invalidStrategyType
+
+	invalidRollingUpdate := validFederatedApplication()
+	invalidRollingUpdate.Spec.RolloutStrategy.Type = appsv1.RecreateDeploymentStrategyType
+	errorCases["spec.rolloutStrategy.rollingUpdate: Forbidden"] = invalidRollingUpdate
+
+	for k, v := range errorCases {
+		errs := ValidateFederatedApplication(v)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func validFederatedApplication() *v1beta1.FederatedApplication {
+	maxUnavailable := intstr.FromString("25%")
+	return &v1beta1.FederatedApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web",
+		},
+		Spec: v1beta1.FederatedApplicationSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": "web",
+				},
+			},
+			Placement: &v1beta1.FederatedApplicationPlacement{
+				Clusters: []string{"cluster1", "cluster2"},
+			},
+			RolloutStrategy: &appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+		},
+	}
+}
+
+func TestValidateClusterJoinRequest(t *testing.T) {
+	approved := validClusterJoinRequest()
+	approved.SetCondition(v1beta1.ClusterJoinRequestApproved, corev1.ConditionTrue, "Approved", "")
+	approved.SetCondition(v1beta1.ClusterJoinRequestJoined, corev1.ConditionTrue, "Joined", "")
+
+	if errs := ValidateClusterJoinRequest(validClusterJoinRequest(), false); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+	if errs := ValidateClusterJoinRequest(approved, true); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string]*v1beta1.ClusterJoinRequest{}
+
+	noClusterName := validClusterJoinRequest()
+	noClusterName.Spec.ClusterName = ""
+	errorCases["spec.clusterName: Required value"] = noClusterName
+
+	invalidClusterName := validClusterJoinRequest()
+	invalidClusterName.Spec.ClusterName = "Invalid_Name"
+	errorCases["spec.clusterName: Invalid value"] = invalidClusterName
+
+	noAPIEndpoint := validClusterJoinRequest()
+	noAPIEndpoint.Spec.APIEndpoint = ""
+	errorCases["spec.apiEndpoint: Required value"] = noAPIEndpoint
+
+	invalidLabels := validClusterJoinRequest()
+	invalidLabels.Spec.ClusterLabels = map[string]string{"region": "not a valid value"}
+	errorCases["spec.clusterLabels: Invalid value"] = invalidLabels
+
+	for k, v := range errorCases {
+		errs := ValidateClusterJoinRequest(v, false)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+
+	statusErrorCases := map[string]*v1beta1.ClusterJoinRequest{}
+
+	invalidConditionType := validClusterJoinRequest()
+	invalidConditionType.SetCondition("Pending", corev1.ConditionTrue, "", "")
+	statusErrorCases["status.conditions[0].type: Unsupported value"] = invalidConditionType
+
+	duplicateCondition := validClusterJoinRequest()
+	duplicateCondition.Status.Conditions = []v1beta1.ClusterJoinRequestCondition{
+		{Type: v1beta1.ClusterJoinRequestApproved, Status: corev1.ConditionTrue},
+		{Type: v1beta1.ClusterJoinRequestApproved, Status: corev1.ConditionFalse},
+	}
+	statusErrorCases["status.conditions[1].type: Duplicate value"] = duplicateCondition
+
+	approvedAndDenied := approved.DeepCopy()
+	approvedAndDenied.SetCondition(v1beta1.ClusterJoinRequestDenied, corev1.ConditionTrue, "Denied", "")
+	statusErrorCases["status.conditions: Invalid value"] = approvedAndDenied
+
+	for k, v := range statusErrorCases {
+		errs := ValidateClusterJoinRequest(v, true)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func validClusterJoinRequest() *v1beta1.ClusterJoinRequest {
+	return &v1beta1.ClusterJoinRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "edge-1",
+		},
+		Spec: v1beta1.ClusterJoinRequestSpec{
+			ClusterName:         "edge-1",
+			APIEndpoint:         "https://edge-1.example.com:6443",
+			ServiceAccountToken: []byte("token"),
+			ClusterLabels:       map[string]string{"region": "us-east"},
+		},
+	}
+}
+
+func TestValidateKubeFedInstance(t *testing.T) {
+	instances := []v1beta1.KubeFedInstance{
+		*newKubeFedInstance("tenant-a-system", "tenant-a"),
+		*newKubeFedInstance("tenant-b-system", "tenant-b"),
+	}
+
+	if errs := ValidateKubeFedInstance(newKubeFedInstance("tenant-c-system", "tenant-c"), instances); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+	// An instance does not overlap with its own registration.
+	if errs := ValidateKubeFedInstance(&instances[0], instances); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string]*v1beta1.KubeFedInstance{}
+
+	noKubeFedNamespace := newKubeFedInstance("tenant-c-system", "tenant-c")
+	noKubeFedNamespace.Spec.KubeFedNamespace = ""
+	errorCases["spec.kubefedNamespace: Required value"] = noKubeFedNamespace
+
+	invalidTargetNamespace := newKubeFedInstance("tenant-c-system", "Tenant_C")
+	errorCases["spec.targetNamespace: Invalid value"] = invalidTargetNamespace
+
+	overlappingNamespace := newKubeFedInstance("tenant-c-system", "tenant-b")
+	errorCases[`spec.targetNamespace: Forbidden: overlaps with the target namespace of KubeFedInstance "tenant-b-system"`] = overlappingNamespace
+
+	allNamespaces := newKubeFedInstance("kube-federation-system", "")
+	errorCases["spec.targetNamespace: Forbidden"] = allNamespaces
+
+	for k, v := range errorCases {
+		errs := ValidateKubeFedInstance(v, instances)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func newKubeFedInstance(kubeFedNamespace, targetNamespace string) *v1beta1.KubeFedInstance {
+	return &v1beta1.KubeFedInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kubeFedNamespace,
+		},
+		Spec: v1beta1.KubeFedInstanceSpec{
+			KubeFedNamespace: kubeFedNamespace,
+			TargetNamespace:  targetNamespace,
+		},
+	}
+}
+
+func TestValidateKubeFedInstallation(t *testing.T) {
+	installations := []v1beta1.KubeFedInstallation{
+		*newKubeFedInstallation("tenant-a-system", apiextv1b1.NamespaceScoped),
+	}
+
+	if errs := ValidateKubeFedInstallation(newKubeFedInstallation("tenant-b-system", apiextv1b1.NamespaceScoped), installations); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+	// An installation does not overlap with itself.
+	if errs := ValidateKubeFedInstallation(&installations[0], installations); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string]*v1beta1.KubeFedInstallation{}
+
+	noVersion := newKubeFedInstallation("tenant-b-system", apiextv1b1.NamespaceScoped)
+	noVersion.Spec.Version = ""
+	errorCases["spec.version: Required value"] = noVersion
+
+	invalidVersion := newKubeFedInstallation("tenant-b-system", apiextv1b1.NamespaceScoped)
+	invalidVersion.Spec.Version = "../v0.4.0"
+	errorCases["spec.version: Invalid value"] = invalidVersion
+
+	invalidScope := newKubeFedInstallation("tenant-b-system", "Tenant")
+	errorCases["spec.scope: Unsupported value"] = invalidScope
+
+	noKubeFedNamespace := newKubeFedInstallation("", apiextv1b1.NamespaceScoped)
+	errorCases["spec.kubefedNamespace: Required value"] = noKubeFedNamespace
+
+	taggedImageRepository := newKubeFedInstallation("tenant-b-system", apiextv1b1.NamespaceScoped)
+	taggedImageRepository.Spec.ImageRepository = "registry.example.com/kubefed:v0.4.0"
+	errorCases["spec.imageRepository: Invalid value"] = taggedImageRepository
+
+	invalidFailurePolicy := newKubeFedInstallation("tenant-b-system", apiextv1b1.NamespaceScoped)
+	failurePolicy := v1beta1.WebhookFailurePolicy("Retry")
+	invalidFailurePolicy.Spec.Webhook = &v1beta1.WebhookConfig{FailurePolicy: &failurePolicy}
+	errorCases["spec.webhook.failurePolicy: Unsupported value"] = invalidFailurePolicy
+
+	sameNamespace := newKubeFedInstallation("tenant-a-system", apiextv1b1.NamespaceScoped)
+	sameNamespace.Name = "tenant-a"
+	errorCases[`spec.kubefedNamespace: Forbidden: is the namespace of KubeFedInstallation "tenant-a-system"`] = sameNamespace
+
+	clusterScoped := newKubeFedInstallation("kube-federation-system", apiextv1b1.ClusterScoped)
+	errorCases["spec.scope: Forbidden"] = clusterScoped
+
+	for k, v := range errorCases {
+		errs := ValidateKubeFedInstallation(v, installations)
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
+
+func newKubeFedInstallation(kubeFedNamespace string, scope apiextv1b1.ResourceScope) *v1beta1.KubeFedInstallation {
+	return &v1beta1.KubeFedInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kubeFedNamespace,
+		},
+		Spec: v1beta1.KubeFedInstallationSpec{
+			Version:          "v0.4.0",
+			Scope:            scope,
+			KubeFedNamespace: kubeFedNamespace,
+		},
+	}
+}
+
+func TestValidateKubeFedConfig(t *testing.T) {
+	errs := ValidateKubeFedConfig(testcommon.ValidKubeFedConfig(), testcommon.ValidKubeFedConfig())
+	if len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string]*v1beta1.KubeFedConfig{}
+
+	invalidScope := testcommon.ValidKubeFedConfig()
+	invalidScope.Spec.Scope = "NeitherClusterOrNamespaceScoped"
+	errorCases["spec.scope: Unsupported value"] = invalidScope
+
+	immutableScope := testcommon.ValidKubeFedConfig()
+	immutableScope.Spec.Scope = apiextv1b1.NamespaceScoped
+	errorCases[`spec.scope: Invalid value: "Namespaced": field is immutable`] = immutableScope
+
+	invalidControllerDurationNil := testcommon.ValidKubeFedConfig()
+	invalidControllerDurationNil.Spec.ControllerDuration = nil
+	errorCases["spec.controllerDuration: Required value"] = invalidControllerDurationNil
+
+	invalidAvailableDelayNil := testcommon.ValidKubeFedConfig()
+	invalidAvailableDelayNil.Spec.ControllerDuration.AvailableDelay = nil
+	errorCases["spec.controllerDuration.availableDelay: Required value"] = invalidAvailableDelayNil
+
+	invalidAvailableDelayGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidAvailableDelayGreaterThan0.Spec.ControllerDuration.AvailableDelay.Duration = 0
+	errorCases["spec.controllerDuration.availableDelay: Invalid value"] = invalidAvailableDelayGreaterThan0
+
+	invalidUnavailableDelayNil := testcommon.ValidKubeFedConfig()
+	invalidUnavailableDelayNil.Spec.ControllerDuration.UnavailableDelay = nil
+	errorCases["spec.controllerDuration.unavailableDelay: Required value"] = invalidUnavailableDelayNil
+
+	invalidUnavailableDelayGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidUnavailableDelayGreaterThan0.Spec.ControllerDuration.UnavailableDelay.Duration = 0
+	errorCases["spec.controllerDuration.unavailableDelay: Invalid value"] = invalidUnavailableDelayGreaterThan0
+
+	invalidLeaderElectNil := testcommon.ValidKubeFedConfig()
+	invalidLeaderElectNil.Spec.LeaderElect = nil
+	errorCases["spec.leaderElect: Required value"] = invalidLeaderElectNil
+
+	invalidLeaseDurationNil := testcommon.ValidKubeFedConfig()
+	invalidLeaseDurationNil.Spec.LeaderElect.LeaseDuration = nil
+	errorCases["spec.leaderElect.leaseDuration: Required value"] = invalidLeaseDurationNil
+
+	invalidLeaseDurationGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidLeaseDurationGreaterThan0.Spec.LeaderElect.LeaseDuration.Duration = 0
+	errorCases["spec.leaderElect.leaseDuration: Invalid value"] = invalidLeaseDurationGreaterThan0
+
+	invalidRenewDeadlineNil := testcommon.ValidKubeFedConfig()
+	invalidRenewDeadlineNil.Spec.LeaderElect.RenewDeadline = nil
+	errorCases["spec.leaderElect.renewDeadline: Required value"] = invalidRenewDeadlineNil
+
+	invalidRenewDeadlineGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidRenewDeadlineGreaterThan0.Spec.LeaderElect.RenewDeadline.Duration = 0
+	errorCases["spec.leaderElect.renewDeadline: Invalid value"] = invalidRenewDeadlineGreaterThan0
+
+	// spec.leaderElect.leaderDuration must be greater than renewDeadline
+	invalidElectorLeaseDurationGreater := testcommon.ValidKubeFedConfig()
+	invalidElectorLeaseDurationGreater.Spec.LeaderElect.LeaseDuration.Duration = 1
+	invalidElectorLeaseDurationGreater.Spec.LeaderElect.RenewDeadline.Duration = 2
+	errorCases["spec.leaderElect.leaseDuration: Invalid value"] = invalidElectorLeaseDurationGreater
+
+	invalidRetryPeriodNil := testcommon.ValidKubeFedConfig()
+	invalidRetryPeriodNil.Spec.LeaderElect.RetryPeriod = nil
+	errorCases["spec.leaderElect.retryPeriod: Required value"] = invalidRetryPeriodNil
+
+	invalidRetryPeriodGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidRetryPeriodGreaterThan0.Spec.LeaderElect.RetryPeriod.Duration = 0
+	errorCases["spec.leaderElect.retryPeriod: Invalid value"] = invalidRetryPeriodGreaterThan0
+
+	// spec.leaderElect.renewDeadline must be greater than retryPeriod*JitterFactor(1.2)
+	invalidElectorDuration := testcommon.ValidKubeFedConfig()
+	invalidElectorDuration.Spec.LeaderElect.RenewDeadline.Duration = 12
+	invalidElectorDuration.Spec.LeaderElect.RetryPeriod.Duration = 10
+	errorCases["spec.leaderElect.renewDeadline: Invalid value"] = invalidElectorDuration
+
+	invalidElectorResourceLockNil := testcommon.ValidKubeFedConfig()
+	invalidElectorResourceLockNil.Spec.LeaderElect.ResourceLock = nil
+	errorCases["spec.leaderElect.resourceLock: Required value"] = invalidElectorResourceLockNil
+
+	invalidElectorResourceLock := testcommon.ValidKubeFedConfig()
+	invalidElectorResourceLockType := v1beta1.ResourceLockType("NeitherConfigmapsOrEndpoints")
+	invalidElectorResourceLock.Spec.LeaderElect.ResourceLock = &invalidElectorResourceLockType
+	errorCases["spec.leaderElect.resourceLock: Unsupported value"] = invalidElectorResourceLock
+
+	invalidFeatureGateNil := testcommon.ValidKubeFedConfig()
+	invalidFeatureGateNil.Spec.FeatureGates = nil
+	errorCases["spec.featureGates: Required value"] = invalidFeatureGateNil
+
+	invalidFeatureGateName := testcommon.ValidKubeFedConfig()
+	invalidFeatureGateName.Spec.FeatureGates[0].Name = "BadFeatureName"
+	errorCases["spec.featureGates.name: Unsupported value"] = invalidFeatureGateName
+
+	invalidDupFeatureGates := testcommon.ValidKubeFedConfig()
+	dupFeature := v1beta1.FeatureGatesConfig{
+		Name:          string(features.PushReconciler),
+		Configuration: v1beta1.ConfigurationEnabled,
+	}
+	invalidDupFeatureGates.Spec.FeatureGates = append(invalidDupFeatureGates.Spec.FeatureGates, dupFeature)
+	errorCases["spec.featureGates.name: Duplicate value"] = invalidDupFeatureGates
+
+	invalidFeatureGateConf := testcommon.ValidKubeFedConfig()
+	invalidFeatureGateConf.Spec.FeatureGates[0].Configuration = v1beta1.ConfigurationMode("NeitherEnableOrDisable")
+	errorCases["spec.featureGates.configuration: Unsupported value"] = invalidFeatureGateConf
+
+	invalidClusterHealthCheckNil := testcommon.ValidKubeFedConfig()
+	invalidClusterHealthCheckNil.Spec.ClusterHealthCheck = nil
+	errorCases["spec.clusterHealthCheck: Required value"] = invalidClusterHealthCheckNil
+
+	zeroInt := int64(0)
+	zeroIntPtr := &zeroInt
+
+	invalidPeriodNil := testcommon.ValidKubeFedConfig()
+	invalidPeriodNil.Spec.ClusterHealthCheck.Period = nil
+	errorCases["spec.clusterHealthCheck.period: Required value"] = invalidPeriodNil
+
+	invalidPeriodGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidPeriodGreaterThan0.Spec.ClusterHealthCheck.Period.Duration = 0
+	errorCases["spec.clusterHealthCheck.period: Invalid value"] = invalidPeriodGreaterThan0
+
+	invalidFailureThresholdNil := testcommon.ValidKubeFedConfig()
+	invalidFailureThresholdNil.Spec.ClusterHealthCheck.FailureThreshold = nil
 	errorCases["spec.clusterHealthCheck.failureThreshold: Required value"] = invalidFailureThresholdNil
 
 	invalidFailureThresholdGreaterThan0 := testcommon.ValidKubeFedConfig()
 	invalidFailureThresholdGreaterThan0.Spec.ClusterHealthCheck.FailureThreshold = zeroIntPtr
 	errorCases["spec.clusterHealthCheck.failureThreshold: Invalid value"] = invalidFailureThresholdGreaterThan0
 
+	invalidEdgeFailureThresholdGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidEdgeFailureThresholdGreaterThan0.Spec.ClusterHealthCheck.EdgeFailureThreshold = zeroIntPtr
+	errorCases["spec.clusterHealthCheck.edgeFailureThreshold: Invalid value"] = invalidEdgeFailureThresholdGreaterThan0
+
 	invalidSuccessThresholdNil := testcommon.ValidKubeFedConfig()
 	invalidSuccessThresholdNil.Spec.ClusterHealthCheck.SuccessThreshold = nil
 	errorCases["spec.clusterHealthCheck.successThreshold: Required value"] = invalidSuccessThresholdNil
@@ -791,6 +2026,20 @@ func TestValidateKubeFedConfig(t *testing.T) {
 	invalidTimeoutGreaterThan0.Spec.ClusterHealthCheck.Timeout.Duration = 0
 	errorCases["spec.clusterHealthCheck.timeout: Invalid value"] = invalidTimeoutGreaterThan0
 
+	invalidDeadlineGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidDeadlineGreaterThan0.Spec.ClusterHealthCheck.Deadline = &metav1.Duration{}
+	errorCases["spec.clusterHealthCheck.deadline: Invalid value"] = invalidDeadlineGreaterThan0
+
+	invalidWorkersGreaterThan0 := testcommon.ValidKubeFedConfig()
+	invalidWorkers := int64(0)
+	invalidWorkersGreaterThan0.Spec.ClusterHealthCheck.Workers = &invalidWorkers
+	errorCases["spec.clusterHealthCheck.workers: Invalid value"] = invalidWorkersGreaterThan0
+
+	invalidJitterPercentageGreaterThan100 := testcommon.ValidKubeFedConfig()
+	invalidJitterPercentage := int64(101)
+	invalidJitterPercentageGreaterThan100.Spec.ClusterHealthCheck.JitterPercentage = &invalidJitterPercentage
+	errorCases["spec.clusterHealthCheck.jitterPercentage: Invalid value"] = invalidJitterPercentageGreaterThan100
+
 	invalidSyncControllerNil := testcommon.ValidKubeFedConfig()
 	invalidSyncControllerNil.Spec.SyncController = nil
 	errorCases["spec.syncController: Required value"] = invalidSyncControllerNil
@@ -804,6 +2053,150 @@ func TestValidateKubeFedConfig(t *testing.T) {
 	invalidAdoptResources.Spec.SyncController.AdoptResources = &invalidAdoptResourcesValue
 	errorCases["spec.syncController.adoptResources: Unsupported value"] = invalidAdoptResources
 
+	invalidPassthroughLabel := testcommon.ValidKubeFedConfig()
+	invalidPassthroughLabel.Spec.SyncController.PropagatedMetadata = &v1beta1.PropagatedMetadataConfig{
+		PassthroughLabels: []string{"example.com/team", "-team"},
+	}
+	errorCases["spec.syncController.propagatedMetadata.passthroughLabels[1]: Invalid value"] = invalidPassthroughLabel
+
+	invalidFailurePercentage := testcommon.ValidKubeFedConfig()
+	*invalidFailurePercentage.Spec.SyncController.Quarantine.FailurePercentage = 101
+	errorCases["spec.syncController.quarantine.failurePercentage: Invalid value"] = invalidFailurePercentage
+
+	invalidMinimumOperations := testcommon.ValidKubeFedConfig()
+	*invalidMinimumOperations.Spec.SyncController.Quarantine.MinimumOperations = 0
+	errorCases["spec.syncController.quarantine.minimumOperations: Invalid value"] = invalidMinimumOperations
+
+	invalidQuarantineWindowNil := testcommon.ValidKubeFedConfig()
+	invalidQuarantineWindowNil.Spec.SyncController.Quarantine.Window = nil
+	errorCases["spec.syncController.quarantine.window: Required value"] = invalidQuarantineWindowNil
+
+	invalidClusterOperationTimeout := testcommon.ValidKubeFedConfig()
+	invalidClusterOperationTimeout.Spec.SyncController.ClusterOperationTimeout = &metav1.Duration{}
+	errorCases["spec.syncController.clusterOperationTimeout: Invalid value"] = invalidClusterOperationTimeout
+
+	invalidSlowClusterThreshold := testcommon.ValidKubeFedConfig()
+	invalidSlowClusterThreshold.Spec.SyncController.SlowClusterThreshold = &metav1.Duration{Duration: -time.Second}
+	errorCases["spec.syncController.slowClusterThreshold: Invalid value"] = invalidSlowClusterThreshold
+
+	invalidDebounceWindow := testcommon.ValidKubeFedConfig()
+	invalidDebounceWindow.Spec.SyncController.DebounceWindow = &metav1.Duration{}
+	errorCases["spec.syncController.debounceWindow: Invalid value"] = invalidDebounceWindow
+
+	invalidImpersonateServiceAccount := testcommon.ValidKubeFedConfig()
+	invalidImpersonateServiceAccount.Spec.SyncController.ImpersonateServiceAccount = "Tenant_Applier"
+	errorCases["spec.syncController.impersonateServiceAccount: Invalid value"] = invalidImpersonateServiceAccount
+
+	validNotificationSink := func() v1beta1.NotificationSink {
+		return v1beta1.NotificationSink{
+			Name:            "slack",
+			URL:             "https://hooks.example.com/services/T000",
+			Events:          []v1beta1.NotificationEvent{v1beta1.NotificationClusterFailed},
+			PayloadTemplate: `{"text": {{ printf "%s %s failed in %s" .Kind .Name .ClusterName | json }}}`,
+		}
+	}
+
+	missingNotificationSinkName := testcommon.ValidKubeFedConfig()
+	sink := validNotificationSink()
+	sink.Name = ""
+	missingNotificationSinkName.Spec.SyncController.NotificationSinks = []v1beta1.NotificationSink{sink}
+	errorCases["spec.syncController.notificationSinks[0].name: Required value"] = missingNotificationSinkName
+
+	duplicateNotificationSinkName := testcommon.ValidKubeFedConfig()
+	duplicateNotificationSinkName.Spec.SyncController.NotificationSinks = []v1beta1.NotificationSink{validNotificationSink(), validNotificationSink()}
+	errorCases["spec.syncController.notificationSinks[1].name: Duplicate value"] = duplicateNotificationSinkName
+
+	insecureNotificationSinkURL := testcommon.ValidKubeFedConfig()
+	sink = validNotificationSink()
+	sink.URL = "http://hooks.example.com/services/T000"
+	insecureNotificationSinkURL.Spec.SyncController.NotificationSinks = []v1beta1.NotificationSink{sink}
+	errorCases["spec.syncController.notificationSinks[0].url: Invalid value"] = insecureNotificationSinkURL
+
+	invalidNotificationEvent := testcommon.ValidKubeFedConfig()
+	sink = validNotificationSink()
+	sink.Events = []v1beta1.NotificationEvent{"Deleted"}
+	invalidNotificationEvent.Spec.SyncController.NotificationSinks = []v1beta1.NotificationSink{sink}
+	errorCases["spec.syncController.notificationSinks[0].events[0]: Unsupported value"] = invalidNotificationEvent
+
+	invalidPayloadTemplate := testcommon.ValidKubeFedConfig()
+	sink = validNotificationSink()
+	sink.PayloadTemplate = "{{ .Name"
+	invalidPayloadTemplate.Spec.SyncController.NotificationSinks = []v1beta1.NotificationSink{sink}
+	errorCases["spec.syncController.notificationSinks[0].payloadTemplate: Invalid value"] = invalidPayloadTemplate
+
+	invalidNotificationTimeout := testcommon.ValidKubeFedConfig()
+	sink = validNotificationSink()
+	timeoutSeconds := int32(60)
+	sink.TimeoutSeconds = &timeoutSeconds
+	invalidNotificationTimeout.Spec.SyncController.NotificationSinks = []v1beta1.NotificationSink{sink}
+	errorCases["spec.syncController.notificationSinks[0].timeoutSeconds: Invalid value"] = invalidNotificationTimeout
+
+	invalidRevisionHistoryLimit := testcommon.ValidKubeFedConfig()
+	revisionHistoryLimit := int32(0)
+	invalidRevisionHistoryLimit.Spec.SyncController.RevisionHistoryLimit = &revisionHistoryLimit
+	errorCases["spec.syncController.revisionHistoryLimit: Invalid value"] = invalidRevisionHistoryLimit
+
+	invalidDeletionHold := testcommon.ValidKubeFedConfig()
+	invalidDeletionHold.Spec.SyncController.DeletionHold = &metav1.Duration{}
+	errorCases["spec.syncController.deletionHold: Invalid value"] = invalidDeletionHold
+
+	invalidClusterApplyRateLimit := testcommon.ValidKubeFedConfig()
+	invalidClusterApplyRateLimit.Spec.SyncController.ClusterApplyRateLimit = &v1beta1.ApplyRateLimit{}
+	errorCases["spec.syncController.clusterApplyRateLimit.objectsPerSecond: Invalid value"] = invalidClusterApplyRateLimit
+
+	invalidReleaseAfter := testcommon.ValidKubeFedConfig()
+	invalidReleaseAfter.Spec.SyncController.Quarantine.ReleaseAfter = &metav1.Duration{}
+	errorCases["spec.syncController.quarantine.releaseAfter: Invalid value"] = invalidReleaseAfter
+
+	invalidMinInterval := testcommon.ValidKubeFedConfig()
+	invalidMinInterval.Spec.StatusController.AdaptiveCollection.MinInterval = &metav1.Duration{}
+	errorCases["spec.statusController.adaptiveCollection.minInterval: Invalid value"] = invalidMinInterval
+
+	invalidMaxInterval := testcommon.ValidKubeFedConfig()
+	invalidMaxInterval.Spec.StatusController.AdaptiveCollection.MaxInterval = &metav1.Duration{
+		Duration: invalidMaxInterval.Spec.StatusController.AdaptiveCollection.MinInterval.Duration / 2,
+	}
+	errorCases["spec.statusController.adaptiveCollection.maxInterval: Invalid value"] = invalidMaxInterval
+
+	invalidStableCollections := testcommon.ValidKubeFedConfig()
+	*invalidStableCollections.Spec.StatusController.AdaptiveCollection.StableCollections = 0
+	errorCases["spec.statusController.adaptiveCollection.stableCollections: Invalid value"] = invalidStableCollections
+
+	invalidLogFormat := testcommon.ValidKubeFedConfig()
+	invalidLogFormatValue := v1beta1.LogFormat("xml")
+	invalidLogFormat.Spec.Logging.Format = &invalidLogFormatValue
+	errorCases["spec.logging.format: Unsupported value"] = invalidLogFormat
+
+	invalidFailurePolicy := testcommon.ValidKubeFedConfig()
+	invalidFailurePolicyValue := v1beta1.WebhookFailurePolicy("Retry")
+	invalidFailurePolicy.Spec.Webhook.FailurePolicy = &invalidFailurePolicyValue
+	errorCases["spec.webhook.failurePolicy: Unsupported value"] = invalidFailurePolicy
+
+	invalidNamespaceSelector := testcommon.ValidKubeFedConfig()
+	invalidNamespaceSelector.Spec.Webhook.NamespaceSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      "name",
+			Operator: metav1.LabelSelectorOpIn,
+		}},
+	}
+	errorCases["spec.webhook.namespaceSelector: Invalid value"] = invalidNamespaceSelector
+
+	invalidComplianceMode := testcommon.ValidKubeFedConfig()
+	invalidComplianceModeValue := v1beta1.ComplianceMode("FIPS")
+	invalidComplianceMode.Spec.Compliance = &v1beta1.ComplianceConfig{Mode: &invalidComplianceModeValue}
+	errorCases["spec.compliance.mode: Unsupported value"] = invalidComplianceMode
+
+	invalidSamenessPolicy := testcommon.ValidKubeFedConfig()
+	invalidSamenessPolicyValue := v1beta1.NamespaceSamenessPolicy("Enforce")
+	invalidSamenessPolicy.Spec.NamespaceSameness = &v1beta1.NamespaceSamenessConfig{Policy: &invalidSamenessPolicyValue}
+	errorCases["spec.namespaceSameness.policy: Unsupported value"] = invalidSamenessPolicy
+
+	invalidSamenessInterval := testcommon.ValidKubeFedConfig()
+	invalidSamenessInterval.Spec.NamespaceSameness = &v1beta1.NamespaceSamenessConfig{
+		Interval: &metav1.Duration{Duration: 10 * time.Second},
+	}
+	errorCases["spec.namespaceSameness.interval: Invalid value"] = invalidSamenessInterval
+
 	for k, v := range errorCases {
 		errs := ValidateKubeFedConfig(v, testcommon.ValidKubeFedConfig())
 		if len(errs) == 0 {
@@ -21,6 +21,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -41,131 +43,70 @@ func (in *APIResource) DeepCopy() *APIResource {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
-	*out = *in
-	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Reason != nil {
-		in, out := &in.Reason, &out.Reason
-		*out = new(string)
-		**out = **in
-	}
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
-func (in *ClusterCondition) DeepCopy() *ClusterCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterHealthCheckConfig) DeepCopyInto(out *ClusterHealthCheckConfig) {
+func (in *AdaptiveStatusCollectionConfig) DeepCopyInto(out *AdaptiveStatusCollectionConfig) {
 	*out = *in
-	if in.Period != nil {
-		in, out := &in.Period, &out.Period
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.FailureThreshold != nil {
-		in, out := &in.FailureThreshold, &out.FailureThreshold
-		*out = new(int64)
-		**out = **in
-	}
-	if in.SuccessThreshold != nil {
-		in, out := &in.SuccessThreshold, &out.SuccessThreshold
-		*out = new(int64)
-		**out = **in
-	}
-	if in.Timeout != nil {
-		in, out := &in.Timeout, &out.Timeout
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
 		*out = new(v1.Duration)
 		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheckConfig.
-func (in *ClusterHealthCheckConfig) DeepCopy() *ClusterHealthCheckConfig {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterHealthCheckConfig)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DurationConfig) DeepCopyInto(out *DurationConfig) {
-	*out = *in
-	if in.AvailableDelay != nil {
-		in, out := &in.AvailableDelay, &out.AvailableDelay
+	if in.MaxInterval != nil {
+		in, out := &in.MaxInterval, &out.MaxInterval
 		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.UnavailableDelay != nil {
-		in, out := &in.UnavailableDelay, &out.UnavailableDelay
-		*out = new(v1.Duration)
+	if in.StableCollections != nil {
+		in, out := &in.StableCollections, &out.StableCollections
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DurationConfig.
-func (in *DurationConfig) DeepCopy() *DurationConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptiveStatusCollectionConfig.
+func (in *AdaptiveStatusCollectionConfig) DeepCopy() *AdaptiveStatusCollectionConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(DurationConfig)
+	out := new(AdaptiveStatusCollectionConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureGatesConfig) DeepCopyInto(out *FeatureGatesConfig) {
+func (in *ApplyRateLimit) DeepCopyInto(out *ApplyRateLimit) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGatesConfig.
-func (in *FeatureGatesConfig) DeepCopy() *FeatureGatesConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyRateLimit.
+func (in *ApplyRateLimit) DeepCopy() *ApplyRateLimit {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureGatesConfig)
+	out := new(ApplyRateLimit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfig) DeepCopyInto(out *FederatedTypeConfig) {
+func (in *AutoEnablePolicy) DeepCopyInto(out *AutoEnablePolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfig.
-func (in *FederatedTypeConfig) DeepCopy() *FederatedTypeConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoEnablePolicy.
+func (in *AutoEnablePolicy) DeepCopy() *AutoEnablePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfig)
+	out := new(AutoEnablePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FederatedTypeConfig) DeepCopyObject() runtime.Object {
+func (in *AutoEnablePolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -173,31 +114,31 @@ func (in *FederatedTypeConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfigList) DeepCopyInto(out *FederatedTypeConfigList) {
+func (in *AutoEnablePolicyList) DeepCopyInto(out *AutoEnablePolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]FederatedTypeConfig, len(*in))
+		*out = make([]AutoEnablePolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigList.
-func (in *FederatedTypeConfigList) DeepCopy() *FederatedTypeConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoEnablePolicyList.
+func (in *AutoEnablePolicyList) DeepCopy() *AutoEnablePolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfigList)
+	out := new(AutoEnablePolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FederatedTypeConfigList) DeepCopyObject() runtime.Object {
+func (in *AutoEnablePolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -205,54 +146,103 @@ func (in *FederatedTypeConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfigSpec) DeepCopyInto(out *FederatedTypeConfigSpec) {
+func (in *AutoEnablePolicySpec) DeepCopyInto(out *AutoEnablePolicySpec) {
 	*out = *in
-	out.TargetType = in.TargetType
-	out.FederatedType = in.FederatedType
-	if in.StatusType != nil {
-		in, out := &in.StatusType, &out.StatusType
-		*out = new(APIResource)
-		**out = **in
+	if in.GroupSuffixes != nil {
+		in, out := &in.GroupSuffixes, &out.GroupSuffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.StatusCollection != nil {
-		in, out := &in.StatusCollection, &out.StatusCollection
-		*out = new(StatusCollectionMode)
-		**out = **in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigSpec.
-func (in *FederatedTypeConfigSpec) DeepCopy() *FederatedTypeConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoEnablePolicySpec.
+func (in *AutoEnablePolicySpec) DeepCopy() *AutoEnablePolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfigSpec)
+	out := new(AutoEnablePolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfigStatus) DeepCopyInto(out *FederatedTypeConfigStatus) {
+func (in *BackfillPhaseProgress) DeepCopyInto(out *BackfillPhaseProgress) {
 	*out = *in
-	if in.StatusController != nil {
-		in, out := &in.StatusController, &out.StatusController
-		*out = new(ControllerStatus)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackfillPhaseProgress.
+func (in *BackfillPhaseProgress) DeepCopy() *BackfillPhaseProgress {
+	if in == nil {
+		return nil
 	}
+	out := new(BackfillPhaseProgress)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigStatus.
-func (in *FederatedTypeConfigStatus) DeepCopy() *FederatedTypeConfigStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackfillProgress) DeepCopyInto(out *BackfillProgress) {
+	*out = *in
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]BackfillPhaseProgress, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.PhaseStartTime.DeepCopyInto(&out.PhaseStartTime)
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackfillProgress.
+func (in *BackfillProgress) DeepCopy() *BackfillProgress {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfigStatus)
+	out := new(BackfillProgress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedCluster) DeepCopyInto(out *KubeFedCluster) {
+func (in *Blueprint) DeepCopyInto(out *Blueprint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Blueprint.
+func (in *Blueprint) DeepCopy() *Blueprint {
+	if in == nil {
+		return nil
+	}
+	out := new(Blueprint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Blueprint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintInstance) DeepCopyInto(out *BlueprintInstance) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -260,18 +250,18 @@ func (in *KubeFedCluster) DeepCopyInto(out *KubeFedCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedCluster.
-func (in *KubeFedCluster) DeepCopy() *KubeFedCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintInstance.
+func (in *BlueprintInstance) DeepCopy() *BlueprintInstance {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedCluster)
+	out := new(BlueprintInstance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedCluster) DeepCopyObject() runtime.Object {
+func (in *BlueprintInstance) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -279,31 +269,31 @@ func (in *KubeFedCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedClusterList) DeepCopyInto(out *KubeFedClusterList) {
+func (in *BlueprintInstanceList) DeepCopyInto(out *BlueprintInstanceList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KubeFedCluster, len(*in))
+		*out = make([]BlueprintInstance, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterList.
-func (in *KubeFedClusterList) DeepCopy() *KubeFedClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintInstanceList.
+func (in *BlueprintInstanceList) DeepCopy() *BlueprintInstanceList {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedClusterList)
+	out := new(BlueprintInstanceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedClusterList) DeepCopyObject() runtime.Object {
+func (in *BlueprintInstanceList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -311,115 +301,89 @@ func (in *KubeFedClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedClusterSpec) DeepCopyInto(out *KubeFedClusterSpec) {
+func (in *BlueprintInstanceResource) DeepCopyInto(out *BlueprintInstanceResource) {
 	*out = *in
-	if in.CABundle != nil {
-		in, out := &in.CABundle, &out.CABundle
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
-	}
-	out.SecretRef = in.SecretRef
-	if in.DisabledTLSValidations != nil {
-		in, out := &in.DisabledTLSValidations, &out.DisabledTLSValidations
-		*out = make([]TLSValidation, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterSpec.
-func (in *KubeFedClusterSpec) DeepCopy() *KubeFedClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintInstanceResource.
+func (in *BlueprintInstanceResource) DeepCopy() *BlueprintInstanceResource {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedClusterSpec)
+	out := new(BlueprintInstanceResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedClusterStatus) DeepCopyInto(out *KubeFedClusterStatus) {
+func (in *BlueprintInstanceSpec) DeepCopyInto(out *BlueprintInstanceSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ClusterCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	out.BlueprintRef = in.BlueprintRef
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	if in.Zones != nil {
-		in, out := &in.Zones, &out.Zones
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Region != nil {
-		in, out := &in.Region, &out.Region
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterStatus.
-func (in *KubeFedClusterStatus) DeepCopy() *KubeFedClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintInstanceSpec.
+func (in *BlueprintInstanceSpec) DeepCopy() *BlueprintInstanceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedClusterStatus)
+	out := new(BlueprintInstanceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedConfig) DeepCopyInto(out *KubeFedConfig) {
+func (in *BlueprintInstanceStatus) DeepCopyInto(out *BlueprintInstanceStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]BlueprintInstanceResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfig.
-func (in *KubeFedConfig) DeepCopy() *KubeFedConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintInstanceStatus.
+func (in *BlueprintInstanceStatus) DeepCopy() *BlueprintInstanceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedConfig)
+	out := new(BlueprintInstanceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedConfigList) DeepCopyInto(out *KubeFedConfigList) {
+func (in *BlueprintList) DeepCopyInto(out *BlueprintList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KubeFedConfig, len(*in))
+		*out = make([]Blueprint, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigList.
-func (in *KubeFedConfigList) DeepCopy() *KubeFedConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintList.
+func (in *BlueprintList) DeepCopy() *BlueprintList {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedConfigList)
+	out := new(BlueprintList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedConfigList) DeepCopyObject() runtime.Object {
+func (in *BlueprintList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -427,91 +391,2350 @@ func (in *KubeFedConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedConfigSpec) DeepCopyInto(out *KubeFedConfigSpec) {
+func (in *BlueprintParameter) DeepCopyInto(out *BlueprintParameter) {
 	*out = *in
-	if in.ControllerDuration != nil {
-		in, out := &in.ControllerDuration, &out.ControllerDuration
-		*out = new(DurationConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.LeaderElect != nil {
-		in, out := &in.LeaderElect, &out.LeaderElect
-		*out = new(LeaderElectConfig)
-		(*in).DeepCopyInto(*out)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
 	}
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make([]FeatureGatesConfig, len(*in))
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ClusterHealthCheck != nil {
-		in, out := &in.ClusterHealthCheck, &out.ClusterHealthCheck
-		*out = new(ClusterHealthCheckConfig)
-		(*in).DeepCopyInto(*out)
+	if in.Minimum != nil {
+		in, out := &in.Minimum, &out.Minimum
+		*out = new(int64)
+		**out = **in
 	}
-	if in.SyncController != nil {
-		in, out := &in.SyncController, &out.SyncController
-		*out = new(SyncControllerConfig)
-		(*in).DeepCopyInto(*out)
+	if in.Maximum != nil {
+		in, out := &in.Maximum, &out.Maximum
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigSpec.
-func (in *KubeFedConfigSpec) DeepCopy() *KubeFedConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintParameter.
+func (in *BlueprintParameter) DeepCopy() *BlueprintParameter {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedConfigSpec)
+	out := new(BlueprintParameter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LeaderElectConfig) DeepCopyInto(out *LeaderElectConfig) {
+func (in *BlueprintReference) DeepCopyInto(out *BlueprintReference) {
 	*out = *in
-	if in.LeaseDuration != nil {
-		in, out := &in.LeaseDuration, &out.LeaseDuration
-		*out = new(v1.Duration)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintReference.
+func (in *BlueprintReference) DeepCopy() *BlueprintReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintSpec) DeepCopyInto(out *BlueprintSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]BlueprintParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintSpec.
+func (in *BlueprintSpec) DeepCopy() *BlueprintSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAllowlist) DeepCopyInto(out *ClusterAllowlist) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAllowlist.
+func (in *ClusterAllowlist) DeepCopy() *ClusterAllowlist {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAllowlist)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterAllowlist) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAllowlistList) DeepCopyInto(out *ClusterAllowlistList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterAllowlist, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAllowlistList.
+func (in *ClusterAllowlistList) DeepCopy() *ClusterAllowlistList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAllowlistList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterAllowlistList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAllowlistSpec) DeepCopyInto(out *ClusterAllowlistSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAllowlistSpec.
+func (in *ClusterAllowlistSpec) DeepCopy() *ClusterAllowlistSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAllowlistSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Reason != nil {
+		in, out := &in.Reason, &out.Reason
+		*out = new(string)
+		**out = **in
+	}
+	if in.Message != nil {
+		in, out := &in.Message, &out.Message
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
+func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDiscoveryCache) DeepCopyInto(out *ClusterDiscoveryCache) {
+	*out = *in
+	in.LastRefreshTime.DeepCopyInto(&out.LastRefreshTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDiscoveryCache.
+func (in *ClusterDiscoveryCache) DeepCopy() *ClusterDiscoveryCache {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDiscoveryCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterFaultDomain) DeepCopyInto(out *ClusterFaultDomain) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterFaultDomain.
+func (in *ClusterFaultDomain) DeepCopy() *ClusterFaultDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterFaultDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterGroup) DeepCopyInto(out *ClusterGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterGroup.
+func (in *ClusterGroup) DeepCopy() *ClusterGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterGroupList) DeepCopyInto(out *ClusterGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterGroupList.
+func (in *ClusterGroupList) DeepCopy() *ClusterGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterGroupSpec) DeepCopyInto(out *ClusterGroupSpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterGroupSpec.
+func (in *ClusterGroupSpec) DeepCopy() *ClusterGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInventory) DeepCopyInto(out *ClusterInventory) {
+	*out = *in
+	if in.KubeletVersions != nil {
+		in, out := &in.KubeletVersions, &out.KubeletVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.CRDs != nil {
+		in, out := &in.CRDs, &out.CRDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIVersions != nil {
+		in, out := &in.APIVersions, &out.APIVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastRefreshTime.DeepCopyInto(&out.LastRefreshTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInventory.
+func (in *ClusterInventory) DeepCopy() *ClusterInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequest) DeepCopyInto(out *ClusterJoinRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequest.
+func (in *ClusterJoinRequest) DeepCopy() *ClusterJoinRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterJoinRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequestCondition) DeepCopyInto(out *ClusterJoinRequestCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequestCondition.
+func (in *ClusterJoinRequestCondition) DeepCopy() *ClusterJoinRequestCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequestCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequestList) DeepCopyInto(out *ClusterJoinRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterJoinRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequestList.
+func (in *ClusterJoinRequestList) DeepCopy() *ClusterJoinRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterJoinRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequestSpec) DeepCopyInto(out *ClusterJoinRequestSpec) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountToken != nil {
+		in, out := &in.ServiceAccountToken, &out.ServiceAccountToken
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterLabels != nil {
+		in, out := &in.ClusterLabels, &out.ClusterLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequestSpec.
+func (in *ClusterJoinRequestSpec) DeepCopy() *ClusterJoinRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequestStatus) DeepCopyInto(out *ClusterJoinRequestStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterJoinRequestCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequestStatus.
+func (in *ClusterJoinRequestStatus) DeepCopy() *ClusterJoinRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthCheckConfig) DeepCopyInto(out *ClusterHealthCheckConfig) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EdgeFailureThreshold != nil {
+		in, out := &in.EdgeFailureThreshold, &out.EdgeFailureThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(int64)
+		**out = **in
+	}
+	if in.JitterPercentage != nil {
+		in, out := &in.JitterPercentage, &out.JitterPercentage
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheckConfig.
+func (in *ClusterHealthCheckConfig) DeepCopy() *ClusterHealthCheckConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthCheckConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNetwork) DeepCopyInto(out *ClusterNetwork) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNetwork.
+func (in *ClusterNetwork) DeepCopy() *ClusterNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuarantine) DeepCopyInto(out *ClusterQuarantine) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuarantine.
+func (in *ClusterQuarantine) DeepCopy() *ClusterQuarantine {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuarantine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceConfig) DeepCopyInto(out *ComplianceConfig) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ComplianceMode)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceConfig.
+func (in *ComplianceConfig) DeepCopy() *ComplianceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceStatus) DeepCopyInto(out *ComplianceStatus) {
+	*out = *in
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisabledIntegrations != nil {
+		in, out := &in.DisabledIntegrations, &out.DisabledIntegrations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceStatus.
+func (in *ComplianceStatus) DeepCopy() *ComplianceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConflictResolution) DeepCopyInto(out *ConflictResolution) {
+	*out = *in
+	if in.LocalFields != nil {
+		in, out := &in.LocalFields, &out.LocalFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConflictResolution.
+func (in *ConflictResolution) DeepCopy() *ConflictResolution {
+	if in == nil {
+		return nil
+	}
+	out := new(ConflictResolution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchMutatorConfig) DeepCopyInto(out *DispatchMutatorConfig) {
+	*out = *in
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageRewrite != nil {
+		in, out := &in.ImageRewrite, &out.ImageRewrite
+		*out = new(ImageRewriteMutator)
+		**out = **in
+	}
+	if in.LabelInjection != nil {
+		in, out := &in.LabelInjection, &out.LabelInjection
+		*out = new(LabelInjectionMutator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelectorInjection != nil {
+		in, out := &in.NodeSelectorInjection, &out.NodeSelectorInjection
+		*out = new(NodeSelectorInjectionMutator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRequestScaling != nil {
+		in, out := &in.ResourceRequestScaling, &out.ResourceRequestScaling
+		*out = new(ResourceRequestScalingMutator)
+		**out = **in
+	}
+	if in.SubjectRewrite != nil {
+		in, out := &in.SubjectRewrite, &out.SubjectRewrite
+		*out = new(SubjectRewriteMutator)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchMutatorConfig.
+func (in *DispatchMutatorConfig) DeepCopy() *DispatchMutatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchMutatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DurationConfig) DeepCopyInto(out *DurationConfig) {
+	*out = *in
+	if in.AvailableDelay != nil {
+		in, out := &in.AvailableDelay, &out.AvailableDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.UnavailableDelay != nil {
+		in, out := &in.UnavailableDelay, &out.UnavailableDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DurationConfig.
+func (in *DurationConfig) DeepCopy() *DurationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DurationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretReference) DeepCopyInto(out *ExternalSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretReference.
+func (in *ExternalSecretReference) DeepCopy() *ExternalSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultInjection) DeepCopyInto(out *FaultInjection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultInjection.
+func (in *FaultInjection) DeepCopy() *FaultInjection {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultInjection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FaultInjection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultInjectionList) DeepCopyInto(out *FaultInjectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FaultInjection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultInjectionList.
+func (in *FaultInjectionList) DeepCopy() *FaultInjectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultInjectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FaultInjectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultInjectionSpec) DeepCopyInto(out *FaultInjectionSpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]FaultOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultInjectionSpec.
+func (in *FaultInjectionSpec) DeepCopy() *FaultInjectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultInjectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureGatesConfig) DeepCopyInto(out *FeatureGatesConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGatesConfig.
+func (in *FeatureGatesConfig) DeepCopy() *FeatureGatesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureGatesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedApplication) DeepCopyInto(out *FederatedApplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedApplication.
+func (in *FederatedApplication) DeepCopy() *FederatedApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedApplicationList) DeepCopyInto(out *FederatedApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FederatedApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedApplicationList.
+func (in *FederatedApplicationList) DeepCopy() *FederatedApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedApplicationPlacement) DeepCopyInto(out *FederatedApplicationPlacement) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterGroups != nil {
+		in, out := &in.ClusterGroups, &out.ClusterGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedApplicationPlacement.
+func (in *FederatedApplicationPlacement) DeepCopy() *FederatedApplicationPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedApplicationPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedApplicationResource) DeepCopyInto(out *FederatedApplicationResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedApplicationResource.
+func (in *FederatedApplicationResource) DeepCopy() *FederatedApplicationResource {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedApplicationResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedApplicationResourceStatus) DeepCopyInto(out *FederatedApplicationResourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedApplicationResourceStatus.
+func (in *FederatedApplicationResourceStatus) DeepCopy() *FederatedApplicationResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedApplicationResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedApplicationSpec) DeepCopyInto(out *FederatedApplicationSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]FederatedApplicationResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(FederatedApplicationPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedApplicationSpec.
+func (in *FederatedApplicationSpec) DeepCopy() *FederatedApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedApplicationStatus) DeepCopyInto(out *FederatedApplicationStatus) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]FederatedApplicationResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedApplicationStatus.
+func (in *FederatedApplicationStatus) DeepCopy() *FederatedApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTemplate) DeepCopyInto(out *FederatedTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTemplate.
+func (in *FederatedTemplate) DeepCopy() *FederatedTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTemplateList) DeepCopyInto(out *FederatedTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FederatedTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTemplateList.
+func (in *FederatedTemplateList) DeepCopy() *FederatedTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTemplateParameter) DeepCopyInto(out *FederatedTemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTemplateParameter.
+func (in *FederatedTemplateParameter) DeepCopy() *FederatedTemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTemplateSpec) DeepCopyInto(out *FederatedTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]FederatedTemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTemplateSpec.
+func (in *FederatedTemplateSpec) DeepCopy() *FederatedTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfig) DeepCopyInto(out *FederatedTypeConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfig.
+func (in *FederatedTypeConfig) DeepCopy() *FederatedTypeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedTypeConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfigClusterStatus) DeepCopyInto(out *FederatedTypeConfigClusterStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigClusterStatus.
+func (in *FederatedTypeConfigClusterStatus) DeepCopy() *FederatedTypeConfigClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfigClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfigList) DeepCopyInto(out *FederatedTypeConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FederatedTypeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigList.
+func (in *FederatedTypeConfigList) DeepCopy() *FederatedTypeConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedTypeConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfigSpec) DeepCopyInto(out *FederatedTypeConfigSpec) {
+	*out = *in
+	out.TargetType = in.TargetType
+	if in.ResourceDeletion != nil {
+		in, out := &in.ResourceDeletion, &out.ResourceDeletion
+		*out = new(ResourceDeletion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PropagationMode != nil {
+		in, out := &in.PropagationMode, &out.PropagationMode
+		*out = new(ResourcePropagationMode)
+		**out = **in
+	}
+	if in.ConflictResolution != nil {
+		in, out := &in.ConflictResolution, &out.ConflictResolution
+		*out = new(ConflictResolution)
+		(*in).DeepCopyInto(*out)
+	}
+	out.FederatedType = in.FederatedType
+	if in.StatusType != nil {
+		in, out := &in.StatusType, &out.StatusType
+		*out = new(APIResource)
+		**out = **in
+	}
+	if in.StatusCollection != nil {
+		in, out := &in.StatusCollection, &out.StatusCollection
+		*out = new(StatusCollectionMode)
+		**out = **in
+	}
+	if in.StatusFields != nil {
+		in, out := &in.StatusFields, &out.StatusFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthInterpreter != nil {
+		in, out := &in.HealthInterpreter, &out.HealthInterpreter
+		*out = new(HealthInterpreter)
+		**out = **in
+	}
+	if in.DispatchMutators != nil {
+		in, out := &in.DispatchMutators, &out.DispatchMutators
+		*out = make([]DispatchMutatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PropagationWebhooks != nil {
+		in, out := &in.PropagationWebhooks, &out.PropagationWebhooks
+		*out = make([]PropagationWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigSpec.
+func (in *FederatedTypeConfigSpec) DeepCopy() *FederatedTypeConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfigStatus) DeepCopyInto(out *FederatedTypeConfigStatus) {
+	*out = *in
+	if in.StatusController != nil {
+		in, out := &in.StatusController, &out.StatusController
+		*out = new(ControllerStatus)
+		**out = **in
+	}
+	if in.ResourceCount != nil {
+		in, out := &in.ResourceCount, &out.ResourceCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PropagatedResourceCount != nil {
+		in, out := &in.PropagatedResourceCount, &out.PropagatedResourceCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]FederatedTypeConfigClusterStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceDeletion != nil {
+		in, out := &in.ResourceDeletion, &out.ResourceDeletion
+		*out = new(ResourceDeletionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigStatus.
+func (in *FederatedTypeConfigStatus) DeepCopy() *FederatedTypeConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthInterpreter) DeepCopyInto(out *HealthInterpreter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthInterpreter.
+func (in *HealthInterpreter) DeepCopy() *HealthInterpreter {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthInterpreter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewriteMutator) DeepCopyInto(out *ImageRewriteMutator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewriteMutator.
+func (in *ImageRewriteMutator) DeepCopy() *ImageRewriteMutator {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewriteMutator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedCluster) DeepCopyInto(out *KubeFedCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedCluster.
+func (in *KubeFedCluster) DeepCopy() *KubeFedCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedClusterList) DeepCopyInto(out *KubeFedClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeFedCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterList.
+func (in *KubeFedClusterList) DeepCopy() *KubeFedClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedClusterSpec) DeepCopyInto(out *KubeFedClusterSpec) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+	if in.DisabledTLSValidations != nil {
+		in, out := &in.DisabledTLSValidations, &out.DisabledTLSValidations
+		*out = make([]TLSValidation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(ClusterNetwork)
+		**out = **in
+	}
+	if in.OperationTimeout != nil {
+		in, out := &in.OperationTimeout, &out.OperationTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ApplyRateLimit != nil {
+		in, out := &in.ApplyRateLimit, &out.ApplyRateLimit
+		*out = new(ApplyRateLimit)
+		**out = **in
+	}
+	if in.FaultDomain != nil {
+		in, out := &in.FaultDomain, &out.FaultDomain
+		*out = new(ClusterFaultDomain)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterSpec.
+func (in *KubeFedClusterSpec) DeepCopy() *KubeFedClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedClusterStatus) DeepCopyInto(out *KubeFedClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(ClusterInventory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backfill != nil {
+		in, out := &in.Backfill, &out.Backfill
+		*out = new(BackfillProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quarantine != nil {
+		in, out := &in.Quarantine, &out.Quarantine
+		*out = new(ClusterQuarantine)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiscoveryCache != nil {
+		in, out := &in.DiscoveryCache, &out.DiscoveryCache
+		*out = new(ClusterDiscoveryCache)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterStatus.
+func (in *KubeFedClusterStatus) DeepCopy() *KubeFedClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfig) DeepCopyInto(out *KubeFedConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfig.
+func (in *KubeFedConfig) DeepCopy() *KubeFedConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfigList) DeepCopyInto(out *KubeFedConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeFedConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigList.
+func (in *KubeFedConfigList) DeepCopy() *KubeFedConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfigSpec) DeepCopyInto(out *KubeFedConfigSpec) {
+	*out = *in
+	if in.ControllerDuration != nil {
+		in, out := &in.ControllerDuration, &out.ControllerDuration
+		*out = new(DurationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LeaderElect != nil {
+		in, out := &in.LeaderElect, &out.LeaderElect
+		*out = new(LeaderElectConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]FeatureGatesConfig, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterHealthCheck != nil {
+		in, out := &in.ClusterHealthCheck, &out.ClusterHealthCheck
+		*out = new(ClusterHealthCheckConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncController != nil {
+		in, out := &in.SyncController, &out.SyncController
+		*out = new(SyncControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatusController != nil {
+		in, out := &in.StatusController, &out.StatusController
+		*out = new(StatusControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Compliance != nil {
+		in, out := &in.Compliance, &out.Compliance
+		*out = new(ComplianceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSameness != nil {
+		in, out := &in.NamespaceSameness, &out.NamespaceSameness
+		*out = new(NamespaceSamenessConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigSpec.
+func (in *KubeFedConfigSpec) DeepCopy() *KubeFedConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstallation) DeepCopyInto(out *KubeFedInstallation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstallation.
+func (in *KubeFedInstallation) DeepCopy() *KubeFedInstallation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstallation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedInstallation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstallationCondition) DeepCopyInto(out *KubeFedInstallationCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstallationCondition.
+func (in *KubeFedInstallationCondition) DeepCopy() *KubeFedInstallationCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstallationCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstallationList) DeepCopyInto(out *KubeFedInstallationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeFedInstallation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstallationList.
+func (in *KubeFedInstallationList) DeepCopy() *KubeFedInstallationList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstallationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedInstallationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstallationSpec) DeepCopyInto(out *KubeFedInstallationSpec) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerManagerResources != nil {
+		in, out := &in.ControllerManagerResources, &out.ControllerManagerResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebhookResources != nil {
+		in, out := &in.WebhookResources, &out.WebhookResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstallationSpec.
+func (in *KubeFedInstallationSpec) DeepCopy() *KubeFedInstallationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstallationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstallationStatus) DeepCopyInto(out *KubeFedInstallationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]KubeFedInstallationCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstallationStatus.
+func (in *KubeFedInstallationStatus) DeepCopy() *KubeFedInstallationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstallationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstance) DeepCopyInto(out *KubeFedInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstance.
+func (in *KubeFedInstance) DeepCopy() *KubeFedInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedInstance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstanceList) DeepCopyInto(out *KubeFedInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeFedInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstanceList.
+func (in *KubeFedInstanceList) DeepCopy() *KubeFedInstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedInstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedInstanceSpec) DeepCopyInto(out *KubeFedInstanceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedInstanceSpec.
+func (in *KubeFedInstanceSpec) DeepCopy() *KubeFedInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfigStatus) DeepCopyInto(out *KubeFedConfigStatus) {
+	*out = *in
+	if in.Compliance != nil {
+		in, out := &in.Compliance, &out.Compliance
+		*out = new(ComplianceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigStatus.
+func (in *KubeFedConfigStatus) DeepCopy() *KubeFedConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelInjectionMutator) DeepCopyInto(out *LabelInjectionMutator) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelInjectionMutator.
+func (in *LabelInjectionMutator) DeepCopy() *LabelInjectionMutator {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelInjectionMutator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectConfig) DeepCopyInto(out *LeaderElectConfig) {
+	*out = *in
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(v1.Duration)
+		**out = **in
 	}
 	if in.RenewDeadline != nil {
 		in, out := &in.RenewDeadline, &out.RenewDeadline
 		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.RetryPeriod != nil {
-		in, out := &in.RetryPeriod, &out.RetryPeriod
+	if in.RetryPeriod != nil {
+		in, out := &in.RetryPeriod, &out.RetryPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResourceLock != nil {
+		in, out := &in.ResourceLock, &out.ResourceLock
+		*out = new(ResourceLockType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectConfig.
+func (in *LeaderElectConfig) DeepCopy() *LeaderElectConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalSecretReference) DeepCopyInto(out *LocalSecretReference) {
+	*out = *in
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalSecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretReference.
+func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingConfig) DeepCopyInto(out *LoggingConfig) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(LogFormat)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingConfig.
+func (in *LoggingConfig) DeepCopy() *LoggingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceWindow) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowList) DeepCopyInto(out *MaintenanceWindowList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowList.
+func (in *MaintenanceWindowList) DeepCopy() *MaintenanceWindowList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceWindowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceProfile) DeepCopyInto(out *NamespaceProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceProfile.
+func (in *NamespaceProfile) DeepCopy() *NamespaceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceProfileList) DeepCopyInto(out *NamespaceProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceProfileList.
+func (in *NamespaceProfileList) DeepCopy() *NamespaceProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceProfileSpec) DeepCopyInto(out *NamespaceProfileSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceProfileSpec.
+func (in *NamespaceProfileSpec) DeepCopy() *NamespaceProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSamenessConfig) DeepCopyInto(out *NamespaceSamenessConfig) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(NamespaceSamenessPolicy)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSamenessConfig.
+func (in *NamespaceSamenessConfig) DeepCopy() *NamespaceSamenessConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSamenessConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSelectorInjectionMutator) DeepCopyInto(out *NodeSelectorInjectionMutator) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSelectorInjectionMutator.
+func (in *NodeSelectorInjectionMutator) DeepCopy() *NodeSelectorInjectionMutator {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSelectorInjectionMutator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagatedMetadataConfig) DeepCopyInto(out *PropagatedMetadataConfig) {
+	*out = *in
+	if in.PassthroughLabels != nil {
+		in, out := &in.PassthroughLabels, &out.PassthroughLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagatedMetadataConfig.
+func (in *PropagatedMetadataConfig) DeepCopy() *PropagatedMetadataConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagatedMetadataConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagationWebhook) DeepCopyInto(out *PropagationWebhook) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationWebhook.
+func (in *PropagationWebhook) DeepCopy() *PropagationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuarantineConfig) DeepCopyInto(out *QuarantineConfig) {
+	*out = *in
+	if in.FailurePercentage != nil {
+		in, out := &in.FailurePercentage, &out.FailurePercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinimumOperations != nil {
+		in, out := &in.MinimumOperations, &out.MinimumOperations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
 		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.ResourceLock != nil {
-		in, out := &in.ResourceLock, &out.ResourceLock
-		*out = new(ResourceLockType)
+	if in.ReleaseAfter != nil {
+		in, out := &in.ReleaseAfter, &out.ReleaseAfter
+		*out = new(v1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectConfig.
-func (in *LeaderElectConfig) DeepCopy() *LeaderElectConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarantineConfig.
+func (in *QuarantineConfig) DeepCopy() *QuarantineConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(LeaderElectConfig)
+	out := new(QuarantineConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalSecretReference) DeepCopyInto(out *LocalSecretReference) {
+func (in *ResourceDeletion) DeepCopyInto(out *ResourceDeletion) {
 	*out = *in
+	if in.DeletesPerSecond != nil {
+		in, out := &in.DeletesPerSecond, &out.DeletesPerSecond
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretReference.
-func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDeletion.
+func (in *ResourceDeletion) DeepCopy() *ResourceDeletion {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalSecretReference)
+	out := new(ResourceDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDeletionStatus) DeepCopyInto(out *ResourceDeletionStatus) {
+	*out = *in
+	if in.PendingClusters != nil {
+		in, out := &in.PendingClusters, &out.PendingClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDeletionStatus.
+func (in *ResourceDeletionStatus) DeepCopy() *ResourceDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRequestScalingMutator) DeepCopyInto(out *ResourceRequestScalingMutator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRequestScalingMutator.
+func (in *ResourceRequestScalingMutator) DeepCopy() *ResourceRequestScalingMutator {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRequestScalingMutator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusControllerConfig) DeepCopyInto(out *StatusControllerConfig) {
+	*out = *in
+	if in.AdaptiveCollection != nil {
+		in, out := &in.AdaptiveCollection, &out.AdaptiveCollection
+		*out = new(AdaptiveStatusCollectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusControllerConfig.
+func (in *StatusControllerConfig) DeepCopy() *StatusControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubjectMapping) DeepCopyInto(out *SubjectMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubjectMapping.
+func (in *SubjectMapping) DeepCopy() *SubjectMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(SubjectMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubjectRewriteMutator) DeepCopyInto(out *SubjectRewriteMutator) {
+	*out = *in
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]SubjectMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubjectRewriteMutator.
+func (in *SubjectRewriteMutator) DeepCopy() *SubjectRewriteMutator {
+	if in == nil {
+		return nil
+	}
+	out := new(SubjectRewriteMutator)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -524,6 +2747,53 @@ func (in *SyncControllerConfig) DeepCopyInto(out *SyncControllerConfig) {
 		*out = new(ResourceAdoption)
 		**out = **in
 	}
+	if in.ClusterApplyRateLimit != nil {
+		in, out := &in.ClusterApplyRateLimit, &out.ClusterApplyRateLimit
+		*out = new(ApplyRateLimit)
+		**out = **in
+	}
+	if in.ClusterOperationTimeout != nil {
+		in, out := &in.ClusterOperationTimeout, &out.ClusterOperationTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DebounceWindow != nil {
+		in, out := &in.DebounceWindow, &out.DebounceWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeletionHold != nil {
+		in, out := &in.DeletionHold, &out.DeletionHold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NotificationSinks != nil {
+		in, out := &in.NotificationSinks, &out.NotificationSinks
+		*out = make([]NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PropagatedMetadata != nil {
+		in, out := &in.PropagatedMetadata, &out.PropagatedMetadata
+		*out = new(PropagatedMetadataConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quarantine != nil {
+		in, out := &in.Quarantine, &out.Quarantine
+		*out = new(QuarantineConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SlowClusterThreshold != nil {
+		in, out := &in.SlowClusterThreshold, &out.SlowClusterThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncControllerConfig.
@@ -535,3 +2805,28 @@ func (in *SyncControllerConfig) DeepCopy() *SyncControllerConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
+	*out = *in
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(WebhookFailurePolicy)
+		**out = **in
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfig.
+func (in *WebhookConfig) DeepCopy() *WebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
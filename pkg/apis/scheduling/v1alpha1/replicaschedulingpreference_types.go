@@ -20,6 +20,38 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ReplicaDistribution describes how replicas are distributed among
+// clusters.
+type ReplicaDistribution string
+
+const (
+	// WeightedDistribution distributes replicas in proportion to the
+	// weights of the clusters.
+	WeightedDistribution ReplicaDistribution = "Weighted"
+	// CostOptimizedDistribution distributes replicas to the clusters
+	// with the lowest cost weight first.
+	CostOptimizedDistribution ReplicaDistribution = "CostOptimized"
+	// SpilloverDistribution distributes replicas to the primary
+	// cluster first, and only the remainder to the other clusters.
+	SpilloverDistribution ReplicaDistribution = "Spillover"
+)
+
+// FailoverDomain is the level of the fault domains of clusters that
+// replicas fail over across.
+type FailoverDomain string
+
+const (
+	// ProviderFailoverDomain fails replicas over to clusters of other
+	// providers.
+	ProviderFailoverDomain FailoverDomain = "Provider"
+	// RegionFailoverDomain fails replicas over to clusters of other
+	// regions.
+	RegionFailoverDomain FailoverDomain = "Region"
+	// DatacenterFailoverDomain fails replicas over to clusters of
+	// other datacenters.
+	DatacenterFailoverDomain FailoverDomain = "Datacenter"
+)
+
 // ReplicaSchedulingPreferenceSpec defines the desired state of ReplicaSchedulingPreference
 type ReplicaSchedulingPreferenceSpec struct {
 	//TODO (@irfanurrehman); upgrade this to label selector only if need be.
@@ -45,12 +77,145 @@ type ReplicaSchedulingPreferenceSpec struct {
 	// +optional
 	Rebalance bool `json:"rebalance,omitempty"`
 
+	// How replicas in excess of the minimum replicas of each cluster
+	// are distributed. Weighted distributes them in proportion to the
+	// weights of the clusters. CostOptimized assigns them to the
+	// clusters with the lowest cost weight first, so that more
+	// expensive clusters only receive replicas that cheaper clusters
+	// lack the capacity or maximum replicas for. Spillover assigns them
+	// to the primary cluster, and distributes only the replicas the
+	// primary cluster lacks the capacity or maximum replicas for to the
+	// other clusters in proportion to their weights. Defaults to
+	// Weighted.
+	// +optional
+	Distribution ReplicaDistribution `json:"distribution,omitempty"`
+
+	// Name of the cluster that absorbs replicas up to its capacity or
+	// maximum replicas when the distribution is Spillover. Replicas
+	// spilled over to other clusters are removed from them once the
+	// primary cluster regains capacity, regardless of rebalance.
+	// +optional
+	PrimaryCluster string `json:"primaryCluster,omitempty"`
+
+	// Number of seconds for which the capacity of a cluster, as estimated
+	// from pods that could not be scheduled there, continues to limit the
+	// replicas assigned to that cluster after it stops reporting
+	// unschedulable pods. Prevents replicas from repeatedly moving back to
+	// a capacity-starved cluster. Defaults to 300 (5 minutes). A value
+	// of 0 disables stabilization.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StabilizationWindowSeconds *int32 `json:"stabilizationWindowSeconds,omitempty"`
+
+	// Temporarily reduces the weights of clusters in which pods of the
+	// target workload fail to be scheduled or are crash looping. Weights
+	// are not tuned if omitted.
+	// +optional
+	WeightAutoTuning *WeightAutoTuning `json:"weightAutoTuning,omitempty"`
+
 	// A mapping between cluster names and preferences regarding a local workload object (dep, rs, .. ) in
 	// these clusters.
 	// "*" (if provided) applies to all clusters if an explicit mapping is not provided.
 	// If omitted, clusters without explicit preferences should not have any replicas scheduled.
 	// +optional
 	Clusters map[string]ClusterPreferences `json:"clusters,omitempty"`
+
+	// Minimum number of replicas that should be assigned to each cluster
+	// with preferences. Takes precedence over a smaller minReplicas in the
+	// preferences for a cluster. 0 by default.
+	// +optional
+	MinReplicasPerCluster int64 `json:"minReplicasPerCluster,omitempty"`
+
+	// Maximum number of replicas that should be assigned to each cluster
+	// with preferences. Takes precedence over a larger maxReplicas in the
+	// preferences for a cluster. Unbounded if no value provided (default).
+	// +optional
+	MaxReplicasPerCluster *int64 `json:"maxReplicasPerCluster,omitempty"`
+
+	// Federated workloads in the same namespace that the target workload
+	// must not share a cluster with. Clusters in which any of the named
+	// workloads are scheduled will not have replicas scheduled.
+	// +optional
+	ClusterAntiAffinity []ClusterAntiAffinityTerm `json:"clusterAntiAffinity,omitempty"`
+
+	// Time windows during which the cluster preferences of the window
+	// replace those of the clusters field, e.g. to shift replicas to
+	// the regions where it is daytime. If several windows are active
+	// at the same time, the first one listed applies. Replicas are
+	// only moved from running clusters if rebalance is true.
+	// +optional
+	Schedules []ScheduleWindow `json:"schedules,omitempty"`
+
+	// The level (Provider, Region or Datacenter) of the fault domains
+	// of clusters that replicas fail over across. While a cluster is
+	// not ready, the clusters sharing its fault domain at this level
+	// keep the replicas already propagated to them but are not
+	// scheduled any more, so that the replicas of the failed cluster
+	// are scheduled to other fault domains. The fault domain of a
+	// cluster is taken from the faultDomain of its KubeFedCluster.
+	// Replicas fail over to any ready cluster if omitted.
+	// +optional
+	FailoverDomain FailoverDomain `json:"failoverDomain,omitempty"`
+}
+
+// ScheduleWindow is a recurring time window during which different
+// cluster preferences apply.
+type ScheduleWindow struct {
+	// Days of the week on which the window starts, as the first three
+	// letters of their English names (e.g. Mon). Every day if omitted.
+	// +optional
+	Days []string `json:"days,omitempty"`
+
+	// Time of day at which the window starts, in HH:MM format.
+	Start string `json:"start"`
+
+	// Time of day at which the window ends, in HH:MM format. A window
+	// whose end is not after its start ends on the following day.
+	End string `json:"end"`
+
+	// IANA name of the time zone of the start and end of the window
+	// (e.g. Europe/Berlin). Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Number of minutes over which the weights and minimum replicas
+	// shift gradually to the preferences of the window after it
+	// starts, and back to the preferences of the clusters field before
+	// it ends. 0 by default.
+	// +optional
+	RampMinutes int32 `json:"rampMinutes,omitempty"`
+
+	// A mapping between cluster names and the preferences that apply
+	// while the window is active. "*" (if provided) applies to all
+	// clusters if an explicit mapping is not provided.
+	Clusters map[string]ClusterPreferences `json:"clusters"`
+}
+
+// WeightAutoTuning configures the reduction of the weights of clusters
+// in which the target workload is failing.
+type WeightAutoTuning struct {
+	// Maximum percentage by which the weight of a cluster may be
+	// reduced. The weight of a cluster is reduced by the percentage of
+	// the pods of the target workload in that cluster that are
+	// unschedulable or crash looping, up to this limit. Defaults to 50.
+	// +optional
+	MaxReductionPercentage *int32 `json:"maxReductionPercentage,omitempty"`
+
+	// Number of seconds for which the weight of a cluster remains
+	// reduced after it stops reporting failing pods. Defaults to 600
+	// (10 minutes).
+	// +optional
+	RecoverySeconds *int32 `json:"recoverySeconds,omitempty"`
+}
+
+// ClusterAntiAffinityTerm identifies a federated workload that should
+// not be scheduled to the same cluster as the target of an RSP.
+type ClusterAntiAffinityTerm struct {
+	// Kind of the federated workload (FederatedDeployment or FederatedReplicaSet).
+	TargetKind string `json:"targetKind"`
+
+	// Name of the federated workload in the namespace of the RSP.
+	Name string `json:"name"`
 }
 
 // Preferences regarding number of replicas assigned to a cluster workload object (dep, rs, ..) within
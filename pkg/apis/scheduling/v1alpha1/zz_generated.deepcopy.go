@@ -24,6 +24,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAntiAffinityTerm) DeepCopyInto(out *ClusterAntiAffinityTerm) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAntiAffinityTerm.
+func (in *ClusterAntiAffinityTerm) DeepCopy() *ClusterAntiAffinityTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAntiAffinityTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterPreferences) DeepCopyInto(out *ClusterPreferences) {
 	*out = *in
@@ -106,6 +121,16 @@ func (in *ReplicaSchedulingPreferenceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicaSchedulingPreferenceSpec) DeepCopyInto(out *ReplicaSchedulingPreferenceSpec) {
 	*out = *in
+	if in.StabilizationWindowSeconds != nil {
+		in, out := &in.StabilizationWindowSeconds, &out.StabilizationWindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WeightAutoTuning != nil {
+		in, out := &in.WeightAutoTuning, &out.WeightAutoTuning
+		*out = new(WeightAutoTuning)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make(map[string]ClusterPreferences, len(*in))
@@ -113,6 +138,23 @@ func (in *ReplicaSchedulingPreferenceSpec) DeepCopyInto(out *ReplicaSchedulingPr
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.MaxReplicasPerCluster != nil {
+		in, out := &in.MaxReplicasPerCluster, &out.MaxReplicasPerCluster
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ClusterAntiAffinity != nil {
+		in, out := &in.ClusterAntiAffinity, &out.ClusterAntiAffinity
+		*out = make([]ClusterAntiAffinityTerm, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ScheduleWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaSchedulingPreferenceSpec.
@@ -125,6 +167,33 @@ func (in *ReplicaSchedulingPreferenceSpec) DeepCopy() *ReplicaSchedulingPreferen
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make(map[string]ClusterPreferences, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicaSchedulingPreferenceStatus) DeepCopyInto(out *ReplicaSchedulingPreferenceStatus) {
 	*out = *in
@@ -139,3 +208,28 @@ func (in *ReplicaSchedulingPreferenceStatus) DeepCopy() *ReplicaSchedulingPrefer
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightAutoTuning) DeepCopyInto(out *WeightAutoTuning) {
+	*out = *in
+	if in.MaxReductionPercentage != nil {
+		in, out := &in.MaxReductionPercentage, &out.MaxReductionPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RecoverySeconds != nil {
+		in, out := &in.RecoverySeconds, &out.RecoverySeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightAutoTuning.
+func (in *WeightAutoTuning) DeepCopy() *WeightAutoTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightAutoTuning)
+	in.DeepCopyInto(out)
+	return out
+}
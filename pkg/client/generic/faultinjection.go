@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// FaultFunc is called before each operation of a fault injecting
+// client. The operation is not performed if an error is returned.
+type FaultFunc func(ctx context.Context, operation fedv1b1.FaultOperation) error
+
+type faultInjectingClient struct {
+	client Client
+	fault  FaultFunc
+}
+
+// NewFaultInjectingClient returns a client that calls the given fault
+// function before delegating each operation to the given client.
+func NewFaultInjectingClient(client Client, fault FaultFunc) Client {
+	return &faultInjectingClient{client: client, fault: fault}
+}
+
+func (c *faultInjectingClient) Create(ctx context.Context, obj runtime.Object) error {
+	if err := c.fault(ctx, fedv1b1.FaultOperationCreate); err != nil {
+		return err
+	}
+	return c.client.Create(ctx, obj)
+}
+
+func (c *faultInjectingClient) Get(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	if err := c.fault(ctx, fedv1b1.FaultOperationGet); err != nil {
+		return err
+	}
+	return c.client.Get(ctx, obj, namespace, name)
+}
+
+func (c *faultInjectingClient) Update(ctx context.Context, obj runtime.Object) error {
+	if err := c.fault(ctx, fedv1b1.FaultOperationUpdate); err != nil {
+		return err
+	}
+	return c.client.Update(ctx, obj)
+}
+
+func (c *faultInjectingClient) Delete(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	if err := c.fault(ctx, fedv1b1.FaultOperationDelete); err != nil {
+		return err
+	}
+	return c.client.Delete(ctx, obj, namespace, name)
+}
+
+func (c *faultInjectingClient) List(ctx context.Context, obj runtime.Object, namespace string, opts ...client.ListOption) error {
+	if err := c.fault(ctx, fedv1b1.FaultOperationList); err != nil {
+		return err
+	}
+	return c.client.List(ctx, obj, namespace, opts...)
+}
+
+func (c *faultInjectingClient) UpdateStatus(ctx context.Context, obj runtime.Object) error {
+	if err := c.fault(ctx, fedv1b1.FaultOperationUpdateStatus); err != nil {
+		return err
+	}
+	return c.client.UpdateStatus(ctx, obj)
+}
+
+// Patch is subject to the faults injected for updates.
+func (c *faultInjectingClient) Patch(ctx context.Context, obj runtime.Object, patchType types.PatchType, data []byte) error {
+	if err := c.fault(ctx, fedv1b1.FaultOperationUpdate); err != nil {
+		return err
+	}
+	return c.client.Patch(ctx, obj, patchType, data)
+}
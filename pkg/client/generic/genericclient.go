@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -34,6 +35,7 @@ type Client interface {
 	Delete(ctx context.Context, obj runtime.Object, namespace, name string) error
 	List(ctx context.Context, obj runtime.Object, namespace string, opts ...client.ListOption) error
 	UpdateStatus(ctx context.Context, obj runtime.Object) error
+	Patch(ctx context.Context, obj runtime.Object, patchType types.PatchType, data []byte) error
 }
 
 type genericClient struct {
@@ -45,6 +47,14 @@ func New(config *rest.Config) (Client, error) {
 	return &genericClient{client}, err
 }
 
+// NewWithMapper returns a client that maps resources with the given
+// mapper rather than with a mapper that discovers the APIs of the
+// server.
+func NewWithMapper(config *rest.Config, mapper meta.RESTMapper) (Client, error) {
+	client, err := client.New(config, client.Options{Scheme: scheme.Scheme, Mapper: mapper})
+	return &genericClient{client}, err
+}
+
 func NewForConfigOrDie(config *rest.Config) Client {
 	client, err := New(config)
 	if err != nil {
@@ -89,3 +99,23 @@ func (c *genericClient) List(ctx context.Context, obj runtime.Object, namespace
 func (c *genericClient) UpdateStatus(ctx context.Context, obj runtime.Object) error {
 	return c.client.Status().Update(ctx, obj)
 }
+
+// Patch applies the given patch to the resource identified by the
+// given object and updates the object with the result.
+func (c *genericClient) Patch(ctx context.Context, obj runtime.Object, patchType types.PatchType, data []byte) error {
+	return c.client.Patch(ctx, obj, rawPatch{patchType: patchType, data: data})
+}
+
+// rawPatch is a patch whose data has already been computed.
+type rawPatch struct {
+	patchType types.PatchType
+	data      []byte
+}
+
+func (p rawPatch) Type() types.PatchType {
+	return p.patchType
+}
+
+func (p rawPatch) Data(obj runtime.Object) ([]byte, error) {
+	return p.data, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WaitFunc blocks until an operation of a rate-limited client may
+// proceed, or returns an error if the context is done first.
+type WaitFunc func(ctx context.Context) error
+
+type rateLimitedClient struct {
+	client Client
+	wait   WaitFunc
+}
+
+// NewRateLimitedClient returns a client that calls the given wait
+// function before each create, update and patch delegated to the given
+// client. Reads, deletes and status updates are not limited.
+func NewRateLimitedClient(client Client, wait WaitFunc) Client {
+	return &rateLimitedClient{client: client, wait: wait}
+}
+
+func (c *rateLimitedClient) Create(ctx context.Context, obj runtime.Object) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.client.Create(ctx, obj)
+}
+
+func (c *rateLimitedClient) Get(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	return c.client.Get(ctx, obj, namespace, name)
+}
+
+func (c *rateLimitedClient) Update(ctx context.Context, obj runtime.Object) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.client.Update(ctx, obj)
+}
+
+func (c *rateLimitedClient) Delete(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	return c.client.Delete(ctx, obj, namespace, name)
+}
+
+func (c *rateLimitedClient) List(ctx context.Context, obj runtime.Object, namespace string, opts ...client.ListOption) error {
+	return c.client.List(ctx, obj, namespace, opts...)
+}
+
+func (c *rateLimitedClient) UpdateStatus(ctx context.Context, obj runtime.Object) error {
+	return c.client.UpdateStatus(ctx, obj)
+}
+
+func (c *rateLimitedClient) Patch(ctx context.Context, obj runtime.Object, patchType types.PatchType, data []byte) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.client.Patch(ctx, obj, patchType, data)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TimeoutFunc returns the duration after which an operation of a
+// timeout client is cancelled. Operations are not cancelled if it
+// returns 0.
+type TimeoutFunc func() time.Duration
+
+// LatencyFunc is called with the duration of each operation of a
+// timeout client.
+type LatencyFunc func(latency time.Duration)
+
+type timeoutClient struct {
+	client  Client
+	timeout TimeoutFunc
+	latency LatencyFunc
+}
+
+// NewTimeoutClient returns a client that cancels each operation
+// delegated to the given client that does not complete within the
+// duration returned by the given timeout function. The duration of
+// every operation is reported to the given latency function, if not
+// nil.
+func NewTimeoutClient(client Client, timeout TimeoutFunc, latency LatencyFunc) Client {
+	return &timeoutClient{client: client, timeout: timeout, latency: latency}
+}
+
+func (c *timeoutClient) Create(ctx context.Context, obj runtime.Object) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Create(ctx, obj)
+	})
+}
+
+func (c *timeoutClient) Get(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Get(ctx, obj, namespace, name)
+	})
+}
+
+func (c *timeoutClient) Update(ctx context.Context, obj runtime.Object) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Update(ctx, obj)
+	})
+}
+
+func (c *timeoutClient) Delete(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Delete(ctx, obj, namespace, name)
+	})
+}
+
+func (c *timeoutClient) List(ctx context.Context, obj runtime.Object, namespace string, opts ...client.ListOption) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.List(ctx, obj, namespace, opts...)
+	})
+}
+
+func (c *timeoutClient) UpdateStatus(ctx context.Context, obj runtime.Object) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.UpdateStatus(ctx, obj)
+	})
+}
+
+func (c *timeoutClient) Patch(ctx context.Context, obj runtime.Object, patchType types.PatchType, data []byte) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Patch(ctx, obj, patchType, data)
+	})
+}
+
+func (c *timeoutClient) do(ctx context.Context, operation func(context.Context) error) error {
+	if timeout := c.timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := operation(ctx)
+	if c.latency != nil {
+		c.latency(time.Since(start))
+	}
+	return err
+}
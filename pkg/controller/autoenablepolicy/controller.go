@@ -0,0 +1,292 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoenablepolicy
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextv1b1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// PolicyLabel identifies the AutoEnablePolicy a
+	// FederatedTypeConfig was generated for.
+	PolicyLabel = "kubefed.io/auto-enable-policy"
+
+	// resyncPeriod is how often every policy is reconciled to retry
+	// the CRDs whose propagation could not be enabled.
+	resyncPeriod = 5 * time.Minute
+)
+
+// Controller enables propagation of the CRDs selected by an
+// AutoEnablePolicy by generating a federated type CRD and a
+// FederatedTypeConfig for each of them, as `kubefedctl enable` does.
+// Propagation is only ever enabled: removing a policy or a CRD leaves
+// the types it enabled in place until they are disabled with
+// `kubefedctl disable`.
+type Controller struct {
+	client genericclient.Client
+
+	kubeConfig *restclient.Config
+
+	// fedNamespace is the namespace containing the policies and the
+	// FederatedTypeConfigs.
+	fedNamespace string
+
+	// Store for the policies
+	store cache.Store
+	// Informer for the policies
+	controller cache.Controller
+
+	// Store for the CRDs of the host cluster
+	crdStore cache.Store
+	// Informer for the CRDs of the host cluster
+	crdController cache.Controller
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller for enabling the CRDs
+// selected by AutoEnablePolicies.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting auto-enable policy controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to enable the CRDs selected
+// by AutoEnablePolicies.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "AutoEnablePolicies"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	genericclient, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	crdClient, err := apiextv1b1client.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:       genericclient,
+		kubeConfig:   kubeConfig,
+		fedNamespace: config.KubeFedNamespace,
+	}
+
+	c.worker = util.NewReconcileWorker("autoenablepolicycontroller", c.reconcile, util.WorkerTiming{})
+
+	c.store, c.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.AutoEnablePolicy{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any CRD may be selected by any policy, so every policy is
+	// reconciled when a CRD changes.
+	c.crdStore, c.crdController = cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
+				return crdClient.CustomResourceDefinitions().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return crdClient.CustomResourceDefinitions().Watch(options)
+			},
+		},
+		&apiextv1b1.CustomResourceDefinition{},
+		util.NoResyncPeriod,
+		util.NewTriggerOnAllChanges(func(pkgruntime.Object) {
+			c.enqueueAll()
+		}),
+	)
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+	go c.crdController.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced, c.crdController.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for caches to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+
+	go wait.Until(c.enqueueAll, resyncPeriod, stopChan)
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.store.List() {
+		c.worker.EnqueueObject(obj.(pkgruntime.Object))
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("autoenablepolicycontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile auto-enable policy for %q", key)
+
+	cachedObj, exist, err := c.store.GetByKey(key)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query auto-enable policy store for %q", key))
+		return util.StatusError
+	}
+	if !exist {
+		return util.StatusAllOK
+	}
+	policy := cachedObj.(*fedv1b1.AutoEnablePolicy)
+	if policy.DeletionTimestamp != nil {
+		return util.StatusAllOK
+	}
+
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	if err := c.client.List(context.TODO(), typeConfigs, c.fedNamespace); err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to list FederatedTypeConfigs"))
+		return util.StatusError
+	}
+
+	// The CRDs of types that are already enabled and of federated
+	// types, including those of federated types that are being
+	// generated by any policy, are never enabled.
+	excludedNames := sets.NewString()
+	for i := range typeConfigs.Items {
+		typeConfig := &typeConfigs.Items[i]
+		excludedNames.Insert(typeconfig.GroupQualifiedName(typeConfig.GetTargetType()))
+		excludedNames.Insert(typeconfig.GroupQualifiedName(typeConfig.GetFederatedType()))
+	}
+	excludedGroups := sets.NewString(options.DefaultFederatedGroup)
+	for _, obj := range c.store.List() {
+		if federatedGroup := obj.(*fedv1b1.AutoEnablePolicy).Spec.FederatedGroup; federatedGroup != "" {
+			excludedGroups.Insert(federatedGroup)
+		}
+	}
+
+	crds := []*apiextv1b1.CustomResourceDefinition{}
+	for _, obj := range c.crdStore.List() {
+		crds = append(crds, obj.(*apiextv1b1.CustomResourceDefinition))
+	}
+	selected, err := selectCRDs(policy, crds, excludedNames, excludedGroups)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to select the CRDs of auto-enable policy %q", key))
+		return util.StatusError
+	}
+
+	result := util.StatusAllOK
+	for _, crd := range selected {
+		if err := c.enableCRD(policy, crd); err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to enable propagation of %q for auto-enable policy %q", crd.Name, key))
+			result = util.StatusError
+		}
+	}
+	return result
+}
+
+// enableCRD generates and creates the federated type CRD and the
+// FederatedTypeConfig for the given CRD.
+func (c *Controller) enableCRD(policy *fedv1b1.AutoEnablePolicy, crd *apiextv1b1.CustomResourceDefinition) error {
+	directive := enable.NewEnableTypeDirective()
+	directive.Name = crd.Name
+	if policy.Spec.FederatedGroup != "" {
+		directive.Spec.FederatedGroup = policy.Spec.FederatedGroup
+	}
+
+	// The API resource of a newly established CRD may not be
+	// discoverable yet, in which case enabling is retried.
+	resources, err := enable.GetResources(c.kubeConfig, directive)
+	if err != nil {
+		return err
+	}
+	typeConfig := resources.TypeConfig.(*fedv1b1.FederatedTypeConfig)
+	typeConfig.Labels = map[string]string{PolicyLabel: policy.Name}
+
+	klog.Infof("Enabling propagation of %q for auto-enable policy %q", crd.Name, policy.Name)
+	return enable.CreateResources(nil, c.kubeConfig, resources, c.fedNamespace, false)
+}
+
+// selectCRDs returns the established CRDs selected by the given
+// policy, ordered by name, excluding the CRDs with the given names or
+// of the given API groups.
+func selectCRDs(policy *fedv1b1.AutoEnablePolicy, crds []*apiextv1b1.CustomResourceDefinition, excludedNames, excludedGroups sets.String) ([]*apiextv1b1.CustomResourceDefinition, error) {
+	selected := []*apiextv1b1.CustomResourceDefinition{}
+	for _, crd := range crds {
+		if crd.DeletionTimestamp != nil || !isEstablished(crd) {
+			continue
+		}
+		if excludedNames.Has(crd.Name) || excludedGroups.Has(crd.Spec.Group) {
+			continue
+		}
+		ok, err := util.AutoEnablePolicySelects(policy, crd.Spec.Group, crd.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			selected = append(selected, crd)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+	return selected, nil
+}
+
+// isEstablished returns whether the given CRD is served by the API
+// server.
+func isEstablished(crd *apiextv1b1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextv1b1.Established {
+			return condition.Status == apiextv1b1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoenablepolicy
+
+import (
+	"reflect"
+	"testing"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestSelectCRDs(t *testing.T) {
+	policy := &fedv1b1.AutoEnablePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "mycompany"},
+		Spec: fedv1b1.AutoEnablePolicySpec{
+			GroupSuffixes:  []string{"mycompany.io"},
+			FederatedGroup: "types.mycompany.io",
+		},
+	}
+	crds := []*apiextv1b1.CustomResourceDefinition{
+		newCRD("widgets.apps.mycompany.io", "apps.mycompany.io", true),
+		newCRD("caches.mycompany.io", "mycompany.io", true),
+		newCRD("pending.mycompany.io", "mycompany.io", false),
+		newCRD("databases.mycompany.io", "mycompany.io", true),
+		newCRD("federatedcaches.types.mycompany.io", "types.mycompany.io", true),
+		newCRD("certificates.cert-manager.io", "cert-manager.io", true),
+		newCRD("federatedwidgets.types.kubefed.io", "types.kubefed.io", true),
+	}
+	excludedNames := sets.NewString("databases.mycompany.io")
+	excludedGroups := sets.NewString("types.kubefed.io", "types.mycompany.io")
+
+	selected, err := selectCRDs(policy, crds, excludedNames, excludedGroups)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, crd := range selected {
+		names = append(names, crd.Name)
+	}
+	expected := []string{"caches.mycompany.io", "widgets.apps.mycompany.io"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v to be selected, got %v", expected, names)
+	}
+}
+
+func newCRD(name, group string, established bool) *apiextv1b1.CustomResourceDefinition {
+	status := apiextv1b1.ConditionFalse
+	if established {
+		status = apiextv1b1.ConditionTrue
+	}
+	return &apiextv1b1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       apiextv1b1.CustomResourceDefinitionSpec{Group: group},
+		Status: apiextv1b1.CustomResourceDefinitionStatus{
+			Conditions: []apiextv1b1.CustomResourceDefinitionCondition{
+				{Type: apiextv1b1.Established, Status: status},
+			},
+		},
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backfill
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclient "k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	genscheme "sigs.k8s.io/kubefed/pkg/client/generic/scheme"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// progressPeriod is how often the progress of the backfill of a
+	// cluster is refreshed.
+	progressPeriod = 15 * time.Second
+
+	// startWindow is how soon after its creation a cluster must become
+	// ready for it to be backfilled. Clusters that were joined before
+	// the feature was enabled are not backfilled.
+	startWindow = 10 * time.Minute
+
+	// phaseSettleDelay is the minimum duration of a phase, allowing
+	// the sync controllers to record the status of the resources of
+	// the phase for the cluster before the phase is considered
+	// complete.
+	phaseSettleDelay = 30 * time.Second
+
+	// phaseTimeout is the maximum duration of a phase. Resources that
+	// fail to propagate do not block later phases indefinitely.
+	phaseTimeout = 5 * time.Minute
+)
+
+// Controller orders the propagation of existing federated resources to
+// newly joined clusters and records its progress in the status of the
+// KubeFedCluster.
+type Controller struct {
+	client genericclient.Client
+
+	kubeConfig *restclient.Config
+
+	// fedNamespace is the namespace containing the KubeFedClusters
+	// and FederatedTypeConfigs.
+	fedNamespace string
+
+	// targetNamespace is the namespace containing federated
+	// resources, or all namespaces.
+	targetNamespace string
+
+	// Store for the KubeFedCluster objects
+	store cache.Store
+	// Informer for the KubeFedCluster objects
+	controller cache.Controller
+
+	eventRecorder record.EventRecorder
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller for backfilling newly joined clusters.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting cluster backfill controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to backfill newly joined clusters.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "ClusterBackfill"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	client, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:          client,
+		kubeConfig:      kubeConfig,
+		fedNamespace:    config.KubeFedNamespace,
+		targetNamespace: config.TargetNamespace,
+	}
+
+	kubeClient := kubeclient.NewForConfigOrDie(kubeConfig)
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	c.eventRecorder = broadcaster.NewRecorder(genscheme.Scheme, corev1.EventSource{Component: "backfill-controller"})
+
+	c.worker = util.NewReconcileWorker("backfillcontroller", c.reconcile, util.WorkerTiming{})
+
+	c.store, c.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.KubeFedCluster{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+
+	// The propagation of federated resources is not watched, so
+	// clusters are periodically reconciled to refresh the progress of
+	// their backfill.
+	go wait.Until(c.enqueueAll, progressPeriod, stopChan)
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.store.List() {
+		c.worker.EnqueueObject(obj.(runtime.Object))
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("backfillcontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile backfill for cluster %q", key)
+
+	cachedObj, exist, err := c.store.GetByKey(key)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query KubeFedCluster store for %q", key))
+		return util.StatusError
+	}
+	if !exist {
+		return util.StatusAllOK
+	}
+	cluster := cachedObj.(*fedv1b1.KubeFedCluster).DeepCopy()
+	backfill := cluster.Status.Backfill
+
+	if !util.IsClusterReady(&cluster.Status) {
+		return util.StatusAllOK
+	}
+	now := time.Now()
+	if backfill == nil {
+		if now.Sub(cluster.CreationTimestamp.Time) > startWindow {
+			return util.StatusAllOK
+		}
+		startTime := metav1.NewTime(now)
+		cluster.Status.Backfill = &fedv1b1.BackfillProgress{
+			Phase:          util.BackfillPhases[0],
+			StartTime:      startTime,
+			PhaseStartTime: startTime,
+		}
+		return c.updateStatus(cluster)
+	}
+	if backfill.CompletionTime != nil {
+		return util.StatusAllOK
+	}
+
+	progress, err := c.phaseProgress(cluster.Name)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to compute backfill progress of cluster %q", key))
+		return util.StatusError
+	}
+
+	updated, timedOutPhases := advanceBackfill(backfill, progress, now)
+	for _, phase := range timedOutPhases {
+		c.eventRecorder.Eventf(cluster, corev1.EventTypeWarning, "BackfillPhaseTimedOut",
+			"Resources of backfill phase %s were not all propagated within %v", phase, phaseTimeout)
+	}
+	if updated.CompletionTime != nil {
+		c.eventRecorder.Eventf(cluster, corev1.EventTypeNormal, "BackfillCompleted",
+			"Backfill completed in %v", updated.CompletionTime.Sub(updated.StartTime.Time).Round(time.Second))
+	}
+	if equality.Semantic.DeepEqual(backfill, updated) {
+		return util.StatusAllOK
+	}
+	cluster.Status.Backfill = updated
+	return c.updateStatus(cluster)
+}
+
+func (c *Controller) updateStatus(cluster *fedv1b1.KubeFedCluster) util.ReconciliationStatus {
+	err := c.client.UpdateStatus(context.TODO(), cluster)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to update backfill status of cluster %q", cluster.Name))
+		return util.StatusError
+	}
+	return util.StatusAllOK
+}
+
+// phaseProgress returns the number of federated resources of each
+// backfill phase whose status includes the named cluster and the
+// number that have been propagated to it.
+func (c *Controller) phaseProgress(clusterName string) (map[fedv1b1.BackfillPhase]*fedv1b1.BackfillPhaseProgress, error) {
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	err := c.client.List(context.TODO(), typeConfigs, c.fedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+
+	progress := make(map[fedv1b1.BackfillPhase]*fedv1b1.BackfillPhaseProgress)
+	for _, phase := range util.BackfillPhases {
+		progress[phase] = &fedv1b1.BackfillPhaseProgress{Phase: phase}
+	}
+	for i := range typeConfigs.Items {
+		typeConfig := &typeConfigs.Items[i]
+		if !typeConfig.GetPropagationEnabled() {
+			continue
+		}
+		fedType := typeConfig.GetFederatedType()
+		client, err := util.NewResourceClient(c.kubeConfig, &fedType)
+		if err != nil {
+			return nil, err
+		}
+		objList, err := client.Resources(c.targetNamespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list %s", fedType.Kind)
+		}
+		phaseProgress := progress[util.BackfillPhaseForType(typeConfig.GetTargetType())]
+		for j := range objList.Items {
+			resource := &status.GenericFederatedResource{}
+			if err := util.UnstructuredToInterface(&objList.Items[j], resource); err != nil {
+				return nil, err
+			}
+			if resource.Status == nil {
+				continue
+			}
+			for _, clusterStatus := range resource.Status.Clusters {
+				if clusterStatus.Name != clusterName {
+					continue
+				}
+				phaseProgress.Total++
+				if clusterStatus.Status == status.ClusterPropagationOK {
+					phaseProgress.Propagated++
+				}
+			}
+		}
+	}
+	return progress, nil
+}
+
+// advanceBackfill returns the given backfill updated with the given
+// progress, advanced past every phase that has completed or timed out,
+// and the phases that timed out.
+func advanceBackfill(backfill *fedv1b1.BackfillProgress, progress map[fedv1b1.BackfillPhase]*fedv1b1.BackfillPhaseProgress,
+	now time.Time) (*fedv1b1.BackfillProgress, []fedv1b1.BackfillPhase) {
+
+	updated := backfill.DeepCopy()
+	updated.Phases = nil
+	var total, propagated int32
+	for _, phase := range util.BackfillPhases {
+		phaseProgress := progress[phase]
+		if phaseProgress == nil {
+			phaseProgress = &fedv1b1.BackfillPhaseProgress{Phase: phase}
+		}
+		updated.Phases = append(updated.Phases, *phaseProgress)
+		total += phaseProgress.Total
+		propagated += phaseProgress.Propagated
+	}
+
+	var timedOut []fedv1b1.BackfillPhase
+	for i := util.BackfillPhaseIndex(updated.Phase); i < len(util.BackfillPhases); i++ {
+		phaseDuration := now.Sub(updated.PhaseStartTime.Time)
+		if phaseDuration < phaseSettleDelay {
+			break
+		}
+		phaseProgress := updated.Phases[i]
+		if phaseProgress.Propagated < phaseProgress.Total {
+			if phaseDuration < phaseTimeout {
+				break
+			}
+			timedOut = append(timedOut, updated.Phase)
+		}
+		if i+1 == len(util.BackfillPhases) {
+			completionTime := metav1.NewTime(now)
+			updated.Phase = fedv1b1.BackfillComplete
+			updated.CompletionTime = &completionTime
+			updated.EstimatedCompletionTime = nil
+			return updated, timedOut
+		}
+		// Resources of the next phase are created once the phase
+		// change is observed by the sync controllers, so the next
+		// phase must be given time to settle.
+		updated.Phase = util.BackfillPhases[i+1]
+		updated.PhaseStartTime = metav1.NewTime(now)
+	}
+
+	updated.EstimatedCompletionTime = nil
+	if propagated > 0 && propagated < total {
+		elapsed := now.Sub(updated.StartTime.Time)
+		estimate := metav1.NewTime(updated.StartTime.Add(elapsed * time.Duration(total) / time.Duration(propagated)))
+		updated.EstimatedCompletionTime = &estimate
+	}
+	return updated, timedOut
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backfill
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestAdvanceBackfill(t *testing.T) {
+	start := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	newBackfill := func(phase fedv1b1.BackfillPhase, phaseStart time.Time) *fedv1b1.BackfillProgress {
+		return &fedv1b1.BackfillProgress{
+			Phase:          phase,
+			StartTime:      metav1.NewTime(start),
+			PhaseStartTime: metav1.NewTime(phaseStart),
+		}
+	}
+	progress := func(namespaces, workloads [2]int32) map[fedv1b1.BackfillPhase]*fedv1b1.BackfillPhaseProgress {
+		return map[fedv1b1.BackfillPhase]*fedv1b1.BackfillPhaseProgress{
+			fedv1b1.BackfillNamespaces: {Phase: fedv1b1.BackfillNamespaces, Total: namespaces[0], Propagated: namespaces[1]},
+			fedv1b1.BackfillWorkloads:  {Phase: fedv1b1.BackfillWorkloads, Total: workloads[0], Propagated: workloads[1]},
+		}
+	}
+
+	testCases := map[string]struct {
+		backfill         *fedv1b1.BackfillProgress
+		progress         map[fedv1b1.BackfillPhase]*fedv1b1.BackfillPhaseProgress
+		now              time.Time
+		expectedPhase    fedv1b1.BackfillPhase
+		expectedTimedOut int
+		expectComplete   bool
+	}{
+		"Phase is not advanced before it settles": {
+			backfill:      newBackfill(fedv1b1.BackfillNamespaces, start),
+			progress:      progress([2]int32{2, 2}, [2]int32{4, 0}),
+			now:           start.Add(10 * time.Second),
+			expectedPhase: fedv1b1.BackfillNamespaces,
+		},
+		"Completed phase is advanced": {
+			backfill:      newBackfill(fedv1b1.BackfillNamespaces, start),
+			progress:      progress([2]int32{2, 2}, [2]int32{4, 0}),
+			now:           start.Add(time.Minute),
+			expectedPhase: fedv1b1.BackfillRBAC,
+		},
+		"Incomplete phase is not advanced": {
+			backfill:      newBackfill(fedv1b1.BackfillNamespaces, start),
+			progress:      progress([2]int32{2, 1}, [2]int32{4, 0}),
+			now:           start.Add(time.Minute),
+			expectedPhase: fedv1b1.BackfillNamespaces,
+		},
+		"Incomplete phase is advanced after timing out": {
+			backfill:         newBackfill(fedv1b1.BackfillNamespaces, start),
+			progress:         progress([2]int32{2, 1}, [2]int32{4, 0}),
+			now:              start.Add(10 * time.Minute),
+			expectedPhase:    fedv1b1.BackfillRBAC,
+			expectedTimedOut: 1,
+		},
+		"Backfill completes after the last phase": {
+			backfill:       newBackfill(fedv1b1.BackfillWorkloads, start.Add(time.Minute)),
+			progress:       progress([2]int32{2, 2}, [2]int32{4, 4}),
+			now:            start.Add(2 * time.Minute),
+			expectedPhase:  fedv1b1.BackfillComplete,
+			expectComplete: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			updated, timedOut := advanceBackfill(tc.backfill, tc.progress, tc.now)
+			if updated.Phase != tc.expectedPhase {
+				t.Errorf("Expected phase %q, got %q", tc.expectedPhase, updated.Phase)
+			}
+			if len(timedOut) != tc.expectedTimedOut {
+				t.Errorf("Expected %d timed out phases, got %v", tc.expectedTimedOut, timedOut)
+			}
+			if complete := updated.CompletionTime != nil; complete != tc.expectComplete {
+				t.Errorf("Expected completion %v, got %v", tc.expectComplete, complete)
+			}
+			if len(updated.Phases) != 5 {
+				t.Errorf("Expected progress for 5 phases, got %d", len(updated.Phases))
+			}
+		})
+	}
+
+	// Half of the resources were propagated in 2 minutes, so the
+	// backfill is estimated to complete in another 2 minutes.
+	updated, _ := advanceBackfill(newBackfill(fedv1b1.BackfillWorkloads, start), progress([2]int32{2, 2}, [2]int32{2, 0}), start.Add(2*time.Minute))
+	expected := start.Add(4 * time.Minute)
+	if updated.EstimatedCompletionTime == nil || !updated.EstimatedCompletionTime.Time.Equal(expected) {
+		t.Errorf("Expected estimated completion at %v, got %v", expected, updated.EstimatedCompletionTime)
+	}
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blueprint
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// InstanceLabel identifies the BlueprintInstance a federated
+	// resource was created for.
+	InstanceLabel = "kubefed.io/blueprint-instance"
+
+	// resyncPeriod is how often every instance is reconciled to
+	// revert changes to its resources.
+	resyncPeriod = time.Minute
+)
+
+// Controller creates the federated resources of the Blueprint of each
+// BlueprintInstance in the namespace of the instance, and removes the
+// resources that the blueprint no longer defines.
+type Controller struct {
+	client genericclient.Client
+
+	// fedNamespace is the namespace containing the blueprints and the
+	// FederatedTypeConfigs.
+	fedNamespace string
+
+	// Store for the BlueprintInstances
+	instanceStore cache.Store
+	// Informer for the BlueprintInstances
+	instanceController cache.Controller
+
+	// Store for the Blueprints
+	blueprintStore cache.Store
+	// Informer for the Blueprints
+	blueprintController cache.Controller
+
+	// resourceClients holds the client for each federated type.
+	resourceClients *util.ResourceClientCache
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller for instantiating blueprints.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting blueprint controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to instantiate blueprints.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "Blueprints"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	genericclient, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:          genericclient,
+		fedNamespace:    config.KubeFedNamespace,
+		resourceClients: util.NewResourceClientCache(kubeConfig),
+	}
+
+	c.worker = util.NewReconcileWorker("blueprintcontroller", c.reconcile, util.WorkerTiming{})
+
+	c.instanceStore, c.instanceController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.TargetNamespace,
+		&fedv1b1.BlueprintInstance{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.blueprintStore, c.blueprintController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.Blueprint{},
+		util.NoResyncPeriod,
+		c.enqueueInstances,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.instanceController.Run(stopChan)
+	go c.blueprintController.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.instanceController.HasSynced, c.blueprintController.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+
+	// Federated resources are not watched, so instances are
+	// periodically reconciled.
+	go wait.Until(c.enqueueAll, resyncPeriod, stopChan)
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.instanceStore.List() {
+		c.worker.EnqueueObject(obj.(runtime.Object))
+	}
+}
+
+// enqueueInstances enqueues the instances of the given blueprint.
+func (c *Controller) enqueueInstances(obj runtime.Object) {
+	blueprint, ok := obj.(*fedv1b1.Blueprint)
+	if !ok {
+		return
+	}
+	for _, cachedObj := range c.instanceStore.List() {
+		instance := cachedObj.(*fedv1b1.BlueprintInstance)
+		if instance.Spec.BlueprintRef.Name == blueprint.Name {
+			c.worker.EnqueueObject(instance)
+		}
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("blueprintcontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile BlueprintInstance for %q", key)
+
+	cachedObj, exist, err := c.instanceStore.GetByKey(key)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query BlueprintInstance store for %q", key))
+		return util.StatusError
+	}
+	if !exist {
+		// The resources of the instance are garbage collected.
+		return util.StatusAllOK
+	}
+	instance := cachedObj.(*fedv1b1.BlueprintInstance).DeepCopy()
+	if instance.DeletionTimestamp != nil {
+		return util.StatusAllOK
+	}
+
+	resources, err := c.renderInstance(instance)
+	if err != nil {
+		// The resources of the instance are retained until the
+		// instance or its blueprint is corrected.
+		klog.V(2).Infof("Unable to render BlueprintInstance %q: %v", key, err)
+		return c.updateStatus(instance, nil, []string{err.Error()})
+	}
+
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	if err := c.client.List(context.TODO(), typeConfigs, c.fedNamespace); err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to list FederatedTypeConfigs"))
+		return util.StatusError
+	}
+	federatedTypes := namespacedFederatedTypes(typeConfigs.Items)
+
+	result := util.StatusAllOK
+	messages := []string{}
+	created := []fedv1b1.BlueprintInstanceResource{}
+	desired := make(map[string]map[string]bool)
+	for _, obj := range resources {
+		gvk := obj.GroupVersionKind()
+		federatedType, ok := federatedTypes[gvk.GroupKind()]
+		if !ok || federatedType.Version != gvk.Version {
+			messages = append(messages, fmt.Sprintf("%s %q is not of a namespaced federated type enabled for propagation", obj.GetKind(), obj.GetName()))
+			continue
+		}
+		if desired[federatedType.Kind] == nil {
+			desired[federatedType.Kind] = make(map[string]bool)
+		}
+		desired[federatedType.Kind][obj.GetName()] = true
+
+		client, err := c.resourceClients.Get(federatedType)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to create client for %s", federatedType.Kind))
+			result = util.StatusError
+			continue
+		}
+		owned, err := ensureResource(client, instance, obj)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to ensure %s %q of BlueprintInstance %q", obj.GetKind(), obj.GetName(), key))
+			messages = append(messages, fmt.Sprintf("%s %q could not be applied", obj.GetKind(), obj.GetName()))
+			result = util.StatusError
+			continue
+		}
+		if !owned {
+			messages = append(messages, fmt.Sprintf("%s %q already exists and was not created for the instance", obj.GetKind(), obj.GetName()))
+			continue
+		}
+		created = append(created, fedv1b1.BlueprintInstanceResource{Kind: obj.GetKind(), Name: obj.GetName()})
+	}
+
+	// Remove the resources of the instance that its blueprint no
+	// longer defines.
+	selector := labels.SelectorFromSet(labels.Set{InstanceLabel: instance.Name})
+	for _, federatedType := range federatedTypes {
+		client, err := c.resourceClients.Get(federatedType)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to create client for %s", federatedType.Kind))
+			result = util.StatusError
+			continue
+		}
+		list, err := client.Resources(instance.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to list %s of BlueprintInstance %q", federatedType.Kind, key))
+			result = util.StatusError
+			continue
+		}
+		for _, obj := range list.Items {
+			if desired[federatedType.Kind][obj.GetName()] || !isOwnedBy(&obj, instance) {
+				continue
+			}
+			klog.V(2).Infof("Deleting %s %s/%s of BlueprintInstance %q", federatedType.Kind, obj.GetNamespace(), obj.GetName(), key)
+			err := client.Resources(obj.GetNamespace()).Delete(obj.GetName(), &metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				utilruntime.HandleError(errors.Wrapf(err, "Failed to delete %s %s/%s", federatedType.Kind, obj.GetNamespace(), obj.GetName()))
+				result = util.StatusError
+			}
+		}
+	}
+
+	if c.updateStatus(instance, created, messages) == util.StatusError {
+		return util.StatusError
+	}
+	return result
+}
+
+// renderInstance returns the federated resources of the given instance
+// after validating its parameters against its blueprint.
+func (c *Controller) renderInstance(instance *fedv1b1.BlueprintInstance) ([]*unstructured.Unstructured, error) {
+	name := instance.Spec.BlueprintRef.Name
+	cachedObj, exist, err := c.blueprintStore.GetByKey(util.QualifiedName{Namespace: c.fedNamespace, Name: name}.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve Blueprint %q", name)
+	}
+	if !exist {
+		return nil, errors.Errorf("Blueprint %q not found", name)
+	}
+	blueprint := cachedObj.(*fedv1b1.Blueprint)
+	if errs := validation.ValidateBlueprintInstanceParameters(instance, blueprint); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+	return util.RenderBlueprint(blueprint, instance.Spec.Parameters)
+}
+
+// updateStatus records the resources created for the given instance
+// and the messages explaining why it is not ready, if any.
+func (c *Controller) updateStatus(instance *fedv1b1.BlueprintInstance, resources []fedv1b1.BlueprintInstanceResource, messages []string) util.ReconciliationStatus {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	instanceStatus := fedv1b1.BlueprintInstanceStatus{
+		ObservedGeneration: instance.Generation,
+		Ready:              len(messages) == 0,
+		Message:            strings.Join(messages, "; "),
+		Resources:          resources,
+	}
+	if len(messages) > 0 && resources == nil {
+		// Resources are retained while the instance cannot be
+		// rendered.
+		instanceStatus.Resources = instance.Status.Resources
+	}
+	if equality.Semantic.DeepEqual(instance.Status, instanceStatus) {
+		return util.StatusAllOK
+	}
+	instance.Status = instanceStatus
+	if err := c.client.UpdateStatus(context.TODO(), instance); err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to update status of BlueprintInstance %q", util.NewQualifiedName(instance)))
+		return util.StatusError
+	}
+	return util.StatusAllOK
+}
+
+// ensureResource creates or updates a federated resource of the given
+// instance, and returns whether the resource is owned by the instance.
+// A federated resource of the same name that was not created for the
+// instance is left alone.
+func ensureResource(client util.ResourceClient, instance *fedv1b1.BlueprintInstance, desired *unstructured.Unstructured) (bool, error) {
+	namespace := instance.Namespace
+	obj, err := client.Resources(namespace).Get(desired.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		resource := desiredResource(instance, desired)
+		klog.V(2).Infof("Creating %s %s/%s of BlueprintInstance %q", resource.GetKind(), namespace, resource.GetName(), instance.Name)
+		_, err = client.Resources(namespace).Create(resource, metav1.CreateOptions{})
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !isOwnedBy(obj, instance) {
+		return false, nil
+	}
+	resource := desiredResource(instance, desired)
+	if reflect.DeepEqual(obj.Object[util.SpecField], resource.Object[util.SpecField]) &&
+		reflect.DeepEqual(obj.GetLabels(), resource.GetLabels()) &&
+		reflect.DeepEqual(obj.GetAnnotations(), resource.GetAnnotations()) {
+		return true, nil
+	}
+	obj.Object[util.SpecField] = resource.Object[util.SpecField]
+	obj.SetLabels(resource.GetLabels())
+	obj.SetAnnotations(resource.GetAnnotations())
+	klog.V(2).Infof("Updating %s %s/%s of BlueprintInstance %q", obj.GetKind(), namespace, obj.GetName(), instance.Name)
+	_, err = client.Resources(namespace).Update(obj, metav1.UpdateOptions{})
+	return err == nil, err
+}
+
+// desiredResource returns the federated resource rendered for the
+// given instance, labeled with and owned by the instance.
+func desiredResource(instance *fedv1b1.BlueprintInstance, rendered *unstructured.Unstructured) *unstructured.Unstructured {
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if spec, ok := rendered.Object[util.SpecField]; ok {
+		resource.Object[util.SpecField] = runtime.DeepCopyJSONValue(spec)
+	}
+	resource.SetAPIVersion(rendered.GetAPIVersion())
+	resource.SetKind(rendered.GetKind())
+	resource.SetNamespace(instance.Namespace)
+	resource.SetName(rendered.GetName())
+	resourceLabels := rendered.GetLabels()
+	if resourceLabels == nil {
+		resourceLabels = make(map[string]string)
+	}
+	resourceLabels[InstanceLabel] = instance.Name
+	resource.SetLabels(resourceLabels)
+	resource.SetAnnotations(rendered.GetAnnotations())
+	resource.SetOwnerReferences([]metav1.OwnerReference{ownerReference(instance)})
+	return resource
+}
+
+func ownerReference(instance *fedv1b1.BlueprintInstance) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{
+		APIVersion: fedv1b1.SchemeGroupVersion.String(),
+		Kind:       "BlueprintInstance",
+		Name:       instance.Name,
+		UID:        instance.UID,
+		Controller: &controller,
+	}
+}
+
+// isOwnedBy returns whether the given federated resource was created
+// for the given instance.
+func isOwnedBy(obj *unstructured.Unstructured, instance *fedv1b1.BlueprintInstance) bool {
+	if obj.GetLabels()[InstanceLabel] != instance.Name {
+		return false
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == instance.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// namespacedFederatedTypes returns the federated types of the
+// namespaced types enabled for propagation, keyed by federated group
+// and kind.
+func namespacedFederatedTypes(typeConfigs []fedv1b1.FederatedTypeConfig) map[schema.GroupKind]metav1.APIResource {
+	federatedTypes := make(map[schema.GroupKind]metav1.APIResource)
+	for i := range typeConfigs {
+		typeConfig := &typeConfigs[i]
+		if !typeConfig.GetNamespaced() || !typeConfig.GetPropagationEnabled() || typeConfig.GetTargetType().Kind == util.NamespaceKind {
+			continue
+		}
+		federatedType := typeConfig.GetFederatedType()
+		federatedTypes[schema.GroupKind{Group: federatedType.Group, Kind: federatedType.Kind}] = federatedType
+	}
+	return federatedTypes
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blueprint
+
+import (
+	"reflect"
+	"testing"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func testInstance() *fedv1b1.BlueprintInstance {
+	return &fedv1b1.BlueprintInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "frontend",
+			Namespace: "team-a",
+			UID:       types.UID("instance-uid"),
+		},
+	}
+}
+
+func TestDesiredResource(t *testing.T) {
+	instance := testInstance()
+	rendered := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "types.kubefed.io/v1beta1",
+		"kind":       "FederatedConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "frontend",
+			"labels":          map[string]interface{}{"tier": "standard"},
+			"resourceVersion": "1",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{"data": map[string]interface{}{"a": "b"}},
+		},
+		"status": map[string]interface{}{"clusters": []interface{}{}},
+	}}
+
+	resource := desiredResource(instance, rendered)
+	if resource.GetNamespace() != instance.Namespace || resource.GetName() != "frontend" {
+		t.Fatalf("Expected resource %s/frontend, got %s/%s", instance.Namespace, resource.GetNamespace(), resource.GetName())
+	}
+	expectedLabels := map[string]string{"tier": "standard", InstanceLabel: instance.Name}
+	if !reflect.DeepEqual(resource.GetLabels(), expectedLabels) {
+		t.Errorf("Expected labels %v, got %v", expectedLabels, resource.GetLabels())
+	}
+	if resource.GetResourceVersion() != "" {
+		t.Errorf("Expected no resource version, got %q", resource.GetResourceVersion())
+	}
+	if _, ok := resource.Object["status"]; ok {
+		t.Errorf("Expected no status")
+	}
+	if !reflect.DeepEqual(resource.Object["spec"], rendered.Object["spec"]) {
+		t.Errorf("Expected spec %v, got %v", rendered.Object["spec"], resource.Object["spec"])
+	}
+	if !isOwnedBy(resource, instance) {
+		t.Errorf("Expected the resource to be owned by the instance")
+	}
+}
+
+func TestIsOwnedBy(t *testing.T) {
+	instance := testInstance()
+	testCases := map[string]struct {
+		labels   map[string]string
+		uid      types.UID
+		expected bool
+	}{
+		"labeled and owned": {
+			labels:   map[string]string{InstanceLabel: instance.Name},
+			uid:      instance.UID,
+			expected: true,
+		},
+		"not labeled": {
+			uid: instance.UID,
+		},
+		"labeled for another instance": {
+			labels: map[string]string{InstanceLabel: "backend"},
+			uid:    instance.UID,
+		},
+		"owned by a previous instance of the same name": {
+			labels: map[string]string{InstanceLabel: instance.Name},
+			uid:    types.UID("previous-uid"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetLabels(tc.labels)
+			obj.SetOwnerReferences([]metav1.OwnerReference{{UID: tc.uid}})
+			if owned := isOwnedBy(obj, instance); owned != tc.expected {
+				t.Errorf("Expected owned to be %v, got %v", tc.expected, owned)
+			}
+		})
+	}
+}
+
+func TestNamespacedFederatedTypes(t *testing.T) {
+	typeConfig := func(kind string, scope apiextv1b1.ResourceScope, propagation fedv1b1.PropagationMode) fedv1b1.FederatedTypeConfig {
+		return fedv1b1.FederatedTypeConfig{
+			Spec: fedv1b1.FederatedTypeConfigSpec{
+				TargetType: fedv1b1.APIResource{Version: "v1", Kind: kind, Scope: scope},
+				FederatedType: fedv1b1.APIResource{
+					Group:   "types.kubefed.io",
+					Version: "v1beta1",
+					Kind:    "Federated" + kind,
+					Scope:   apiextv1b1.NamespaceScoped,
+				},
+				Propagation: propagation,
+			},
+		}
+	}
+	typeConfigs := []fedv1b1.FederatedTypeConfig{
+		typeConfig("ConfigMap", apiextv1b1.NamespaceScoped, fedv1b1.PropagationEnabled),
+		typeConfig("Secret", apiextv1b1.NamespaceScoped, fedv1b1.PropagationDisabled),
+		typeConfig("ClusterRole", apiextv1b1.ClusterScoped, fedv1b1.PropagationEnabled),
+		typeConfig("Namespace", apiextv1b1.ClusterScoped, fedv1b1.PropagationEnabled),
+	}
+
+	federatedTypes := namespacedFederatedTypes(typeConfigs)
+	if len(federatedTypes) != 1 {
+		t.Fatalf("Expected 1 federated type, got %d", len(federatedTypes))
+	}
+	federatedType, ok := federatedTypes[schema.GroupKind{Group: "types.kubefed.io", Kind: "FederatedConfigMap"}]
+	if !ok {
+		t.Fatalf("Expected FederatedConfigMap to be a namespaced federated type")
+	}
+	if federatedType.Version != "v1beta1" {
+		t.Errorf("Expected version %q, got %q", "v1beta1", federatedType.Version)
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterjoinrequest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// JoinRequestAnnotation is set on the KubeFedCluster and secret
+	// created for an approved join request to record the name of the
+	// request.
+	JoinRequestAnnotation = "kubefed.io/join-request"
+
+	// Reasons recorded on the Joined condition.
+	reasonJoined        = "ClusterJoined"
+	reasonClusterExists = "ClusterExists"
+)
+
+// Controller joins the clusters of approved ClusterJoinRequests by
+// creating a KubeFedCluster and a secret holding the token provided by
+// the member cluster.
+type Controller struct {
+	client genericclient.Client
+
+	// Store for the ClusterJoinRequest objects
+	store cache.Store
+	// Informer for the ClusterJoinRequest objects
+	controller cache.Controller
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller for managing ClusterJoinRequest objects.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting ClusterJoinRequest controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to manage ClusterJoinRequest objects.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "ClusterJoinRequest"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	genericclient, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client: genericclient,
+	}
+
+	c.worker = util.NewReconcileWorker("clusterjoinrequestcontroller", c.reconcile, util.WorkerTiming{})
+
+	// Only watch the KubeFed namespace to ensure restrictive authz
+	// can be applied to a namespaced control plane.
+	c.store, c.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.ClusterJoinRequest{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
+		runtime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("clusterjoinrequestcontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile ClusterJoinRequest for %q", key)
+
+	cachedObj, err := c.objCopyFromCache(key)
+	if err != nil {
+		return util.StatusError
+	}
+	if cachedObj == nil {
+		return util.StatusAllOK
+	}
+	request := cachedObj.(*fedv1b1.ClusterJoinRequest)
+
+	// Only approved requests that have not already been processed
+	// result in a cluster being joined.
+	if !request.IsConditionTrue(fedv1b1.ClusterJoinRequestApproved) ||
+		request.IsConditionTrue(fedv1b1.ClusterJoinRequestDenied) ||
+		request.GetCondition(fedv1b1.ClusterJoinRequestJoined) != nil {
+		return util.StatusAllOK
+	}
+
+	cluster := &fedv1b1.KubeFedCluster{}
+	err = c.client.Get(context.TODO(), cluster, request.Namespace, request.Spec.ClusterName)
+	switch {
+	case apierrors.IsNotFound(err):
+		cluster, err = c.joinCluster(request)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to join cluster %q for ClusterJoinRequest %q", request.Spec.ClusterName, key))
+			return util.StatusError
+		}
+	case err != nil:
+		runtime.HandleError(errors.Wrapf(err, "Failed to get KubeFedCluster %q", request.Spec.ClusterName))
+		return util.StatusError
+	case !createdByRequest(cluster, request):
+		// A join request must not be able to replace the credentials
+		// of a cluster that is already joined.
+		message := fmt.Sprintf("KubeFedCluster %q already exists", request.Spec.ClusterName)
+		return c.setJoinedCondition(request, corev1.ConditionFalse, reasonClusterExists, message)
+	}
+
+	// The token is now stored in the secret referenced by the
+	// KubeFedCluster and should not be retained in the request.
+	if len(request.Spec.ServiceAccountToken) > 0 {
+		request.Spec.ServiceAccountToken = nil
+		if err := c.client.Update(context.TODO(), request); err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to remove token from ClusterJoinRequest %q", key))
+			return util.StatusError
+		}
+	}
+
+	klog.Infof("Joined cluster %q for ClusterJoinRequest %q", cluster.Name, key)
+	message := fmt.Sprintf("KubeFedCluster %q was created", cluster.Name)
+	return c.setJoinedCondition(request, corev1.ConditionTrue, reasonJoined, message)
+}
+
+// joinCluster creates the secret and KubeFedCluster for the cluster of
+// the given request.
+func (c *Controller) joinCluster(request *fedv1b1.ClusterJoinRequest) (*fedv1b1.KubeFedCluster, error) {
+	annotations := map[string]string{
+		JoinRequestAnnotation: request.Name,
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    request.Namespace,
+			GenerateName: request.Spec.ClusterName + "-",
+			Annotations:  annotations,
+		},
+		Data: map[string][]byte{
+			util.TokenKey: request.Spec.ServiceAccountToken,
+		},
+	}
+	if err := c.client.Create(context.TODO(), secret); err != nil {
+		return nil, errors.Wrap(err, "failed to create secret")
+	}
+
+	cluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   request.Namespace,
+			Name:        request.Spec.ClusterName,
+			Labels:      request.Spec.ClusterLabels,
+			Annotations: annotations,
+		},
+		Spec: fedv1b1.KubeFedClusterSpec{
+			APIEndpoint: request.Spec.APIEndpoint,
+			CABundle:    request.Spec.CABundle,
+			SecretRef: fedv1b1.LocalSecretReference{
+				Name: secret.Name,
+			},
+		},
+	}
+	if err := c.client.Create(context.TODO(), cluster); err != nil {
+		return nil, errors.Wrap(err, "failed to create KubeFedCluster")
+	}
+	metrics.JoinedClusterTotalInc()
+	return cluster, nil
+}
+
+func (c *Controller) setJoinedCondition(request *fedv1b1.ClusterJoinRequest, status corev1.ConditionStatus, reason, message string) util.ReconciliationStatus {
+	request.SetCondition(fedv1b1.ClusterJoinRequestJoined, status, reason, message)
+	if err := c.client.UpdateStatus(context.TODO(), request); err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to update status of ClusterJoinRequest %q", util.NewQualifiedName(request)))
+		return util.StatusError
+	}
+	return util.StatusAllOK
+}
+
+func (c *Controller) objCopyFromCache(key string) (pkgruntime.Object, error) {
+	cachedObj, exist, err := c.store.GetByKey(key)
+	if err != nil {
+		wrappedErr := errors.Wrapf(err, "Failed to query ClusterJoinRequest store for %q", key)
+		runtime.HandleError(wrappedErr)
+		return nil, err
+	}
+	if !exist {
+		return nil, nil
+	}
+	return cachedObj.(pkgruntime.Object).DeepCopyObject(), nil
+}
+
+// createdByRequest returns whether the given cluster was created for
+// the given join request.
+func createdByRequest(cluster *fedv1b1.KubeFedCluster, request *fedv1b1.ClusterJoinRequest) bool {
+	return cluster.Annotations[JoinRequestAnnotation] == request.Name
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterrecords
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1a1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// removalDelay is how long after a KubeFedCluster is removed its
+	// records are pruned, allowing the sync controllers to stop
+	// writing records for it first.
+	removalDelay = 30 * time.Second
+
+	userAgent = "StaleClusterRecordCleanup"
+)
+
+// cleanupKey is the key the worker of the controller prunes records
+// for.
+var cleanupKey = util.QualifiedName{Name: "stale-cluster-records"}
+
+// Controller prunes the records of clusters that are no longer joined
+// from the PropagatedVersions and ClusterPropagatedVersions, from the
+// propagation status of federated resources and from the collected
+// status of federated resources when a KubeFedCluster is removed and
+// when the controller starts. The records of a cluster otherwise
+// persist after it is unjoined until each resource happens to be
+// reconciled again.
+type Controller struct {
+	client genericclient.Client
+
+	// fedNamespace is the namespace containing the
+	// FederatedTypeConfigs and KubeFedClusters.
+	fedNamespace string
+
+	// targetNamespace is the namespace of the federated resources, or
+	// all namespaces for a cluster-scoped control plane.
+	targetNamespace string
+
+	// Store and informer for the FederatedTypeConfigs
+	typeConfigStore      cache.Store
+	typeConfigController cache.Controller
+
+	// Store and informer for the KubeFedClusters
+	clusterStore      cache.Store
+	clusterController cache.Controller
+
+	// resourceClients holds the client for each federated and status
+	// type.
+	resourceClients *util.ResourceClientCache
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller pruning the records of
+// clusters that are no longer joined.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting stale cluster record cleanup controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to prune the records of
+// clusters that are no longer joined.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	client, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	c := &Controller{
+		client:          client,
+		fedNamespace:    config.KubeFedNamespace,
+		targetNamespace: config.TargetNamespace,
+		resourceClients: util.NewResourceClientCache(kubeConfig),
+	}
+
+	c.worker = util.NewReconcileWorker("staleclusterrecords", c.reconcile, util.WorkerTiming{})
+
+	// Type configs are read by the next cleanup.
+	c.typeConfigStore, c.typeConfigController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.FederatedTypeConfig{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) {},
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.clusterStore, c.clusterController, err = util.NewGenericInformerWithEventHandler(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.KubeFedCluster{},
+		util.NoResyncPeriod,
+		&cache.ResourceEventHandlerFuncs{
+			DeleteFunc: func(obj interface{}) {
+				c.worker.EnqueueWithDelay(cleanupKey, removalDelay)
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.typeConfigController.Run(stopChan)
+	go c.clusterController.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.typeConfigController.HasSynced, c.clusterController.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for caches to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+	// Prune the records of clusters removed while the controller was
+	// not running.
+	c.worker.Enqueue(cleanupKey)
+}
+
+// reconcile prunes stale cluster records, rechecking if an object
+// could not be pruned.
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	if !c.cleanup() {
+		return util.StatusNeedsRecheck
+	}
+	return util.StatusAllOK
+}
+
+// cleanup prunes the records of clusters that are not KubeFedClusters
+// from all objects recording the state of member clusters, and returns
+// whether all objects were pruned.
+func (c *Controller) cleanup() bool {
+	clusterNames := sets.NewString()
+	for _, obj := range c.clusterStore.List() {
+		cluster := obj.(*fedv1b1.KubeFedCluster)
+		clusterNames.Insert(cluster.Name)
+	}
+
+	complete := c.cleanupPropagatedVersions(clusterNames)
+
+	for _, obj := range c.typeConfigStore.List() {
+		typeConfig := obj.(*fedv1b1.FederatedTypeConfig)
+		if !c.cleanupResources(typeConfig.GetFederatedType(), clusterNames, true, "status", "clusters") {
+			complete = false
+		}
+		if statusType := typeConfig.GetStatusType(); statusType != nil {
+			if !c.cleanupResources(*statusType, clusterNames, false, "clusterStatus") {
+				complete = false
+			}
+		}
+	}
+
+	if complete {
+		metrics.RecordStaleClusterRecordsCleanupCompleted(time.Now())
+	}
+	return complete
+}
+
+// cleanupPropagatedVersions prunes the versions of clusters that are
+// not in the given set from the PropagatedVersions and, for a
+// cluster-scoped control plane, the ClusterPropagatedVersions, and
+// returns whether all of them were pruned.
+func (c *Controller) cleanupPropagatedVersions(clusterNames sets.String) bool {
+	complete := true
+	versionList := &fedv1a1.PropagatedVersionList{}
+	if err := c.client.List(context.TODO(), versionList, c.targetNamespace); err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to list PropagatedVersions"))
+		complete = false
+	} else {
+		pruned := 0
+		for i := range versionList.Items {
+			version := &versionList.Items[i]
+			count, ok := c.cleanupVersion("PropagatedVersion", version, &version.Status, clusterNames)
+			pruned += count
+			complete = complete && ok
+		}
+		metrics.RecordStaleClusterRecordsScanned("PropagatedVersion", len(versionList.Items), pruned)
+	}
+
+	if c.targetNamespace != metav1.NamespaceAll {
+		return complete
+	}
+	clusterVersionList := &fedv1a1.ClusterPropagatedVersionList{}
+	if err := c.client.List(context.TODO(), clusterVersionList, metav1.NamespaceAll); err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to list ClusterPropagatedVersions"))
+		return false
+	}
+	pruned := 0
+	for i := range clusterVersionList.Items {
+		version := &clusterVersionList.Items[i]
+		count, ok := c.cleanupVersion("ClusterPropagatedVersion", version, &version.Status, clusterNames)
+		pruned += count
+		complete = complete && ok
+	}
+	metrics.RecordStaleClusterRecordsScanned("ClusterPropagatedVersion", len(clusterVersionList.Items), pruned)
+	return complete
+}
+
+// cleanupVersion prunes the versions of clusters that are not in the
+// given set from the given status of the given version object of the
+// given kind, and returns the number of versions pruned and whether
+// the object no longer records such clusters.
+func (c *Controller) cleanupVersion(kind string, obj pkgruntime.Object, status *fedv1a1.PropagatedVersionStatus, clusterNames sets.String) (int, bool) {
+	clusterVersions, pruned := pruneClusterVersions(status.ClusterVersions, clusterNames)
+	if pruned == 0 {
+		return 0, true
+	}
+	status.ClusterVersions = clusterVersions
+	// The update fails with a conflict if the sync controller has
+	// since written the version object, in which case the versions are
+	// pruned when the cleanup is rechecked.
+	if err := c.client.UpdateStatus(context.TODO(), obj); err != nil {
+		logUpdateError(err, kind, util.NewQualifiedName(obj))
+		return 0, false
+	}
+	return pruned, true
+}
+
+// cleanupResources prunes the entries of clusters that are not in the
+// given set from the list at the given path of each resource of the
+// given type, and returns whether all of them were pruned.
+func (c *Controller) cleanupResources(apiResource metav1.APIResource, clusterNames sets.String, statusSubresource bool, fields ...string) bool {
+	client, err := c.resourceClients.Get(apiResource)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to create client for %s", apiResource.Kind))
+		return false
+	}
+	resources, err := client.Resources(c.targetNamespace).List(metav1.ListOptions{})
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to list %s", apiResource.Kind))
+		return false
+	}
+
+	complete := true
+	pruned := 0
+	for i := range resources.Items {
+		resource := &resources.Items[i]
+		qualifiedName := util.NewQualifiedName(resource)
+		count, err := pruneClusterEntries(resource, clusterNames, fields...)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to read the cluster entries of %s %q", apiResource.Kind, qualifiedName))
+			complete = false
+			continue
+		}
+		if count == 0 {
+			continue
+		}
+		if statusSubresource {
+			_, err = client.Resources(resource.GetNamespace()).UpdateStatus(resource, metav1.UpdateOptions{})
+		} else {
+			_, err = client.Resources(resource.GetNamespace()).Update(resource, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			logUpdateError(err, apiResource.Kind, qualifiedName)
+			complete = false
+			continue
+		}
+		pruned += count
+	}
+	metrics.RecordStaleClusterRecordsScanned(apiResource.Kind, len(resources.Items), pruned)
+	return complete
+}
+
+func logUpdateError(err error, kind string, qualifiedName util.QualifiedName) {
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		klog.V(2).Infof("Not pruning the cluster records of %s %q since it changed: %v", kind, qualifiedName, err)
+		return
+	}
+	utilruntime.HandleError(errors.Wrapf(err, "Failed to prune the cluster records of %s %q", kind, qualifiedName))
+}
+
+// pruneClusterVersions returns the given versions without the versions
+// of clusters that are not in the given set, and the number of versions
+// removed.
+func pruneClusterVersions(versions []fedv1a1.ClusterObjectVersion, clusterNames sets.String) ([]fedv1a1.ClusterObjectVersion, int) {
+	retained := []fedv1a1.ClusterObjectVersion{}
+	for _, version := range versions {
+		if clusterNames.Has(version.ClusterName) {
+			retained = append(retained, version)
+		}
+	}
+	return retained, len(versions) - len(retained)
+}
+
+// pruneClusterEntries removes the entries of clusters that are not in
+// the given set from the list at the given path of the given object,
+// and returns the number of entries removed. An entry names its cluster
+// in the `name` or `clusterName` field.
+func pruneClusterEntries(obj *unstructured.Unstructured, clusterNames sets.String, fields ...string) (int, error) {
+	entries, ok, err := unstructured.NestedSlice(obj.Object, fields...)
+	if err != nil || !ok {
+		return 0, err
+	}
+	retained := []interface{}{}
+	for _, rawEntry := range entries {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			return 0, errors.Errorf("unexpected cluster entry of type %T", rawEntry)
+		}
+		clusterName, ok := entry["clusterName"].(string)
+		if !ok {
+			clusterName, _ = entry["name"].(string)
+		}
+		if clusterNames.Has(clusterName) {
+			retained = append(retained, entry)
+		}
+	}
+	pruned := len(entries) - len(retained)
+	if pruned == 0 {
+		return 0, nil
+	}
+	return pruned, unstructured.SetNestedSlice(obj.Object, retained, fields...)
+}
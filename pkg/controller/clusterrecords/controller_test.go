@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterrecords
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1a1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+)
+
+func TestPruneClusterVersions(t *testing.T) {
+	versions := []fedv1a1.ClusterObjectVersion{
+		{ClusterName: "cluster1", Version: "gen:1"},
+		{ClusterName: "cluster2", Version: "gen:2"},
+		{ClusterName: "cluster3", Version: "rv:3"},
+	}
+	retained, pruned := pruneClusterVersions(versions, sets.NewString("cluster1", "cluster3"))
+	expected := []fedv1a1.ClusterObjectVersion{
+		{ClusterName: "cluster1", Version: "gen:1"},
+		{ClusterName: "cluster3", Version: "rv:3"},
+	}
+	if pruned != 1 || !reflect.DeepEqual(retained, expected) {
+		t.Fatalf("Expected %v with 1 version pruned, got %v with %d pruned", expected, retained, pruned)
+	}
+}
+
+func TestPruneClusterEntries(t *testing.T) {
+	clusterNames := sets.NewString("cluster1")
+	testCases := map[string]struct {
+		fields   []string
+		entries  []interface{}
+		expected []interface{}
+		pruned   int
+	}{
+		"propagation status entries": {
+			fields: []string{"status", "clusters"},
+			entries: []interface{}{
+				map[string]interface{}{"name": "cluster1"},
+				map[string]interface{}{"name": "cluster2", "status": "ClusterNotReady"},
+			},
+			expected: []interface{}{
+				map[string]interface{}{"name": "cluster1"},
+			},
+			pruned: 1,
+		},
+		"collected status entries": {
+			fields: []string{"clusterStatus"},
+			entries: []interface{}{
+				map[string]interface{}{"clusterName": "cluster2"},
+				map[string]interface{}{"clusterName": "cluster3"},
+			},
+			expected: []interface{}{},
+			pruned:   2,
+		},
+		"no stale entries": {
+			fields: []string{"clusterStatus"},
+			entries: []interface{}{
+				map[string]interface{}{"clusterName": "cluster1"},
+			},
+			expected: []interface{}{
+				map[string]interface{}{"clusterName": "cluster1"},
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if err := unstructured.SetNestedSlice(obj.Object, tc.entries, tc.fields...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			pruned, err := pruneClusterEntries(obj, clusterNames, tc.fields...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pruned != tc.pruned {
+				t.Fatalf("Expected %d entries pruned, got %d", tc.pruned, pruned)
+			}
+			entries, _, _ := unstructured.NestedSlice(obj.Object, tc.fields...)
+			if !reflect.DeepEqual(entries, tc.expected) {
+				t.Fatalf("Expected entries %v, got %v", tc.expected, entries)
+			}
+		})
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if pruned, err := pruneClusterEntries(obj, clusterNames, "clusterStatus"); pruned != 0 || err != nil {
+		t.Fatalf("Expected nothing pruned from an object without entries, got %d, %v", pruned, err)
+	}
+}
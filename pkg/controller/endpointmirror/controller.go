@@ -0,0 +1,450 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointmirror
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	allClustersKey = "ALL_CLUSTERS"
+
+	// ManagedByLabel indicates the controller or entity that manages
+	// an EndpointSlice. Slices not managed by the in-cluster
+	// EndpointSlice controller are left alone by it.
+	ManagedByLabel = "endpointslice.kubernetes.io/managed-by"
+	ManagedByValue = "kubefed.io"
+
+	// SourceClusterLabel identifies the member cluster whose
+	// endpoints a mirrored EndpointSlice contains.
+	SourceClusterLabel = "kubefed.io/source-cluster"
+)
+
+var (
+	federatedServiceAPIResource = metav1.APIResource{
+		Group:        "types.kubefed.io",
+		Version:      "v1beta1",
+		Kind:         "FederatedService",
+		Name:         "federatedservices",
+		SingularName: "federatedservice",
+		Namespaced:   true,
+	}
+
+	endpointSliceAPIResource = metav1.APIResource{
+		Group:        "discovery.k8s.io",
+		Version:      "v1beta1",
+		Kind:         "EndpointSlice",
+		Name:         "endpointslices",
+		SingularName: "endpointslice",
+		Namespaced:   true,
+	}
+)
+
+// Controller mirrors the ready endpoints of federated services
+// between the member clusters the services are propagated to.
+//
+// Mirroring assumes a flat network in which pod IPs of one member
+// cluster are routable from every other member cluster.
+type Controller struct {
+	// For triggering reconciliation of all target resources. This is
+	// used when a new cluster becomes available.
+	clusterDeliverer *util.DelayingDeliverer
+
+	// Store for FederatedService resources in the host cluster
+	federatedServiceStore cache.Store
+	// Informer for FederatedService resources in the host cluster
+	federatedServiceController cache.Controller
+
+	// Informer for endpointslice resources in member clusters
+	informer util.FederatedInformer
+
+	worker util.ReconcileWorker
+
+	clusterAvailableDelay   time.Duration
+	clusterUnavailableDelay time.Duration
+	smallDelay              time.Duration
+
+	// The name of the KubeFed instance to label created resources
+	// with, if any.
+	instanceName string
+}
+
+// StartController starts the Controller for mirroring EndpointSlices.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	if config.MinimizeLatency {
+		controller.minimizeLatency()
+	}
+	klog.Infof("Starting EndpointSlice mirroring controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to mirror EndpointSlices.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, "EndpointMirror")
+	c := &Controller{
+		clusterAvailableDelay:   config.ClusterAvailableDelay,
+		clusterUnavailableDelay: config.ClusterUnavailableDelay,
+		smallDelay:              time.Second * 3,
+		instanceName:            config.InstanceName,
+	}
+
+	c.worker = util.NewReconcileWorker("endpointmirrorcontroller", c.reconcile, util.WorkerTiming{
+		ClusterSyncDelay: c.clusterAvailableDelay,
+	})
+
+	// Build deliverer for triggering cluster reconciliations.
+	c.clusterDeliverer = util.NewDelayingDeliverer()
+
+	federatedServiceClient, err := util.NewResourceClient(config.KubeConfig, &federatedServiceAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	c.federatedServiceStore, c.federatedServiceController = util.NewResourceInformer(
+		federatedServiceClient,
+		config.TargetNamespace,
+		&federatedServiceAPIResource,
+		c.worker.EnqueueObject,
+	)
+
+	// Both the slices created by the EndpointSlice controller for
+	// propagated services (which inherit the managed label of their
+	// service) and the mirrored slices are visible to the managed
+	// resource informer.
+	c.informer, err = util.NewFederatedInformer(
+		config,
+		client,
+		&endpointSliceAPIResource,
+		c.enqueueEndpointSlice,
+		&util.ClusterLifecycleHandlerFuncs{
+			ClusterAvailable: func(cluster *fedv1b1.KubeFedCluster) {
+				// When new cluster becomes available process all the target resources again.
+				c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+			},
+			// When a cluster becomes unavailable process all the target resources again.
+			ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
+				c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterUnavailableDelay))
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// minimizeLatency reduces delays and timeouts to make the controller more responsive (useful for testing).
+func (c *Controller) minimizeLatency() {
+	c.clusterAvailableDelay = time.Second
+	c.clusterUnavailableDelay = time.Second
+	c.smallDelay = 20 * time.Millisecond
+	c.worker.SetDelay(50*time.Millisecond, c.clusterAvailableDelay)
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.federatedServiceController.Run(stopChan)
+	c.informer.Start()
+	c.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
+		c.reconcileOnClusterChange()
+	})
+
+	c.worker.Run(stopChan)
+
+	// Ensure all goroutines are cleaned up when the stop channel closes
+	go func() {
+		<-stopChan
+		c.informer.Stop()
+		c.clusterDeliverer.Stop()
+	}()
+}
+
+// enqueueEndpointSlice enqueues the service that owns the given
+// endpointslice.
+func (c *Controller) enqueueEndpointSlice(obj pkgruntime.Object) {
+	slice := util.MetaAccessor(obj)
+	serviceName, ok := slice.GetLabels()[util.ServiceNameLabel]
+	if !ok {
+		return
+	}
+	c.worker.EnqueueForRetry(util.QualifiedName{Namespace: slice.GetNamespace(), Name: serviceName})
+}
+
+// Check whether all data stores are in sync. False is returned if any of the informer/stores is not yet
+// synced with the corresponding api server.
+func (c *Controller) isSynced() bool {
+	if !c.federatedServiceController.HasSynced() {
+		klog.V(2).Infof("FederatedService not synced")
+		return false
+	}
+	if !c.informer.ClustersSynced() {
+		klog.V(2).Infof("Cluster list not synced")
+		return false
+	}
+	clusters, err := c.informer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready clusters"))
+		return false
+	}
+	return c.informer.GetTargetStore().ClustersSynced(clusters)
+}
+
+// The function triggers reconciliation of all target federated resources.
+func (c *Controller) reconcileOnClusterChange() {
+	if !c.isSynced() {
+		c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+	}
+	for _, obj := range c.federatedServiceStore.List() {
+		qualifiedName := util.NewQualifiedName(obj.(pkgruntime.Object))
+		c.worker.EnqueueWithDelay(qualifiedName, c.smallDelay)
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	defer metrics.UpdateControllerReconcileDurationFromStart("endpointmirrorcontroller", time.Now())
+
+	if !c.isSynced() {
+		return util.StatusNotSynced
+	}
+
+	key := qualifiedName.String()
+
+	klog.V(4).Infof("Starting to reconcile EndpointSlices for %v", key)
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished reconciling EndpointSlices for %v (duration: %v)", key, time.Since(startTime))
+	}()
+
+	cachedObj, exist, err := c.federatedServiceStore.GetByKey(key)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to query FederatedService store for %q", key))
+		return util.StatusError
+	}
+
+	placedClusters := sets.String{}
+	if exist {
+		fedService := cachedObj.(*unstructured.Unstructured)
+		if fedService.GetDeletionTimestamp() == nil {
+			placedClusters, err = status.PropagatedClusterNames(fedService)
+			if err != nil {
+				runtime.HandleError(errors.Wrapf(err, "Failed to determine propagated clusters for FederatedService %q", key))
+				return util.StatusError
+			}
+		}
+	}
+
+	clusters, err := c.informer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready cluster list"))
+		return util.StatusError
+	}
+
+	// Collect the endpointslices of the service in each cluster,
+	// distinguishing between the slices maintained by the cluster
+	// itself and the slices mirrored from other clusters.
+	sourceSlices := make(map[string][]*unstructured.Unstructured)
+	mirroredSlices := make(map[string]map[string]*unstructured.Unstructured)
+	for _, cluster := range clusters {
+		local, mirrored, err := c.serviceSlices(cluster.Name, qualifiedName)
+		if err != nil {
+			runtime.HandleError(err)
+			return util.StatusError
+		}
+		if placedClusters.Has(cluster.Name) {
+			sourceSlices[cluster.Name] = local
+		}
+		mirroredSlices[cluster.Name] = mirrored
+	}
+
+	result := util.StatusAllOK
+	for _, cluster := range clusters {
+		desired := make(map[string]*unstructured.Unstructured)
+		if placedClusters.Has(cluster.Name) {
+			for sourceCluster, slices := range sourceSlices {
+				if sourceCluster == cluster.Name {
+					continue
+				}
+				for _, slice := range slices {
+					mirror := mirrorSlice(slice, sourceCluster, qualifiedName)
+					if mirror != nil {
+						util.AddInstanceLabel(mirror, c.instanceName)
+						desired[mirror.GetName()] = mirror
+					}
+				}
+			}
+		}
+		err := c.syncMirroredSlices(cluster.Name, desired, mirroredSlices[cluster.Name])
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to mirror EndpointSlices of %q to cluster %q", key, cluster.Name))
+			result = util.StatusNeedsRecheck
+		}
+	}
+	return result
+}
+
+// serviceSlices returns the endpointslices of a service in the given
+// cluster, split into slices maintained in the cluster and slices
+// mirrored by this controller (keyed by name).
+func (c *Controller) serviceSlices(clusterName string, qualifiedName util.QualifiedName) ([]*unstructured.Unstructured, map[string]*unstructured.Unstructured, error) {
+	objs, err := c.informer.GetTargetStore().ListFromCluster(clusterName)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to list endpointslices from cluster %q", clusterName)
+	}
+	namespace := util.NamespaceForCluster(clusterName, qualifiedName.Namespace)
+	local := []*unstructured.Unstructured{}
+	mirrored := make(map[string]*unstructured.Unstructured)
+	for _, obj := range objs {
+		slice := obj.(*unstructured.Unstructured)
+		labels := slice.GetLabels()
+		if slice.GetNamespace() != namespace || labels[util.ServiceNameLabel] != qualifiedName.Name {
+			continue
+		}
+		if labels[ManagedByLabel] == ManagedByValue {
+			mirrored[slice.GetName()] = slice
+		} else {
+			local = append(local, slice)
+		}
+	}
+	return local, mirrored, nil
+}
+
+func (c *Controller) syncMirroredSlices(clusterName string, desired, existing map[string]*unstructured.Unstructured) error {
+	if len(desired) == 0 && len(existing) == 0 {
+		return nil
+	}
+	client, err := c.informer.GetClientForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	for name, slice := range desired {
+		slice.SetNamespace(util.NamespaceForCluster(clusterName, slice.GetNamespace()))
+		clusterObj, ok := existing[name]
+		if !ok {
+			klog.V(4).Infof("Creating mirrored EndpointSlice %s/%s in cluster %q", slice.GetNamespace(), name, clusterName)
+			err := client.Create(context.Background(), slice)
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+			continue
+		}
+		if mirroredContentEqual(clusterObj, slice) {
+			continue
+		}
+		klog.V(4).Infof("Updating mirrored EndpointSlice %s/%s in cluster %q", slice.GetNamespace(), name, clusterName)
+		updated := clusterObj.DeepCopy()
+		for _, field := range []string{"addressType", "endpoints", "ports"} {
+			updated.Object[field] = slice.Object[field]
+		}
+		err := client.Update(context.Background(), updated)
+		if err != nil {
+			return err
+		}
+	}
+	for name, clusterObj := range existing {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		klog.V(4).Infof("Deleting mirrored EndpointSlice %s/%s in cluster %q", clusterObj.GetNamespace(), name, clusterName)
+		err := client.Delete(context.Background(), clusterObj, clusterObj.GetNamespace(), name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// mirrorSlice returns an endpointslice containing the ready endpoints
+// of the given slice from the source cluster. Returns nil if the
+// slice does not contain ready endpoints.
+func mirrorSlice(slice *unstructured.Unstructured, sourceCluster string, qualifiedName util.QualifiedName) *unstructured.Unstructured {
+	endpoints, _, _ := unstructured.NestedSlice(slice.Object, "endpoints")
+	readyEndpoints := []interface{}{}
+	for _, e := range endpoints {
+		endpoint, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// A nil ready condition is to be interpreted as ready.
+		ready, found, err := unstructured.NestedBool(endpoint, "conditions", "ready")
+		if err == nil && found && !ready {
+			continue
+		}
+		// Topology and target references are only meaningful in
+		// the source cluster.
+		readyEndpoints = append(readyEndpoints, map[string]interface{}{
+			"addresses": endpoint["addresses"],
+			"conditions": map[string]interface{}{
+				"ready": true,
+			},
+		})
+	}
+	if len(readyEndpoints) == 0 {
+		return nil
+	}
+
+	mirror := &unstructured.Unstructured{}
+	mirror.SetAPIVersion(slice.GetAPIVersion())
+	mirror.SetKind(slice.GetKind())
+	mirror.SetName(fmt.Sprintf("%s-%s", slice.GetName(), sourceCluster))
+	mirror.SetNamespace(qualifiedName.Namespace)
+	mirror.SetLabels(map[string]string{
+		util.ServiceNameLabel: qualifiedName.Name,
+		ManagedByLabel:        ManagedByValue,
+		SourceClusterLabel:    sourceCluster,
+	})
+	util.AddManagedLabel(mirror)
+	mirror.Object["addressType"] = slice.Object["addressType"]
+	mirror.Object["endpoints"] = readyEndpoints
+	if ports, ok := slice.Object["ports"]; ok {
+		mirror.Object["ports"] = ports
+	}
+	return mirror
+}
+
+func mirroredContentEqual(a, b *unstructured.Unstructured) bool {
+	for _, field := range []string{"addressType", "endpoints", "ports"} {
+		if !reflect.DeepEqual(a.Object[field], b.Object[field]) {
+			return false
+		}
+	}
+	return true
+}
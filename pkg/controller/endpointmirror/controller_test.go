@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointmirror
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestMirrorSlice(t *testing.T) {
+	qualifiedName := util.QualifiedName{Namespace: "ns", Name: "svc"}
+	ports := []interface{}{
+		map[string]interface{}{"name": "http", "port": int64(80), "protocol": "TCP"},
+	}
+
+	testCases := map[string]struct {
+		endpoints         []interface{}
+		expectedEndpoints []interface{}
+	}{
+		"Slice without endpoints is not mirrored": {
+			endpoints: []interface{}{},
+		},
+		"Slice without ready endpoints is not mirrored": {
+			endpoints: []interface{}{
+				endpoint("10.0.0.1", false),
+			},
+		},
+		"Only ready endpoints are mirrored": {
+			endpoints: []interface{}{
+				endpoint("10.0.0.1", true),
+				endpoint("10.0.0.2", false),
+				map[string]interface{}{
+					"addresses": []interface{}{"10.0.0.3"},
+				},
+			},
+			expectedEndpoints: []interface{}{
+				endpoint("10.0.0.1", true),
+				endpoint("10.0.0.3", true),
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			slice := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion":  "discovery.k8s.io/v1beta1",
+				"kind":        "EndpointSlice",
+				"addressType": "IPv4",
+				"endpoints":   tc.endpoints,
+				"ports":       ports,
+			}}
+			slice.SetName("svc-abcde")
+			slice.SetNamespace("ns")
+
+			mirror := mirrorSlice(slice, "cluster1", qualifiedName)
+			if tc.expectedEndpoints == nil {
+				if mirror != nil {
+					t.Fatalf("Expected no mirror, got %v", mirror)
+				}
+				return
+			}
+			if mirror == nil {
+				t.Fatalf("Expected a mirror")
+			}
+			if mirror.GetName() != "svc-abcde-cluster1" {
+				t.Fatalf("Unexpected name %q", mirror.GetName())
+			}
+			labels := mirror.GetLabels()
+			if labels[util.ServiceNameLabel] != "svc" || labels[SourceClusterLabel] != "cluster1" ||
+				labels[ManagedByLabel] != ManagedByValue || !util.HasManagedLabel(mirror) {
+				t.Fatalf("Unexpected labels %v", labels)
+			}
+			if !reflect.DeepEqual(tc.expectedEndpoints, mirror.Object["endpoints"]) {
+				t.Fatalf("Expected endpoints %v, got %v", tc.expectedEndpoints, mirror.Object["endpoints"])
+			}
+			if !reflect.DeepEqual(ports, mirror.Object["ports"]) {
+				t.Fatalf("Expected ports %v, got %v", ports, mirror.Object["ports"])
+			}
+		})
+	}
+}
+
+func endpoint(address string, ready bool) map[string]interface{} {
+	return map[string]interface{}{
+		"addresses": []interface{}{address},
+		"conditions": map[string]interface{}{
+			"ready": ready,
+		},
+	}
+}
@@ -0,0 +1,467 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedapplication
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// statusPeriod is how often the status of every application is
+	// refreshed to reflect the propagation of its resources.
+	statusPeriod = 30 * time.Second
+
+	deploymentKind = "Deployment"
+)
+
+// Controller applies the shared placement, rollout strategy and pause
+// state of FederatedApplications to the federated resources they
+// group, and records the aggregated propagation status of the
+// resources in the status of each application.
+type Controller struct {
+	client genericclient.Client
+
+	// fedNamespace is the namespace containing the
+	// FederatedTypeConfigs.
+	fedNamespace string
+
+	// Store for the FederatedApplication objects
+	store cache.Store
+	// Informer for the FederatedApplication objects
+	controller cache.Controller
+
+	// resourceClients holds the client for each federated type.
+	resourceClients *util.ResourceClientCache
+
+	worker util.ReconcileWorker
+}
+
+// federatedType describes a federated type that resources of an
+// application may be of.
+type federatedType struct {
+	apiResource metav1.APIResource
+	targetKind  string
+}
+
+// StartController starts the Controller for managing FederatedApplication objects.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting FederatedApplication controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to manage FederatedApplication objects.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "FederatedApplication"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	genericclient, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:          genericclient,
+		fedNamespace:    config.KubeFedNamespace,
+		resourceClients: util.NewResourceClientCache(kubeConfig),
+	}
+
+	c.worker = util.NewReconcileWorker("federatedapplicationcontroller", c.reconcile, util.WorkerTiming{})
+
+	c.store, c.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		config.TargetNamespace,
+		&fedv1b1.FederatedApplication{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+
+	// Changes to the resources of an application are not watched, so
+	// applications are periodically reconciled to refresh their
+	// status and to pick up newly selected resources.
+	go wait.Until(c.enqueueAll, statusPeriod, stopChan)
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.store.List() {
+		c.worker.EnqueueObject(obj.(runtime.Object))
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("federatedapplicationcontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile FederatedApplication for %q", key)
+
+	cachedObj, exist, err := c.store.GetByKey(key)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query FederatedApplication store for %q", key))
+		return util.StatusError
+	}
+	if !exist {
+		return util.StatusAllOK
+	}
+	app := cachedObj.(*fedv1b1.FederatedApplication).DeepCopy()
+	// The resources of a deleted application are removed by the
+	// garbage collector.
+	if app.DeletionTimestamp != nil {
+		return util.StatusAllOK
+	}
+
+	fedTypes, err := c.federatedTypes()
+	if err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to list FederatedTypeConfigs"))
+		return util.StatusError
+	}
+
+	var selector labels.Selector
+	if app.Spec.Selector != nil {
+		selector, err = metav1.LabelSelectorAsSelector(app.Spec.Selector)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Invalid selector of FederatedApplication %q", key))
+			return util.StatusAllOK
+		}
+	}
+	listed := make(map[fedv1b1.FederatedApplicationResource]bool)
+	for _, resource := range app.Spec.Resources {
+		listed[resource] = true
+	}
+
+	result := util.StatusAllOK
+	found := make(map[fedv1b1.FederatedApplicationResource]*unstructured.Unstructured)
+	for kind, fedType := range fedTypes {
+		client, err := c.resourceClients.Get(fedType.apiResource)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to create client for %s", kind))
+			result = util.StatusError
+			continue
+		}
+		objList, err := client.Resources(app.Namespace).List(metav1.ListOptions{})
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to list %s in namespace %q", kind, app.Namespace))
+			result = util.StatusError
+			continue
+		}
+		for i := range objList.Items {
+			obj := &objList.Items[i]
+			resource := fedv1b1.FederatedApplicationResource{Kind: kind, Name: obj.GetName()}
+			member := listed[resource] || (selector != nil && selector.Matches(labels.Set(obj.GetLabels())))
+			if member {
+				found[resource] = obj
+			}
+			updated := obj.DeepCopy()
+			var applyErr error
+			if member {
+				applyErr = applyApplication(updated, app, fedType.targetKind)
+			} else {
+				removeOwnerReference(updated, app)
+			}
+			if applyErr != nil {
+				utilruntime.HandleError(errors.Wrapf(applyErr, "Failed to apply FederatedApplication %q to %s %q", key, kind, obj.GetName()))
+				result = util.StatusError
+				continue
+			}
+			if equality.Semantic.DeepEqual(obj, updated) {
+				continue
+			}
+			klog.V(4).Infof("Updating %s %q for FederatedApplication %q", kind, obj.GetName(), key)
+			updatedObj, err := client.Resources(app.Namespace).Update(updated, metav1.UpdateOptions{})
+			if err != nil {
+				utilruntime.HandleError(errors.Wrapf(err, "Failed to update %s %q for FederatedApplication %q", kind, obj.GetName(), key))
+				result = util.StatusError
+				continue
+			}
+			if member {
+				found[resource] = updatedObj
+			}
+		}
+	}
+	if result != util.StatusAllOK {
+		return result
+	}
+
+	appStatus := applicationStatus(app, found)
+	if equality.Semantic.DeepEqual(app.Status, appStatus) {
+		return util.StatusAllOK
+	}
+	app.Status = appStatus
+	if err := c.client.UpdateStatus(context.TODO(), app); err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to update status of FederatedApplication %q", key))
+		return util.StatusError
+	}
+	return util.StatusAllOK
+}
+
+// federatedTypes returns the namespaced federated types configured by
+// FederatedTypeConfigs, keyed by federated kind.
+func (c *Controller) federatedTypes() (map[string]federatedType, error) {
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	if err := c.client.List(context.TODO(), typeConfigs, c.fedNamespace); err != nil {
+		return nil, err
+	}
+	fedTypes := make(map[string]federatedType)
+	for i := range typeConfigs.Items {
+		typeConfig := &typeConfigs.Items[i]
+		if !typeConfig.GetNamespaced() {
+			continue
+		}
+		apiResource := typeConfig.GetFederatedType()
+		fedTypes[apiResource.Kind] = federatedType{
+			apiResource: apiResource,
+			targetKind:  typeConfig.GetTargetType().Kind,
+		}
+	}
+	return fedTypes, nil
+}
+
+// applyApplication sets the owner reference, pause state, placement
+// and rollout strategy of the given application on a federated
+// resource it groups.
+func applyApplication(obj *unstructured.Unstructured, app *fedv1b1.FederatedApplication, targetKind string) error {
+	ensureOwnerReference(obj, app)
+	util.SetPaused(obj, app.Spec.Paused)
+
+	if placement := app.Spec.Placement; placement != nil {
+		if err := setPlacement(obj, placement); err != nil {
+			return err
+		}
+	}
+
+	if app.Spec.RolloutStrategy != nil && targetKind == deploymentKind {
+		strategy, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app.Spec.RolloutStrategy)
+		if err != nil {
+			return err
+		}
+		err = unstructured.SetNestedField(obj.Object, strategy, util.SpecField, util.TemplateField, util.SpecField, "strategy")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPlacement replaces the clusters, cluster groups and cluster
+// selector of the placement of a federated resource.
+func setPlacement(obj *unstructured.Unstructured, placement *fedv1b1.FederatedApplicationPlacement) error {
+	placementMap, _, err := unstructured.NestedMap(obj.Object, util.SpecField, util.PlacementField)
+	if err != nil {
+		return err
+	}
+	if placementMap == nil {
+		placementMap = make(map[string]interface{})
+	}
+	delete(placementMap, util.ClustersField)
+	delete(placementMap, util.ClusterGroupsField)
+	delete(placementMap, util.ClusterSelectorField)
+
+	if placement.Clusters != nil {
+		clusters := []interface{}{}
+		for _, clusterName := range placement.Clusters {
+			clusters = append(clusters, map[string]interface{}{"name": clusterName})
+		}
+		placementMap[util.ClustersField] = clusters
+	}
+	if placement.ClusterGroups != nil {
+		groups := []interface{}{}
+		for _, groupName := range placement.ClusterGroups {
+			groups = append(groups, groupName)
+		}
+		placementMap[util.ClusterGroupsField] = groups
+	}
+	if placement.ClusterSelector != nil {
+		selector, err := runtime.DefaultUnstructuredConverter.ToUnstructured(placement.ClusterSelector)
+		if err != nil {
+			return err
+		}
+		placementMap[util.ClusterSelectorField] = selector
+	}
+	return unstructured.SetNestedMap(obj.Object, placementMap, util.SpecField, util.PlacementField)
+}
+
+func ownerReference(app *fedv1b1.FederatedApplication) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: fedv1b1.SchemeGroupVersion.String(),
+		Kind:       "FederatedApplication",
+		Name:       app.Name,
+		UID:        app.UID,
+	}
+}
+
+// ensureOwnerReference makes the application an owner of the federated
+// resource so that the resource is deleted with the application.
+func ensureOwnerReference(obj *unstructured.Unstructured, app *fedv1b1.FederatedApplication) {
+	ownerRefs := obj.GetOwnerReferences()
+	for _, ref := range ownerRefs {
+		if ref.UID == app.UID {
+			return
+		}
+	}
+	obj.SetOwnerReferences(append(ownerRefs, ownerReference(app)))
+}
+
+// removeOwnerReference removes the application from the owners of a
+// federated resource it no longer groups.
+func removeOwnerReference(obj *unstructured.Unstructured, app *fedv1b1.FederatedApplication) {
+	ownerRefs := obj.GetOwnerReferences()
+	for i, ref := range ownerRefs {
+		if ref.UID == app.UID {
+			obj.SetOwnerReferences(append(ownerRefs[:i], ownerRefs[i+1:]...))
+			return
+		}
+	}
+}
+
+// applicationStatus computes the status of an application from the
+// federated resources it groups.
+func applicationStatus(app *fedv1b1.FederatedApplication, found map[fedv1b1.FederatedApplicationResource]*unstructured.Unstructured) fedv1b1.FederatedApplicationStatus {
+	resources := make(map[fedv1b1.FederatedApplicationResource]bool)
+	for resource := range found {
+		resources[resource] = true
+	}
+	for _, resource := range app.Spec.Resources {
+		resources[resource] = true
+	}
+
+	appStatus := fedv1b1.FederatedApplicationStatus{
+		ObservedGeneration: app.Generation,
+	}
+	for resource := range resources {
+		resourceStatus := fedv1b1.FederatedApplicationResourceStatus{
+			Kind: resource.Kind,
+			Name: resource.Name,
+		}
+		if obj, ok := found[resource]; ok {
+			resourceStatus.Found = true
+			resourceStatus.Propagated, resourceStatus.Reason = propagationState(obj)
+			resourceStatus.Health = string(healthState(obj))
+		}
+		if resourceStatus.Propagated {
+			appStatus.PropagatedResourceCount++
+		}
+		if resourceStatus.Health == string(health.Healthy) {
+			appStatus.ReadyResourceCount++
+		}
+		appStatus.Resources = append(appStatus.Resources, resourceStatus)
+	}
+	appStatus.ResourceCount = int32(len(appStatus.Resources))
+	sort.Slice(appStatus.Resources, func(i, j int) bool {
+		if appStatus.Resources[i].Kind != appStatus.Resources[j].Kind {
+			return appStatus.Resources[i].Kind < appStatus.Resources[j].Kind
+		}
+		return appStatus.Resources[i].Name < appStatus.Resources[j].Name
+	})
+	return appStatus
+}
+
+// propagationState returns whether the current generation of a
+// federated resource has been propagated to all of its clusters and,
+// if not, the reason reported by its propagation condition.
+func propagationState(obj *unstructured.Unstructured) (bool, string) {
+	resource := &status.GenericFederatedResource{}
+	if err := util.UnstructuredToInterface(obj, resource); err != nil {
+		klog.V(4).Infof("Unable to determine propagation of %s %q: %v", obj.GetKind(), obj.GetName(), err)
+		return false, ""
+	}
+	if resource.Status == nil || resource.Status.ObservedGeneration != obj.GetGeneration() {
+		return false, ""
+	}
+	for _, condition := range resource.Status.Conditions {
+		if condition.Type != status.PropagationConditionType {
+			continue
+		}
+		if condition.Status == apiv1.ConditionTrue {
+			return true, ""
+		}
+		return false, string(condition.Reason)
+	}
+	return false, ""
+}
+
+// healthState returns the health of a federated resource across its
+// clusters as reported by its ready condition, or an empty health if
+// the resource has no ready condition.
+func healthState(obj *unstructured.Unstructured) health.Health {
+	resource := &status.GenericFederatedResource{}
+	if err := util.UnstructuredToInterface(obj, resource); err != nil {
+		klog.V(4).Infof("Unable to determine health of %s %q: %v", obj.GetKind(), obj.GetName(), err)
+		return ""
+	}
+	if resource.Status == nil {
+		return ""
+	}
+	for _, condition := range resource.Status.Conditions {
+		if condition.Type != status.ReadyConditionType {
+			continue
+		}
+		switch {
+		case resource.Status.ObservedGeneration != obj.GetGeneration():
+			return health.Progressing
+		case condition.Status == apiv1.ConditionTrue:
+			return health.Healthy
+		case condition.Reason == status.ClustersDegraded:
+			return health.Degraded
+		}
+		return health.Progressing
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedapplication
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestApplyApplication(t *testing.T) {
+	app := &fedv1b1.FederatedApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web",
+			UID:  "app-uid",
+		},
+		Spec: fedv1b1.FederatedApplicationSpec{
+			Placement: &fedv1b1.FederatedApplicationPlacement{
+				Clusters: []string{"cluster1"},
+			},
+			RolloutStrategy: &appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Paused: true,
+		},
+	}
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"placement": map[string]interface{}{
+					"clusterSelector": map[string]interface{}{},
+					"requiredCRDs":    []interface{}{"certificates.cert-manager.io"},
+				},
+			},
+		},
+	}
+	if err := applyApplication(obj, app, deploymentKind); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	clusterNames, err := util.GetClusterNames(obj)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(clusterNames, []string{"cluster1"}) {
+		t.Errorf("Expected clusters [cluster1], got %v", clusterNames)
+	}
+	if _, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "placement", "clusterSelector"); ok {
+		t.Errorf("Expected the cluster selector to be removed")
+	}
+	if _, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "placement", "requiredCRDs"); !ok {
+		t.Errorf("Expected required CRDs to be retained")
+	}
+	strategyType, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "spec", "strategy", "type")
+	if strategyType != string(appsv1.RecreateDeploymentStrategyType) {
+		t.Errorf("Expected strategy type %q, got %q", appsv1.RecreateDeploymentStrategyType, strategyType)
+	}
+	if !util.IsPaused(obj) {
+		t.Errorf("Expected propagation to be paused")
+	}
+	ownerRefs := obj.GetOwnerReferences()
+	if len(ownerRefs) != 1 || ownerRefs[0].UID != app.UID {
+		t.Fatalf("Expected the application to be the owner, got %v", ownerRefs)
+	}
+
+	// Applying the application again should not change the resource.
+	updated := obj.DeepCopy()
+	if err := applyApplication(updated, app, deploymentKind); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(obj, updated) {
+		t.Errorf("Expected no change, got %v", updated)
+	}
+
+	removeOwnerReference(obj, app)
+	if ownerRefs := obj.GetOwnerReferences(); len(ownerRefs) != 0 {
+		t.Errorf("Expected no owners, got %v", ownerRefs)
+	}
+}
+
+func TestApplicationStatus(t *testing.T) {
+	app := &fedv1b1.FederatedApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Generation: 2,
+		},
+		Spec: fedv1b1.FederatedApplicationSpec{
+			Resources: []fedv1b1.FederatedApplicationResource{
+				{Kind: "FederatedService", Name: "missing"},
+			},
+		},
+	}
+	newResource := func(name string, propagated bool) *unstructured.Unstructured {
+		conditionStatus := "False"
+		reason := "CheckClusters"
+		if propagated {
+			conditionStatus = "True"
+			reason = ""
+		}
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":   "Propagation",
+							"status": conditionStatus,
+							"reason": reason,
+						},
+					},
+				},
+			},
+		}
+		obj.SetName(name)
+		obj.SetGeneration(1)
+		return obj
+	}
+	withReadyCondition := func(obj *unstructured.Unstructured, conditionStatus, reason string) *unstructured.Unstructured {
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		conditions = append(conditions, map[string]interface{}{
+			"type":   "Ready",
+			"status": conditionStatus,
+			"reason": reason,
+		})
+		_ = unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions")
+		return obj
+	}
+	found := map[fedv1b1.FederatedApplicationResource]*unstructured.Unstructured{
+		{Kind: "FederatedDeployment", Name: "web"}:  withReadyCondition(newResource("web", true), "True", ""),
+		{Kind: "FederatedDeployment", Name: "api"}:  newResource("api", false),
+		{Kind: "FederatedStatefulSet", Name: "db"}:  withReadyCondition(newResource("db", true), "False", "ClustersDegraded"),
+		{Kind: "FederatedDaemonSet", Name: "agent"}: withReadyCondition(newResource("agent", true), "False", "ClustersProgressing"),
+	}
+
+	expected := fedv1b1.FederatedApplicationStatus{
+		ObservedGeneration:      2,
+		ResourceCount:           5,
+		PropagatedResourceCount: 3,
+		ReadyResourceCount:      1,
+		Resources: []fedv1b1.FederatedApplicationResourceStatus{
+			{Kind: "FederatedDaemonSet", Name: "agent", Found: true, Propagated: true, Health: "Progressing"},
+			{Kind: "FederatedDeployment", Name: "api", Found: true, Reason: "CheckClusters"},
+			{Kind: "FederatedDeployment", Name: "web", Found: true, Propagated: true, Health: "Healthy"},
+			{Kind: "FederatedService", Name: "missing"},
+			{Kind: "FederatedStatefulSet", Name: "db", Found: true, Propagated: true, Health: "Degraded"},
+		},
+	}
+	if appStatus := applicationStatus(app, found); !reflect.DeepEqual(appStatus, expected) {
+		t.Fatalf("Expected status %#v, got %#v", expected, appStatus)
+	}
+}
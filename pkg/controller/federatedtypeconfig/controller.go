@@ -90,7 +90,7 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 		stopChannels:     make(map[string]chan struct{}),
 	}
 
-	c.worker = util.NewReconcileWorker(c.reconcile, util.WorkerTiming{})
+	c.worker = util.NewReconcileWorker("federatedtypeconfigcontroller", c.reconcile, util.WorkerTiming{})
 
 	// Only watch the KubeFed namespace to ensure
 	// restrictive authz can be applied to a namespaced
@@ -163,6 +163,7 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 
 		typeConfig.Status.ObservedGeneration = typeConfig.Generation
 		typeConfig.Status.PropagationController = corev1b1.ControllerStatusNotRunning
+		clearSyncControllerStatus(typeConfig)
 
 		if typeConfig.Status.StatusController == nil {
 			typeConfig.Status.StatusController = new(corev1b1.ControllerStatus)
@@ -177,6 +178,7 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	}
 
 	statusKey := typeConfig.Name + "/status"
+	deletionKey := typeConfig.Name + "/deletion"
 	syncStopChan, syncRunning := c.getStopChannel(typeConfig.Name)
 	statusStopChan, statusRunning := c.getStopChannel(statusKey)
 
@@ -188,6 +190,9 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 		if statusRunning {
 			c.stopController(statusKey, statusStopChan)
 		}
+		if deletionStopChan, deletionRunning := c.getStopChannel(deletionKey); deletionRunning {
+			c.stopController(deletionKey, deletionStopChan)
+		}
 
 		if typeConfig.IsNamespace() {
 			klog.Infof("Reconciling all namespaced FederatedTypeConfig resources on deletion of %q", key)
@@ -244,12 +249,17 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 		}
 	}
 
+	// The resources of the type are only deleted from member clusters
+	// once the sync controller has been stopped.
+	c.reconcileResourceDeletion(deletionKey, typeConfig, syncEnabled)
+
 	typeConfig.Status.ObservedGeneration = typeConfig.Generation
 	syncControllerRunning := startNewSyncController || (syncRunning && !stopSyncController)
 	if syncControllerRunning {
 		typeConfig.Status.PropagationController = corev1b1.ControllerStatusRunning
 	} else {
 		typeConfig.Status.PropagationController = corev1b1.ControllerStatusNotRunning
+		clearSyncControllerStatus(typeConfig)
 	}
 
 	if typeConfig.Status.StatusController == nil {
@@ -270,6 +280,50 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	return util.StatusAllOK
 }
 
+// reconcileResourceDeletion starts or stops the deletion of the
+// resources of the type from member clusters, and updates the status
+// of the deletion recorded in the given FederatedTypeConfig.
+func (c *Controller) reconcileResourceDeletion(key string, tc *corev1b1.FederatedTypeConfig, syncEnabled bool) {
+	config := tc.Spec.ResourceDeletion
+	if syncEnabled || config == nil {
+		tc.Status.ResourceDeletion = nil
+	}
+	progress := tc.Status.ResourceDeletion
+
+	complete := progress != nil && progress.Phase == corev1b1.ResourceDeletionComplete
+	runDeletion := !syncEnabled && config != nil && !config.Paused && !complete
+	stopChan, running := c.getStopChannel(key)
+	if runDeletion && !running {
+		c.startResourceDeletion(key, tc)
+	} else if !runDeletion && running {
+		c.stopController(key, stopChan)
+	}
+
+	if progress != nil && config != nil && config.Paused && progress.Phase == corev1b1.ResourceDeletionRunning {
+		progress.Phase = corev1b1.ResourceDeletionPaused
+		progress.EstimatedCompletionTime = nil
+	}
+}
+
+func (c *Controller) startResourceDeletion(key string, tc *corev1b1.FederatedTypeConfig) {
+	deleter := newResourceDeleter(c.controllerConfig, c.client, tc)
+	stopChan := make(chan struct{})
+	go deleter.run(stopChan)
+	klog.Infof("Started deletion of the resources of %q from member clusters", tc.Name)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stopChannels[key] = stopChan
+}
+
+// clearSyncControllerStatus removes the status fields recorded by the
+// sync controller so that stale values are not reported while it is
+// not running.
+func clearSyncControllerStatus(tc *corev1b1.FederatedTypeConfig) {
+	tc.Status.ResourceCount = nil
+	tc.Status.PropagatedResourceCount = nil
+	tc.Status.Clusters = nil
+}
+
 func (c *Controller) objCopyFromCache(key string) (pkgruntime.Object, error) {
 	cachedObj, exist, err := c.store.GetByKey(key)
 	if err != nil {
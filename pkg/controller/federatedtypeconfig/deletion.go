@@ -0,0 +1,313 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedtypeconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+
+	corev1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	// DefaultDeletesPerSecond is the rate at which the resources of a
+	// type are deleted from member clusters if not configured.
+	DefaultDeletesPerSecond = 10
+
+	// The interval at which the progress of a deletion is recorded.
+	deletionStatusInterval = 5 * time.Second
+	// The interval after which a deletion that left resources in
+	// place (e.g. in clusters that were not ready) is retried.
+	deletionRetryInterval = 30 * time.Second
+)
+
+// resourceDeleter deletes the resources of a type whose propagation
+// is disabled from member clusters at a limited rate, recording its
+// progress in the status of the FederatedTypeConfig.
+type resourceDeleter struct {
+	controllerConfig *util.ControllerConfig
+	client           genericclient.Client
+
+	typeConfigName   util.QualifiedName
+	targetType       metav1.APIResource
+	federatedType    metav1.APIResource
+	deletesPerSecond int32
+
+	progress corev1b1.ResourceDeletionStatus
+}
+
+// deletionTarget is a resource to delete from a member cluster.
+type deletionTarget struct {
+	client    util.ResourceClient
+	namespace string
+	name      string
+}
+
+func newResourceDeleter(controllerConfig *util.ControllerConfig, client genericclient.Client, typeConfig *corev1b1.FederatedTypeConfig) *resourceDeleter {
+	deletesPerSecond := int32(DefaultDeletesPerSecond)
+	if typeConfig.Spec.ResourceDeletion.DeletesPerSecond != nil {
+		deletesPerSecond = *typeConfig.Spec.ResourceDeletion.DeletesPerSecond
+	}
+	return &resourceDeleter{
+		controllerConfig: controllerConfig,
+		client:           client,
+		typeConfigName:   util.NewQualifiedName(typeConfig),
+		targetType:       typeConfig.GetTargetType(),
+		federatedType:    typeConfig.GetFederatedType(),
+		deletesPerSecond: deletesPerSecond,
+		progress:         resumedDeletion(typeConfig.Status.ResourceDeletion, time.Now()),
+	}
+}
+
+// resumedDeletion returns the progress a deletion starts from. A
+// deletion that was paused or interrupted keeps its start time and
+// the count of the resources it deleted.
+func resumedDeletion(existing *corev1b1.ResourceDeletionStatus, now time.Time) corev1b1.ResourceDeletionStatus {
+	if existing == nil || existing.Phase == corev1b1.ResourceDeletionComplete {
+		return corev1b1.ResourceDeletionStatus{
+			Phase:     corev1b1.ResourceDeletionRunning,
+			StartTime: metav1.NewTime(now),
+		}
+	}
+	return corev1b1.ResourceDeletionStatus{
+		Phase:     corev1b1.ResourceDeletionRunning,
+		Deleted:   existing.Deleted,
+		StartTime: existing.StartTime,
+	}
+}
+
+// run deletes resources until none remain or the stop channel is
+// closed.
+func (d *resourceDeleter) run(stopChan <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		done, err := d.deletePass(ctx)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to delete the resources of FederatedTypeConfig %q from member clusters", d.typeConfigName))
+		}
+		if done {
+			klog.Infof("Deleted the resources of FederatedTypeConfig %q from member clusters", d.typeConfigName)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(deletionRetryInterval):
+		}
+	}
+}
+
+// deletePass deletes the resources found in the member clusters that
+// are ready, and returns whether no resources remain to be deleted.
+func (d *resourceDeleter) deletePass(ctx context.Context) (bool, error) {
+	targets, pendingClusters, err := d.listTargets()
+	if err != nil {
+		return false, err
+	}
+	d.progress.Phase = corev1b1.ResourceDeletionRunning
+	d.progress.Total = d.progress.Deleted + int64(len(targets))
+	d.progress.Failed = 0
+	d.progress.PendingClusters = pendingClusters
+	d.progress.EstimatedCompletionTime = estimateDeletionCompletion(&d.progress, time.Now())
+	if err := d.updateStatus(); err != nil {
+		return false, err
+	}
+
+	limiter := flowcontrol.NewTokenBucketRateLimiter(float32(d.deletesPerSecond), int(d.deletesPerSecond))
+	defer limiter.Stop()
+	background := metav1.DeletePropagationBackground
+	lastUpdate := time.Now()
+	for _, target := range targets {
+		if err := limiter.Wait(ctx); err != nil {
+			// Stopped, e.g. since the deletion was paused.
+			return false, nil
+		}
+		err := target.client.Resources(target.namespace).Delete(target.name, &metav1.DeleteOptions{PropagationPolicy: &background})
+		if err == nil || apierrors.IsNotFound(err) {
+			d.progress.Deleted++
+		} else {
+			d.progress.Failed++
+			klog.V(2).Infof("Failed to delete %s %q: %v", d.targetType.Kind, util.QualifiedName{Namespace: target.namespace, Name: target.name}, err)
+		}
+		if now := time.Now(); now.Sub(lastUpdate) >= deletionStatusInterval {
+			d.progress.EstimatedCompletionTime = estimateDeletionCompletion(&d.progress, now)
+			if err := d.updateStatus(); err != nil {
+				runtime.HandleError(err)
+			}
+			lastUpdate = now
+		}
+	}
+
+	done := d.progress.Failed == 0 && len(pendingClusters) == 0
+	if done {
+		completionTime := metav1.Now()
+		d.progress.Phase = corev1b1.ResourceDeletionComplete
+		d.progress.CompletionTime = &completionTime
+		d.progress.EstimatedCompletionTime = nil
+	} else {
+		d.progress.EstimatedCompletionTime = estimateDeletionCompletion(&d.progress, time.Now())
+	}
+	return done, d.updateStatus()
+}
+
+// estimateDeletionCompletion returns the time the given deletion is
+// expected to complete given the rate of deletion since it started.
+func estimateDeletionCompletion(progress *corev1b1.ResourceDeletionStatus, now time.Time) *metav1.Time {
+	if progress.Deleted == 0 || progress.Deleted >= progress.Total {
+		return nil
+	}
+	elapsed := now.Sub(progress.StartTime.Time)
+	estimate := metav1.NewTime(progress.StartTime.Add(elapsed * time.Duration(progress.Total) / time.Duration(progress.Deleted)))
+	return &estimate
+}
+
+// listTargets returns the resources of the target type managed by the
+// control plane in the member clusters that are ready, and the names
+// of the clusters whose resources could not be listed. Resources whose
+// federated resource enables orphaning are left in place.
+func (d *resourceDeleter) listTargets() ([]deletionTarget, []string, error) {
+	clusterList := &corev1b1.KubeFedClusterList{}
+	err := d.client.List(context.TODO(), clusterList, d.controllerConfig.KubeFedNamespace)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to list KubeFedClusters")
+	}
+	orphaned, err := d.orphanedResources()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selector := util.ManagedLabelSelector(d.controllerConfig.InstanceName).String()
+	var targets []deletionTarget
+	var pendingClusters []string
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if !util.IsClusterReady(&cluster.Status) {
+			pendingClusters = append(pendingClusters, cluster.Name)
+			continue
+		}
+		client, err := d.clusterResourceClient(cluster)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to create a client for cluster %q", cluster.Name))
+			pendingClusters = append(pendingClusters, cluster.Name)
+			continue
+		}
+		list, err := client.Resources(d.controllerConfig.TargetNamespace).List(metav1.ListOptions{LabelSelector: selector})
+		if apierrors.IsNotFound(err) {
+			// The cluster does not serve the target type.
+			continue
+		}
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to list %s in cluster %q", d.targetType.Kind, cluster.Name))
+			pendingClusters = append(pendingClusters, cluster.Name)
+			continue
+		}
+		for _, obj := range list.Items {
+			if orphaned.Has(util.NewQualifiedName(&obj).String()) {
+				continue
+			}
+			targets = append(targets, deletionTarget{
+				client:    client,
+				namespace: obj.GetNamespace(),
+				name:      obj.GetName(),
+			})
+		}
+	}
+	return targets, pendingClusters, nil
+}
+
+// orphanedResources returns the keys of the federated resources of the
+// type that enable orphaning.
+func (d *resourceDeleter) orphanedResources() (sets.String, error) {
+	orphaned := sets.String{}
+	client, err := util.NewResourceClient(d.controllerConfig.KubeConfig, &d.federatedType)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.Resources(d.controllerConfig.TargetNamespace).List(metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return orphaned, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list %s", d.federatedType.Kind)
+	}
+	for i := range list.Items {
+		if util.IsOrphaningEnabled(&list.Items[i]) {
+			orphaned.Insert(util.NewQualifiedName(&list.Items[i]).String())
+		}
+	}
+	return orphaned, nil
+}
+
+func (d *resourceDeleter) clusterResourceClient(cluster *corev1b1.KubeFedCluster) (util.ResourceClient, error) {
+	config, err := util.BuildClusterConfig(cluster, d.client, d.controllerConfig.KubeFedNamespace, d.controllerConfig.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if d.controllerConfig.ClusterTransports != nil {
+		config, err = d.controllerConfig.ClusterTransports.Configure(cluster, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	restclient.AddUserAgent(config, "resource-deletion")
+	return util.NewResourceClient(config, &d.targetType)
+}
+
+// updateStatus records the progress of the deletion in the status of
+// the FederatedTypeConfig, unless the deletion no longer applies. A
+// deletion whose pausing has not yet stopped it is reported as paused.
+func (d *resourceDeleter) updateStatus() error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		typeConfig := &corev1b1.FederatedTypeConfig{}
+		err := d.client.Get(context.TODO(), typeConfig, d.typeConfigName.Namespace, d.typeConfigName.Name)
+		if err != nil {
+			return err
+		}
+		if typeConfig.GetPropagationEnabled() || typeConfig.Spec.ResourceDeletion == nil {
+			return nil
+		}
+		progress := d.progress.DeepCopy()
+		if typeConfig.Spec.ResourceDeletion.Paused && progress.Phase != corev1b1.ResourceDeletionComplete {
+			progress.Phase = corev1b1.ResourceDeletionPaused
+			progress.EstimatedCompletionTime = nil
+		}
+		typeConfig.Status.ResourceDeletion = progress
+		return d.client.UpdateStatus(context.TODO(), typeConfig)
+	})
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedtypeconfig
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestResumedDeletion(t *testing.T) {
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(time.Hour)
+
+	fresh := resumedDeletion(nil, now)
+	if fresh.Phase != corev1b1.ResourceDeletionRunning || !fresh.StartTime.Time.Equal(now) || fresh.Deleted != 0 {
+		t.Errorf("Expected a new deletion starting now, got %+v", fresh)
+	}
+
+	paused := &corev1b1.ResourceDeletionStatus{
+		Phase:     corev1b1.ResourceDeletionPaused,
+		Total:     100,
+		Deleted:   40,
+		Failed:    2,
+		StartTime: metav1.NewTime(start),
+	}
+	resumed := resumedDeletion(paused, now)
+	if resumed.Phase != corev1b1.ResourceDeletionRunning || !resumed.StartTime.Time.Equal(start) || resumed.Deleted != 40 || resumed.Failed != 0 {
+		t.Errorf("Expected the paused deletion to be resumed, got %+v", resumed)
+	}
+
+	completionTime := metav1.NewTime(start.Add(time.Minute))
+	complete := &corev1b1.ResourceDeletionStatus{
+		Phase:          corev1b1.ResourceDeletionComplete,
+		Total:          100,
+		Deleted:        100,
+		StartTime:      metav1.NewTime(start),
+		CompletionTime: &completionTime,
+	}
+	restarted := resumedDeletion(complete, now)
+	if !restarted.StartTime.Time.Equal(now) || restarted.Deleted != 0 || restarted.CompletionTime != nil {
+		t.Errorf("Expected a completed deletion to be restarted, got %+v", restarted)
+	}
+}
+
+func TestEstimateDeletionCompletion(t *testing.T) {
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	progress := &corev1b1.ResourceDeletionStatus{
+		Phase:     corev1b1.ResourceDeletionRunning,
+		Total:     1000,
+		StartTime: metav1.NewTime(start),
+	}
+	if estimate := estimateDeletionCompletion(progress, start.Add(time.Minute)); estimate != nil {
+		t.Errorf("Expected no estimate before any resource is deleted, got %v", estimate)
+	}
+
+	// A quarter of the resources were deleted in 5 minutes, so the
+	// deletion is estimated to complete in another 15 minutes.
+	progress.Deleted = 250
+	estimate := estimateDeletionCompletion(progress, start.Add(5*time.Minute))
+	expected := start.Add(20 * time.Minute)
+	if estimate == nil || !estimate.Time.Equal(expected) {
+		t.Errorf("Expected estimated completion at %v, got %v", expected, estimate)
+	}
+
+	progress.Deleted = 1000
+	if estimate := estimateDeletionCompletion(progress, start.Add(20*time.Minute)); estimate != nil {
+		t.Errorf("Expected no estimate once all resources are deleted, got %v", estimate)
+	}
+}
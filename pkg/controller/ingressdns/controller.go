@@ -92,7 +92,7 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 		smallDelay:              time.Second * 3,
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker("ingressdnscontroller", s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
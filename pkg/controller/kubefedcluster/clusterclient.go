@@ -17,12 +17,15 @@ limitations under the License.
 package kubefedcluster
 
 import (
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextv1b1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -59,17 +62,27 @@ const (
 // particular KubeFedCluster.
 type ClusterClient struct {
 	kubeClient  *kubeclientset.Clientset
+	crdClient   apiextv1b1client.CustomResourceDefinitionsGetter
 	clusterName string
 }
 
 // NewClusterClientSet returns a ClusterClient for the given KubeFedCluster.
 // The kubeClient is used to configure the ClusterClient's internal client
-// with information from a kubeconfig stored in a kubernetes secret.
-func NewClusterClientSet(c *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string, timeout time.Duration) (*ClusterClient, error) {
-	clusterConfig, err := util.BuildClusterConfig(c, client, fedNamespace)
+// with information from a kubeconfig stored in a kubernetes secret. The
+// hostConfig is used to reach the cluster hosting the control plane.
+// The shared transport of the cluster is used if transports is set.
+func NewClusterClientSet(c *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string, hostConfig *restclient.Config,
+	timeout time.Duration, transports *util.ClusterTransportCache) (*ClusterClient, error) {
+	clusterConfig, err := util.BuildClusterConfig(c, client, fedNamespace, hostConfig)
 	if err != nil {
 		return nil, err
 	}
+	if transports != nil {
+		clusterConfig, err = transports.Configure(c, clusterConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
 	clusterConfig.Timeout = timeout
 	var clusterClientSet = ClusterClient{clusterName: c.Name}
 	if clusterConfig != nil {
@@ -77,6 +90,10 @@ func NewClusterClientSet(c *fedv1b1.KubeFedCluster, client generic.Client, fedNa
 		if clusterClientSet.kubeClient == nil {
 			return nil, nil
 		}
+		clusterClientSet.crdClient, err = apiextv1b1client.NewForConfig(restclient.AddUserAgent(clusterConfig, UserAgentName))
+		if err != nil {
+			return nil, err
+		}
 	}
 	return &clusterClientSet, nil
 }
@@ -166,6 +183,83 @@ func (self *ClusterClient) GetClusterZones() ([]string, string, error) {
 	return zones.List(), region, nil
 }
 
+// GetClusterInventory describes the nodes, the installed
+// CustomResourceDefinitions and the served API versions of the cluster.
+func (self *ClusterClient) GetClusterInventory() (*fedv1b1.ClusterInventory, error) {
+	nodes, err := self.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list nodes")
+	}
+	crds, err := self.crdClient.CustomResourceDefinitions().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list CustomResourceDefinitions")
+	}
+
+	inventory := nodeInventory(nodes.Items)
+	for _, crd := range crds.Items {
+		inventory.CRDs = append(inventory.CRDs, crd.Name)
+	}
+	sort.Strings(inventory.CRDs)
+
+	groups, err := self.kubeClient.Discovery().ServerGroups()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve the API groups")
+	}
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			inventory.APIVersions = append(inventory.APIVersions, version.GroupVersion)
+		}
+	}
+	sort.Strings(inventory.APIVersions)
+	inventory.LastRefreshTime = metav1.Now()
+	return inventory, nil
+}
+
+// nodeInventory returns an inventory describing the given nodes.
+func nodeInventory(nodes []corev1.Node) *fedv1b1.ClusterInventory {
+	inventory := &fedv1b1.ClusterInventory{
+		NodeCount: int32(len(nodes)),
+	}
+	kubeletVersions := sets.NewString()
+	allocatable := corev1.ResourceList{}
+	for i, node := range nodes {
+		// As for zones, the region and cloud provider are assumed to
+		// be the same for all nodes in the cluster.
+		if i == 0 {
+			inventory.Region = getRegionNameForNode(node)
+			inventory.CloudProvider = getCloudProviderForNode(node)
+		}
+		if version := node.Status.NodeInfo.KubeletVersion; version != "" {
+			kubeletVersions.Insert(version)
+		}
+		for name, quantity := range node.Status.Allocatable {
+			total, ok := allocatable[name]
+			if !ok {
+				total = resource.Quantity{Format: quantity.Format}
+			}
+			total.Add(quantity)
+			allocatable[name] = total
+		}
+	}
+	if kubeletVersions.Len() > 0 {
+		inventory.KubeletVersions = kubeletVersions.List()
+	}
+	if len(allocatable) > 0 {
+		inventory.Allocatable = allocatable
+	}
+	return inventory
+}
+
+// Find the name of the cloud provider of a Node from the scheme of its
+// provider ID, e.g. 'aws' for 'aws:///us-east-1a/i-0123456789'.
+func getCloudProviderForNode(node corev1.Node) string {
+	index := strings.Index(node.Spec.ProviderID, "://")
+	if index < 0 {
+		return ""
+	}
+	return node.Spec.ProviderID[:index]
+}
+
 // Find the name of the zone in which a Node is running.
 func getZoneNameForNode(node corev1.Node) string {
 	for key, value := range node.Labels {
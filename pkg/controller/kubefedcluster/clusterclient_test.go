@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedcluster
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeInventory(t *testing.T) {
+	node := func(name, providerID, kubeletVersion, cpu string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					LabelZoneRegion: "us-east-1",
+				},
+			},
+			Spec: corev1.NodeSpec{
+				ProviderID: providerID,
+			},
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{
+					KubeletVersion: kubeletVersion,
+				},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse(cpu),
+				},
+			},
+		}
+	}
+	nodes := []corev1.Node{
+		node("node1", "aws:///us-east-1a/i-0123", "v1.17.3", "1500m"),
+		node("node2", "aws:///us-east-1b/i-4567", "v1.16.8", "2"),
+		node("node3", "aws:///us-east-1b/i-8901", "v1.17.3", "500m"),
+	}
+
+	inventory := nodeInventory(nodes)
+	if inventory.NodeCount != 3 {
+		t.Errorf("Expected 3 nodes, got %d", inventory.NodeCount)
+	}
+	expectedVersions := []string{"v1.16.8", "v1.17.3"}
+	if !reflect.DeepEqual(inventory.KubeletVersions, expectedVersions) {
+		t.Errorf("Expected kubelet versions %v, got %v", expectedVersions, inventory.KubeletVersions)
+	}
+	if inventory.CloudProvider != "aws" {
+		t.Errorf("Expected cloud provider %q, got %q", "aws", inventory.CloudProvider)
+	}
+	if inventory.Region != "us-east-1" {
+		t.Errorf("Expected region %q, got %q", "us-east-1", inventory.Region)
+	}
+	cpu := inventory.Allocatable[corev1.ResourceCPU]
+	if cpu.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("Expected 4 allocatable cpus, got %s", cpu.String())
+	}
+
+	empty := nodeInventory(nil)
+	if empty.NodeCount != 0 || empty.KubeletVersions != nil || empty.Allocatable != nil {
+		t.Errorf("Expected an empty inventory for no nodes, got %#v", empty)
+	}
+}
@@ -19,6 +19,7 @@ package kubefedcluster
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
@@ -45,6 +47,10 @@ import (
 	"sigs.k8s.io/kubefed/pkg/metrics"
 )
 
+// clusterInventoryRefreshPeriod is how often the inventory of a ready
+// cluster is refreshed.
+const clusterInventoryRefreshPeriod = 5 * time.Minute
+
 // ClusterData stores cluster client and previous health check probe results of individual cluster.
 type ClusterData struct {
 	// clusterKubeClient is the kube client for the cluster.
@@ -56,6 +62,13 @@ type ClusterData struct {
 	// How many times in a row the probe has returned the same result.
 	resultRun int64
 
+	// lastSyncTime is the time of the last successful probe.
+	lastSyncTime *metav1.Time
+
+	// lastInventoryAttempt is the time the inventory of the cluster
+	// was last retrieved, successfully or not.
+	lastInventoryAttempt time.Time
+
 	// cachedObj holds the last observer object from apiserver
 	cachedObj *fedv1b1.KubeFedCluster
 }
@@ -65,6 +78,9 @@ type ClusterData struct {
 type ClusterController struct {
 	client genericclient.Client
 
+	// kubeClient is used to renew the heartbeat leases of clusters.
+	kubeClient kubeclient.Interface
+
 	// clusterHealthCheckConfig is the configurable parameters for cluster health check
 	clusterHealthCheckConfig *util.ClusterHealthCheckConfig
 
@@ -73,15 +89,38 @@ type ClusterController struct {
 	// clusterDataMap is a mapping of clusterName and the cluster specific details.
 	clusterDataMap map[string]*ClusterData
 
+	// clusterStore is the cache of KubeFedClusters.
+	clusterStore cache.Store
+
 	// clusterController is the cache.Controller where callbacks are registered
 	// for events on KubeFedClusters.
 	clusterController cache.Controller
 
+	// checkQueue holds the names of the clusters whose health is due
+	// to be checked. A cluster is only checked by one worker at a time.
+	checkQueue workqueue.DelayingInterface
+
 	// fedNamespace is the name of the namespace containing
 	// KubeFedCluster resources and their associated secrets.
 	fedNamespace string
 
+	// hostConfig is used to access the cluster hosting the control
+	// plane when it is also a member cluster.
+	hostConfig *restclient.Config
+
 	eventRecorder record.EventRecorder
+
+	// discoveryCache is revalidated when the inventory of a cluster
+	// is refreshed. Nil if discovery is not cached.
+	discoveryCache *util.ClusterDiscoveryCache
+
+	// clusterTransports provides the shared transport of each cluster.
+	// Nil if transports are not shared.
+	clusterTransports *util.ClusterTransportCache
+
+	// schemaCache caches the OpenAPI schema of each cluster. Nil if
+	// schemas are not used.
+	schemaCache *util.ClusterSchemaCache
 }
 
 // StartClusterController starts a new cluster controller.
@@ -106,16 +145,22 @@ func newClusterController(config *util.ControllerConfig, clusterHealthCheckConfi
 		clusterHealthCheckConfig: clusterHealthCheckConfig,
 		clusterDataMap:           make(map[string]*ClusterData),
 		fedNamespace:             config.KubeFedNamespace,
+		hostConfig:               config.KubeConfig,
+		discoveryCache:           config.DiscoveryCache,
+		clusterTransports:        config.ClusterTransports,
+		schemaCache:              config.SchemaCache,
+		checkQueue:               workqueue.NewNamedDelayingQueue("kubefedcluster-health-check"),
 	}
 
 	kubeClient := kubeclient.NewForConfigOrDie(kubeConfig)
+	cc.kubeClient = kubeClient
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
 	recorder := broadcaster.NewRecorder(genscheme.Scheme, corev1.EventSource{Component: fmt.Sprintf("kubefedcluster-controller")})
 	cc.eventRecorder = recorder
 
 	var err error
-	_, cc.clusterController, err = util.NewGenericInformerWithEventHandler(
+	cc.clusterStore, cc.clusterController, err = util.NewGenericInformerWithEventHandler(
 		config.KubeConfig,
 		config.KubeFedNamespace,
 		&fedv1b1.KubeFedCluster{},
@@ -140,6 +185,9 @@ func newClusterController(config *util.ControllerConfig, clusterHealthCheckConfi
 			AddFunc: func(obj interface{}) {
 				castObj := obj.(*fedv1b1.KubeFedCluster)
 				cc.addToClusterSet(castObj)
+				// Spread the initial checks of clusters that are
+				// observed at the same time, e.g. on startup.
+				cc.checkQueue.AddAfter(castObj.Name, cc.jitter())
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				var clusterChanged bool
@@ -171,6 +219,15 @@ func (cc *ClusterController) delFromClusterSet(obj *fedv1b1.KubeFedCluster) {
 	defer cc.mu.Unlock()
 	klog.V(1).Infof("ClusterController observed a cluster deletion: %v", obj.Name)
 	delete(cc.clusterDataMap, obj.Name)
+	if cc.discoveryCache != nil {
+		cc.discoveryCache.Forget(obj.Name)
+	}
+	if cc.clusterTransports != nil {
+		cc.clusterTransports.Forget(obj.Name)
+	}
+	if cc.schemaCache != nil {
+		cc.schemaCache.Forget(obj.Name)
+	}
 }
 
 // addToClusterSet creates a new client for the cluster and stores it in cluster data map.
@@ -185,7 +242,7 @@ func (cc *ClusterController) addToClusterSet(obj *fedv1b1.KubeFedCluster) {
 	klog.V(1).Infof("ClusterController observed a new cluster: %v", obj.Name)
 
 	// create the restclient of cluster
-	restClient, err := NewClusterClientSet(obj, cc.client, cc.fedNamespace, cc.clusterHealthCheckConfig.Timeout)
+	restClient, err := NewClusterClientSet(obj, cc.client, cc.fedNamespace, cc.hostConfig, cc.clusterHealthCheckConfig.Timeout, cc.clusterTransports)
 	if err != nil || restClient == nil {
 		cc.RecordError(obj, "MalformedClusterConfig", errors.Wrap(err, "The configuration for this cluster may be malformed"))
 		return
@@ -197,50 +254,123 @@ func (cc *ClusterController) addToClusterSet(obj *fedv1b1.KubeFedCluster) {
 func (cc *ClusterController) Run(stopChan <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	go cc.clusterController.Run(stopChan)
-	// monitor cluster status periodically, in phase 1 we just get the health state from "/healthz"
-	go wait.Until(func() {
-		if err := cc.updateClusterStatus(); err != nil {
-			klog.Errorf("Error monitoring cluster status: %v", err)
-		}
-	}, cc.clusterHealthCheckConfig.Period, stopChan)
+
+	workers := cc.clusterHealthCheckConfig.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := int64(0); i < workers; i++ {
+		go wait.Until(cc.worker, time.Second, stopChan)
+	}
+	go func() {
+		<-stopChan
+		cc.checkQueue.ShutDown()
+	}()
 }
 
-// updateClusterStatus checks cluster health and updates status of all KubeFedClusters
-func (cc *ClusterController) updateClusterStatus() error {
-	clusters := &fedv1b1.KubeFedClusterList{}
-	err := cc.client.List(context.TODO(), clusters, cc.fedNamespace)
+func (cc *ClusterController) worker() {
+	for cc.processNextCheck() {
+	}
+}
+
+// processNextCheck checks the health of the next cluster that is due.
+// A check that does not complete before the deadline is left to
+// complete in the background so that it no longer occupies the worker.
+func (cc *ClusterController) processNextCheck() bool {
+	item, quit := cc.checkQueue.Get()
+	if quit {
+		return false
+	}
+	clusterName := item.(string)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cc.checkClusterHealth(clusterName)
+	}()
+
+	// A nil channel never receives, so there is no deadline unless
+	// one is configured.
+	var deadline <-chan time.Time
+	if cc.clusterHealthCheckConfig.Deadline > 0 {
+		timer := time.NewTimer(cc.clusterHealthCheckConfig.Deadline)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	select {
+	case <-done:
+		cc.finishCheck(clusterName)
+	case <-deadline:
+		klog.Warningf("Health check of cluster %q did not complete within %v", clusterName, cc.clusterHealthCheckConfig.Deadline)
+		metrics.ClusterHealthCheckDeadlineExceeded(clusterName)
+		go func() {
+			<-done
+			cc.finishCheck(clusterName)
+		}()
+	}
+	return true
+}
+
+// finishCheck schedules the next check of the cluster unless it has
+// been removed.
+func (cc *ClusterController) finishCheck(clusterName string) {
+	cc.checkQueue.Done(clusterName)
+
+	key := util.QualifiedName{Namespace: cc.fedNamespace, Name: clusterName}.String()
+	_, exists, err := cc.clusterStore.GetByKey(key)
 	if err != nil {
-		return err
+		klog.Errorf("Failed to retrieve cluster %q from the store: %v", clusterName, err)
+	}
+	if !exists {
+		return
+	}
+	cc.checkQueue.AddAfter(clusterName, cc.clusterHealthCheckConfig.Period+cc.jitter())
+}
+
+// jitter returns a random delay of up to the configured percentage of
+// the health check period.
+func (cc *ClusterController) jitter() time.Duration {
+	percentage := cc.clusterHealthCheckConfig.JitterPercentage
+	if percentage <= 0 {
+		return 0
 	}
+	return time.Duration(rand.Float64() * float64(percentage) / 100 * float64(cc.clusterHealthCheckConfig.Period))
+}
 
-	var wg sync.WaitGroup
-	for _, obj := range clusters.Items {
+// checkClusterHealth checks the health of the named cluster and updates
+// its status.
+func (cc *ClusterController) checkClusterHealth(clusterName string) {
+	key := util.QualifiedName{Namespace: cc.fedNamespace, Name: clusterName}.String()
+	obj, exists, err := cc.clusterStore.GetByKey(key)
+	if err != nil {
+		klog.Errorf("Failed to retrieve cluster %q from the store: %v", clusterName, err)
+		return
+	}
+	if !exists {
+		return
+	}
+	cluster := obj.(*fedv1b1.KubeFedCluster).DeepCopy()
+
+	cc.mu.RLock()
+	clusterData := cc.clusterDataMap[cluster.Name]
+	cc.mu.RUnlock()
+	if clusterData == nil {
+		// Retry adding cluster client
+		cc.addToClusterSet(cluster)
 		cc.mu.RLock()
-		cluster := obj.DeepCopy()
-		clusterData := cc.clusterDataMap[cluster.Name]
+		clusterData = cc.clusterDataMap[cluster.Name]
 		cc.mu.RUnlock()
 		if clusterData == nil {
-			// Retry adding cluster client
-			cc.addToClusterSet(cluster)
-			cc.mu.RLock()
-			clusterData = cc.clusterDataMap[cluster.Name]
-			cc.mu.RUnlock()
-			if clusterData == nil {
-				klog.Warningf("Failed to retrieve stored data for cluster %s", cluster.Name)
-				continue
-			}
+			klog.Warningf("Failed to retrieve stored data for cluster %s", cluster.Name)
+			return
 		}
-
-		wg.Add(1)
-		go cc.updateIndividualClusterStatus(cluster, clusterData, &wg)
 	}
 
-	wg.Wait()
-	return nil
+	cc.updateIndividualClusterStatus(cluster, clusterData)
 }
 
-func (cc *ClusterController) updateIndividualClusterStatus(cluster *fedv1b1.KubeFedCluster,
-	storedData *ClusterData, wg *sync.WaitGroup) {
+func (cc *ClusterController) updateIndividualClusterStatus(cluster *fedv1b1.KubeFedCluster, storedData *ClusterData) {
 	defer metrics.ClusterHealthStatusDurationFromStart(time.Now())
 
 	clusterClient := storedData.clusterKubeClient
@@ -250,34 +380,84 @@ func (cc *ClusterController) updateIndividualClusterStatus(cluster *fedv1b1.Kube
 		cc.RecordError(cluster, "RetrievingClusterHealthFailed", errors.Wrap(err, "Failed to retrieve health of the cluster"))
 	}
 
-	currentClusterStatus = thresholdAdjustedClusterStatus(currentClusterStatus, storedData, cc.clusterHealthCheckConfig)
+	if util.IsClusterReady(currentClusterStatus) {
+		now := metav1.Now()
+		storedData.lastSyncTime = &now
+	}
+
+	// The APIs of a cluster may have changed while it was not ready,
+	// e.g. due to an upgrade.
+	if util.IsClusterReady(currentClusterStatus) && storedData.clusterStatus != nil && !util.IsClusterReady(storedData.clusterStatus) {
+		if cc.discoveryCache != nil {
+			cc.discoveryCache.Invalidate(cluster.Name)
+		}
+		if cc.schemaCache != nil {
+			cc.schemaCache.Forget(cluster.Name)
+		}
+	}
+
+	failureThreshold := cc.clusterHealthCheckConfig.FailureThresholdFor(cluster)
+	currentClusterStatus = thresholdAdjustedClusterStatus(currentClusterStatus, storedData, failureThreshold, cc.clusterHealthCheckConfig)
 
 	if utilfeature.DefaultFeatureGate.Enabled(features.CrossClusterServiceDiscovery) {
 		currentClusterStatus = cc.updateClusterZonesAndRegion(currentClusterStatus, cluster, clusterClient)
 	}
 
+	currentClusterStatus.Inventory = cc.clusterInventory(currentClusterStatus, cluster, storedData, clusterClient)
+
+	// Backfill progress is maintained by the backfill controller.
+	currentClusterStatus.Backfill = cluster.Status.Backfill
+
+	// Quarantine is maintained by the quarantine monitor and by
+	// kubefedctl.
+	currentClusterStatus.Quarantine = cluster.Status.Quarantine
+
+	if cc.discoveryCache != nil {
+		currentClusterStatus.DiscoveryCache = cc.discoveryCache.Status(cluster.Name)
+	}
+
 	storedData.clusterStatus = currentClusterStatus
+
+	if util.IsClusterReady(currentClusterStatus) {
+		if err := renewLease(cc.kubeClient.CoordinationV1(), cluster, cc.clusterHealthCheckConfig, time.Now()); err != nil {
+			klog.Warningf("Failed to renew the lease of cluster %q: %v", cluster.Name, err)
+		}
+	}
+
+	// Heartbeats are recorded by the lease, so the status only needs
+	// to be written when it changes.
+	if !clusterStatusChanged(&cluster.Status, currentClusterStatus) {
+		return
+	}
+	// Preserve the last sync time recorded before a restart of the
+	// controller until the cluster is successfully probed.
+	currentClusterStatus.LastSyncTime = cluster.Status.LastSyncTime
+	if storedData.lastSyncTime != nil {
+		currentClusterStatus.LastSyncTime = storedData.lastSyncTime.DeepCopy()
+	}
 	cluster.Status = *currentClusterStatus
 	if err := cc.client.UpdateStatus(context.TODO(), cluster); err != nil {
 		klog.Warningf("Failed to update the status of cluster %q: %v", cluster.Name, err)
 	}
-
-	wg.Done()
 }
 
 func (cc *ClusterController) RecordError(cluster runtime.Object, errorCode string, err error) {
 	cc.eventRecorder.Eventf(cluster, corev1.EventTypeWarning, errorCode, err.Error())
 }
 
+// thresholdAdjustedClusterStatus returns the given status of a probe
+// unless the number of consecutive probes with that result is below the
+// success threshold or the given failure threshold, in which case the
+// previous status is returned.
 func thresholdAdjustedClusterStatus(clusterStatus *fedv1b1.KubeFedClusterStatus, storedData *ClusterData,
-	clusterHealthCheckConfig *util.ClusterHealthCheckConfig) *fedv1b1.KubeFedClusterStatus {
+	failureThreshold int64, clusterHealthCheckConfig *util.ClusterHealthCheckConfig) *fedv1b1.KubeFedClusterStatus {
 
 	if storedData.clusterStatus == nil {
 		storedData.resultRun = 1
 		return clusterStatus
 	}
 
-	threshold := clusterHealthCheckConfig.FailureThreshold
+	threshold := failureThreshold
 	if util.IsClusterReady(clusterStatus) {
 		threshold = clusterHealthCheckConfig.SuccessThreshold
 	}
@@ -331,10 +511,47 @@ func (cc *ClusterController) updateClusterZonesAndRegion(clusterStatus *fedv1b1.
 	return clusterStatus
 }
 
+// clusterInventory returns the inventory to record for the cluster. The
+// inventory is refreshed at most every clusterInventoryRefreshPeriod
+// while the cluster is ready. Otherwise the previously recorded
+// inventory is returned.
+func (cc *ClusterController) clusterInventory(clusterStatus *fedv1b1.KubeFedClusterStatus, cluster *fedv1b1.KubeFedCluster,
+	storedData *ClusterData, clusterClient *ClusterClient) *fedv1b1.ClusterInventory {
+
+	inventory := cluster.Status.Inventory
+	if !util.IsClusterReady(clusterStatus) || time.Since(storedData.lastInventoryAttempt) < clusterInventoryRefreshPeriod {
+		return inventory
+	}
+	storedData.lastInventoryAttempt = time.Now()
+
+	refreshed, err := clusterClient.GetClusterInventory()
+	if err != nil {
+		cc.RecordError(cluster, "RetrievingClusterInventoryFailed", errors.Wrap(err, "Failed to retrieve the inventory of the cluster"))
+		return inventory
+	}
+	if cc.discoveryCache != nil && cc.discoveryCache.Revalidate(cluster.Name, refreshed) {
+		klog.V(2).Infof("Invalidated the cached discovery of cluster %q after a change of its APIs", cluster.Name)
+	}
+	return refreshed
+}
+
 func clusterStatusEqual(newClusterStatus, oldClusterStatus *fedv1b1.KubeFedClusterStatus) bool {
 	return util.IsClusterReady(newClusterStatus) == util.IsClusterReady(oldClusterStatus)
 }
 
+// clusterStatusChanged returns whether the cluster status differs from
+// the previously recorded status other than by the time of the probe
+// or of the last sync.
+func clusterStatusChanged(oldClusterStatus, newClusterStatus *fedv1b1.KubeFedClusterStatus) bool {
+	oldCopy := oldClusterStatus.DeepCopy()
+	newCopy := newClusterStatus.DeepCopy()
+	setProbeTime(oldCopy, metav1.Time{})
+	setProbeTime(newCopy, metav1.Time{})
+	oldCopy.LastSyncTime = nil
+	newCopy.LastSyncTime = nil
+	return !equality.Semantic.DeepEqual(oldCopy, newCopy)
+}
+
 func setProbeTime(clusterStatus *fedv1b1.KubeFedClusterStatus, probeTime metav1.Time) {
 	for i := 0; i < len(clusterStatus.Conditions); i++ {
 		clusterStatus.Conditions[i].LastProbeTime = probeTime
@@ -91,7 +91,7 @@ func TestThresholdCheckedClusterStatus(t *testing.T) {
 
 	for testName, tc := range testCases {
 		t.Run(testName, func(t *testing.T) {
-			newClusterStatus := thresholdAdjustedClusterStatus(tc.clusterStatus, tc.storedClusterData, config)
+			newClusterStatus := thresholdAdjustedClusterStatus(tc.clusterStatus, tc.storedClusterData, config.FailureThreshold, config)
 			if !reflect.DeepEqual(tc.expectedClusterStatus, newClusterStatus) {
 				t.Fatalf("Unexpected state, expected: %v, got:%v", tc.expectedClusterStatus, newClusterStatus)
 			}
@@ -104,6 +104,84 @@ func TestThresholdCheckedClusterStatus(t *testing.T) {
 
 }
 
+func TestEdgeClusterFailureThreshold(t *testing.T) {
+	epoch := metav1.Now()
+	t1 := metav1.Time{Time: epoch.Add(1 * time.Second)}
+	t2 := metav1.Time{Time: epoch.Add(2 * time.Second)}
+
+	config := &util.ClusterHealthCheckConfig{
+		Period:               10 * time.Second,
+		FailureThreshold:     3,
+		EdgeFailureThreshold: 30,
+		SuccessThreshold:     1,
+		Timeout:              3 * time.Second,
+	}
+	cluster := &fedv1b1.KubeFedCluster{
+		Spec: fedv1b1.KubeFedClusterSpec{
+			ConnectivityProfile: fedv1b1.ConnectivityProfileEdge,
+		},
+	}
+
+	failureThreshold := config.FailureThresholdFor(cluster)
+	if failureThreshold != config.EdgeFailureThreshold {
+		t.Fatalf("Unexpected failure threshold, expected: %v, got: %v", config.EdgeFailureThreshold, failureThreshold)
+	}
+
+	// A failure run that would exceed the standard failure threshold
+	// leaves an edge cluster ready.
+	storedData := &ClusterData{
+		clusterStatus: clusterStatus(corev1.ConditionTrue, t1, t1),
+		resultRun:     config.FailureThreshold,
+	}
+	newClusterStatus := thresholdAdjustedClusterStatus(clusterStatus(corev1.ConditionFalse, t2, t2), storedData, failureThreshold, config)
+	expectedClusterStatus := clusterStatus(corev1.ConditionTrue, t2, t1)
+	if !reflect.DeepEqual(expectedClusterStatus, newClusterStatus) {
+		t.Fatalf("Unexpected state, expected: %v, got:%v", expectedClusterStatus, newClusterStatus)
+	}
+}
+
+func TestClusterStatusChanged(t *testing.T) {
+	epoch := metav1.Now()
+	t1 := metav1.Time{Time: epoch.Add(1 * time.Second)}
+	t2 := metav1.Time{Time: epoch.Add(2 * time.Second)}
+
+	testCases := map[string]struct {
+		oldClusterStatus *fedv1b1.KubeFedClusterStatus
+		newClusterStatus *fedv1b1.KubeFedClusterStatus
+		expectedChanged  bool
+	}{
+		"OnlyProbeTimeChanged": {
+			oldClusterStatus: clusterStatus(corev1.ConditionTrue, t1, t1),
+			newClusterStatus: clusterStatus(corev1.ConditionTrue, t2, t1),
+			expectedChanged:  false,
+		},
+		"OnlyLastSyncTimeChanged": {
+			oldClusterStatus: clusterStatus(corev1.ConditionTrue, t1, t1),
+			newClusterStatus: withLastSyncTime(clusterStatus(corev1.ConditionTrue, t2, t1), t2),
+			expectedChanged:  false,
+		},
+		"ConditionTransitioned": {
+			oldClusterStatus: clusterStatus(corev1.ConditionTrue, t1, t1),
+			newClusterStatus: clusterStatus(corev1.ConditionFalse, t2, t2),
+			expectedChanged:  true,
+		},
+		"NoPreviousStatus": {
+			oldClusterStatus: &fedv1b1.KubeFedClusterStatus{},
+			newClusterStatus: clusterStatus(corev1.ConditionTrue, t1, t1),
+			expectedChanged:  true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			changed := clusterStatusChanged(tc.oldClusterStatus, tc.newClusterStatus)
+			if changed != tc.expectedChanged {
+				t.Fatalf("Unexpected result, expected: %v, got: %v", tc.expectedChanged, changed)
+			}
+		})
+	}
+}
+
 func clusterStatus(status corev1.ConditionStatus, lastProbeTime, lastTransitionTime metav1.Time) *fedv1b1.KubeFedClusterStatus {
 	return &fedv1b1.KubeFedClusterStatus{
 		Conditions: []fedv1b1.ClusterCondition{{
@@ -114,3 +192,8 @@ func clusterStatus(status corev1.ConditionStatus, lastProbeTime, lastTransitionT
 		}},
 	}
 }
+
+func withLastSyncTime(clusterStatus *fedv1b1.KubeFedClusterStatus, lastSyncTime metav1.Time) *fedv1b1.KubeFedClusterStatus {
+	clusterStatus.LastSyncTime = &lastSyncTime
+	return clusterStatus
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedcluster
+
+import (
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationclientv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// LeaseHolderIdentity identifies the cluster controller as the holder
+// of the heartbeat leases of KubeFedClusters.
+const LeaseHolderIdentity = "kubefed-cluster-controller"
+
+// renewLease records a heartbeat for a cluster by renewing the Lease of
+// the same name in the KubeFed namespace, creating it if necessary.
+// Heartbeats are recorded in a Lease rather than the status of the
+// KubeFedCluster so that the status only needs to be written when the
+// health of the cluster changes.
+func renewLease(client coordinationclientv1.LeasesGetter, cluster *fedv1b1.KubeFedCluster,
+	config *util.ClusterHealthCheckConfig, renewTime time.Time) error {
+	leases := client.Leases(cluster.Namespace)
+	holderIdentity := LeaseHolderIdentity
+	// A lease that has not been renewed for this long indicates that
+	// the cluster would be considered unhealthy.
+	durationSeconds := int32((config.Period * time.Duration(config.FailureThresholdFor(cluster))).Seconds())
+	microRenewTime := metav1.NewMicroTime(renewTime)
+
+	lease, err := leases.Get(cluster.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      cluster.Name,
+				// Ensure the lease is garbage collected with the cluster.
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(cluster, fedv1b1.SchemeGroupVersion.WithKind("KubeFedCluster")),
+				},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holderIdentity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &microRenewTime,
+				RenewTime:            &microRenewTime,
+			},
+		}
+		_, err = leases.Create(lease)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lease.Spec.HolderIdentity = &holderIdentity
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &microRenewTime
+	_, err = leases.Update(lease)
+	return err
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedcluster
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestRenewLease(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	cluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-federation-system",
+			Name:      "cluster1",
+		},
+	}
+	config := &util.ClusterHealthCheckConfig{
+		Period:           10 * time.Second,
+		FailureThreshold: 3,
+	}
+
+	epoch := time.Now()
+	for i := 0; i < 2; i++ {
+		renewTime := epoch.Add(time.Duration(i) * config.Period)
+		if err := renewLease(client.CoordinationV1(), cluster, config, renewTime); err != nil {
+			t.Fatalf("Unexpected error renewing lease: %v", err)
+		}
+
+		lease, err := client.CoordinationV1().Leases(cluster.Namespace).Get(cluster.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error retrieving lease: %v", err)
+		}
+		if !lease.Spec.RenewTime.Time.Equal(renewTime) {
+			t.Fatalf("Unexpected renew time, expected: %v, got: %v", renewTime, lease.Spec.RenewTime.Time)
+		}
+		if !lease.Spec.AcquireTime.Time.Equal(epoch) {
+			t.Fatalf("Unexpected acquire time, expected: %v, got: %v", epoch, lease.Spec.AcquireTime.Time)
+		}
+		if *lease.Spec.LeaseDurationSeconds != 30 {
+			t.Fatalf("Unexpected lease duration, expected: 30, got: %v", *lease.Spec.LeaseDurationSeconds)
+		}
+	}
+}
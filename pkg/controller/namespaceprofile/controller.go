@@ -0,0 +1,394 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaceprofile
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// ProfileLabel assigns a NamespaceProfile to a FederatedNamespace.
+	ProfileLabel = "kubefed.io/namespace-profile"
+
+	// SourceLabel identifies the NamespaceProfile a federated
+	// resource was created for.
+	SourceLabel = "kubefed.io/namespace-profile-source"
+
+	// resyncPeriod is how often every namespace profile is
+	// reconciled to pick up new and relabeled federated namespaces.
+	resyncPeriod = time.Minute
+)
+
+// Controller propagates the resources of a NamespaceProfile to every
+// federated namespace labeled with the name of the profile. Each
+// resource is created as a federated resource in the namespace that
+// follows the placement of the federated namespace.
+type Controller struct {
+	client genericclient.Client
+
+	// fedNamespace is the namespace containing the namespace
+	// profiles and the FederatedTypeConfigs.
+	fedNamespace string
+	// targetNamespace is the namespace containing the federated
+	// namespaces, or all namespaces if empty.
+	targetNamespace string
+
+	// Store for the namespace profiles
+	store cache.Store
+	// Informer for the namespace profiles
+	controller cache.Controller
+
+	// resourceClients holds the client for each federated type.
+	resourceClients *util.ResourceClientCache
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller for propagating namespace
+// profiles.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting namespace profile controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to propagate namespace
+// profiles.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "NamespaceProfiles"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	genericclient, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:          genericclient,
+		fedNamespace:    config.KubeFedNamespace,
+		targetNamespace: config.TargetNamespace,
+		resourceClients: util.NewResourceClientCache(kubeConfig),
+	}
+
+	c.worker = util.NewReconcileWorker("namespaceprofilecontroller", c.reconcile, util.WorkerTiming{})
+
+	c.store, c.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.NamespaceProfile{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+
+	// Federated namespaces and type configs are not watched, so
+	// namespace profiles are periodically reconciled.
+	go wait.Until(c.enqueueAll, resyncPeriod, stopChan)
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.store.List() {
+		c.worker.EnqueueObject(obj.(runtime.Object))
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("namespaceprofilecontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile namespace profile for %q", key)
+
+	var profile *fedv1b1.NamespaceProfile
+	cachedObj, exist, err := c.store.GetByKey(key)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query namespace profile store for %q", key))
+		return util.StatusError
+	}
+	if exist {
+		profile = cachedObj.(*fedv1b1.NamespaceProfile)
+		if profile.DeletionTimestamp != nil {
+			profile = nil
+		}
+	}
+
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	if err := c.client.List(context.TODO(), typeConfigs, c.fedNamespace); err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to list FederatedTypeConfigs"))
+		return util.StatusError
+	}
+	namespaceClient, federatedTypes, err := c.federatedTypes(typeConfigs.Items)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return util.StatusError
+	}
+	if namespaceClient == nil {
+		klog.V(2).Infof("Namespaces are not enabled for propagation, not propagating namespace profile %q", key)
+		return util.StatusAllOK
+	}
+
+	result := util.StatusAllOK
+
+	// Determine the federated resources of the profile, keyed by
+	// federated kind and name.
+	desiredResources := make(map[string]map[string]map[string]interface{})
+	if profile != nil {
+		for i, raw := range profile.Spec.Resources {
+			obj, err := profileResource(raw)
+			if err != nil {
+				utilruntime.HandleError(errors.Wrapf(err, "Invalid resource %d of namespace profile %q", i, key))
+				result = util.StatusError
+				continue
+			}
+			targetKind := obj.GroupVersionKind().GroupKind()
+			federatedType, ok := federatedTypes[targetKind]
+			if !ok {
+				utilruntime.HandleError(errors.Errorf("Resource %s %q of namespace profile %q is not of a namespaced type enabled for propagation", obj.GetKind(), obj.GetName(), key))
+				result = util.StatusError
+				continue
+			}
+			kind := federatedType.Kind
+			if desiredResources[kind] == nil {
+				desiredResources[kind] = make(map[string]map[string]interface{})
+			}
+			desiredResources[kind][obj.GetName()] = federatedResourceSpec(obj)
+		}
+	}
+
+	// Determine the namespaces the profile is propagated to.
+	namespaces := make(map[string]bool)
+	if profile != nil {
+		selector := labels.SelectorFromSet(labels.Set{ProfileLabel: qualifiedName.Name})
+		fedNamespaces, err := namespaceClient.Resources(c.targetNamespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			utilruntime.HandleError(errors.Wrap(err, "Failed to list FederatedNamespaces"))
+			return util.StatusError
+		}
+		for _, fedNamespace := range fedNamespaces.Items {
+			if fedNamespace.GetDeletionTimestamp() == nil && fedNamespace.GetNamespace() != c.fedNamespace {
+				namespaces[fedNamespace.GetNamespace()] = true
+			}
+		}
+	}
+
+	// Remove the resources of the profile that are no longer part of
+	// the profile or whose namespace no longer uses the profile.
+	selector := labels.SelectorFromSet(labels.Set{SourceLabel: qualifiedName.Name})
+	for _, federatedType := range federatedTypes {
+		client, err := c.resourceClients.Get(federatedType)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to create client for %s", federatedType.Kind))
+			result = util.StatusError
+			continue
+		}
+		resources, err := client.Resources(c.targetNamespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to list %s for namespace profile %q", federatedType.Kind, key))
+			result = util.StatusError
+			continue
+		}
+		for _, resource := range resources.Items {
+			if namespaces[resource.GetNamespace()] && desiredResources[federatedType.Kind][resource.GetName()] != nil {
+				continue
+			}
+			klog.V(2).Infof("Deleting %s %s/%s of namespace profile %q", federatedType.Kind, resource.GetNamespace(), resource.GetName(), key)
+			err := client.Resources(resource.GetNamespace()).Delete(resource.GetName(), &metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				utilruntime.HandleError(errors.Wrapf(err, "Failed to delete %s %s/%s", federatedType.Kind, resource.GetNamespace(), resource.GetName()))
+				result = util.StatusError
+			}
+		}
+	}
+
+	for _, federatedType := range federatedTypes {
+		specs := desiredResources[federatedType.Kind]
+		if len(specs) == 0 {
+			continue
+		}
+		client, err := c.resourceClients.Get(federatedType)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to create client for %s", federatedType.Kind))
+			result = util.StatusError
+			continue
+		}
+		for namespace := range namespaces {
+			for name, spec := range specs {
+				err := c.ensureResource(client, federatedType, namespace, name, qualifiedName.Name, spec)
+				if err != nil {
+					utilruntime.HandleError(errors.Wrapf(err, "Failed to propagate %s %q of namespace profile %q to namespace %q", federatedType.Kind, name, key, namespace))
+					result = util.StatusError
+				}
+			}
+		}
+	}
+	return result
+}
+
+// ensureResource creates or updates a federated resource of a
+// namespace profile in the given namespace. A federated resource of
+// the same name that was not created for the profile is left alone.
+func (c *Controller) ensureResource(client util.ResourceClient, apiResource metav1.APIResource, namespace, name, profileName string, spec map[string]interface{}) error {
+	obj, err := client.Resources(namespace).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		resource := &unstructured.Unstructured{Object: map[string]interface{}{
+			util.SpecField: spec,
+		}}
+		resource.SetAPIVersion(schema.GroupVersion{Group: apiResource.Group, Version: apiResource.Version}.String())
+		resource.SetKind(apiResource.Kind)
+		resource.SetNamespace(namespace)
+		resource.SetName(name)
+		resource.SetLabels(map[string]string{SourceLabel: profileName})
+		klog.V(2).Infof("Creating %s %s/%s of namespace profile %q", apiResource.Kind, namespace, name, profileName)
+		_, err = client.Resources(namespace).Create(resource, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if obj.GetLabels()[SourceLabel] != profileName {
+		klog.V(2).Infof("%s %s/%s was not created for namespace profile %q, leaving it alone", apiResource.Kind, namespace, name, profileName)
+		return nil
+	}
+	if reflect.DeepEqual(obj.Object[util.SpecField], spec) {
+		return nil
+	}
+	obj.Object[util.SpecField] = spec
+	klog.V(2).Infof("Updating %s %s/%s of namespace profile %q", apiResource.Kind, namespace, name, profileName)
+	_, err = client.Resources(namespace).Update(obj, metav1.UpdateOptions{})
+	return err
+}
+
+// federatedTypes returns the client for the federated type of
+// namespaces, or nil if namespaces are not configured, and the
+// federated types of the namespaced types enabled for propagation
+// keyed by target group and kind.
+func (c *Controller) federatedTypes(typeConfigs []fedv1b1.FederatedTypeConfig) (util.ResourceClient, map[schema.GroupKind]metav1.APIResource, error) {
+	var namespaceClient util.ResourceClient
+	federatedTypes := make(map[schema.GroupKind]metav1.APIResource)
+	for i := range typeConfigs {
+		typeConfig := &typeConfigs[i]
+		targetType := typeConfig.GetTargetType()
+		if targetType.Kind == util.NamespaceKind {
+			var err error
+			namespaceClient, err = c.resourceClients.Get(typeConfig.GetFederatedType())
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "Failed to create client for %s", typeConfig.GetFederatedType().Kind)
+			}
+			continue
+		}
+		if !typeConfig.GetNamespaced() || !typeConfig.GetPropagationEnabled() {
+			continue
+		}
+		federatedTypes[schema.GroupKind{Group: targetType.Group, Kind: targetType.Kind}] = typeConfig.GetFederatedType()
+	}
+	return namespaceClient, federatedTypes, nil
+}
+
+// profileResource returns the given resource of a namespace profile
+// as an unstructured object.
+func profileResource(raw runtime.RawExtension) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+		return nil, err
+	}
+	if obj.GetName() == "" {
+		return nil, errors.New("metadata.name is required")
+	}
+	return obj, nil
+}
+
+// federatedResourceSpec returns the spec of the federated resource
+// propagating the given resource of a namespace profile. The template
+// retains the labels and annotations of the resource.
+func federatedResourceSpec(obj *unstructured.Unstructured) map[string]interface{} {
+	template := make(map[string]interface{})
+	for field, value := range obj.Object {
+		switch field {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		template[field] = runtime.DeepCopyJSONValue(value)
+	}
+	metadata := make(map[string]interface{})
+	if objLabels := obj.GetLabels(); len(objLabels) > 0 {
+		metadata["labels"] = stringMap(objLabels)
+	}
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		metadata["annotations"] = stringMap(annotations)
+	}
+	if len(metadata) > 0 {
+		template["metadata"] = metadata
+	}
+	return map[string]interface{}{
+		util.TemplateField: template,
+		// The placement of a federated namespace limits the clusters
+		// that resources in the namespace are propagated to.
+		util.PlacementField: map[string]interface{}{
+			util.ClusterSelectorField: map[string]interface{}{},
+		},
+	}
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
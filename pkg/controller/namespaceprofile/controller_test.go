@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaceprofile
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestFederatedResourceSpec(t *testing.T) {
+	raw := runtime.RawExtension{Raw: []byte(`{
+		"apiVersion": "v1",
+		"kind": "ResourceQuota",
+		"metadata": {
+			"name": "quota",
+			"namespace": "ignored",
+			"labels": {"team": "a"},
+			"resourceVersion": "1"
+		},
+		"spec": {"hard": {"pods": "10"}},
+		"status": {"used": {"pods": "1"}}
+	}`)}
+	obj, err := profileResource(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if obj.GetName() != "quota" {
+		t.Fatalf("Expected name %q, got %q", "quota", obj.GetName())
+	}
+
+	expected := map[string]interface{}{
+		util.TemplateField: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{"team": "a"},
+			},
+			"spec": map[string]interface{}{
+				"hard": map[string]interface{}{"pods": "10"},
+			},
+		},
+		util.PlacementField: map[string]interface{}{
+			util.ClusterSelectorField: map[string]interface{}{},
+		},
+	}
+	if spec := federatedResourceSpec(obj); !reflect.DeepEqual(spec, expected) {
+		t.Errorf("Expected spec %v, got %v", expected, spec)
+	}
+}
+
+func TestProfileResourceRequiresName(t *testing.T) {
+	raw := runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "LimitRange", "metadata": {}}`)}
+	if _, err := profileResource(raw); err == nil {
+		t.Errorf("Expected an error for a resource without a name")
+	}
+}
@@ -0,0 +1,423 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaceprojection
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// SourceLabel identifies the namespace of the federated resource
+	// that a projected federated resource of the same name was
+	// created for.
+	SourceLabel = "kubefed.io/projection-source"
+
+	namespacesField        = "namespaces"
+	namespaceSelectorField = "namespaceSelector"
+)
+
+// typeInformer is the informer for the resources of a federated type
+// whose resources are projected.
+type typeInformer struct {
+	apiResource metav1.APIResource
+	client      util.ResourceClient
+	store       cache.Store
+	controller  cache.Controller
+	stopChan    chan struct{}
+}
+
+// Controller projects each federated resource whose placement lists
+// or selects namespaces into those namespaces of the host cluster. A
+// projection is a federated resource of the same name and spec that is
+// propagated like any other, so that config or secrets needed by every
+// tenant namespace can be maintained as a single federated resource.
+// Projections are created as federated resources rather than resolved
+// when the resource is propagated since the sync controller records
+// the versions, status and deletion of a single object per cluster.
+type Controller struct {
+	// fedNamespace is the namespace containing the
+	// FederatedTypeConfigs.
+	fedNamespace string
+
+	// Store and informer for the FederatedTypeConfigs
+	typeConfigStore      cache.Store
+	typeConfigController cache.Controller
+
+	// Store and informer for the namespaces
+	namespaceStore      cache.Store
+	namespaceController cache.Controller
+
+	// resourceClients holds the client for each federated type.
+	resourceClients *util.ResourceClientCache
+
+	// typeInformers holds the informer for each federated type whose
+	// resources are projected, keyed by the qualified name of its
+	// FederatedTypeConfig.
+	typeInformers map[util.QualifiedName]*typeInformer
+	lock          sync.Mutex
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller projecting federated
+// resources into namespaces.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting namespace projection controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to project federated
+// resources into namespaces.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "NamespaceProjection"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+
+	c := &Controller{
+		fedNamespace:    config.KubeFedNamespace,
+		resourceClients: util.NewResourceClientCache(kubeConfig),
+		typeInformers:   make(map[util.QualifiedName]*typeInformer),
+	}
+
+	// The resources of each federated type are reconciled together,
+	// keyed by the qualified name of the FederatedTypeConfig.
+	c.worker = util.NewReconcileWorker("namespaceprojectioncontroller", c.reconcile, util.WorkerTiming{})
+
+	var err error
+	c.typeConfigStore, c.typeConfigController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.FederatedTypeConfig{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// A new or relabeled namespace may be selected by the resources
+	// of any type.
+	c.namespaceStore, c.namespaceController, err = util.NewGenericInformer(
+		kubeConfig,
+		metav1.NamespaceAll,
+		&corev1.Namespace{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) {
+			c.enqueueAll()
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.typeConfigController.Run(stopChan)
+	go c.namespaceController.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.typeConfigController.HasSynced, c.namespaceController.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for caches to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+
+	// Ensure the informers of federated types are stopped when the
+	// stop channel closes
+	go func() {
+		<-stopChan
+		c.shutDown()
+	}()
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.typeConfigStore.List() {
+		c.worker.EnqueueObject(obj.(pkgruntime.Object))
+	}
+}
+
+// reconcile projects the federated resources of the type configured by
+// the named FederatedTypeConfig, and removes the projections whose
+// resource no longer projects into their namespace.
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("namespaceprojectioncontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile namespace projection for %q", key)
+
+	cachedObj, exist, err := c.typeConfigStore.GetByKey(key)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query FederatedTypeConfig store for %q", key))
+		return util.StatusError
+	}
+	if !exist {
+		c.stopTypeInformer(qualifiedName)
+		return util.StatusAllOK
+	}
+	typeConfig := cachedObj.(*fedv1b1.FederatedTypeConfig)
+	if typeConfig.DeletionTimestamp != nil || typeConfig.IsNamespace() || !typeConfig.GetNamespaced() || !typeConfig.GetPropagationEnabled() {
+		c.stopTypeInformer(qualifiedName)
+		return util.StatusAllOK
+	}
+
+	informer, err := c.ensureTypeInformer(qualifiedName, typeConfig.GetFederatedType())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return util.StatusError
+	}
+	if !informer.controller.HasSynced() {
+		return util.StatusNeedsRecheck
+	}
+	return c.reconcileType(informer)
+}
+
+// ensureTypeInformer returns the informer for the given federated
+// type, starting it if it is not running or was started for a
+// different API resource.
+func (c *Controller) ensureTypeInformer(qualifiedName util.QualifiedName, apiResource metav1.APIResource) (*typeInformer, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if informer, ok := c.typeInformers[qualifiedName]; ok {
+		if reflect.DeepEqual(informer.apiResource, apiResource) {
+			return informer, nil
+		}
+		close(informer.stopChan)
+		delete(c.typeInformers, qualifiedName)
+	}
+
+	client, err := c.resourceClients.Get(apiResource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create client for %s", apiResource.Kind)
+	}
+	informer := &typeInformer{
+		apiResource: apiResource,
+		client:      client,
+		stopChan:    make(chan struct{}),
+	}
+	informer.store, informer.controller = util.NewResourceInformer(
+		client,
+		metav1.NamespaceAll,
+		&apiResource,
+		func(pkgruntime.Object) {
+			c.worker.Enqueue(qualifiedName)
+		},
+	)
+	go informer.controller.Run(informer.stopChan)
+	klog.V(2).Infof("Started watching %s for projection", apiResource.Kind)
+	c.typeInformers[qualifiedName] = informer
+	return informer, nil
+}
+
+// stopTypeInformer stops the informer for the federated type
+// configured by the named FederatedTypeConfig, if it is running.
+func (c *Controller) stopTypeInformer(qualifiedName util.QualifiedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	informer, ok := c.typeInformers[qualifiedName]
+	if !ok {
+		return
+	}
+	klog.V(2).Infof("Stopped watching %s for projection", informer.apiResource.Kind)
+	close(informer.stopChan)
+	delete(c.typeInformers, qualifiedName)
+}
+
+func (c *Controller) shutDown() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for qualifiedName, informer := range c.typeInformers {
+		close(informer.stopChan)
+		delete(c.typeInformers, qualifiedName)
+	}
+}
+
+func (c *Controller) reconcileType(informer *typeInformer) util.ReconciliationStatus {
+	kind := informer.apiResource.Kind
+
+	namespaces := []*corev1.Namespace{}
+	for _, obj := range c.namespaceStore.List() {
+		namespaces = append(namespaces, obj.(*corev1.Namespace))
+	}
+	resources := informer.store.List()
+
+	result := util.StatusAllOK
+
+	// The namespaces each resource is projected into, keyed by the
+	// qualified name of the resource.
+	projections := make(map[util.QualifiedName]sets.String)
+	for _, obj := range resources {
+		resource := obj.(*unstructured.Unstructured)
+		if _, ok := resource.GetLabels()[SourceLabel]; ok || resource.GetDeletionTimestamp() != nil {
+			continue
+		}
+		qualifiedName := util.NewQualifiedName(resource)
+		targetNamespaces, err := projectedNamespaces(resource, namespaces, c.fedNamespace)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to determine the namespaces %s %q is projected into", kind, qualifiedName))
+			result = util.StatusError
+			continue
+		}
+		if targetNamespaces.Len() == 0 {
+			continue
+		}
+		projections[qualifiedName] = targetNamespaces
+		spec := projectionSpec(resource)
+		for _, namespace := range targetNamespaces.List() {
+			if err := c.ensureProjection(informer, namespace, qualifiedName, spec); err != nil {
+				utilruntime.HandleError(errors.Wrapf(err, "Failed to project %s %q into namespace %q", kind, qualifiedName, namespace))
+				result = util.StatusError
+			}
+		}
+	}
+
+	// Remove the projections whose resource no longer exists or no
+	// longer projects into their namespace.
+	for _, obj := range resources {
+		resource := obj.(*unstructured.Unstructured)
+		sourceNamespace, ok := resource.GetLabels()[SourceLabel]
+		if !ok || resource.GetDeletionTimestamp() != nil {
+			continue
+		}
+		sourceName := util.QualifiedName{Namespace: sourceNamespace, Name: resource.GetName()}
+		if projections[sourceName].Has(resource.GetNamespace()) {
+			continue
+		}
+		klog.V(2).Infof("Deleting %s %s/%s projected from namespace %q", kind, resource.GetNamespace(), resource.GetName(), sourceNamespace)
+		err := informer.client.Resources(resource.GetNamespace()).Delete(resource.GetName(), &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to delete %s %s/%s", kind, resource.GetNamespace(), resource.GetName()))
+			result = util.StatusError
+		}
+	}
+
+	return result
+}
+
+// ensureProjection creates or updates the projection of the named
+// federated resource in the given namespace. A federated resource of
+// the same name that is not a projection of the resource is left alone.
+func (c *Controller) ensureProjection(informer *typeInformer, namespace string, sourceName util.QualifiedName, spec map[string]interface{}) error {
+	apiResource := informer.apiResource
+	client := informer.client
+	projectionName := util.QualifiedName{Namespace: namespace, Name: sourceName.Name}
+	cachedObj, exist, err := informer.store.GetByKey(projectionName.String())
+	if err != nil {
+		return err
+	}
+	if !exist {
+		projection := &unstructured.Unstructured{Object: map[string]interface{}{
+			util.SpecField: spec,
+		}}
+		projection.SetAPIVersion(schema.GroupVersion{Group: apiResource.Group, Version: apiResource.Version}.String())
+		projection.SetKind(apiResource.Kind)
+		projection.SetNamespace(namespace)
+		projection.SetName(sourceName.Name)
+		projection.SetLabels(map[string]string{SourceLabel: sourceName.Namespace})
+		klog.V(2).Infof("Projecting %s %q into namespace %q", apiResource.Kind, sourceName, namespace)
+		_, err = client.Resources(namespace).Create(projection, metav1.CreateOptions{})
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			// The namespace does not exist or is being deleted.
+			klog.V(2).Infof("Not projecting %s %q into namespace %q: %v", apiResource.Kind, sourceName, namespace, err)
+			return nil
+		}
+		return err
+	}
+
+	obj := cachedObj.(*unstructured.Unstructured)
+	if obj.GetLabels()[SourceLabel] != sourceName.Namespace {
+		klog.V(2).Infof("%s %q is not a projection of %q, leaving it alone", apiResource.Kind, projectionName, sourceName)
+		return nil
+	}
+	if reflect.DeepEqual(obj.Object[util.SpecField], spec) {
+		return nil
+	}
+	obj = obj.DeepCopy()
+	obj.Object[util.SpecField] = spec
+	klog.V(2).Infof("Updating the projection of %s %q in namespace %q", apiResource.Kind, sourceName, namespace)
+	_, err = client.Resources(namespace).Update(obj, metav1.UpdateOptions{})
+	return err
+}
+
+// projectedNamespaces returns the namespaces that the placement of the
+// given federated resource lists or selects, other than its own
+// namespace and the KubeFed system namespace. Selected namespaces that
+// are being deleted are excluded.
+func projectedNamespaces(resource *unstructured.Unstructured, namespaces []*corev1.Namespace, fedNamespace string) (sets.String, error) {
+	placement, err := util.UnmarshalGenericPlacement(resource)
+	if err != nil {
+		return nil, err
+	}
+	result := sets.NewString(placement.ProjectedNamespaces()...)
+	selector, err := placement.NamespaceSelector()
+	if err != nil {
+		return nil, err
+	}
+	if selector != nil {
+		for _, namespace := range namespaces {
+			if namespace.DeletionTimestamp == nil && selector.Matches(labels.Set(namespace.Labels)) {
+				result.Insert(namespace.Name)
+			}
+		}
+	}
+	result.Delete(resource.GetNamespace(), fedNamespace)
+	return result, nil
+}
+
+// projectionSpec returns the spec of a projection of the given
+// federated resource, which does not project any further.
+func projectionSpec(resource *unstructured.Unstructured) map[string]interface{} {
+	// NestedMap returns a copy of the spec.
+	spec, _, _ := unstructured.NestedMap(resource.Object, util.SpecField)
+	if spec == nil {
+		spec = make(map[string]interface{})
+	}
+	unstructured.RemoveNestedField(spec, util.PlacementField, namespacesField)
+	unstructured.RemoveNestedField(spec, util.PlacementField, namespaceSelectorField)
+	return spec
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaceprojection
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func federatedConfigMap(placement map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			"placement": placement,
+		},
+	}}
+	obj.SetNamespace("source")
+	obj.SetName("config")
+	return obj
+}
+
+func TestProjectedNamespaces(t *testing.T) {
+	now := metav1.Now()
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "source", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b", Labels: map[string]string{"tenant": "true"}, DeletionTimestamp: &now}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-federation-system", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	}
+	testCases := map[string]struct {
+		placement map[string]interface{}
+		expected  []string
+	}{
+		"no projection": {
+			placement: map[string]interface{}{"clusterSelector": map[string]interface{}{}},
+			expected:  []string{},
+		},
+		"listed namespaces": {
+			placement: map[string]interface{}{"namespaces": []interface{}{"source", "other", "missing"}},
+			expected:  []string{"missing", "other"},
+		},
+		"selected namespaces": {
+			placement: map[string]interface{}{"namespaceSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"tenant": "true"},
+			}},
+			expected: []string{"tenant-a"},
+		},
+		"listed and selected namespaces": {
+			placement: map[string]interface{}{
+				"namespaces": []interface{}{"other"},
+				"namespaceSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"tenant": "true"},
+				},
+			},
+			expected: []string{"other", "tenant-a"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := projectedNamespaces(federatedConfigMap(tc.placement), namespaces, "kube-federation-system")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.List(), tc.expected) {
+				t.Fatalf("Expected namespaces %v, got %v", tc.expected, result.List())
+			}
+		})
+	}
+}
+
+func TestProjectionSpec(t *testing.T) {
+	resource := federatedConfigMap(map[string]interface{}{
+		"clusterSelector":   map[string]interface{}{},
+		"namespaces":        []interface{}{"other"},
+		"namespaceSelector": map[string]interface{}{},
+	})
+	expected := map[string]interface{}{
+		"template": map[string]interface{}{
+			"data": map[string]interface{}{"key": "value"},
+		},
+		"placement": map[string]interface{}{
+			"clusterSelector": map[string]interface{}{},
+		},
+	}
+	if spec := projectionSpec(resource); !reflect.DeepEqual(spec, expected) {
+		t.Fatalf("Expected spec %v, got %v", expected, spec)
+	}
+	if _, ok, _ := unstructured.NestedSlice(resource.Object, "spec", "placement", "namespaces"); !ok {
+		t.Fatalf("Expected the spec of the resource to be unchanged")
+	}
+}
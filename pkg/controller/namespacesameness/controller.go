@@ -0,0 +1,705 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacesameness
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeclient "k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	genscheme "sigs.k8s.io/kubefed/pkg/client/generic/scheme"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// DefaultInterval is how often federated namespaces are verified
+	// if not configured.
+	DefaultInterval = 5 * time.Minute
+
+	userAgent = "NamespaceSameness"
+)
+
+// Reasons of deviations, used as the label of the deviation metric
+// and in events.
+const (
+	ReasonNamespaceMissing   = "NamespaceMissing"
+	ReasonLabelMismatch      = "LabelMismatch"
+	ReasonAnnotationMismatch = "AnnotationMismatch"
+	ReasonQuotaMismatch      = "QuotaMismatch"
+)
+
+// deviation describes how a namespace in a member cluster differs
+// from the namespace expected in every cluster.
+type deviation struct {
+	cluster string
+	reason  string
+	message string
+}
+
+// Controller periodically verifies that each federated namespace
+// exists in every ready member cluster that resources of the namespace
+// are placed in, with the labels and annotations of its
+// FederatedNamespace and with the same ResourceQuotas. Deviations are
+// reported, and repaired if the policy is Repair.
+type Controller struct {
+	client genericclient.Client
+
+	kubeConfig *restclient.Config
+
+	// fedNamespace is the namespace containing the
+	// FederatedTypeConfigs and KubeFedClusters.
+	fedNamespace string
+
+	policy fedv1b1.NamespaceSamenessPolicy
+
+	// interval is how often each namespace is verified.
+	interval time.Duration
+
+	// Store and informer for the namespaces of the host cluster
+	namespaceStore      cache.Store
+	namespaceController cache.Controller
+
+	// Store and informer for the FederatedTypeConfigs
+	typeConfigStore      cache.Store
+	typeConfigController cache.Controller
+
+	// Store and informer for the KubeFedClusters
+	clusterStore      cache.Store
+	clusterController cache.Controller
+
+	// resourceClients holds the client for each federated type.
+	resourceClients *util.ResourceClientCache
+
+	// clusterClients holds the client for each member cluster, keyed
+	// by cluster name.
+	clusterClients map[string]genericclient.Client
+
+	// clusterTransports provides the shared transport of each member
+	// cluster. Nil if transports are not shared.
+	clusterTransports *util.ClusterTransportCache
+
+	// deviations holds the deviations last found for each namespace,
+	// keyed by namespace name.
+	deviations map[string][]deviation
+
+	eventRecorder record.EventRecorder
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller verifying namespace sameness
+// according to the configuration of the controller manager.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	policy := fedv1b1.NamespaceSamenessReport
+	interval := DefaultInterval
+	if samenessConfig := config.NamespaceSameness; samenessConfig != nil {
+		if samenessConfig.Policy != nil {
+			policy = *samenessConfig.Policy
+		}
+		if samenessConfig.Interval != nil {
+			interval = samenessConfig.Interval.Duration
+		}
+	}
+	controller, err := newController(config, policy, interval)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting namespace sameness controller with the %q policy", policy)
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to verify namespace
+// sameness.
+func newController(config *util.ControllerConfig, policy fedv1b1.NamespaceSamenessPolicy, interval time.Duration) (*Controller, error) {
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	client, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient := kubeclient.NewForConfigOrDie(kubeConfig)
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(genscheme.Scheme, corev1.EventSource{Component: "namespacesameness-controller"})
+
+	c := &Controller{
+		client:            client,
+		kubeConfig:        kubeConfig,
+		fedNamespace:      config.KubeFedNamespace,
+		policy:            policy,
+		interval:          interval,
+		resourceClients:   util.NewResourceClientCache(kubeConfig),
+		clusterClients:    make(map[string]genericclient.Client),
+		clusterTransports: config.ClusterTransports,
+		deviations:        make(map[string][]deviation),
+		eventRecorder:     recorder,
+	}
+
+	c.worker = util.NewReconcileWorker("namespacesameness", c.reconcile, util.WorkerTiming{})
+
+	c.namespaceStore, c.namespaceController, err = util.NewGenericInformer(
+		kubeConfig,
+		metav1.NamespaceAll,
+		&corev1.Namespace{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// A change to the federated types affects every namespace.
+	c.typeConfigStore, c.typeConfigController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.FederatedTypeConfig{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) { c.enqueueAll() },
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Changes to clusters are observed when namespaces are next
+	// verified.
+	c.clusterStore, c.clusterController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.KubeFedCluster{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) {},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.namespaceController.Run(stopChan)
+	go c.typeConfigController.Run(stopChan)
+	go c.clusterController.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.namespaceController.HasSynced, c.typeConfigController.HasSynced, c.clusterController.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for caches to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.namespaceStore.List() {
+		c.worker.EnqueueObject(obj.(pkgruntime.Object))
+	}
+}
+
+// reconcile verifies the federated namespace of the given namespace,
+// records the number of deviations per cluster and reason, and
+// schedules the next verification of the namespace.
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	namespace := qualifiedName.Name
+	defer metrics.UpdateControllerReconcileDurationFromStart("namespacesamenesscontroller", time.Now())
+
+	_, exists, err := c.namespaceStore.GetByKey(namespace)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query namespace store for %q", namespace))
+		return util.StatusError
+	}
+	if !exists {
+		c.recordDeviations(namespace, nil)
+		return util.StatusAllOK
+	}
+
+	deviations, err := c.verify(namespace)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return util.StatusError
+	}
+	c.recordDeviations(namespace, deviations)
+
+	// Namespaces in member clusters are not watched, so each
+	// namespace is verified again after the interval.
+	c.worker.EnqueueWithDelay(qualifiedName, c.interval)
+	return util.StatusAllOK
+}
+
+// verify verifies the federated namespace of the given namespace, if
+// any, and returns the deviations found.
+func (c *Controller) verify(namespace string) ([]deviation, error) {
+	var namespaceType *metav1.APIResource
+	resourceTypes := []metav1.APIResource{}
+	for _, obj := range c.typeConfigStore.List() {
+		typeConfig := obj.(*fedv1b1.FederatedTypeConfig)
+		if !typeConfig.GetPropagationEnabled() {
+			continue
+		}
+		federatedType := typeConfig.GetFederatedType()
+		if typeConfig.IsNamespace() {
+			namespaceType = &federatedType
+		} else if typeConfig.GetNamespaced() {
+			resourceTypes = append(resourceTypes, federatedType)
+		}
+	}
+	if namespaceType == nil {
+		klog.V(2).Infof("Namespaces are not enabled for propagation, not verifying the sameness of namespace %q", namespace)
+		return nil, nil
+	}
+
+	clusters := []*fedv1b1.KubeFedCluster{}
+	for _, obj := range c.clusterStore.List() {
+		cluster := obj.(*fedv1b1.KubeFedCluster)
+		if util.IsClusterReady(&cluster.Status) {
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	namespaceClient, err := c.resourceClients.Get(*namespaceType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create client for %s", namespaceType.Kind)
+	}
+	fedNamespace, err := namespaceClient.Resources(namespace).Get(namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get %s %q", namespaceType.Kind, namespace)
+	}
+
+	// The clusters the resources of the namespace are placed in.
+	placement := placedClusters(fedNamespace, clusters)
+	for _, resourceType := range resourceTypes {
+		client, err := c.resourceClients.Get(resourceType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create client for %s", resourceType.Kind)
+		}
+		resources, err := client.Resources(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list %s in namespace %q", resourceType.Kind, namespace)
+		}
+		for i := range resources.Items {
+			placement.Insert(placedClusters(&resources.Items[i], clusters).UnsortedList()...)
+		}
+	}
+
+	return c.verifyNamespace(fedNamespace, clusters, placement), nil
+}
+
+// recordDeviations records the deviations found for the given
+// namespace and updates the number of deviations per cluster and
+// reason across all namespaces.
+func (c *Controller) recordDeviations(namespace string, deviations []deviation) {
+	if len(deviations) == 0 {
+		delete(c.deviations, namespace)
+	} else {
+		c.deviations[namespace] = deviations
+	}
+	metrics.RecordNamespaceSamenessDeviations(deviationCounts(c.deviations))
+}
+
+// deviationCounts returns the number of the given deviations per
+// cluster and reason.
+func deviationCounts(namespaceDeviations map[string][]deviation) map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	for _, deviations := range namespaceDeviations {
+		for _, d := range deviations {
+			if counts[d.cluster] == nil {
+				counts[d.cluster] = make(map[string]int)
+			}
+			counts[d.cluster][d.reason]++
+		}
+	}
+	return counts
+}
+
+// verifyNamespace verifies the namespace of the given FederatedNamespace
+// in the given clusters, and returns the deviations found.
+func (c *Controller) verifyNamespace(fedNamespace *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster, clusterNames sets.String) []deviation {
+	namespace := fedNamespace.GetNamespace()
+	overrides, err := util.GetOverrides(fedNamespace)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to read the overrides of the federated namespace %q", namespace))
+		return nil
+	}
+	expectedLabels, _, err := unstructured.NestedStringMap(fedNamespace.Object, util.SpecField, util.TemplateField, "metadata", "labels")
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to read the labels of the federated namespace %q", namespace))
+		return nil
+	}
+	expectedAnnotations, _, err := unstructured.NestedStringMap(fedNamespace.Object, util.SpecField, util.TemplateField, "metadata", "annotations")
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to read the annotations of the federated namespace %q", namespace))
+		return nil
+	}
+
+	deviations := []deviation{}
+	clusterNamespaces := make(map[string]*corev1.Namespace)
+	clusterQuotas := make(map[string][]corev1.ResourceQuota)
+	for _, cluster := range clusters {
+		if !clusterNames.Has(cluster.Name) {
+			continue
+		}
+		client, err := c.clusterClient(cluster)
+		if err != nil {
+			klog.V(4).Infof("Failed to create client for cluster %q: %v", cluster.Name, err)
+			continue
+		}
+		clusterNamespace := &corev1.Namespace{}
+		err = client.Get(context.TODO(), clusterNamespace, "", namespace)
+		if apierrors.IsNotFound(err) {
+			clusterNamespace = nil
+		} else if err != nil {
+			// The client is recreated in case the credentials of the
+			// cluster have changed.
+			delete(c.clusterClients, cluster.Name)
+			klog.V(4).Infof("Failed to get namespace %q in cluster %q: %v", namespace, cluster.Name, err)
+			continue
+		}
+		clusterNamespaces[cluster.Name] = clusterNamespace
+		deviations = append(deviations, metadataDeviations(cluster.Name, clusterNamespace,
+			excludeOverridden(expectedLabels, overrides[cluster.Name], "/metadata/labels"),
+			excludeOverridden(expectedAnnotations, overrides[cluster.Name], "/metadata/annotations"))...)
+
+		if clusterNamespace == nil {
+			continue
+		}
+		quotaList := &corev1.ResourceQuotaList{}
+		if err := client.List(context.TODO(), quotaList, namespace); err != nil {
+			klog.V(4).Infof("Failed to list the ResourceQuotas of namespace %q in cluster %q: %v", namespace, cluster.Name, err)
+			delete(clusterNamespaces, cluster.Name)
+			continue
+		}
+		clusterQuotas[cluster.Name] = unmanagedQuotas(quotaList.Items)
+	}
+	expectedQuotas, quotaDeviations := quotaDeviations(clusterQuotas)
+	deviations = append(deviations, quotaDeviations...)
+
+	for _, d := range deviations {
+		klog.V(2).Infof("Namespace %q in cluster %q is not the same: %s", namespace, d.cluster, d.message)
+		c.eventRecorder.Eventf(fedNamespace, corev1.EventTypeWarning, d.reason,
+			"Namespace %q in cluster %q is not the same: %s", namespace, d.cluster, d.message)
+	}
+	if c.policy != fedv1b1.NamespaceSamenessRepair {
+		return deviations
+	}
+
+	repaired := sets.NewString()
+	for _, d := range deviations {
+		if repaired.Has(d.cluster) {
+			continue
+		}
+		repaired.Insert(d.cluster)
+		client := c.clusterClients[d.cluster]
+		if client == nil {
+			continue
+		}
+		err := repairNamespace(client, namespace, clusterNamespaces[d.cluster],
+			excludeOverridden(expectedLabels, overrides[d.cluster], "/metadata/labels"),
+			excludeOverridden(expectedAnnotations, overrides[d.cluster], "/metadata/annotations"),
+			expectedQuotas)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to repair namespace %q in cluster %q", namespace, d.cluster))
+			continue
+		}
+		c.eventRecorder.Eventf(fedNamespace, corev1.EventTypeNormal, "NamespaceRepaired",
+			"Repaired namespace %q in cluster %q", namespace, d.cluster)
+	}
+	return deviations
+}
+
+// placedClusters returns the names of the given clusters that the
+// placement of the given federated resource selects by name or by
+// cluster selector, and of the clusters its propagation status reports.
+func placedClusters(resource *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster) sets.String {
+	clusterNames := sets.NewString()
+
+	placement, err := util.UnmarshalGenericPlacement(resource)
+	if err == nil {
+		if names := placement.ClusterNames(); names != nil {
+			clusterNames.Insert(names...)
+		} else if placement.Spec.Placement.ClusterSelector != nil {
+			if selector, err := placement.ClusterSelector(); err == nil {
+				for _, cluster := range clusters {
+					if selector.Matches(labels.Set(cluster.Labels)) {
+						clusterNames.Insert(cluster.Name)
+					}
+				}
+			}
+		}
+	}
+
+	fedResource := &status.GenericFederatedResource{}
+	if err := util.UnstructuredToInterface(resource, fedResource); err == nil && fedResource.Status != nil {
+		for _, cluster := range fedResource.Status.Clusters {
+			clusterNames.Insert(cluster.Name)
+		}
+	}
+	return clusterNames
+}
+
+// metadataDeviations returns the deviations of the given namespace in
+// the named cluster, nil if it does not exist, from the expected labels
+// and annotations. Labels and annotations not expected are ignored.
+func metadataDeviations(clusterName string, namespace *corev1.Namespace, expectedLabels, expectedAnnotations map[string]string) []deviation {
+	if namespace == nil {
+		return []deviation{{
+			cluster: clusterName,
+			reason:  ReasonNamespaceMissing,
+			message: "the namespace does not exist",
+		}}
+	}
+	deviations := []deviation{}
+	if keys := mismatchedKeys(expectedLabels, namespace.Labels); len(keys) > 0 {
+		deviations = append(deviations, deviation{
+			cluster: clusterName,
+			reason:  ReasonLabelMismatch,
+			message: fmt.Sprintf("labels %s differ", strings.Join(keys, ", ")),
+		})
+	}
+	if keys := mismatchedKeys(expectedAnnotations, namespace.Annotations); len(keys) > 0 {
+		deviations = append(deviations, deviation{
+			cluster: clusterName,
+			reason:  ReasonAnnotationMismatch,
+			message: fmt.Sprintf("annotations %s differ", strings.Join(keys, ", ")),
+		})
+	}
+	return deviations
+}
+
+// mismatchedKeys returns the sorted keys of expected whose values are
+// missing from or differ in actual.
+func mismatchedKeys(expected, actual map[string]string) []string {
+	keys := []string{}
+	for key, value := range expected {
+		if actualValue, ok := actual[key]; !ok || actualValue != value {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// excludeOverridden returns the given labels or annotations without
+// the keys that the given overrides of a cluster replace, so that
+// values intentionally differing per cluster are not reported.
+func excludeOverridden(expected map[string]string, overrides util.ClusterOverrides, path string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range expected {
+		keyPath := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+		overridden := false
+		for _, override := range overrides {
+			if override.Path == path || override.Path == keyPath || strings.HasPrefix(path, override.Path+"/") {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// unmanagedQuotas returns the given ResourceQuotas that are not
+// propagated by KubeFed. The quotas propagated by KubeFed are kept up
+// to date by their federated resources, whose overrides may vary them
+// per cluster.
+func unmanagedQuotas(quotas []corev1.ResourceQuota) []corev1.ResourceQuota {
+	result := []corev1.ResourceQuota{}
+	for _, quota := range quotas {
+		if quota.Labels[util.ManagedByKubeFedLabelKey] != util.ManagedByKubeFedLabelValue {
+			result = append(result, quota)
+		}
+	}
+	return result
+}
+
+// quotaDeviations returns the ResourceQuotas shared by most of the
+// given clusters, keyed by name, and the deviations of the clusters
+// whose quotas differ. Ties are broken in favor of the quotas of the
+// cluster whose name sorts first.
+func quotaDeviations(clusterQuotas map[string][]corev1.ResourceQuota) (map[string]corev1.ResourceList, []deviation) {
+	clusterNames := []string{}
+	for clusterName := range clusterQuotas {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+
+	fingerprints := make(map[string]string)
+	counts := make(map[string]int)
+	expectedFingerprint := ""
+	var expected map[string]corev1.ResourceList
+	for _, clusterName := range clusterNames {
+		hard := quotaHard(clusterQuotas[clusterName])
+		fingerprint := quotaFingerprint(hard)
+		fingerprints[clusterName] = fingerprint
+		counts[fingerprint]++
+		if expected == nil || counts[fingerprint] > counts[expectedFingerprint] {
+			expectedFingerprint = fingerprint
+			expected = hard
+		}
+	}
+
+	deviations := []deviation{}
+	for _, clusterName := range clusterNames {
+		if fingerprints[clusterName] == expectedFingerprint {
+			continue
+		}
+		deviations = append(deviations, deviation{
+			cluster: clusterName,
+			reason:  ReasonQuotaMismatch,
+			message: fmt.Sprintf("the ResourceQuotas are %s rather than %s", describeQuotas(fingerprints[clusterName]), describeQuotas(expectedFingerprint)),
+		})
+	}
+	return expected, deviations
+}
+
+// quotaHard returns the hard limits of the given quotas keyed by name.
+func quotaHard(quotas []corev1.ResourceQuota) map[string]corev1.ResourceList {
+	hard := make(map[string]corev1.ResourceList)
+	for _, quota := range quotas {
+		hard[quota.Name] = quota.Spec.Hard
+	}
+	return hard
+}
+
+// quotaFingerprint returns a canonical description of the given hard
+// limits of quotas.
+func quotaFingerprint(hard map[string]corev1.ResourceList) string {
+	quotas := []string{}
+	for name, resources := range hard {
+		limits := []string{}
+		for resourceName, quantity := range resources {
+			limits = append(limits, fmt.Sprintf("%s=%s", resourceName, quantity.String()))
+		}
+		sort.Strings(limits)
+		quotas = append(quotas, fmt.Sprintf("%s(%s)", name, strings.Join(limits, ",")))
+	}
+	sort.Strings(quotas)
+	return strings.Join(quotas, " ")
+}
+
+func describeQuotas(fingerprint string) string {
+	if fingerprint == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%q", fingerprint)
+}
+
+// repairNamespace creates the given namespace in a member cluster if
+// it does not exist, sets its expected labels and annotations, and
+// creates or updates its expected ResourceQuotas. Quotas that are not
+// expected are left in place.
+func repairNamespace(client genericclient.Client, name string, namespace *corev1.Namespace, expectedLabels, expectedAnnotations map[string]string, expectedQuotas map[string]corev1.ResourceList) error {
+	if namespace == nil {
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      expectedLabels,
+				Annotations: expectedAnnotations,
+			},
+		}
+		if err := client.Create(context.TODO(), namespace); err != nil {
+			return err
+		}
+	} else if len(mismatchedKeys(expectedLabels, namespace.Labels)) > 0 || len(mismatchedKeys(expectedAnnotations, namespace.Annotations)) > 0 {
+		namespace = namespace.DeepCopy()
+		if namespace.Labels == nil {
+			namespace.Labels = make(map[string]string)
+		}
+		for key, value := range expectedLabels {
+			namespace.Labels[key] = value
+		}
+		if namespace.Annotations == nil {
+			namespace.Annotations = make(map[string]string)
+		}
+		for key, value := range expectedAnnotations {
+			namespace.Annotations[key] = value
+		}
+		if err := client.Update(context.TODO(), namespace); err != nil {
+			return err
+		}
+	}
+
+	for quotaName, hard := range expectedQuotas {
+		quota := &corev1.ResourceQuota{}
+		err := client.Get(context.TODO(), quota, name, quotaName)
+		if apierrors.IsNotFound(err) {
+			quota = &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Namespace: name, Name: quotaName},
+				Spec:       corev1.ResourceQuotaSpec{Hard: hard.DeepCopy()},
+			}
+			err = client.Create(context.TODO(), quota)
+		} else if err == nil && quotaFingerprint(map[string]corev1.ResourceList{quotaName: quota.Spec.Hard}) != quotaFingerprint(map[string]corev1.ResourceList{quotaName: hard}) {
+			quota.Spec.Hard = hard.DeepCopy()
+			err = client.Update(context.TODO(), quota)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Failed to repair ResourceQuota %q", quotaName)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) clusterClient(cluster *fedv1b1.KubeFedCluster) (genericclient.Client, error) {
+	if client, ok := c.clusterClients[cluster.Name]; ok {
+		return client, nil
+	}
+	config, err := util.BuildClusterConfig(cluster, c.client, c.fedNamespace, c.kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if c.clusterTransports != nil {
+		config, err = c.clusterTransports.Configure(cluster, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	restclient.AddUserAgent(config, userAgent)
+	client, err := genericclient.New(config)
+	if err != nil {
+		return nil, err
+	}
+	c.clusterClients[cluster.Name] = client
+	return client, nil
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacesameness
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestMetadataDeviations(t *testing.T) {
+	expectedLabels := map[string]string{"team": "a"}
+	expectedAnnotations := map[string]string{"owner": "alice"}
+	testCases := map[string]struct {
+		namespace *corev1.Namespace
+		reasons   []string
+	}{
+		"missing namespace": {
+			reasons: []string{ReasonNamespaceMissing},
+		},
+		"same namespace with additional metadata": {
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"team": "a", "local": "true"},
+				Annotations: map[string]string{"owner": "alice"},
+			}},
+			reasons: []string{},
+		},
+		"differing label": {
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"team": "b"},
+				Annotations: map[string]string{"owner": "alice"},
+			}},
+			reasons: []string{ReasonLabelMismatch},
+		},
+		"missing label and annotation": {
+			namespace: &corev1.Namespace{},
+			reasons:   []string{ReasonLabelMismatch, ReasonAnnotationMismatch},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			reasons := []string{}
+			for _, d := range metadataDeviations("cluster1", tc.namespace, expectedLabels, expectedAnnotations) {
+				if d.cluster != "cluster1" {
+					t.Errorf("Expected a deviation in cluster1, got %q", d.cluster)
+				}
+				reasons = append(reasons, d.reason)
+			}
+			if !reflect.DeepEqual(reasons, tc.reasons) {
+				t.Errorf("Expected reasons %v, got %v", tc.reasons, reasons)
+			}
+		})
+	}
+}
+
+func TestExcludeOverridden(t *testing.T) {
+	expected := map[string]string{"team": "a", "example.com/tier": "gold"}
+	testCases := map[string]struct {
+		overrides util.ClusterOverrides
+		result    map[string]string
+	}{
+		"no overrides": {
+			result: expected,
+		},
+		"override of an escaped key": {
+			overrides: util.ClusterOverrides{{Path: "/metadata/labels/example.com~1tier", Value: "silver"}},
+			result:    map[string]string{"team": "a"},
+		},
+		"override of all labels": {
+			overrides: util.ClusterOverrides{{Path: "/metadata/labels"}},
+			result:    map[string]string{},
+		},
+		"override of the metadata": {
+			overrides: util.ClusterOverrides{{Path: "/metadata"}},
+			result:    map[string]string{},
+		},
+		"override of other fields": {
+			overrides: util.ClusterOverrides{{Path: "/metadata/annotations/team"}, {Path: "/spec/finalizers"}},
+			result:    expected,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := excludeOverridden(expected, tc.overrides, "/metadata/labels")
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("Expected %v, got %v", tc.result, result)
+			}
+		})
+	}
+}
+
+func quota(name, cpu string) corev1.ResourceQuota {
+	return corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		},
+	}
+}
+
+func TestQuotaDeviations(t *testing.T) {
+	testCases := map[string]struct {
+		clusterQuotas map[string][]corev1.ResourceQuota
+		expectedCPU   string
+		clusters      []string
+	}{
+		"same quotas": {
+			clusterQuotas: map[string][]corev1.ResourceQuota{
+				"cluster1": {quota("compute", "4")},
+				"cluster2": {quota("compute", "4000m")},
+			},
+			expectedCPU: "4",
+			clusters:    []string{},
+		},
+		"majority wins": {
+			clusterQuotas: map[string][]corev1.ResourceQuota{
+				"cluster1": {quota("compute", "2")},
+				"cluster2": {quota("compute", "4")},
+				"cluster3": {quota("compute", "4")},
+			},
+			expectedCPU: "4",
+			clusters:    []string{"cluster1"},
+		},
+		"tie broken by cluster name": {
+			clusterQuotas: map[string][]corev1.ResourceQuota{
+				"cluster2": {},
+				"cluster1": {quota("compute", "2")},
+			},
+			expectedCPU: "2",
+			clusters:    []string{"cluster2"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			expected, deviations := quotaDeviations(tc.clusterQuotas)
+			hard, ok := expected["compute"]
+			if !ok || len(expected) != 1 {
+				t.Fatalf("Expected only the compute quota, got %v", expected)
+			}
+			if cpu := hard[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse(tc.expectedCPU)) != 0 {
+				t.Errorf("Expected %s CPU, got %s", tc.expectedCPU, cpu.String())
+			}
+			clusters := []string{}
+			for _, d := range deviations {
+				if d.reason != ReasonQuotaMismatch {
+					t.Errorf("Expected reason %s, got %s", ReasonQuotaMismatch, d.reason)
+				}
+				clusters = append(clusters, d.cluster)
+			}
+			if !reflect.DeepEqual(clusters, tc.clusters) {
+				t.Errorf("Expected deviations in %v, got %v", tc.clusters, clusters)
+			}
+		})
+	}
+}
+
+func TestUnmanagedQuotas(t *testing.T) {
+	managed := quota("managed", "1")
+	managed.Labels = map[string]string{util.ManagedByKubeFedLabelKey: util.ManagedByKubeFedLabelValue}
+	quotas := unmanagedQuotas([]corev1.ResourceQuota{managed, quota("local", "1")})
+	if len(quotas) != 1 || quotas[0].Name != "local" {
+		t.Errorf("Expected only the local quota, got %v", quotas)
+	}
+}
+
+func TestPlacedClusters(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"region": "eu"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster2", Labels: map[string]string{"region": "us"}}},
+	}
+	testCases := map[string]struct {
+		spec     map[string]interface{}
+		status   map[string]interface{}
+		clusters []string
+	}{
+		"explicit clusters": {
+			spec: map[string]interface{}{"placement": map[string]interface{}{
+				"clusters": []interface{}{map[string]interface{}{"name": "cluster2"}},
+			}},
+			clusters: []string{"cluster2"},
+		},
+		"cluster selector": {
+			spec: map[string]interface{}{"placement": map[string]interface{}{
+				"clusterSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"region": "eu"}},
+			}},
+			clusters: []string{"cluster1"},
+		},
+		"propagation status": {
+			spec: map[string]interface{}{"placement": map[string]interface{}{
+				"clusters": []interface{}{},
+			}},
+			status: map[string]interface{}{"clusters": []interface{}{
+				map[string]interface{}{"name": "cluster1", "status": "Removed"},
+			}},
+			clusters: []string{"cluster1"},
+		},
+		"no placement": {
+			spec:     map[string]interface{}{},
+			clusters: []string{},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resource := &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "test", "namespace": "test"},
+				"spec":     tc.spec,
+			}}
+			if tc.status != nil {
+				resource.Object["status"] = tc.status
+			}
+			clusterNames := placedClusters(resource, clusters).List()
+			if !reflect.DeepEqual(clusterNames, tc.clusters) {
+				t.Errorf("Expected clusters %v, got %v", tc.clusters, clusterNames)
+			}
+		})
+	}
+}
+
+func TestDeviationCounts(t *testing.T) {
+	namespaceDeviations := map[string][]deviation{
+		"ns1": {
+			{cluster: "cluster1", reason: ReasonNamespaceMissing},
+			{cluster: "cluster2", reason: ReasonLabelMismatch},
+		},
+		"ns2": {
+			{cluster: "cluster2", reason: ReasonLabelMismatch},
+			{cluster: "cluster2", reason: ReasonQuotaMismatch},
+		},
+	}
+	expected := map[string]map[string]int{
+		"cluster1": {ReasonNamespaceMissing: 1},
+		"cluster2": {ReasonLabelMismatch: 2, ReasonQuotaMismatch: 1},
+	}
+	if counts := deviationCounts(namespaceDeviations); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected counts %v, got %v", expected, counts)
+	}
+}
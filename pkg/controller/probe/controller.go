@@ -0,0 +1,433 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// ProbeName is the name of the namespace containing the probe
+	// and of the probe ConfigMap.
+	ProbeName = "kubefed-propagation-probe"
+
+	// ProbeTimestampKey is the key of the probe ConfigMap whose value
+	// identifies the current probe.
+	ProbeTimestampKey = "timestamp"
+
+	// pollInterval is how often member clusters and the status of the
+	// probe are checked while a probe is in progress.
+	pollInterval = time.Second
+)
+
+// probeKey is the key the worker of the controller runs probes for.
+var probeKey = util.QualifiedName{Namespace: ProbeName, Name: ProbeName}
+
+// federatedType is a federated type and the client for it.
+type federatedType struct {
+	apiResource metav1.APIResource
+	client      util.ResourceClient
+}
+
+// Controller periodically updates a FederatedConfigMap propagated to
+// every cluster and measures, per cluster, how long it takes for the
+// update to be applied and for the propagation status to report it.
+type Controller struct {
+	client genericclient.Client
+
+	kubeConfig *restclient.Config
+
+	// fedNamespace is the namespace containing the
+	// FederatedTypeConfigs and KubeFedClusters.
+	fedNamespace string
+
+	// interval is how often a probe is started. A probe that has not
+	// completed by the start of the next is counted as failed for the
+	// clusters it has not completed for.
+	interval time.Duration
+
+	// Store and informer for the FederatedTypeConfigs
+	typeConfigStore      cache.Store
+	typeConfigController cache.Controller
+
+	// Store and informer for the KubeFedClusters
+	clusterStore      cache.Store
+	clusterController cache.Controller
+
+	// resourceClients holds the client for each federated type.
+	resourceClients *util.ResourceClientCache
+
+	// clusterClients holds the client for each member cluster, keyed
+	// by cluster name.
+	clusterClients map[string]genericclient.Client
+
+	// clusterTransports provides the shared transport of each member
+	// cluster. Nil if transports are not shared.
+	clusterTransports *util.ClusterTransportCache
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller probing propagation at the
+// given interval.
+func StartController(config *util.ControllerConfig, interval time.Duration, stopChan <-chan struct{}) error {
+	controller, err := newController(config, interval)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting propagation probe controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to probe propagation.
+func newController(config *util.ControllerConfig, interval time.Duration) (*Controller, error) {
+	userAgent := "PropagationProbe"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	client, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:            client,
+		kubeConfig:        kubeConfig,
+		fedNamespace:      config.KubeFedNamespace,
+		interval:          interval,
+		resourceClients:   util.NewResourceClientCache(kubeConfig),
+		clusterClients:    make(map[string]genericclient.Client),
+		clusterTransports: config.ClusterTransports,
+	}
+
+	c.worker = util.NewReconcileWorker("propagationprobe", c.reconcile, util.WorkerTiming{})
+
+	// Changes to type configs and clusters are observed by the next
+	// probe.
+	c.typeConfigStore, c.typeConfigController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.FederatedTypeConfig{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) {},
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.clusterStore, c.clusterController, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.KubeFedCluster{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) {},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.typeConfigController.Run(stopChan)
+	go c.clusterController.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.typeConfigController.HasSynced, c.clusterController.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for caches to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+	c.worker.Enqueue(probeKey)
+}
+
+// reconcile runs a probe and schedules the next one to start after the
+// probe interval.
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	c.probe()
+	c.worker.EnqueueWithDelay(qualifiedName, c.interval)
+	return util.StatusAllOK
+}
+
+// probe updates the probe and waits for it to be applied to and
+// reported for each ready cluster, recording the durations.
+func (c *Controller) probe() {
+	configMapType, namespaceType, err := c.federatedTypes()
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	if configMapType == nil || namespaceType == nil {
+		klog.V(2).Infof("ConfigMaps or namespaces are not enabled for propagation, not probing propagation")
+		return
+	}
+
+	clusters := make(map[string]*fedv1b1.KubeFedCluster)
+	for _, obj := range c.clusterStore.List() {
+		cluster := obj.(*fedv1b1.KubeFedCluster)
+		if util.IsClusterReady(&cluster.Status) {
+			clusters[cluster.Name] = cluster
+		}
+	}
+	if len(clusters) == 0 {
+		return
+	}
+
+	start := time.Now()
+	timestamp := start.UTC().Format(time.RFC3339Nano)
+	generation, err := c.ensureProbe(namespaceType, configMapType, timestamp)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to update the propagation probe"))
+		return
+	}
+	klog.V(4).Infof("Started propagation probe %q", timestamp)
+
+	pendingApply := sets.StringKeySet(clusters)
+	pendingStatus := sets.StringKeySet(clusters)
+	_ = wait.PollImmediate(pollInterval, c.interval, func() (bool, error) {
+		for _, clusterName := range pendingApply.List() {
+			if c.applied(clusters[clusterName], timestamp) {
+				metrics.ProbeApplyDurationFromStart(clusterName, start)
+				pendingApply.Delete(clusterName)
+			}
+		}
+		if pendingStatus.Len() > 0 {
+			reported, err := c.reportedClusters(configMapType.client, generation)
+			if err != nil {
+				klog.V(4).Infof("Failed to get the status of the propagation probe: %v", err)
+			}
+			for _, clusterName := range pendingStatus.Intersection(reported).List() {
+				metrics.ProbeStatusDurationFromStart(clusterName, start)
+				pendingStatus.Delete(clusterName)
+			}
+		}
+		return pendingApply.Len() == 0 && pendingStatus.Len() == 0, nil
+	})
+
+	for _, clusterName := range pendingApply.List() {
+		klog.Warningf("Propagation probe %q was not applied to cluster %q within %v", timestamp, clusterName, c.interval)
+		metrics.ProbeFailed(clusterName, metrics.ProbeStageApply)
+	}
+	for _, clusterName := range pendingStatus.List() {
+		klog.Warningf("Propagation probe %q was not reported for cluster %q within %v", timestamp, clusterName, c.interval)
+		metrics.ProbeFailed(clusterName, metrics.ProbeStageStatus)
+	}
+}
+
+// ensureProbe creates or updates the FederatedNamespace and the
+// FederatedConfigMap of the probe so that the ConfigMap holds the given
+// timestamp, and returns the generation of the FederatedConfigMap.
+func (c *Controller) ensureProbe(namespaceType, configMapType *federatedType, timestamp string) (int64, error) {
+	namespace := &corev1.Namespace{}
+	err := c.client.Get(context.TODO(), namespace, "", ProbeName)
+	if apierrors.IsNotFound(err) {
+		namespace.Name = ProbeName
+		err = c.client.Create(context.TODO(), namespace)
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return 0, errors.Wrapf(err, "Failed to ensure namespace %q", ProbeName)
+	}
+
+	namespaceClient := namespaceType.client.Resources(ProbeName)
+	_, err = namespaceClient.Get(ProbeName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = namespaceClient.Create(newFederatedObject(namespaceType.apiResource, probeNamespaceSpec()), metav1.CreateOptions{})
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to ensure FederatedNamespace %q", ProbeName)
+	}
+
+	spec := probeConfigMapSpec(timestamp)
+	configMapClient := configMapType.client.Resources(ProbeName)
+	fedConfigMap, err := configMapClient.Get(ProbeName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		fedConfigMap, err = configMapClient.Create(newFederatedObject(configMapType.apiResource, spec), metav1.CreateOptions{})
+	} else if err == nil {
+		fedConfigMap.Object[util.SpecField] = spec
+		fedConfigMap, err = configMapClient.Update(fedConfigMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to ensure FederatedConfigMap %q", ProbeName)
+	}
+	return fedConfigMap.GetGeneration(), nil
+}
+
+// applied checks whether the probe ConfigMap in the given cluster holds
+// the given timestamp.
+func (c *Controller) applied(cluster *fedv1b1.KubeFedCluster, timestamp string) bool {
+	client, err := c.clusterClient(cluster)
+	if err != nil {
+		klog.V(4).Infof("Failed to create client for cluster %q: %v", cluster.Name, err)
+		return false
+	}
+	configMap := &corev1.ConfigMap{}
+	err = client.Get(context.TODO(), configMap, ProbeName, ProbeName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			// The client is recreated in case the credentials of
+			// the cluster have changed.
+			delete(c.clusterClients, cluster.Name)
+		}
+		return false
+	}
+	return configMap.Data[ProbeTimestampKey] == timestamp
+}
+
+// reportedClusters returns the names of the clusters the propagation
+// status of the probe reports as successfully propagated to for at
+// least the given generation.
+func (c *Controller) reportedClusters(configMapClient util.ResourceClient, generation int64) (sets.String, error) {
+	fedConfigMap, err := configMapClient.Resources(ProbeName).Get(ProbeName, metav1.GetOptions{})
+	if err != nil {
+		return sets.NewString(), err
+	}
+	resource := &status.GenericFederatedResource{}
+	if err := util.UnstructuredToInterface(fedConfigMap, resource); err != nil {
+		return sets.NewString(), err
+	}
+	return reportedClusterNames(resource, generation), nil
+}
+
+// reportedClusterNames returns the names of the clusters the status of
+// the given federated resource reports as successfully propagated to,
+// provided that the status reflects at least the given generation.
+func reportedClusterNames(resource *status.GenericFederatedResource, generation int64) sets.String {
+	clusterNames := sets.NewString()
+	if resource.Status == nil || resource.Status.ObservedGeneration < generation {
+		return clusterNames
+	}
+	for _, cluster := range resource.Status.Clusters {
+		if cluster.Status == status.ClusterPropagationOK {
+			clusterNames.Insert(cluster.Name)
+		}
+	}
+	return clusterNames
+}
+
+func (c *Controller) clusterClient(cluster *fedv1b1.KubeFedCluster) (genericclient.Client, error) {
+	if client, ok := c.clusterClients[cluster.Name]; ok {
+		return client, nil
+	}
+	config, err := util.BuildClusterConfig(cluster, c.client, c.fedNamespace, c.kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if c.clusterTransports != nil {
+		config, err = c.clusterTransports.Configure(cluster, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	restclient.AddUserAgent(config, "PropagationProbe")
+	client, err := genericclient.New(config)
+	if err != nil {
+		return nil, err
+	}
+	c.clusterClients[cluster.Name] = client
+	return client, nil
+}
+
+// federatedTypes returns the federated types of ConfigMaps and
+// namespaces, or nil for a type that is not configured.
+func (c *Controller) federatedTypes() (*federatedType, *federatedType, error) {
+	var configMapType, namespaceType *federatedType
+	for _, obj := range c.typeConfigStore.List() {
+		typeConfig := obj.(*fedv1b1.FederatedTypeConfig)
+		var err error
+		switch typeConfig.GetTargetType().Kind {
+		case util.ConfigMapKind:
+			configMapType, err = c.federatedType(typeConfig.GetFederatedType())
+		case util.NamespaceKind:
+			namespaceType, err = c.federatedType(typeConfig.GetFederatedType())
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to create client for %s", typeConfig.GetFederatedType().Kind)
+		}
+	}
+	return configMapType, namespaceType, nil
+}
+
+func (c *Controller) federatedType(apiResource metav1.APIResource) (*federatedType, error) {
+	client, err := c.resourceClients.Get(apiResource)
+	if err != nil {
+		return nil, err
+	}
+	return &federatedType{apiResource: apiResource, client: client}, nil
+}
+
+// newFederatedObject returns a federated resource of the given type
+// for the probe with the given spec.
+func newFederatedObject(apiResource metav1.APIResource, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		util.SpecField: spec,
+	}}
+	obj.SetAPIVersion(schema.GroupVersion{Group: apiResource.Group, Version: apiResource.Version}.String())
+	obj.SetKind(apiResource.Kind)
+	obj.SetNamespace(ProbeName)
+	obj.SetName(ProbeName)
+	return obj
+}
+
+// probeNamespaceSpec returns the spec of the FederatedNamespace of the
+// probe, which places the namespace in all clusters.
+func probeNamespaceSpec() map[string]interface{} {
+	return map[string]interface{}{
+		util.PlacementField: map[string]interface{}{
+			util.ClusterSelectorField: map[string]interface{}{},
+		},
+	}
+}
+
+// probeConfigMapSpec returns the spec of the FederatedConfigMap of the
+// probe holding the given timestamp, which places the ConfigMap in all
+// clusters.
+func probeConfigMapSpec(timestamp string) map[string]interface{} {
+	return map[string]interface{}{
+		util.TemplateField: map[string]interface{}{
+			"data": map[string]interface{}{
+				ProbeTimestampKey: timestamp,
+			},
+		},
+		util.PlacementField: map[string]interface{}{
+			util.ClusterSelectorField: map[string]interface{}{},
+		},
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+)
+
+func TestReportedClusterNames(t *testing.T) {
+	clusters := []status.GenericClusterStatus{
+		{Name: "cluster1"},
+		{Name: "cluster2", Status: status.UpdateFailed},
+		{Name: "cluster3"},
+	}
+
+	testCases := map[string]struct {
+		status   *status.GenericFederatedStatus
+		expected sets.String
+	}{
+		"No status": {
+			expected: sets.NewString(),
+		},
+		"Status of a previous generation": {
+			status:   &status.GenericFederatedStatus{ObservedGeneration: 1, Clusters: clusters},
+			expected: sets.NewString(),
+		},
+		"Status of the probed generation": {
+			status:   &status.GenericFederatedStatus{ObservedGeneration: 2, Clusters: clusters},
+			expected: sets.NewString("cluster1", "cluster3"),
+		},
+		"Status of a later generation": {
+			status:   &status.GenericFederatedStatus{ObservedGeneration: 3, Clusters: clusters},
+			expected: sets.NewString("cluster1", "cluster3"),
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			resource := &status.GenericFederatedResource{Status: tc.status}
+			clusterNames := reportedClusterNames(resource, 2)
+			if !clusterNames.Equal(tc.expected) {
+				t.Errorf("Expected clusters %v, got %v", tc.expected.List(), clusterNames.List())
+			}
+		})
+	}
+}
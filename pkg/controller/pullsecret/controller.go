@@ -0,0 +1,449 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsecret
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// ReplicateLabel marks a pull secret in the KubeFed system
+	// namespace for replication.
+	ReplicateLabel = "kubefed.io/replicate-pull-secret"
+
+	// SourceLabel identifies the pull secret a FederatedSecret was
+	// replicated from.
+	SourceLabel = "kubefed.io/pull-secret-source"
+
+	// resyncPeriod is how often every pull secret is reconciled to
+	// pick up new federated namespaces, clusters and registry
+	// overrides.
+	resyncPeriod = time.Minute
+
+	dockerConfigJSONPath = "/data/" + apiv1.DockerConfigJsonKey
+)
+
+// Controller replicates registry pull secrets from the KubeFed system
+// namespace to every federated namespace as FederatedSecrets. The
+// credentials of a registry are also provided for the registry
+// mirrors that images are rewritten to for a cluster.
+type Controller struct {
+	client genericclient.Client
+
+	// fedNamespace is the namespace containing the pull secrets and
+	// the FederatedTypeConfigs.
+	fedNamespace string
+	// targetNamespace is the namespace containing the federated
+	// namespaces, or all namespaces if empty.
+	targetNamespace string
+
+	// Store for the pull secrets
+	store cache.Store
+	// Informer for the pull secrets
+	controller cache.Controller
+
+	// resourceClients holds the client for each federated type.
+	resourceClients *util.ResourceClientCache
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller for replicating pull secrets.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting pull secret replication controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to replicate pull secrets.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	userAgent := "PullSecretReplication"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	genericclient, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:          genericclient,
+		fedNamespace:    config.KubeFedNamespace,
+		targetNamespace: config.TargetNamespace,
+		resourceClients: util.NewResourceClientCache(kubeConfig),
+	}
+
+	c.worker = util.NewReconcileWorker("pullsecretcontroller", c.reconcile, util.WorkerTiming{})
+
+	c.store, c.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&apiv1.Secret{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+
+	// Federated namespaces, clusters and type configs are not
+	// watched, so pull secrets are periodically reconciled.
+	go wait.Until(c.enqueueAll, resyncPeriod, stopChan)
+}
+
+func (c *Controller) enqueueAll() {
+	for _, obj := range c.store.List() {
+		c.worker.EnqueueObject(obj.(runtime.Object))
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("pullsecretcontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile pull secret for %q", key)
+
+	var secret *apiv1.Secret
+	cachedObj, exist, err := c.store.GetByKey(key)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to query pull secret store for %q", key))
+		return util.StatusError
+	}
+	if exist {
+		secret = cachedObj.(*apiv1.Secret)
+		if !isReplicated(secret) {
+			secret = nil
+		}
+	}
+
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	if err := c.client.List(context.TODO(), typeConfigs, c.fedNamespace); err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "Failed to list FederatedTypeConfigs"))
+		return util.StatusError
+	}
+	secretType, secretClient, namespaceClient, err := c.federatedClients(typeConfigs.Items)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return util.StatusError
+	}
+	if secretClient == nil || namespaceClient == nil {
+		klog.V(2).Infof("Secrets or namespaces are not enabled for propagation, not replicating pull secret %q", key)
+		return util.StatusAllOK
+	}
+
+	// Determine the namespaces the pull secret is replicated to.
+	namespaces := make(map[string]bool)
+	if secret != nil {
+		fedNamespaces, err := namespaceClient.Resources(c.targetNamespace).List(metav1.ListOptions{})
+		if err != nil {
+			utilruntime.HandleError(errors.Wrap(err, "Failed to list FederatedNamespaces"))
+			return util.StatusError
+		}
+		for _, fedNamespace := range fedNamespaces.Items {
+			if fedNamespace.GetDeletionTimestamp() == nil && fedNamespace.GetNamespace() != c.fedNamespace {
+				namespaces[fedNamespace.GetNamespace()] = true
+			}
+		}
+	}
+
+	var desiredSpec map[string]interface{}
+	if secret != nil {
+		clusters := &fedv1b1.KubeFedClusterList{}
+		if err := c.client.List(context.TODO(), clusters, c.fedNamespace); err != nil {
+			utilruntime.HandleError(errors.Wrap(err, "Failed to list KubeFedClusters"))
+			return util.StatusError
+		}
+		desiredSpec, err = federatedSecretSpec(secret, clusters.Items, typeConfigs.Items)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to compute FederatedSecret for pull secret %q", key))
+			return util.StatusAllOK
+		}
+	}
+
+	result := util.StatusAllOK
+
+	// Remove the replicas of the pull secret from namespaces that are
+	// no longer federated.
+	selector := labels.SelectorFromSet(labels.Set{SourceLabel: qualifiedName.Name})
+	replicas, err := secretClient.Resources(c.targetNamespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to list FederatedSecrets for pull secret %q", key))
+		return util.StatusError
+	}
+	for _, replica := range replicas.Items {
+		if namespaces[replica.GetNamespace()] {
+			continue
+		}
+		klog.V(2).Infof("Deleting FederatedSecret %s/%s replicated from pull secret %q", replica.GetNamespace(), replica.GetName(), key)
+		err := secretClient.Resources(replica.GetNamespace()).Delete(replica.GetName(), &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to delete FederatedSecret %s/%s", replica.GetNamespace(), replica.GetName()))
+			result = util.StatusError
+		}
+	}
+
+	for namespace := range namespaces {
+		err := c.ensureReplica(secretClient, secretType, namespace, qualifiedName.Name, desiredSpec)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to replicate pull secret %q to namespace %q", key, namespace))
+			result = util.StatusError
+		}
+	}
+	return result
+}
+
+// ensureReplica creates or updates the FederatedSecret replicating a
+// pull secret in the given namespace. A FederatedSecret of the same
+// name that was not replicated from the pull secret is left alone.
+func (c *Controller) ensureReplica(client util.ResourceClient, apiResource metav1.APIResource, namespace, name string, spec map[string]interface{}) error {
+	obj, err := client.Resources(namespace).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		replica := &unstructured.Unstructured{Object: map[string]interface{}{
+			util.SpecField: spec,
+		}}
+		replica.SetAPIVersion(schema.GroupVersion{Group: apiResource.Group, Version: apiResource.Version}.String())
+		replica.SetKind(apiResource.Kind)
+		replica.SetNamespace(namespace)
+		replica.SetName(name)
+		replica.SetLabels(map[string]string{SourceLabel: name})
+		klog.V(2).Infof("Creating FederatedSecret %s/%s replicated from pull secret %q", namespace, name, name)
+		_, err = client.Resources(namespace).Create(replica, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if obj.GetLabels()[SourceLabel] != name {
+		klog.V(2).Infof("FederatedSecret %s/%s was not replicated from a pull secret, leaving it alone", namespace, name)
+		return nil
+	}
+	if reflect.DeepEqual(obj.Object[util.SpecField], spec) {
+		return nil
+	}
+	obj.Object[util.SpecField] = spec
+	klog.V(2).Infof("Updating FederatedSecret %s/%s replicated from pull secret %q", namespace, name, name)
+	_, err = client.Resources(namespace).Update(obj, metav1.UpdateOptions{})
+	return err
+}
+
+// federatedClients returns the federated type of secrets and the
+// clients for the federated types of secrets and namespaces, or nil
+// for a type that is not configured.
+func (c *Controller) federatedClients(typeConfigs []fedv1b1.FederatedTypeConfig) (metav1.APIResource, util.ResourceClient, util.ResourceClient, error) {
+	var secretType metav1.APIResource
+	var secretClient, namespaceClient util.ResourceClient
+	for i := range typeConfigs {
+		typeConfig := &typeConfigs[i]
+		var err error
+		switch typeConfig.GetTargetType().Kind {
+		case util.SecretKind:
+			secretType = typeConfig.GetFederatedType()
+			secretClient, err = c.resourceClients.Get(secretType)
+		case util.NamespaceKind:
+			namespaceClient, err = c.resourceClients.Get(typeConfig.GetFederatedType())
+		}
+		if err != nil {
+			return secretType, nil, nil, errors.Wrapf(err, "Failed to create client for %s", typeConfig.GetFederatedType().Kind)
+		}
+	}
+	return secretType, secretClient, namespaceClient, nil
+}
+
+// isReplicated returns whether the given secret is a pull secret
+// marked for replication.
+func isReplicated(secret *apiv1.Secret) bool {
+	return secret.Type == apiv1.SecretTypeDockerConfigJson && secret.Labels[ReplicateLabel] == "true"
+}
+
+// federatedSecretSpec returns the spec of the FederatedSecret
+// replicating the given pull secret to all clusters. The pull secret
+// is overridden for clusters whose images are rewritten to registry
+// mirrors.
+func federatedSecretSpec(secret *apiv1.Secret, clusters []fedv1b1.KubeFedCluster, typeConfigs []fedv1b1.FederatedTypeConfig) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	for key, value := range secret.Data {
+		data[key] = base64.StdEncoding.EncodeToString(value)
+	}
+	spec := map[string]interface{}{
+		util.TemplateField: map[string]interface{}{
+			"type": string(secret.Type),
+			"data": data,
+		},
+		// The placement of a federated namespace limits the clusters
+		// that resources in the namespace are propagated to.
+		util.PlacementField: map[string]interface{}{
+			util.ClusterSelectorField: map[string]interface{}{},
+		},
+	}
+
+	sortedClusters := append([]fedv1b1.KubeFedCluster{}, clusters...)
+	sort.Slice(sortedClusters, func(i, j int) bool {
+		return sortedClusters[i].Name < sortedClusters[j].Name
+	})
+	overrides := []interface{}{}
+	for i := range sortedClusters {
+		cluster := &sortedClusters[i]
+		rewrites, err := imageRewrites(cluster, typeConfigs)
+		if err != nil {
+			return nil, err
+		}
+		dockerConfig, changed, err := mirrorDockerConfig(secret.Data[apiv1.DockerConfigJsonKey], rewrites)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+		overrides = append(overrides, map[string]interface{}{
+			util.ClusterNameField: cluster.Name,
+			util.ClusterOverridesField: []interface{}{
+				map[string]interface{}{
+					util.PathField:  dockerConfigJSONPath,
+					util.ValueField: base64.StdEncoding.EncodeToString(dockerConfig),
+				},
+			},
+		})
+	}
+	if len(overrides) > 0 {
+		spec[util.OverridesField] = overrides
+	}
+	return spec, nil
+}
+
+// imageRewrites returns the image rewrites configured by dispatch
+// mutators of any federated type that apply to the given cluster.
+func imageRewrites(cluster *fedv1b1.KubeFedCluster, typeConfigs []fedv1b1.FederatedTypeConfig) ([]fedv1b1.ImageRewriteMutator, error) {
+	var rewrites []fedv1b1.ImageRewriteMutator
+	for _, typeConfig := range typeConfigs {
+		for _, mutator := range typeConfig.Spec.DispatchMutators {
+			if mutator.ImageRewrite == nil {
+				continue
+			}
+			if mutator.ClusterSelector != nil {
+				selector, err := metav1.LabelSelectorAsSelector(mutator.ClusterSelector)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid cluster selector of dispatch mutator for %q", typeConfig.Name)
+				}
+				if !selector.Matches(labels.Set(cluster.Labels)) {
+					continue
+				}
+			}
+			rewrites = append(rewrites, *mutator.ImageRewrite)
+		}
+	}
+	return rewrites, nil
+}
+
+// mirrorDockerConfig returns the given docker config with the
+// credentials of each rewritten registry added for the registry mirror
+// it is rewritten to, unless the docker config already includes
+// credentials for the mirror. Whether the docker config was changed is
+// also returned.
+func mirrorDockerConfig(data []byte, rewrites []fedv1b1.ImageRewriteMutator) ([]byte, bool, error) {
+	if len(rewrites) == 0 || len(data) == 0 {
+		return data, false, nil
+	}
+	dockerConfig := make(map[string]interface{})
+	if err := json.Unmarshal(data, &dockerConfig); err != nil {
+		return nil, false, errors.Wrap(err, "invalid docker config")
+	}
+	auths, ok := dockerConfig["auths"].(map[string]interface{})
+	if !ok {
+		return data, false, nil
+	}
+
+	hostAuths := make(map[string]interface{})
+	for registry, auth := range auths {
+		hostAuths[registryHost(registry)] = auth
+	}
+	changed := false
+	for _, rewrite := range rewrites {
+		auth, ok := hostAuths[registryHost(rewrite.From)]
+		if !ok {
+			continue
+		}
+		mirror := registryHost(rewrite.To)
+		if _, ok := hostAuths[mirror]; ok {
+			continue
+		}
+		auths[mirror] = auth
+		hostAuths[mirror] = auth
+		changed = true
+	}
+	if !changed {
+		return data, false, nil
+	}
+	mirrored, err := json.Marshal(dockerConfig)
+	if err != nil {
+		return nil, false, err
+	}
+	return mirrored, true, nil
+}
+
+// registryHost returns the host of a registry given as a url or as an
+// image prefix (e.g. https://registry.example.com/v1/ or
+// registry.example.com/library/).
+func registryHost(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	return strings.SplitN(registry, "/", 2)[0]
+}
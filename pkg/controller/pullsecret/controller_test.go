@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsecret
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestMirrorDockerConfig(t *testing.T) {
+	dockerConfig := `{"auths":{"https://docker.io/v1/":{"auth":"c291cmNl"},"quay.io":{"auth":"cXVheQ=="}}}`
+
+	testCases := map[string]struct {
+		rewrites        []fedv1b1.ImageRewriteMutator
+		expectedChanged bool
+		expectedAuths   map[string]interface{}
+	}{
+		"No rewrites": {},
+		"Rewrite of a registry without credentials": {
+			rewrites: []fedv1b1.ImageRewriteMutator{
+				{From: "gcr.io/", To: "mirror.example.com/"},
+			},
+		},
+		"Rewrite to a mirror with credentials": {
+			rewrites: []fedv1b1.ImageRewriteMutator{
+				{From: "docker.io/", To: "quay.io/"},
+			},
+		},
+		"Rewrite to a mirror without credentials": {
+			rewrites: []fedv1b1.ImageRewriteMutator{
+				{From: "docker.io/library/", To: "mirror.example.com/library/"},
+			},
+			expectedChanged: true,
+			expectedAuths: map[string]interface{}{
+				"https://docker.io/v1/": map[string]interface{}{"auth": "c291cmNl"},
+				"quay.io":               map[string]interface{}{"auth": "cXVheQ=="},
+				"mirror.example.com":    map[string]interface{}{"auth": "c291cmNl"},
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			data, changed, err := mirrorDockerConfig([]byte(dockerConfig), tc.rewrites)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if changed != tc.expectedChanged {
+				t.Fatalf("Expected changed to be %v, got %v", tc.expectedChanged, changed)
+			}
+			if !changed {
+				if string(data) != dockerConfig {
+					t.Fatalf("Expected unchanged docker config, got %s", data)
+				}
+				return
+			}
+			result := make(map[string]interface{})
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result["auths"], tc.expectedAuths) {
+				t.Errorf("Expected auths %v, got %v", tc.expectedAuths, result["auths"])
+			}
+		})
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	testCases := map[string]string{
+		"https://index.docker.io/v1/":  "index.docker.io",
+		"registry.example.com":         "registry.example.com",
+		"registry.example.com/library": "registry.example.com",
+		"localhost:5000/":              "localhost:5000",
+	}
+	for registry, expected := range testCases {
+		if host := registryHost(registry); host != expected {
+			t.Errorf("Expected host %q for %q, got %q", expected, registry, host)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quarantine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclient "k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	genscheme "sigs.k8s.io/kubefed/pkg/client/generic/scheme"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// evaluationPeriod is how often the outcomes of the applies to
+	// each cluster are evaluated.
+	evaluationPeriod = 30 * time.Second
+
+	// QuarantineReason is the reason recorded for clusters quarantined
+	// for rejecting too many applies.
+	QuarantineReason = "ApplyFailureRate"
+)
+
+// outcome is the outcome of a single create or update in a cluster.
+type outcome struct {
+	time   time.Time
+	failed bool
+}
+
+// Monitor observes the outcome of the creates and updates performed by
+// the sync controllers and quarantines clusters that reject too many
+// of them.
+type Monitor struct {
+	sync.Mutex
+
+	client genericclient.Client
+
+	config fedv1b1.QuarantineConfig
+
+	// outcomes of the applies to each cluster within the window.
+	outcomes map[string][]outcome
+
+	// Store for the KubeFedCluster objects
+	store cache.Store
+	// Informer for the KubeFedCluster objects
+	controller cache.Controller
+
+	eventRecorder record.EventRecorder
+}
+
+// StartMonitor starts a Monitor for quarantining clusters.
+func StartMonitor(config *util.ControllerConfig, stopChan <-chan struct{}) (*Monitor, error) {
+	monitor, err := newMonitor(config)
+	if err != nil {
+		return nil, err
+	}
+	klog.Infof("Starting cluster quarantine monitor")
+	monitor.Run(stopChan)
+	return monitor, nil
+}
+
+// newMonitor returns a new Monitor for quarantining clusters.
+func newMonitor(config *util.ControllerConfig) (*Monitor, error) {
+	if config.Quarantine == nil {
+		return nil, errors.New("Quarantine configuration is required")
+	}
+
+	userAgent := "ClusterQuarantine"
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, userAgent)
+	client, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Monitor{
+		client:   client,
+		config:   *config.Quarantine,
+		outcomes: make(map[string][]outcome),
+	}
+
+	kubeClient := kubeclient.NewForConfigOrDie(kubeConfig)
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	m.eventRecorder = broadcaster.NewRecorder(genscheme.Scheme, corev1.EventSource{Component: "quarantine-monitor"})
+
+	m.store, m.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.KubeFedCluster{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) {},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Run runs the Monitor.
+func (m *Monitor) Run(stopChan <-chan struct{}) {
+	go m.controller.Run(stopChan)
+
+	// wait for the caches to synchronize before evaluating clusters
+	if !cache.WaitForCacheSync(stopChan, m.controller.HasSynced) {
+		utilruntime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	go wait.Until(m.evaluate, evaluationPeriod, stopChan)
+}
+
+// ObserveApply records the outcome of a create or update in the named
+// cluster.
+func (m *Monitor) ObserveApply(clusterName string, failed bool) {
+	m.Lock()
+	defer m.Unlock()
+	m.outcomes[clusterName] = append(m.outcomes[clusterName], outcome{time: time.Now(), failed: failed})
+}
+
+// evaluate quarantines clusters that rejected too many applies within
+// the window and releases clusters whose quarantine has expired.
+func (m *Monitor) evaluate() {
+	now := time.Now()
+	for _, obj := range m.store.List() {
+		cluster := obj.(*fedv1b1.KubeFedCluster).DeepCopy()
+		total, failed := m.countOutcomes(cluster.Name, now.Add(-m.config.Window.Duration))
+
+		quarantine := cluster.Status.Quarantine
+		if quarantine != nil {
+			metrics.RegisterKubefedClusterQuarantined(cluster.Name, true)
+			if m.config.ReleaseAfter != nil && now.Sub(quarantine.StartTime.Time) >= m.config.ReleaseAfter.Duration {
+				m.release(cluster)
+			}
+			continue
+		}
+		metrics.RegisterKubefedClusterQuarantined(cluster.Name, false)
+
+		if !shouldQuarantine(total, failed, m.config) {
+			continue
+		}
+		cluster.Status.Quarantine = &fedv1b1.ClusterQuarantine{
+			Reason:    QuarantineReason,
+			Message:   fmt.Sprintf("%d of %d creates and updates failed within %v", failed, total, m.config.Window.Duration),
+			StartTime: metav1.NewTime(now),
+		}
+		err := m.client.UpdateStatus(context.TODO(), cluster)
+		if err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "Failed to quarantine cluster %q", cluster.Name))
+			continue
+		}
+		klog.Warningf("Quarantined cluster %q: %s", cluster.Name, cluster.Status.Quarantine.Message)
+		m.eventRecorder.Eventf(cluster, corev1.EventTypeWarning, "ClusterQuarantined",
+			"Propagation to the cluster is suspended: %s", cluster.Status.Quarantine.Message)
+		metrics.RegisterKubefedClusterQuarantined(cluster.Name, true)
+		m.resetOutcomes(cluster.Name)
+	}
+}
+
+func (m *Monitor) release(cluster *fedv1b1.KubeFedCluster) {
+	cluster.Status.Quarantine = nil
+	err := m.client.UpdateStatus(context.TODO(), cluster)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "Failed to release quarantine of cluster %q", cluster.Name))
+		return
+	}
+	klog.Infof("Released quarantine of cluster %q", cluster.Name)
+	m.eventRecorder.Eventf(cluster, corev1.EventTypeNormal, "QuarantineReleased",
+		"Quarantine released after %v", m.config.ReleaseAfter.Duration)
+	metrics.RegisterKubefedClusterQuarantined(cluster.Name, false)
+	m.resetOutcomes(cluster.Name)
+}
+
+// countOutcomes discards the outcomes for the named cluster that
+// precede the given time and returns the number of remaining outcomes
+// and the number of those that failed.
+func (m *Monitor) countOutcomes(clusterName string, since time.Time) (int, int) {
+	m.Lock()
+	defer m.Unlock()
+	outcomes := m.outcomes[clusterName]
+	i := 0
+	for i < len(outcomes) && outcomes[i].time.Before(since) {
+		i++
+	}
+	outcomes = outcomes[i:]
+	m.outcomes[clusterName] = outcomes
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.failed {
+			failed++
+		}
+	}
+	return len(outcomes), failed
+}
+
+func (m *Monitor) resetOutcomes(clusterName string) {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.outcomes, clusterName)
+}
+
+// shouldQuarantine returns whether a cluster for which the given number
+// of applies failed out of the given total should be quarantined.
+func shouldQuarantine(total, failed int, config fedv1b1.QuarantineConfig) bool {
+	if total == 0 || total < int(*config.MinimumOperations) {
+		return false
+	}
+	return failed*100 >= int(*config.FailurePercentage)*total
+}
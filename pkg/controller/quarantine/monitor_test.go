@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quarantine
+
+import (
+	"testing"
+	"time"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestShouldQuarantine(t *testing.T) {
+	failurePercentage := int32(50)
+	minimumOperations := int32(20)
+	config := fedv1b1.QuarantineConfig{
+		FailurePercentage: &failurePercentage,
+		MinimumOperations: &minimumOperations,
+	}
+
+	testCases := map[string]struct {
+		total    int
+		failed   int
+		expected bool
+	}{
+		"No operations": {
+			total:    0,
+			failed:   0,
+			expected: false,
+		},
+		"Too few operations": {
+			total:    19,
+			failed:   19,
+			expected: false,
+		},
+		"Failure rate below threshold": {
+			total:    40,
+			failed:   19,
+			expected: false,
+		},
+		"Failure rate at threshold": {
+			total:    40,
+			failed:   20,
+			expected: true,
+		},
+		"All operations failed": {
+			total:    20,
+			failed:   20,
+			expected: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			if result := shouldQuarantine(tc.total, tc.failed, config); result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCountOutcomes(t *testing.T) {
+	now := time.Now()
+	m := &Monitor{
+		outcomes: map[string][]outcome{
+			"cluster1": {
+				{time: now.Add(-10 * time.Minute), failed: true},
+				{time: now.Add(-2 * time.Minute), failed: true},
+				{time: now.Add(-time.Minute), failed: false},
+			},
+		},
+	}
+
+	total, failed := m.countOutcomes("cluster1", now.Add(-5*time.Minute))
+	if total != 2 || failed != 1 {
+		t.Errorf("Expected 1 of 2 operations to have failed, got %d of %d", failed, total)
+	}
+	if len(m.outcomes["cluster1"]) != 2 {
+		t.Errorf("Expected outcomes outside the window to be discarded, got %v", m.outcomes["cluster1"])
+	}
+}
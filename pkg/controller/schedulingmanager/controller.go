@@ -93,7 +93,7 @@ func newSchedulingManager(config *util.ControllerConfig) (*SchedulingManager, er
 		schedulers: util.NewSafeMap(),
 	}
 
-	c.worker = util.NewReconcileWorker(c.reconcile, util.WorkerTiming{})
+	c.worker = util.NewReconcileWorker("schedulingmanagercontroller", c.reconcile, util.WorkerTiming{})
 
 	var err error
 	c.store, c.controller, err = util.NewGenericInformer(
@@ -104,7 +104,7 @@ func newSchedulingPreferenceController(config *util.ControllerConfig, scheduling
 		eventRecorder:           recorder,
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(userAgent, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
@@ -123,7 +123,16 @@ func newSchedulingPreferenceController(config *util.ControllerConfig, scheduling
 			ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
 				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(s.clusterUnavailableDelay))
 			},
+			// When a cluster is cordoned or uncordoned, the replicas
+			// it may be scheduled change.
+			ClusterCordonChanged: func(cluster *fedv1b1.KubeFedCluster) {
+				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+			},
+		},
+		RecheckHandler: func(qualifiedName util.QualifiedName, delay time.Duration) {
+			s.worker.EnqueueWithDelay(qualifiedName, delay)
 		},
+		EventRecorder: recorder,
 	}
 	scheduler, err := schedulingType.SchedulerFactory(config, eventHandlers)
 	if err != nil {
@@ -102,7 +102,7 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 		fedNamespace:            config.KubeFedNamespace,
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker("servicednscontroller", s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
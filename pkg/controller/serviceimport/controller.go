@@ -0,0 +1,484 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceimport
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	allClustersKey = "ALL_CLUSTERS"
+
+	ServiceImportKind = "ServiceImport"
+
+	// ServiceImport types defined by the Multi-Cluster Services API.
+	ClusterSetIPType = "ClusterSetIP"
+	HeadlessType     = "Headless"
+)
+
+var (
+	federatedServiceAPIResource = metav1.APIResource{
+		Group:        "types.kubefed.io",
+		Version:      "v1beta1",
+		Kind:         "FederatedService",
+		Name:         "federatedservices",
+		SingularName: "federatedservice",
+		Namespaced:   true,
+	}
+
+	endpointSliceAPIResource = metav1.APIResource{
+		Group:        "discovery.k8s.io",
+		Version:      "v1beta1",
+		Kind:         "EndpointSlice",
+		Name:         "endpointslices",
+		SingularName: "endpointslice",
+		Namespaced:   true,
+	}
+
+	serviceImportAPIResource = metav1.APIResource{
+		Group:        "multicluster.x-k8s.io",
+		Version:      "v1alpha1",
+		Kind:         ServiceImportKind,
+		Name:         "serviceimports",
+		SingularName: "serviceimport",
+		Namespaced:   true,
+	}
+)
+
+// Controller maintains Multi-Cluster Services API ServiceImport
+// resources in member clusters for federated services.
+type Controller struct {
+	// For triggering reconciliation of all target resources. This is
+	// used when a new cluster becomes available.
+	clusterDeliverer *util.DelayingDeliverer
+
+	// Store for FederatedService resources in the host cluster
+	federatedServiceStore cache.Store
+	// Informer for FederatedService resources in the host cluster
+	federatedServiceController cache.Controller
+
+	// Informer for endpointslice resources in member clusters
+	endpointSliceInformer util.FederatedInformer
+
+	// Informer for serviceimport resources in member clusters
+	serviceImportInformer util.FederatedInformer
+
+	worker util.ReconcileWorker
+
+	clusterAvailableDelay   time.Duration
+	clusterUnavailableDelay time.Duration
+	smallDelay              time.Duration
+
+	// The name of the KubeFed instance to label created resources
+	// with, if any.
+	instanceName string
+}
+
+// StartController starts the Controller for managing ServiceImport objects.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	if config.MinimizeLatency {
+		controller.minimizeLatency()
+	}
+	klog.Infof("Starting ServiceImport controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to manage ServiceImport objects.
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, "ServiceImport")
+	c := &Controller{
+		clusterAvailableDelay:   config.ClusterAvailableDelay,
+		clusterUnavailableDelay: config.ClusterUnavailableDelay,
+		smallDelay:              time.Second * 3,
+		instanceName:            config.InstanceName,
+	}
+
+	c.worker = util.NewReconcileWorker("serviceimportcontroller", c.reconcile, util.WorkerTiming{
+		ClusterSyncDelay: c.clusterAvailableDelay,
+	})
+
+	// Build deliverer for triggering cluster reconciliations.
+	c.clusterDeliverer = util.NewDelayingDeliverer()
+
+	federatedServiceClient, err := util.NewResourceClient(config.KubeConfig, &federatedServiceAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	c.federatedServiceStore, c.federatedServiceController = util.NewResourceInformer(
+		federatedServiceClient,
+		config.TargetNamespace,
+		&federatedServiceAPIResource,
+		c.worker.EnqueueObject,
+	)
+
+	clusterLifecycle := &util.ClusterLifecycleHandlerFuncs{
+		ClusterAvailable: func(cluster *fedv1b1.KubeFedCluster) {
+			// When new cluster becomes available process all the target resources again.
+			c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+		},
+		// When a cluster becomes unavailable process all the target resources again.
+		ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
+			c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterUnavailableDelay))
+		},
+	}
+
+	// EndpointSlices inherit the labels of their service, so slices
+	// of services propagated by KubeFed will be visible to the
+	// managed resource informer.
+	c.endpointSliceInformer, err = util.NewFederatedInformer(
+		config,
+		client,
+		&endpointSliceAPIResource,
+		c.enqueueEndpointSlice,
+		clusterLifecycle,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.serviceImportInformer, err = util.NewFederatedInformer(
+		config,
+		client,
+		&serviceImportAPIResource,
+		c.worker.EnqueueObject,
+		&util.ClusterLifecycleHandlerFuncs{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// minimizeLatency reduces delays and timeouts to make the controller more responsive (useful for testing).
+func (c *Controller) minimizeLatency() {
+	c.clusterAvailableDelay = time.Second
+	c.clusterUnavailableDelay = time.Second
+	c.smallDelay = 20 * time.Millisecond
+	c.worker.SetDelay(50*time.Millisecond, c.clusterAvailableDelay)
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.federatedServiceController.Run(stopChan)
+	c.endpointSliceInformer.Start()
+	c.serviceImportInformer.Start()
+	c.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
+		c.reconcileOnClusterChange()
+	})
+
+	c.worker.Run(stopChan)
+
+	// Ensure all goroutines are cleaned up when the stop channel closes
+	go func() {
+		<-stopChan
+		c.endpointSliceInformer.Stop()
+		c.serviceImportInformer.Stop()
+		c.clusterDeliverer.Stop()
+	}()
+}
+
+// enqueueEndpointSlice enqueues the service that owns the given
+// endpointslice.
+func (c *Controller) enqueueEndpointSlice(obj pkgruntime.Object) {
+	slice := util.MetaAccessor(obj)
+	serviceName, ok := slice.GetLabels()[util.ServiceNameLabel]
+	if !ok {
+		return
+	}
+	c.worker.EnqueueForRetry(util.QualifiedName{Namespace: slice.GetNamespace(), Name: serviceName})
+}
+
+// Check whether all data stores are in sync. False is returned if any of the informer/stores is not yet
+// synced with the corresponding api server.
+func (c *Controller) isSynced() bool {
+	if !c.federatedServiceController.HasSynced() {
+		klog.V(2).Infof("FederatedService not synced")
+		return false
+	}
+	for _, informer := range []util.FederatedInformer{c.endpointSliceInformer, c.serviceImportInformer} {
+		if !informer.ClustersSynced() {
+			klog.V(2).Infof("Cluster list not synced")
+			return false
+		}
+		clusters, err := informer.GetReadyClusters()
+		if err != nil {
+			runtime.HandleError(errors.Wrap(err, "Failed to get ready clusters"))
+			return false
+		}
+		if !informer.GetTargetStore().ClustersSynced(clusters) {
+			return false
+		}
+	}
+	return true
+}
+
+// The function triggers reconciliation of all target federated resources.
+func (c *Controller) reconcileOnClusterChange() {
+	if !c.isSynced() {
+		c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+	}
+	for _, obj := range c.federatedServiceStore.List() {
+		qualifiedName := util.NewQualifiedName(obj.(pkgruntime.Object))
+		c.worker.EnqueueWithDelay(qualifiedName, c.smallDelay)
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	defer metrics.UpdateControllerReconcileDurationFromStart("serviceimportcontroller", time.Now())
+
+	if !c.isSynced() {
+		return util.StatusNotSynced
+	}
+
+	key := qualifiedName.String()
+
+	klog.V(4).Infof("Starting to reconcile ServiceImport for %v", key)
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished reconciling ServiceImport for %v (duration: %v)", key, time.Since(startTime))
+	}()
+
+	cachedObj, exist, err := c.federatedServiceStore.GetByKey(key)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to query FederatedService store for %q", key))
+		return util.StatusError
+	}
+
+	var desiredImport *unstructured.Unstructured
+	consumingClusters := sets.String{}
+	if exist {
+		fedService := cachedObj.(*unstructured.Unstructured)
+		if fedService.GetDeletionTimestamp() == nil {
+			consumingClusters, err = status.PropagatedClusterNames(fedService)
+			if err != nil {
+				runtime.HandleError(errors.Wrapf(err, "Failed to determine propagated clusters for FederatedService %q", key))
+				return util.StatusError
+			}
+			desiredImport, err = c.serviceImportForService(fedService, consumingClusters, key)
+			if err != nil {
+				runtime.HandleError(errors.Wrapf(err, "Failed to compute ServiceImport for FederatedService %q", key))
+				return util.StatusError
+			}
+		}
+	}
+
+	clusters, err := c.serviceImportInformer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready cluster list"))
+		return util.StatusError
+	}
+
+	result := util.StatusAllOK
+	for _, cluster := range clusters {
+		var clusterErr error
+		if desiredImport != nil && consumingClusters.Has(cluster.Name) {
+			clusterErr = c.ensureServiceImport(cluster.Name, key, desiredImport)
+		} else {
+			clusterErr = c.removeServiceImport(cluster.Name, key)
+		}
+		if clusterErr != nil {
+			runtime.HandleError(errors.Wrapf(clusterErr, "Failed to reconcile ServiceImport %q in cluster %q", key, cluster.Name))
+			result = util.StatusNeedsRecheck
+		}
+	}
+	return result
+}
+
+// serviceImportForService computes the ServiceImport that represents
+// the given federated service. The clusters listed in the status of
+// the import are the propagated clusters whose endpointslices for the
+// service contain at least one ready endpoint.
+func (c *Controller) serviceImportForService(fedService *unstructured.Unstructured, propagated sets.String, key string) (*unstructured.Unstructured, error) {
+	exportingClusters := []string{}
+	for _, clusterName := range propagated.List() {
+		slices, err := c.endpointSliceInformer.GetTargetStore().ListFromCluster(clusterName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list endpointslices from cluster %q", clusterName)
+		}
+		ready, err := hasReadyEndpoints(slices, fedService.GetNamespace(), fedService.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if ready {
+			exportingClusters = append(exportingClusters, clusterName)
+		}
+	}
+	return newServiceImport(fedService, exportingClusters, c.instanceName)
+}
+
+// newServiceImport returns the ServiceImport for the given federated
+// service that lists the given clusters as exporting the service.
+func newServiceImport(fedService *unstructured.Unstructured, exportingClusterNames []string, instanceName string) (*unstructured.Unstructured, error) {
+	serviceSpec, _, err := unstructured.NestedMap(fedService.Object, util.SpecField, util.TemplateField, util.SpecField)
+	if err != nil {
+		return nil, err
+	}
+
+	importType := ClusterSetIPType
+	if clusterIP, ok := serviceSpec["clusterIP"].(string); ok && clusterIP == "None" {
+		importType = HeadlessType
+	}
+
+	ports := []interface{}{}
+	if servicePorts, ok := serviceSpec["ports"].([]interface{}); ok {
+		for _, p := range servicePorts {
+			servicePort, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			port := map[string]interface{}{}
+			for _, field := range []string{"name", "protocol", "port"} {
+				if value, ok := servicePort[field]; ok {
+					port[field] = value
+				}
+			}
+			ports = append(ports, port)
+		}
+	}
+
+	exportingClusters := []interface{}{}
+	for _, clusterName := range exportingClusterNames {
+		exportingClusters = append(exportingClusters, map[string]interface{}{"cluster": clusterName})
+	}
+
+	serviceImport := &unstructured.Unstructured{}
+	serviceImport.SetAPIVersion(metav1.GroupVersion{Group: serviceImportAPIResource.Group, Version: serviceImportAPIResource.Version}.String())
+	serviceImport.SetKind(ServiceImportKind)
+	serviceImport.SetName(fedService.GetName())
+	serviceImport.SetNamespace(fedService.GetNamespace())
+	util.AddManagedLabel(serviceImport)
+	util.AddInstanceLabel(serviceImport, instanceName)
+	serviceImport.Object[util.SpecField] = map[string]interface{}{
+		"type":  importType,
+		"ports": ports,
+	}
+	serviceImport.Object[util.StatusField] = map[string]interface{}{
+		util.ClustersField: exportingClusters,
+	}
+	return serviceImport, nil
+}
+
+// hasReadyEndpoints indicates whether the given endpointslices of a
+// cluster contain at least one ready endpoint for the named service.
+func hasReadyEndpoints(slices []interface{}, namespace, serviceName string) (bool, error) {
+	for _, obj := range slices {
+		slice := obj.(*unstructured.Unstructured)
+		if slice.GetNamespace() != namespace || slice.GetLabels()[util.ServiceNameLabel] != serviceName {
+			continue
+		}
+		endpoints, _, err := unstructured.NestedSlice(slice.Object, "endpoints")
+		if err != nil {
+			return false, err
+		}
+		for _, e := range endpoints {
+			endpoint, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// A nil ready condition is to be interpreted as ready.
+			ready, found, err := unstructured.NestedBool(endpoint, "conditions", "ready")
+			if err != nil || !found || ready {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (c *Controller) ensureServiceImport(clusterName, key string, desired *unstructured.Unstructured) error {
+	client, err := c.serviceImportInformer.GetClientForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	desired = desired.DeepCopy()
+	desired.SetNamespace(util.NamespaceForCluster(clusterName, desired.GetNamespace()))
+
+	cachedObj, exist, err := c.serviceImportInformer.GetTargetStore().GetByKey(clusterName, key)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		klog.V(4).Infof("Creating ServiceImport %q in cluster %q", key, clusterName)
+		// The status subresource is ignored on creation and must
+		// be written separately.
+		desiredStatus := desired.Object[util.StatusField]
+		err := client.Create(context.Background(), desired)
+		if err != nil {
+			return err
+		}
+		desired.Object[util.StatusField] = desiredStatus
+		return client.UpdateStatus(context.Background(), desired)
+	}
+
+	clusterObj := cachedObj.(*unstructured.Unstructured).DeepCopy()
+	if !reflect.DeepEqual(clusterObj.Object[util.SpecField], desired.Object[util.SpecField]) {
+		klog.V(4).Infof("Updating ServiceImport %q in cluster %q", key, clusterName)
+		clusterObj.Object[util.SpecField] = desired.Object[util.SpecField]
+		err := client.Update(context.Background(), clusterObj)
+		if err != nil {
+			return err
+		}
+	}
+	if !reflect.DeepEqual(clusterObj.Object[util.StatusField], desired.Object[util.StatusField]) {
+		clusterObj.Object[util.StatusField] = desired.Object[util.StatusField]
+		return client.UpdateStatus(context.Background(), clusterObj)
+	}
+	return nil
+}
+
+func (c *Controller) removeServiceImport(clusterName, key string) error {
+	cachedObj, exist, err := c.serviceImportInformer.GetTargetStore().GetByKey(clusterName, key)
+	if err != nil || !exist {
+		return err
+	}
+	clusterObj := cachedObj.(*unstructured.Unstructured)
+	client, err := c.serviceImportInformer.GetClientForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("Deleting ServiceImport %q in cluster %q", key, clusterName)
+	err = client.Delete(context.Background(), clusterObj, clusterObj.GetNamespace(), clusterObj.GetName())
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceimport
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestNewServiceImport(t *testing.T) {
+	testCases := map[string]struct {
+		clusterIP    string
+		expectedType string
+	}{
+		"Service with a cluster IP is imported with a cluster set IP": {
+			expectedType: ClusterSetIPType,
+		},
+		"Headless service is imported as headless": {
+			clusterIP:    "None",
+			expectedType: HeadlessType,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			serviceSpec := map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"name": "http", "port": int64(80), "protocol": "TCP", "targetPort": int64(8080)},
+				},
+			}
+			if len(tc.clusterIP) > 0 {
+				serviceSpec["clusterIP"] = tc.clusterIP
+			}
+			fedService := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": serviceSpec,
+					},
+				},
+			}}
+			fedService.SetName("svc")
+			fedService.SetNamespace("ns")
+
+			serviceImport, err := newServiceImport(fedService, []string{"cluster1", "cluster2"}, "")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if serviceImport.GetName() != "svc" || serviceImport.GetNamespace() != "ns" {
+				t.Errorf("Expected the import to be named ns/svc, got %s/%s", serviceImport.GetNamespace(), serviceImport.GetName())
+			}
+			if !util.HasManagedLabel(serviceImport) {
+				t.Errorf("Expected the import to be labeled as managed")
+			}
+			importType, _, _ := unstructured.NestedString(serviceImport.Object, "spec", "type")
+			if importType != tc.expectedType {
+				t.Errorf("Expected type %q, got %q", tc.expectedType, importType)
+			}
+			// Only the fields of ports defined by the Multi-Cluster
+			// Services API are imported.
+			ports, _, _ := unstructured.NestedSlice(serviceImport.Object, "spec", "ports")
+			expectedPorts := []interface{}{
+				map[string]interface{}{"name": "http", "port": int64(80), "protocol": "TCP"},
+			}
+			if !reflect.DeepEqual(ports, expectedPorts) {
+				t.Errorf("Expected ports %v, got %v", expectedPorts, ports)
+			}
+			clusters, _, _ := unstructured.NestedSlice(serviceImport.Object, "status", "clusters")
+			expectedClusters := []interface{}{
+				map[string]interface{}{"cluster": "cluster1"},
+				map[string]interface{}{"cluster": "cluster2"},
+			}
+			if !reflect.DeepEqual(clusters, expectedClusters) {
+				t.Errorf("Expected clusters %v, got %v", expectedClusters, clusters)
+			}
+		})
+	}
+}
+
+func TestHasReadyEndpoints(t *testing.T) {
+	newSlice := func(namespace, serviceName string, endpoints ...interface{}) interface{} {
+		slice := &unstructured.Unstructured{Object: map[string]interface{}{
+			"endpoints": endpoints,
+		}}
+		slice.SetNamespace(namespace)
+		slice.SetLabels(map[string]string{util.ServiceNameLabel: serviceName})
+		return slice
+	}
+	endpoint := func(ready bool) interface{} {
+		return map[string]interface{}{
+			"addresses":  []interface{}{"10.0.0.1"},
+			"conditions": map[string]interface{}{"ready": ready},
+		}
+	}
+
+	testCases := map[string]struct {
+		slices        []interface{}
+		expectedReady bool
+	}{
+		"No endpointslices": {},
+		"Only endpoints that are not ready": {
+			slices: []interface{}{newSlice("ns", "svc", endpoint(false))},
+		},
+		"Ready endpoint": {
+			slices:        []interface{}{newSlice("ns", "svc", endpoint(false), endpoint(true))},
+			expectedReady: true,
+		},
+		"Endpoint without a ready condition is ready": {
+			slices: []interface{}{newSlice("ns", "svc", map[string]interface{}{
+				"addresses": []interface{}{"10.0.0.1"},
+			})},
+			expectedReady: true,
+		},
+		"Ready endpoints of other services are ignored": {
+			slices: []interface{}{
+				newSlice("ns", "other", endpoint(true)),
+				newSlice("other", "svc", endpoint(true)),
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			ready, err := hasReadyEndpoints(tc.slices, "ns", "svc")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ready != tc.expectedReady {
+				t.Errorf("Expected ready to be %v, got %v", tc.expectedReady, ready)
+			}
+		})
+	}
+}
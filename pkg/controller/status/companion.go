@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	// CompanionOfAnnotation identifies the status object a companion
+	// status object holds the status of a single cluster for. An
+	// annotation is used since the name of a status object may be too
+	// long for a label value.
+	CompanionOfAnnotation = "kubefed.io/status-companion-of"
+
+	// CompanionsAnnotation is set on a status object whose per-cluster
+	// status is stored in companion status objects.
+	CompanionsAnnotation = "kubefed.io/status-companions"
+)
+
+// companionName returns the name of the companion status object
+// holding the status of the given cluster.
+func companionName(name, clusterName string) string {
+	return fmt.Sprintf("%s-%s", name, clusterName)
+}
+
+// hasCompanions returns whether the per-cluster status of the given
+// status object is stored in companion status objects.
+func hasCompanions(status *unstructured.Unstructured) bool {
+	return status != nil && status.GetAnnotations()[CompanionsAnnotation] == "true"
+}
+
+// splitStatus returns the given status unchanged if it does not exceed
+// the size limit of federated resources. Otherwise, the status of
+// each cluster is moved to a companion status object, and the
+// returned status only lists the names of the clusters.
+func splitStatus(status util.FederatedResource) (util.FederatedResource, []util.FederatedResource, error) {
+	size, err := util.ObjectSize(status)
+	if err != nil {
+		return status, nil, err
+	}
+	if size <= util.MaxFederatedResourceSize {
+		return status, nil, nil
+	}
+
+	primary := status
+	primary.Annotations = map[string]string{CompanionsAnnotation: "true"}
+	primary.ClusterStatus = make([]util.ResourceClusterStatus, len(status.ClusterStatus))
+	companions := make([]util.FederatedResource, 0, len(status.ClusterStatus))
+	for i, clusterStatus := range status.ClusterStatus {
+		primary.ClusterStatus[i] = util.ResourceClusterStatus{ClusterName: clusterStatus.ClusterName, Health: clusterStatus.Health}
+		companions = append(companions, util.FederatedResource{
+			TypeMeta: status.TypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            companionName(status.Name, clusterStatus.ClusterName),
+				Namespace:       status.Namespace,
+				Annotations:     map[string]string{CompanionOfAnnotation: status.Name},
+				OwnerReferences: status.OwnerReferences,
+			},
+			ClusterStatus: []util.ResourceClusterStatus{clusterStatus},
+		})
+	}
+	return primary, companions, nil
+}
+
+// reconcileCompanions ensures that the companion status objects of the
+// status object with the given name match the given companions, and
+// returns whether any of them changed.
+func (s *KubeFedStatusController) reconcileCompanions(qualifiedName util.QualifiedName, companions []util.FederatedResource) (bool, error) {
+	client := s.statusClient.Resources(qualifiedName.Namespace)
+
+	desired := make(map[string]*unstructured.Unstructured)
+	for _, companion := range companions {
+		obj, err := util.GetUnstructured(companion)
+		if err != nil {
+			return false, err
+		}
+		desired[obj.GetName()] = obj
+	}
+
+	changed := false
+	for _, cachedObj := range s.statusStore.List() {
+		existing := cachedObj.(*unstructured.Unstructured)
+		if existing.GetNamespace() != qualifiedName.Namespace || existing.GetAnnotations()[CompanionOfAnnotation] != qualifiedName.Name {
+			continue
+		}
+		companion, ok := desired[existing.GetName()]
+		if !ok {
+			err := client.Delete(existing.GetName(), &metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return changed, errors.Wrapf(err, "Failed to delete companion status object %q", existing.GetName())
+			}
+			changed = true
+			continue
+		}
+		delete(desired, existing.GetName())
+		if reflect.DeepEqual(existing.Object["clusterStatus"], companion.Object["clusterStatus"]) {
+			continue
+		}
+		updated := existing.DeepCopy()
+		updated.Object["clusterStatus"] = companion.Object["clusterStatus"]
+		_, err := client.Update(updated, metav1.UpdateOptions{})
+		if err != nil {
+			return changed, errors.Wrapf(err, "Failed to update companion status object %q", existing.GetName())
+		}
+		changed = true
+	}
+
+	for name, companion := range desired {
+		_, err := client.Create(companion, metav1.CreateOptions{})
+		if err != nil {
+			return changed, errors.Wrapf(err, "Failed to create companion status object %q", name)
+		}
+		changed = true
+	}
+	return changed, nil
+}
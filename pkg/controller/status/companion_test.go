@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestSplitStatus(t *testing.T) {
+	newStatus := func(messageSize int) util.FederatedResource {
+		message := strings.Repeat("x", messageSize)
+		return util.FederatedResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			ClusterStatus: []util.ResourceClusterStatus{
+				{ClusterName: "cluster1", Status: map[string]interface{}{"message": message}},
+				{ClusterName: "cluster2", Status: map[string]interface{}{"message": message}},
+			},
+		}
+	}
+
+	t.Run("Status within the size limit", func(t *testing.T) {
+		status := newStatus(1024)
+		primary, companions, err := splitStatus(status)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(companions) != 0 {
+			t.Fatalf("Expected no companions, got %d", len(companions))
+		}
+		if primary.ClusterStatus[0].Status == nil || primary.Annotations != nil {
+			t.Fatalf("Expected the status to be unchanged, got %v", primary)
+		}
+	})
+
+	t.Run("Status exceeding the size limit", func(t *testing.T) {
+		status := newStatus(util.MaxFederatedResourceSize / 2)
+		primary, companions, err := splitStatus(status)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if primary.Annotations[CompanionsAnnotation] != "true" {
+			t.Errorf("Expected annotation %q to be set", CompanionsAnnotation)
+		}
+		if len(companions) != len(status.ClusterStatus) {
+			t.Fatalf("Expected %d companions, got %d", len(status.ClusterStatus), len(companions))
+		}
+		for i, clusterStatus := range status.ClusterStatus {
+			if primary.ClusterStatus[i].ClusterName != clusterStatus.ClusterName || primary.ClusterStatus[i].Status != nil {
+				t.Errorf("Expected only the cluster name for %q, got %v", clusterStatus.ClusterName, primary.ClusterStatus[i])
+			}
+			companion := companions[i]
+			if companion.Name != "foo-"+clusterStatus.ClusterName || companion.Namespace != "bar" {
+				t.Errorf("Unexpected companion %s/%s", companion.Namespace, companion.Name)
+			}
+			if companion.Annotations[CompanionOfAnnotation] != "foo" {
+				t.Errorf("Expected companion of %q, got %q", "foo", companion.Annotations[CompanionOfAnnotation])
+			}
+			if len(companion.ClusterStatus) != 1 || companion.ClusterStatus[0].Status["message"] != clusterStatus.Status["message"] {
+				t.Errorf("Expected the companion to hold the status of %q", clusterStatus.ClusterName)
+			}
+		}
+		if status.ClusterStatus[0].Status == nil {
+			t.Errorf("Expected the given status to be unmodified")
+		}
+	})
+}
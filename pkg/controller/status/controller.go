@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +31,7 @@ import (
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
 
@@ -37,6 +39,9 @@ import (
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+	"sigs.k8s.io/kubefed/pkg/features"
+	"sigs.k8s.io/kubefed/pkg/logging"
 	"sigs.k8s.io/kubefed/pkg/metrics"
 )
 
@@ -66,12 +71,29 @@ type KubeFedStatusController struct {
 
 	worker util.ReconcileWorker
 
+	logger logr.Logger
+
 	clusterAvailableDelay   time.Duration
 	clusterUnavailableDelay time.Duration
 	smallDelay              time.Duration
 
+	// intervals, if set, adapts how soon the status of a resource is
+	// collected after it changes in a member cluster to how often its
+	// status changes.
+	intervals *collectionIntervals
+
+	// companionObjects indicates whether the status of each cluster
+	// is stored in a companion status object when the status of a
+	// resource exceeds the size limit of federated resources.
+	companionObjects bool
+
 	typeConfig typeconfig.Interface
 
+	// Interprets the health of resources in member clusters. Nil if
+	// the HealthInterpretation feature is disabled or the health of
+	// the target type cannot be interpreted.
+	healthInterpreter health.Interpreter
+
 	client       genericclient.Client
 	statusClient util.ResourceClient
 
@@ -116,16 +138,29 @@ func newKubeFedStatusController(controllerConfig *util.ControllerConfig, typeCon
 		clusterAvailableDelay:   controllerConfig.ClusterAvailableDelay,
 		clusterUnavailableDelay: controllerConfig.ClusterUnavailableDelay,
 		smallDelay:              time.Second * 3,
+		logger:                  logging.NewLogger("status-controller").WithValues(logging.FTCKey, typeConfig.GetObjectMeta().Name),
 		typeConfig:              typeConfig,
 		client:                  client,
 		statusClient:            statusClient,
 		fedNamespace:            controllerConfig.KubeFedNamespace,
+		companionObjects:        utilfeature.DefaultFeatureGate.Enabled(features.StatusCompanionObjects),
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(userAgent, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.AdaptiveStatusCollection) && controllerConfig.StatusCollection != nil {
+		s.intervals = newCollectionIntervals(controllerConfig.StatusCollection)
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.HealthInterpretation) {
+		s.healthInterpreter, err = health.ForTypeConfig(typeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Build deliverer for triggering cluster reconciliations.
 	s.clusterDeliverer = util.NewDelayingDeliverer()
 
@@ -138,13 +173,23 @@ func newKubeFedStatusController(controllerConfig *util.ControllerConfig, typeCon
 	s.federatedStore, s.federatedController = util.NewResourceInformer(federatedTypeClient, targetNamespace, &targetAPIResource, enqueueObj)
 	s.statusStore, s.statusController = util.NewResourceInformer(statusClient, targetNamespace, statusAPIResource, enqueueObj)
 
-	// Federated informer for resources in member clusters
-	s.informer, err = util.NewFederatedInformer(
+	// Federated informer for resources in member clusters. The watch
+	// of a cluster that becomes available again may be resumed rather
+	// than listing all resources of the cluster again.
+	newFederatedInformer := util.NewFederatedInformer
+	if utilfeature.DefaultFeatureGate.Enabled(features.ResumableStatusWatches) {
+		newFederatedInformer = util.NewResumableFederatedInformer
+	}
+	s.informer, err = newFederatedInformer(
 		controllerConfig,
 		client,
 		&targetAPIResource,
 		func(obj pkgruntime.Object) {
-			qualifiedName := util.NewQualifiedName(obj)
+			qualifiedName := util.NewSourceQualifiedName(obj)
+			if s.intervals != nil {
+				s.worker.EnqueueWithDelay(qualifiedName, s.intervals.get(qualifiedName.String()))
+				return
+			}
 			s.worker.EnqueueForRetry(qualifiedName)
 		},
 		&util.ClusterLifecycleHandlerFuncs{
@@ -196,15 +241,15 @@ func (s *KubeFedStatusController) Run(stopChan <-chan struct{}) {
 // synced with the corresponding api server.
 func (s *KubeFedStatusController) isSynced() bool {
 	if !s.informer.ClustersSynced() {
-		klog.V(2).Infof("Cluster list not synced")
+		s.logger.V(2).Info("Cluster list not synced")
 		return false
 	}
 	if !s.federatedController.HasSynced() {
-		klog.V(2).Infof("Federated type not synced")
+		s.logger.V(2).Info("Federated type not synced")
 		return false
 	}
 	if !s.statusController.HasSynced() {
-		klog.V(2).Infof("Status not synced")
+		s.logger.V(2).Info("Status not synced")
 		return false
 	}
 
@@ -240,11 +285,12 @@ func (s *KubeFedStatusController) reconcile(qualifiedName util.QualifiedName) ut
 	federatedKind := s.typeConfig.GetFederatedType().Kind
 	statusKind := s.typeConfig.GetStatusType().Kind
 	key := qualifiedName.String()
+	logger := s.logger.WithValues(logging.QualifiedNameKey, qualifiedName, logging.ReconcileIDKey, logging.NewReconcileID())
 
-	klog.V(4).Infof("Starting to reconcile %v %v", statusKind, key)
+	logger.V(4).Info("Starting to reconcile", "kind", statusKind)
 	startTime := time.Now()
 	defer func() {
-		klog.V(4).Infof("Finished reconciling %v %v (duration: %v)", statusKind, key, time.Since(startTime))
+		logger.V(4).Info("Finished reconciling", "kind", statusKind, "duration", time.Since(startTime))
 	}()
 
 	fedObject, err := s.objFromCache(s.federatedStore, federatedKind, key)
@@ -253,7 +299,10 @@ func (s *KubeFedStatusController) reconcile(qualifiedName util.QualifiedName) ut
 	}
 
 	if fedObject == nil || fedObject.GetDeletionTimestamp() != nil {
-		klog.V(4).Infof("No federated type for %v %v found", federatedKind, key)
+		logger.V(4).Info("No federated resource found", "kind", federatedKind)
+		if s.intervals != nil {
+			s.intervals.forget(key)
+		}
 		// Status object is removed by GC. So we don't have to do anything more here.
 		return util.StatusAllOK
 	}
@@ -264,7 +313,13 @@ func (s *KubeFedStatusController) reconcile(qualifiedName util.QualifiedName) ut
 		return util.StatusNotSynced
 	}
 
-	clusterStatus, err := s.clusterStatuses(clusterNames, key)
+	placement, err := util.UnmarshalGenericPlacement(fedObject)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to read the placement of %s %q", federatedKind, key))
+		return util.StatusError
+	}
+
+	clusterStatus, err := s.clusterStatuses(clusterNames, qualifiedName, placement)
 	if err != nil {
 		return util.StatusError
 	}
@@ -295,23 +350,50 @@ func (s *KubeFedStatusController) reconcile(qualifiedName util.QualifiedName) ut
 		},
 		ClusterStatus: clusterStatus,
 	}
+
+	var companions []util.FederatedResource
+	if s.companionObjects {
+		federatedResource, companions, err = splitStatus(federatedResource)
+		if err != nil {
+			logger.Error(err, "Failed to determine the size of the status object", "kind", statusKind)
+			return util.StatusError
+		}
+	} else if size, err := util.ObjectSize(federatedResource); err == nil && size > util.MaxFederatedResourceSize {
+		runtime.HandleError(errors.Errorf("Status object for federated type %s %q is %d bytes, which exceeds the limit of %d bytes. "+
+			"Enable the %s feature gate to store the status of each cluster in a companion object.",
+			statusKind, key, size, util.MaxFederatedResourceSize, features.StatusCompanionObjects))
+	}
+
 	status, err := util.GetUnstructured(federatedResource)
 	if err != nil {
-		klog.Errorf("Failed to convert to Unstructured: %s %q: %v", statusKind, key, err)
+		logger.Error(err, "Failed to convert to Unstructured", "kind", statusKind)
 		return util.StatusError
 	}
 
+	changed := existingStatus == nil || !reflect.DeepEqual(existingStatus.Object["clusterStatus"], status.Object["clusterStatus"]) ||
+		hasCompanions(existingStatus) != hasCompanions(status)
+
 	if existingStatus == nil {
 		_, err = s.statusClient.Resources(qualifiedName.Namespace).Create(status, metav1.CreateOptions{})
 		if err != nil {
 			runtime.HandleError(errors.Wrapf(err, "Failed to create status object for federated type %s %q", statusKind, key))
 			return util.StatusNeedsRecheck
 		}
-	} else if !reflect.DeepEqual(existingStatus.Object["clusterStatus"], status.Object["clusterStatus"]) {
+	} else if changed {
 		if status.Object["clusterStatus"] == nil {
 			status.Object["clusterStatus"] = make([]util.ResourceClusterStatus, 0)
 		}
 		existingStatus.Object["clusterStatus"] = status.Object["clusterStatus"]
+		annotations := existingStatus.GetAnnotations()
+		if hasCompanions(status) {
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[CompanionsAnnotation] = "true"
+		} else {
+			delete(annotations, CompanionsAnnotation)
+		}
+		existingStatus.SetAnnotations(annotations)
 		_, err = s.statusClient.Resources(qualifiedName.Namespace).Update(existingStatus, metav1.UpdateOptions{})
 		if err != nil {
 			runtime.HandleError(errors.Wrapf(err, "Failed to update status object for federated type %s %q", statusKind, key))
@@ -319,6 +401,21 @@ func (s *KubeFedStatusController) reconcile(qualifiedName util.QualifiedName) ut
 		}
 	}
 
+	// Companion status objects are removed once the status of the
+	// resource fits in its status object again.
+	if len(companions) > 0 || hasCompanions(existingStatus) {
+		companionsChanged, err := s.reconcileCompanions(qualifiedName, companions)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to reconcile companion status objects for federated type %s %q", statusKind, key))
+			return util.StatusNeedsRecheck
+		}
+		changed = changed || companionsChanged
+	}
+
+	if s.intervals != nil {
+		s.intervals.observe(key, changed)
+	}
+
 	return util.StatusAllOK
 }
 
@@ -360,11 +457,12 @@ func (s *KubeFedStatusController) clusterNames() ([]string, error) {
 }
 
 // clusterStatuses returns the resource status in member cluster.
-func (s *KubeFedStatusController) clusterStatuses(clusterNames []string, key string) ([]util.ResourceClusterStatus, error) {
+func (s *KubeFedStatusController) clusterStatuses(clusterNames []string, qualifiedName util.QualifiedName, placement *util.GenericPlacement) ([]util.ResourceClusterStatus, error) {
 	clusterStatus := []util.ResourceClusterStatus{}
 
 	targetKind := s.typeConfig.GetTargetType().Kind
 	for _, clusterName := range clusterNames {
+		key := util.TargetNameForCluster(clusterName, qualifiedName, false, placement).String()
 		clusterObj, exist, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "Failed to get %s %q from cluster %q", targetKind, key, clusterName)
@@ -373,7 +471,10 @@ func (s *KubeFedStatusController) clusterStatuses(clusterNames []string, key str
 		}
 
 		var status map[string]interface{}
-		if exist {
+		var clusterHealth health.Health
+		// The status of a resource propagated for a different
+		// federated resource with a colliding name is not collected.
+		if exist && util.IsPropagatedFor(clusterObj.(*unstructured.Unstructured), clusterName, qualifiedName) {
 			clusterObj := clusterObj.(*unstructured.Unstructured)
 
 			var found bool
@@ -382,8 +483,24 @@ func (s *KubeFedStatusController) clusterStatuses(clusterNames []string, key str
 				wrappedErr := errors.Wrapf(err, "Failed to get status of cluster resource object %s %q for cluster %q", targetKind, key, clusterName)
 				runtime.HandleError(wrappedErr)
 			}
+			// The health is interpreted from the entire resource
+			// before its status fields are selected.
+			if s.healthInterpreter != nil {
+				clusterHealth, err = s.healthInterpreter.Interpret(clusterObj)
+				if err != nil {
+					wrappedErr := errors.Wrapf(err, "Failed to interpret the health of cluster resource object %s %q for cluster %q", targetKind, key, clusterName)
+					runtime.HandleError(wrappedErr)
+				}
+			}
+			if statusFields := s.typeConfig.GetStatusFields(); len(status) > 0 && len(statusFields) > 0 {
+				status, err = selectStatusFields(status, statusFields)
+				if err != nil {
+					wrappedErr := errors.Wrapf(err, "Failed to select status fields of cluster resource object %s %q for cluster %q", targetKind, key, clusterName)
+					runtime.HandleError(wrappedErr)
+				}
+			}
 		}
-		resourceClusterStatus := util.ResourceClusterStatus{ClusterName: clusterName, Status: status}
+		resourceClusterStatus := util.ResourceClusterStatus{ClusterName: clusterName, Status: status, Health: clusterHealth}
 		clusterStatus = append(clusterStatus, resourceClusterStatus)
 	}
 
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// selectStatusFields returns a copy of the given status containing
+// only the fields at the given dot-separated paths. Fields that are
+// not present in the status are ignored.
+func selectStatusFields(status map[string]interface{}, paths []string) (map[string]interface{}, error) {
+	selected := make(map[string]interface{})
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		value, found, err := unstructured.NestedFieldCopy(status, fields...)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		err = unstructured.SetNestedField(selected, value, fields...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return selected, nil
+}
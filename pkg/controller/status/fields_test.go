@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectStatusFields(t *testing.T) {
+	status := map[string]interface{}{
+		"availableReplicas": int64(3),
+		"replicas":          int64(3),
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Available", "status": "True"},
+		},
+		"loadBalancer": map[string]interface{}{
+			"ingress": []interface{}{
+				map[string]interface{}{"ip": "10.0.0.1"},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		paths    []string
+		expected map[string]interface{}
+	}{
+		"Top-level fields": {
+			paths: []string{"availableReplicas", "conditions"},
+			expected: map[string]interface{}{
+				"availableReplicas": int64(3),
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True"},
+				},
+			},
+		},
+		"Nested field": {
+			paths: []string{"loadBalancer.ingress"},
+			expected: map[string]interface{}{
+				"loadBalancer": map[string]interface{}{
+					"ingress": []interface{}{
+						map[string]interface{}{"ip": "10.0.0.1"},
+					},
+				},
+			},
+		},
+		"Missing field": {
+			paths:    []string{"readyReplicas"},
+			expected: map[string]interface{}{},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			selected, err := selectStatusFields(status, tc.paths)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(selected, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, selected)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"sync"
+	"time"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// collectionIntervals tracks the interval at which the status of each
+// resource is collected. The interval of a resource whose status
+// changed is reset to the minimum interval, and is doubled up to the
+// maximum interval whenever its status is found unchanged for the
+// configured number of consecutive collections.
+type collectionIntervals struct {
+	sync.Mutex
+
+	minInterval       time.Duration
+	maxInterval       time.Duration
+	stableCollections int32
+
+	resources map[string]*collectionInterval
+}
+
+type collectionInterval struct {
+	interval time.Duration
+	// unchanged is the number of consecutive collections that found
+	// the status unchanged since the interval was last adapted.
+	unchanged int32
+}
+
+func newCollectionIntervals(config *fedv1b1.AdaptiveStatusCollectionConfig) *collectionIntervals {
+	return &collectionIntervals{
+		minInterval:       config.MinInterval.Duration,
+		maxInterval:       config.MaxInterval.Duration,
+		stableCollections: *config.StableCollections,
+		resources:         make(map[string]*collectionInterval),
+	}
+}
+
+// get returns the interval at which the status of the resource with
+// the given key is collected.
+func (c *collectionIntervals) get(key string) time.Duration {
+	c.Lock()
+	defer c.Unlock()
+	if resource, ok := c.resources[key]; ok {
+		return resource.interval
+	}
+	return c.minInterval
+}
+
+// observe adapts the interval of the resource with the given key to
+// whether its status was found changed by a collection.
+func (c *collectionIntervals) observe(key string, changed bool) {
+	c.Lock()
+	defer c.Unlock()
+	resource, ok := c.resources[key]
+	if !ok {
+		resource = &collectionInterval{interval: c.minInterval}
+		c.resources[key] = resource
+	}
+	if changed {
+		resource.interval = c.minInterval
+		resource.unchanged = 0
+		return
+	}
+	resource.unchanged++
+	if resource.unchanged < c.stableCollections {
+		return
+	}
+	resource.unchanged = 0
+	resource.interval *= 2
+	if resource.interval > c.maxInterval {
+		resource.interval = c.maxInterval
+	}
+}
+
+// forget stops tracking the resource with the given key.
+func (c *collectionIntervals) forget(key string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.resources, key)
+}
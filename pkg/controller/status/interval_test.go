@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestCollectionIntervals(t *testing.T) {
+	stableCollections := int32(2)
+	intervals := newCollectionIntervals(&fedv1b1.AdaptiveStatusCollectionConfig{
+		MinInterval:       &metav1.Duration{Duration: 10 * time.Second},
+		MaxInterval:       &metav1.Duration{Duration: 30 * time.Second},
+		StableCollections: &stableCollections,
+	})
+	key := "ns/foo"
+
+	steps := []struct {
+		changed  bool
+		expected time.Duration
+	}{
+		{changed: true, expected: 10 * time.Second},
+		{changed: false, expected: 10 * time.Second},
+		{changed: false, expected: 20 * time.Second},
+		{changed: false, expected: 20 * time.Second},
+		{changed: false, expected: 30 * time.Second},
+		{changed: false, expected: 30 * time.Second},
+		{changed: false, expected: 30 * time.Second},
+		{changed: true, expected: 10 * time.Second},
+	}
+	for i, step := range steps {
+		intervals.observe(key, step.changed)
+		if interval := intervals.get(key); interval != step.expected {
+			t.Fatalf("Step %d: expected interval %v, got %v", i, step.expected, interval)
+		}
+	}
+
+	intervals.forget(key)
+	if interval := intervals.get(key); interval != 10*time.Second {
+		t.Errorf("Expected the minimum interval for a forgotten resource, got %v", interval)
+	}
+}
@@ -17,18 +17,28 @@ limitations under the License.
 package sync
 
 import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/mutator"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/version"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/features"
 )
 
 // FederatedResourceAccessor provides a way to retrieve and visit
@@ -64,9 +74,58 @@ type resourceAccessor struct {
 	fedNamespaceStore      cache.Store
 	fedNamespaceController cache.Controller
 
+	// The informer used to source cluster groups referenced by the
+	// placement of federated resources.
+	clusterGroupStore      cache.Store
+	clusterGroupController cache.Controller
+
+	// The informer used to source FederatedTemplates referenced by
+	// federated resources. Will only be initialized if the
+	// FederatedTemplates feature is enabled.
+	federatedTemplateStore      cache.Store
+	federatedTemplateController cache.Controller
+
+	// The informer used to source the MaintenanceWindows of member
+	// clusters. Will only be initialized if the MaintenanceWindows
+	// feature is enabled.
+	maintenanceWindowStore      cache.Store
+	maintenanceWindowController cache.Controller
+
+	// The informer used to source the ClusterAllowlists that limit
+	// the clusters resources are propagated to. Will only be
+	// initialized if the ClusterAllowlists feature is enabled.
+	clusterAllowlistStore      cache.Store
+	clusterAllowlistController cache.Controller
+
+	volumeClaimKind string
+
+	// The informer used to source federated persistent volume claims
+	// referenced by the placement of namespaced federated resources.
+	// Will only be initialized if the target resource is namespaced
+	// and the type for persistent volume claims is enabled.
+	volumeClaimStore      cache.Store
+	volumeClaimController cache.Controller
+
 	// Manages propagated versions
 	versionManager *version.VersionManager
 
+	// Mutates resources before they are propagated. Will be nil if
+	// no mutators are configured for the type.
+	mutators *mutator.Pipeline
+
+	// Retrieves ready member clusters by name.
+	getCluster clusterFunc
+
+	// Determines whether managed resources exist in member clusters.
+	managedObjectExists managedObjectFunc
+
+	// The standard metadata to add to propagated resources.
+	propagatedMetadata *fedv1b1.PropagatedMetadataConfig
+
+	// The name of the KubeFed instance to label propagated resources
+	// with, if any.
+	instanceName string
+
 	// Records events on the federated resource
 	eventRecorder record.EventRecorder
 }
@@ -77,15 +136,23 @@ func NewFederatedResourceAccessor(
 	fedNamespaceAPIResource *metav1.APIResource,
 	client genericclient.Client,
 	enqueueObj func(pkgruntime.Object),
-	eventRecorder record.EventRecorder) (FederatedResourceAccessor, error) {
+	eventRecorder record.EventRecorder,
+	mutators *mutator.Pipeline,
+	getCluster clusterFunc,
+	managedObjectExists managedObjectFunc) (FederatedResourceAccessor, error) {
 
 	a := &resourceAccessor{
 		limitedScope:            controllerConfig.LimitedScope(),
+		propagatedMetadata:      controllerConfig.PropagatedMetadata,
+		instanceName:            controllerConfig.InstanceName,
 		typeConfig:              typeConfig,
 		targetIsNamespace:       typeConfig.GetTargetType().Kind == util.NamespaceKind,
 		fedNamespace:            controllerConfig.KubeFedNamespace,
 		fedNamespaceAPIResource: fedNamespaceAPIResource,
 		eventRecorder:           eventRecorder,
+		mutators:                mutators,
+		getCluster:              getCluster,
+		managedObjectExists:     managedObjectExists,
 	}
 
 	targetNamespace := controllerConfig.TargetNamespace
@@ -136,6 +203,97 @@ func NewFederatedResourceAccessor(
 		a.fedNamespaceStore, a.fedNamespaceController = util.NewResourceInformer(fedNamespaceClient, targetNamespace, fedNamespaceAPIResource, fedNamespaceEnqueue)
 	}
 
+	// When a cluster group changes, every resource needs to be
+	// reconciled since any resource may reference the group.
+	clusterGroupEnqueue := func(pkgruntime.Object) {
+		for _, rawObj := range a.federatedStore.List() {
+			enqueueObj(rawObj.(pkgruntime.Object))
+		}
+	}
+	a.clusterGroupStore, a.clusterGroupController, err = util.NewGenericInformer(
+		controllerConfig.KubeConfig,
+		controllerConfig.KubeFedNamespace,
+		&fedv1b1.ClusterGroup{},
+		util.NoResyncPeriod,
+		clusterGroupEnqueue,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.FederatedTemplates) {
+		// When a FederatedTemplate changes, the resources that
+		// reference it need to be reconciled.
+		federatedTemplateEnqueue := func(templateObj pkgruntime.Object) {
+			templateName := util.NewQualifiedName(templateObj).Name
+			for _, rawObj := range a.federatedStore.List() {
+				obj := rawObj.(*unstructured.Unstructured)
+				ref, err := util.GetTemplateRef(obj)
+				if err == nil && ref != nil && ref.Name == templateName {
+					enqueueObj(obj)
+				}
+			}
+		}
+		a.federatedTemplateStore, a.federatedTemplateController, err = util.NewGenericInformer(
+			controllerConfig.KubeConfig,
+			controllerConfig.KubeFedNamespace,
+			&fedv1b1.FederatedTemplate{},
+			util.NoResyncPeriod,
+			federatedTemplateEnqueue,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.MaintenanceWindows) {
+		// When a MaintenanceWindow changes, updates that were
+		// deferred may now be possible and every resource needs to be
+		// reconciled.
+		maintenanceWindowEnqueue := func(pkgruntime.Object) {
+			for _, rawObj := range a.federatedStore.List() {
+				enqueueObj(rawObj.(pkgruntime.Object))
+			}
+		}
+		a.maintenanceWindowStore, a.maintenanceWindowController, err = util.NewGenericInformer(
+			controllerConfig.KubeConfig,
+			controllerConfig.KubeFedNamespace,
+			&fedv1b1.MaintenanceWindow{},
+			util.NoResyncPeriod,
+			maintenanceWindowEnqueue,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.ClusterAllowlists) {
+		// When a ClusterAllowlist changes, the clusters any resource
+		// may be propagated to may change.
+		clusterAllowlistEnqueue := func(pkgruntime.Object) {
+			for _, rawObj := range a.federatedStore.List() {
+				enqueueObj(rawObj.(pkgruntime.Object))
+			}
+		}
+		a.clusterAllowlistStore, a.clusterAllowlistController, err = util.NewGenericInformer(
+			controllerConfig.KubeConfig,
+			controllerConfig.KubeFedNamespace,
+			&fedv1b1.ClusterAllowlist{},
+			util.NoResyncPeriod,
+			clusterAllowlistEnqueue,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if typeConfig.GetNamespaced() {
+		err := a.initVolumeClaimInformer(controllerConfig, client, enqueueObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	a.versionManager = version.NewVersionManager(
 		client,
 		typeConfig.GetFederatedNamespaced(),
@@ -156,6 +314,19 @@ func (a *resourceAccessor) Run(stopChan <-chan struct{}) {
 	if a.fedNamespaceController != nil {
 		go a.fedNamespaceController.Run(stopChan)
 	}
+	go a.clusterGroupController.Run(stopChan)
+	if a.federatedTemplateController != nil {
+		go a.federatedTemplateController.Run(stopChan)
+	}
+	if a.maintenanceWindowController != nil {
+		go a.maintenanceWindowController.Run(stopChan)
+	}
+	if a.clusterAllowlistController != nil {
+		go a.clusterAllowlistController.Run(stopChan)
+	}
+	if a.volumeClaimController != nil {
+		go a.volumeClaimController.Run(stopChan)
+	}
 }
 
 func (a *resourceAccessor) HasSynced() bool {
@@ -176,6 +347,26 @@ func (a *resourceAccessor) HasSynced() bool {
 		klog.V(2).Infof("FederatedNamespace informer for %s not synced", kind)
 		return false
 	}
+	if !a.clusterGroupController.HasSynced() {
+		klog.V(2).Infof("ClusterGroup informer for %s not synced", kind)
+		return false
+	}
+	if a.federatedTemplateController != nil && !a.federatedTemplateController.HasSynced() {
+		klog.V(2).Infof("FederatedTemplate informer for %s not synced", kind)
+		return false
+	}
+	if a.maintenanceWindowController != nil && !a.maintenanceWindowController.HasSynced() {
+		klog.V(2).Infof("MaintenanceWindow informer for %s not synced", kind)
+		return false
+	}
+	if a.clusterAllowlistController != nil && !a.clusterAllowlistController.HasSynced() {
+		klog.V(2).Infof("ClusterAllowlist informer for %s not synced", kind)
+		return false
+	}
+	if a.volumeClaimController != nil && !a.volumeClaimController.HasSynced() {
+		klog.V(2).Infof("%s informer for %s not synced", a.volumeClaimKind, kind)
+		return false
+	}
 	return true
 }
 
@@ -265,18 +456,63 @@ func (a *resourceAccessor) FederatedResource(eventSource util.QualifiedName) (Fe
 		// will be removed.
 	}
 
+	placement, err := util.UnmarshalGenericPlacement(resource)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to read the placement of %s %q", kind, key)
+	}
+	if a.limitedScope && len(placement.NamespaceMapping()) > 0 {
+		// The informers of a namespace-scoped control plane only
+		// observe the target namespace in member clusters.
+		a.eventRecorder.Eventf(
+			resource, corev1.EventTypeWarning,
+			"NamespaceMappingNotSupported", "Mapping namespaces is not supported by a namespace-scoped control plane.")
+		placement.Spec.Placement.NamespaceMapping = nil
+	}
+
+	var getTemplate util.FederatedTemplateFunc
+	if a.federatedTemplateStore != nil {
+		getTemplate = a.federatedTemplate
+	}
+	var getMaintenanceWindows maintenanceWindowsFunc
+	if a.maintenanceWindowStore != nil {
+		getMaintenanceWindows = a.maintenanceWindows
+	}
+	var getClusterAllowlists clusterAllowlistsFunc
+	if a.clusterAllowlistStore != nil {
+		getClusterAllowlists = a.clusterAllowlists
+	}
+
+	template, _, err := util.ResolveTemplate(resource, getTemplate)
+	// The template is not needed to remove a deleted resource from
+	// member clusters.
+	if err != nil && resource.GetDeletionTimestamp() == nil {
+		a.eventRecorder.Eventf(resource, corev1.EventTypeWarning, "TemplateResolutionFailed", err.Error())
+		return nil, false, errors.Wrapf(err, "failed to resolve the template of %s %q", kind, key)
+	}
+
 	return &federatedResource{
-		limitedScope:      a.limitedScope,
-		typeConfig:        a.typeConfig,
-		targetIsNamespace: a.targetIsNamespace,
-		targetName:        targetName,
-		federatedKind:     kind,
-		federatedName:     federatedName,
-		federatedResource: resource,
-		versionManager:    a.versionManager,
-		namespace:         namespace,
-		fedNamespace:      fedNamespace,
-		eventRecorder:     a.eventRecorder,
+		limitedScope:           a.limitedScope,
+		typeConfig:             a.typeConfig,
+		targetIsNamespace:      a.targetIsNamespace,
+		targetName:             targetName,
+		placement:              placement,
+		federatedKind:          kind,
+		federatedName:          federatedName,
+		federatedResource:      resource,
+		template:               template,
+		versionManager:         a.versionManager,
+		namespace:              namespace,
+		fedNamespace:           fedNamespace,
+		getClusterGroup:        a.clusterGroup,
+		getVolumeClaimClusters: a.volumeClaimClusters,
+		getMaintenanceWindows:  getMaintenanceWindows,
+		getClusterAllowlists:   getClusterAllowlists,
+		mutators:               a.mutators,
+		getCluster:             a.getCluster,
+		managedObjectExists:    a.managedObjectExists,
+		propagatedMetadata:     a.propagatedMetadata,
+		instanceName:           a.instanceName,
+		eventRecorder:          a.eventRecorder,
 	}, false, nil
 }
 
@@ -286,6 +522,102 @@ func (a *resourceAccessor) VisitFederatedResources(visitFunc func(obj interface{
 	}
 }
 
+func (a *resourceAccessor) clusterGroup(name string) (*fedv1b1.ClusterGroup, error) {
+	key := util.QualifiedName{Namespace: a.fedNamespace, Name: name}.String()
+	cachedObj, exist, err := a.clusterGroupStore.GetByKey(key)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return cachedObj.(*fedv1b1.ClusterGroup), nil
+}
+
+func (a *resourceAccessor) federatedTemplate(name string) (*fedv1b1.FederatedTemplate, error) {
+	key := util.QualifiedName{Namespace: a.fedNamespace, Name: name}.String()
+	cachedObj, exist, err := a.federatedTemplateStore.GetByKey(key)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return cachedObj.(*fedv1b1.FederatedTemplate), nil
+}
+
+func (a *resourceAccessor) maintenanceWindows() []*fedv1b1.MaintenanceWindow {
+	windows := []*fedv1b1.MaintenanceWindow{}
+	for _, obj := range a.maintenanceWindowStore.List() {
+		windows = append(windows, obj.(*fedv1b1.MaintenanceWindow))
+	}
+	return windows
+}
+
+func (a *resourceAccessor) clusterAllowlists() []*fedv1b1.ClusterAllowlist {
+	allowlists := []*fedv1b1.ClusterAllowlist{}
+	for _, obj := range a.clusterAllowlistStore.List() {
+		allowlists = append(allowlists, obj.(*fedv1b1.ClusterAllowlist))
+	}
+	return allowlists
+}
+
+// initVolumeClaimInformer initializes an informer for the federated
+// type of persistent volume claims if the type is enabled. The clusters
+// a federated persistent volume claim has been placed in limit the
+// placement of the resources that reference it.
+func (a *resourceAccessor) initVolumeClaimInformer(controllerConfig *util.ControllerConfig, client genericclient.Client, enqueueObj func(pkgruntime.Object)) error {
+	volumeClaimTypeConfig := &fedv1b1.FederatedTypeConfig{}
+	err := client.Get(context.TODO(), volumeClaimTypeConfig, controllerConfig.KubeFedNamespace, util.PersistentVolumeClaimName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error retrieving FederatedTypeConfig %q", util.PersistentVolumeClaimName)
+	}
+	volumeClaimAPIResource := volumeClaimTypeConfig.GetFederatedType()
+	a.volumeClaimKind = volumeClaimAPIResource.Kind
+
+	// When the placement of a claim changes, the resources in its
+	// namespace that reference it need to be reconciled.
+	volumeClaimEnqueue := func(volumeClaimObj pkgruntime.Object) {
+		claimName := util.NewQualifiedName(volumeClaimObj)
+		for _, rawObj := range a.federatedStore.List() {
+			obj := rawObj.(*unstructured.Unstructured)
+			if obj.GetNamespace() != claimName.Namespace {
+				continue
+			}
+			claimNames, _, _ := unstructured.NestedStringSlice(obj.Object, util.SpecField, util.PlacementField, util.VolumeClaimsField)
+			if sets.NewString(claimNames...).Has(claimName.Name) {
+				enqueueObj(obj)
+			}
+		}
+	}
+	volumeClaimClient, err := util.NewResourceClient(controllerConfig.KubeConfig, &volumeClaimAPIResource)
+	if err != nil {
+		return err
+	}
+	a.volumeClaimStore, a.volumeClaimController = util.NewResourceInformer(volumeClaimClient, controllerConfig.TargetNamespace, &volumeClaimAPIResource, volumeClaimEnqueue)
+	return nil
+}
+
+func (a *resourceAccessor) volumeClaimClusters(qualifiedName util.QualifiedName) (sets.String, error) {
+	if a.volumeClaimStore == nil {
+		return nil, errors.Errorf("spec.placement.volumeClaims requires the FederatedTypeConfig %q to be enabled", util.PersistentVolumeClaimName)
+	}
+	volumeClaim, err := util.ObjFromCache(a.volumeClaimStore, a.volumeClaimKind, qualifiedName.String())
+	if err != nil || volumeClaim == nil {
+		return nil, err
+	}
+	clusterStatuses, _, err := unstructured.NestedSlice(volumeClaim.Object, util.StatusField, util.ClustersField)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the clusters of %s %q", a.volumeClaimKind, qualifiedName)
+	}
+	clusterNames := sets.String{}
+	for _, clusterStatus := range clusterStatuses {
+		if clusterStatus, ok := clusterStatus.(map[string]interface{}); ok {
+			if name, ok := clusterStatus[util.NameField].(string); ok {
+				clusterNames.Insert(name)
+			}
+		}
+	}
+	return clusterNames, nil
+}
+
 func (a *resourceAccessor) isSystemNamespace(namespace string) bool {
 	// TODO(font): Need a configurable or discoverable list of namespaces
 	// to not propagate beyond just the default system namespaces e.g.
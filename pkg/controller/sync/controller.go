@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
@@ -33,6 +34,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -43,10 +46,16 @@ import (
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/mutator"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/webhook"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 	finalizersutil "sigs.k8s.io/kubefed/pkg/controller/util/finalizers"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+	"sigs.k8s.io/kubefed/pkg/features"
+	"sigs.k8s.io/kubefed/pkg/logging"
 	"sigs.k8s.io/kubefed/pkg/metrics"
+	"sigs.k8s.io/kubefed/pkg/tracing"
 )
 
 const (
@@ -64,6 +73,15 @@ type KubeFedSyncController struct {
 	// TODO(marun) add comment
 	worker util.ReconcileWorker
 
+	// Propagates resources to slow member clusters separately so
+	// that they do not delay propagation to the other clusters. Nil
+	// if slow clusters are not isolated.
+	slowClusterWorker util.ReconcileWorker
+
+	// Tracks the latency of requests to member clusters to identify
+	// slow clusters. Nil if slow clusters are not isolated.
+	clusterLatency *util.ClusterLatencyTracker
+
 	// For triggering reconciliation of all target resources. This is
 	// used when a new cluster becomes available.
 	clusterDeliverer *util.DelayingDeliverer
@@ -74,6 +92,8 @@ type KubeFedSyncController struct {
 	// For events
 	eventRecorder record.EventRecorder
 
+	logger logr.Logger
+
 	clusterAvailableDelay   time.Duration
 	clusterUnavailableDelay time.Duration
 	smallDelay              time.Duration
@@ -86,7 +106,59 @@ type KubeFedSyncController struct {
 
 	skipAdoptingResources bool
 
+	// The duration for which the reconciliation of a changed
+	// federated resource is delayed to coalesce successive changes.
+	debounceWindow time.Duration
+
+	// The duration for which the removal of resources from member
+	// clusters is held after their federated resource is deleted.
+	deletionHold time.Duration
+
+	// The name of the service account impersonated in the namespace
+	// of each namespaced resource applied to member clusters.
+	impersonateServiceAccount string
+
+	// Whether the classes referenced by resources are verified to
+	// exist in member clusters before resources are propagated.
+	validateDependencies bool
+
+	// Whether ConfigMaps and Secrets are patched with their changes
+	// rather than updated in full in member clusters.
+	differentialPropagation bool
+
+	// Calls the propagation webhooks configured for the type before
+	// resources are created or updated in member clusters. Nil if no
+	// webhooks are configured.
+	reviewer *webhook.Reviewer
+
 	limitedScope bool
+
+	// Records resources with pending or failed operations so that
+	// they can be replayed first after a restart. Nil if the
+	// DispatchJournal feature is disabled.
+	journal *dispatchJournal
+
+	// The phase of the backfill of a newly joined cluster in which
+	// resources of the type are created.
+	backfillPhase fedv1b1.BackfillPhase
+
+	// Observes the outcome of creates and updates in member clusters
+	// to quarantine clusters that reject too many of them. Nil if the
+	// ClusterQuarantine feature is disabled.
+	applyObserver util.ApplyObserver
+
+	// Notifies the configured sinks of the transitions of the
+	// propagation state of resources. Nil if no sinks are configured.
+	notifier *util.PropagationNotifier
+
+	// Records the revisions of resources propagated to all selected
+	// clusters. Nil if the RevisionHistory feature is disabled.
+	history *revisionHistory
+
+	// Interprets the health of resources in member clusters. Nil if
+	// the HealthInterpretation feature is disabled or the health of
+	// the target type cannot be interpreted.
+	healthInterpreter health.Interpreter
 }
 
 // StartKubeFedSyncController starts a new sync controller for a type config
@@ -117,19 +189,52 @@ func newKubeFedSyncController(controllerConfig *util.ControllerConfig, typeConfi
 	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: userAgent})
 
 	s := &KubeFedSyncController{
-		clusterAvailableDelay:   controllerConfig.ClusterAvailableDelay,
-		clusterUnavailableDelay: controllerConfig.ClusterUnavailableDelay,
-		smallDelay:              time.Second * 3,
-		eventRecorder:           recorder,
-		typeConfig:              typeConfig,
-		hostClusterClient:       client,
-		skipAdoptingResources:   controllerConfig.SkipAdoptingResources,
-		limitedScope:            controllerConfig.LimitedScope(),
+		clusterAvailableDelay:     controllerConfig.ClusterAvailableDelay,
+		clusterUnavailableDelay:   controllerConfig.ClusterUnavailableDelay,
+		smallDelay:                time.Second * 3,
+		eventRecorder:             recorder,
+		logger:                    logging.NewLogger("sync-controller").WithValues(logging.FTCKey, typeConfig.GetObjectMeta().Name),
+		typeConfig:                typeConfig,
+		hostClusterClient:         client,
+		skipAdoptingResources:     controllerConfig.SkipAdoptingResources,
+		debounceWindow:            controllerConfig.DebounceWindow,
+		deletionHold:              controllerConfig.DeletionHold,
+		impersonateServiceAccount: controllerConfig.ImpersonateServiceAccount,
+		validateDependencies:      utilfeature.DefaultFeatureGate.Enabled(features.DependencyValidation),
+		differentialPropagation:   utilfeature.DefaultFeatureGate.Enabled(features.DifferentialPropagation),
+		limitedScope:              controllerConfig.LimitedScope(),
+		backfillPhase:             util.BackfillPhaseForType(typeConfig.GetTargetType()),
+		applyObserver:             controllerConfig.ApplyObserver,
+		clusterLatency:            controllerConfig.ClusterLatency,
+		notifier:                  controllerConfig.PropagationNotifier,
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.DispatchJournal) {
+		s.journal = newDispatchJournal(kubeClient.CoreV1(), controllerConfig.KubeFedNamespace, typeConfig.GetObjectMeta().Name)
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.RevisionHistory) {
+		limit := controllerConfig.RevisionHistoryLimit
+		if limit == 0 {
+			limit = DefaultRevisionHistoryLimit
+		}
+		s.history = newRevisionHistory(kubeClient.AppsV1(), controllerConfig.KubeFedNamespace, limit)
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.HealthInterpretation) {
+		var err error
+		s.healthInterpreter, err = health.ForTypeConfig(typeConfig)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(userAgent, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
+	if s.clusterLatency != nil {
+		s.slowClusterWorker = util.NewReconcileWorker(userAgent+"-slow-clusters", s.reconcileSlowClusters, util.WorkerTiming{
+			ClusterSyncDelay: s.clusterAvailableDelay,
+		})
+	}
 
 	// Build deliverer for triggering cluster reconciliations.
 	s.clusterDeliverer = util.NewDelayingDeliverer()
@@ -143,7 +248,7 @@ func newKubeFedSyncController(controllerConfig *util.ControllerConfig, typeConfi
 		client,
 		&targetAPIResource,
 		func(obj pkgruntime.Object) {
-			qualifiedName := util.NewQualifiedName(obj)
+			qualifiedName := util.NewSourceQualifiedName(obj)
 			s.worker.EnqueueForRetry(qualifiedName)
 		},
 		&util.ClusterLifecycleHandlerFuncs{
@@ -155,15 +260,48 @@ func newKubeFedSyncController(controllerConfig *util.ControllerConfig, typeConfi
 			ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
 				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(s.clusterUnavailableDelay))
 			},
+			// When the CRDs or APIs of a cluster change, placement
+			// may select or exclude the cluster.
+			ClusterInventoryChanged: func(cluster *fedv1b1.KubeFedCluster) {
+				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(s.clusterAvailableDelay))
+			},
+			// When the backfill of a cluster advances, resources
+			// whose creation was deferred can be created.
+			ClusterBackfillPhaseChanged: func(cluster *fedv1b1.KubeFedCluster) {
+				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+			},
+			// When a cluster is quarantined or released, propagation
+			// to the cluster stops or resumes.
+			ClusterQuarantineChanged: func(cluster *fedv1b1.KubeFedCluster) {
+				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+			},
+			// When a cluster is cordoned or uncordoned, placement
+			// may select or exclude the cluster.
+			ClusterCordonChanged: func(cluster *fedv1b1.KubeFedCluster) {
+				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+			},
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	mutators, err := mutator.NewPipeline(typeConfig.GetDispatchMutators())
+	if err != nil {
+		return nil, err
+	}
+	if targetAPIResource.Kind == util.NetworkPolicyKind {
+		mutators = mutators.Append(mutator.NewClusterCIDRMutator(s.informer.GetClusters))
+	}
+
+	s.reviewer, err = webhook.NewReviewer(typeConfig.GetPropagationWebhooks(), s.informer.GetReadyCluster)
+	if err != nil {
+		return nil, err
+	}
+
 	s.fedAccessor, err = NewFederatedResourceAccessor(
 		controllerConfig, typeConfig, fedNamespaceAPIResource,
-		client, s.worker.EnqueueObject, recorder)
+		client, s.enqueueChangedObject, recorder, mutators, s.informer.GetReadyCluster, s.managedObjectExists)
 	if err != nil {
 		return nil, err
 	}
@@ -171,12 +309,30 @@ func newKubeFedSyncController(controllerConfig *util.ControllerConfig, typeConfi
 	return s, nil
 }
 
+// managedObjectExists returns whether the managed resource with the
+// given name is present in the target store of the named cluster.
+func (s *KubeFedSyncController) managedObjectExists(clusterName string, targetName util.QualifiedName) (bool, error) {
+	_, exists, err := s.informer.GetTargetStore().GetByKey(clusterName, targetName.String())
+	return exists, err
+}
+
+// enqueueChangedObject enqueues the federated resource for the given
+// changed object after the debounce window. The deliverer retains the
+// earliest delivery of a resource, so changes made within the window
+// of the first are reconciled together.
+func (s *KubeFedSyncController) enqueueChangedObject(obj pkgruntime.Object) {
+	s.worker.EnqueueWithDelay(util.NewQualifiedName(obj), s.debounceWindow)
+}
+
 // minimizeLatency reduces delays and timeouts to make the controller more responsive (useful for testing).
 func (s *KubeFedSyncController) minimizeLatency() {
 	s.clusterAvailableDelay = time.Second
 	s.clusterUnavailableDelay = time.Second
 	s.smallDelay = 20 * time.Millisecond
 	s.worker.SetDelay(50*time.Millisecond, s.clusterAvailableDelay)
+	if s.slowClusterWorker != nil {
+		s.slowClusterWorker.SetDelay(50*time.Millisecond, s.clusterAvailableDelay)
+	}
 }
 
 func (s *KubeFedSyncController) Run(stopChan <-chan struct{}) {
@@ -186,7 +342,21 @@ func (s *KubeFedSyncController) Run(stopChan <-chan struct{}) {
 		s.reconcileOnClusterChange()
 	})
 
+	if s.journal != nil {
+		// Load the journal before any reconciliation can update it
+		// to avoid discarding the entries of the previous instance.
+		if err := s.journal.load(); err != nil {
+			runtime.HandleError(errors.Wrap(err, "Failed to load sync journal"))
+		}
+		s.journal.run(stopChan)
+		go s.replayJournal(stopChan)
+	}
+
 	s.worker.Run(stopChan)
+	if s.slowClusterWorker != nil {
+		s.slowClusterWorker.Run(stopChan)
+	}
+	s.runTypeConfigStatusUpdates(stopChan)
 
 	// Ensure all goroutines are cleaned up when the stop channel closes
 	go func() {
@@ -196,11 +366,33 @@ func (s *KubeFedSyncController) Run(stopChan <-chan struct{}) {
 	}()
 }
 
+// replayJournal enqueues the resources recorded in the journal by a
+// previous instance of the controller once the caches have synced.
+// Resources that are reconciled before the caches sync are delayed by
+// the cluster sync delay, so the recorded resources are reconciled
+// before the remainder.
+func (s *KubeFedSyncController) replayJournal(stopChan <-chan struct{}) {
+	pending := s.journal.pending()
+	if len(pending) == 0 {
+		return
+	}
+	err := wait.PollImmediateUntil(s.smallDelay, func() (bool, error) {
+		return s.isSynced(), nil
+	}, stopChan)
+	if err != nil {
+		return
+	}
+	s.logger.Info("Replaying resources recorded in the sync journal", "count", len(pending))
+	for _, qualifiedName := range pending {
+		s.worker.Enqueue(qualifiedName)
+	}
+}
+
 // Check whether all data stores are in sync. False is returned if any of the informer/stores is not yet
 // synced with the corresponding api server.
 func (s *KubeFedSyncController) isSynced() bool {
 	if !s.informer.ClustersSynced() {
-		klog.V(2).Infof("Cluster list not synced")
+		s.logger.V(2).Info("Cluster list not synced")
 		return false
 	}
 	if !s.fedAccessor.HasSynced() {
@@ -238,6 +430,8 @@ func (s *KubeFedSyncController) reconcile(qualifiedName util.QualifiedName) util
 	}
 
 	kind := s.typeConfig.GetFederatedType().Kind
+	reconcileID := logging.NewReconcileID()
+	logger := s.logger.WithValues(logging.QualifiedNameKey, qualifiedName, logging.ReconcileIDKey, reconcileID)
 
 	fedResource, possibleOrphan, err := s.fedAccessor.FederatedResource(qualifiedName)
 	if err != nil {
@@ -247,8 +441,8 @@ func (s *KubeFedSyncController) reconcile(qualifiedName util.QualifiedName) util
 	if possibleOrphan {
 		apiResource := s.typeConfig.GetTargetType()
 		gvk := apiResourceToGVK(&apiResource)
-		klog.V(2).Infof("Ensuring the removal of the label %q from %s %q in member clusters.", util.ManagedByKubeFedLabelKey, gvk.Kind, qualifiedName)
-		err = s.removeManagedLabel(gvk, qualifiedName)
+		logger.V(2).Info("Ensuring the removal of the managed label in member clusters", "label", util.ManagedByKubeFedLabelKey, "kind", gvk.Kind)
+		err = s.removeManagedLabel(gvk, qualifiedName, dispatch.TargetNames(qualifiedName))
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to remove the label %q from %s %q in member clusters", util.ManagedByKubeFedLabelKey, gvk.Kind, qualifiedName)
 			runtime.HandleError(wrappedErr)
@@ -258,65 +452,205 @@ func (s *KubeFedSyncController) reconcile(qualifiedName util.QualifiedName) util
 		return util.StatusAllOK
 	}
 	if fedResource == nil {
+		if s.journal != nil {
+			s.journal.forget(qualifiedName)
+		}
+		if s.history != nil {
+			s.history.forget(qualifiedName)
+		}
+		metrics.ForgetUnsyncedClusters(s.unsyncedKey(qualifiedName))
 		return util.StatusAllOK
 	}
 
-	key := fedResource.FederatedName().String()
-
-	klog.V(4).Infof("Starting to reconcile %s %q", kind, key)
+	logger.V(4).Info("Starting to reconcile", "kind", kind)
 	startTime := time.Now()
 	defer func() {
-		klog.V(4).Infof("Finished reconciling %s %q (duration: %v)", kind, key, time.Since(startTime))
+		logger.V(4).Info("Finished reconciling", "kind", kind, "duration", time.Since(startTime))
 		metrics.ReconcileFederatedResourcesDurationFromStart(startTime)
 	}()
 
 	if fedResource.Object().GetDeletionTimestamp() != nil {
-		return s.ensureDeletion(fedResource)
+		return s.ensureDeletion(logger, fedResource)
 	}
-	err = s.ensureFinalizer(fedResource)
+	err = s.ensureFinalizer(logger, fedResource)
 	if err != nil {
 		fedResource.RecordError("EnsureFinalizerError", errors.Wrap(err, "Failed to ensure finalizer"))
 		return util.StatusError
 	}
 
-	return s.syncToClusters(fedResource)
+	s.clearStaleErrors(logger, fedResource)
+
+	if util.IsPaused(fedResource.Object()) {
+		logger.V(3).Info("Propagation is paused", "kind", kind, "annotation", util.PausedAnnotation)
+		return util.StatusAllOK
+	}
+
+	span := tracing.StartSpan("reconcile",
+		tracing.String(logging.FTCKey, s.typeConfig.GetObjectMeta().Name),
+		tracing.String(logging.QualifiedNameKey, qualifiedName.String()),
+		tracing.String(logging.ReconcileIDKey, reconcileID),
+	)
+	defer span.End()
+
+	reconcileStatus := s.syncToClusters(logger, span, fedResource, false)
+	if reconcileStatus == util.StatusError {
+		span.SetFailed()
+	}
+	return reconcileStatus
+}
+
+// reconcileSlowClusters propagates the named resource to the slow
+// member clusters that reconcile skipped.
+func (s *KubeFedSyncController) reconcileSlowClusters(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	if !s.isSynced() {
+		return util.StatusNotSynced
+	}
+
+	kind := s.typeConfig.GetFederatedType().Kind
+	reconcileID := logging.NewReconcileID()
+	logger := s.logger.WithValues(logging.QualifiedNameKey, qualifiedName, logging.ReconcileIDKey, reconcileID)
+
+	fedResource, possibleOrphan, err := s.fedAccessor.FederatedResource(qualifiedName)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Error creating FederatedResource helper for %s %q", kind, qualifiedName))
+		return util.StatusError
+	}
+	// Orphaned, deleted and paused resources are handled by reconcile.
+	if possibleOrphan || fedResource == nil || fedResource.Object().GetDeletionTimestamp() != nil || util.IsPaused(fedResource.Object()) {
+		return util.StatusAllOK
+	}
+
+	logger.V(4).Info("Starting to reconcile slow clusters", "kind", kind)
+
+	span := tracing.StartSpan("reconcile slow clusters",
+		tracing.String(logging.FTCKey, s.typeConfig.GetObjectMeta().Name),
+		tracing.String(logging.QualifiedNameKey, qualifiedName.String()),
+		tracing.String(logging.ReconcileIDKey, reconcileID),
+	)
+	defer span.End()
+
+	reconcileStatus := s.syncToClusters(logger, span, fedResource, true)
+	if reconcileStatus == util.StatusError {
+		span.SetFailed()
+	}
+	return reconcileStatus
+}
+
+// isSlowCluster returns whether the named cluster is slow to respond
+// and should be propagated to separately from the other clusters.
+func (s *KubeFedSyncController) isSlowCluster(clusterName string) bool {
+	return s.clusterLatency != nil && s.clusterLatency.IsSlow(clusterName)
 }
 
 // syncToClusters ensures that the state of the given object is
-// synchronized to member clusters.
-func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) util.ReconciliationStatus {
+// synchronized to member clusters. Creates and updates in slow
+// clusters are deferred to the slow cluster worker unless slowClusters
+// is true, in which case only slow clusters and those whose
+// propagation was deferred are synchronized.
+func (s *KubeFedSyncController) syncToClusters(logger logr.Logger, span *tracing.Span, fedResource FederatedResource, slowClusters bool) util.ReconciliationStatus {
+	// Resources created before the admission webhook started
+	// enforcing the size limit are still propagated, but recording
+	// their propagation status may fail.
+	if size, err := util.ObjectSize(fedResource.Object()); err == nil && size > util.MaxFederatedResourceSize {
+		fedResource.RecordError("ResourceTooLarge", errors.Errorf("The resource is %d bytes, which exceeds the limit of %d bytes for federated resources", size, util.MaxFederatedResourceSize))
+	}
+
 	clusters, err := s.informer.GetClusters()
 	if err != nil {
 		fedResource.RecordError(string(status.ClusterRetrievalFailed), errors.Wrap(err, "Failed to retrieve list of clusters"))
-		return s.setFederatedStatus(fedResource, status.ClusterRetrievalFailed, nil)
+		return s.setFederatedStatus(logger, fedResource, status.ClusterRetrievalFailed, nil)
 	}
 
-	selectedClusterNames, err := fedResource.ComputePlacement(clusters)
+	selectedClusterNames, placementDecisions, err := fedResource.ComputePlacement(clusters)
 	if err != nil {
 		fedResource.RecordError(string(status.ComputePlacementFailed), errors.Wrap(err, "Failed to compute placement"))
-		return s.setFederatedStatus(fedResource, status.ComputePlacementFailed, nil)
+		return s.setFederatedStatus(logger, fedResource, status.ComputePlacementFailed, nil)
 	}
 
-	kind := fedResource.TargetKind()
-	key := fedResource.TargetName().String()
-	klog.V(4).Infof("Ensuring %s %q in clusters: %s", kind, key, strings.Join(selectedClusterNames.List(), ","))
+	logger.V(4).Info("Ensuring target resource in clusters", "kind", fedResource.TargetKind(), "clusters", strings.Join(selectedClusterNames.List(), ","))
 
-	dispatcher := dispatch.NewManagedDispatcher(s.informer.GetClientForCluster, fedResource, s.skipAdoptingResources)
+	// The status recorded for clusters that are not synchronized by
+	// this pass is retained so that the passes for fast and slow
+	// clusters do not overwrite each other's status.
+	recordedStatus, err := status.RecordedClusterStatus(fedResource.Object())
+	if err != nil {
+		runtime.HandleError(err)
+	}
+	if slowClusters && recordedStatus == nil {
+		// The status of the current generation has yet to be
+		// recorded by reconcile, which will enqueue the resource
+		// again.
+		return util.StatusAllOK
+	}
+	slowClusterDeferred := false
+
+	dispatcher := dispatch.NewManagedDispatcher(s.applyClientAccessor(fedResource), fedResource, s.skipAdoptingResources, s.validateDependencies, s.differentialPropagation, s.typeConfig.GetPropagationCreateOnly(), s.reviewer, s.applyObserver, logger, span)
+	pinnedClusters := util.PinnedClusters(fedResource.Object())
+	deferredClusters, maintenanceDelay := fedResource.DeferredClusters(selectedClusterNames)
+	dispatcher.DeferUpdates(deferredClusters)
+	dispatcher.CompareWithSchemas(s.informer.GetSchemaForCluster)
+	dispatcher.ResolveConflicts(s.typeConfig.GetConflictResolution())
+	// Clusters with an explicit order are never deferred to the slow
+	// cluster worker so that the order is honored.
+	clusterOrder := fedResource.ClusterOrder()
+	orderedClusters := sets.NewString(clusterOrder...)
+	dispatcher.OrderClusters(clusterOrder)
+
+	var clusterHealth map[string]health.Health
+	if s.healthInterpreter != nil {
+		clusterHealth = s.clusterHealth(logger, fedResource, clusters, selectedClusterNames)
+		// Updates of clusters with an explicit order wait for the
+		// resource to become healthy in the preceding clusters.
+		if len(clusterOrder) > 1 {
+			dispatcher.HoldUpdates(heldClusters(clusterOrder, selectedClusterNames, recordedStatus, clusterHealth))
+		}
+	}
 
 	for _, cluster := range clusters {
 		clusterName := cluster.Name
 		selectedCluster := selectedClusterNames.Has(clusterName)
+		slowCluster := s.isSlowCluster(clusterName) && !orderedClusters.Has(clusterName)
+
+		if slowClusters {
+			propStatus, recorded := recordedStatus[clusterName]
+			deferred := recorded && propStatus == status.SlowClusterPending
+			if !selectedCluster || !util.IsClusterReady(&cluster.Status) || util.IsClusterQuarantined(cluster) ||
+				!deferred && !slowCluster {
+				if recorded {
+					dispatcher.RecordStatus(clusterName, propStatus)
+				}
+				continue
+			}
+		}
 
 		if !util.IsClusterReady(&cluster.Status) {
 			if selectedCluster {
 				// Cluster state only needs to be reported in resource
 				// status for clusters selected for placement.
-				err := errors.New("Cluster not ready")
-				dispatcher.RecordClusterError(status.ClusterNotReady, clusterName, err)
+				if util.IsEdgeCluster(cluster) {
+					// Edge clusters are expected to be
+					// intermittently unreachable. The resource
+					// will be propagated when the cluster becomes
+					// available again.
+					dispatcher.RecordStatus(clusterName, status.PendingDelivery)
+				} else {
+					err := errors.New("Cluster not ready")
+					dispatcher.RecordClusterError(status.ClusterNotReady, clusterName, err)
+				}
+			}
+			continue
+		}
+
+		if util.IsClusterQuarantined(cluster) {
+			// Nothing is dispatched to a quarantined cluster until
+			// the quarantine is released.
+			if selectedCluster {
+				dispatcher.RecordStatus(clusterName, status.ClusterQuarantined)
 			}
 			continue
 		}
 
+		key := fedResource.TargetNameForCluster(clusterName).String()
 		rawClusterObj, _, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
 		if err != nil {
 			wrappedErr := errors.Wrap(err, "Failed to retrieve cached cluster object")
@@ -329,12 +663,36 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 			clusterObj = rawClusterObj.(*unstructured.Unstructured)
 		}
 
+		if clusterObj != nil && util.IsLocallyManaged(clusterObj) {
+			// The resource has been taken over in the cluster and is
+			// neither updated nor removed until it is released.
+			if selectedCluster {
+				dispatcher.RecordStatus(clusterName, status.LocallyManaged)
+			}
+			continue
+		}
+
+		if clusterObj != nil && pinnedClusters.Has(clusterName) {
+			// The version of the resource in the cluster is pinned
+			// and is neither updated nor removed until the cluster
+			// is unpinned.
+			if selectedCluster {
+				dispatcher.RecordStatus(clusterName, status.Pinned)
+			}
+			continue
+		}
+
 		// Resource should not exist in the named cluster
 		if !selectedCluster {
 			if clusterObj == nil {
 				// Resource does not exist in the cluster
 				continue
 			}
+			if !util.IsPropagatedFor(clusterObj, clusterName, fedResource.TargetName()) {
+				// Resource is managed by a different federated
+				// resource whose name collides
+				continue
+			}
 			if clusterObj.GetDeletionTimestamp() != nil {
 				// Resource is marked for deletion
 				dispatcher.RecordStatus(clusterName, status.WaitingForRemoval)
@@ -352,11 +710,29 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 
 		// Resource should appear in the named cluster
 
+		if !slowClusters && slowCluster {
+			// The resource will be created or updated by the slow
+			// cluster worker.
+			propStatus, recorded := recordedStatus[clusterName]
+			if !recorded {
+				propStatus = status.SlowClusterPending
+			}
+			dispatcher.RecordStatus(clusterName, propStatus)
+			slowClusterDeferred = true
+			continue
+		}
+
 		// TODO(marun) Consider waiting until the result of resource
 		// creation has reached the target store before attempting
 		// subsequent operations.  Otherwise the object won't be found
 		// but an add operation will fail with AlreadyExists.
 		if clusterObj == nil {
+			if util.IsBackfillDeferred(cluster, s.backfillPhase) {
+				// The resource will be created once the earlier
+				// phases of the backfill of the cluster complete.
+				dispatcher.RecordStatus(clusterName, status.BackfillPending)
+				continue
+			}
 			dispatcher.Create(clusterName)
 		} else {
 			dispatcher.Update(clusterName, clusterObj)
@@ -369,7 +745,10 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 
 	// Write updated versions to the API.
 	updatedVersionMap := dispatcher.VersionMap()
+	versionSpan := span.StartChild("update versions")
 	err = fedResource.UpdateVersions(selectedClusterNames.List(), updatedVersionMap)
+	versionSpan.RecordError(err)
+	versionSpan.End()
 	if err != nil {
 		// Versioning of federated resources is an optimization to
 		// avoid unnecessary updates, and failure to record version
@@ -378,10 +757,103 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 	}
 
 	collectedStatus := dispatcher.CollectedStatus()
-	return s.setFederatedStatus(fedResource, status.AggregateSuccess, &collectedStatus)
+	if s.journal != nil {
+		s.journal.record(fedResource.FederatedName(), collectedStatus.StatusMap)
+	}
+	s.recordUnsyncedClusters(fedResource.FederatedName(), collectedStatus.StatusMap)
+	if utilfeature.DefaultFeatureGate.Enabled(features.PlacementDecisions) {
+		collectedStatus.PlacementDecisions = placementDecisions
+	}
+	if clusterHealth != nil {
+		collectedStatus.ClusterHealth = collectedClusterHealth(clusterHealth)
+	}
+	// The status update may retrieve the resource again, so the
+	// propagated version is retained for the revision history.
+	var propagatedObj *unstructured.Unstructured
+	if s.history != nil && timeoutErr == nil && allPropagated(collectedStatus.StatusMap) {
+		propagatedObj = fedResource.Object().DeepCopy()
+	}
+	statusSpan := span.StartChild("update status")
+	defer statusSpan.End()
+	reconcileStatus := s.setFederatedStatus(logger, fedResource, status.AggregateSuccess, &collectedStatus)
+	if propagatedObj != nil && reconcileStatus == util.StatusAllOK {
+		if err := s.history.record(propagatedObj); err != nil {
+			runtime.HandleError(errors.Wrapf(err, "failed to record the revision of %s %q", fedResource.FederatedKind(), fedResource.FederatedName()))
+		}
+	}
+	if slowClusterDeferred && reconcileStatus == util.StatusAllOK {
+		s.slowClusterWorker.Enqueue(fedResource.FederatedName())
+	}
+	if maintenanceDelay > 0 && maintenanceDeferred(collectedStatus.StatusMap) {
+		// Reconcile again when a maintenance window opens to perform
+		// the deferred updates.
+		s.worker.EnqueueWithDelay(fedResource.FederatedName(), maintenanceDelay)
+	}
+	return reconcileStatus
 }
 
-func (s *KubeFedSyncController) setFederatedStatus(fedResource FederatedResource,
+// clearStaleErrors removes the failures recorded for earlier
+// generations of the resource from its status before the current
+// generation is propagated, which may take some time or, if
+// propagation is paused, not happen at all.
+func (s *KubeFedSyncController) clearStaleErrors(logger logr.Logger, fedResource FederatedResource) {
+	obj := fedResource.Object()
+	cleared, err := status.ClearStaleErrors(obj)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "failed to clear the stale errors of %s %q", fedResource.FederatedKind(), fedResource.FederatedName()))
+		return
+	}
+	if !cleared {
+		return
+	}
+	logger.V(2).Info("Clearing the failures of earlier generations", "kind", fedResource.FederatedKind(), "generation", obj.GetGeneration())
+	err = s.hostClusterClient.UpdateStatus(context.TODO(), obj)
+	if err != nil {
+		// The status is written again once the current generation
+		// has been propagated.
+		runtime.HandleError(errors.Wrapf(err, "failed to clear the stale errors of %s %q", fedResource.FederatedKind(), fedResource.FederatedName()))
+	}
+}
+
+// allPropagated returns whether the resource was propagated to all
+// selected clusters.
+func allPropagated(statusMap status.PropagationStatusMap) bool {
+	for _, propStatus := range statusMap {
+		if propStatus != status.ClusterPropagationOK {
+			return false
+		}
+	}
+	return true
+}
+
+// maintenanceDeferred returns whether the update of the resource in
+// any cluster was deferred to a maintenance window.
+func maintenanceDeferred(statusMap status.PropagationStatusMap) bool {
+	for _, propStatus := range statusMap {
+		if propStatus == status.MaintenanceDeferred {
+			return true
+		}
+	}
+	return false
+}
+
+// recordUnsyncedClusters records the clusters the named resource is not
+// in sync with for the unsynced metrics.
+func (s *KubeFedSyncController) recordUnsyncedClusters(qualifiedName util.QualifiedName, statusMap status.PropagationStatusMap) {
+	clusterNames := []string{}
+	for clusterName, propStatus := range statusMap {
+		if propStatus != status.ClusterPropagationOK {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+	metrics.RecordUnsyncedClusters(s.unsyncedKey(qualifiedName), qualifiedName.Namespace, clusterNames)
+}
+
+func (s *KubeFedSyncController) unsyncedKey(qualifiedName util.QualifiedName) string {
+	return fmt.Sprintf("%s/%s", s.typeConfig.GetObjectMeta().Name, qualifiedName)
+}
+
+func (s *KubeFedSyncController) setFederatedStatus(logger logr.Logger, fedResource FederatedResource,
 	reason status.AggregateReason, collectedStatus *status.CollectedPropagationStatus) util.ReconciliationStatus {
 
 	if collectedStatus == nil {
@@ -403,22 +875,35 @@ func (s *KubeFedSyncController) setFederatedStatus(fedResource FederatedResource
 		}
 	}
 
+	// The propagation state prior to the update, to notify of its
+	// transitions.
+	var previous *status.PropagationState
+	updated := false
+
 	// If the underlying resource has changed, attempt to retrieve and
 	// update it repeatedly.
 	err := wait.PollImmediate(1*time.Second, 5*time.Second, func() (bool, error) {
+		if s.notifier != nil {
+			var err error
+			previous, err = status.RecordedPropagationState(obj)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to read the recorded status")
+			}
+		}
 		if updateRequired, err := status.SetFederatedStatus(obj, reason, *collectedStatus); err != nil {
 			return false, errors.Wrapf(err, "failed to set the status")
 		} else if !updateRequired {
-			klog.V(4).Infof("No status update necessary for %s %q", kind, name)
+			logger.V(4).Info("No status update necessary", "kind", kind)
 			return true, nil
 		}
 
 		err := s.hostClusterClient.UpdateStatus(context.TODO(), obj)
 		if err == nil {
+			updated = true
 			return true, nil
 		}
 		if apierrors.IsConflict(err) {
-			klog.V(2).Infof("Failed to set propagation status for %s %q due to conflict (will retry): %v.", kind, name, err)
+			logger.V(2).Info("Failed to set propagation status due to conflict (will retry)", "kind", kind, "error", err)
 			err := s.hostClusterClient.Get(context.TODO(), obj, obj.GetNamespace(), obj.GetName())
 			if err != nil {
 				return false, errors.Wrapf(err, "failed to retrieve resource")
@@ -432,36 +917,46 @@ func (s *KubeFedSyncController) setFederatedStatus(fedResource FederatedResource
 		return util.StatusError
 	}
 
+	if updated && s.notifier != nil {
+		current, err := status.RecordedPropagationState(obj)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "failed to read the propagation status of %s %q", kind, name))
+			return util.StatusAllOK
+		}
+		for _, notification := range propagationNotifications(obj, previous, current, time.Now()) {
+			s.notifier.Notify(notification)
+		}
+	}
+
 	return util.StatusAllOK
 }
 
-func (s *KubeFedSyncController) ensureDeletion(fedResource FederatedResource) util.ReconciliationStatus {
+func (s *KubeFedSyncController) ensureDeletion(logger logr.Logger, fedResource FederatedResource) util.ReconciliationStatus {
 	fedResource.DeleteVersions()
 
 	key := fedResource.FederatedName().String()
 	kind := fedResource.FederatedKind()
 
-	klog.V(2).Infof("Ensuring deletion of %s %q", kind, key)
+	logger.V(2).Info("Ensuring deletion", "kind", kind)
 
 	obj := fedResource.Object()
 
 	finalizers := sets.NewString(obj.GetFinalizers()...)
 	if !finalizers.Has(FinalizerSyncController) {
-		klog.V(2).Infof("%s %q does not have the %q finalizer. Nothing to do.", kind, key, FinalizerSyncController)
+		logger.V(2).Info("Finalizer not present. Nothing to do.", "kind", kind, "finalizer", FinalizerSyncController)
 		return util.StatusAllOK
 	}
 
 	if util.IsOrphaningEnabled(obj) {
-		klog.V(2).Infof("Found %q annotation on %s %q. Removing the finalizer.",
-			util.OrphanManagedResourcesAnnotation, kind, key)
-		err := s.removeFinalizer(fedResource)
+		logger.V(2).Info("Found orphaning annotation. Removing the finalizer.", "kind", kind, "annotation", util.OrphanManagedResourcesAnnotation)
+		err := s.removeFinalizer(logger, fedResource)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to remove finalizer %q from %s %q", FinalizerSyncController, kind, key)
 			runtime.HandleError(wrappedErr)
 			return util.StatusError
 		}
-		klog.V(2).Infof("Initiating the removal of the label %q from resources previously managed by %s %q.", util.ManagedByKubeFedLabelKey, kind, key)
-		err = s.removeManagedLabel(fedResource.TargetGVK(), fedResource.TargetName())
+		logger.V(2).Info("Initiating the removal of the managed label from resources previously managed", "kind", kind, "label", util.ManagedByKubeFedLabelKey)
+		err = s.removeManagedLabel(fedResource.TargetGVK(), fedResource.TargetName(), fedResource.TargetNameForCluster)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to remove the label %q from all resources previously managed by %s %q", util.ManagedByKubeFedLabelKey, kind, key)
 			runtime.HandleError(wrappedErr)
@@ -470,8 +965,35 @@ func (s *KubeFedSyncController) ensureDeletion(fedResource FederatedResource) ut
 		return util.StatusAllOK
 	}
 
-	klog.V(2).Infof("Deleting resources managed by %s %q from member clusters.", kind, key)
-	recheckRequired, err := s.deleteFromClusters(fedResource)
+	if s.deletionHold > 0 {
+		heldUntil := obj.GetDeletionTimestamp().Add(s.deletionHold)
+		if remaining := time.Until(heldUntil); remaining > 0 {
+			logger.V(2).Info("Holding the removal of managed resources from member clusters", "kind", kind, "remaining", remaining.String())
+			err := s.setDeletionHeldUntil(fedResource, heldUntil)
+			if err != nil {
+				runtime.HandleError(errors.Wrapf(err, "failed to record the deletion hold of %s %q", kind, key))
+				return util.StatusError
+			}
+			s.worker.EnqueueWithDelay(fedResource.FederatedName(), remaining)
+			return util.StatusAllOK
+		}
+	}
+
+	if isJob(fedResource.TargetGVK()) && util.IsForegroundDeletion(obj) {
+		wait, err := s.awaitJobCompletion(fedResource)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "failed to await the completion of the Jobs managed by %s %q", kind, key))
+			return util.StatusError
+		}
+		if wait > 0 {
+			logger.V(2).Info("Waiting for managed Jobs to finish before removing them from member clusters", "kind", kind, "recheck", wait.String())
+			s.worker.EnqueueWithDelay(fedResource.FederatedName(), wait)
+			return util.StatusAllOK
+		}
+	}
+
+	logger.V(2).Info("Deleting managed resources from member clusters", "kind", kind)
+	recheckRequired, err := s.deleteFromClusters(logger, fedResource)
 	if err != nil {
 		wrappedErr := errors.Wrapf(err, "failed to delete %s %q", kind, key)
 		runtime.HandleError(wrappedErr)
@@ -483,10 +1005,23 @@ func (s *KubeFedSyncController) ensureDeletion(fedResource FederatedResource) ut
 	return util.StatusAllOK
 }
 
+// setDeletionHeldUntil records in the status of the given deleted
+// federated resource the time until which the removal of its managed
+// resources is held, so that the deletion can be cancelled in time.
+func (s *KubeFedSyncController) setDeletionHeldUntil(fedResource FederatedResource, heldUntil time.Time) error {
+	obj := fedResource.Object()
+	updateRequired, err := status.SetDeletionHeldUntil(obj, heldUntil)
+	if err != nil || !updateRequired {
+		return err
+	}
+	fedResource.RecordEvent("DeletionHeld", "Removal of the managed resources from member clusters is held until %s", heldUntil.UTC().Format(time.RFC3339))
+	return s.hostClusterClient.UpdateStatus(context.TODO(), obj)
+}
+
 // removeManagedLabel attempts to remove the managed label from
-// resources with the given name in member clusters.
-func (s *KubeFedSyncController) removeManagedLabel(gvk schema.GroupVersionKind, qualifiedName util.QualifiedName) error {
-	ok, err := s.handleDeletionInClusters(gvk, qualifiedName, func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
+// resources with the given names in member clusters.
+func (s *KubeFedSyncController) removeManagedLabel(gvk schema.GroupVersionKind, targetName util.QualifiedName, targetNames dispatch.TargetNameFunc) error {
+	ok, err := s.handleDeletionInClusters(gvk, targetName, targetNames, nil, func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
 		if clusterObj.GetDeletionTimestamp() != nil {
 			return
 		}
@@ -502,12 +1037,11 @@ func (s *KubeFedSyncController) removeManagedLabel(gvk schema.GroupVersionKind,
 	return nil
 }
 
-func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource) (bool, error) {
+func (s *KubeFedSyncController) deleteFromClusters(logger logr.Logger, fedResource FederatedResource) (bool, error) {
 	gvk := fedResource.TargetGVK()
-	qualifiedName := fedResource.TargetName()
 
 	remainingClusters := []string{}
-	ok, err := s.handleDeletionInClusters(gvk, qualifiedName, func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
+	ok, err := s.handleDeletionInClusters(gvk, fedResource.TargetName(), fedResource.TargetNameForCluster, fedResource.ClusterOrder(), func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
 		// If the containing namespace of a FederatedNamespace is
 		// marked for deletion, it is impossible to require the
 		// removal of the namespace in advance of removal of the sync
@@ -517,6 +1051,13 @@ func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource
 			return
 		}
 
+		if util.IsLocallyManaged(clusterObj) && clusterObj.GetDeletionTimestamp() == nil {
+			// A resource that has been taken over in the cluster is
+			// retained and only released from management.
+			dispatcher.RemoveManagedLabel(clusterName, clusterObj)
+			return
+		}
+
 		remainingClusters = append(remainingClusters, clusterName)
 
 		// Avoid attempting any operation on a deleted resource.
@@ -541,9 +1082,7 @@ func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource
 		return false, errors.Errorf("failed to remove managed resources from one or more clusters.")
 	}
 	if len(remainingClusters) > 0 {
-		fedKind := fedResource.FederatedKind()
-		fedName := fedResource.FederatedName()
-		klog.V(2).Infof("Waiting for resources managed by %s %q to be removed from the following clusters: %s", fedKind, fedName, strings.Join(remainingClusters, ", "))
+		logger.V(2).Info("Waiting for managed resources to be removed from member clusters", "kind", fedResource.FederatedKind(), "clusters", strings.Join(remainingClusters, ","))
 		return true, nil
 	}
 	err = s.ensureRemovedOrUnmanaged(fedResource)
@@ -551,7 +1090,22 @@ func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource
 		return false, errors.Wrapf(err, "failed to verify that managed resources no longer exist in any cluster")
 	}
 	// Managed resources no longer exist in any member cluster
-	return false, s.removeFinalizer(fedResource)
+	return false, s.removeFinalizer(logger, fedResource)
+}
+
+// applyClientAccessor returns the accessor of the clients with which
+// the given federated resource is applied to member clusters. The
+// configured service account is impersonated in the namespace of a
+// namespaced resource.
+func (s *KubeFedSyncController) applyClientAccessor(fedResource FederatedResource) func(string) (genericclient.Client, error) {
+	namespace := fedResource.TargetName().Namespace
+	if s.impersonateServiceAccount == "" || namespace == "" {
+		return s.informer.GetClientForCluster
+	}
+	userName := serviceaccount.MakeUsername(namespace, s.impersonateServiceAccount)
+	return func(clusterName string) (genericclient.Client, error) {
+		return s.informer.GetImpersonatingClientForCluster(clusterName, userName)
+	}
 }
 
 // ensureRemovedOrUnmanaged ensures that no resources in member
@@ -565,7 +1119,7 @@ func (s *KubeFedSyncController) ensureRemovedOrUnmanaged(fedResource FederatedRe
 		return errors.Wrap(err, "failed to get a list of clusters")
 	}
 
-	dispatcher := dispatch.NewCheckUnmanagedDispatcher(s.informer.GetClientForCluster, fedResource.TargetGVK(), fedResource.TargetName())
+	dispatcher := dispatch.NewCheckUnmanagedDispatcher(s.informer.GetClientForCluster, fedResource.TargetGVK(), fedResource.TargetName(), fedResource.TargetNameForCluster)
 	unreadyClusters := []string{}
 	for _, cluster := range clusters {
 		if !util.IsClusterReady(&cluster.Status) {
@@ -588,8 +1142,10 @@ func (s *KubeFedSyncController) ensureRemovedOrUnmanaged(fedResource FederatedRe
 }
 
 // handleDeletionInClusters invokes the provided deletion handler for
-// each managed resource in member clusters.
-func (s *KubeFedSyncController) handleDeletionInClusters(gvk schema.GroupVersionKind, qualifiedName util.QualifiedName,
+// each managed resource in member clusters that was propagated for
+// the target resource with the given name. The operations for the
+// clusters in the given order are dispatched in reverse order.
+func (s *KubeFedSyncController) handleDeletionInClusters(gvk schema.GroupVersionKind, targetName util.QualifiedName, targetNames dispatch.TargetNameFunc, clusterOrder []string,
 	deletionFunc func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured)) (bool, error) {
 
 	clusters, err := s.informer.GetClusters()
@@ -597,7 +1153,8 @@ func (s *KubeFedSyncController) handleDeletionInClusters(gvk schema.GroupVersion
 		return false, errors.Wrap(err, "failed to get a list of clusters")
 	}
 
-	dispatcher := dispatch.NewUnmanagedDispatcher(s.informer.GetClientForCluster, gvk, qualifiedName)
+	dispatcher := dispatch.NewUnmanagedDispatcher(s.informer.GetClientForCluster, gvk, targetNames)
+	dispatcher.OrderClusters(clusterOrder)
 	retrievalFailureClusters := []string{}
 	unreadyClusters := []string{}
 	for _, cluster := range clusters {
@@ -608,7 +1165,7 @@ func (s *KubeFedSyncController) handleDeletionInClusters(gvk schema.GroupVersion
 			continue
 		}
 
-		key := util.QualifiedNameForCluster(clusterName, qualifiedName).String()
+		key := targetNames(clusterName).String()
 		rawClusterObj, _, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to retrieve %s %q for cluster %q", gvk.Kind, key, clusterName)
@@ -620,6 +1177,9 @@ func (s *KubeFedSyncController) handleDeletionInClusters(gvk schema.GroupVersion
 			continue
 		}
 		clusterObj := rawClusterObj.(*unstructured.Unstructured)
+		if !util.IsPropagatedFor(clusterObj, clusterName, targetName) {
+			continue
+		}
 		deletionFunc(dispatcher, clusterName, clusterObj)
 	}
 	ok, timeoutErr := dispatcher.Wait()
@@ -635,22 +1195,22 @@ func (s *KubeFedSyncController) handleDeletionInClusters(gvk schema.GroupVersion
 	return ok, nil
 }
 
-func (s *KubeFedSyncController) ensureFinalizer(fedResource FederatedResource) error {
+func (s *KubeFedSyncController) ensureFinalizer(logger logr.Logger, fedResource FederatedResource) error {
 	obj := fedResource.Object()
 	isUpdated, err := finalizersutil.AddFinalizers(obj, sets.NewString(FinalizerSyncController))
 	if err != nil || !isUpdated {
 		return err
 	}
-	klog.V(2).Infof("Adding finalizer %s to %s %q", FinalizerSyncController, fedResource.FederatedKind(), fedResource.FederatedName())
+	logger.V(2).Info("Adding finalizer", "kind", fedResource.FederatedKind(), "finalizer", FinalizerSyncController)
 	return s.hostClusterClient.Update(context.TODO(), obj)
 }
 
-func (s *KubeFedSyncController) removeFinalizer(fedResource FederatedResource) error {
+func (s *KubeFedSyncController) removeFinalizer(logger logr.Logger, fedResource FederatedResource) error {
 	obj := fedResource.Object()
 	isUpdated, err := finalizersutil.RemoveFinalizers(obj, sets.NewString(FinalizerSyncController))
 	if err != nil || !isUpdated {
 		return err
 	}
-	klog.V(2).Infof("Removing finalizer %s from %s %q", FinalizerSyncController, fedResource.FederatedKind(), fedResource.FederatedName())
+	logger.V(2).Info("Removing finalizer", "kind", fedResource.FederatedKind(), "finalizer", FinalizerSyncController)
 	return s.hostClusterClient.Update(context.TODO(), obj)
 }
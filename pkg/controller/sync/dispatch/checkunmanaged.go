@@ -43,16 +43,18 @@ type CheckUnmanagedDispatcher interface {
 type checkUnmanagedDispatcherImpl struct {
 	dispatcher *operationDispatcherImpl
 
-	targetGVK  schema.GroupVersionKind
-	targetName util.QualifiedName
+	targetGVK   schema.GroupVersionKind
+	targetName  util.QualifiedName
+	targetNames TargetNameFunc
 }
 
-func NewCheckUnmanagedDispatcher(clientAccessor clientAccessorFunc, targetGVK schema.GroupVersionKind, targetName util.QualifiedName) CheckUnmanagedDispatcher {
+func NewCheckUnmanagedDispatcher(clientAccessor clientAccessorFunc, targetGVK schema.GroupVersionKind, targetName util.QualifiedName, targetNames TargetNameFunc) CheckUnmanagedDispatcher {
 	dispatcher := newOperationDispatcher(clientAccessor, nil)
 	return &checkUnmanagedDispatcherImpl{
-		dispatcher: dispatcher,
-		targetGVK:  targetGVK,
-		targetName: targetName,
+		dispatcher:  dispatcher,
+		targetGVK:   targetGVK,
+		targetName:  targetName,
+		targetNames: targetNames,
 	}
 }
 
@@ -64,10 +66,9 @@ func (d *checkUnmanagedDispatcherImpl) Wait() (bool, error) {
 // exist in the given cluster, or if it does exist, that it does not
 // have the managed label.
 func (d *checkUnmanagedDispatcherImpl) CheckRemovedOrUnlabeled(clusterName string, isHostNamespace isNamespaceInHostClusterFunc) {
-	d.dispatcher.incrementOperationsInitiated()
 	const op = "check for deletion of resource or removal of managed label from"
 	const opContinuous = "Checking for deletion of resource or removal of managed label from"
-	go d.dispatcher.clusterOperation(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
+	d.dispatcher.dispatch(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
 		targetName := d.targetNameForCluster(clusterName)
 
 		klog.V(2).Infof(eventTemplate, opContinuous, d.targetGVK.Kind, targetName, clusterName)
@@ -83,6 +84,11 @@ func (d *checkUnmanagedDispatcherImpl) CheckRemovedOrUnlabeled(clusterName strin
 			runtime.HandleError(wrappedErr)
 			return util.StatusError
 		}
+		if !util.IsPropagatedFor(clusterObj, clusterName, d.targetName) {
+			// The resource is managed by a different federated
+			// resource whose name collides.
+			return util.StatusAllOK
+		}
 		if clusterObj.GetDeletionTimestamp() != nil {
 			if isHostNamespace(clusterObj) {
 				return util.StatusAllOK
@@ -107,5 +113,5 @@ func (d *checkUnmanagedDispatcherImpl) wrapOperationError(err error, clusterName
 }
 
 func (d *checkUnmanagedDispatcherImpl) targetNameForCluster(clusterName string) util.QualifiedName {
-	return util.QualifiedNameForCluster(clusterName, d.targetName)
+	return d.targetNames(clusterName)
 }
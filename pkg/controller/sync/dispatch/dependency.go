@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/client/generic"
+)
+
+// dependency is a cluster-scoped object that a resource references
+// and that must exist in a member cluster for the resource to work.
+type dependency struct {
+	apiVersion string
+	kind       string
+	name       string
+	// The path of the field of the resource that references the
+	// dependency.
+	field string
+}
+
+// podSpecPaths are the paths of the pod specs of the resources that
+// run pods.
+var podSpecPaths = [][]string{
+	// Pod
+	{"spec"},
+	// Deployment, ReplicaSet, StatefulSet, DaemonSet and Job
+	{"spec", "template", "spec"},
+	// CronJob
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// dependencies returns the runtime, priority and storage classes
+// referenced by the given resource.
+func dependencies(obj *unstructured.Unstructured) []dependency {
+	deps := []dependency{}
+	for _, path := range podSpecPaths {
+		podSpec, ok, _ := unstructured.NestedMap(obj.Object, path...)
+		if !ok {
+			continue
+		}
+		prefix := strings.Join(path, ".")
+		if name, ok := podSpec["runtimeClassName"].(string); ok && name != "" {
+			deps = append(deps, dependency{"node.k8s.io/v1beta1", "RuntimeClass", name, prefix + ".runtimeClassName"})
+		}
+		if name, ok := podSpec["priorityClassName"].(string); ok && name != "" {
+			deps = append(deps, dependency{"scheduling.k8s.io/v1", "PriorityClass", name, prefix + ".priorityClassName"})
+		}
+	}
+
+	// PersistentVolumeClaim
+	if name, ok, _ := unstructured.NestedString(obj.Object, "spec", "storageClassName"); ok && name != "" {
+		deps = append(deps, dependency{"storage.k8s.io/v1", "StorageClass", name, "spec.storageClassName"})
+	}
+	// StatefulSet
+	claimTemplates, _, _ := unstructured.NestedSlice(obj.Object, "spec", "volumeClaimTemplates")
+	for _, rawTemplate := range claimTemplates {
+		claimTemplate, ok := rawTemplate.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok, _ := unstructured.NestedString(claimTemplate, "spec", "storageClassName"); ok && name != "" {
+			deps = append(deps, dependency{"storage.k8s.io/v1", "StorageClass", name, "spec.volumeClaimTemplates.spec.storageClassName"})
+		}
+	}
+	return deps
+}
+
+// checkDependencies verifies that the dependencies of the given
+// resource exist in the member cluster of the given client. The error
+// for a missing dependency names it, and the returned boolean
+// indicates whether an error is due to a missing dependency rather
+// than a failure to retrieve one.
+func checkDependencies(client generic.Client, obj *unstructured.Unstructured) (bool, error) {
+	for _, dep := range dependencies(obj) {
+		depObj := &unstructured.Unstructured{}
+		depObj.SetAPIVersion(dep.apiVersion)
+		depObj.SetKind(dep.kind)
+		err := client.Get(context.Background(), depObj, "", dep.name)
+		if apierrors.IsNotFound(err) {
+			return true, errors.Errorf("%s %q referenced by %s does not exist in the cluster", dep.kind, dep.name, dep.field)
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to retrieve %s %q referenced by %s", dep.kind, dep.name, dep.field)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDependencies(t *testing.T) {
+	testCases := map[string]struct {
+		obj                  map[string]interface{}
+		expectedDependencies []dependency
+	}{
+		"Deployment with runtime and priority classes": {
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"runtimeClassName":  "gvisor",
+							"priorityClassName": "critical",
+						},
+					},
+				},
+			},
+			expectedDependencies: []dependency{
+				{"node.k8s.io/v1beta1", "RuntimeClass", "gvisor", "spec.template.spec.runtimeClassName"},
+				{"scheduling.k8s.io/v1", "PriorityClass", "critical", "spec.template.spec.priorityClassName"},
+			},
+		},
+		"CronJob with priority class": {
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"jobTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"priorityClassName": "batch",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedDependencies: []dependency{
+				{"scheduling.k8s.io/v1", "PriorityClass", "batch", "spec.jobTemplate.spec.template.spec.priorityClassName"},
+			},
+		},
+		"PersistentVolumeClaim with storage class": {
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"storageClassName": "ssd",
+				},
+			},
+			expectedDependencies: []dependency{
+				{"storage.k8s.io/v1", "StorageClass", "ssd", "spec.storageClassName"},
+			},
+		},
+		"PersistentVolumeClaim without storage class": {
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"storageClassName": "",
+				},
+			},
+			expectedDependencies: []dependency{},
+		},
+		"StatefulSet with volume claim templates": {
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"volumeClaimTemplates": []interface{}{
+						map[string]interface{}{
+							"spec": map[string]interface{}{
+								"storageClassName": "ssd",
+							},
+						},
+						map[string]interface{}{
+							"spec": map[string]interface{}{},
+						},
+					},
+				},
+			},
+			expectedDependencies: []dependency{
+				{"storage.k8s.io/v1", "StorageClass", "ssd", "spec.volumeClaimTemplates.spec.storageClassName"},
+			},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			deps := dependencies(&unstructured.Unstructured{Object: testCase.obj})
+			if !reflect.DeepEqual(deps, testCase.expectedDependencies) {
+				t.Fatalf("Expected dependencies %v, got %v", testCase.expectedDependencies, deps)
+			}
+		})
+	}
+}
@@ -23,30 +23,37 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/webhook"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/logging"
 	"sigs.k8s.io/kubefed/pkg/metrics"
+	"sigs.k8s.io/kubefed/pkg/tracing"
 )
 
 // FederatedResourceForDispatch is the subset of the FederatedResource
 // interface required for dispatching operations to managed resources.
 type FederatedResourceForDispatch interface {
 	TargetName() util.QualifiedName
+	TargetNameForCluster(clusterName string) util.QualifiedName
 	TargetKind() string
 	TargetGVK() schema.GroupVersionKind
 	Object() *unstructured.Unstructured
 	VersionForCluster(clusterName string) (string, error)
 	ObjectForCluster(clusterName string) (*unstructured.Unstructured, error)
-	ApplyOverrides(obj *unstructured.Unstructured, clusterName string) error
+	ApplyOverrides(obj *unstructured.Unstructured, clusterName string, resolveValue util.OverrideValueFunc) error
 	RecordError(errorCode string, err error)
 	RecordEvent(reason, messageFmt string, args ...interface{})
 	IsNamespaceInHostCluster(clusterObj pkgruntime.Object) bool
@@ -64,32 +71,84 @@ type ManagedDispatcher interface {
 
 	RecordClusterError(propStatus status.PropagationStatus, clusterName string, err error)
 	RecordStatus(clusterName string, propStatus status.PropagationStatus)
+
+	// DeferUpdates defers the updates of resources in the named
+	// clusters until their maintenance windows open.
+	DeferUpdates(clusterNames sets.String)
+
+	// HoldUpdates holds the updates of resources in the named
+	// clusters until the resource is healthy in the clusters
+	// preceding them in the cluster order.
+	HoldUpdates(clusterNames sets.String)
+
+	// CompareWithSchemas ignores the fields defaulted by member
+	// clusters, according to the schemas returned by the given
+	// function, when determining whether resources need to be updated.
+	CompareWithSchemas(getSchema util.ClusterSchemaFunc)
+
+	// ResolveConflicts resolves updates that conflict with a
+	// concurrent change of resources in member clusters according to
+	// the given configuration. Conflicting updates fail if it is nil.
+	ResolveConflicts(resolution *fedv1b1.ConflictResolution)
 }
 
+// The number of times a conflicting update is retried when resolving
+// the conflict.
+const maxConflictRetries = 3
+
 type managedDispatcherImpl struct {
 	sync.RWMutex
 
-	dispatcher            *operationDispatcherImpl
-	unmanagedDispatcher   *unmanagedDispatcherImpl
-	fedResource           FederatedResourceForDispatch
-	versionMap            map[string]string
-	statusMap             status.PropagationStatusMap
-	skipAdoptingResources bool
+	dispatcher              *operationDispatcherImpl
+	unmanagedDispatcher     *unmanagedDispatcherImpl
+	fedResource             FederatedResourceForDispatch
+	versionMap              map[string]string
+	statusMap               status.PropagationStatusMap
+	skipAdoptingResources   bool
+	validateDependencies    bool
+	differentialPropagation bool
+	createOnly              bool
+	reviewer                *webhook.Reviewer
+	applyObserver           util.ApplyObserver
+	logger                  logr.Logger
+
+	// The clusters in which resources are not updated outside of a
+	// maintenance window.
+	deferredClusters sets.String
+
+	// The clusters in which resources are not updated until the
+	// resource is healthy in the preceding clusters.
+	heldClusters sets.String
+
+	// Returns the schema of a cluster. Nil if resources are not
+	// compared with the schemas of clusters.
+	getSchema util.ClusterSchemaFunc
+
+	// How conflicting updates are resolved. Nil if they are left to
+	// be retried by a later reconciliation.
+	conflictResolution *fedv1b1.ConflictResolution
 
 	// Track when resource updates are performed to allow indicating
 	// when a change was last propagated to member clusters.
 	resourcesUpdated bool
 }
 
-func NewManagedDispatcher(clientAccessor clientAccessorFunc, fedResource FederatedResourceForDispatch, skipAdoptingResources bool) ManagedDispatcher {
+func NewManagedDispatcher(clientAccessor clientAccessorFunc, fedResource FederatedResourceForDispatch, skipAdoptingResources, validateDependencies, differentialPropagation, createOnly bool, reviewer *webhook.Reviewer, applyObserver util.ApplyObserver, logger logr.Logger, span *tracing.Span) ManagedDispatcher {
 	d := &managedDispatcherImpl{
-		fedResource:           fedResource,
-		versionMap:            make(map[string]string),
-		statusMap:             make(status.PropagationStatusMap),
-		skipAdoptingResources: skipAdoptingResources,
+		fedResource:             fedResource,
+		versionMap:              make(map[string]string),
+		statusMap:               make(status.PropagationStatusMap),
+		skipAdoptingResources:   skipAdoptingResources,
+		validateDependencies:    validateDependencies,
+		differentialPropagation: differentialPropagation,
+		createOnly:              createOnly,
+		reviewer:                reviewer,
+		applyObserver:           applyObserver,
+		logger:                  logger,
 	}
 	d.dispatcher = newOperationDispatcher(clientAccessor, d)
-	d.unmanagedDispatcher = newUnmanagedDispatcher(d.dispatcher, d, fedResource.TargetGVK(), fedResource.TargetName())
+	d.dispatcher.span = span
+	d.unmanagedDispatcher = newUnmanagedDispatcher(d.dispatcher, d, fedResource.TargetGVK(), fedResource.TargetNameForCluster)
 	return d
 }
 
@@ -133,9 +192,8 @@ func (d *managedDispatcherImpl) Create(clusterName string) {
 	// Wait() if a timeout does not occur.
 	d.RecordStatus(clusterName, status.CreationTimedOut)
 	start := time.Now()
-	d.dispatcher.incrementOperationsInitiated()
 	const op = "create"
-	go d.dispatcher.clusterOperation(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
+	d.dispatcher.dispatch(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
 		d.recordEvent(clusterName, op, "Creating")
 
 		obj, err := d.fedResource.ObjectForCluster(clusterName)
@@ -143,13 +201,29 @@ func (d *managedDispatcherImpl) Create(clusterName string) {
 			return d.recordOperationError(status.ComputeResourceFailed, clusterName, op, err)
 		}
 
-		err = d.fedResource.ApplyOverrides(obj, clusterName)
+		err = d.fedResource.ApplyOverrides(obj, clusterName, OverrideValueResolver(client, obj.GetNamespace()))
 		if err != nil {
 			return d.recordOperationError(status.ApplyOverridesFailed, clusterName, op, err)
 		}
 
+		err = d.setOwnerReferences(client, obj)
+		if err != nil {
+			return d.recordOperationError(status.OwnerResolutionFailed, clusterName, op, err)
+		}
+
+		if reconciliationStatus, ok := d.checkDependencies(client, obj, clusterName, op); !ok {
+			return reconciliationStatus
+		}
+
+		if reconciliationStatus, ok := d.review(obj, clusterName, op); !ok {
+			return reconciliationStatus
+		}
+
+		createStart := time.Now()
 		err = client.Create(context.Background(), obj)
+		metrics.DispatchOperation(d.fedResource.TargetKind(), clusterName, op, err, createStart)
 		if err == nil {
+			d.observeApply(clusterName, false)
 			version := util.ObjectVersion(obj)
 			d.recordVersion(clusterName, version)
 			metrics.DispatchOperationDurationFromStart("create", start)
@@ -160,6 +234,7 @@ func (d *managedDispatcherImpl) Create(clusterName string) {
 		// already exists indicates ServerTimeout instead of AlreadyExists.
 		alreadyExists := apierrors.IsAlreadyExists(err) || d.fedResource.TargetKind() == util.NamespaceKind && apierrors.IsServerTimeout(err)
 		if !alreadyExists {
+			d.observeApply(clusterName, true)
 			return d.recordOperationError(status.CreationFailed, clusterName, op, err)
 		}
 
@@ -186,28 +261,21 @@ func (d *managedDispatcherImpl) Create(clusterName string) {
 func (d *managedDispatcherImpl) Update(clusterName string, clusterObj *unstructured.Unstructured) {
 	d.RecordStatus(clusterName, status.UpdateTimedOut)
 
-	d.dispatcher.incrementOperationsInitiated()
 	const op = "update"
-	go d.dispatcher.clusterOperation(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
+	d.dispatcher.dispatch(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
 		if util.IsExplicitlyUnmanaged(clusterObj) {
 			err := errors.Errorf("Unable to manage the object which has label %s: %s", util.ManagedByKubeFedLabelKey, util.UnmanagedByKubeFedLabelValue)
 			return d.recordOperationError(status.ManagedLabelFalse, clusterName, op, err)
 		}
 
-		obj, err := d.fedResource.ObjectForCluster(clusterName)
-		if err != nil {
-			return d.recordOperationError(status.ComputeResourceFailed, clusterName, op, err)
+		if util.HasManagedLabel(clusterObj) && !util.IsPropagatedFor(clusterObj, clusterName, d.fedResource.TargetName()) {
+			err := errors.Errorf("The resource is managed by the federated resource for %q", util.NewSourceQualifiedName(clusterObj))
+			return d.recordOperationError(status.NameCollision, clusterName, op, err)
 		}
 
-		err = RetainClusterFields(d.fedResource.TargetKind(), obj, clusterObj, d.fedResource.Object())
+		obj, propStatus, err := d.desiredObject(client, clusterName, clusterObj)
 		if err != nil {
-			wrappedErr := errors.Wrapf(err, "failed to retain fields")
-			return d.recordOperationError(status.FieldRetentionFailed, clusterName, op, wrappedErr)
-		}
-
-		err = d.fedResource.ApplyOverrides(obj, clusterName)
-		if err != nil {
-			return d.recordOperationError(status.ApplyOverridesFailed, clusterName, op, err)
+			return d.recordOperationError(propStatus, clusterName, op, err)
 		}
 
 		version, err := d.fedResource.VersionForCluster(clusterName)
@@ -218,11 +286,51 @@ func (d *managedDispatcherImpl) Update(clusterName string, clusterObj *unstructu
 			// Resource is current
 			return util.StatusAllOK
 		}
+		if d.equivalent(clusterName, obj, clusterObj) {
+			// The resource only differs in fields defaulted by the
+			// cluster. Recording its version avoids comparing it
+			// again until either it or the federated resource changes.
+			d.recordVersion(clusterName, util.ObjectVersion(clusterObj))
+			return util.StatusAllOK
+		}
+
+		if d.createOnly {
+			// Resources of a create-only type are left to be
+			// managed in member clusters once created.
+			d.RecordStatus(clusterName, status.Drifted)
+			return util.StatusAllOK
+		}
+
+		if d.deferredClusters.Has(clusterName) {
+			// The resource will be updated once a maintenance
+			// window of the cluster opens.
+			d.RecordStatus(clusterName, status.MaintenanceDeferred)
+			return util.StatusAllOK
+		}
+
+		if d.heldClusters.Has(clusterName) {
+			// The resource will be updated once it is healthy in
+			// the preceding clusters.
+			d.RecordStatus(clusterName, status.WaitingForHealthy)
+			return util.StatusAllOK
+		}
+
+		if reconciliationStatus, ok := d.checkDependencies(client, obj, clusterName, op); !ok {
+			return reconciliationStatus
+		}
+
+		if reconciliationStatus, ok := d.review(obj, clusterName, op); !ok {
+			return reconciliationStatus
+		}
 
 		// Only record an event if the resource is not current
 		d.recordEvent(clusterName, op, "Updating")
 
-		err = client.Update(context.Background(), obj)
+		err = d.update(client, clusterName, obj, clusterObj)
+		if apierrors.IsConflict(err) {
+			obj, err = d.resolveConflict(client, clusterName, obj, err)
+		}
+		d.observeApply(clusterName, err != nil)
 		if err != nil {
 			return d.recordOperationError(status.UpdateFailed, clusterName, op, err)
 		}
@@ -233,6 +341,189 @@ func (d *managedDispatcherImpl) Update(clusterName string, clusterObj *unstructu
 	})
 }
 
+// desiredObject returns the resource that the given resource in the
+// named cluster is to be updated to. If the resource cannot be
+// computed, the status to record for the cluster is returned with the
+// error.
+func (d *managedDispatcherImpl) desiredObject(client generic.Client, clusterName string, clusterObj *unstructured.Unstructured) (*unstructured.Unstructured, status.PropagationStatus, error) {
+	obj, err := d.fedResource.ObjectForCluster(clusterName)
+	if err != nil {
+		return nil, status.ComputeResourceFailed, err
+	}
+
+	err = RetainClusterFields(d.fedResource.TargetKind(), obj, clusterObj, d.fedResource.Object())
+	if err != nil {
+		return nil, status.FieldRetentionFailed, errors.Wrapf(err, "failed to retain fields")
+	}
+
+	err = d.fedResource.ApplyOverrides(obj, clusterName, OverrideValueResolver(client, obj.GetNamespace()))
+	if err != nil {
+		return nil, status.ApplyOverridesFailed, err
+	}
+
+	err = d.setOwnerReferences(client, obj)
+	if err != nil {
+		return nil, status.OwnerResolutionFailed, err
+	}
+	return obj, "", nil
+}
+
+// resolveConflict retries an update of the given resource in the named
+// cluster that failed with the given conflict error, according to the
+// conflict resolution of the type. The resource last attempted to be
+// updated is returned with the error of the attempt.
+func (d *managedDispatcherImpl) resolveConflict(client generic.Client, clusterName string, obj *unstructured.Unstructured, err error) (*unstructured.Unstructured, error) {
+	strategy := "None"
+	if d.conflictResolution != nil {
+		strategy = string(d.conflictResolution.Strategy)
+	}
+	metrics.DispatchConflict(clusterName, strategy)
+	if d.conflictResolution == nil {
+		return obj, err
+	}
+
+	for i := 0; i < maxConflictRetries; i++ {
+		clusterObj := &unstructured.Unstructured{}
+		clusterObj.SetGroupVersionKind(obj.GroupVersionKind())
+		getErr := client.Get(context.Background(), clusterObj, obj.GetNamespace(), obj.GetName())
+		if getErr != nil {
+			return obj, errors.Wrapf(getErr, "failed to retrieve the resource after a conflicting update")
+		}
+
+		obj, err = d.rebase(client, clusterName, obj, clusterObj)
+		if err != nil {
+			return obj, err
+		}
+		d.logger.V(4).Info("Retrying conflicting update", logging.ClusterKey, clusterName, "strategy", strategy)
+		err = d.update(client, clusterName, obj, clusterObj)
+		if !apierrors.IsConflict(err) {
+			return obj, err
+		}
+		metrics.DispatchConflict(clusterName, strategy)
+	}
+	return obj, err
+}
+
+// rebase returns the given resource updated to replace the given
+// changed resource in the named cluster according to the conflict
+// resolution strategy of the type.
+func (d *managedDispatcherImpl) rebase(client generic.Client, clusterName string, obj, clusterObj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if d.conflictResolution.Strategy == fedv1b1.ConflictStrategyPreferFederated {
+		obj.SetResourceVersion(clusterObj.GetResourceVersion())
+		return obj, nil
+	}
+
+	rebased, _, err := d.desiredObject(client, clusterName, clusterObj)
+	if err != nil {
+		return obj, err
+	}
+	if d.conflictResolution.Strategy == fedv1b1.ConflictStrategyPreferLocalFields {
+		err = RetainLocalFields(rebased, clusterObj, d.conflictResolution.LocalFields)
+		if err != nil {
+			return obj, errors.Wrapf(err, "failed to retain local fields")
+		}
+	}
+	// Propagation webhooks may mutate the rebased resource, so it is
+	// reviewed again.
+	if d.reviewer != nil {
+		err = d.reviewer.Review(rebased, clusterName, "update")
+		if err != nil {
+			return obj, err
+		}
+	}
+	return rebased, nil
+}
+
+// equivalent returns whether the given cluster resource differs from
+// the desired resource only in fields defaulted by the cluster. The
+// resources are not considered equivalent if the schema of the cluster
+// is not available.
+func (d *managedDispatcherImpl) equivalent(clusterName string, obj, clusterObj *unstructured.Unstructured) bool {
+	if d.getSchema == nil {
+		return false
+	}
+	clusterSchema, err := d.getSchema(clusterName)
+	if err != nil {
+		d.logger.V(4).Info("Unable to retrieve the schema of the cluster", logging.ClusterKey, clusterName, "error", err.Error())
+		return false
+	}
+	return clusterSchema != nil && clusterSchema.Equivalent(obj, clusterObj)
+}
+
+// update updates the given resource in the named member cluster. If
+// differential propagation is enabled, ConfigMaps and Secrets are
+// instead patched with their changes to the given cluster resource
+// when the patch is substantially smaller than the resource.
+func (d *managedDispatcherImpl) update(client generic.Client, clusterName string, obj, clusterObj *unstructured.Unstructured) error {
+	if d.differentialPropagation && differentialKinds.Has(obj.GetKind()) {
+		patch, ok, err := differentialPatch(obj, clusterObj)
+		if err != nil {
+			return err
+		}
+		if ok {
+			start := time.Now()
+			err = client.Patch(context.Background(), obj, types.MergePatchType, patch)
+			metrics.DispatchOperation(d.fedResource.TargetKind(), clusterName, "patch", err, start)
+			return err
+		}
+	}
+	start := time.Now()
+	err := client.Update(context.Background(), obj)
+	metrics.DispatchOperation(d.fedResource.TargetKind(), clusterName, "update", err, start)
+	return err
+}
+
+// setOwnerReferences sets the owner references declared by the
+// federated resource on the given object, resolving the uid of each
+// owner in the member cluster. An owner that has yet to be created in
+// the member cluster prevents the object from being propagated.
+func (d *managedDispatcherImpl) setOwnerReferences(client generic.Client, obj *unstructured.Unstructured) error {
+	refs, err := util.GetOwnerReferences(d.fedResource.Object())
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	return util.SetOwnerReferences(obj, refs, ownerUIDResolver(client, obj.GetNamespace()))
+}
+
+// checkDependencies verifies that the dependencies of the given object
+// exist in the named cluster if dependency validation is enabled.
+// Propagation to the cluster is prevented with the MissingDependency
+// status while a dependency is missing.
+func (d *managedDispatcherImpl) checkDependencies(client generic.Client, obj *unstructured.Unstructured, clusterName, op string) (util.ReconciliationStatus, bool) {
+	if !d.validateDependencies {
+		return util.StatusAllOK, true
+	}
+	missing, err := checkDependencies(client, obj)
+	if missing {
+		return d.recordOperationError(status.MissingDependency, clusterName, op, err), false
+	}
+	if err != nil {
+		return d.recordOperationError(status.RetrievalFailed, clusterName, op, err), false
+	}
+	return util.StatusAllOK, true
+}
+
+// review calls the propagation webhooks of the type with the given
+// object before it is created or updated in the named cluster.
+// Propagation to the cluster is prevented with the PropagationDenied
+// status if a webhook denies it.
+func (d *managedDispatcherImpl) review(obj *unstructured.Unstructured, clusterName, op string) (util.ReconciliationStatus, bool) {
+	if d.reviewer == nil {
+		return util.StatusAllOK, true
+	}
+	err := d.reviewer.Review(obj, clusterName, op)
+	if webhook.IsDenied(err) {
+		return d.recordOperationError(status.PropagationDenied, clusterName, op, err), false
+	}
+	if err != nil {
+		return d.recordOperationError(status.WebhookFailed, clusterName, op, err), false
+	}
+	return util.StatusAllOK, true
+}
+
 func (d *managedDispatcherImpl) Delete(clusterName string) {
 	d.RecordStatus(clusterName, status.DeletionTimedOut)
 
@@ -250,6 +541,26 @@ func (d *managedDispatcherImpl) RecordClusterError(propStatus status.Propagation
 	d.RecordStatus(clusterName, propStatus)
 }
 
+func (d *managedDispatcherImpl) DeferUpdates(clusterNames sets.String) {
+	d.deferredClusters = clusterNames
+}
+
+func (d *managedDispatcherImpl) HoldUpdates(clusterNames sets.String) {
+	d.heldClusters = clusterNames
+}
+
+func (d *managedDispatcherImpl) OrderClusters(clusterNames []string) {
+	d.dispatcher.setClusterOrder(clusterNames)
+}
+
+func (d *managedDispatcherImpl) CompareWithSchemas(getSchema util.ClusterSchemaFunc) {
+	d.getSchema = getSchema
+}
+
+func (d *managedDispatcherImpl) ResolveConflicts(resolution *fedv1b1.ConflictResolution) {
+	d.conflictResolution = resolution
+}
+
 func (d *managedDispatcherImpl) RecordStatus(clusterName string, propStatus status.PropagationStatus) {
 	d.Lock()
 	defer d.Unlock()
@@ -262,10 +573,19 @@ func (d *managedDispatcherImpl) recordOperationError(propStatus status.Propagati
 	return util.StatusError
 }
 
+// observeApply reports the outcome of a create or update in the named
+// cluster to the apply observer, if one is configured.
+func (d *managedDispatcherImpl) observeApply(clusterName string, failed bool) {
+	if d.applyObserver != nil {
+		d.applyObserver.ObserveApply(clusterName, failed)
+	}
+}
+
 func (d *managedDispatcherImpl) recordError(clusterName, operation string, err error) {
 	targetName := d.unmanagedDispatcher.targetNameForCluster(clusterName)
 	args := []interface{}{operation, d.fedResource.TargetKind(), targetName, clusterName}
 	eventType := fmt.Sprintf("%sInClusterFailed", strings.Replace(strings.Title(operation), " ", "", -1))
+	d.logger.Error(err, "Operation failed", logging.ClusterKey, clusterName, "operation", operation, "kind", d.fedResource.TargetKind())
 	d.fedResource.RecordError(eventType, errors.Wrapf(err, "Failed to "+eventTemplate, args...))
 }
 
@@ -273,6 +593,7 @@ func (d *managedDispatcherImpl) recordEvent(clusterName, operation, operationCon
 	targetName := d.unmanagedDispatcher.targetNameForCluster(clusterName)
 	args := []interface{}{operationContinuous, d.fedResource.TargetKind(), targetName, clusterName}
 	eventType := fmt.Sprintf("%sInCluster", strings.Replace(strings.Title(operation), " ", "", -1))
+	d.logger.V(2).Info("Dispatching operation", logging.ClusterKey, clusterName, "operation", operation, "kind", d.fedResource.TargetKind())
 	d.fedResource.RecordEvent(eventType, eventTemplate, args...)
 }
 
@@ -17,20 +17,38 @@ limitations under the License.
 package dispatch
 
 import (
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/tracing"
 )
 
 type clientAccessorFunc func(clusterName string) (generic.Client, error)
 
+type operationFunc func(generic.Client) util.ReconciliationStatus
+
+// The operations that are dispatched in the order of their clusters.
+// Other operations, like deletions, are dispatched in reverse order.
+var forwardOperations = sets.NewString("create", "update")
+
+// queuedOperation is an operation for a cluster whose operations are
+// dispatched in order.
+type queuedOperation struct {
+	clusterName string
+	op          string
+	opFunc      operationFunc
+}
+
 type dispatchRecorder interface {
 	recordEvent(clusterName, operation, operationContinuous string)
 	recordOperationError(status status.PropagationStatus, clusterName, operation string, err error) util.ReconciliationStatus
@@ -51,27 +69,56 @@ type operationDispatcherImpl struct {
 	resultChan          chan util.ReconciliationStatus
 	operationsInitiated int32
 
+	// Closed once Wait stops waiting for results so that operations
+	// completing after a timeout do not block sending their results.
+	done chan struct{}
+
+	// The time allowed for concurrent operations and for each
+	// operation dispatched in order.
 	timeout time.Duration
 
 	recorder dispatchRecorder
+
+	// Parent of the spans recording each cluster operation.
+	span *tracing.Span
+
+	// The position of each cluster whose operations are dispatched
+	// one at a time in order. Nil if all operations are dispatched
+	// concurrently.
+	clusterOrder map[string]int
+
+	sequenceLock     sync.Mutex
+	queuedOperations []queuedOperation
+	sequenceStarted  bool
+	sequenceLength   int
 }
 
 func newOperationDispatcher(clientAccessor clientAccessorFunc, recorder dispatchRecorder) *operationDispatcherImpl {
 	return &operationDispatcherImpl{
 		clientAccessor: clientAccessor,
 		resultChan:     make(chan util.ReconciliationStatus),
+		done:           make(chan struct{}),
 		timeout:        30 * time.Second, // TODO(marun) Make this configurable
 		recorder:       recorder,
 	}
 }
 
 func (d *operationDispatcherImpl) Wait() (bool, error) {
+	d.startSequence()
+
+	// Operations dispatched in order run one after another, so each
+	// of them is allowed the timeout.
+	timeout := d.timeout
+	if d.sequenceLength > 1 {
+		timeout *= time.Duration(d.sequenceLength)
+	}
+
 	ok := true
 	timedOut := false
 	start := time.Now()
 	for i := int32(0); i < atomic.LoadInt32(&d.operationsInitiated); i++ {
 		now := time.Now()
-		if !now.Before(start.Add(d.timeout)) {
+		if !now.Before(start.Add(timeout)) {
 			timedOut = true
 			break
 		}
@@ -81,34 +128,167 @@ func (d *operationDispatcherImpl) Wait() (bool, error) {
 				ok = false
 			}
 			break
-		case <-time.After(start.Add(d.timeout).Sub(now)):
+		case <-time.After(start.Add(timeout).Sub(now)):
 			timedOut = true
 			break
 		}
 	}
 	if timedOut {
-		return false, errors.Errorf("Failed to finish %d operations in %v", atomic.LoadInt32(&d.operationsInitiated), d.timeout)
+		close(d.done)
+		return false, errors.Errorf("Failed to finish %d operations in %v", atomic.LoadInt32(&d.operationsInitiated), timeout)
 	}
 	return ok, nil
 }
 
-func (d *operationDispatcherImpl) clusterOperation(clusterName, op string, opFunc func(generic.Client) util.ReconciliationStatus) {
+// sendResult sends the result of an operation to Wait. False is
+// returned without blocking if Wait is no longer waiting for results.
+func (d *operationDispatcherImpl) sendResult(result util.ReconciliationStatus) bool {
+	select {
+	case d.resultChan <- result:
+		return true
+	case <-d.done:
+		return false
+	}
+}
+
+// setClusterOrder dispatches the operations for the named clusters one
+// at a time in the given order. Creates and updates follow the order
+// and are dispatched before other operations, which follow the reverse
+// order. Once an operation fails, the operations for the clusters that
+// follow are not attempted.
+func (d *operationDispatcherImpl) setClusterOrder(clusterNames []string) {
+	if len(clusterNames) == 0 {
+		d.clusterOrder = nil
+		return
+	}
+	d.clusterOrder = make(map[string]int, len(clusterNames))
+	for i, clusterName := range clusterNames {
+		if _, ok := d.clusterOrder[clusterName]; !ok {
+			d.clusterOrder[clusterName] = i
+		}
+	}
+}
+
+// dispatch initiates the given operation for the named cluster. The
+// operation is queued until Wait is called if the operations of the
+// cluster are dispatched in order.
+func (d *operationDispatcherImpl) dispatch(clusterName, op string, opFunc operationFunc) {
+	d.incrementOperationsInitiated()
+	if _, ok := d.clusterOrder[clusterName]; !ok {
+		go d.clusterOperation(clusterName, op, opFunc)
+		return
+	}
+
+	d.sequenceLock.Lock()
+	started := d.sequenceStarted
+	if !started {
+		d.queuedOperations = append(d.queuedOperations, queuedOperation{
+			clusterName: clusterName,
+			op:          op,
+			opFunc:      opFunc,
+		})
+	}
+	d.sequenceLock.Unlock()
+	if started {
+		// The operation was initiated by a queued operation for the
+		// same cluster (e.g. a create that found an existing
+		// resource), and completes before the sequence continues.
+		d.clusterOperation(clusterName, op, opFunc)
+	}
+}
+
+// startSequence starts dispatching the queued operations one at a
+// time.
+func (d *operationDispatcherImpl) startSequence() {
+	d.sequenceLock.Lock()
+	defer d.sequenceLock.Unlock()
+	if d.sequenceStarted {
+		return
+	}
+	d.sequenceStarted = true
+	operations := d.queuedOperations
+	d.queuedOperations = nil
+	d.sequenceLength = len(operations)
+	if len(operations) == 0 {
+		return
+	}
+
+	sort.SliceStable(operations, func(i, j int) bool {
+		forwardI, forwardJ := forwardOperations.Has(operations[i].op), forwardOperations.Has(operations[j].op)
+		if forwardI != forwardJ {
+			return forwardI
+		}
+		positionI, positionJ := d.clusterOrder[operations[i].clusterName], d.clusterOrder[operations[j].clusterName]
+		if forwardI {
+			return positionI < positionJ
+		}
+		return positionI > positionJ
+	})
+	go func() {
+		failedCluster := ""
+		for _, operation := range operations {
+			// The remaining operations are abandoned once Wait has
+			// timed out, and will be dispatched by a later reconcile.
+			select {
+			case <-d.done:
+				return
+			default:
+			}
+			if len(failedCluster) > 0 {
+				if !d.sendResult(d.skipOperation(operation, failedCluster)) {
+					return
+				}
+				continue
+			}
+			result := d.runOperation(operation.clusterName, operation.op, operation.opFunc)
+			if result == util.StatusError {
+				failedCluster = operation.clusterName
+			}
+			if !d.sendResult(result) {
+				return
+			}
+		}
+	}()
+}
+
+// skipOperation records that the given queued operation was not
+// attempted because the operation for a preceding cluster failed.
+func (d *operationDispatcherImpl) skipOperation(operation queuedOperation, failedCluster string) util.ReconciliationStatus {
+	err := errors.Errorf("Not attempted because the %s operation for the preceding cluster %q failed", operation.op, failedCluster)
+	if d.recorder == nil {
+		runtime.HandleError(err)
+		return util.StatusError
+	}
+	return d.recorder.recordOperationError(status.PrecedingClusterFailed, operation.clusterName, operation.op, err)
+}
+
+func (d *operationDispatcherImpl) clusterOperation(clusterName, op string, opFunc operationFunc) {
+	d.sendResult(d.runOperation(clusterName, op, opFunc))
+}
+
+func (d *operationDispatcherImpl) runOperation(clusterName, op string, opFunc operationFunc) util.ReconciliationStatus {
+	span := d.span.StartChild(op, tracing.String("cluster", clusterName))
+	defer span.End()
+
 	// TODO(marun) Support cancellation of client calls on timeout.
 	client, err := d.clientAccessor(clusterName)
 	if err != nil {
 		wrappedErr := errors.Wrapf(err, "Error retrieving client for cluster")
+		span.RecordError(wrappedErr)
 		if d.recorder == nil {
 			runtime.HandleError(wrappedErr)
 		} else {
 			d.recorder.recordOperationError(status.ClientRetrievalFailed, clusterName, op, wrappedErr)
 		}
-		d.resultChan <- util.StatusError
-		return
+		return util.StatusError
 	}
 
 	// TODO(marun) Retry on recoverable errors (e.g. IsConflict, AlreadyExists)
 	ok := opFunc(client)
-	d.resultChan <- ok
+	if ok == util.StatusError {
+		span.SetFailed()
+	}
+	return ok
 }
 
 func (d *operationDispatcherImpl) incrementOperationsInitiated() {
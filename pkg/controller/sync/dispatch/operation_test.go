@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestOrderedOperations(t *testing.T) {
+	testCases := map[string]struct {
+		operations       []string
+		failedCluster    string
+		expectedSequence []string
+		expectedOK       bool
+	}{
+		"Creates and updates follow the order": {
+			operations:       []string{"update/prod", "create/staging", "update/dev"},
+			expectedSequence: []string{"update/dev", "create/staging", "update/prod"},
+			expectedOK:       true,
+		},
+		"Deletions follow the reverse order after creates and updates": {
+			operations:       []string{"delete/dev", "create/prod", "delete/staging"},
+			expectedSequence: []string{"create/prod", "delete/staging", "delete/dev"},
+			expectedOK:       true,
+		},
+		"Operations following a failure are not attempted": {
+			operations:       []string{"update/dev", "update/staging", "update/prod"},
+			failedCluster:    "staging",
+			expectedSequence: []string{"update/dev", "update/staging"},
+			expectedOK:       false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			clientAccessor := func(clusterName string) (generic.Client, error) {
+				return nil, nil
+			}
+			d := newOperationDispatcher(clientAccessor, nil)
+			d.setClusterOrder([]string{"dev", "staging", "prod"})
+
+			var lock sync.Mutex
+			sequence := []string{}
+			for _, operation := range tc.operations {
+				parts := strings.SplitN(operation, "/", 2)
+				op, clusterName := parts[0], parts[1]
+				d.dispatch(clusterName, op, func(generic.Client) util.ReconciliationStatus {
+					lock.Lock()
+					defer lock.Unlock()
+					sequence = append(sequence, op+"/"+clusterName)
+					if clusterName == tc.failedCluster {
+						return util.StatusError
+					}
+					return util.StatusAllOK
+				})
+			}
+
+			ok, err := d.Wait()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ok != tc.expectedOK {
+				t.Errorf("Expected ok to be %v, got %v", tc.expectedOK, ok)
+			}
+			if !reflect.DeepEqual(sequence, tc.expectedSequence) {
+				t.Errorf("Expected sequence %v, got %v", tc.expectedSequence, sequence)
+			}
+		})
+	}
+}
+
+func TestOrderedOperationsTimeout(t *testing.T) {
+	clientAccessor := func(clusterName string) (generic.Client, error) {
+		return nil, nil
+	}
+
+	// Each operation dispatched in order is allowed the timeout, so a
+	// sequence taking longer than the timeout in total completes.
+	d := newOperationDispatcher(clientAccessor, nil)
+	d.timeout = 100 * time.Millisecond
+	d.setClusterOrder([]string{"dev", "staging", "prod"})
+	for _, clusterName := range []string{"dev", "staging", "prod"} {
+		d.dispatch(clusterName, "update", func(generic.Client) util.ReconciliationStatus {
+			time.Sleep(50 * time.Millisecond)
+			return util.StatusAllOK
+		})
+	}
+	ok, err := d.Wait()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("Expected the operations to succeed")
+	}
+
+	// Once an operation exceeds its timeout, the operations that
+	// follow it are not attempted and results no longer block.
+	d = newOperationDispatcher(clientAccessor, nil)
+	d.timeout = 10 * time.Millisecond
+	d.setClusterOrder([]string{"dev", "prod"})
+	blocked := make(chan struct{})
+	attempted := make(chan string, 2)
+	for _, clusterName := range []string{"dev", "prod"} {
+		clusterName := clusterName
+		d.dispatch(clusterName, "update", func(generic.Client) util.ReconciliationStatus {
+			attempted <- clusterName
+			<-blocked
+			return util.StatusAllOK
+		})
+	}
+	if _, err := d.Wait(); err == nil {
+		t.Fatalf("Expected a timeout error")
+	}
+	close(blocked)
+	if sent := d.sendResult(util.StatusAllOK); sent {
+		t.Fatalf("Expected a result sent after the timeout to be dropped")
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(attempted)
+	clusterNames := []string{}
+	for clusterName := range attempted {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	if !reflect.DeepEqual(clusterNames, []string{"dev"}) {
+		t.Fatalf("Expected only the operation for dev to be attempted, got %v", clusterNames)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// OverrideValueResolver returns a function that retrieves override
+// values from ConfigMaps in a member cluster with the given client.
+// References that do not specify a namespace are resolved in the
+// namespace of the resource being propagated.
+func OverrideValueResolver(client generic.Client, namespace string) util.OverrideValueFunc {
+	return func(source *util.OverrideValueSource) (interface{}, error) {
+		ref := source.ConfigMapKeyRef
+		if ref == nil {
+			return nil, errors.New("no value source specified")
+		}
+		refNamespace := ref.Namespace
+		if refNamespace == "" {
+			refNamespace = namespace
+		}
+		if refNamespace == "" {
+			return nil, errors.Errorf("a namespace must be specified for ConfigMap %q of a cluster-scoped resource", ref.Name)
+		}
+
+		configMap := &corev1.ConfigMap{}
+		err := client.Get(context.Background(), configMap, refNamespace, ref.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve ConfigMap \"%s/%s\"", refNamespace, ref.Name)
+		}
+		value, ok := configMap.Data[ref.Key]
+		if !ok {
+			return nil, errors.Errorf("key %q not found in ConfigMap \"%s/%s\"", ref.Key, refNamespace, ref.Name)
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// ownerUIDResolver returns a function that retrieves the uid of an
+// owner in a member cluster with the given client. Owners are expected
+// to be in the namespace of the resource being propagated.
+func ownerUIDResolver(client generic.Client, namespace string) util.OwnerUIDFunc {
+	return func(ref util.GenericOwnerReference) (types.UID, error) {
+		owner := &unstructured.Unstructured{}
+		owner.SetAPIVersion(ref.APIVersion)
+		owner.SetKind(ref.Kind)
+		err := client.Get(context.Background(), owner, namespace, ref.Name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to retrieve %s %q", ref.Kind, ref.Name)
+		}
+		if owner.GetDeletionTimestamp() != nil {
+			return "", errors.Errorf("%s %q is being deleted", ref.Kind, ref.Name)
+		}
+		return owner.GetUID(), nil
+	}
+}
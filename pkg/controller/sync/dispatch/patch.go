@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"encoding/json"
+
+	"github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	// maxDifferentialPatchRatio is the largest size of a patch,
+	// relative to the size of the full object, for which a resource
+	// is patched rather than updated.
+	maxDifferentialPatchRatio = 0.5
+)
+
+var (
+	// differentialKinds are the kinds of resources that are patched
+	// with their changes when the DifferentialPropagation feature is
+	// enabled. Their data is typically large relative to the part of
+	// it that changes.
+	differentialKinds = sets.NewString(util.ConfigMapKind, util.SecretKind)
+
+	// serverMetadataFields are the metadata fields that are set by
+	// the API server of a member cluster and are never propagated.
+	serverMetadataFields = []string{"uid", "selfLink", "creationTimestamp", "generation", "managedFields"}
+)
+
+// differentialPatch returns a JSON merge patch that changes the given
+// resource of a member cluster into the desired resource, and whether
+// the patch is small enough relative to the desired resource to be
+// worth sending instead of the desired resource. The patch includes
+// the resource version of the cluster resource so that it fails on
+// conflict like an update.
+func differentialPatch(desiredObj, clusterObj *unstructured.Unstructured) ([]byte, bool, error) {
+	original := clusterObj.DeepCopy()
+	for _, field := range serverMetadataFields {
+		unstructured.RemoveNestedField(original.Object, "metadata", field)
+	}
+	originalJSON, err := original.MarshalJSON()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to marshal the cluster resource")
+	}
+	desiredJSON, err := desiredObj.MarshalJSON()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to marshal the desired resource")
+	}
+	patchJSON, err := jsonpatch.CreateMergePatch(originalJSON, desiredJSON)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to compute the patch")
+	}
+
+	patch := make(map[string]interface{})
+	if err := json.Unmarshal(patchJSON, &patch); err != nil {
+		return nil, false, errors.Wrap(err, "failed to unmarshal the patch")
+	}
+	err = unstructured.SetNestedField(patch, clusterObj.GetResourceVersion(), "metadata", "resourceVersion")
+	if err != nil {
+		return nil, false, err
+	}
+	patchJSON, err = json.Marshal(patch)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to marshal the patch")
+	}
+	return patchJSON, float64(len(patchJSON)) <= maxDifferentialPatchRatio*float64(len(desiredJSON)), nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDifferentialPatch(t *testing.T) {
+	largeValue := strings.Repeat("x", 1000)
+	newConfigMap := func(data map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":            "settings",
+					"namespace":       "team-a",
+					"resourceVersion": "42",
+				},
+				"data": data,
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		desiredData   map[string]interface{}
+		expectedPatch map[string]interface{}
+		expectedOK    bool
+	}{
+		"Small change to a large resource": {
+			desiredData: map[string]interface{}{"large": largeValue, "level": "debug"},
+			expectedPatch: map[string]interface{}{
+				"metadata": map[string]interface{}{"resourceVersion": "42"},
+				"data":     map[string]interface{}{"level": "debug"},
+			},
+			expectedOK: true,
+		},
+		"Removed key": {
+			desiredData: map[string]interface{}{"large": largeValue},
+			expectedPatch: map[string]interface{}{
+				"metadata": map[string]interface{}{"resourceVersion": "42"},
+				"data":     map[string]interface{}{"level": nil},
+			},
+			expectedOK: true,
+		},
+		"Change to most of the resource": {
+			desiredData: map[string]interface{}{"large": strings.Repeat("y", 1000), "level": "info"},
+			expectedPatch: map[string]interface{}{
+				"metadata": map[string]interface{}{"resourceVersion": "42"},
+				"data":     map[string]interface{}{"large": strings.Repeat("y", 1000)},
+			},
+			expectedOK: false,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			clusterObj := newConfigMap(map[string]interface{}{"large": largeValue, "level": "info"})
+			clusterObj.SetUID("1234")
+			clusterObj.SetGeneration(3)
+			desiredObj := newConfigMap(tc.desiredData)
+
+			patchJSON, ok, err := differentialPatch(desiredObj, clusterObj)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			patch := make(map[string]interface{})
+			if err := json.Unmarshal(patchJSON, &patch); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(patch, tc.expectedPatch) {
+				t.Errorf("Expected patch %v, got %v", tc.expectedPatch, patch)
+			}
+			if ok != tc.expectedOK {
+				t.Errorf("Expected ok %v, got %v", tc.expectedOK, ok)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package dispatch
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -42,6 +45,9 @@ func RetainClusterFields(targetKind string, desiredObj, clusterObj, fedObj *unst
 	if targetKind == util.ServiceAccountKind {
 		return retainServiceAccountFields(desiredObj, clusterObj)
 	}
+	if targetKind == util.PersistentVolumeClaimKind {
+		return retainPersistentVolumeClaimFields(desiredObj, clusterObj)
+	}
 	return retainReplicas(desiredObj, clusterObj, fedObj)
 }
 
@@ -104,34 +110,99 @@ func retainServiceFields(desiredObj, clusterObj *unstructured.Unstructured) erro
 // secret from a service account, prompting continual regeneration by the
 // service account controller in the member cluster.
 //
-// TODO(marun) Clearing a manually-set secrets field will require resetting
-// placement.  Is there a better way to do this?
+// If the desired representation does include secrets, references to token
+// secrets generated for the service account are merged from the cluster
+// object.  References to token secrets in the desired representation are
+// dropped since they refer to secrets generated in another cluster.
 func retainServiceAccountFields(desiredObj, clusterObj *unstructured.Unstructured) error {
 	// Check whether the secrets field is populated in the desired object.
 	desiredSecrets, ok, err := unstructured.NestedSlice(desiredObj.Object, util.SecretsField)
 	if err != nil {
 		return errors.Wrap(err, "Error retrieving secrets from desired service account")
 	}
-	if ok && len(desiredSecrets) > 0 {
-		// Field is populated, so an update to the target resource does not
-		// risk triggering a race with the service account controller.
+
+	// Retrieve the secrets from the cluster object.
+	secrets, _, err := unstructured.NestedSlice(clusterObj.Object, util.SecretsField)
+	if err != nil {
+		return errors.Wrap(err, "Error retrieving secrets from service account")
+	}
+
+	if !ok || len(desiredSecrets) == 0 {
+		// Retain all secrets of the cluster object.
+		if len(secrets) > 0 {
+			err := unstructured.SetNestedField(desiredObj.Object, secrets, util.SecretsField)
+			if err != nil {
+				return errors.Wrap(err, "Error setting secrets for service account")
+			}
+		}
 		return nil
 	}
 
-	// Retrieve the secrets from the cluster object and retain them.
-	secrets, ok, err := unstructured.NestedSlice(clusterObj.Object, util.SecretsField)
+	tokenPrefix := fmt.Sprintf("%s-token-", desiredObj.GetName())
+	mergedSecrets := []interface{}{}
+	for _, secret := range desiredSecrets {
+		if !isTokenSecretReference(secret, tokenPrefix) {
+			mergedSecrets = append(mergedSecrets, secret)
+		}
+	}
+	for _, secret := range secrets {
+		if isTokenSecretReference(secret, tokenPrefix) {
+			mergedSecrets = append(mergedSecrets, secret)
+		}
+	}
+	if len(mergedSecrets) == 0 {
+		unstructured.RemoveNestedField(desiredObj.Object, util.SecretsField)
+		return nil
+	}
+	err = unstructured.SetNestedField(desiredObj.Object, mergedSecrets, util.SecretsField)
 	if err != nil {
-		return errors.Wrap(err, "Error retrieving secrets from service account")
+		return errors.Wrap(err, "Error setting secrets for service account")
 	}
-	if ok && len(secrets) > 0 {
-		err := unstructured.SetNestedField(desiredObj.Object, secrets, util.SecretsField)
+	return nil
+}
+
+// retainPersistentVolumeClaimFields retains the fields of the spec of a
+// persistent volume claim that are set in the member cluster when the
+// desired representation does not include a value for them. The volume
+// a claim is bound to and the default storage class are assigned by
+// the member cluster, and the spec of a claim may not be changed to
+// clear them.
+func retainPersistentVolumeClaimFields(desiredObj, clusterObj *unstructured.Unstructured) error {
+	for _, field := range []string{util.VolumeNameField, util.StorageClassNameField} {
+		_, ok, err := unstructured.NestedString(desiredObj.Object, util.SpecField, field)
 		if err != nil {
-			return errors.Wrap(err, "Error setting secrets for service account")
+			return errors.Wrapf(err, "Error retrieving %s from desired persistent volume claim", field)
+		}
+		if ok {
+			continue
+		}
+		value, ok, err := unstructured.NestedString(clusterObj.Object, util.SpecField, field)
+		if err != nil {
+			return errors.Wrapf(err, "Error retrieving %s from persistent volume claim", field)
+		}
+		if !ok || value == "" {
+			continue
+		}
+		err = unstructured.SetNestedField(desiredObj.Object, value, util.SpecField, field)
+		if err != nil {
+			return errors.Wrapf(err, "Error setting %s for persistent volume claim", field)
 		}
 	}
 	return nil
 }
 
+// isTokenSecretReference returns whether the given secret reference of
+// a service account refers to a token secret generated by the service
+// account controller.
+func isTokenSecretReference(secret interface{}, tokenPrefix string) bool {
+	ref, ok := secret.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	name, ok := ref["name"].(string)
+	return ok && strings.HasPrefix(name, tokenPrefix)
+}
+
 func retainReplicas(desiredObj, clusterObj, fedObj *unstructured.Unstructured) error {
 	// Retain the replicas field if the federated object has been
 	// configured to do so.  If the replicas field is intended to be
@@ -155,3 +226,26 @@ func retainReplicas(desiredObj, clusterObj, fedObj *unstructured.Unstructured) e
 	}
 	return nil
 }
+
+// RetainLocalFields updates the desired object with the values of the
+// given fields of the cluster object, with the components of each path
+// separated by dots. A field missing from the cluster object is
+// removed from the desired object.
+func RetainLocalFields(desiredObj, clusterObj *unstructured.Unstructured, paths []string) error {
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		value, ok, err := unstructured.NestedFieldCopy(clusterObj.Object, fields...)
+		if err != nil {
+			return errors.Wrapf(err, "Error retrieving %q from cluster object", path)
+		}
+		if !ok {
+			unstructured.RemoveNestedField(desiredObj.Object, fields...)
+			continue
+		}
+		err = unstructured.SetNestedField(desiredObj.Object, value, fields...)
+		if err != nil {
+			return errors.Wrapf(err, "Error setting %q", path)
+		}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package dispatch
 
 import (
+	"reflect"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -85,3 +86,165 @@ func TestRetainClusterFields(t *testing.T) {
 		})
 	}
 }
+
+func TestRetainServiceAccountFields(t *testing.T) {
+	secretRefs := func(names ...string) []interface{} {
+		refs := []interface{}{}
+		for _, name := range names {
+			refs = append(refs, map[string]interface{}{"name": name})
+		}
+		return refs
+	}
+
+	testCases := map[string]struct {
+		desiredSecrets  []interface{}
+		clusterSecrets  []interface{}
+		expectedSecrets []interface{}
+	}{
+		"cluster secrets retained when desired secrets are not set": {
+			clusterSecrets:  secretRefs("sa-token-abcde", "manual"),
+			expectedSecrets: secretRefs("sa-token-abcde", "manual"),
+		},
+		"generated token secret merged into desired secrets": {
+			desiredSecrets:  secretRefs("registry"),
+			clusterSecrets:  secretRefs("sa-token-abcde", "other"),
+			expectedSecrets: secretRefs("registry", "sa-token-abcde"),
+		},
+		"token secret of another cluster replaced by generated token secret": {
+			desiredSecrets:  secretRefs("sa-token-fghij", "registry"),
+			clusterSecrets:  secretRefs("sa-token-abcde"),
+			expectedSecrets: secretRefs("registry", "sa-token-abcde"),
+		},
+		"token secret of another cluster removed": {
+			desiredSecrets: secretRefs("sa-token-fghij"),
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			desiredObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			desiredObj.SetName("sa")
+			if testCase.desiredSecrets != nil {
+				desiredObj.Object[util.SecretsField] = testCase.desiredSecrets
+			}
+			clusterObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			clusterObj.SetName("sa")
+			if testCase.clusterSecrets != nil {
+				clusterObj.Object[util.SecretsField] = testCase.clusterSecrets
+			}
+
+			if err := RetainClusterFields(util.ServiceAccountKind, desiredObj, clusterObj, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			secrets, _, err := unstructured.NestedSlice(desiredObj.Object, util.SecretsField)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(secrets, testCase.expectedSecrets) {
+				t.Fatalf("Expected secrets %v, got %v", testCase.expectedSecrets, secrets)
+			}
+		})
+	}
+}
+
+func TestRetainPersistentVolumeClaimFields(t *testing.T) {
+	testCases := map[string]struct {
+		desiredSpec  map[string]interface{}
+		clusterSpec  map[string]interface{}
+		expectedSpec map[string]interface{}
+	}{
+		"bound volume and default storage class retained": {
+			desiredSpec: map[string]interface{}{},
+			clusterSpec: map[string]interface{}{
+				"volumeName":       "pvc-1234",
+				"storageClassName": "standard",
+			},
+			expectedSpec: map[string]interface{}{
+				"volumeName":       "pvc-1234",
+				"storageClassName": "standard",
+			},
+		},
+		"desired storage class not replaced": {
+			desiredSpec: map[string]interface{}{
+				"storageClassName": "ssd",
+			},
+			clusterSpec: map[string]interface{}{
+				"volumeName":       "pvc-1234",
+				"storageClassName": "standard",
+			},
+			expectedSpec: map[string]interface{}{
+				"volumeName":       "pvc-1234",
+				"storageClassName": "ssd",
+			},
+		},
+		"unbound claim": {
+			desiredSpec:  map[string]interface{}{},
+			clusterSpec:  map[string]interface{}{},
+			expectedSpec: map[string]interface{}{},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			desiredObj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"spec": testCase.desiredSpec,
+				},
+			}
+			clusterObj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"spec": testCase.clusterSpec,
+				},
+			}
+			fedObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if err := RetainClusterFields(util.PersistentVolumeClaimKind, desiredObj, clusterObj, fedObj); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(desiredObj.Object["spec"], testCase.expectedSpec) {
+				t.Fatalf("Expected spec %v, got %v", testCase.expectedSpec, desiredObj.Object["spec"])
+			}
+		})
+	}
+}
+
+func TestRetainLocalFields(t *testing.T) {
+	desiredObj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"paused":   true,
+				"template": map[string]interface{}{
+					"image": "nginx:1.19",
+				},
+			},
+		},
+	}
+	clusterObj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"image": "nginx:1.18",
+				},
+			},
+		},
+	}
+
+	err := RetainLocalFields(desiredObj, clusterObj, []string{"spec.replicas", "spec.paused"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{
+				"image": "nginx:1.19",
+			},
+		},
+	}
+	if !reflect.DeepEqual(desiredObj.Object, expected) {
+		t.Errorf("Expected %v, got %v", expected, desiredObj.Object)
+	}
+}
@@ -43,28 +43,47 @@ type UnmanagedDispatcher interface {
 
 	Delete(clusterName string)
 	RemoveManagedLabel(clusterName string, clusterObj *unstructured.Unstructured)
+
+	// OrderClusters dispatches the operations for the named clusters
+	// one at a time in the given order, and must be called before any
+	// operation is dispatched. Creates and updates follow the order,
+	// deletions follow the reverse order, and the operations for the
+	// clusters that follow a failed operation are not attempted.
+	OrderClusters(clusterNames []string)
+}
+
+// TargetNameFunc returns the name of a target resource in the named
+// member cluster.
+type TargetNameFunc func(clusterName string) util.QualifiedName
+
+// TargetNames returns a TargetNameFunc for a target resource whose
+// name does not vary between member clusters.
+func TargetNames(targetName util.QualifiedName) TargetNameFunc {
+	return func(clusterName string) util.QualifiedName {
+		return util.QualifiedNameForCluster(clusterName, targetName)
+	}
 }
 
 type unmanagedDispatcherImpl struct {
 	dispatcher *operationDispatcherImpl
 
-	targetGVK  schema.GroupVersionKind
-	targetName util.QualifiedName
+	targetGVK   schema.GroupVersionKind
+	targetNames TargetNameFunc
 
 	recorder dispatchRecorder
 }
 
-func NewUnmanagedDispatcher(clientAccessor clientAccessorFunc, targetGVK schema.GroupVersionKind, targetName util.QualifiedName) UnmanagedDispatcher {
+func NewUnmanagedDispatcher(clientAccessor clientAccessorFunc, targetGVK schema.GroupVersionKind, targetNames TargetNameFunc) UnmanagedDispatcher {
 	dispatcher := newOperationDispatcher(clientAccessor, nil)
-	return newUnmanagedDispatcher(dispatcher, nil, targetGVK, targetName)
+	return newUnmanagedDispatcher(dispatcher, nil, targetGVK, targetNames)
 }
 
-func newUnmanagedDispatcher(dispatcher *operationDispatcherImpl, recorder dispatchRecorder, targetGVK schema.GroupVersionKind, targetName util.QualifiedName) *unmanagedDispatcherImpl {
+func newUnmanagedDispatcher(dispatcher *operationDispatcherImpl, recorder dispatchRecorder, targetGVK schema.GroupVersionKind, targetNames TargetNameFunc) *unmanagedDispatcherImpl {
 	return &unmanagedDispatcherImpl{
-		dispatcher: dispatcher,
-		targetGVK:  targetGVK,
-		targetName: targetName,
-		recorder:   recorder,
+		dispatcher:  dispatcher,
+		targetGVK:   targetGVK,
+		targetNames: targetNames,
+		recorder:    recorder,
 	}
 }
 
@@ -72,12 +91,15 @@ func (d *unmanagedDispatcherImpl) Wait() (bool, error) {
 	return d.dispatcher.Wait()
 }
 
+func (d *unmanagedDispatcherImpl) OrderClusters(clusterNames []string) {
+	d.dispatcher.setClusterOrder(clusterNames)
+}
+
 func (d *unmanagedDispatcherImpl) Delete(clusterName string) {
 	start := time.Now()
-	d.dispatcher.incrementOperationsInitiated()
 	const op = "delete"
 	const opContinuous = "Deleting"
-	go d.dispatcher.clusterOperation(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
+	d.dispatcher.dispatch(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
 		targetName := d.targetNameForCluster(clusterName)
 		if d.recorder == nil {
 			klog.V(2).Infof(eventTemplate, opContinuous, d.targetGVK.Kind, targetName, clusterName)
@@ -87,7 +109,9 @@ func (d *unmanagedDispatcherImpl) Delete(clusterName string) {
 
 		obj := &unstructured.Unstructured{}
 		obj.SetGroupVersionKind(d.targetGVK)
+		deleteStart := time.Now()
 		err := client.Delete(context.Background(), obj, targetName.Namespace, targetName.Name)
+		metrics.DispatchOperation(d.targetGVK.Kind, clusterName, op, err, deleteStart)
 		if apierrors.IsNotFound(err) {
 			err = nil
 		}
@@ -106,10 +130,9 @@ func (d *unmanagedDispatcherImpl) Delete(clusterName string) {
 }
 
 func (d *unmanagedDispatcherImpl) RemoveManagedLabel(clusterName string, clusterObj *unstructured.Unstructured) {
-	d.dispatcher.incrementOperationsInitiated()
 	const op = "remove managed label from"
 	const opContinuous = "Removing managed label from"
-	go d.dispatcher.clusterOperation(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
+	d.dispatcher.dispatch(clusterName, op, func(client generic.Client) util.ReconciliationStatus {
 		if d.recorder == nil {
 			klog.V(2).Infof(eventTemplate, opContinuous, d.targetGVK.Kind, d.targetNameForCluster(clusterName), clusterName)
 		} else {
@@ -121,7 +144,9 @@ func (d *unmanagedDispatcherImpl) RemoveManagedLabel(clusterName string, cluster
 
 		util.RemoveManagedLabel(updateObj)
 
+		start := time.Now()
 		err := client.Update(context.Background(), updateObj)
+		metrics.DispatchOperation(d.targetGVK.Kind, clusterName, "removelabel", err, start)
 		if err != nil {
 			if d.recorder == nil {
 				wrappedErr := d.wrapOperationError(err, clusterName, op)
@@ -140,7 +165,7 @@ func (d *unmanagedDispatcherImpl) wrapOperationError(err error, clusterName, ope
 }
 
 func (d *unmanagedDispatcherImpl) targetNameForCluster(clusterName string) util.QualifiedName {
-	return util.QualifiedNameForCluster(clusterName, d.targetName)
+	return d.targetNames(clusterName)
 }
 
 func wrapOperationError(err error, operation, targetKind, targetName, clusterName string) error {
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+)
+
+// clusterHealth returns the health of the resource in each of the
+// given selected clusters that is ready and in which the resource has
+// been propagated. The health of a resource that cannot be interpreted
+// is not returned.
+func (s *KubeFedSyncController) clusterHealth(logger logr.Logger, fedResource FederatedResource, clusters []*fedv1b1.KubeFedCluster, selectedClusterNames sets.String) map[string]health.Health {
+	clusterHealth := make(map[string]health.Health)
+	for _, cluster := range clusters {
+		clusterName := cluster.Name
+		if !selectedClusterNames.Has(clusterName) || !util.IsClusterReady(&cluster.Status) {
+			continue
+		}
+		key := fedResource.TargetNameForCluster(clusterName).String()
+		rawClusterObj, exists, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
+		if err != nil || !exists {
+			continue
+		}
+		clusterObj := rawClusterObj.(*unstructured.Unstructured)
+		if !util.IsPropagatedFor(clusterObj, clusterName, fedResource.TargetName()) {
+			continue
+		}
+		clusterHealth[clusterName], err = s.healthInterpreter.Interpret(clusterObj)
+		if err != nil {
+			logger.Error(err, "Failed to interpret the health of the resource", "cluster", clusterName)
+			delete(clusterHealth, clusterName)
+		}
+	}
+	return clusterHealth
+}
+
+// collectedClusterHealth returns the given health of the resource in
+// each cluster to be recorded in its status.
+func collectedClusterHealth(clusterHealth map[string]health.Health) []status.GenericClusterHealth {
+	collected := []status.GenericClusterHealth{}
+	for clusterName, interpreted := range clusterHealth {
+		collected = append(collected, status.GenericClusterHealth{Name: clusterName, Health: interpreted})
+	}
+	return collected
+}
+
+// heldClusters returns the selected clusters of the given cluster
+// order in which updates of the resource are held because a preceding
+// selected cluster has yet to be updated to the current generation of
+// the resource, according to the given recorded status, or the
+// resource is not healthy in it. Updates thereby roll out one cluster
+// at a time, each waiting for the resource to become healthy in the
+// cluster before.
+func heldClusters(clusterOrder []string, selectedClusterNames sets.String, recordedStatus status.PropagationStatusMap, clusterHealth map[string]health.Health) sets.String {
+	held := sets.String{}
+	waiting := false
+	for _, clusterName := range clusterOrder {
+		if !selectedClusterNames.Has(clusterName) {
+			continue
+		}
+		if waiting {
+			held.Insert(clusterName)
+			continue
+		}
+		propStatus, recorded := recordedStatus[clusterName]
+		updated := recorded && (propStatus == status.ClusterPropagationOK || propStatus == status.Drifted ||
+			propStatus == status.LocallyManaged || propStatus == status.Pinned)
+		waiting = !updated || clusterHealth[clusterName] != health.Healthy
+	}
+	return held
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+)
+
+func TestHeldClusters(t *testing.T) {
+	clusterOrder := []string{"canary", "east", "west"}
+	testCases := map[string]struct {
+		selectedClusters []string
+		recordedStatus   status.PropagationStatusMap
+		clusterHealth    map[string]health.Health
+		expected         sets.String
+	}{
+		"Current generation not yet recorded": {
+			selectedClusters: clusterOrder,
+			clusterHealth:    map[string]health.Health{"canary": health.Healthy, "east": health.Healthy, "west": health.Healthy},
+			expected:         sets.NewString("east", "west"),
+		},
+		"Progressing in the first cluster": {
+			selectedClusters: clusterOrder,
+			recordedStatus:   status.PropagationStatusMap{"canary": status.ClusterPropagationOK, "east": status.WaitingForHealthy, "west": status.WaitingForHealthy},
+			clusterHealth:    map[string]health.Health{"canary": health.Progressing, "east": health.Healthy, "west": health.Healthy},
+			expected:         sets.NewString("east", "west"),
+		},
+		"Healthy in the first cluster": {
+			selectedClusters: clusterOrder,
+			recordedStatus:   status.PropagationStatusMap{"canary": status.ClusterPropagationOK, "east": status.WaitingForHealthy, "west": status.WaitingForHealthy},
+			clusterHealth:    map[string]health.Health{"canary": health.Healthy, "east": health.Healthy, "west": health.Healthy},
+			expected:         sets.NewString("west"),
+		},
+		"Update of a preceding cluster failed": {
+			selectedClusters: clusterOrder,
+			recordedStatus:   status.PropagationStatusMap{"canary": status.ClusterPropagationOK, "east": status.UpdateFailed, "west": status.WaitingForHealthy},
+			clusterHealth:    map[string]health.Health{"canary": health.Healthy, "east": health.Healthy, "west": health.Healthy},
+			expected:         sets.NewString("west"),
+		},
+		"Unselected clusters are skipped": {
+			selectedClusters: []string{"canary", "west"},
+			recordedStatus:   status.PropagationStatusMap{"canary": status.ClusterPropagationOK, "west": status.WaitingForHealthy},
+			clusterHealth:    map[string]health.Health{"canary": health.Healthy},
+			expected:         sets.NewString(),
+		},
+		"Rolled out to all clusters": {
+			selectedClusters: clusterOrder,
+			recordedStatus:   status.PropagationStatusMap{"canary": status.ClusterPropagationOK, "east": status.ClusterPropagationOK, "west": status.ClusterPropagationOK},
+			clusterHealth:    map[string]health.Health{"canary": health.Healthy, "east": health.Healthy, "west": health.Degraded},
+			expected:         sets.NewString(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := heldClusters(clusterOrder, sets.NewString(tc.selectedClusters...), tc.recordedStatus, tc.clusterHealth)
+			if !result.Equal(tc.expected) {
+				t.Errorf("Expected held clusters %v, got %v", tc.expected.List(), result.List())
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	gosync "sync"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// DefaultRevisionHistoryLimit is the number of revisions kept for each
+// federated resource if the limit is not configured.
+const DefaultRevisionHistoryLimit = 10
+
+// revisionHistory records the template and overrides of federated
+// resources in ControllerRevisions each time they have been
+// propagated to all selected clusters, so that a resource can be
+// rolled back to a previously propagated version.
+type revisionHistory struct {
+	client           appsv1client.ControllerRevisionsGetter
+	kubefedNamespace string
+	limit            int
+
+	lock gosync.Mutex
+	// The name of the latest revision recorded for a federated
+	// resource, keyed by its UID, to avoid retrieving the revisions
+	// of unchanged resources.
+	latest map[types.UID]string
+	// The UID of each federated resource keyed by its qualified name,
+	// to forget the latest revision of deleted resources.
+	uids map[util.QualifiedName]types.UID
+}
+
+func newRevisionHistory(client appsv1client.ControllerRevisionsGetter, kubefedNamespace string, limit int) *revisionHistory {
+	return &revisionHistory{
+		client:           client,
+		kubefedNamespace: kubefedNamespace,
+		limit:            limit,
+		latest:           make(map[types.UID]string),
+		uids:             make(map[util.QualifiedName]types.UID),
+	}
+}
+
+// record records the current template and overrides of the given
+// federated resource as its latest revision. A revision with the same
+// content is renumbered rather than recorded again, and the oldest
+// revisions beyond the limit are removed.
+func (h *revisionHistory) record(fedObject *unstructured.Unstructured) error {
+	newRevision, err := util.NewRevision(fedObject, h.kubefedNamespace, 0)
+	if err != nil {
+		return err
+	}
+	uid := fedObject.GetUID()
+	if h.latestRevision(uid) == newRevision.Name {
+		return nil
+	}
+
+	revisions, err := util.ListRevisions(h.client, fedObject, h.kubefedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list revisions")
+	}
+	revisionClient := h.client.ControllerRevisions(newRevision.Namespace)
+
+	var nextRevision int64 = 1
+	if len(revisions) > 0 {
+		nextRevision = revisions[len(revisions)-1].Revision + 1
+	}
+	existing := -1
+	for i := range revisions {
+		if revisions[i].Name == newRevision.Name {
+			existing = i
+			break
+		}
+	}
+	switch {
+	case existing == -1:
+		newRevision.Revision = nextRevision
+		created, err := revisionClient.Create(newRevision)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create revision %q", newRevision.Name)
+		}
+		revisions = append(revisions, *created)
+	case existing != len(revisions)-1:
+		// The resource was rolled back or changed back to the
+		// content of an earlier revision.
+		revision := revisions[existing].DeepCopy()
+		revision.Revision = nextRevision
+		updated, err := revisionClient.Update(revision)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update revision %q", revision.Name)
+		}
+		revisions = append(append(revisions[:existing], revisions[existing+1:]...), *updated)
+	}
+
+	for i := 0; i < len(revisions)-h.limit; i++ {
+		err := revisionClient.Delete(revisions[i].Name, &metav1.DeleteOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete revision %q", revisions[i].Name)
+		}
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.latest[uid] = newRevision.Name
+	h.uids[util.NewQualifiedName(fedObject)] = uid
+	return nil
+}
+
+func (h *revisionHistory) latestRevision(uid types.UID) string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.latest[uid]
+}
+
+// forget removes the named federated resource from the history once
+// it has been deleted. Its revisions are garbage collected.
+func (h *revisionHistory) forget(qualifiedName util.QualifiedName) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if uid, ok := h.uids[qualifiedName]; ok {
+		delete(h.latest, uid)
+		delete(h.uids, qualifiedName)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestRevisionHistory(t *testing.T) {
+	client := fake.NewSimpleClientset().AppsV1()
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.io/v1beta1")
+	fedObject.SetKind("FederatedConfigMap")
+	fedObject.SetNamespace("ns")
+	fedObject.SetName("foo")
+	fedObject.SetUID("uid")
+
+	setTemplate := func(value string) {
+		err := unstructured.SetNestedField(fedObject.Object, value, "spec", "template", "data", "key")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	history := newRevisionHistory(client, "kube-federation-system", 3)
+	for _, value := range []string{"a", "b", "b", "a", "c", "d"} {
+		setTemplate(value)
+		if err := history.record(fedObject); err != nil {
+			t.Fatalf("Unexpected error recording revision %q: %v", value, err)
+		}
+	}
+
+	revisions, err := util.ListRevisions(client, fedObject, "kube-federation-system")
+	if err != nil {
+		t.Fatalf("Unexpected error listing revisions: %v", err)
+	}
+	// The content of the first revision was recorded again as
+	// revision 3, and revision 2 was removed beyond the limit.
+	expectedValues := []string{"a", "c", "d"}
+	expectedNumbers := []int64{3, 4, 5}
+	values := []string{}
+	numbers := []int64{}
+	for i := range revisions {
+		restored := fedObject.DeepCopy()
+		if err := util.RestoreRevision(restored, &revisions[i]); err != nil {
+			t.Fatalf("Unexpected error restoring revision: %v", err)
+		}
+		value, _, _ := unstructured.NestedString(restored.Object, "spec", "template", "data", "key")
+		values = append(values, value)
+		numbers = append(numbers, revisions[i].Revision)
+	}
+	if !reflect.DeepEqual(values, expectedValues) {
+		t.Errorf("Expected revisions of %v, got %v", expectedValues, values)
+	}
+	if !reflect.DeepEqual(numbers, expectedNumbers) {
+		t.Errorf("Expected revision numbers %v, got %v", expectedNumbers, numbers)
+	}
+
+	history.forget(util.NewQualifiedName(fedObject))
+	if name := history.latestRevision(fedObject.GetUID()); name != "" {
+		t.Errorf("Expected the latest revision to be forgotten, got %q", name)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// jobCompletionRecheckInterval is how often the managed Jobs of a
+// FederatedJob deleted in the foreground are checked while one of
+// them is still running. Changes to the Jobs also trigger a check.
+const jobCompletionRecheckInterval = 30 * time.Second
+
+func isJob(gvk schema.GroupVersionKind) bool {
+	return gvk.Group == batchv1.GroupName && gvk.Kind == util.JobKind
+}
+
+// awaitJobCompletion records the completion of the managed Jobs of the
+// given deleted FederatedJob in its status and returns how long to
+// wait before checking again, or zero once every Job has finished and
+// its ttlSecondsAfterFinished has elapsed.
+func (s *KubeFedSyncController) awaitJobCompletion(fedResource FederatedResource) (time.Duration, error) {
+	clusters, err := s.informer.GetClusters()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get a list of clusters")
+	}
+
+	targetName := fedResource.TargetName()
+	clusterObjs := make(map[string]*unstructured.Unstructured)
+	for _, cluster := range clusters {
+		if !util.IsClusterReady(&cluster.Status) {
+			continue
+		}
+		key := fedResource.TargetNameForCluster(cluster.Name).String()
+		rawClusterObj, _, err := s.informer.GetTargetStore().GetByKey(cluster.Name, key)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to retrieve Job %q for cluster %q", key, cluster.Name)
+		}
+		if rawClusterObj == nil {
+			continue
+		}
+		clusterObj := rawClusterObj.(*unstructured.Unstructured)
+		if !util.IsPropagatedFor(clusterObj, cluster.Name, targetName) || clusterObj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		clusterObjs[cluster.Name] = clusterObj
+	}
+
+	obj := fedResource.Object()
+	recorded, err := status.GetJobCompletion(obj)
+	if err != nil {
+		return 0, err
+	}
+	completion, wait, err := jobCompletion(recorded, clusterObjs, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	updateRequired, err := status.SetJobCompletion(obj, completion)
+	if err != nil || !updateRequired {
+		return wait, err
+	}
+	if wait == 0 {
+		fedResource.RecordEvent("JobsFinished", "Managed Jobs finished with %d succeeded and %d failed pods", completion.Succeeded, completion.Failed)
+	}
+	return wait, s.hostClusterClient.UpdateStatus(context.TODO(), obj)
+}
+
+// jobCompletion aggregates the completion of the given Jobs, keyed by
+// cluster name, with the completion previously recorded for clusters
+// whose Job has since been removed. How long to wait before checking
+// again is also returned, or zero if the Jobs can be removed.
+func jobCompletion(recorded *status.JobCompletion, clusterObjs map[string]*unstructured.Unstructured, now time.Time) (*status.JobCompletion, time.Duration, error) {
+	clusterCompletions := make(map[string]status.JobClusterCompletion)
+	if recorded != nil {
+		for _, clusterCompletion := range recorded.Clusters {
+			clusterCompletions[clusterCompletion.Name] = clusterCompletion
+		}
+	}
+
+	var wait time.Duration
+	for clusterName, clusterObj := range clusterObjs {
+		job := &batchv1.Job{}
+		if err := util.UnstructuredToInterface(clusterObj, job); err != nil {
+			return nil, 0, errors.Wrapf(err, "failed to convert Job for cluster %q", clusterName)
+		}
+		finishedAt, finished := jobFinishedAt(job)
+		clusterCompletions[clusterName] = status.JobClusterCompletion{
+			Name:      clusterName,
+			Succeeded: int64(job.Status.Succeeded),
+			Failed:    int64(job.Status.Failed),
+			Finished:  finished,
+		}
+
+		remaining := jobCompletionRecheckInterval
+		if finished {
+			remaining = 0
+			if ttl := job.Spec.TTLSecondsAfterFinished; ttl != nil {
+				remaining = finishedAt.Add(time.Duration(*ttl) * time.Second).Sub(now)
+			}
+		}
+		if remaining > 0 && (wait == 0 || remaining < wait) {
+			wait = remaining
+		}
+	}
+
+	completion := &status.JobCompletion{}
+	for _, clusterCompletion := range clusterCompletions {
+		completion.Succeeded += clusterCompletion.Succeeded
+		completion.Failed += clusterCompletion.Failed
+		completion.Clusters = append(completion.Clusters, clusterCompletion)
+	}
+	sort.Slice(completion.Clusters, func(i, j int) bool {
+		return completion.Clusters[i].Name < completion.Clusters[j].Name
+	})
+	return completion, wait, nil
+}
+
+// jobFinishedAt returns when the given Job finished and whether it has
+// finished.
+func jobFinishedAt(job *batchv1.Job) (time.Time, bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != apiv1.ConditionTrue {
+			continue
+		}
+		if condition.Type != batchv1.JobComplete && condition.Type != batchv1.JobFailed {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete && job.Status.CompletionTime != nil {
+			return job.Status.CompletionTime.Time, true
+		}
+		return condition.LastTransitionTime.Time, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+)
+
+func TestJobCompletion(t *testing.T) {
+	// Times are serialized with a precision of seconds.
+	now := time.Now().Truncate(time.Second)
+	finishedAt := now.Add(-time.Minute)
+	ttl := int32(300)
+
+	running := newTestJob(1, 0, nil, nil)
+	completed := newTestJob(3, 1, &batchv1.JobCondition{Type: batchv1.JobComplete}, nil)
+	failed := newTestJob(0, 2, &batchv1.JobCondition{Type: batchv1.JobFailed}, nil)
+	completedWithTTL := newTestJob(2, 0, &batchv1.JobCondition{Type: batchv1.JobComplete}, &ttl)
+	for _, job := range []*batchv1.Job{completed, failed, completedWithTTL} {
+		job.Status.Conditions[0].LastTransitionTime = metav1.NewTime(finishedAt)
+	}
+
+	testCases := map[string]struct {
+		recorded     *status.JobCompletion
+		jobs         map[string]*batchv1.Job
+		expected     *status.JobCompletion
+		expectedWait time.Duration
+	}{
+		"No Jobs": {
+			expected: &status.JobCompletion{},
+		},
+		"Running Job": {
+			jobs: map[string]*batchv1.Job{"cluster1": running, "cluster2": completed},
+			expected: &status.JobCompletion{
+				Succeeded: 4,
+				Failed:    1,
+				Clusters: []status.JobClusterCompletion{
+					{Name: "cluster1", Succeeded: 1},
+					{Name: "cluster2", Succeeded: 3, Failed: 1, Finished: true},
+				},
+			},
+			expectedWait: jobCompletionRecheckInterval,
+		},
+		"Finished Jobs": {
+			jobs: map[string]*batchv1.Job{"cluster1": completed, "cluster2": failed},
+			expected: &status.JobCompletion{
+				Succeeded: 3,
+				Failed:    3,
+				Clusters: []status.JobClusterCompletion{
+					{Name: "cluster1", Succeeded: 3, Failed: 1, Finished: true},
+					{Name: "cluster2", Failed: 2, Finished: true},
+				},
+			},
+		},
+		"Finished Job within its ttl": {
+			jobs: map[string]*batchv1.Job{"cluster1": completedWithTTL},
+			expected: &status.JobCompletion{
+				Succeeded: 2,
+				Clusters: []status.JobClusterCompletion{
+					{Name: "cluster1", Succeeded: 2, Finished: true},
+				},
+			},
+			expectedWait: 4 * time.Minute,
+		},
+		"Completion of a removed Job is retained": {
+			recorded: &status.JobCompletion{
+				Succeeded: 5,
+				Clusters: []status.JobClusterCompletion{
+					{Name: "cluster1", Succeeded: 5, Finished: true},
+				},
+			},
+			jobs: map[string]*batchv1.Job{"cluster2": failed},
+			expected: &status.JobCompletion{
+				Succeeded: 5,
+				Failed:    2,
+				Clusters: []status.JobClusterCompletion{
+					{Name: "cluster1", Succeeded: 5, Finished: true},
+					{Name: "cluster2", Failed: 2, Finished: true},
+				},
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			clusterObjs := make(map[string]*unstructured.Unstructured)
+			for clusterName, job := range tc.jobs {
+				content, err := pkgruntime.DefaultUnstructuredConverter.ToUnstructured(job)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				clusterObjs[clusterName] = &unstructured.Unstructured{Object: content}
+			}
+			completion, wait, err := jobCompletion(tc.recorded, clusterObjs, now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(completion, tc.expected) {
+				t.Errorf("Expected completion %v, got %v", tc.expected, completion)
+			}
+			if wait != tc.expectedWait {
+				t.Errorf("Expected wait %v, got %v", tc.expectedWait, wait)
+			}
+		})
+	}
+}
+
+func newTestJob(succeeded, failed int32, condition *batchv1.JobCondition, ttl *int32) *batchv1.Job {
+	job := &batchv1.Job{
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: ttl,
+		},
+		Status: batchv1.JobStatus{
+			Succeeded: succeeded,
+			Failed:    failed,
+		},
+	}
+	if condition != nil {
+		condition.Status = apiv1.ConditionTrue
+		job.Status.Conditions = []batchv1.JobCondition{*condition}
+	}
+	return job
+}
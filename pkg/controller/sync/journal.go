@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	gosync "sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	journalConfigMapPrefix = "kubefed-sync-journal-"
+	journalDataKey         = "pending"
+
+	// The journal is written at most this often to limit the load
+	// on the API of the host cluster.
+	journalFlushPeriod = 10 * time.Second
+
+	// The data of a ConfigMap is limited to 1 MiB. The oldest entries
+	// are trimmed from a journal that would exceed the limit and are
+	// rediscovered by the initial reconciliation of all resources.
+	maxJournalSize = 1024*1024 - len(journalDataKey)
+)
+
+// dispatchJournal records the federated resources for which operations
+// in member clusters are pending or have failed. The journal is
+// persisted in a ConfigMap in the KubeFed namespace so that a sync
+// controller can replay the recorded resources first after a restart
+// rather than waiting for them to be rediscovered.
+type dispatchJournal struct {
+	client    corev1client.ConfigMapsGetter
+	namespace string
+	name      string
+
+	lock gosync.Mutex
+	// The names of the clusters with pending or failed operations
+	// keyed by the qualified name of the federated resource.
+	entries map[string][]string
+	// The order in which the entries were recorded, used to trim the
+	// oldest entries first.
+	recorded map[string]int64
+	sequence int64
+	dirty    bool
+}
+
+func newDispatchJournal(client corev1client.ConfigMapsGetter, namespace, typeConfigName string) *dispatchJournal {
+	return &dispatchJournal{
+		client:    client,
+		namespace: namespace,
+		name:      journalConfigMapPrefix + typeConfigName,
+		entries:   make(map[string][]string),
+		recorded:  make(map[string]int64),
+	}
+}
+
+// load reads the journal persisted by a previous instance of the
+// controller.
+func (j *dispatchJournal) load() error {
+	configMap, err := j.client.ConfigMaps(j.namespace).Get(j.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	entries := make(map[string][]string)
+	if data, ok := configMap.Data[journalDataKey]; ok {
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return errors.Wrapf(err, "failed to decode sync journal %q", j.name)
+		}
+	}
+
+	// The order in which the persisted entries were recorded is not
+	// retained.
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.entries = entries
+	j.recorded = make(map[string]int64, len(keys))
+	for _, key := range keys {
+		j.recorded[key] = j.sequence
+		j.sequence++
+	}
+	return nil
+}
+
+// pending returns the qualified names of the resources with pending or
+// failed operations.
+func (j *dispatchJournal) pending() []util.QualifiedName {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	keys := make([]string, 0, len(j.entries))
+	for key := range j.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	names := make([]util.QualifiedName, 0, len(keys))
+	for _, key := range keys {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			klog.Warningf("Ignoring invalid key %q in sync journal %q: %v", key, j.name, err)
+			continue
+		}
+		names = append(names, util.QualifiedName{Namespace: namespace, Name: name})
+	}
+	return names
+}
+
+// record updates the journal with the clusters for which the given
+// propagation status indicates a pending or failed operation.
+func (j *dispatchJournal) record(qualifiedName util.QualifiedName, statusMap status.PropagationStatusMap) {
+	clusterNames := []string{}
+	for clusterName, propStatus := range statusMap {
+		if propStatus.IsFailure() || propStatus.IsPending() {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+	sort.Strings(clusterNames)
+
+	key := qualifiedName.String()
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	existing, ok := j.entries[key]
+	switch {
+	case len(clusterNames) == 0:
+		if !ok {
+			return
+		}
+		delete(j.entries, key)
+		delete(j.recorded, key)
+	case ok && reflect.DeepEqual(existing, clusterNames):
+		return
+	case ok:
+		j.entries[key] = clusterNames
+	default:
+		j.entries[key] = clusterNames
+		j.recorded[key] = j.sequence
+		j.sequence++
+	}
+	j.dirty = true
+}
+
+// forget removes the given resource from the journal.
+func (j *dispatchJournal) forget(qualifiedName util.QualifiedName) {
+	j.record(qualifiedName, nil)
+}
+
+// flush persists the journal if it has changed since it was last
+// persisted.
+func (j *dispatchJournal) flush() error {
+	j.lock.Lock()
+	if !j.dirty {
+		j.lock.Unlock()
+		return nil
+	}
+	j.trim()
+	data, err := json.Marshal(j.entries)
+	j.dirty = false
+	j.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = j.write(string(data))
+	if err != nil {
+		// Ensure the write is retried on the next flush.
+		j.lock.Lock()
+		j.dirty = true
+		j.lock.Unlock()
+	}
+	return err
+}
+
+// trim removes the oldest entries of the journal until its serialized
+// size fits in a ConfigMap. Must be called with the lock held.
+func (j *dispatchJournal) trim() {
+	// The size of the serialized journal is at most the sum of the
+	// sizes of its entries and separators and the enclosing braces.
+	size := 1
+	for key, clusterNames := range j.entries {
+		size += journalEntrySize(key, clusterNames)
+	}
+	if size <= maxJournalSize {
+		return
+	}
+
+	keys := make([]string, 0, len(j.entries))
+	for key := range j.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return j.recorded[keys[a]] < j.recorded[keys[b]]
+	})
+	trimmed := 0
+	for _, key := range keys {
+		if size <= maxJournalSize {
+			break
+		}
+		size -= journalEntrySize(key, j.entries[key])
+		delete(j.entries, key)
+		delete(j.recorded, key)
+		trimmed++
+	}
+	klog.V(2).Infof("Trimmed the %d oldest entries from sync journal %q to fit in a ConfigMap", trimmed, j.name)
+}
+
+// journalEntrySize returns the size of the given journal entry when
+// serialized, including its separator.
+func journalEntrySize(key string, clusterNames []string) int {
+	keyData, _ := json.Marshal(key)
+	valueData, _ := json.Marshal(clusterNames)
+	return len(keyData) + len(valueData) + 2
+}
+
+func (j *dispatchJournal) write(data string) error {
+	configMaps := j.client.ConfigMaps(j.namespace)
+	configMap, err := configMaps.Get(j.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: j.namespace,
+				Name:      j.name,
+			},
+			Data: map[string]string{
+				journalDataKey: data,
+			},
+		}
+		_, err = configMaps.Create(configMap)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[journalDataKey] = data
+	_, err = configMaps.Update(configMap)
+	return err
+}
+
+// run periodically persists the journal until the stop channel is
+// closed.
+func (j *dispatchJournal) run(stopChan <-chan struct{}) {
+	go wait.Until(func() {
+		if err := j.flush(); err != nil {
+			klog.Warningf("Failed to persist sync journal %q: %v", j.name, err)
+		}
+	}, journalFlushPeriod, stopChan)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestDispatchJournal(t *testing.T) {
+	client := fake.NewSimpleClientset().CoreV1()
+	namespace := "kube-federation-system"
+	typeConfigName := "deployments.apps"
+
+	failed := util.QualifiedName{Namespace: "ns", Name: "failed"}
+	pending := util.QualifiedName{Namespace: "ns", Name: "pending"}
+	propagated := util.QualifiedName{Namespace: "ns", Name: "propagated"}
+
+	journal := newDispatchJournal(client, namespace, typeConfigName)
+	journal.record(failed, status.PropagationStatusMap{
+		"cluster1": status.ClusterPropagationOK,
+		"cluster2": status.CreationFailed,
+	})
+	journal.record(pending, status.PropagationStatusMap{
+		"cluster1": status.PendingDelivery,
+	})
+	journal.record(propagated, status.PropagationStatusMap{
+		"cluster1": status.ClusterPropagationOK,
+		"cluster2": status.WaitingForRemoval,
+	})
+	if err := journal.flush(); err != nil {
+		t.Fatalf("Unexpected error flushing journal: %v", err)
+	}
+
+	// A new journal, as created after a restart, should load the
+	// entries persisted by the previous one.
+	restored := newDispatchJournal(client, namespace, typeConfigName)
+	if err := restored.load(); err != nil {
+		t.Fatalf("Unexpected error loading journal: %v", err)
+	}
+	expected := []util.QualifiedName{failed, pending}
+	if names := restored.pending(); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected pending %v, got %v", expected, names)
+	}
+	if clusterNames := restored.entries[failed.String()]; !reflect.DeepEqual(clusterNames, []string{"cluster2"}) {
+		t.Fatalf("Expected clusters [cluster2] for %q, got %v", failed, clusterNames)
+	}
+
+	// Resources are removed once propagated or deleted.
+	restored.record(failed, status.PropagationStatusMap{
+		"cluster1": status.ClusterPropagationOK,
+		"cluster2": status.ClusterPropagationOK,
+	})
+	restored.forget(pending)
+	if err := restored.flush(); err != nil {
+		t.Fatalf("Unexpected error flushing journal: %v", err)
+	}
+
+	reloaded := newDispatchJournal(client, namespace, typeConfigName)
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Unexpected error loading journal: %v", err)
+	}
+	if names := reloaded.pending(); len(names) != 0 {
+		t.Fatalf("Expected no pending resources, got %v", names)
+	}
+}
+
+func TestDispatchJournalLoadMissing(t *testing.T) {
+	journal := newDispatchJournal(fake.NewSimpleClientset().CoreV1(), "kube-federation-system", "deployments.apps")
+	if err := journal.load(); err != nil {
+		t.Fatalf("Unexpected error loading missing journal: %v", err)
+	}
+	if names := journal.pending(); len(names) != 0 {
+		t.Fatalf("Expected no pending resources, got %v", names)
+	}
+}
+
+func TestDispatchJournalTrimsOldestEntries(t *testing.T) {
+	client := fake.NewSimpleClientset().CoreV1()
+	journal := newDispatchJournal(client, "kube-federation-system", "deployments.apps")
+
+	// Resources with long names failing in many clusters exceed the
+	// size limit of a ConfigMap well before many thousands of them
+	// are recorded.
+	statusMap := status.PropagationStatusMap{}
+	for i := 0; i < 20; i++ {
+		statusMap[fmt.Sprintf("%s-%d", strings.Repeat("c", 60), i)] = status.CreationFailed
+	}
+	qualifiedNames := []util.QualifiedName{}
+	for i := 0; i < 1000; i++ {
+		qualifiedName := util.QualifiedName{
+			Namespace: strings.Repeat("n", 63),
+			Name:      fmt.Sprintf("%s-%d", strings.Repeat("r", 240), i),
+		}
+		qualifiedNames = append(qualifiedNames, qualifiedName)
+		journal.record(qualifiedName, statusMap)
+	}
+	if err := journal.flush(); err != nil {
+		t.Fatalf("Unexpected error flushing journal: %v", err)
+	}
+
+	configMap, err := client.ConfigMaps("kube-federation-system").Get(journal.name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error retrieving journal: %v", err)
+	}
+	if size := len(configMap.Data[journalDataKey]); size > maxJournalSize {
+		t.Fatalf("Expected journal of at most %d bytes, got %d", maxJournalSize, size)
+	}
+	if _, ok := journal.entries[qualifiedNames[0].String()]; ok {
+		t.Errorf("Expected the oldest entry %q to be trimmed", qualifiedNames[0])
+	}
+	if _, ok := journal.entries[qualifiedNames[len(qualifiedNames)-1].String()]; !ok {
+		t.Errorf("Expected the newest entry %q to be retained", qualifiedNames[len(qualifiedNames)-1])
+	}
+}
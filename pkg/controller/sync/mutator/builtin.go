@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+type imageRewriteMutator struct {
+	config fedv1b1.ImageRewriteMutator
+}
+
+func (m *imageRewriteMutator) Name() string {
+	return "imageRewrite"
+}
+
+func (m *imageRewriteMutator) Mutate(obj *unstructured.Unstructured, _ *fedv1b1.KubeFedCluster) error {
+	return visitContainers(obj, func(container map[string]interface{}) error {
+		image, ok := container["image"].(string)
+		if ok && strings.HasPrefix(image, m.config.From) {
+			container["image"] = m.config.To + strings.TrimPrefix(image, m.config.From)
+		}
+		return nil
+	})
+}
+
+type labelInjectionMutator struct {
+	config fedv1b1.LabelInjectionMutator
+}
+
+func (m *labelInjectionMutator) Name() string {
+	return "labelInjection"
+}
+
+func (m *labelInjectionMutator) Mutate(obj *unstructured.Unstructured, _ *fedv1b1.KubeFedCluster) error {
+	if err := mergeStringMap(obj.Object, m.config.Labels, "metadata", "labels"); err != nil {
+		return err
+	}
+	if !m.config.PodTemplate {
+		return nil
+	}
+	templateFields := podTemplateFields(obj)
+	if templateFields == nil {
+		return nil
+	}
+	return mergeStringMap(obj.Object, m.config.Labels, append(templateFields, "metadata", "labels")...)
+}
+
+type nodeSelectorInjectionMutator struct {
+	config fedv1b1.NodeSelectorInjectionMutator
+}
+
+func (m *nodeSelectorInjectionMutator) Name() string {
+	return "nodeSelectorInjection"
+}
+
+func (m *nodeSelectorInjectionMutator) Mutate(obj *unstructured.Unstructured, _ *fedv1b1.KubeFedCluster) error {
+	specFields := podSpecFields(obj)
+	if specFields == nil {
+		return nil
+	}
+	return mergeStringMap(obj.Object, m.config.NodeSelector, append(specFields, "nodeSelector")...)
+}
+
+type resourceRequestScalingMutator struct {
+	config fedv1b1.ResourceRequestScalingMutator
+}
+
+func (m *resourceRequestScalingMutator) Name() string {
+	return "resourceRequestScaling"
+}
+
+func (m *resourceRequestScalingMutator) Mutate(obj *unstructured.Unstructured, _ *fedv1b1.KubeFedCluster) error {
+	return visitContainers(obj, func(container map[string]interface{}) error {
+		requests, ok, err := unstructured.NestedMap(container, "resources", "requests")
+		if err != nil || !ok {
+			return err
+		}
+		limits, _, err := unstructured.NestedMap(container, "resources", "limits")
+		if err != nil {
+			return err
+		}
+		for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			key := string(resourceName)
+			request, ok := requests[key]
+			if !ok {
+				continue
+			}
+			quantity, err := parseQuantity(request)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s request", key)
+			}
+			scaled := scaleQuantity(quantity, resourceName, m.config.Percent)
+			if limit, ok := limits[key]; ok {
+				limitQuantity, err := parseQuantity(limit)
+				if err != nil {
+					return errors.Wrapf(err, "invalid %s limit", key)
+				}
+				if scaled.Cmp(limitQuantity) > 0 {
+					scaled = limitQuantity
+				}
+			}
+			requests[key] = scaled.String()
+		}
+		return unstructured.SetNestedMap(container, requests, "resources", "requests")
+	})
+}
+
+type subjectRewriteMutator struct {
+	config fedv1b1.SubjectRewriteMutator
+}
+
+func (m *subjectRewriteMutator) Name() string {
+	return "subjectRewrite"
+}
+
+func (m *subjectRewriteMutator) Mutate(obj *unstructured.Unstructured, _ *fedv1b1.KubeFedCluster) error {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != rbacv1.GroupName || (gvk.Kind != "RoleBinding" && gvk.Kind != "ClusterRoleBinding") {
+		return nil
+	}
+	subjects, ok, err := unstructured.NestedSlice(obj.Object, "subjects")
+	if err != nil || !ok {
+		return err
+	}
+	for i, rawSubject := range subjects {
+		subject, ok := rawSubject.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("subjects[%d] is not an object", i)
+		}
+		kind, _ := subject["kind"].(string)
+		name, _ := subject["name"].(string)
+		for _, mapping := range m.config.Mappings {
+			if mapping.Kind == kind && mapping.From == name {
+				subject["name"] = mapping.To
+				break
+			}
+		}
+	}
+	return unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+}
+
+func parseQuantity(value interface{}) (resource.Quantity, error) {
+	return resource.ParseQuantity(fmt.Sprintf("%v", value))
+}
+
+// scaleQuantity scales cpu with millicore precision and other
+// resources with unit precision.
+func scaleQuantity(quantity resource.Quantity, resourceName corev1.ResourceName, percent int32) resource.Quantity {
+	if resourceName == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(quantity.MilliValue()*int64(percent)/100, quantity.Format)
+	}
+	return *resource.NewQuantity(quantity.Value()*int64(percent)/100, quantity.Format)
+}
+
+// podTemplateFields returns the path of the pod template of the given
+// object, or nil if the object does not have a pod template.
+func podTemplateFields(obj *unstructured.Unstructured) []string {
+	var fields []string
+	switch obj.GetKind() {
+	case "Pod":
+		return nil
+	case "CronJob":
+		fields = []string{"spec", "jobTemplate", "spec", "template"}
+	default:
+		fields = []string{"spec", "template"}
+	}
+	if _, ok, _ := unstructured.NestedMap(obj.Object, fields...); !ok {
+		return nil
+	}
+	return fields
+}
+
+// podSpecFields returns the path of the pod spec of the given object,
+// or nil if the object does not contain a pod spec.
+func podSpecFields(obj *unstructured.Unstructured) []string {
+	if obj.GetKind() == "Pod" {
+		return []string{"spec"}
+	}
+	templateFields := podTemplateFields(obj)
+	if templateFields == nil {
+		return nil
+	}
+	return append(templateFields, "spec")
+}
+
+// visitContainers invokes the given function for each container and
+// init container of the pod spec of the given object.
+func visitContainers(obj *unstructured.Unstructured, visitFunc func(container map[string]interface{}) error) error {
+	specFields := podSpecFields(obj)
+	if specFields == nil {
+		return nil
+	}
+	for _, containersField := range []string{"initContainers", "containers"} {
+		fields := append(append([]string{}, specFields...), containersField)
+		containers, ok, err := unstructured.NestedSlice(obj.Object, fields...)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		for i, rawContainer := range containers {
+			container, ok := rawContainer.(map[string]interface{})
+			if !ok {
+				return errors.Errorf("%s[%d] is not an object", strings.Join(fields, "."), i)
+			}
+			if err := visitFunc(container); err != nil {
+				return errors.Wrapf(err, "%s[%d]", strings.Join(fields, "."), i)
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, containers, fields...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeStringMap adds the given entries to the string map at the given
+// path, overwriting existing entries with the same key.
+func mergeStringMap(obj map[string]interface{}, entries map[string]string, fields ...string) error {
+	existing, _, err := unstructured.NestedStringMap(obj, fields...)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		existing = make(map[string]string, len(entries))
+	}
+	for key, value := range entries {
+		existing[key] = value
+	}
+	return unstructured.SetNestedStringMap(obj, existing, fields...)
+}
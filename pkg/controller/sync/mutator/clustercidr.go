@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutator
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// ClustersFunc returns the member clusters registered with KubeFed.
+type ClustersFunc func() ([]*fedv1b1.KubeFedCluster, error)
+
+// clusterCIDRMutator renders the templates in the ipBlock rules of a
+// network policy, allowing the address ranges recorded on
+// KubeFedClusters to be referenced symbolically, e.g.
+//
+//	cidr: '{{ .Cluster "prod-eu" .PodCIDR }}'
+type clusterCIDRMutator struct {
+	getClusters ClustersFunc
+}
+
+// NewClusterCIDRMutator returns a mutator that substitutes the address
+// ranges of member clusters into the ipBlock rules of network policies.
+func NewClusterCIDRMutator(getClusters ClustersFunc) DispatchMutator {
+	return &clusterCIDRMutator{getClusters: getClusters}
+}
+
+func (m *clusterCIDRMutator) Name() string {
+	return "clusterCIDR"
+}
+
+// The fields of a network policy rule containing the peers with
+// ipBlocks, keyed by the field containing the rules.
+var networkPolicyPeerFields = map[string]string{
+	"ingress": "from",
+	"egress":  "to",
+}
+
+func (m *clusterCIDRMutator) Mutate(obj *unstructured.Unstructured, cluster *fedv1b1.KubeFedCluster) error {
+	data := &cidrTemplateData{
+		Name:        cluster.Name,
+		PodCIDR:     podCIDRField,
+		ServiceCIDR: serviceCIDRField,
+		getClusters: m.getClusters,
+	}
+	for rulesField, peersField := range networkPolicyPeerFields {
+		rules, ok, err := unstructured.NestedSlice(obj.Object, "spec", rulesField)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		for _, rawRule := range rules {
+			rule, ok := rawRule.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			peers, ok := rule[peersField].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, rawPeer := range peers {
+				peer, ok := rawPeer.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				ipBlock, ok := peer["ipBlock"].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if err := renderIPBlock(ipBlock, data); err != nil {
+					return errors.Wrapf(err, "spec.%s", rulesField)
+				}
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, rules, "spec", rulesField); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderIPBlock(ipBlock map[string]interface{}, data *cidrTemplateData) error {
+	if cidr, ok := ipBlock["cidr"].(string); ok {
+		rendered, err := renderCIDR(cidr, data)
+		if err != nil {
+			return err
+		}
+		ipBlock["cidr"] = rendered
+	}
+	except, ok := ipBlock["except"].([]interface{})
+	if !ok {
+		return nil
+	}
+	for i, rawCIDR := range except {
+		cidr, ok := rawCIDR.(string)
+		if !ok {
+			continue
+		}
+		rendered, err := renderCIDR(cidr, data)
+		if err != nil {
+			return err
+		}
+		except[i] = rendered
+	}
+	return nil
+}
+
+func renderCIDR(cidr string, data *cidrTemplateData) (string, error) {
+	if !strings.Contains(cidr, "{{") {
+		return cidr, nil
+	}
+	tmpl, err := template.New("cidr").Option("missingkey=error").Parse(cidr)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid cidr template %q", cidr)
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", errors.Wrapf(err, "failed to render cidr template %q", cidr)
+	}
+	return rendered.String(), nil
+}
+
+type cidrField string
+
+const (
+	podCIDRField     cidrField = "PodCIDR"
+	serviceCIDRField cidrField = "ServiceCIDR"
+)
+
+// cidrTemplateData is the data available to the templates of ipBlock
+// rules.
+type cidrTemplateData struct {
+	// The name of the cluster the policy is propagated to.
+	Name string
+	// Select the CIDR to return from Cluster.
+	PodCIDR     cidrField
+	ServiceCIDR cidrField
+
+	getClusters ClustersFunc
+	clusters    map[string]*fedv1b1.KubeFedCluster
+}
+
+// Cluster returns the given CIDR of the named cluster.
+func (d *cidrTemplateData) Cluster(name string, field cidrField) (string, error) {
+	if d.clusters == nil {
+		clusters, err := d.getClusters()
+		if err != nil {
+			return "", err
+		}
+		d.clusters = make(map[string]*fedv1b1.KubeFedCluster, len(clusters))
+		for _, cluster := range clusters {
+			d.clusters[cluster.Name] = cluster
+		}
+	}
+
+	cluster, ok := d.clusters[name]
+	if !ok {
+		return "", errors.Errorf("cluster %q not found", name)
+	}
+	var cidr string
+	if network := cluster.Spec.Network; network != nil {
+		switch field {
+		case podCIDRField:
+			cidr = network.PodCIDR
+		case serviceCIDRField:
+			cidr = network.ServiceCIDR
+		default:
+			return "", errors.Errorf("unknown cidr %q", field)
+		}
+	}
+	if cidr == "" {
+		return "", errors.Errorf("%s is not recorded for cluster %q", field, name)
+	}
+	return cidr, nil
+}
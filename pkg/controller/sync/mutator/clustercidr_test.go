@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutator
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestClusterCIDRMutator(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		networkCluster("prod-eu", "10.1.0.0/16", "10.96.0.0/12"),
+		networkCluster("prod-us", "10.2.0.0/16", ""),
+	}
+	m := NewClusterCIDRMutator(func() ([]*fedv1b1.KubeFedCluster, error) {
+		return clusters, nil
+	})
+
+	testCases := map[string]struct {
+		cidr        string
+		except      []interface{}
+		expected    string
+		expectedExc []interface{}
+		expectErr   bool
+	}{
+		"literal cidr is unchanged": {
+			cidr:     "192.168.0.0/16",
+			expected: "192.168.0.0/16",
+		},
+		"pod cidr of another cluster": {
+			cidr:     `{{ .Cluster "prod-eu" .PodCIDR }}`,
+			expected: "10.1.0.0/16",
+		},
+		"service cidr of the target cluster in except": {
+			cidr:        "10.0.0.0/8",
+			except:      []interface{}{`{{ .Cluster .Name .ServiceCIDR }}`},
+			expected:    "10.0.0.0/8",
+			expectedExc: []interface{}{"10.96.0.0/12"},
+		},
+		"unknown cluster": {
+			cidr:      `{{ .Cluster "prod-ap" .PodCIDR }}`,
+			expectErr: true,
+		},
+		"cidr not recorded": {
+			cidr:      `{{ .Cluster "prod-us" .ServiceCIDR }}`,
+			expectErr: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := networkPolicy(tc.cidr, tc.except)
+			err := m.Mutate(obj, clusters[0])
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := networkPolicy(tc.expected, tc.expectedExc)
+			if !reflect.DeepEqual(obj, expected) {
+				t.Fatalf("Expected %v, got %v", expected, obj)
+			}
+		})
+	}
+}
+
+func networkCluster(name, podCIDR, serviceCIDR string) *fedv1b1.KubeFedCluster {
+	return &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: fedv1b1.KubeFedClusterSpec{
+			Network: &fedv1b1.ClusterNetwork{
+				PodCIDR:     podCIDR,
+				ServiceCIDR: serviceCIDR,
+			},
+		},
+	}
+}
+
+func networkPolicy(cidr string, except []interface{}) *unstructured.Unstructured {
+	ipBlock := map[string]interface{}{
+		"cidr": cidr,
+	}
+	if except != nil {
+		ipBlock["except"] = except
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "NetworkPolicy",
+			"spec": map[string]interface{}{
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"from": []interface{}{
+							map[string]interface{}{
+								"ipBlock": ipBlock,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutator
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// DispatchMutator transforms the resource rendered from the template
+// of a federated resource for a member cluster before it is
+// propagated to that cluster.
+type DispatchMutator interface {
+	// Name identifies the mutator in errors.
+	Name() string
+	// Mutate modifies the given object for the given cluster.
+	Mutate(obj *unstructured.Unstructured, cluster *fedv1b1.KubeFedCluster) error
+}
+
+// Pipeline applies an ordered list of mutators, each limited to the
+// clusters matched by its cluster selector.
+type Pipeline struct {
+	mutators []*selectiveMutator
+	version  string
+}
+
+type selectiveMutator struct {
+	DispatchMutator
+	selector labels.Selector
+}
+
+// NewPipeline returns the pipeline for the given mutator
+// configuration, or nil if no mutators are configured.
+func NewPipeline(configs []fedv1b1.DispatchMutatorConfig) (*Pipeline, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	p := &Pipeline{}
+	for i := range configs {
+		config := &configs[i]
+		m, err := newMutator(config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid dispatch mutator %d", i)
+		}
+		selector := labels.Everything()
+		if config.ClusterSelector != nil {
+			selector, err = metav1.LabelSelectorAsSelector(config.ClusterSelector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid cluster selector for dispatch mutator %d", i)
+			}
+		}
+		p.mutators = append(p.mutators, &selectiveMutator{DispatchMutator: m, selector: selector})
+	}
+
+	// The version identifies the configuration so that a change to
+	// the configuration results in resources being updated in member
+	// clusters.
+	data, err := json.Marshal(configs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal dispatch mutators")
+	}
+	hash := md5.Sum(data)
+	p.version = hex.EncodeToString(hash[:])
+
+	return p, nil
+}
+
+// Append returns the given pipeline with the given mutator appended,
+// applying to all clusters. A pipeline is created if the given
+// pipeline is nil.
+func (p *Pipeline) Append(m DispatchMutator) *Pipeline {
+	if p == nil {
+		p = &Pipeline{}
+	}
+	p.mutators = append(p.mutators, &selectiveMutator{DispatchMutator: m, selector: labels.Everything()})
+	return p
+}
+
+// Version returns a hash of the configuration of the pipeline. The
+// version is empty if the pipeline only contains appended mutators.
+func (p *Pipeline) Version() string {
+	return p.version
+}
+
+// Mutate applies, in order, the mutators whose cluster selector matches
+// the labels of the given cluster to the given object.
+func (p *Pipeline) Mutate(obj *unstructured.Unstructured, cluster *fedv1b1.KubeFedCluster) error {
+	for i, m := range p.mutators {
+		if !m.selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		if err := m.Mutate(obj, cluster); err != nil {
+			return errors.Wrapf(err, "dispatch mutator %d (%s) failed", i, m.Name())
+		}
+	}
+	return nil
+}
+
+func newMutator(config *fedv1b1.DispatchMutatorConfig) (DispatchMutator, error) {
+	switch {
+	case config.ImageRewrite != nil:
+		return &imageRewriteMutator{config: *config.ImageRewrite}, nil
+	case config.LabelInjection != nil:
+		return &labelInjectionMutator{config: *config.LabelInjection}, nil
+	case config.NodeSelectorInjection != nil:
+		return &nodeSelectorInjectionMutator{config: *config.NodeSelectorInjection}, nil
+	case config.ResourceRequestScaling != nil:
+		return &resourceRequestScalingMutator{config: *config.ResourceRequestScaling}, nil
+	case config.SubjectRewrite != nil:
+		return &subjectRewriteMutator{config: *config.SubjectRewrite}, nil
+	}
+	return nil, errors.New("no mutator specified")
+}
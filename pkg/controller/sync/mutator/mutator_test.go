@@ -0,0 +1,244 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutator
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestPipeline(t *testing.T) {
+	configs := []fedv1b1.DispatchMutatorConfig{
+		{
+			ImageRewrite: &fedv1b1.ImageRewriteMutator{
+				From: "docker.io/",
+				To:   "mirror.example.com/",
+			},
+		},
+		{
+			LabelInjection: &fedv1b1.LabelInjectionMutator{
+				Labels:      map[string]string{"team": "payments"},
+				PodTemplate: true,
+			},
+		},
+		{
+			ClusterSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubefed.io/cluster-class": "edge"},
+			},
+			NodeSelectorInjection: &fedv1b1.NodeSelectorInjectionMutator{
+				NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+			},
+		},
+		{
+			ClusterSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubefed.io/cluster-class": "edge"},
+			},
+			ResourceRequestScaling: &fedv1b1.ResourceRequestScalingMutator{
+				Percent: 50,
+			},
+		},
+	}
+	pipeline, err := NewPipeline(configs)
+	if err != nil {
+		t.Fatalf("Unexpected error creating pipeline: %v", err)
+	}
+
+	testCases := map[string]struct {
+		clusterLabels map[string]string
+		expected      *unstructured.Unstructured
+	}{
+		"cluster not matching selectors": {
+			expected: deployment("mirror.example.com/nginx", nil, "500m", "128Mi", "1"),
+		},
+		"cluster matching selectors": {
+			clusterLabels: map[string]string{"kubefed.io/cluster-class": "edge"},
+			expected:      deployment("mirror.example.com/nginx", map[string]interface{}{"kubernetes.io/arch": "arm64"}, "250m", "64Mi", "1"),
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			cluster := &fedv1b1.KubeFedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cluster1",
+					Labels: tc.clusterLabels,
+				},
+			}
+			obj := deployment("docker.io/nginx", nil, "500m", "128Mi", "1")
+			if err := pipeline.Mutate(obj, cluster); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			setLabels(tc.expected, map[string]interface{}{"team": "payments"})
+			if !reflect.DeepEqual(obj, tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, obj)
+			}
+		})
+	}
+}
+
+func TestResourceRequestScalingCappedAtLimit(t *testing.T) {
+	m := &resourceRequestScalingMutator{config: fedv1b1.ResourceRequestScalingMutator{Percent: 300}}
+	obj := deployment("nginx", nil, "500m", "128Mi", "1")
+	if err := m.Mutate(obj, &fedv1b1.KubeFedCluster{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := deployment("nginx", nil, "1", "384Mi", "1")
+	if !reflect.DeepEqual(obj, expected) {
+		t.Fatalf("Expected %v, got %v", expected, obj)
+	}
+}
+
+func TestSubjectRewrite(t *testing.T) {
+	m := &subjectRewriteMutator{config: fedv1b1.SubjectRewriteMutator{
+		Mappings: []fedv1b1.SubjectMapping{
+			{Kind: "Group", From: "platform-admins", To: "oidc:platform-admins"},
+			{Kind: "User", From: "jane", To: "jane@example.com"},
+		},
+	}}
+	binding := func(kind string, subjects ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       kind,
+			"subjects":   subjects,
+		}}
+	}
+	subject := func(kind, name string) map[string]interface{} {
+		return map[string]interface{}{"kind": kind, "name": name}
+	}
+
+	testCases := map[string]struct {
+		obj      *unstructured.Unstructured
+		expected *unstructured.Unstructured
+	}{
+		"RoleBinding subjects are renamed": {
+			obj: binding("RoleBinding",
+				subject("Group", "platform-admins"),
+				subject("User", "jane"),
+				subject("ServiceAccount", "jane"),
+			),
+			expected: binding("RoleBinding",
+				subject("Group", "oidc:platform-admins"),
+				subject("User", "jane@example.com"),
+				subject("ServiceAccount", "jane"),
+			),
+		},
+		"ClusterRoleBinding subjects are renamed": {
+			obj:      binding("ClusterRoleBinding", subject("Group", "platform-admins")),
+			expected: binding("ClusterRoleBinding", subject("Group", "oidc:platform-admins")),
+		},
+		"Subjects not matching a mapping are unchanged": {
+			obj:      binding("RoleBinding", subject("Group", "jane")),
+			expected: binding("RoleBinding", subject("Group", "jane")),
+		},
+		"Other resources are unchanged": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"subjects":   []interface{}{subject("Group", "platform-admins")},
+			}},
+			expected: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"subjects":   []interface{}{subject("Group", "platform-admins")},
+			}},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if err := m.Mutate(tc.obj, &fedv1b1.KubeFedCluster{}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.obj, tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, tc.obj)
+			}
+		})
+	}
+}
+
+func TestNewPipelineVersion(t *testing.T) {
+	if pipeline, err := NewPipeline(nil); pipeline != nil || err != nil {
+		t.Fatalf("Expected no pipeline for no mutators, got %v, %v", pipeline, err)
+	}
+
+	newPipeline := func(percent int32) *Pipeline {
+		pipeline, err := NewPipeline([]fedv1b1.DispatchMutatorConfig{
+			{ResourceRequestScaling: &fedv1b1.ResourceRequestScalingMutator{Percent: percent}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error creating pipeline: %v", err)
+		}
+		return pipeline
+	}
+	if newPipeline(50).Version() != newPipeline(50).Version() {
+		t.Fatalf("Expected the same version for the same configuration")
+	}
+	if newPipeline(50).Version() == newPipeline(75).Version() {
+		t.Fatalf("Expected a different version for a different configuration")
+	}
+
+	if _, err := NewPipeline([]fedv1b1.DispatchMutatorConfig{{}}); err == nil {
+		t.Fatalf("Expected an error for a mutator without configuration")
+	}
+}
+
+func deployment(image string, nodeSelector map[string]interface{}, cpuRequest, memoryRequest, cpuLimit string) *unstructured.Unstructured {
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name":  "nginx",
+				"image": image,
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{
+						"cpu":    cpuRequest,
+						"memory": memoryRequest,
+					},
+					"limits": map[string]interface{}{
+						"cpu": cpuLimit,
+					},
+				},
+			},
+		},
+	}
+	if nodeSelector != nil {
+		podSpec["nodeSelector"] = nodeSelector
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "nginx",
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": podSpec,
+				},
+			},
+		},
+	}
+}
+
+func setLabels(obj *unstructured.Unstructured, labels map[string]interface{}) {
+	obj.Object["metadata"].(map[string]interface{})["labels"] = labels
+	obj.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["metadata"] = map[string]interface{}{
+		"labels": labels,
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// propagationNotifications returns the notifications of the
+// transitions between the given previous and current propagation
+// states of the given federated resource. A cluster is only reported
+// when it starts failing or drifting, not for as long as it keeps
+// failing or drifting.
+func propagationNotifications(fedObject *unstructured.Unstructured, previous, current *status.PropagationState, now time.Time) []*util.PropagationNotification {
+	newNotification := func(event fedv1b1.NotificationEvent) *util.PropagationNotification {
+		return &util.PropagationNotification{
+			Event:      event,
+			Kind:       fedObject.GetKind(),
+			Namespace:  fedObject.GetNamespace(),
+			Name:       fedObject.GetName(),
+			Generation: fedObject.GetGeneration(),
+			Time:       now.UTC().Format(time.RFC3339),
+		}
+	}
+
+	notifications := []*util.PropagationNotification{}
+
+	clusterNames := []string{}
+	for clusterName := range current.Clusters {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+	for _, clusterName := range clusterNames {
+		clusterStatus := current.Clusters[clusterName]
+		previousStatus, existed := previous.Clusters[clusterName]
+		var event fedv1b1.NotificationEvent
+		switch {
+		case clusterStatus.IsFailure() && !(existed && previousStatus.IsFailure()):
+			event = fedv1b1.NotificationClusterFailed
+		case clusterStatus == status.Drifted && previousStatus != status.Drifted:
+			event = fedv1b1.NotificationDriftDetected
+		default:
+			continue
+		}
+		notification := newNotification(event)
+		notification.ClusterName = clusterName
+		notification.ClusterStatus = string(clusterStatus)
+		notifications = append(notifications, notification)
+	}
+
+	if current.Propagated && !previous.Propagated {
+		notifications = append(notifications, newNotification(fedv1b1.NotificationAllClustersReady))
+	}
+
+	return notifications
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+)
+
+func TestPropagationNotifications(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetKind("FederatedDeployment")
+	fedObject.SetNamespace("shop")
+	fedObject.SetName("frontend")
+	fedObject.SetGeneration(2)
+	now := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		previous       status.PropagationState
+		current        status.PropagationState
+		expectedEvents []string
+	}{
+		"All clusters became ready": {
+			previous: status.PropagationState{Clusters: status.PropagationStatusMap{"cluster1": status.CreationFailed}},
+			current: status.PropagationState{
+				Propagated: true,
+				Clusters:   status.PropagationStatusMap{"cluster1": status.ClusterPropagationOK},
+			},
+			expectedEvents: []string{"AllClustersReady"},
+		},
+		"Cluster started failing": {
+			previous: status.PropagationState{
+				Propagated: true,
+				Clusters:   status.PropagationStatusMap{"cluster1": status.ClusterPropagationOK},
+			},
+			current: status.PropagationState{Clusters: status.PropagationStatusMap{
+				"cluster1": status.UpdateFailed,
+				"cluster2": status.CreationTimedOut,
+			}},
+			expectedEvents: []string{"ClusterFailed/cluster1/UpdateFailed", "ClusterFailed/cluster2/CreationTimedOut"},
+		},
+		"Cluster still failing with a different status": {
+			previous: status.PropagationState{Clusters: status.PropagationStatusMap{"cluster1": status.CreationFailed}},
+			current:  status.PropagationState{Clusters: status.PropagationStatusMap{"cluster1": status.ClusterNotReady}},
+		},
+		"Cluster pending delivery": {
+			previous: status.PropagationState{Clusters: status.PropagationStatusMap{}},
+			current:  status.PropagationState{Clusters: status.PropagationStatusMap{"cluster1": status.PendingDelivery}},
+		},
+		"Resource drifted": {
+			previous: status.PropagationState{
+				Propagated: true,
+				Clusters:   status.PropagationStatusMap{"cluster1": status.ClusterPropagationOK, "cluster2": status.Drifted},
+			},
+			current: status.PropagationState{
+				Propagated: true,
+				Clusters:   status.PropagationStatusMap{"cluster1": status.Drifted, "cluster2": status.Drifted},
+			},
+			expectedEvents: []string{"DriftDetected/cluster1/Drifted"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			notifications := propagationNotifications(fedObject, &tc.previous, &tc.current, now)
+			events := []string{}
+			for _, notification := range notifications {
+				if notification.Kind != "FederatedDeployment" || notification.Namespace != "shop" ||
+					notification.Name != "frontend" || notification.Generation != 2 ||
+					notification.Time != "2020-04-01T10:00:00Z" {
+					t.Errorf("Unexpected resource in notification %+v", notification)
+				}
+				event := string(notification.Event)
+				if notification.Event != fedv1b1.NotificationAllClustersReady {
+					event = event + "/" + notification.ClusterName + "/" + notification.ClusterStatus
+				}
+				events = append(events, event)
+			}
+			if len(tc.expectedEvents) == 0 && len(events) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(events, tc.expectedEvents) {
+				t.Errorf("Expected events %v, got %v", tc.expectedEvents, events)
+			}
+		})
+	}
+}
@@ -17,14 +17,62 @@ limitations under the License.
 package sync
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
 
+// clusterGroupFunc returns the ClusterGroup with the given name, or
+// nil if it does not exist.
+type clusterGroupFunc func(name string) (*fedv1b1.ClusterGroup, error)
+
+// volumeClaimClustersFunc returns the names of the clusters the
+// FederatedPersistentVolumeClaim with the given name has been placed
+// in, or nil if it does not exist.
+type volumeClaimClustersFunc func(qualifiedName util.QualifiedName) (sets.String, error)
+
+// placementDecisions records, by cluster name, why each cluster was
+// selected or excluded by placement. Recording to a nil map is a
+// no-op.
+type placementDecisions map[string]status.GenericPlacementDecision
+
+func (d placementDecisions) record(clusterName string, selected bool, reason status.PlacementReason, messageFmt string, args ...interface{}) {
+	if d == nil {
+		return
+	}
+	d[clusterName] = status.GenericPlacementDecision{
+		Name:     clusterName,
+		Selected: selected,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFmt, args...),
+	}
+}
+
+// exclude records that a previously selected cluster was excluded.
+func (d placementDecisions) exclude(clusterName string, reason status.PlacementReason, messageFmt string, args ...interface{}) {
+	if decision, ok := d[clusterName]; ok && decision.Selected {
+		d.record(clusterName, false, reason, messageFmt, args...)
+	}
+}
+
+// List returns the recorded decisions.
+func (d placementDecisions) List() []status.GenericPlacementDecision {
+	decisions := []status.GenericPlacementDecision{}
+	for _, decision := range d {
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
+
 // computeNamespacedPlacement determines placement for namespaced
 // federated resources (e.g. FederatedConfigMap).
 //
@@ -39,8 +87,8 @@ import (
 // because the single namespace by definition must exist on member
 // clusters, so namespace placement becomes a mechanism for limiting
 // rather than allowing propagation.
-func computeNamespacedPlacement(resource, namespace *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster, limitedScope bool) (selectedClusters sets.String, err error) {
-	resourceClusters, err := computePlacement(resource, clusters)
+func computeNamespacedPlacement(resource, namespace *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster, limitedScope bool, getClusterGroup clusterGroupFunc, decisions placementDecisions) (selectedClusters sets.String, err error) {
+	resourceClusters, err := computePlacement(resource, clusters, getClusterGroup, decisions)
 	if err != nil {
 		return nil, err
 	}
@@ -53,59 +101,243 @@ func computeNamespacedPlacement(resource, namespace *unstructured.Unstructured,
 			return resourceClusters, nil
 		}
 		// Resource should not exist in any member clusters.
+		for clusterName := range resourceClusters {
+			decisions.exclude(clusterName, status.NamespaceNotPropagated, "Namespace %q is not federated", resource.GetNamespace())
+		}
 		return sets.String{}, nil
 	}
 
-	namespaceClusters, err := computePlacement(namespace, clusters)
+	namespaceClusters, err := computePlacement(namespace, clusters, getClusterGroup, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	// If both namespace and resource placement exist, the desired
 	// list of clusters is their intersection.
+	for clusterName := range resourceClusters.Difference(namespaceClusters) {
+		decisions.exclude(clusterName, status.NamespaceNotPlaced, "Not selected by the placement of the federated namespace %q", namespace.GetName())
+	}
 	return resourceClusters.Intersection(namespaceClusters), nil
 }
 
 // computePlacement determines the selected clusters for a federated
 // resource.
-func computePlacement(resource *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster) (selectedClusters sets.String, err error) {
-	selectedNames, err := selectedClusterNames(resource, clusters)
+func computePlacement(resource *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster, getClusterGroup clusterGroupFunc, decisions placementDecisions) (selectedClusters sets.String, err error) {
+	selectedNames, err := selectedClusterNames(resource, clusters, getClusterGroup, decisions)
 	if err != nil {
 		return nil, err
 	}
 	clusterNames := getClusterNames(clusters)
+	for clusterName := range selectedNames.Difference(clusterNames) {
+		decisions.record(clusterName, false, status.ClusterNotRegistered, "Listed in spec.placement.clusters but not registered with KubeFed")
+	}
 	return clusterNames.Intersection(selectedNames), nil
 }
 
-func selectedClusterNames(resource *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster) (sets.String, error) {
+func selectedClusterNames(resource *unstructured.Unstructured, clusters []*fedv1b1.KubeFedCluster, getClusterGroup clusterGroupFunc, decisions placementDecisions) (sets.String, error) {
 	placement, err := util.UnmarshalGenericPlacement(resource)
 	if err != nil {
 		return nil, err
 	}
+	requiredSelector, err := placement.RequiredClusterSelector()
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid spec.placement.requiredClusterSelector")
+	}
 
 	selectedNames := sets.String{}
 	clusterNames := placement.ClusterNames()
-	// Only use selector if clusters are nil. An empty list of
-	// clusters implies no clusters are selected.
-	if clusterNames == nil {
+	groupNames := placement.ClusterGroupNames()
+	// Only use cluster groups if clusters are nil, and only use
+	// selector if both clusters and cluster groups are nil. An empty
+	// list of clusters or cluster groups implies no clusters are
+	// selected.
+	if clusterNames == nil && groupNames != nil {
+		groupsByCluster := map[string][]string{}
+		for _, groupName := range groupNames {
+			group, err := getClusterGroup(groupName)
+			if err != nil {
+				return nil, err
+			}
+			if group == nil {
+				return nil, errors.Errorf("ClusterGroup %q not found", groupName)
+			}
+			members, err := util.ClusterGroupMembers(group, clusters)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to determine members of ClusterGroup %q", groupName)
+			}
+			for member := range members {
+				groupsByCluster[member] = append(groupsByCluster[member], groupName)
+			}
+			selectedNames = selectedNames.Union(members)
+		}
+		for _, cluster := range clusters {
+			if memberOf, ok := groupsByCluster[cluster.Name]; ok {
+				decisions.record(cluster.Name, true, status.ClusterGroupMember, "Member of ClusterGroup %s", strings.Join(memberOf, ", "))
+			} else {
+				decisions.record(cluster.Name, false, status.NotClusterGroupMember, "Not a member of any ClusterGroup in spec.placement.clusterGroups")
+			}
+		}
+	} else if clusterNames == nil {
 		selector, err := placement.ClusterSelector()
 		if err != nil {
 			return nil, err
 		}
 		for _, cluster := range clusters {
-			if selector.Matches(labels.Set(cluster.Labels)) {
+			switch {
+			case placement.Spec.Placement.ClusterSelector == nil && requiredSelector != nil:
+				// The required selector alone determines the
+				// selected clusters.
+				if requiredSelector.Matches(labels.Set(cluster.Labels)) {
+					selectedNames.Insert(cluster.Name)
+					decisions.record(cluster.Name, true, status.RequiredClusterSelectorMatched, "Matched spec.placement.requiredClusterSelector %q", requiredSelector.String())
+				} else {
+					decisions.record(cluster.Name, false, status.RequiredClusterSelectorNotMatched, "Did not match spec.placement.requiredClusterSelector %q", requiredSelector.String())
+				}
+			case placement.Spec.Placement.ClusterSelector == nil:
+				decisions.record(cluster.Name, false, status.NoPlacement, "No clusters, clusterGroups or clusterSelector specified in spec.placement")
+			case selector.Matches(labels.Set(cluster.Labels)):
 				selectedNames.Insert(cluster.Name)
+				decisions.record(cluster.Name, true, status.ClusterSelectorMatched, "Matched spec.placement.clusterSelector %q", selector.String())
+			default:
+				decisions.record(cluster.Name, false, status.ClusterSelectorNotMatched, "Did not match spec.placement.clusterSelector %q", selector.String())
 			}
 		}
 	} else {
 		for _, clusterName := range clusterNames {
 			selectedNames.Insert(clusterName)
 		}
+		for _, cluster := range clusters {
+			if selectedNames.Has(cluster.Name) {
+				decisions.record(cluster.Name, true, status.ClusterListed, "Listed in spec.placement.clusters")
+			} else {
+				decisions.record(cluster.Name, false, status.ClusterNotListed, "Not listed in spec.placement.clusters")
+			}
+		}
 	}
 
+	excludeClustersNotMatchingSelector(selectedNames, clusters, requiredSelector, decisions)
+
+	excludeClustersMissingCRDs(selectedNames, clusters, placement.RequiredCRDs(), decisions)
+
 	return selectedNames, nil
 }
 
+// excludeClustersNotMatchingSelector removes from the selected names
+// the clusters whose labels do not match the required cluster
+// selector of the placement. A nil selector excludes no clusters.
+func excludeClustersNotMatchingSelector(selectedNames sets.String, clusters []*fedv1b1.KubeFedCluster, selector labels.Selector, decisions placementDecisions) {
+	if selector == nil {
+		return
+	}
+	for _, cluster := range clusters {
+		if !selectedNames.Has(cluster.Name) || selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		selectedNames.Delete(cluster.Name)
+		decisions.exclude(cluster.Name, status.RequiredClusterSelectorNotMatched, "Did not match spec.placement.requiredClusterSelector %q", selector.String())
+	}
+}
+
+// excludeCordonedClusters removes from the selected names the
+// clusters that are cordoned, unless the managed resource already
+// exists in them. Whether the resource exists is determined from the
+// member cluster rather than from the last propagation status so that
+// a resource whose last propagation failed is not removed.
+func excludeCordonedClusters(selectedNames sets.String, clusters []*fedv1b1.KubeFedCluster, isManagedInCluster func(clusterName string) (bool, error), decisions placementDecisions) error {
+	for _, cluster := range clusters {
+		if !cluster.Spec.Unschedulable || !selectedNames.Has(cluster.Name) {
+			continue
+		}
+		managed, err := isManagedInCluster(cluster.Name)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to determine whether the resource exists in cordoned cluster %q", cluster.Name)
+		}
+		if managed {
+			continue
+		}
+		selectedNames.Delete(cluster.Name)
+		decisions.exclude(cluster.Name, status.ClusterCordoned, "The cluster is cordoned")
+	}
+	return nil
+}
+
+// excludeClustersMissingCRDs removes from the selected names the
+// clusters whose inventory does not include all of the required
+// CustomResourceDefinitions. A cluster that has yet to report an
+// inventory is excluded until it does.
+func excludeClustersMissingCRDs(selectedNames sets.String, clusters []*fedv1b1.KubeFedCluster, requiredCRDs []string, decisions placementDecisions) {
+	if len(requiredCRDs) == 0 {
+		return
+	}
+	for _, cluster := range clusters {
+		if !selectedNames.Has(cluster.Name) {
+			continue
+		}
+		inventory := cluster.Status.Inventory
+		if inventory == nil {
+			selectedNames.Delete(cluster.Name)
+			decisions.exclude(cluster.Name, status.RequiredCRDsMissing, "The inventory of the cluster has not been reported")
+			continue
+		}
+		missing := sets.NewString(requiredCRDs...).Difference(sets.NewString(inventory.CRDs...))
+		if missing.Len() > 0 {
+			selectedNames.Delete(cluster.Name)
+			decisions.exclude(cluster.Name, status.RequiredCRDsMissing, "Missing CustomResourceDefinitions required by spec.placement.requiredCRDs: %s", strings.Join(missing.List(), ", "))
+		}
+	}
+}
+
+// excludeClustersMissingAPI removes from the selected clusters those
+// whose inventory indicates that the given API version is not served.
+// A cluster that has yet to report the API versions it serves is not
+// excluded.
+func excludeClustersMissingAPI(selectedClusters sets.String, clusters []*fedv1b1.KubeFedCluster, apiVersion string, decisions placementDecisions) {
+	for _, cluster := range clusters {
+		if !selectedClusters.Has(cluster.Name) {
+			continue
+		}
+		inventory := cluster.Status.Inventory
+		if inventory == nil || len(inventory.APIVersions) == 0 {
+			continue
+		}
+		if !sets.NewString(inventory.APIVersions...).Has(apiVersion) {
+			selectedClusters.Delete(cluster.Name)
+			decisions.exclude(cluster.Name, status.APIMissing, "API version %q is not served by the cluster", apiVersion)
+		}
+	}
+}
+
+// excludeClustersWithoutVolumeClaims removes from the selected clusters
+// those in which any of the named FederatedPersistentVolumeClaims in
+// the given namespace has not been placed, so that a workload is only
+// propagated to the clusters that hold its data.
+func excludeClustersWithoutVolumeClaims(selectedClusters sets.String, namespace string, claimNames []string, getVolumeClaimClusters volumeClaimClustersFunc, decisions placementDecisions) error {
+	for _, claimName := range claimNames {
+		claimClusters, err := getVolumeClaimClusters(util.QualifiedName{Namespace: namespace, Name: claimName})
+		if err != nil {
+			return err
+		}
+		if claimClusters == nil {
+			return errors.Errorf("FederatedPersistentVolumeClaim %q not found", claimName)
+		}
+		for clusterName := range selectedClusters.Difference(claimClusters) {
+			selectedClusters.Delete(clusterName)
+			decisions.exclude(clusterName, status.VolumeClaimNotPlaced, "FederatedPersistentVolumeClaim %q listed in spec.placement.volumeClaims is not placed in the cluster", claimName)
+		}
+	}
+	return nil
+}
+
+// excludeClustersNotAllowed removes from the selected clusters those
+// that are not listed by a ClusterAllowlist that applies to the
+// resource.
+func excludeClustersNotAllowed(selectedClusters, allowedClusters sets.String, decisions placementDecisions) {
+	for clusterName := range selectedClusters.Difference(allowedClusters) {
+		selectedClusters.Delete(clusterName)
+		decisions.exclude(clusterName, status.ClusterNotAllowed, "Not listed by a ClusterAllowlist for the namespace of the resource")
+	}
+}
+
 func getClusterNames(clusters []*fedv1b1.KubeFedCluster) sets.String {
 	clusterNames := sets.String{}
 	for _, cluster := range clusters {
@@ -20,11 +20,13 @@ import (
 	"reflect"
 	"testing"
 
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
 
@@ -42,13 +44,42 @@ func TestSelectedClusterNames(t *testing.T) {
 					"foo": "bar",
 				},
 			},
+			Status: fedv1b1.KubeFedClusterStatus{
+				Inventory: &fedv1b1.ClusterInventory{
+					CRDs: []string{"certificates.cert-manager.io"},
+				},
+			},
 		},
 	}
 
+	clusterGroups := map[string]*fedv1b1.ClusterGroup{
+		"static": {
+			Spec: fedv1b1.ClusterGroupSpec{
+				Clusters: []string{"cluster1", "cluster3"},
+			},
+		},
+		"selected": {
+			Spec: fedv1b1.ClusterGroupSpec{
+				ClusterSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"foo": "bar",
+					},
+				},
+			},
+		},
+	}
+	getClusterGroup := func(name string) (*fedv1b1.ClusterGroup, error) {
+		return clusterGroups[name], nil
+	}
+
 	testCases := map[string]struct {
-		clusterNames    []string
-		clusterSelector map[string]string
-		expectedNames   sets.String
+		clusterNames     []string
+		clusterGroups    []string
+		clusterSelector  map[string]string
+		requiredSelector map[string]string
+		requiredCRDs     []string
+		expectedNames    sets.String
+		expectedErr      bool
 	}{
 		"ignore cluster selector when cluster names present": {
 			clusterNames:    []string{"cluster1"},
@@ -75,6 +106,64 @@ func TestSelectedClusterNames(t *testing.T) {
 			},
 			expectedNames: sets.NewString("cluster2"),
 		},
+		"ignore cluster groups when cluster names present": {
+			clusterNames:  []string{"cluster2"},
+			clusterGroups: []string{"static"},
+			expectedNames: sets.NewString("cluster2"),
+		},
+		"no clusters when cluster groups empty": {
+			clusterGroups:   []string{},
+			clusterSelector: map[string]string{},
+			expectedNames:   sets.NewString(),
+		},
+		"ignore cluster selector when cluster groups present": {
+			clusterGroups:   []string{"static"},
+			clusterSelector: map[string]string{},
+			expectedNames:   sets.NewString("cluster1"),
+		},
+		"union of members when multiple cluster groups present": {
+			clusterGroups: []string{"static", "selected"},
+			expectedNames: sets.NewString("cluster1", "cluster2"),
+		},
+		"error when cluster group not found": {
+			clusterGroups: []string{"missing"},
+			expectedErr:   true,
+		},
+		"only clusters with required crds when required crds present": {
+			clusterNames:  []string{"cluster1", "cluster2"},
+			requiredCRDs:  []string{"certificates.cert-manager.io"},
+			expectedNames: sets.NewString("cluster2"),
+		},
+		"no clusters when required crds missing": {
+			clusterSelector: map[string]string{},
+			requiredCRDs:    []string{"certificates.cert-manager.io", "issuers.cert-manager.io"},
+			expectedNames:   sets.NewString(),
+		},
+		"only matching clusters when required selector present": {
+			clusterNames: []string{"cluster1", "cluster2"},
+			requiredSelector: map[string]string{
+				"foo": "bar",
+			},
+			expectedNames: sets.NewString("cluster2"),
+		},
+		"required selector limits cluster groups": {
+			clusterGroups: []string{"static"},
+			requiredSelector: map[string]string{
+				"foo": "bar",
+			},
+			expectedNames: sets.NewString(),
+		},
+		"matching clusters when required selector is the only placement": {
+			requiredSelector: map[string]string{
+				"foo": "bar",
+			},
+			expectedNames: sets.NewString("cluster2"),
+		},
+		"all clusters when required selector empty": {
+			clusterSelector:  map[string]string{},
+			requiredSelector: map[string]string{},
+			expectedNames:    sets.NewString("cluster1", "cluster2"),
+		},
 	}
 
 	for testName, testCase := range testCases {
@@ -87,13 +176,34 @@ func TestSelectedClusterNames(t *testing.T) {
 			if err := util.SetClusterNames(obj, testCase.clusterNames); err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
+			if testCase.clusterGroups != nil {
+				if err := unstructured.SetNestedStringSlice(obj.Object, testCase.clusterGroups, util.SpecField, util.PlacementField, util.ClusterGroupsField); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
 			if testCase.clusterSelector != nil {
 				if err := unstructured.SetNestedStringMap(obj.Object, testCase.clusterSelector, util.SpecField, util.PlacementField, util.ClusterSelectorField, util.MatchLabelsField); err != nil {
 					t.Fatalf("Unexpected error: %v", err)
 				}
 			}
+			if testCase.requiredSelector != nil {
+				if err := unstructured.SetNestedStringMap(obj.Object, testCase.requiredSelector, util.SpecField, util.PlacementField, util.RequiredClusterSelectorField, util.MatchLabelsField); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+			if testCase.requiredCRDs != nil {
+				if err := unstructured.SetNestedStringSlice(obj.Object, testCase.requiredCRDs, util.SpecField, util.PlacementField, util.RequiredCRDsField); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
 
-			selectedNames, err := selectedClusterNames(obj, clusters)
+			selectedNames, err := selectedClusterNames(obj, clusters, getClusterGroup, nil)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
@@ -103,3 +213,309 @@ func TestSelectedClusterNames(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeNamespacedPlacementDecisions(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster2",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster3",
+			},
+		},
+	}
+	getClusterGroup := func(name string) (*fedv1b1.ClusterGroup, error) {
+		return nil, nil
+	}
+
+	newObj := func(name string, clusterNames []string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"spec": make(map[string]interface{}),
+			},
+		}
+		obj.SetName(name)
+		obj.SetNamespace("ns")
+		if err := util.SetClusterNames(obj, clusterNames); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return obj
+	}
+	resource := newObj("resource", []string{"cluster1", "cluster2", "cluster4"})
+	namespace := newObj("ns", []string{"cluster1", "cluster3"})
+
+	decisions := placementDecisions{}
+	selectedNames, err := computeNamespacedPlacement(resource, namespace, clusters, false, getClusterGroup, decisions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedNames := sets.NewString("cluster1")
+	if !reflect.DeepEqual(selectedNames, expectedNames) {
+		t.Fatalf("Expected names %v, got %v", expectedNames, selectedNames)
+	}
+
+	expectedReasons := map[string]status.PlacementReason{
+		"cluster1": status.ClusterListed,
+		"cluster2": status.NamespaceNotPlaced,
+		"cluster3": status.ClusterNotListed,
+		"cluster4": status.ClusterNotRegistered,
+	}
+	if len(decisions) != len(expectedReasons) {
+		t.Fatalf("Expected %d decisions, got %v", len(expectedReasons), decisions)
+	}
+	for clusterName, expectedReason := range expectedReasons {
+		decision := decisions[clusterName]
+		if decision.Reason != expectedReason {
+			t.Fatalf("Expected reason %q for %q, got %q", expectedReason, clusterName, decision.Reason)
+		}
+		if decision.Selected != selectedNames.Has(clusterName) {
+			t.Fatalf("Expected selected to be %v for %q", selectedNames.Has(clusterName), clusterName)
+		}
+	}
+}
+
+func TestExcludeClustersMissingAPI(t *testing.T) {
+	newCluster := func(name string, inventory *fedv1b1.ClusterInventory) *fedv1b1.KubeFedCluster {
+		return &fedv1b1.KubeFedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Status: fedv1b1.KubeFedClusterStatus{
+				Inventory: inventory,
+			},
+		}
+	}
+	clusters := []*fedv1b1.KubeFedCluster{
+		newCluster("served", &fedv1b1.ClusterInventory{
+			APIVersions: []string{"apps/v1", "cert-manager.io/v1alpha2", "v1"},
+		}),
+		newCluster("missing", &fedv1b1.ClusterInventory{
+			APIVersions: []string{"apps/v1", "v1"},
+		}),
+		newCluster("unreported", nil),
+		newCluster("unselected", &fedv1b1.ClusterInventory{
+			APIVersions: []string{"v1"},
+		}),
+	}
+
+	selectedClusters := sets.NewString("served", "missing", "unreported")
+	decisions := placementDecisions{}
+	for clusterName := range selectedClusters {
+		decisions.record(clusterName, true, status.ClusterListed, "Listed in spec.placement.clusters")
+	}
+	excludeClustersMissingAPI(selectedClusters, clusters, "cert-manager.io/v1alpha2", decisions)
+
+	expectedClusters := sets.NewString("served", "unreported")
+	if !reflect.DeepEqual(selectedClusters, expectedClusters) {
+		t.Fatalf("Expected clusters %v, got %v", expectedClusters, selectedClusters)
+	}
+	if reason := decisions["missing"].Reason; reason != status.APIMissing {
+		t.Fatalf("Expected reason %q for %q, got %q", status.APIMissing, "missing", reason)
+	}
+	if _, ok := decisions["unselected"]; ok {
+		t.Fatalf("Expected no decision for %q", "unselected")
+	}
+}
+
+func TestExcludeCordonedClusters(t *testing.T) {
+	newCluster := func(name string, unschedulable bool) *fedv1b1.KubeFedCluster {
+		return &fedv1b1.KubeFedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: fedv1b1.KubeFedClusterSpec{
+				Unschedulable: unschedulable,
+			},
+		}
+	}
+	clusters := []*fedv1b1.KubeFedCluster{
+		newCluster("schedulable", false),
+		newCluster("cordoned", true),
+		newCluster("cordoned-propagated", true),
+		newCluster("cordoned-unselected", true),
+	}
+
+	selectedClusters := sets.NewString("schedulable", "cordoned", "cordoned-propagated")
+	decisions := placementDecisions{}
+	for clusterName := range selectedClusters {
+		decisions.record(clusterName, true, status.ClusterListed, "Listed in spec.placement.clusters")
+	}
+	isManagedInCluster := func(clusterName string) (bool, error) {
+		return clusterName == "cordoned-propagated", nil
+	}
+	if err := excludeCordonedClusters(selectedClusters, clusters, isManagedInCluster, decisions); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedClusters := sets.NewString("schedulable", "cordoned-propagated")
+	if !reflect.DeepEqual(selectedClusters, expectedClusters) {
+		t.Fatalf("Expected clusters %v, got %v", expectedClusters, selectedClusters)
+	}
+	if reason := decisions["cordoned"].Reason; reason != status.ClusterCordoned {
+		t.Fatalf("Expected reason %q for %q, got %q", status.ClusterCordoned, "cordoned", reason)
+	}
+	if _, ok := decisions["cordoned-unselected"]; ok {
+		t.Fatalf("Expected no decision for %q", "cordoned-unselected")
+	}
+}
+
+func TestComputePlacementRetainsCordonedClusterWithError(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster2"},
+			Spec:       fedv1b1.KubeFedClusterSpec{Unschedulable: true},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster3"},
+			Spec:       fedv1b1.KubeFedClusterSpec{Unschedulable: true},
+		},
+	}
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": make(map[string]interface{}),
+		},
+	}
+	obj.SetName("reader")
+	if err := util.SetClusterNames(obj, []string{"cluster1", "cluster2", "cluster3"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The last update of the resource in the cordoned cluster2
+	// failed, but the resource exists there and needs to be retained.
+	obj.Object["status"] = map[string]interface{}{
+		"clusters": []interface{}{
+			map[string]interface{}{"name": "cluster1"},
+			map[string]interface{}{"name": "cluster2", "status": string(status.UpdateFailed)},
+		},
+	}
+	placement, err := util.UnmarshalGenericPlacement(obj)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	existingClusters := sets.NewString("cluster1", "cluster2")
+	resource := &federatedResource{
+		typeConfig: &fedv1b1.FederatedTypeConfig{
+			Spec: fedv1b1.FederatedTypeConfigSpec{
+				TargetType: fedv1b1.APIResource{
+					Group:   "rbac.authorization.k8s.io",
+					Version: "v1",
+					Kind:    "ClusterRole",
+					Scope:   apiextv1b1.ClusterScoped,
+				},
+			},
+		},
+		targetName:        util.QualifiedName{Name: "reader"},
+		placement:         placement,
+		federatedName:     util.QualifiedName{Name: "reader"},
+		federatedResource: obj,
+		managedObjectExists: func(clusterName string, targetName util.QualifiedName) (bool, error) {
+			return existingClusters.Has(clusterName) && targetName.Name == "reader", nil
+		},
+	}
+
+	selectedClusters, decisions, err := resource.ComputePlacement(clusters)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A cluster that is not selected would have the resource removed
+	// from it.
+	expectedClusters := sets.NewString("cluster1", "cluster2")
+	if !reflect.DeepEqual(selectedClusters, expectedClusters) {
+		t.Fatalf("Expected clusters %v, got %v", expectedClusters, selectedClusters)
+	}
+	for _, decision := range decisions {
+		if decision.Name == "cluster3" && decision.Reason != status.ClusterCordoned {
+			t.Fatalf("Expected reason %q for %q, got %q", status.ClusterCordoned, "cluster3", decision.Reason)
+		}
+	}
+}
+
+func TestExcludeClustersNotAllowed(t *testing.T) {
+	selectedClusters := sets.NewString("allowed", "not-allowed")
+	decisions := placementDecisions{}
+	for clusterName := range selectedClusters {
+		decisions.record(clusterName, true, status.ClusterListed, "Listed in spec.placement.clusters")
+	}
+	excludeClustersNotAllowed(selectedClusters, sets.NewString("allowed", "unselected"), decisions)
+
+	expectedClusters := sets.NewString("allowed")
+	if !reflect.DeepEqual(selectedClusters, expectedClusters) {
+		t.Fatalf("Expected clusters %v, got %v", expectedClusters, selectedClusters)
+	}
+	if reason := decisions["not-allowed"].Reason; reason != status.ClusterNotAllowed {
+		t.Fatalf("Expected reason %q for %q, got %q", status.ClusterNotAllowed, "not-allowed", reason)
+	}
+	if _, ok := decisions["unselected"]; ok {
+		t.Fatalf("Expected no decision for %q", "unselected")
+	}
+}
+
+func TestExcludeClustersWithoutVolumeClaims(t *testing.T) {
+	volumeClaimClusters := map[string]sets.String{
+		"ns/data":  sets.NewString("cluster1", "cluster2"),
+		"ns/cache": sets.NewString("cluster2", "cluster3"),
+	}
+	getVolumeClaimClusters := func(qualifiedName util.QualifiedName) (sets.String, error) {
+		return volumeClaimClusters[qualifiedName.String()], nil
+	}
+
+	testCases := map[string]struct {
+		claimNames       []string
+		expectedClusters sets.String
+		expectedErr      bool
+	}{
+		"all clusters when no claims listed": {
+			expectedClusters: sets.NewString("cluster1", "cluster2", "cluster3"),
+		},
+		"clusters of the claim": {
+			claimNames:       []string{"data"},
+			expectedClusters: sets.NewString("cluster1", "cluster2"),
+		},
+		"clusters of all claims": {
+			claimNames:       []string{"data", "cache"},
+			expectedClusters: sets.NewString("cluster2"),
+		},
+		"error when claim not found": {
+			claimNames:  []string{"missing"},
+			expectedErr: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			selectedClusters := sets.NewString("cluster1", "cluster2", "cluster3")
+			decisions := placementDecisions{}
+			for clusterName := range selectedClusters {
+				decisions.record(clusterName, true, status.ClusterListed, "Listed in spec.placement.clusters")
+			}
+			err := excludeClustersWithoutVolumeClaims(selectedClusters, "ns", testCase.claimNames, getVolumeClaimClusters, decisions)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(selectedClusters, testCase.expectedClusters) {
+				t.Fatalf("Expected clusters %v, got %v", testCase.expectedClusters, selectedClusters)
+			}
+			for clusterName, decision := range decisions {
+				if !selectedClusters.Has(clusterName) && decision.Reason != status.VolumeClaimNotPlaced {
+					t.Fatalf("Expected reason %q for %q, got %q", status.VolumeClaimNotPlaced, clusterName, decision.Reason)
+				}
+			}
+		})
+	}
+}
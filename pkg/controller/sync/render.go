@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/mutator"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// RenderForCluster returns the resource that the sync controller would
+// propagate to the given cluster for the given federated resource. If
+// clusterObj is not nil, it is the resource already in the cluster
+// and the fields the sync controller retains on update are retained
+// from it. Override values referenced with valueFrom are retrieved
+// with the given function, and a FederatedTemplate referenced by the
+// federated resource is retrieved with getTemplate.
+//
+// Owner references and mutators that depend on the state of other
+// clusters are not applied, and warnings that would be recorded as
+// events for the federated resource are discarded.
+func RenderForCluster(typeConfig typeconfig.Interface, fedObject *unstructured.Unstructured, cluster *fedv1b1.KubeFedCluster,
+	clusterObj *unstructured.Unstructured, resolveValue util.OverrideValueFunc, getTemplate util.FederatedTemplateFunc,
+	propagatedMetadata *fedv1b1.PropagatedMetadataConfig) (*unstructured.Unstructured, error) {
+
+	targetIsNamespace := typeConfig.GetTargetType().Kind == util.NamespaceKind
+	targetName := util.NewQualifiedName(fedObject)
+	if targetIsNamespace {
+		targetName = util.QualifiedName{Name: fedObject.GetNamespace()}
+	}
+	placement, err := util.UnmarshalGenericPlacement(fedObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the placement")
+	}
+	template, _, err := util.ResolveTemplate(fedObject, getTemplate)
+	if err != nil {
+		return nil, err
+	}
+	mutators, err := mutator.NewPipeline(typeConfig.GetDispatchMutators())
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &federatedResource{
+		typeConfig:        typeConfig,
+		targetIsNamespace: targetIsNamespace,
+		targetName:        targetName,
+		placement:         placement,
+		federatedKind:     typeConfig.GetFederatedType().Kind,
+		federatedName:     util.NewQualifiedName(fedObject),
+		federatedResource: fedObject,
+		template:          template,
+		mutators:          mutators,
+		getCluster: func(name string) (*fedv1b1.KubeFedCluster, bool, error) {
+			return cluster, name == cluster.Name, nil
+		},
+		propagatedMetadata: propagatedMetadata,
+		// A recorder without a channel discards events.
+		eventRecorder: &record.FakeRecorder{},
+	}
+
+	obj, err := resource.ObjectForCluster(cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+	if clusterObj != nil {
+		err = dispatch.RetainClusterFields(resource.TargetKind(), obj, clusterObj, fedObject)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retain fields")
+		}
+	}
+	err = resource.ApplyOverrides(obj, cluster.Name, resolveValue)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	kfenable "sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+)
+
+func TestRenderForCluster(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType: fedv1b1.APIResource{
+				Version:    "v1",
+				Kind:       util.ConfigMapKind,
+				PluralName: "configmaps",
+				Scope:      apiextv1b1.NamespaceScoped,
+			},
+			FederatedType: fedv1b1.APIResource{
+				Group:      "types.kubefed.io",
+				Version:    "v1beta1",
+				Kind:       "FederatedConfigMap",
+				PluralName: "federatedconfigmaps",
+				Scope:      apiextv1b1.NamespaceScoped,
+			},
+		},
+	}
+	fedObject := &unstructured.Unstructured{}
+	yaml := `
+apiVersion: types.kubefed.io/v1beta1
+kind: FederatedConfigMap
+metadata:
+  name: settings
+  namespace: team-a
+spec:
+  template:
+    data:
+      level: info
+  placement:
+    clusters:
+    - name: prod-eu
+    nameTemplates:
+      prod-eu:
+        suffix: -eu
+  overrides:
+  - clusterName: prod-eu
+    clusterOverrides:
+    - path: /data/level
+      value: debug
+`
+	err := kfenable.DecodeYAML(strings.NewReader(yaml), fedObject)
+	if err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err)
+	}
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "prod-eu"}}
+
+	clusterObj := &unstructured.Unstructured{}
+	clusterObj.SetResourceVersion("42")
+
+	testCases := map[string]struct {
+		clusterObj              *unstructured.Unstructured
+		expectedResourceVersion string
+	}{
+		"Resource not in the cluster": {},
+		"Resource in the cluster": {
+			clusterObj:              clusterObj,
+			expectedResourceVersion: "42",
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj, err := RenderForCluster(typeConfig, fedObject, cluster, tc.clusterObj, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("An unexpected error occurred: %v", err)
+			}
+			if obj.GetKind() != util.ConfigMapKind || obj.GetAPIVersion() != "v1" {
+				t.Errorf("Expected a v1 ConfigMap, got %s %s", obj.GetAPIVersion(), obj.GetKind())
+			}
+			expectedName := util.QualifiedName{Namespace: "team-a", Name: "settings-eu"}
+			if name := util.NewQualifiedName(obj); name != expectedName {
+				t.Errorf("Expected name %q, got %q", expectedName, name)
+			}
+			expectedData := map[string]interface{}{"level": "debug"}
+			if data := obj.Object["data"]; !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected data %v, got %v", expectedData, data)
+			}
+			if !util.HasManagedLabel(obj) {
+				t.Errorf("Expected the managed label")
+			}
+			if obj.GetResourceVersion() != tc.expectedResourceVersion {
+				t.Errorf("Expected resource version %q, got %q", tc.expectedResourceVersion, obj.GetResourceVersion())
+			}
+		})
+	}
+}
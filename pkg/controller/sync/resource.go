@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -36,6 +37,8 @@ import (
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/mutator"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/version"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
@@ -50,28 +53,56 @@ type FederatedResource interface {
 	FederatedKind() string
 	UpdateVersions(selectedClusters []string, versionMap map[string]string) error
 	DeleteVersions()
-	ComputePlacement(clusters []*fedv1b1.KubeFedCluster) (selectedClusters sets.String, err error)
+	ComputePlacement(clusters []*fedv1b1.KubeFedCluster) (selectedClusters sets.String, decisions []status.GenericPlacementDecision, err error)
 	NamespaceNotFederated() bool
+	DeferredClusters(clusterNames sets.String) (deferredClusters sets.String, delay time.Duration)
+	ClusterOrder() []string
 }
 
 type federatedResource struct {
 	sync.RWMutex
 
-	limitedScope      bool
-	typeConfig        typeconfig.Interface
-	targetIsNamespace bool
-	targetName        util.QualifiedName
-	federatedKind     string
-	federatedName     util.QualifiedName
-	federatedResource *unstructured.Unstructured
-	versionManager    *version.VersionManager
-	overridesMap      util.OverridesMap
-	versionMap        map[string]string
-	namespace         *unstructured.Unstructured
-	fedNamespace      *unstructured.Unstructured
-	eventRecorder     record.EventRecorder
+	limitedScope           bool
+	typeConfig             typeconfig.Interface
+	targetIsNamespace      bool
+	targetName             util.QualifiedName
+	placement              *util.GenericPlacement
+	federatedKind          string
+	federatedName          util.QualifiedName
+	federatedResource      *unstructured.Unstructured
+	template               map[string]interface{}
+	versionManager         *version.VersionManager
+	overridesMap           util.OverridesMap
+	versionMap             map[string]string
+	namespace              *unstructured.Unstructured
+	fedNamespace           *unstructured.Unstructured
+	getClusterGroup        clusterGroupFunc
+	getVolumeClaimClusters volumeClaimClustersFunc
+	getMaintenanceWindows  maintenanceWindowsFunc
+	getClusterAllowlists   clusterAllowlistsFunc
+	mutators               *mutator.Pipeline
+	getCluster             clusterFunc
+	managedObjectExists    managedObjectFunc
+	propagatedMetadata     *fedv1b1.PropagatedMetadataConfig
+	instanceName           string
+	eventRecorder          record.EventRecorder
 }
 
+// clusterFunc returns the ready member cluster with the given name.
+type clusterFunc func(name string) (*fedv1b1.KubeFedCluster, bool, error)
+
+// managedObjectFunc returns whether the managed resource with the
+// given name exists in the named member cluster.
+type managedObjectFunc func(clusterName string, targetName util.QualifiedName) (bool, error)
+
+// maintenanceWindowsFunc returns the MaintenanceWindows of all member
+// clusters.
+type maintenanceWindowsFunc func() []*fedv1b1.MaintenanceWindow
+
+// clusterAllowlistsFunc returns the ClusterAllowlists of the control
+// plane.
+type clusterAllowlistsFunc func() []*fedv1b1.ClusterAllowlist
+
 func (r *federatedResource) FederatedName() util.QualifiedName {
 	return r.federatedName
 }
@@ -84,6 +115,14 @@ func (r *federatedResource) TargetName() util.QualifiedName {
 	return r.targetName
 }
 
+// TargetNameForCluster returns the name of the target resource in the
+// named cluster, which differs from the target name if the placement
+// of the resource maps the namespace or specifies a name template for
+// the cluster.
+func (r *federatedResource) TargetNameForCluster(clusterName string) util.QualifiedName {
+	return util.TargetNameForCluster(clusterName, r.targetName, r.targetIsNamespace, r.placement)
+}
+
 func (r *federatedResource) TargetKind() string {
 	return r.typeConfig.GetTargetType().Kind
 }
@@ -98,14 +137,37 @@ func (r *federatedResource) Object() *unstructured.Unstructured {
 }
 
 func (r *federatedResource) TemplateVersion() (string, error) {
-	obj := r.federatedResource
-	return GetTemplateHash(obj.Object)
+	// The template is hashed once resolved so that a change to a
+	// referenced FederatedTemplate results in updates.
+	if r.template == nil {
+		return "", nil
+	}
+	obj := &unstructured.Unstructured{Object: r.template}
+	return hashUnstructured(obj, "the template")
 }
 
 func (r *federatedResource) OverrideVersion() (string, error) {
 	// TODO(marun) Consider hashing overrides per cluster to minimize
 	// unnecessary updates.
-	return GetOverrideHash(r.federatedResource)
+	overrideVersion, err := GetOverrideHash(r.federatedResource)
+	if err != nil {
+		return "", err
+	}
+	// Changing the declared owners of the resource likewise needs to
+	// result in updates.
+	ownerVersion, err := GetOwnerReferencesHash(r.federatedResource)
+	if err != nil {
+		return "", err
+	}
+	if len(ownerVersion) > 0 {
+		overrideVersion = overrideVersion + "-" + ownerVersion
+	}
+	if r.mutators == nil || len(r.mutators.Version()) == 0 {
+		return overrideVersion, nil
+	}
+	// Changing the dispatch mutators of the type needs to result in
+	// the same updates as changing the overrides of the resource.
+	return overrideVersion + "-" + r.mutators.Version(), nil
 }
 
 func (r *federatedResource) VersionForCluster(clusterName string) (string, error) {
@@ -129,17 +191,64 @@ func (r *federatedResource) DeleteVersions() {
 	r.versionManager.Delete(r.federatedName)
 }
 
-func (r *federatedResource) ComputePlacement(clusters []*fedv1b1.KubeFedCluster) (sets.String, error) {
+func (r *federatedResource) ComputePlacement(clusters []*fedv1b1.KubeFedCluster) (sets.String, []status.GenericPlacementDecision, error) {
+	decisions := placementDecisions{}
+	var selectedClusters sets.String
+	var err error
 	if r.typeConfig.GetNamespaced() {
-		return computeNamespacedPlacement(r.federatedResource, r.fedNamespace, clusters, r.limitedScope)
+		selectedClusters, err = computeNamespacedPlacement(r.federatedResource, r.fedNamespace, clusters, r.limitedScope, r.getClusterGroup, decisions)
+	} else {
+		selectedClusters, err = computePlacement(r.federatedResource, clusters, r.getClusterGroup, decisions)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	err = excludeClustersWithoutVolumeClaims(selectedClusters, r.federatedName.Namespace, r.placement.VolumeClaimNames(), r.getVolumeClaimClusters, decisions)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = excludeCordonedClusters(selectedClusters, clusters, r.isManagedInCluster, decisions)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetType := r.typeConfig.GetTargetType()
+	excludeClustersMissingAPI(selectedClusters, clusters, schema.GroupVersion{Group: targetType.Group, Version: targetType.Version}.String(), decisions)
+	if r.getClusterAllowlists != nil {
+		allowedClusters := util.AllowedClusters(r.getClusterAllowlists(), r.federatedName.Namespace)
+		excludeClustersNotAllowed(selectedClusters, allowedClusters, decisions)
 	}
-	return computePlacement(r.federatedResource, clusters)
+	return selectedClusters, decisions.List(), nil
+}
+
+// isManagedInCluster returns whether the managed resource exists in
+// the named cluster.
+func (r *federatedResource) isManagedInCluster(clusterName string) (bool, error) {
+	return r.managedObjectExists(clusterName, r.TargetNameForCluster(clusterName))
 }
 
 func (r *federatedResource) NamespaceNotFederated() bool {
 	return r.typeConfig.GetNamespaced() && r.fedNamespace == nil
 }
 
+// DeferredClusters returns the names of the given clusters in which
+// updates of the resource are deferred because none of their
+// maintenance windows is open, and the time until the earliest of
+// those windows opens. Updates of critical resources are never
+// deferred.
+func (r *federatedResource) DeferredClusters(clusterNames sets.String) (sets.String, time.Duration) {
+	if r.getMaintenanceWindows == nil || util.IsCritical(r.federatedResource) {
+		return sets.String{}, 0
+	}
+	return util.DeferredClusters(r.getMaintenanceWindows(), clusterNames, time.Now())
+}
+
+// ClusterOrder returns the names of the clusters in the order in which
+// the resource is propagated to them, or nil if it is propagated to
+// all clusters concurrently.
+func (r *federatedResource) ClusterOrder() []string {
+	return r.placement.ClusterOrder()
+}
+
 func (r *federatedResource) IsNamespaceInHostCluster(clusterObj pkgruntime.Object) bool {
 	// TODO(marun) This comment should be added to the documentation
 	// and removed from this function (where it is no longer
@@ -165,14 +274,11 @@ func (r *federatedResource) IsNamespaceInHostCluster(clusterObj pkgruntime.Objec
 
 // TODO(marun) Marshall the template once per reconcile, not per-cluster
 func (r *federatedResource) ObjectForCluster(clusterName string) (*unstructured.Unstructured, error) {
-	templateBody, ok, err := unstructured.NestedMap(r.federatedResource.Object, util.SpecField, util.TemplateField)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error retrieving template body")
-	}
-	if !ok {
-		// Some resources (like namespaces) can be created from an
-		// empty template.
-		templateBody = make(map[string]interface{})
+	templateBody := make(map[string]interface{})
+	// Some resources (like namespaces) can be created from an empty
+	// template.
+	if r.template != nil {
+		templateBody = pkgruntime.DeepCopyJSON(r.template)
 	}
 	obj := &unstructured.Unstructured{Object: templateBody}
 
@@ -192,10 +298,10 @@ func (r *federatedResource) ObjectForCluster(clusterName string) (*unstructured.
 	// clusters.
 	//
 	// TODO(marun) this should be documented
-	obj.SetName(r.federatedResource.GetName())
+	targetName := r.TargetNameForCluster(clusterName)
+	obj.SetName(targetName.Name)
 	if !r.targetIsNamespace {
-		namespace := util.NamespaceForCluster(clusterName, r.federatedResource.GetNamespace())
-		obj.SetNamespace(namespace)
+		obj.SetNamespace(targetName.Namespace)
 	}
 	targetApiResource := r.typeConfig.GetTargetType()
 	obj.SetKind(targetApiResource.Kind)
@@ -209,15 +315,27 @@ func (r *federatedResource) ObjectForCluster(clusterName string) (*unstructured.
 	return obj, nil
 }
 
-// ApplyOverrides applies overrides for the named cluster to the given
-// object. The managed label is added afterwards to ensure labeling even if an
-// override was attempted.
-func (r *federatedResource) ApplyOverrides(obj *unstructured.Unstructured, clusterName string) error {
+// ApplyOverrides applies the dispatch mutators configured for the type
+// and then the overrides for the named cluster to the given object, so
+// that explicit overrides take precedence over mutators. Override
+// values referenced with valueFrom are retrieved from the member
+// cluster with the given function. The managed label and any
+// configured propagated metadata are added afterwards to ensure
+// labeling even if an override was attempted.
+func (r *federatedResource) ApplyOverrides(obj *unstructured.Unstructured, clusterName string, resolveValue util.OverrideValueFunc) error {
+	if err := r.applyMutators(obj, clusterName); err != nil {
+		return err
+	}
+
 	overrides, err := r.overridesForCluster(clusterName)
 	if err != nil {
 		return err
 	}
 	if overrides != nil {
+		overrides, err = util.ResolveOverrideValues(overrides, resolveValue)
+		if err != nil {
+			return err
+		}
 		if err := util.ApplyJsonPatch(obj, overrides); err != nil {
 			return err
 		}
@@ -227,10 +345,36 @@ func (r *federatedResource) ApplyOverrides(obj *unstructured.Unstructured, clust
 	// managed label.  The label is intended to be targeted by all the
 	// KubeFed controllers.
 	util.AddManagedLabel(obj)
+	util.AddInstanceLabel(obj, r.instanceName)
+
+	// A resource whose namespace or name is changed records the name
+	// it was propagated for so that the resource can be attributed to
+	// the federated resource.
+	if r.TargetNameForCluster(clusterName) != util.QualifiedNameForCluster(clusterName, r.targetName) {
+		util.SetSourceName(obj, r.targetName)
+	}
+
+	// The configured standard metadata is likewise added after
+	// overrides so that member cluster tooling can rely on it.
+	util.AddPropagatedMetadata(obj, r.federatedResource, clusterName, r.propagatedMetadata, time.Now())
 
 	return nil
 }
 
+func (r *federatedResource) applyMutators(obj *unstructured.Unstructured, clusterName string) error {
+	if r.mutators == nil {
+		return nil
+	}
+	cluster, ok, err := r.getCluster(clusterName)
+	if err != nil {
+		return errors.Wrapf(err, "Error retrieving cluster %q", clusterName)
+	}
+	if !ok {
+		return errors.Errorf("Cluster %q is not ready", clusterName)
+	}
+	return r.mutators.Mutate(obj, cluster)
+}
+
 // TODO(marun) Use an enumeration for errorCode.
 func (r *federatedResource) RecordError(errorCode string, err error) {
 	r.eventRecorder.Eventf(r.Object(), corev1.EventTypeWarning, errorCode, err.Error())
@@ -286,6 +430,25 @@ func GetOverrideHash(rawObj *unstructured.Unstructured) (string, error) {
 	return hashUnstructured(obj, "overrides")
 }
 
+// GetOwnerReferencesHash returns a hash of the owner references
+// declared by a federated resource, or an empty string if none are
+// declared.
+func GetOwnerReferencesHash(rawObj *unstructured.Unstructured) (string, error) {
+	refs, err := util.GetOwnerReferences(rawObj)
+	if err != nil {
+		return "", errors.Wrap(err, "Error retrieving owner references")
+	}
+	if len(refs) == 0 {
+		return "", nil
+	}
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"ownerReferences": refs,
+		},
+	}
+	return hashUnstructured(obj, "ownerReferences")
+}
+
 // TODO(marun) Investigate alternate ways of computing the hash of a field map.
 func hashUnstructured(obj *unstructured.Unstructured, description string) (string, error) {
 	jsonBytes, err := obj.MarshalJSON()
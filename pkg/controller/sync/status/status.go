@@ -18,6 +18,8 @@ package status
 
 import (
 	"encoding/json"
+	"reflect"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -25,8 +27,11 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
 )
 
 type PropagationStatus string
@@ -35,15 +40,49 @@ type AggregateReason string
 
 type ConditionType string
 
+type PlacementReason string
+
 const (
 	ClusterPropagationOK PropagationStatus = ""
 	WaitingForRemoval    PropagationStatus = "WaitingForRemoval"
+	// The cluster is an edge cluster that is not ready and the
+	// resource will be propagated when it reconnects.
+	PendingDelivery PropagationStatus = "PendingDelivery"
+	// The cluster is being backfilled and the resource will be
+	// created once the preceding phases of the backfill complete.
+	BackfillPending PropagationStatus = "BackfillPending"
+	// The resource in the cluster differs from the federated
+	// resource, but is not updated because the propagation mode of the
+	// type is CreateOnly.
+	Drifted PropagationStatus = "Drifted"
+	// The cluster is slow to respond and the resource will be
+	// propagated to it separately from the other clusters.
+	SlowClusterPending PropagationStatus = "SlowClusterPending"
+	// The resource in the cluster has been annotated to be managed
+	// locally and is neither updated nor removed.
+	LocallyManaged PropagationStatus = "LocallyManaged"
+	// The version of the resource in the cluster has been pinned and
+	// the resource is neither updated nor removed.
+	Pinned PropagationStatus = "Pinned"
+	// The resource in the cluster differs from the federated
+	// resource, but is not updated until a maintenance window of the
+	// cluster opens.
+	MaintenanceDeferred PropagationStatus = "MaintenanceDeferred"
+	// The resource in the cluster differs from the federated
+	// resource, but is not updated until the resource is healthy in
+	// the clusters preceding the cluster in the cluster order.
+	WaitingForHealthy PropagationStatus = "WaitingForHealthy"
 
 	// Cluster-specific errors
 	ClusterNotReady        PropagationStatus = "ClusterNotReady"
+	ClusterQuarantined     PropagationStatus = "ClusterQuarantined"
 	CachedRetrievalFailed  PropagationStatus = "CachedRetrievalFailed"
 	ComputeResourceFailed  PropagationStatus = "ComputeResourceFailed"
 	ApplyOverridesFailed   PropagationStatus = "ApplyOverridesFailed"
+	OwnerResolutionFailed  PropagationStatus = "OwnerResolutionFailed"
+	MissingDependency      PropagationStatus = "MissingDependency"
+	PropagationDenied      PropagationStatus = "PropagationDenied"
+	WebhookFailed          PropagationStatus = "WebhookFailed"
 	CreationFailed         PropagationStatus = "CreationFailed"
 	UpdateFailed           PropagationStatus = "UpdateFailed"
 	DeletionFailed         PropagationStatus = "DeletionFailed"
@@ -54,6 +93,8 @@ const (
 	VersionRetrievalFailed PropagationStatus = "VersionRetrievalFailed"
 	ClientRetrievalFailed  PropagationStatus = "ClientRetrievalFailed"
 	ManagedLabelFalse      PropagationStatus = "ManagedLabelFalse"
+	NameCollision          PropagationStatus = "NameCollision"
+	PrecedingClusterFailed PropagationStatus = "PrecedingClusterFailed"
 
 	// Operation timeout errors
 	CreationTimedOut     PropagationStatus = "CreationTimedOut"
@@ -66,13 +107,45 @@ const (
 	ComputePlacementFailed AggregateReason = "ComputePlacementFailed"
 	CheckClusters          AggregateReason = "CheckClusters"
 	NamespaceNotFederated  AggregateReason = "NamespaceNotFederated"
+	// The failures recorded for an earlier generation were cleared
+	// and the current generation has yet to be propagated.
+	PropagationPending AggregateReason = "PropagationPending"
+	// The resource is progressing in at least one cluster.
+	ClustersProgressing AggregateReason = "ClustersProgressing"
+	// The resource is degraded in at least one cluster.
+	ClustersDegraded AggregateReason = "ClustersDegraded"
 
 	PropagationConditionType ConditionType = "Propagation"
+	// ReadyConditionType reports whether the resource has been
+	// propagated and is healthy in all selected clusters.
+	ReadyConditionType ConditionType = "Ready"
+
+	// Reasons a cluster was selected or excluded by placement
+	ClusterListed                     PlacementReason = "ClusterListed"
+	ClusterNotListed                  PlacementReason = "ClusterNotListed"
+	ClusterNotRegistered              PlacementReason = "ClusterNotRegistered"
+	ClusterGroupMember                PlacementReason = "ClusterGroupMember"
+	NotClusterGroupMember             PlacementReason = "NotClusterGroupMember"
+	ClusterSelectorMatched            PlacementReason = "ClusterSelectorMatched"
+	ClusterSelectorNotMatched         PlacementReason = "ClusterSelectorNotMatched"
+	NoPlacement                       PlacementReason = "NoPlacement"
+	NamespaceNotPlaced                PlacementReason = "NamespaceNotPlaced"
+	NamespaceNotPropagated            PlacementReason = "NamespaceNotPropagated"
+	RequiredCRDsMissing               PlacementReason = "RequiredCRDsMissing"
+	APIMissing                        PlacementReason = "APIMissing"
+	VolumeClaimNotPlaced              PlacementReason = "VolumeClaimNotPlaced"
+	ClusterCordoned                   PlacementReason = "ClusterCordoned"
+	ClusterNotAllowed                 PlacementReason = "ClusterNotAllowed"
+	RequiredClusterSelectorMatched    PlacementReason = "RequiredClusterSelectorMatched"
+	RequiredClusterSelectorNotMatched PlacementReason = "RequiredClusterSelectorNotMatched"
 )
 
 type GenericClusterStatus struct {
 	Name   string            `json:"name"`
 	Status PropagationStatus `json:"status,omitempty"`
+	// The generation of the federated resource the status refers to.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
 }
 
 type GenericCondition struct {
@@ -92,10 +165,33 @@ type GenericCondition struct {
 	Reason AggregateReason `json:"reason,omitempty"`
 }
 
+// GenericPlacementDecision records why a cluster was selected or
+// excluded by the placement of a federated resource.
+type GenericPlacementDecision struct {
+	Name     string          `json:"name"`
+	Selected bool            `json:"selected"`
+	Reason   PlacementReason `json:"reason"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GenericClusterHealth records the interpreted health of the resource
+// in a cluster.
+type GenericClusterHealth struct {
+	Name   string        `json:"name"`
+	Health health.Health `json:"health"`
+}
+
 type GenericFederatedStatus struct {
-	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
-	Conditions         []*GenericCondition    `json:"conditions,omitempty"`
-	Clusters           []GenericClusterStatus `json:"clusters,omitempty"`
+	ObservedGeneration int64                      `json:"observedGeneration,omitempty"`
+	Conditions         []*GenericCondition        `json:"conditions,omitempty"`
+	Clusters           []GenericClusterStatus     `json:"clusters,omitempty"`
+	PlacementDecisions []GenericPlacementDecision `json:"placementDecisions,omitempty"`
+	ClusterHealth      []GenericClusterHealth     `json:"clusterHealth,omitempty"`
+	// The time until which the removal of resources from member
+	// clusters is held after the federated resource was deleted.
+	// +optional
+	DeletionHeldUntil string `json:"deletionHeldUntil,omitempty"`
 }
 
 type GenericFederatedResource struct {
@@ -110,6 +206,14 @@ type PropagationStatusMap map[string]PropagationStatus
 type CollectedPropagationStatus struct {
 	StatusMap        PropagationStatusMap
 	ResourcesUpdated bool
+	// PlacementDecisions will be written to status.placementDecisions
+	// if non-nil. A nil value removes any previously recorded
+	// decisions.
+	PlacementDecisions []GenericPlacementDecision
+	// ClusterHealth will be written to status.clusterHealth and rolled
+	// up into the Ready condition if non-nil. A nil value removes any
+	// previously recorded health and the Ready condition.
+	ClusterHealth []GenericClusterHealth
 }
 
 // SetFederatedStatus sets the conditions and clusters fields of the
@@ -144,6 +248,266 @@ func SetFederatedStatus(fedObject *unstructured.Unstructured, reason AggregateRe
 	return true, nil
 }
 
+// SetDeletionHeldUntil records in the status of the given federated
+// resource the time until which the removal of its resources from
+// member clusters is held. Returns a boolean indication of whether the
+// status has been changed.
+func SetDeletionHeldUntil(fedObject *unstructured.Unstructured, heldUntil time.Time) (bool, error) {
+	value := heldUntil.UTC().Format(time.RFC3339)
+	existing, _, err := unstructured.NestedString(fedObject.Object, util.StatusField, "deletionHeldUntil")
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to read the deletion hold")
+	}
+	if existing == value {
+		return false, nil
+	}
+	err = unstructured.SetNestedField(fedObject.Object, value, util.StatusField, "deletionHeldUntil")
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to set the deletion hold")
+	}
+	return true, nil
+}
+
+// JobCompletion aggregates the completion of the Jobs managed by a
+// FederatedJob whose deletion is propagated in the foreground.
+type JobCompletion struct {
+	Succeeded int64                  `json:"succeeded"`
+	Failed    int64                  `json:"failed"`
+	Clusters  []JobClusterCompletion `json:"clusters,omitempty"`
+}
+
+// JobClusterCompletion is the completion of the Job in a member
+// cluster.
+type JobClusterCompletion struct {
+	Name      string `json:"name"`
+	Succeeded int64  `json:"succeeded"`
+	Failed    int64  `json:"failed"`
+	Finished  bool   `json:"finished"`
+}
+
+// GetJobCompletion returns the completion of Jobs recorded in the
+// status of the given federated resource, or nil if none is recorded.
+func GetJobCompletion(fedObject *unstructured.Unstructured) (*JobCompletion, error) {
+	value, ok, err := unstructured.NestedMap(fedObject.Object, util.StatusField, "jobCompletion")
+	if err != nil || !ok {
+		return nil, errors.Wrapf(err, "Failed to read the job completion")
+	}
+	completion := &JobCompletion{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(value, completion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the job completion")
+	}
+	return completion, nil
+}
+
+// SetJobCompletion records the given completion of Jobs in the status
+// of the given federated resource. Returns a boolean indication of
+// whether the status has been changed.
+func SetJobCompletion(fedObject *unstructured.Unstructured, completion *JobCompletion) (bool, error) {
+	value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(completion)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to convert the job completion")
+	}
+	existing, _, err := unstructured.NestedFieldNoCopy(fedObject.Object, util.StatusField, "jobCompletion")
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to read the job completion")
+	}
+	if reflect.DeepEqual(existing, value) {
+		return false, nil
+	}
+	err = unstructured.SetNestedField(fedObject.Object, value, util.StatusField, "jobCompletion")
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to set the job completion")
+	}
+	return true, nil
+}
+
+// PropagatedClusterNames returns the names of the clusters that the
+// sync controller has recorded as successfully propagated to in the
+// status of the given federated resource. A resource that has drifted
+// from the federated resource or is managed locally was still
+// propagated to the cluster.
+func PropagatedClusterNames(fedObject *unstructured.Unstructured) (sets.String, error) {
+	resource := &GenericFederatedResource{}
+	err := util.UnstructuredToInterface(fedObject, resource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshall to generic resource")
+	}
+	clusterNames := sets.String{}
+	if resource.Status == nil {
+		return clusterNames, nil
+	}
+	for _, cluster := range resource.Status.Clusters {
+		if cluster.Status == ClusterPropagationOK || cluster.Status == Drifted || cluster.Status == LocallyManaged ||
+			cluster.Status == Pinned || cluster.Status == MaintenanceDeferred {
+			clusterNames.Insert(cluster.Name)
+		}
+	}
+	return clusterNames, nil
+}
+
+// RecordedClusterStatus returns the propagation status of each cluster
+// recorded in the status of the given federated resource. Nil is
+// returned if the recorded status does not reflect the current
+// generation of the resource.
+func RecordedClusterStatus(fedObject *unstructured.Unstructured) (PropagationStatusMap, error) {
+	resource := &GenericFederatedResource{}
+	err := util.UnstructuredToInterface(fedObject, resource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshall to generic resource")
+	}
+	if resource.Status == nil || resource.Status.ObservedGeneration != fedObject.GetGeneration() {
+		return nil, nil
+	}
+	statusMap := make(PropagationStatusMap)
+	for _, cluster := range resource.Status.Clusters {
+		statusMap[cluster.Name] = cluster.Status
+	}
+	return statusMap, nil
+}
+
+// PropagationState is the propagation state of a federated resource
+// recorded in its status, regardless of the generation it reflects.
+type PropagationState struct {
+	// Whether the Propagation condition is True.
+	Propagated bool
+	Clusters   PropagationStatusMap
+}
+
+// RecordedPropagationState returns the propagation state recorded in
+// the status of the given federated resource.
+func RecordedPropagationState(fedObject *unstructured.Unstructured) (*PropagationState, error) {
+	resource := &GenericFederatedResource{}
+	err := util.UnstructuredToInterface(fedObject, resource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshall to generic resource")
+	}
+	state := &PropagationState{Clusters: make(PropagationStatusMap)}
+	if resource.Status == nil {
+		return state, nil
+	}
+	for _, condition := range resource.Status.Conditions {
+		if condition.Type == PropagationConditionType {
+			state.Propagated = condition.Status == apiv1.ConditionTrue
+		}
+	}
+	for _, cluster := range resource.Status.Clusters {
+		state.Clusters[cluster.Name] = cluster.Status
+	}
+	return state, nil
+}
+
+// IsFailure returns whether the status indicates that propagation to
+// the cluster failed, rather than succeeded or is pending.
+func (s PropagationStatus) IsFailure() bool {
+	switch s {
+	case ClusterPropagationOK, WaitingForRemoval, PendingDelivery, BackfillPending, Drifted,
+		SlowClusterPending, LocallyManaged, Pinned, MaintenanceDeferred, WaitingForHealthy:
+		return false
+	}
+	return true
+}
+
+// IsPending returns whether the status indicates that an operation in
+// the cluster is pending.
+func (s PropagationStatus) IsPending() bool {
+	switch s {
+	case PendingDelivery, BackfillPending, SlowClusterPending, WaitingForHealthy:
+		return true
+	}
+	return false
+}
+
+// IsPropagated returns whether the sync controller has recorded in the
+// status of the given federated resource that its current generation
+// was successfully propagated to all selected clusters.
+func IsPropagated(fedObject *unstructured.Unstructured) (bool, error) {
+	resource := &GenericFederatedResource{}
+	err := util.UnstructuredToInterface(fedObject, resource)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to unmarshall to generic resource")
+	}
+	if resource.Status == nil || resource.Status.ObservedGeneration != fedObject.GetGeneration() {
+		return false, nil
+	}
+	for _, condition := range resource.Status.Conditions {
+		if condition.Type == PropagationConditionType {
+			return condition.Status == apiv1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
+// ClearStaleErrors removes from the status of the given federated
+// resource the failures recorded for clusters at an earlier generation
+// than its current one, so that failures of a template that has since
+// been replaced are not reported while the current generation has yet
+// to be propagated. A Propagation condition that only reported those
+// failures becomes Unknown. Returns a boolean indication of whether the
+// status has been changed.
+func ClearStaleErrors(fedObject *unstructured.Unstructured) (bool, error) {
+	resource := &GenericFederatedResource{}
+	err := util.UnstructuredToInterface(fedObject, resource)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to unmarshall to generic resource")
+	}
+	generation := fedObject.GetGeneration()
+	if resource.Status == nil || resource.Status.ObservedGeneration >= generation {
+		return false, nil
+	}
+	if !resource.Status.clearStaleErrors(generation) {
+		return false, nil
+	}
+
+	resourceJSON, err := json.Marshal(resource)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to marshall generic status to json")
+	}
+	resourceObj := &unstructured.Unstructured{}
+	err = resourceObj.UnmarshalJSON(resourceJSON)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to marshall generic resource json to unstructured")
+	}
+	fedObject.Object[util.StatusField] = resourceObj.Object[util.StatusField]
+	return true, nil
+}
+
+// clearStaleErrors removes the failures recorded for clusters at a
+// generation earlier than the given one. Returns a boolean indication
+// of whether the status has been changed.
+func (s *GenericFederatedStatus) clearStaleErrors(generation int64) bool {
+	clusters := []GenericClusterStatus{}
+	failing := false
+	for _, cluster := range s.Clusters {
+		if !cluster.Status.IsFailure() {
+			clusters = append(clusters, cluster)
+			continue
+		}
+		if cluster.Generation >= generation {
+			clusters = append(clusters, cluster)
+			failing = true
+		}
+	}
+	if len(clusters) == len(s.Clusters) {
+		return false
+	}
+	s.Clusters = clusters
+
+	if failing {
+		return true
+	}
+	for _, condition := range s.Conditions {
+		if condition.Type == PropagationConditionType && condition.Reason == CheckClusters {
+			now := time.Now().UTC().Format(time.RFC3339)
+			condition.Status = apiv1.ConditionUnknown
+			condition.Reason = PropagationPending
+			condition.LastTransitionTime = now
+			condition.LastUpdateTime = now
+		}
+	}
+	return true
+}
+
 // update ensures that the status reflects the given generation, reason
 // and collected status. Returns a boolean indication of whether the
 // status has been changed.
@@ -156,17 +520,24 @@ func (s *GenericFederatedStatus) update(generation int64, reason AggregateReason
 	}
 
 	// Identify whether one or more clusters could not be reconciled
-	// successfully.
+	// successfully. Drift of a resource that is intentionally not
+	// updated, a resource that is managed locally or pinned, and an
+	// update deferred to a maintenance window are reported without
+	// failing propagation.
 	if reason == AggregateSuccess {
 		for _, value := range collectedStatus.StatusMap {
-			if value != ClusterPropagationOK {
+			if value != ClusterPropagationOK && value != Drifted && value != LocallyManaged && value != Pinned &&
+				value != MaintenanceDeferred {
 				reason = CheckClusters
 				break
 			}
 		}
 	}
 
-	clustersChanged := s.setClusters(collectedStatus.StatusMap)
+	clustersChanged := s.clustersDiffers(collectedStatus.StatusMap)
+	clusterStatusUpdated := s.setClusters(collectedStatus.StatusMap, generation)
+
+	decisionsChanged := s.setPlacementDecisions(collectedStatus.PlacementDecisions)
 
 	// Indicate that changes were propagated if either status.clusters
 	// was changed or if existing resources were updated (which could
@@ -175,27 +546,43 @@ func (s *GenericFederatedStatus) update(generation int64, reason AggregateReason
 
 	propStatusUpdated := s.setPropagationCondition(reason, changesPropagated)
 
-	statusUpdated := generationUpdated || propStatusUpdated
+	healthChanged := s.setClusterHealth(collectedStatus.ClusterHealth)
+	readyUpdated := s.setReadyCondition(reason, collectedStatus)
+
+	statusUpdated := generationUpdated || clusterStatusUpdated || propStatusUpdated || decisionsChanged ||
+		healthChanged || readyUpdated
 	return statusUpdated
 }
 
 // setClusters sets the status.clusters slice from a propagation status
-// map. Returns a boolean indication of whether the status.clusters was
-// modified.
-func (s *GenericFederatedStatus) setClusters(statusMap PropagationStatusMap) bool {
-	if !s.clustersDiffers(statusMap) {
+// map recorded for the given generation. Returns a boolean indication
+// of whether the status.clusters was modified.
+func (s *GenericFederatedStatus) setClusters(statusMap PropagationStatusMap, generation int64) bool {
+	if !s.clustersDiffers(statusMap) && !s.clusterGenerationDiffers(generation) {
 		return false
 	}
 	s.Clusters = []GenericClusterStatus{}
 	for clusterName, status := range statusMap {
 		s.Clusters = append(s.Clusters, GenericClusterStatus{
-			Name:   clusterName,
-			Status: status,
+			Name:       clusterName,
+			Status:     status,
+			Generation: generation,
 		})
 	}
 	return true
 }
 
+// clusterGenerationDiffers checks whether any entry of
+// `status.clusters` refers to a generation other than the given one.
+func (s *GenericFederatedStatus) clusterGenerationDiffers(generation int64) bool {
+	for _, status := range s.Clusters {
+		if status.Generation != generation {
+			return true
+		}
+	}
+	return false
+}
+
 // clustersDiffers checks whether `status.clusters` differs from the
 // given status map.
 func (s *GenericFederatedStatus) clustersDiffers(statusMap PropagationStatusMap) bool {
@@ -210,10 +597,97 @@ func (s *GenericFederatedStatus) clustersDiffers(statusMap PropagationStatusMap)
 	return false
 }
 
+// setPlacementDecisions sets status.placementDecisions, ordered by
+// cluster name. Returns a boolean indication of whether
+// status.placementDecisions was modified.
+func (s *GenericFederatedStatus) setPlacementDecisions(decisions []GenericPlacementDecision) bool {
+	if len(decisions) == 0 {
+		decisions = nil
+	} else {
+		decisions = append([]GenericPlacementDecision{}, decisions...)
+		sort.Slice(decisions, func(i, j int) bool {
+			return decisions[i].Name < decisions[j].Name
+		})
+	}
+	if reflect.DeepEqual(s.PlacementDecisions, decisions) {
+		return false
+	}
+	s.PlacementDecisions = decisions
+	return true
+}
+
+// setClusterHealth sets status.clusterHealth, ordered by cluster name.
+// Returns a boolean indication of whether status.clusterHealth was
+// modified.
+func (s *GenericFederatedStatus) setClusterHealth(clusterHealth []GenericClusterHealth) bool {
+	if len(clusterHealth) == 0 {
+		clusterHealth = nil
+	} else {
+		clusterHealth = append([]GenericClusterHealth{}, clusterHealth...)
+		sort.Slice(clusterHealth, func(i, j int) bool {
+			return clusterHealth[i].Name < clusterHealth[j].Name
+		})
+	}
+	if reflect.DeepEqual(s.ClusterHealth, clusterHealth) {
+		return false
+	}
+	s.ClusterHealth = clusterHealth
+	return true
+}
+
+// setReadyCondition ensures that the Ready condition rolls up the
+// given propagation reason and the health of the resource in the
+// clusters it was propagated to, or that it is removed if the health
+// of the resource is not interpreted. Returns a boolean indication of
+// whether the condition was changed.
+func (s *GenericFederatedStatus) setReadyCondition(propagationReason AggregateReason, collectedStatus CollectedPropagationStatus) bool {
+	if collectedStatus.ClusterHealth == nil {
+		for i, condition := range s.Conditions {
+			if condition.Type == ReadyConditionType {
+				s.Conditions = append(s.Conditions[:i], s.Conditions[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+	return s.setCondition(ReadyConditionType, readyReason(propagationReason, collectedStatus), false)
+}
+
+// readyReason returns the reason of the Ready condition: the reason
+// propagation failed if it did, and otherwise the reason the resource
+// is not healthy in all of the clusters it was propagated to. A
+// cluster whose health has yet to be interpreted is progressing.
+func readyReason(propagationReason AggregateReason, collectedStatus CollectedPropagationStatus) AggregateReason {
+	if propagationReason != AggregateSuccess {
+		return propagationReason
+	}
+	clusterHealth := make(map[string]health.Health, len(collectedStatus.ClusterHealth))
+	for _, entry := range collectedStatus.ClusterHealth {
+		clusterHealth[entry.Name] = entry.Health
+	}
+	healthList := []health.Health{}
+	for clusterName := range collectedStatus.StatusMap {
+		healthList = append(healthList, clusterHealth[clusterName])
+	}
+	switch health.Rollup(healthList) {
+	case health.Degraded:
+		return ClustersDegraded
+	case health.Progressing:
+		return ClustersProgressing
+	}
+	return AggregateSuccess
+}
+
 // setPropagationCondition ensures that the Propagation condition is
 // updated to reflect the given reason.  The type of the condition is
 // derived from the reason (empty -> True, not empty -> False).
 func (s *GenericFederatedStatus) setPropagationCondition(reason AggregateReason, changesPropagated bool) bool {
+	return s.setCondition(PropagationConditionType, reason, changesPropagated)
+}
+
+// setCondition ensures that the condition of the given type is updated
+// to reflect the given reason, from which its status is derived.
+func (s *GenericFederatedStatus) setCondition(conditionType ConditionType, reason AggregateReason, changesPropagated bool) bool {
 	// Determine the appropriate status from the reason.
 	var newStatus apiv1.ConditionStatus
 	if reason == AggregateSuccess {
@@ -225,34 +699,34 @@ func (s *GenericFederatedStatus) setPropagationCondition(reason AggregateReason,
 	if s.Conditions == nil {
 		s.Conditions = []*GenericCondition{}
 	}
-	var propCondition *GenericCondition
+	var statusCondition *GenericCondition
 	for _, condition := range s.Conditions {
-		if condition.Type == PropagationConditionType {
-			propCondition = condition
+		if condition.Type == conditionType {
+			statusCondition = condition
 			break
 		}
 	}
 
-	newCondition := propCondition == nil
+	newCondition := statusCondition == nil
 	if newCondition {
-		propCondition = &GenericCondition{
-			Type: PropagationConditionType,
+		statusCondition = &GenericCondition{
+			Type: conditionType,
 		}
-		s.Conditions = append(s.Conditions, propCondition)
+		s.Conditions = append(s.Conditions, statusCondition)
 	}
 
 	now := time.Now().UTC().Format(time.RFC3339)
 
-	transition := newCondition || !(propCondition.Status == newStatus && propCondition.Reason == reason)
+	transition := newCondition || !(statusCondition.Status == newStatus && statusCondition.Reason == reason)
 	if transition {
-		propCondition.LastTransitionTime = now
-		propCondition.Status = newStatus
-		propCondition.Reason = reason
+		statusCondition.LastTransitionTime = now
+		statusCondition.Status = newStatus
+		statusCondition.Reason = reason
 	}
 
 	updateRequired := changesPropagated || transition
 	if updateRequired {
-		propCondition.LastUpdateTime = now
+		statusCondition.LastUpdateTime = now
 	}
 
 	return updateRequired
@@ -17,18 +17,26 @@ limitations under the License.
 package status
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
 )
 
 func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 	testCases := map[string]struct {
-		generation       int64
-		reason           AggregateReason
-		statusMap        PropagationStatusMap
-		resourcesUpdated bool
-		expectedChanged  bool
+		generation         int64
+		reason             AggregateReason
+		statusMap          PropagationStatusMap
+		resourcesUpdated   bool
+		placementDecisions []GenericPlacementDecision
+		expectedChanged    bool
 	}{
 		"No change in clusters indicates unchanged": {
 			statusMap: PropagationStatusMap{
@@ -53,6 +61,19 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 			generation:      1,
 			expectedChanged: true,
 		},
+		"Change in placement decisions indicates changed": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+			},
+			placementDecisions: []GenericPlacementDecision{
+				{
+					Name:     "cluster1",
+					Selected: true,
+					Reason:   ClusterListed,
+				},
+			},
+			expectedChanged: true,
+		},
 	}
 	for testName, tc := range testCases {
 		t.Run(testName, func(t *testing.T) {
@@ -70,8 +91,9 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 				},
 			}
 			collectedStatus := CollectedPropagationStatus{
-				StatusMap:        tc.statusMap,
-				ResourcesUpdated: tc.resourcesUpdated,
+				StatusMap:          tc.statusMap,
+				ResourcesUpdated:   tc.resourcesUpdated,
+				PlacementDecisions: tc.placementDecisions,
 			}
 			changed := propStatus.update(tc.generation, tc.reason, collectedStatus)
 			if tc.expectedChanged != changed {
@@ -80,3 +102,441 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestGenericPropagationStatusUpdateCondition(t *testing.T) {
+	testCases := map[string]struct {
+		statusMap      PropagationStatusMap
+		expectedStatus apiv1.ConditionStatus
+		expectedReason AggregateReason
+	}{
+		"Propagated clusters indicate success": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+			},
+			expectedStatus: apiv1.ConditionTrue,
+			expectedReason: AggregateSuccess,
+		},
+		"Drifted cluster indicates success": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+				"cluster2": Drifted,
+			},
+			expectedStatus: apiv1.ConditionTrue,
+			expectedReason: AggregateSuccess,
+		},
+		"Locally managed cluster indicates success": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+				"cluster2": LocallyManaged,
+			},
+			expectedStatus: apiv1.ConditionTrue,
+			expectedReason: AggregateSuccess,
+		},
+		"Failed cluster indicates failure": {
+			statusMap: PropagationStatusMap{
+				"cluster1": Drifted,
+				"cluster2": UpdateFailed,
+			},
+			expectedStatus: apiv1.ConditionFalse,
+			expectedReason: CheckClusters,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			propStatus := &GenericFederatedStatus{}
+			propStatus.update(0, AggregateSuccess, CollectedPropagationStatus{StatusMap: tc.statusMap})
+			if len(propStatus.Conditions) != 1 {
+				t.Fatalf("Expected 1 condition, got %d", len(propStatus.Conditions))
+			}
+			condition := propStatus.Conditions[0]
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason {
+				t.Fatalf("Expected condition status %q with reason %q, got %q with reason %q",
+					tc.expectedStatus, tc.expectedReason, condition.Status, condition.Reason)
+			}
+		})
+	}
+}
+
+func TestGenericPropagationStatusUpdateReadyCondition(t *testing.T) {
+	testCases := map[string]struct {
+		statusMap      PropagationStatusMap
+		clusterHealth  []GenericClusterHealth
+		expectedReady  bool
+		expectedStatus apiv1.ConditionStatus
+		expectedReason AggregateReason
+	}{
+		"Health not interpreted removes the condition": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+			},
+		},
+		"Healthy clusters indicate ready": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+				"cluster2": ClusterPropagationOK,
+			},
+			clusterHealth: []GenericClusterHealth{
+				{Name: "cluster1", Health: health.Healthy},
+				{Name: "cluster2", Health: health.Healthy},
+			},
+			expectedReady:  true,
+			expectedStatus: apiv1.ConditionTrue,
+			expectedReason: AggregateSuccess,
+		},
+		"Cluster without health indicates progressing": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+				"cluster2": ClusterPropagationOK,
+			},
+			clusterHealth: []GenericClusterHealth{
+				{Name: "cluster1", Health: health.Healthy},
+			},
+			expectedReady:  true,
+			expectedStatus: apiv1.ConditionFalse,
+			expectedReason: ClustersProgressing,
+		},
+		"Degraded cluster indicates degraded": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+				"cluster2": ClusterPropagationOK,
+			},
+			clusterHealth: []GenericClusterHealth{
+				{Name: "cluster1", Health: health.Progressing},
+				{Name: "cluster2", Health: health.Degraded},
+			},
+			expectedReady:  true,
+			expectedStatus: apiv1.ConditionFalse,
+			expectedReason: ClustersDegraded,
+		},
+		"Failed propagation indicates not ready": {
+			statusMap: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+				"cluster2": WaitingForHealthy,
+			},
+			clusterHealth: []GenericClusterHealth{
+				{Name: "cluster1", Health: health.Healthy},
+				{Name: "cluster2", Health: health.Healthy},
+			},
+			expectedReady:  true,
+			expectedStatus: apiv1.ConditionFalse,
+			expectedReason: CheckClusters,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			propStatus := &GenericFederatedStatus{
+				Conditions: []*GenericCondition{
+					{
+						Type:   ReadyConditionType,
+						Status: apiv1.ConditionUnknown,
+					},
+				},
+			}
+			propStatus.update(0, AggregateSuccess, CollectedPropagationStatus{StatusMap: tc.statusMap, ClusterHealth: tc.clusterHealth})
+			var ready *GenericCondition
+			for _, condition := range propStatus.Conditions {
+				if condition.Type == ReadyConditionType {
+					ready = condition
+				}
+			}
+			if !tc.expectedReady {
+				if ready != nil {
+					t.Fatalf("Expected no Ready condition, got %v", *ready)
+				}
+				return
+			}
+			if ready == nil {
+				t.Fatalf("Expected a Ready condition")
+			}
+			if ready.Status != tc.expectedStatus || ready.Reason != tc.expectedReason {
+				t.Fatalf("Expected Ready status %q with reason %q, got %q with reason %q",
+					tc.expectedStatus, tc.expectedReason, ready.Status, ready.Reason)
+			}
+		})
+	}
+}
+
+func TestClearStaleErrors(t *testing.T) {
+	testCases := map[string]struct {
+		observedGeneration int64
+		clusters           []GenericClusterStatus
+		reason             AggregateReason
+		expectedChanged    bool
+		expectedClusters   []string
+		expectedStatus     apiv1.ConditionStatus
+		expectedReason     AggregateReason
+	}{
+		"Failures of an earlier generation are cleared": {
+			observedGeneration: 1,
+			clusters: []GenericClusterStatus{
+				{Name: "cluster1", Generation: 1},
+				{Name: "cluster2", Status: UpdateFailed, Generation: 1},
+			},
+			reason:           CheckClusters,
+			expectedChanged:  true,
+			expectedClusters: []string{"cluster1"},
+			expectedStatus:   apiv1.ConditionUnknown,
+			expectedReason:   PropagationPending,
+		},
+		"Pending statuses of an earlier generation are retained": {
+			observedGeneration: 1,
+			clusters: []GenericClusterStatus{
+				{Name: "cluster1", Status: PendingDelivery, Generation: 1},
+			},
+			reason:           AggregateSuccess,
+			expectedClusters: []string{"cluster1"},
+			expectedStatus:   apiv1.ConditionTrue,
+			expectedReason:   AggregateSuccess,
+		},
+		"Failures of the current generation are retained": {
+			observedGeneration: 2,
+			clusters: []GenericClusterStatus{
+				{Name: "cluster2", Status: UpdateFailed, Generation: 2},
+			},
+			reason:           CheckClusters,
+			expectedClusters: []string{"cluster2"},
+			expectedStatus:   apiv1.ConditionFalse,
+			expectedReason:   CheckClusters,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			conditionStatus := apiv1.ConditionTrue
+			if tc.reason != AggregateSuccess {
+				conditionStatus = apiv1.ConditionFalse
+			}
+			resource := &GenericFederatedResource{
+				Status: &GenericFederatedStatus{
+					ObservedGeneration: tc.observedGeneration,
+					Clusters:           tc.clusters,
+					Conditions: []*GenericCondition{
+						{
+							Type:   PropagationConditionType,
+							Status: conditionStatus,
+							Reason: tc.reason,
+						},
+					},
+				},
+			}
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resource)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			fedObject := &unstructured.Unstructured{Object: content}
+			fedObject.SetGeneration(2)
+
+			changed, err := ClearStaleErrors(fedObject)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if changed != tc.expectedChanged {
+				t.Fatalf("Expected changed to be %v, got %v", tc.expectedChanged, changed)
+			}
+			cleared := &GenericFederatedResource{}
+			if err := util.UnstructuredToInterface(fedObject, cleared); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			clusterNames := []string{}
+			for _, cluster := range cleared.Status.Clusters {
+				clusterNames = append(clusterNames, cluster.Name)
+			}
+			if !reflect.DeepEqual(clusterNames, tc.expectedClusters) {
+				t.Errorf("Expected clusters %v, got %v", tc.expectedClusters, clusterNames)
+			}
+			condition := cleared.Status.Conditions[0]
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason {
+				t.Errorf("Expected condition status %q with reason %q, got %q with reason %q",
+					tc.expectedStatus, tc.expectedReason, condition.Status, condition.Reason)
+			}
+		})
+	}
+}
+
+func TestSetDeletionHeldUntil(t *testing.T) {
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	heldUntil := time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	changed, err := SetDeletionHeldUntil(fedObject, heldUntil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !changed {
+		t.Fatalf("Expected the status to be changed")
+	}
+	value, _, _ := unstructured.NestedString(fedObject.Object, "status", "deletionHeldUntil")
+	if value != "2020-03-01T12:00:00Z" {
+		t.Fatalf("Expected deletionHeldUntil to be %q, got %q", "2020-03-01T12:00:00Z", value)
+	}
+
+	changed, err = SetDeletionHeldUntil(fedObject, heldUntil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if changed {
+		t.Fatalf("Expected the status to be unchanged")
+	}
+}
+
+func TestPropagatedClusterNames(t *testing.T) {
+	testCases := map[string]struct {
+		status           map[string]interface{}
+		expectedClusters []string
+	}{
+		"No status indicates no clusters": {
+			expectedClusters: []string{},
+		},
+		"Only successfully propagated clusters are returned": {
+			status: map[string]interface{}{
+				"clusters": []interface{}{
+					map[string]interface{}{
+						"name": "cluster1",
+					},
+					map[string]interface{}{
+						"name":   "cluster2",
+						"status": string(CreationFailed),
+					},
+					map[string]interface{}{
+						"name": "cluster3",
+					},
+					map[string]interface{}{
+						"name":   "cluster4",
+						"status": string(Drifted),
+					},
+					map[string]interface{}{
+						"name":   "cluster5",
+						"status": string(LocallyManaged),
+					},
+				},
+			},
+			expectedClusters: []string{"cluster1", "cluster3", "cluster4", "cluster5"},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tc.status != nil {
+				fedObject.Object["status"] = tc.status
+			}
+			clusterNames, err := PropagatedClusterNames(fedObject)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedClusters, clusterNames.List()) {
+				t.Fatalf("Expected clusters %v, got %v", tc.expectedClusters, clusterNames.List())
+			}
+		})
+	}
+}
+
+func TestIsPropagated(t *testing.T) {
+	propagationCondition := func(status apiv1.ConditionStatus) []interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				"type":   string(PropagationConditionType),
+				"status": string(status),
+			},
+		}
+	}
+	testCases := map[string]struct {
+		generation         int64
+		status             map[string]interface{}
+		expectedPropagated bool
+	}{
+		"No status indicates not propagated": {
+			generation: 1,
+		},
+		"Propagation condition true for the current generation indicates propagated": {
+			generation: 2,
+			status: map[string]interface{}{
+				"observedGeneration": int64(2),
+				"conditions":         propagationCondition(apiv1.ConditionTrue),
+			},
+			expectedPropagated: true,
+		},
+		"Propagation condition true for a previous generation indicates not propagated": {
+			generation: 2,
+			status: map[string]interface{}{
+				"observedGeneration": int64(1),
+				"conditions":         propagationCondition(apiv1.ConditionTrue),
+			},
+		},
+		"Propagation condition false indicates not propagated": {
+			generation: 1,
+			status: map[string]interface{}{
+				"observedGeneration": int64(1),
+				"conditions":         propagationCondition(apiv1.ConditionFalse),
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			fedObject.SetGeneration(tc.generation)
+			if tc.status != nil {
+				fedObject.Object["status"] = tc.status
+			}
+			propagated, err := IsPropagated(fedObject)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if propagated != tc.expectedPropagated {
+				t.Fatalf("Expected propagated %v, got %v", tc.expectedPropagated, propagated)
+			}
+		})
+	}
+}
+
+func TestRecordedClusterStatus(t *testing.T) {
+	clusters := []interface{}{
+		map[string]interface{}{
+			"name": "cluster1",
+		},
+		map[string]interface{}{
+			"name":   "cluster2",
+			"status": string(SlowClusterPending),
+		},
+	}
+	testCases := map[string]struct {
+		generation     int64
+		status         map[string]interface{}
+		expectedStatus PropagationStatusMap
+	}{
+		"No status indicates no recorded status": {
+			generation: 1,
+		},
+		"Status for the current generation is returned": {
+			generation: 2,
+			status: map[string]interface{}{
+				"observedGeneration": int64(2),
+				"clusters":           clusters,
+			},
+			expectedStatus: PropagationStatusMap{
+				"cluster1": ClusterPropagationOK,
+				"cluster2": SlowClusterPending,
+			},
+		},
+		"Status for a previous generation indicates no recorded status": {
+			generation: 2,
+			status: map[string]interface{}{
+				"observedGeneration": int64(1),
+				"clusters":           clusters,
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			fedObject.SetGeneration(tc.generation)
+			if tc.status != nil {
+				fedObject.Object["status"] = tc.status
+			}
+			statusMap, err := RecordedClusterStatus(fedObject)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedStatus, statusMap) {
+				t.Fatalf("Expected status %v, got %v", tc.expectedStatus, statusMap)
+			}
+		})
+	}
+}
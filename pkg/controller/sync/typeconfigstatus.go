@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// typeConfigStatusPeriod is how often the sync controller records the
+// state of the resources of its type in the status of the
+// FederatedTypeConfig.
+const typeConfigStatusPeriod = 30 * time.Second
+
+// runTypeConfigStatusUpdates periodically records the state of the
+// resources of the type in the status of the FederatedTypeConfig until
+// the stop channel is closed.
+func (s *KubeFedSyncController) runTypeConfigStatusUpdates(stopChan <-chan struct{}) {
+	go wait.Until(s.updateTypeConfigStatus, typeConfigStatusPeriod, stopChan)
+}
+
+// updateTypeConfigStatus records the number of federated resources of
+// the type, how many of them are propagated and whether the informer
+// for the target type has synced in each ready cluster.
+func (s *KubeFedSyncController) updateTypeConfigStatus() {
+	if !s.isSynced() {
+		return
+	}
+
+	var resourceCount, propagatedCount int64
+	s.fedAccessor.VisitFederatedResources(func(obj interface{}) {
+		fedObject, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return
+		}
+		resourceCount++
+		propagated, err := status.IsPropagated(fedObject)
+		if err != nil {
+			s.logger.V(4).Info("Unable to determine whether resource is propagated", "resource", util.NewQualifiedName(fedObject), "error", err.Error())
+			return
+		}
+		if propagated {
+			propagatedCount++
+		}
+	})
+	clusters := typeConfigClusterStatus(s.informer.SyncStatus())
+
+	objectMeta := s.typeConfig.GetObjectMeta()
+	typeConfig := &fedv1b1.FederatedTypeConfig{}
+	err := s.hostClusterClient.Get(context.TODO(), typeConfig, objectMeta.Namespace, objectMeta.Name)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to retrieve FederatedTypeConfig %q to update its status", objectMeta.Name))
+		return
+	}
+
+	typeConfigStatus := &typeConfig.Status
+	if typeConfigStatus.ResourceCount != nil && *typeConfigStatus.ResourceCount == resourceCount &&
+		typeConfigStatus.PropagatedResourceCount != nil && *typeConfigStatus.PropagatedResourceCount == propagatedCount &&
+		reflect.DeepEqual(typeConfigStatus.Clusters, clusters) {
+		return
+	}
+	typeConfigStatus.ResourceCount = &resourceCount
+	typeConfigStatus.PropagatedResourceCount = &propagatedCount
+	typeConfigStatus.Clusters = clusters
+	err = s.hostClusterClient.UpdateStatus(context.TODO(), typeConfig)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to update status of FederatedTypeConfig %q", objectMeta.Name))
+	}
+}
+
+// typeConfigClusterStatus returns the per-cluster status to record for
+// the given informer sync status, sorted by cluster name.
+func typeConfigClusterStatus(syncStatus util.InformerSyncStatus) []fedv1b1.FederatedTypeConfigClusterStatus {
+	if len(syncStatus.Clusters) == 0 {
+		return nil
+	}
+	clusters := make([]fedv1b1.FederatedTypeConfigClusterStatus, 0, len(syncStatus.Clusters))
+	for clusterName, synced := range syncStatus.Clusters {
+		clusters = append(clusters, fedv1b1.FederatedTypeConfigClusterStatus{
+			Name:   clusterName,
+			Synced: synced,
+		})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters
+}
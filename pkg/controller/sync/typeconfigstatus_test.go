@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"reflect"
+	"testing"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestTypeConfigClusterStatus(t *testing.T) {
+	syncStatus := util.InformerSyncStatus{
+		Clusters: map[string]bool{
+			"cluster2": false,
+			"cluster1": true,
+		},
+	}
+	expected := []fedv1b1.FederatedTypeConfigClusterStatus{
+		{Name: "cluster1", Synced: true},
+		{Name: "cluster2", Synced: false},
+	}
+	if clusters := typeConfigClusterStatus(syncStatus); !reflect.DeepEqual(clusters, expected) {
+		t.Fatalf("Expected %v, got %v", expected, clusters)
+	}
+
+	if clusters := typeConfigClusterStatus(util.InformerSyncStatus{}); clusters != nil {
+		t.Fatalf("Expected no clusters, got %v", clusters)
+	}
+}
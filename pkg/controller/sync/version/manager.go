@@ -44,6 +44,8 @@ import (
 // implement to allow versions to be tracked by the VersionManager.
 type VersionedResource interface {
 	FederatedName() util.QualifiedName
+	TargetName() util.QualifiedName
+	TargetNameForCluster(clusterName string) util.QualifiedName
 	Object() *unstructured.Unstructured
 	TemplateVersion() (string, error)
 	OverrideVersion() (string, error)
@@ -128,6 +130,17 @@ func (m *VersionManager) Get(resource VersionedResource) (map[string]string, err
 	if templateVersion == status.TemplateVersion &&
 		overrideVersion == status.OverrideVersion {
 		for _, versions := range status.ClusterVersions {
+			// A version recorded for a resource of a different
+			// name does not apply to the resource of the current
+			// name.
+			if versions.TargetName != recordedTargetName(resource, versions.ClusterName) {
+				continue
+			}
+			// A pinned version may have been produced for an
+			// earlier version of the resource.
+			if versions.Pinned {
+				continue
+			}
 			versionMap[versions.ClusterName] = versions.Version
 		}
 	}
@@ -163,10 +176,16 @@ func (m *VersionManager) Update(resource VersionedResource,
 		if oldStatus.TemplateVersion == templateVersion && oldStatus.OverrideVersion == overrideVersion {
 			clusterVersions = oldStatus.ClusterVersions
 		}
-		clusterVersions = updateClusterVersions(clusterVersions, versionMap, selectedClusters)
+		clusterVersions = updateClusterVersions(clusterVersions, versionMap, selectedClusters, func(clusterName string) string {
+			return recordedTargetName(resource, clusterName)
+		})
+		clusterVersions = pinClusterVersions(clusterVersions, oldStatus.ClusterVersions, util.PinnedClusters(resource.Object()))
 	} else {
 		clusterVersions = VersionMapToClusterVersions(versionMap)
 	}
+	for i := range clusterVersions {
+		clusterVersions[i].TargetName = recordedTargetName(resource, clusterVersions[i].ClusterName)
+	}
 
 	status := &fedv1a1.PropagatedVersionStatus{
 		TemplateVersion: templateVersion,
@@ -181,7 +200,7 @@ func (m *VersionManager) Update(resource VersionedResource,
 	}
 
 	if obj == nil {
-		ownerReference := ownerReferenceForUnstructured(resource.Object())
+		ownerReference := OwnerReferenceForUnstructured(resource.Object())
 		obj = m.adapter.NewVersion(qualifiedName, ownerReference, status)
 		m.versions[key] = obj
 	} else {
@@ -419,7 +438,9 @@ func setResourceVersion(obj pkgruntime.Object, resourceVersion string) error {
 	return nil
 }
 
-func ownerReferenceForUnstructured(obj *unstructured.Unstructured) metav1.OwnerReference {
+// OwnerReferenceForUnstructured returns the owner reference that a
+// version resource holds to the given versioned resource.
+func OwnerReferenceForUnstructured(obj *unstructured.Unstructured) metav1.OwnerReference {
 	gvk := obj.GetObjectKind().GroupVersionKind()
 	return metav1.OwnerReference{
 		APIVersion: gvk.GroupVersion().String(),
@@ -429,15 +450,30 @@ func ownerReferenceForUnstructured(obj *unstructured.Unstructured) metav1.OwnerR
 	}
 }
 
+// recordedTargetName returns the name of the target resource in the
+// named cluster to record with its version, which is empty unless the
+// name differs from the name of the versioned resource.
+func recordedTargetName(resource VersionedResource, clusterName string) string {
+	targetName := resource.TargetNameForCluster(clusterName)
+	if targetName == util.QualifiedNameForCluster(clusterName, resource.TargetName()) {
+		return ""
+	}
+	return targetName.String()
+}
+
 func updateClusterVersions(oldVersions []fedv1a1.ClusterObjectVersion,
-	newVersions map[string]string, selectedClusters []string) []fedv1a1.ClusterObjectVersion {
+	newVersions map[string]string, selectedClusters []string, targetNames func(clusterName string) string) []fedv1a1.ClusterObjectVersion {
 
-	// Retain versions for selected clusters that were not changed
+	// Retain versions for selected clusters that were not changed and
+	// whose resources were not renamed
 	selectedClusterSet := sets.NewString(selectedClusters...)
 	for _, oldVersion := range oldVersions {
 		if !selectedClusterSet.Has(oldVersion.ClusterName) {
 			continue
 		}
+		if oldVersion.TargetName != targetNames(oldVersion.ClusterName) {
+			continue
+		}
 		if _, ok := newVersions[oldVersion.ClusterName]; !ok {
 			newVersions[oldVersion.ClusterName] = oldVersion.Version
 		}
@@ -446,6 +482,44 @@ func updateClusterVersions(oldVersions []fedv1a1.ClusterObjectVersion,
 	return VersionMapToClusterVersions(newVersions)
 }
 
+// pinClusterVersions returns the given cluster versions with the
+// versions of pinned clusters retained from the old versions, even if
+// the resource has since changed. The retained version of a cluster
+// that is no longer pinned is removed unless a new version has been
+// produced, so that the resource is updated in the cluster.
+func pinClusterVersions(clusterVersions, oldVersions []fedv1a1.ClusterObjectVersion, pinned sets.String) []fedv1a1.ClusterObjectVersion {
+	oldPinned := make(map[string]fedv1a1.ClusterObjectVersion)
+	for _, oldVersion := range oldVersions {
+		if pinned.Has(oldVersion.ClusterName) || oldVersion.Pinned {
+			oldPinned[oldVersion.ClusterName] = oldVersion
+		}
+	}
+
+	pinnedVersions := []fedv1a1.ClusterObjectVersion{}
+	for _, clusterVersion := range clusterVersions {
+		oldVersion, ok := oldPinned[clusterVersion.ClusterName]
+		if !ok {
+			pinnedVersions = append(pinnedVersions, clusterVersion)
+			continue
+		}
+		delete(oldPinned, clusterVersion.ClusterName)
+		if pinned.Has(clusterVersion.ClusterName) {
+			oldVersion.Pinned = true
+			pinnedVersions = append(pinnedVersions, oldVersion)
+		} else if !oldVersion.Pinned || clusterVersion.Version != oldVersion.Version {
+			pinnedVersions = append(pinnedVersions, clusterVersion)
+		}
+	}
+	for clusterName, oldVersion := range oldPinned {
+		if pinned.Has(clusterName) {
+			oldVersion.Pinned = true
+			pinnedVersions = append(pinnedVersions, oldVersion)
+		}
+	}
+	util.SortClusterVersions(pinnedVersions)
+	return pinnedVersions
+}
+
 func VersionMapToClusterVersions(versionMap map[string]string) []fedv1a1.ClusterObjectVersion {
 	clusterVersions := []fedv1a1.ClusterObjectVersion{}
 	for clusterName, version := range versionMap {
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1a1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+)
+
+func TestPinClusterVersions(t *testing.T) {
+	testCases := map[string]struct {
+		clusterVersions []fedv1a1.ClusterObjectVersion
+		oldVersions     []fedv1a1.ClusterObjectVersion
+		pinned          sets.String
+		expected        []fedv1a1.ClusterObjectVersion
+	}{
+		"Version of a pinned cluster is retained after the resource changes": {
+			clusterVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster1", Version: "gen:3"},
+			},
+			oldVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster1", Version: "gen:2"},
+				{ClusterName: "cluster2", Version: "gen:2"},
+			},
+			pinned: sets.NewString("cluster2"),
+			expected: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster1", Version: "gen:3"},
+				{ClusterName: "cluster2", Version: "gen:2", Pinned: true},
+			},
+		},
+		"Retained version of an unpinned cluster is removed": {
+			clusterVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster2", Version: "gen:2"},
+			},
+			oldVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster2", Version: "gen:2", Pinned: true},
+			},
+			pinned:   sets.NewString(),
+			expected: []fedv1a1.ClusterObjectVersion{},
+		},
+		"New version of an unpinned cluster is recorded": {
+			clusterVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster2", Version: "gen:3"},
+			},
+			oldVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster2", Version: "gen:2", Pinned: true},
+			},
+			pinned: sets.NewString(),
+			expected: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster2", Version: "gen:3"},
+			},
+		},
+		"Versions are unchanged without pinned clusters": {
+			clusterVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster1", Version: "gen:3"},
+			},
+			oldVersions: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster1", Version: "gen:2"},
+			},
+			pinned: sets.NewString(),
+			expected: []fedv1a1.ClusterObjectVersion{
+				{ClusterName: "cluster1", Version: "gen:3"},
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			versions := pinClusterVersions(tc.clusterVersions, tc.oldVersions, tc.pinned)
+			if !reflect.DeepEqual(versions, tc.expected) {
+				t.Errorf("Expected versions %v, got %v", tc.expected, versions)
+			}
+		})
+	}
+}
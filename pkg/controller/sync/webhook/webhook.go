@@ -0,0 +1,260 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	ReviewAPIVersion = "core.kubefed.io/v1beta1"
+	ReviewKind       = "PropagationReview"
+
+	defaultTimeout = 10 * time.Second
+
+	// maxResponseSize bounds the size of a review read from a
+	// webhook.
+	maxResponseSize = 3 * 1024 * 1024
+)
+
+// PropagationReview is exchanged with a propagation webhook. The
+// request is posted to the webhook, which responds with the same
+// review with the response set.
+type PropagationReview struct {
+	APIVersion string                     `json:"apiVersion"`
+	Kind       string                     `json:"kind"`
+	Request    *PropagationReviewRequest  `json:"request,omitempty"`
+	Response   *PropagationReviewResponse `json:"response,omitempty"`
+}
+
+// PropagationReviewRequest describes a resource about to be created or
+// updated in a member cluster.
+type PropagationReviewRequest struct {
+	// The operation about to be performed, create or update.
+	Operation string `json:"operation"`
+	// The name and labels of the member cluster.
+	ClusterName   string            `json:"clusterName"`
+	ClusterLabels map[string]string `json:"clusterLabels,omitempty"`
+	// The resource as it will be created or updated in the cluster.
+	Object *unstructured.Unstructured `json:"object"`
+}
+
+// PropagationReviewResponse is the decision of a propagation webhook.
+type PropagationReviewResponse struct {
+	// Whether the resource may be propagated to the cluster.
+	Allowed bool `json:"allowed"`
+	// The reason the resource was denied.
+	Message string `json:"message,omitempty"`
+	// JSON patch operations applied to the resource if it is
+	// allowed, in the format of overrides.
+	Patch util.ClusterOverrides `json:"patch,omitempty"`
+}
+
+// ClusterFunc returns the ready member cluster with the given name.
+type ClusterFunc func(name string) (*fedv1b1.KubeFedCluster, bool, error)
+
+// DeniedError indicates that a webhook denied the propagation of a
+// resource to a cluster.
+type DeniedError struct {
+	Webhook string
+	Message string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("denied by propagation webhook %q: %s", e.Webhook, e.Message)
+}
+
+// IsDenied returns whether the given error indicates that a webhook
+// denied propagation.
+func IsDenied(err error) bool {
+	_, ok := errors.Cause(err).(*DeniedError)
+	return ok
+}
+
+// Reviewer calls the propagation webhooks configured for a type with
+// the resources about to be propagated to member clusters.
+type Reviewer struct {
+	webhooks   []*propagationWebhook
+	getCluster ClusterFunc
+}
+
+type propagationWebhook struct {
+	name          string
+	url           string
+	selector      labels.Selector
+	failurePolicy fedv1b1.PropagationWebhookFailurePolicy
+	client        *http.Client
+}
+
+// NewReviewer returns the reviewer for the given webhook
+// configuration, or nil if no webhooks are configured.
+func NewReviewer(configs []fedv1b1.PropagationWebhook, getCluster ClusterFunc) (*Reviewer, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	r := &Reviewer{getCluster: getCluster}
+	for i := range configs {
+		config := &configs[i]
+		w, err := newPropagationWebhook(config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid propagation webhook %q", config.Name)
+		}
+		r.webhooks = append(r.webhooks, w)
+	}
+	return r, nil
+}
+
+func newPropagationWebhook(config *fedv1b1.PropagationWebhook) (*propagationWebhook, error) {
+	selector := labels.Everything()
+	if config.ClusterSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(config.ClusterSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid cluster selector")
+		}
+	}
+
+	tlsConfig := &tls.Config{}
+	if len(config.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(config.CABundle) {
+			return nil, errors.New("no certificates found in caBundle")
+		}
+	}
+	timeout := defaultTimeout
+	if config.TimeoutSeconds != nil {
+		timeout = time.Duration(*config.TimeoutSeconds) * time.Second
+	}
+
+	failurePolicy := config.FailurePolicy
+	if len(failurePolicy) == 0 {
+		failurePolicy = fedv1b1.PropagationWebhookFail
+	}
+
+	return &propagationWebhook{
+		name:          config.Name,
+		url:           config.URL,
+		selector:      selector,
+		failurePolicy: failurePolicy,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Review calls in order the webhooks that apply to the named cluster
+// with the given object about to be created or updated in it, and
+// applies the patches they respond with to the object. A *DeniedError
+// is returned if a webhook denies propagation.
+func (r *Reviewer) Review(obj *unstructured.Unstructured, clusterName, operation string) error {
+	cluster, ok, err := r.getCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("cluster %q is not ready", clusterName)
+	}
+	for _, w := range r.webhooks {
+		if !w.selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		err := w.review(obj, cluster, operation)
+		if err == nil {
+			continue
+		}
+		if IsDenied(err) {
+			return err
+		}
+		if w.failurePolicy == fedv1b1.PropagationWebhookIgnore {
+			klog.V(2).Infof("Ignoring failure of propagation webhook %q: %v", w.name, err)
+			continue
+		}
+		return errors.Wrapf(err, "propagation webhook %q failed", w.name)
+	}
+	return nil
+}
+
+func (w *propagationWebhook) review(obj *unstructured.Unstructured, cluster *fedv1b1.KubeFedCluster, operation string) error {
+	if util.RestrictedCompliance() {
+		// The webhook fails according to its failure policy.
+		return errors.New("outbound calls are disabled in the restricted compliance mode")
+	}
+
+	body, err := json.Marshal(&PropagationReview{
+		APIVersion: ReviewAPIVersion,
+		Kind:       ReviewKind,
+		Request: &PropagationReviewRequest{
+			Operation:     operation,
+			ClusterName:   cluster.Name,
+			ClusterLabels: cluster.Labels,
+			Object:        obj,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode review")
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %q", resp.Status)
+	}
+	respBody, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, maxResponseSize))
+	if err != nil {
+		return errors.Wrap(err, "failed to read review")
+	}
+	review := &PropagationReview{}
+	if err := json.Unmarshal(respBody, review); err != nil {
+		return errors.Wrap(err, "failed to decode review")
+	}
+	if review.Response == nil {
+		return errors.New("review has no response")
+	}
+
+	if !review.Response.Allowed {
+		return &DeniedError{Webhook: w.name, Message: review.Response.Message}
+	}
+	if len(review.Response.Patch) == 0 {
+		return nil
+	}
+	if err := util.ApplyJsonPatch(obj, review.Response.Patch); err != nil {
+		return errors.Wrap(err, "failed to apply patch")
+	}
+	return nil
+}
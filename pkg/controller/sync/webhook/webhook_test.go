@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestReview(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := &PropagationReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response := &PropagationReviewResponse{Allowed: true}
+		switch {
+		case r.URL.Path == "/error":
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		case review.Request.ClusterLabels["env"] == "prod":
+			response = &PropagationReviewResponse{Message: "not approved for prod"}
+		default:
+			response.Patch = util.ClusterOverrides{
+				{Path: "/metadata/labels", Value: map[string]interface{}{"cluster": review.Request.ClusterName}},
+			}
+		}
+		review.Request = nil
+		review.Response = response
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	clusters := map[string]*fedv1b1.KubeFedCluster{
+		"cluster1": {ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"env": "dev"}}},
+		"cluster2": {ObjectMeta: metav1.ObjectMeta{Name: "cluster2", Labels: map[string]string{"env": "prod"}}},
+	}
+	getCluster := func(name string) (*fedv1b1.KubeFedCluster, bool, error) {
+		cluster, ok := clusters[name]
+		return cluster, ok, nil
+	}
+
+	testCases := map[string]struct {
+		path           string
+		selector       *metav1.LabelSelector
+		failurePolicy  fedv1b1.PropagationWebhookFailurePolicy
+		clusterName    string
+		expectedLabels map[string]string
+		expectDenied   bool
+		expectError    bool
+	}{
+		"Allowed with a patch": {
+			clusterName:    "cluster1",
+			expectedLabels: map[string]string{"cluster": "cluster1"},
+		},
+		"Denied": {
+			clusterName:  "cluster2",
+			expectDenied: true,
+			expectError:  true,
+		},
+		"Cluster not matching the selector": {
+			selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+			clusterName: "cluster2",
+		},
+		"Failure": {
+			path:        "/error",
+			clusterName: "cluster1",
+			expectError: true,
+		},
+		"Ignored failure": {
+			path:          "/error",
+			failurePolicy: fedv1b1.PropagationWebhookIgnore,
+			clusterName:   "cluster1",
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			reviewer, err := NewReviewer([]fedv1b1.PropagationWebhook{
+				{
+					Name:            "policy",
+					URL:             server.URL + tc.path,
+					CABundle:        caBundle,
+					ClusterSelector: tc.selector,
+					FailurePolicy:   tc.failurePolicy,
+				},
+			}, getCluster)
+			if err != nil {
+				t.Fatalf("Unexpected error creating reviewer: %v", err)
+			}
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("ConfigMap")
+			obj.SetName("config")
+
+			err = reviewer.Review(obj, tc.clusterName, "create")
+			if tc.expectError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.expectError, err)
+			}
+			if tc.expectDenied != IsDenied(err) {
+				t.Errorf("Expected denied %v, got %v", tc.expectDenied, err)
+			}
+			if !reflect.DeepEqual(obj.GetLabels(), tc.expectedLabels) {
+				t.Errorf("Expected labels %v, got %v", tc.expectedLabels, obj.GetLabels())
+			}
+		})
+	}
+}
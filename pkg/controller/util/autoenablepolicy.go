@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// kubeFedGroupSuffix is the suffix of the API groups of KubeFed.
+const kubeFedGroupSuffix = "kubefed.io"
+
+// GroupHasSuffix returns whether the given API group is the given
+// suffix or a subdomain of it.
+func GroupHasSuffix(group, suffix string) bool {
+	return group == suffix || strings.HasSuffix(group, "."+suffix)
+}
+
+// IsKubeFedGroup returns whether the given API group is one of the
+// groups of KubeFed, including that of the default federated types.
+func IsKubeFedGroup(group string) bool {
+	return GroupHasSuffix(group, kubeFedGroupSuffix)
+}
+
+// AutoEnablePolicySelects returns whether the given policy selects a
+// CRD of the given API group with the given labels. The CRDs of the
+// groups of KubeFed are never selected.
+func AutoEnablePolicySelects(policy *fedv1b1.AutoEnablePolicy, group string, crdLabels map[string]string) (bool, error) {
+	if IsKubeFedGroup(group) {
+		return false, nil
+	}
+	if len(policy.Spec.GroupSuffixes) > 0 {
+		matched := false
+		for _, suffix := range policy.Spec.GroupSuffixes {
+			if GroupHasSuffix(group, suffix) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if policy.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector)
+		if err != nil {
+			return false, err
+		}
+		if !selector.Matches(labels.Set(crdLabels)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestAutoEnablePolicySelects(t *testing.T) {
+	testCases := map[string]struct {
+		spec     fedv1b1.AutoEnablePolicySpec
+		group    string
+		labels   map[string]string
+		expected bool
+	}{
+		"group equal to suffix is selected": {
+			spec:     fedv1b1.AutoEnablePolicySpec{GroupSuffixes: []string{"mycompany.io"}},
+			group:    "mycompany.io",
+			expected: true,
+		},
+		"subdomain of suffix is selected": {
+			spec:     fedv1b1.AutoEnablePolicySpec{GroupSuffixes: []string{"example.com", "mycompany.io"}},
+			group:    "apps.mycompany.io",
+			expected: true,
+		},
+		"group sharing a partial suffix is not selected": {
+			spec:     fedv1b1.AutoEnablePolicySpec{GroupSuffixes: []string{"mycompany.io"}},
+			group:    "notmycompany.io",
+			expected: false,
+		},
+		"group matching the selector is selected": {
+			spec: fedv1b1.AutoEnablePolicySpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"federate": "true"}},
+			},
+			group:    "example.com",
+			labels:   map[string]string{"federate": "true"},
+			expected: true,
+		},
+		"group matching the suffix but not the selector is not selected": {
+			spec: fedv1b1.AutoEnablePolicySpec{
+				GroupSuffixes: []string{"mycompany.io"},
+				Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"federate": "true"}},
+			},
+			group:    "apps.mycompany.io",
+			labels:   map[string]string{"federate": "false"},
+			expected: false,
+		},
+		"group of KubeFed is never selected": {
+			spec: fedv1b1.AutoEnablePolicySpec{
+				Selector: &metav1.LabelSelector{},
+			},
+			group:    "types.kubefed.io",
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			policy := &fedv1b1.AutoEnablePolicy{Spec: tc.spec}
+			selected, err := AutoEnablePolicySelects(policy, tc.group, tc.labels)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if selected != tc.expected {
+				t.Errorf("Expected selected to be %v, got %v", tc.expected, selected)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// BackfillPhases are the phases of the backfill of a newly joined
+// cluster in the order they are performed.
+var BackfillPhases = []fedv1b1.BackfillPhase{
+	fedv1b1.BackfillNamespaces,
+	fedv1b1.BackfillRBAC,
+	fedv1b1.BackfillCRDs,
+	fedv1b1.BackfillConfiguration,
+	fedv1b1.BackfillWorkloads,
+}
+
+const rbacGroup = "rbac.authorization.k8s.io"
+
+// BackfillPhaseForType returns the phase of a backfill in which
+// resources of the given target type are created.
+func BackfillPhaseForType(targetType metav1.APIResource) fedv1b1.BackfillPhase {
+	switch {
+	case targetType.Kind == NamespaceKind:
+		return fedv1b1.BackfillNamespaces
+	case targetType.Group == rbacGroup, targetType.Kind == "ServiceAccount":
+		return fedv1b1.BackfillRBAC
+	case targetType.Kind == "CustomResourceDefinition":
+		return fedv1b1.BackfillCRDs
+	case targetType.Kind == "ConfigMap", targetType.Kind == "Secret":
+		return fedv1b1.BackfillConfiguration
+	}
+	return fedv1b1.BackfillWorkloads
+}
+
+// BackfillPhaseIndex returns the position of the given phase in the
+// order of backfill phases. Phases not part of the order, including
+// BackfillComplete, follow all other phases.
+func BackfillPhaseIndex(phase fedv1b1.BackfillPhase) int {
+	for i, p := range BackfillPhases {
+		if p == phase {
+			return i
+		}
+	}
+	return len(BackfillPhases)
+}
+
+// IsBackfillDeferred returns whether creation of resources of the given
+// phase in the cluster should wait for an earlier phase of the backfill
+// of the cluster to complete.
+func IsBackfillDeferred(cluster *fedv1b1.KubeFedCluster, phase fedv1b1.BackfillPhase) bool {
+	backfill := cluster.Status.Backfill
+	if backfill == nil || backfill.CompletionTime != nil {
+		return false
+	}
+	return BackfillPhaseIndex(phase) > BackfillPhaseIndex(backfill.Phase)
+}
+
+// backfillPhaseChanged returns whether the backfill phase of the
+// cluster has changed.
+func backfillPhaseChanged(oldCluster, curCluster *fedv1b1.KubeFedCluster) bool {
+	var oldPhase, curPhase fedv1b1.BackfillPhase
+	if oldCluster.Status.Backfill != nil {
+		oldPhase = oldCluster.Status.Backfill.Phase
+	}
+	if curCluster.Status.Backfill != nil {
+		curPhase = curCluster.Status.Backfill.Phase
+	}
+	return oldPhase != curPhase
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// RenderBlueprint returns the federated resources of the given
+// Blueprint for an instance with the given parameters. References of
+// the form $(name) to the parameters of the blueprint are replaced by
+// the given values or the defaults of the parameters, which are
+// expected to have been validated against the blueprint.
+func RenderBlueprint(blueprint *fedv1b1.Blueprint, parameters map[string]string) ([]*unstructured.Unstructured, error) {
+	values := make(map[string]string)
+	typedValues := make(map[string]interface{})
+	for _, parameter := range blueprint.Spec.Parameters {
+		value, ok := parameters[parameter.Name]
+		if !ok {
+			if parameter.Default == nil {
+				return nil, errors.Errorf("parameter %q of Blueprint %q is required", parameter.Name, blueprint.Name)
+			}
+			value = *parameter.Default
+		}
+		values[parameter.Name] = value
+		switch parameter.Type {
+		case fedv1b1.BlueprintParameterTypeInteger:
+			integer, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("parameter %q of Blueprint %q must be an integer", parameter.Name, blueprint.Name)
+			}
+			typedValues[parameter.Name] = integer
+		case fedv1b1.BlueprintParameterTypeBoolean:
+			typedValues[parameter.Name] = value == "true"
+		}
+	}
+
+	oldnew := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		oldnew = append(oldnew, "$("+name+")", value)
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	resources := make([]*unstructured.Unstructured, 0, len(blueprint.Spec.Resources))
+	for i, raw := range blueprint.Spec.Resources {
+		content := make(map[string]interface{})
+		if err := json.Unmarshal(raw.Raw, &content); err != nil {
+			return nil, errors.Wrapf(err, "failed to read resource %d of Blueprint %q", i, blueprint.Name)
+		}
+		obj := &unstructured.Unstructured{
+			Object: substituteParameters(content, replacer, typedValues).(map[string]interface{}),
+		}
+		if errs := validation.IsDNS1123Subdomain(obj.GetName()); len(errs) > 0 {
+			return nil, errors.Errorf("resource %d of Blueprint %q is named %q, which is invalid: %s", i, blueprint.Name, obj.GetName(), strings.Join(errs, ","))
+		}
+		resources = append(resources, obj)
+	}
+	return resources, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestRenderBlueprint(t *testing.T) {
+	replicas := "1"
+	blueprint := &fedv1b1.Blueprint{
+		Spec: fedv1b1.BlueprintSpec{
+			Parameters: []fedv1b1.BlueprintParameter{
+				{Name: "appName"},
+				{Name: "replicas", Type: fedv1b1.BlueprintParameterTypeInteger, Default: &replicas},
+				{Name: "debug", Type: fedv1b1.BlueprintParameterTypeBoolean},
+			},
+			Resources: []runtime.RawExtension{
+				{Raw: []byte(`{
+					"apiVersion": "types.kubefed.io/v1beta1",
+					"kind": "FederatedDeployment",
+					"metadata": {"name": "$(appName)-web"},
+					"spec": {"template": {"spec": {
+						"replicas": "$(replicas)",
+						"template": {"spec": {"containers": [{
+							"name": "web",
+							"args": ["--debug=$(debug)", "$(debug)", "$(HOME)"]
+						}]}}
+					}}}
+				}`)},
+			},
+		},
+	}
+
+	resources, err := RenderBlueprint(blueprint, map[string]string{"appName": "shop", "debug": "true"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 resource, got %d", len(resources))
+	}
+	obj := resources[0]
+	if obj.GetName() != "shop-web" {
+		t.Errorf("Expected name %q, got %q", "shop-web", obj.GetName())
+	}
+	spec := obj.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	if spec["replicas"] != int64(1) {
+		t.Errorf("Expected replicas to be the integer default, got %#v", spec["replicas"])
+	}
+	containers := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	args := containers[0].(map[string]interface{})["args"]
+	expectedArgs := []interface{}{"--debug=true", true, "$(HOME)"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+
+	if _, err := RenderBlueprint(blueprint, map[string]string{"debug": "true"}); err == nil {
+		t.Errorf("Expected an error for a missing parameter")
+	}
+	if _, err := RenderBlueprint(blueprint, map[string]string{"appName": "Shop", "debug": "true"}); err == nil {
+		t.Errorf("Expected an error for an invalid name")
+	}
+}
@@ -36,6 +36,7 @@ import (
 
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util/secretprovider"
 )
 
 const (
@@ -50,8 +51,24 @@ const (
 
 // BuildClusterConfig returns a restclient.Config that can be used to configure
 // a client for the given KubeFedCluster or an error. The client is used to
-// access kubernetes secrets in the kubefed namespace.
-func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string) (*restclient.Config, error) {
+// access kubernetes secrets in the kubefed namespace. If the cluster is
+// the host cluster and hostConfig is provided, the returned config
+// targets the api server of the host cluster the same way hostConfig
+// does rather than the api endpoint of the cluster. In the restricted
+// compliance mode, the returned config only connects with approved TLS
+// versions and cipher suites.
+func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string, hostConfig *restclient.Config) (*restclient.Config, error) {
+	clusterConfig, err := buildClusterConfig(fedCluster, client, fedNamespace, hostConfig)
+	if err != nil || !RestrictedCompliance() {
+		return clusterConfig, err
+	}
+	if err := restrictClusterTransport(fedCluster.Name, clusterConfig); err != nil {
+		return nil, err
+	}
+	return clusterConfig, nil
+}
+
+func buildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string, hostConfig *restclient.Config) (*restclient.Config, error) {
 	clusterName := fedCluster.Name
 
 	apiEndpoint := fedCluster.Spec.APIEndpoint
@@ -60,19 +77,18 @@ func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Clien
 		return nil, errors.Errorf("The api endpoint of cluster %s is empty", clusterName)
 	}
 
-	secretName := fedCluster.Spec.SecretRef.Name
-	if secretName == "" {
-		return nil, errors.Errorf("Cluster %s does not have a secret name", clusterName)
+	if fedCluster.Spec.UseServiceAccount {
+		return serviceAccountClusterConfig(fedCluster, hostConfig)
 	}
-	secret := &apiv1.Secret{}
-	err := client.Get(context.TODO(), secret, fedNamespace, secretName)
+
+	token, err := clusterToken(fedCluster, client, fedNamespace)
 	if err != nil {
 		return nil, err
 	}
 
-	token, tokenFound := secret.Data[TokenKey]
-	if !tokenFound || len(token) == 0 {
-		return nil, errors.Errorf("The secret for cluster %s is missing a non-empty value for %q", clusterName, TokenKey)
+	if fedCluster.Spec.HostCluster && hostConfig != nil {
+		klog.V(1).Infof("Cluster %s will be accessed with the connection of the host cluster", clusterName)
+		return hostClusterConfig(hostConfig, string(token)), nil
 	}
 
 	clusterConfig, err := clientcmd.BuildConfigFromFlags(apiEndpoint, "")
@@ -85,6 +101,9 @@ func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Clien
 	clusterConfig.Burst = KubeAPIBurst
 
 	if len(fedCluster.Spec.DisabledTLSValidations) != 0 {
+		if RestrictedCompliance() {
+			return nil, errors.Errorf("Cluster %s may not disable TLS validations in the restricted compliance mode", clusterName)
+		}
 		klog.V(1).Infof("Cluster %s will use a custom transport for TLS certificate validation", fedCluster.Name)
 		if err = CustomizeTLSTransport(fedCluster, clusterConfig); err != nil {
 			return nil, err
@@ -94,6 +113,70 @@ func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Clien
 	return clusterConfig, nil
 }
 
+// clusterToken returns the token required to access the given cluster
+// from either its secret or the external secret provider referenced
+// by the cluster.
+func clusterToken(fedCluster *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string) ([]byte, error) {
+	clusterName := fedCluster.Name
+	if external := fedCluster.Spec.SecretRef.External; external != nil {
+		token, err := secretprovider.Token(external)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve the token for cluster %s", clusterName)
+		}
+		return token, nil
+	}
+
+	secretName := fedCluster.Spec.SecretRef.Name
+	if secretName == "" {
+		return nil, errors.Errorf("Cluster %s does not have a secret name", clusterName)
+	}
+	secret := &apiv1.Secret{}
+	err := client.Get(context.TODO(), secret, fedNamespace, secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenFound := secret.Data[TokenKey]
+	if !tokenFound || len(token) == 0 {
+		return nil, errors.Errorf("The secret for cluster %s is missing a non-empty value for %q", clusterName, TokenKey)
+	}
+	return token, nil
+}
+
+// hostClusterConfig returns a config that reaches the api server of
+// the host cluster like the given host config but authenticates with
+// the token of the member cluster. The permissions granted to the
+// control plane by joining the cluster are thus retained.
+func hostClusterConfig(hostConfig *restclient.Config, token string) *restclient.Config {
+	clusterConfig := restclient.AnonymousClientConfig(hostConfig)
+	clusterConfig.BearerToken = token
+	clusterConfig.QPS = KubeAPIQPS
+	clusterConfig.Burst = KubeAPIBurst
+	return clusterConfig
+}
+
+// serviceAccountClusterConfig returns a config that reaches the api
+// server of the host cluster with the credentials of the control plane,
+// i.e. those of hostConfig or, if hostConfig is not provided, of the
+// service account the control plane runs as.
+func serviceAccountClusterConfig(fedCluster *fedv1b1.KubeFedCluster, hostConfig *restclient.Config) (*restclient.Config, error) {
+	if !fedCluster.Spec.HostCluster {
+		return nil, errors.Errorf("Cluster %s uses the service account of the control plane but is not the host cluster", fedCluster.Name)
+	}
+	if hostConfig == nil {
+		var err error
+		hostConfig, err = restclient.InClusterConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to load the in-cluster configuration for cluster %s", fedCluster.Name)
+		}
+	}
+	klog.V(1).Infof("Cluster %s will be accessed with the service account of the control plane", fedCluster.Name)
+	clusterConfig := restclient.CopyConfig(hostConfig)
+	clusterConfig.QPS = KubeAPIQPS
+	clusterConfig.Burst = KubeAPIBurst
+	return clusterConfig, nil
+}
+
 // IsPrimaryCluster checks if the caller is working with objects for the
 // primary cluster by checking if the UIDs match for both ObjectMetas passed
 // in.
@@ -121,8 +204,15 @@ func CustomizeTLSTransport(fedCluster *fedv1b1.KubeFedCluster, clientConfig *res
 		return errors.Errorf("Cluster %s custom certificate validation error: %s", fedCluster.Name, err)
 	}
 
+	clientConfig.Transport = newTLSTransport(transportConfig)
+	clientConfig.TLSClientConfig = restclient.TLSClientConfig{}
+	return nil
+}
+
+// newTLSTransport returns a transport with the given TLS configuration.
+func newTLSTransport(tlsConfig *tls.Config) *http.Transport {
 	// using the same defaults as http.DefaultTransport
-	clientConfig.Transport = &http.Transport{
+	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
@@ -133,10 +223,8 @@ func CustomizeTLSTransport(fedCluster *fedv1b1.KubeFedCluster, clientConfig *res
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig:       transportConfig,
+		TLSClientConfig:       tlsConfig,
 	}
-	clientConfig.TLSClientConfig = restclient.TLSClientConfig{}
-	return nil
 }
 
 // CustomizeCertificateValidation modifies an existing tls.Config to disable the
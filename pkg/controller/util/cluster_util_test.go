@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	restclient "k8s.io/client-go/rest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestHostClusterConfig(t *testing.T) {
+	hostConfig := &restclient.Config{
+		Host:        "https://10.96.0.1:443",
+		BearerToken: "host-token",
+		TLSClientConfig: restclient.TLSClientConfig{
+			CAData:   []byte("host-ca"),
+			CertData: []byte("host-cert"),
+			KeyData:  []byte("host-key"),
+		},
+	}
+
+	config := hostClusterConfig(hostConfig, "member-token")
+
+	if config.Host != hostConfig.Host {
+		t.Errorf("Expected host %q, got %q", hostConfig.Host, config.Host)
+	}
+	if string(config.CAData) != "host-ca" {
+		t.Errorf("Expected the certificate authority of the host config, got %q", config.CAData)
+	}
+	if config.BearerToken != "member-token" {
+		t.Errorf("Expected the token of the member cluster, got %q", config.BearerToken)
+	}
+	if len(config.CertData) != 0 || len(config.KeyData) != 0 {
+		t.Errorf("Expected the client certificate of the host config to be omitted")
+	}
+	if config.QPS != KubeAPIQPS || config.Burst != KubeAPIBurst {
+		t.Errorf("Expected qps %v and burst %v, got %v and %v", KubeAPIQPS, KubeAPIBurst, config.QPS, config.Burst)
+	}
+	if hostConfig.BearerToken != "host-token" {
+		t.Errorf("Expected the host config to be unchanged")
+	}
+}
+
+func TestServiceAccountClusterConfig(t *testing.T) {
+	hostConfig := &restclient.Config{
+		Host:        "https://10.96.0.1:443",
+		BearerToken: "control-plane-token",
+	}
+	cluster := &fedv1b1.KubeFedCluster{}
+	cluster.Name = "host"
+	cluster.Spec.APIEndpoint = "https://host.example.com"
+	cluster.Spec.UseServiceAccount = true
+
+	// No client is needed since no secret is retrieved.
+	if _, err := BuildClusterConfig(cluster, nil, DefaultKubeFedSystemNamespace, hostConfig); err == nil {
+		t.Errorf("Expected an error for a cluster that is not the host cluster")
+	}
+
+	cluster.Spec.HostCluster = true
+	config, err := BuildClusterConfig(cluster, nil, DefaultKubeFedSystemNamespace, hostConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Host != hostConfig.Host {
+		t.Errorf("Expected host %q, got %q", hostConfig.Host, config.Host)
+	}
+	if config.BearerToken != "control-plane-token" {
+		t.Errorf("Expected the token of the control plane, got %q", config.BearerToken)
+	}
+	if config == hostConfig {
+		t.Errorf("Expected a copy of the host config")
+	}
+}
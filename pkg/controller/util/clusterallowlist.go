@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// AllowedClusters returns the names of the clusters that the federated
+// resources in the given namespace may be propagated to according to
+// the given allowlists. The namespace of cluster-scoped resources is
+// empty. No clusters are allowed if no allowlist applies.
+func AllowedClusters(allowlists []*fedv1b1.ClusterAllowlist, namespace string) sets.String {
+	allowed := sets.String{}
+	for _, allowlist := range allowlists {
+		applies := allowlist.Spec.ClusterScoped
+		if len(namespace) > 0 {
+			applies = sets.NewString(allowlist.Spec.Namespaces...).Has(namespace)
+		}
+		if applies {
+			allowed.Insert(allowlist.Spec.Clusters...)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestAllowedClusters(t *testing.T) {
+	allowlists := []*fedv1b1.ClusterAllowlist{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments"},
+			Spec: fedv1b1.ClusterAllowlistSpec{
+				Namespaces: []string{"payments", "payments-batch"},
+				Clusters:   []string{"prod-east", "prod-west"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+			Spec: fedv1b1.ClusterAllowlistSpec{
+				Namespaces: []string{"payments"},
+				Clusters:   []string{"staging"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec: fedv1b1.ClusterAllowlistSpec{
+				ClusterScoped: true,
+				Clusters:      []string{"prod-east"},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		namespace string
+		expected  sets.String
+	}{
+		"Namespace listed by multiple allowlists": {
+			namespace: "payments",
+			expected:  sets.NewString("prod-east", "prod-west", "staging"),
+		},
+		"Namespace listed by one allowlist": {
+			namespace: "payments-batch",
+			expected:  sets.NewString("prod-east", "prod-west"),
+		},
+		"Namespace not listed": {
+			namespace: "web",
+			expected:  sets.NewString(),
+		},
+		"Cluster-scoped resources": {
+			expected: sets.NewString("prod-east"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			allowed := AllowedClusters(allowlists, tc.namespace)
+			if !allowed.Equal(tc.expected) {
+				t.Errorf("Expected allowed clusters %v, got %v", tc.expected.List(), allowed.List())
+			}
+		})
+	}
+}
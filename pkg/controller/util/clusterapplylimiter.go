@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+// ClusterApplyLimiter limits the rate at which resources are created
+// and updated in each member cluster with a token bucket per cluster.
+// The limiter is shared by the clients of all sync controllers so that
+// the limit of a cluster applies to all federated types together.
+type ClusterApplyLimiter struct {
+	sync.Mutex
+
+	// The limit of clusters that do not override it. Applies are not
+	// limited if nil.
+	defaultLimit *fedv1b1.ApplyRateLimit
+
+	limiters map[string]*clusterRateLimiter
+}
+
+type clusterRateLimiter struct {
+	limit   fedv1b1.ApplyRateLimit
+	limiter flowcontrol.RateLimiter
+}
+
+// NewClusterApplyLimiter returns a limiter that limits applies to
+// clusters that do not override it with the given limit, if not nil.
+func NewClusterApplyLimiter(defaultLimit *fedv1b1.ApplyRateLimit) *ClusterApplyLimiter {
+	return &ClusterApplyLimiter{
+		defaultLimit: defaultLimit,
+		limiters:     make(map[string]*clusterRateLimiter),
+	}
+}
+
+// Wait blocks until a resource may be applied to the given cluster,
+// or returns an error if the context is done first. The time waited is
+// recorded so that limits that are too tight can be detected.
+func (l *ClusterApplyLimiter) Wait(ctx context.Context, cluster *fedv1b1.KubeFedCluster) error {
+	limiter := l.limiterFor(cluster)
+	if limiter == nil {
+		return nil
+	}
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	metrics.ClusterApplyQueueDurationFromStart(cluster.Name, start)
+	return err
+}
+
+// limiterFor returns the rate limiter of the given cluster, or nil if
+// applies to the cluster are not limited. The limiter is replaced
+// when the limit of the cluster changes.
+func (l *ClusterApplyLimiter) limiterFor(cluster *fedv1b1.KubeFedCluster) flowcontrol.RateLimiter {
+	limit := l.defaultLimit
+	if cluster.Spec.ApplyRateLimit != nil {
+		limit = cluster.Spec.ApplyRateLimit
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	existing, ok := l.limiters[cluster.Name]
+	if limit == nil {
+		if ok {
+			existing.limiter.Stop()
+			delete(l.limiters, cluster.Name)
+		}
+		return nil
+	}
+	if ok && existing.limit == *limit {
+		return existing.limiter
+	}
+	if ok {
+		existing.limiter.Stop()
+	}
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = limit.ObjectsPerSecond
+	}
+	limiter := &clusterRateLimiter{
+		limit:   *limit,
+		limiter: flowcontrol.NewTokenBucketRateLimiter(float32(limit.ObjectsPerSecond), int(burst)),
+	}
+	l.limiters[cluster.Name] = limiter
+	return limiter.limiter
+}
+
+// wrapClientWithApplyLimit returns a client for the named cluster
+// whose creates, updates and patches wait for the apply rate limit of
+// the cluster.
+func wrapClientWithApplyLimit(client generic.Client, clusterName string, limiter *ClusterApplyLimiter, getCluster func(string) (*fedv1b1.KubeFedCluster, bool, error)) generic.Client {
+	return generic.NewRateLimitedClient(client, func(ctx context.Context) error {
+		cluster, found, err := getCluster(clusterName)
+		if err != nil || !found {
+			return nil
+		}
+		return limiter.Wait(ctx, cluster)
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestClusterApplyLimiterFor(t *testing.T) {
+	defaultLimit := &fedv1b1.ApplyRateLimit{ObjectsPerSecond: 10}
+	limiter := NewClusterApplyLimiter(defaultLimit)
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	defaultLimiter := limiter.limiterFor(cluster)
+	if defaultLimiter == nil {
+		t.Fatalf("Expected the default limit to apply")
+	}
+	if defaultLimiter.QPS() != 10 {
+		t.Errorf("Expected 10 QPS, got %v", defaultLimiter.QPS())
+	}
+	if limiter.limiterFor(cluster) != defaultLimiter {
+		t.Errorf("Expected the limiter to be reused while the limit is unchanged")
+	}
+
+	cluster.Spec.ApplyRateLimit = &fedv1b1.ApplyRateLimit{ObjectsPerSecond: 2, Burst: 5}
+	overriddenLimiter := limiter.limiterFor(cluster)
+	if overriddenLimiter == defaultLimiter || overriddenLimiter.QPS() != 2 {
+		t.Errorf("Expected a limiter with the limit of the cluster")
+	}
+
+	unlimited := NewClusterApplyLimiter(nil)
+	cluster.Spec.ApplyRateLimit = nil
+	if unlimited.limiterFor(cluster) != nil {
+		t.Errorf("Expected applies not to be limited without a limit")
+	}
+}
+
+func TestClusterApplyLimiterWait(t *testing.T) {
+	limiter := NewClusterApplyLimiter(&fedv1b1.ApplyRateLimit{ObjectsPerSecond: 1, Burst: 2})
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	// The burst is available immediately.
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(context.Background(), cluster); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// The next apply has to wait for a token.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, cluster); err == nil {
+		t.Errorf("Expected the apply to wait beyond the deadline")
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+	"time"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/client/generic"
+)
+
+// latencyWeight is the weight of the latest request in the average
+// latency of a cluster.
+const latencyWeight = 0.2
+
+// ClusterLatencyTracker tracks the average latency of the requests
+// made to member clusters to identify the clusters that are slow to
+// respond.
+type ClusterLatencyTracker struct {
+	sync.RWMutex
+
+	// The average latency above which a cluster is considered slow.
+	threshold time.Duration
+
+	// Exponentially weighted moving average of the latency of the
+	// requests to each cluster.
+	latencies map[string]time.Duration
+}
+
+// NewClusterLatencyTracker returns a tracker that considers clusters
+// whose average request latency exceeds the given threshold slow.
+func NewClusterLatencyTracker(threshold time.Duration) *ClusterLatencyTracker {
+	return &ClusterLatencyTracker{
+		threshold: threshold,
+		latencies: make(map[string]time.Duration),
+	}
+}
+
+// ObserveLatency records the latency of a request to the named
+// cluster.
+func (t *ClusterLatencyTracker) ObserveLatency(clusterName string, latency time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+	average, ok := t.latencies[clusterName]
+	if !ok {
+		t.latencies[clusterName] = latency
+		return
+	}
+	t.latencies[clusterName] = average + time.Duration(latencyWeight*float64(latency-average))
+}
+
+// IsSlow returns whether the average request latency of the named
+// cluster exceeds the threshold.
+func (t *ClusterLatencyTracker) IsSlow(clusterName string) bool {
+	t.RLock()
+	defer t.RUnlock()
+	return t.latencies[clusterName] > t.threshold
+}
+
+// wrapClientWithTimeout returns a client for the named cluster that
+// cancels requests that exceed the operation timeout of the cluster,
+// or the given default timeout if the cluster does not override it,
+// and reports the latency of requests to the tracker if not nil.
+func wrapClientWithTimeout(client generic.Client, clusterName string, defaultTimeout time.Duration, tracker *ClusterLatencyTracker, getCluster func(string) (*fedv1b1.KubeFedCluster, bool, error)) generic.Client {
+	var latency generic.LatencyFunc
+	if tracker != nil {
+		latency = func(latency time.Duration) {
+			tracker.ObserveLatency(clusterName, latency)
+		}
+	}
+	return generic.NewTimeoutClient(client, func() time.Duration {
+		if cluster, found, err := getCluster(clusterName); err == nil && found && cluster.Spec.OperationTimeout != nil {
+			return cluster.Spec.OperationTimeout.Duration
+		}
+		return defaultTimeout
+	}, latency)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClusterLatencyTracker(t *testing.T) {
+	testCases := map[string]struct {
+		latencies []time.Duration
+		expected  bool
+	}{
+		"No requests": {
+			expected: false,
+		},
+		"Fast request": {
+			latencies: []time.Duration{100 * time.Millisecond},
+			expected:  false,
+		},
+		"Slow request": {
+			latencies: []time.Duration{2 * time.Second},
+			expected:  true,
+		},
+		"Single slow request after fast requests": {
+			latencies: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 2 * time.Second},
+			expected:  false,
+		},
+		"Consecutive slow requests after fast requests": {
+			latencies: []time.Duration{100 * time.Millisecond, 5 * time.Second, 5 * time.Second},
+			expected:  true,
+		},
+		"Fast requests after slow requests": {
+			latencies: append([]time.Duration{5 * time.Second}, repeatLatency(100*time.Millisecond, 10)...),
+			expected:  false,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			tracker := NewClusterLatencyTracker(time.Second)
+			for _, latency := range tc.latencies {
+				tracker.ObserveLatency("cluster1", latency)
+			}
+			if slow := tracker.IsSlow("cluster1"); slow != tc.expected {
+				t.Errorf("Expected slow to be %v, got %v", tc.expected, slow)
+			}
+			if tracker.IsSlow("cluster2") {
+				t.Errorf("Expected a cluster without requests not to be slow")
+			}
+		})
+	}
+}
+
+func repeatLatency(latency time.Duration, count int) []time.Duration {
+	latencies := make([]time.Duration, count)
+	for i := range latencies {
+		latencies[i] = latency
+	}
+	return latencies
+}
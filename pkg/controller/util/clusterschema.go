@@ -0,0 +1,409 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	openapi_v2 "github.com/googleapis/gnostic/OpenAPIv2"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// The interval after which the schema of a member cluster is
+	// fetched again to pick up changes to the CRDs of the cluster.
+	schemaRefreshInterval = 10 * time.Minute
+
+	// The maximum number of references followed to resolve a schema.
+	maxSchemaReferences = 10
+
+	gvkExtension           = "x-kubernetes-group-version-kind"
+	listTypeExtension      = "x-kubernetes-list-type"
+	listMapKeysExtension   = "x-kubernetes-list-map-keys"
+	patchMergeKeyExtension = "x-kubernetes-patch-merge-key"
+	definitionsRefPrefix   = "#/definitions/"
+)
+
+// Top-level fields that are not considered when comparing the content
+// of resources. The metadata is compared separately and the status is
+// owned by the member cluster.
+var uncomparedFields = sets.NewString("apiVersion", "kind", "metadata", "status")
+
+// ClusterSchema describes the resources served by a member cluster
+// according to the OpenAPI schema published by the cluster.
+type ClusterSchema struct {
+	definitions map[string]*openapi_v2.Schema
+	kinds       map[schema.GroupVersionKind]*openapi_v2.Schema
+}
+
+// NewClusterSchema returns the schema described by the given OpenAPI
+// document.
+func NewClusterSchema(doc *openapi_v2.Document) *ClusterSchema {
+	s := &ClusterSchema{
+		definitions: make(map[string]*openapi_v2.Schema),
+		kinds:       make(map[schema.GroupVersionKind]*openapi_v2.Schema),
+	}
+	for _, named := range doc.GetDefinitions().GetAdditionalProperties() {
+		s.definitions[named.GetName()] = named.GetValue()
+		for _, gvk := range schemaGVKs(named.GetValue()) {
+			s.kinds[gvk] = named.GetValue()
+		}
+	}
+	return s
+}
+
+// Equivalent returns whether the given cluster object differs from the
+// desired object only in fields that were defaulted by the cluster.
+func (s *ClusterSchema) Equivalent(desiredObj, clusterObj *unstructured.Unstructured) bool {
+	prunedObj := s.PruneDefaultedFields(desiredObj, clusterObj)
+	if !ObjectMetaObjEquivalent(desiredObj, prunedObj) {
+		return false
+	}
+	return valuesEquivalent(withoutFields(desiredObj.Object, uncomparedFields), withoutFields(prunedObj.Object, uncomparedFields))
+}
+
+// PruneDefaultedFields returns a copy of the given cluster object
+// without the fields that are absent from the desired object and
+// whose values are the defaults declared by the schema of the
+// cluster. The cluster object is returned unchanged if the cluster
+// does not publish a schema for its kind.
+func (s *ClusterSchema) PruneDefaultedFields(desiredObj, clusterObj *unstructured.Unstructured) *unstructured.Unstructured {
+	kindSchema, ok := s.kinds[clusterObj.GroupVersionKind()]
+	if !ok {
+		return clusterObj
+	}
+	prunedObj := clusterObj.DeepCopy()
+	s.prune(kindSchema, desiredObj.Object, prunedObj.Object)
+	return prunedObj
+}
+
+// prune removes the fields of the given actual value that are absent
+// from the desired value and have their default value. Lists are
+// pruned item by item, matching items by the keys declared by the
+// schema if any and by position otherwise.
+func (s *ClusterSchema) prune(fieldSchema *openapi_v2.Schema, desired, actual interface{}) {
+	keys := listKeys(fieldSchema)
+	fieldSchema = s.resolve(fieldSchema)
+	if fieldSchema == nil {
+		return
+	}
+	switch actualValue := actual.(type) {
+	case map[string]interface{}:
+		desiredValue, _ := desired.(map[string]interface{})
+		for key, value := range actualValue {
+			propertySchema := s.property(fieldSchema, key)
+			if propertySchema == nil {
+				continue
+			}
+			desiredPropertyValue, ok := desiredValue[key]
+			if !ok || desiredPropertyValue == nil {
+				if s.isDefault(propertySchema, value) {
+					delete(actualValue, key)
+				}
+				continue
+			}
+			s.prune(propertySchema, desiredPropertyValue, value)
+		}
+	case []interface{}:
+		desiredValue, _ := desired.([]interface{})
+		itemSchema := s.items(fieldSchema)
+		if itemSchema == nil {
+			return
+		}
+		for i, item := range actualValue {
+			var desiredItem interface{}
+			if len(keys) > 0 {
+				desiredItem = findListItem(desiredValue, item, keys)
+			} else if i < len(desiredValue) {
+				desiredItem = desiredValue[i]
+			}
+			if desiredItem != nil {
+				s.prune(itemSchema, desiredItem, item)
+			}
+		}
+	}
+}
+
+// resolve follows the references of the given schema to the schema
+// they refer to.
+func (s *ClusterSchema) resolve(fieldSchema *openapi_v2.Schema) *openapi_v2.Schema {
+	for i := 0; fieldSchema != nil && i < maxSchemaReferences; i++ {
+		if ref := fieldSchema.GetXRef(); ref != "" {
+			fieldSchema = s.definitions[strings.TrimPrefix(ref, definitionsRefPrefix)]
+			continue
+		}
+		if allOf := fieldSchema.GetAllOf(); len(allOf) == 1 && len(fieldSchema.GetProperties().GetAdditionalProperties()) == 0 {
+			// A reference may be wrapped to allow it to be described.
+			fieldSchema = allOf[0]
+			continue
+		}
+		return fieldSchema
+	}
+	return nil
+}
+
+// property returns the schema of the named property of the given
+// object schema, if known.
+func (s *ClusterSchema) property(objectSchema *openapi_v2.Schema, name string) *openapi_v2.Schema {
+	for _, named := range objectSchema.GetProperties().GetAdditionalProperties() {
+		if named.GetName() == name {
+			return named.GetValue()
+		}
+	}
+	return objectSchema.GetAdditionalProperties().GetSchema()
+}
+
+// items returns the schema of the items of the given array schema, if
+// known.
+func (s *ClusterSchema) items(arraySchema *openapi_v2.Schema) *openapi_v2.Schema {
+	items := arraySchema.GetItems().GetSchema()
+	if len(items) == 0 {
+		return nil
+	}
+	return items[0]
+}
+
+// isDefault returns whether the given value is the default declared by
+// the given schema.
+func (s *ClusterSchema) isDefault(fieldSchema *openapi_v2.Schema, value interface{}) bool {
+	defaultValue := fieldSchema.GetDefault()
+	if defaultValue == nil {
+		if resolved := s.resolve(fieldSchema); resolved != nil {
+			defaultValue = resolved.GetDefault()
+		}
+	}
+	if defaultValue == nil {
+		return false
+	}
+	var parsedValue interface{}
+	if err := yaml.Unmarshal([]byte(defaultValue.GetYaml()), &parsedValue); err != nil {
+		return false
+	}
+	return valuesEquivalent(parsedValue, value)
+}
+
+// schemaGVKs returns the kinds described by the given schema.
+func schemaGVKs(kindSchema *openapi_v2.Schema) []schema.GroupVersionKind {
+	var gvks []schema.GroupVersionKind
+	for _, extension := range kindSchema.GetVendorExtension() {
+		if extension.GetName() != gvkExtension {
+			continue
+		}
+		var values []struct {
+			Group   string `json:"group"`
+			Version string `json:"version"`
+			Kind    string `json:"kind"`
+		}
+		if err := yaml.Unmarshal([]byte(extension.GetValue().GetYaml()), &values); err != nil {
+			return nil
+		}
+		for _, value := range values {
+			gvks = append(gvks, schema.GroupVersionKind{Group: value.Group, Version: value.Version, Kind: value.Kind})
+		}
+	}
+	return gvks
+}
+
+// listKeys returns the keys that identify the items of a list with the
+// given schema, if any.
+func listKeys(fieldSchema *openapi_v2.Schema) []string {
+	var listType string
+	var mapKeys []string
+	var mergeKey string
+	for _, extension := range fieldSchema.GetVendorExtension() {
+		value := []byte(extension.GetValue().GetYaml())
+		switch extension.GetName() {
+		case listTypeExtension:
+			_ = yaml.Unmarshal(value, &listType)
+		case listMapKeysExtension:
+			_ = yaml.Unmarshal(value, &mapKeys)
+		case patchMergeKeyExtension:
+			_ = yaml.Unmarshal(value, &mergeKey)
+		}
+	}
+	if listType == "map" && len(mapKeys) > 0 {
+		return mapKeys
+	}
+	if mergeKey != "" {
+		return []string{mergeKey}
+	}
+	return nil
+}
+
+// findListItem returns the item of the given list with the same keys
+// as the given item, if any.
+func findListItem(list []interface{}, item interface{}, keys []string) interface{} {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, candidate := range list {
+		candidateMap, ok := candidate.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches := true
+		for _, key := range keys {
+			if !valuesEquivalent(candidateMap[key], itemMap[key]) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// withoutFields returns a shallow copy of the given object without
+// the given fields.
+func withoutFields(obj map[string]interface{}, fields sets.String) map[string]interface{} {
+	result := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		if !fields.Has(key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// valuesEquivalent returns whether the given values of unstructured
+// content are equal, treating null fields as absent and numbers as
+// equal regardless of their type.
+func valuesEquivalent(a, b interface{}) bool {
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		bValue, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range aValue {
+			if value != nil && !valuesEquivalent(value, bValue[key]) {
+				return false
+			}
+		}
+		for key, value := range bValue {
+			if value != nil && aValue[key] == nil {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bValue, ok := b.([]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for i := range aValue {
+			if !valuesEquivalent(aValue[i], bValue[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJSON) == string(bJSON)
+}
+
+// ClusterSchemaFunc returns the schema of the named cluster, or nil if
+// the schema is not used.
+type ClusterSchemaFunc func(clusterName string) (*ClusterSchema, error)
+
+// ClusterSchemaCache caches the OpenAPI schemas of member clusters.
+type ClusterSchemaCache struct {
+	sync.Mutex
+
+	entries map[string]*schemaCacheEntry
+}
+
+type schemaCacheEntry struct {
+	// The generation of the KubeFedCluster the schema was fetched
+	// for.
+	generation int64
+
+	schema *ClusterSchema
+
+	// The error that prevented the schema from being fetched. Cached
+	// to avoid fetching the schema of a cluster that does not serve
+	// it on every comparison.
+	err error
+
+	fetchTime time.Time
+}
+
+// NewClusterSchemaCache returns an empty schema cache.
+func NewClusterSchemaCache() *ClusterSchemaCache {
+	return &ClusterSchemaCache{
+		entries: make(map[string]*schemaCacheEntry),
+	}
+}
+
+// Schema returns the schema of the given cluster. The schema is
+// fetched with the given configuration if it is not cached, was
+// fetched for a previous generation of the cluster or has expired.
+func (c *ClusterSchemaCache) Schema(cluster *fedv1b1.KubeFedCluster, config *restclient.Config) (*ClusterSchema, error) {
+	c.Lock()
+	entry, ok := c.entries[cluster.Name]
+	c.Unlock()
+	if ok && entry.generation == cluster.Generation && time.Since(entry.fetchTime) < schemaRefreshInterval {
+		return entry.schema, entry.err
+	}
+
+	// The schema is fetched without holding the lock since it may
+	// be large. Concurrent fetches for the same cluster are harmless.
+	entry = &schemaCacheEntry{
+		generation: cluster.Generation,
+		fetchTime:  time.Now(),
+	}
+	entry.schema, entry.err = fetchClusterSchema(config)
+
+	c.Lock()
+	defer c.Unlock()
+	c.entries[cluster.Name] = entry
+	return entry.schema, entry.err
+}
+
+// Forget discards the cached schema of the named cluster.
+func (c *ClusterSchemaCache) Forget(clusterName string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, clusterName)
+}
+
+func fetchClusterSchema(config *restclient.Config) (*ClusterSchema, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := discoveryClient.OpenAPISchema()
+	if err != nil {
+		return nil, err
+	}
+	return NewClusterSchema(doc), nil
+}
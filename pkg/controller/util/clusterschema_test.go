@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	openapi_v2 "github.com/googleapis/gnostic/OpenAPIv2"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestClusterSchemaEquivalent(t *testing.T) {
+	clusterSchema := NewClusterSchema(widgetSchemaDocument())
+
+	testCases := map[string]struct {
+		kind          string
+		desiredSpec   map[string]interface{}
+		clusterSpec   map[string]interface{}
+		expectedEqual bool
+	}{
+		"Identical resources are equivalent": {
+			desiredSpec:   map[string]interface{}{"replicas": int64(2)},
+			clusterSpec:   map[string]interface{}{"replicas": int64(2)},
+			expectedEqual: true,
+		},
+		"Defaulted fields are ignored": {
+			desiredSpec: map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80)},
+				},
+			},
+			clusterSpec: map[string]interface{}{
+				"replicas": int64(1),
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+				},
+			},
+			expectedEqual: true,
+		},
+		"Fields with values other than their default are not ignored": {
+			desiredSpec:   map[string]interface{}{},
+			clusterSpec:   map[string]interface{}{"replicas": int64(2)},
+			expectedEqual: false,
+		},
+		"Fields without default are not ignored": {
+			desiredSpec:   map[string]interface{}{},
+			clusterSpec:   map[string]interface{}{"paused": true},
+			expectedEqual: false,
+		},
+		"Fields set in the desired resource are compared": {
+			desiredSpec: map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "protocol": "UDP"},
+				},
+			},
+			clusterSpec: map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+				},
+			},
+			expectedEqual: false,
+		},
+		"Null fields of the desired resource are treated as absent": {
+			desiredSpec:   map[string]interface{}{"replicas": nil},
+			clusterSpec:   map[string]interface{}{"replicas": int64(1)},
+			expectedEqual: true,
+		},
+		"Resources of kinds without schema are compared strictly": {
+			kind:          "Gadget",
+			desiredSpec:   map[string]interface{}{},
+			clusterSpec:   map[string]interface{}{"replicas": int64(1)},
+			expectedEqual: false,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			kind := tc.kind
+			if kind == "" {
+				kind = "Widget"
+			}
+			desiredObj := newWidget(kind, tc.desiredSpec)
+			clusterObj := newWidget(kind, tc.clusterSpec)
+			clusterObj.SetResourceVersion("42")
+			clusterObj.Object["status"] = map[string]interface{}{"ready": true}
+
+			if equal := clusterSchema.Equivalent(desiredObj, clusterObj); equal != tc.expectedEqual {
+				t.Errorf("Expected equivalent to be %v, got %v", tc.expectedEqual, equal)
+			}
+		})
+	}
+}
+
+func newWidget(kind string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion("example.io/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace("ns")
+	obj.SetName("widget")
+	return obj
+}
+
+func widgetSchemaDocument() *openapi_v2.Document {
+	return &openapi_v2.Document{
+		Definitions: &openapi_v2.Definitions{
+			AdditionalProperties: []*openapi_v2.NamedSchema{
+				{
+					Name: "io.example.v1.Widget",
+					Value: &openapi_v2.Schema{
+						VendorExtension: []*openapi_v2.NamedAny{
+							namedAny(gvkExtension, "- group: example.io\n  kind: Widget\n  version: v1\n"),
+						},
+						Properties: properties(map[string]*openapi_v2.Schema{
+							"spec": {XRef: "#/definitions/io.example.v1.WidgetSpec"},
+						}),
+					},
+				},
+				{
+					Name: "io.example.v1.WidgetSpec",
+					Value: &openapi_v2.Schema{
+						Properties: properties(map[string]*openapi_v2.Schema{
+							"replicas": {Default: &openapi_v2.Any{Yaml: "1"}},
+							"paused":   {},
+							"ports": {
+								VendorExtension: []*openapi_v2.NamedAny{
+									namedAny(patchMergeKeyExtension, "port"),
+								},
+								Items: &openapi_v2.ItemsItem{
+									Schema: []*openapi_v2.Schema{
+										{XRef: "#/definitions/io.example.v1.WidgetPort"},
+									},
+								},
+							},
+						}),
+					},
+				},
+				{
+					Name: "io.example.v1.WidgetPort",
+					Value: &openapi_v2.Schema{
+						Properties: properties(map[string]*openapi_v2.Schema{
+							"port":     {},
+							"protocol": {Default: &openapi_v2.Any{Yaml: "TCP"}},
+						}),
+					},
+				},
+			},
+		},
+	}
+}
+
+func properties(schemas map[string]*openapi_v2.Schema) *openapi_v2.Properties {
+	result := &openapi_v2.Properties{}
+	for name, value := range schemas {
+		result.AdditionalProperties = append(result.AdditionalProperties, &openapi_v2.NamedSchema{Name: name, Value: value})
+	}
+	return result
+}
+
+func namedAny(name, yaml string) *openapi_v2.NamedAny {
+	return &openapi_v2.NamedAny{Name: name, Value: &openapi_v2.Any{Yaml: yaml}}
+}
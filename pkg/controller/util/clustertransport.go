@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+// idleConnsPerHost matches the number of idle connections per host
+// retained by the transports of client-go.
+const idleConnsPerHost = 25
+
+// ClusterTransportCache provides a transport per member cluster that is
+// shared by the clients that controllers create for the cluster, so
+// that they reuse the same connections instead of each performing
+// their own TLS handshakes.
+type ClusterTransportCache struct {
+	sync.Mutex
+
+	entries map[string]*transportEntry
+}
+
+type transportEntry struct {
+	// Digest of the generation of the KubeFedCluster and of the
+	// credentials the transport was created for.
+	fingerprint string
+
+	transport *http.Transport
+}
+
+// NewClusterTransportCache returns an empty transport cache.
+func NewClusterTransportCache() *ClusterTransportCache {
+	return &ClusterTransportCache{
+		entries: make(map[string]*transportEntry),
+	}
+}
+
+// Configure returns a copy of the given configuration for the cluster
+// that uses the shared transport of the cluster. A new transport
+// replaces the shared one when the spec of the cluster or the
+// credentials in the configuration have changed, e.g. due to a
+// rotation of the token or CA bundle, and the idle connections of the
+// replaced transport are closed.
+func (c *ClusterTransportCache) Configure(cluster *fedv1b1.KubeFedCluster, config *restclient.Config) (*restclient.Config, error) {
+	if config.Transport != nil {
+		if _, ok := config.Transport.(*http.Transport); !ok {
+			// A transport that cannot be instrumented is not shared.
+			return config, nil
+		}
+	}
+	fingerprint := transportFingerprint(cluster, config)
+
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[cluster.Name]
+	if !ok || entry.fingerprint != fingerprint {
+		clusterTransport, err := newClusterTransport(cluster.Name, config)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			klog.V(2).Infof("Replacing the transport of cluster %q after a change of its configuration", cluster.Name)
+			entry.transport.CloseIdleConnections()
+		}
+		entry = &transportEntry{
+			fingerprint: fingerprint,
+			transport:   clusterTransport,
+		}
+		c.entries[cluster.Name] = entry
+	}
+
+	sharedConfig := restclient.CopyConfig(config)
+	sharedConfig.Transport = entry.transport
+	// The TLS configuration is embedded in the transport.
+	sharedConfig.TLSClientConfig = restclient.TLSClientConfig{}
+	return sharedConfig, nil
+}
+
+// Forget removes the named cluster from the cache and closes the idle
+// connections of its transport.
+func (c *ClusterTransportCache) Forget(clusterName string) {
+	c.Lock()
+	defer c.Unlock()
+	if entry, ok := c.entries[clusterName]; ok {
+		entry.transport.CloseIdleConnections()
+		delete(c.entries, clusterName)
+	}
+}
+
+// newClusterTransport returns a transport for the given configuration
+// that records the connections it opens to the named cluster.
+func newClusterTransport(clusterName string, config *restclient.Config) (*http.Transport, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if customTransport, ok := config.Transport.(*http.Transport); ok {
+		// The transport customized for the TLS validations of the
+		// cluster is created for each configuration and so is not
+		// shared with anything else.
+		dial := customTransport.DialContext
+		if dial == nil {
+			dial = dialer.DialContext
+		}
+		customTransport.DialContext = countingDialContext(clusterName, dial)
+		return customTransport, nil
+	}
+
+	transportConfig, err := config.TransportConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := transport.TLSConfigFor(transportConfig)
+	if err != nil {
+		return nil, err
+	}
+	dial := dialer.DialContext
+	if config.Dial != nil {
+		dial = config.Dial
+	}
+	return utilnet.SetTransportDefaults(&http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConnsPerHost: idleConnsPerHost,
+		DialContext:         countingDialContext(clusterName, dial),
+	}), nil
+}
+
+// transportFingerprint returns a digest of the generation of the
+// cluster and of the endpoint and credentials of the configuration.
+func transportFingerprint(cluster *fedv1b1.KubeFedCluster, config *restclient.Config) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\n", cluster.Generation)
+	fmt.Fprintf(hash, "%s\n%s\n%s\n", config.Host, config.BearerToken, config.BearerTokenFile)
+	fmt.Fprintf(hash, "%t\n%s\n", config.Insecure, config.ServerName)
+	fmt.Fprintf(hash, "%s\n%s\n%s\n", config.CAFile, config.CertFile, config.KeyFile)
+	hash.Write(config.CAData)
+	hash.Write(config.CertData)
+	hash.Write(config.KeyData)
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+type dialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// countingDialContext wraps the given dial function to record the
+// number of open connections to the named cluster.
+func countingDialContext(clusterName string, dial dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		metrics.ClusterClientConnectionOpened(clusterName)
+		return &countedConn{Conn: conn, clusterName: clusterName}, nil
+	}
+}
+
+// countedConn records the closing of a connection opened by
+// countingDialContext.
+type countedConn struct {
+	net.Conn
+
+	clusterName string
+	closeOnce   sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.closeOnce.Do(func() {
+		metrics.ClusterClientConnectionClosed(c.clusterName)
+	})
+	return c.Conn.Close()
+}
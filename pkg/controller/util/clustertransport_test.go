@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	restclient "k8s.io/client-go/rest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestClusterTransportCache(t *testing.T) {
+	cache := NewClusterTransportCache()
+	cluster := &fedv1b1.KubeFedCluster{}
+	cluster.Name = "cluster1"
+	cluster.Generation = 1
+	config := &restclient.Config{
+		Host:        "https://cluster1.example.com",
+		BearerToken: "token1",
+	}
+
+	sharedConfig, err := cache.Configure(cluster, config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sharedConfig.Transport == nil {
+		t.Fatalf("Expected the configuration to use a shared transport")
+	}
+	if config.Transport != nil {
+		t.Fatalf("Expected the given configuration not to be modified")
+	}
+	if sharedConfig.BearerToken != config.BearerToken {
+		t.Fatalf("Expected the bearer token to be retained, got %q", sharedConfig.BearerToken)
+	}
+	if _, err := restclient.TransportFor(sharedConfig); err != nil {
+		t.Fatalf("Expected a valid configuration, got: %v", err)
+	}
+
+	otherConfig, _ := cache.Configure(cluster, restclient.CopyConfig(config))
+	if otherConfig.Transport != sharedConfig.Transport {
+		t.Fatalf("Expected the transport to be shared by configurations with the same credentials")
+	}
+
+	rotatedConfig := restclient.CopyConfig(config)
+	rotatedConfig.BearerToken = "token2"
+	if rotated, _ := cache.Configure(cluster, rotatedConfig); rotated.Transport == sharedConfig.Transport {
+		t.Fatalf("Expected a new transport after a rotation of the credentials")
+	}
+
+	updated := cluster.DeepCopy()
+	updated.Generation = 2
+	if updatedConfig, _ := cache.Configure(updated, rotatedConfig); updatedConfig.Transport == sharedConfig.Transport {
+		t.Fatalf("Expected a new transport after a change of the cluster spec")
+	}
+
+	cache.Forget(cluster.Name)
+	if _, ok := cache.entries[cluster.Name]; ok {
+		t.Fatalf("Expected a forgotten cluster to be removed from the cache")
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// restrictedTLSVersion is the minimum TLS version of connections to
+// member clusters in the restricted compliance mode.
+const restrictedTLSVersion = "1.2"
+
+// The cipher suites approved for connections to member clusters in
+// the restricted compliance mode. TLS 1.3 suites are not configurable
+// and are all approved.
+var restrictedCipherSuites = []struct {
+	id   uint16
+	name string
+}{
+	{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+	{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+	{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+}
+
+// restrictedCompliance is non-zero if the process runs in the
+// restricted compliance mode. The mode applies to the whole process
+// since it governs every connection the process makes.
+var restrictedCompliance int32
+
+// SetComplianceMode sets the compliance mode of the process.
+func SetComplianceMode(mode fedv1b1.ComplianceMode) {
+	var restricted int32
+	if mode == fedv1b1.ComplianceModeRestricted {
+		restricted = 1
+	}
+	atomic.StoreInt32(&restrictedCompliance, restricted)
+}
+
+// RestrictedCompliance returns whether the process runs in the
+// restricted compliance mode, in which connections to member clusters
+// are restricted to approved TLS versions and cipher suites and no
+// outbound calls are made other than to the API servers of the host
+// and member clusters.
+func RestrictedCompliance() bool {
+	return atomic.LoadInt32(&restrictedCompliance) != 0
+}
+
+// RestrictTLSConfig restricts the given TLS configuration to the TLS
+// versions and cipher suites of the restricted compliance mode.
+func RestrictTLSConfig(tlsConfig *tls.Config) {
+	tlsConfig.MinVersion = tls.VersionTLS12
+	tlsConfig.CipherSuites = make([]uint16, 0, len(restrictedCipherSuites))
+	for _, suite := range restrictedCipherSuites {
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, suite.id)
+	}
+}
+
+// NewComplianceStatus returns the compliance posture of the process,
+// given the outbound integrations that were disabled by its mode.
+func NewComplianceStatus(disabledIntegrations []string) *fedv1b1.ComplianceStatus {
+	now := metav1.Now()
+	status := &fedv1b1.ComplianceStatus{
+		Mode:                 fedv1b1.ComplianceModeStandard,
+		DisabledIntegrations: disabledIntegrations,
+		LastUpdateTime:       &now,
+	}
+	if !RestrictedCompliance() {
+		return status
+	}
+	status.Mode = fedv1b1.ComplianceModeRestricted
+	status.MinTLSVersion = restrictedTLSVersion
+	for _, suite := range restrictedCipherSuites {
+		status.CipherSuites = append(status.CipherSuites, suite.name)
+	}
+	return status
+}
+
+// restrictClusterTransport configures the given client configuration
+// for the named cluster to connect with the TLS versions and cipher
+// suites of the restricted compliance mode.
+func restrictClusterTransport(clusterName string, clientConfig *restclient.Config) error {
+	if clientConfig.Insecure || strings.HasPrefix(clientConfig.Host, "http://") {
+		return errors.Errorf("Cluster %s may not be accessed insecurely in the restricted compliance mode", clusterName)
+	}
+	if clientConfig.Transport != nil {
+		customTransport, ok := clientConfig.Transport.(*http.Transport)
+		if !ok {
+			return errors.Errorf("Cluster %s uses a transport that cannot be restricted", clusterName)
+		}
+		customTransport = customTransport.Clone()
+		if customTransport.TLSClientConfig == nil {
+			customTransport.TLSClientConfig = &tls.Config{}
+		}
+		RestrictTLSConfig(customTransport.TLSClientConfig)
+		clientConfig.Transport = customTransport
+		return nil
+	}
+
+	clientTransportConfig, err := clientConfig.TransportConfig()
+	if err != nil {
+		return errors.Errorf("Cluster %s client transport config error: %s", clusterName, err)
+	}
+	tlsConfig, err := transport.TLSConfigFor(clientTransportConfig)
+	if err != nil {
+		return errors.Errorf("Cluster %s transport error: %s", clusterName, err)
+	}
+	if tlsConfig == nil {
+		// The cluster is verified with the system roots.
+		tlsConfig = &tls.Config{}
+	}
+	RestrictTLSConfig(tlsConfig)
+	clientConfig.Transport = newTLSTransport(tlsConfig)
+	clientConfig.TLSClientConfig = restclient.TLSClientConfig{}
+	return nil
+}
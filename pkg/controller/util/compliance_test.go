@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	restclient "k8s.io/client-go/rest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestRestrictClusterTransport(t *testing.T) {
+	testCases := map[string]struct {
+		config        *restclient.Config
+		expectedError bool
+	}{
+		"Cluster verified with the system roots is restricted": {
+			config: &restclient.Config{Host: "https://example.com"},
+		},
+		"Cluster with a custom transport is restricted": {
+			config: &restclient.Config{
+				Host:      "https://example.com",
+				Transport: &http.Transport{},
+			},
+		},
+		"Insecure cluster is refused": {
+			config: &restclient.Config{
+				Host:            "https://example.com",
+				TLSClientConfig: restclient.TLSClientConfig{Insecure: true},
+			},
+			expectedError: true,
+		},
+		"Cluster without TLS is refused": {
+			config:        &restclient.Config{Host: "http://example.com"},
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := restrictClusterTransport("test", tc.config)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			transport, ok := tc.config.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Expected an *http.Transport, got %T", tc.config.Transport)
+			}
+			tlsConfig := transport.TLSClientConfig
+			if tlsConfig.MinVersion != tls.VersionTLS12 {
+				t.Errorf("Expected minimum TLS version %x, got %x", tls.VersionTLS12, tlsConfig.MinVersion)
+			}
+			if len(tlsConfig.CipherSuites) != len(restrictedCipherSuites) {
+				t.Errorf("Expected %d cipher suites, got %d", len(restrictedCipherSuites), len(tlsConfig.CipherSuites))
+			}
+		})
+	}
+}
+
+func TestNewComplianceStatus(t *testing.T) {
+	defer SetComplianceMode(fedv1b1.ComplianceModeStandard)
+
+	SetComplianceMode(fedv1b1.ComplianceModeStandard)
+	status := NewComplianceStatus(nil)
+	if status.Mode != fedv1b1.ComplianceModeStandard || len(status.CipherSuites) != 0 {
+		t.Errorf("Unexpected status in the standard mode: %#v", status)
+	}
+
+	SetComplianceMode(fedv1b1.ComplianceModeRestricted)
+	status = NewComplianceStatus([]string{"tracing"})
+	if status.Mode != fedv1b1.ComplianceModeRestricted {
+		t.Errorf("Expected mode %q, got %q", fedv1b1.ComplianceModeRestricted, status.Mode)
+	}
+	if status.MinTLSVersion != restrictedTLSVersion {
+		t.Errorf("Expected minimum TLS version %q, got %q", restrictedTLSVersion, status.MinTLSVersion)
+	}
+	if len(status.CipherSuites) != len(restrictedCipherSuites) {
+		t.Errorf("Expected %d cipher suites, got %d", len(restrictedCipherSuites), len(status.CipherSuites))
+	}
+	if len(status.DisabledIntegrations) != 1 || status.DisabledIntegrations[0] != "tracing" {
+		t.Errorf("Unexpected disabled integrations: %v", status.DisabledIntegrations)
+	}
+}
@@ -29,8 +29,23 @@ const (
 
 	ServiceKind = "Service"
 
+	// ServiceNameLabel is set by the EndpointSlice controller on
+	// every slice it manages to identify the owning service.
+	ServiceNameLabel = "kubernetes.io/service-name"
+
 	ServiceAccountKind = "ServiceAccount"
 
+	NetworkPolicyKind = "NetworkPolicy"
+
+	SecretKind = "Secret"
+
+	ConfigMapKind = "ConfigMap"
+
+	JobKind = "Job"
+
+	PersistentVolumeClaimName = "persistentvolumeclaims"
+	PersistentVolumeClaimKind = "PersistentVolumeClaim"
+
 	// The following fields are used to interact with unstructured
 	// resources.
 
@@ -42,17 +57,26 @@ const (
 	// ServiceAccount fields
 	SecretsField = "secrets"
 
+	// PersistentVolumeClaim fields
+	VolumeNameField       = "volumeName"
+	StorageClassNameField = "storageClassName"
+
 	// Scale types
 	ReplicasField       = "replicas"
 	RetainReplicasField = "retainReplicas"
 
 	// Template fields
-	TemplateField = "template"
+	TemplateField    = "template"
+	TemplateRefField = "templateRef"
 
 	// Placement fields
-	PlacementField       = "placement"
-	ClusterSelectorField = "clusterSelector"
-	MatchLabelsField     = "matchLabels"
+	PlacementField               = "placement"
+	ClusterGroupsField           = "clusterGroups"
+	ClusterSelectorField         = "clusterSelector"
+	MatchLabelsField             = "matchLabels"
+	RequiredClusterSelectorField = "requiredClusterSelector"
+	RequiredCRDsField            = "requiredCRDs"
+	VolumeClaimsField            = "volumeClaims"
 
 	// Override fields
 	OverridesField        = "overrides"
@@ -58,10 +58,28 @@ type KubeFedNamespaces struct {
 
 // ClusterHealthCheckConfig defines the configurable parameters for cluster health check
 type ClusterHealthCheckConfig struct {
-	Period           time.Duration
-	FailureThreshold int64
-	SuccessThreshold int64
-	Timeout          time.Duration
+	Period               time.Duration
+	FailureThreshold     int64
+	EdgeFailureThreshold int64
+	SuccessThreshold     int64
+	Timeout              time.Duration
+	// Deadline is the duration after which a health check that has not
+	// completed no longer occupies a worker. Zero means no deadline.
+	Deadline time.Duration
+	// Workers is the maximum number of concurrent health checks.
+	Workers int64
+	// JitterPercentage is the maximum percentage of the period by
+	// which health checks are randomly delayed.
+	JitterPercentage int64
+}
+
+// FailureThresholdFor returns the minimum consecutive failures for the
+// health of the given cluster to be considered failed.
+func (c *ClusterHealthCheckConfig) FailureThresholdFor(cluster *fedv1b1.KubeFedCluster) int64 {
+	if IsEdgeCluster(cluster) && c.EdgeFailureThreshold > 0 {
+		return c.EdgeFailureThreshold
+	}
+	return c.FailureThreshold
 }
 
 // ControllerConfig defines the configuration common to KubeFed
@@ -73,6 +91,53 @@ type ControllerConfig struct {
 	ClusterUnavailableDelay time.Duration
 	MinimizeLatency         bool
 	SkipAdoptingResources   bool
+	DebounceWindow          time.Duration
+	DeletionHold            time.Duration
+	PropagatedMetadata      *fedv1b1.PropagatedMetadataConfig
+	Quarantine              *fedv1b1.QuarantineConfig
+	StatusCollection        *fedv1b1.AdaptiveStatusCollectionConfig
+	// ImpersonateServiceAccount, if set, is the name of the service
+	// account impersonated in the namespace of each namespaced
+	// resource applied to member clusters.
+	ImpersonateServiceAccount string
+	// InstanceName, if set, is the name of the KubeFedInstance of
+	// the control plane. Resources propagated to member clusters are
+	// labeled with it to distinguish them from those of other
+	// control planes.
+	InstanceName string
+	// ApplyObserver, if set, is notified of the outcome of every
+	// create and update of a resource in a member cluster.
+	ApplyObserver ApplyObserver
+	// ClusterOperationTimeout is the duration after which requests to
+	// member clusters are cancelled. Requests are not cancelled if 0.
+	ClusterOperationTimeout time.Duration
+	// ClusterLatency, if set, tracks the latency of requests to
+	// member clusters so that the sync controller can propagate to
+	// slow clusters separately.
+	ClusterLatency *ClusterLatencyTracker
+	// PropagationNotifier, if set, is notified of the transitions of
+	// the propagation state of federated resources.
+	PropagationNotifier *PropagationNotifier
+	// RevisionHistoryLimit is the number of revisions kept for each
+	// federated resource if the RevisionHistory feature is enabled.
+	// The default limit is used if 0.
+	RevisionHistoryLimit int
+	// ClusterApplyLimiter, if set, limits the rate at which resources
+	// are created and updated in member clusters.
+	ClusterApplyLimiter *ClusterApplyLimiter
+	// DiscoveryCache, if set, caches the API discovery of member
+	// clusters for the clients created for them.
+	DiscoveryCache *ClusterDiscoveryCache
+	// ClusterTransports, if set, provides the transport shared by the
+	// clients created for each member cluster.
+	ClusterTransports *ClusterTransportCache
+	// SchemaCache, if set, caches the OpenAPI schemas of member
+	// clusters so that fields defaulted by a cluster are ignored when
+	// comparing resources.
+	SchemaCache *ClusterSchemaCache
+	// NamespaceSameness configures the verification that federated
+	// namespaces are the same in every member cluster.
+	NamespaceSameness *fedv1b1.NamespaceSamenessConfig
 }
 
 func (c *ControllerConfig) LimitedScope() bool {
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CronSchedule is a parsed cron expression with the fields minute,
+// hour, day of month, month and day of week.
+type CronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	// Following cron, if both day fields are restricted a day matches
+	// if either of them does.
+	daysOfMonthRestricted bool
+	daysOfWeekRestricted  bool
+}
+
+// ParseCronSchedule parses a cron expression of 5 space-separated
+// fields. Each field is a '*', a value, a range 'a-b' or a comma-separated
+// list thereof, optionally followed by a step '/n'. Day of week 7 is
+// Sunday like 0.
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("expected 5 fields in cron schedule %q, found %d", spec, len(fields))
+	}
+	schedule := &CronSchedule{}
+	var err error
+	if schedule.minutes, _, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, errors.Wrap(err, "invalid minute")
+	}
+	if schedule.hours, _, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, errors.Wrap(err, "invalid hour")
+	}
+	if schedule.daysOfMonth, schedule.daysOfMonthRestricted, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, errors.Wrap(err, "invalid day of month")
+	}
+	if schedule.months, _, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, errors.Wrap(err, "invalid month")
+	}
+	if schedule.daysOfWeek, schedule.daysOfWeekRestricted, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, errors.Wrap(err, "invalid day of week")
+	}
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+	return schedule, nil
+}
+
+// parseCronField returns the values of a cron field as a bit set and
+// whether the field restricts the values at all.
+func parseCronField(field string, min, max int) (uint64, bool, error) {
+	var bits uint64
+	restricted := false
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, false, errors.Errorf("invalid step in %q", part)
+			}
+		}
+		var low, high int
+		switch {
+		case rangePart == "*":
+			low, high = min, max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = parseCronValue(bounds[0], min, max); err != nil {
+				return 0, false, err
+			}
+			if high, err = parseCronValue(bounds[1], min, max); err != nil {
+				return 0, false, err
+			}
+			if low > high {
+				return 0, false, errors.Errorf("invalid range %q", rangePart)
+			}
+			restricted = true
+		default:
+			var err error
+			if low, err = parseCronValue(rangePart, min, max); err != nil {
+				return 0, false, err
+			}
+			high = low
+			if step != 1 {
+				high = max
+			}
+			restricted = true
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, restricted, nil
+}
+
+func parseCronValue(value string, min, max int) (int, error) {
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q", value)
+	}
+	if result < min || result > max {
+		return 0, errors.Errorf("value %d out of range [%d, %d]", result, min, max)
+	}
+	return result, nil
+}
+
+// Next returns the first time matching the schedule strictly after the
+// given time, in the location of the given time. The zero time is
+// returned if the schedule never matches within 5 years (e.g. for
+// February 30th).
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	location := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, location)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.daysOfMonthRestricted && s.daysOfWeekRestricted {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
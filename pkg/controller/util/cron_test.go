@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2020, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		schedule string
+		expected time.Time
+	}{
+		"Every minute": {
+			schedule: "* * * * *",
+			expected: time.Date(2020, time.January, 15, 10, 31, 0, 0, time.UTC),
+		},
+		"Later the same day": {
+			schedule: "0 22 * * *",
+			expected: time.Date(2020, time.January, 15, 22, 0, 0, 0, time.UTC),
+		},
+		"Next day": {
+			schedule: "0 2 * * *",
+			expected: time.Date(2020, time.January, 16, 2, 0, 0, 0, time.UTC),
+		},
+		"Step": {
+			schedule: "*/20 * * * *",
+			expected: time.Date(2020, time.January, 15, 10, 40, 0, 0, time.UTC),
+		},
+		"List and range": {
+			schedule: "0 1,3-5 * * *",
+			expected: time.Date(2020, time.January, 16, 1, 0, 0, 0, time.UTC),
+		},
+		"Day of week": {
+			schedule: "0 2 * * 6",
+			expected: time.Date(2020, time.January, 18, 2, 0, 0, 0, time.UTC),
+		},
+		"Sunday as 7": {
+			schedule: "0 2 * * 7",
+			expected: time.Date(2020, time.January, 19, 2, 0, 0, 0, time.UTC),
+		},
+		"Day of month and month": {
+			schedule: "0 0 1 3 *",
+			expected: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"Either day field": {
+			schedule: "0 0 20 * 5",
+			expected: time.Date(2020, time.January, 17, 0, 0, 0, 0, time.UTC),
+		},
+		"Leap day": {
+			schedule: "0 0 29 2 *",
+			expected: time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		"Never": {
+			schedule: "0 0 30 2 *",
+			expected: time.Time{},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tc.schedule)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := schedule.Next(now)
+			if !result.Equal(tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseCronSchedule(schedule); err == nil {
+			t.Errorf("Expected an error for schedule %q", schedule)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+// QueueStatus describes the work queue of a reconcile worker.
+type QueueStatus struct {
+	// Name of the controller owning the worker.
+	Name string `json:"name"`
+	// Number of items waiting to be reconciled.
+	Length int `json:"length"`
+	// Seconds the oldest item has been waiting to be reconciled.
+	OldestItemAgeSeconds float64 `json:"oldestItemAgeSeconds"`
+}
+
+// InformerSyncStatus describes whether the informers of a federated
+// informer have synced in each ready cluster.
+type InformerSyncStatus struct {
+	// Name of the resource watched by the informer.
+	Name string `json:"name"`
+	// Whether the informer for each cluster has synced, keyed by
+	// cluster name.
+	Clusters map[string]bool `json:"clusters"`
+}
+
+// debugRegistry tracks the running workers and federated informers so
+// that their state can be served by the debug endpoints.
+type debugRegistry struct {
+	sync.Mutex
+	workers   map[*asyncWorker]struct{}
+	informers map[*federatedInformerImpl]struct{}
+}
+
+var registry = &debugRegistry{
+	workers:   make(map[*asyncWorker]struct{}),
+	informers: make(map[*federatedInformerImpl]struct{}),
+}
+
+func (r *debugRegistry) addWorker(w *asyncWorker) {
+	r.Lock()
+	defer r.Unlock()
+	r.workers[w] = struct{}{}
+}
+
+func (r *debugRegistry) removeWorker(w *asyncWorker) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.workers, w)
+}
+
+func (r *debugRegistry) addInformer(f *federatedInformerImpl) {
+	r.Lock()
+	defer r.Unlock()
+	r.informers[f] = struct{}{}
+}
+
+func (r *debugRegistry) removeInformer(f *federatedInformerImpl) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.informers, f)
+}
+
+// QueueStatuses returns the status of the queues of all running
+// reconcile workers, sorted by name.
+func QueueStatuses() []QueueStatus {
+	registry.Lock()
+	defer registry.Unlock()
+
+	statuses := []QueueStatus{}
+	for w := range registry.workers {
+		statuses = append(statuses, w.queueStatus())
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// InformerSyncStatuses returns the per-cluster sync status of all
+// running federated informers, sorted by name.
+func InformerSyncStatuses() []InformerSyncStatus {
+	registry.Lock()
+	defer registry.Unlock()
+
+	statuses := []InformerSyncStatus{}
+	for f := range registry.informers {
+		statuses = append(statuses, f.SyncStatus())
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// RegisterDebugHandlers adds handlers serving the queue and informer
+// sync status of the running controllers to the given mux.
+func RegisterDebugHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/queues", func(w http.ResponseWriter, _ *http.Request) {
+		writeDebugJSON(w, QueueStatuses())
+	})
+	mux.HandleFunc("/debug/informers", func(w http.ResponseWriter, _ *http.Request) {
+		writeDebugJSON(w, InformerSyncStatuses())
+	})
+}
+
+// ServeDebug serves the given debug handler at the given address. The
+// handler is served over TLS with the given certificate and key files
+// if provided. Since requests carry bearer tokens, the handler is only
+// served over plain HTTP on a loopback address.
+func ServeDebug(address, certFile, keyFile string, handler http.Handler) error {
+	serveTLS := len(certFile) > 0 || len(keyFile) > 0
+	if err := ValidateServingAddress(address, serveTLS); err != nil {
+		return err
+	}
+
+	server := &http.Server{Addr: address, Handler: handler}
+	if RestrictedCompliance() {
+		server.TLSConfig = &tls.Config{}
+		RestrictTLSConfig(server.TLSConfig)
+	}
+	if serveTLS {
+		klog.Infof("Serving debug endpoints at %s over TLS", address)
+		return server.ListenAndServeTLS(certFile, keyFile)
+	}
+	klog.Infof("Serving debug endpoints at %s", address)
+	return server.ListenAndServe()
+}
+
+// AuthorizeDebugRequests returns a handler that only passes on
+// requests whose bearer token is authenticated by the API server of
+// the host cluster with a TokenReview and whose user is permitted by a
+// SubjectAccessReview to access the requested non-resource path, e.g.
+// `get` on `/debug/pprof/heap`.
+func AuthorizeDebugRequests(client kubeclientset.Interface, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "Bearer "
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, prefix) || len(auth) == len(prefix) {
+			http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+			return
+		}
+		tokenReview, err := client.AuthenticationV1().TokenReviews().Create(&authnv1.TokenReview{
+			Spec: authnv1.TokenReviewSpec{Token: strings.TrimPrefix(auth, prefix)},
+		})
+		if err != nil {
+			klog.Errorf("Error reviewing the token of a debug request: %v", err)
+			http.Error(w, "unable to authenticate the request", http.StatusInternalServerError)
+			return
+		}
+		if !tokenReview.Status.Authenticated {
+			http.Error(w, "the bearer token is not valid", http.StatusUnauthorized)
+			return
+		}
+
+		user := tokenReview.Status.User
+		extra := make(map[string]authzv1.ExtraValue, len(user.Extra))
+		for key, value := range user.Extra {
+			extra[key] = authzv1.ExtraValue(value)
+		}
+		accessReview, err := client.AuthorizationV1().SubjectAccessReviews().Create(&authzv1.SubjectAccessReview{
+			Spec: authzv1.SubjectAccessReviewSpec{
+				User:   user.Username,
+				UID:    user.UID,
+				Groups: user.Groups,
+				Extra:  extra,
+				NonResourceAttributes: &authzv1.NonResourceAttributes{
+					Path: r.URL.Path,
+					Verb: strings.ToLower(r.Method),
+				},
+			},
+		})
+		if err != nil {
+			klog.Errorf("Error reviewing the access of %q to %q: %v", user.Username, r.URL.Path, err)
+			http.Error(w, "unable to authorize the request", http.StatusInternalServerError)
+			return
+		}
+		if !accessReview.Status.Allowed {
+			http.Error(w, "access to the debug endpoint is forbidden", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func writeDebugJSON(w http.ResponseWriter, obj interface{}) {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestQueueStatuses(t *testing.T) {
+	blocked := make(chan struct{})
+	worker := NewReconcileWorker("test", func(qualifiedName QualifiedName) ReconciliationStatus {
+		<-blocked
+		return StatusAllOK
+	}, WorkerTiming{}).(*asyncWorker)
+
+	stopChan := make(chan struct{})
+	worker.Run(stopChan)
+	defer close(stopChan)
+	defer close(blocked)
+
+	worker.Enqueue(QualifiedName{Namespace: "ns", Name: "a"})
+	worker.Enqueue(QualifiedName{Namespace: "ns", Name: "b"})
+	worker.Enqueue(QualifiedName{Namespace: "ns", Name: "c"})
+
+	// The first item is being reconciled and the remaining items are
+	// waiting in the queue.
+	var status QueueStatus
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		for _, status = range QueueStatuses() {
+			if status.Name == "test" {
+				return status.Length == 2, nil
+			}
+		}
+		return false, nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, status.OldestItemAgeSeconds > 0)
+}
+
+func TestAuthorizeDebugRequests(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authnv1.TokenReview)
+		review.Status.Authenticated = review.Spec.Token != "invalid"
+		review.Status.User.Username = review.Spec.Token
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+		attributes := review.Spec.NonResourceAttributes
+		review.Status.Allowed = review.Spec.User == "admin" && attributes.Verb == "get" && attributes.Path == "/debug/queues"
+		return true, review, nil
+	})
+	handler := AuthorizeDebugRequests(client, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := map[string]struct {
+		token        string
+		path         string
+		expectedCode int
+	}{
+		"No token": {
+			path:         "/debug/queues",
+			expectedCode: http.StatusUnauthorized,
+		},
+		"Invalid token": {
+			token:        "invalid",
+			path:         "/debug/queues",
+			expectedCode: http.StatusUnauthorized,
+		},
+		"Unauthorized user": {
+			token:        "viewer",
+			path:         "/debug/queues",
+			expectedCode: http.StatusForbidden,
+		},
+		"Unauthorized path": {
+			token:        "admin",
+			path:         "/debug/pprof/heap",
+			expectedCode: http.StatusForbidden,
+		},
+		"Authorized user and path": {
+			token:        "admin",
+			path:         "/debug/queues",
+			expectedCode: http.StatusOK,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if len(tc.token) > 0 {
+				request.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestServeDebugRejectsNonLoopbackAddress(t *testing.T) {
+	testCases := map[string]struct {
+		address string
+	}{
+		"All interfaces": {
+			address: ":8081",
+		},
+		"Non-loopback address": {
+			address: "10.0.0.1:8081",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Validation fails before the address is bound.
+			err := ServeDebug(tc.address, "", "", http.NotFoundHandler())
+			if err == nil {
+				t.Fatalf("Expected serving over plain HTTP at %q to be rejected, got %v", tc.address, err)
+			}
+		})
+	}
+}
@@ -61,3 +61,25 @@ func TestDelayingDeliverer(t *testing.T) {
 		// Ok. Expected
 	}
 }
+
+func TestDelayingDelivererCoalescesLaterDeliveries(t *testing.T) {
+	targetChannel := make(chan *DelayingDelivererItem)
+	now := time.Now()
+	d := NewDelayingDelivererWithChannel(targetChannel)
+	d.Start()
+	defer d.Stop()
+	d.DeliverAt("a", "first", now.Add(100*time.Millisecond))
+	d.DeliverAt("a", "second", now.Add(200*time.Millisecond))
+	d.DeliverAt("a", "third", now.Add(300*time.Millisecond))
+
+	i0 := <-targetChannel
+	assert.Equal(t, "a", i0.Key)
+	assert.Equal(t, "first", i0.Value.(string))
+
+	select {
+	case <-targetChannel:
+		t.Fatalf("Later deliveries of the same key should be coalesced")
+	case <-time.After(500 * time.Millisecond):
+		// Ok. Expected
+	}
+}
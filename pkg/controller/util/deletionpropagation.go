@@ -0,0 +1,37 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// If this annotation is set to "Foreground" on a FederatedJob, the
+	// removal of its Jobs from member clusters waits for the Jobs to
+	// finish and for their ttlSecondsAfterFinished to elapse, and the
+	// completion of the Jobs is recorded in the status of the
+	// FederatedJob.
+	DeletionPropagationPolicyAnnotation = "kubefed.io/deletion-propagation-policy"
+)
+
+// IsForegroundDeletion returns whether the deletion of the given
+// federated resource is propagated in the foreground.
+func IsForegroundDeletion(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[DeletionPropagationPolicyAnnotation] == string(metav1.DeletePropagationForeground)
+}
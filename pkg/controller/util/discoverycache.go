@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// ClusterDiscoveryCache caches the API discovery of member clusters so
+// that the clients of a cluster share a single discovery that is only
+// fetched again when the APIs served by the cluster change.
+type ClusterDiscoveryCache struct {
+	sync.Mutex
+
+	entries map[string]*discoveryCacheEntry
+}
+
+type discoveryCacheEntry struct {
+	// The generation of the KubeFedCluster the discovery was fetched
+	// for. A change of the spec of the cluster may change its
+	// endpoint or credentials.
+	generation int64
+
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+
+	// Digest of the APIs served by the cluster as of the last
+	// revalidation. Empty until the entry is first revalidated.
+	digest string
+
+	// The time the discovery was last fetched or invalidated.
+	lastRefreshTime metav1.Time
+}
+
+// NewClusterDiscoveryCache returns an empty discovery cache.
+func NewClusterDiscoveryCache() *ClusterDiscoveryCache {
+	return &ClusterDiscoveryCache{
+		entries: make(map[string]*discoveryCacheEntry),
+	}
+}
+
+// RESTMapper returns a mapper for the given cluster that is backed by
+// its cached discovery. The discovery is fetched with the given
+// configuration when the mapper is first used.
+func (c *ClusterDiscoveryCache) RESTMapper(cluster *fedv1b1.KubeFedCluster, config *restclient.Config) (meta.RESTMapper, error) {
+	c.Lock()
+	defer c.Unlock()
+	if entry, ok := c.entries[cluster.Name]; ok && entry.generation == cluster.Generation {
+		return entry.mapper, nil
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	entry := &discoveryCacheEntry{
+		generation:      cluster.Generation,
+		mapper:          restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		lastRefreshTime: metav1.Now(),
+	}
+	c.entries[cluster.Name] = entry
+	return entry.mapper, nil
+}
+
+// Revalidate compares the given inventory of the named cluster with
+// the inventory the cached discovery was last validated against, and
+// invalidates the discovery if the CRDs or API versions of the cluster
+// have changed. The inventory acts as a cheap validator, in the manner
+// of an ETag, that avoids fetching the resources of every API group to
+// detect changes. Returns whether the discovery was invalidated.
+func (c *ClusterDiscoveryCache) Revalidate(clusterName string, inventory *fedv1b1.ClusterInventory) bool {
+	digest := inventoryDigest(inventory)
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[clusterName]
+	if !ok {
+		return false
+	}
+	if entry.digest == "" || entry.digest == digest {
+		// The discovery fetched before the first revalidation is
+		// assumed to be consistent with the inventory.
+		entry.digest = digest
+		return false
+	}
+	entry.digest = digest
+	entry.mapper.Reset()
+	entry.lastRefreshTime = metav1.Now()
+	return true
+}
+
+// Invalidate discards the cached discovery of the named cluster so that
+// it is fetched again when next used.
+func (c *ClusterDiscoveryCache) Invalidate(clusterName string) {
+	c.Lock()
+	defer c.Unlock()
+	if entry, ok := c.entries[clusterName]; ok {
+		entry.mapper.Reset()
+		entry.lastRefreshTime = metav1.Now()
+	}
+}
+
+// Forget removes the named cluster from the cache.
+func (c *ClusterDiscoveryCache) Forget(clusterName string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, clusterName)
+}
+
+// Status returns the status of the cached discovery of the named
+// cluster, or nil if its discovery is not cached.
+func (c *ClusterDiscoveryCache) Status(clusterName string) *fedv1b1.ClusterDiscoveryCache {
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[clusterName]
+	if !ok {
+		return nil
+	}
+	return &fedv1b1.ClusterDiscoveryCache{
+		LastRefreshTime: entry.lastRefreshTime,
+	}
+}
+
+// inventoryDigest returns a digest of the CRDs and API versions of the
+// given inventory.
+func inventoryDigest(inventory *fedv1b1.ClusterInventory) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n", strings.Join(inventory.CRDs, ","))
+	fmt.Fprintf(hash, "%s\n", strings.Join(inventory.APIVersions, ","))
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
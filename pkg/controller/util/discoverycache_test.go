@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	restclient "k8s.io/client-go/rest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestClusterDiscoveryCache(t *testing.T) {
+	cache := NewClusterDiscoveryCache()
+	cluster := &fedv1b1.KubeFedCluster{}
+	cluster.Name = "cluster1"
+	cluster.Generation = 1
+	config := &restclient.Config{Host: "https://cluster1.example.com"}
+
+	if status := cache.Status(cluster.Name); status != nil {
+		t.Fatalf("Expected no status for an uncached cluster, got %v", status)
+	}
+
+	mapper, err := cache.RESTMapper(cluster, config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cachedMapper, _ := cache.RESTMapper(cluster, config); cachedMapper != mapper {
+		t.Fatalf("Expected the cached mapper to be returned")
+	}
+	if cache.Status(cluster.Name) == nil {
+		t.Fatalf("Expected status for a cached cluster")
+	}
+
+	inventory := &fedv1b1.ClusterInventory{
+		CRDs:        []string{"certificates.cert-manager.io"},
+		APIVersions: []string{"apps/v1", "v1"},
+	}
+	if cache.Revalidate(cluster.Name, inventory) {
+		t.Fatalf("Expected the first revalidation not to invalidate the discovery")
+	}
+	if cache.Revalidate(cluster.Name, inventory.DeepCopy()) {
+		t.Fatalf("Expected revalidation with an unchanged inventory not to invalidate the discovery")
+	}
+	changed := inventory.DeepCopy()
+	changed.CRDs = append(changed.CRDs, "issuers.cert-manager.io")
+	if !cache.Revalidate(cluster.Name, changed) {
+		t.Fatalf("Expected revalidation with a changed inventory to invalidate the discovery")
+	}
+
+	updated := cluster.DeepCopy()
+	updated.Generation = 2
+	if updatedMapper, _ := cache.RESTMapper(updated, config); updatedMapper == mapper {
+		t.Fatalf("Expected a new mapper after a change of the cluster spec")
+	}
+
+	cache.Forget(cluster.Name)
+	if status := cache.Status(cluster.Name); status != nil {
+		t.Fatalf("Expected no status for a forgotten cluster, got %v", status)
+	}
+	if cache.Revalidate(cluster.Name, inventory) {
+		t.Fatalf("Expected revalidation of an uncached cluster not to invalidate anything")
+	}
+}
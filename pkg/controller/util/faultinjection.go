@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/client/generic"
+)
+
+// FaultInjector injects the faults described by the FaultInjection
+// resources of the KubeFed system namespace into the requests made by
+// the clients for member clusters.
+type FaultInjector struct {
+	store cache.Store
+}
+
+// faultInjector is set by StartFaultInjector and consulted when a
+// client for a member cluster is created.
+var faultInjector *FaultInjector
+
+// StartFaultInjector starts watching FaultInjection resources and
+// causes the clients subsequently created for member clusters to
+// inject the faults they describe. It must be called before the
+// controllers creating those clients are started.
+func StartFaultInjector(config *ControllerConfig, stopChan <-chan struct{}) error {
+	store, controller, err := NewGenericInformer(
+		config.KubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.FaultInjection{},
+		NoResyncPeriod,
+		func(pkgruntime.Object) {},
+	)
+	if err != nil {
+		return err
+	}
+	go controller.Run(stopChan)
+	faultInjector = &FaultInjector{store: store}
+	klog.Infof("Injecting faults into the requests made to member clusters")
+	return nil
+}
+
+// wrapClientForCluster returns a client for the named cluster that
+// injects faults if the fault injector has been started, and the
+// given client otherwise. Cluster labels are looked up for every
+// request so that changes to them apply to existing clients.
+func wrapClientForCluster(client generic.Client, clusterName string, getCluster func(string) (*fedv1b1.KubeFedCluster, bool, error)) generic.Client {
+	injector := faultInjector
+	if injector == nil {
+		return client
+	}
+	return generic.NewFaultInjectingClient(client, func(ctx context.Context, operation fedv1b1.FaultOperation) error {
+		var clusterLabels map[string]string
+		if cluster, found, err := getCluster(clusterName); err == nil && found {
+			clusterLabels = cluster.Labels
+		}
+		return injector.Inject(ctx, clusterName, clusterLabels, operation)
+	})
+}
+
+// Inject delays the given operation against the named cluster by the
+// latency of the FaultInjections that apply to it and returns an error
+// if the operation is chosen to fail.
+func (i *FaultInjector) Inject(ctx context.Context, clusterName string, clusterLabels map[string]string, operation fedv1b1.FaultOperation) error {
+	var latency time.Duration
+	var failure *fedv1b1.FaultInjection
+	for _, obj := range i.store.List() {
+		faultInjection := obj.(*fedv1b1.FaultInjection)
+		if !faultApplies(&faultInjection.Spec, clusterName, clusterLabels, operation) {
+			continue
+		}
+		if faultInjection.Spec.Latency != nil {
+			latency += faultInjection.Spec.Latency.Duration
+		}
+		if failure == nil && rand.Int31n(100) < faultInjection.Spec.ErrorPercentage {
+			failure = faultInjection
+		}
+	}
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if failure != nil {
+		return injectedError(failure, clusterName, operation)
+	}
+	return nil
+}
+
+// faultApplies checks whether the given fault injection spec applies
+// to the given operation against the named cluster.
+func faultApplies(spec *fedv1b1.FaultInjectionSpec, clusterName string, clusterLabels map[string]string, operation fedv1b1.FaultOperation) bool {
+	if len(spec.Operations) > 0 {
+		found := false
+		for _, specOperation := range spec.Operations {
+			if specOperation == operation {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(spec.Clusters) > 0 {
+		for _, name := range spec.Clusters {
+			if name == clusterName {
+				return true
+			}
+		}
+		return false
+	}
+	if spec.ClusterSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.ClusterSelector)
+		if err != nil {
+			return false
+		}
+		return selector.Matches(labels.Set(clusterLabels))
+	}
+	return true
+}
+
+func injectedError(faultInjection *fedv1b1.FaultInjection, clusterName string, operation fedv1b1.FaultOperation) error {
+	code := int(faultInjection.Spec.ErrorCode)
+	if code == 0 {
+		code = http.StatusServiceUnavailable
+	}
+	message := fmt.Sprintf("fault injected by FaultInjection %q into %s request to cluster %q", faultInjection.Name, operation, clusterName)
+	return apierrors.NewGenericServerResponse(code, string(operation), schema.GroupResource{Group: fedv1b1.SchemeGroupVersion.Group, Resource: "faultinjections"}, faultInjection.Name, message, 0, false)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestFaultApplies(t *testing.T) {
+	clusterLabels := map[string]string{"region": "europe"}
+
+	testCases := map[string]struct {
+		spec     fedv1b1.FaultInjectionSpec
+		expected bool
+	}{
+		"All clusters and operations": {
+			spec:     fedv1b1.FaultInjectionSpec{},
+			expected: true,
+		},
+		"Named cluster": {
+			spec:     fedv1b1.FaultInjectionSpec{Clusters: []string{"cluster2", "cluster1"}},
+			expected: true,
+		},
+		"Other named cluster": {
+			spec:     fedv1b1.FaultInjectionSpec{Clusters: []string{"cluster2"}},
+			expected: false,
+		},
+		"Selected cluster": {
+			spec: fedv1b1.FaultInjectionSpec{
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "europe"}},
+			},
+			expected: true,
+		},
+		"Unselected cluster": {
+			spec: fedv1b1.FaultInjectionSpec{
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "america"}},
+			},
+			expected: false,
+		},
+		"Matching operation": {
+			spec:     fedv1b1.FaultInjectionSpec{Operations: []fedv1b1.FaultOperation{fedv1b1.FaultOperationGet, fedv1b1.FaultOperationUpdate}},
+			expected: true,
+		},
+		"Other operation": {
+			spec:     fedv1b1.FaultInjectionSpec{Operations: []fedv1b1.FaultOperation{fedv1b1.FaultOperationDelete}},
+			expected: false,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			result := faultApplies(&tc.spec, "cluster1", clusterLabels, fedv1b1.FaultOperationUpdate)
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFaultInjectorInject(t *testing.T) {
+	newInjector := func(specs ...fedv1b1.FaultInjectionSpec) *FaultInjector {
+		store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+		for i, spec := range specs {
+			faultInjection := &fedv1b1.FaultInjection{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-federation-system", Name: string(rune('a' + i))},
+				Spec:       spec,
+			}
+			if err := store.Add(faultInjection); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		return &FaultInjector{store: store}
+	}
+
+	t.Run("No faults", func(t *testing.T) {
+		injector := newInjector(fedv1b1.FaultInjectionSpec{Clusters: []string{"cluster2"}, ErrorPercentage: 100})
+		if err := injector.Inject(context.Background(), "cluster1", nil, fedv1b1.FaultOperationCreate); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		injector := newInjector(fedv1b1.FaultInjectionSpec{ErrorPercentage: 100, ErrorCode: http.StatusTooManyRequests})
+		err := injector.Inject(context.Background(), "cluster1", nil, fedv1b1.FaultOperationCreate)
+		if !apierrors.IsTooManyRequests(err) {
+			t.Errorf("Expected a too many requests error, got %v", err)
+		}
+	})
+
+	t.Run("Default error code", func(t *testing.T) {
+		injector := newInjector(fedv1b1.FaultInjectionSpec{ErrorPercentage: 100})
+		err := injector.Inject(context.Background(), "cluster1", nil, fedv1b1.FaultOperationCreate)
+		if !apierrors.IsServiceUnavailable(err) {
+			t.Errorf("Expected a service unavailable error, got %v", err)
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		latency := 50 * time.Millisecond
+		injector := newInjector(
+			fedv1b1.FaultInjectionSpec{Latency: &metav1.Duration{Duration: latency}},
+			fedv1b1.FaultInjectionSpec{Latency: &metav1.Duration{Duration: latency}},
+		)
+		start := time.Now()
+		if err := injector.Inject(context.Background(), "cluster1", nil, fedv1b1.FaultOperationGet); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 2*latency {
+			t.Errorf("Expected a latency of at least %v, got %v", 2*latency, elapsed)
+		}
+	})
+
+	t.Run("Cancelled latency", func(t *testing.T) {
+		injector := newInjector(fedv1b1.FaultInjectionSpec{Latency: &metav1.Duration{Duration: time.Hour}})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := injector.Inject(ctx, "cluster1", nil, fedv1b1.FaultOperationGet); err != context.Canceled {
+			t.Errorf("Expected the context to be cancelled, got %v", err)
+		}
+	})
+}
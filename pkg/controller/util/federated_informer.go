@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
 	apiv1 "k8s.io/api/core/v1"
@@ -34,6 +35,7 @@ import (
 	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/logging"
 	"sigs.k8s.io/kubefed/pkg/metrics"
 )
 
@@ -80,6 +82,14 @@ type RegisteredClustersView interface {
 	// GetClientForCluster returns a client for the cluster, if present.
 	GetClientForCluster(clusterName string) (generic.Client, error)
 
+	// GetImpersonatingClientForCluster returns a client for the
+	// cluster, if present, that impersonates the given user.
+	GetImpersonatingClientForCluster(clusterName, userName string) (generic.Client, error)
+
+	// GetSchemaForCluster returns the OpenAPI schema of the cluster,
+	// or nil if the schemas of clusters are not used.
+	GetSchemaForCluster(clusterName string) (*ClusterSchema, error)
+
 	// GetUnreadyClusters returns a list of all clusters that are not ready yet.
 	GetUnreadyClusters() ([]*fedv1b1.KubeFedCluster, error)
 
@@ -111,6 +121,9 @@ type FederatedInformer interface {
 	// Returns a store created over all stores from target informers.
 	GetTargetStore() FederatedReadOnlyStore
 
+	// Returns whether the target informer of each ready cluster has synced.
+	SyncStatus() InformerSyncStatus
+
 	// Starts all the processes.
 	Start()
 
@@ -132,6 +145,15 @@ type ClusterLifecycleHandlerFuncs struct {
 	// Fired when the cluster becomes unavailable. The second arg contains data that was present
 	// in the cluster before deletion.
 	ClusterUnavailable func(*fedv1b1.KubeFedCluster, []interface{})
+	// Fired when the CustomResourceDefinitions or API versions
+	// recorded in the inventory of an available cluster change.
+	ClusterInventoryChanged func(*fedv1b1.KubeFedCluster)
+	// Fired when the backfill phase of an available cluster changes.
+	ClusterBackfillPhaseChanged func(*fedv1b1.KubeFedCluster)
+	// Fired when an available cluster is quarantined or released.
+	ClusterQuarantineChanged func(*fedv1b1.KubeFedCluster)
+	// Fired when an available cluster is cordoned or uncordoned.
+	ClusterCordonChanged func(*fedv1b1.KubeFedCluster)
 }
 
 // Builds a FederatedInformer for the given configuration.
@@ -141,6 +163,29 @@ func NewFederatedInformer(
 	apiResource *metav1.APIResource,
 	triggerFunc func(pkgruntime.Object),
 	clusterLifecycle *ClusterLifecycleHandlerFuncs) (FederatedInformer, error) {
+	return newFederatedInformer(config, client, apiResource, triggerFunc, clusterLifecycle, nil)
+}
+
+// NewResumableFederatedInformer builds a FederatedInformer whose
+// watch of a member cluster that becomes available again is resumed
+// from the last observed resource version instead of listing the
+// resources of the cluster again.
+func NewResumableFederatedInformer(
+	config *ControllerConfig,
+	client generic.Client,
+	apiResource *metav1.APIResource,
+	triggerFunc func(pkgruntime.Object),
+	clusterLifecycle *ClusterLifecycleHandlerFuncs) (FederatedInformer, error) {
+	return newFederatedInformer(config, client, apiResource, triggerFunc, clusterLifecycle, newWatchResumption(watchResumptionRetention))
+}
+
+func newFederatedInformer(
+	config *ControllerConfig,
+	client generic.Client,
+	apiResource *metav1.APIResource,
+	triggerFunc func(pkgruntime.Object),
+	clusterLifecycle *ClusterLifecycleHandlerFuncs,
+	resumption *watchResumption) (FederatedInformer, error) {
 
 	targetInformerFactory := func(cluster *fedv1b1.KubeFedCluster, clusterConfig *restclient.Config) (cache.Store, cache.Controller, error) {
 		resourceClient, err := NewResourceClient(clusterConfig, apiResource)
@@ -148,26 +193,51 @@ func NewFederatedInformer(
 			return nil, nil, err
 		}
 		targetNamespace := NamespaceForCluster(cluster.Name, config.TargetNamespace)
-		store, controller := NewManagedResourceInformer(resourceClient, targetNamespace, apiResource, triggerFunc)
+		if resumption == nil {
+			store, controller := NewManagedResourceInformer(resourceClient, targetNamespace, config.InstanceName, apiResource, triggerFunc)
+			return store, controller, nil
+		}
+		listWatch := newResourceListWatch(resourceClient, targetNamespace, ManagedLabelSelector(config.InstanceName).String())
+		store, controller := newListWatchInformer(resumption.listWatch(cluster, listWatch), apiResource, triggerFunc)
 		return store, controller, nil
 	}
 
+	name := apiResource.Name
+	if len(apiResource.Group) > 0 {
+		name = fmt.Sprintf("%s.%s", apiResource.Name, apiResource.Group)
+	}
+
 	federatedInformer := &federatedInformerImpl{
+		name:                  name,
+		logger:                logging.NewLogger("federated-informer").WithValues("resource", name),
 		targetInformerFactory: targetInformerFactory,
 		configFactory: func(cluster *fedv1b1.KubeFedCluster) (*restclient.Config, error) {
-			clusterConfig, err := BuildClusterConfig(cluster, client, config.KubeFedNamespace)
+			clusterConfig, err := BuildClusterConfig(cluster, client, config.KubeFedNamespace, config.KubeConfig)
 			if err != nil {
 				return nil, err
 			}
 			if clusterConfig == nil {
 				return nil, errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
 			}
+			if config.ClusterTransports != nil {
+				clusterConfig, err = config.ClusterTransports.Configure(cluster, clusterConfig)
+				if err != nil {
+					return nil, err
+				}
+			}
 			restclient.AddUserAgent(clusterConfig, userAgentName)
 			return clusterConfig, nil
 		},
-		targetInformers: make(map[string]informer),
-		fedNamespace:    config.KubeFedNamespace,
-		clusterClients:  make(map[string]generic.Client),
+		targetInformers:      make(map[string]informer),
+		fedNamespace:         config.KubeFedNamespace,
+		clusterClients:       make(map[string]generic.Client),
+		impersonatingClients: make(map[string]map[string]generic.Client),
+		operationTimeout:     config.ClusterOperationTimeout,
+		clusterLatency:       config.ClusterLatency,
+		applyLimiter:         config.ClusterApplyLimiter,
+		discoveryCache:       config.DiscoveryCache,
+		schemaCache:          config.SchemaCache,
+		resumption:           resumption,
 	}
 
 	getClusterData := func(name string) []interface{} {
@@ -194,6 +264,9 @@ func NewFederatedInformer(
 						data = getClusterData(oldCluster.Name)
 					}
 					federatedInformer.deleteCluster(oldCluster)
+					if resumption != nil {
+						resumption.forget(oldCluster.Name)
+					}
 					if clusterLifecycle.ClusterUnavailable != nil {
 						clusterLifecycle.ClusterUnavailable(oldCluster, data)
 					}
@@ -205,12 +278,12 @@ func NewFederatedInformer(
 					klog.Errorf("Cluster %v/%v not added; incorrect type", curCluster.Namespace, curCluster.Name)
 				} else if IsClusterReady(&curCluster.Status) {
 					federatedInformer.addCluster(curCluster)
-					klog.Infof("Cluster %v/%v is ready", curCluster.Namespace, curCluster.Name)
+					federatedInformer.logger.Info("Cluster is ready", logging.ClusterKey, curCluster.Name)
 					if clusterLifecycle.ClusterAvailable != nil {
 						clusterLifecycle.ClusterAvailable(curCluster)
 					}
 				} else {
-					klog.Infof("Cluster %v/%v not added; it is not ready.", curCluster.Namespace, curCluster.Name)
+					federatedInformer.logger.Info("Cluster not added; it is not ready", logging.ClusterKey, curCluster.Name)
 				}
 			},
 			UpdateFunc: func(old, cur interface{}) {
@@ -224,7 +297,7 @@ func NewFederatedInformer(
 					klog.Errorf("Internal error: Cluster %v not updated. New cluster not of correct type.", cur)
 					return
 				}
-				if IsClusterReady(&oldCluster.Status) != IsClusterReady(&curCluster.Status) || !reflect.DeepEqual(oldCluster.Spec, curCluster.Spec) || !reflect.DeepEqual(oldCluster.ObjectMeta.Labels, curCluster.ObjectMeta.Labels) || !reflect.DeepEqual(oldCluster.ObjectMeta.Annotations, curCluster.ObjectMeta.Annotations) {
+				if IsClusterReady(&oldCluster.Status) != IsClusterReady(&curCluster.Status) || clusterSpecChanged(oldCluster, curCluster) || !reflect.DeepEqual(oldCluster.ObjectMeta.Labels, curCluster.ObjectMeta.Labels) || !reflect.DeepEqual(oldCluster.ObjectMeta.Annotations, curCluster.ObjectMeta.Annotations) {
 					var data []interface{}
 					if clusterLifecycle.ClusterUnavailable != nil {
 						data = getClusterData(oldCluster.Name)
@@ -240,6 +313,14 @@ func NewFederatedInformer(
 							clusterLifecycle.ClusterAvailable(curCluster)
 						}
 					}
+				} else if clusterLifecycle.ClusterInventoryChanged != nil && IsClusterReady(&curCluster.Status) && inventoryAPIsChanged(oldCluster, curCluster) {
+					clusterLifecycle.ClusterInventoryChanged(curCluster)
+				} else if clusterLifecycle.ClusterBackfillPhaseChanged != nil && IsClusterReady(&curCluster.Status) && backfillPhaseChanged(oldCluster, curCluster) {
+					clusterLifecycle.ClusterBackfillPhaseChanged(curCluster)
+				} else if clusterLifecycle.ClusterQuarantineChanged != nil && IsClusterReady(&curCluster.Status) && quarantineChanged(oldCluster, curCluster) {
+					clusterLifecycle.ClusterQuarantineChanged(curCluster)
+				} else if clusterLifecycle.ClusterCordonChanged != nil && IsClusterReady(&curCluster.Status) && oldCluster.Spec.Unschedulable != curCluster.Spec.Unschedulable {
+					clusterLifecycle.ClusterCordonChanged(curCluster)
 				} else {
 					klog.V(7).Infof("Cluster %v not updated to %v as ready status and specs are identical", oldCluster, curCluster)
 				}
@@ -249,6 +330,17 @@ func NewFederatedInformer(
 	return federatedInformer, err
 }
 
+// clusterSpecChanged returns whether the spec of the cluster has
+// changed in a way that requires the informers for the cluster to be
+// recreated. Cordoning only affects placement and does not.
+func clusterSpecChanged(oldCluster, curCluster *fedv1b1.KubeFedCluster) bool {
+	oldSpec := oldCluster.Spec.DeepCopy()
+	curSpec := curCluster.Spec.DeepCopy()
+	oldSpec.Unschedulable = false
+	curSpec.Unschedulable = false
+	return !reflect.DeepEqual(oldSpec, curSpec)
+}
+
 func IsClusterReady(clusterStatus *fedv1b1.KubeFedClusterStatus) bool {
 	for _, condition := range clusterStatus.Conditions {
 		if condition.Type == fedcommon.ClusterReady {
@@ -260,6 +352,12 @@ func IsClusterReady(clusterStatus *fedv1b1.KubeFedClusterStatus) bool {
 	return false
 }
 
+// IsEdgeCluster returns whether the cluster has the Edge connectivity
+// profile and is expected to be only intermittently reachable.
+func IsEdgeCluster(cluster *fedv1b1.KubeFedCluster) bool {
+	return cluster.Spec.ConnectivityProfile == fedv1b1.ConnectivityProfileEdge
+}
+
 type informer struct {
 	controller cache.Controller
 	store      cache.Store
@@ -269,6 +367,12 @@ type informer struct {
 type federatedInformerImpl struct {
 	sync.Mutex
 
+	// Name of the watched resource, used to identify the informer in
+	// the debug endpoints.
+	name string
+
+	logger logr.Logger
+
 	// Informer on federated clusters.
 	clusterInformer informer
 
@@ -284,8 +388,36 @@ type federatedInformerImpl struct {
 	// Caches cluster clients (reduces client discovery and secret retrieval)
 	clusterClients map[string]generic.Client
 
+	// Caches the impersonating clients of each cluster, keyed by the
+	// name of the impersonated user.
+	impersonatingClients map[string]map[string]generic.Client
+
 	// Namespace from which to source KubeFedCluster resources
 	fedNamespace string
+
+	// The duration after which requests to member clusters are
+	// cancelled unless overridden by a cluster.
+	operationTimeout time.Duration
+
+	// Tracks the latency of requests to member clusters. Nil if slow
+	// clusters are not isolated.
+	clusterLatency *ClusterLatencyTracker
+
+	// Limits the rate of applies to member clusters. Nil if applies
+	// are not limited.
+	applyLimiter *ClusterApplyLimiter
+
+	// Caches the API discovery of member clusters. Nil if discovery
+	// is not cached.
+	discoveryCache *ClusterDiscoveryCache
+
+	// Caches the OpenAPI schemas of member clusters. Nil if resources
+	// are not compared with the schemas of clusters.
+	schemaCache *ClusterSchemaCache
+
+	// Resumes the watches of member clusters that become available
+	// again. Nil if watches are not resumed.
+	resumption *watchResumption
 }
 
 // *federatedInformerImpl implements FederatedInformer interface.
@@ -297,6 +429,8 @@ type federatedStoreImpl struct {
 
 func (f *federatedInformerImpl) Stop() {
 	klog.V(4).Infof("Stopping federated informer.")
+	registry.removeInformer(f)
+
 	f.Lock()
 	defer f.Unlock()
 
@@ -313,6 +447,8 @@ func (f *federatedInformerImpl) Stop() {
 }
 
 func (f *federatedInformerImpl) Start() {
+	registry.addInformer(f)
+
 	f.Lock()
 	defer f.Unlock()
 
@@ -320,6 +456,22 @@ func (f *federatedInformerImpl) Start() {
 	go f.clusterInformer.controller.Run(f.clusterInformer.stopChan)
 }
 
+// SyncStatus returns whether the target informer of each ready
+// cluster has synced.
+func (f *federatedInformerImpl) SyncStatus() InformerSyncStatus {
+	f.Lock()
+	defer f.Unlock()
+
+	status := InformerSyncStatus{
+		Name:     f.name,
+		Clusters: make(map[string]bool),
+	}
+	for clusterName, informer := range f.targetInformers {
+		status.Clusters[clusterName] = informer.controller.HasSynced()
+	}
+	return status
+}
+
 // GetClientForCluster returns a client for the cluster, if present.
 func (f *federatedInformerImpl) GetClientForCluster(clusterName string) (generic.Client, error) {
 	defer metrics.ClusterClientConnectionDurationFromStart(time.Now())
@@ -330,19 +482,97 @@ func (f *federatedInformerImpl) GetClientForCluster(clusterName string) (generic
 	if client, ok := f.clusterClients[clusterName]; ok {
 		return client, nil
 	}
+	client, err := f.buildClientUnlocked(clusterName, restclient.ImpersonationConfig{})
+	if err != nil {
+		return client, err
+	}
+	f.clusterClients[clusterName] = client
+
+	return client, nil
+}
+
+// GetImpersonatingClientForCluster returns a client for the cluster, if
+// present, that impersonates the given user.
+func (f *federatedInformerImpl) GetImpersonatingClientForCluster(clusterName, userName string) (generic.Client, error) {
+	defer metrics.ClusterClientConnectionDurationFromStart(time.Now())
+	f.Lock()
+	defer f.Unlock()
+
+	if client, ok := f.impersonatingClients[clusterName][userName]; ok {
+		return client, nil
+	}
+	client, err := f.buildClientUnlocked(clusterName, restclient.ImpersonationConfig{UserName: userName})
+	if err != nil {
+		return client, err
+	}
+	if _, ok := f.impersonatingClients[clusterName]; !ok {
+		f.impersonatingClients[clusterName] = make(map[string]generic.Client)
+	}
+	f.impersonatingClients[clusterName][userName] = client
+
+	return client, nil
+}
+
+// GetSchemaForCluster returns the OpenAPI schema of the cluster, or nil
+// if the schemas of clusters are not used.
+func (f *federatedInformerImpl) GetSchemaForCluster(clusterName string) (*ClusterSchema, error) {
+	if f.schemaCache == nil {
+		return nil, nil
+	}
+	f.Lock()
+	cluster, found, err := f.getReadyClusterUnlocked(clusterName)
+	if err != nil || !found {
+		f.Unlock()
+		return nil, err
+	}
+	config, err := f.configFactory(cluster)
+	f.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return f.schemaCache.Schema(cluster, config)
+}
+
+// buildClientUnlocked returns a new client for the named cluster that
+// impersonates as configured.
+func (f *federatedInformerImpl) buildClientUnlocked(clusterName string, impersonate restclient.ImpersonationConfig) (generic.Client, error) {
 	config, err := f.getConfigForClusterUnlocked(clusterName)
 	if err != nil {
 		return nil, errors.Wrap(err, "Client creation failed")
 	}
-	client, err := generic.New(config)
+	config.Impersonate = impersonate
+	client, err := f.newClientUnlocked(clusterName, config)
 	if err != nil {
 		return client, err
 	}
-	f.clusterClients[clusterName] = client
-
+	client = wrapClientForCluster(client, clusterName, f.GetReadyCluster)
+	client = wrapClientWithTimeout(client, clusterName, f.operationTimeout, f.clusterLatency, f.GetReadyCluster)
+	if f.applyLimiter != nil {
+		// Time spent waiting for the apply rate limit counts neither
+		// towards the operation timeout nor the latency of a cluster.
+		client = wrapClientWithApplyLimit(client, clusterName, f.applyLimiter, f.GetReadyCluster)
+	}
 	return client, nil
 }
 
+// newClientUnlocked returns a client for the named cluster that maps
+// resources with the cached discovery of the cluster if discovery is
+// cached.
+func (f *federatedInformerImpl) newClientUnlocked(clusterName string, config *restclient.Config) (generic.Client, error) {
+	if f.discoveryCache == nil {
+		return generic.New(config)
+	}
+	cluster, found, err := f.getReadyClusterUnlocked(clusterName)
+	if err != nil || !found {
+		return generic.New(config)
+	}
+	mapper, err := f.discoveryCache.RESTMapper(cluster, config)
+	if err != nil {
+		return nil, err
+	}
+	return generic.NewWithMapper(config, mapper)
+}
+
 func (f *federatedInformerImpl) getConfigForClusterUnlocked(clusterName string) (*restclient.Config, error) {
 	// No locking needed. Will happen in f.GetCluster.
 	klog.V(4).Infof("Getting config for cluster %q", clusterName)
@@ -441,7 +671,7 @@ func (f *federatedInformerImpl) addCluster(cluster *fedv1b1.KubeFedCluster) {
 		store, controller, err := f.targetInformerFactory(cluster, config)
 		if err != nil {
 			// TODO: create also an event for cluster.
-			klog.Errorf("Failed to create an informer for cluster %q: %v", cluster.Name, err)
+			f.logger.Error(err, "Failed to create an informer", logging.ClusterKey, cluster.Name)
 			return
 		}
 		targetInformer := informer{
@@ -453,7 +683,7 @@ func (f *federatedInformerImpl) addCluster(cluster *fedv1b1.KubeFedCluster) {
 		go targetInformer.controller.Run(targetInformer.stopChan)
 	} else {
 		// TODO: create also an event for cluster.
-		klog.Errorf("Failed to create a client for cluster: %v", err)
+		f.logger.Error(err, "Failed to create a client", logging.ClusterKey, cluster.Name)
 	}
 }
 
@@ -464,9 +694,13 @@ func (f *federatedInformerImpl) deleteCluster(cluster *fedv1b1.KubeFedCluster) {
 	name := cluster.Name
 	if targetInformer, found := f.targetInformers[name]; found {
 		close(targetInformer.stopChan)
+		if f.resumption != nil {
+			f.resumption.suspend(name)
+		}
 	}
 	delete(f.targetInformers, name)
 	delete(f.clusterClients, name)
+	delete(f.impersonatingClients, name)
 }
 
 // Returns a store created over all stores from target informers.
@@ -571,3 +805,14 @@ func (fs *federatedStoreImpl) ClustersSynced(clusters []*fedv1b1.KubeFedCluster)
 	}
 	return true
 }
+
+// inventoryAPIsChanged returns whether the CustomResourceDefinitions or
+// API versions recorded in the inventory of the cluster have changed.
+func inventoryAPIsChanged(oldCluster, curCluster *fedv1b1.KubeFedCluster) bool {
+	oldInventory := oldCluster.Status.Inventory
+	curInventory := curCluster.Status.Inventory
+	if oldInventory == nil || curInventory == nil {
+		return oldInventory != curInventory
+	}
+	return !reflect.DeepEqual(oldInventory.CRDs, curInventory.CRDs) || !reflect.DeepEqual(oldInventory.APIVersions, curInventory.APIVersions)
+}
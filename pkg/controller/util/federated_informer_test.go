@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestClusterSpecChanged(t *testing.T) {
+	newCluster := func(apiEndpoint string, unschedulable bool) *fedv1b1.KubeFedCluster {
+		return &fedv1b1.KubeFedCluster{
+			Spec: fedv1b1.KubeFedClusterSpec{
+				APIEndpoint:   apiEndpoint,
+				Unschedulable: unschedulable,
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		oldCluster *fedv1b1.KubeFedCluster
+		curCluster *fedv1b1.KubeFedCluster
+		expected   bool
+	}{
+		"Unchanged spec": {
+			oldCluster: newCluster("https://cluster1", false),
+			curCluster: newCluster("https://cluster1", false),
+		},
+		"Cordoned cluster": {
+			oldCluster: newCluster("https://cluster1", false),
+			curCluster: newCluster("https://cluster1", true),
+		},
+		"Uncordoned cluster": {
+			oldCluster: newCluster("https://cluster1", true),
+			curCluster: newCluster("https://cluster1", false),
+		},
+		"Changed API endpoint": {
+			oldCluster: newCluster("https://cluster1", false),
+			curCluster: newCluster("https://cluster1.example.com", false),
+			expected:   true,
+		},
+		"Changed API endpoint of cordoned cluster": {
+			oldCluster: newCluster("https://cluster1", false),
+			curCluster: newCluster("https://cluster1.example.com", true),
+			expected:   true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			if changed := clusterSpecChanged(tc.oldCluster, tc.curCluster); changed != tc.expected {
+				t.Errorf("Expected changed to be %v, got %v", tc.expected, changed)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package util
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
 )
 
 // FederatedResource is a generic representation of a federated type
@@ -32,4 +34,7 @@ type FederatedResource struct {
 type ResourceClusterStatus struct {
 	ClusterName string                 `json:"clusterName,omitempty"`
 	Status      map[string]interface{} `json:"status,omitempty"`
+	// Health is the health of the resource interpreted from its
+	// status, if the HealthInterpretation feature is enabled.
+	Health health.Health `json:"health,omitempty"`
 }
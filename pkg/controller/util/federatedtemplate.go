@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// GenericTemplateRef references the FederatedTemplate in the KubeFed
+// system namespace that provides the template of a federated resource.
+type GenericTemplateRef struct {
+	Name       string            `json:"name"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+type GenericTemplateRefSpec struct {
+	TemplateRef *GenericTemplateRef `json:"templateRef,omitempty"`
+}
+
+type GenericTemplateRefHolder struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GenericTemplateRefSpec `json:"spec,omitempty"`
+}
+
+// FederatedTemplateFunc returns the FederatedTemplate with the given
+// name, or nil if it does not exist.
+type FederatedTemplateFunc func(name string) (*fedv1b1.FederatedTemplate, error)
+
+// GetTemplateRef returns the FederatedTemplate reference of the given
+// federated resource, or nil if it does not reference one.
+func GetTemplateRef(obj *unstructured.Unstructured) (*GenericTemplateRef, error) {
+	holder := &GenericTemplateRefHolder{}
+	if err := UnstructuredToInterface(obj, holder); err != nil {
+		return nil, errors.Wrapf(err, "failed to read spec.%s", TemplateRefField)
+	}
+	return holder.Spec.TemplateRef, nil
+}
+
+// ResolveTemplate returns the template of the target resource of the
+// given federated resource and whether the resource has one. The
+// template is either embedded in the resource or rendered from the
+// FederatedTemplate the resource references, which is retrieved with
+// the given function. A nil function indicates that referencing a
+// FederatedTemplate is not supported.
+func ResolveTemplate(obj *unstructured.Unstructured, getTemplate FederatedTemplateFunc) (map[string]interface{}, bool, error) {
+	template, hasTemplate, err := unstructured.NestedMap(obj.Object, SpecField, TemplateField)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to read spec.%s", TemplateField)
+	}
+	ref, err := GetTemplateRef(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	if ref == nil {
+		return template, hasTemplate, nil
+	}
+	if hasTemplate {
+		return nil, false, errors.Errorf("only one of spec.%s or spec.%s may be specified", TemplateField, TemplateRefField)
+	}
+	if getTemplate == nil {
+		return nil, false, errors.Errorf("spec.%s requires the FederatedTemplates feature to be enabled", TemplateRefField)
+	}
+	federatedTemplate, err := getTemplate(ref.Name)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to retrieve FederatedTemplate %q", ref.Name)
+	}
+	if federatedTemplate == nil {
+		return nil, false, errors.Errorf("FederatedTemplate %q not found", ref.Name)
+	}
+	template, err = RenderFederatedTemplate(federatedTemplate, ref.Parameters)
+	if err != nil {
+		return nil, false, err
+	}
+	return template, true, nil
+}
+
+// RenderFederatedTemplate returns the template of the given
+// FederatedTemplate with references of the form $(name) to its
+// parameters replaced by the given values or the defaults of the
+// parameters. References to names that are not parameters of the
+// template, such as those to environment variables in the command of
+// a container, are left unchanged.
+func RenderFederatedTemplate(federatedTemplate *fedv1b1.FederatedTemplate, parameters map[string]string) (map[string]interface{}, error) {
+	values := make(map[string]string)
+	for _, parameter := range federatedTemplate.Spec.Parameters {
+		if value, ok := parameters[parameter.Name]; ok {
+			values[parameter.Name] = value
+		} else if parameter.Default != nil {
+			values[parameter.Name] = *parameter.Default
+		} else {
+			return nil, errors.Errorf("parameter %q of FederatedTemplate %q is required", parameter.Name, federatedTemplate.Name)
+		}
+	}
+	for name := range parameters {
+		if _, ok := values[name]; !ok {
+			return nil, errors.Errorf("parameter %q is not declared by FederatedTemplate %q", name, federatedTemplate.Name)
+		}
+	}
+
+	template := make(map[string]interface{})
+	if err := json.Unmarshal(federatedTemplate.Spec.Template.Raw, &template); err != nil {
+		return nil, errors.Wrapf(err, "failed to read the template of FederatedTemplate %q", federatedTemplate.Name)
+	}
+	oldnew := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		oldnew = append(oldnew, "$("+name+")", value)
+	}
+	return substituteParameters(template, strings.NewReplacer(oldnew...), nil).(map[string]interface{}), nil
+}
+
+// substituteParameters replaces parameter references in the string
+// values of the given JSON value. A string value that consists of a
+// single reference to a parameter with a typed value is replaced with
+// the typed value.
+func substituteParameters(value interface{}, replacer *strings.Replacer, typedValues map[string]interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case string:
+		if strings.HasPrefix(typedValue, "$(") && strings.HasSuffix(typedValue, ")") {
+			if parameterValue, ok := typedValues[typedValue[2:len(typedValue)-1]]; ok {
+				return parameterValue
+			}
+		}
+		return replacer.Replace(typedValue)
+	case map[string]interface{}:
+		for key, fieldValue := range typedValue {
+			typedValue[key] = substituteParameters(fieldValue, replacer, typedValues)
+		}
+	case []interface{}:
+		for i, item := range typedValue {
+			typedValue[i] = substituteParameters(item, replacer, typedValues)
+		}
+	}
+	return value
+}
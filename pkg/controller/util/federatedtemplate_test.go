@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestResolveTemplate(t *testing.T) {
+	level := "info"
+	federatedTemplate := &fedv1b1.FederatedTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-settings"},
+		Spec: fedv1b1.FederatedTemplateSpec{
+			Template: runtime.RawExtension{
+				Raw: []byte(`{"data":{"tenant":"$(tenant)","level":"$(level)","command":"echo $(HOME)","replicas":2},"items":["$(tenant)-a"]}`),
+			},
+			Parameters: []fedv1b1.FederatedTemplateParameter{
+				{Name: "tenant"},
+				{Name: "level", Default: &level},
+			},
+		},
+	}
+	getTemplate := func(name string) (*fedv1b1.FederatedTemplate, error) {
+		if name == federatedTemplate.Name {
+			return federatedTemplate, nil
+		}
+		return nil, nil
+	}
+	templateRef := func(name string, parameters map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"name":       name,
+			"parameters": parameters,
+		}
+	}
+
+	testCases := map[string]struct {
+		spec             map[string]interface{}
+		getTemplate      FederatedTemplateFunc
+		expectedTemplate map[string]interface{}
+		expectedOK       bool
+		expectedErr      bool
+	}{
+		"Embedded template": {
+			spec: map[string]interface{}{
+				"template": map[string]interface{}{"data": map[string]interface{}{"level": "$(level)"}},
+			},
+			getTemplate:      getTemplate,
+			expectedTemplate: map[string]interface{}{"data": map[string]interface{}{"level": "$(level)"}},
+			expectedOK:       true,
+		},
+		"No template": {
+			spec:        map[string]interface{}{},
+			getTemplate: getTemplate,
+		},
+		"Referenced template": {
+			spec: map[string]interface{}{
+				"templateRef": templateRef("tenant-settings", map[string]interface{}{"tenant": "team-a"}),
+			},
+			getTemplate: getTemplate,
+			expectedTemplate: map[string]interface{}{
+				"data": map[string]interface{}{
+					"tenant":   "team-a",
+					"level":    "info",
+					"command":  "echo $(HOME)",
+					"replicas": int64(2),
+				},
+				"items": []interface{}{"team-a-a"},
+			},
+			expectedOK: true,
+		},
+		"Missing required parameter": {
+			spec: map[string]interface{}{
+				"templateRef": templateRef("tenant-settings", nil),
+			},
+			getTemplate: getTemplate,
+			expectedErr: true,
+		},
+		"Undeclared parameter": {
+			spec: map[string]interface{}{
+				"templateRef": templateRef("tenant-settings", map[string]interface{}{"tenant": "team-a", "HOME": "/root"}),
+			},
+			getTemplate: getTemplate,
+			expectedErr: true,
+		},
+		"Missing template": {
+			spec: map[string]interface{}{
+				"templateRef": templateRef("other", nil),
+			},
+			getTemplate: getTemplate,
+			expectedErr: true,
+		},
+		"Both embedded and referenced template": {
+			spec: map[string]interface{}{
+				"template":    map[string]interface{}{},
+				"templateRef": templateRef("tenant-settings", map[string]interface{}{"tenant": "team-a"}),
+			},
+			getTemplate: getTemplate,
+			expectedErr: true,
+		},
+		"Referenced template not supported": {
+			spec: map[string]interface{}{
+				"templateRef": templateRef("tenant-settings", map[string]interface{}{"tenant": "team-a"}),
+			},
+			expectedErr: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": tc.spec}}
+			template, ok, err := ResolveTemplate(obj, tc.getTemplate)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ok != tc.expectedOK {
+				t.Errorf("Expected ok %v, got %v", tc.expectedOK, ok)
+			}
+			if !reflect.DeepEqual(template, tc.expectedTemplate) {
+				t.Errorf("Expected template %v, got %v", tc.expectedTemplate, template)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// deploymentHealth follows the rollout status reported by kubectl: a
+// deployment is progressing until all of its replicas have been
+// updated and are available, and degraded once its progress deadline
+// is exceeded.
+func deploymentHealth(obj *unstructured.Unstructured) (Health, error) {
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+		return "", err
+	}
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return Progressing, nil
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return Degraded, nil
+		}
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	if status.UpdatedReplicas < replicas || status.Replicas > status.UpdatedReplicas || status.AvailableReplicas < status.UpdatedReplicas {
+		return Progressing, nil
+	}
+	return Healthy, nil
+}
+
+// statefulSetHealth considers a statefulset healthy once the replicas
+// above its rolling update partition have been updated and all of its
+// replicas are ready.
+func statefulSetHealth(obj *unstructured.Unstructured) (Health, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, statefulSet); err != nil {
+		return "", err
+	}
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return Progressing, nil
+	}
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	status := statefulSet.Status
+	if status.ReadyReplicas < replicas {
+		return Progressing, nil
+	}
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return Healthy, nil
+	}
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0 {
+		if status.UpdatedReplicas < replicas-*rollingUpdate.Partition {
+			return Progressing, nil
+		}
+		return Healthy, nil
+	}
+	if status.UpdateRevision != status.CurrentRevision {
+		return Progressing, nil
+	}
+	return Healthy, nil
+}
+
+// daemonSetHealth considers a daemonset healthy once its pods have
+// been updated and are available on all of the nodes they are
+// scheduled to.
+func daemonSetHealth(obj *unstructured.Unstructured) (Health, error) {
+	daemonSet := &appsv1.DaemonSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonSet); err != nil {
+		return "", err
+	}
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return Progressing, nil
+	}
+	status := daemonSet.Status
+	if daemonSet.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType && status.UpdatedNumberScheduled < status.DesiredNumberScheduled {
+		return Progressing, nil
+	}
+	if status.NumberAvailable < status.DesiredNumberScheduled {
+		return Progressing, nil
+	}
+	return Healthy, nil
+}
+
+// jobHealth considers a job healthy once it has completed and degraded
+// once it has failed.
+func jobHealth(obj *unstructured.Unstructured) (Health, error) {
+	job := &batchv1.Job{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, job); err != nil {
+		return "", err
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobFailed:
+			return Degraded, nil
+		case batchv1.JobComplete:
+			return Healthy, nil
+		}
+	}
+	return Progressing, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestBuiltinInterpreters(t *testing.T) {
+	testCases := map[string]struct {
+		kind     schema.GroupKind
+		spec     map[string]interface{}
+		status   map[string]interface{}
+		expected Health
+	}{
+		"Deployment rolled out": {
+			kind:     schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			spec:     map[string]interface{}{"replicas": int64(2)},
+			status:   map[string]interface{}{"observedGeneration": int64(1), "replicas": int64(2), "updatedReplicas": int64(2), "availableReplicas": int64(2)},
+			expected: Healthy,
+		},
+		"Deployment generation not observed": {
+			kind:     schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			spec:     map[string]interface{}{"replicas": int64(2)},
+			status:   map[string]interface{}{"replicas": int64(2), "updatedReplicas": int64(2), "availableReplicas": int64(2)},
+			expected: Progressing,
+		},
+		"Deployment with old replicas": {
+			kind:     schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			spec:     map[string]interface{}{"replicas": int64(2)},
+			status:   map[string]interface{}{"observedGeneration": int64(1), "replicas": int64(3), "updatedReplicas": int64(2), "availableReplicas": int64(2)},
+			expected: Progressing,
+		},
+		"Deployment past its progress deadline": {
+			kind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			spec: map[string]interface{}{"replicas": int64(2)},
+			status: map[string]interface{}{
+				"observedGeneration": int64(1),
+				"replicas":           int64(2),
+				"updatedReplicas":    int64(1),
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"},
+				},
+			},
+			expected: Degraded,
+		},
+		"StatefulSet updated": {
+			kind:     schema.GroupKind{Group: "apps", Kind: "StatefulSet"},
+			spec:     map[string]interface{}{"replicas": int64(3)},
+			status:   map[string]interface{}{"observedGeneration": int64(1), "readyReplicas": int64(3), "currentRevision": "web-2", "updateRevision": "web-2"},
+			expected: Healthy,
+		},
+		"StatefulSet updating": {
+			kind:     schema.GroupKind{Group: "apps", Kind: "StatefulSet"},
+			spec:     map[string]interface{}{"replicas": int64(3)},
+			status:   map[string]interface{}{"observedGeneration": int64(1), "readyReplicas": int64(3), "currentRevision": "web-1", "updateRevision": "web-2"},
+			expected: Progressing,
+		},
+		"StatefulSet updated above partition": {
+			kind: schema.GroupKind{Group: "apps", Kind: "StatefulSet"},
+			spec: map[string]interface{}{
+				"replicas": int64(3),
+				"updateStrategy": map[string]interface{}{
+					"type":          "RollingUpdate",
+					"rollingUpdate": map[string]interface{}{"partition": int64(2)},
+				},
+			},
+			status:   map[string]interface{}{"observedGeneration": int64(1), "readyReplicas": int64(3), "updatedReplicas": int64(1), "currentRevision": "web-1", "updateRevision": "web-2"},
+			expected: Healthy,
+		},
+		"DaemonSet available": {
+			kind:     schema.GroupKind{Group: "apps", Kind: "DaemonSet"},
+			status:   map[string]interface{}{"observedGeneration": int64(1), "desiredNumberScheduled": int64(4), "updatedNumberScheduled": int64(4), "numberAvailable": int64(4)},
+			expected: Healthy,
+		},
+		"DaemonSet unavailable": {
+			kind:     schema.GroupKind{Group: "apps", Kind: "DaemonSet"},
+			status:   map[string]interface{}{"observedGeneration": int64(1), "desiredNumberScheduled": int64(4), "updatedNumberScheduled": int64(4), "numberAvailable": int64(3)},
+			expected: Progressing,
+		},
+		"Job complete": {
+			kind: schema.GroupKind{Group: "batch", Kind: "Job"},
+			status: map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Complete", "status": "True"}},
+			},
+			expected: Healthy,
+		},
+		"Job failed": {
+			kind: schema.GroupKind{Group: "batch", Kind: "Job"},
+			status: map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Failed", "status": "True"}},
+			},
+			expected: Degraded,
+		},
+		"Job running": {
+			kind:     schema.GroupKind{Group: "batch", Kind: "Job"},
+			status:   map[string]interface{}{"active": int64(1)},
+			expected: Progressing,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "test", "generation": int64(1)},
+			}}
+			if tc.spec != nil {
+				obj.Object["spec"] = tc.spec
+			}
+			obj.Object["status"] = tc.status
+			interpreter := RegisteredInterpreter(tc.kind)
+			if interpreter == nil {
+				t.Fatalf("No interpreter registered for %v", tc.kind)
+			}
+			health, err := interpreter.Interpret(obj)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if health != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, health)
+			}
+		})
+	}
+}
+
+func TestRollup(t *testing.T) {
+	testCases := map[string]struct {
+		clusterHealth []Health
+		expected      Health
+	}{
+		"All healthy": {
+			clusterHealth: []Health{Healthy, Healthy},
+			expected:      Healthy,
+		},
+		"Progressing in one cluster": {
+			clusterHealth: []Health{Healthy, Progressing},
+			expected:      Progressing,
+		},
+		"Degraded in one cluster": {
+			clusterHealth: []Health{Progressing, Degraded, Healthy},
+			expected:      Degraded,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if health := Rollup(tc.clusterHealth); health != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, health)
+			}
+		})
+	}
+}
@@ -0,0 +1,741 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// selfVariable is the variable the interpreted resource is bound to.
+const selfVariable = "self"
+
+// expressionInterpreter interprets the health of a resource with
+// expressions in a subset of the Common Expression Language.
+type expressionInterpreter struct {
+	healthy  expression
+	degraded expression
+}
+
+// NewExpressionInterpreter returns an interpreter evaluating the given
+// expressions over the resource bound to `self`. The resource is
+// degraded if the degraded expression is provided and true, healthy if
+// the healthy expression is true and progressing otherwise.
+func NewExpressionInterpreter(healthy, degraded string) (Interpreter, error) {
+	interpreter := &expressionInterpreter{}
+	var err error
+	interpreter.healthy, err = parseExpression(healthy)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid healthy expression")
+	}
+	if len(degraded) > 0 {
+		interpreter.degraded, err = parseExpression(degraded)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid degraded expression")
+		}
+	}
+	return interpreter, nil
+}
+
+func (i *expressionInterpreter) Interpret(obj *unstructured.Unstructured) (Health, error) {
+	if i.degraded != nil {
+		degraded, err := i.degraded.Evaluate(obj)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to evaluate the degraded expression")
+		}
+		if degraded {
+			return Degraded, nil
+		}
+	}
+	healthy, err := i.healthy.Evaluate(obj)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to evaluate the healthy expression")
+	}
+	if healthy {
+		return Healthy, nil
+	}
+	return Progressing, nil
+}
+
+// ValidateExpression returns an error if the given health expression
+// cannot be parsed.
+func ValidateExpression(source string) error {
+	_, err := parseExpression(source)
+	return err
+}
+
+// expression is a parsed boolean expression over a resource.
+type expression interface {
+	// Evaluate returns the value of the expression for the given
+	// resource.
+	Evaluate(obj *unstructured.Unstructured) (bool, error)
+}
+
+// parseExpression parses a boolean expression over a resource bound to
+// `self`. The supported syntax is a subset of the Common Expression
+// Language:
+//
+//   - literals: integers, floats, 'single' or "double" quoted strings,
+//     true, false and null
+//   - field selection (`self.status.phase`) and indexing of lists and
+//     maps (`self.spec.containers[0]`, `self.metadata.labels['app']`)
+//   - `has(self.status.phase)` testing the presence of a field
+//   - `size(x)` returning the length of a list, map or string
+//   - the `x.exists(v, predicate)` and `x.all(v, predicate)` macros
+//     over the elements of a list
+//   - comparisons (==, !=, <, <=, >, >=), !, && and ||
+//
+// Unlike CEL, selecting a field that is not present yields null rather
+// than an error, and null is false where a boolean is expected, so
+// that expressions over status fields that have yet to be reported
+// evaluate to false.
+func parseExpression(source string) (expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, scope: []string{selfVariable}}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, errors.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return &rootExpression{node: n}, nil
+}
+
+type rootExpression struct {
+	node node
+}
+
+func (e *rootExpression) Evaluate(obj *unstructured.Unstructured) (bool, error) {
+	value, err := e.node.eval(map[string]interface{}{selfVariable: obj.Object})
+	if err != nil {
+		return false, err
+	}
+	return asBool(value)
+}
+
+type tokenKind int
+
+const (
+	identToken tokenKind = iota
+	numberToken
+	stringToken
+	operatorToken
+	endToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// value is the parsed value of number and string tokens.
+	value interface{}
+	pos   int
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ".", ","}
+
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+	for pos := 0; pos < len(source); {
+		c := rune(source[pos])
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case c == '_' || unicode.IsLetter(c):
+			start := pos
+			for pos < len(source) && (source[pos] == '_' || unicode.IsLetter(rune(source[pos])) || unicode.IsDigit(rune(source[pos]))) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: identToken, text: source[start:pos], pos: start})
+		case unicode.IsDigit(c):
+			start := pos
+			for pos < len(source) && (unicode.IsDigit(rune(source[pos])) || source[pos] == '.') {
+				pos++
+			}
+			text := source[start:pos]
+			var value interface{}
+			var err error
+			if strings.Contains(text, ".") {
+				value, err = strconv.ParseFloat(text, 64)
+			} else {
+				value, err = strconv.ParseInt(text, 10, 64)
+			}
+			if err != nil {
+				return nil, errors.Errorf("invalid number %q at position %d", text, start)
+			}
+			tokens = append(tokens, token{kind: numberToken, text: text, value: value, pos: start})
+		case c == '\'' || c == '"':
+			start := pos
+			pos++
+			var value strings.Builder
+			for ; pos < len(source) && rune(source[pos]) != c; pos++ {
+				if source[pos] == '\\' && pos+1 < len(source) {
+					pos++
+				}
+				value.WriteByte(source[pos])
+			}
+			if pos >= len(source) {
+				return nil, errors.Errorf("unterminated string at position %d", start)
+			}
+			pos++
+			tokens = append(tokens, token{kind: stringToken, text: source[start:pos], value: value.String(), pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(source[pos:], op) {
+					tokens = append(tokens, token{kind: operatorToken, text: op, pos: pos})
+					pos += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errors.Errorf("unexpected character %q at position %d", c, pos)
+			}
+		}
+	}
+	return append(tokens, token{kind: endToken, text: "end of expression", pos: len(source)}), nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+	// scope holds the variables bound by the enclosing macros.
+	scope []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) done() bool {
+	return p.peek().kind == endToken
+}
+
+// accept consumes the next token if it is the given operator.
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == operatorToken && t.text == op {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		return errors.Errorf("expected %q at position %d, found %q", op, p.peek().pos, p.peek().text)
+	}
+	return nil
+}
+
+func (p *parser) expectIdent() (string, error) {
+	t := p.peek()
+	if t.kind != identToken {
+		return "", errors.Errorf("expected an identifier at position %d, found %q", t.pos, t.text)
+	}
+	p.next++
+	return t.text, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &comparisonNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			name, err := p.expectIdent()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept("(") {
+				n = &selectNode{operand: n, field: name}
+				continue
+			}
+			if name != "exists" && name != "all" {
+				return nil, errors.Errorf("unsupported function %q", name)
+			}
+			n, err = p.parseMacro(n, name == "all")
+			if err != nil {
+				return nil, err
+			}
+		case p.accept("["):
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = &indexNode{operand: n, index: index}
+		default:
+			return n, nil
+		}
+	}
+}
+
+// parseMacro parses the arguments of the exists or all macro following
+// its opening parenthesis.
+func (p *parser) parseMacro(operand node, all bool) (node, error) {
+	variable, err := p.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	p.scope = append(p.scope, variable)
+	predicate, err := p.parseOr()
+	p.scope = p.scope[:len(p.scope)-1]
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &macroNode{operand: operand, variable: variable, predicate: predicate, all: all}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case numberToken, stringToken:
+		p.next++
+		return &literalNode{value: t.value}, nil
+	case identToken:
+		p.next++
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		case "has", "size":
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			argument, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if t.text == "size" {
+				return &sizeNode{operand: argument}, nil
+			}
+			selection, ok := argument.(*selectNode)
+			if !ok {
+				return nil, errors.Errorf("the argument of has() at position %d must be a field selection", t.pos)
+			}
+			return &hasNode{selection: selection}, nil
+		}
+		for _, variable := range p.scope {
+			if variable == t.text {
+				return &variableNode{name: t.text}, nil
+			}
+		}
+		return nil, errors.Errorf("undeclared reference to %q at position %d", t.text, t.pos)
+	}
+	if p.accept("(") {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, errors.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+// node is a node of a parsed expression evaluated with the given
+// variable bindings.
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) eval(vars map[string]interface{}) (interface{}, error) {
+	return vars[n.name], nil
+}
+
+type selectNode struct {
+	operand node
+	field   string
+}
+
+func (n *selectNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return value[n.field], nil
+	}
+	return nil, errors.Errorf("cannot select field %q of %s", n.field, typeName(value))
+}
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+func (n *indexNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		key, ok := index.(string)
+		if !ok {
+			return nil, errors.Errorf("cannot index a map with %s", typeName(index))
+		}
+		return value[key], nil
+	case []interface{}:
+		i, ok := index.(int64)
+		if !ok {
+			return nil, errors.Errorf("cannot index a list with %s", typeName(index))
+		}
+		if i < 0 || i >= int64(len(value)) {
+			return nil, nil
+		}
+		return value[i], nil
+	}
+	return nil, errors.Errorf("cannot index %s", typeName(value))
+}
+
+type hasNode struct {
+	selection *selectNode
+}
+
+func (n *hasNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.selection.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	_, found := fields[n.selection.field]
+	return found, nil
+}
+
+type sizeNode struct {
+	operand node
+}
+
+func (n *sizeNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case nil:
+		return int64(0), nil
+	case string:
+		return int64(len(value)), nil
+	case []interface{}:
+		return int64(len(value)), nil
+	case map[string]interface{}:
+		return int64(len(value)), nil
+	}
+	return nil, errors.Errorf("cannot determine the size of %s", typeName(value))
+}
+
+type macroNode struct {
+	operand   node
+	variable  string
+	predicate node
+	// all indicates whether the predicate must hold for all elements
+	// rather than for any element.
+	all bool
+}
+
+func (n *macroNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	var elements []interface{}
+	switch value := value.(type) {
+	case nil:
+	case []interface{}:
+		elements = value
+	default:
+		return nil, errors.Errorf("cannot iterate over %s", typeName(value))
+	}
+	scoped := make(map[string]interface{}, len(vars)+1)
+	for name, value := range vars {
+		scoped[name] = value
+	}
+	for _, element := range elements {
+		scoped[n.variable] = element
+		value, err := n.predicate.eval(scoped)
+		if err != nil {
+			return nil, err
+		}
+		result, err := asBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if result != n.all {
+			return !n.all, nil
+		}
+	}
+	return n.all, nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	result, err := asBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return !result, nil
+}
+
+type logicalNode struct {
+	// or indicates a disjunction rather than a conjunction.
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	for _, operand := range []node{n.left, n.right} {
+		value, err := operand.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		result, err := asBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if result == n.or {
+			return n.or, nil
+		}
+	}
+	return !n.or, nil
+}
+
+type comparisonNode struct {
+	op          string
+	left, right node
+}
+
+func (n *comparisonNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+	if left == nil || right == nil {
+		return false, nil
+	}
+	var order int
+	leftNumber, leftIsNumber := asNumber(left)
+	rightNumber, rightIsNumber := asNumber(right)
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+	switch {
+	case leftIsNumber && rightIsNumber:
+		order = compareNumbers(leftNumber, rightNumber)
+	case leftIsString && rightIsString:
+		order = strings.Compare(leftString, rightString)
+	default:
+		return nil, errors.Errorf("cannot compare %s with %s", typeName(left), typeName(right))
+	}
+	switch n.op {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	}
+	return order >= 0, nil
+}
+
+func equal(left, right interface{}) bool {
+	leftNumber, leftIsNumber := asNumber(left)
+	rightNumber, rightIsNumber := asNumber(right)
+	if leftIsNumber && rightIsNumber {
+		return compareNumbers(leftNumber, rightNumber) == 0
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func compareNumbers(left, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	}
+	return 0
+}
+
+func asNumber(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+// asBool returns the given value where a boolean is expected. Null is
+// false so that fields that are not present do not fail evaluation.
+func asBool(value interface{}) (bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return value, nil
+	}
+	return false, errors.Errorf("expected a bool, found %s", typeName(value))
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestExpressionEvaluate(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"generation": int64(2),
+			"labels":     map[string]interface{}{"app": "db"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+		},
+		"status": map[string]interface{}{
+			"observedGeneration": int64(2),
+			"readyReplicas":      int64(3),
+			"phase":              "Running",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "Synced", "status": "False"},
+			},
+		},
+	}}
+
+	testCases := map[string]struct {
+		expression  string
+		expected    bool
+		expectedErr bool
+	}{
+		"Field comparison": {
+			expression: "self.status.readyReplicas >= self.spec.replicas && self.status.observedGeneration == self.metadata.generation",
+			expected:   true,
+		},
+		"String comparison": {
+			expression: `self.status.phase == "Running" || self.status.phase == 'Succeeded'`,
+			expected:   true,
+		},
+		"Exists macro": {
+			expression: "self.status.conditions.exists(c, c.type == 'Ready' && c.status == 'True')",
+			expected:   true,
+		},
+		"All macro": {
+			expression: "self.status.conditions.all(c, c.status == 'True')",
+			expected:   false,
+		},
+		"Presence of fields": {
+			expression: "has(self.status.phase) && !has(self.status.reason)",
+			expected:   true,
+		},
+		"Missing field compared as null": {
+			expression: "self.status.reason == null",
+			expected:   true,
+		},
+		"Ordering of missing field is false": {
+			expression: "self.status.updatedReplicas >= 0",
+			expected:   false,
+		},
+		"Missing field is false": {
+			expression: "self.status.ready",
+			expected:   false,
+		},
+		"Size and indexing": {
+			expression: "size(self.status.conditions) == 2 && self.status.conditions[1].type == 'Synced' && self.metadata.labels['app'] == 'db'",
+			expected:   true,
+		},
+		"Integer equals double": {
+			expression: "self.spec.replicas == 3.0",
+			expected:   true,
+		},
+		"Comparison of different types": {
+			expression:  "self.status.phase > 1",
+			expectedErr: true,
+		},
+		"Non-boolean result": {
+			expression:  "self.status.phase",
+			expectedErr: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			expr, err := parseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing %q: %v", tc.expression, err)
+			}
+			result, err := expr.Evaluate(obj)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error evaluating %q", tc.expression)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error evaluating %q: %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expected %q to be %v, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"status.phase == 'Running'",
+		"self.status.",
+		"has(self)",
+		"self.status.conditions.map(c, c.type)",
+		"self.status.phase == 'Running",
+		"(self.status.ready",
+		"self.status.ready # true",
+	} {
+		if _, err := parseExpression(expression); err == nil {
+			t.Errorf("Expected an error parsing %q", expression)
+		}
+	}
+}
+
+func TestExpressionInterpreter(t *testing.T) {
+	interpreter, err := NewExpressionInterpreter("self.status.phase == 'Succeeded'", "self.status.phase == 'Failed'")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for phase, expected := range map[string]Health{
+		"Succeeded": Healthy,
+		"Failed":    Degraded,
+		"Running":   Progressing,
+	} {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"phase": phase},
+		}}
+		health, err := interpreter.Interpret(obj)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if health != expected {
+			t.Errorf("Expected phase %q to be %q, got %q", phase, expected, health)
+		}
+	}
+}
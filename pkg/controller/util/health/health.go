@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+)
+
+// Health is the interpreted health of a resource in a member cluster.
+type Health string
+
+const (
+	// The resource has reached its desired state.
+	Healthy Health = "Healthy"
+	// The resource is working towards its desired state.
+	Progressing Health = "Progressing"
+	// The resource has failed to reach its desired state and is not
+	// expected to without intervention.
+	Degraded Health = "Degraded"
+)
+
+// Interpreter interprets the health of a resource in a member cluster
+// from its spec and status.
+type Interpreter interface {
+	Interpret(obj *unstructured.Unstructured) (Health, error)
+}
+
+// InterpreterFunc adapts a function to an Interpreter.
+type InterpreterFunc func(obj *unstructured.Unstructured) (Health, error)
+
+func (f InterpreterFunc) Interpret(obj *unstructured.Unstructured) (Health, error) {
+	return f(obj)
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[schema.GroupKind]Interpreter{
+		{Group: "apps", Kind: "DaemonSet"}:   InterpreterFunc(daemonSetHealth),
+		{Group: "apps", Kind: "Deployment"}:  InterpreterFunc(deploymentHealth),
+		{Group: "apps", Kind: "StatefulSet"}: InterpreterFunc(statefulSetHealth),
+		{Group: "batch", Kind: "Job"}:        InterpreterFunc(jobHealth),
+	}
+)
+
+// Register registers the interpreter of the given kind, replacing any
+// interpreter registered for the kind before.
+func Register(groupKind schema.GroupKind, interpreter Interpreter) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[groupKind] = interpreter
+}
+
+// RegisteredInterpreter returns the interpreter registered for the
+// given kind, or nil if none is registered.
+func RegisteredInterpreter(groupKind schema.GroupKind) Interpreter {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return registry[groupKind]
+}
+
+// ForTypeConfig returns the interpreter of the target type of the
+// given type config: the expressions configured by the type config if
+// provided, and the interpreter registered for the target kind
+// otherwise. Nil is returned if the health of the target type cannot
+// be interpreted.
+func ForTypeConfig(typeConfig typeconfig.Interface) (Interpreter, error) {
+	if config := typeConfig.GetHealthInterpreter(); config != nil {
+		interpreter, err := NewExpressionInterpreter(config.Healthy, config.Degraded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid health interpreter of %q", typeConfig.GetObjectMeta().Name)
+		}
+		return interpreter, nil
+	}
+	targetType := typeConfig.GetTargetType()
+	return RegisteredInterpreter(schema.GroupKind{Group: targetType.Group, Kind: targetType.Kind}), nil
+}
+
+// Rollup returns the health of a resource across clusters given its
+// health in each of them: degraded if degraded in any cluster, healthy
+// if healthy in all of them and progressing otherwise.
+func Rollup(clusterHealth []Health) Health {
+	result := Healthy
+	for _, health := range clusterHealth {
+		switch health {
+		case Degraded:
+			return Degraded
+		case Healthy:
+		default:
+			result = Progressing
+		}
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+const (
+	// If this annotation is present on a resource in a member cluster,
+	// e.g. because a cluster administrator has taken over the resource
+	// during an incident, the sync controller neither updates nor
+	// removes the resource until the annotation is removed.
+	IgnoreAnnotation = "kubefed.io/ignore"
+	IgnoreValue      = "true"
+)
+
+// IsLocallyManaged checks whether a resource in a member cluster is
+// managed locally rather than by the sync controller.
+func IsLocallyManaged(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[IgnoreAnnotation] == IgnoreValue
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// If this annotation is present on a federated resource, updates of
+	// the resource in member clusters are not deferred until the
+	// maintenance windows of the clusters open.
+	CriticalAnnotation = "kubefed.io/critical"
+	CriticalValue      = "true"
+)
+
+// IsCritical checks whether updates of a federated resource bypass
+// maintenance windows.
+func IsCritical(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[CriticalAnnotation] == CriticalValue
+}
+
+// MaintenanceWindowState returns whether the given maintenance window
+// is open at the given time, and the time it closes if it is open or
+// the time it next opens otherwise. The zero time is returned if the
+// window never opens.
+func MaintenanceWindowState(window *fedv1b1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	location := time.UTC
+	if len(window.Spec.TimeZone) > 0 {
+		var err error
+		location, err = time.LoadLocation(window.Spec.TimeZone)
+		if err != nil {
+			return false, time.Time{}, errors.Wrapf(err, "invalid time zone %q", window.Spec.TimeZone)
+		}
+	}
+	schedule, err := ParseCronSchedule(window.Spec.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	duration := window.Spec.Duration.Duration
+	if duration <= 0 {
+		return false, time.Time{}, errors.Errorf("invalid duration %v", duration)
+	}
+
+	// The window is open if it opened within the duration before now.
+	// Backing off a minute accounts for a window opening exactly
+	// duration ago, which Next would otherwise skip.
+	start := schedule.Next(now.Add(-duration).Add(-time.Minute).In(location))
+	for !start.IsZero() && !start.Add(duration).After(now) {
+		start = schedule.Next(start)
+	}
+	if start.IsZero() {
+		return false, time.Time{}, nil
+	}
+	if !start.After(now) {
+		return true, start.Add(duration), nil
+	}
+	return false, start, nil
+}
+
+// DeferredClusters returns the names of the given clusters whose
+// resources may not be updated at the given time, because the clusters
+// have maintenance windows and none of them is open. The returned
+// delay is the time until the earliest window of those clusters opens,
+// or zero if no cluster is deferred. Invalid windows are ignored.
+func DeferredClusters(windows []*fedv1b1.MaintenanceWindow, clusterNames sets.String, now time.Time) (sets.String, time.Duration) {
+	hasWindow := sets.String{}
+	open := sets.String{}
+	nextOpening := make(map[string]time.Time)
+	for _, window := range windows {
+		clusterName := window.Spec.ClusterName
+		if !clusterNames.Has(clusterName) {
+			continue
+		}
+		isOpen, next, err := MaintenanceWindowState(window, now)
+		if err != nil {
+			klog.Errorf("Ignoring maintenance window %s/%s: %v", window.Namespace, window.Name, err)
+			continue
+		}
+		hasWindow.Insert(clusterName)
+		if isOpen {
+			open.Insert(clusterName)
+			continue
+		}
+		if next.IsZero() {
+			continue
+		}
+		if current, ok := nextOpening[clusterName]; !ok || next.Before(current) {
+			nextOpening[clusterName] = next
+		}
+	}
+
+	deferred := hasWindow.Difference(open)
+	var delay time.Duration
+	for clusterName := range deferred {
+		next, ok := nextOpening[clusterName]
+		if !ok {
+			continue
+		}
+		if clusterDelay := next.Sub(now); delay == 0 || clusterDelay < delay {
+			delay = clusterDelay
+		}
+	}
+	return deferred, delay
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func newMaintenanceWindow(clusterName, schedule string, duration time.Duration, timeZone string) *fedv1b1.MaintenanceWindow {
+	return &fedv1b1.MaintenanceWindow{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		Spec: fedv1b1.MaintenanceWindowSpec{
+			ClusterName: clusterName,
+			Schedule:    schedule,
+			Duration:    metav1.Duration{Duration: duration},
+			TimeZone:    timeZone,
+		},
+	}
+}
+
+func TestMaintenanceWindowState(t *testing.T) {
+	now := time.Date(2020, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		window       *fedv1b1.MaintenanceWindow
+		expectedOpen bool
+		expectedNext time.Time
+	}{
+		"Open": {
+			window:       newMaintenanceWindow("cluster1", "0 10 * * *", time.Hour, ""),
+			expectedOpen: true,
+			expectedNext: time.Date(2020, time.January, 15, 11, 0, 0, 0, time.UTC),
+		},
+		"Opening now": {
+			window:       newMaintenanceWindow("cluster1", "30 10 * * *", time.Hour, ""),
+			expectedOpen: true,
+			expectedNext: time.Date(2020, time.January, 15, 11, 30, 0, 0, time.UTC),
+		},
+		"Closed": {
+			window:       newMaintenanceWindow("cluster1", "0 9 * * *", time.Hour, ""),
+			expectedOpen: false,
+			expectedNext: time.Date(2020, time.January, 16, 9, 0, 0, 0, time.UTC),
+		},
+		"Closing now": {
+			window:       newMaintenanceWindow("cluster1", "30 9 * * *", time.Hour, ""),
+			expectedOpen: false,
+			expectedNext: time.Date(2020, time.January, 16, 9, 30, 0, 0, time.UTC),
+		},
+		"Open since the previous day": {
+			window:       newMaintenanceWindow("cluster1", "0 22 * * *", 14*time.Hour, ""),
+			expectedOpen: true,
+			expectedNext: time.Date(2020, time.January, 15, 12, 0, 0, 0, time.UTC),
+		},
+		"Time zone": {
+			window:       newMaintenanceWindow("cluster1", "0 11 * * *", time.Hour, "Europe/Berlin"),
+			expectedOpen: true,
+			expectedNext: time.Date(2020, time.January, 15, 11, 0, 0, 0, time.UTC),
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			open, next, err := MaintenanceWindowState(tc.window, now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if open != tc.expectedOpen {
+				t.Errorf("Expected open %v, got %v", tc.expectedOpen, open)
+			}
+			if !next.Equal(tc.expectedNext) {
+				t.Errorf("Expected next %v, got %v", tc.expectedNext, next)
+			}
+		})
+	}
+}
+
+func TestDeferredClusters(t *testing.T) {
+	now := time.Date(2020, time.January, 15, 10, 30, 0, 0, time.UTC)
+	windows := []*fedv1b1.MaintenanceWindow{
+		newMaintenanceWindow("open", "0 10 * * *", time.Hour, ""),
+		newMaintenanceWindow("closed", "0 12 * * *", time.Hour, ""),
+		newMaintenanceWindow("closed", "0 14 * * *", time.Hour, ""),
+		newMaintenanceWindow("one-open", "0 8 * * *", time.Hour, ""),
+		newMaintenanceWindow("one-open", "0 10 * * *", time.Hour, ""),
+		newMaintenanceWindow("later", "0 20 * * *", time.Hour, ""),
+		newMaintenanceWindow("invalid", "0 10 * *", time.Hour, ""),
+		newMaintenanceWindow("unselected", "0 11 * * *", time.Hour, ""),
+	}
+	clusterNames := sets.NewString("open", "closed", "one-open", "later", "invalid", "without-window")
+
+	deferred, delay := DeferredClusters(windows, clusterNames, now)
+	expected := sets.NewString("closed", "later")
+	if !deferred.Equal(expected) {
+		t.Errorf("Expected deferred clusters %v, got %v", expected.List(), deferred.List())
+	}
+	if delay != 90*time.Minute {
+		t.Errorf("Expected delay %v, got %v", 90*time.Minute, delay)
+	}
+}
@@ -18,12 +18,18 @@ package util
 
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
 	ManagedByKubeFedLabelKey     = "kubefed.io/managed"
 	ManagedByKubeFedLabelValue   = "true"
 	UnmanagedByKubeFedLabelValue = "false"
+
+	// KubeFedInstanceLabelKey identifies the control plane managing a
+	// resource when several control planes propagate to the same
+	// member clusters.
+	KubeFedInstanceLabelKey = "kubefed.io/instance"
 )
 
 // HasManagedLabel indicates whether the given object has the managed
@@ -57,13 +63,40 @@ func AddManagedLabel(obj *unstructured.Unstructured) {
 	obj.SetLabels(labels)
 }
 
+// AddInstanceLabel ensures that the given object is labeled with the
+// name of the control plane instance managing it. Objects are not
+// labeled if the instance is unnamed.
+func AddInstanceLabel(obj *unstructured.Unstructured, instanceName string) {
+	if instanceName == "" {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[KubeFedInstanceLabelKey] = instanceName
+	obj.SetLabels(labels)
+}
+
+// ManagedLabelSelector returns the selector for resources managed by
+// the named control plane instance, or by KubeFed if the instance is
+// unnamed.
+func ManagedLabelSelector(instanceName string) labels.Selector {
+	set := labels.Set{ManagedByKubeFedLabelKey: ManagedByKubeFedLabelValue}
+	if instanceName != "" {
+		set[KubeFedInstanceLabelKey] = instanceName
+	}
+	return set.AsSelector()
+}
+
 // RemoveManagedLabel ensures that the given object does not have the
-// managed label.
+// managed label or the instance label.
 func RemoveManagedLabel(obj *unstructured.Unstructured) {
 	labels := obj.GetLabels()
 	if labels == nil || labels[ManagedByKubeFedLabelKey] != ManagedByKubeFedLabelValue {
 		return
 	}
 	delete(labels, ManagedByKubeFedLabelKey)
+	delete(labels, KubeFedInstanceLabelKey)
 	obj.SetLabels(labels)
 }
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	defaultNotificationTimeout = 10 * time.Second
+
+	// notificationQueueLength bounds the number of notifications
+	// waiting to be posted to a sink.
+	notificationQueueLength = 100
+
+	// maxNotificationResponseSize bounds the size of a response read
+	// from a sink.
+	maxNotificationResponseSize = 64 * 1024
+)
+
+// PropagationNotification describes a transition of the propagation
+// state of a federated resource. It is posted to notification sinks
+// as JSON, or is the data of their payload template.
+type PropagationNotification struct {
+	Event fedv1b1.NotificationEvent `json:"event"`
+	// The kind, namespace and name of the federated resource.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// The generation of the federated resource whose propagation
+	// transitioned.
+	Generation int64 `json:"generation"`
+	// The cluster and its propagation status, for the transitions of
+	// a single cluster.
+	ClusterName   string `json:"clusterName,omitempty"`
+	ClusterStatus string `json:"clusterStatus,omitempty"`
+	// The time of the transition in RFC 3339 format.
+	Time string `json:"time"`
+}
+
+// ParseNotificationTemplate parses the payload template of a
+// notification sink. The template may use the json function to render
+// a value as JSON, e.g. to quote a string.
+func ParseNotificationTemplate(text string) (*template.Template, error) {
+	return template.New("payload").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"json": marshalJSONString}).
+		Parse(text)
+}
+
+func marshalJSONString(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// PropagationNotifier posts notifications of transitions of the
+// propagation state of federated resources to the configured sinks.
+// Each sink is posted to in the background from a bounded queue so
+// that a slow or unavailable sink does not delay propagation, and
+// notifications are dropped while the queue of a sink is full.
+type PropagationNotifier struct {
+	sinks []*notificationSink
+}
+
+type notificationSink struct {
+	name string
+	url  string
+	// events the sink is notified of, or all events if empty
+	events   sets.String
+	template *template.Template
+	client   *http.Client
+	queue    chan *PropagationNotification
+}
+
+// NewPropagationNotifier returns the notifier for the given sinks, or
+// nil if no sinks are configured.
+func NewPropagationNotifier(configs []fedv1b1.NotificationSink) (*PropagationNotifier, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	n := &PropagationNotifier{}
+	for i := range configs {
+		config := &configs[i]
+		sink, err := newNotificationSink(config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid notification sink %q", config.Name)
+		}
+		n.sinks = append(n.sinks, sink)
+	}
+	for _, sink := range n.sinks {
+		go sink.run()
+	}
+	return n, nil
+}
+
+func newNotificationSink(config *fedv1b1.NotificationSink) (*notificationSink, error) {
+	events := sets.NewString()
+	for _, event := range config.Events {
+		events.Insert(string(event))
+	}
+
+	var tmpl *template.Template
+	if len(config.PayloadTemplate) > 0 {
+		var err error
+		tmpl, err = ParseNotificationTemplate(config.PayloadTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid payload template")
+		}
+	}
+
+	// Sinks are held to the TLS versions and cipher suites of the
+	// restricted compliance mode regardless of the mode.
+	tlsConfig := &tls.Config{}
+	RestrictTLSConfig(tlsConfig)
+	if len(config.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(config.CABundle) {
+			return nil, errors.New("no certificates found in caBundle")
+		}
+	}
+	timeout := defaultNotificationTimeout
+	if config.TimeoutSeconds != nil {
+		timeout = time.Duration(*config.TimeoutSeconds) * time.Second
+	}
+
+	return &notificationSink{
+		name:     config.Name,
+		url:      config.URL,
+		events:   events,
+		template: tmpl,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		queue: make(chan *PropagationNotification, notificationQueueLength),
+	}, nil
+}
+
+// Notify queues the given notification for the sinks that are
+// notified of its event.
+func (n *PropagationNotifier) Notify(notification *PropagationNotification) {
+	event := string(notification.Event)
+	for _, sink := range n.sinks {
+		if sink.events.Len() > 0 && !sink.events.Has(event) {
+			continue
+		}
+		select {
+		case sink.queue <- notification:
+		default:
+			klog.Warningf("Dropping %s notification for %s %q since the queue of notification sink %q is full",
+				event, notification.Kind, notificationResourceName(notification), sink.name)
+			metrics.RecordPropagationNotification(sink.name, event, metrics.NotificationResultDropped)
+		}
+	}
+}
+
+func (s *notificationSink) run() {
+	for notification := range s.queue {
+		event := string(notification.Event)
+		if err := s.post(notification); err != nil {
+			klog.Errorf("Failed to post %s notification for %s %q to notification sink %q: %v",
+				event, notification.Kind, notificationResourceName(notification), s.name, err)
+			metrics.RecordPropagationNotification(s.name, event, metrics.NotificationResultFailed)
+			continue
+		}
+		metrics.RecordPropagationNotification(s.name, event, metrics.NotificationResultSent)
+	}
+}
+
+// post posts the given notification to the sink, rendered by its
+// payload template if it has one.
+func (s *notificationSink) post(notification *PropagationNotification) error {
+	body, err := s.payload(notification)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	defer resp.Body.Close()
+	// Drain the response so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxNotificationResponseSize))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("sink responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *notificationSink) payload(notification *PropagationNotification) ([]byte, error) {
+	if s.template == nil {
+		return json.Marshal(notification)
+	}
+	buf := &bytes.Buffer{}
+	if err := s.template.Execute(buf, notification); err != nil {
+		return nil, errors.Wrap(err, "failed to render payload template")
+	}
+	return buf.Bytes(), nil
+}
+
+func notificationResourceName(notification *PropagationNotification) string {
+	return QualifiedName{Namespace: notification.Namespace, Name: notification.Name}.String()
+}
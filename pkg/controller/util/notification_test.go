@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestNotificationSinkPost(t *testing.T) {
+	var body string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		if r.URL.Path == "/error" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	notification := &PropagationNotification{
+		Event:         fedv1b1.NotificationClusterFailed,
+		Kind:          "FederatedDeployment",
+		Namespace:     "shop",
+		Name:          "frontend",
+		Generation:    3,
+		ClusterName:   "cluster2",
+		ClusterStatus: "UpdateFailed",
+		Time:          "2020-04-01T10:00:00Z",
+	}
+
+	testCases := map[string]struct {
+		path         string
+		template     string
+		expectedBody string
+		expectError  bool
+	}{
+		"Notification posted as JSON": {
+			expectedBody: `{"event":"ClusterFailed","kind":"FederatedDeployment","namespace":"shop","name":"frontend","generation":3,"clusterName":"cluster2","clusterStatus":"UpdateFailed","time":"2020-04-01T10:00:00Z"}`,
+		},
+		"Notification rendered by the payload template": {
+			template:     `{"text": {{ printf "%s/%s failed in %s: %s" .Namespace .Name .ClusterName .ClusterStatus | json }}}`,
+			expectedBody: `{"text": "shop/frontend failed in cluster2: UpdateFailed"}`,
+		},
+		"Error status": {
+			path:        "/error",
+			expectError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			body = ""
+			sink, err := newNotificationSink(&fedv1b1.NotificationSink{
+				Name:            "test",
+				URL:             server.URL + tc.path,
+				CABundle:        caBundle,
+				PayloadTemplate: tc.template,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			err = sink.post(notification)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if body != tc.expectedBody {
+				t.Errorf("Expected body %s, got %s", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestNotificationSinkRestrictedTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS11}
+	server.StartTLS()
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	sink, err := newNotificationSink(&fedv1b1.NotificationSink{
+		Name:     "test",
+		URL:      server.URL,
+		CABundle: caBundle,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := sink.post(&PropagationNotification{Event: fedv1b1.NotificationClusterFailed}); err == nil {
+		t.Fatalf("Expected posting to a sink limited to TLS 1.1 to fail")
+	}
+}
+
+func TestPropagationNotifierNotify(t *testing.T) {
+	all, err := newNotificationSink(&fedv1b1.NotificationSink{Name: "all", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	drift, err := newNotificationSink(&fedv1b1.NotificationSink{
+		Name:   "drift",
+		URL:    "https://example.com",
+		Events: []fedv1b1.NotificationEvent{fedv1b1.NotificationDriftDetected},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	notifier := &PropagationNotifier{sinks: []*notificationSink{all, drift}}
+
+	notifier.Notify(&PropagationNotification{Event: fedv1b1.NotificationAllClustersReady})
+	notifier.Notify(&PropagationNotification{Event: fedv1b1.NotificationDriftDetected})
+
+	if len(all.queue) != 2 {
+		t.Errorf("Expected 2 notifications to be queued for the sink of all events, got %d", len(all.queue))
+	}
+	if len(drift.queue) != 1 {
+		t.Errorf("Expected 1 notification to be queued for the sink of drift, got %d", len(drift.queue))
+	}
+
+	// Notifications are dropped rather than blocking once the queue
+	// is full.
+	for i := 0; i < notificationQueueLength; i++ {
+		notifier.Notify(&PropagationNotification{Event: fedv1b1.NotificationDriftDetected})
+	}
+	if len(drift.queue) != notificationQueueLength {
+		t.Errorf("Expected the queue to be full, got %d notifications", len(drift.queue))
+	}
+}
@@ -28,11 +28,31 @@ import (
 )
 
 type ClusterOverride struct {
-	Op    string      `json:"op,omitempty"`
-	Path  string      `json:"path"`
-	Value interface{} `json:"value,omitempty"`
+	Op        string               `json:"op,omitempty"`
+	Path      string               `json:"path"`
+	Value     interface{}          `json:"value,omitempty"`
+	ValueFrom *OverrideValueSource `json:"valueFrom,omitempty"`
 }
 
+// OverrideValueSource references a value that is resolved in the
+// member cluster an override applies to.
+type OverrideValueSource struct {
+	ConfigMapKeyRef *ConfigMapKeyReference `json:"configMapKeyRef,omitempty"`
+}
+
+// ConfigMapKeyReference selects a key of a ConfigMap in a member
+// cluster. The namespace defaults to the namespace of the resource in
+// the member cluster.
+type ConfigMapKeyReference struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+// OverrideValueFunc returns the value referenced by the given source
+// in a member cluster.
+type OverrideValueFunc func(source *OverrideValueSource) (interface{}, error)
+
 type GenericOverrideItem struct {
 	ClusterName      string            `json:"clusterName"`
 	ClusterOverrides []ClusterOverride `json:"clusterOverrides,omitempty"`
@@ -124,6 +144,9 @@ func GetOverrides(rawObj *unstructured.Unstructured) (OverridesMap, error) {
 			if paths.Has(path) {
 				return nil, errors.Errorf("path %q appears more than once for cluster %q", path, clusterName)
 			}
+			if err := validateValueFrom(clusterOverride); err != nil {
+				return nil, errors.Wrapf(err, "override[%d] for cluster %q is invalid", i, clusterName)
+			}
 			paths.Insert(path)
 		}
 		overridesMap[clusterName] = clusterOverrides
@@ -132,6 +155,48 @@ func GetOverrides(rawObj *unstructured.Unstructured) (OverridesMap, error) {
 	return overridesMap, nil
 }
 
+func validateValueFrom(override ClusterOverride) error {
+	if override.ValueFrom == nil {
+		return nil
+	}
+	if override.Value != nil {
+		return errors.New("value and valueFrom may not both be specified")
+	}
+	if override.Op == "remove" {
+		return errors.New("valueFrom may not be specified for a remove operation")
+	}
+	ref := override.ValueFrom.ConfigMapKeyRef
+	if ref == nil {
+		return errors.New("valueFrom must specify configMapKeyRef")
+	}
+	if ref.Name == "" || ref.Key == "" {
+		return errors.New("valueFrom.configMapKeyRef must specify name and key")
+	}
+	return nil
+}
+
+// ResolveOverrideValues returns a copy of the given overrides with the
+// values of overrides specifying valueFrom retrieved by the given
+// function.
+func ResolveOverrideValues(overrides ClusterOverrides, resolveValue OverrideValueFunc) (ClusterOverrides, error) {
+	resolved := make(ClusterOverrides, len(overrides))
+	for i, override := range overrides {
+		if override.ValueFrom != nil {
+			if resolveValue == nil {
+				return nil, errors.Errorf("unable to resolve the value of the override for path %q", override.Path)
+			}
+			value, err := resolveValue(override.ValueFrom)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to resolve the value of the override for path %q", override.Path)
+			}
+			override.Value = value
+			override.ValueFrom = nil
+		}
+		resolved[i] = override
+	}
+	return resolved, nil
+}
+
 // SetOverrides sets the spec.overrides field of the unstructured
 // object from the provided overrides map.
 func SetOverrides(fedObject *unstructured.Unstructured, overridesMap OverridesMap) error {
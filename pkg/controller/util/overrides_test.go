@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResolveOverrideValues(t *testing.T) {
+	domainRef := &OverrideValueSource{
+		ConfigMapKeyRef: &ConfigMapKeyReference{Name: "cluster-info", Key: "ingressDomain"},
+	}
+	overrides := ClusterOverrides{
+		{Path: "/spec/replicas", Value: int64(3)},
+		{Path: "/spec/rules/0/host", ValueFrom: domainRef},
+	}
+	resolveValue := func(source *OverrideValueSource) (interface{}, error) {
+		if source.ConfigMapKeyRef.Key != "ingressDomain" {
+			return nil, errors.Errorf("key %q not found", source.ConfigMapKeyRef.Key)
+		}
+		return "apps.eu.example.com", nil
+	}
+
+	resolved, err := ResolveOverrideValues(overrides, resolveValue)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := ClusterOverrides{
+		{Path: "/spec/replicas", Value: int64(3)},
+		{Path: "/spec/rules/0/host", Value: "apps.eu.example.com"},
+	}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("Expected %v, got %v", expected, resolved)
+	}
+	if overrides[1].ValueFrom != domainRef || overrides[1].Value != nil {
+		t.Errorf("Expected the given overrides to be unchanged")
+	}
+
+	domainRef.ConfigMapKeyRef.Key = "storageClass"
+	if _, err := ResolveOverrideValues(overrides, resolveValue); err == nil || !strings.Contains(err.Error(), "/spec/rules/0/host") {
+		t.Errorf("Expected an error identifying the override path, got %v", err)
+	}
+	if _, err := ResolveOverrideValues(overrides, nil); err == nil {
+		t.Errorf("Expected an error resolving a value without a resolver")
+	}
+}
+
+func TestGetOverridesValueFrom(t *testing.T) {
+	testCases := map[string]struct {
+		override      map[string]interface{}
+		expectedError string
+	}{
+		"valid reference": {
+			override: map[string]interface{}{
+				"path": "/spec/storageClassName",
+				"valueFrom": map[string]interface{}{
+					"configMapKeyRef": map[string]interface{}{"name": "cluster-info", "key": "storageClass"},
+				},
+			},
+		},
+		"value and valueFrom": {
+			override: map[string]interface{}{
+				"path":  "/spec/storageClassName",
+				"value": "standard",
+				"valueFrom": map[string]interface{}{
+					"configMapKeyRef": map[string]interface{}{"name": "cluster-info", "key": "storageClass"},
+				},
+			},
+			expectedError: "may not both be specified",
+		},
+		"remove with valueFrom": {
+			override: map[string]interface{}{
+				"op":   "remove",
+				"path": "/spec/storageClassName",
+				"valueFrom": map[string]interface{}{
+					"configMapKeyRef": map[string]interface{}{"name": "cluster-info", "key": "storageClass"},
+				},
+			},
+			expectedError: "remove operation",
+		},
+		"missing key": {
+			override: map[string]interface{}{
+				"path": "/spec/storageClassName",
+				"valueFrom": map[string]interface{}{
+					"configMapKeyRef": map[string]interface{}{"name": "cluster-info"},
+				},
+			},
+			expectedError: "must specify name and key",
+		},
+		"missing source": {
+			override: map[string]interface{}{
+				"path":      "/spec/storageClassName",
+				"valueFrom": map[string]interface{}{},
+			},
+			expectedError: "must specify configMapKeyRef",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"overrides": []interface{}{
+							map[string]interface{}{
+								"clusterName":      "cluster1",
+								"clusterOverrides": []interface{}{tc.override},
+							},
+						},
+					},
+				},
+			}
+			_, err := GetOverrides(obj)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Fatalf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
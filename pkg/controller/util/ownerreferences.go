@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// GenericOwnerReference refers to a resource in the same namespace of
+// a member cluster that owns the target resource of a federated
+// resource. Unlike a metav1.OwnerReference, the uid of the owner is
+// not specified since it differs between member clusters.
+type GenericOwnerReference struct {
+	APIVersion         string `json:"apiVersion"`
+	Kind               string `json:"kind"`
+	Name               string `json:"name"`
+	Controller         *bool  `json:"controller,omitempty"`
+	BlockOwnerDeletion *bool  `json:"blockOwnerDeletion,omitempty"`
+}
+
+type GenericOwnerReferenceSpec struct {
+	OwnerReferences []GenericOwnerReference `json:"ownerReferences,omitempty"`
+}
+
+type GenericOwnerReferences struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GenericOwnerReferenceSpec `json:"spec,omitempty"`
+}
+
+// OwnerUIDFunc returns the uid of the referenced owner in a member
+// cluster.
+type OwnerUIDFunc func(ref GenericOwnerReference) (types.UID, error)
+
+// GetOwnerReferences returns the owner references declared by the
+// given federated resource.
+func GetOwnerReferences(fedObject *unstructured.Unstructured) ([]GenericOwnerReference, error) {
+	owners := &GenericOwnerReferences{}
+	err := UnstructuredToInterface(fedObject, owners)
+	if err != nil {
+		return nil, err
+	}
+	return owners.Spec.OwnerReferences, nil
+}
+
+// SetOwnerReferences sets the owner references of the given object to
+// the given declared owner references, resolving the uid of each owner
+// with the given function. Owner references set by the template are
+// replaced.
+func SetOwnerReferences(obj *unstructured.Unstructured, refs []GenericOwnerReference, resolveUID OwnerUIDFunc) error {
+	ownerRefs := make([]metav1.OwnerReference, 0, len(refs))
+	for _, ref := range refs {
+		uid, err := resolveUID(ref)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve owner %s %q", ref.Kind, ref.Name)
+		}
+		ownerRefs = append(ownerRefs, metav1.OwnerReference{
+			APIVersion:         ref.APIVersion,
+			Kind:               ref.Kind,
+			Name:               ref.Name,
+			UID:                uid,
+			Controller:         ref.Controller,
+			BlockOwnerDeletion: ref.BlockOwnerDeletion,
+		})
+	}
+	obj.SetOwnerReferences(ownerRefs)
+	return nil
+}
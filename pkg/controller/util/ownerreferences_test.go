@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSetOwnerReferences(t *testing.T) {
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ownerReferences": []interface{}{
+				map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"name":       "web",
+					"controller": true,
+				},
+			},
+		},
+	}}
+	refs, err := GetOwnerReferences(fedObject)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "template", UID: "host-uid"}})
+	resolveUID := func(ref GenericOwnerReference) (types.UID, error) {
+		if ref.Name != "web" {
+			return "", errors.Errorf("%s %q not found", ref.Kind, ref.Name)
+		}
+		return "member-uid", nil
+	}
+	if err := SetOwnerReferences(obj, refs, resolveUID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	controller := true
+	expected := []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "member-uid", Controller: &controller},
+	}
+	if !reflect.DeepEqual(obj.GetOwnerReferences(), expected) {
+		t.Errorf("Expected owner references %v, got %v", expected, obj.GetOwnerReferences())
+	}
+
+	refs[0].Name = "api"
+	if err := SetOwnerReferences(obj, refs, resolveUID); err == nil {
+		t.Errorf("Expected an error for an owner that does not exist")
+	}
+}
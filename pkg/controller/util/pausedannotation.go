@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+const (
+	// If this annotation is present on a federated resource, changes to
+	// the resource are not propagated to member clusters until the
+	// annotation is removed.
+	PausedAnnotation = "kubefed.io/paused"
+	PausedValue      = "true"
+)
+
+// IsPaused checks whether propagation of a federated resource is
+// paused.
+func IsPaused(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[PausedAnnotation] == PausedValue
+}
+
+// SetPaused pauses or resumes propagation of a federated resource.
+func SetPaused(obj *unstructured.Unstructured, paused bool) {
+	annotations := obj.GetAnnotations()
+	if !paused {
+		if _, ok := annotations[PausedAnnotation]; !ok {
+			return
+		}
+		delete(annotations, PausedAnnotation)
+		obj.SetAnnotations(annotations)
+		return
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[PausedAnnotation] = PausedValue
+	obj.SetAnnotations(annotations)
+}
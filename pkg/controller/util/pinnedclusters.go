@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// The value of this annotation on a federated resource is a
+	// comma-separated list of the names of the clusters whose version
+	// of the resource is pinned. The resource in a pinned cluster is
+	// neither updated nor removed until the cluster is unpinned.
+	PinnedClustersAnnotation = "kubefed.io/pinned-clusters"
+)
+
+// PinnedClusters returns the names of the clusters in which the
+// version of a federated resource is pinned.
+func PinnedClusters(obj *unstructured.Unstructured) sets.String {
+	pinned := sets.String{}
+	for _, clusterName := range strings.Split(obj.GetAnnotations()[PinnedClustersAnnotation], ",") {
+		if clusterName = strings.TrimSpace(clusterName); len(clusterName) > 0 {
+			pinned.Insert(clusterName)
+		}
+	}
+	return pinned
+}
+
+// SetPinnedClusters records the names of the clusters in which the
+// version of a federated resource is pinned, removing the annotation
+// if no cluster is pinned.
+func SetPinnedClusters(obj *unstructured.Unstructured, pinned sets.String) {
+	annotations := obj.GetAnnotations()
+	if pinned.Len() == 0 {
+		if _, ok := annotations[PinnedClustersAnnotation]; !ok {
+			return
+		}
+		delete(annotations, PinnedClustersAnnotation)
+		obj.SetAnnotations(annotations)
+		return
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[PinnedClustersAnnotation] = strings.Join(pinned.List(), ",")
+	obj.SetAnnotations(annotations)
+}
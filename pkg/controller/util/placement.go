@@ -17,18 +17,51 @@ limitations under the License.
 package util
 
 import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 )
 
 type GenericClusterReference struct {
 	Name string `json:"name"`
 }
 
+// GenericNameTemplate describes how the name of a resource in a
+// member cluster is derived from the name of the federated resource.
+type GenericNameTemplate struct {
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// GenericPreferredClusterSelector is a weighted label selector for
+// the clusters the replica scheduler should favor. Preferred selectors
+// only apply to resources targeted by a ReplicaSchedulingPreference;
+// they do not affect the clusters selected by sync placement.
+type GenericPreferredClusterSelector struct {
+	Weight     int64                `json:"weight"`
+	Preference metav1.LabelSelector `json:"preference"`
+}
+
 type GenericPlacementFields struct {
-	Clusters        []GenericClusterReference `json:"clusters,omitempty"`
-	ClusterSelector *metav1.LabelSelector     `json:"clusterSelector,omitempty"`
+	Clusters                  []GenericClusterReference         `json:"clusters,omitempty"`
+	ClusterGroups             []string                          `json:"clusterGroups,omitempty"`
+	ClusterSelector           *metav1.LabelSelector             `json:"clusterSelector,omitempty"`
+	RequiredClusterSelector   *metav1.LabelSelector             `json:"requiredClusterSelector,omitempty"`
+	PreferredClusterSelectors []GenericPreferredClusterSelector `json:"preferredClusterSelectors,omitempty"`
+	RequiredCRDs              []string                          `json:"requiredCRDs,omitempty"`
+	VolumeClaims              []string                          `json:"volumeClaims,omitempty"`
+	NamespaceMapping          map[string]string                 `json:"namespaceMapping,omitempty"`
+	NameTemplates             map[string]GenericNameTemplate    `json:"nameTemplates,omitempty"`
+	Namespaces                []string                          `json:"namespaces,omitempty"`
+	NamespaceSelector         *metav1.LabelSelector             `json:"namespaceSelector,omitempty"`
+	Order                     []string                          `json:"order,omitempty"`
 }
 
 type GenericPlacementSpec struct {
@@ -62,10 +95,100 @@ func (p *GenericPlacement) ClusterNames() []string {
 	return clusterNames
 }
 
+// ClusterGroupNames returns the names of the cluster groups referenced
+// by the placement. A nil result indicates that no cluster groups
+// were specified.
+func (p *GenericPlacement) ClusterGroupNames() []string {
+	return p.Spec.Placement.ClusterGroups
+}
+
+// RequiredCRDs returns the names of the CustomResourceDefinitions that
+// must be installed in a cluster for it to be selected.
+func (p *GenericPlacement) RequiredCRDs() []string {
+	return p.Spec.Placement.RequiredCRDs
+}
+
+// VolumeClaimNames returns the names of the federated persistent
+// volume claims in the namespace of the resource that a cluster must
+// be a placement of for it to be selected.
+func (p *GenericPlacement) VolumeClaimNames() []string {
+	return p.Spec.Placement.VolumeClaims
+}
+
+// NamespaceMapping returns the namespaces the resource is propagated
+// to in the member clusters for which the placement specifies a
+// namespace other than the namespace of the resource, keyed by
+// cluster name.
+func (p *GenericPlacement) NamespaceMapping() map[string]string {
+	return p.Spec.Placement.NamespaceMapping
+}
+
+// NameTemplates returns the templates for the names of the resource
+// in the member clusters for which the placement specifies one, keyed
+// by cluster name.
+func (p *GenericPlacement) NameTemplates() map[string]GenericNameTemplate {
+	return p.Spec.Placement.NameTemplates
+}
+
+// ProjectedNamespaces returns the namespaces of the host cluster that
+// the resource is projected to in addition to its own namespace.
+func (p *GenericPlacement) ProjectedNamespaces() []string {
+	return p.Spec.Placement.Namespaces
+}
+
+// NamespaceSelector returns the selector for the namespaces of the
+// host cluster that the resource is projected to, or nil if the
+// placement does not specify one.
+func (p *GenericPlacement) NamespaceSelector() (labels.Selector, error) {
+	if p.Spec.Placement.NamespaceSelector == nil {
+		return nil, nil
+	}
+	return metav1.LabelSelectorAsSelector(p.Spec.Placement.NamespaceSelector)
+}
+
+// ClusterOrder returns the names of the clusters in the order in which
+// the resource is propagated to them. A nil result indicates that the
+// resource is propagated to all clusters concurrently.
+func (p *GenericPlacement) ClusterOrder() []string {
+	return p.Spec.Placement.Order
+}
+
 func (p *GenericPlacement) ClusterSelector() (labels.Selector, error) {
 	return metav1.LabelSelectorAsSelector(p.Spec.Placement.ClusterSelector)
 }
 
+// RequiredClusterSelector returns the selector that clusters must
+// match to be selected, or nil if the placement does not specify one.
+func (p *GenericPlacement) RequiredClusterSelector() (labels.Selector, error) {
+	if p.Spec.Placement.RequiredClusterSelector == nil {
+		return nil, nil
+	}
+	return metav1.LabelSelectorAsSelector(p.Spec.Placement.RequiredClusterSelector)
+}
+
+// PreferredClusterWeights returns the sum of the weights of the
+// preferred cluster selectors matched by each of the given clusters,
+// keyed by cluster name. Clusters that match no preferred selector
+// are omitted, as are selectors without a positive weight.
+func (p *GenericPlacement) PreferredClusterWeights(clusters []*fedv1b1.KubeFedCluster) (map[string]int64, error) {
+	weights := make(map[string]int64)
+	for i, term := range p.Spec.Placement.PreferredClusterSelectors {
+		if term.Weight <= 0 {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&term.Preference)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid preference of spec.placement.preferredClusterSelectors[%d]", i)
+		}
+		for _, cluster := range clusters {
+			if selector.Matches(labels.Set(cluster.Labels)) {
+				weights[cluster.Name] += term.Weight
+			}
+		}
+	}
+	return weights, nil
+}
+
 func GetClusterNames(obj *unstructured.Unstructured) ([]string, error) {
 	placement, err := UnmarshalGenericPlacement(obj)
 	if err != nil {
@@ -86,3 +209,45 @@ func SetClusterNames(obj *unstructured.Unstructured, clusterNames []string) erro
 	}
 	return unstructured.SetNestedSlice(obj.Object, clusters, SpecField, PlacementField, ClustersField)
 }
+
+// SetPlacement replaces the spec.placement field of the given
+// federated resource with the given placement.
+func SetPlacement(obj *unstructured.Unstructured, placement GenericPlacementFields) error {
+	content, err := json.Marshal(placement)
+	if err != nil {
+		return err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(obj.Object, fields, SpecField, PlacementField)
+}
+
+// ClusterGroupMembers returns the names of the given clusters that are
+// members of the cluster group.
+func ClusterGroupMembers(group *fedv1b1.ClusterGroup, clusters []*fedv1b1.KubeFedCluster) (sets.String, error) {
+	members := sets.String{}
+	if group.Spec.Clusters != nil {
+		memberNames := sets.NewString(group.Spec.Clusters...)
+		for _, cluster := range clusters {
+			if memberNames.Has(cluster.Name) {
+				members.Insert(cluster.Name)
+			}
+		}
+		return members, nil
+	}
+	if group.Spec.ClusterSelector == nil {
+		return members, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(group.Spec.ClusterSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		if selector.Matches(labels.Set(cluster.Labels)) {
+			members.Insert(cluster.Name)
+		}
+	}
+	return members, nil
+}
@@ -20,6 +20,8 @@ import (
 	"hash/fnv"
 	"sort"
 
+	"github.com/pkg/errors"
+
 	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
 )
 
@@ -27,6 +29,8 @@ import (
 // federated clusters.
 type Planner struct {
 	preferences *fedschedulingv1a1.ReplicaSchedulingPreference
+	// The cost weights of the clusters, keyed by cluster name.
+	clusterCosts map[string]int64
 }
 
 type namedClusterPreferences struct {
@@ -46,9 +50,10 @@ func (a byWeight) Less(i, j int) bool {
 	return (a[i].Weight > a[j].Weight) || (a[i].Weight == a[j].Weight && a[i].hash < a[j].hash)
 }
 
-func NewPlanner(preferences *fedschedulingv1a1.ReplicaSchedulingPreference) *Planner {
+func NewPlanner(preferences *fedschedulingv1a1.ReplicaSchedulingPreference, clusterCosts map[string]int64) *Planner {
 	return &Planner{
-		preferences: preferences,
+		preferences:  preferences,
+		clusterCosts: clusterCosts,
 	}
 }
 
@@ -67,6 +72,17 @@ func NewPlanner(preferences *fedschedulingv1a1.ReplicaSchedulingPreference) *Pla
 func (p *Planner) Plan(availableClusters []string, currentReplicaCount map[string]int64,
 	estimatedCapacity map[string]int64, replicaSetKey string) (map[string]int64, map[string]int64, error) {
 
+	distribution := p.preferences.Spec.Distribution
+	switch distribution {
+	case "", fedschedulingv1a1.WeightedDistribution, fedschedulingv1a1.CostOptimizedDistribution:
+	case fedschedulingv1a1.SpilloverDistribution:
+		if p.preferences.Spec.PrimaryCluster == "" {
+			return nil, nil, errors.New("a primary cluster is required for spillover distribution")
+		}
+	default:
+		return nil, nil, errors.Errorf("unsupported distribution %q", distribution)
+	}
+
 	preferences := make([]*namedClusterPreferences, 0, len(availableClusters))
 	plan := make(map[string]int64, len(preferences))
 	overflow := make(map[string]int64, len(preferences))
@@ -84,7 +100,7 @@ func (p *Planner) Plan(availableClusters []string, currentReplicaCount map[strin
 		return &namedClusterPreferences{
 			clusterName:        name,
 			hash:               hasher.Sum32(),
-			ClusterPreferences: pref,
+			ClusterPreferences: p.boundedPreferences(pref),
 		}, nil
 	}
 
@@ -130,7 +146,9 @@ func (p *Planner) Plan(availableClusters []string, currentReplicaCount map[strin
 	// distribution code.
 	preallocated := make(map[string]int64)
 
-	if !p.preferences.Spec.Rebalance {
+	// Replicas spilled over from the primary cluster are not retained
+	// so that they move back once the primary cluster regains capacity.
+	if !p.preferences.Spec.Rebalance && distribution != fedschedulingv1a1.SpilloverDistribution {
 		for _, preference := range preferences {
 			planned := plan[preference.clusterName]
 			count, hasSome := currentReplicaCount[preference.clusterName]
@@ -153,6 +171,15 @@ func (p *Planner) Plan(availableClusters []string, currentReplicaCount map[strin
 		}
 	}
 
+	if distribution == fedschedulingv1a1.CostOptimizedDistribution {
+		overflow = p.distributeByCost(preferences, plan, estimatedCapacity, remainingReplicas)
+		return plan, overflow, nil
+	}
+
+	if distribution == fedschedulingv1a1.SpilloverDistribution {
+		preferences, remainingReplicas = p.fillPrimary(preferences, plan, estimatedCapacity, remainingReplicas)
+	}
+
 	modified := true
 
 	// It is possible single pass of the loop is not enough to distribute all replicas among clusters due
@@ -245,9 +272,118 @@ func (p *Planner) Plan(availableClusters []string, currentReplicaCount map[strin
 	}
 }
 
+// distributeByCost assigns the remaining replicas to the clusters
+// with the lowest cost first. Clusters of equal cost are filled in
+// order of decreasing weight, and clusters with no weight receive no
+// replicas beyond those already planned. Replicas that no cluster can
+// take within its maximum replicas and capacity are returned as the
+// overflow of the cheapest clusters that lack the capacity for them,
+// up to their maximum replicas, as with weighted distribution.
+func (p *Planner) distributeByCost(preferences []*namedClusterPreferences, plan map[string]int64,
+	estimatedCapacity map[string]int64, remainingReplicas int64) map[string]int64 {
+
+	byCost := make([]*namedClusterPreferences, len(preferences))
+	copy(byCost, preferences)
+	sort.SliceStable(byCost, func(i, j int) bool {
+		return p.clusterCosts[byCost[i].clusterName] < p.clusterCosts[byCost[j].clusterName]
+	})
+
+	// The replicas each cluster would have taken beyond its capacity,
+	// in order of increasing cost.
+	var overflowed []*namedClusterPreferences
+	capacityOverflow := make(map[string]int64)
+	for _, preference := range byCost {
+		if remainingReplicas <= 0 {
+			break
+		}
+		if preference.Weight <= 0 {
+			continue
+		}
+		start := plan[preference.clusterName]
+		total := start + remainingReplicas
+		if preference.MaxReplicas != nil && total > *preference.MaxReplicas {
+			total = *preference.MaxReplicas
+		}
+		if capacity, hasCapacity := estimatedCapacity[preference.clusterName]; hasCapacity && total > capacity {
+			overflowed = append(overflowed, preference)
+			capacityOverflow[preference.clusterName] = total - maxInt64(capacity, start)
+			total = capacity
+		}
+		if total <= start {
+			continue
+		}
+		remainingReplicas -= total - start
+		plan[preference.clusterName] = total
+	}
+
+	// Only the replicas that more expensive clusters could not take
+	// overflow.
+	overflow := make(map[string]int64)
+	for _, preference := range overflowed {
+		if remainingReplicas <= 0 {
+			break
+		}
+		extra := minInt64(capacityOverflow[preference.clusterName], remainingReplicas)
+		if extra > 0 {
+			overflow[preference.clusterName] = extra
+			remainingReplicas -= extra
+		}
+	}
+	return overflow
+}
+
+// fillPrimary assigns the remaining replicas to the primary cluster up
+// to its maximum replicas or capacity. The preferences of the other
+// clusters, among which the replicas still remaining are to be
+// distributed, are returned along with the number of those replicas.
+func (p *Planner) fillPrimary(preferences []*namedClusterPreferences, plan map[string]int64,
+	estimatedCapacity map[string]int64, remainingReplicas int64) ([]*namedClusterPreferences, int64) {
+
+	secondaries := make([]*namedClusterPreferences, 0, len(preferences))
+	for _, preference := range preferences {
+		if preference.clusterName != p.preferences.Spec.PrimaryCluster {
+			secondaries = append(secondaries, preference)
+			continue
+		}
+		start := plan[preference.clusterName]
+		total := start + remainingReplicas
+		if preference.MaxReplicas != nil && total > *preference.MaxReplicas {
+			total = *preference.MaxReplicas
+		}
+		if capacity, hasCapacity := estimatedCapacity[preference.clusterName]; hasCapacity && total > capacity {
+			total = capacity
+		}
+		if total > start {
+			remainingReplicas -= total - start
+			plan[preference.clusterName] = total
+		}
+	}
+	return secondaries, remainingReplicas
+}
+
+// boundedPreferences applies the per-cluster replica bounds of the
+// planner preferences to the given cluster preferences.
+func (p *Planner) boundedPreferences(pref fedschedulingv1a1.ClusterPreferences) fedschedulingv1a1.ClusterPreferences {
+	if p.preferences.Spec.MinReplicasPerCluster > pref.MinReplicas {
+		pref.MinReplicas = p.preferences.Spec.MinReplicasPerCluster
+	}
+	maxReplicas := p.preferences.Spec.MaxReplicasPerCluster
+	if maxReplicas != nil && (pref.MaxReplicas == nil || *maxReplicas < *pref.MaxReplicas) {
+		pref.MaxReplicas = maxReplicas
+	}
+	return pref
+}
+
 func minInt64(a int64, b int64) int64 {
 	if a < b {
 		return a
 	}
 	return b
 }
+
+func maxInt64(a int64, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -30,7 +30,7 @@ func doCheck(t *testing.T, pref map[string]fedschedulingv1a1.ClusterPreferences,
 			Clusters:      pref,
 			TotalReplicas: int32(replicas),
 		},
-	})
+	}, nil)
 	plan, overflow, err := planer.Plan(clusters, map[string]int64{}, map[string]int64{}, "")
 	assert.Nil(t, err)
 	assert.EqualValues(t, expected, plan)
@@ -44,7 +44,7 @@ func doCheckWithExisting(t *testing.T, pref map[string]fedschedulingv1a1.Cluster
 			Clusters:      pref,
 			TotalReplicas: int32(replicas),
 		},
-	})
+	}, nil)
 	plan, overflow, err := planer.Plan(clusters, existing, map[string]int64{}, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(overflow))
@@ -62,7 +62,7 @@ func doCheckWithExistingAndCapacity(t *testing.T, rebalance bool, pref map[strin
 			Clusters:      pref,
 			TotalReplicas: int32(replicas),
 		},
-	})
+	}, nil)
 	plan, overflow, err := planer.Plan(clusters, existing, capacity, "")
 	assert.Nil(t, err)
 	assert.EqualValues(t, expected, plan)
@@ -308,6 +308,44 @@ func TestMax(t *testing.T) {
 		map[string]int64{"A": 0, "B": 0, "C": 0})
 }
 
+func TestReplicasPerCluster(t *testing.T) {
+	check := func(pref map[string]fedschedulingv1a1.ClusterPreferences, minReplicas int64, maxReplicas *int64, expected map[string]int64) {
+		planer := NewPlanner(&fedschedulingv1a1.ReplicaSchedulingPreference{
+			Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+				Clusters:              pref,
+				TotalReplicas:         50,
+				MinReplicasPerCluster: minReplicas,
+				MaxReplicasPerCluster: maxReplicas,
+			},
+		}, nil)
+		plan, _, err := planer.Plan([]string{"A", "B", "C"}, map[string]int64{}, map[string]int64{}, "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected, plan)
+	}
+
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 0}},
+		2, nil,
+		map[string]int64{"A": 2, "B": 2, "C": 2})
+
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 0},
+		"A": {MinReplicas: 5, Weight: 0}},
+		2, nil,
+		map[string]int64{"A": 5, "B": 2, "C": 2})
+
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1}},
+		0, pint(5),
+		map[string]int64{"A": 5, "B": 5, "C": 5})
+
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1},
+		"A": {Weight: 1, MaxReplicas: pint(3)}},
+		0, pint(5),
+		map[string]int64{"A": 3, "B": 5, "C": 5})
+}
+
 func TestWeight(t *testing.T) {
 	doCheck(t, map[string]fedschedulingv1a1.ClusterPreferences{
 		"A": {Weight: 1},
@@ -358,3 +396,161 @@ func TestWeight(t *testing.T) {
 		91, []string{"A", "B", "C", "D", "E"},
 		map[string]int64{"A": 10, "B": 25, "C": 21, "D": 10, "E": 25})
 }
+
+func TestCostOptimized(t *testing.T) {
+	costs := map[string]int64{"A": 10, "B": 1, "C": 5}
+	check := func(pref map[string]fedschedulingv1a1.ClusterPreferences, replicas int64, minReplicas int64,
+		capacity map[string]int64, expected map[string]int64) {
+		planer := NewPlanner(&fedschedulingv1a1.ReplicaSchedulingPreference{
+			Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+				Clusters:              pref,
+				TotalReplicas:         int32(replicas),
+				MinReplicasPerCluster: minReplicas,
+				Distribution:          fedschedulingv1a1.CostOptimizedDistribution,
+			},
+		}, costs)
+		plan, overflow, err := planer.Plan([]string{"A", "B", "C"}, map[string]int64{}, capacity, "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected, plan)
+		assert.Equal(t, 0, len(overflow))
+	}
+
+	// All replicas are scheduled to the cheapest cluster.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1}},
+		50, 0, map[string]int64{},
+		map[string]int64{"A": 0, "B": 50, "C": 0})
+
+	// The minimum replicas of each cluster are honored.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1}},
+		50, 2, map[string]int64{},
+		map[string]int64{"A": 2, "B": 46, "C": 2})
+
+	// Replicas burst to more expensive clusters when cheaper
+	// clusters reach their maximum or capacity.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1},
+		"B": {Weight: 1, MaxReplicas: pint(20)}},
+		50, 0, map[string]int64{"C": 25},
+		map[string]int64{"A": 5, "B": 20, "C": 25})
+
+	// Clusters without weight receive no replicas beyond their minimum.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1},
+		"B": {MinReplicas: 3}},
+		50, 0, map[string]int64{},
+		map[string]int64{"A": 0, "B": 3, "C": 47})
+}
+
+func TestCostOptimizedOverflow(t *testing.T) {
+	costs := map[string]int64{"A": 10, "B": 1, "C": 5}
+	check := func(replicas int64, maxReplicas int64, capacity map[string]int64,
+		expected map[string]int64, expectedOverflow map[string]int64) {
+		planer := NewPlanner(&fedschedulingv1a1.ReplicaSchedulingPreference{
+			Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+				Clusters:              map[string]fedschedulingv1a1.ClusterPreferences{"*": {Weight: 1}},
+				TotalReplicas:         int32(replicas),
+				MaxReplicasPerCluster: pint(maxReplicas),
+				Distribution:          fedschedulingv1a1.CostOptimizedDistribution,
+			},
+		}, costs)
+		plan, overflow, err := planer.Plan([]string{"A", "B", "C"}, map[string]int64{}, capacity, "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected, plan)
+		assert.EqualValues(t, expectedOverflow, overflow)
+	}
+
+	// Replicas that do not fit within the maximum replicas and
+	// capacity of the clusters overflow in the cheapest clusters that
+	// lack the capacity, up to their maximum replicas.
+	check(50, 10, map[string]int64{"A": 8, "C": 5},
+		map[string]int64{"A": 8, "B": 10, "C": 5},
+		map[string]int64{"A": 2, "C": 5})
+
+	// Only the replicas that no cluster can take overflow.
+	check(22, 10, map[string]int64{"C": 5},
+		map[string]int64{"A": 7, "B": 10, "C": 5},
+		map[string]int64{})
+
+	// Without capacity limits, the maximum replicas are honored.
+	check(50, 10, map[string]int64{},
+		map[string]int64{"A": 10, "B": 10, "C": 10},
+		map[string]int64{})
+}
+
+func TestUnsupportedDistribution(t *testing.T) {
+	planer := NewPlanner(&fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			Clusters:      map[string]fedschedulingv1a1.ClusterPreferences{"*": {Weight: 1}},
+			TotalReplicas: 50,
+			Distribution:  "Cheapest",
+		},
+	}, nil)
+	_, _, err := planer.Plan([]string{"A", "B", "C"}, map[string]int64{}, map[string]int64{}, "")
+	assert.NotNil(t, err)
+}
+
+func TestSpillover(t *testing.T) {
+	check := func(pref map[string]fedschedulingv1a1.ClusterPreferences, existing map[string]int64,
+		capacity map[string]int64, expected map[string]int64) {
+		planer := NewPlanner(&fedschedulingv1a1.ReplicaSchedulingPreference{
+			Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+				Clusters:       pref,
+				TotalReplicas:  50,
+				Distribution:   fedschedulingv1a1.SpilloverDistribution,
+				PrimaryCluster: "A",
+			},
+		}, nil)
+		plan, overflow, err := planer.Plan([]string{"A", "B", "C"}, existing, capacity, "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected, plan)
+		assert.Equal(t, 0, len(overflow))
+	}
+
+	// The primary cluster absorbs all replicas.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1}},
+		map[string]int64{}, map[string]int64{},
+		map[string]int64{"A": 50, "B": 0, "C": 0})
+
+	// Replicas beyond the maximum of the primary cluster spill over.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1},
+		"A": {Weight: 1, MaxReplicas: pint(30)}},
+		map[string]int64{}, map[string]int64{},
+		map[string]int64{"A": 30, "B": 10, "C": 10})
+
+	// Replicas beyond the capacity of the primary cluster spill over
+	// in proportion to the weights of the other clusters.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1},
+		"C": {Weight: 3}},
+		map[string]int64{}, map[string]int64{"A": 10},
+		map[string]int64{"A": 10, "B": 10, "C": 30})
+
+	// Spilled over replicas are removed once the primary cluster
+	// regains capacity, even without rebalancing.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1}},
+		map[string]int64{"A": 10, "B": 20, "C": 20}, map[string]int64{},
+		map[string]int64{"A": 50, "B": 0, "C": 0})
+
+	// The minimum replicas of the other clusters are honored.
+	check(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1, MinReplicas: 5}},
+		map[string]int64{}, map[string]int64{},
+		map[string]int64{"A": 40, "B": 5, "C": 5})
+}
+
+func TestSpilloverWithoutPrimaryCluster(t *testing.T) {
+	planer := NewPlanner(&fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			Clusters:      map[string]fedschedulingv1a1.ClusterPreferences{"*": {Weight: 1}},
+			TotalReplicas: 50,
+			Distribution:  fedschedulingv1a1.SpilloverDistribution,
+		},
+	}, nil)
+	_, _, err := planer.Plan([]string{"A", "B", "C"}, map[string]int64{}, map[string]int64{}, "")
+	assert.NotNil(t, err)
+}
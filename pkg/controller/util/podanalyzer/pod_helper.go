@@ -29,6 +29,9 @@ type PodAnalysisResult struct {
 	RunningAndReady int
 	// Number of pods that have been in unschedulable state for UnshedulableThreshold seconds.
 	Unschedulable int
+	// Number of pods with a container that is waiting to be restarted
+	// after repeatedly crashing.
+	CrashLooping int
 
 	// TODO: Handle other scenarios like pod waiting too long for scheduler etc.
 }
@@ -36,6 +39,10 @@ type PodAnalysisResult struct {
 const (
 	// TODO: make it configurable
 	UnschedulableThreshold = 60 * time.Second
+
+	// CrashLoopBackOffReason is the waiting reason reported by the
+	// kubelet for a container that is backing off after crashing.
+	CrashLoopBackOffReason = "CrashLoopBackOff"
 )
 
 // AnalyzePods calculates how many pods from the list are in one of
@@ -58,6 +65,18 @@ func AnalyzePods(podList *api_v1.PodList, currentTime time.Time) PodAnalysisResu
 				result.Unschedulable++
 			}
 		}
+		if isCrashLooping(&pod) {
+			result.CrashLooping++
+		}
 	}
 	return result
 }
+
+func isCrashLooping(pod *api_v1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason == CrashLoopBackOffReason {
+			return true
+		}
+	}
+	return false
+}
@@ -55,6 +55,20 @@ func TestAnalyze(t *testing.T) {
 			Phase:      api_v1.PodPending,
 			Conditions: []api_v1.PodCondition{},
 		})
+	podCrashLooping := newPod(t, "pC",
+		api_v1.PodStatus{
+			Phase: api_v1.PodRunning,
+			ContainerStatuses: []api_v1.ContainerStatus{
+				{
+					Name: "app",
+					State: api_v1.ContainerState{
+						Waiting: &api_v1.ContainerStateWaiting{
+							Reason: CrashLoopBackOffReason,
+						},
+					},
+				},
+			},
+		})
 
 	result := AnalyzePods(&api_v1.PodList{Items: []api_v1.Pod{*podRunning, *podRunning, *podRunning, *podUnschedulable, *podUnschedulable}}, now)
 	assert.Equal(t, PodAnalysisResult{
@@ -69,6 +83,14 @@ func TestAnalyze(t *testing.T) {
 		RunningAndReady: 0,
 		Unschedulable:   0,
 	}, result)
+
+	result = AnalyzePods(&api_v1.PodList{Items: []api_v1.Pod{*podRunning, *podCrashLooping}}, now)
+	assert.Equal(t, PodAnalysisResult{
+		Total:           2,
+		RunningAndReady: 1,
+		Unschedulable:   0,
+		CrashLooping:    1,
+	}, result)
 }
 
 func newPod(t *testing.T, name string, status api_v1.PodStatus) *api_v1.Pod {
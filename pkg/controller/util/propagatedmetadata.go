@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	ClusterNameLabelKey               = "kubefed.io/cluster-name"
+	FederatedResourceUIDAnnotationKey = "kubefed.io/federated-resource-uid"
+	PropagationTimestampAnnotationKey = "kubefed.io/propagated-at"
+)
+
+// AddPropagatedMetadata adds the labels and annotations enabled by the
+// given configuration to an object propagated from the given
+// federated object to the named cluster.
+func AddPropagatedMetadata(obj, fedObject *unstructured.Unstructured, clusterName string, config *fedv1b1.PropagatedMetadataConfig, now time.Time) {
+	if config == nil {
+		return
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	fedLabels := fedObject.GetLabels()
+	for _, key := range config.PassthroughLabels {
+		if value, ok := fedLabels[key]; ok {
+			labels[key] = value
+		}
+	}
+	// Cluster names are not guaranteed to be valid label values.
+	if config.ClusterNameLabel && len(validation.IsValidLabelValue(clusterName)) == 0 {
+		labels[ClusterNameLabelKey] = clusterName
+	}
+	obj.SetLabels(labels)
+
+	if !config.FederatedResourceUIDAnnotation && !config.PropagationTimestampAnnotation {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if config.FederatedResourceUIDAnnotation {
+		annotations[FederatedResourceUIDAnnotationKey] = string(fedObject.GetUID())
+	}
+	if config.PropagationTimestampAnnotation {
+		annotations[PropagationTimestampAnnotationKey] = now.UTC().Format(time.RFC3339)
+	}
+	obj.SetAnnotations(annotations)
+}
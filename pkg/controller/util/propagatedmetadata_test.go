@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestAddPropagatedMetadata(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetUID(types.UID("1234"))
+	fedObject.SetLabels(map[string]string{
+		"team":  "payments",
+		"other": "ignored",
+	})
+
+	testCases := map[string]struct {
+		config              *fedv1b1.PropagatedMetadataConfig
+		clusterName         string
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		"no configuration": {
+			clusterName:    "cluster1",
+			expectedLabels: map[string]string{"app": "nginx"},
+		},
+		"all metadata": {
+			config: &fedv1b1.PropagatedMetadataConfig{
+				ClusterNameLabel:               true,
+				FederatedResourceUIDAnnotation: true,
+				PropagationTimestampAnnotation: true,
+				PassthroughLabels:              []string{"team", "missing"},
+			},
+			clusterName: "cluster1",
+			expectedLabels: map[string]string{
+				"app":               "nginx",
+				"team":              "payments",
+				ClusterNameLabelKey: "cluster1",
+			},
+			expectedAnnotations: map[string]string{
+				FederatedResourceUIDAnnotationKey: "1234",
+				PropagationTimestampAnnotationKey: "2020-06-01T12:00:00Z",
+			},
+		},
+		"cluster name that is not a valid label value": {
+			config: &fedv1b1.PropagatedMetadataConfig{
+				ClusterNameLabel: true,
+			},
+			clusterName:    "cluster1.example.com.with.a.name.that.is.longer.than.sixty-three.characters",
+			expectedLabels: map[string]string{"app": "nginx"},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetLabels(map[string]string{"app": "nginx"})
+			AddPropagatedMetadata(obj, fedObject, tc.clusterName, tc.config, now)
+			if labels := obj.GetLabels(); !reflect.DeepEqual(labels, tc.expectedLabels) {
+				t.Fatalf("Expected labels %v, got %v", tc.expectedLabels, labels)
+			}
+			if annotations := obj.GetAnnotations(); !reflect.DeepEqual(annotations, tc.expectedAnnotations) {
+				t.Fatalf("Expected annotations %v, got %v", tc.expectedAnnotations, annotations)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package util
 
 import (
 	"fmt"
+	"strings"
 
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
@@ -55,3 +56,13 @@ func (n QualifiedName) String() string {
 	}
 	return fmt.Sprintf("%s/%s", n.Namespace, n.Name)
 }
+
+// ParseQualifiedName parses the string representation of a
+// QualifiedName.
+func ParseQualifiedName(s string) QualifiedName {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) == 1 {
+		return QualifiedName{Name: parts[0]}
+	}
+	return QualifiedName{Namespace: parts[0], Name: parts[1]}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// ApplyObserver is notified of the outcome of creates and updates of
+// resources in member clusters.
+type ApplyObserver interface {
+	ObserveApply(clusterName string, failed bool)
+}
+
+// IsClusterQuarantined returns whether propagation to the cluster is
+// suspended by a quarantine.
+func IsClusterQuarantined(cluster *fedv1b1.KubeFedCluster) bool {
+	return cluster.Status.Quarantine != nil
+}
+
+// quarantineChanged returns whether the cluster has been quarantined
+// or released.
+func quarantineChanged(oldCluster, curCluster *fedv1b1.KubeFedCluster) bool {
+	return IsClusterQuarantined(oldCluster) != IsClusterQuarantined(curCluster)
+}
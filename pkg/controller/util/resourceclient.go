@@ -17,6 +17,8 @@ limitations under the License.
 package util
 
 import (
+	"sync"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -68,3 +70,41 @@ func (c *resourceClient) Resources(namespace string) dynamic.ResourceInterface {
 func (c *resourceClient) Kind() string {
 	return c.kind
 }
+
+// ResourceClientCache lazily creates a ResourceClient for each API
+// resource accessed by a controller and reuses it for later accesses.
+type ResourceClientCache struct {
+	config *rest.Config
+
+	lock    sync.Mutex
+	clients map[schema.GroupVersionResource]ResourceClient
+}
+
+func NewResourceClientCache(config *rest.Config) *ResourceClientCache {
+	return &ResourceClientCache{
+		config:  config,
+		clients: make(map[schema.GroupVersionResource]ResourceClient),
+	}
+}
+
+// Get returns the client for the given API resource, creating it if it
+// does not yet exist.
+func (c *ResourceClientCache) Get(apiResource metav1.APIResource) (ResourceClient, error) {
+	resource := schema.GroupVersionResource{
+		Group:    apiResource.Group,
+		Version:  apiResource.Version,
+		Resource: apiResource.Name,
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if client, ok := c.clients[resource]; ok {
+		return client, nil
+	}
+	client, err := NewResourceClient(c.config, &apiResource)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[resource] = client
+	return client, nil
+}
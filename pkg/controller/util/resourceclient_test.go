@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestResourceClientCache(t *testing.T) {
+	cache := NewResourceClientCache(&rest.Config{Host: "https://localhost:6443"})
+	deployments := metav1.APIResource{Group: "types.kubefed.io", Version: "v1beta1", Name: "federateddeployments", Kind: "FederatedDeployment", Namespaced: true}
+	services := metav1.APIResource{Group: "types.kubefed.io", Version: "v1beta1", Name: "federatedservices", Kind: "FederatedService", Namespaced: true}
+
+	client, err := cache.Get(deployments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.Kind() != deployments.Kind {
+		t.Errorf("Expected a client for kind %q, got %q", deployments.Kind, client.Kind())
+	}
+	cached, err := cache.Get(deployments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cached != client {
+		t.Errorf("Expected the client for %q to be reused", deployments.Name)
+	}
+	other, err := cache.Get(services)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if other == client || other.Kind() != services.Kind {
+		t.Errorf("Expected a separate client for %q", services.Name)
+	}
+}
@@ -21,7 +21,6 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/labels"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -35,13 +34,32 @@ func NewResourceInformer(client ResourceClient, namespace string, apiResource *m
 }
 
 // NewManagedResourceInformer returns an informer limited to resources
-// managed by KubeFed as indicated by labeling.
-func NewManagedResourceInformer(client ResourceClient, namespace string, apiResource *metav1.APIResource, triggerFunc func(pkgruntime.Object)) (cache.Store, cache.Controller) {
-	labelSelector := labels.Set(map[string]string{ManagedByKubeFedLabelKey: ManagedByKubeFedLabelValue}).AsSelector().String()
+// managed by the named KubeFed instance as indicated by labeling.
+func NewManagedResourceInformer(client ResourceClient, namespace, instanceName string, apiResource *metav1.APIResource, triggerFunc func(pkgruntime.Object)) (cache.Store, cache.Controller) {
+	labelSelector := ManagedLabelSelector(instanceName).String()
 	return newResourceInformer(client, namespace, apiResource, triggerFunc, labelSelector)
 }
 
 func newResourceInformer(client ResourceClient, namespace string, apiResource *metav1.APIResource, triggerFunc func(pkgruntime.Object), labelSelector string) (cache.Store, cache.Controller) {
+	return newListWatchInformer(newResourceListWatch(client, namespace, labelSelector), apiResource, triggerFunc)
+}
+
+// newResourceListWatch returns a list watch of the resources matching
+// the given label selector.
+func newResourceListWatch(client ResourceClient, namespace, labelSelector string) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
+			options.LabelSelector = labelSelector
+			return client.Resources(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector
+			return client.Resources(namespace).Watch(options)
+		},
+	}
+}
+
+func newListWatchInformer(listWatch cache.ListerWatcher, apiResource *metav1.APIResource, triggerFunc func(pkgruntime.Object)) (cache.Store, cache.Controller) {
 	obj := &unstructured.Unstructured{}
 
 	if apiResource != nil {
@@ -49,16 +67,7 @@ func newResourceInformer(client ResourceClient, namespace string, apiResource *m
 		obj.SetGroupVersionKind(gvk)
 	}
 	return cache.NewInformer(
-		&cache.ListWatch{
-			ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
-				options.LabelSelector = labelSelector
-				return client.Resources(namespace).List(options)
-			},
-			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-				options.LabelSelector = labelSelector
-				return client.Resources(namespace).Watch(options)
-			},
-		},
+		listWatch,
 		obj, // use an unstructured type with apiVersion / kind populated for informer logging purposes
 		NoResyncPeriod,
 		NewTriggerOnAllChanges(triggerFunc),
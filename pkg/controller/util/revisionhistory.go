@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/rand"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+)
+
+const (
+	// RevisionOfLabel labels the ControllerRevisions recording the
+	// history of a federated resource with the UID of the resource.
+	RevisionOfLabel = "kubefed.io/revision-of"
+
+	// The maximum length of the name of a federated resource that is
+	// used as the prefix of the names of its revisions.
+	maxRevisionPrefixLength = 200
+)
+
+// revisionData is the content of a revision of a federated resource.
+type revisionData struct {
+	Template  interface{} `json:"template,omitempty"`
+	Overrides interface{} `json:"overrides,omitempty"`
+}
+
+// RevisionNamespace returns the namespace of the revisions of the
+// given federated resource. The revisions of cluster-scoped resources
+// are kept in the KubeFed namespace.
+func RevisionNamespace(fedObject *unstructured.Unstructured, kubefedNamespace string) string {
+	if namespace := fedObject.GetNamespace(); len(namespace) > 0 {
+		return namespace
+	}
+	return kubefedNamespace
+}
+
+// NewRevision returns a revision recording the template and overrides
+// of the given federated resource. The name of the revision is derived
+// from its content so that identical content maps to the same
+// revision.
+func NewRevision(fedObject *unstructured.Unstructured, kubefedNamespace string, revision int64) (*appsv1.ControllerRevision, error) {
+	data := revisionData{}
+	data.Template, _, _ = unstructured.NestedFieldNoCopy(fedObject.Object, SpecField, TemplateField)
+	data.Overrides, _, _ = unstructured.NestedFieldNoCopy(fedObject.Object, SpecField, OverridesField)
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the revision")
+	}
+
+	hasher := fnv.New32a()
+	hasher.Write([]byte(fedObject.GetUID()))
+	hasher.Write(raw)
+	hash := rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+
+	prefix := fedObject.GetName()
+	if len(prefix) > maxRevisionPrefixLength {
+		prefix = prefix[:maxRevisionPrefixLength]
+	}
+
+	trueVar := true
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: RevisionNamespace(fedObject, kubefedNamespace),
+			Name:      fmt.Sprintf("%s-%s", prefix, hash),
+			Labels: map[string]string{
+				RevisionOfLabel: string(fedObject.GetUID()),
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         fedObject.GetAPIVersion(),
+				Kind:               fedObject.GetKind(),
+				Name:               fedObject.GetName(),
+				UID:                fedObject.GetUID(),
+				BlockOwnerDeletion: &trueVar,
+			}},
+		},
+		Data:     runtime.RawExtension{Raw: raw},
+		Revision: revision,
+	}, nil
+}
+
+// ListRevisions returns the revisions of the given federated resource
+// ordered from oldest to newest.
+func ListRevisions(client appsv1client.ControllerRevisionsGetter, fedObject *unstructured.Unstructured, kubefedNamespace string) ([]appsv1.ControllerRevision, error) {
+	selector := labels.SelectorFromSet(labels.Set{RevisionOfLabel: string(fedObject.GetUID())})
+	revisionList, err := client.ControllerRevisions(RevisionNamespace(fedObject, kubefedNamespace)).List(metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	revisions := revisionList.Items
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// RestoreRevision sets the template and overrides of the given
+// federated resource to those recorded in the given revision.
+func RestoreRevision(fedObject *unstructured.Unstructured, revision *appsv1.ControllerRevision) error {
+	// Integers are decoded as int64 as they would be by the API.
+	data := map[string]interface{}{}
+	if err := utiljson.Unmarshal(revision.Data.Raw, &data); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal revision %q", revision.Name)
+	}
+	for _, field := range []string{TemplateField, OverridesField} {
+		value, ok := data[field]
+		if !ok {
+			unstructured.RemoveNestedField(fedObject.Object, SpecField, field)
+			continue
+		}
+		if err := unstructured.SetNestedField(fedObject.Object, value, SpecField, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRestoreRevision(t *testing.T) {
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(3)},
+			},
+			"placement": map[string]interface{}{
+				"clusterSelector": map[string]interface{}{},
+			},
+		},
+	}}
+	fedObject.SetName("foo")
+	fedObject.SetUID("uid")
+	revision, err := NewRevision(fedObject, "kube-federation-system", 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if revision.Namespace != "kube-federation-system" {
+		t.Errorf("Expected the revision of a cluster-scoped resource in the KubeFed namespace, got %q", revision.Namespace)
+	}
+
+	changed := fedObject.DeepCopy()
+	err = unstructured.SetNestedField(changed.Object, int64(5), "spec", "template", "spec", "replicas")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = unstructured.SetNestedSlice(changed.Object, []interface{}{
+		map[string]interface{}{"clusterName": "cluster1"},
+	}, "spec", "overrides")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	changedRevision, err := NewRevision(changed, "kube-federation-system", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if changedRevision.Name == revision.Name {
+		t.Errorf("Expected revisions of different content to have different names")
+	}
+
+	if err := RestoreRevision(changed, revision); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(changed.Object, fedObject.Object) {
+		t.Errorf("Expected the restored resource to be %v, got %v", fedObject.Object, changed.Object)
+	}
+}
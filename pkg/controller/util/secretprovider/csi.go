@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// DefaultCSIDirectory is the directory the Secrets Store CSI driver
+// mounts the secrets of the control plane in by default.
+const DefaultCSIDirectory = "/mnt/secrets-store"
+
+type csiProvider struct {
+	dir string
+}
+
+// NewCSIProvider returns a provider that reads tokens from the files
+// mounted in the given directory by the Secrets Store CSI driver. The
+// path of a reference names the file holding the token or, if it is a
+// directory, the directory holding the token in a file named by the
+// key.
+func NewCSIProvider(dir string) Provider {
+	return &csiProvider{dir: dir}
+}
+
+func (p *csiProvider) Token(ref *fedv1b1.ExternalSecretReference) ([]byte, error) {
+	path := filepath.Join(p.dir, ref.Path)
+	if !strings.HasPrefix(path, filepath.Clean(p.dir)+string(filepath.Separator)) {
+		return nil, errors.Errorf("path %q is outside of %q", ref.Path, p.dir)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, keyOf(ref))
+	}
+	token, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSpace(string(token))), nil
+}
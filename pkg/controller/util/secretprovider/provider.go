@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretprovider resolves the credentials of member clusters
+// that are held by external secret providers rather than by secrets in
+// the host cluster.
+package secretprovider
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// DefaultKey is the key of the token within the credentials of a
+// cluster if the reference does not specify one.
+const DefaultKey = "token"
+
+// Provider retrieves the credentials of member clusters from an
+// external secret store.
+type Provider interface {
+	// Token returns the token identified by the given reference.
+	Token(ref *fedv1b1.ExternalSecretReference) ([]byte, error)
+}
+
+var (
+	providersLock sync.RWMutex
+	providers     = make(map[string]Provider)
+)
+
+// Register makes the given provider available under the given name,
+// replacing any provider previously registered under the name.
+func Register(name string, provider Provider) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	providers[name] = provider
+}
+
+// Token returns the token identified by the given reference from the
+// provider it names.
+func Token(ref *fedv1b1.ExternalSecretReference) ([]byte, error) {
+	providersLock.RLock()
+	provider, ok := providers[ref.Provider]
+	providersLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("secret provider %q is not registered", ref.Provider)
+	}
+	token, err := provider.Token(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve %q from secret provider %q", ref.Path, ref.Provider)
+	}
+	if len(token) == 0 {
+		return nil, errors.Errorf("secret provider %q returned an empty token for %q", ref.Provider, ref.Path)
+	}
+	return token, nil
+}
+
+func keyOf(ref *fedv1b1.ExternalSecretReference) string {
+	if ref.Key == "" {
+		return DefaultKey
+	}
+	return ref.Key
+}
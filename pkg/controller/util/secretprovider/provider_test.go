@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestCSIProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets-store")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "cluster1"), []byte("token1\n"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "cluster2"), 0700); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "cluster2", "bearer"), []byte("token2"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	Register("csi", NewCSIProvider(dir))
+
+	testCases := map[string]struct {
+		ref           fedv1b1.ExternalSecretReference
+		expectedToken string
+	}{
+		"File named by the path": {
+			ref:           fedv1b1.ExternalSecretReference{Provider: "csi", Path: "cluster1"},
+			expectedToken: "token1",
+		},
+		"File named by the key in the directory of the path": {
+			ref:           fedv1b1.ExternalSecretReference{Provider: "csi", Path: "cluster2", Key: "bearer"},
+			expectedToken: "token2",
+		},
+		"Missing file": {
+			ref: fedv1b1.ExternalSecretReference{Provider: "csi", Path: "cluster3"},
+		},
+		"Path outside of the directory": {
+			ref: fedv1b1.ExternalSecretReference{Provider: "csi", Path: "../cluster1"},
+		},
+		"Unregistered provider": {
+			ref: fedv1b1.ExternalSecretReference{Provider: "unknown", Path: "cluster1"},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			token, err := Token(&tc.ref)
+			if tc.expectedToken == "" {
+				if err == nil {
+					t.Errorf("Expected an error, got token %q", token)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(token) != tc.expectedToken {
+				t.Errorf("Expected token %q, got %q", tc.expectedToken, token)
+			}
+		})
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var data map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/secret/data/cluster1":
+			data = map[string]interface{}{
+				"data":     map[string]interface{}{"token": "token1"},
+				"metadata": map[string]interface{}{"version": 1},
+			}
+		case "/v1/kv/cluster2":
+			data = map[string]interface{}{"bearer": "token2"}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer server.Close()
+
+	os.Setenv(vaultTokenEnv, "root")
+	defer os.Unsetenv(vaultTokenEnv)
+	provider, err := NewVaultProvider(VaultConfig{Address: server.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	token, err := provider.Token(&fedv1b1.ExternalSecretReference{Path: "secret/data/cluster1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(token) != "token1" {
+		t.Errorf("Expected the token of a versioned secret, got %q", token)
+	}
+
+	token, err = provider.Token(&fedv1b1.ExternalSecretReference{Path: "kv/cluster2", Key: "bearer"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(token) != "token2" {
+		t.Errorf("Expected the token of an unversioned secret, got %q", token)
+	}
+
+	if _, err := provider.Token(&fedv1b1.ExternalSecretReference{Path: "secret/data/cluster3"}); err == nil {
+		t.Errorf("Expected an error for a missing secret")
+	}
+}
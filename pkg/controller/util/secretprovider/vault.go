@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// DefaultVaultAuthPath is the path the Kubernetes auth method of
+	// Vault is mounted at by default.
+	DefaultVaultAuthPath = "kubernetes"
+
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	vaultTokenEnv           = "VAULT_TOKEN"
+	vaultRequestTimeout     = 10 * time.Second
+
+	// The margin before the expiry of a Vault token after which the
+	// control plane logs in again.
+	vaultTokenRenewalMargin = 30 * time.Second
+)
+
+// VaultConfig configures the retrieval of tokens from HashiCorp Vault.
+type VaultConfig struct {
+	// Address of the Vault server, e.g. https://vault.example.com:8200.
+	Address string
+	// Role the control plane logs in as with the Kubernetes auth
+	// method, using the token of its service account. The token in
+	// the VAULT_TOKEN environment variable is used if empty.
+	Role string
+	// AuthPath is the path the Kubernetes auth method is mounted at.
+	AuthPath string
+	// CAFile, if set, is the path of the certificate authority used
+	// to verify the certificate of the Vault server.
+	CAFile string
+}
+
+type vaultProvider struct {
+	sync.Mutex
+
+	config     VaultConfig
+	httpClient *http.Client
+
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewVaultProvider returns a provider that reads tokens from the
+// secrets of a Vault server. The path of a reference is the path of a
+// secret of either version of the key/value secrets engine, e.g.
+// secret/data/clusters/cluster1, and the key names the field of the
+// secret holding the token.
+func NewVaultProvider(config VaultConfig) (Provider, error) {
+	if config.AuthPath == "" {
+		config.AuthPath = DefaultVaultAuthPath
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAFile != "" {
+		caData, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the CA of the Vault server")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, errors.Errorf("no certificates found in %q", config.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return &vaultProvider{
+		config: config,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   vaultRequestTimeout,
+		},
+	}, nil
+}
+
+func (p *vaultProvider) Token(ref *fedv1b1.ExternalSecretReference) ([]byte, error) {
+	vaultToken, err := p.vaultToken()
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	err = p.do(http.MethodGet, strings.TrimPrefix(ref.Path, "/"), vaultToken, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	data := response.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			// Secrets of version 2 of the key/value secrets engine
+			// are nested along with their metadata.
+			data = nested
+		}
+	}
+	key := keyOf(ref)
+	token, ok := data[key].(string)
+	if !ok {
+		return nil, errors.Errorf("secret has no string value for key %q", key)
+	}
+	return []byte(token), nil
+}
+
+// vaultToken returns the token the control plane authenticates to
+// Vault with, logging in with the Kubernetes auth method if a role is
+// configured and the previous token is about to expire.
+func (p *vaultProvider) vaultToken() (string, error) {
+	if p.config.Role == "" {
+		token := os.Getenv(vaultTokenEnv)
+		if token == "" {
+			return "", errors.Errorf("neither a Vault role nor %s is configured", vaultTokenEnv)
+		}
+		return token, nil
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+	jwt, err := ioutil.ReadFile(serviceAccountTokenFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the service account token")
+	}
+	request := map[string]string{
+		"role": p.config.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}
+	var response struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	path := fmt.Sprintf("auth/%s/login", strings.Trim(p.config.AuthPath, "/"))
+	if err := p.do(http.MethodPost, path, "", request, &response); err != nil {
+		return "", errors.Wrap(err, "failed to log in to Vault")
+	}
+	if response.Auth.ClientToken == "" {
+		return "", errors.New("Vault did not return a token")
+	}
+	p.token = response.Auth.ClientToken
+	p.tokenExpiry = time.Now().Add(time.Duration(response.Auth.LeaseDuration)*time.Second - vaultTokenRenewalMargin)
+	return p.token, nil
+}
+
+// do sends a request to the given path of the Vault API and decodes
+// the response into the given result.
+func (p *vaultProvider) do(method, path, vaultToken string, body, result interface{}) error {
+	var bodyReader *bytes.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(content)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(p.config.Address, "/"), path)
+	request, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		return err
+	}
+	if vaultToken != "" {
+		request.Header.Set("X-Vault-Token", vaultToken)
+	}
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("Vault returned %s: %s", response.Status, strings.TrimSpace(string(content)))
+	}
+	return json.Unmarshal(content, result)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateServingAddress checks that endpoints authenticated with
+// bearer tokens are not served at the given address over plain HTTP
+// unless the address is a loopback address.
+func ValidateServingAddress(address string, serveTLS bool) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid address %q", address)
+	}
+	if serveTLS || host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return errors.Errorf("a certificate and key are required to serve at the non-loopback address %q, since bearer tokens would otherwise be sent in cleartext", address)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestValidateServingAddress(t *testing.T) {
+	testCases := map[string]struct {
+		address     string
+		serveTLS    bool
+		expectError bool
+	}{
+		"Loopback address": {
+			address: "127.0.0.1:8082",
+		},
+		"Loopback IPv6 address": {
+			address: "[::1]:8082",
+		},
+		"Localhost": {
+			address: "localhost:8082",
+		},
+		"All interfaces without TLS": {
+			address:     ":8082",
+			expectError: true,
+		},
+		"Non-loopback address without TLS": {
+			address:     "10.0.0.1:8082",
+			expectError: true,
+		},
+		"Non-loopback address with TLS": {
+			address:  ":8082",
+			serveTLS: true,
+		},
+		"Invalid address": {
+			address:     "8082",
+			serveTLS:    true,
+			expectError: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateServingAddress(tc.address, tc.serveTLS)
+			if tc.expectError && err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
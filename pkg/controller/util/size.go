@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+)
+
+// MaxFederatedResourceSize is the size in bytes above which federated
+// resources and the status collected for them are considered too
+// large. etcd rejects requests larger than 1.5MiB by default, and the
+// limit leaves room for the propagation status recorded by the sync
+// controller.
+const MaxFederatedResourceSize = 1024 * 1024
+
+// ObjectSize returns the size in bytes of the json serialization of
+// the given object.
+func ObjectSize(obj interface{}) (int, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// SourceNameAnnotation is set on a resource in a member cluster
+	// whose name differs from the name of the resource in the host
+	// cluster. It records the name in the host cluster so that
+	// events for the resource can be attributed to the federated
+	// resource that manages it.
+	SourceNameAnnotation = "kubefed.io/source-name"
+)
+
+// TargetNameForCluster returns the name of the target resource with
+// the given name in the named member cluster. The namespace of a
+// namespaced target resource, or the name of a namespace, is replaced
+// by the namespace the given placement maps for the cluster. The name
+// of a resource other than a namespace is then derived from the name
+// template the placement specifies for the cluster.
+func TargetNameForCluster(clusterName string, targetName QualifiedName, targetIsNamespace bool, placement *GenericPlacement) QualifiedName {
+	qualifiedName := QualifiedNameForCluster(clusterName, targetName)
+	if placement == nil {
+		return qualifiedName
+	}
+	if namespace, ok := placement.NamespaceMapping()[clusterName]; ok {
+		switch {
+		case targetIsNamespace:
+			qualifiedName.Name = namespace
+		case len(qualifiedName.Namespace) > 0:
+			qualifiedName.Namespace = namespace
+		}
+	}
+	if template, ok := placement.NameTemplates()[clusterName]; ok && !targetIsNamespace {
+		qualifiedName.Name = template.Prefix + qualifiedName.Name + template.Suffix
+	}
+	return qualifiedName
+}
+
+// SetSourceName records the name in the host cluster of the given
+// resource of a member cluster.
+func SetSourceName(obj *unstructured.Unstructured, sourceName QualifiedName) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[SourceNameAnnotation] = sourceName.String()
+	obj.SetAnnotations(annotations)
+}
+
+// IsPropagatedFor checks whether the given resource of the named member
+// cluster was propagated for the target resource with the given name.
+// A resource that is found under the name of the target resource in
+// the member cluster may instead have been propagated for a different
+// target resource whose name was changed to the same name.
+func IsPropagatedFor(obj pkgruntime.Object, clusterName string, targetName QualifiedName) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	if sourceName, ok := accessor.GetAnnotations()[SourceNameAnnotation]; ok {
+		return sourceName == targetName.String()
+	}
+	// A resource that does not record a different name was
+	// propagated for the target resource of the same name.
+	return NewQualifiedName(obj) == QualifiedNameForCluster(clusterName, targetName)
+}
+
+// NewSourceQualifiedName returns the name in the host cluster of the
+// given resource of a member cluster. The name is the name of the
+// resource unless a different name was recorded by SetSourceName.
+func NewSourceQualifiedName(obj pkgruntime.Object) QualifiedName {
+	qualifiedName := NewQualifiedName(obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return qualifiedName
+	}
+	sourceName, ok := accessor.GetAnnotations()[SourceNameAnnotation]
+	if !ok || len(sourceName) == 0 {
+		return qualifiedName
+	}
+	return ParseQualifiedName(sourceName)
+}
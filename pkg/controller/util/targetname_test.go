@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTargetNameForCluster(t *testing.T) {
+	placement := &GenericPlacement{
+		Spec: GenericPlacementSpec{
+			Placement: GenericPlacementFields{
+				NamespaceMapping: map[string]string{
+					"cluster2": "prod-team-a",
+				},
+				NameTemplates: map[string]GenericNameTemplate{
+					"cluster3": {Suffix: "-eu"},
+					"cluster4": {Prefix: "eu-"},
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		clusterName       string
+		targetName        QualifiedName
+		targetIsNamespace bool
+		expectedName      QualifiedName
+	}{
+		"Unmapped cluster": {
+			clusterName:  "cluster1",
+			targetName:   QualifiedName{Namespace: "team-a", Name: "web"},
+			expectedName: QualifiedName{Namespace: "team-a", Name: "web"},
+		},
+		"Mapped cluster": {
+			clusterName:  "cluster2",
+			targetName:   QualifiedName{Namespace: "team-a", Name: "web"},
+			expectedName: QualifiedName{Namespace: "prod-team-a", Name: "web"},
+		},
+		"Mapped namespace": {
+			clusterName:       "cluster2",
+			targetName:        QualifiedName{Name: "team-a"},
+			targetIsNamespace: true,
+			expectedName:      QualifiedName{Name: "prod-team-a"},
+		},
+		"Cluster-scoped resource": {
+			clusterName:  "cluster2",
+			targetName:   QualifiedName{Name: "admin"},
+			expectedName: QualifiedName{Name: "admin"},
+		},
+		"Name suffix": {
+			clusterName:  "cluster3",
+			targetName:   QualifiedName{Namespace: "team-a", Name: "web"},
+			expectedName: QualifiedName{Namespace: "team-a", Name: "web-eu"},
+		},
+		"Name prefix": {
+			clusterName:  "cluster4",
+			targetName:   QualifiedName{Name: "admin"},
+			expectedName: QualifiedName{Name: "eu-admin"},
+		},
+		"Name template of a namespace": {
+			clusterName:       "cluster3",
+			targetName:        QualifiedName{Name: "team-a"},
+			targetIsNamespace: true,
+			expectedName:      QualifiedName{Name: "team-a"},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			name := TargetNameForCluster(tc.clusterName, tc.targetName, tc.targetIsNamespace, placement)
+			if name != tc.expectedName {
+				t.Errorf("Expected name %q, got %q", tc.expectedName, name)
+			}
+		})
+	}
+}
+
+func TestNewSourceQualifiedName(t *testing.T) {
+	testCases := map[string]struct {
+		name         QualifiedName
+		sourceName   *QualifiedName
+		expectedName QualifiedName
+	}{
+		"Resource without source name": {
+			name:         QualifiedName{Namespace: "team-a", Name: "web"},
+			expectedName: QualifiedName{Namespace: "team-a", Name: "web"},
+		},
+		"Resource in a mapped namespace": {
+			name:         QualifiedName{Namespace: "prod-team-a", Name: "web"},
+			sourceName:   &QualifiedName{Namespace: "team-a", Name: "web"},
+			expectedName: QualifiedName{Namespace: "team-a", Name: "web"},
+		},
+		"Mapped namespace": {
+			name:         QualifiedName{Name: "prod-team-a"},
+			sourceName:   &QualifiedName{Name: "team-a"},
+			expectedName: QualifiedName{Name: "team-a"},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetNamespace(tc.name.Namespace)
+			obj.SetName(tc.name.Name)
+			if tc.sourceName != nil {
+				SetSourceName(obj, *tc.sourceName)
+			}
+			name := NewSourceQualifiedName(obj)
+			if name != tc.expectedName {
+				t.Errorf("Expected name %q, got %q", tc.expectedName, name)
+			}
+		})
+	}
+}
+
+func TestIsPropagatedFor(t *testing.T) {
+	targetName := QualifiedName{Namespace: "team-a", Name: "web"}
+
+	testCases := map[string]struct {
+		name           QualifiedName
+		sourceName     *QualifiedName
+		expectedResult bool
+	}{
+		"Resource of the same name": {
+			name:           QualifiedName{Namespace: "team-a", Name: "web"},
+			expectedResult: true,
+		},
+		"Renamed resource": {
+			name:           QualifiedName{Namespace: "team-a", Name: "web-eu"},
+			sourceName:     &QualifiedName{Namespace: "team-a", Name: "web"},
+			expectedResult: true,
+		},
+		"Resource renamed to the target name": {
+			name:           QualifiedName{Namespace: "team-a", Name: "web"},
+			sourceName:     &QualifiedName{Namespace: "team-b", Name: "web"},
+			expectedResult: false,
+		},
+		"Resource of the renamed target name": {
+			name:           QualifiedName{Namespace: "team-a", Name: "web-eu"},
+			expectedResult: false,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetNamespace(tc.name.Namespace)
+			obj.SetName(tc.name.Name)
+			if tc.sourceName != nil {
+				SetSourceName(obj, *tc.sourceName)
+			}
+			result := IsPropagatedFor(obj, "cluster1", targetName)
+			if result != tc.expectedResult {
+				t.Errorf("Expected %v, got %v", tc.expectedResult, result)
+			}
+		})
+	}
+}
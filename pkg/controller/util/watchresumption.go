@@ -0,0 +1,233 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// watchResumptionRetention is the duration for which the watch of a
+// member cluster that became unavailable can be resumed. Resuming an
+// older watch would most likely fail since the API server only keeps a
+// limited window of changes.
+const watchResumptionRetention = 10 * time.Minute
+
+// watchResumption allows the watch of a member cluster to be resumed
+// from the last observed resource version when the cluster becomes
+// available again, rather than listing all resources of the cluster
+// again. Watches request bookmarks so that the observed resource version
+// stays recent while none of the watched resources change. Should the
+// resource version have expired, the informer falls back to listing the
+// resources of the cluster.
+type watchResumption struct {
+	sync.Mutex
+
+	retention time.Duration
+	// Returns the current time. Overridden in tests.
+	now func() time.Time
+
+	// The watch of each cluster, keyed by cluster name.
+	clusters map[string]*clusterWatch
+}
+
+// clusterWatch mirrors the resources observed by the watch of a member
+// cluster. The mirror is updated as the list and watch events are
+// received, so that it is consistent with the observed resource version
+// regardless of the events the informer has processed.
+type clusterWatch struct {
+	// The API endpoint of the cluster. A watch is only resumed for the
+	// same endpoint.
+	apiEndpoint     string
+	resourceVersion string
+	objects         map[string]*unstructured.Unstructured
+	// The time at which the cluster became unavailable, or zero while
+	// the cluster is available.
+	suspendedAt time.Time
+}
+
+func newWatchResumption(retention time.Duration) *watchResumption {
+	return &watchResumption{
+		retention: retention,
+		now:       time.Now,
+		clusters:  make(map[string]*clusterWatch),
+	}
+}
+
+// listWatch returns a list watch for the given cluster that resumes
+// the suspended watch of the cluster, if any, and otherwise delegates
+// to the given list watch.
+func (r *watchResumption) listWatch(cluster *fedv1b1.KubeFedCluster, listWatch *cache.ListWatch) *cache.ListWatch {
+	clusterName := cluster.Name
+	current := &clusterWatch{
+		apiEndpoint: cluster.Spec.APIEndpoint,
+		objects:     make(map[string]*unstructured.Unstructured),
+	}
+
+	r.Lock()
+	resumable := r.clusters[clusterName]
+	if resumable != nil && !r.resumable(resumable, current.apiEndpoint) {
+		resumable = nil
+	}
+	r.clusters[clusterName] = current
+	r.Unlock()
+
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
+			if resumable != nil {
+				list := r.resume(current, resumable)
+				// A watch is only resumed once. Should it fail, the
+				// resources are listed.
+				resumable = nil
+				klog.V(2).Infof("Resuming watch of cluster %q from resource version %s", clusterName, list.GetResourceVersion())
+				return list, nil
+			}
+			list, err := listWatch.ListFunc(options)
+			if err != nil {
+				return nil, err
+			}
+			if err := r.observeList(current, list); err != nil {
+				return nil, err
+			}
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.AllowWatchBookmarks = true
+			w, err := listWatch.WatchFunc(options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				r.observeEvent(current, event)
+				return event, true
+			}), nil
+		},
+	}
+}
+
+// suspend records that the named cluster became unavailable. Its watch
+// may be resumed until the retention expires.
+func (r *watchResumption) suspend(clusterName string) {
+	r.Lock()
+	defer r.Unlock()
+	if w, ok := r.clusters[clusterName]; ok && w.suspendedAt.IsZero() {
+		w.suspendedAt = r.now()
+	}
+}
+
+// forget discards the watch of the named cluster.
+func (r *watchResumption) forget(clusterName string) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.clusters, clusterName)
+}
+
+func (r *watchResumption) resumable(w *clusterWatch, apiEndpoint string) bool {
+	return !w.suspendedAt.IsZero() &&
+		r.now().Sub(w.suspendedAt) < r.retention &&
+		len(w.resourceVersion) > 0 &&
+		w.apiEndpoint == apiEndpoint
+}
+
+// resume returns the resources mirrored by the given suspended watch
+// as a list at its resource version, and mirrors them in the given
+// current watch.
+func (r *watchResumption) resume(current, suspended *clusterWatch) *unstructured.UnstructuredList {
+	r.Lock()
+	defer r.Unlock()
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	list.SetResourceVersion(suspended.resourceVersion)
+	for key, obj := range suspended.objects {
+		list.Items = append(list.Items, *obj)
+		current.objects[key] = obj
+	}
+	current.resourceVersion = suspended.resourceVersion
+	return list
+}
+
+func (r *watchResumption) observeList(w *clusterWatch, list pkgruntime.Object) error {
+	listAccessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return errors.Wrap(err, "Failed to access list metadata")
+	}
+	objects := make(map[string]*unstructured.Unstructured)
+	err = meta.EachListItem(list, func(item pkgruntime.Object) error {
+		obj, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			return errors.Errorf("Unexpected list item of type %T", item)
+		}
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return err
+		}
+		objects[key] = obj
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if !w.suspendedAt.IsZero() {
+		return nil
+	}
+	w.objects = objects
+	w.resourceVersion = listAccessor.GetResourceVersion()
+	return nil
+}
+
+func (r *watchResumption) observeEvent(w *clusterWatch, event watch.Event) {
+	if event.Type == watch.Error {
+		return
+	}
+	obj, ok := event.Object.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	// The mirror of a suspended watch is kept as of the time the
+	// cluster became unavailable.
+	if !w.suspendedAt.IsZero() {
+		return
+	}
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		w.objects[key] = obj
+	case watch.Deleted:
+		delete(w.objects, key)
+	}
+	w.resourceVersion = obj.GetResourceVersion()
+}
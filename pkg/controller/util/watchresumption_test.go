@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+type fakeListWatch struct {
+	lists   int
+	watches []*watch.FakeWatcher
+	list    *unstructured.UnstructuredList
+}
+
+func (f *fakeListWatch) listWatch() *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
+			f.lists++
+			return f.list.DeepCopy(), nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			if !options.AllowWatchBookmarks {
+				return nil, errors.New("bookmarks not requested")
+			}
+			w := watch.NewFakeWithChanSize(10, false)
+			f.watches = append(f.watches, w)
+			return w, nil
+		},
+	}
+}
+
+func resumptionTestObject(name, resourceVersion string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func TestWatchResumption(t *testing.T) {
+	now := time.Now()
+	r := newWatchResumption(time.Minute)
+	r.now = func() time.Time { return now }
+
+	cluster := &fedv1b1.KubeFedCluster{}
+	cluster.Name = "cluster1"
+	cluster.Spec.APIEndpoint = "https://cluster1.example.com"
+
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	list.SetResourceVersion("10")
+	list.Items = []unstructured.Unstructured{*resumptionTestObject("a", "5"), *resumptionTestObject("b", "6")}
+	fake := &fakeListWatch{list: list}
+
+	lw := r.listWatch(cluster, fake.listWatch())
+	if _, err := lw.List(metav1.ListOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	w, err := lw.Watch(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fake.watches[0].Add(resumptionTestObject("c", "11"))
+	fake.watches[0].Delete(resumptionTestObject("a", "12"))
+	fake.watches[0].Action(watch.Bookmark, resumptionTestObject("", "15"))
+	for i := 0; i < 3; i++ {
+		<-w.ResultChan()
+	}
+
+	r.suspend(cluster.Name)
+	// Events received after the cluster became unavailable are ignored.
+	fake.watches[0].Add(resumptionTestObject("d", "16"))
+	<-w.ResultChan()
+
+	lw = r.listWatch(cluster, fake.listWatch())
+	obj, err := lw.List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fake.lists != 1 {
+		t.Fatalf("Expected the resumed watch not to list the resources of the cluster")
+	}
+	resumed := obj.(*unstructured.UnstructuredList)
+	if resumed.GetResourceVersion() != "15" {
+		t.Fatalf("Expected the watch to resume from resource version 15, got %q", resumed.GetResourceVersion())
+	}
+	names := map[string]bool{}
+	for _, item := range resumed.Items {
+		names[item.GetName()] = true
+	}
+	if len(names) != 2 || !names["b"] || !names["c"] {
+		t.Fatalf("Expected the resumed resources to be b and c, got %v", names)
+	}
+
+	// A watch is only resumed once.
+	if _, err := lw.List(metav1.ListOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fake.lists != 2 {
+		t.Fatalf("Expected the resources to be listed after the resumed watch failed")
+	}
+}
+
+func TestWatchResumptionNotResumable(t *testing.T) {
+	testCases := map[string]func(r *watchResumption, cluster *fedv1b1.KubeFedCluster, now *time.Time){
+		"retention expired": func(r *watchResumption, cluster *fedv1b1.KubeFedCluster, now *time.Time) {
+			r.suspend(cluster.Name)
+			*now = now.Add(2 * time.Minute)
+		},
+		"API endpoint changed": func(r *watchResumption, cluster *fedv1b1.KubeFedCluster, now *time.Time) {
+			r.suspend(cluster.Name)
+			cluster.Spec.APIEndpoint = "https://cluster1.example.org"
+		},
+		"cluster forgotten": func(r *watchResumption, cluster *fedv1b1.KubeFedCluster, now *time.Time) {
+			r.suspend(cluster.Name)
+			r.forget(cluster.Name)
+		},
+		"cluster not suspended": func(r *watchResumption, cluster *fedv1b1.KubeFedCluster, now *time.Time) {},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			r := newWatchResumption(time.Minute)
+			r.now = func() time.Time { return now }
+
+			cluster := &fedv1b1.KubeFedCluster{}
+			cluster.Name = "cluster1"
+			cluster.Spec.APIEndpoint = "https://cluster1.example.com"
+
+			list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+			list.SetResourceVersion("10")
+			fake := &fakeListWatch{list: list}
+
+			if _, err := r.listWatch(cluster, fake.listWatch()).List(metav1.ListOptions{}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tc(r, cluster, &now)
+			if _, err := r.listWatch(cluster, fake.listWatch()).List(metav1.ListOptions{}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fake.lists != 2 {
+				t.Fatalf("Expected the resources of the cluster to be listed again")
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"sync"
 	"time"
 
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
@@ -47,6 +48,10 @@ type WorkerTiming struct {
 }
 
 type asyncWorker struct {
+	// Name of the controller owning the worker, used to identify the
+	// worker in the debug endpoints.
+	name string
+
 	reconcile ReconcileFunc
 
 	timing WorkerTiming
@@ -62,9 +67,13 @@ type asyncWorker struct {
 
 	// Backoff manager
 	backoff *flowcontrol.Backoff
+
+	// The time at which each item in the queue was added.
+	queuedLock sync.Mutex
+	queued     map[*DelayingDelivererItem]time.Time
 }
 
-func NewReconcileWorker(reconcile ReconcileFunc, timing WorkerTiming) ReconcileWorker {
+func NewReconcileWorker(name string, reconcile ReconcileFunc, timing WorkerTiming) ReconcileWorker {
 	if timing.Interval == 0 {
 		timing.Interval = time.Second * 1
 	}
@@ -78,11 +87,13 @@ func NewReconcileWorker(reconcile ReconcileFunc, timing WorkerTiming) ReconcileW
 		timing.MaxBackoff = time.Minute
 	}
 	return &asyncWorker{
+		name:      name,
 		reconcile: reconcile,
 		timing:    timing,
 		deliverer: NewDelayingDeliverer(),
 		queue:     workqueue.New(),
 		backoff:   flowcontrol.NewBackOff(timing.InitialBackoff, timing.MaxBackoff),
+		queued:    make(map[*DelayingDelivererItem]time.Time),
 	}
 }
 
@@ -114,13 +125,18 @@ func (w *asyncWorker) EnqueueWithDelay(qualifiedName QualifiedName, delay time.D
 func (w *asyncWorker) Run(stopChan <-chan struct{}) {
 	StartBackoffGC(w.backoff, stopChan)
 	w.deliverer.StartWithHandler(func(item *DelayingDelivererItem) {
+		w.queuedLock.Lock()
+		w.queued[item] = time.Now()
+		w.queuedLock.Unlock()
 		w.queue.Add(item)
 	})
 	go wait.Until(w.worker, w.timing.Interval, stopChan)
+	registry.addWorker(w)
 
 	// Ensure all goroutines are cleaned up when the stop channel closes
 	go func() {
 		<-stopChan
+		registry.removeWorker(w)
 		w.queue.ShutDown()
 		w.deliverer.Stop()
 	}()
@@ -152,6 +168,10 @@ func (w *asyncWorker) worker() {
 		}
 
 		item := obj.(*DelayingDelivererItem)
+		w.queuedLock.Lock()
+		delete(w.queued, item)
+		w.queuedLock.Unlock()
+
 		qualifiedName := item.Value.(*QualifiedName)
 		status := w.reconcile(*qualifiedName)
 		w.queue.Done(item)
@@ -168,3 +188,23 @@ func (w *asyncWorker) worker() {
 		}
 	}
 }
+
+// queueStatus returns the length of the worker's queue and the age of
+// its oldest item.
+func (w *asyncWorker) queueStatus() QueueStatus {
+	w.queuedLock.Lock()
+	defer w.queuedLock.Unlock()
+
+	status := QueueStatus{
+		Name:   w.name,
+		Length: w.queue.Len(),
+	}
+	now := time.Now()
+	for _, queuedTime := range w.queued {
+		age := now.Sub(queuedTime).Seconds()
+		if age > status.OldestItemAgeSeconds {
+			status.OldestItemAgeSeconds = age
+		}
+	}
+	return status
+}
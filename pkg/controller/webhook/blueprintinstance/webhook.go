@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blueprintinstance
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/openshift/generic-admission-server/pkg/apiserver"
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook"
+)
+
+const (
+	ResourceName       = "BlueprintInstance"
+	resourcePluralName = "blueprintinstances"
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// BlueprintInstanceAdmissionHook validates BlueprintInstances, and
+// the parameters of an instance against its blueprint when the
+// blueprint exists.
+type BlueprintInstanceAdmissionHook struct {
+	lock        sync.RWMutex
+	initialized bool
+
+	// namespace is the namespace containing the blueprints.
+	namespace string
+
+	// blueprintStore caches the Blueprints of the control plane.
+	blueprintStore cache.Store
+}
+
+var _ apiserver.ValidatingAdmissionHook = &BlueprintInstanceAdmissionHook{}
+
+func (a *BlueprintInstanceAdmissionHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	klog.Infof("New ValidatingResource for %q", ResourceName)
+	return webhook.NewValidatingResource(resourcePluralName), strings.ToLower(ResourceName)
+}
+
+func (a *BlueprintInstanceAdmissionHook) Validate(admissionSpec *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	status := &admissionv1beta1.AdmissionResponse{}
+
+	klog.V(4).Infof("Validating %q AdmissionRequest = %s", ResourceName, webhook.AdmissionRequestDebugString(admissionSpec))
+
+	// We want to let through:
+	// - Requests that are not for create, update
+	// - Requests for things that are not BlueprintInstances
+	if webhook.Allowed(admissionSpec, resourcePluralName, status) {
+		return status
+	}
+
+	admittingObject := &v1beta1.BlueprintInstance{}
+	err := webhook.Unmarshal(&admissionSpec.Object, admittingObject, status)
+	if err != nil {
+		return status
+	}
+
+	if !webhook.Initialized(&a.initialized, &a.lock, status) {
+		return status
+	}
+
+	klog.V(4).Infof("Validating %q = %+v", ResourceName, *admittingObject)
+
+	webhook.Validate(status, func() field.ErrorList {
+		allErrs := validation.ValidateBlueprintInstance(admittingObject)
+		if len(allErrs) > 0 {
+			return allErrs
+		}
+		blueprint := a.blueprint(admittingObject.Spec.BlueprintRef.Name)
+		if blueprint == nil {
+			// The instance becomes ready once its blueprint is
+			// created.
+			return allErrs
+		}
+		return validation.ValidateBlueprintInstanceParameters(admittingObject, blueprint)
+	})
+
+	return status
+}
+
+// blueprint returns the cached Blueprint of the given name, or nil if
+// it does not exist.
+func (a *BlueprintInstanceAdmissionHook) blueprint(name string) *v1beta1.Blueprint {
+	obj, exists, err := a.blueprintStore.GetByKey(util.QualifiedName{Namespace: a.namespace, Name: name}.String())
+	if err != nil || !exists {
+		return nil
+	}
+	return obj.(*v1beta1.Blueprint)
+}
+
+func (a *BlueprintInstanceAdmissionHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// The webhook is deployed in the namespace of the control plane.
+	a.namespace = util.DefaultKubeFedSystemNamespace
+	if data, err := ioutil.ReadFile(serviceAccountNamespaceFile); err == nil {
+		a.namespace = strings.TrimSpace(string(data))
+	}
+	store, controller, err := util.NewGenericInformer(kubeClientConfig, a.namespace, &v1beta1.Blueprint{}, util.NoResyncPeriod, func(pkgruntime.Object) {})
+	if err != nil {
+		return err
+	}
+	go controller.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, controller.HasSynced) {
+		return errors.New("Timed out waiting for the Blueprint cache to sync")
+	}
+	a.blueprintStore = store
+
+	a.initialized = true
+	klog.Infof("Initialized admission webhook for %q", resourcePluralName)
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	validatingConfigurationName = "validations.core.kubefed.io"
+	mutatingConfigurationName   = "mutation.core.kubefed.io"
+)
+
+// ValidatingWebhookConfigurationName returns the name of the
+// ValidatingWebhookConfiguration installed for a control plane of the
+// given scope in the given namespace.
+func ValidatingWebhookConfigurationName(scope apiextv1b1.ResourceScope, namespace string) string {
+	return configurationName(validatingConfigurationName, scope, namespace)
+}
+
+// MutatingWebhookConfigurationName returns the name of the
+// MutatingWebhookConfiguration installed for a control plane of the
+// given scope in the given namespace.
+func MutatingWebhookConfigurationName(scope apiextv1b1.ResourceScope, namespace string) string {
+	return configurationName(mutatingConfigurationName, scope, namespace)
+}
+
+// Webhook configurations are cluster-scoped, so those of namespaced
+// control planes are made unique by the namespace.
+func configurationName(name string, scope apiextv1b1.ResourceScope, namespace string) string {
+	if scope == apiextv1b1.NamespaceScoped {
+		return name + "-" + namespace
+	}
+	return name
+}
+
+// ApplyConfig updates the failure policy and namespace selector of the
+// admission webhooks of a control plane to match the given
+// configuration. Webhook configurations that are not installed are
+// ignored.
+func ApplyConfig(client kubeclientset.Interface, scope apiextv1b1.ResourceScope, namespace string, config *v1beta1.WebhookConfig) error {
+	if config == nil {
+		return nil
+	}
+	var failurePolicy *admissionregistrationv1beta1.FailurePolicyType
+	if config.FailurePolicy != nil {
+		policy := admissionregistrationv1beta1.FailurePolicyType(*config.FailurePolicy)
+		failurePolicy = &policy
+	}
+	namespaceSelector := config.NamespaceSelector
+
+	validatingClient := client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	name := ValidatingWebhookConfigurationName(scope, namespace)
+	validating, err := validatingClient.Get(name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		klog.V(2).Infof("ValidatingWebhookConfiguration %q not found; skipping webhook configuration", name)
+	case err != nil:
+		return errors.Wrapf(err, "failed to get ValidatingWebhookConfiguration %q", name)
+	default:
+		changed := false
+		for i := range validating.Webhooks {
+			webhook := &validating.Webhooks[i]
+			if applyToWebhook(&webhook.FailurePolicy, &webhook.NamespaceSelector, failurePolicy, namespaceSelector) {
+				changed = true
+			}
+		}
+		if changed {
+			if _, err := validatingClient.Update(validating); err != nil {
+				return errors.Wrapf(err, "failed to update ValidatingWebhookConfiguration %q", name)
+			}
+			klog.Infof("Updated ValidatingWebhookConfiguration %q from KubeFedConfig", name)
+		}
+	}
+
+	mutatingClient := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	name = MutatingWebhookConfigurationName(scope, namespace)
+	mutating, err := mutatingClient.Get(name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		klog.V(2).Infof("MutatingWebhookConfiguration %q not found; skipping webhook configuration", name)
+	case err != nil:
+		return errors.Wrapf(err, "failed to get MutatingWebhookConfiguration %q", name)
+	default:
+		changed := false
+		for i := range mutating.Webhooks {
+			webhook := &mutating.Webhooks[i]
+			if applyToWebhook(&webhook.FailurePolicy, &webhook.NamespaceSelector, failurePolicy, namespaceSelector) {
+				changed = true
+			}
+		}
+		if changed {
+			if _, err := mutatingClient.Update(mutating); err != nil {
+				return errors.Wrapf(err, "failed to update MutatingWebhookConfiguration %q", name)
+			}
+			klog.Infof("Updated MutatingWebhookConfiguration %q from KubeFedConfig", name)
+		}
+	}
+
+	return nil
+}
+
+// applyToWebhook sets the failure policy and namespace selector of a
+// webhook to the given values, leaving those that are nil unchanged.
+// Returns whether the webhook was changed.
+func applyToWebhook(webhookPolicy **admissionregistrationv1beta1.FailurePolicyType, webhookSelector **metav1.LabelSelector,
+	failurePolicy *admissionregistrationv1beta1.FailurePolicyType, namespaceSelector *metav1.LabelSelector) bool {
+	changed := false
+	if failurePolicy != nil && (*webhookPolicy == nil || **webhookPolicy != *failurePolicy) {
+		policy := *failurePolicy
+		*webhookPolicy = &policy
+		changed = true
+	}
+	if namespaceSelector != nil && !reflect.DeepEqual(*webhookSelector, namespaceSelector) {
+		*webhookSelector = namespaceSelector.DeepCopy()
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestApplyConfig(t *testing.T) {
+	fail := admissionregistrationv1beta1.Fail
+	installedSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"name": "kube-federation-system"},
+	}
+	client := fake.NewSimpleClientset(
+		&admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "validations.core.kubefed.io-kube-federation-system"},
+			Webhooks: []admissionregistrationv1beta1.ValidatingWebhook{
+				{Name: "kubefedclusters.core.kubefed.io", FailurePolicy: &fail, NamespaceSelector: installedSelector},
+				{Name: "kubefedconfigs.core.kubefed.io", FailurePolicy: &fail, NamespaceSelector: installedSelector},
+			},
+		},
+	)
+
+	ignore := v1beta1.WebhookFailurePolicyIgnore
+	config := &v1beta1.WebhookConfig{FailurePolicy: &ignore}
+	// The mutating webhook configuration is not installed and should
+	// be skipped.
+	err := ApplyConfig(client, apiextv1b1.NamespaceScoped, "kube-federation-system", config)
+	assert.NoError(t, err)
+
+	validating, err := client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(
+		"validations.core.kubefed.io-kube-federation-system", metav1.GetOptions{})
+	assert.NoError(t, err)
+	for _, webhook := range validating.Webhooks {
+		assert.Equal(t, admissionregistrationv1beta1.Ignore, *webhook.FailurePolicy)
+		// An unset namespace selector leaves the installed one unchanged.
+		assert.Equal(t, installedSelector, webhook.NamespaceSelector)
+	}
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedresource
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/openshift/generic-admission-server/pkg/apiserver"
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook"
+	"sigs.k8s.io/kubefed/pkg/features"
+)
+
+const (
+	ResourceName       = "FederatedResource"
+	resourcePluralName = "federatedresources"
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// FederatedResourceAdmissionHook rejects federated resources whose
+// template and overrides are too large for the propagation status of
+// the resource to be recorded, placements that newly list cordoned
+// clusters, and placements that list clusters not allowed by a
+// ClusterAllowlist when the ClusterAllowlists feature gate is enabled
+// for the control plane. The webhook configuration selects the
+// resources of the federated types.
+type FederatedResourceAdmissionHook struct {
+	lock        sync.RWMutex
+	initialized bool
+
+	// clusterStore caches the KubeFedClusters of the control plane.
+	clusterStore cache.Store
+
+	// configStore caches the KubeFedConfig of the control plane,
+	// which determines whether ClusterAllowlists are enforced.
+	configStore cache.Store
+
+	// clusterAllowlistStore caches the ClusterAllowlists of the
+	// control plane.
+	clusterAllowlistStore cache.Store
+
+	namespace string
+}
+
+var _ apiserver.ValidatingAdmissionHook = &FederatedResourceAdmissionHook{}
+
+func (a *FederatedResourceAdmissionHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	klog.Infof("New ValidatingResource for %q", ResourceName)
+	return webhook.NewValidatingResource(resourcePluralName), strings.ToLower(ResourceName)
+}
+
+func (a *FederatedResourceAdmissionHook) Validate(admissionSpec *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	status := &admissionv1beta1.AdmissionResponse{}
+
+	klog.V(4).Infof("Validating %q AdmissionRequest = %s", ResourceName, webhook.AdmissionRequestDebugString(admissionSpec))
+
+	// We want to let through:
+	// - Requests that are not for create, update
+	// - Requests for subresources, since the size of the status
+	//   written by the sync controller is bounded by the number of
+	//   member clusters
+	createOrUpdate := admissionSpec.Operation == admissionv1beta1.Create || admissionSpec.Operation == admissionv1beta1.Update
+	if !createOrUpdate || len(admissionSpec.SubResource) > 0 {
+		status.Allowed = true
+		return status
+	}
+
+	if !webhook.Initialized(&a.initialized, &a.lock, status) {
+		return status
+	}
+
+	webhook.Validate(status, func() field.ErrorList {
+		allErrs := ValidateSize(len(admissionSpec.Object.Raw))
+		allErrs = append(allErrs, a.validateCordonedClusters(admissionSpec)...)
+		return append(allErrs, a.validateAllowedClusters(admissionSpec)...)
+	})
+
+	return status
+}
+
+// ValidateSize validates the size in bytes of a serialized federated
+// resource.
+func ValidateSize(size int) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if size > util.MaxFederatedResourceSize {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+			fmt.Sprintf("the resource is %d bytes, which exceeds the limit of %d bytes for federated resources", size, util.MaxFederatedResourceSize)))
+	}
+	return allErrs
+}
+
+// validateCordonedClusters validates that the placement of the
+// federated resource in the given request does not newly list a
+// cordoned cluster.
+func (a *FederatedResourceAdmissionHook) validateCordonedClusters(admissionSpec *admissionv1beta1.AdmissionRequest) field.ErrorList {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(admissionSpec.Object.Raw); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath(""), "", err.Error())}
+	}
+	var oldObj *unstructured.Unstructured
+	if admissionSpec.Operation == admissionv1beta1.Update {
+		oldObj = &unstructured.Unstructured{}
+		if err := oldObj.UnmarshalJSON(admissionSpec.OldObject.Raw); err != nil {
+			return field.ErrorList{field.Invalid(field.NewPath(""), "", err.Error())}
+		}
+	}
+
+	cordoned := sets.String{}
+	for _, cachedObj := range a.clusterStore.List() {
+		cluster := cachedObj.(*fedv1b1.KubeFedCluster)
+		if cluster.Spec.Unschedulable {
+			cordoned.Insert(cluster.Name)
+		}
+	}
+	return ValidateCordonedClusters(obj, oldObj, cordoned)
+}
+
+// ValidateCordonedClusters validates that the placement of a federated
+// resource does not list cordoned clusters that were not already
+// listed by the previous version of the resource, if any.
+func ValidateCordonedClusters(obj, oldObj *unstructured.Unstructured, cordoned sets.String) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cordoned.Len() == 0 {
+		return allErrs
+	}
+	placement, err := util.UnmarshalGenericPlacement(obj)
+	if err != nil {
+		return append(allErrs, field.Invalid(field.NewPath("spec", "placement"), "", err.Error()))
+	}
+	listed := sets.String{}
+	if oldObj != nil {
+		oldPlacement, err := util.UnmarshalGenericPlacement(oldObj)
+		if err == nil {
+			listed.Insert(oldPlacement.ClusterNames()...)
+		}
+	}
+	clustersPath := field.NewPath("spec", "placement", "clusters")
+	for i, clusterName := range placement.ClusterNames() {
+		if cordoned.Has(clusterName) && !listed.Has(clusterName) {
+			allErrs = append(allErrs, field.Forbidden(clustersPath.Index(i).Child("name"),
+				fmt.Sprintf("cluster %q is cordoned and does not accept new placements", clusterName)))
+		}
+	}
+	return allErrs
+}
+
+// validateAllowedClusters validates that the placement of the
+// federated resource in the given request only lists clusters allowed
+// by the ClusterAllowlists for its namespace, if allowlists are
+// enforced.
+func (a *FederatedResourceAdmissionHook) validateAllowedClusters(admissionSpec *admissionv1beta1.AdmissionRequest) field.ErrorList {
+	if !a.allowlistsEnforced() {
+		return nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(admissionSpec.Object.Raw); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath(""), "", err.Error())}
+	}
+	allowlists := []*fedv1b1.ClusterAllowlist{}
+	for _, cachedObj := range a.clusterAllowlistStore.List() {
+		allowlists = append(allowlists, cachedObj.(*fedv1b1.ClusterAllowlist))
+	}
+	return ValidateAllowedClusters(obj, util.AllowedClusters(allowlists, obj.GetNamespace()))
+}
+
+// allowlistsEnforced returns whether the ClusterAllowlists feature gate
+// is enabled by the KubeFedConfig of the control plane.
+func (a *FederatedResourceAdmissionHook) allowlistsEnforced() bool {
+	key := util.QualifiedName{Namespace: a.namespace, Name: util.KubeFedConfigName}.String()
+	cachedObj, exists, err := a.configStore.GetByKey(key)
+	if err != nil || !exists {
+		return false
+	}
+	for _, featureGate := range cachedObj.(*fedv1b1.KubeFedConfig).Spec.FeatureGates {
+		if featureGate.Name == string(features.ClusterAllowlists) {
+			return featureGate.Configuration == fedv1b1.ConfigurationEnabled
+		}
+	}
+	return false
+}
+
+// ValidateAllowedClusters validates that the placement of a federated
+// resource only lists allowed clusters. Clusters selected by the
+// cluster selector or cluster groups of the placement are not
+// validated, since the sync controller does not propagate to the
+// clusters that are not allowed.
+func ValidateAllowedClusters(obj *unstructured.Unstructured, allowed sets.String) field.ErrorList {
+	allErrs := field.ErrorList{}
+	placement, err := util.UnmarshalGenericPlacement(obj)
+	if err != nil {
+		return append(allErrs, field.Invalid(field.NewPath("spec", "placement"), "", err.Error()))
+	}
+	clustersPath := field.NewPath("spec", "placement", "clusters")
+	for i, clusterName := range placement.ClusterNames() {
+		if !allowed.Has(clusterName) {
+			allErrs = append(allErrs, field.Forbidden(clustersPath.Index(i).Child("name"),
+				fmt.Sprintf("cluster %q is not listed by a ClusterAllowlist for the namespace of the resource", clusterName)))
+		}
+	}
+	return allErrs
+}
+
+func (a *FederatedResourceAdmissionHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// The webhook is deployed in the namespace of the control plane.
+	namespace := util.DefaultKubeFedSystemNamespace
+	if data, err := ioutil.ReadFile(serviceAccountNamespaceFile); err == nil {
+		namespace = strings.TrimSpace(string(data))
+	}
+	store, controller, err := util.NewGenericInformer(kubeClientConfig, namespace, &fedv1b1.KubeFedCluster{}, util.NoResyncPeriod, func(pkgruntime.Object) {})
+	if err != nil {
+		return err
+	}
+	go controller.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, controller.HasSynced) {
+		return errors.New("Timed out waiting for the KubeFedCluster cache to sync")
+	}
+	a.clusterStore = store
+
+	configStore, configController, err := util.NewGenericInformer(kubeClientConfig, namespace, &fedv1b1.KubeFedConfig{}, util.NoResyncPeriod, func(pkgruntime.Object) {})
+	if err != nil {
+		return err
+	}
+	allowlistStore, allowlistController, err := util.NewGenericInformer(kubeClientConfig, namespace, &fedv1b1.ClusterAllowlist{}, util.NoResyncPeriod, func(pkgruntime.Object) {})
+	if err != nil {
+		return err
+	}
+	go configController.Run(stopCh)
+	go allowlistController.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, configController.HasSynced, allowlistController.HasSynced) {
+		return errors.New("Timed out waiting for the KubeFedConfig and ClusterAllowlist caches to sync")
+	}
+	a.configStore = configStore
+	a.clusterAllowlistStore = allowlistStore
+	a.namespace = namespace
+
+	a.initialized = true
+	klog.Infof("Initialized admission webhook for %q", resourcePluralName)
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedresource
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestValidateCordonedClusters(t *testing.T) {
+	newResource := func(clusterNames ...string) *unstructured.Unstructured {
+		clusters := []interface{}{}
+		for _, clusterName := range clusterNames {
+			clusters = append(clusters, map[string]interface{}{"name": clusterName})
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"placement": map[string]interface{}{
+					"clusters": clusters,
+				},
+			},
+		}}
+	}
+	cordoned := sets.NewString("cordoned")
+
+	testCases := map[string]struct {
+		obj         *unstructured.Unstructured
+		oldObj      *unstructured.Unstructured
+		expectedErr bool
+	}{
+		"Create listing schedulable clusters": {
+			obj: newResource("cluster1", "cluster2"),
+		},
+		"Create listing a cordoned cluster": {
+			obj:         newResource("cluster1", "cordoned"),
+			expectedErr: true,
+		},
+		"Update adding a cordoned cluster": {
+			obj:         newResource("cluster1", "cordoned"),
+			oldObj:      newResource("cluster1"),
+			expectedErr: true,
+		},
+		"Update retaining a cordoned cluster": {
+			obj:    newResource("cluster2", "cordoned"),
+			oldObj: newResource("cluster1", "cordoned"),
+		},
+		"Update removing a cordoned cluster": {
+			obj:    newResource("cluster1"),
+			oldObj: newResource("cluster1", "cordoned"),
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			errs := ValidateCordonedClusters(tc.obj, tc.oldObj, cordoned)
+			if tc.expectedErr && len(errs) == 0 {
+				t.Fatalf("Expected an error")
+			}
+			if !tc.expectedErr && len(errs) != 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateAllowedClusters(t *testing.T) {
+	newResource := func(clusterNames ...string) *unstructured.Unstructured {
+		placement := map[string]interface{}{}
+		if clusterNames != nil {
+			clusters := []interface{}{}
+			for _, clusterName := range clusterNames {
+				clusters = append(clusters, map[string]interface{}{"name": clusterName})
+			}
+			placement["clusters"] = clusters
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"placement": placement,
+			},
+		}}
+	}
+	allowed := sets.NewString("prod-east", "prod-west")
+
+	testCases := map[string]struct {
+		obj         *unstructured.Unstructured
+		expectedErr bool
+	}{
+		"Placement listing allowed clusters": {
+			obj: newResource("prod-east", "prod-west"),
+		},
+		"Placement listing a cluster that is not allowed": {
+			obj:         newResource("prod-east", "prod-eats"),
+			expectedErr: true,
+		},
+		"Placement without clusters": {
+			obj: newResource(),
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			errs := ValidateAllowedClusters(tc.obj, allowed)
+			if tc.expectedErr && len(errs) == 0 {
+				t.Fatalf("Expected an error")
+			}
+			if !tc.expectedErr && len(errs) != 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboard serves a read-only summary of the state of the
+// fleet as JSON for dashboards. The summary is collected periodically
+// and served from memory so that the number of requests to the API
+// server does not depend on the number of dashboard requests.
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const userAgent = "kubefed-dashboard"
+
+// Server collects and serves the summary of the fleet.
+type Server struct {
+	config     *rest.Config
+	client     genericclient.Client
+	kubeClient kubeclientset.Interface
+
+	kubeFedNamespace string
+	targetNamespace  string
+	interval         time.Duration
+
+	lock    sync.RWMutex
+	summary *Summary
+}
+
+// NewServer returns a server that collects the summary of the fleet of
+// the given controller config at the given interval.
+func NewServer(controllerConfig *util.ControllerConfig, interval time.Duration) (*Server, error) {
+	config := rest.CopyConfig(controllerConfig.KubeConfig)
+	rest.AddUserAgent(config, userAgent)
+	client, err := genericclient.New(config)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubeclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		config:           config,
+		client:           client,
+		kubeClient:       kubeClient,
+		kubeFedNamespace: controllerConfig.KubeFedNamespace,
+		targetNamespace:  controllerConfig.TargetNamespace,
+		interval:         interval,
+	}, nil
+}
+
+// Run collects the summary until the stop channel is closed and serves
+// it at the given address.
+func (s *Server) Run(address string, stopChan <-chan struct{}) {
+	go wait.Until(func() {
+		if err := s.collect(); err != nil {
+			klog.Errorf("Error collecting the dashboard summary: %v", err)
+		}
+	}, s.interval, stopChan)
+
+	server := &http.Server{Addr: address, Handler: s.Handler()}
+	go func() {
+		<-stopChan
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Errorf("Error shutting down the dashboard server: %v", err)
+		}
+	}()
+	klog.Infof("Serving the dashboard summary at %s", address)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		klog.Fatalf("Error serving the dashboard summary: %v", err)
+	}
+}
+
+// Handler returns the handler serving the summary and its parts.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/summary", s.serve(func(summary *Summary) interface{} {
+		return summary
+	}))
+	mux.HandleFunc("/api/v1/clusters", s.serve(func(summary *Summary) interface{} {
+		return summary.Clusters
+	}))
+	mux.HandleFunc("/api/v1/namespaces", s.serve(func(summary *Summary) interface{} {
+		return summary.Namespaces
+	}))
+	mux.HandleFunc("/api/v1/reasons", s.serve(func(summary *Summary) interface{} {
+		return summary.TopErrorReasons
+	}))
+	mux.HandleFunc("/api/v1/events", s.serve(func(summary *Summary) interface{} {
+		return summary.RecentEvents
+	}))
+	return mux
+}
+
+func (s *Server) serve(part func(*Summary) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		s.lock.RLock()
+		summary := s.summary
+		s.lock.RUnlock()
+		if summary == nil {
+			http.Error(w, "the summary has not yet been collected", http.StatusServiceUnavailable)
+			return
+		}
+		data, err := json.MarshalIndent(part(summary), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
+
+// collect lists the clusters, the federated resources of all enabled
+// types and the events of the target namespace and replaces the
+// summary with their aggregate.
+func (s *Server) collect() error {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := s.client.List(context.Background(), clusterList, s.kubeFedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list clusters")
+	}
+
+	typeConfigList := &fedv1b1.FederatedTypeConfigList{}
+	err = s.client.List(context.Background(), typeConfigList, s.kubeFedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list federated type configs")
+	}
+	federatedKinds := sets.NewString()
+	resources := []status.GenericFederatedResource{}
+	for i := range typeConfigList.Items {
+		typeConfig := &typeConfigList.Items[i]
+		federatedType := typeConfig.GetFederatedType()
+		federatedKinds.Insert(federatedType.Kind)
+		typeResources, err := s.listResources(&federatedType)
+		if err != nil {
+			// The resources of a type whose CRD has yet to be
+			// created are omitted.
+			klog.V(2).Infof("Failed to list %q for the dashboard summary: %v", federatedType.Kind, err)
+			continue
+		}
+		resources = append(resources, typeResources...)
+	}
+
+	eventList, err := s.kubeClient.CoreV1().Events(s.targetNamespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list events")
+	}
+	events := eventList.Items
+	if s.targetNamespace != metav1.NamespaceAll && s.targetNamespace != s.kubeFedNamespace {
+		// Events of clusters are recorded in the KubeFed namespace.
+		clusterEventList, err := s.kubeClient.CoreV1().Events(s.kubeFedNamespace).List(metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to list cluster events")
+		}
+		events = append(events, clusterEventList.Items...)
+	}
+
+	summary := summarize(clusterList.Items, resources, events, federatedKinds, metav1.Now())
+	s.lock.Lock()
+	s.summary = summary
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *Server) listResources(apiResource *metav1.APIResource) ([]status.GenericFederatedResource, error) {
+	client, err := util.NewResourceClient(s.config, apiResource)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.Resources(s.targetNamespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]status.GenericFederatedResource, 0, len(list.Items))
+	for i := range list.Items {
+		resource := status.GenericFederatedResource{}
+		if err := util.UnstructuredToInterface(&list.Items[i], &resource); err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
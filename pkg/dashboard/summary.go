@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	// The number of error reasons and events included in a summary.
+	maxReasons = 10
+	maxEvents  = 50
+
+	ClusterHealthReady       = "Ready"
+	ClusterHealthNotReady    = "NotReady"
+	ClusterHealthOffline     = "Offline"
+	ClusterHealthQuarantined = "Quarantined"
+)
+
+// Summary is the state of the fleet as of the last collection.
+type Summary struct {
+	CollectedAt     metav1.Time        `json:"collectedAt"`
+	Clusters        []ClusterSummary   `json:"clusters"`
+	Namespaces      []NamespaceSummary `json:"namespaces"`
+	TopErrorReasons []ReasonCount      `json:"topErrorReasons"`
+	RecentEvents    []EventSummary     `json:"recentEvents"`
+}
+
+// ClusterSummary describes the health of a member cluster.
+type ClusterSummary struct {
+	Name   string            `json:"name"`
+	Health string            `json:"health"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Region string            `json:"region,omitempty"`
+	// The message of the Ready condition of a cluster that is not
+	// ready.
+	Message       string       `json:"message,omitempty"`
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+}
+
+// NamespaceSummary counts the federated resources of a namespace by
+// the state of their propagation. Cluster-scoped resources are
+// counted with an empty namespace.
+type NamespaceSummary struct {
+	Namespace string `json:"namespace"`
+	Resources int    `json:"resources"`
+	// Resources propagated to all of their clusters.
+	Propagated int `json:"propagated"`
+	// Resources that failed to be propagated to one or more
+	// clusters.
+	NotPropagated int `json:"notPropagated"`
+	// Clusters of the resources with a propagation error.
+	ClusterErrors int `json:"clusterErrors"`
+}
+
+// ReasonCount is the number of occurrences of a reason propagation
+// failed.
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// EventSummary is an event recorded for a federated resource or a
+// member cluster.
+type EventSummary struct {
+	Kind          string      `json:"kind"`
+	Namespace     string      `json:"namespace,omitempty"`
+	Name          string      `json:"name"`
+	Type          string      `json:"type"`
+	Reason        string      `json:"reason"`
+	Message       string      `json:"message"`
+	Count         int32       `json:"count,omitempty"`
+	LastTimestamp metav1.Time `json:"lastTimestamp"`
+}
+
+// summarize aggregates the given clusters, federated resources and
+// events into a summary. Only events recorded for the given federated
+// kinds or for clusters are included.
+func summarize(clusters []fedv1b1.KubeFedCluster, resources []status.GenericFederatedResource, events []corev1.Event, federatedKinds sets.String, now metav1.Time) *Summary {
+	summary := &Summary{
+		CollectedAt:     now,
+		Clusters:        []ClusterSummary{},
+		Namespaces:      []NamespaceSummary{},
+		TopErrorReasons: []ReasonCount{},
+		RecentEvents:    []EventSummary{},
+	}
+
+	for i := range clusters {
+		summary.Clusters = append(summary.Clusters, summarizeCluster(&clusters[i]))
+	}
+	sort.Slice(summary.Clusters, func(i, j int) bool {
+		return summary.Clusters[i].Name < summary.Clusters[j].Name
+	})
+
+	namespaces := make(map[string]*NamespaceSummary)
+	reasons := make(map[string]int)
+	for _, resource := range resources {
+		namespace, ok := namespaces[resource.Namespace]
+		if !ok {
+			namespace = &NamespaceSummary{Namespace: resource.Namespace}
+			namespaces[resource.Namespace] = namespace
+		}
+		namespace.Resources++
+		if resource.Status == nil {
+			continue
+		}
+		for _, condition := range resource.Status.Conditions {
+			if condition.Type != status.PropagationConditionType {
+				continue
+			}
+			switch condition.Status {
+			case corev1.ConditionTrue:
+				namespace.Propagated++
+			case corev1.ConditionFalse:
+				namespace.NotPropagated++
+				// The clusters of a resource with the
+				// CheckClusters reason are counted by their
+				// status.
+				if condition.Reason != status.CheckClusters {
+					reasons[string(condition.Reason)]++
+				}
+			}
+		}
+		for _, cluster := range resource.Status.Clusters {
+			if cluster.Status == status.ClusterPropagationOK || cluster.Status == status.WaitingForRemoval || cluster.Status == status.Drifted ||
+				cluster.Status == status.LocallyManaged || cluster.Status == status.Pinned || cluster.Status == status.MaintenanceDeferred {
+				continue
+			}
+			if cluster.Generation < resource.Generation {
+				// The failure of an earlier generation is
+				// cleared once the resource is reconciled.
+				continue
+			}
+			namespace.ClusterErrors++
+			reasons[string(cluster.Status)]++
+		}
+	}
+	for _, namespace := range namespaces {
+		summary.Namespaces = append(summary.Namespaces, *namespace)
+	}
+	sort.Slice(summary.Namespaces, func(i, j int) bool {
+		return summary.Namespaces[i].Namespace < summary.Namespaces[j].Namespace
+	})
+
+	for reason, count := range reasons {
+		summary.TopErrorReasons = append(summary.TopErrorReasons, ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(summary.TopErrorReasons, func(i, j int) bool {
+		a, b := summary.TopErrorReasons[i], summary.TopErrorReasons[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Reason < b.Reason
+	})
+	if len(summary.TopErrorReasons) > maxReasons {
+		summary.TopErrorReasons = summary.TopErrorReasons[:maxReasons]
+	}
+
+	for _, event := range events {
+		kind := event.InvolvedObject.Kind
+		if kind != "KubeFedCluster" && !federatedKinds.Has(kind) {
+			continue
+		}
+		summary.RecentEvents = append(summary.RecentEvents, EventSummary{
+			Kind:          kind,
+			Namespace:     event.InvolvedObject.Namespace,
+			Name:          event.InvolvedObject.Name,
+			Type:          event.Type,
+			Reason:        event.Reason,
+			Message:       event.Message,
+			Count:         event.Count,
+			LastTimestamp: event.LastTimestamp,
+		})
+	}
+	sort.SliceStable(summary.RecentEvents, func(i, j int) bool {
+		return summary.RecentEvents[j].LastTimestamp.Before(&summary.RecentEvents[i].LastTimestamp)
+	})
+	if len(summary.RecentEvents) > maxEvents {
+		summary.RecentEvents = summary.RecentEvents[:maxEvents]
+	}
+
+	return summary
+}
+
+func summarizeCluster(cluster *fedv1b1.KubeFedCluster) ClusterSummary {
+	clusterSummary := ClusterSummary{
+		Name:   cluster.Name,
+		Health: ClusterHealthNotReady,
+		Labels: cluster.Labels,
+	}
+	if cluster.Status.Region != nil {
+		clusterSummary.Region = *cluster.Status.Region
+	}
+	for i := range cluster.Status.Conditions {
+		condition := &cluster.Status.Conditions[i]
+		switch {
+		case condition.Type == fedcommon.ClusterReady:
+			clusterSummary.LastProbeTime = &condition.LastProbeTime
+			if condition.Status != corev1.ConditionTrue && condition.Message != nil {
+				clusterSummary.Message = *condition.Message
+			}
+		case condition.Type == fedcommon.ClusterOffline && condition.Status == corev1.ConditionTrue:
+			clusterSummary.Health = ClusterHealthOffline
+		}
+	}
+	switch {
+	case util.IsClusterQuarantined(cluster):
+		clusterSummary.Health = ClusterHealthQuarantined
+	case util.IsClusterReady(&cluster.Status):
+		clusterSummary.Health = ClusterHealthReady
+	}
+	return clusterSummary
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+)
+
+func TestSummarize(t *testing.T) {
+	now := metav1.NewTime(time.Date(2020, time.March, 2, 12, 0, 0, 0, time.UTC))
+	clusters := []fedv1b1.KubeFedCluster{
+		newCluster("cluster2", corev1.ConditionFalse),
+		newCluster("cluster1", corev1.ConditionTrue),
+	}
+	resources := []status.GenericFederatedResource{
+		newResource("ns1", corev1.ConditionTrue, "", nil),
+		newResource("ns1", corev1.ConditionFalse, status.CheckClusters, []status.GenericClusterStatus{
+			{Name: "cluster1"},
+			{Name: "cluster2", Status: status.ClusterNotReady},
+		}),
+		newResource("ns2", corev1.ConditionFalse, status.ComputePlacementFailed, nil),
+		newResource("ns2", corev1.ConditionFalse, status.CheckClusters, []status.GenericClusterStatus{
+			{Name: "cluster2", Status: status.ClusterNotReady},
+		}),
+	}
+	events := []corev1.Event{
+		newEvent("FederatedDeployment", "CreateInCluster", now.Add(-time.Minute)),
+		newEvent("Deployment", "ScalingReplicaSet", now.Add(-time.Second)),
+		newEvent("KubeFedCluster", "ClusterNotReady", now.Add(-time.Second)),
+	}
+
+	summary := summarize(clusters, resources, events, sets.NewString("FederatedDeployment"), now)
+
+	expectedHealth := []string{ClusterHealthReady, ClusterHealthNotReady}
+	for i, cluster := range summary.Clusters {
+		if cluster.Health != expectedHealth[i] {
+			t.Errorf("Expected health %q for cluster %q, got %q", expectedHealth[i], cluster.Name, cluster.Health)
+		}
+	}
+	expectedNamespaces := []NamespaceSummary{
+		{Namespace: "ns1", Resources: 2, Propagated: 1, NotPropagated: 1, ClusterErrors: 1},
+		{Namespace: "ns2", Resources: 2, NotPropagated: 2, ClusterErrors: 1},
+	}
+	if !reflect.DeepEqual(summary.Namespaces, expectedNamespaces) {
+		t.Errorf("Expected namespaces %v, got %v", expectedNamespaces, summary.Namespaces)
+	}
+	expectedReasons := []ReasonCount{
+		{Reason: string(status.ClusterNotReady), Count: 2},
+		{Reason: string(status.ComputePlacementFailed), Count: 1},
+	}
+	if !reflect.DeepEqual(summary.TopErrorReasons, expectedReasons) {
+		t.Errorf("Expected reasons %v, got %v", expectedReasons, summary.TopErrorReasons)
+	}
+	expectedEventReasons := []string{"ClusterNotReady", "CreateInCluster"}
+	eventReasons := []string{}
+	for _, event := range summary.RecentEvents {
+		eventReasons = append(eventReasons, event.Reason)
+	}
+	if !reflect.DeepEqual(eventReasons, expectedEventReasons) {
+		t.Errorf("Expected events %v, got %v", expectedEventReasons, eventReasons)
+	}
+}
+
+func newCluster(name string, ready corev1.ConditionStatus) fedv1b1.KubeFedCluster {
+	return fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: fedv1b1.KubeFedClusterStatus{
+			Conditions: []fedv1b1.ClusterCondition{
+				{Type: fedcommon.ClusterReady, Status: ready},
+			},
+		},
+	}
+}
+
+func TestSummarizeStaleClusterErrors(t *testing.T) {
+	resource := newResource("ns1", corev1.ConditionFalse, status.CheckClusters, []status.GenericClusterStatus{
+		{Name: "cluster1", Status: status.UpdateFailed, Generation: 1},
+		{Name: "cluster2", Status: status.UpdateFailed, Generation: 2},
+	})
+	resource.Generation = 2
+
+	summary := summarize(nil, []status.GenericFederatedResource{resource}, nil, sets.NewString(), metav1.Now())
+
+	// Only the failure of the current generation is counted.
+	expectedNamespaces := []NamespaceSummary{
+		{Namespace: "ns1", Resources: 1, NotPropagated: 1, ClusterErrors: 1},
+	}
+	if !reflect.DeepEqual(summary.Namespaces, expectedNamespaces) {
+		t.Errorf("Expected namespaces %v, got %v", expectedNamespaces, summary.Namespaces)
+	}
+}
+
+func newResource(namespace string, propagated corev1.ConditionStatus, reason status.AggregateReason, clusters []status.GenericClusterStatus) status.GenericFederatedResource {
+	return status.GenericFederatedResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		Status: &status.GenericFederatedStatus{
+			Conditions: []*status.GenericCondition{
+				{Type: status.PropagationConditionType, Status: propagated, Reason: reason},
+			},
+			Clusters: clusters,
+		},
+	}
+}
+
+func newEvent(kind, reason string, timestamp time.Time) corev1.Event {
+	return corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: "test"},
+		Reason:         reason,
+		LastTimestamp:  metav1.NewTime(timestamp),
+	}
+}
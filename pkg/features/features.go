@@ -53,6 +53,214 @@ const (
 	//
 	// DNS based federated ingress feature.
 	FederatedIngress featuregate.Feature = "FederatedIngress"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Multi-Cluster Services API (ServiceImport) compatibility for federated services.
+	// https://github.com/kubernetes/enhancements/tree/master/keps/sig-multicluster/1645-multi-cluster-services-api
+	MultiClusterServices featuregate.Feature = "MultiClusterServices"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Mirrors ready endpoints of federated services between member clusters
+	// as EndpointSlices. Assumes pod IPs are routable between member clusters.
+	CrossClusterEndpoints featuregate.Feature = "CrossClusterEndpoints"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Records why each member cluster was selected or excluded by the placement
+	// of a federated resource in status.placementDecisions.
+	PlacementDecisions featuregate.Feature = "PlacementDecisions"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Joins the clusters of approved ClusterJoinRequests created by
+	// member clusters with a bootstrap token.
+	ClusterJoinRequests featuregate.Feature = "ClusterJoinRequests"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Records federated resources with pending or failed operations in
+	// member clusters in a ConfigMap so that they are reconciled first after
+	// the sync controller restarts.
+	DispatchJournal featuregate.Feature = "DispatchJournal"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Manages the federated resources grouped by FederatedApplications.
+	FederatedApplications featuregate.Feature = "FederatedApplications"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Propagate existing federated resources to newly joined clusters in
+	// phases: namespaces, RBAC, CRDs, configuration and then workloads.
+	ClusterBackfill featuregate.Feature = "ClusterBackfill"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Quarantine member clusters that reject a high fraction of the resources propagated to them.
+	ClusterQuarantine featuregate.Feature = "ClusterQuarantine"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Register the control plane in a KubeFedInstance claiming its target namespace, and label propagated resources with the name of the instance so that several control planes can share a host cluster and member clusters.
+	ControlPlaneInstances featuregate.Feature = "ControlPlaneInstances"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Replicate registry pull secrets from the KubeFed system namespace to every federated namespace as FederatedSecrets, adding credentials for the registry mirrors images are rewritten to.
+	PullSecretReplication featuregate.Feature = "PullSecretReplication"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Collect the status of resources whose status rarely changes less often than the status of resources whose status recently changed.
+	AdaptiveStatusCollection featuregate.Feature = "AdaptiveStatusCollection"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Store the status collected from each member cluster in a companion status object when the status of a federated resource would otherwise exceed the size limit of federated resources.
+	StatusCompanionObjects featuregate.Feature = "StatusCompanionObjects"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Verify in each member cluster that the runtime, storage and priority
+	// classes referenced by a resource exist before propagating it.
+	DependencyValidation featuregate.Feature = "DependencyValidation"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Injects the latency and errors described by FaultInjection
+	// resources into the requests made to member clusters.
+	FaultInjection featuregate.Feature = "FaultInjection"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Periodically propagate a probe ConfigMap to every cluster and export
+	// the time taken to apply it and to report its status as metrics.
+	PropagationProbe featuregate.Feature = "PropagationProbe"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Updates ConfigMaps and Secrets in member clusters with a patch of
+	// their changes rather than the full object when the patch is
+	// substantially smaller.
+	DifferentialPropagation featuregate.Feature = "DifferentialPropagation"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Allows federated resources to reference a shared FederatedTemplate
+	// with spec.templateRef instead of embedding a template.
+	FederatedTemplates featuregate.Feature = "FederatedTemplates"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Cache the API discovery of member clusters and refresh it only
+	// when the APIs served by a cluster change.
+	DiscoveryCache featuregate.Feature = "DiscoveryCache"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Share the transport, and thus the connections, used by the clients
+	// of a member cluster across controllers.
+	SharedClusterTransport featuregate.Feature = "SharedClusterTransport"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Propagate the resources of a NamespaceProfile to the federated namespaces with the profile.
+	NamespaceProfiles featuregate.Feature = "NamespaceProfiles"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Defer updates of propagated resources in member clusters outside of
+	// the MaintenanceWindows of the clusters.
+	MaintenanceWindows featuregate.Feature = "MaintenanceWindows"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Ignore fields defaulted by a member cluster, as described by the
+	// OpenAPI schema of the cluster, when determining whether a resource
+	// in the cluster needs to be updated.
+	SchemaAwareComparison featuregate.Feature = "SchemaAwareComparison"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Create the federated resources of a Blueprint for each of its BlueprintInstances.
+	Blueprints featuregate.Feature = "Blueprints"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Periodically verify that federated namespaces are the same in every member cluster that resources of the namespace are placed in.
+	NamespaceSameness featuregate.Feature = "NamespaceSameness"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Only propagate resources to the clusters listed by the ClusterAllowlists that apply to their namespace.
+	ClusterAllowlists featuregate.Feature = "ClusterAllowlists"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Resume the watches of the status controller on a member cluster from the last observed resource version when the cluster becomes available again.
+	ResumableStatusWatches featuregate.Feature = "ResumableStatusWatches"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Periodically prune the propagated versions and statuses recorded for clusters that are no longer joined.
+	StaleClusterRecordCleanup featuregate.Feature = "StaleClusterRecordCleanup"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Project federated resources into the namespaces listed or selected by their placement.
+	NamespaceProjection featuregate.Feature = "NamespaceProjection"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Automatically enable propagation of the CRDs selected by an AutoEnablePolicy.
+	AutoEnablePolicies featuregate.Feature = "AutoEnablePolicies"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Record the history of federated resources in ControllerRevisions so
+	// that they can be rolled back with kubefedctl rollback.
+	RevisionHistory featuregate.Feature = "RevisionHistory"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.2
+	//
+	// Interpret the health of resources in member clusters, record it in
+	// the status of federated resources and use it to gate ordered
+	// rollouts and to fail replicas over from clusters in which a
+	// workload is degraded.
+	HealthInterpretation featuregate.Feature = "HealthInterpretation"
 )
 
 func init() {
@@ -69,4 +277,35 @@ var DefaultKubeFedFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec
 	PushReconciler:               {Default: true, PreRelease: featuregate.Beta},
 	CrossClusterServiceDiscovery: {Default: true, PreRelease: featuregate.Alpha},
 	FederatedIngress:             {Default: true, PreRelease: featuregate.Alpha},
+	MultiClusterServices:         {Default: false, PreRelease: featuregate.Alpha},
+	CrossClusterEndpoints:        {Default: false, PreRelease: featuregate.Alpha},
+	PlacementDecisions:           {Default: false, PreRelease: featuregate.Alpha},
+	ClusterJoinRequests:          {Default: false, PreRelease: featuregate.Alpha},
+	DispatchJournal:              {Default: false, PreRelease: featuregate.Alpha},
+	FederatedApplications:        {Default: false, PreRelease: featuregate.Alpha},
+	ClusterBackfill:              {Default: false, PreRelease: featuregate.Alpha},
+	ClusterQuarantine:            {Default: false, PreRelease: featuregate.Alpha},
+	ControlPlaneInstances:        {Default: false, PreRelease: featuregate.Alpha},
+	PullSecretReplication:        {Default: false, PreRelease: featuregate.Alpha},
+	AdaptiveStatusCollection:     {Default: false, PreRelease: featuregate.Alpha},
+	StatusCompanionObjects:       {Default: false, PreRelease: featuregate.Alpha},
+	DependencyValidation:         {Default: false, PreRelease: featuregate.Alpha},
+	FaultInjection:               {Default: false, PreRelease: featuregate.Alpha},
+	PropagationProbe:             {Default: false, PreRelease: featuregate.Alpha},
+	DifferentialPropagation:      {Default: false, PreRelease: featuregate.Alpha},
+	FederatedTemplates:           {Default: false, PreRelease: featuregate.Alpha},
+	DiscoveryCache:               {Default: false, PreRelease: featuregate.Alpha},
+	SharedClusterTransport:       {Default: false, PreRelease: featuregate.Alpha},
+	NamespaceProfiles:            {Default: false, PreRelease: featuregate.Alpha},
+	MaintenanceWindows:           {Default: false, PreRelease: featuregate.Alpha},
+	SchemaAwareComparison:        {Default: false, PreRelease: featuregate.Alpha},
+	Blueprints:                   {Default: false, PreRelease: featuregate.Alpha},
+	NamespaceSameness:            {Default: false, PreRelease: featuregate.Alpha},
+	ClusterAllowlists:            {Default: false, PreRelease: featuregate.Alpha},
+	ResumableStatusWatches:       {Default: false, PreRelease: featuregate.Alpha},
+	StaleClusterRecordCleanup:    {Default: false, PreRelease: featuregate.Alpha},
+	NamespaceProjection:          {Default: false, PreRelease: featuregate.Alpha},
+	AutoEnablePolicies:           {Default: false, PreRelease: featuregate.Alpha},
+	RevisionHistory:              {Default: false, PreRelease: featuregate.Alpha},
+	HealthInterpretation:         {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"testing"
+
+	"k8s.io/component-base/featuregate"
+)
+
+const chartKubeFedConfig = "../../charts/kubefed/charts/controllermanager/templates/kubefedconfig.yaml"
+
+var (
+	gateNameRE          = regexp.MustCompile(`^  - name: (\w+)$`)
+	gateConfigurationRE = regexp.MustCompile(`^    configuration: {{ \.Values\.featureGates\.(\w+) \| default "(\w+)" \| quote }}$`)
+)
+
+// TestChartFeatureGates verifies that the KubeFedConfig of the helm
+// chart configures every feature gate in its featureGates block with
+// the default of the gate. A gate listed elsewhere in the template
+// would be rendered as part of another field, or not at all.
+func TestChartFeatureGates(t *testing.T) {
+	file, err := os.Open(chartKubeFedConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	chartDefaults := make(map[string]string)
+	inFeatureGates := false
+	lastGate := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "  featureGates:" {
+			inFeatureGates = true
+			continue
+		}
+		if match := gateNameRE.FindStringSubmatch(line); match != nil {
+			if !inFeatureGates {
+				t.Errorf("Feature gate %q is configured outside of the featureGates block", match[1])
+			}
+			lastGate = match[1]
+			continue
+		}
+		if match := gateConfigurationRE.FindStringSubmatch(line); match != nil {
+			if match[1] != lastGate {
+				t.Errorf("Expected the configuration of feature gate %q, got the value of %q", lastGate, match[1])
+			}
+			chartDefaults[lastGate] = match[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for feature, spec := range DefaultKubeFedFeatureGates {
+		expected := "Disabled"
+		if spec.Default {
+			expected = "Enabled"
+		}
+		chartDefault, ok := chartDefaults[string(feature)]
+		if !ok {
+			t.Errorf("Feature gate %q is missing from the featureGates block of the chart", feature)
+			continue
+		}
+		if chartDefault != expected {
+			t.Errorf("Expected feature gate %q to default to %q in the chart, got %q", feature, expected, chartDefault)
+		}
+	}
+	for name := range chartDefaults {
+		if _, ok := DefaultKubeFedFeatureGates[featuregate.Feature(name)]; !ok {
+			t.Errorf("Unknown feature gate %q is configured by the chart", name)
+		}
+	}
+}
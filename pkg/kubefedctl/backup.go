@@ -0,0 +1,318 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	backup_long = `
+		Backup exports the KubeFedClusters, FederatedTypeConfigs,
+		federated type CRDs and federated resources of a KubeFed
+		control plane to a file from which the control plane can
+		be rebuilt with kubefedctl restore.
+
+		The secrets holding the credentials of member clusters
+		are only exported if --include-secrets is provided, since
+		the file then needs to be protected like a kubeconfig.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	backup_example = `
+		# Export the control plane of the cluster with context bar
+		kubefedctl backup kubefed-backup.yaml --host-cluster-context=bar`
+)
+
+var (
+	customResourceDefinitionAPIResource = metav1.APIResource{
+		Group:   apiextv1b1.SchemeGroupVersion.Group,
+		Version: apiextv1b1.SchemeGroupVersion.Version,
+		Name:    "customresourcedefinitions",
+		Kind:    "CustomResourceDefinition",
+	}
+	federatedTypeConfigAPIResource = metav1.APIResource{
+		Group:      fedv1b1.SchemeGroupVersion.Group,
+		Version:    fedv1b1.SchemeGroupVersion.Version,
+		Name:       "federatedtypeconfigs",
+		Kind:       "FederatedTypeConfig",
+		Namespaced: true,
+	}
+	kubeFedClusterAPIResource = metav1.APIResource{
+		Group:      fedv1b1.SchemeGroupVersion.Group,
+		Version:    fedv1b1.SchemeGroupVersion.Version,
+		Name:       "kubefedclusters",
+		Kind:       "KubeFedCluster",
+		Namespaced: true,
+	}
+	secretAPIResource = metav1.APIResource{
+		Version:    "v1",
+		Name:       "secrets",
+		Kind:       ctlutil.SecretKind,
+		Namespaced: true,
+	}
+)
+
+type backupControlPlane struct {
+	options.GlobalSubcommandOptions
+	filename       string
+	includeSecrets bool
+}
+
+// NewCmdBackup defines the `backup` command that exports a KubeFed
+// control plane to a file.
+func NewCmdBackup(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &backupControlPlane{}
+
+	cmd := &cobra.Command{
+		Use:     "backup FILENAME --host-cluster-context=HOST_CONTEXT",
+		Short:   "Export a KubeFed control plane to a file",
+		Long:    backup_long,
+		Example: backup_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				klog.Fatalf("Error: FILENAME is required")
+			}
+			opts.filename = args[0]
+
+			err := opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	flags.BoolVar(&opts.includeSecrets, "include-secrets", false,
+		"If true, the secrets holding the credentials of member clusters are included in the backup.")
+
+	return cmd
+}
+
+// Run exports the control plane to the file.
+func (o *backupControlPlane) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+
+	objs, err := exportControlPlane(hostConfig, o.KubeFedNamespace, o.includeSecrets)
+	if err != nil {
+		return err
+	}
+	if !o.includeSecrets {
+		for _, cluster := range filterByGroupKind(objs, kubeFedClusterAPIResource) {
+			secretName, _, _ := unstructured.NestedString(cluster.Object, "spec", "secretRef", "name")
+			fmt.Fprintf(cmdOut, "Secret %q of KubeFedCluster %q is not included in the backup\n", secretName, cluster.GetName())
+		}
+	}
+
+	f, err := os.Create(util.ExpandPath(o.filename))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create %q", o.filename)
+	}
+	defer f.Close()
+	err = federate.WriteUnstructuredObjsToYaml(objs, f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmdOut, "Exported %d resources to %q\n", len(objs), o.filename)
+	return nil
+}
+
+// exportControlPlane returns the resources of the control plane in the
+// given namespace in the order in which they need to be restored.
+func exportControlPlane(hostConfig *rest.Config, kubefedNamespace string, includeSecrets bool) ([]*unstructured.Unstructured, error) {
+	scope, err := options.GetScopeFromKubeFedConfig(hostConfig, kubefedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	targetNamespace := metav1.NamespaceAll
+	if scope == apiextv1b1.NamespaceScoped {
+		targetNamespace = kubefedNamespace
+	}
+
+	clusters, err := listForBackup(hostConfig, kubeFedClusterAPIResource, kubefedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	var secrets []*unstructured.Unstructured
+	if includeSecrets {
+		for _, cluster := range clusters {
+			secretName, _, _ := unstructured.NestedString(cluster.Object, "spec", "secretRef", "name")
+			secret, err := getForBackup(hostConfig, secretAPIResource, kubefedNamespace, secretName)
+			if err != nil {
+				return nil, err
+			}
+			secrets = append(secrets, secret)
+		}
+	}
+
+	typeConfigs, err := listForBackup(hostConfig, federatedTypeConfigAPIResource, kubefedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	var crds, federatedResources []*unstructured.Unstructured
+	for _, obj := range typeConfigs {
+		typeConfig, err := decodeTypeConfig(obj)
+		if err != nil {
+			return nil, err
+		}
+		federatedAPIResource := typeConfig.GetFederatedType()
+
+		crd, err := getForBackup(hostConfig, customResourceDefinitionAPIResource, "", typeconfig.GroupQualifiedName(federatedAPIResource))
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, crd)
+
+		resources, err := listForBackup(hostConfig, federatedAPIResource, targetNamespace)
+		if err != nil {
+			return nil, err
+		}
+		federatedResources = append(federatedResources, resources...)
+	}
+
+	var objs []*unstructured.Unstructured
+	objs = append(objs, crds...)
+	objs = append(objs, typeConfigs...)
+	objs = append(objs, secrets...)
+	objs = append(objs, clusters...)
+	objs = append(objs, federatedResources...)
+	sortForRestore(objs)
+	return objs, nil
+}
+
+func decodeTypeConfig(obj *unstructured.Unstructured) (*fedv1b1.FederatedTypeConfig, error) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typeConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode FederatedTypeConfig %q", obj.GetName())
+	}
+	return typeConfig, nil
+}
+
+// filterByGroupKind returns the given objects of the group and kind of
+// the given api resource.
+func filterByGroupKind(objs []*unstructured.Unstructured, apiResource metav1.APIResource) []*unstructured.Unstructured {
+	groupKind := schema.GroupKind{Group: apiResource.Group, Kind: apiResource.Kind}
+	var filtered []*unstructured.Unstructured
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() == groupKind {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+func listForBackup(config *rest.Config, apiResource metav1.APIResource, namespace string) ([]*unstructured.Unstructured, error) {
+	client, err := ctlutil.NewResourceClient(config, &apiResource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create client for %s", apiResource.Kind)
+	}
+	list, err := client.Resources(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list %s", apiResource.Kind)
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		// Items of a list do not always specify their api version and kind.
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: apiResource.Group, Version: apiResource.Version, Kind: apiResource.Kind})
+		cleanForBackup(obj)
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func getForBackup(config *rest.Config, apiResource metav1.APIResource, namespace, name string) (*unstructured.Unstructured, error) {
+	client, err := ctlutil.NewResourceClient(config, &apiResource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create client for %s", apiResource.Kind)
+	}
+	obj, err := client.Resources(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get %s %q", apiResource.Kind, name)
+	}
+	cleanForBackup(obj)
+	return obj, nil
+}
+
+// cleanForBackup removes the fields of the given object that are set
+// by the system or by controllers and that cannot be restored.
+func cleanForBackup(obj *unstructured.Unstructured) {
+	for _, field := range []string{"uid", "resourceVersion", "selfLink", "generation", "creationTimestamp",
+		"deletionTimestamp", "deletionGracePeriodSeconds", "managedFields", "finalizers", "ownerReferences"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+}
+
+// restorePriority returns the order in which objects of the given kind
+// are restored. The types of federated resources need to be defined
+// before the resources, and clusters need their credentials.
+// Federated namespaces are restored before the federated resources
+// they contain.
+func restorePriority(gvk schema.GroupVersionKind) int {
+	switch gvk.GroupKind() {
+	case schema.GroupKind{Group: customResourceDefinitionAPIResource.Group, Kind: customResourceDefinitionAPIResource.Kind}:
+		return 0
+	case schema.GroupKind{Group: fedv1b1.SchemeGroupVersion.Group, Kind: federatedTypeConfigAPIResource.Kind}:
+		return 1
+	case schema.GroupKind{Kind: ctlutil.SecretKind}:
+		return 2
+	case schema.GroupKind{Group: fedv1b1.SchemeGroupVersion.Group, Kind: kubeFedClusterAPIResource.Kind}:
+		return 3
+	}
+	if gvk.Kind == util.FederatedKindPrefix+ctlutil.NamespaceKind {
+		return 4
+	}
+	return 5
+}
+
+// sortForRestore sorts the given objects in the order in which they
+// are restored.
+func sortForRestore(objs []*unstructured.Unstructured) {
+	sort.SliceStable(objs, func(i, j int) bool {
+		return restorePriority(objs[i].GroupVersionKind()) < restorePriority(objs[j].GroupVersionKind())
+	})
+}
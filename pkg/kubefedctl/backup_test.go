@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+)
+
+func newBackupObject(apiVersion, kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	return obj
+}
+
+func TestSortForRestore(t *testing.T) {
+	objs := []*unstructured.Unstructured{
+		newBackupObject("types.kubefed.io/v1beta1", "FederatedDeployment", "app"),
+		newBackupObject("core.kubefed.io/v1beta1", "KubeFedCluster", "cluster1"),
+		newBackupObject("types.kubefed.io/v1beta1", "FederatedNamespace", "ns"),
+		newBackupObject("v1", "Secret", "cluster1-token"),
+		newBackupObject("core.kubefed.io/v1beta1", "FederatedTypeConfig", "deployments.apps"),
+		newBackupObject("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "federateddeployments.types.kubefed.io"),
+		newBackupObject("types.kubefed.io/v1beta1", "FederatedConfigMap", "config"),
+	}
+	sortForRestore(objs)
+
+	expected := []string{
+		"federateddeployments.types.kubefed.io",
+		"deployments.apps",
+		"cluster1-token",
+		"cluster1",
+		"ns",
+		"app",
+		"config",
+	}
+	names := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		names = append(names, obj.GetName())
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected restore order %v, got %v", expected, names)
+	}
+}
+
+func TestCleanForBackup(t *testing.T) {
+	obj := newBackupObject("types.kubefed.io/v1beta1", "FederatedConfigMap", "config")
+	obj.SetNamespace("test")
+	obj.SetLabels(map[string]string{"app": "test"})
+	obj.SetUID("1234")
+	obj.SetResourceVersion("42")
+	obj.SetGeneration(3)
+	obj.SetFinalizers([]string{"kubefed.io/sync-controller"})
+	obj.Object["status"] = map[string]interface{}{"clusters": []interface{}{}}
+
+	cleanForBackup(obj)
+
+	expected := newBackupObject("types.kubefed.io/v1beta1", "FederatedConfigMap", "config")
+	expected.SetNamespace("test")
+	expected.SetLabels(map[string]string{"app": "test"})
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("Expected %v, got %v", expected, obj)
+	}
+}
+
+func TestFilterByGroupKind(t *testing.T) {
+	objs := []*unstructured.Unstructured{
+		newBackupObject("core.kubefed.io/v1beta1", "KubeFedCluster", "cluster1"),
+		newBackupObject("v1", "Secret", "cluster1-token"),
+		newBackupObject("core.kubefed.io/v1beta1", "KubeFedCluster", "cluster2"),
+		newBackupObject("example.com/v1", "KubeFedCluster", "other"),
+	}
+	names := []string{}
+	for _, obj := range filterByGroupKind(objs, kubeFedClusterAPIResource) {
+		names = append(names, obj.GetName())
+	}
+	expected := []string{"cluster1", "cluster2"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestRestoreAPIResources(t *testing.T) {
+	typeConfig := newBackupObject("core.kubefed.io/v1beta1", "FederatedTypeConfig", "deployments.apps")
+	typeConfig.Object["spec"] = map[string]interface{}{
+		"targetType": map[string]interface{}{
+			"group":      "apps",
+			"version":    "v1",
+			"kind":       "Deployment",
+			"pluralName": "deployments",
+			"scope":      "Namespaced",
+		},
+		"federatedType": map[string]interface{}{
+			"group":      "types.kubefed.io",
+			"version":    "v1beta1",
+			"kind":       "FederatedDeployment",
+			"pluralName": "federateddeployments",
+			"scope":      "Namespaced",
+		},
+	}
+	objs := []*unstructured.Unstructured{
+		typeConfig,
+		newBackupObject("types.kubefed.io/v1beta1", "FederatedDeployment", "app"),
+	}
+
+	apiResources, err := restoreAPIResources(objs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := apiResources[schema.GroupKind{Group: "core.kubefed.io", Kind: "KubeFedCluster"}]; !ok {
+		t.Errorf("Expected the api resource of KubeFedClusters")
+	}
+	apiResource, ok := apiResources[schema.GroupKind{Group: "types.kubefed.io", Kind: "FederatedDeployment"}]
+	if !ok {
+		t.Fatalf("Expected the api resource of the federated type")
+	}
+	if apiResource.Name != "federateddeployments" || !apiResource.Namespaced {
+		t.Errorf("Expected the namespaced resource federateddeployments, got %v", apiResource)
+	}
+}
+
+func TestMigrateComplete(t *testing.T) {
+	testCases := map[string]struct {
+		opts         migrateControlPlane
+		expectedName string
+		expectedErr  bool
+	}{
+		"New host cluster is required": {
+			opts:        migrateControlPlane{onConflict: conflictSkip},
+			expectedErr: true,
+		},
+		"Name defaults to the context": {
+			opts:         migrateControlPlane{toHostClusterContext: "baz", onConflict: conflictSkip},
+			expectedName: "baz",
+		},
+		"Name overrides the context": {
+			opts:         migrateControlPlane{toHostClusterContext: "baz", toHostClusterName: "cluster-baz", onConflict: conflictFail},
+			expectedName: "cluster-baz",
+		},
+		"Invalid conflict handling": {
+			opts:        migrateControlPlane{toHostClusterContext: "baz", onConflict: "merge"},
+			expectedErr: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			opts := tc.opts
+			err := opts.Complete(nil)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if opts.toHostClusterName != tc.expectedName {
+				t.Errorf("Expected host cluster name %q, got %q", tc.expectedName, opts.toHostClusterName)
+			}
+		})
+	}
+}
+
+func TestScaleControllerManager(t *testing.T) {
+	testCases := map[string]struct {
+		dryRun          bool
+		currentReplicas int32
+		replicas        int32
+		expectedUpdate  bool
+	}{
+		"Controller manager is stopped": {
+			currentReplicas: 2,
+			replicas:        0,
+			expectedUpdate:  true,
+		},
+		"Stopped controller manager is not updated": {
+			currentReplicas: 0,
+			replicas:        0,
+		},
+		"Controller manager is not scaled in a dry run": {
+			dryRun:          true,
+			currentReplicas: 2,
+			replicas:        0,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			client.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "scale" {
+					return false, nil, nil
+				}
+				return true, &autoscalingv1.Scale{
+					ObjectMeta: metav1.ObjectMeta{Namespace: action.GetNamespace(), Name: controllerManagerDeploymentName},
+					Spec:       autoscalingv1.ScaleSpec{Replicas: tc.currentReplicas},
+				}, nil
+			})
+			var updatedReplicas *int32
+			client.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				scale := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+				updatedReplicas = &scale.Spec.Replicas
+				return true, scale, nil
+			})
+
+			o := &migrateControlPlane{
+				GlobalSubcommandOptions: options.GlobalSubcommandOptions{
+					KubeFedNamespace: "kube-federation-system",
+					DryRun:           tc.dryRun,
+				},
+			}
+			previousReplicas, err := o.scaleControllerManager(&bytes.Buffer{}, client, "bar", tc.replicas)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if previousReplicas != tc.currentReplicas {
+				t.Errorf("Expected previous replicas %d, got %d", tc.currentReplicas, previousReplicas)
+			}
+			if !tc.expectedUpdate {
+				if updatedReplicas != nil {
+					t.Errorf("Expected the controller manager not to be scaled")
+				}
+				return
+			}
+			if updatedReplicas == nil || *updatedReplicas != tc.replicas {
+				t.Errorf("Expected the controller manager to be scaled to %d replicas", tc.replicas)
+			}
+		})
+	}
+}
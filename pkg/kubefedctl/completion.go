@@ -0,0 +1,342 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	completion_long = `
+		Completion outputs the shell completion code for kubefedctl
+		in the given shell. Only bash is supported.
+
+		In addition to commands and flags, the names of contexts,
+		clusters, enabled types and federated resources are
+		completed. Names other than contexts are fetched from the
+		host cluster when completion is requested.
+
+		The completion code depends on the bash-completion package
+		being installed.`
+	completion_example = `
+		# Load the kubefedctl completion code into the current shell
+		source <(kubefedctl completion bash)
+
+		# Load the kubefedctl completion code into every new shell
+		kubefedctl completion bash > /etc/bash_completion.d/kubefedctl`
+
+	// Names of flags that are completed with the names of the
+	// contexts of the local kubeconfig.
+	contextFlagNames = []string{"host-cluster-context", "cluster-context", "to-host-cluster-context"}
+	// Names of flags that are completed with the names of the
+	// clusters registered with the control plane.
+	clusterFlagNames = []string{"add-cluster", "remove-cluster", "copy-overrides-from", "against-cluster"}
+	// Names of flags that are completed with the names of the
+	// enabled types.
+	typeFlagNames = []string{"type"}
+)
+
+// Shell functions called by the generated bash completion code to
+// complete the positional arguments of commands. The values of the
+// flags that select the host cluster and namespace are passed on to
+// `kubefedctl completion names` to fetch names from the host cluster.
+const bashCompletionFunc = `__kubefedctl_override_flag_list=(--kubeconfig --host-cluster-context --kubefed-namespace --namespace -n)
+__kubefedctl_override_flags()
+{
+    local ${__kubefedctl_override_flag_list[*]//-/_}
+    local two_word_of of var
+    for w in "${words[@]}"; do
+        if [ -n "${two_word_of}" ]; then
+            eval "${two_word_of//-/_}=\"${two_word_of}=\${w}\""
+            two_word_of=
+            continue
+        fi
+        for of in "${__kubefedctl_override_flag_list[@]}"; do
+            case "${w}" in
+                ${of}=*)
+                    eval "${of//-/_}=\"${w}\""
+                    ;;
+                ${of})
+                    two_word_of="${of}"
+                    ;;
+            esac
+        done
+    done
+    for var in "${__kubefedctl_override_flag_list[@]//-/_}"; do
+        if eval "test -n \"\$${var}\""; then
+            eval "echo -n \${${var}}' '"
+        fi
+    done
+}
+
+__kubefedctl_get_names()
+{
+    local kubefedctl_out
+    if kubefedctl_out=$(kubefedctl completion names $(__kubefedctl_override_flags) "$@" 2>/dev/null); then
+        COMPREPLY=( $( compgen -W "${kubefedctl_out[*]}" -- "$cur" ) )
+    fi
+}
+
+__kubefedctl_get_contexts()
+{
+    __kubefedctl_get_names contexts
+}
+
+__kubefedctl_get_clusters()
+{
+    __kubefedctl_get_names clusters
+}
+
+__kubefedctl_get_types()
+{
+    __kubefedctl_get_names types
+}
+
+__kubefedctl_custom_func() {
+    case ${last_command} in
+        kubefedctl_join)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __kubefedctl_get_contexts
+            fi
+            ;;
+        kubefedctl_unjoin | kubefedctl_quarantine_*)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __kubefedctl_get_clusters
+            fi
+            ;;
+        kubefedctl_enable)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __kubefedctl_get_names apiresources
+            fi
+            ;;
+        kubefedctl_disable | kubefedctl_federate)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __kubefedctl_get_types
+            fi
+            ;;
+        kubefedctl_orphaning-deletion_* | kubefedctl_undelete)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __kubefedctl_get_names federatedtypes
+            elif [[ ${#nouns[@]} -eq 1 ]]; then
+                __kubefedctl_get_names resources "${nouns[0]}"
+            fi
+            ;;
+        *)
+            ;;
+    esac
+}
+`
+
+// NewCmdCompletion defines the `completion` command that outputs
+// shell completion code for `kubefedctl`.
+func NewCmdCompletion(out io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "completion SHELL",
+		Short:   "Output shell completion code for the given shell (bash)",
+		Long:    completion_long,
+		Example: completion_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := runCompletion(out, cmd, args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+	cmd.AddCommand(newCmdCompletionNames(out, config))
+
+	return cmd
+}
+
+func runCompletion(out io.Writer, cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("SHELL is required")
+	}
+	if args[0] != "bash" {
+		return errors.Errorf("Unsupported shell %q. Only bash is supported.", args[0])
+	}
+
+	rootCmd := cmd.Root()
+	if rootCmd.Name() != "kubefedctl" {
+		return errors.Errorf("Completion is only supported for the kubefedctl command, not %q", rootCmd.Name())
+	}
+	rootCmd.BashCompletionFunction = bashCompletionFunc
+	annotateCompletionFlags(rootCmd)
+	return rootCmd.GenBashCompletion(out)
+}
+
+// annotateCompletionFlags marks the flags of the given command and its
+// children whose values are names to be completed by the functions of
+// bashCompletionFunc.
+func annotateCompletionFlags(cmd *cobra.Command) {
+	annotate := func(flags *pflag.FlagSet, names []string, completionFunc string) {
+		for _, name := range names {
+			flag := flags.Lookup(name)
+			if flag == nil || flag.Value.Type() == "stringToString" {
+				continue
+			}
+			_ = flags.SetAnnotation(name, cobra.BashCompCustom, []string{completionFunc})
+		}
+	}
+	annotate(cmd.Flags(), contextFlagNames, "__kubefedctl_get_contexts")
+	annotate(cmd.Flags(), clusterFlagNames, "__kubefedctl_get_clusters")
+	annotate(cmd.Flags(), typeFlagNames, "__kubefedctl_get_types")
+	for _, child := range cmd.Commands() {
+		annotateCompletionFlags(child)
+	}
+}
+
+type completionNames struct {
+	options.GlobalSubcommandOptions
+	resourceNamespace string
+}
+
+// newCmdCompletionNames defines the hidden `completion names` command
+// that outputs the names completed by the bash completion code.
+func newCmdCompletionNames(out io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &completionNames{}
+
+	cmd := &cobra.Command{
+		Use:    "names (contexts | clusters | types | federatedtypes | apiresources | resources TYPE)",
+		Short:  "Output the names completed by the shell completion code",
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			names, err := opts.Run(config, args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+			for _, name := range names {
+				fmt.Fprintln(out, name)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	flags.StringVarP(&opts.resourceNamespace, "namespace", "n", "", "Namespace of the federated resources. Defaults to the namespace of the current context.")
+
+	return cmd
+}
+
+// Run returns the names of the given kind.
+func (o *completionNames) Run(config util.FedConfig, args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.New("KIND is required")
+	}
+
+	hostClientConfig := config.GetClientConfig(o.HostClusterContext, o.Kubeconfig)
+	if args[0] == "contexts" {
+		return util.ContextNames(hostClientConfig)
+	}
+	hostConfig, err := hostClientConfig.ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get host cluster config")
+	}
+	if args[0] == "apiresources" {
+		return enable.APIResourceNames(hostConfig)
+	}
+	if args[0] == "resources" {
+		if len(args) != 2 {
+			return nil, errors.New("TYPE is required")
+		}
+		return o.resourceNames(config, hostConfig, args[1])
+	}
+
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	switch args[0] {
+	case "clusters":
+		clusterList := &fedv1b1.KubeFedClusterList{}
+		err := client.List(context.TODO(), clusterList, o.KubeFedNamespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range clusterList.Items {
+			names = append(names, cluster.Name)
+		}
+	case "types", "federatedtypes":
+		typeConfigList := &fedv1b1.FederatedTypeConfigList{}
+		err := client.List(context.TODO(), typeConfigList, o.KubeFedNamespace)
+		if err != nil {
+			return nil, err
+		}
+		for i := range typeConfigList.Items {
+			typeConfig := &typeConfigList.Items[i]
+			if args[0] == "types" {
+				names = append(names, typeConfig.Name)
+			} else {
+				names = append(names, typeconfig.GroupQualifiedName(typeConfig.GetFederatedType()))
+			}
+		}
+	default:
+		return nil, errors.Errorf("Unknown kind %q", args[0])
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// resourceNames returns the names of the resources of the given type
+// in the namespace of the command.
+func (o *completionNames) resourceNames(config util.FedConfig, hostConfig *rest.Config, typeName string) ([]string, error) {
+	namespace := o.resourceNamespace
+	if len(namespace) == 0 {
+		var err error
+		namespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	apiResource, err := enable.LookupAPIResource(hostConfig, typeName, "")
+	if err != nil {
+		return nil, err
+	}
+	resourceClient, err := ctlutil.NewResourceClient(hostConfig, apiResource)
+	if err != nil {
+		return nil, err
+	}
+	resourceList, err := resourceClient.Resources(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(resourceList.Items))
+	for _, resource := range resourceList.Items {
+		names = append(names, resource.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
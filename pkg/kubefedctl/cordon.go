@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	cordon_long = `
+		Cordon a cluster so that no new placements are made to
+		it. Resources already propagated to the cluster remain in
+		place, and the replicas already scheduled to the cluster
+		are not increased.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	cordon_example = `
+		# Cordon cluster foo
+		kubefedctl cordon cluster foo --host-cluster-context=bar`
+
+	uncordon_long = `
+		Uncordon a cluster so that new placements are made to it
+		again.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	uncordon_example = `
+		# Uncordon cluster foo
+		kubefedctl uncordon cluster foo --host-cluster-context=bar`
+)
+
+type cordonCluster struct {
+	options.GlobalSubcommandOptions
+	name          string
+	unschedulable bool
+}
+
+// NewCmdCordon defines the `cordon` command that marks clusters as
+// unschedulable.
+func NewCmdCordon(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	return newCmdCordon(cmdOut, config, "cordon", "Mark a cluster as unschedulable",
+		cordon_long, cordon_example, true)
+}
+
+// NewCmdUncordon defines the `uncordon` command that marks clusters
+// as schedulable.
+func NewCmdUncordon(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	return newCmdCordon(cmdOut, config, "uncordon", "Mark a cluster as schedulable",
+		uncordon_long, uncordon_example, false)
+}
+
+func newCmdCordon(cmdOut io.Writer, config util.FedConfig, verb, short, long, example string, unschedulable bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   verb,
+		Short: short,
+		Long:  short,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts := &cordonCluster{unschedulable: unschedulable}
+	clusterCmd := &cobra.Command{
+		Use:     "cluster CLUSTER_NAME --host-cluster-context=HOST_CONTEXT",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				klog.Fatalf("Error: CLUSTER_NAME is required")
+			}
+			opts.name = args[0]
+
+			err := opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+	opts.GlobalSubcommandBind(clusterCmd.Flags())
+	cmd.AddCommand(clusterCmd)
+
+	return cmd
+}
+
+// Run cordons or uncordons the cluster.
+func (o *cordonCluster) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostClientConfig := config.GetClientConfig(o.HostClusterContext, o.Kubeconfig)
+	if err := o.SetHostClusterContextFromConfig(hostClientConfig); err != nil {
+		return err
+	}
+	hostConfig, err := hostClientConfig.ClientConfig()
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return err
+	}
+
+	cluster := &fedv1b1.KubeFedCluster{}
+	err = client.Get(context.TODO(), cluster, o.KubeFedNamespace, o.name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get KubeFedCluster %q", o.name)
+	}
+
+	if cluster.Spec.Unschedulable == o.unschedulable {
+		if o.unschedulable {
+			fmt.Fprintf(cmdOut, "Cluster %q is already cordoned\n", o.name)
+		} else {
+			fmt.Fprintf(cmdOut, "Cluster %q is already uncordoned\n", o.name)
+		}
+		return nil
+	}
+
+	cluster.Spec.Unschedulable = o.unschedulable
+	if o.DryRun {
+		return nil
+	}
+	err = client.Update(context.TODO(), cluster)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update KubeFedCluster %q", o.name)
+	}
+
+	if o.unschedulable {
+		fmt.Fprintf(cmdOut, "Cordoned cluster %q\n", o.name)
+	} else {
+		fmt.Fprintf(cmdOut, "Uncordoned cluster %q\n", o.name)
+	}
+	return nil
+}
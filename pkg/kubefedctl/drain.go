@@ -0,0 +1,502 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	drain_long = `
+		Drain a cluster in preparation for its decommissioning.
+		The cluster is cordoned, and is then gradually removed from
+		the ReplicaSchedulingPreferences and from the placement of
+		the federated resources that list it by name. The steps are
+		spread evenly over the grace period and their progress is
+		reported.
+
+		The replicas scheduled to the cluster by an RSP are reduced
+		by the maxSurge of the rollout strategy of its workload at
+		each step, so that the other clusters scale up at the pace
+		of a rollout. A resource that is only placed in the cluster
+		is not removed from it. Workloads are removed before
+		configuration resources, which can optionally be left in
+		place with --keep-config. Resources placed by clusterGroups
+		or a cluster selector are reported and skipped.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	drain_example = `
+		# Drain cluster prod-eu over 30 minutes
+		kubefedctl drain cluster prod-eu --grace=30m --host-cluster-context=bar
+
+		# Show the steps of draining cluster prod-eu, leaving its
+		# configuration resources in place
+		kubefedctl drain cluster prod-eu --keep-config --dry-run`
+
+	// workloadKinds are the target kinds whose resources run pods
+	// and are removed from a drained cluster before other resources.
+	workloadKinds = sets.NewString("Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "Job", "CronJob")
+)
+
+const defaultMaxSurgePercentage = "25%"
+
+type drainCluster struct {
+	options.GlobalSubcommandOptions
+	name       string
+	grace      time.Duration
+	keepConfig bool
+}
+
+// drainStep is a single change made while draining a cluster.
+type drainStep struct {
+	description string
+	apply       func() error
+}
+
+// Bind adds the drain specific arguments to the flagset passed in as
+// an argument.
+func (o *drainCluster) Bind(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.grace, "grace", 0, "The period over which the steps of the drain are spread. Steps are applied without delay if not provided.")
+	flags.BoolVar(&o.keepConfig, "keep-config", false, "Leave resources other than workloads in place in the cluster.")
+}
+
+// NewCmdDrain defines the `drain` command that gradually evacuates
+// clusters.
+func NewCmdDrain(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drain",
+		Short: "Gradually evacuate a cluster",
+		Long:  "Gradually evacuate a cluster",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts := &drainCluster{}
+	clusterCmd := &cobra.Command{
+		Use:     "cluster CLUSTER_NAME [--grace=DURATION] [--keep-config] --host-cluster-context=HOST_CONTEXT",
+		Short:   "Gradually evacuate a cluster",
+		Long:    drain_long,
+		Example: drain_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				klog.Fatalf("Error: CLUSTER_NAME is required")
+			}
+			opts.name = args[0]
+
+			err := opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+	flags := clusterCmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+	cmd.AddCommand(clusterCmd)
+
+	return cmd
+}
+
+// Run implements the `drain cluster` command.
+func (o *drainCluster) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create host cluster client")
+	}
+
+	cluster := &fedv1b1.KubeFedCluster{}
+	err = client.Get(context.TODO(), cluster, o.KubeFedNamespace, o.name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get KubeFedCluster %q", o.name)
+	}
+	if !cluster.Spec.Unschedulable && !o.DryRun {
+		cluster.Spec.Unschedulable = true
+		err = client.Update(context.TODO(), cluster)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to cordon KubeFedCluster %q", o.name)
+		}
+	}
+	fmt.Fprintf(cmdOut, "Cordoned cluster %q\n", o.name)
+
+	typeConfigList := &fedv1b1.FederatedTypeConfigList{}
+	err = client.List(context.TODO(), typeConfigList, o.KubeFedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+	resourceClients := make(map[string]ctlutil.ResourceClient)
+	var typeConfigs []*fedv1b1.FederatedTypeConfig
+	for i := range typeConfigList.Items {
+		typeConfig := &typeConfigList.Items[i]
+		if !typeConfig.GetNamespaced() {
+			continue
+		}
+		fedType := typeConfig.GetFederatedType()
+		resourceClient, err := ctlutil.NewResourceClient(hostConfig, &fedType)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create client for %s", fedType.Kind)
+		}
+		resourceClients[fedType.Kind] = resourceClient
+		typeConfigs = append(typeConfigs, typeConfig)
+	}
+
+	rspSteps, scheduled, err := o.replicaSchedulingSteps(cmdOut, client, resourceClients)
+	if err != nil {
+		return err
+	}
+	workloadSteps, configSteps, err := o.placementSteps(cmdOut, typeConfigs, resourceClients, scheduled)
+	if err != nil {
+		return err
+	}
+	steps := append(rspSteps, workloadSteps...)
+	if o.keepConfig {
+		fmt.Fprintf(cmdOut, "Leaving %d configuration resource(s) in place\n", len(configSteps))
+	} else {
+		steps = append(steps, configSteps...)
+	}
+
+	if len(steps) == 0 {
+		fmt.Fprintf(cmdOut, "Nothing to drain from cluster %q\n", o.name)
+		return nil
+	}
+	interval := o.grace / time.Duration(len(steps))
+	for i, step := range steps {
+		if i > 0 && interval > 0 && !o.DryRun {
+			time.Sleep(interval)
+		}
+		fmt.Fprintf(cmdOut, "[%d/%d] %s\n", i+1, len(steps), step.description)
+		if o.DryRun {
+			continue
+		}
+		if err := step.apply(); err != nil {
+			return err
+		}
+	}
+	if o.DryRun {
+		fmt.Fprintf(cmdOut, "Cluster %q would be drained in %d step(s) (dry run)\n", o.name, len(steps))
+	} else {
+		fmt.Fprintf(cmdOut, "Drained cluster %q\n", o.name)
+	}
+	return nil
+}
+
+// replicaSchedulingSteps returns the steps reducing the replicas that
+// the ReplicaSchedulingPreferences schedule to the drained cluster,
+// and the keys of the federated workloads that the RSPs schedule.
+func (o *drainCluster) replicaSchedulingSteps(cmdOut io.Writer, client genericclient.Client, resourceClients map[string]ctlutil.ResourceClient) ([]drainStep, sets.String, error) {
+	rspList := &fedschedulingv1a1.ReplicaSchedulingPreferenceList{}
+	err := client.List(context.TODO(), rspList, metav1.NamespaceAll)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to list ReplicaSchedulingPreferences")
+	}
+
+	var steps []drainStep
+	scheduled := sets.String{}
+	for i := range rspList.Items {
+		rsp := &rspList.Items[i]
+		key := rsp.Spec.TargetKind + "/" + ctlutil.NewQualifiedName(rsp).String()
+		scheduled.Insert(key)
+
+		preferences, ok := drainedPreferences(rsp, o.name)
+		if !ok {
+			continue
+		}
+		if !schedulesToOtherClusters(rsp, o.name) {
+			fmt.Fprintf(cmdOut, "Skipping ReplicaSchedulingPreference %q: replicas are only scheduled to cluster %q\n", ctlutil.NewQualifiedName(rsp), o.name)
+			continue
+		}
+
+		resourceClient, ok := resourceClients[rsp.Spec.TargetKind]
+		if !ok {
+			continue
+		}
+		workload, err := resourceClient.Resources(rsp.Namespace).Get(rsp.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to get %s %q", rsp.Spec.TargetKind, ctlutil.NewQualifiedName(rsp))
+		}
+		replicas, err := clusterReplicas(workload, o.name)
+		if err != nil {
+			return nil, nil, err
+		}
+		stepSize, err := rolloutStepSize(workload, rsp.Spec.TotalReplicas)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, maxReplicas := range replicaSteps(replicas, stepSize) {
+			steps = append(steps, o.rspStep(client, ctlutil.NewQualifiedName(rsp), preferences, maxReplicas))
+		}
+	}
+	return steps, scheduled, nil
+}
+
+// rspStep returns the step limiting the replicas scheduled to the
+// drained cluster by the named RSP to the given maximum.
+func (o *drainCluster) rspStep(client genericclient.Client, qualifiedName ctlutil.QualifiedName, preferences fedschedulingv1a1.ClusterPreferences, maxReplicas int64) drainStep {
+	return drainStep{
+		description: fmt.Sprintf("Scheduling at most %d replica(s) to cluster %q for ReplicaSchedulingPreference %q", maxReplicas, o.name, qualifiedName),
+		apply: func() error {
+			rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{}
+			err := client.Get(context.TODO(), rsp, qualifiedName.Namespace, qualifiedName.Name)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to get ReplicaSchedulingPreference %q", qualifiedName)
+			}
+			if len(rsp.Spec.Clusters) == 0 {
+				// Retain the default of scheduling to all clusters
+				// with equal weight.
+				rsp.Spec.Clusters = map[string]fedschedulingv1a1.ClusterPreferences{
+					"*": {Weight: 1},
+				}
+			}
+			max := maxReplicas
+			preferences.MaxReplicas = &max
+			if preferences.MinReplicas > max {
+				preferences.MinReplicas = max
+			}
+			rsp.Spec.Clusters[o.name] = preferences
+			err = client.Update(context.TODO(), rsp)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to update ReplicaSchedulingPreference %q", qualifiedName)
+			}
+			return nil
+		},
+	}
+}
+
+// placementSteps returns the steps removing the drained cluster from
+// the placement of workloads and of other resources. Workloads
+// scheduled by an RSP, whose placement is maintained by the scheduler,
+// are excluded.
+func (o *drainCluster) placementSteps(cmdOut io.Writer, typeConfigs []*fedv1b1.FederatedTypeConfig, resourceClients map[string]ctlutil.ResourceClient, scheduled sets.String) ([]drainStep, []drainStep, error) {
+	var workloadSteps, configSteps []drainStep
+	for _, typeConfig := range typeConfigs {
+		fedKind := typeConfig.GetFederatedType().Kind
+		resourceClient := resourceClients[fedKind]
+		objList, err := resourceClient.Resources(metav1.NamespaceAll).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to list %s", fedKind)
+		}
+
+		isWorkload := workloadKinds.Has(typeConfig.GetTargetType().Kind)
+		for i := range objList.Items {
+			obj := &objList.Items[i]
+			qualifiedName := ctlutil.NewQualifiedName(obj)
+			if isWorkload && scheduled.Has(fedKind+"/"+qualifiedName.String()) {
+				continue
+			}
+			clusterNames, byName, err := placedClusterNames(obj)
+			if err != nil {
+				fmt.Fprintf(cmdOut, "Skipping %s %q: %v\n", fedKind, qualifiedName, err)
+				continue
+			}
+			if !byName {
+				fmt.Fprintf(cmdOut, "Skipping %s %q: placement is not determined by cluster name\n", fedKind, qualifiedName)
+				continue
+			}
+			if !sets.NewString(clusterNames...).Has(o.name) {
+				continue
+			}
+			if len(clusterNames) == 1 {
+				fmt.Fprintf(cmdOut, "Skipping %s %q: the resource is only placed in cluster %q\n", fedKind, qualifiedName, o.name)
+				continue
+			}
+
+			step := o.placementStep(resourceClient, fedKind, qualifiedName)
+			if isWorkload {
+				workloadSteps = append(workloadSteps, step)
+			} else {
+				configSteps = append(configSteps, step)
+			}
+		}
+	}
+	return workloadSteps, configSteps, nil
+}
+
+// placementStep returns the step removing the drained cluster from the
+// placement of the named federated resource.
+func (o *drainCluster) placementStep(resourceClient ctlutil.ResourceClient, fedKind string, qualifiedName ctlutil.QualifiedName) drainStep {
+	patch := &patchPlacementOptions{removeClusters: []string{o.name}}
+	return drainStep{
+		description: fmt.Sprintf("Removing cluster %q from the placement of %s %q", o.name, fedKind, qualifiedName),
+		apply: func() error {
+			obj, err := resourceClient.Resources(qualifiedName.Namespace).Get(qualifiedName.Name, metav1.GetOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "Failed to get %s %q", fedKind, qualifiedName)
+			}
+			changes, err := patch.patch(obj)
+			if err != nil || len(changes) == 0 {
+				return err
+			}
+			_, err = resourceClient.Resources(qualifiedName.Namespace).Update(obj, metav1.UpdateOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "Failed to update %s %q", fedKind, qualifiedName)
+			}
+			return nil
+		},
+	}
+}
+
+// placedClusterNames returns the names of the clusters listed in the
+// placement of the given federated resource, and whether the
+// placement is determined by cluster name.
+func placedClusterNames(obj *unstructured.Unstructured) ([]string, bool, error) {
+	placement, err := ctlutil.UnmarshalGenericPlacement(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	clusterNames := placement.ClusterNames()
+	if clusterNames == nil && (placement.ClusterGroupNames() != nil || placement.Spec.Placement.ClusterSelector != nil ||
+		placement.Spec.Placement.RequiredClusterSelector != nil) {
+		return nil, false, nil
+	}
+	return clusterNames, true, nil
+}
+
+// drainedPreferences returns the preferences of the drained cluster
+// in the given RSP, and whether the RSP may schedule replicas to the
+// cluster.
+func drainedPreferences(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusterName string) (fedschedulingv1a1.ClusterPreferences, bool) {
+	if len(rsp.Spec.Clusters) == 0 {
+		return fedschedulingv1a1.ClusterPreferences{Weight: 1}, true
+	}
+	preferences, ok := rsp.Spec.Clusters[clusterName]
+	if !ok {
+		preferences, ok = rsp.Spec.Clusters["*"]
+	}
+	if !ok || (preferences.MaxReplicas != nil && *preferences.MaxReplicas == 0) {
+		return preferences, false
+	}
+	return preferences, true
+}
+
+// schedulesToOtherClusters returns whether the given RSP may schedule
+// replicas to clusters other than the drained cluster.
+func schedulesToOtherClusters(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusterName string) bool {
+	if len(rsp.Spec.Clusters) == 0 {
+		return true
+	}
+	for name, preferences := range rsp.Spec.Clusters {
+		if name == clusterName {
+			continue
+		}
+		if preferences.MaxReplicas == nil || *preferences.MaxReplicas > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterReplicas returns the replicas of the given federated workload
+// overridden for the given cluster by the scheduler.
+func clusterReplicas(obj *unstructured.Unstructured, clusterName string) (int64, error) {
+	overridesMap, err := ctlutil.GetOverrides(obj)
+	if err != nil {
+		return 0, err
+	}
+	for _, override := range overridesMap[clusterName] {
+		if override.Path != "/spec/replicas" {
+			continue
+		}
+		switch value := override.Value.(type) {
+		case int64:
+			return value, nil
+		case float64:
+			return int64(value), nil
+		}
+	}
+	return 0, nil
+}
+
+// rolloutStepSize returns the number of replicas by which the replicas
+// of a cluster are reduced at each step, which is the maxSurge of the
+// rolling update strategy of the given federated workload resolved
+// against the total replicas. The default maxSurge of deployments
+// applies if none is provided.
+func rolloutStepSize(obj *unstructured.Unstructured, totalReplicas int32) (int64, error) {
+	maxSurge := intstr.FromString(defaultMaxSurgePercentage)
+	value, ok, err := unstructured.NestedFieldNoCopy(obj.Object, ctlutil.SpecField, ctlutil.TemplateField,
+		"spec", "strategy", "rollingUpdate", "maxSurge")
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		switch value := value.(type) {
+		case int64:
+			maxSurge = intstr.FromInt(int(value))
+		case float64:
+			maxSurge = intstr.FromInt(int(value))
+		case string:
+			maxSurge = intstr.FromString(value)
+		}
+	}
+	stepSize, err := intstr.GetValueFromIntOrPercent(&maxSurge, int(totalReplicas), true)
+	if err != nil {
+		return 0, errors.Wrap(err, "Invalid maxSurge")
+	}
+	if stepSize < 1 {
+		stepSize = 1
+	}
+	return int64(stepSize), nil
+}
+
+// replicaSteps returns the decreasing maximum replicas of a cluster at
+// each step of reducing the given replicas to zero by the given step
+// size.
+func replicaSteps(replicas, stepSize int64) []int64 {
+	var steps []int64
+	for replicas > 0 {
+		replicas -= stepSize
+		if replicas < 0 {
+			replicas = 0
+		}
+		steps = append(steps, replicas)
+	}
+	if len(steps) == 0 {
+		steps = append(steps, 0)
+	}
+	return steps
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestReplicaSteps(t *testing.T) {
+	testCases := map[string]struct {
+		replicas int64
+		stepSize int64
+		expected []int64
+	}{
+		"No replicas": {
+			replicas: 0,
+			stepSize: 2,
+			expected: []int64{0},
+		},
+		"Replicas divisible by the step size": {
+			replicas: 6,
+			stepSize: 2,
+			expected: []int64{4, 2, 0},
+		},
+		"Replicas not divisible by the step size": {
+			replicas: 5,
+			stepSize: 2,
+			expected: []int64{3, 1, 0},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			steps := replicaSteps(tc.replicas, tc.stepSize)
+			if !reflect.DeepEqual(steps, tc.expected) {
+				t.Errorf("Expected steps %v, got %v", tc.expected, steps)
+			}
+		})
+	}
+}
+
+func TestRolloutStepSize(t *testing.T) {
+	newWorkload := func(maxSurge interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if maxSurge != nil {
+			err := unstructured.SetNestedField(obj.Object, maxSurge, "spec", "template", "spec", "strategy", "rollingUpdate", "maxSurge")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		return obj
+	}
+
+	testCases := map[string]struct {
+		maxSurge interface{}
+		expected int64
+	}{
+		"Default maxSurge": {
+			expected: 3,
+		},
+		"Absolute maxSurge": {
+			maxSurge: int64(4),
+			expected: 4,
+		},
+		"Percentage maxSurge": {
+			maxSurge: "50%",
+			expected: 5,
+		},
+		"Zero maxSurge": {
+			maxSurge: int64(0),
+			expected: 1,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			stepSize, err := rolloutStepSize(newWorkload(tc.maxSurge), 10)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stepSize != tc.expected {
+				t.Errorf("Expected step size %d, got %d", tc.expected, stepSize)
+			}
+		})
+	}
+}
+
+func TestDrainedPreferences(t *testing.T) {
+	zero := int64(0)
+	testCases := map[string]struct {
+		clusters        map[string]fedschedulingv1a1.ClusterPreferences
+		expectedDrained bool
+		expectedOthers  bool
+	}{
+		"Default preferences": {
+			expectedDrained: true,
+			expectedOthers:  true,
+		},
+		"Wildcard preferences": {
+			clusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*": {Weight: 1},
+			},
+			expectedDrained: true,
+			expectedOthers:  true,
+		},
+		"Only the drained cluster": {
+			clusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"drained": {Weight: 1},
+			},
+			expectedDrained: true,
+		},
+		"Drained cluster already limited to no replicas": {
+			clusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"drained": {MaxReplicas: &zero},
+				"other":   {Weight: 1},
+			},
+			expectedOthers: true,
+		},
+		"Drained cluster without preferences": {
+			clusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"other": {Weight: 1},
+			},
+			expectedOthers: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+				Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+					Clusters: tc.clusters,
+				},
+			}
+			if _, drained := drainedPreferences(rsp, "drained"); drained != tc.expectedDrained {
+				t.Errorf("Expected drained %v, got %v", tc.expectedDrained, drained)
+			}
+			if others := schedulesToOtherClusters(rsp, "drained"); others != tc.expectedOthers {
+				t.Errorf("Expected scheduling to other clusters %v, got %v", tc.expectedOthers, others)
+			}
+		})
+	}
+}
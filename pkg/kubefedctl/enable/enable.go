@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
@@ -65,12 +67,21 @@ var (
 
 		# Enable federation of Deployments identified by name specified in
 		# deployment.yaml
-		kubefedctl enable -f deployment.yaml`
+		kubefedctl enable -f deployment.yaml
+
+		# Choose the type to enable from the API resources of
+		# the host cluster
+		kubefedctl enable --interactive
+
+		# Validate the resources that enabling Deployments would apply
+		# and write them to files in the manifests directory instead
+		kubefedctl enable deployments.apps --simulate --output-dir=manifests`
 )
 
 type enableType struct {
 	options.GlobalSubcommandOptions
 	options.CommonEnableOptions
+	options.CompatibilityOptions
 	enableTypeOptions
 }
 
@@ -79,6 +90,9 @@ type enableTypeOptions struct {
 	output              string
 	outputYAML          bool
 	filename            string
+	interactive         bool
+	simulate            bool
+	outputDir           string
 	enableTypeDirective *EnableTypeDirective
 }
 
@@ -88,6 +102,9 @@ func (o *enableTypeOptions) Bind(flags *pflag.FlagSet) {
 	flags.StringVar(&o.federatedVersion, "federated-version", options.DefaultFederatedVersion, "The API version to use for the generated federated type.")
 	flags.StringVarP(&o.output, "output", "o", "", "If provided, the resources that would be created in the API by the command are instead output to stdout in the provided format.  Valid values are ['yaml'].")
 	flags.StringVarP(&o.filename, "filename", "f", "", "If provided, the command will be configured from the provided yaml file.  Only --output will be accepted from the command line")
+	flags.BoolVar(&o.interactive, "interactive", false, "If true and NAME is not provided, the API resources of the host cluster are listed to choose the type to enable from.")
+	flags.BoolVar(&o.simulate, "simulate", false, "If true, the resources that would be created in the API by the command are validated against the host cluster without changing it, and are output to stdout as yaml or to --output-dir.")
+	flags.StringVar(&o.outputDir, "output-dir", "", "If provided with --simulate, each resource is written to a yaml file in this directory rather than to stdout.")
 }
 
 // NewCmdTypeEnable defines the `enable` command that
@@ -101,6 +118,14 @@ func NewCmdTypeEnable(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
 		Long:    enable_long,
 		Example: enable_example,
 		Run: func(cmd *cobra.Command, args []string) {
+			if opts.interactive && len(args) == 0 && len(opts.filename) == 0 {
+				name, err := opts.pickTargetName(cmdOut, config)
+				if err != nil {
+					klog.Fatalf("Error: %v", err)
+				}
+				args = []string{name}
+			}
+
 			err := opts.Complete(args)
 			if err != nil {
 				klog.Fatalf("Error: %v", err)
@@ -116,6 +141,7 @@ func NewCmdTypeEnable(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
 	flags := cmd.Flags()
 	opts.GlobalSubcommandBind(flags)
 	opts.CommonSubcommandBind(flags, federatedGroupUsage, targetVersionUsage)
+	opts.CompatibilityBind(flags)
 	opts.Bind(flags)
 
 	return cmd
@@ -131,6 +157,12 @@ func (j *enableType) Complete(args []string) error {
 	} else if len(j.output) > 0 {
 		return errors.Errorf("Invalid value for --output: %s", j.output)
 	}
+	if j.simulate && j.outputYAML {
+		return errors.New("--simulate and --output are mutually exclusive")
+	}
+	if len(j.outputDir) > 0 && !j.simulate {
+		return errors.New("--output-dir requires --simulate")
+	}
 
 	if len(j.filename) > 0 {
 		err := DecodeYAMLFromFile(j.filename, fd)
@@ -159,6 +191,20 @@ func (j *enableType) Complete(args []string) error {
 	return nil
 }
 
+// pickTargetName prompts for the name of the type to enable from the
+// API resources of the host cluster.
+func (j *enableType) pickTargetName(cmdOut io.Writer, config util.FedConfig) (string, error) {
+	hostConfig, err := config.HostConfig(j.HostClusterContext, j.Kubeconfig)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to get host cluster config")
+	}
+	names, err := APIResourceNames(hostConfig)
+	if err != nil {
+		return "", err
+	}
+	return util.Pick(os.Stdin, cmdOut, "Type to enable", names)
+}
+
 // Run is the implementation of the `enable` command.
 func (j *enableType) Run(cmdOut io.Writer, config util.FedConfig) error {
 	hostConfig, err := config.HostConfig(j.HostClusterContext, j.Kubeconfig)
@@ -182,6 +228,16 @@ func (j *enableType) Run(cmdOut io.Writer, config util.FedConfig) error {
 		return nil
 	}
 
+	// The generated CRD may use schema features that an older
+	// control plane does not understand.
+	if err := j.CheckCompatibility(hostConfig, j.KubeFedNamespace); err != nil {
+		return err
+	}
+
+	if j.simulate {
+		return SimulateResources(cmdOut, hostConfig, resources, j.KubeFedNamespace, j.outputDir)
+	}
+
 	return CreateResources(cmdOut, hostConfig, resources, j.KubeFedNamespace, j.DryRun)
 }
 
@@ -196,6 +252,9 @@ func GetResources(config *rest.Config, enableTypeDirective *EnableTypeDirective)
 		return nil, err
 	}
 	klog.V(2).Infof("Found type %q", resourceKey(*apiResource))
+	if !IsPropagatableAPIResource(*apiResource) {
+		return nil, errors.Errorf("Type %q cannot be propagated since it does not support the verbs: %s", resourceKey(*apiResource), strings.Join(missingPropagationVerbs(*apiResource), ", "))
+	}
 
 	typeConfig := GenerateTypeConfigForTarget(*apiResource, enableTypeDirective)
 
@@ -382,11 +441,39 @@ func GenerateTypeConfigForTarget(apiResource metav1.APIResource, enableTypeDirec
 		},
 	}
 
+	if typeConfig.Name == "daemonsets.apps" {
+		enableDaemonSetStatusCollection(typeConfig)
+	}
+
 	// Set defaults that would normally be set by the api
 	fedv1b1.SetFederatedTypeConfigDefaults(typeConfig)
 	return typeConfig
 }
 
+// enableDaemonSetStatusCollection configures the collection of the
+// node counts of DaemonSets in member clusters into
+// FederatedDaemonSetStatus resources. Since DaemonSets are placed
+// rather than scheduled, their node counts are the only indication of
+// how widely they are running.
+func enableDaemonSetStatusCollection(typeConfig *fedv1b1.FederatedTypeConfig) {
+	statusCollection := fedv1b1.StatusCollectionEnabled
+	typeConfig.Spec.StatusCollection = &statusCollection
+	typeConfig.Spec.StatusType = &fedv1b1.APIResource{
+		Group:      "core.kubefed.io",
+		Version:    "v1alpha1",
+		Kind:       "FederatedDaemonSetStatus",
+		PluralName: "federateddaemonsetstatuses",
+		Scope:      apiextv1b1.NamespaceScoped,
+	}
+	typeConfig.Spec.StatusFields = []string{
+		"desiredNumberScheduled",
+		"currentNumberScheduled",
+		"updatedNumberScheduled",
+		"numberReady",
+		"numberAvailable",
+	}
+}
+
 func qualifiedAPIResourceName(resource metav1.APIResource) string {
 	if resource.Group == "" {
 		return fmt.Sprintf("%s/%s", resource.Name, resource.Version)
@@ -397,9 +484,60 @@ func qualifiedAPIResourceName(resource metav1.APIResource) string {
 func federatedTypeCRD(typeConfig typeconfig.Interface, accessor schemaAccessor, shortNames []string) *apiextv1b1.CustomResourceDefinition {
 	templateSchema := accessor.templateSchema()
 	schema := federatedTypeValidationSchema(templateSchema)
+	if targetType := typeConfig.GetTargetType(); targetType.Group == "batch" && targetType.Kind == ctlutil.JobKind {
+		addJobCompletionSchema(schema)
+	}
 	return CrdForAPIResource(typeConfig.GetFederatedType(), schema, shortNames)
 }
 
+// addJobCompletionSchema adds the completion of Jobs recorded when the
+// deletion of a FederatedJob is propagated in the foreground to the
+// status of the given validation schema.
+func addJobCompletionSchema(schema *apiextv1b1.CustomResourceValidation) {
+	statusSchema := schema.OpenAPIV3Schema.Properties["status"]
+	statusSchema.Properties["jobCompletion"] = apiextv1b1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextv1b1.JSONSchemaProps{
+			"succeeded": {
+				Format: "int64",
+				Type:   "integer",
+			},
+			"failed": {
+				Format: "int64",
+				Type:   "integer",
+			},
+			"clusters": {
+				Type: "array",
+				Items: &apiextv1b1.JSONSchemaPropsOrArray{
+					Schema: &apiextv1b1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextv1b1.JSONSchemaProps{
+							"name": {
+								Type: "string",
+							},
+							"succeeded": {
+								Format: "int64",
+								Type:   "integer",
+							},
+							"failed": {
+								Format: "int64",
+								Type:   "integer",
+							},
+							"finished": {
+								Type: "boolean",
+							},
+						},
+						Required: []string{
+							"name",
+						},
+					},
+				},
+			},
+		},
+	}
+	schema.OpenAPIV3Schema.Properties["status"] = statusSchema
+}
+
 func writeObjectsToYAML(objects []pkgruntime.Object, w io.Writer) error {
 	for _, obj := range objects {
 		if _, err := w.Write([]byte("---\n")); err != nil {
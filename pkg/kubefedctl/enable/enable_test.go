@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1a1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+)
+
+func TestGenerateTypeConfigForDaemonSets(t *testing.T) {
+	apiResource := metav1.APIResource{
+		Group:      "apps",
+		Version:    "v1",
+		Kind:       "DaemonSet",
+		Name:       "daemonsets",
+		Namespaced: true,
+	}
+	typeConfig := GenerateTypeConfigForTarget(apiResource, NewEnableTypeDirective())
+
+	if !typeConfig.GetStatusEnabled() {
+		t.Fatalf("Expected status collection to be enabled for %q", typeConfig.GetObjectMeta().Name)
+	}
+	statusType := typeConfig.GetStatusType()
+	if statusType == nil {
+		t.Fatalf("Expected a status type")
+	}
+	if statusType.Kind != "FederatedDaemonSetStatus" || statusType.Name != "federateddaemonsetstatuses" || !statusType.Namespaced {
+		t.Errorf("Expected the namespaced FederatedDaemonSetStatus type, got %v", statusType)
+	}
+
+	// The collected fields need to be fields of the status of a
+	// DaemonSet that are recorded as node counts.
+	nodeCounts, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&fedv1a1.DaemonSetNodeCounts{
+		DesiredNumberScheduled: 1,
+		CurrentNumberScheduled: 1,
+		UpdatedNumberScheduled: 1,
+		NumberReady:            1,
+		NumberAvailable:        1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	daemonSetStatus, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&appsv1.DaemonSetStatus{
+		DesiredNumberScheduled: 1,
+		CurrentNumberScheduled: 1,
+		UpdatedNumberScheduled: 1,
+		NumberReady:            1,
+		NumberAvailable:        1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	statusFields := sets.NewString(typeConfig.GetStatusFields()...)
+	for field := range nodeCounts {
+		if !statusFields.Has(field) {
+			t.Errorf("Expected node count %q to be collected", field)
+		}
+	}
+	for _, field := range statusFields.List() {
+		if _, ok := nodeCounts[field]; !ok {
+			t.Errorf("Collected field %q is not a node count", field)
+		}
+		if _, ok := daemonSetStatus[field]; !ok {
+			t.Errorf("Collected field %q is not a field of the status of a DaemonSet", field)
+		}
+	}
+}
+
+func TestGenerateTypeConfigWithoutStatusCollection(t *testing.T) {
+	apiResource := metav1.APIResource{
+		Group:      "apps",
+		Version:    "v1",
+		Kind:       "Deployment",
+		Name:       "deployments",
+		Namespaced: true,
+	}
+	typeConfig := GenerateTypeConfigForTarget(apiResource, NewEnableTypeDirective())
+
+	if typeConfig.GetStatusEnabled() {
+		t.Errorf("Expected status collection to be disabled for %q", typeConfig.GetObjectMeta().Name)
+	}
+	if typeConfig.GetStatusType() != nil {
+		t.Errorf("Expected no status type, got %v", typeConfig.GetStatusType())
+	}
+}
@@ -25,13 +25,22 @@ import (
 	apiextv1b1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog"
 	"k8s.io/kube-openapi/pkg/util/proto"
 	"k8s.io/kubectl/pkg/util/openapi"
 )
 
+var apiServiceGVR = schema.GroupVersionResource{
+	Group:    "apiregistration.k8s.io",
+	Version:  "v1",
+	Resource: "apiservices",
+}
+
 type schemaAccessor interface {
 	templateSchema() map[string]apiextv1b1.JSONSchemaProps
 }
@@ -45,7 +54,82 @@ func newSchemaAccessor(config *rest.Config, apiResource metav1.APIResource) (sch
 	if crdAccessor != nil {
 		return crdAccessor, nil
 	}
-	return newOpenAPISchemaAccessor(config, apiResource)
+	openAPIAccessor, err := newOpenAPISchemaAccessor(config, apiResource)
+	if err != nil {
+		return nil, err
+	}
+	if openAPIAccessor != nil {
+		return openAPIAccessor, nil
+	}
+
+	// Aggregated apiservers are not required to publish an OpenAPI
+	// schema, in which case the template is not validated.
+	aggregated, err := isAggregatedAPI(config, apiResource)
+	if err != nil {
+		return nil, err
+	}
+	if !aggregated {
+		return nil, errors.Errorf("Unable to find openapi schema for %q", resourceKey(apiResource))
+	}
+	klog.Warningf("The aggregated API serving %q does not publish an openapi schema. The template of the federated type will not be validated.", resourceKey(apiResource))
+	return &schemalessAccessor{}, nil
+}
+
+// isAggregatedAPI returns whether the given resource is served by an
+// aggregated apiserver rather than by the kube-apiserver itself.
+func isAggregatedAPI(config *rest.Config, apiResource metav1.APIResource) (bool, error) {
+	if len(apiResource.Group) == 0 {
+		return false, nil
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to create dynamic client")
+	}
+	name := fmt.Sprintf("%s.%s", apiResource.Version, apiResource.Group)
+	apiService, err := client.Resource(apiServiceGVR).Get(name, metav1.GetOptions{})
+	// A caller that may not read APIServices is treated as it was
+	// before aggregated APIs were supported.
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "Error attempting retrieval of APIService %q", name)
+	}
+	// The APIServices of the groups served by the kube-apiserver do
+	// not reference a service.
+	service, _, err := unstructured.NestedMap(apiService.Object, "spec", "service")
+	if err != nil {
+		return false, errors.Wrapf(err, "Error reading the service of APIService %q", name)
+	}
+	return service != nil, nil
+}
+
+// schemalessAccessor provides no schema for the template of a type
+// whose apiserver does not publish one.
+type schemalessAccessor struct{}
+
+func (a *schemalessAccessor) templateSchema() map[string]apiextv1b1.JSONSchemaProps {
+	return nil
+}
+
+// FederatedTypeSchema returns the schema of the federated type of the
+// given target type. Unlike the schema of the generated CRD, whose
+// template is an arbitrary object, the template is described by the
+// schema of the target type.
+func FederatedTypeSchema(config *rest.Config, targetAPIResource metav1.APIResource) (*apiextv1b1.JSONSchemaProps, error) {
+	accessor, err := newSchemaAccessor(config, targetAPIResource)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error initializing validation schema accessor")
+	}
+	templateSchema := accessor.templateSchema()
+	schema := federatedTypeValidationSchema(templateSchema).OpenAPIV3Schema
+	if templateSchema != nil {
+		schema.Properties["spec"].Properties["template"] = apiextv1b1.JSONSchemaProps{
+			Type:       "object",
+			Properties: templateSchema,
+		}
+	}
+	return schema, nil
 }
 
 type crdSchemaAccessor struct {
@@ -100,7 +184,7 @@ func newOpenAPISchemaAccessor(config *rest.Config, apiResource metav1.APIResourc
 	}
 	targetResource := resources.LookupResource(gvk)
 	if targetResource == nil {
-		return nil, errors.Errorf("Unable to find openapi schema for %q", gvk)
+		return nil, nil
 	}
 	return &openAPISchemaAccessor{
 		targetResource: targetResource,
@@ -131,8 +215,9 @@ type jsonSchemaVistor struct {
 
 func (v *jsonSchemaVistor) VisitArray(a *proto.Array) {
 	arraySchema := apiextv1b1.JSONSchemaProps{
-		Type:  "array",
-		Items: &apiextv1b1.JSONSchemaPropsOrArray{},
+		Type:        "array",
+		Description: a.GetDescription(),
+		Items:       &apiextv1b1.JSONSchemaPropsOrArray{},
 	}
 	localVisitor := &jsonSchemaVistor{
 		collect: func(schema apiextv1b1.JSONSchemaProps) {
@@ -145,7 +230,8 @@ func (v *jsonSchemaVistor) VisitArray(a *proto.Array) {
 
 func (v *jsonSchemaVistor) VisitMap(m *proto.Map) {
 	mapSchema := apiextv1b1.JSONSchemaProps{
-		Type: "object",
+		Type:        "object",
+		Description: m.GetDescription(),
 		AdditionalProperties: &apiextv1b1.JSONSchemaPropsOrBool{
 			Allows: true,
 		},
@@ -166,9 +252,10 @@ func (v *jsonSchemaVistor) VisitPrimitive(p *proto.Primitive) {
 
 func (v *jsonSchemaVistor) VisitKind(k *proto.Kind) {
 	kindSchema := apiextv1b1.JSONSchemaProps{
-		Type:       "object",
-		Properties: make(map[string]apiextv1b1.JSONSchemaProps),
-		Required:   k.RequiredFields,
+		Type:        "object",
+		Description: k.GetDescription(),
+		Properties:  make(map[string]apiextv1b1.JSONSchemaProps),
+		Required:    k.RequiredFields,
 	}
 	for key, fieldSchema := range k.Fields {
 		// Status cannot be defined for a template
@@ -194,11 +281,24 @@ func (v *jsonSchemaVistor) VisitReference(r proto.Reference) {
 		return
 	}
 
-	r.SubSchema().Accept(v)
+	// The description of a field that references a definition is
+	// specific to the field and takes precedence over the
+	// description of the definition.
+	localVisitor := &jsonSchemaVistor{
+		collect: func(schema apiextv1b1.JSONSchemaProps) {
+			if description := r.GetDescription(); len(description) > 0 {
+				schema.Description = description
+			}
+			v.collect(schema)
+		},
+	}
+	r.SubSchema().Accept(localVisitor)
 }
 
 func schemaForPrimitive(p *proto.Primitive) apiextv1b1.JSONSchemaProps {
-	schema := apiextv1b1.JSONSchemaProps{}
+	schema := apiextv1b1.JSONSchemaProps{
+		Description: p.GetDescription(),
+	}
 
 	if p.Format == "int-or-string" {
 		schema.AnyOf = []apiextv1b1.JSONSchemaProps{
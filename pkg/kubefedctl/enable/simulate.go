@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextv1b1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+// SimulateResources validates the FederatedTypeConfig and federated
+// type CRD that enabling a type would apply against the host cluster
+// without changing it, and writes them to outputDir, or to cmdOut if
+// outputDir is empty. Nothing is written if validation fails.
+func SimulateResources(cmdOut io.Writer, config *rest.Config, resources *typeResources, namespace, outputDir string) error {
+	concreteTypeConfig := resources.TypeConfig.(*fedv1b1.FederatedTypeConfig)
+	concreteTypeConfig.Namespace = namespace
+
+	if err := validation.ValidateFederatedTypeConfig(concreteTypeConfig, false).ToAggregate(); err != nil {
+		return errors.Wrapf(err, "Invalid FederatedTypeConfig %q", concreteTypeConfig.Name)
+	}
+
+	// A dry run detects conflicts with the types already enabled.
+	if err := CreateResources(nil, config, resources, namespace, true); err != nil {
+		return err
+	}
+
+	if err := dryRunCRD(config, resources.CRD); err != nil {
+		return err
+	}
+
+	objects := []pkgruntime.Object{concreteTypeConfig, resources.CRD}
+	if outputDir == "" {
+		return writeObjectsToYAML(objects, cmdOut)
+	}
+	filenames, err := writeObjectsToDir(objects, outputDir)
+	if err != nil {
+		return err
+	}
+	for _, filename := range filenames {
+		fmt.Fprintf(cmdOut, "Wrote %s\n", filename)
+	}
+	return nil
+}
+
+// dryRunCRD creates or updates the given CRD in the host cluster with
+// a server-side dry run so that the API server validates its schema
+// without persisting it.
+func dryRunCRD(config *rest.Config, crd *apiextv1b1.CustomResourceDefinition) error {
+	crdClient, err := apiextv1b1client.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create crd clientset")
+	}
+
+	result := &apiextv1b1.CustomResourceDefinition{}
+	existingCRD, err := crdClient.CustomResourceDefinitions().Get(crd.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		err = crdClient.RESTClient().Post().
+			Resource("customresourcedefinitions").
+			Param("dryRun", metav1.DryRunAll).
+			Body(crd).
+			Do().
+			Into(result)
+	case err != nil:
+		return errors.Wrapf(err, "Error getting CRD %q", crd.Name)
+	default:
+		existingCRD.Spec = crd.Spec
+		err = crdClient.RESTClient().Put().
+			Resource("customresourcedefinitions").
+			Name(crd.Name).
+			Param("dryRun", metav1.DryRunAll).
+			Body(existingCRD).
+			Do().
+			Into(result)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "CRD %q was rejected by the host cluster", crd.Name)
+	}
+	return nil
+}
+
+// writeObjectsToDir writes each of the given objects to a yaml file in
+// the given directory named after its kind and name, and returns the
+// paths of the files.
+func writeObjectsToDir(objects []pkgruntime.Object, dir string) ([]string, error) {
+	dir = util.ExpandPath(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create directory %q", dir)
+	}
+	filenames := []string{}
+	for _, obj := range objects {
+		filename, err := objectFilename(obj)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, filename)
+		if err := writeObjectToFile(obj, path); err != nil {
+			return nil, errors.Wrapf(err, "Failed to write %q", path)
+		}
+		filenames = append(filenames, path)
+	}
+	return filenames, nil
+}
+
+func writeObjectToFile(obj pkgruntime.Object, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeObjectToYAML(obj, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// objectFilename returns the name of the file an object is written to,
+// e.g. federatedtypeconfig.deployments.apps.yaml.
+func objectFilename(obj pkgruntime.Object) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	return fmt.Sprintf("%s.%s.yaml", strings.ToLower(kind), accessor.GetName()), nil
+}
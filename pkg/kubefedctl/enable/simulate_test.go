@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestWriteObjectsToDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubefedctl-enable")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	apiResource := metav1.APIResource{
+		Name:       "deployments",
+		Group:      "apps",
+		Version:    "v1",
+		Kind:       "Deployment",
+		Namespaced: true,
+	}
+	directive := NewEnableTypeDirective()
+	directive.Name = "deployments.apps"
+	typeConfig := GenerateTypeConfigForTarget(apiResource, directive).(*fedv1b1.FederatedTypeConfig)
+	crd := CrdForAPIResource(typeConfig.GetFederatedType(), nil, nil)
+
+	filenames, err := writeObjectsToDir([]pkgruntime.Object{typeConfig, crd}, filepath.Join(dir, "manifests"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedFilenames := []string{
+		filepath.Join(dir, "manifests", "federatedtypeconfig.deployments.apps.yaml"),
+		filepath.Join(dir, "manifests", "customresourcedefinition.federateddeployments.types.kubefed.io.yaml"),
+	}
+	if !reflect.DeepEqual(filenames, expectedFilenames) {
+		t.Fatalf("Expected files %v, got %v", expectedFilenames, filenames)
+	}
+
+	writtenTypeConfig := &fedv1b1.FederatedTypeConfig{}
+	if err := DecodeYAMLFromFile(filenames[0], writtenTypeConfig); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(writtenTypeConfig.Spec, typeConfig.Spec) {
+		t.Errorf("Expected spec %v, got %v", typeConfig.Spec, writtenTypeConfig.Spec)
+	}
+
+	writtenCRD := &apiextv1b1.CustomResourceDefinition{}
+	if err := DecodeYAMLFromFile(filenames[1], writtenCRD); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if writtenCRD.Name != crd.Name || writtenCRD.Spec.Names.Kind != "FederatedDeployment" {
+		t.Errorf("Expected CRD %q for FederatedDeployment, got %q for %q", crd.Name, writtenCRD.Name, writtenCRD.Spec.Names.Kind)
+	}
+	data, err := ioutil.ReadFile(filenames[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.HasPrefix(string(data), "---") {
+		t.Errorf("Expected a single document without a separator")
+	}
+}
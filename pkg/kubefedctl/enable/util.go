@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -27,16 +28,23 @@ import (
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/common"
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
 )
 
+// propagationVerbs are the verbs the sync controller requires of a
+// target type.
+var propagationVerbs = []string{"create", "delete", "get", "list", "update", "watch"}
+
 func DecodeYAMLFromFile(filename string, obj interface{}) error {
-	f, err := os.Open(filename)
+	f, err := os.Open(util.ExpandPath(filename))
 	if err != nil {
 		return err
 	}
@@ -83,10 +91,14 @@ func CrdForAPIResource(apiResource metav1.APIResource, validation *apiextv1b1.Cu
 }
 
 func LookupAPIResource(config *rest.Config, key, targetVersion string) (*metav1.APIResource, error) {
-	resourceLists, err := GetServerPreferredResources(config)
+	resourceLists, failedGroups, err := getServerPreferredResources(config)
 	if err != nil {
 		return nil, err
 	}
+	return lookupAPIResource(resourceLists, failedGroups, key, targetVersion)
+}
+
+func lookupAPIResource(resourceLists []*metav1.APIResourceList, failedGroups map[schema.GroupVersion]error, key, targetVersion string) (*metav1.APIResource, error) {
 
 	var targetResource *metav1.APIResource
 	var matchedResources []string
@@ -120,9 +132,64 @@ func LookupAPIResource(config *rest.Config, key, targetVersion string) (*metav1.
 		return targetResource, nil
 	}
 
+	// The resource may be served by an aggregated apiserver that is
+	// not currently available.
+	if len(failedGroups) > 0 {
+		var groupVersions []string
+		for gv := range failedGroups {
+			groupVersions = append(groupVersions, gv.String())
+		}
+		sort.Strings(groupVersions)
+		return nil, errors.Errorf("Unable to find api resource named %q. The resources of the following group versions could not be discovered: %s", key, strings.Join(groupVersions, ", "))
+	}
+
 	return nil, errors.Errorf("Unable to find api resource named %q.", key)
 }
 
+// IsPropagatableAPIResource returns whether the verbs of the given
+// resource allow it to be propagated by the sync controller. Some
+// aggregated APIs (e.g. metrics.k8s.io) only serve reads.
+func IsPropagatableAPIResource(apiResource metav1.APIResource) bool {
+	verbs := sets.NewString(apiResource.Verbs...)
+	return verbs.HasAll(propagationVerbs...)
+}
+
+func missingPropagationVerbs(apiResource metav1.APIResource) []string {
+	return sets.NewString(propagationVerbs...).Difference(sets.NewString(apiResource.Verbs...)).List()
+}
+
+// APIResourceNames returns the group-qualified plural names of the
+// API resources of the given cluster that can be enabled for
+// propagation. Subresources, federated resources and resources of the
+// deprecated extensions group are omitted, as are resources that do
+// not support the verbs required for propagation.
+func APIResourceNames(config *rest.Config) ([]string, error) {
+	resourceLists, err := GetServerPreferredResources(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error parsing GroupVersion")
+		}
+		if gv.Group == "extensions" {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || util.IsFederatedAPIResource(resource.Kind, gv.Group) ||
+				!IsPropagatableAPIResource(resource) {
+				continue
+			}
+			names = append(names, groupQualifiedName(resource.Name, gv.Group))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 func NameMatchesResource(name string, apiResource metav1.APIResource, group string) bool {
 	lowerCaseName := strings.ToLower(name)
 	if lowerCaseName == apiResource.Name ||
@@ -140,18 +207,39 @@ func NameMatchesResource(name string, apiResource metav1.APIResource, group stri
 	return false
 }
 
+// GetServerPreferredResources returns the preferred resources of the
+// given cluster. The resources of group versions whose discovery
+// failed (e.g. those of an unavailable aggregated apiserver) are
+// omitted rather than failing the listing.
 func GetServerPreferredResources(config *rest.Config) ([]*metav1.APIResourceList, error) {
+	resourceLists, _, err := getServerPreferredResources(config)
+	return resourceLists, err
+}
+
+func getServerPreferredResources(config *rest.Config) ([]*metav1.APIResourceList, map[schema.GroupVersion]error, error) {
 	// TODO(marun) Consider using a caching scheme ala kubectl
 	client, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error creating discovery client")
+		return nil, nil, errors.Wrap(err, "Error creating discovery client")
 	}
+	return partialDiscoveryResult(client.ServerPreferredResources())
+}
 
-	resourceLists, err := client.ServerPreferredResources()
-	if err != nil {
-		return nil, errors.Wrap(err, "Error listing api resources")
+// partialDiscoveryResult tolerates the failure to discover individual
+// group versions, returning the resources that could be discovered
+// and the errors of the group versions that could not.
+func partialDiscoveryResult(resourceLists []*metav1.APIResourceList, err error) ([]*metav1.APIResourceList, map[schema.GroupVersion]error, error) {
+	if err == nil {
+		return resourceLists, nil, nil
+	}
+	if !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, nil, errors.Wrap(err, "Error listing api resources")
+	}
+	failedGroups := err.(*discovery.ErrGroupDiscoveryFailed).Groups
+	for gv, groupErr := range failedGroups {
+		klog.Warningf("Unable to discover the resources of %q: %v", gv, groupErr)
 	}
-	return resourceLists, nil
+	return resourceLists, failedGroups, nil
 }
 
 func NamespacedToScope(apiResource metav1.APIResource) apiextv1b1.ResourceScope {
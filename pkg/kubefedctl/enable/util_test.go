@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+var allVerbs = []string{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}
+
+func TestPartialDiscoveryResult(t *testing.T) {
+	resourceLists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: allVerbs},
+			},
+		},
+	}
+	metricsGV := schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+	groupErr := &discovery.ErrGroupDiscoveryFailed{
+		Groups: map[schema.GroupVersion]error{
+			metricsGV: errors.New("the server is currently unable to handle the request"),
+		},
+	}
+
+	lists, failedGroups, err := partialDiscoveryResult(resourceLists, groupErr)
+	if err != nil {
+		t.Fatalf("Unexpected error for a partial discovery failure: %v", err)
+	}
+	if len(lists) != 1 {
+		t.Fatalf("Expected the discovered resources to be returned, got %d lists", len(lists))
+	}
+	if _, ok := failedGroups[metricsGV]; !ok {
+		t.Fatalf("Expected %q to be reported as failed", metricsGV)
+	}
+
+	_, _, err = partialDiscoveryResult(nil, errors.New("connection refused"))
+	if err == nil {
+		t.Fatalf("Expected an error for a complete discovery failure")
+	}
+}
+
+func TestLookupAPIResource(t *testing.T) {
+	resourceLists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "wardle.example.com/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "flunders", SingularName: "flunder", Kind: "Flunder", Namespaced: true, Verbs: allVerbs},
+			},
+		},
+	}
+	failedGroups := map[schema.GroupVersion]error{
+		{Group: "metrics.k8s.io", Version: "v1beta1"}: errors.New("the server is currently unable to handle the request"),
+	}
+
+	resource, err := lookupAPIResource(resourceLists, failedGroups, "flunders", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resource.Group != "wardle.example.com" || resource.Version != "v1alpha1" {
+		t.Fatalf("Unexpected group version %s/%s", resource.Group, resource.Version)
+	}
+
+	_, err = lookupAPIResource(resourceLists, failedGroups, "nodes.metrics.k8s.io", "")
+	if err == nil {
+		t.Fatalf("Expected an error for a resource that could not be discovered")
+	}
+	if !strings.Contains(err.Error(), "metrics.k8s.io/v1beta1") {
+		t.Fatalf("Expected the error to name the group version that could not be discovered, got: %v", err)
+	}
+}
+
+func TestIsPropagatableAPIResource(t *testing.T) {
+	testCases := map[string]struct {
+		verbs    []string
+		expected bool
+	}{
+		"All verbs": {
+			verbs:    allVerbs,
+			expected: true,
+		},
+		"Read-only": {
+			verbs:    []string{"get", "list"},
+			expected: false,
+		},
+		"No update": {
+			verbs:    []string{"create", "delete", "get", "list", "watch"},
+			expected: false,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			apiResource := metav1.APIResource{Name: "widgets", Verbs: tc.verbs}
+			if actual := IsPropagatableAPIResource(apiResource); actual != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFederatedTypeValidationSchemaWithoutTemplateSchema(t *testing.T) {
+	schema := federatedTypeValidationSchema(nil)
+	specProperties := schema.OpenAPIV3Schema.Properties["spec"].Properties
+	for _, field := range []string{"template", util.TemplateRefField} {
+		if _, ok := specProperties[field]; !ok {
+			t.Fatalf("Expected field %q to be declared in the absence of a template schema", field)
+		}
+	}
+	if _, ok := specProperties[util.RetainReplicasField]; ok {
+		t.Fatalf("Expected field %q to be omitted in the absence of a template schema", util.RetainReplicasField)
+	}
+}
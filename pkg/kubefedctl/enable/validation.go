@@ -49,47 +49,118 @@ func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaP
 							},
 						},
 					},
-					"clusterSelector": {
-						Type: "object",
-						Properties: map[string]v1beta1.JSONSchemaProps{
-							"matchExpressions": {
-								Type: "array",
-								Items: &v1beta1.JSONSchemaPropsOrArray{
-									Schema: &v1beta1.JSONSchemaProps{
-										Type: "object",
-										Properties: map[string]v1beta1.JSONSchemaProps{
-											"key": {
-												Type: "string",
-											},
-											"operator": {
-												Type: "string",
-											},
-											"values": {
-												Type: "array",
-												Items: &v1beta1.JSONSchemaPropsOrArray{
-													Schema: &v1beta1.JSONSchemaProps{
-														Type: "string",
-													},
-												},
-											},
-										},
-										Required: []string{
-											"key",
-											"operator",
-										},
+					// References to one or more ClusterGroup resources
+					// in the KubeFed system namespace whose members
+					// should be selected. Ignored if clusters is set.
+					"clusterGroups": {
+						Type: "array",
+						Items: &v1beta1.JSONSchemaPropsOrArray{
+							Schema: &v1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
+					"clusterSelector": labelSelectorSchema(),
+					// A label selector that clusters must match to be
+					// selected, regardless of whether they are selected
+					// by clusters, clusterGroups or clusterSelector.
+					"requiredClusterSelector": labelSelectorSchema(),
+					// Weighted label selectors for the clusters that
+					// the replica scheduler should favor. Only
+					// applies with a ReplicaSchedulingPreference.
+					"preferredClusterSelectors": {
+						Type: "array",
+						Items: &v1beta1.JSONSchemaPropsOrArray{
+							Schema: &v1beta1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]v1beta1.JSONSchemaProps{
+									"weight": {
+										Type:   "integer",
+										Format: "int64",
 									},
+									"preference": labelSelectorSchema(),
+								},
+								Required: []string{
+									"preference",
+									"weight",
 								},
 							},
-							"matchLabels": {
+						},
+					},
+					// Prefixes and suffixes of the names of the
+					// resource in member clusters, keyed by cluster
+					// name.
+					"nameTemplates": {
+						Type: "object",
+						AdditionalProperties: &v1beta1.JSONSchemaPropsOrBool{
+							Schema: &v1beta1.JSONSchemaProps{
 								Type: "object",
-								AdditionalProperties: &v1beta1.JSONSchemaPropsOrBool{
-									Schema: &v1beta1.JSONSchemaProps{
+								Properties: map[string]v1beta1.JSONSchemaProps{
+									"prefix": {
+										Type: "string",
+									},
+									"suffix": {
 										Type: "string",
 									},
 								},
 							},
 						},
 					},
+					// Namespaces to propagate to in member clusters,
+					// keyed by cluster name.
+					"namespaceMapping": {
+						Type: "object",
+						AdditionalProperties: &v1beta1.JSONSchemaPropsOrBool{
+							Schema: &v1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
+					// Additional namespaces of the host cluster that
+					// the resource is projected to.
+					"namespaces": {
+						Type: "array",
+						Items: &v1beta1.JSONSchemaPropsOrArray{
+							Schema: &v1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
+					// A label selector for additional namespaces of
+					// the host cluster that the resource is projected
+					// to.
+					"namespaceSelector": labelSelectorSchema(),
+					// Names of clusters in the order in which the
+					// resource is propagated to them.
+					"order": {
+						Type: "array",
+						Items: &v1beta1.JSONSchemaPropsOrArray{
+							Schema: &v1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
+					// Names of CustomResourceDefinitions that must be
+					// installed in a cluster for it to be selected.
+					"requiredCRDs": {
+						Type: "array",
+						Items: &v1beta1.JSONSchemaPropsOrArray{
+							Schema: &v1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
+					// Names of FederatedPersistentVolumeClaims in the
+					// namespace of the resource that must be placed in a
+					// cluster for it to be selected.
+					"volumeClaims": {
+						Type: "array",
+						Items: &v1beta1.JSONSchemaPropsOrArray{
+							Schema: &v1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
 				},
 			},
 			"overrides": {
@@ -137,6 +208,29 @@ func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaP
 													},
 												},
 											},
+											"valueFrom": {
+												Type: "object",
+												Properties: map[string]v1beta1.JSONSchemaProps{
+													"configMapKeyRef": {
+														Type: "object",
+														Properties: map[string]v1beta1.JSONSchemaProps{
+															"key": {
+																Type: "string",
+															},
+															"name": {
+																Type: "string",
+															},
+															"namespace": {
+																Type: "string",
+															},
+														},
+														Required: []string{
+															"name",
+															"key",
+														},
+													},
+												},
+											},
 										},
 										Required: []string{
 											"path",
@@ -148,30 +242,128 @@ func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaP
 					},
 				},
 			},
+			// References to other resources propagated to the same
+			// member cluster that own the resource. The uid of each
+			// owner is resolved in the member cluster.
+			"ownerReferences": {
+				Type: "array",
+				Items: &v1beta1.JSONSchemaPropsOrArray{
+					Schema: &v1beta1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"apiVersion": {
+								Type: "string",
+							},
+							"blockOwnerDeletion": {
+								Type: "boolean",
+							},
+							"controller": {
+								Type: "boolean",
+							},
+							"kind": {
+								Type: "string",
+							},
+							"name": {
+								Type: "string",
+							},
+						},
+						Required: []string{
+							"apiVersion",
+							"kind",
+							"name",
+						},
+					},
+				},
+			},
 		},
 	})
-	if templateSchema != nil {
-		specProperties := schema.OpenAPIV3Schema.Properties["spec"].Properties
-		specProperties["template"] = v1beta1.JSONSchemaProps{
-			Type: "object",
-		}
-		// Add retainReplicas field to types that exposes a replicas
-		// field that could be targeted by HPA.
-		if templateSpec, ok := templateSchema["spec"]; ok {
-			// TODO: find a simpler way to detect that a resource is scalable than having to compute the entire schema.
-			if replicasField, ok := templateSpec.Properties["replicas"]; ok {
-				if replicasField.Type == "integer" && replicasField.Format == "int32" {
-					specProperties[util.RetainReplicasField] = v1beta1.JSONSchemaProps{
-						Type: "boolean",
-					}
+	// The template is declared even for types whose schema is not
+	// known (e.g. types of aggregated APIs that do not publish one).
+	specProperties := schema.OpenAPIV3Schema.Properties["spec"].Properties
+	specProperties["template"] = v1beta1.JSONSchemaProps{
+		Type: "object",
+	}
+	// A reference to a FederatedTemplate that provides the
+	// template in place of the template field.
+	specProperties[util.TemplateRefField] = v1beta1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1beta1.JSONSchemaProps{
+			"name": {
+				Type: "string",
+			},
+			"parameters": {
+				Type: "object",
+				AdditionalProperties: &v1beta1.JSONSchemaPropsOrBool{
+					Schema: &v1beta1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+		},
+		Required: []string{
+			"name",
+		},
+	}
+	// Add retainReplicas field to types that exposes a replicas
+	// field that could be targeted by HPA.
+	if templateSpec, ok := templateSchema["spec"]; ok {
+		// TODO: find a simpler way to detect that a resource is scalable than having to compute the entire schema.
+		if replicasField, ok := templateSpec.Properties["replicas"]; ok {
+			if replicasField.Type == "integer" && replicasField.Format == "int32" {
+				specProperties[util.RetainReplicasField] = v1beta1.JSONSchemaProps{
+					Type: "boolean",
 				}
 			}
 		}
-
 	}
 	return schema
 }
 
+// labelSelectorSchema returns the schema of a metav1.LabelSelector.
+func labelSelectorSchema() v1beta1.JSONSchemaProps {
+	return v1beta1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1beta1.JSONSchemaProps{
+			"matchExpressions": {
+				Type: "array",
+				Items: &v1beta1.JSONSchemaPropsOrArray{
+					Schema: &v1beta1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"key": {
+								Type: "string",
+							},
+							"operator": {
+								Type: "string",
+							},
+							"values": {
+								Type: "array",
+								Items: &v1beta1.JSONSchemaPropsOrArray{
+									Schema: &v1beta1.JSONSchemaProps{
+										Type: "string",
+									},
+								},
+							},
+						},
+						Required: []string{
+							"key",
+							"operator",
+						},
+					},
+				},
+			},
+			"matchLabels": {
+				Type: "object",
+				AdditionalProperties: &v1beta1.JSONSchemaPropsOrBool{
+					Schema: &v1beta1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+		},
+	}
+}
+
 func ValidationSchema(specProps v1beta1.JSONSchemaProps) *v1beta1.CustomResourceValidation {
 	return &v1beta1.CustomResourceValidation{
 		OpenAPIV3Schema: &v1beta1.JSONSchemaProps{
@@ -230,6 +422,10 @@ func ValidationSchema(specProps v1beta1.JSONSchemaProps) *v1beta1.CustomResource
 										"name": {
 											Type: "string",
 										},
+										"generation": {
+											Format: "int64",
+											Type:   "integer",
+										},
 										"status": {
 											Type: "string",
 										},
@@ -240,10 +436,61 @@ func ValidationSchema(specProps v1beta1.JSONSchemaProps) *v1beta1.CustomResource
 								},
 							},
 						},
+						"clusterHealth": {
+							Type: "array",
+							Items: &v1beta1.JSONSchemaPropsOrArray{
+								Schema: &v1beta1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]v1beta1.JSONSchemaProps{
+										"name": {
+											Type: "string",
+										},
+										"health": {
+											Type: "string",
+										},
+									},
+									Required: []string{
+										"name",
+										"health",
+									},
+								},
+							},
+						},
+						"deletionHeldUntil": {
+							Format: "date-time",
+							Type:   "string",
+						},
 						"observedGeneration": {
 							Format: "int64",
 							Type:   "integer",
 						},
+						"placementDecisions": {
+							Type: "array",
+							Items: &v1beta1.JSONSchemaPropsOrArray{
+								Schema: &v1beta1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]v1beta1.JSONSchemaProps{
+										"name": {
+											Type: "string",
+										},
+										"selected": {
+											Type: "boolean",
+										},
+										"reason": {
+											Type: "string",
+										},
+										"message": {
+											Type: "string",
+										},
+									},
+									Required: []string{
+										"name",
+										"selected",
+										"reason",
+									},
+								},
+							},
+						},
 					},
 				},
 			},
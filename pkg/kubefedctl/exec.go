@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	exec_long = `
+		Execute a command in a container of a pod of a federated
+		workload in a member cluster. The pods are those selected by
+		the resource the federated workload propagates to the cluster
+		(e.g. the Deployment of a FederatedDeployment). Unless a pod is
+		named, the command is executed in the first running pod.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	exec_example = `
+		# List the files of the working directory of a pod of the FederatedDeployment myapp in cluster prod-eu
+		kubefedctl exec federateddeployment/myapp --cluster prod-eu -n ns1 -- ls
+
+		# Pass stdin to a command executed in container app of pod myapp-5d8f7-x2k4q in cluster prod-eu
+		kubefedctl exec federateddeployment/myapp --cluster prod-eu --pod myapp-5d8f7-x2k4q -c app -i -n ns1 -- sh -c 'cat > /tmp/input'`
+)
+
+type workloadExec struct {
+	options.GlobalSubcommandOptions
+	typeName          string
+	resourceName      string
+	resourceNamespace string
+	clusterName       string
+	podName           string
+	container         string
+	stdin             bool
+	command           []string
+}
+
+// Bind adds the exec specific arguments to the flagset passed in as an
+// argument.
+func (o *workloadExec) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of the federated workload. Defaults to the namespace of the current context.")
+	flags.StringVar(&o.clusterName, "cluster", "", "The name of the cluster to execute the command in.")
+	flags.StringVar(&o.podName, "pod", "", "The name of the pod to execute the command in. Defaults to the first running pod of the workload.")
+	flags.StringVarP(&o.container, "container", "c", "", "The container to execute the command in. Defaults to the first container of the pod.")
+	flags.BoolVarP(&o.stdin, "stdin", "i", false, "Pass stdin to the command.")
+}
+
+// NewCmdExec defines the `exec` command that executes a command in a
+// pod of a federated workload in a member cluster.
+func NewCmdExec(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &workloadExec{}
+
+	cmd := &cobra.Command{
+		Use:     "exec TYPE/NAME --cluster=CLUSTER_NAME -- COMMAND [args...]",
+		Short:   "Execute a command in a pod of a federated workload in a member cluster",
+		Long:    exec_long,
+		Example: exec_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args, cmd.ArgsLenAtDash(), config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Complete ensures that options are valid and marshals them if necessary.
+func (o *workloadExec) Complete(args []string, argsLenAtDash int, config util.FedConfig) error {
+	if argsLenAtDash < 0 || argsLenAtDash == len(args) {
+		return errors.New("a command is required after --")
+	}
+	o.command = args[argsLenAtDash:]
+
+	var err error
+	o.typeName, o.resourceName, err = parseWorkloadArgs(args[:argsLenAtDash])
+	if err != nil {
+		return err
+	}
+
+	if len(o.clusterName) == 0 {
+		return errors.New("--cluster is required")
+	}
+
+	if len(o.resourceNamespace) == 0 {
+		o.resourceNamespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		return err
+	}
+	return nil
+}
+
+// Run implements the `exec` command.
+func (o *workloadExec) Run(cmdOut io.Writer, config util.FedConfig) error {
+	workload, err := loadFederatedWorkload(config, o.GlobalSubcommandOptions, o.typeName, o.resourceName, o.resourceNamespace)
+	if err != nil {
+		return err
+	}
+	clusterPods, err := workload.clusterPods(o.clusterName)
+	if err != nil {
+		return err
+	}
+	pod, err := execPod(clusterPods.pods, o.podName)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to select a pod of %s %q in cluster %q", workload.fedObject.GetKind(), workload.qualifiedName(), o.clusterName)
+	}
+
+	request := clusterPods.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: o.container,
+			Command:   o.command,
+			Stdin:     o.stdin,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(clusterPods.clusterConfig, "POST", request.URL())
+	if err != nil {
+		return errors.Wrapf(err, "Failed to execute the command in pod %q of cluster %q", pod.Name, o.clusterName)
+	}
+
+	streamOptions := remotecommand.StreamOptions{
+		Stdout: cmdOut,
+		Stderr: os.Stderr,
+	}
+	if o.stdin {
+		streamOptions.Stdin = os.Stdin
+	}
+	return executor.Stream(streamOptions)
+}
+
+// execPod returns the named pod of the given pods, or the first
+// running pod if no name is given.
+func execPod(pods []corev1.Pod, podName string) (*corev1.Pod, error) {
+	for i := range pods {
+		pod := &pods[i]
+		if len(podName) > 0 {
+			if pod.Name == podName {
+				return pod, nil
+			}
+			continue
+		}
+		if pod.Status.Phase == corev1.PodRunning {
+			return pod, nil
+		}
+	}
+	if len(podName) > 0 {
+		return nil, errors.Errorf("pod %q does not belong to the workload", podName)
+	}
+	return nil, errors.New("no pod is running")
+}
@@ -0,0 +1,380 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	explain_long = `
+		Explain describes the fields of a federated type. Fields are
+		identified by a JSONPath-like expression of the form
+		<type>.<field>[.<field>], where the type is the plural name
+		or kind of the federated type.
+
+		In addition to the fields of the template, which are those
+		of the target type, the semantics of the placement, overrides
+		and status fields that are specific to KubeFed are described.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	explain_example = `
+		# Describe the overrides of a FederatedDeployment
+		kubefedctl explain federateddeployment.spec.overrides
+
+		# Describe the containers of the template of a FederatedDeployment
+		kubefedctl explain federateddeployment.spec.template.spec.template.spec.containers`
+
+	// federatedFieldDescriptions describes the fields common to all
+	// federated types, keyed by their path.
+	federatedFieldDescriptions = map[string]string{
+		"": "A federated resource propagates a resource of the target type to member clusters. " +
+			"The resource is created in each cluster selected by the placement from the template, " +
+			"with the overrides for the cluster applied.",
+		"apiVersion": "APIVersion defines the versioned schema of this representation of an object.",
+		"kind":       "Kind is a string value representing the REST resource this object represents.",
+		"metadata": "Standard object metadata. The labels and annotations of the federated resource are " +
+			"not propagated; those of the template are.",
+		"spec": "The desired state of the resource in member clusters.",
+		"spec.template": "The resource that is propagated to member clusters. Its name and namespace are " +
+			"those of the federated resource unless mapped by the placement. Fields of the target type " +
+			"that are managed by member clusters, such as the replicas of a scalable resource, are " +
+			"retained when the resource is updated.",
+		"spec.templateRef": "A reference to a FederatedTemplate in the KubeFed system namespace that " +
+			"provides the template. Mutually exclusive with template. Requires the FederatedTemplates " +
+			"feature.",
+		"spec.templateRef.name": "The name of the FederatedTemplate.",
+		"spec.templateRef.parameters": "Values of the parameters declared by the FederatedTemplate, " +
+			"keyed by parameter name. Each $(name) in the string values of the template is replaced " +
+			"with the value of the parameter.",
+		"spec.placement": "The member clusters the resource is propagated to. If clusters is set, " +
+			"clusterGroups and clusterSelector are ignored. If clusterGroups is set, clusterSelector is " +
+			"ignored. If none is set, the resource is only propagated to the clusters matching " +
+			"requiredClusterSelector, if set. For a namespaced resource, the " +
+			"clusters are further limited to those the FederatedNamespace of its namespace is placed in.",
+		"spec.placement.clusters":      "The names of the KubeFedClusters the resource is propagated to.",
+		"spec.placement.clusters.name": "The name of a KubeFedCluster.",
+		"spec.placement.clusterGroups": "The names of ClusterGroups in the KubeFed system namespace whose " +
+			"members the resource is propagated to. Ignored if clusters is set.",
+		"spec.placement.clusterSelector": "A label selector for the KubeFedClusters the resource is " +
+			"propagated to. An empty selector selects all clusters. Ignored if clusters or " +
+			"clusterGroups is set.",
+		"spec.placement.clusterSelector.matchExpressions": "A list of label selector requirements. " +
+			"The requirements are ANDed.",
+		"spec.placement.clusterSelector.matchExpressions.key":      "The label key that the selector applies to.",
+		"spec.placement.clusterSelector.matchExpressions.operator": "One of In, NotIn, Exists and DoesNotExist.",
+		"spec.placement.clusterSelector.matchExpressions.values": "The values of the label. Must be " +
+			"non-empty for In and NotIn, and empty for Exists and DoesNotExist.",
+		"spec.placement.clusterSelector.matchLabels": "A map of labels that selected clusters must have.",
+		"spec.placement.nameTemplates": "A prefix and suffix added to the name of the resource in a " +
+			"member cluster, keyed by cluster name. Not supported for namespaces.",
+		"spec.placement.nameTemplates.prefix": "The prefix added to the name of the resource.",
+		"spec.placement.nameTemplates.suffix": "The suffix added to the name of the resource.",
+		"spec.placement.namespaceMapping": "The namespace the resource is propagated to in a member " +
+			"cluster, keyed by cluster name.",
+		"spec.placement.namespaceSelector": "A label selector for namespaces of the host cluster that the " +
+			"resource is projected to, in addition to its own namespace. Requires the NamespaceProjection " +
+			"feature.",
+		"spec.placement.namespaces": "Namespaces of the host cluster that the resource is projected to, " +
+			"in addition to its own namespace. Requires the NamespaceProjection feature.",
+		"spec.placement.order": "The names of KubeFedClusters in the order in which the resource is " +
+			"created and updated in them, one cluster at a time. The resource is removed from them in " +
+			"reverse order. Clusters that are not listed are not ordered.",
+		"spec.placement.preferredClusterSelectors": "Weighted label selectors for the KubeFedClusters " +
+			"the replica scheduler favors. The weights of the selectors a cluster matches are added to " +
+			"its weight in the ReplicaSchedulingPreference. Only applies to resources targeted by a " +
+			"ReplicaSchedulingPreference and does not affect which clusters are selected.",
+		"spec.placement.preferredClusterSelectors.preference": "A label selector for the favored clusters.",
+		"spec.placement.preferredClusterSelectors.weight":     "The weight added to the clusters matching the preference.",
+		"spec.placement.requiredClusterSelector": "A label selector that KubeFedClusters must match to be " +
+			"selected, in addition to being selected by clusters, clusterGroups or clusterSelector. If none " +
+			"of those is set, the clusters matching the selector are selected.",
+		"spec.placement.requiredCRDs": "The names of CustomResourceDefinitions that must be installed " +
+			"in a cluster for it to be selected.",
+		"spec.placement.volumeClaims": "The names of FederatedPersistentVolumeClaims in the namespace of " +
+			"the resource. Only clusters that the claims are placed in are selected.",
+		"spec.overrides": "Changes to the template that are applied for individual clusters. Overrides " +
+			"for clusters that are not selected by the placement are ignored.",
+		"spec.overrides.clusterName":      "The name of the KubeFedCluster the overrides apply to.",
+		"spec.overrides.clusterOverrides": "The changes applied to the template for the cluster, in order.",
+		"spec.overrides.clusterOverrides.op": "The JSON patch operation of the override: add, remove " +
+			"or replace. Defaults to replace.",
+		"spec.overrides.clusterOverrides.path": "The JSON pointer to the field of the template that is " +
+			"changed, e.g. /spec/replicas.",
+		"spec.overrides.clusterOverrides.value": "The value of the field. Not validated against the " +
+			"schema of the target type, so errors are only reported in the propagation status.",
+		"spec.overrides.clusterOverrides.valueFrom": "A reference to the value of the field in the " +
+			"member cluster. Mutually exclusive with value.",
+		"spec.overrides.clusterOverrides.valueFrom.configMapKeyRef": "A key of a ConfigMap in the member " +
+			"cluster whose value is the value of the field.",
+		"spec.overrides.clusterOverrides.valueFrom.configMapKeyRef.key":       "The key of the ConfigMap.",
+		"spec.overrides.clusterOverrides.valueFrom.configMapKeyRef.name":      "The name of the ConfigMap.",
+		"spec.overrides.clusterOverrides.valueFrom.configMapKeyRef.namespace": "The namespace of the ConfigMap. Defaults to the namespace of the resource in the member cluster.",
+		"spec.ownerReferences": "References to other resources propagated to the same member cluster " +
+			"that own the resource. The uid of each owner is resolved in the member cluster.",
+		"spec.retainReplicas": "Whether the replicas of the resource in member clusters are retained " +
+			"when the resource is updated, so that they can be managed by a HorizontalPodAutoscaler " +
+			"in each cluster.",
+		"status":                               "The propagation status of the resource, written by the sync controller.",
+		"status.conditions":                    "The conditions of the resource. The Propagation condition reports whether the resource was propagated to all selected clusters. The Ready condition, recorded when the HealthInterpretation feature is enabled, reports whether it is also healthy in all of them.",
+		"status.conditions.type":               "The type of the condition.",
+		"status.conditions.status":             "The status of the condition: True, False or Unknown.",
+		"status.conditions.reason":             "The reason for the last transition of the condition.",
+		"status.conditions.lastUpdateTime":     "The last time the condition was updated.",
+		"status.conditions.lastTransitionTime": "The last time the condition transitioned from one status to another.",
+		"status.clusters": "The clusters the resource is propagated to. A cluster without a status was " +
+			"propagated to successfully.",
+		"status.clusters.name":   "The name of the KubeFedCluster.",
+		"status.clusters.status": "The reason propagation to the cluster failed or is pending.",
+		"status.clusters.generation": "The generation of the resource that the status of the cluster refers to. " +
+			"Failures of earlier generations are cleared when a new generation is observed.",
+		"status.clusterHealth": "The health of the resource in each cluster it is propagated to. Only " +
+			"recorded when the HealthInterpretation feature is enabled.",
+		"status.clusterHealth.name":   "The name of the KubeFedCluster.",
+		"status.clusterHealth.health": "The health of the resource in the cluster: Healthy, Progressing or Degraded.",
+		"status.observedGeneration":   "The generation of the resource that the status was computed for.",
+		"status.placementDecisions": "Why each cluster was selected or excluded by the placement. Only " +
+			"recorded when the PlacementDecisions feature is enabled.",
+		"status.placementDecisions.name":     "The name of the KubeFedCluster.",
+		"status.placementDecisions.selected": "Whether the cluster was selected.",
+		"status.placementDecisions.reason":   "The reason the cluster was selected or excluded.",
+		"status.placementDecisions.message":  "Details of why the cluster was selected or excluded.",
+	}
+)
+
+type explainType struct {
+	options.GlobalSubcommandOptions
+}
+
+// NewCmdExplain defines the `explain` command that describes the
+// fields of a federated type.
+func NewCmdExplain(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &explainType{}
+
+	cmd := &cobra.Command{
+		Use:     "explain TYPE[.FIELD]...",
+		Short:   "Describe the fields of a federated type",
+		Long:    explain_long,
+		Example: explain_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				klog.Fatalf("Error: exactly one TYPE[.FIELD]... argument is required")
+			}
+			err := opts.Run(cmdOut, config, args[0])
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.GlobalSubcommandBind(cmd.Flags())
+
+	return cmd
+}
+
+// Run describes the federated type field identified by the given
+// expression.
+func (o *explainType) Run(cmdOut io.Writer, config util.FedConfig, expression string) error {
+	parts := strings.Split(expression, ".")
+	typeName, fieldPath := parts[0], parts[1:]
+
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	err = client.List(context.TODO(), typeConfigs, o.KubeFedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+	typeConfig := lookupFederatedTypeConfig(typeConfigs.Items, typeName)
+	if typeConfig == nil {
+		return errors.Errorf("%q is not an enabled federated type", typeName)
+	}
+
+	fedSchema, err := enable.FederatedTypeSchema(hostConfig, typeConfig.GetTargetType())
+	if err != nil {
+		return err
+	}
+	federatedType := typeConfig.GetFederatedType()
+	gv := schema.GroupVersion{Group: federatedType.Group, Version: federatedType.Version}
+	return explainField(cmdOut, federatedType.Kind, gv.String(), fedSchema, fieldPath)
+}
+
+// lookupFederatedTypeConfig returns the type config whose federated
+// type has the given plural name or kind, ignoring case.
+func lookupFederatedTypeConfig(typeConfigs []fedv1b1.FederatedTypeConfig, name string) *fedv1b1.FederatedTypeConfig {
+	for i := range typeConfigs {
+		federatedType := typeConfigs[i].GetFederatedType()
+		if strings.EqualFold(name, federatedType.Name) || strings.EqualFold(name, federatedType.Kind) {
+			return &typeConfigs[i]
+		}
+	}
+	return nil
+}
+
+// explainField writes the description of the field of the given schema
+// at the given path, followed by the descriptions of its fields.
+func explainField(w io.Writer, kind, version string, fieldSchema *apiextv1b1.JSONSchemaProps, fieldPath []string) error {
+	var required []string
+	for i, name := range fieldPath {
+		objectSchema := elementSchema(fieldSchema)
+		property, ok := objectSchema.Properties[name]
+		if !ok {
+			return errors.Errorf("field %q does not exist", strings.Join(fieldPath[:i+1], "."))
+		}
+		required = objectSchema.Required
+		fieldSchema = &property
+	}
+
+	fmt.Fprintf(w, "KIND:     %s\n", kind)
+	fmt.Fprintf(w, "VERSION:  %s\n\n", version)
+	path := strings.Join(fieldPath, ".")
+	objectSchema := elementSchema(fieldSchema)
+	if len(fieldPath) > 0 {
+		label := "FIELD:   "
+		if len(objectSchema.Properties) > 0 {
+			label = "RESOURCE:"
+		}
+		name := fieldPath[len(fieldPath)-1]
+		fmt.Fprintf(w, "%s %s <%s>%s\n\n", label, name, schemaTypeName(fieldSchema), requiredSuffix(name, required))
+	}
+	fmt.Fprintf(w, "DESCRIPTION:\n")
+	writeDescription(w, fieldDescription(path, fieldSchema), "     ")
+
+	if len(objectSchema.Properties) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\nFIELDS:\n")
+	names := make([]string, 0, len(objectSchema.Properties))
+	for name := range objectSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property := objectSchema.Properties[name]
+		fmt.Fprintf(w, "   %s\t<%s>%s\n", name, schemaTypeName(&property), requiredSuffix(name, objectSchema.Required))
+		propertyPath := name
+		if len(path) > 0 {
+			propertyPath = path + "." + name
+		}
+		writeDescription(w, fieldDescription(propertyPath, &property), "     ")
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// elementSchema returns the schema of the elements of an array, or the
+// schema of the values of a map, and the given schema otherwise.
+func elementSchema(fieldSchema *apiextv1b1.JSONSchemaProps) *apiextv1b1.JSONSchemaProps {
+	switch {
+	case fieldSchema.Items != nil && fieldSchema.Items.Schema != nil:
+		return fieldSchema.Items.Schema
+	case fieldSchema.AdditionalProperties != nil && fieldSchema.AdditionalProperties.Schema != nil &&
+		len(fieldSchema.AdditionalProperties.Schema.Properties) > 0:
+		return fieldSchema.AdditionalProperties.Schema
+	}
+	return fieldSchema
+}
+
+// fieldDescription returns the description of the field at the given
+// path. Fields specific to federated types are described by KubeFed,
+// and fields of the template by the schema of the target type.
+func fieldDescription(path string, fieldSchema *apiextv1b1.JSONSchemaProps) string {
+	if !strings.HasPrefix(path, "spec.template.") {
+		if description, ok := federatedFieldDescriptions[path]; ok {
+			return description
+		}
+	}
+	if len(fieldSchema.Description) > 0 {
+		return fieldSchema.Description
+	}
+	return "<empty>"
+}
+
+// schemaTypeName returns the name of the type of the given schema in
+// the notation of `kubectl explain`.
+func schemaTypeName(fieldSchema *apiextv1b1.JSONSchemaProps) string {
+	switch fieldSchema.Type {
+	case "array":
+		if fieldSchema.Items != nil && fieldSchema.Items.Schema != nil {
+			return "[]" + schemaTypeName(fieldSchema.Items.Schema)
+		}
+		return "[]"
+	case "object":
+		if fieldSchema.AdditionalProperties != nil && fieldSchema.AdditionalProperties.Schema != nil {
+			return "map[string]" + schemaTypeName(fieldSchema.AdditionalProperties.Schema)
+		}
+		return "Object"
+	case "":
+		if len(fieldSchema.AnyOf) > 0 {
+			return "string"
+		}
+		return "Object"
+	}
+	return fieldSchema.Type
+}
+
+func requiredSuffix(name string, required []string) string {
+	for _, requiredName := range required {
+		if requiredName == name {
+			return " -required-"
+		}
+	}
+	return ""
+}
+
+// writeDescription writes the given description wrapped at 80
+// columns, with each line prefixed by the given indent.
+func writeDescription(w io.Writer, description, indent string) {
+	line := indent
+	for _, word := range strings.Fields(description) {
+		if len(line) > len(indent) && len(line)+1+len(word) > 80 {
+			fmt.Fprintln(w, line)
+			line = indent
+		}
+		if len(line) > len(indent) {
+			line += " "
+		}
+		line += word
+	}
+	fmt.Fprintln(w, line)
+}
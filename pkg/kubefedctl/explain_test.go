@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+)
+
+func newExplainSchema() *apiextv1b1.JSONSchemaProps {
+	return &apiextv1b1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextv1b1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextv1b1.JSONSchemaProps{
+					"template": {
+						Type: "object",
+						Properties: map[string]apiextv1b1.JSONSchemaProps{
+							"spec": {
+								Type:        "object",
+								Description: "Specification of the desired behavior of the Deployment.",
+								Properties: map[string]apiextv1b1.JSONSchemaProps{
+									"replicas": {
+										Type:        "integer",
+										Description: "Number of desired pods.",
+									},
+								},
+							},
+						},
+					},
+					"overrides": {
+						Type: "array",
+						Items: &apiextv1b1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1b1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextv1b1.JSONSchemaProps{
+									"clusterName": {Type: "string"},
+									"clusterOverrides": {
+										Type: "array",
+										Items: &apiextv1b1.JSONSchemaPropsOrArray{
+											Schema: &apiextv1b1.JSONSchemaProps{
+												Type: "object",
+												Properties: map[string]apiextv1b1.JSONSchemaProps{
+													"path": {Type: "string"},
+												},
+												Required: []string{"path"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExplainField(t *testing.T) {
+	testCases := map[string]struct {
+		fieldPath     []string
+		expectedLines []string
+	}{
+		"Federated type": {
+			fieldPath: nil,
+			expectedLines: []string{
+				"KIND:     FederatedDeployment",
+				"VERSION:  types.kubefed.io/v1beta1",
+				"   spec\t<Object>",
+			},
+		},
+		"Overrides": {
+			fieldPath: []string{"spec", "overrides"},
+			expectedLines: []string{
+				"RESOURCE: overrides <[]Object>",
+				"     Changes to the template that are applied for individual clusters.",
+				"   clusterName\t<string>",
+				"     The name of the KubeFedCluster the overrides apply to.",
+				"   clusterOverrides\t<[]Object>",
+			},
+		},
+		"Override path": {
+			fieldPath: []string{"spec", "overrides", "clusterOverrides", "path"},
+			expectedLines: []string{
+				"FIELD:    path <string> -required-",
+				"     The JSON pointer to the field of the template that is changed, e.g.",
+			},
+		},
+		"Template field": {
+			fieldPath: []string{"spec", "template", "spec"},
+			expectedLines: []string{
+				"RESOURCE: spec <Object>",
+				"     Specification of the desired behavior of the Deployment.",
+				"   replicas\t<integer>",
+				"     Number of desired pods.",
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := explainField(buf, "FederatedDeployment", "types.kubefed.io/v1beta1", newExplainSchema(), tc.fieldPath)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(buf.String(), "\n")
+			for _, expectedLine := range tc.expectedLines {
+				found := false
+				for _, line := range lines {
+					if line == expectedLine {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected line %q in output:\n%s", expectedLine, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestExplainFieldNotFound(t *testing.T) {
+	err := explainField(&bytes.Buffer{}, "FederatedDeployment", "types.kubefed.io/v1beta1", newExplainSchema(), []string{"spec", "placement"})
+	if err == nil || !strings.Contains(err.Error(), `"spec.placement"`) {
+		t.Errorf("Expected an error for a missing field, got %v", err)
+	}
+}
@@ -76,6 +76,7 @@ var (
 
 type federateResource struct {
 	options.GlobalSubcommandOptions
+	options.CompatibilityOptions
 	typeName             string
 	resourceName         string
 	resourceNamespace    string
@@ -154,6 +155,7 @@ func NewCmdFederateResource(cmdOut io.Writer, config util.FedConfig) *cobra.Comm
 
 	flags := cmd.Flags()
 	opts.GlobalSubcommandBind(flags)
+	opts.CompatibilityBind(flags)
 	opts.Bind(flags)
 
 	return cmd
@@ -227,6 +229,10 @@ func (j *federateResource) Run(cmdOut io.Writer, config util.FedConfig) error {
 		return nil
 	}
 
+	if err := j.CheckCompatibility(hostConfig, j.KubeFedNamespace); err != nil {
+		return err
+	}
+
 	return CreateResources(cmdOut, hostConfig, artifactsList, j.KubeFedNamespace, j.enableType, j.DryRun)
 }
 
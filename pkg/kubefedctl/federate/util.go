@@ -231,7 +231,7 @@ func DecodeUnstructuredFromFile(filename string) ([]*unstructured.Unstructured,
 		f = os.Stdin
 	} else {
 		var err error
-		f, err = os.Open(filename)
+		f, err = os.Open(util.ExpandPath(filename))
 
 		if err != nil {
 			return nil, err
@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	history_long = `
+		History lists the revisions of a federated resource recorded
+		each time its template and overrides were propagated to all
+		selected clusters, or shows the template and overrides of a
+		single revision. Revisions are only recorded if the
+		RevisionHistory feature is enabled.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	history_example = `
+		# List the revisions of the FederatedDeployment named foo
+		kubefedctl history federateddeployments foo -n ns1 --host-cluster-context=cluster1
+
+		# Show the template and overrides of revision 3
+		kubefedctl history federateddeployments foo --revision=3 -n ns1 --host-cluster-context=cluster1`
+
+	rollback_long = `
+		Rollback restores the template and overrides of a federated
+		resource from a revision recorded in its history, which the
+		sync controller then propagates to member clusters. The
+		placement of the resource is not changed.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	rollback_example = `
+		# Roll back the FederatedDeployment named foo to its previous revision
+		kubefedctl rollback federateddeployments foo -n ns1 --host-cluster-context=cluster1
+
+		# Roll back the FederatedDeployment named foo to revision 3
+		kubefedctl rollback federateddeployments foo --to-revision=3 -n ns1 --host-cluster-context=cluster1`
+)
+
+type revisionResource struct {
+	options.GlobalSubcommandOptions
+	typeName          string
+	resourceName      string
+	resourceNamespace string
+	revision          int64
+	rollback          bool
+}
+
+// Bind adds the history and rollback specific arguments to the
+// flagset passed in as an argument.
+func (o *revisionResource) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of the federated resource. Defaults to the namespace of the current context.")
+	if o.rollback {
+		flags.Int64Var(&o.revision, "to-revision", 0, "The revision to roll back to. Defaults to the latest revision that differs from the current template and overrides.")
+	} else {
+		flags.Int64Var(&o.revision, "revision", 0, "Show the template and overrides of the given revision.")
+	}
+}
+
+// NewCmdHistory defines the `history` command that lists the
+// revisions of a federated resource.
+func NewCmdHistory(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	return newCmdRevision(cmdOut, config, "history", "List the revisions of a federated resource",
+		history_long, history_example, false)
+}
+
+// NewCmdRollback defines the `rollback` command that restores a
+// federated resource from one of its revisions.
+func NewCmdRollback(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	return newCmdRevision(cmdOut, config, "rollback", "Roll back a federated resource to a previous revision",
+		rollback_long, rollback_example, true)
+}
+
+func newCmdRevision(cmdOut io.Writer, config util.FedConfig, verb, short, long, example string, rollback bool) *cobra.Command {
+	opts := &revisionResource{rollback: rollback}
+
+	cmd := &cobra.Command{
+		Use:     verb + " TYPE NAME",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Complete ensures that options are valid and marshals them if necessary.
+func (o *revisionResource) Complete(args []string, config util.FedConfig) error {
+	if len(args) != 2 {
+		return errors.New("a federated type and a resource name are required")
+	}
+	o.typeName = args[0]
+	o.resourceName = args[1]
+
+	if o.revision < 0 {
+		return errors.New("the revision must not be negative")
+	}
+
+	if len(o.resourceNamespace) == 0 {
+		var err error
+		o.resourceNamespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		return err
+	}
+	return nil
+}
+
+// Run implements the `history` and `rollback` commands.
+func (o *revisionResource) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	apiResource, err := enable.LookupAPIResource(hostConfig, o.typeName, "")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to find targeted %s type", o.typeName)
+	}
+	if !util.IsFederatedAPIResource(apiResource.Kind, apiResource.Group) {
+		return errors.Errorf("%s is not a federated type", o.typeName)
+	}
+	client, err := ctlutil.NewResourceClient(hostConfig, apiResource)
+	if err != nil {
+		return errors.Wrapf(err, "Error creating client for %s", apiResource.Kind)
+	}
+	resourceClient := client.Resources(o.resourceNamespace)
+	kubeClient, err := kubeclient.NewForConfig(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Error creating client for the host cluster")
+	}
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: o.resourceNamespace, Name: o.resourceName}
+	obj, err := resourceClient.Get(o.resourceName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", apiResource.Kind, qualifiedName)
+	}
+	revisions, err := ctlutil.ListRevisions(kubeClient.AppsV1(), obj, o.KubeFedNamespace)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list the revisions of %s %q", apiResource.Kind, qualifiedName)
+	}
+	current, err := ctlutil.NewRevision(obj, o.KubeFedNamespace, 0)
+	if err != nil {
+		return err
+	}
+
+	if !o.rollback {
+		if o.revision == 0 {
+			return writeRevisions(cmdOut, revisions, current.Name, time.Now())
+		}
+		revision, err := findRevision(revisions, o.revision)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to show the history of %s %q", apiResource.Kind, qualifiedName)
+		}
+		return writeRevision(cmdOut, revision)
+	}
+
+	revision, err := rollbackRevision(revisions, current.Name, o.revision)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to roll back %s %q", apiResource.Kind, qualifiedName)
+	}
+	if revision.Name == current.Name {
+		fmt.Fprintf(cmdOut, "%s %q is already at revision %d\n", apiResource.Kind, qualifiedName, revision.Revision)
+		return nil
+	}
+	if o.DryRun {
+		fmt.Fprintf(cmdOut, "%s %q would be rolled back to revision %d (dry run)\n", apiResource.Kind, qualifiedName, revision.Revision)
+		return nil
+	}
+
+	if err := ctlutil.RestoreRevision(obj, revision); err != nil {
+		return errors.Wrapf(err, "Unable to roll back %s %q", apiResource.Kind, qualifiedName)
+	}
+	_, err = resourceClient.Update(obj, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update %s %q", apiResource.Kind, qualifiedName)
+	}
+	fmt.Fprintf(cmdOut, "%s %q rolled back to revision %d\n", apiResource.Kind, qualifiedName, revision.Revision)
+	return nil
+}
+
+// findRevision returns the revision with the given number.
+func findRevision(revisions []appsv1.ControllerRevision, number int64) (*appsv1.ControllerRevision, error) {
+	for i := range revisions {
+		if revisions[i].Revision == number {
+			return &revisions[i], nil
+		}
+	}
+	return nil, errors.Errorf("revision %d not found", number)
+}
+
+// rollbackRevision returns the revision to roll back to: the revision
+// with the given number, or the latest revision other than the
+// current one, named by currentName, if the number is 0.
+func rollbackRevision(revisions []appsv1.ControllerRevision, currentName string, number int64) (*appsv1.ControllerRevision, error) {
+	if number != 0 {
+		return findRevision(revisions, number)
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Name != currentName {
+			return &revisions[i], nil
+		}
+	}
+	return nil, errors.New("no previous revision found")
+}
+
+// writeRevisions lists the given revisions, marking the one whose
+// content is that of the resource.
+func writeRevisions(cmdOut io.Writer, revisions []appsv1.ControllerRevision, currentName string, now time.Time) error {
+	w := tabwriter.NewWriter(cmdOut, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tAGE\tCURRENT")
+	for _, revision := range revisions {
+		current := ""
+		if revision.Name == currentName {
+			current = "*"
+		}
+		age := duration.HumanDuration(now.Sub(revision.CreationTimestamp.Time))
+		fmt.Fprintf(w, "%d\t%s\t%s\n", revision.Revision, age, current)
+	}
+	return w.Flush()
+}
+
+// writeRevision writes the template and overrides recorded in the
+// given revision as YAML.
+func writeRevision(cmdOut io.Writer, revision *appsv1.ControllerRevision) error {
+	data, err := yaml.JSONToYAML(revision.Data.Raw)
+	if err != nil {
+		return errors.Wrapf(err, "Error encoding revision %d to yaml", revision.Revision)
+	}
+	_, err = cmdOut.Write(data)
+	return err
+}
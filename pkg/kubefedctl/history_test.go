@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRollbackRevision(t *testing.T) {
+	revisions := []appsv1.ControllerRevision{
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-a"}, Revision: 1},
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-b"}, Revision: 2},
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-c"}, Revision: 3},
+	}
+	testCases := map[string]struct {
+		currentName   string
+		number        int64
+		expectedName  string
+		expectedError bool
+	}{
+		"Previous revision of the latest": {
+			currentName:  "foo-c",
+			expectedName: "foo-b",
+		},
+		"Latest revision of a changed resource": {
+			currentName:  "foo-d",
+			expectedName: "foo-c",
+		},
+		"Given revision": {
+			currentName:  "foo-c",
+			number:       1,
+			expectedName: "foo-a",
+		},
+		"Missing revision": {
+			currentName:   "foo-c",
+			number:        4,
+			expectedError: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			revision, err := rollbackRevision(revisions, tc.currentName, tc.number)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if revision.Name != tc.expectedName {
+				t.Errorf("Expected revision %q, got %q", tc.expectedName, revision.Name)
+			}
+		})
+	}
+
+	if _, err := rollbackRevision(revisions[:1], "foo-a", 0); err == nil {
+		t.Errorf("Expected an error rolling back without a previous revision")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	goerrors "errors"
 	"io"
+	"os"
 	"reflect"
 	"strings"
 	"time"
@@ -65,7 +66,18 @@ var (
 		# be a valid RFC 1123 subdomain name. Cluster context
 		# must be specified if the cluster name is different
 		# than the cluster's context in the local kubeconfig.
-		kubefedctl join foo --host-cluster-context=bar`
+		kubefedctl join foo --host-cluster-context=bar
+
+		# Request that a cluster be joined by authenticating to
+		# the host cluster with a bootstrap token. The cluster
+		# is joined once the resulting ClusterJoinRequest is
+		# approved.
+		kubefedctl join foo --host-cluster-context=bar --bootstrap-token=abcdef.0123456789abcdef
+
+		# Choose the context of the cluster to register from the
+		# contexts of the local kubeconfig. The cluster name
+		# defaults to the name of the chosen context.
+		kubefedctl join --host-cluster-context=bar --interactive`
 
 	// Policy rules allowing full access to resources in the cluster
 	// or namespace.
@@ -88,6 +100,7 @@ var (
 type joinFederation struct {
 	options.GlobalSubcommandOptions
 	options.CommonJoinOptions
+	options.CompatibilityOptions
 	joinFederationOptions
 }
 
@@ -95,6 +108,8 @@ type joinFederationOptions struct {
 	secretName      string
 	scope           apiextv1b1.ResourceScope
 	errorOnExisting bool
+	bootstrapToken  string
+	interactive     bool
 }
 
 // Bind adds the join specific arguments to the flagset passed in as an
@@ -104,6 +119,10 @@ func (o *joinFederationOptions) Bind(flags *pflag.FlagSet) {
 		"Name of the secret where the cluster's credentials will be stored in the host cluster. This name should be a valid RFC 1035 label. If unspecified, defaults to a generated name containing the cluster name.")
 	flags.BoolVar(&o.errorOnExisting, "error-on-existing", true,
 		"Whether the join operation will throw an error if it encounters existing artifacts with the same name as those it's trying to create. If false, the join operation will update existing artifacts to match its own specification.")
+	flags.StringVar(&o.bootstrapToken, "bootstrap-token", "",
+		"Bootstrap token used to authenticate to the host cluster. If specified, a ClusterJoinRequest is created in the host cluster instead of a KubeFedCluster, and the cluster is joined once the request is approved.")
+	flags.BoolVar(&o.interactive, "interactive", false,
+		"If true and --cluster-context is not provided, the contexts of the local kubeconfig are listed to choose the context of the joining cluster from. The cluster name defaults to the name of the chosen context.")
 }
 
 // NewCmdJoin defines the `join` command that registers a cluster with
@@ -117,6 +136,17 @@ func NewCmdJoin(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
 		Long:    join_long,
 		Example: join_example,
 		Run: func(cmd *cobra.Command, args []string) {
+			if opts.interactive && opts.ClusterContext == "" {
+				clusterContext, err := opts.pickClusterContext(cmdOut, config)
+				if err != nil {
+					klog.Fatalf("Error: %v", err)
+				}
+				opts.ClusterContext = clusterContext
+				if len(args) == 0 {
+					args = []string{clusterContext}
+				}
+			}
+
 			err := opts.Complete(args)
 			if err != nil {
 				klog.Fatalf("Error: %v", err)
@@ -132,6 +162,7 @@ func NewCmdJoin(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
 	flags := cmd.Flags()
 	opts.GlobalSubcommandBind(flags)
 	opts.CommonSubcommandBind(flags)
+	opts.CompatibilityBind(flags)
 	opts.Bind(flags)
 
 	return cmd
@@ -164,6 +195,32 @@ func (j *joinFederation) Complete(args []string) error {
 	return nil
 }
 
+// pickClusterContext prompts for the context of the joining cluster
+// from the contexts of the local kubeconfig other than the context of
+// the host cluster.
+func (j *joinFederation) pickClusterContext(cmdOut io.Writer, config util.FedConfig) (string, error) {
+	hostClientConfig := config.GetClientConfig(j.HostClusterContext, j.Kubeconfig)
+	hostClusterContext := j.HostClusterContext
+	if hostClusterContext == "" {
+		var err error
+		hostClusterContext, err = options.CurrentContext(hostClientConfig)
+		if err != nil {
+			return "", err
+		}
+	}
+	contextNames, err := util.ContextNames(hostClientConfig)
+	if err != nil {
+		return "", err
+	}
+	var choices []string
+	for _, name := range contextNames {
+		if name != hostClusterContext {
+			choices = append(choices, name)
+		}
+	}
+	return util.Pick(os.Stdin, cmdOut, "Context of the cluster to join", choices)
+}
+
 // Run is the implementation of the `join` command.
 func (j *joinFederation) Run(cmdOut io.Writer, config util.FedConfig) error {
 	hostClientConfig := config.GetClientConfig(j.HostClusterContext, j.Kubeconfig)
@@ -178,12 +235,20 @@ func (j *joinFederation) Run(cmdOut io.Writer, config util.FedConfig) error {
 		klog.V(2).Infof("Failed to get host cluster config: %v", err)
 		return err
 	}
+	if j.bootstrapToken != "" {
+		hostConfig = bootstrapConfig(hostConfig, j.bootstrapToken)
+	}
 
 	j.scope, err = options.GetScopeFromKubeFedConfig(hostConfig, j.KubeFedNamespace)
 	if err != nil {
 		return err
 	}
 
+	err = j.CheckCompatibility(hostConfig, j.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+
 	clusterConfig, err := config.ClusterConfig(j.ClusterContext, j.Kubeconfig)
 	if err != nil {
 		klog.V(2).Infof("Failed to get joining cluster config: %v", err)
@@ -195,6 +260,12 @@ func (j *joinFederation) Run(cmdOut io.Writer, config util.FedConfig) error {
 		hostClusterName = j.HostClusterName
 	}
 
+	if j.bootstrapToken != "" {
+		_, err = RequestJoinCluster(hostConfig, clusterConfig, j.KubeFedNamespace,
+			hostClusterName, j.ClusterName, j.scope, j.DryRun, j.errorOnExisting)
+		return err
+	}
+
 	_, err = JoinCluster(hostConfig, clusterConfig, j.KubeFedNamespace,
 		hostClusterName, j.ClusterName, j.secretName, j.scope, j.DryRun, j.errorOnExisting)
 
@@ -274,8 +345,14 @@ func joinClusterForNamespace(hostConfig, clusterConfig *rest.Config, kubefedName
 		disabledTLSValidations = append(disabledTLSValidations, fedv1b1.TLSAll)
 	}
 
+	hostCluster, err := isHostCluster(hostClientset, clusterClientset)
+	if err != nil {
+		klog.V(2).Infof("Failed to determine whether cluster %s is the host cluster: %v", joiningClusterName, err)
+		return nil, err
+	}
+
 	kubefedCluster, err := createKubeFedCluster(client, joiningClusterName, clusterConfig.Host,
-		secret.Name, kubefedNamespace, caBundle, disabledTLSValidations, dryRun, errorOnExisting)
+		secret.Name, kubefedNamespace, caBundle, disabledTLSValidations, hostCluster, dryRun, errorOnExisting)
 	if err != nil {
 		klog.V(2).Infof("Failed to create federated cluster resource: %v", err)
 		return nil, err
@@ -312,11 +389,25 @@ func performPreflightChecks(clusterClientset kubeclient.Interface, name, hostClu
 	}
 }
 
+// isHostCluster checks whether the joining cluster is the host cluster
+// by comparing the uids of the kube-system namespaces of both clusters.
+func isHostCluster(hostClientset, clusterClientset kubeclient.Interface) (bool, error) {
+	hostNamespace, err := hostClientset.CoreV1().Namespaces().Get(metav1.NamespaceSystem, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "Error retrieving namespace %q from host cluster", metav1.NamespaceSystem)
+	}
+	clusterNamespace, err := clusterClientset.CoreV1().Namespaces().Get(metav1.NamespaceSystem, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "Error retrieving namespace %q from joining cluster", metav1.NamespaceSystem)
+	}
+	return ctlutil.IsPrimaryCluster(hostNamespace, clusterNamespace), nil
+}
+
 // createKubeFedCluster creates a federated cluster resource that associates
 // the cluster and secret.
 func createKubeFedCluster(client genericclient.Client, joiningClusterName, apiEndpoint,
 	secretName, kubefedNamespace string, caBundle []byte, disabledTLSValidations []fedv1b1.TLSValidation,
-	dryRun, errorOnExisting bool) (*fedv1b1.KubeFedCluster, error) {
+	hostCluster, dryRun, errorOnExisting bool) (*fedv1b1.KubeFedCluster, error) {
 	fedCluster := &fedv1b1.KubeFedCluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: kubefedNamespace,
@@ -329,6 +420,7 @@ func createKubeFedCluster(client genericclient.Client, joiningClusterName, apiEn
 				Name: secretName,
 			},
 			DisabledTLSValidations: disabledTLSValidations,
+			HostCluster:            hostCluster,
 		},
 	}
 
@@ -818,6 +910,42 @@ func populateSecretInHostCluster(clusterClientset, hostClientset kubeclient.Inte
 		return dryRunSecret, nil, nil
 	}
 
+	token, caBundle, err := getServiceAccountToken(clusterClientset, saName, joiningNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create a secret in the host cluster containing the token.
+	v1Secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hostNamespace,
+		},
+		Data: map[string][]byte{
+			ctlutil.TokenKey: token,
+		},
+	}
+
+	if secretName == "" {
+		v1Secret.GenerateName = joiningClusterName + "-"
+	} else {
+		v1Secret.Name = secretName
+	}
+
+	v1SecretResult, err := hostClientset.CoreV1().Secrets(hostNamespace).Create(&v1Secret)
+	if err != nil {
+		klog.V(2).Infof("Could not create secret in host cluster: %v", err)
+		return nil, nil, err
+	}
+
+	klog.V(2).Infof("Created secret in host cluster named: %s", v1SecretResult.Name)
+	return v1SecretResult, caBundle, nil
+}
+
+// getServiceAccountToken retrieves the token and CA bundle of the
+// service account named saName in the joining cluster.
+func getServiceAccountToken(clusterClientset kubeclient.Interface, saName,
+	joiningNamespace string) ([]byte, []byte, error) {
+
 	// Get the secret from the joining cluster.
 	var secret *corev1.Secret
 	err := wait.PollImmediate(1*time.Second, serviceAccountSecretTimeout, func() (bool, error) {
@@ -853,32 +981,7 @@ func populateSecretInHostCluster(clusterClientset, hostClientset kubeclient.Inte
 		return nil, nil, errors.Errorf("Key %q not found in service account secret", ctlutil.TokenKey)
 	}
 
-	// Create a secret in the host cluster containing the token.
-	v1Secret := corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: hostNamespace,
-		},
-		Data: map[string][]byte{
-			ctlutil.TokenKey: token,
-		},
-	}
-
-	if secretName == "" {
-		v1Secret.GenerateName = joiningClusterName + "-"
-	} else {
-		v1Secret.Name = secretName
-	}
-
-	v1SecretResult, err := hostClientset.CoreV1().Secrets(hostNamespace).Create(&v1Secret)
-	if err != nil {
-		klog.V(2).Infof("Could not create secret in host cluster: %v", err)
-		return nil, nil, err
-	}
-
 	// caBundle is optional so no error is suggested if it is not
 	// found in the secret.
-	caBundle := secret.Data["ca.crt"]
-
-	klog.V(2).Infof("Created secret in host cluster named: %s", v1SecretResult.Name)
-	return v1SecretResult, caBundle, nil
+	return token, secret.Data["ca.crt"], nil
 }
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	joinrequest_approve_long = `
+		Approve a ClusterJoinRequest so that its cluster is
+		joined to the KubeFed control plane.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	joinrequest_approve_example = `
+		# Approve the request to join cluster foo
+		kubefedctl joinrequest approve foo --host-cluster-context=bar`
+
+	joinrequest_deny_long = `
+		Deny a ClusterJoinRequest so that its cluster is not
+		joined to the KubeFed control plane.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	joinrequest_deny_example = `
+		# Deny the request to join cluster foo
+		kubefedctl joinrequest deny foo --host-cluster-context=bar`
+)
+
+// bootstrapConfig returns a copy of the given host cluster config that
+// authenticates with the given bootstrap token instead of the
+// credentials of the kubeconfig.
+func bootstrapConfig(hostConfig *rest.Config, token string) *rest.Config {
+	config := rest.AnonymousClientConfig(hostConfig)
+	config.BearerToken = token
+	return config
+}
+
+// RequestJoinCluster prepares a cluster to be managed by a KubeFed
+// control plane and requests that it be joined by creating a
+// ClusterJoinRequest in the host cluster. Unlike JoinCluster, the host
+// config only needs to be authorized to create ClusterJoinRequests.
+func RequestJoinCluster(hostConfig, clusterConfig *rest.Config, kubefedNamespace,
+	hostClusterName, joiningClusterName string,
+	scope apiextv1b1.ResourceScope, dryRun, errorOnExisting bool) (*fedv1b1.ClusterJoinRequest, error) {
+
+	clusterClientset, err := util.ClusterClientset(clusterConfig)
+	if err != nil {
+		klog.V(2).Infof("Failed to get joining cluster clientset: %v", err)
+		return nil, err
+	}
+
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		klog.V(2).Infof("Failed to get kubefed clientset: %v", err)
+		return nil, err
+	}
+
+	klog.V(2).Infof("Performing preflight checks.")
+	err = performPreflightChecks(clusterClientset, joiningClusterName, hostClusterName, kubefedNamespace, errorOnExisting)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.V(2).Infof("Creating %s namespace in joining cluster", kubefedNamespace)
+	_, err = createKubeFedNamespace(clusterClientset, kubefedNamespace, joiningClusterName, dryRun)
+	if err != nil {
+		klog.V(2).Infof("Error creating %s namespace in joining cluster: %v", kubefedNamespace, err)
+		return nil, err
+	}
+
+	saName, err := createAuthorizedServiceAccount(clusterClientset, kubefedNamespace,
+		joiningClusterName, hostClusterName, scope, dryRun, errorOnExisting)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &fedv1b1.ClusterJoinRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: kubefedNamespace,
+			Name:      joiningClusterName,
+		},
+		Spec: fedv1b1.ClusterJoinRequestSpec{
+			ClusterName: joiningClusterName,
+			APIEndpoint: clusterConfig.Host,
+		},
+	}
+	if dryRun {
+		return request, nil
+	}
+
+	token, caBundle, err := getServiceAccountToken(clusterClientset, saName, kubefedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	request.Spec.ServiceAccountToken = token
+	request.Spec.CABundle = caBundle
+
+	err = client.Create(context.TODO(), request)
+	if err != nil {
+		klog.V(2).Infof("Failed to create cluster join request: %v", err)
+		return nil, err
+	}
+
+	klog.V(2).Infof("Created cluster join request %q", request.Name)
+	return request, nil
+}
+
+type joinRequestDecision struct {
+	options.GlobalSubcommandOptions
+	name string
+}
+
+// NewCmdJoinRequest defines the `joinrequest` command that approves or
+// denies requests to join a KubeFed control plane.
+func NewCmdJoinRequest(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "joinrequest",
+		Short: "Approve or deny requests to join a KubeFed control plane",
+		Long:  "Approve or deny requests to join a KubeFed control plane",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+	cmd.AddCommand(newCmdJoinRequestDecision(cmdOut, config, "approve", "Approve a request to join a KubeFed control plane",
+		joinrequest_approve_long, joinrequest_approve_example, fedv1b1.ClusterJoinRequestApproved))
+	cmd.AddCommand(newCmdJoinRequestDecision(cmdOut, config, "deny", "Deny a request to join a KubeFed control plane",
+		joinrequest_deny_long, joinrequest_deny_example, fedv1b1.ClusterJoinRequestDenied))
+
+	return cmd
+}
+
+func newCmdJoinRequestDecision(cmdOut io.Writer, config util.FedConfig, verb, short, long, example string,
+	conditionType fedv1b1.ClusterJoinRequestConditionType) *cobra.Command {
+	opts := &joinRequestDecision{}
+
+	cmd := &cobra.Command{
+		Use:     verb + " REQUEST_NAME --host-cluster-context=HOST_CONTEXT",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				klog.Fatalf("Error: REQUEST_NAME is required")
+			}
+			opts.name = args[0]
+
+			err := opts.Run(cmdOut, config, conditionType)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.GlobalSubcommandBind(cmd.Flags())
+
+	return cmd
+}
+
+// Run sets the condition of the given type on the join request.
+func (o *joinRequestDecision) Run(cmdOut io.Writer, config util.FedConfig,
+	conditionType fedv1b1.ClusterJoinRequestConditionType) error {
+	hostClientConfig := config.GetClientConfig(o.HostClusterContext, o.Kubeconfig)
+	if err := o.SetHostClusterContextFromConfig(hostClientConfig); err != nil {
+		return err
+	}
+	hostConfig, err := hostClientConfig.ClientConfig()
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return err
+	}
+
+	request := &fedv1b1.ClusterJoinRequest{}
+	err = client.Get(context.TODO(), request, o.KubeFedNamespace, o.name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get ClusterJoinRequest %q", o.name)
+	}
+	if request.IsConditionTrue(fedv1b1.ClusterJoinRequestApproved) || request.IsConditionTrue(fedv1b1.ClusterJoinRequestDenied) {
+		return errors.Errorf("ClusterJoinRequest %q has already been approved or denied", o.name)
+	}
+
+	request.SetCondition(conditionType, corev1.ConditionTrue, "KubefedctlDecision",
+		fmt.Sprintf("%s by kubefedctl", conditionType))
+	if o.DryRun {
+		return nil
+	}
+	err = client.UpdateStatus(context.TODO(), request)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update ClusterJoinRequest %q", o.name)
+	}
+
+	fmt.Fprintf(cmdOut, "ClusterJoinRequest %q %s\n", o.name, conditionType)
+	return nil
+}
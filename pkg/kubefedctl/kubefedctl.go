@@ -19,6 +19,9 @@ package kubefedctl
 import (
 	"flag"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -29,6 +32,7 @@ import (
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/orphaning"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/sched"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
 )
 
@@ -36,7 +40,7 @@ import (
 func NewKubeFedCtlCommand(out io.Writer) *cobra.Command {
 	// Parent command to which all subcommands are added.
 	rootCmd := &cobra.Command{
-		Use:   "kubefedctl",
+		Use:   commandName(os.Args[0]),
 		Short: "kubefedctl controls a Kubernetes Cluster Federation",
 		Long:  "kubefedctl controls a Kubernetes Cluster Federation. Find more information at https://sigs.k8s.io/kubefed.",
 
@@ -61,12 +65,43 @@ func NewKubeFedCtlCommand(out io.Writer) *cobra.Command {
 	rootCmd.AddCommand(federate.NewCmdFederateResource(out, fedConfig))
 	rootCmd.AddCommand(NewCmdJoin(out, fedConfig))
 	rootCmd.AddCommand(NewCmdUnjoin(out, fedConfig))
+	rootCmd.AddCommand(NewCmdJoinRequest(out, fedConfig))
 	rootCmd.AddCommand(orphaning.NewCmdOrphaning(out, fedConfig))
+	rootCmd.AddCommand(sched.NewCmdSched(out, fedConfig))
+	rootCmd.AddCommand(NewCmdPatchPlacement(out, fedConfig))
+	rootCmd.AddCommand(NewCmdExplain(out, fedConfig))
+	rootCmd.AddCommand(NewCmdValidate(out, fedConfig))
+	rootCmd.AddCommand(NewCmdUndelete(out, fedConfig))
+	rootCmd.AddCommand(NewCmdQuarantine(out, fedConfig))
+	rootCmd.AddCommand(NewCmdCordon(out, fedConfig))
+	rootCmd.AddCommand(NewCmdUncordon(out, fedConfig))
+	rootCmd.AddCommand(NewCmdDrain(out, fedConfig))
+	rootCmd.AddCommand(NewCmdPin(out, fedConfig))
+	rootCmd.AddCommand(NewCmdUnpin(out, fedConfig))
+	rootCmd.AddCommand(NewCmdHistory(out, fedConfig))
+	rootCmd.AddCommand(NewCmdRollback(out, fedConfig))
+	rootCmd.AddCommand(NewCmdLogs(out, fedConfig))
+	rootCmd.AddCommand(NewCmdExec(out, fedConfig))
+	rootCmd.AddCommand(NewCmdBackup(out, fedConfig))
+	rootCmd.AddCommand(NewCmdRestore(out, fedConfig))
+	rootCmd.AddCommand(NewCmdMigrate(out, fedConfig))
+	rootCmd.AddCommand(NewCmdCompletion(out, fedConfig))
 	rootCmd.AddCommand(NewCmdVersion(out))
 
 	return rootCmd
 }
 
+// commandName returns the name by which the command was invoked for use
+// in help output. When installed as a kubectl plugin (e.g. by krew) the
+// binary is named kubectl-kubefed and invoked as `kubectl kubefed`.
+func commandName(binaryPath string) string {
+	binaryName := strings.TrimSuffix(filepath.Base(binaryPath), ".exe")
+	if strings.HasPrefix(binaryName, "kubectl-") {
+		return "kubectl " + strings.TrimPrefix(binaryName, "kubectl-")
+	}
+	return "kubefedctl"
+}
+
 func runHelp(cmd *cobra.Command, args []string) error {
 	return cmd.Help()
 }
@@ -0,0 +1,433 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	logs_long = `
+		Print the logs of the pods of a federated workload in one or
+		more member clusters. The pods are those selected by the
+		resource the federated workload propagates to each cluster
+		(e.g. the Deployment of a FederatedDeployment). The lines of
+		the logs of different pods and clusters are interleaved, and
+		each line is prefixed with the cluster, pod and container it
+		originates from.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	logs_example = `
+		# Print the logs of the pods of the FederatedDeployment myapp in cluster prod-eu
+		kubefedctl logs federateddeployment/myapp --cluster prod-eu -n ns1
+
+		# Follow the logs of the pods of the FederatedDeployment myapp in every cluster it is propagated to
+		kubefedctl logs federateddeployment/myapp --all-clusters -n ns1 -f`
+)
+
+type workloadLogs struct {
+	options.GlobalSubcommandOptions
+	typeName          string
+	resourceName      string
+	resourceNamespace string
+	clusterNames      []string
+	allClusters       bool
+	container         string
+	follow            bool
+	tail              int64
+	timestamps        bool
+}
+
+// Bind adds the logs specific arguments to the flagset passed in as an
+// argument.
+func (o *workloadLogs) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of the federated workload. Defaults to the namespace of the current context.")
+	flags.StringSliceVar(&o.clusterNames, "cluster", nil, "The name of a cluster to print the logs of. May be repeated.")
+	flags.BoolVar(&o.allClusters, "all-clusters", false, "Print the logs of every cluster the workload is propagated to.")
+	flags.StringVarP(&o.container, "container", "c", "", "The container to print the logs of. Defaults to all containers of the pods.")
+	flags.BoolVarP(&o.follow, "follow", "f", false, "Stream the logs as they are written.")
+	flags.Int64Var(&o.tail, "tail", -1, "The number of recent lines of each log to print. Defaults to all lines.")
+	flags.BoolVar(&o.timestamps, "timestamps", false, "Include the timestamp of each line.")
+}
+
+// NewCmdLogs defines the `logs` command that prints the logs of a
+// federated workload across member clusters.
+func NewCmdLogs(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &workloadLogs{}
+
+	cmd := &cobra.Command{
+		Use:     "logs TYPE/NAME (--cluster=CLUSTER_NAME | --all-clusters)",
+		Short:   "Print the logs of the pods of a federated workload in member clusters",
+		Long:    logs_long,
+		Example: logs_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Complete ensures that options are valid and marshals them if necessary.
+func (o *workloadLogs) Complete(args []string, config util.FedConfig) error {
+	var err error
+	o.typeName, o.resourceName, err = parseWorkloadArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if len(o.clusterNames) == 0 && !o.allClusters {
+		return errors.New("one of --cluster or --all-clusters is required")
+	}
+	if len(o.clusterNames) > 0 && o.allClusters {
+		return errors.New("--cluster and --all-clusters are mutually exclusive")
+	}
+
+	if len(o.resourceNamespace) == 0 {
+		o.resourceNamespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		return err
+	}
+	return nil
+}
+
+// Run implements the `logs` command.
+func (o *workloadLogs) Run(cmdOut io.Writer, config util.FedConfig) error {
+	workload, err := loadFederatedWorkload(config, o.GlobalSubcommandOptions, o.typeName, o.resourceName, o.resourceNamespace)
+	if err != nil {
+		return err
+	}
+	clusterNames := o.clusterNames
+	if o.allClusters {
+		propagated, err := status.PropagatedClusterNames(workload.fedObject)
+		if err != nil {
+			return err
+		}
+		if propagated.Len() == 0 {
+			return errors.Errorf("%s %q is not propagated to any cluster", workload.fedObject.GetKind(), workload.qualifiedName())
+		}
+		clusterNames = propagated.List()
+	}
+
+	logOptions := &corev1.PodLogOptions{
+		Container:  o.container,
+		Follow:     o.follow,
+		Timestamps: o.timestamps,
+	}
+	if o.tail >= 0 {
+		logOptions.TailLines = &o.tail
+	}
+
+	writer := &prefixedLineWriter{out: cmdOut}
+	failures := 0
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	for _, clusterName := range clusterNames {
+		clusterPods, err := workload.clusterPods(clusterName)
+		if err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Failed to retrieve the pods in cluster %q: %v\n", clusterName, err)
+			continue
+		}
+		for _, pod := range clusterPods.pods {
+			for _, container := range podContainers(pod, o.container) {
+				streamOptions := logOptions.DeepCopy()
+				streamOptions.Container = container
+				prefix := fmt.Sprintf("[%s/%s/%s]", clusterName, pod.Name, container)
+				request := clusterPods.kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, streamOptions)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := streamLogs(request, prefix, writer)
+					if err != nil {
+						lock.Lock()
+						failures++
+						lock.Unlock()
+						fmt.Fprintf(os.Stderr, "%s Failed to retrieve the logs: %v\n", prefix, err)
+					}
+				}()
+			}
+		}
+	}
+	wg.Wait()
+
+	if failures > 0 {
+		return errors.Errorf("failed to retrieve %d log(s)", failures)
+	}
+	return nil
+}
+
+func streamLogs(request *rest.Request, prefix string, writer *prefixedLineWriter) error {
+	stream, err := request.Stream()
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	return writer.copyLines(prefix, stream)
+}
+
+// prefixedLineWriter writes the lines of concurrent streams prefixed
+// with the origin of each stream, without interleaving partial lines.
+type prefixedLineWriter struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+func (w *prefixedLineWriter) copyLines(prefix string, in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		w.lock.Lock()
+		fmt.Fprintf(w.out, "%s %s\n", prefix, scanner.Text())
+		w.lock.Unlock()
+	}
+	return scanner.Err()
+}
+
+// podContainers returns the containers of the given pod to retrieve
+// the logs of.
+func podContainers(pod corev1.Pod, container string) []string {
+	if len(container) > 0 {
+		return []string{container}
+	}
+	containers := []string{}
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, c.Name)
+	}
+	return containers
+}
+
+// parseWorkloadArgs returns the type and name of a federated workload
+// given either as TYPE/NAME or as TYPE NAME.
+func parseWorkloadArgs(args []string) (string, string, error) {
+	switch len(args) {
+	case 1:
+		parts := strings.SplitN(args[0], "/", 2)
+		if len(parts) == 2 && len(parts[0]) > 0 && len(parts[1]) > 0 {
+			return parts[0], parts[1], nil
+		}
+	case 2:
+		return args[0], args[1], nil
+	}
+	return "", "", errors.New("a federated type and a resource name are required as TYPE/NAME")
+}
+
+// federatedWorkload is a federated resource whose target resources
+// run pods in member clusters.
+type federatedWorkload struct {
+	hostConfig       *rest.Config
+	client           genericclient.Client
+	kubefedNamespace string
+	typeConfig       *fedv1b1.FederatedTypeConfig
+	fedObject        *unstructured.Unstructured
+}
+
+// workloadPods are the pods of a federated workload in a member
+// cluster.
+type workloadPods struct {
+	clusterConfig *rest.Config
+	kubeClient    kubeclient.Interface
+	pods          []corev1.Pod
+}
+
+func loadFederatedWorkload(config util.FedConfig, globalOptions options.GlobalSubcommandOptions, typeName, name, namespace string) (*federatedWorkload, error) {
+	hostConfig, err := config.HostConfig(globalOptions.HostClusterContext, globalOptions.Kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			globalOptions.HostClusterContext, globalOptions.Kubeconfig)
+	}
+	apiResource, err := enable.LookupAPIResource(hostConfig, typeName, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to find targeted %s type", typeName)
+	}
+	if !util.IsFederatedAPIResource(apiResource.Kind, apiResource.Group) {
+		return nil, errors.Errorf("%s is not a federated type", typeName)
+	}
+	resourceClient, err := ctlutil.NewResourceClient(hostConfig, apiResource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating client for %s", apiResource.Kind)
+	}
+	fedObject, err := resourceClient.Resources(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve %s %q", apiResource.Kind, ctlutil.QualifiedName{Namespace: namespace, Name: name})
+	}
+
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	err = client.List(context.TODO(), typeConfigs, globalOptions.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+	typeConfig := lookupFederatedTypeConfigForObject(typeConfigs.Items, fedObject)
+	if typeConfig == nil {
+		return nil, errors.Errorf("%s is not an enabled federated type", apiResource.Kind)
+	}
+
+	return &federatedWorkload{
+		hostConfig:       hostConfig,
+		client:           client,
+		kubefedNamespace: globalOptions.KubeFedNamespace,
+		typeConfig:       typeConfig,
+		fedObject:        fedObject,
+	}, nil
+}
+
+func (w *federatedWorkload) qualifiedName() ctlutil.QualifiedName {
+	return ctlutil.NewQualifiedName(w.fedObject)
+}
+
+// clusterPods retrieves the pods selected by the target resource of
+// the workload in the named cluster.
+func (w *federatedWorkload) clusterPods(clusterName string) (*workloadPods, error) {
+	cluster := &fedv1b1.KubeFedCluster{}
+	err := w.client.Get(context.TODO(), cluster, w.kubefedNamespace, clusterName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get KubeFedCluster %q", clusterName)
+	}
+	clusterConfig, err := ctlutil.BuildClusterConfig(cluster, w.client, w.kubefedNamespace, w.hostConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to build the config of cluster %q", clusterName)
+	}
+	kubeClient, err := kubeclient.NewForConfig(clusterConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the client of cluster %q", clusterName)
+	}
+
+	placement, err := ctlutil.UnmarshalGenericPlacement(w.fedObject)
+	if err != nil {
+		return nil, err
+	}
+	targetName := ctlutil.TargetNameForCluster(clusterName, w.qualifiedName(), false, placement)
+	targetAPIResource := w.typeConfig.GetTargetType()
+	targetClient, err := ctlutil.NewResourceClient(clusterConfig, &targetAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	clusterObj, err := targetClient.Resources(targetName.Namespace).Get(targetName.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve %s %q", targetAPIResource.Kind, targetName)
+	}
+
+	result := &workloadPods{clusterConfig: clusterConfig, kubeClient: kubeClient}
+	if targetAPIResource.Kind == "Pod" {
+		pod := corev1.Pod{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, &pod)
+		if err != nil {
+			return nil, err
+		}
+		result.pods = []corev1.Pod{pod}
+		return result, nil
+	}
+
+	selector, err := podSelector(clusterObj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to determine the pods of %s %q", targetAPIResource.Kind, targetName)
+	}
+	podList, err := kubeClient.CoreV1().Pods(targetName.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list the pods of %s %q", targetAPIResource.Kind, targetName)
+	}
+	result.pods = podList.Items
+	sort.Slice(result.pods, func(i, j int) bool {
+		return result.pods[i].Name < result.pods[j].Name
+	})
+	return result, nil
+}
+
+// podSelector returns the selector of the pods of a workload resource
+// from its spec.selector, which is either a label selector (e.g. for a
+// Deployment, StatefulSet or Job) or a map of labels (e.g. for a
+// ReplicationController).
+func podSelector(obj *unstructured.Unstructured) (labels.Selector, error) {
+	rawSelector, found, err := unstructured.NestedMap(obj.Object, ctlutil.SpecField, "selector")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.Errorf("%s does not select pods", obj.GetKind())
+	}
+	var selector labels.Selector
+	_, hasMatchLabels := rawSelector["matchLabels"]
+	_, hasMatchExpressions := rawSelector["matchExpressions"]
+	if hasMatchLabels || hasMatchExpressions {
+		labelSelector := &metav1.LabelSelector{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawSelector, labelSelector)
+		if err != nil {
+			return nil, err
+		}
+		selector, err = metav1.LabelSelectorAsSelector(labelSelector)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		labelMap, _, err := unstructured.NestedStringMap(obj.Object, ctlutil.SpecField, "selector")
+		if err != nil {
+			return nil, err
+		}
+		selector = labels.SelectorFromSet(labelMap)
+	}
+	if selector.Empty() {
+		return nil, errors.Errorf("%s selects every pod of its namespace", obj.GetKind())
+	}
+	return selector, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseWorkloadArgs(t *testing.T) {
+	testCases := map[string]struct {
+		args          []string
+		expectedType  string
+		expectedName  string
+		expectedError bool
+	}{
+		"Type and name separated by a slash": {
+			args:         []string{"federateddeployment/myapp"},
+			expectedType: "federateddeployment",
+			expectedName: "myapp",
+		},
+		"Type and name as separate arguments": {
+			args:         []string{"federateddeployment", "myapp"},
+			expectedType: "federateddeployment",
+			expectedName: "myapp",
+		},
+		"Missing name": {
+			args:          []string{"federateddeployment/"},
+			expectedError: true,
+		},
+		"No arguments": {
+			expectedError: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			typeName, name, err := parseWorkloadArgs(tc.args)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if typeName != tc.expectedType || name != tc.expectedName {
+				t.Errorf("Expected %s/%s, got %s/%s", tc.expectedType, tc.expectedName, typeName, name)
+			}
+		})
+	}
+}
+
+func TestPodSelector(t *testing.T) {
+	testCases := map[string]struct {
+		selector      interface{}
+		expected      string
+		expectedError bool
+	}{
+		"Label selector": {
+			selector: map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "myapp"},
+			},
+			expected: "app=myapp",
+		},
+		"Map of labels": {
+			selector: map[string]interface{}{"app": "myapp"},
+			expected: "app=myapp",
+		},
+		"Empty selector": {
+			selector:      map[string]interface{}{},
+			expectedError: true,
+		},
+		"No selector": {
+			expectedError: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			spec := map[string]interface{}{}
+			if tc.selector != nil {
+				spec["selector"] = tc.selector
+			}
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": spec,
+			}}
+			selector, err := podSelector(obj)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if selector.String() != tc.expected {
+				t.Errorf("Expected selector %q, got %q", tc.expected, selector.String())
+			}
+		})
+	}
+}
+
+func TestPrefixedLineWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := &prefixedLineWriter{out: out}
+	err := writer.copyLines("[cluster1/pod1/app]", strings.NewReader("first\nsecond"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "[cluster1/pod1/app] first\n[cluster1/pod1/app] second\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestExecPod(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+	}
+
+	pod, err := execPod(pods, "")
+	if err != nil || pod.Name != "pod2" {
+		t.Errorf("Expected the first running pod pod2, got %v (error: %v)", pod, err)
+	}
+	pod, err = execPod(pods, "pod1")
+	if err != nil || pod.Name != "pod1" {
+		t.Errorf("Expected the named pod pod1, got %v (error: %v)", pod, err)
+	}
+	if _, err := execPod(pods, "pod3"); err == nil {
+		t.Errorf("Expected an error for a pod that does not belong to the workload")
+	}
+	if _, err := execPod(pods[:1], ""); err == nil {
+		t.Errorf("Expected an error if no pod is running")
+	}
+}
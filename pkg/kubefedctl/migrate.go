@@ -0,0 +1,447 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1a1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/version"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const controllerManagerDeploymentName = "kubefed-controller-manager"
+
+var (
+	migrate_long = `
+		Migrate moves a KubeFed control plane to a different host
+		cluster without disrupting the workloads propagated to
+		member clusters.
+
+		KubeFed needs to be deployed to the new host cluster with
+		the same scope and namespace before migrating. The
+		controller managers of both host clusters are stopped
+		while the migration is in progress. The federated types
+		and resources are copied to the new host cluster, the
+		member clusters are joined to it with new credentials,
+		and the propagated version records are copied once they
+		are verified against the member clusters, so that the
+		new control plane adopts the propagated resources in place
+		without updating them. The controller manager of the
+		previous host cluster remains stopped.
+
+		Current context is assumed to be the Kubernetes cluster
+		currently hosting the KubeFed control plane. Please use
+		the --host-cluster-context flag otherwise.`
+	migrate_example = `
+		# Move the control plane of the cluster with context bar to
+		# the cluster with context baz, accessing member cluster
+		# cluster2 with context cluster2-admin
+		kubefedctl migrate --host-cluster-context=bar --to-host-cluster-context=baz --cluster-context=cluster2=cluster2-admin`
+)
+
+type migrateControlPlane struct {
+	options.GlobalSubcommandOptions
+	toHostClusterContext string
+	toHostClusterName    string
+	clusterContexts      map[string]string
+	onConflict           string
+}
+
+// Bind adds the migrate specific arguments to the flagset passed in
+// as an argument.
+func (o *migrateControlPlane) Bind(flags *pflag.FlagSet) {
+	flags.StringVar(&o.toHostClusterContext, "to-host-cluster-context", "",
+		"Context of the cluster to move the control plane to.")
+	flags.StringVar(&o.toHostClusterName, "to-host-cluster-name", "",
+		"If set, overrides the use of to-host-cluster-context name in resource names created in member clusters. This option must be used when the context name has characters invalid for kubernetes resources like \"/\" and \":\".")
+	flags.StringToStringVar(&o.clusterContexts, "cluster-context", map[string]string{},
+		"Context of a member cluster in the local kubeconfig in the form CLUSTER_NAME=CONTEXT. The context of a member cluster defaults to its name.")
+	flags.StringVar(&o.onConflict, "on-conflict", conflictSkip,
+		"How to handle resources that already exist in the new host cluster. Valid values are 'skip', 'overwrite' and 'fail'.")
+}
+
+// Complete ensures that options are valid.
+func (o *migrateControlPlane) Complete(args []string) error {
+	if len(o.toHostClusterContext) == 0 {
+		return errors.New("--to-host-cluster-context is required")
+	}
+	if len(o.toHostClusterName) == 0 {
+		o.toHostClusterName = o.toHostClusterContext
+	}
+	switch o.onConflict {
+	case conflictSkip, conflictOverwrite, conflictFail:
+	default:
+		return errors.Errorf("Invalid value for --on-conflict: %s", o.onConflict)
+	}
+	return nil
+}
+
+// NewCmdMigrate defines the `migrate` command that moves a KubeFed
+// control plane to a different host cluster.
+func NewCmdMigrate(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &migrateControlPlane{}
+
+	cmd := &cobra.Command{
+		Use:     "migrate --host-cluster-context=HOST_CONTEXT --to-host-cluster-context=NEW_HOST_CONTEXT",
+		Short:   "Move a KubeFed control plane to a different host cluster",
+		Long:    migrate_long,
+		Example: migrate_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Run moves the control plane to the new host cluster.
+func (o *migrateControlPlane) Run(cmdOut io.Writer, config util.FedConfig) error {
+	fromClientConfig := config.GetClientConfig(o.HostClusterContext, o.Kubeconfig)
+	if err := o.SetHostClusterContextFromConfig(fromClientConfig); err != nil {
+		return err
+	}
+	fromConfig, err := fromClientConfig.ClientConfig()
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	toConfig, err := config.HostConfig(o.toHostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.toHostClusterContext, o.Kubeconfig)
+	}
+
+	scope, err := options.GetScopeFromKubeFedConfig(fromConfig, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	toScope, err := options.GetScopeFromKubeFedConfig(toConfig, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	if scope != toScope {
+		return errors.Errorf("The control plane in cluster %q has scope %q, but the control plane in cluster %q has scope %q",
+			o.HostClusterContext, scope, o.toHostClusterContext, toScope)
+	}
+
+	fromKubeClient, err := kubeclient.NewForConfig(fromConfig)
+	if err != nil {
+		return err
+	}
+	toKubeClient, err := kubeclient.NewForConfig(toConfig)
+	if err != nil {
+		return err
+	}
+
+	// Neither control plane may propagate while the migration is in
+	// progress. The controller manager of the new host cluster is
+	// started once the version records have been copied, since they
+	// are only loaded on startup.
+	fromReplicas, err := o.scaleControllerManager(cmdOut, fromKubeClient, o.HostClusterContext, 0)
+	if err != nil {
+		return err
+	}
+	toReplicas, err := o.scaleControllerManager(cmdOut, toKubeClient, o.toHostClusterContext, 0)
+	if err != nil {
+		return err
+	}
+
+	err = o.migrate(cmdOut, config, fromConfig, toConfig, scope)
+	if err != nil {
+		fmt.Fprintf(cmdOut, "Migration failed, restarting the controller manager of cluster %q\n", o.HostClusterContext)
+		if _, scaleErr := o.scaleControllerManager(cmdOut, fromKubeClient, o.HostClusterContext, fromReplicas); scaleErr != nil {
+			klog.Errorf("Failed to restart the controller manager of cluster %q: %v", o.HostClusterContext, scaleErr)
+		}
+		return err
+	}
+
+	_, err = o.scaleControllerManager(cmdOut, toKubeClient, o.toHostClusterContext, toReplicas)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmdOut, "Migrated the control plane from cluster %q to cluster %q. "+
+		"The credentials issued to the previous host cluster can be revoked by deleting its service accounts in the %q namespace of the member clusters.\n",
+		o.HostClusterContext, o.toHostClusterContext, o.KubeFedNamespace)
+	return nil
+}
+
+func (o *migrateControlPlane) migrate(cmdOut io.Writer, config util.FedConfig, fromConfig, toConfig *rest.Config, scope apiextv1b1.ResourceScope) error {
+	objs, err := exportControlPlane(fromConfig, o.KubeFedNamespace, false)
+	if err != nil {
+		return err
+	}
+	clusters := filterByGroupKind(objs, kubeFedClusterAPIResource)
+	var resources []*unstructured.Unstructured
+	for _, obj := range objs {
+		if obj.GroupVersionKind().Kind != kubeFedClusterAPIResource.Kind {
+			resources = append(resources, obj)
+		}
+	}
+
+	restorer := &restoreControlPlane{
+		GlobalSubcommandOptions: o.GlobalSubcommandOptions,
+		onConflict:              o.onConflict,
+	}
+	err = restorer.importControlPlane(cmdOut, toConfig, resources)
+	if err != nil {
+		return err
+	}
+
+	toClient, err := genericclient.New(toConfig)
+	if err != nil {
+		return err
+	}
+	clusterConfigs := make(map[string]*rest.Config)
+	for _, cluster := range clusters {
+		clusterConfig, err := o.joinCluster(cmdOut, config, toClient, toConfig, cluster, scope)
+		if err != nil {
+			return err
+		}
+		clusterConfigs[cluster.GetName()] = clusterConfig
+	}
+
+	fromClient, err := genericclient.New(fromConfig)
+	if err != nil {
+		return err
+	}
+	return o.migrateVersions(cmdOut, fromClient, toClient, toConfig, objs, clusterConfigs)
+}
+
+// scaleControllerManager scales the controller manager of the given
+// cluster to the given number of replicas, and returns its previous
+// number of replicas.
+func (o *migrateControlPlane) scaleControllerManager(cmdOut io.Writer, client kubeclient.Interface, clusterContext string, replicas int32) (int32, error) {
+	deployments := client.AppsV1().Deployments(o.KubeFedNamespace)
+	scale, err := deployments.GetScale(controllerManagerDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to get the scale of the controller manager of cluster %q", clusterContext)
+	}
+	previousReplicas := scale.Spec.Replicas
+	if previousReplicas == replicas || o.DryRun {
+		return previousReplicas, nil
+	}
+	scale.Spec.Replicas = replicas
+	_, err = deployments.UpdateScale(controllerManagerDeploymentName, scale)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to scale the controller manager of cluster %q", clusterContext)
+	}
+	fmt.Fprintf(cmdOut, "Scaled the controller manager of cluster %q to %d replicas\n", clusterContext, replicas)
+	return previousReplicas, nil
+}
+
+// joinCluster joins the given member cluster to the new host cluster,
+// issuing new credentials for it, and returns the configuration for
+// accessing the member cluster.
+func (o *migrateControlPlane) joinCluster(cmdOut io.Writer, config util.FedConfig, toClient genericclient.Client, toConfig *rest.Config,
+	cluster *unstructured.Unstructured, scope apiextv1b1.ResourceScope) (*rest.Config, error) {
+
+	name := cluster.GetName()
+	clusterContext, ok := o.clusterContexts[name]
+	if !ok {
+		clusterContext = name
+	}
+	clusterConfig, err := config.ClusterConfig(clusterContext, o.Kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			clusterContext, o.Kubeconfig)
+	}
+
+	_, err = JoinCluster(toConfig, clusterConfig, o.KubeFedNamespace, o.toHostClusterName, name, "", scope, o.DryRun, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to join cluster %q to cluster %q", name, o.toHostClusterContext)
+	}
+	fmt.Fprintf(cmdOut, "Joined cluster %q to cluster %q\n", name, o.toHostClusterContext)
+
+	// Labels select clusters for placement and need to be retained.
+	if len(cluster.GetLabels()) == 0 || o.DryRun {
+		return clusterConfig, nil
+	}
+	kubefedCluster := &fedv1b1.KubeFedCluster{}
+	err = toClient.Get(context.TODO(), kubefedCluster, o.KubeFedNamespace, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get KubeFedCluster %q", name)
+	}
+	if kubefedCluster.Labels == nil {
+		kubefedCluster.Labels = make(map[string]string)
+	}
+	for key, value := range cluster.GetLabels() {
+		kubefedCluster.Labels[key] = value
+	}
+	err = toClient.Update(context.TODO(), kubefedCluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to update the labels of KubeFedCluster %q", name)
+	}
+	return clusterConfig, nil
+}
+
+// migrateVersions copies the propagated version records of the
+// federated resources that were copied unchanged to the new host
+// cluster. Only the versions of clusters whose resource has not
+// changed since it was last propagated are retained, so that the new
+// control plane updates the resources that need updating and adopts
+// the others in place.
+func (o *migrateControlPlane) migrateVersions(cmdOut io.Writer, fromClient, toClient genericclient.Client, toConfig *rest.Config,
+	objs []*unstructured.Unstructured, clusterConfigs map[string]*rest.Config) error {
+
+	verified, discarded := 0, 0
+	for _, obj := range filterByGroupKind(objs, federatedTypeConfigAPIResource) {
+		typeConfig, err := decodeTypeConfig(obj)
+		if err != nil {
+			return err
+		}
+		federatedAPIResource := typeConfig.GetFederatedType()
+		fedClient, err := ctlutil.NewResourceClient(toConfig, &federatedAPIResource)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create client for %s", federatedAPIResource.Kind)
+		}
+		adapter := version.NewVersionAdapter(typeConfig.GetFederatedNamespaced())
+
+		for _, fedObject := range filterByGroupKind(objs, federatedAPIResource) {
+			qualifiedName := ctlutil.NewQualifiedName(fedObject)
+			versionName := ctlutil.QualifiedName{
+				Namespace: fedObject.GetNamespace(),
+				Name:      common.PropagatedVersionName(typeConfig.GetTargetType().Kind, fedObject.GetName()),
+			}
+
+			oldVersion := adapter.NewObject()
+			err := fromClient.Get(context.TODO(), oldVersion, versionName.Namespace, versionName.Name)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "Failed to get %s %q", adapter.TypeName(), versionName)
+			}
+
+			// The version record is only valid for the resource if
+			// it was copied unchanged.
+			newObject := fedObject
+			if !o.DryRun {
+				newObject, err = fedClient.Resources(fedObject.GetNamespace()).Get(fedObject.GetName(), metav1.GetOptions{})
+				if err != nil {
+					return errors.Wrapf(err, "Failed to get %s %q", federatedAPIResource.Kind, qualifiedName)
+				}
+			}
+			if !reflect.DeepEqual(newObject.Object["spec"], fedObject.Object["spec"]) {
+				fmt.Fprintf(cmdOut, "Discarded the propagated versions of %s %q since it differs in cluster %q\n",
+					federatedAPIResource.Kind, qualifiedName, o.toHostClusterContext)
+				discarded++
+				continue
+			}
+
+			status := adapter.GetStatus(oldVersion)
+			status.ClusterVersions, err = verifyClusterVersions(typeConfig.GetTargetType(), fedObject, status.ClusterVersions, clusterConfigs)
+			if err != nil {
+				return err
+			}
+			verified++
+			if o.DryRun {
+				continue
+			}
+
+			ownerReference := version.OwnerReferenceForUnstructured(newObject)
+			newVersion := adapter.NewVersion(versionName, ownerReference, status)
+			err = toClient.Create(context.TODO(), newVersion)
+			if apierrors.IsAlreadyExists(err) {
+				err = toClient.Get(context.TODO(), newVersion, versionName.Namespace, versionName.Name)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "Failed to create %s %q", adapter.TypeName(), versionName)
+			}
+			// The status of a version record is a subresource.
+			adapter.SetStatus(newVersion, status)
+			err = toClient.UpdateStatus(context.TODO(), newVersion)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to update the status of %s %q", adapter.TypeName(), versionName)
+			}
+		}
+	}
+	fmt.Fprintf(cmdOut, "Copied %d propagated version records, discarded %d\n", verified, discarded)
+	return nil
+}
+
+// verifyClusterVersions returns the given cluster versions of the
+// given federated resource that match the version of the resource in
+// the member cluster.
+func verifyClusterVersions(targetAPIResource metav1.APIResource, fedObject *unstructured.Unstructured,
+	clusterVersions []fedv1a1.ClusterObjectVersion, clusterConfigs map[string]*rest.Config) ([]fedv1a1.ClusterObjectVersion, error) {
+
+	verified := []fedv1a1.ClusterObjectVersion{}
+	for _, clusterVersion := range clusterVersions {
+		clusterConfig, ok := clusterConfigs[clusterVersion.ClusterName]
+		if !ok {
+			// The cluster is no longer joined.
+			continue
+		}
+		client, err := ctlutil.NewResourceClient(clusterConfig, &targetAPIResource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create client for %s in cluster %q", targetAPIResource.Kind, clusterVersion.ClusterName)
+		}
+		targetName := ctlutil.QualifiedName{Name: fedObject.GetName()}
+		if targetAPIResource.Namespaced {
+			targetName.Namespace = fedObject.GetNamespace()
+		}
+		if len(clusterVersion.TargetName) > 0 {
+			// The resource was propagated to a different namespace
+			// or under a different name.
+			targetName = ctlutil.ParseQualifiedName(clusterVersion.TargetName)
+		}
+		clusterObj, err := client.Resources(targetName.Namespace).Get(targetName.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get %s %q in cluster %q", targetAPIResource.Kind, targetName, clusterVersion.ClusterName)
+		}
+		if ctlutil.ObjectVersion(clusterObj) == clusterVersion.Version {
+			verified = append(verified, clusterVersion)
+		}
+	}
+	return verified, nil
+}
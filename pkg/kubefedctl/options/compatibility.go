@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/version"
+)
+
+// CompatibilityOptions holds the configuration required by subcommands
+// of `kubefedctl` that check their compatibility with the version of
+// the KubeFed control plane before modifying it.
+type CompatibilityOptions struct {
+	Force bool
+}
+
+// CompatibilityBind adds the compatibility flags to the flagset passed in.
+func (o *CompatibilityOptions) CompatibilityBind(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.Force, "force", false,
+		"Proceed even if the version of kubefedctl is incompatible with the version of the KubeFed control plane.")
+}
+
+// CheckCompatibility compares the version of kubefedctl with the
+// version of the controller manager recorded in the KubeFedConfig of
+// the given namespace. An error is returned if the versions are
+// incompatible and --force was not specified.
+func (o *CompatibilityOptions) CheckCompatibility(hostConfig *rest.Config, namespace string) error {
+	fedConfig, err := GetKubeFedConfig(hostConfig, namespace)
+	if err != nil {
+		return err
+	}
+	controllerVersion := fedConfig.Status.ControllerVersion
+	if len(controllerVersion) == 0 {
+		klog.Warningf("The version of the KubeFed control plane is unknown. It may predate kubefedctl version %s.", version.Get().Version)
+		return nil
+	}
+
+	warning, err := CheckVersionSkew(version.Get().Version, controllerVersion)
+	if err != nil {
+		if !o.Force {
+			return errors.Wrap(err, "Use --force to proceed regardless")
+		}
+		klog.Warningf("Proceeding despite version skew: %v", err)
+		return nil
+	}
+	if len(warning) > 0 {
+		klog.Warning(warning)
+	}
+	return nil
+}
+
+// CheckVersionSkew determines whether a kubefedctl of the given client
+// version can safely operate on a control plane of the given controller
+// version. A kubefedctl with a newer major or minor version than the
+// control plane may generate resources the controllers do not
+// understand, which is reported as an error. An older kubefedctl is
+// compatible but may not support recent features, which is reported as
+// a warning. Versions that cannot be parsed are not compared.
+func CheckVersionSkew(clientVersion, controllerVersion string) (string, error) {
+	client, err := utilversion.ParseGeneric(clientVersion)
+	if err != nil {
+		klog.V(2).Infof("Not checking version skew for unparseable kubefedctl version %q", clientVersion)
+		return "", nil
+	}
+	controller, err := utilversion.ParseGeneric(controllerVersion)
+	if err != nil {
+		klog.V(2).Infof("Not checking version skew for unparseable control plane version %q", controllerVersion)
+		return "", nil
+	}
+
+	switch {
+	case client.Major() != controller.Major(),
+		client.Major() == controller.Major() && client.Minor() > controller.Minor():
+		return "", errors.Errorf("kubefedctl version %s is newer than the KubeFed control plane version %s and may create resources the control plane does not support",
+			clientVersion, controllerVersion)
+	case client.Minor() < controller.Minor():
+		return fmt.Sprintf("kubefedctl version %s is older than the KubeFed control plane version %s. Upgrade kubefedctl to use all features of the control plane.",
+			clientVersion, controllerVersion), nil
+	}
+	return "", nil
+}
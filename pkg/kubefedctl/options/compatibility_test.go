@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"testing"
+)
+
+func TestCheckVersionSkew(t *testing.T) {
+	testCases := map[string]struct {
+		clientVersion     string
+		controllerVersion string
+		expectWarning     bool
+		expectErr         bool
+	}{
+		"same version": {
+			clientVersion:     "v0.3.0",
+			controllerVersion: "v0.3.0",
+		},
+		"different patch version": {
+			clientVersion:     "v0.3.1",
+			controllerVersion: "v0.3.0-12-g1cff6a6",
+		},
+		"older client minor version": {
+			clientVersion:     "v0.2.0",
+			controllerVersion: "v0.3.0",
+			expectWarning:     true,
+		},
+		"newer client minor version": {
+			clientVersion:     "v0.4.0",
+			controllerVersion: "v0.3.0",
+			expectErr:         true,
+		},
+		"different major version": {
+			clientVersion:     "v0.3.0",
+			controllerVersion: "v1.0.0",
+			expectErr:         true,
+		},
+		"unparseable controller version": {
+			clientVersion:     "v0.3.0",
+			controllerVersion: "unknown",
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			warning, err := CheckVersionSkew(tc.clientVersion, tc.controllerVersion)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectErr, err)
+			}
+			if tc.expectWarning != (len(warning) > 0) {
+				t.Fatalf("Expected warning: %v, got: %q", tc.expectWarning, warning)
+			}
+		})
+	}
+}
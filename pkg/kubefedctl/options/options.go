@@ -102,16 +102,27 @@ func (o *CommonJoinOptions) SetName(args []string) error {
 }
 
 func GetScopeFromKubeFedConfig(hostConfig *rest.Config, namespace string) (apiextv1b1.ResourceScope, error) {
+	fedConfig, err := GetKubeFedConfig(hostConfig, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	return fedConfig.Spec.Scope, nil
+}
+
+// GetKubeFedConfig retrieves the KubeFedConfig of the control plane in
+// the given namespace.
+func GetKubeFedConfig(hostConfig *rest.Config, namespace string) (*fedv1b1.KubeFedConfig, error) {
 	client, err := genericclient.New(hostConfig)
 	if err != nil {
 		err = errors.Wrap(err, "Failed to get kubefed clientset")
-		return "", err
+		return nil, err
 	}
 
 	fedConfig := &fedv1b1.KubeFedConfig{}
 	err = client.Get(context.TODO(), fedConfig, namespace, util.KubeFedConfigName)
 	if apierrors.IsNotFound(err) {
-		return "", errors.Errorf(
+		return nil, errors.Errorf(
 			"A KubeFedConfig named %q was not found in namespace %q. Is a KubeFed control plane running in this namespace?",
 			util.KubeFedConfigName, namespace)
 	} else if err != nil {
@@ -120,10 +131,10 @@ func GetScopeFromKubeFedConfig(hostConfig *rest.Config, namespace string) (apiex
 			Name:      util.KubeFedConfigName,
 		}
 		err = errors.Wrapf(err, "Error retrieving KubeFedConfig %q", config)
-		return "", err
+		return nil, err
 	}
 
-	return fedConfig.Spec.Scope, nil
+	return fedConfig, nil
 }
 
 // CommonEnableOptions holds the common configuration required by the enable
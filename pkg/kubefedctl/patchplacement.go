@@ -0,0 +1,356 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	patch_placement_long = `
+		Adds clusters to or removes clusters from the placement of
+		all federated resources in a namespace that match a label
+		selector. The overrides of an existing cluster can optionally
+		be copied to added clusters. With --dry-run, the changes that
+		would be made are printed without updating any resources.
+
+		Only resources placed by cluster name are changed. Resources
+		whose placement relies solely on clusterGroups or a
+		cluster selector are reported and skipped.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	patch_placement_example = `
+		# Add cluster prod-ap to the placement of the federated resources
+		# labeled app=foo, copying the overrides of cluster prod-eu
+		kubefedctl patch-placement --selector app=foo --add-cluster prod-ap --copy-overrides-from prod-eu --host-cluster-context=cluster1
+
+		# Show the changes that removing cluster prod-us would make
+		kubefedctl patch-placement --selector app=foo --remove-cluster prod-us --dry-run`
+)
+
+type patchPlacement struct {
+	options.GlobalSubcommandOptions
+	patchPlacementOptions
+	resourceNamespace string
+	selector          string
+	types             []string
+}
+
+type patchPlacementOptions struct {
+	addClusters       []string
+	removeClusters    []string
+	copyOverridesFrom string
+}
+
+// Bind adds the patch-placement specific arguments to the flagset
+// passed in as an argument.
+func (o *patchPlacement) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.selector, "selector", "l", "", "Label selector of the federated resources to patch.")
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of the federated resources to patch. Defaults to the namespace of the current context.")
+	flags.StringSliceVar(&o.types, "type", nil, "Names of the FederatedTypeConfigs of the resources to patch, e.g. deployments.apps. Defaults to all enabled namespaced types.")
+	flags.StringSliceVar(&o.addClusters, "add-cluster", nil, "Names of the clusters to add to the placement.")
+	flags.StringSliceVar(&o.removeClusters, "remove-cluster", nil, "Names of the clusters to remove from the placement. Overrides for removed clusters are also removed.")
+	flags.StringVar(&o.copyOverridesFrom, "copy-overrides-from", "", "Name of a cluster whose overrides are copied to added clusters that do not have overrides.")
+}
+
+// NewCmdPatchPlacement defines the `patch-placement` command that
+// bulk-edits the placement of federated resources.
+func NewCmdPatchPlacement(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &patchPlacement{}
+
+	cmd := &cobra.Command{
+		Use:     "patch-placement --selector=SELECTOR [--add-cluster=CLUSTER] [--remove-cluster=CLUSTER]",
+		Short:   "Add or remove clusters from the placement of federated resources matching a selector",
+		Long:    patch_placement_long,
+		Example: patch_placement_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Complete ensures that options are valid and marshals them if necessary.
+func (o *patchPlacement) Complete(args []string, config util.FedConfig) error {
+	if len(o.selector) == 0 {
+		return errors.New("a label selector must be provided with --selector")
+	}
+	if len(o.addClusters) == 0 && len(o.removeClusters) == 0 {
+		return errors.New("at least one of --add-cluster and --remove-cluster must be provided")
+	}
+	if len(o.copyOverridesFrom) > 0 && len(o.addClusters) == 0 {
+		return errors.New("--copy-overrides-from requires --add-cluster")
+	}
+	removed := sets.NewString(o.removeClusters...)
+	for _, clusterName := range o.addClusters {
+		if removed.Has(clusterName) {
+			return errors.Errorf("cluster %q may not be both added and removed", clusterName)
+		}
+	}
+	if removed.Has(o.copyOverridesFrom) {
+		return errors.Errorf("overrides may not be copied from removed cluster %q", o.copyOverridesFrom)
+	}
+	if len(o.resourceNamespace) == 0 {
+		var err error
+		o.resourceNamespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		return err
+	}
+	return nil
+}
+
+// Run implements the `patch-placement` command.
+func (o *patchPlacement) Run(cmdOut io.Writer, config util.FedConfig) error {
+	selector, err := labels.Parse(o.selector)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid label selector %q", o.selector)
+	}
+
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create host cluster client")
+	}
+
+	typeConfigs, err := o.typeConfigs(client)
+	if err != nil {
+		return err
+	}
+
+	patched := 0
+	for _, typeConfig := range typeConfigs {
+		fedType := typeConfig.GetFederatedType()
+		resourceClient, err := ctlutil.NewResourceClient(hostConfig, &fedType)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create client for %s", fedType.Kind)
+		}
+		objList, err := resourceClient.Resources(o.resourceNamespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to list %s in namespace %q", fedType.Kind, o.resourceNamespace)
+		}
+
+		for i := range objList.Items {
+			obj := &objList.Items[i]
+			qualifiedName := ctlutil.NewQualifiedName(obj)
+			changes, err := o.patch(obj)
+			if err != nil {
+				fmt.Fprintf(cmdOut, "Skipping %s %q: %v\n", fedType.Kind, qualifiedName, err)
+				continue
+			}
+			if len(changes) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(cmdOut, "%s %q:\n", fedType.Kind, qualifiedName)
+			for _, change := range changes {
+				fmt.Fprintf(cmdOut, "  %s\n", change)
+			}
+			patched++
+			if o.DryRun {
+				continue
+			}
+			_, err = resourceClient.Resources(obj.GetNamespace()).Update(obj, metav1.UpdateOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "Failed to update %s %q", fedType.Kind, qualifiedName)
+			}
+		}
+	}
+
+	if o.DryRun {
+		fmt.Fprintf(cmdOut, "%d federated resource(s) would be patched (dry run)\n", patched)
+	} else {
+		fmt.Fprintf(cmdOut, "%d federated resource(s) patched\n", patched)
+	}
+	return nil
+}
+
+// typeConfigs returns the FederatedTypeConfigs of the namespaced types
+// whose resources should be patched.
+func (o *patchPlacement) typeConfigs(client genericclient.Client) ([]*fedv1b1.FederatedTypeConfig, error) {
+	typeConfigList := &fedv1b1.FederatedTypeConfigList{}
+	err := client.List(context.TODO(), typeConfigList, o.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+
+	requested := sets.NewString(o.types...)
+	var typeConfigs []*fedv1b1.FederatedTypeConfig
+	for i := range typeConfigList.Items {
+		typeConfig := &typeConfigList.Items[i]
+		if requested.Len() > 0 && !requested.Has(typeConfig.Name) {
+			continue
+		}
+		requested.Delete(typeConfig.Name)
+		if !typeConfig.GetNamespaced() {
+			continue
+		}
+		typeConfigs = append(typeConfigs, typeConfig)
+	}
+	if requested.Len() > 0 {
+		return nil, errors.Errorf("FederatedTypeConfigs not found: %v", requested.List())
+	}
+	return typeConfigs, nil
+}
+
+// patch modifies the placement and overrides of the given federated
+// resource and returns a description of each change made.
+func (o *patchPlacementOptions) patch(obj *unstructured.Unstructured) ([]string, error) {
+	_, hasClusters, err := unstructured.NestedFieldNoCopy(obj.Object, ctlutil.SpecField, ctlutil.PlacementField, ctlutil.ClustersField)
+	if err != nil {
+		return nil, err
+	}
+	if !hasClusters {
+		for _, field := range []string{ctlutil.ClusterGroupsField, ctlutil.ClusterSelectorField, ctlutil.RequiredClusterSelectorField} {
+			_, ok, err := unstructured.NestedFieldNoCopy(obj.Object, ctlutil.SpecField, ctlutil.PlacementField, field)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return nil, errors.Errorf("placement is determined by %s rather than by cluster name", field)
+			}
+		}
+	}
+
+	clusterNames, err := ctlutil.GetClusterNames(obj)
+	if err != nil {
+		return nil, err
+	}
+	placed := sets.NewString(clusterNames...)
+
+	removed := sets.NewString(o.removeClusters...)
+	var changes []string
+	newClusterNames := []string{}
+	for _, clusterName := range clusterNames {
+		if removed.Has(clusterName) {
+			changes = append(changes, fmt.Sprintf("- spec.placement.clusters: %s", clusterName))
+			continue
+		}
+		newClusterNames = append(newClusterNames, clusterName)
+	}
+	for _, clusterName := range o.addClusters {
+		if placed.Has(clusterName) {
+			continue
+		}
+		placed.Insert(clusterName)
+		newClusterNames = append(newClusterNames, clusterName)
+		changes = append(changes, fmt.Sprintf("+ spec.placement.clusters: %s", clusterName))
+	}
+
+	overridesMap, err := ctlutil.GetOverrides(obj)
+	if err != nil {
+		return nil, err
+	}
+	var overrideChanges []string
+	for _, clusterName := range o.removeClusters {
+		if _, ok := overridesMap[clusterName]; ok {
+			delete(overridesMap, clusterName)
+			overrideChanges = append(overrideChanges, fmt.Sprintf("- spec.overrides: %s", clusterName))
+		}
+	}
+	if sourceOverrides, ok := overridesMap[o.copyOverridesFrom]; ok && len(o.copyOverridesFrom) > 0 {
+		for _, clusterName := range o.addClusters {
+			if _, ok := overridesMap[clusterName]; ok {
+				continue
+			}
+			overridesMap[clusterName] = append(ctlutil.ClusterOverrides{}, sourceOverrides...)
+			overrideChanges = append(overrideChanges, fmt.Sprintf("+ spec.overrides: %s (copied from %s)", clusterName, o.copyOverridesFrom))
+		}
+	}
+
+	if len(changes) > 0 {
+		if err := ctlutil.SetClusterNames(obj, newClusterNames); err != nil {
+			return nil, err
+		}
+	}
+	if len(overrideChanges) > 0 {
+		sort.Strings(overrideChanges)
+		if err := setOverrides(obj, overridesMap); err != nil {
+			return nil, err
+		}
+		changes = append(changes, overrideChanges...)
+	}
+	return changes, nil
+}
+
+// setOverrides sets the overrides of the given federated resource,
+// ordered by cluster name to keep the result stable.
+func setOverrides(obj *unstructured.Unstructured, overridesMap ctlutil.OverridesMap) error {
+	clusterNames := make([]string, 0, len(overridesMap))
+	for clusterName := range overridesMap {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+
+	overrideItems := []ctlutil.GenericOverrideItem{}
+	for _, clusterName := range clusterNames {
+		overrideItems = append(overrideItems, ctlutil.GenericOverrideItem{
+			ClusterName:      clusterName,
+			ClusterOverrides: overridesMap[clusterName],
+		})
+	}
+
+	// Round-trip the overrides through json to ensure the values can
+	// be set in an unstructured object.
+	data, err := json.Marshal(overrideItems)
+	if err != nil {
+		return err
+	}
+	overrides := []interface{}{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return err
+	}
+	return unstructured.SetNestedSlice(obj.Object, overrides, ctlutil.SpecField, ctlutil.OverridesField)
+}
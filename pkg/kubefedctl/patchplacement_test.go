@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func newPlacedResource(placement map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"placement": placement,
+				"overrides": []interface{}{
+					map[string]interface{}{
+						"clusterName": "prod-eu",
+						"clusterOverrides": []interface{}{
+							map[string]interface{}{"path": "/spec/replicas", "value": int64(5)},
+						},
+					},
+					map[string]interface{}{
+						"clusterName": "prod-us",
+						"clusterOverrides": []interface{}{
+							map[string]interface{}{"path": "/spec/replicas", "value": int64(3)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPatchPlacement(t *testing.T) {
+	obj := newPlacedResource(map[string]interface{}{
+		"clusters": []interface{}{
+			map[string]interface{}{"name": "prod-eu"},
+			map[string]interface{}{"name": "prod-us"},
+		},
+	})
+	o := &patchPlacementOptions{
+		addClusters:       []string{"prod-ap", "prod-eu"},
+		removeClusters:    []string{"prod-us"},
+		copyOverridesFrom: "prod-eu",
+	}
+
+	changes, err := o.patch(obj)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedChanges := []string{
+		"- spec.placement.clusters: prod-us",
+		"+ spec.placement.clusters: prod-ap",
+		"+ spec.overrides: prod-ap (copied from prod-eu)",
+		"- spec.overrides: prod-us",
+	}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Errorf("Expected changes %v, got %v", expectedChanges, changes)
+	}
+
+	clusterNames, err := ctlutil.GetClusterNames(obj)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedClusters := []string{"prod-eu", "prod-ap"}
+	if !reflect.DeepEqual(clusterNames, expectedClusters) {
+		t.Errorf("Expected clusters %v, got %v", expectedClusters, clusterNames)
+	}
+
+	overridesMap, err := ctlutil.GetOverrides(obj)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := overridesMap["prod-us"]; ok {
+		t.Errorf("Expected overrides for prod-us to be removed")
+	}
+	if !reflect.DeepEqual(overridesMap["prod-ap"], overridesMap["prod-eu"]) {
+		t.Errorf("Expected overrides for prod-ap to match prod-eu, got %v", overridesMap["prod-ap"])
+	}
+
+	// Patching again should be a no-op.
+	changes, err = o.patch(obj)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestPatchPlacementSkipsSelectorPlacement(t *testing.T) {
+	obj := newPlacedResource(map[string]interface{}{
+		"clusterSelector": map[string]interface{}{},
+	})
+	o := &patchPlacementOptions{addClusters: []string{"prod-ap"}}
+
+	if _, err := o.patch(obj); err == nil {
+		t.Fatalf("Expected an error for a resource placed by cluster selector")
+	}
+}
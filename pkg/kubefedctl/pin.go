@@ -0,0 +1,224 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	pin_long = `
+		Pin the version of a federated resource that is currently
+		propagated to one or more member clusters. Subsequent updates
+		to the federated resource are not propagated to a pinned
+		cluster, and the resource is not removed from it, until the
+		cluster is unpinned. The pinned version is recorded in the
+		PropagatedVersion of the resource.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	pin_example = `
+		# Hold back cluster prod-eu from updates to the FederatedDeployment named foo
+		kubefedctl pin federateddeployments foo --cluster prod-eu -n ns1 --host-cluster-context=cluster1`
+
+	unpin_long = `
+		Unpin the version of a federated resource in one or more member
+		clusters so that the clusters are updated to the current
+		version of the resource.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	unpin_example = `
+		# Resume updates of the FederatedDeployment named foo in cluster prod-eu
+		kubefedctl unpin federateddeployments foo --cluster prod-eu -n ns1 --host-cluster-context=cluster1`
+)
+
+type pinResource struct {
+	options.GlobalSubcommandOptions
+	typeName          string
+	resourceName      string
+	resourceNamespace string
+	clusterNames      []string
+	pin               bool
+}
+
+// Bind adds the pin specific arguments to the flagset passed in as an
+// argument.
+func (o *pinResource) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of the federated resource. Defaults to the namespace of the current context.")
+	flags.StringSliceVar(&o.clusterNames, "cluster", nil, "The name of a cluster. May be repeated.")
+}
+
+// NewCmdPin defines the `pin` command that holds back member clusters
+// from updates to a federated resource.
+func NewCmdPin(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	return newCmdPin(cmdOut, config, "pin", "Pin the version of a federated resource in member clusters",
+		pin_long, pin_example, true)
+}
+
+// NewCmdUnpin defines the `unpin` command that resumes updates to a
+// federated resource in member clusters.
+func NewCmdUnpin(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	return newCmdPin(cmdOut, config, "unpin", "Unpin the version of a federated resource in member clusters",
+		unpin_long, unpin_example, false)
+}
+
+func newCmdPin(cmdOut io.Writer, config util.FedConfig, verb, short, long, example string, pin bool) *cobra.Command {
+	opts := &pinResource{pin: pin}
+
+	cmd := &cobra.Command{
+		Use:     verb + " TYPE NAME --cluster=CLUSTER_NAME",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Complete ensures that options are valid and marshals them if necessary.
+func (o *pinResource) Complete(args []string, config util.FedConfig) error {
+	if len(args) != 2 {
+		return errors.New("a federated type and a resource name are required")
+	}
+	o.typeName = args[0]
+	o.resourceName = args[1]
+
+	if len(o.clusterNames) == 0 {
+		return errors.New("at least one --cluster is required")
+	}
+
+	if len(o.resourceNamespace) == 0 {
+		var err error
+		o.resourceNamespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		return err
+	}
+	return nil
+}
+
+// Run implements the `pin` and `unpin` commands.
+func (o *pinResource) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	apiResource, err := enable.LookupAPIResource(hostConfig, o.typeName, "")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to find targeted %s type", o.typeName)
+	}
+	if !util.IsFederatedAPIResource(apiResource.Kind, apiResource.Group) {
+		return errors.Errorf("%s is not a federated type", o.typeName)
+	}
+	client, err := ctlutil.NewResourceClient(hostConfig, apiResource)
+	if err != nil {
+		return errors.Wrapf(err, "Error creating client for %s", apiResource.Kind)
+	}
+	resourceClient := client.Resources(o.resourceNamespace)
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: o.resourceNamespace, Name: o.resourceName}
+	obj, err := resourceClient.Get(o.resourceName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", apiResource.Kind, qualifiedName)
+	}
+
+	pinned, err := pinnedClusters(obj, o.clusterNames, o.pin)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to %s %s %q", pinVerb(o.pin), apiResource.Kind, qualifiedName)
+	}
+	if pinned.Equal(ctlutil.PinnedClusters(obj)) {
+		fmt.Fprintf(cmdOut, "%s %q is already %sned in cluster(s) %s\n", apiResource.Kind, qualifiedName, pinVerb(o.pin), strings.Join(o.clusterNames, ", "))
+		return nil
+	}
+	if o.DryRun {
+		fmt.Fprintf(cmdOut, "%s %q would be %sned in cluster(s) %s (dry run)\n", apiResource.Kind, qualifiedName, pinVerb(o.pin), strings.Join(o.clusterNames, ", "))
+		return nil
+	}
+
+	ctlutil.SetPinnedClusters(obj, pinned)
+	_, err = resourceClient.Update(obj, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update %s %q", apiResource.Kind, qualifiedName)
+	}
+	fmt.Fprintf(cmdOut, "%s %q %sned in cluster(s) %s\n", apiResource.Kind, qualifiedName, pinVerb(o.pin), strings.Join(o.clusterNames, ", "))
+	return nil
+}
+
+// pinnedClusters returns the clusters the given federated resource is
+// pinned in once the named clusters are pinned or unpinned. Only a
+// cluster the resource is currently propagated to can be pinned.
+func pinnedClusters(obj *unstructured.Unstructured, clusterNames []string, pin bool) (sets.String, error) {
+	pinned := ctlutil.PinnedClusters(obj)
+	if !pin {
+		return pinned.Difference(sets.NewString(clusterNames...)), nil
+	}
+
+	propagated, err := status.PropagatedClusterNames(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterName := range clusterNames {
+		if !pinned.Has(clusterName) && !propagated.Has(clusterName) {
+			return nil, errors.Errorf("the resource is not propagated to cluster %q", clusterName)
+		}
+		pinned.Insert(clusterName)
+	}
+	return pinned, nil
+}
+
+func pinVerb(pin bool) string {
+	if pin {
+		return "pin"
+	}
+	return "unpin"
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestPinnedClusters(t *testing.T) {
+	testCases := map[string]struct {
+		pinned        string
+		clusterNames  []string
+		pin           bool
+		expected      sets.String
+		expectedError bool
+	}{
+		"Propagated cluster can be pinned": {
+			clusterNames: []string{"cluster1"},
+			pin:          true,
+			expected:     sets.NewString("cluster1"),
+		},
+		"Cluster with failed propagation cannot be pinned": {
+			clusterNames:  []string{"cluster2"},
+			pin:           true,
+			expectedError: true,
+		},
+		"Cluster the resource is not propagated to cannot be pinned": {
+			clusterNames:  []string{"cluster3"},
+			pin:           true,
+			expectedError: true,
+		},
+		"Pinned cluster remains pinned": {
+			pinned:       "cluster2",
+			clusterNames: []string{"cluster1", "cluster2"},
+			pin:          true,
+			expected:     sets.NewString("cluster1", "cluster2"),
+		},
+		"Cluster can be unpinned": {
+			pinned:       "cluster1,cluster2",
+			clusterNames: []string{"cluster2"},
+			expected:     sets.NewString("cluster1"),
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{
+					"clusters": []interface{}{
+						map[string]interface{}{"name": "cluster1"},
+						map[string]interface{}{"name": "cluster2", "status": "UpdateFailed"},
+					},
+				},
+			}}
+			if len(tc.pinned) > 0 {
+				obj.SetAnnotations(map[string]string{ctlutil.PinnedClustersAnnotation: tc.pinned})
+			}
+			pinned, err := pinnedClusters(obj, tc.clusterNames, tc.pin)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !pinned.Equal(tc.expected) {
+				t.Errorf("Expected pinned clusters %v, got %v", tc.expected.List(), pinned.List())
+			}
+		})
+	}
+}
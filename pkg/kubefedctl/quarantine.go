@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	quarantine_add_long = `
+		Quarantine a cluster so that no resources are propagated
+		to it until the quarantine is released.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	quarantine_add_example = `
+		# Quarantine cluster foo
+		kubefedctl quarantine add foo --host-cluster-context=bar`
+
+	quarantine_release_long = `
+		Release the quarantine of a cluster so that resources
+		are propagated to it again.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	quarantine_release_example = `
+		# Release the quarantine of cluster foo
+		kubefedctl quarantine release foo --host-cluster-context=bar`
+)
+
+type quarantineCluster struct {
+	options.GlobalSubcommandOptions
+	name    string
+	release bool
+}
+
+// NewCmdQuarantine defines the `quarantine` command that quarantines
+// clusters or releases their quarantine.
+func NewCmdQuarantine(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quarantine",
+		Short: "Quarantine a cluster or release its quarantine",
+		Long:  "Quarantine a cluster or release its quarantine",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+	cmd.AddCommand(newCmdQuarantineCluster(cmdOut, config, "add", "Quarantine a cluster",
+		quarantine_add_long, quarantine_add_example, false))
+	cmd.AddCommand(newCmdQuarantineCluster(cmdOut, config, "release", "Release the quarantine of a cluster",
+		quarantine_release_long, quarantine_release_example, true))
+
+	return cmd
+}
+
+func newCmdQuarantineCluster(cmdOut io.Writer, config util.FedConfig, verb, short, long, example string, release bool) *cobra.Command {
+	opts := &quarantineCluster{release: release}
+
+	cmd := &cobra.Command{
+		Use:     verb + " CLUSTER_NAME --host-cluster-context=HOST_CONTEXT",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				klog.Fatalf("Error: CLUSTER_NAME is required")
+			}
+			opts.name = args[0]
+
+			err := opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.GlobalSubcommandBind(cmd.Flags())
+
+	return cmd
+}
+
+// Run quarantines the cluster or releases its quarantine.
+func (o *quarantineCluster) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostClientConfig := config.GetClientConfig(o.HostClusterContext, o.Kubeconfig)
+	if err := o.SetHostClusterContextFromConfig(hostClientConfig); err != nil {
+		return err
+	}
+	hostConfig, err := hostClientConfig.ClientConfig()
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return err
+	}
+
+	cluster := &fedv1b1.KubeFedCluster{}
+	err = client.Get(context.TODO(), cluster, o.KubeFedNamespace, o.name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get KubeFedCluster %q", o.name)
+	}
+
+	quarantined := cluster.Status.Quarantine != nil
+	if o.release && !quarantined {
+		return errors.Errorf("KubeFedCluster %q is not quarantined", o.name)
+	}
+	if !o.release && quarantined {
+		return errors.Errorf("KubeFedCluster %q is already quarantined", o.name)
+	}
+
+	if o.release {
+		cluster.Status.Quarantine = nil
+	} else {
+		cluster.Status.Quarantine = &fedv1b1.ClusterQuarantine{
+			Reason:    "Manual",
+			Message:   "Quarantined by kubefedctl",
+			StartTime: metav1.Now(),
+		}
+	}
+	if o.DryRun {
+		return nil
+	}
+	err = client.UpdateStatus(context.TODO(), cluster)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update KubeFedCluster %q", o.name)
+	}
+
+	if o.release {
+		fmt.Fprintf(cmdOut, "Released the quarantine of cluster %q\n", o.name)
+	} else {
+		fmt.Fprintf(cmdOut, "Quarantined cluster %q\n", o.name)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictFail      = "fail"
+
+	// The CRD of a federated type may take a little while to be
+	// established after it is restored.
+	restoreRetryTimeout  = 30 * time.Second
+	restoreRetryInterval = 1 * time.Second
+)
+
+var (
+	restore_long = `
+		Restore replays a file exported by kubefedctl backup to
+		rebuild a KubeFed control plane. The control plane needs
+		to be deployed before the file is restored.
+
+		Resources in member clusters that were propagated by the
+		original control plane are adopted by the restored
+		control plane rather than recreated.
+
+		Current context is assumed to be a Kubernetes cluster
+		hosting a KubeFed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+	restore_example = `
+		# Restore a control plane to the cluster with context bar,
+		# replacing resources that already exist
+		kubefedctl restore kubefed-backup.yaml --host-cluster-context=bar --on-conflict=overwrite`
+)
+
+type restoreControlPlane struct {
+	options.GlobalSubcommandOptions
+	filename   string
+	onConflict string
+}
+
+// Bind adds the restore specific arguments to the flagset passed in
+// as an argument.
+func (o *restoreControlPlane) Bind(flags *pflag.FlagSet) {
+	flags.StringVar(&o.onConflict, "on-conflict", conflictSkip,
+		"How to handle resources of the backup that already exist. Valid values are 'skip', 'overwrite' and 'fail'.")
+}
+
+// Complete ensures that options are valid.
+func (o *restoreControlPlane) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("FILENAME is required")
+	}
+	o.filename = args[0]
+
+	switch o.onConflict {
+	case conflictSkip, conflictOverwrite, conflictFail:
+	default:
+		return errors.Errorf("Invalid value for --on-conflict: %s", o.onConflict)
+	}
+	return nil
+}
+
+// NewCmdRestore defines the `restore` command that rebuilds a KubeFed
+// control plane from a file exported by the `backup` command.
+func NewCmdRestore(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &restoreControlPlane{}
+
+	cmd := &cobra.Command{
+		Use:     "restore FILENAME --host-cluster-context=HOST_CONTEXT",
+		Short:   "Restore a KubeFed control plane from a file",
+		Long:    restore_long,
+		Example: restore_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Run restores the resources of the file.
+func (o *restoreControlPlane) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+
+	objs, err := federate.DecodeUnstructuredFromFile(o.filename)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read %q", o.filename)
+	}
+	return o.importControlPlane(cmdOut, hostConfig, objs)
+}
+
+// importControlPlane creates the given resources exported from a
+// control plane.
+func (o *restoreControlPlane) importControlPlane(cmdOut io.Writer, hostConfig *rest.Config, objs []*unstructured.Unstructured) error {
+	apiResources, err := restoreAPIResources(objs)
+	if err != nil {
+		return err
+	}
+	sortForRestore(objs)
+
+	kubeClient, err := kubeclient.NewForConfig(hostConfig)
+	if err != nil {
+		return err
+	}
+
+	namespaces := sets.NewString()
+	secrets := sets.NewString()
+	for _, obj := range objs {
+		apiResource, ok := apiResources[obj.GroupVersionKind().GroupKind()]
+		if !ok {
+			return errors.Errorf("The backup does not include a FederatedTypeConfig for %s %q", obj.GetKind(), obj.GetName())
+		}
+
+		namespace := obj.GetNamespace()
+		if apiResource.Namespaced && !namespaces.Has(namespace) {
+			err := o.ensureNamespace(cmdOut, kubeClient, namespace)
+			if err != nil {
+				return err
+			}
+			namespaces.Insert(namespace)
+		}
+
+		switch obj.GroupVersionKind().GroupKind() {
+		case schema.GroupKind{Kind: ctlutil.SecretKind}:
+			secrets.Insert(ctlutil.NewQualifiedName(obj).String())
+		case schema.GroupKind{Group: fedv1b1.SchemeGroupVersion.Group, Kind: kubeFedClusterAPIResource.Kind}:
+			o.checkClusterSecret(cmdOut, kubeClient, obj, secrets)
+		}
+
+		client, err := ctlutil.NewResourceClient(hostConfig, &apiResource)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create client for %s", apiResource.Kind)
+		}
+		err = o.restoreObject(cmdOut, client.Resources(namespace), obj)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreAPIResources returns the api resources of the kinds of
+// objects that can be restored from the given objects.
+func restoreAPIResources(objs []*unstructured.Unstructured) (map[schema.GroupKind]metav1.APIResource, error) {
+	apiResources := make(map[schema.GroupKind]metav1.APIResource)
+	for _, apiResource := range []metav1.APIResource{customResourceDefinitionAPIResource, federatedTypeConfigAPIResource, kubeFedClusterAPIResource, secretAPIResource} {
+		apiResources[schema.GroupKind{Group: apiResource.Group, Kind: apiResource.Kind}] = apiResource
+	}
+	for _, obj := range filterByGroupKind(objs, federatedTypeConfigAPIResource) {
+		typeConfig, err := decodeTypeConfig(obj)
+		if err != nil {
+			return nil, err
+		}
+		federatedAPIResource := typeConfig.GetFederatedType()
+		apiResources[schema.GroupKind{Group: federatedAPIResource.Group, Kind: federatedAPIResource.Kind}] = federatedAPIResource
+	}
+	return apiResources, nil
+}
+
+func (o *restoreControlPlane) ensureNamespace(cmdOut io.Writer, client kubeclient.Interface, name string) error {
+	_, err := client.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "Failed to get namespace %q", name)
+	}
+	if !o.DryRun {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		_, err = client.CoreV1().Namespaces().Create(namespace)
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "Failed to create namespace %q", name)
+		}
+	}
+	fmt.Fprintf(cmdOut, "Created namespace %q\n", name)
+	return nil
+}
+
+// checkClusterSecret warns if the secret holding the credentials of
+// the given cluster is neither restored nor present.
+func (o *restoreControlPlane) checkClusterSecret(cmdOut io.Writer, client kubeclient.Interface, cluster *unstructured.Unstructured, restoredSecrets sets.String) {
+	secretName, _, _ := unstructured.NestedString(cluster.Object, "spec", "secretRef", "name")
+	secret := ctlutil.QualifiedName{Namespace: cluster.GetNamespace(), Name: secretName}
+	if restoredSecrets.Has(secret.String()) {
+		return
+	}
+	_, err := client.CoreV1().Secrets(secret.Namespace).Get(secret.Name, metav1.GetOptions{})
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(cmdOut, "Warning: secret %q of KubeFedCluster %q could not be retrieved: %v. "+
+		"The cluster will not become ready until the secret is created or the cluster is joined again.\n",
+		secret, cluster.GetName(), err)
+}
+
+func (o *restoreControlPlane) restoreObject(cmdOut io.Writer, client dynamic.ResourceInterface, obj *unstructured.Unstructured) error {
+	kind := obj.GetKind()
+	qualifiedName := ctlutil.NewQualifiedName(obj)
+	if o.DryRun {
+		fmt.Fprintf(cmdOut, "Restored %s %q (dry run)\n", kind, qualifiedName)
+		return nil
+	}
+
+	exists := false
+	err := wait.PollImmediate(restoreRetryInterval, restoreRetryTimeout, func() (bool, error) {
+		_, err := client.Create(obj, metav1.CreateOptions{})
+		switch {
+		case err == nil:
+			return true, nil
+		case apierrors.IsNotFound(err):
+			return false, nil
+		case apierrors.IsAlreadyExists(err):
+			exists = true
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create %s %q", kind, qualifiedName)
+	}
+	if !exists {
+		fmt.Fprintf(cmdOut, "Restored %s %q\n", kind, qualifiedName)
+		return nil
+	}
+
+	switch o.onConflict {
+	case conflictSkip:
+		fmt.Fprintf(cmdOut, "Skipped %s %q since it already exists\n", kind, qualifiedName)
+		return nil
+	case conflictFail:
+		return errors.Errorf("%s %q already exists", kind, qualifiedName)
+	}
+
+	existing, err := client.Get(obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get %s %q", kind, qualifiedName)
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(obj, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update %s %q", kind, qualifiedName)
+	}
+	fmt.Fprintf(cmdOut, "Overwrote %s %q\n", kind, qualifiedName)
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sched
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+// NewCmdSched the head of scheduling sub commands
+func NewCmdSched(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sched",
+		Short: "Inspect scheduling of federated workloads",
+		Long:  "Inspect scheduling of federated workloads",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+	cmd.AddCommand(newCmdSimulate(cmdOut, config))
+
+	return cmd
+}
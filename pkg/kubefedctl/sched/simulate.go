@@ -0,0 +1,373 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sched
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+	"sigs.k8s.io/kubefed/pkg/features"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+	"sigs.k8s.io/kubefed/pkg/schedulingtypes"
+)
+
+var (
+	simulate_long = `
+		Computes the replica distribution and cluster placement that
+		the scheduler would produce for a ReplicaSchedulingPreference
+		against the current state of member clusters, without creating
+		or updating any resources.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	simulate_example = `
+		# Simulate scheduling of the ReplicaSchedulingPreference in rsp.yaml
+		kubefedctl sched simulate -f rsp.yaml --host-cluster-context=cluster1`
+)
+
+type simulateSchedule struct {
+	options.GlobalSubcommandOptions
+	filename          string
+	resourceNamespace string
+}
+
+// Bind adds the simulate specific arguments to the flagset passed in as an argument.
+func (o *simulateSchedule) Bind(flags *pflag.FlagSet) error {
+	flags.StringVarP(&o.filename, "filename", "f", "", "File containing the ReplicaSchedulingPreference to simulate, or '-' for stdin.")
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of the ReplicaSchedulingPreference if not set in the file.")
+	return flags.MarkHidden("dry-run")
+}
+
+func newCmdSimulate(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &simulateSchedule{}
+	cmd := &cobra.Command{
+		Use:     "simulate -f FILENAME",
+		Short:   "Simulate the scheduling of a ReplicaSchedulingPreference",
+		Long:    simulate_long,
+		Example: simulate_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	err := opts.Bind(flags)
+	if err != nil {
+		klog.Fatalf("Error: %v", err)
+	}
+
+	return cmd
+}
+
+// Complete ensures that options are valid and marshals them if necessary.
+func (o *simulateSchedule) Complete(args []string, config util.FedConfig) error {
+	if len(o.filename) == 0 {
+		return errors.New("a file containing a ReplicaSchedulingPreference must be provided with --filename")
+	}
+	if len(o.resourceNamespace) == 0 {
+		var err error
+		o.resourceNamespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		return err
+	}
+	return nil
+}
+
+// Run implements the `simulate` command.
+func (o *simulateSchedule) Run(cmdOut io.Writer, config util.FedConfig) error {
+	rsp, err := o.readRSP()
+	if err != nil {
+		return err
+	}
+	qualifiedName := ctlutil.NewQualifiedName(rsp)
+
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	hostClient, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create host cluster client")
+	}
+
+	fedConfig, err := options.GetKubeFedConfig(hostConfig, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err = hostClient.List(context.TODO(), clusterList, o.KubeFedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list KubeFedClusters")
+	}
+	clusters := []*fedv1b1.KubeFedCluster{}
+	for i := range clusterList.Items {
+		clusters = append(clusters, &clusterList.Items[i])
+	}
+	clusterClients, err := o.readyClusterClients(hostConfig, hostClient, clusters)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := o.schedulingInputs(hostClient, fedConfig, rsp, clusters, clusterClients)
+	if err != nil {
+		return err
+	}
+	clusterNames := schedulingtypes.SchedulingClusterNames(clusters)
+	simulation, err := schedulingtypes.SimulateSchedule(rsp, qualifiedName, clusterNames, inputs)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to simulate scheduling of %q", qualifiedName)
+	}
+
+	return writeSimulation(cmdOut, clusterNames, inputs.AntiAffinityClusters, simulation)
+}
+
+// schedulingInputs returns the state of the federation that the RSP is
+// scheduled against, read the same way the scheduler observes it.
+func (o *simulateSchedule) schedulingInputs(hostClient genericclient.Client, fedConfig *fedv1b1.KubeFedConfig, rsp *fedschedulingv1a1.ReplicaSchedulingPreference,
+	clusters []*fedv1b1.KubeFedCluster, clusterClients map[string]genericclient.Client) (*schedulingtypes.SchedulingInputs, error) {
+
+	typeConfig, err := o.targetTypeConfig(hostClient, rsp.Spec.TargetKind)
+	if err != nil {
+		return nil, err
+	}
+	excludedClusters, err := antiAffinityClusters(hostClient, rsp)
+	if err != nil {
+		return nil, err
+	}
+
+	targetType := typeConfig.GetTargetType()
+	targetAPIVersion := schema.GroupVersion{Group: targetType.Group, Version: targetType.Version}.String()
+	inputs := &schedulingtypes.SchedulingInputs{
+		Clusters:             clusters,
+		AntiAffinityClusters: excludedClusters,
+		// Workloads in clusters that are not ready cannot be read,
+		// as the scheduler cannot observe them either.
+		ObjectGetter: func(clusterName, key string) (interface{}, bool, error) {
+			client, ok := clusterClients[clusterName]
+			if !ok {
+				return nil, false, nil
+			}
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(targetAPIVersion)
+			obj.SetKind(targetType.Kind)
+			err := client.Get(context.TODO(), obj, rsp.Namespace, rsp.Name)
+			if apierrors.IsNotFound(err) {
+				return nil, false, nil
+			}
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "Failed to retrieve %s %q from cluster %q", targetType.Kind, key, clusterName)
+			}
+			return obj, true, nil
+		},
+		PodsGetter: func(clusterName string, obj *unstructured.Unstructured) (*corev1.PodList, error) {
+			return schedulingtypes.ListWorkloadPods(clusterClients[clusterName], obj)
+		},
+	}
+
+	inputs.Placement, err = targetPlacement(hostClient, typeConfig, rsp)
+	if err != nil {
+		return nil, err
+	}
+	if featureEnabled(fedConfig, features.HealthInterpretation) {
+		inputs.HealthInterpreter, err = health.ForTypeConfig(typeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if featureEnabled(fedConfig, features.ClusterAllowlists) {
+		allowlistList := &fedv1b1.ClusterAllowlistList{}
+		err = hostClient.List(context.TODO(), allowlistList, o.KubeFedNamespace)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list ClusterAllowlists")
+		}
+		inputs.ClusterAllowlists = []*fedv1b1.ClusterAllowlist{}
+		for i := range allowlistList.Items {
+			inputs.ClusterAllowlists = append(inputs.ClusterAllowlists, &allowlistList.Items[i])
+		}
+	}
+	return inputs, nil
+}
+
+// targetTypeConfig returns the FederatedTypeConfig of the given
+// federated kind.
+func (o *simulateSchedule) targetTypeConfig(hostClient genericclient.Client, federatedKind string) (*fedv1b1.FederatedTypeConfig, error) {
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	err := hostClient.List(context.TODO(), typeConfigs, o.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+	for i := range typeConfigs.Items {
+		typeConfig := &typeConfigs.Items[i]
+		if typeConfig.GetFederatedType().Kind == federatedKind {
+			return typeConfig, nil
+		}
+	}
+	return nil, errors.Errorf("Target kind %q is not enabled for scheduling", federatedKind)
+}
+
+// targetPlacement returns the placement of the target federated
+// resource of the RSP, or nil if it does not exist.
+func targetPlacement(hostClient genericclient.Client, typeConfig *fedv1b1.FederatedTypeConfig, rsp *fedschedulingv1a1.ReplicaSchedulingPreference) (*ctlutil.GenericPlacement, error) {
+	federatedType := typeConfig.GetFederatedType()
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion(schema.GroupVersion{Group: federatedType.Group, Version: federatedType.Version}.String())
+	fedObject.SetKind(federatedType.Kind)
+	err := hostClient.Get(context.TODO(), fedObject, rsp.Namespace, rsp.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve %s %q", federatedType.Kind, ctlutil.NewQualifiedName(rsp))
+	}
+	return ctlutil.UnmarshalGenericPlacement(fedObject)
+}
+
+// featureEnabled returns whether the given feature gate is enabled by
+// the KubeFedConfig of the control plane.
+func featureEnabled(fedConfig *fedv1b1.KubeFedConfig, feature featuregate.Feature) bool {
+	for _, featureGate := range fedConfig.Spec.FeatureGates {
+		if featureGate.Name == string(feature) {
+			return featureGate.Configuration == fedv1b1.ConfigurationEnabled
+		}
+	}
+	return features.DefaultKubeFedFeatureGates[feature].Default
+}
+
+// readRSP reads the ReplicaSchedulingPreference from the configured file.
+func (o *simulateSchedule) readRSP() (*fedschedulingv1a1.ReplicaSchedulingPreference, error) {
+	objs, err := federate.DecodeUnstructuredFromFile(o.filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode %q", o.filename)
+	}
+	for _, obj := range objs {
+		if obj.GetKind() != schedulingtypes.RSPKind {
+			continue
+		}
+		rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{}
+		if err := ctlutil.UnstructuredToInterface(obj, rsp); err != nil {
+			return nil, errors.Wrapf(err, "Failed to decode %s", schedulingtypes.RSPKind)
+		}
+		if len(rsp.Namespace) == 0 {
+			rsp.Namespace = o.resourceNamespace
+		}
+		return rsp, nil
+	}
+	return nil, errors.Errorf("No %s found in %q", schedulingtypes.RSPKind, o.filename)
+}
+
+// readyClusterClients returns clients for the given member clusters
+// that are ready, keyed by cluster name.
+func (o *simulateSchedule) readyClusterClients(hostConfig *rest.Config, hostClient genericclient.Client, clusters []*fedv1b1.KubeFedCluster) (map[string]genericclient.Client, error) {
+	clients := make(map[string]genericclient.Client)
+	for _, cluster := range clusters {
+		if !ctlutil.IsClusterReady(&cluster.Status) {
+			klog.V(2).Infof("Not reading the state of cluster %q that is not ready", cluster.Name)
+			continue
+		}
+		clusterConfig, err := ctlutil.BuildClusterConfig(cluster, hostClient, o.KubeFedNamespace, hostConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to build configuration for cluster %q", cluster.Name)
+		}
+		client, err := genericclient.New(clusterConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create client for cluster %q", cluster.Name)
+		}
+		clients[cluster.Name] = client
+	}
+	return clients, nil
+}
+
+// antiAffinityClusters returns the names of the clusters in which the
+// workloads named by the cluster anti-affinity of the RSP are
+// scheduled.
+func antiAffinityClusters(hostClient genericclient.Client, rsp *fedschedulingv1a1.ReplicaSchedulingPreference) (sets.String, error) {
+	excludedClusters := sets.String{}
+	for _, term := range rsp.Spec.ClusterAntiAffinity {
+		fedObject := &unstructured.Unstructured{}
+		fedObject.SetAPIVersion("types.kubefed.io/v1beta1")
+		fedObject.SetKind(term.TargetKind)
+		err := hostClient.Get(context.TODO(), fedObject, rsp.Namespace, term.Name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve %s %q", term.TargetKind, term.Name)
+		}
+		scheduledClusters, err := schedulingtypes.ScheduledClusters(fedObject)
+		if err != nil {
+			return nil, err
+		}
+		excludedClusters = excludedClusters.Union(scheduledClusters)
+	}
+	return excludedClusters, nil
+}
+
+func writeSimulation(cmdOut io.Writer, clusterNames []string, excludedClusters sets.String, simulation *schedulingtypes.ScheduleSimulation) error {
+	clusterNames = append([]string{}, clusterNames...)
+	sort.Strings(clusterNames)
+
+	w := tabwriter.NewWriter(cmdOut, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tCURRENT\tCAPACITY\tREPLICAS\tPLACED")
+	for _, clusterName := range clusterNames {
+		capacity := "-"
+		if value, ok := simulation.EstimatedCapacity[clusterName]; ok {
+			capacity = fmt.Sprintf("%d", value)
+		}
+		replicas, placed := simulation.Replicas[clusterName]
+		placement := fmt.Sprintf("%t", placed)
+		if excludedClusters.Has(clusterName) {
+			placement = "false (anti-affinity)"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\n", clusterName, simulation.CurrentReplicas[clusterName], capacity, replicas, placement)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const undeletePollInterval = 1 * time.Second
+
+var (
+	undelete_long = `
+		Undelete cancels the deletion of a federated resource whose
+		removal from member clusters is held by the deletionHold of
+		the sync controller. The resource is removed from the host
+		cluster with its resources in member clusters retained, and
+		then created again so that the retained resources are
+		adopted. Resources in member clusters are not adopted if the
+		adoption of resources is disabled.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	undelete_example = `
+		# Cancel the deletion of the FederatedDeployment named foo
+		kubefedctl undelete federateddeployments foo -n ns1 --host-cluster-context=cluster1`
+)
+
+type undeleteResource struct {
+	options.GlobalSubcommandOptions
+	typeName          string
+	resourceName      string
+	resourceNamespace string
+	timeout           time.Duration
+}
+
+// Bind adds the undelete specific arguments to the flagset passed in
+// as an argument.
+func (o *undeleteResource) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of the federated resource. Defaults to the namespace of the current context.")
+	flags.DurationVar(&o.timeout, "timeout", 2*time.Minute, "How long to wait for the deleted federated resource to be removed before it is created again.")
+}
+
+// NewCmdUndelete defines the `undelete` command that cancels the held
+// deletion of a federated resource.
+func NewCmdUndelete(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &undeleteResource{}
+
+	cmd := &cobra.Command{
+		Use:     "undelete TYPE NAME",
+		Short:   "Cancel the held deletion of a federated resource",
+		Long:    undelete_long,
+		Example: undelete_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Complete ensures that options are valid and marshals them if necessary.
+func (o *undeleteResource) Complete(args []string, config util.FedConfig) error {
+	if len(args) != 2 {
+		return errors.New("a federated type and a resource name are required")
+	}
+	o.typeName = args[0]
+	o.resourceName = args[1]
+
+	if len(o.resourceNamespace) == 0 {
+		var err error
+		o.resourceNamespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		return err
+	}
+	return nil
+}
+
+// Run implements the `undelete` command.
+func (o *undeleteResource) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration for cluster context %q in kubeconfig %q.",
+			o.HostClusterContext, o.Kubeconfig)
+	}
+	apiResource, err := enable.LookupAPIResource(hostConfig, o.typeName, "")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to find targeted %s type", o.typeName)
+	}
+	if !util.IsFederatedAPIResource(apiResource.Kind, apiResource.Group) {
+		return errors.Errorf("%s is not a federated type", o.typeName)
+	}
+	client, err := ctlutil.NewResourceClient(hostConfig, apiResource)
+	if err != nil {
+		return errors.Wrapf(err, "Error creating client for %s", apiResource.Kind)
+	}
+	resourceClient := client.Resources(o.resourceNamespace)
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: o.resourceNamespace, Name: o.resourceName}
+	obj, err := resourceClient.Get(o.resourceName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", apiResource.Kind, qualifiedName)
+	}
+	if err := checkDeletionHeld(obj, time.Now()); err != nil {
+		return errors.Wrapf(err, "Unable to undelete %s %q", apiResource.Kind, qualifiedName)
+	}
+	if o.DryRun {
+		fmt.Fprintf(cmdOut, "%s %q would be undeleted (dry run)\n", apiResource.Kind, qualifiedName)
+		return nil
+	}
+
+	restoredObj := obj.DeepCopy()
+	cleanForBackup(restoredObj)
+	restoredObj.SetOwnerReferences(obj.GetOwnerReferences())
+
+	// Orphaning the managed resources allows the sync controller to
+	// remove its finalizer without removing the resources from member
+	// clusters.
+	ctlutil.EnableOrphaning(obj)
+	_, err = resourceClient.Update(obj, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retain the resources of %s %q in member clusters", apiResource.Kind, qualifiedName)
+	}
+	fmt.Fprintf(cmdOut, "Waiting for %s %q to be removed with its resources retained in member clusters\n", apiResource.Kind, qualifiedName)
+
+	err = wait.PollImmediate(undeletePollInterval, o.timeout, func() (bool, error) {
+		_, err := resourceClient.Get(o.resourceName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to wait for the removal of %s %q", apiResource.Kind, qualifiedName)
+	}
+
+	_, err = resourceClient.Create(restoredObj, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create %s %q again", apiResource.Kind, qualifiedName)
+	}
+	fmt.Fprintf(cmdOut, "%s %q undeleted\n", apiResource.Kind, qualifiedName)
+	return nil
+}
+
+// checkDeletionHeld returns an error if the given federated resource
+// is not being deleted or if the removal of its resources from member
+// clusters is no longer held at the given time.
+func checkDeletionHeld(obj *unstructured.Unstructured, now time.Time) error {
+	if obj.GetDeletionTimestamp() == nil {
+		return errors.New("the resource is not being deleted")
+	}
+	value, _, err := unstructured.NestedString(obj.Object, ctlutil.StatusField, "deletionHeldUntil")
+	if err != nil {
+		return err
+	}
+	if len(value) == 0 {
+		return errors.New("the removal of the resource from member clusters is not held")
+	}
+	heldUntil, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return errors.Wrapf(err, "invalid deletionHeldUntil %q", value)
+	}
+	if !now.Before(heldUntil) {
+		return errors.Errorf("the deletion hold expired at %s", value)
+	}
+	return nil
+}
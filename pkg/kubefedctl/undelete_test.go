@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCheckDeletionHeld(t *testing.T) {
+	now := time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+	deletionTimestamp := metav1.NewTime(now.Add(-time.Minute))
+
+	testCases := map[string]struct {
+		deleted       bool
+		heldUntil     string
+		expectedError bool
+	}{
+		"Resource that is not deleted cannot be undeleted": {
+			expectedError: true,
+		},
+		"Deleted resource without a hold cannot be undeleted": {
+			deleted:       true,
+			expectedError: true,
+		},
+		"Deleted resource with an expired hold cannot be undeleted": {
+			deleted:       true,
+			heldUntil:     "2020-03-01T12:00:00Z",
+			expectedError: true,
+		},
+		"Deleted resource with an invalid hold cannot be undeleted": {
+			deleted:       true,
+			heldUntil:     "soon",
+			expectedError: true,
+		},
+		"Deleted resource with a hold can be undeleted": {
+			deleted:   true,
+			heldUntil: "2020-03-01T12:05:00Z",
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tc.deleted {
+				obj.SetDeletionTimestamp(&deletionTimestamp)
+			}
+			if len(tc.heldUntil) > 0 {
+				obj.Object["status"] = map[string]interface{}{
+					"deletionHeldUntil": tc.heldUntil,
+				}
+			}
+			err := checkDeletionHeld(obj, now)
+			if tc.expectedError && err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if !tc.expectedError && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
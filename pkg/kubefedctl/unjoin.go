@@ -244,19 +244,23 @@ func deleteFederatedClusterAndSecret(hostClientset kubeclient.Interface, client
 		return errors.Wrapf(err, "Failed to get kubefed cluster \"%s/%s\"", kubefedNamespace, unjoiningClusterName)
 	}
 
-	err = hostClientset.CoreV1().Secrets(kubefedNamespace).Delete(fedCluster.Spec.SecretRef.Name,
-		&metav1.DeleteOptions{})
-	if apierrors.IsNotFound(err) {
-		klog.V(2).Infof("Secret \"%s/%s\" does not exist in the host cluster.", kubefedNamespace, fedCluster.Spec.SecretRef.Name)
-	} else if err != nil {
-		wrappedErr := errors.Wrapf(err, "Failed to delete secret \"%s/%s\" for unjoin cluster %q",
-			kubefedNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
-		if !forceDeletion {
-			return wrappedErr
+	// A cluster accessed with the service account of the control
+	// plane does not reference a secret.
+	if fedCluster.Spec.SecretRef.Name != "" {
+		err = hostClientset.CoreV1().Secrets(kubefedNamespace).Delete(fedCluster.Spec.SecretRef.Name,
+			&metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			klog.V(2).Infof("Secret \"%s/%s\" does not exist in the host cluster.", kubefedNamespace, fedCluster.Spec.SecretRef.Name)
+		} else if err != nil {
+			wrappedErr := errors.Wrapf(err, "Failed to delete secret \"%s/%s\" for unjoin cluster %q",
+				kubefedNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
+			if !forceDeletion {
+				return wrappedErr
+			}
+			klog.V(2).Infof("%v", wrappedErr)
+		} else {
+			klog.V(2).Infof("Deleted secret \"%s/%s\" for unjoin cluster %q", kubefedNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
 		}
-		klog.V(2).Infof("%v", wrappedErr)
-	} else {
-		klog.V(2).Infof("Deleted secret \"%s/%s\" for unjoin cluster %q", kubefedNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
 	}
 
 	err = client.Delete(context.TODO(), fedCluster, fedCluster.Namespace, fedCluster.Name)
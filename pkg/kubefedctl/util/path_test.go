@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/util/homedir"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := homedir.HomeDir()
+	testCases := map[string]string{
+		"":               "",
+		"-":              "-",
+		"~":              home,
+		"~/.kube/config": filepath.Join(home, ".kube", "config"),
+		"config/kubefed": filepath.Join("config", "kubefed"),
+		"/tmp/~/config":  filepath.FromSlash("/tmp/~/config"),
+		"~other/.kube":   filepath.FromSlash("~other/.kube"),
+	}
+	for path, expected := range testCases {
+		if actual := ExpandPath(path); actual != expected {
+			t.Errorf("Expected %q to expand to %q, got %q", path, expected, actual)
+		}
+	}
+}
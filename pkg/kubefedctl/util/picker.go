@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Pick lists the given choices on out and prompts for one of them to
+// be entered on in, either by its number or by its value. The prompt
+// is repeated until a valid choice is entered.
+func Pick(in io.Reader, out io.Writer, prompt string, choices []string) (string, error) {
+	if len(choices) == 0 {
+		return "", errors.Errorf("Nothing to choose from for %q", prompt)
+	}
+	for i, choice := range choices {
+		fmt.Fprintf(out, "%3d) %s\n", i+1, choice)
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprintf(out, "%s: ", prompt)
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if len(answer) > 0 {
+			if choice, ok := matchChoice(answer, choices); ok {
+				return choice, nil
+			}
+			fmt.Fprintf(out, "Invalid choice %q\n", answer)
+		}
+		if err == io.EOF {
+			return "", errors.Errorf("No choice was entered for %q", prompt)
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to read choice")
+		}
+	}
+}
+
+func matchChoice(answer string, choices []string) (string, bool) {
+	if i, err := strconv.Atoi(answer); err == nil {
+		if i < 1 || i > len(choices) {
+			return "", false
+		}
+		return choices[i-1], true
+	}
+	for _, choice := range choices {
+		if choice == answer {
+			return choice, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestPick(t *testing.T) {
+	choices := []string{"cluster1", "cluster2", "cluster3"}
+
+	testCases := map[string]struct {
+		input          string
+		expectedChoice string
+		expectedErr    bool
+	}{
+		"Choice by number": {
+			input:          "2\n",
+			expectedChoice: "cluster2",
+		},
+		"Choice by value": {
+			input:          "cluster3\n",
+			expectedChoice: "cluster3",
+		},
+		"Choice without trailing newline": {
+			input:          "1",
+			expectedChoice: "cluster1",
+		},
+		"Invalid choice followed by a valid choice": {
+			input:          "4\n\ncluster4\n1\n",
+			expectedChoice: "cluster1",
+		},
+		"No choice": {
+			input:       "",
+			expectedErr: true,
+		},
+		"Only invalid choices": {
+			input:       "0\n",
+			expectedErr: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			choice, err := Pick(strings.NewReader(tc.input), ioutil.Discard, "Cluster", choices)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got choice %q", choice)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if choice != tc.expectedChoice {
+				t.Errorf("Expected choice %q, got %q", tc.expectedChoice, choice)
+			}
+		})
+	}
+}
+
+func TestPickListsChoices(t *testing.T) {
+	out := &bytes.Buffer{}
+	_, err := Pick(strings.NewReader("1\n"), out, "Cluster", []string{"cluster1", "cluster2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "  1) cluster1\n  2) cluster2\nCluster: "
+	if out.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, out.String())
+	}
+}
@@ -18,6 +18,7 @@ package util
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -81,7 +82,7 @@ func (a *fedConfig) ClusterConfig(context, kubeconfigPath string) (*rest.Config,
 func (a *fedConfig) GetClientConfig(context, kubeconfigPath string) clientcmd.ClientConfig {
 	loadingRules := *a.pathOptions.LoadingRules
 	loadingRules.Precedence = a.pathOptions.GetLoadingPrecedence()
-	loadingRules.ExplicitPath = kubeconfigPath
+	loadingRules.ExplicitPath = ExpandPath(kubeconfigPath)
 	overrides := &clientcmd.ConfigOverrides{
 		CurrentContext: context,
 	}
@@ -89,6 +90,21 @@ func (a *fedConfig) GetClientConfig(context, kubeconfigPath string) clientcmd.Cl
 	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&loadingRules, overrides)
 }
 
+// ContextNames returns the sorted names of the contexts of the given
+// client config.
+func ContextNames(config clientcmd.ClientConfig) ([]string, error) {
+	rawConfig, err := config.RawConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to load kubeconfig")
+	}
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 // HostClientset provides a kubernetes API compliant clientset to
 // communicate with the host cluster's kubernetes API server.
 func HostClientset(config *rest.Config) (*kubeclient.Clientset, error) {
@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	validate_long = `
+		Validate renders the resources that federated resources read
+		from a file would propagate to member clusters and submits
+		them to the member clusters as server-side dry-run creates or
+		updates. Errors reported by admission in a member cluster,
+		such as rejections by policy controllers, quotas or pod
+		security, are surfaced without committing the federated
+		resources to the host cluster.
+
+		The federated types of the resources must be enabled. The
+		overrides for each cluster are applied, and values of
+		overrides referenced with valueFrom are retrieved from the
+		member cluster.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the
+		--host-cluster-context flag otherwise.`
+
+	validate_example = `
+		# Validate the federated resources of a file against the cluster prod-eu
+		kubefedctl validate -f federated.yaml --against-cluster prod-eu
+
+		# Validate federated resources read from stdin against two clusters
+		cat federated.yaml | kubefedctl validate -f - --against-cluster prod-eu --against-cluster prod-us`
+)
+
+type validateResources struct {
+	options.GlobalSubcommandOptions
+
+	filename          string
+	clusterNames      []string
+	resourceNamespace string
+}
+
+// Bind adds the validate specific arguments to the flagset passed in
+// as an argument.
+func (o *validateResources) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.filename, "filename", "f", "", "The file of federated resources to validate. Use - to read from stdin.")
+	flags.StringSliceVar(&o.clusterNames, "against-cluster", nil, "The name of a member cluster to validate the resources against. May be repeated.")
+	flags.StringVarP(&o.resourceNamespace, "namespace", "n", "", "Namespace of federated resources that do not specify one. Defaults to the namespace of the current context.")
+}
+
+// Complete ensures that options are valid.
+func (o *validateResources) Complete() error {
+	if len(o.filename) == 0 {
+		return errors.New("a filename is required")
+	}
+	if len(o.clusterNames) == 0 {
+		return errors.New("at least one cluster is required")
+	}
+	return nil
+}
+
+// NewCmdValidate defines the `validate` command that validates
+// federated resources against member clusters.
+func NewCmdValidate(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &validateResources{}
+
+	cmd := &cobra.Command{
+		Use:     "validate -f FILENAME --against-cluster CLUSTER_NAME",
+		Short:   "Validate federated resources against member clusters with a server-side dry-run",
+		Long:    validate_long,
+		Example: validate_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete()
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// Run validates the federated resources of the file against each of
+// the member clusters.
+func (o *validateResources) Run(cmdOut io.Writer, config util.FedConfig) error {
+	fedObjects, err := federate.DecodeUnstructuredFromFile(o.filename)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to load federated resources from %q", o.filename)
+	}
+
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+	fedConfig, err := options.GetKubeFedConfig(hostConfig, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	typeConfigs := &fedv1b1.FederatedTypeConfigList{}
+	err = client.List(context.TODO(), typeConfigs, o.KubeFedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+
+	namespace := o.resourceNamespace
+	if len(namespace) == 0 {
+		namespace, err = util.GetNamespace(o.HostClusterContext, o.Kubeconfig, config)
+		if err != nil {
+			return err
+		}
+	}
+	var propagatedMetadata *fedv1b1.PropagatedMetadataConfig
+	if fedConfig.Spec.SyncController != nil {
+		propagatedMetadata = fedConfig.Spec.SyncController.PropagatedMetadata
+	}
+
+	getTemplate := func(name string) (*fedv1b1.FederatedTemplate, error) {
+		template := &fedv1b1.FederatedTemplate{}
+		err := client.Get(context.TODO(), template, o.KubeFedNamespace, name)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return template, err
+	}
+
+	failures := 0
+	for _, clusterName := range o.clusterNames {
+		cluster := &fedv1b1.KubeFedCluster{}
+		err := client.Get(context.TODO(), cluster, o.KubeFedNamespace, clusterName)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get KubeFedCluster %q", clusterName)
+		}
+		clusterConfig, err := ctlutil.BuildClusterConfig(cluster, client, o.KubeFedNamespace, hostConfig)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to build the config of cluster %q", clusterName)
+		}
+		clusterClient, err := genericclient.New(clusterConfig)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get the client of cluster %q", clusterName)
+		}
+
+		for _, fedObject := range fedObjects {
+			typeConfig := lookupFederatedTypeConfigForObject(typeConfigs.Items, fedObject)
+			if typeConfig == nil {
+				return errors.Errorf("%s %q is not of an enabled federated type", fedObject.GetKind(), fedObject.GetName())
+			}
+			if typeConfig.GetFederatedNamespaced() && len(fedObject.GetNamespace()) == 0 {
+				fedObject.SetNamespace(namespace)
+			}
+
+			description := fmt.Sprintf("%s %q", fedObject.GetKind(), ctlutil.NewQualifiedName(fedObject))
+			operation, err := validateForCluster(clusterConfig, clusterClient, typeConfig, fedObject, cluster, getTemplate, propagatedMetadata)
+			if err != nil {
+				failures++
+				fmt.Fprintf(cmdOut, "%s failed validation against cluster %q: %v\n", description, clusterName, err)
+				continue
+			}
+			fmt.Fprintf(cmdOut, "%s validated against cluster %q (dry-run %s)\n", description, clusterName, operation)
+		}
+	}
+
+	if failures > 0 {
+		return errors.Errorf("%d of %d validations failed", failures, len(fedObjects)*len(o.clusterNames))
+	}
+	return nil
+}
+
+// validateForCluster renders the resource the given federated resource
+// would propagate to the given cluster and submits it to the cluster
+// as a dry-run create or, if the resource already exists, a dry-run
+// update. The operation that was submitted is returned.
+func validateForCluster(clusterConfig *rest.Config, clusterClient genericclient.Client, typeConfig *fedv1b1.FederatedTypeConfig,
+	fedObject *unstructured.Unstructured, cluster *fedv1b1.KubeFedCluster, getTemplate ctlutil.FederatedTemplateFunc,
+	propagatedMetadata *fedv1b1.PropagatedMetadataConfig) (string, error) {
+
+	targetAPIResource := typeConfig.GetTargetType()
+	resourceClient, err := ctlutil.NewResourceClient(clusterConfig, &targetAPIResource)
+	if err != nil {
+		return "", err
+	}
+
+	// The name of the resource in the cluster is only known once it
+	// is rendered, so the resource is rendered once to retrieve the
+	// existing resource and again to retain its fields.
+	resolveValue := dispatch.OverrideValueResolver(clusterClient, fedObject.GetNamespace())
+	obj, err := sync.RenderForCluster(typeConfig, fedObject, cluster, nil, resolveValue, getTemplate, propagatedMetadata)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render the resource")
+	}
+	resources := resourceClient.Resources(obj.GetNamespace())
+	clusterObj, err := resources.Get(obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = resources.Create(obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		return "create", err
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve the resource")
+	}
+
+	if ctlutil.IsExplicitlyUnmanaged(clusterObj) {
+		return "", errors.Errorf("the resource has label %s: %s", ctlutil.ManagedByKubeFedLabelKey, ctlutil.UnmanagedByKubeFedLabelValue)
+	}
+	obj, err = sync.RenderForCluster(typeConfig, fedObject, cluster, clusterObj, resolveValue, getTemplate, propagatedMetadata)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render the resource")
+	}
+	_, err = resources.Update(obj, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+	return "update", err
+}
+
+// lookupFederatedTypeConfigForObject returns the type config whose
+// federated type is the type of the given object.
+func lookupFederatedTypeConfigForObject(typeConfigs []fedv1b1.FederatedTypeConfig, obj *unstructured.Unstructured) *fedv1b1.FederatedTypeConfig {
+	gvk := obj.GroupVersionKind()
+	for i := range typeConfigs {
+		federatedType := typeConfigs[i].GetFederatedType()
+		if federatedType.Kind == gvk.Kind && federatedType.Group == gvk.Group {
+			return &typeConfigs[i]
+		}
+	}
+	return nil
+}
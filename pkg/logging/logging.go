@@ -0,0 +1,259 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pborman/uuid"
+
+	"k8s.io/klog"
+)
+
+// Keys used to correlate log entries across controllers.
+const (
+	// ClusterKey identifies the member cluster an entry pertains to.
+	ClusterKey = "cluster"
+	// QualifiedNameKey identifies the namespace/name of the federated
+	// resource an entry pertains to.
+	QualifiedNameKey = "qualifiedName"
+	// FTCKey identifies the FederatedTypeConfig of the controller
+	// emitting an entry.
+	FTCKey = "ftc"
+	// ReconcileIDKey identifies a single reconciliation of a resource.
+	ReconcileIDKey = "reconcileID"
+)
+
+// Format is the format log entries are written in.
+type Format string
+
+const (
+	// TextFormat writes entries via klog as a message followed by
+	// key=value pairs.
+	TextFormat Format = "text"
+	// JSONFormat writes entries to stderr as one JSON object per line.
+	JSONFormat Format = "json"
+)
+
+var (
+	formatLock sync.RWMutex
+	format     = TextFormat
+
+	// output is where entries are written in JSON format.
+	output io.Writer = os.Stderr
+	// outputLock serializes writes to output.
+	outputLock sync.Mutex
+)
+
+// SetFormat configures the format of subsequently written entries.
+func SetFormat(f Format) error {
+	if f != TextFormat && f != JSONFormat {
+		return fmt.Errorf("unsupported log format %q", f)
+	}
+	formatLock.Lock()
+	defer formatLock.Unlock()
+	format = f
+	return nil
+}
+
+func currentFormat() Format {
+	formatLock.RLock()
+	defer formatLock.RUnlock()
+	return format
+}
+
+// NewLogger returns a logger with the given name. Verbosity is
+// controlled by the klog -v flag.
+func NewLogger(name string) logr.Logger {
+	return &logger{name: name}
+}
+
+// NewReconcileID returns an identifier that is unique to a single
+// reconciliation.
+func NewReconcileID() string {
+	return uuid.New()
+}
+
+// logger implements logr.Logger on top of klog.
+type logger struct {
+	name   string
+	level  int
+	values []interface{}
+}
+
+var _ logr.Logger = &logger{}
+
+func (l *logger) Enabled() bool {
+	return bool(klog.V(klog.Level(l.level)))
+}
+
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	if !l.Enabled() {
+		return
+	}
+	l.write(false, nil, msg, keysAndValues)
+}
+
+func (l *logger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.write(true, err, msg, keysAndValues)
+}
+
+func (l *logger) V(level int) logr.InfoLogger {
+	return &logger{
+		name:   l.name,
+		level:  l.level + level,
+		values: l.values,
+	}
+}
+
+func (l *logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	values := make([]interface{}, 0, len(l.values)+len(keysAndValues))
+	values = append(values, l.values...)
+	values = append(values, keysAndValues...)
+	return &logger{
+		name:   l.name,
+		level:  l.level,
+		values: values,
+	}
+}
+
+func (l *logger) WithName(name string) logr.Logger {
+	if len(l.name) > 0 {
+		name = fmt.Sprintf("%s.%s", l.name, name)
+	}
+	return &logger{
+		name:   name,
+		level:  l.level,
+		values: l.values,
+	}
+}
+
+func (l *logger) write(isError bool, err error, msg string, keysAndValues []interface{}) {
+	values := make([]interface{}, 0, len(l.values)+len(keysAndValues))
+	values = append(values, l.values...)
+	values = append(values, keysAndValues...)
+
+	if currentFormat() == JSONFormat {
+		l.writeJSON(isError, err, msg, values)
+		return
+	}
+
+	var buf bytes.Buffer
+	if len(l.name) > 0 {
+		buf.WriteString(l.name)
+		buf.WriteString(": ")
+	}
+	buf.WriteString(msg)
+	if err != nil {
+		values = append(values, "error", err)
+	}
+	for i := 0; i < len(values); i += 2 {
+		fmt.Fprintf(&buf, " %s=%s", keyString(values[i]), textValue(valueAt(values, i+1)))
+	}
+	// Depth accounts for write and Info/Error.
+	if isError {
+		klog.ErrorDepth(2, buf.String())
+	} else {
+		klog.InfoDepth(2, buf.String())
+	}
+}
+
+func (l *logger) writeJSON(isError bool, err error, msg string, values []interface{}) {
+	level := "info"
+	if isError {
+		level = "error"
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeJSONField(&buf, "ts", time.Now().UTC().Format(time.RFC3339Nano), true)
+	writeJSONField(&buf, "level", level, false)
+	if !isError {
+		writeJSONField(&buf, "v", l.level, false)
+	}
+	if len(l.name) > 0 {
+		writeJSONField(&buf, "logger", l.name, false)
+	}
+	writeJSONField(&buf, "msg", msg, false)
+	if err != nil {
+		writeJSONField(&buf, "error", err.Error(), false)
+	}
+	for i := 0; i < len(values); i += 2 {
+		writeJSONField(&buf, keyString(values[i]), jsonValue(valueAt(values, i+1)), false)
+	}
+	buf.WriteString("}\n")
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	_, _ = output.Write(buf.Bytes())
+}
+
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	encodedKey, _ := json.Marshal(key)
+	buf.Write(encodedKey)
+	buf.WriteByte(':')
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		encodedValue, _ = json.Marshal(fmt.Sprintf("%+v", value))
+	}
+	buf.Write(encodedValue)
+}
+
+func valueAt(values []interface{}, i int) interface{} {
+	if i < len(values) {
+		return values[i]
+	}
+	return "(MISSING)"
+}
+
+func keyString(key interface{}) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+// jsonValue returns a representation of the value that encodes
+// consistently with the text format for errors and stringers.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return value
+}
+
+func textValue(value interface{}) string {
+	switch v := jsonValue(value).(type) {
+	case string:
+		return strconv.Quote(v)
+	default:
+		return fmt.Sprintf("%+v", v)
+	}
+}
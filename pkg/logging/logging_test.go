@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStringer struct{}
+
+func (testStringer) String() string {
+	return "ns/name"
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	originalOutput := output
+	output = &buf
+	assert.NoError(t, SetFormat(JSONFormat))
+	defer func() {
+		output = originalOutput
+		_ = SetFormat(TextFormat)
+	}()
+
+	logger := NewLogger("sync").WithValues(FTCKey, "deployments.apps")
+	logger.Error(errors.New("boom"), "Failed to update", QualifiedNameKey, testStringer{}, ClusterKey, "cluster1")
+
+	entry := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "sync", entry["logger"])
+	assert.Equal(t, "Failed to update", entry["msg"])
+	assert.Equal(t, "boom", entry["error"])
+	assert.Equal(t, "deployments.apps", entry[FTCKey])
+	assert.Equal(t, "ns/name", entry[QualifiedNameKey])
+	assert.Equal(t, "cluster1", entry[ClusterKey])
+}
+
+func TestSetFormat(t *testing.T) {
+	assert.Error(t, SetFormat(Format("xml")))
+	assert.Equal(t, TextFormat, currentFormat())
+}
@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -32,6 +34,13 @@ var (
 		}, []string{"state", "cluster"},
 	)
 
+	kubefedClusterQuarantined = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubefedcluster_quarantined",
+			Help: "Whether propagation to a kubefed cluster is suspended by a quarantine.",
+		}, []string{"cluster"},
+	)
+
 	joinedClusterTotal = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "joined_cluster_total",
@@ -87,6 +96,28 @@ var (
 		}, []string{"action"},
 	)
 
+	dispatchOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubefed_dispatch_operations_total",
+			Help: "Number of requests to member clusters dispatched for resources of a kind, by operation and result.",
+		}, []string{"kind", "cluster", "op", "result"},
+	)
+
+	dispatchOperationKindDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubefed_dispatch_operation_duration_seconds",
+			Help:    "Time taken by requests to member clusters dispatched for resources of a kind, by operation.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1.0, 2.5, 5.0, 7.5, 10.0, 12.5, 15.0, 17.5, 20.0, 22.5, 25.0, 27.5, 30.0, 50.0, 75.0, 100.0, 1000.0},
+		}, []string{"kind", "op"},
+	)
+
+	dispatchConflicts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dispatch_conflicts_total",
+			Help: "Number of updates of resources in a cluster that conflicted with a concurrent change of the resource.",
+		}, []string{"cluster", "strategy"},
+	)
+
 	controllerRuntimeReconcileDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "controller_runtime_reconcile_duration_seconds",
@@ -95,6 +126,86 @@ var (
 		}, []string{"controller"},
 	)
 
+	probeApplyDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "propagation_probe_apply_duration_seconds",
+			Help:    "Time taken for an update of the propagation probe to be applied to a cluster.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1.0, 2.5, 5.0, 7.5, 10.0, 12.5, 15.0, 17.5, 20.0, 22.5, 25.0, 27.5, 30.0, 50.0, 75.0, 100.0, 1000.0},
+		}, []string{"cluster"},
+	)
+
+	probeStatusDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "propagation_probe_status_duration_seconds",
+			Help:    "Time taken for an update of the propagation probe to be reported as propagated to a cluster.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1.0, 2.5, 5.0, 7.5, 10.0, 12.5, 15.0, 17.5, 20.0, 22.5, 25.0, 27.5, 30.0, 50.0, 75.0, 100.0, 1000.0},
+		}, []string{"cluster"},
+	)
+
+	probeFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "propagation_probe_failures_total",
+			Help: "Number of updates of the propagation probe that were not applied to or reported for a cluster in time.",
+		}, []string{"cluster", "stage"},
+	)
+
+	clusterApplyQueueDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cluster_apply_queue_duration_seconds",
+			Help:    "Time creates and updates of resources waited for the apply rate limit of a cluster.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1.0, 2.5, 5.0, 7.5, 10.0, 12.5, 15.0, 17.5, 20.0, 22.5, 25.0, 27.5, 30.0, 50.0, 75.0, 100.0, 1000.0},
+		}, []string{"cluster"},
+	)
+
+	clusterClientOpenConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cluster_client_open_connections",
+			Help: "Number of open connections of the shared transport of a cluster.",
+		}, []string{"cluster"},
+	)
+
+	clusterHealthCheckDeadlineExceeded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cluster_health_check_deadline_exceeded_total",
+			Help: "Number of cluster health checks that did not complete before the deadline.",
+		}, []string{"cluster"},
+	)
+
+	namespaceSamenessDeviations = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "namespace_sameness_deviations",
+			Help: "Number of federated namespaces that differ in a member cluster, by reason.",
+		}, []string{"cluster", "reason"},
+	)
+
+	staleClusterRecordsScanned = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stale_cluster_records_scanned_total",
+			Help: "Number of objects scanned for records of clusters that are no longer joined, by kind.",
+		}, []string{"kind"},
+	)
+
+	staleClusterRecordsPruned = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stale_cluster_records_pruned_total",
+			Help: "Number of records of clusters that are no longer joined pruned from objects, by kind.",
+		}, []string{"kind"},
+	)
+
+	staleClusterRecordsLastCleanup = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "stale_cluster_records_last_cleanup_timestamp_seconds",
+			Help: "Time at which the last cleanup of records of clusters that are no longer joined completed.",
+		},
+	)
+
+	propagationNotifications = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubefed_propagation_notifications_total",
+			Help: "Number of notifications of propagation state transitions, by sink, event and result.",
+		}, []string{"sink", "event", "result"},
+	)
+
 	controllerRuntimeReconcileDurationSummary = prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Name:   "controller_runtime_reconcile_quantile_seconds",
@@ -112,21 +223,56 @@ const (
 	ClusterNotReady = "notready"
 	ClusterReady    = "ready"
 	ClusterOffline  = "offline"
+
+	// Stages of the propagation probe
+	ProbeStageApply  = "apply"
+	ProbeStageStatus = "status"
+
+	// Results of dispatched operations
+	DispatchResultSuccess       = "success"
+	DispatchResultConflict      = "conflict"
+	DispatchResultNotFound      = "notfound"
+	DispatchResultAlreadyExists = "alreadyexists"
+	DispatchResultForbidden     = "forbidden"
+	DispatchResultInvalid       = "invalid"
+	DispatchResultTimeout       = "timeout"
+	DispatchResultError         = "error"
+
+	// Results of notifications of propagation state transitions
+	NotificationResultSent    = "sent"
+	NotificationResultFailed  = "failed"
+	NotificationResultDropped = "dropped"
 )
 
 // RegisterAll registers all metrics.
 func RegisterAll() {
 	metrics.Registry.MustRegister(
 		kubefedClusterTotal,
+		kubefedClusterQuarantined,
 		joinedClusterTotal,
 		reconcileFederatedResourcesDuration,
 		clusterHealthStatusDuration,
+		clusterHealthCheckDeadlineExceeded,
 		clusterClientConnectionDuration,
+		clusterClientOpenConnections,
+		clusterApplyQueueDuration,
 		joinedClusterDuration,
 		unjoinedClusterDuration,
 		dispatchOperationDuration,
+		dispatchOperations,
+		dispatchOperationKindDuration,
+		dispatchConflicts,
 		controllerRuntimeReconcileDuration,
 		controllerRuntimeReconcileDurationSummary,
+		probeApplyDuration,
+		probeStatusDuration,
+		probeFailures,
+		namespaceSamenessDeviations,
+		staleClusterRecordsScanned,
+		staleClusterRecordsPruned,
+		staleClusterRecordsLastCleanup,
+		propagationNotifications,
+		unsynced,
 	)
 }
 
@@ -148,6 +294,15 @@ func RegisterKubefedClusterTotal(state, cluster string) {
 	}
 }
 
+// RegisterKubefedClusterQuarantined records whether a kubefed cluster is quarantined
+func RegisterKubefedClusterQuarantined(cluster string, quarantined bool) {
+	value := 0.0
+	if quarantined {
+		value = 1
+	}
+	kubefedClusterQuarantined.WithLabelValues(cluster).Set(value)
+}
+
 // JoinedClusterTotalInc increases by one the number of joined kubefed clusters
 func JoinedClusterTotalInc() {
 	joinedClusterTotal.Inc()
@@ -164,18 +319,76 @@ func DispatchOperationDurationFromStart(action string, start time.Time) {
 	dispatchOperationDuration.WithLabelValues(action).Observe(duration.Seconds())
 }
 
+// DispatchOperation records a request to the given cluster dispatched
+// for a resource of the given kind, which started at the given time
+// and returned the given error
+func DispatchOperation(kind, cluster, op string, err error, start time.Time) {
+	duration := time.Since(start)
+	dispatchOperations.WithLabelValues(kind, cluster, op, dispatchResult(err)).Inc()
+	dispatchOperationKindDuration.WithLabelValues(kind, op).Observe(duration.Seconds())
+}
+
+// dispatchResult returns the result label of a dispatched request that
+// returned the given error.
+func dispatchResult(err error) string {
+	switch {
+	case err == nil:
+		return DispatchResultSuccess
+	case apierrors.IsConflict(err):
+		return DispatchResultConflict
+	case apierrors.IsNotFound(err):
+		return DispatchResultNotFound
+	case apierrors.IsAlreadyExists(err):
+		return DispatchResultAlreadyExists
+	case apierrors.IsForbidden(err):
+		return DispatchResultForbidden
+	case apierrors.IsInvalid(err):
+		return DispatchResultInvalid
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return DispatchResultTimeout
+	default:
+		return DispatchResultError
+	}
+}
+
+// DispatchConflict increases by one the number of updates of resources
+// in the given cluster that conflicted with a concurrent change and
+// were resolved with the given strategy
+func DispatchConflict(cluster, strategy string) {
+	dispatchConflicts.WithLabelValues(cluster, strategy).Inc()
+}
+
 // ClusterHealthStatusDurationFromStart records the duration of the cluster health status operation
 func ClusterHealthStatusDurationFromStart(start time.Time) {
 	duration := time.Since(start)
 	clusterHealthStatusDuration.Observe(duration.Seconds())
 }
 
+// ClusterHealthCheckDeadlineExceeded increases by one the number of
+// health checks of the given cluster that did not complete before the
+// deadline
+func ClusterHealthCheckDeadlineExceeded(cluster string) {
+	clusterHealthCheckDeadlineExceeded.WithLabelValues(cluster).Inc()
+}
+
 // ClusterClientConnectionDurationFromStart records the duration of the cluster client connection operation
 func ClusterClientConnectionDurationFromStart(start time.Time) {
 	duration := time.Since(start)
 	clusterClientConnectionDuration.Observe(duration.Seconds())
 }
 
+// ClusterClientConnectionOpened increases by one the number of open
+// connections to the given cluster
+func ClusterClientConnectionOpened(cluster string) {
+	clusterClientOpenConnections.WithLabelValues(cluster).Inc()
+}
+
+// ClusterClientConnectionClosed decreases by one the number of open
+// connections to the given cluster
+func ClusterClientConnectionClosed(cluster string) {
+	clusterClientOpenConnections.WithLabelValues(cluster).Dec()
+}
+
 // JoinedClusterDurationFromStart records the duration of the cluster joined operation
 func JoinedClusterDurationFromStart(start time.Time) {
 	duration := time.Since(start)
@@ -194,6 +407,65 @@ func ReconcileFederatedResourcesDurationFromStart(start time.Time) {
 	reconcileFederatedResourcesDuration.Observe(duration.Seconds())
 }
 
+// ClusterApplyQueueDurationFromStart records the duration a create or
+// update of a resource waited for the apply rate limit of a cluster
+func ClusterApplyQueueDurationFromStart(cluster string, start time.Time) {
+	duration := time.Since(start)
+	clusterApplyQueueDuration.WithLabelValues(cluster).Observe(duration.Seconds())
+}
+
+// ProbeApplyDurationFromStart records the duration until an update of
+// the propagation probe was applied to a cluster
+func ProbeApplyDurationFromStart(cluster string, start time.Time) {
+	duration := time.Since(start)
+	probeApplyDuration.WithLabelValues(cluster).Observe(duration.Seconds())
+}
+
+// ProbeStatusDurationFromStart records the duration until an update of
+// the propagation probe was reported as propagated to a cluster
+func ProbeStatusDurationFromStart(cluster string, start time.Time) {
+	duration := time.Since(start)
+	probeStatusDuration.WithLabelValues(cluster).Observe(duration.Seconds())
+}
+
+// ProbeFailed increases by one the number of updates of the propagation
+// probe that did not complete the given stage for a cluster in time
+func ProbeFailed(cluster, stage string) {
+	probeFailures.WithLabelValues(cluster, stage).Inc()
+}
+
+// RecordNamespaceSamenessDeviations records the number of federated
+// namespaces that differ in each member cluster, keyed by cluster and
+// reason. Series of a previous verification are replaced.
+func RecordNamespaceSamenessDeviations(counts map[string]map[string]int) {
+	namespaceSamenessDeviations.Reset()
+	for cluster, reasons := range counts {
+		for reason, count := range reasons {
+			namespaceSamenessDeviations.WithLabelValues(cluster, reason).Set(float64(count))
+		}
+	}
+}
+
+// RecordStaleClusterRecordsScanned records that the given number of
+// objects of the given kind were scanned for records of clusters that
+// are no longer joined, and the number of records pruned from them.
+func RecordStaleClusterRecordsScanned(kind string, scanned, pruned int) {
+	staleClusterRecordsScanned.WithLabelValues(kind).Add(float64(scanned))
+	staleClusterRecordsPruned.WithLabelValues(kind).Add(float64(pruned))
+}
+
+// RecordStaleClusterRecordsCleanupCompleted records the time at which
+// a cleanup of records of clusters that are no longer joined completed.
+func RecordStaleClusterRecordsCleanupCompleted(completed time.Time) {
+	staleClusterRecordsLastCleanup.Set(float64(completed.Unix()))
+}
+
+// RecordPropagationNotification records the result of notifying the
+// given sink of a propagation state transition.
+func RecordPropagationNotification(sink, event, result string) {
+	propagationNotifications.WithLabelValues(sink, event, result).Inc()
+}
+
 // UpdateControllerReconcileDurationFromStart records the duration of the reconcile loop
 // of a controller
 func UpdateControllerReconcileDurationFromStart(controller string, start time.Time) {
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDispatchResult(t *testing.T) {
+	resource := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	testCases := map[string]struct {
+		err    error
+		result string
+	}{
+		"no error": {
+			result: DispatchResultSuccess,
+		},
+		"conflict": {
+			err:    apierrors.NewConflict(resource, "web", errors.New("changed")),
+			result: DispatchResultConflict,
+		},
+		"not found": {
+			err:    apierrors.NewNotFound(resource, "web"),
+			result: DispatchResultNotFound,
+		},
+		"already exists": {
+			err:    apierrors.NewAlreadyExists(resource, "web"),
+			result: DispatchResultAlreadyExists,
+		},
+		"forbidden": {
+			err:    apierrors.NewForbidden(resource, "web", errors.New("denied")),
+			result: DispatchResultForbidden,
+		},
+		"timeout": {
+			err:    apierrors.NewServerTimeout(resource, "update", 1),
+			result: DispatchResultTimeout,
+		},
+		"other error": {
+			err:    errors.New("connection refused"),
+			result: DispatchResultError,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if result := dispatchResult(tc.err); result != tc.result {
+				t.Errorf("Expected result %q, got %q", tc.result, result)
+			}
+		})
+	}
+}
+
+func TestDispatchOperation(t *testing.T) {
+	resource := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	DispatchOperation("Deployment", "cluster1", "update", nil, time.Now())
+	DispatchOperation("Deployment", "cluster1", "update", apierrors.NewConflict(resource, "web", errors.New("changed")), time.Now())
+	DispatchOperation("Deployment", "cluster1", "update", apierrors.NewConflict(resource, "api", errors.New("changed")), time.Now())
+
+	if value := testutil.ToFloat64(dispatchOperations.WithLabelValues("Deployment", "cluster1", "update", DispatchResultSuccess)); value != 1 {
+		t.Errorf("Expected 1 successful update, got %v", value)
+	}
+	if value := testutil.ToFloat64(dispatchOperations.WithLabelValues("Deployment", "cluster1", "update", DispatchResultConflict)); value != 2 {
+		t.Errorf("Expected 2 conflicting updates, got %v", value)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	resourcesUnsyncedDesc = prometheus.NewDesc(
+		"kubefed_resources_unsynced",
+		"Number of federated resources not in sync with a member cluster.",
+		[]string{"namespace", "cluster"}, nil,
+	)
+
+	oldestUnsyncedAgeDesc = prometheus.NewDesc(
+		"kubefed_oldest_unsynced_age_seconds",
+		"Seconds the federated resource that has been out of sync with a member cluster the longest has been out of sync.",
+		[]string{"namespace", "cluster"}, nil,
+	)
+
+	unsynced = newUnsyncedCollector(time.Now)
+)
+
+// unsyncedCollector tracks since when federated resources have not been
+// in sync with member clusters, and reports the number of unsynced
+// resources and the age of the oldest of them for each namespace and
+// cluster. Series for which no resource is unsynced are not reported.
+type unsyncedCollector struct {
+	sync.Mutex
+	// Resources that are not in sync with one or more clusters,
+	// keyed by an identifier of the resource.
+	resources map[string]*unsyncedResource
+	now       func() time.Time
+}
+
+type unsyncedResource struct {
+	namespace string
+	// The time since when the resource has not been in sync, keyed by
+	// cluster name.
+	clusters map[string]time.Time
+}
+
+func newUnsyncedCollector(now func() time.Time) *unsyncedCollector {
+	return &unsyncedCollector{
+		resources: make(map[string]*unsyncedResource),
+		now:       now,
+	}
+}
+
+func (c *unsyncedCollector) record(key, namespace string, clusterNames []string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if len(clusterNames) == 0 {
+		delete(c.resources, key)
+		return
+	}
+	previous := c.resources[key]
+	resource := &unsyncedResource{
+		namespace: namespace,
+		clusters:  make(map[string]time.Time, len(clusterNames)),
+	}
+	now := c.now()
+	for _, clusterName := range clusterNames {
+		since := now
+		if previous != nil {
+			if previousSince, ok := previous.clusters[clusterName]; ok {
+				since = previousSince
+			}
+		}
+		resource.clusters[clusterName] = since
+	}
+	c.resources[key] = resource
+}
+
+func (c *unsyncedCollector) forget(key string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.resources, key)
+}
+
+func (c *unsyncedCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourcesUnsyncedDesc
+	ch <- oldestUnsyncedAgeDesc
+}
+
+func (c *unsyncedCollector) Collect(ch chan<- prometheus.Metric) {
+	type series struct {
+		namespace, cluster string
+	}
+	counts := make(map[series]int)
+	oldest := make(map[series]time.Time)
+
+	c.Lock()
+	for _, resource := range c.resources {
+		for clusterName, since := range resource.clusters {
+			s := series{namespace: resource.namespace, cluster: clusterName}
+			counts[s]++
+			if previous, ok := oldest[s]; !ok || since.Before(previous) {
+				oldest[s] = since
+			}
+		}
+	}
+	now := c.now()
+	c.Unlock()
+
+	for s, count := range counts {
+		ch <- prometheus.MustNewConstMetric(resourcesUnsyncedDesc, prometheus.GaugeValue, float64(count), s.namespace, s.cluster)
+		ch <- prometheus.MustNewConstMetric(oldestUnsyncedAgeDesc, prometheus.GaugeValue, now.Sub(oldest[s]).Seconds(), s.namespace, s.cluster)
+	}
+}
+
+// RecordUnsyncedClusters records the names of the clusters the
+// federated resource identified by the given key in the given namespace
+// is not in sync with. A cluster the resource was already not in sync
+// with keeps the time since when it has not been in sync.
+func RecordUnsyncedClusters(key, namespace string, clusterNames []string) {
+	unsynced.record(key, namespace, clusterNames)
+}
+
+// ForgetUnsyncedClusters stops tracking the federated resource
+// identified by the given key, e.g. once it has been deleted.
+func ForgetUnsyncedClusters(key string) {
+	unsynced.forget(key)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestUnsyncedCollector(t *testing.T) {
+	now := time.Date(2020, time.March, 2, 12, 0, 0, 0, time.UTC)
+	collector := newUnsyncedCollector(func() time.Time { return now })
+
+	collector.record("deployments.apps/prod/web", "prod", []string{"cluster1", "cluster2"})
+	now = now.Add(time.Minute)
+	collector.record("deployments.apps/prod/api", "prod", []string{"cluster1"})
+	// Recording a resource again preserves the time since when it has
+	// not been in sync with a cluster.
+	collector.record("deployments.apps/prod/web", "prod", []string{"cluster1"})
+	collector.record("configmaps/dev/config", "dev", []string{"cluster1"})
+	collector.record("configmaps/dev/config", "dev", nil)
+	now = now.Add(time.Minute)
+
+	expected := `
+# HELP kubefed_oldest_unsynced_age_seconds Seconds the federated resource that has been out of sync with a member cluster the longest has been out of sync.
+# TYPE kubefed_oldest_unsynced_age_seconds gauge
+kubefed_oldest_unsynced_age_seconds{cluster="cluster1",namespace="prod"} 120
+# HELP kubefed_resources_unsynced Number of federated resources not in sync with a member cluster.
+# TYPE kubefed_resources_unsynced gauge
+kubefed_resources_unsynced{cluster="cluster1",namespace="prod"} 2
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+
+	collector.forget("deployments.apps/prod/web")
+	collector.forget("deployments.apps/prod/api")
+	if err := testutil.CollectAndCompare(collector, strings.NewReader("")); err != nil {
+		t.Error(err)
+	}
+}
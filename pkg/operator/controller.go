@@ -0,0 +1,408 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	kubeclient "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	// How often installations are converged in the absence of
+	// changes, which also retries installations whose manifests were
+	// not available.
+	resyncPeriod = 5 * time.Minute
+
+	// How long to wait before checking again whether the control
+	// plane of an installation is ready.
+	readinessCheckDelay = 10 * time.Second
+
+	// Reasons recorded on the conditions of an installation.
+	reasonInvalid              = "Invalid"
+	reasonManifestsUnavailable = "ManifestsUnavailable"
+	reasonApplyFailed          = "ApplyFailed"
+	reasonApplied              = "Applied"
+	reasonProgressing          = "Progressing"
+	reasonAvailable            = "Available"
+)
+
+// Controller converges the KubeFed control planes described by
+// KubeFedInstallations by applying the manifests of the desired
+// release, customized for each installation.
+type Controller struct {
+	client     genericclient.Client
+	kubeClient kubeclient.Interface
+	kubeConfig *restclient.Config
+
+	// manifestsDir holds the manifests of each release and scope.
+	manifestsDir string
+	// manifestsNamespace is the namespace the manifests are rendered
+	// for.
+	manifestsNamespace string
+
+	// Store for the KubeFedInstallation objects
+	store cache.Store
+	// Informer for the KubeFedInstallation objects
+	controller cache.Controller
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the Controller for managing
+// KubeFedInstallation objects.
+func StartController(kubeConfig *restclient.Config, manifestsDir, manifestsNamespace string, stopChan <-chan struct{}) error {
+	controller, err := newController(kubeConfig, manifestsDir, manifestsNamespace)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting KubeFedInstallation controller")
+	go controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new controller to manage
+// KubeFedInstallation objects.
+func newController(config *restclient.Config, manifestsDir, manifestsNamespace string) (*Controller, error) {
+	kubeConfig := restclient.CopyConfig(config)
+	restclient.AddUserAgent(kubeConfig, "kubefed-operator")
+	client, err := genericclient.New(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubeclient.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:             client,
+		kubeClient:         kubeClient,
+		kubeConfig:         kubeConfig,
+		manifestsDir:       manifestsDir,
+		manifestsNamespace: manifestsNamespace,
+	}
+
+	c.worker = util.NewReconcileWorker("kubefedinstallationcontroller", c.reconcile, util.WorkerTiming{})
+
+	c.store, c.controller, err = util.NewGenericInformer(
+		kubeConfig,
+		metav1.NamespaceAll,
+		&fedv1b1.KubeFedInstallation{},
+		resyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+
+	// wait for the caches to synchronize before starting the worker
+	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
+		runtime.HandleError(errors.New("Timed out waiting for cache to sync"))
+		return
+	}
+
+	c.worker.Run(stopChan)
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	key := qualifiedName.String()
+	defer metrics.UpdateControllerReconcileDurationFromStart("kubefedinstallationcontroller", time.Now())
+
+	klog.V(3).Infof("Running reconcile KubeFedInstallation for %q", key)
+
+	cachedObj, err := c.objCopyFromCache(key)
+	if err != nil {
+		return util.StatusError
+	}
+	if cachedObj == nil {
+		// The control plane of a deleted installation is left in
+		// place to avoid removing the CRDs, and thus the federated
+		// resources, by accident.
+		return util.StatusAllOK
+	}
+	installation := cachedObj.(*fedv1b1.KubeFedInstallation)
+	installation.Status.ObservedGeneration = installation.Generation
+
+	installations := []fedv1b1.KubeFedInstallation{}
+	for _, obj := range c.store.List() {
+		installations = append(installations, *obj.(*fedv1b1.KubeFedInstallation))
+	}
+	if errs := validation.ValidateKubeFedInstallation(installation, installations); len(errs) > 0 {
+		installation.SetCondition(fedv1b1.KubeFedInstallationApplied, corev1.ConditionFalse, reasonInvalid, errs.ToAggregate().Error())
+		return c.updateStatus(installation)
+	}
+
+	objs, err := loadManifests(c.manifestsDir, c.manifestsNamespace, installation)
+	if err != nil {
+		message := fmt.Sprintf("Failed to load the manifests of version %q: %v", installation.Spec.Version, err)
+		installation.SetCondition(fedv1b1.KubeFedInstallationApplied, corev1.ConditionFalse, reasonManifestsUnavailable, message)
+		return c.updateStatus(installation)
+	}
+
+	if err := c.apply(installation, objs); err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to apply KubeFedInstallation %q", key))
+		installation.SetCondition(fedv1b1.KubeFedInstallationApplied, corev1.ConditionFalse, reasonApplyFailed, err.Error())
+		c.updateStatus(installation)
+		return util.StatusError
+	}
+	message := fmt.Sprintf("Applied the manifests of version %q", installation.Spec.Version)
+	installation.SetCondition(fedv1b1.KubeFedInstallationApplied, corev1.ConditionTrue, reasonApplied, message)
+
+	ready, message, err := c.deploymentsReady(objs)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to determine whether KubeFedInstallation %q is ready", key))
+		c.updateStatus(installation)
+		return util.StatusError
+	}
+	if !ready {
+		installation.SetCondition(fedv1b1.KubeFedInstallationReady, corev1.ConditionFalse, reasonProgressing, message)
+		status := c.updateStatus(installation)
+		c.worker.EnqueueWithDelay(qualifiedName, readinessCheckDelay)
+		return status
+	}
+	if installation.Status.Version != installation.Spec.Version {
+		klog.Infof("KubeFedInstallation %q is ready with version %q", key, installation.Spec.Version)
+	}
+	installation.Status.Version = installation.Spec.Version
+	message = fmt.Sprintf("The control plane of version %q is available", installation.Spec.Version)
+	installation.SetCondition(fedv1b1.KubeFedInstallationReady, corev1.ConditionTrue, reasonAvailable, message)
+	return c.updateStatus(installation)
+}
+
+// apply creates or updates the given objects of an installation in
+// its namespace, after generating the serving certificate of its
+// admission webhook.
+func (c *Controller) apply(installation *fedv1b1.KubeFedInstallation, objs []*unstructured.Unstructured) error {
+	namespace := installation.Spec.KubeFedNamespace
+	if err := c.ensureNamespace(installation, namespace); err != nil {
+		return err
+	}
+	caBundle, err := c.ensureServingCert(installation, namespace)
+	if err != nil {
+		return err
+	}
+	if err := setCABundle(objs, caBundle); err != nil {
+		return err
+	}
+
+	var mapper meta.RESTMapper
+	crdsApplied := false
+	for _, obj := range objs {
+		isCRD := obj.GetKind() == "CustomResourceDefinition"
+		// The mapper is refreshed once the CRDs are applied so that
+		// the kinds they define can be mapped.
+		if mapper == nil || (crdsApplied && !isCRD) {
+			groupResources, err := restmapper.GetAPIGroupResources(c.kubeClient.Discovery())
+			if err != nil {
+				return errors.Wrap(err, "Failed to discover the API resources of the host cluster")
+			}
+			mapper = restmapper.NewDiscoveryRESTMapper(groupResources)
+			crdsApplied = false
+		}
+		if err := c.applyObject(mapper, obj); err != nil {
+			return errors.Wrapf(err, "Failed to apply %s %q", obj.GetKind(), util.NewQualifiedName(obj))
+		}
+		crdsApplied = crdsApplied || isCRD
+	}
+	return nil
+}
+
+// applyObject creates the given object, or replaces the object if it
+// already exists.
+func (c *Controller) applyObject(mapper meta.RESTMapper, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+	apiResource := metav1.APIResource{
+		Group:      mapping.Resource.Group,
+		Version:    mapping.Resource.Version,
+		Name:       mapping.Resource.Resource,
+		Kind:       gvk.Kind,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}
+	client, err := util.NewResourceClient(c.kubeConfig, &apiResource)
+	if err != nil {
+		return err
+	}
+	resources := client.Resources(obj.GetNamespace())
+
+	existing, err := resources.Get(obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = resources.Create(obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	// The cluster IP of a service is allocated on creation and may
+	// not be changed.
+	if clusterIP, ok, _ := unstructured.NestedString(existing.Object, "spec", "clusterIP"); ok && gvk.Kind == "Service" {
+		if err := unstructured.SetNestedField(obj.Object, clusterIP, "spec", "clusterIP"); err != nil {
+			return err
+		}
+	}
+	_, err = resources.Update(obj, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Controller) ensureNamespace(installation *fedv1b1.KubeFedInstallation, name string) error {
+	_, err := c.kubeClient.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{InstallationLabel: installation.Name},
+		},
+	}
+	_, err = c.kubeClient.CoreV1().Namespaces().Create(namespace)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "Failed to create namespace %q", name)
+	}
+	return nil
+}
+
+// ensureServingCert generates a self-signed serving certificate for
+// the admission webhook of an installation unless its secret already
+// exists, and returns the CA bundle the API server should use to
+// verify the webhook.
+func (c *Controller) ensureServingCert(installation *fedv1b1.KubeFedInstallation, namespace string) ([]byte, error) {
+	secret, err := c.kubeClient.CoreV1().Secrets(namespace).Get(webhookServingCertSecret, metav1.GetOptions{})
+	exists := err == nil
+	if exists && len(secret.Data[corev1.ServiceAccountRootCAKey]) > 0 {
+		return secret.Data[corev1.ServiceAccountRootCAKey], nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "Failed to get secret %q", webhookServingCertSecret)
+	}
+
+	host := fmt.Sprintf("%s.%s.svc", webhookService, namespace)
+	alternateDNS := []string{webhookService, fmt.Sprintf("%s.%s", webhookService, namespace)}
+	// The certificate is followed by the certificate of the CA that
+	// signed it, so that it is also the CA bundle of the webhook.
+	cert, key, err := certutil.GenerateSelfSignedCertKey(host, nil, alternateDNS)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate the serving certificate of the admission webhook")
+	}
+	data := map[string][]byte{
+		corev1.TLSCertKey:              cert,
+		corev1.TLSPrivateKeyKey:        key,
+		corev1.ServiceAccountRootCAKey: cert,
+	}
+
+	if exists {
+		secret.Data = data
+		_, err = c.kubeClient.CoreV1().Secrets(namespace).Update(secret)
+	} else {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      webhookServingCertSecret,
+				Labels:    map[string]string{InstallationLabel: installation.Name},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}
+		_, err = c.kubeClient.CoreV1().Secrets(namespace).Create(secret)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to write secret %q", webhookServingCertSecret)
+	}
+	klog.Infof("Generated the serving certificate of the admission webhook in namespace %q", namespace)
+	return cert, nil
+}
+
+// deploymentsReady returns whether every deployment among the given
+// objects has rolled out and is available, or a message describing
+// the first deployment that is not.
+func (c *Controller) deploymentsReady(objs []*unstructured.Unstructured) (bool, string, error) {
+	for _, obj := range objs {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+		deployment, err := c.kubeClient.AppsV1().Deployments(obj.GetNamespace()).Get(obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, "", err
+		}
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation ||
+			deployment.Status.UpdatedReplicas < replicas ||
+			deployment.Status.AvailableReplicas < replicas {
+			return false, fmt.Sprintf("Deployment %q has %d of %d updated replicas available",
+				obj.GetName(), deployment.Status.AvailableReplicas, replicas), nil
+		}
+	}
+	return true, "", nil
+}
+
+func (c *Controller) updateStatus(installation *fedv1b1.KubeFedInstallation) util.ReconciliationStatus {
+	if err := c.client.UpdateStatus(context.TODO(), installation); err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to update status of KubeFedInstallation %q", installation.Name))
+		return util.StatusError
+	}
+	return util.StatusAllOK
+}
+
+func (c *Controller) objCopyFromCache(key string) (pkgruntime.Object, error) {
+	cachedObj, exist, err := c.store.GetByKey(key)
+	if err != nil {
+		wrappedErr := errors.Wrapf(err, "Failed to query KubeFedInstallation store for %q", key)
+		runtime.HandleError(wrappedErr)
+		return nil, err
+	}
+	if !exist {
+		return nil, nil
+	}
+	return cachedObj.(pkgruntime.Object).DeepCopyObject(), nil
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// InstallationLabel is set on the resources applied for a
+	// KubeFedInstallation to the name of the installation.
+	InstallationLabel = "kubefed.io/installation"
+
+	// DefaultManifestsNamespace is the namespace the manifests of a
+	// release are rendered for.
+	DefaultManifestsNamespace = "kube-federation-system"
+
+	controllerManagerDeployment = "kubefed-controller-manager"
+	controllerManagerContainer  = "controller-manager"
+	webhookDeployment           = "kubefed-admission-webhook"
+	webhookContainer            = "admission-webhook"
+	webhookService              = "kubefed-admission-webhook"
+	webhookServingCertSecret    = "kubefed-admission-webhook-serving-cert"
+)
+
+// ManifestsFilename returns the name of the file holding the manifests
+// of the given version of KubeFed for control planes of the given
+// scope, e.g. v0.4.0-cluster.yaml.
+func ManifestsFilename(version string, scope apiextv1b1.ResourceScope) string {
+	if scope == "" {
+		scope = apiextv1b1.ClusterScoped
+	}
+	return fmt.Sprintf("%s-%s.yaml", version, strings.ToLower(string(scope)))
+}
+
+// loadManifests reads the manifests of the version and scope of the
+// given installation from dir and customizes them for the
+// installation. The manifests are expected to be rendered for
+// manifestsNamespace, which is replaced by the KubeFed namespace of
+// the installation.
+func loadManifests(dir, manifestsNamespace string, installation *fedv1b1.KubeFedInstallation) ([]*unstructured.Unstructured, error) {
+	filename := filepath.Join(dir, ManifestsFilename(installation.Spec.Version, installation.Spec.Scope))
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	// Besides the namespace of namespaced resources, the namespace is
+	// referenced by role bindings, webhook configurations and the names
+	// of cluster-scoped resources of namespace-scoped control planes.
+	data = bytes.ReplaceAll(data, []byte(manifestsNamespace), []byte(installation.Spec.KubeFedNamespace))
+
+	objs, err := decodeManifests(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode %q", filename)
+	}
+	result := []*unstructured.Unstructured{}
+	for _, obj := range objs {
+		// The serving certificate of the webhook is generated for each
+		// installation rather than shared by every installation of the
+		// release.
+		if obj.GetKind() == "Secret" && obj.GetName() == webhookServingCertSecret {
+			continue
+		}
+		if err := customizeObject(obj, installation); err != nil {
+			return nil, errors.Wrapf(err, "Failed to customize %s %q", obj.GetKind(), obj.GetName())
+		}
+		result = append(result, obj)
+	}
+	sortForApply(result)
+	return result, nil
+}
+
+// decodeManifests decodes the objects of a multi-document yaml stream,
+// skipping empty documents.
+func decodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		buf, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(buf, &obj.Object); err != nil {
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// customizeObject labels the given object with the name of the
+// installation and applies the image repository, compute resources and
+// webhook configuration of the installation.
+func customizeObject(obj *unstructured.Unstructured, installation *fedv1b1.KubeFedInstallation) error {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[InstallationLabel] = installation.Name
+	obj.SetLabels(labels)
+
+	spec := installation.Spec
+	switch {
+	case obj.GetKind() == "Deployment" && obj.GetName() == controllerManagerDeployment:
+		return customizeContainers(obj, spec.ImageRepository, controllerManagerContainer, spec.ControllerManagerResources)
+	case obj.GetKind() == "Deployment" && obj.GetName() == webhookDeployment:
+		return customizeContainers(obj, spec.ImageRepository, webhookContainer, spec.WebhookResources)
+	case obj.GetKind() == "KubeFedConfig" && spec.Webhook != nil:
+		webhook, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec.Webhook)
+		if err != nil {
+			return err
+		}
+		return unstructured.SetNestedMap(obj.Object, webhook, "spec", "webhook")
+	}
+	return nil
+}
+
+// customizeContainers replaces the repository of the images of the
+// containers of a deployment and the compute resources of the named
+// container.
+func customizeContainers(obj *unstructured.Unstructured, repository, containerName string, resources *corev1.ResourceRequirements) error {
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return err
+	}
+	for i := range containers {
+		container, ok := containers[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if image, ok := container["image"].(string); ok && repository != "" {
+			container["image"] = imageWithRepository(image, repository)
+		}
+		if container["name"] == containerName && resources != nil {
+			resourcesMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resources)
+			if err != nil {
+				return err
+			}
+			container["resources"] = resourcesMap
+		}
+	}
+	return unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+}
+
+// imageWithRepository returns the given image with its repository
+// replaced, e.g. registry.example.com/kubefed:v0.4.0 for
+// quay.io/kubernetes-multicluster/kubefed:v0.4.0.
+func imageWithRepository(image, repository string) string {
+	name := image[strings.LastIndex(image, "/")+1:]
+	return strings.TrimSuffix(repository, "/") + "/" + name
+}
+
+// setCABundle sets the CA bundle of the webhooks of the admission
+// webhook configurations among the given objects.
+func setCABundle(objs []*unstructured.Unstructured, caBundle []byte) error {
+	for _, obj := range objs {
+		if obj.GetKind() != "ValidatingWebhookConfiguration" && obj.GetKind() != "MutatingWebhookConfiguration" {
+			continue
+		}
+		webhooks, _, err := unstructured.NestedSlice(obj.Object, "webhooks")
+		if err != nil {
+			return err
+		}
+		for i := range webhooks {
+			webhook, ok := webhooks[i].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := unstructured.SetNestedField(webhook, base64.StdEncoding.EncodeToString(caBundle), "clientConfig", "caBundle"); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, webhooks, "webhooks"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortForApply orders namespaces before the CRDs, and the CRDs before
+// all other objects, which may be instances of the CRDs.
+func sortForApply(objs []*unstructured.Unstructured) {
+	rank := func(obj *unstructured.Unstructured) int {
+		switch obj.GetKind() {
+		case "Namespace":
+			return 0
+		case "CustomResourceDefinition":
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		return rank(objs[i]) < rank(objs[j])
+	})
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const testManifests = `---
+# Source: kubefed/charts/controllermanager/templates/deployments.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kubefed-controller-manager
+  namespace: kube-federation-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: controller-manager
+        image: quay.io/kubernetes-multicluster/kubefed:v0.4.0
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: kubefed-admission-webhook-serving-cert
+  namespace: kube-federation-system
+---
+apiVersion: core.kubefed.io/v1beta1
+kind: KubeFedConfig
+metadata:
+  name: kubefed
+  namespace: kube-federation-system
+spec:
+  scope: Cluster
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: validations.core.kubefed.io
+webhooks:
+- name: federatedtypeconfigs.core.kubefed.io
+  clientConfig:
+    service:
+      namespace: kube-federation-system
+      name: kubefed-admission-webhook
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: kubefedconfigs.core.kubefed.io
+`
+
+func TestLoadManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubefed-operator")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "v0.4.0-cluster.yaml"), []byte(testManifests), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	failurePolicy := fedv1b1.WebhookFailurePolicyIgnore
+	installation := &fedv1b1.KubeFedInstallation{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubefed"},
+		Spec: fedv1b1.KubeFedInstallationSpec{
+			Version:          "v0.4.0",
+			KubeFedNamespace: "kubefed",
+			ImageRepository:  "registry.example.com/mirror",
+			Webhook:          &fedv1b1.WebhookConfig{FailurePolicy: &failurePolicy},
+			ControllerManagerResources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		},
+	}
+
+	objs, err := loadManifests(dir, DefaultManifestsNamespace, installation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	kinds := []string{}
+	for _, obj := range objs {
+		kinds = append(kinds, obj.GetKind())
+		if obj.GetLabels()[InstallationLabel] != installation.Name {
+			t.Errorf("Expected %s %q to be labeled with the installation", obj.GetKind(), obj.GetName())
+		}
+	}
+	expectedKinds := []string{"CustomResourceDefinition", "Deployment", "KubeFedConfig", "ValidatingWebhookConfiguration"}
+	if len(kinds) != len(expectedKinds) {
+		t.Fatalf("Expected kinds %v, got %v", expectedKinds, kinds)
+	}
+	for i := range kinds {
+		if kinds[i] != expectedKinds[i] {
+			t.Fatalf("Expected kinds %v, got %v", expectedKinds, kinds)
+		}
+	}
+
+	deployment := objs[1]
+	if deployment.GetNamespace() != "kubefed" {
+		t.Errorf("Expected namespace %q, got %q", "kubefed", deployment.GetNamespace())
+	}
+	containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	if image := container["image"]; image != "registry.example.com/mirror/kubefed:v0.4.0" {
+		t.Errorf("Expected the image of the mirror, got %q", image)
+	}
+	memory, _, _ := unstructured.NestedString(container, "resources", "limits", "memory")
+	if memory != "512Mi" {
+		t.Errorf("Expected a memory limit of 512Mi, got %q", memory)
+	}
+
+	policy, _, _ := unstructured.NestedString(objs[2].Object, "spec", "webhook", "failurePolicy")
+	if policy != string(failurePolicy) {
+		t.Errorf("Expected failure policy %q, got %q", failurePolicy, policy)
+	}
+
+	caBundle := []byte("ca")
+	if err := setCABundle(objs, caBundle); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	webhooks, _, _ := unstructured.NestedSlice(objs[3].Object, "webhooks")
+	webhook := webhooks[0].(map[string]interface{})
+	if value, _, _ := unstructured.NestedString(webhook, "clientConfig", "caBundle"); value != base64.StdEncoding.EncodeToString(caBundle) {
+		t.Errorf("Expected the CA bundle to be set, got %q", value)
+	}
+	if namespace, _, _ := unstructured.NestedString(webhook, "clientConfig", "service", "namespace"); namespace != "kubefed" {
+		t.Errorf("Expected the webhook service in namespace %q, got %q", "kubefed", namespace)
+	}
+}
+
+func TestLoadManifestsUnavailable(t *testing.T) {
+	installation := &fedv1b1.KubeFedInstallation{
+		Spec: fedv1b1.KubeFedInstallationSpec{
+			Version:          "v0.4.0",
+			Scope:            apiextv1b1.NamespaceScoped,
+			KubeFedNamespace: "kubefed",
+		},
+	}
+	if _, err := loadManifests(os.TempDir(), DefaultManifestsNamespace, installation); err == nil {
+		t.Errorf("Expected an error for missing manifests")
+	}
+}
+
+func TestImageWithRepository(t *testing.T) {
+	testCases := map[string]string{
+		"quay.io/kubernetes-multicluster/kubefed:v0.4.0": "registry.example.com/kubefed:v0.4.0",
+		"kubefed:v0.4.0": "registry.example.com/kubefed:v0.4.0",
+	}
+	for image, expected := range testCases {
+		if result := imageWithRepository(image, "registry.example.com/"); result != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, image, result)
+		}
+	}
+}
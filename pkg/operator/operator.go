@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/version"
+)
+
+const defaultManifestsDir = "/manifests"
+
+// NewOperatorCommand creates the command running the KubeFed operator,
+// which installs and upgrades KubeFed control planes described by
+// KubeFedInstallations.
+func NewOperatorCommand(stopChan <-chan struct{}) *cobra.Command {
+	var kubeconfig, masterURL, manifestsDir, manifestsNamespace string
+	verFlag := false
+
+	cmd := &cobra.Command{
+		Use:   "operator",
+		Short: "Start the KubeFed operator",
+		Long: `The KubeFed operator installs and upgrades the KubeFed control
+planes described by KubeFedInstallations by applying the
+manifests of the desired release, customized for each
+installation`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(os.Stdout, "KubeFed operator version: %s\n", fmt.Sprintf("%#v", version.Get()))
+			if verFlag {
+				os.Exit(0)
+			}
+
+			config, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
+			if err != nil {
+				klog.Fatalf("Error building kubeconfig: %v", err)
+			}
+			if err := StartController(config, manifestsDir, manifestsNamespace, stopChan); err != nil {
+				klog.Fatalf("Error starting KubeFedInstallation controller: %v", err)
+			}
+			<-stopChan
+		},
+	}
+
+	cmd.Flags().AddGoFlagSet(flag.CommandLine)
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	cmd.Flags().StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	cmd.Flags().StringVar(&manifestsDir, "manifests-dir", defaultManifestsDir, "The directory holding the manifests of each release and scope, named <version>-<scope>.yaml, e.g. v0.4.0-cluster.yaml.")
+	cmd.Flags().StringVar(&manifestsNamespace, "manifests-namespace", DefaultManifestsNamespace, "The namespace the manifests are rendered for, which is replaced by the KubeFed namespace of each installation.")
+	cmd.Flags().BoolVar(&verFlag, "version", false, "Prints the Version info of the operator.")
+
+	return cmd
+}
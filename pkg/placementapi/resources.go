@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// resourceSet accesses the federated resources of the types enabled
+// in the control plane on behalf of the caller of a request.
+type resourceSet struct {
+	config           *rest.Config
+	client           genericclient.Client
+	kubeFedNamespace string
+
+	typeConfigs     map[string]*fedv1b1.FederatedTypeConfig
+	resourceClients map[string]util.ResourceClient
+}
+
+func (s *Server) newResourceSet(config *rest.Config) (*resourceSet, error) {
+	client, err := genericclient.New(config)
+	if err != nil {
+		return nil, err
+	}
+	return &resourceSet{
+		config:           config,
+		client:           client,
+		kubeFedNamespace: s.kubeFedNamespace,
+		typeConfigs:      make(map[string]*fedv1b1.FederatedTypeConfig),
+		resourceClients:  make(map[string]util.ResourceClient),
+	}, nil
+}
+
+func (r *resourceSet) typeConfig(typeName string) (*fedv1b1.FederatedTypeConfig, error) {
+	if typeConfig, ok := r.typeConfigs[typeName]; ok {
+		return typeConfig, nil
+	}
+	typeConfig := &fedv1b1.FederatedTypeConfig{}
+	err := r.client.Get(context.Background(), typeConfig, r.kubeFedNamespace, typeName)
+	if apierrors.IsNotFound(err) {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("type %q is not enabled", typeName))
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.typeConfigs[typeName] = typeConfig
+	return typeConfig, nil
+}
+
+// federatedKinds returns the names of the types with the kind of their
+// federated type as key, limited to the given type if not empty.
+func (r *resourceSet) federatedKinds(typeName string) (map[string]string, error) {
+	kinds := make(map[string]string)
+	if len(typeName) > 0 {
+		typeConfig, err := r.typeConfig(typeName)
+		if err != nil {
+			return nil, err
+		}
+		kinds[typeConfig.GetFederatedType().Kind] = typeName
+		return kinds, nil
+	}
+	typeConfigList := &fedv1b1.FederatedTypeConfigList{}
+	err := r.client.List(context.Background(), typeConfigList, r.kubeFedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, typeConfig := range typeConfigList.Items {
+		kinds[typeConfig.GetFederatedType().Kind] = typeConfig.Name
+	}
+	return kinds, nil
+}
+
+func (r *resourceSet) resourceClient(ref ResourceReference) (util.ResourceClient, error) {
+	typeConfig, err := r.typeConfig(ref.Type)
+	if err != nil {
+		return nil, err
+	}
+	federatedType := typeConfig.GetFederatedType()
+	if federatedType.Namespaced != (len(ref.Namespace) > 0) {
+		if federatedType.Namespaced {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("a namespace is required for type %q", ref.Type))
+		}
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("type %q is not namespaced", ref.Type))
+	}
+	if client, ok := r.resourceClients[ref.Type]; ok {
+		return client, nil
+	}
+	client, err := util.NewResourceClient(r.config, &federatedType)
+	if err != nil {
+		return nil, err
+	}
+	r.resourceClients[ref.Type] = client
+	return client, nil
+}
+
+func (r *resourceSet) get(ref ResourceReference) (*unstructured.Unstructured, error) {
+	client, err := r.resourceClient(ref)
+	if err != nil {
+		return nil, err
+	}
+	return client.Resources(ref.Namespace).Get(ref.Name, metav1.GetOptions{})
+}
+
+func (r *resourceSet) update(ref ResourceReference, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	client, err := r.resourceClient(ref)
+	if err != nil {
+		return nil, err
+	}
+	return client.Resources(ref.Namespace).Update(obj, metav1.UpdateOptions{})
+}
+
+// updatePlacements replaces the placement of the referenced resources
+// with the given placement. All resources are retrieved before any is
+// updated, and if an update fails the resources already updated are
+// restored to their previous placement so that either all or none of
+// the resources end up with the new placement. If a resource version
+// is given, the update fails with a conflict if it does not match the
+// version of the resource.
+func (r *resourceSet) updatePlacements(refs []ResourceReference, placement util.GenericPlacementFields, resourceVersion string) ([]Placement, error) {
+	seen := sets.NewString()
+	objs := make([]*unstructured.Unstructured, 0, len(refs))
+	for _, ref := range refs {
+		key := fmt.Sprintf("%s/%s/%s", ref.Type, ref.Namespace, ref.Name)
+		if seen.Has(key) {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("resource %q is listed more than once", key))
+		}
+		seen.Insert(key)
+
+		obj, err := r.get(ref)
+		if err != nil {
+			return nil, err
+		}
+		if len(resourceVersion) > 0 && obj.GetResourceVersion() != resourceVersion {
+			federatedType := r.typeConfigs[ref.Type].GetFederatedType()
+			groupResource := schema.GroupResource{Group: federatedType.Group, Resource: federatedType.Name}
+			return nil, apierrors.NewConflict(groupResource, ref.Name, errors.Errorf("the resource version is %q", obj.GetResourceVersion()))
+		}
+		objs = append(objs, obj)
+	}
+
+	placements := make([]Placement, 0, len(refs))
+	for i, obj := range objs {
+		updatedObj := obj.DeepCopy()
+		if err := util.SetPlacement(updatedObj, placement); err != nil {
+			r.restorePlacements(refs[:i], objs[:i])
+			return nil, err
+		}
+		updatedObj, err := r.update(refs[i], updatedObj)
+		if err != nil {
+			r.restorePlacements(refs[:i], objs[:i])
+			return nil, errors.Wrapf(err, "failed to update the placement of %q", util.NewQualifiedName(obj))
+		}
+		result, err := newPlacement(refs[i], updatedObj)
+		if err != nil {
+			return nil, err
+		}
+		placements = append(placements, *result)
+	}
+	return placements, nil
+}
+
+// restorePlacements restores the placement of the referenced resources
+// to the placement of the given objects.
+func (r *resourceSet) restorePlacements(refs []ResourceReference, objs []*unstructured.Unstructured) {
+	for i, ref := range refs {
+		placement, ok, _ := unstructured.NestedFieldCopy(objs[i].Object, util.SpecField, util.PlacementField)
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			obj, err := r.get(ref)
+			if err != nil {
+				return err
+			}
+			if ok {
+				err = unstructured.SetNestedField(obj.Object, placement, util.SpecField, util.PlacementField)
+				if err != nil {
+					return err
+				}
+			} else {
+				unstructured.RemoveNestedField(obj.Object, util.SpecField, util.PlacementField)
+			}
+			_, err = r.update(ref, obj)
+			return err
+		})
+		if err != nil {
+			klog.Errorf("Failed to restore the placement of %q: %v", util.NewQualifiedName(objs[i]), err)
+		}
+	}
+}
+
+func newPlacement(ref ResourceReference, obj *unstructured.Unstructured) (*Placement, error) {
+	genericPlacement, err := util.UnmarshalGenericPlacement(obj)
+	if err != nil {
+		return nil, err
+	}
+	resource := &status.GenericFederatedResource{}
+	if err := util.UnstructuredToInterface(obj, resource); err != nil {
+		return nil, err
+	}
+	placement := &Placement{
+		ResourceReference: ref,
+		ResourceVersion:   obj.GetResourceVersion(),
+		Placement:         genericPlacement.Spec.Placement,
+	}
+	if resource.Status != nil {
+		placement.Clusters = resource.Status.Clusters
+		placement.PlacementDecisions = resource.Status.PlacementDecisions
+	}
+	return placement, nil
+}
@@ -0,0 +1,413 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package placementapi serves a REST API for querying and updating
+// the placement of federated resources and streaming the events
+// recorded for their propagation. Requests are made to the API server
+// of the host cluster with the bearer token of the caller so that the
+// caller is subject to its own RBAC permissions.
+package placementapi
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	placementsPath = "/v1/placements"
+	eventsPath     = "/v1/events"
+
+	// maxRequestSize bounds the size of the body of a request.
+	maxRequestSize = 1024 * 1024
+)
+
+var (
+	placementsResource = schema.GroupResource{Resource: "placements"}
+	eventsResource     = schema.GroupResource{Resource: "events"}
+)
+
+// ResourceReference identifies a federated resource by the name of the
+// FederatedTypeConfig of its type, e.g. `deployments.apps`, and its
+// namespace and name.
+type ResourceReference struct {
+	Type      string `json:"type"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Placement is the placement of a federated resource along with the
+// clusters it has been propagated to.
+type Placement struct {
+	ResourceReference `json:",inline"`
+
+	ResourceVersion string                      `json:"resourceVersion"`
+	Placement       util.GenericPlacementFields `json:"placement"`
+
+	// The propagation status of the resource in each cluster it is
+	// placed in, as recorded by the sync controller.
+	Clusters []status.GenericClusterStatus `json:"clusters,omitempty"`
+	// Why each cluster was selected or excluded, if recorded.
+	PlacementDecisions []status.GenericPlacementDecision `json:"placementDecisions,omitempty"`
+}
+
+// PlacementUpdate replaces the placement of a federated resource. The
+// update fails with a conflict if a resource version is given and the
+// resource has since been modified.
+type PlacementUpdate struct {
+	ResourceVersion string                      `json:"resourceVersion,omitempty"`
+	Placement       util.GenericPlacementFields `json:"placement"`
+}
+
+// BatchPlacementUpdate replaces the placement of a set of federated
+// resources with the same placement.
+type BatchPlacementUpdate struct {
+	Resources []ResourceReference         `json:"resources"`
+	Placement util.GenericPlacementFields `json:"placement"`
+}
+
+// BatchPlacementResult lists the placements resulting from a batch
+// update.
+type BatchPlacementResult struct {
+	Placements []Placement `json:"placements"`
+}
+
+// Event is an event recorded for a federated resource.
+type Event struct {
+	Resource      ResourceReference `json:"resource"`
+	Type          string            `json:"type"`
+	Reason        string            `json:"reason"`
+	Message       string            `json:"message"`
+	Count         int32             `json:"count,omitempty"`
+	LastTimestamp metav1.Time       `json:"lastTimestamp,omitempty"`
+}
+
+// Server serves the placement API.
+type Server struct {
+	config           *rest.Config
+	kubeFedNamespace string
+}
+
+// NewServer returns a server for the federated resources of the
+// control plane in the given namespace of the host cluster with the
+// given config. The credentials of the config are not used.
+func NewServer(config *rest.Config, kubeFedNamespace string) *Server {
+	return &Server{
+		config:           rest.AnonymousClientConfig(config),
+		kubeFedNamespace: kubeFedNamespace,
+	}
+}
+
+// Handler returns the handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(placementsPath, s.handleBatch)
+	mux.HandleFunc(placementsPath+"/", s.handlePlacement)
+	mux.HandleFunc(eventsPath, s.handleEvents)
+	return mux
+}
+
+// Serve serves the API at the given address until the stop channel is
+// closed. The API is served over TLS with the given certificate and key
+// files if provided. Since callers authenticate with bearer tokens, the
+// API is only served over plain HTTP on a loopback address.
+func (s *Server) Serve(address, certFile, keyFile string, stopChan <-chan struct{}) {
+	serveTLS := len(certFile) > 0 || len(keyFile) > 0
+	if err := util.ValidateServingAddress(address, serveTLS); err != nil {
+		klog.Fatalf("Error serving the placement API: %v", err)
+	}
+
+	server := &http.Server{Addr: address, Handler: s.Handler()}
+	if util.RestrictedCompliance() {
+		server.TLSConfig = &tls.Config{}
+		util.RestrictTLSConfig(server.TLSConfig)
+	}
+	go func() {
+		<-stopChan
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Errorf("Error shutting down the placement API server: %v", err)
+		}
+	}()
+	var err error
+	if serveTLS {
+		klog.Infof("Serving the placement API at %s over TLS", address)
+		err = server.ListenAndServeTLS(certFile, keyFile)
+	} else {
+		klog.Infof("Serving the placement API at %s", address)
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		klog.Fatalf("Error serving the placement API: %v", err)
+	}
+}
+
+// requestConfig returns the config for requests to the API server on
+// behalf of the caller of the given request.
+func (s *Server) requestConfig(r *http.Request) (*rest.Config, error) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, prefix) || len(auth) == len(prefix) {
+		return nil, apierrors.NewUnauthorized("a bearer token is required")
+	}
+	config := rest.CopyConfig(s.config)
+	config.BearerToken = strings.TrimPrefix(auth, prefix)
+	return config, nil
+}
+
+func (s *Server) handlePlacement(w http.ResponseWriter, r *http.Request) {
+	ref, err := parseResourcePath(strings.TrimPrefix(r.URL.Path, placementsPath+"/"))
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	config, err := s.requestConfig(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resources, err := s.newResourceSet(config)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		obj, err := resources.get(ref)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		placement, err := newPlacement(ref, obj)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, placement)
+	case http.MethodPut:
+		update := &PlacementUpdate{}
+		if err := decodeBody(r, update); err != nil {
+			writeError(w, err)
+			return
+		}
+		placements, err := resources.updatePlacements([]ResourceReference{ref}, update.Placement, update.ResourceVersion)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, placements[0])
+	default:
+		writeError(w, apierrors.NewMethodNotSupported(placementsResource, r.Method))
+	}
+}
+
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, apierrors.NewMethodNotSupported(placementsResource, r.Method))
+		return
+	}
+	config, err := s.requestConfig(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	update := &BatchPlacementUpdate{}
+	if err := decodeBody(r, update); err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(update.Resources) == 0 {
+		writeError(w, apierrors.NewBadRequest("at least one resource is required"))
+		return
+	}
+	resources, err := s.newResourceSet(config)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	placements, err := resources.updatePlacements(update.Resources, update.Placement, "")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &BatchPlacementResult{Placements: placements})
+}
+
+// handleEvents streams the events recorded for federated resources as
+// newline-delimited JSON until the client disconnects. The events can
+// be limited to a namespace and a type with the `namespace` and `type`
+// query parameters.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, apierrors.NewMethodNotSupported(eventsResource, r.Method))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errors.New("streaming is not supported"))
+		return
+	}
+	config, err := s.requestConfig(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resources, err := s.newResourceSet(config)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// The events of the kinds of all federated types are streamed
+	// unless a type is given.
+	typeName := r.URL.Query().Get("type")
+	typeNames, err := resources.federatedKinds(typeName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	opts := metav1.ListOptions{}
+	if len(typeName) > 0 {
+		for kind := range typeNames {
+			opts.FieldSelector = fields.OneTermEqualSelector("involvedObject.kind", kind).String()
+		}
+	}
+
+	kubeClient, err := kubeclientset.NewForConfig(config)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	watcher, err := kubeClient.CoreV1().Events(r.URL.Query().Get("namespace")).Watch(opts)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer watcher.Stop()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case watchEvent, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			if watchEvent.Type != watch.Added && watchEvent.Type != watch.Modified {
+				continue
+			}
+			event, ok := watchEvent.Object.(*corev1.Event)
+			if !ok {
+				continue
+			}
+			typeName, ok := typeNames[event.InvolvedObject.Kind]
+			if !ok {
+				continue
+			}
+			err := encoder.Encode(&Event{
+				Resource: ResourceReference{
+					Type:      typeName,
+					Namespace: event.InvolvedObject.Namespace,
+					Name:      event.InvolvedObject.Name,
+				},
+				Type:          event.Type,
+				Reason:        event.Reason,
+				Message:       event.Message,
+				Count:         event.Count,
+				LastTimestamp: event.LastTimestamp,
+			})
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// parseResourcePath parses a path of the form TYPE/NAMESPACE/NAME for
+// a namespaced resource or TYPE/NAME for a cluster-scoped resource.
+func parseResourcePath(path string) (ResourceReference, error) {
+	parts := strings.Split(path, "/")
+	for _, part := range parts {
+		if len(part) == 0 {
+			return ResourceReference{}, errors.Errorf("invalid resource path %q", path)
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return ResourceReference{Type: parts[0], Name: parts[1]}, nil
+	case 3:
+		return ResourceReference{Type: parts[0], Namespace: parts[1], Name: parts[2]}, nil
+	default:
+		return ResourceReference{}, errors.Errorf("invalid resource path %q", path)
+	}
+}
+
+func decodeBody(r *http.Request, obj interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return apierrors.NewBadRequest(errors.Wrap(err, "invalid request body").Error())
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
+
+// writeError writes the given error as a metav1.Status, preserving the
+// status of errors returned by the API server.
+func writeError(w http.ResponseWriter, err error) {
+	apiStatus, ok := errors.Cause(err).(apierrors.APIStatus)
+	if !ok {
+		apiStatus = apierrors.NewInternalError(err)
+	}
+	status := apiStatus.Status()
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+	if status.Code == 0 {
+		status.Code = http.StatusInternalServerError
+	}
+	writeJSON(w, int(status.Code), &status)
+}
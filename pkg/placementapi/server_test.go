@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestParseResourcePath(t *testing.T) {
+	testCases := map[string]struct {
+		path        string
+		expectedRef ResourceReference
+		expectError bool
+	}{
+		"Namespaced resource": {
+			path:        "deployments.apps/myns/web",
+			expectedRef: ResourceReference{Type: "deployments.apps", Namespace: "myns", Name: "web"},
+		},
+		"Cluster-scoped resource": {
+			path:        "clusterroles.rbac.authorization.k8s.io/admin",
+			expectedRef: ResourceReference{Type: "clusterroles.rbac.authorization.k8s.io", Name: "admin"},
+		},
+		"Missing name": {
+			path:        "deployments.apps",
+			expectError: true,
+		},
+		"Empty namespace": {
+			path:        "deployments.apps//web",
+			expectError: true,
+		},
+		"Too many segments": {
+			path:        "deployments.apps/myns/web/status",
+			expectError: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			ref, err := parseResourcePath(tc.path)
+			if tc.expectError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.expectError, err)
+			}
+			if ref != tc.expectedRef {
+				t.Errorf("Expected %v, got %v", tc.expectedRef, ref)
+			}
+		})
+	}
+}
+
+func TestRequestWithoutToken(t *testing.T) {
+	server := NewServer(&rest.Config{Host: "https://127.0.0.1:6443"}, "kube-federation-system")
+	for _, path := range []string{"/v1/placements/deployments.apps/myns/web", "/v1/events"} {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusUnauthorized, path, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// clusterAffinity applies the cluster affinity of the given placement
+// of the target federated resource of an RSP. Clusters not matching
+// the required cluster selector are removed from the given cluster
+// names, since the sync controller would not propagate replicas to
+// them, and the weights of the preferred cluster selectors are added
+// to the preferences of the clusters matching them.
+func clusterAffinity(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, placement *util.GenericPlacement, clusterNames []string, clusters []*fedv1b1.KubeFedCluster) ([]string, *fedschedulingv1a1.ReplicaSchedulingPreference, error) {
+	if placement == nil {
+		return clusterNames, rsp, nil
+	}
+
+	clusterNames, err := requiredClusterNames(placement, clusterNames, clusters)
+	if err != nil {
+		return nil, nil, err
+	}
+	weights, err := placement.PreferredClusterWeights(clusters)
+	if err != nil {
+		return nil, nil, err
+	}
+	return clusterNames, preferredPreferences(rsp, clusterNames, weights), nil
+}
+
+// requiredClusterNames returns the given cluster names excluding the
+// clusters whose labels do not match the required cluster selector of
+// the given placement.
+func requiredClusterNames(placement *util.GenericPlacement, clusterNames []string, clusters []*fedv1b1.KubeFedCluster) ([]string, error) {
+	selector, err := placement.RequiredClusterSelector()
+	if err != nil || selector == nil {
+		return clusterNames, err
+	}
+	clusterLabels := make(map[string]map[string]string, len(clusters))
+	for _, cluster := range clusters {
+		clusterLabels[cluster.Name] = cluster.Labels
+	}
+	requiredNames := []string{}
+	for _, clusterName := range clusterNames {
+		if selector.Matches(labels.Set(clusterLabels[clusterName])) {
+			requiredNames = append(requiredNames, clusterName)
+		}
+	}
+	return requiredNames, nil
+}
+
+// preferredPreferences returns the given RSP with the given weights
+// added to the weights of the respective clusters. A cluster the RSP
+// gives no weight continues to be given none, so that preferred
+// cluster selectors only favor clusters among those the RSP already
+// schedules replicas to.
+func preferredPreferences(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusterNames []string, weights map[string]int64) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	if len(weights) == 0 {
+		return rsp
+	}
+	rsp = rsp.DeepCopy()
+	if len(rsp.Spec.Clusters) == 0 {
+		rsp.Spec.Clusters = defaultClusterPreferences()
+	}
+
+	clusters := make(map[string]fedschedulingv1a1.ClusterPreferences, len(rsp.Spec.Clusters))
+	for name, preferences := range rsp.Spec.Clusters {
+		clusters[name] = preferences
+	}
+	for _, clusterName := range clusterNames {
+		weight, ok := weights[clusterName]
+		if !ok {
+			continue
+		}
+		preferences, ok := rsp.Spec.Clusters[clusterName]
+		if !ok {
+			preferences, ok = rsp.Spec.Clusters["*"]
+			if !ok {
+				continue
+			}
+		}
+		if preferences.Weight == 0 {
+			continue
+		}
+		preferences.Weight += weight
+		clusters[clusterName] = preferences
+	}
+	rsp.Spec.Clusters = clusters
+	return rsp
+}
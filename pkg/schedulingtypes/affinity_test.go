@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func affinityTestClusters() []*fedv1b1.KubeFedCluster {
+	return []*fedv1b1.KubeFedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "eu-1", Labels: map[string]string{"region": "eu", "tier": "gold"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "eu-2", Labels: map[string]string{"region": "eu"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "us-1", Labels: map[string]string{"region": "us", "tier": "gold"}}},
+	}
+}
+
+func TestRequiredClusterNames(t *testing.T) {
+	clusterNames := []string{"eu-1", "eu-2", "us-1"}
+	testCases := map[string]struct {
+		selector      *metav1.LabelSelector
+		expectedNames []string
+	}{
+		"all clusters without required selector": {
+			expectedNames: clusterNames,
+		},
+		"matching clusters with required selector": {
+			selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}},
+			expectedNames: []string{"eu-1", "eu-2"},
+		},
+		"no clusters when none match": {
+			selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"region": "ap"}},
+			expectedNames: []string{},
+		},
+	}
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			placement := &util.GenericPlacement{}
+			placement.Spec.Placement.RequiredClusterSelector = testCase.selector
+			names, err := requiredClusterNames(placement, clusterNames, affinityTestClusters())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(names, testCase.expectedNames) {
+				t.Errorf("Expected names %v, got %v", testCase.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestPreferredPreferences(t *testing.T) {
+	placement := &util.GenericPlacement{}
+	placement.Spec.Placement.PreferredClusterSelectors = []util.GenericPreferredClusterSelector{
+		{Weight: 10, Preference: metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}},
+		{Weight: 5, Preference: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}}},
+		{Weight: 0, Preference: metav1.LabelSelector{}},
+	}
+	weights, err := placement.PreferredClusterWeights(affinityTestClusters())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedWeights := map[string]int64{"eu-1": 15, "eu-2": 10, "us-1": 5}
+	if !reflect.DeepEqual(weights, expectedWeights) {
+		t.Fatalf("Expected weights %v, got %v", expectedWeights, weights)
+	}
+
+	rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			Clusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*":    {Weight: 1},
+				"us-1": {Weight: 0},
+			},
+		},
+	}
+	result := preferredPreferences(rsp, []string{"eu-1", "eu-2", "us-1"}, weights)
+	expected := map[string]fedschedulingv1a1.ClusterPreferences{
+		"*":    {Weight: 1},
+		"eu-1": {Weight: 16},
+		"eu-2": {Weight: 11},
+		"us-1": {Weight: 0},
+	}
+	if !reflect.DeepEqual(result.Spec.Clusters, expected) {
+		t.Errorf("Expected preferences %v, got %v", expected, result.Spec.Clusters)
+	}
+	if len(rsp.Spec.Clusters) != 2 {
+		t.Errorf("Expected the given RSP to be unchanged")
+	}
+}
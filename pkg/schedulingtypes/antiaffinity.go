@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// antiAffinityIndex records the RSPs whose cluster anti-affinity names
+// each federated workload. The replicas of an RSP are scheduled away
+// from the clusters of the workloads it names, so the RSP has to be
+// rescheduled whenever one of those workloads is rescheduled. The
+// entries of a deleted RSP are retained, and only cause the RSP to be
+// enqueued and found not to exist.
+type antiAffinityIndex struct {
+	sync.Mutex
+	// dependents holds the keys of the RSPs naming each workload,
+	// keyed by workload key.
+	dependents map[string]sets.String
+	// workloads holds the keys of the workloads named by each RSP,
+	// keyed by RSP key.
+	workloads map[string]sets.String
+}
+
+func newAntiAffinityIndex() *antiAffinityIndex {
+	return &antiAffinityIndex{
+		dependents: make(map[string]sets.String),
+		workloads:  make(map[string]sets.String),
+	}
+}
+
+// antiAffinityWorkloadKey returns the key identifying the federated
+// workload of the given kind and qualified name.
+func antiAffinityWorkloadKey(kind string, qualifiedName ctlutil.QualifiedName) string {
+	return fmt.Sprintf("%s/%s", kind, qualifiedName)
+}
+
+// update records the workloads named by the cluster anti-affinity of
+// the given RSP with the given qualified name.
+func (i *antiAffinityIndex) update(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName) {
+	workloads := sets.NewString()
+	for _, term := range rsp.Spec.ClusterAntiAffinity {
+		workloadName := ctlutil.QualifiedName{Namespace: qualifiedName.Namespace, Name: term.Name}
+		workloads.Insert(antiAffinityWorkloadKey(term.TargetKind, workloadName))
+	}
+
+	i.Lock()
+	defer i.Unlock()
+	key := qualifiedName.String()
+	for workload := range i.workloads[key] {
+		i.dependents[workload].Delete(key)
+		if i.dependents[workload].Len() == 0 {
+			delete(i.dependents, workload)
+		}
+	}
+	delete(i.workloads, key)
+	if workloads.Len() == 0 {
+		return
+	}
+	i.workloads[key] = workloads
+	for workload := range workloads {
+		if i.dependents[workload] == nil {
+			i.dependents[workload] = sets.NewString()
+		}
+		i.dependents[workload].Insert(key)
+	}
+}
+
+// dependentsOf returns the qualified names of the RSPs whose cluster
+// anti-affinity names the federated workload of the given kind and
+// qualified name.
+func (i *antiAffinityIndex) dependentsOf(kind string, qualifiedName ctlutil.QualifiedName) []ctlutil.QualifiedName {
+	i.Lock()
+	defer i.Unlock()
+	result := []ctlutil.QualifiedName{}
+	for _, key := range i.dependents[antiAffinityWorkloadKey(kind, qualifiedName)].List() {
+		result = append(result, ctlutil.ParseQualifiedName(key))
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func antiAffinityRSP(name string, workloadNames ...string) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			TargetKind: "FederatedDeployment",
+		},
+	}
+	for _, workloadName := range workloadNames {
+		rsp.Spec.ClusterAntiAffinity = append(rsp.Spec.ClusterAntiAffinity, fedschedulingv1a1.ClusterAntiAffinityTerm{
+			TargetKind: "FederatedDeployment",
+			Name:       workloadName,
+		})
+	}
+	return rsp
+}
+
+func TestAntiAffinityReschedulesDependentRSPs(t *testing.T) {
+	var enqueued []ctlutil.QualifiedName
+	s := &ReplicaScheduler{
+		antiAffinity: newAntiAffinityIndex(),
+		eventHandlers: SchedulerEventHandlers{
+			KubeFedEventHandler: func(obj pkgruntime.Object) {
+				enqueued = append(enqueued, ctlutil.NewQualifiedName(obj))
+			},
+			RecheckHandler: func(qualifiedName ctlutil.QualifiedName, delay time.Duration) {
+				enqueued = append(enqueued, qualifiedName)
+			},
+		},
+	}
+	handlers := s.pluginEventHandlers("FederatedDeployment")
+
+	// Two RSPs whose replicas are scheduled away from each other's
+	// workload.
+	rspA := antiAffinityRSP("a", "b")
+	rspB := antiAffinityRSP("b", "a")
+	s.antiAffinity.update(rspA, ctlutil.NewQualifiedName(rspA))
+	s.antiAffinity.update(rspB, ctlutil.NewQualifiedName(rspB))
+
+	workload := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace("ns")
+		obj.SetName(name)
+		return obj
+	}
+	name := func(name string) ctlutil.QualifiedName {
+		return ctlutil.QualifiedName{Namespace: "ns", Name: name}
+	}
+
+	steps := []struct {
+		description string
+		update      *fedschedulingv1a1.ReplicaSchedulingPreference
+		changed     string
+		expected    []ctlutil.QualifiedName
+	}{
+		{
+			description: "A change to workload a reschedules RSP b",
+			changed:     "a",
+			expected:    []ctlutil.QualifiedName{name("a"), name("b")},
+		},
+		{
+			description: "A change to workload b reschedules RSP a",
+			changed:     "b",
+			expected:    []ctlutil.QualifiedName{name("b"), name("a")},
+		},
+		{
+			description: "A workload that is no longer named does not reschedule RSP b",
+			update:      antiAffinityRSP("b"),
+			changed:     "a",
+			expected:    []ctlutil.QualifiedName{name("a")},
+		},
+		{
+			description: "A workload that is still named reschedules RSP a",
+			changed:     "b",
+			expected:    []ctlutil.QualifiedName{name("b"), name("a")},
+		},
+	}
+	for _, step := range steps {
+		if step.update != nil {
+			s.antiAffinity.update(step.update, ctlutil.NewQualifiedName(step.update))
+		}
+		enqueued = nil
+		handlers.KubeFedEventHandler(workload(step.changed))
+		if !reflect.DeepEqual(enqueued, step.expected) {
+			t.Fatalf("%s: expected %v to be enqueued, got %v", step.description, step.expected, enqueued)
+		}
+	}
+
+	// A workload of another kind with the same name is not named.
+	enqueued = nil
+	s.pluginEventHandlers("FederatedReplicaSet").KubeFedEventHandler(workload("b"))
+	if expected := []ctlutil.QualifiedName{name("b")}; !reflect.DeepEqual(enqueued, expected) {
+		t.Fatalf("Expected %v to be enqueued, got %v", expected, enqueued)
+	}
+}
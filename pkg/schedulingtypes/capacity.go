@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultStabilizationWindow is the period for which an estimated
+	// cluster capacity is honored after last being observed if an RSP
+	// does not specify spec.stabilizationWindowSeconds.
+	DefaultStabilizationWindow = 5 * time.Minute
+)
+
+type capacityEstimate struct {
+	capacity     int64
+	lastObserved time.Time
+}
+
+// capacityTracker remembers the capacity estimated for each cluster of
+// a scheduled workload. Once replicas have been moved away from a
+// cluster that was unable to schedule them, the cluster will no longer
+// report unschedulable pods. Continuing to honor the estimate for a
+// stabilization window prevents the replicas from immediately being
+// scheduled back to the cluster.
+type capacityTracker struct {
+	sync.Mutex
+	estimates map[string]map[string]capacityEstimate
+}
+
+func newCapacityTracker() *capacityTracker {
+	return &capacityTracker{
+		estimates: make(map[string]map[string]capacityEstimate),
+	}
+}
+
+// stabilize records the capacity observed for the clusters of the
+// workload with the given key, and returns the observed capacity
+// merged with previously observed capacity that has not yet expired.
+// Capacity observed now is always returned, so that a window of 0
+// only disables the retention of previously observed capacity.
+func (t *capacityTracker) stabilize(key string, observedCapacity map[string]int64, window time.Duration, now time.Time) map[string]int64 {
+	t.Lock()
+	defer t.Unlock()
+
+	estimates, ok := t.estimates[key]
+	if !ok {
+		estimates = make(map[string]capacityEstimate)
+	}
+	for clusterName, capacity := range observedCapacity {
+		estimates[clusterName] = capacityEstimate{
+			capacity:     capacity,
+			lastObserved: now,
+		}
+	}
+
+	result := make(map[string]int64)
+	for clusterName, estimate := range estimates {
+		if now.Sub(estimate.lastObserved) > window {
+			delete(estimates, clusterName)
+			continue
+		}
+		result[clusterName] = estimate.capacity
+	}
+
+	if len(estimates) == 0 {
+		delete(t.estimates, key)
+	} else {
+		t.estimates[key] = estimates
+	}
+	return result
+}
+
+// forget discards the capacity recorded for the workload with the
+// given key.
+func (t *capacityTracker) forget(key string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.estimates, key)
+}
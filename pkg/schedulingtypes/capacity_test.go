@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestCapacityTrackerStabilize(t *testing.T) {
+	key := "ns/name"
+	window := time.Minute
+	start := time.Now()
+
+	tracker := newCapacityTracker()
+
+	steps := []struct {
+		description string
+		elapsed     time.Duration
+		observed    map[string]int64
+		expected    map[string]int64
+	}{
+		{
+			description: "Observed capacity is returned",
+			observed:    map[string]int64{"cluster1": 3},
+			expected:    map[string]int64{"cluster1": 3},
+		},
+		{
+			description: "Capacity is retained within the window",
+			elapsed:     30 * time.Second,
+			observed:    map[string]int64{"cluster2": 5},
+			expected:    map[string]int64{"cluster1": 3, "cluster2": 5},
+		},
+		{
+			description: "Newly observed capacity replaces retained capacity",
+			elapsed:     45 * time.Second,
+			observed:    map[string]int64{"cluster1": 4},
+			expected:    map[string]int64{"cluster1": 4, "cluster2": 5},
+		},
+		{
+			description: "Capacity expires once the window has elapsed",
+			elapsed:     100 * time.Second,
+			observed:    map[string]int64{},
+			expected:    map[string]int64{"cluster1": 4},
+		},
+		{
+			description: "All capacity eventually expires",
+			elapsed:     200 * time.Second,
+			observed:    map[string]int64{},
+			expected:    map[string]int64{},
+		},
+	}
+	for _, step := range steps {
+		actual := tracker.stabilize(key, step.observed, window, start.Add(step.elapsed))
+		if !reflect.DeepEqual(step.expected, actual) {
+			t.Fatalf("%s: expected %v, got %v", step.description, step.expected, actual)
+		}
+	}
+	if _, ok := tracker.estimates[key]; ok {
+		t.Fatalf("Expected expired estimates to be discarded")
+	}
+}
+
+func TestCapacityTrackerStabilizeWithoutWindow(t *testing.T) {
+	key := "ns/name"
+	start := time.Now()
+
+	tracker := newCapacityTracker()
+
+	// Capacity observed in the same pass is returned even without a
+	// stabilization window.
+	observed := map[string]int64{"cluster1": 3}
+	if actual := tracker.stabilize(key, observed, 0, start); !reflect.DeepEqual(observed, actual) {
+		t.Fatalf("Expected %v, got %v", observed, actual)
+	}
+
+	// Capacity that is no longer observed is not retained.
+	observed = map[string]int64{"cluster2": 5}
+	if actual := tracker.stabilize(key, observed, 0, start.Add(time.Second)); !reflect.DeepEqual(observed, actual) {
+		t.Fatalf("Expected %v, got %v", observed, actual)
+	}
+}
+
+func TestStabilizationWindow(t *testing.T) {
+	seconds := func(value int32) *int32 {
+		return &value
+	}
+	testCases := map[string]struct {
+		seconds        *int32
+		expectedWindow time.Duration
+		expectedErr    bool
+	}{
+		"Default window": {
+			expectedWindow: DefaultStabilizationWindow,
+		},
+		"No window": {
+			seconds:        seconds(0),
+			expectedWindow: 0,
+		},
+		"Configured window": {
+			seconds:        seconds(60),
+			expectedWindow: time.Minute,
+		},
+		"Negative window": {
+			seconds:     seconds(-1),
+			expectedErr: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+				Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+					StabilizationWindowSeconds: tc.seconds,
+				},
+			}
+			window, err := stabilizationWindow(rsp)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if window != tc.expectedWindow {
+				t.Fatalf("Expected window %v, got %v", tc.expectedWindow, window)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+// CordonedClusters returns the names of the given clusters that are
+// cordoned.
+func CordonedClusters(clusters []*fedv1b1.KubeFedCluster) sets.String {
+	cordoned := sets.String{}
+	for _, cluster := range clusters {
+		if cluster.Spec.Unschedulable {
+			cordoned.Insert(cluster.Name)
+		}
+	}
+	return cordoned
+}
+
+// cordonedReplicas returns the replicas of the target workload already
+// propagated to each of the given clusters that is cordoned. No more
+// replicas than these are scheduled to a cordoned cluster.
+func cordonedReplicas(clusterNames []string, cordoned sets.String, key string,
+	objectGetter func(clusterName string, key string) (interface{}, bool, error)) (map[string]int64, error) {
+
+	replicasPerCluster := make(map[string]int64)
+	for _, clusterName := range clusterNames {
+		if !cordoned.Has(clusterName) {
+			continue
+		}
+		obj, exists, err := objectGetter(clusterName, key)
+		if err != nil {
+			return nil, err
+		}
+		replicas := int64(0)
+		if exists {
+			replicas, _, err = unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object, "spec", "replicas")
+			if err != nil {
+				return nil, errors.Wrap(err, "Error retrieving 'replicas' field")
+			}
+		}
+		replicasPerCluster[clusterName] = replicas
+	}
+	return replicasPerCluster, nil
+}
+
+// cordonedPreferences returns the given RSP with the replicas of
+// cordoned clusters bounded by the given maximum replicas. The
+// estimated capacity of a cordoned cluster is also bounded so that
+// neither the minimum replicas of the cluster nor an overflow of
+// other clusters is scheduled to it.
+func cordonedPreferences(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, maxReplicas map[string]int64, estimatedCapacity map[string]int64) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	if len(maxReplicas) == 0 {
+		return rsp
+	}
+	rsp = rsp.DeepCopy()
+	if len(rsp.Spec.Clusters) == 0 {
+		rsp.Spec.Clusters = defaultClusterPreferences()
+	}
+
+	clusters := make(map[string]fedschedulingv1a1.ClusterPreferences, len(rsp.Spec.Clusters))
+	for name, preferences := range rsp.Spec.Clusters {
+		clusters[name] = preferences
+	}
+	for clusterName, replicas := range maxReplicas {
+		if capacity, ok := estimatedCapacity[clusterName]; !ok || replicas < capacity {
+			estimatedCapacity[clusterName] = replicas
+		}
+		preferences, ok := rsp.Spec.Clusters[clusterName]
+		if !ok {
+			preferences, ok = rsp.Spec.Clusters["*"]
+			if !ok {
+				continue
+			}
+		}
+		if preferences.MaxReplicas == nil || replicas < *preferences.MaxReplicas {
+			max := replicas
+			preferences.MaxReplicas = &max
+		}
+		clusters[clusterName] = preferences
+	}
+	rsp.Spec.Clusters = clusters
+	return rsp
+}
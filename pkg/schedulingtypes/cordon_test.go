@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestCordonedReplicas(t *testing.T) {
+	objectGetter := func(clusterName, key string) (interface{}, bool, error) {
+		if clusterName != "cordoned" {
+			return nil, false, nil
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(3)},
+		}}, true, nil
+	}
+	replicas, err := cordonedReplicas([]string{"cordoned", "cordoned-empty", "schedulable"}, sets.NewString("cordoned", "cordoned-empty"), "ns/name", objectGetter)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]int64{"cordoned": 3, "cordoned-empty": 0}
+	if !reflect.DeepEqual(replicas, expected) {
+		t.Errorf("Expected replicas %v, got %v", expected, replicas)
+	}
+}
+
+func TestCordonedPreferences(t *testing.T) {
+	five := int64(5)
+	rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			Clusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*":        {Weight: 1},
+				"cluster1": {Weight: 2, MaxReplicas: &five},
+			},
+		},
+	}
+	estimatedCapacity := map[string]int64{"cluster1": 1}
+	result := cordonedPreferences(rsp, map[string]int64{"cluster1": 2, "cluster2": 0}, estimatedCapacity)
+
+	if *result.Spec.Clusters["cluster1"].MaxReplicas != 2 {
+		t.Errorf("Expected max replicas 2 for cluster1, got %d", *result.Spec.Clusters["cluster1"].MaxReplicas)
+	}
+	if cluster2 := result.Spec.Clusters["cluster2"]; cluster2.Weight != 1 || *cluster2.MaxReplicas != 0 {
+		t.Errorf("Expected weight 1 and max replicas 0 for cluster2, got %v", cluster2)
+	}
+	if *rsp.Spec.Clusters["cluster1"].MaxReplicas != 5 {
+		t.Errorf("Expected the given RSP to be unchanged")
+	}
+	expectedCapacity := map[string]int64{"cluster1": 1, "cluster2": 0}
+	if !reflect.DeepEqual(estimatedCapacity, expectedCapacity) {
+		t.Errorf("Expected capacity %v, got %v", expectedCapacity, estimatedCapacity)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// FailedDomainClusters returns the names of the given clusters that
+// share the fault domain at the given level with a cluster that is not
+// ready. Like cordoned clusters, they are scheduled no more replicas
+// than already propagated to them, so that replicas failing over from
+// the failed cluster land in another fault domain. Clusters without a
+// fault domain at the given level share it with no other cluster.
+func FailedDomainClusters(clusters []*fedv1b1.KubeFedCluster, level fedschedulingv1a1.FailoverDomain) (sets.String, error) {
+	result := sets.String{}
+	if level == "" {
+		return result, nil
+	}
+
+	failedDomains := sets.String{}
+	for _, cluster := range clusters {
+		// Edge clusters are expected to be intermittently unreachable.
+		if ctlutil.IsClusterReady(&cluster.Status) || ctlutil.IsEdgeCluster(cluster) {
+			continue
+		}
+		domain, err := faultDomainKey(cluster.Spec.FaultDomain, level)
+		if err != nil {
+			return nil, err
+		}
+		if domain != "" {
+			failedDomains.Insert(domain)
+		}
+	}
+	if len(failedDomains) == 0 {
+		return result, nil
+	}
+
+	for _, cluster := range clusters {
+		domain, err := faultDomainKey(cluster.Spec.FaultDomain, level)
+		if err != nil {
+			return nil, err
+		}
+		if failedDomains.Has(domain) {
+			result.Insert(cluster.Name)
+		}
+	}
+	return result, nil
+}
+
+// faultDomainKey returns the key identifying the fault domain at the
+// given level of a cluster with the given fault domain, or an empty
+// string if the fault domain is not known at that level.
+func faultDomainKey(faultDomain *fedv1b1.ClusterFaultDomain, level fedschedulingv1a1.FailoverDomain) (string, error) {
+	var depth int
+	switch level {
+	case fedschedulingv1a1.ProviderFailoverDomain:
+		depth = 1
+	case fedschedulingv1a1.RegionFailoverDomain:
+		depth = 2
+	case fedschedulingv1a1.DatacenterFailoverDomain:
+		depth = 3
+	default:
+		return "", errors.Errorf("Unknown failover domain %q", level)
+	}
+	if faultDomain == nil {
+		return "", nil
+	}
+	// A fault domain is qualified by the domains it is nested in.
+	attributes := []string{faultDomain.Provider, faultDomain.Region, faultDomain.Datacenter}[:depth]
+	if attributes[depth-1] == "" {
+		return "", nil
+	}
+	return strings.Join(attributes, "/"), nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func faultDomainCluster(name string, ready bool, faultDomain *fedv1b1.ClusterFaultDomain) *fedv1b1.KubeFedCluster {
+	status := apiv1.ConditionFalse
+	if ready {
+		status = apiv1.ConditionTrue
+	}
+	return &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       fedv1b1.KubeFedClusterSpec{FaultDomain: faultDomain},
+		Status: fedv1b1.KubeFedClusterStatus{
+			Conditions: []fedv1b1.ClusterCondition{{Type: common.ClusterReady, Status: status}},
+		},
+	}
+}
+
+func TestFailedDomainClusters(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		faultDomainCluster("east-a", false, &fedv1b1.ClusterFaultDomain{Provider: "aws", Region: "us-east-1", Datacenter: "us-east-1a"}),
+		faultDomainCluster("east-b", true, &fedv1b1.ClusterFaultDomain{Provider: "aws", Region: "us-east-1", Datacenter: "us-east-1b"}),
+		faultDomainCluster("west-a", true, &fedv1b1.ClusterFaultDomain{Provider: "aws", Region: "us-west-2", Datacenter: "us-west-2a"}),
+		faultDomainCluster("gcp-east", true, &fedv1b1.ClusterFaultDomain{Provider: "gcp", Region: "us-east-1"}),
+		faultDomainCluster("unlabeled", true, nil),
+	}
+
+	testCases := map[string]struct {
+		level    fedschedulingv1a1.FailoverDomain
+		expected sets.String
+	}{
+		"No failover domain": {
+			expected: sets.NewString(),
+		},
+		"Provider": {
+			level:    fedschedulingv1a1.ProviderFailoverDomain,
+			expected: sets.NewString("east-a", "east-b", "west-a"),
+		},
+		"Region": {
+			level:    fedschedulingv1a1.RegionFailoverDomain,
+			expected: sets.NewString("east-a", "east-b"),
+		},
+		"Datacenter": {
+			level:    fedschedulingv1a1.DatacenterFailoverDomain,
+			expected: sets.NewString("east-a"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := FailedDomainClusters(clusters, tc.level)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !result.Equal(tc.expected) {
+				t.Errorf("Expected clusters %v, got %v", tc.expected.List(), result.List())
+			}
+		})
+	}
+
+	if _, err := FailedDomainClusters(clusters, "Rack"); err == nil {
+		t.Errorf("Expected an error for an unknown failover domain")
+	}
+}
+
+func TestFailedDomainClustersIgnoresEdgeClusters(t *testing.T) {
+	edge := faultDomainCluster("edge", false, &fedv1b1.ClusterFaultDomain{Provider: "on-prem", Region: "store-1"})
+	edge.Spec.ConnectivityProfile = fedv1b1.ConnectivityProfileEdge
+	clusters := []*fedv1b1.KubeFedCluster{
+		edge,
+		faultDomainCluster("store", true, &fedv1b1.ClusterFaultDomain{Provider: "on-prem", Region: "store-1"}),
+	}
+	result, err := FailedDomainClusters(clusters, fedschedulingv1a1.RegionFailoverDomain)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Len() != 0 {
+		t.Errorf("Expected no clusters, got %v", result.List())
+	}
+}
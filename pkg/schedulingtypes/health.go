@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+)
+
+// degradedReplicas returns the ready replicas of the target workload
+// in each of the given clusters in which it is degraded. No more
+// replicas than are ready are scheduled to such a cluster, so that the
+// replicas that are not ready are scheduled to other clusters.
+func degradedReplicas(clusterNames []string, key string, interpreter health.Interpreter,
+	objectGetter func(clusterName string, key string) (interface{}, bool, error)) (map[string]int64, error) {
+
+	readyReplicas := make(map[string]int64)
+	for _, clusterName := range clusterNames {
+		obj, exists, err := objectGetter(clusterName, key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		unstructuredObj := obj.(*unstructured.Unstructured)
+		clusterHealth, err := interpreter.Interpret(unstructuredObj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to interpret the health of %q in cluster %q", key, clusterName)
+		}
+		if clusterHealth != health.Degraded {
+			continue
+		}
+		replicas, _, err := unstructured.NestedInt64(unstructuredObj.Object, "status", "readyReplicas")
+		if err != nil {
+			return nil, errors.Wrap(err, "Error retrieving 'readyReplicas' field")
+		}
+		readyReplicas[clusterName] = replicas
+	}
+	return readyReplicas, nil
+}
+
+// limitReplicas returns the given maximum replicas of clusters further
+// limited by the given limits.
+func limitReplicas(maxReplicas, limits map[string]int64) map[string]int64 {
+	if len(limits) == 0 {
+		return maxReplicas
+	}
+	result := make(map[string]int64, len(maxReplicas)+len(limits))
+	for clusterName, replicas := range maxReplicas {
+		result[clusterName] = replicas
+	}
+	for clusterName, limit := range limits {
+		if replicas, ok := result[clusterName]; !ok || limit < replicas {
+			result[clusterName] = limit
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+)
+
+func TestDegradedReplicas(t *testing.T) {
+	objectGetter := func(clusterName, key string) (interface{}, bool, error) {
+		if clusterName == "missing" {
+			return nil, false, nil
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": clusterName},
+			"status":   map[string]interface{}{"readyReplicas": int64(2)},
+		}}, true, nil
+	}
+	interpreter := health.InterpreterFunc(func(obj *unstructured.Unstructured) (health.Health, error) {
+		if obj.GetName() == "degraded" {
+			return health.Degraded, nil
+		}
+		return health.Healthy, nil
+	})
+	replicas, err := degradedReplicas([]string{"degraded", "healthy", "missing"}, "ns/name", interpreter, objectGetter)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]int64{"degraded": 2}
+	if !reflect.DeepEqual(replicas, expected) {
+		t.Errorf("Expected replicas %v, got %v", expected, replicas)
+	}
+}
+
+func TestLimitReplicas(t *testing.T) {
+	maxReplicas := map[string]int64{"cluster1": 1, "cluster2": 5}
+	result := limitReplicas(maxReplicas, map[string]int64{"cluster2": 2, "cluster3": 3})
+	expected := map[string]int64{"cluster1": 1, "cluster2": 2, "cluster3": 3}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected replicas %v, got %v", expected, result)
+	}
+	if maxReplicas["cluster2"] != 5 {
+		t.Errorf("Expected the given replicas to be unchanged")
+	}
+}
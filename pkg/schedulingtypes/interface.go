@@ -17,7 +17,10 @@ limitations under the License.
 package schedulingtypes
 
 import (
+	"time"
+
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	. "sigs.k8s.io/kubefed/pkg/controller/util"
@@ -40,6 +43,11 @@ type SchedulerEventHandlers struct {
 	KubeFedEventHandler      func(pkgruntime.Object)
 	ClusterEventHandler      func(pkgruntime.Object)
 	ClusterLifecycleHandlers *ClusterLifecycleHandlerFuncs
+	// RecheckHandler requests that the named scheduling preference be
+	// reconciled again after the given delay.
+	RecheckHandler func(qualifiedName QualifiedName, delay time.Duration)
+	// EventRecorder records events on scheduling preferences.
+	EventRecorder record.EventRecorder
 }
 
 type SchedulerFactory func(controllerConfig *ControllerConfig, eventHandlers SchedulerEventHandlers) (Scheduler, error)
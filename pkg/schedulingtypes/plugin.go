@@ -27,12 +27,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+	"sigs.k8s.io/kubefed/pkg/features"
 )
 
 const (
@@ -49,6 +53,11 @@ type Plugin struct {
 
 	typeConfig typeconfig.Interface
 
+	// Interprets the health of the target workloads in member
+	// clusters. Nil if the HealthInterpretation feature is disabled or
+	// the health of the target type cannot be interpreted.
+	healthInterpreter health.Interpreter
+
 	stopChannel chan struct{}
 }
 
@@ -74,6 +83,13 @@ func NewPlugin(controllerConfig *util.ControllerConfig, eventHandlers SchedulerE
 		stopChannel:    make(chan struct{}),
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.HealthInterpretation) {
+		p.healthInterpreter, err = health.ForTypeConfig(typeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	targetNamespace := controllerConfig.TargetNamespace
 	kubeFedEventHandler := eventHandlers.KubeFedEventHandler
 
@@ -132,6 +148,72 @@ func (p *Plugin) FederatedTypeExists(key string) bool {
 	return exist
 }
 
+// Placement returns the placement of the federated resource with the
+// given key, or nil if it does not exist.
+func (p *Plugin) Placement(key string) (*util.GenericPlacement, error) {
+	obj, exist, err := p.federatedStore.GetByKey(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to query store for key %q", key)
+	}
+	if !exist {
+		return nil, nil
+	}
+	return util.UnmarshalGenericPlacement(obj.(*unstructured.Unstructured))
+}
+
+// ScheduledClusters returns the names of the clusters in which the
+// federated resource with the given key is placed with a non-zero
+// number of replicas.
+func (p *Plugin) ScheduledClusters(key string) (sets.String, error) {
+	obj, exist, err := p.federatedStore.GetByKey(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to query store for key %q", key)
+	}
+	if !exist {
+		return sets.String{}, nil
+	}
+	return ScheduledClusters(obj.(*unstructured.Unstructured))
+}
+
+// ScheduledClusters returns the names of the clusters in which the
+// given federated resource is placed with a non-zero number of
+// replicas.
+func ScheduledClusters(fedObject *unstructured.Unstructured) (sets.String, error) {
+	placedClusters, err := util.GetClusterNames(fedObject)
+	if err != nil {
+		return nil, err
+	}
+	overridesMap, err := util.GetOverrides(fedObject)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading cluster overrides for %s %q", fedObject.GetKind(), util.NewQualifiedName(fedObject))
+	}
+	clusterNames := sets.String{}
+	for _, clusterName := range placedClusters {
+		if replicasOverride(overridesMap[clusterName]) == 0 {
+			continue
+		}
+		clusterNames.Insert(clusterName)
+	}
+	return clusterNames, nil
+}
+
+// replicasOverride returns the value of the replicas override in the
+// given cluster overrides, or -1 if no replicas override exists.
+func replicasOverride(clusterOverrides util.ClusterOverrides) int64 {
+	for _, overrideItem := range clusterOverrides {
+		if overrideItem.Path != replicasPath {
+			continue
+		}
+		switch value := overrideItem.Value.(type) {
+		case float64:
+			return int64(value)
+		case int64:
+			return value
+		}
+	}
+	return -1
+}
+
 func (p *Plugin) Reconcile(qualifiedName util.QualifiedName, result map[string]int64) error {
 	fedObject, err := p.federatedTypeClient.Resources(qualifiedName.Namespace).Get(qualifiedName.Name, metav1.GetOptions{})
 	if err != nil && apierrors.IsNotFound(err) {
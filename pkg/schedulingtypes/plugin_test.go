@@ -102,3 +102,47 @@ func TestUpdateOverridesMap(t *testing.T) {
 		})
 	}
 }
+
+func TestReplicasOverride(t *testing.T) {
+	testCases := map[string]struct {
+		clusterOverrides util.ClusterOverrides
+		expected         int64
+	}{
+		"Missing replicas override": {
+			clusterOverrides: util.ClusterOverrides{
+				{
+					Path:  "/ultimate/answer",
+					Value: int64(42),
+				},
+			},
+			expected: -1,
+		},
+		"Replicas override decoded from json": {
+			clusterOverrides: util.ClusterOverrides{
+				{
+					Path:  replicasPath,
+					Value: float64(3),
+				},
+			},
+			expected: 3,
+		},
+		"Zero replicas override": {
+			clusterOverrides: util.ClusterOverrides{
+				{
+					Path:  replicasPath,
+					Value: int64(0),
+				},
+			},
+			expected: 0,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			actual := replicasOverride(tc.clusterOverrides)
+			if tc.expected != actual {
+				t.Fatalf("Expected %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -29,15 +29,21 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
 	"sigs.k8s.io/kubefed/pkg/controller/util/planner"
 	"sigs.k8s.io/kubefed/pkg/controller/util/podanalyzer"
+	"sigs.k8s.io/kubefed/pkg/features"
 )
 
 const (
@@ -62,6 +68,20 @@ type ReplicaScheduler struct {
 
 	client      genericclient.Client
 	podInformer ctlutil.FederatedInformer
+
+	capacity *capacityTracker
+	weights  *weightTuner
+
+	// antiAffinity records the RSPs to reschedule when a workload
+	// named by their cluster anti-affinity changes.
+	antiAffinity *antiAffinityIndex
+
+	// The informer used to source the ClusterAllowlists that limit
+	// the clusters replicas are scheduled to. Will only be initialized
+	// if the ClusterAllowlists feature is enabled.
+	clusterAllowlistStore      cache.Store
+	clusterAllowlistController cache.Controller
+	stopChannel                chan struct{}
 }
 
 func NewReplicaScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandlers SchedulerEventHandlers) (Scheduler, error) {
@@ -71,6 +91,10 @@ func NewReplicaScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandle
 		controllerConfig: controllerConfig,
 		eventHandlers:    eventHandlers,
 		client:           client,
+		capacity:         newCapacityTracker(),
+		weights:          newWeightTuner(),
+		antiAffinity:     newAntiAffinityIndex(),
+		stopChannel:      make(chan struct{}),
 	}
 
 	// TODO: Update this to use a typed client from single target informer.
@@ -89,6 +113,22 @@ func NewReplicaScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandle
 		return nil, err
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.ClusterAllowlists) {
+		// A change to an allowlist is observed when the RSPs are next
+		// reconciled. Until then the sync controller prevents
+		// propagation to clusters that are no longer allowed.
+		scheduler.clusterAllowlistStore, scheduler.clusterAllowlistController, err = ctlutil.NewGenericInformer(
+			controllerConfig.KubeConfig,
+			controllerConfig.KubeFedNamespace,
+			&fedv1b1.ClusterAllowlist{},
+			ctlutil.NoResyncPeriod,
+			func(pkgruntime.Object) {},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return scheduler, nil
 }
 
@@ -100,7 +140,7 @@ func (s *ReplicaScheduler) StartPlugin(typeConfig typeconfig.Interface) error {
 	kind := typeConfig.GetFederatedType().Kind
 	// TODO(marun) Return an error if the kind is not supported
 
-	plugin, err := NewPlugin(s.controllerConfig, s.eventHandlers, typeConfig)
+	plugin, err := NewPlugin(s.controllerConfig, s.pluginEventHandlers(kind), typeConfig)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to initialize replica scheduling plugin for %q", kind)
 	}
@@ -111,6 +151,23 @@ func (s *ReplicaScheduler) StartPlugin(typeConfig typeconfig.Interface) error {
 	return nil
 }
 
+// pluginEventHandlers returns the event handlers for the plugin of the
+// given federated kind. A change to a federated workload also
+// reschedules the RSPs whose cluster anti-affinity names it.
+func (s *ReplicaScheduler) pluginEventHandlers(kind string) SchedulerEventHandlers {
+	eventHandlers := s.eventHandlers
+	eventHandlers.KubeFedEventHandler = func(obj pkgruntime.Object) {
+		s.eventHandlers.KubeFedEventHandler(obj)
+		if s.eventHandlers.RecheckHandler == nil {
+			return
+		}
+		for _, qualifiedName := range s.antiAffinity.dependentsOf(kind, ctlutil.NewQualifiedName(obj)) {
+			s.eventHandlers.RecheckHandler(qualifiedName, 0)
+		}
+	}
+	return eventHandlers
+}
+
 func (s *ReplicaScheduler) StopPlugin(kind string) {
 	plugin, ok := s.plugins.Get(kind)
 	if !ok {
@@ -127,6 +184,9 @@ func (s *ReplicaScheduler) ObjectType() pkgruntime.Object {
 
 func (s *ReplicaScheduler) Start() {
 	s.podInformer.Start()
+	if s.clusterAllowlistController != nil {
+		go s.clusterAllowlistController.Run(s.stopChannel)
+	}
 }
 
 func (s *ReplicaScheduler) HasSynced() bool {
@@ -140,6 +200,10 @@ func (s *ReplicaScheduler) HasSynced() bool {
 		klog.V(2).Infof("Cluster list not synced")
 		return false
 	}
+	if s.clusterAllowlistController != nil && !s.clusterAllowlistController.HasSynced() {
+		klog.V(2).Infof("ClusterAllowlist informer not synced")
+		return false
+	}
 	clusters, err := s.podInformer.GetReadyClusters()
 	if err != nil {
 		runtime.HandleError(errors.Wrap(err, "Failed to get ready clusters"))
@@ -154,6 +218,7 @@ func (s *ReplicaScheduler) Stop() {
 	}
 	s.plugins.DeleteAll()
 	s.podInformer.Stop()
+	close(s.stopChannel)
 }
 
 func (s *ReplicaScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctlutil.QualifiedName) ctlutil.ReconciliationStatus {
@@ -162,6 +227,7 @@ func (s *ReplicaScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctluti
 		runtime.HandleError(errors.Errorf("Incorrect runtime object for RSP: %v", rsp))
 		return ctlutil.StatusError
 	}
+	s.antiAffinity.update(rsp, qualifiedName)
 
 	clusterNames, err := s.clusterNames()
 	if err != nil {
@@ -186,10 +252,21 @@ func (s *ReplicaScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctluti
 
 	if !plugin.(*Plugin).FederatedTypeExists(qualifiedName.String()) {
 		// target FederatedType does not exist, nothing to do
+		s.capacity.forget(qualifiedName.String())
+		s.weights.forget(qualifiedName.String())
 		return ctlutil.StatusAllOK
 	}
 
 	key := qualifiedName.String()
+	rsp, recheckDelay, err := scheduledPreferences(rsp, time.Now())
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to evaluate the schedule windows of RSP named %q", key))
+		return ctlutil.StatusError
+	}
+	if recheckDelay > 0 && s.eventHandlers.RecheckHandler != nil {
+		s.eventHandlers.RecheckHandler(qualifiedName, recheckDelay)
+	}
+
 	result, err := s.GetSchedulingResult(rsp, qualifiedName, clusterNames)
 	if err != nil {
 		runtime.HandleError(errors.Wrapf(err, "Failed to compute the schedule information while reconciling RSP named %q", key))
@@ -205,67 +282,349 @@ func (s *ReplicaScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctluti
 	return ctlutil.StatusAllOK
 }
 
-// The list of clusters could come from any target informer
+// The list of clusters could come from any target informer.
 func (s *ReplicaScheduler) clusterNames() ([]string, error) {
-	clusters, err := s.podInformer.GetReadyClusters()
+	clusters, err := s.podInformer.GetClusters()
 	if err != nil {
 		return nil, err
 	}
+	return SchedulingClusterNames(clusters), nil
+}
+
+// SchedulingClusterNames returns the names of the given clusters that
+// replicas are scheduled among. Edge clusters that are not ready are
+// included so that their replicas are not scheduled to other clusters
+// while they are unreachable.
+func SchedulingClusterNames(clusters []*fedv1b1.KubeFedCluster) []string {
 	clusterNames := []string{}
 	for _, cluster := range clusters {
-		clusterNames = append(clusterNames, cluster.Name)
+		if ctlutil.IsClusterReady(&cluster.Status) || ctlutil.IsEdgeCluster(cluster) {
+			clusterNames = append(clusterNames, cluster.Name)
+		}
 	}
+	return clusterNames
+}
 
-	return clusterNames, nil
+// ClusterCosts returns the cost weights of the given clusters keyed by
+// cluster name.
+func ClusterCosts(clusters []*fedv1b1.KubeFedCluster) map[string]int64 {
+	clusterCosts := make(map[string]int64, len(clusters))
+	for _, cluster := range clusters {
+		clusterCosts[cluster.Name] = cluster.Spec.CostWeight
+	}
+	return clusterCosts
+}
+
+// SchedulingInputs is the state of the federation against which the
+// replicas of an RSP are scheduled.
+type SchedulingInputs struct {
+	// Clusters are the member clusters of the federation.
+	Clusters []*fedv1b1.KubeFedCluster
+	// ClusterAllowlists limit the clusters replicas are scheduled
+	// to. Nil if the ClusterAllowlists feature is disabled.
+	ClusterAllowlists []*fedv1b1.ClusterAllowlist
+	// AntiAffinityClusters are the clusters in which the workloads
+	// named by the cluster anti-affinity of the RSP are scheduled.
+	AntiAffinityClusters sets.String
+	// Placement is the placement of the target federated resource of
+	// the RSP, or nil if it does not exist.
+	Placement *ctlutil.GenericPlacement
+	// HealthInterpreter interprets the health of the target workload
+	// in member clusters. Nil if the HealthInterpretation feature is
+	// disabled or the health of the target type cannot be interpreted.
+	HealthInterpreter health.Interpreter
+
+	ObjectGetter func(clusterName string, key string) (interface{}, bool, error)
+	PodsGetter   func(clusterName string, obj *unstructured.Unstructured) (*corev1.PodList, error)
 }
 
 func (s *ReplicaScheduler) GetSchedulingResult(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName, clusterNames []string) (map[string]int64, error) {
-	key := qualifiedName.String()
+	inputs, err := s.schedulingInputs(rsp, qualifiedName)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.computeSchedule(rsp, qualifiedName, clusterNames, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return result.Replicas, nil
+}
 
-	objectGetter := func(clusterName, key string) (interface{}, bool, error) {
-		plugin, ok := s.plugins.Get(rsp.Spec.TargetKind)
-		if !ok {
-			return nil, false, nil
-		}
-		return plugin.(*Plugin).targetInformer.GetTargetStore().GetByKey(clusterName, key)
+// schedulingInputs returns the state of the federation observed by the
+// informers of the scheduler for the given RSP.
+func (s *ReplicaScheduler) schedulingInputs(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName) (*SchedulingInputs, error) {
+	clusters, err := s.podInformer.GetClusters()
+	if err != nil {
+		return nil, err
 	}
-	podsGetter := func(clusterName string, unstructuredObj *unstructured.Unstructured) (*corev1.PodList, error) {
-		client, err := s.podInformer.GetClientForCluster(clusterName)
+	antiAffinityClusters, err := s.antiAffinityClusters(rsp, qualifiedName.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := &SchedulingInputs{
+		Clusters:             clusters,
+		ClusterAllowlists:    s.clusterAllowlists(),
+		AntiAffinityClusters: antiAffinityClusters,
+		ObjectGetter: func(clusterName, key string) (interface{}, bool, error) {
+			plugin, ok := s.plugins.Get(rsp.Spec.TargetKind)
+			if !ok {
+				return nil, false, nil
+			}
+			return plugin.(*Plugin).targetInformer.GetTargetStore().GetByKey(clusterName, key)
+		},
+		PodsGetter: func(clusterName string, unstructuredObj *unstructured.Unstructured) (*corev1.PodList, error) {
+			client, err := s.podInformer.GetClientForCluster(clusterName)
+			if err != nil {
+				return nil, err
+			}
+			return ListWorkloadPods(client, unstructuredObj)
+		},
+	}
+	if plugin, ok := s.plugins.Get(rsp.Spec.TargetKind); ok {
+		inputs.Placement, err = plugin.(*Plugin).Placement(qualifiedName.String())
 		if err != nil {
 			return nil, err
 		}
-		selectorLabels, ok, err := unstructured.NestedStringMap(unstructuredObj.Object, "spec", "selector", "matchLabels")
-		if !ok {
-			return nil, errors.New("missing selector on object")
-		}
-		if err != nil {
-			return nil, errors.Wrap(err, "error retrieving selector from object")
-		}
+		inputs.HealthInterpreter = plugin.(*Plugin).healthInterpreter
+	}
+	return inputs, nil
+}
 
-		podList := &corev1.PodList{}
-		err = client.List(context.Background(), podList, unstructuredObj.GetNamespace(), crclient.MatchingLabels(selectorLabels))
+// computeSchedule computes the replicas to schedule to each of the
+// given clusters for an RSP against the given state of the federation.
+// Both reconciliation and simulation schedule replicas with this
+// method so that a simulation reflects what reconciliation would do.
+func (s *ReplicaScheduler) computeSchedule(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName, clusterNames []string, inputs *SchedulingInputs) (*ScheduleSimulation, error) {
+	key := qualifiedName.String()
+	clusters := inputs.Clusters
+
+	if inputs.ClusterAllowlists != nil {
+		clusterNames = allowedClusterNames(inputs.ClusterAllowlists, qualifiedName.Namespace, clusterNames)
+	}
+	clusterNames = WithoutClusters(clusterNames, inputs.AntiAffinityClusters)
+	clusterNames, rsp, err := clusterAffinity(rsp, inputs.Placement, clusterNames, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	currentReplicasPerCluster, estimatedCapacity, failingPercentage, err := clustersReplicaState(clusterNames, key, inputs.ObjectGetter, inputs.PodsGetter)
+	if err != nil {
+		return nil, err
+	}
+	window, err := stabilizationWindow(rsp)
+	if err != nil {
+		return nil, err
+	}
+	estimatedCapacity = s.capacity.stabilize(key, estimatedCapacity, window, time.Now())
+	rsp = s.tuneWeights(rsp, qualifiedName, clusterNames, failingPercentage)
+
+	// Clusters sharing the fault domain of a failed cluster are held
+	// at their current replicas like cordoned clusters.
+	failedDomainClusters, err := FailedDomainClusters(clusters, rsp.Spec.FailoverDomain)
+	if err != nil {
+		return nil, err
+	}
+	if failedDomainClusters.Len() > 0 {
+		klog.V(2).Infof("Replicas of RSP %q will not be increased in clusters sharing the fault domain of a failed cluster: %v", key, failedDomainClusters.List())
+	}
+	maxReplicas, err := cordonedReplicas(clusterNames, CordonedClusters(clusters).Union(failedDomainClusters), key, inputs.ObjectGetter)
+	if err != nil {
+		return nil, err
+	}
+	// Replicas of a workload that is degraded in a cluster fail over
+	// to other clusters.
+	if inputs.HealthInterpreter != nil {
+		readyReplicas, err := degradedReplicas(clusterNames, key, inputs.HealthInterpreter, inputs.ObjectGetter)
 		if err != nil {
 			return nil, err
 		}
-		return podList, nil
+		if len(readyReplicas) > 0 {
+			klog.V(2).Infof("Replicas of RSP %q will be limited to the ready replicas of clusters in which the workload is degraded: %v", key, readyReplicas)
+		}
+		maxReplicas = limitReplicas(maxReplicas, readyReplicas)
+	}
+	rsp = cordonedPreferences(rsp, maxReplicas, estimatedCapacity)
+
+	// The RSP may still be the cached object, which must not be
+	// modified by the defaulting of its preferences.
+	replicas, err := scheduleReplicas(rsp.DeepCopy(), key, clusterNames, ClusterCosts(clusters), currentReplicasPerCluster, estimatedCapacity)
+	if err != nil {
+		return nil, err
+	}
+	return &ScheduleSimulation{
+		CurrentReplicas:   currentReplicasPerCluster,
+		EstimatedCapacity: estimatedCapacity,
+		Replicas:          replicas,
+	}, nil
+}
+
+// tuneWeights returns the given RSP with the weights of clusters in
+// which pods of the target workload are failing reduced, if weight
+// auto-tuning is enabled for the RSP. An event is recorded on the RSP
+// for every change to the reduction of a cluster, and the RSP is
+// rechecked once the reductions would be lifted.
+func (s *ReplicaScheduler) tuneWeights(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName, clusterNames []string, failingPercentage map[string]int64) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	key := qualifiedName.String()
+	tuning := rsp.Spec.WeightAutoTuning
+	if tuning == nil {
+		s.weights.forget(key)
+		return rsp
+	}
+
+	recovery := weightRecoveryPeriod(tuning)
+	reductions, adjustments := s.weights.tune(key, failingPercentage, maxWeightReduction(tuning), recovery, time.Now())
+	for _, adjustment := range adjustments {
+		klog.V(2).Infof("Weight reduction of cluster %q for RSP %q changed from %d%% to %d%%", adjustment.clusterName, key, adjustment.previous, adjustment.current)
+		if s.eventHandlers.EventRecorder == nil {
+			continue
+		}
+		if adjustment.current == 0 {
+			s.eventHandlers.EventRecorder.Eventf(rsp, corev1.EventTypeNormal, "ClusterWeightRestored",
+				"Weight of cluster %q is no longer reduced since its pods stopped failing", adjustment.clusterName)
+			continue
+		}
+		s.eventHandlers.EventRecorder.Eventf(rsp, corev1.EventTypeWarning, "ClusterWeightReduced",
+			"Weight of cluster %q reduced by %d%% since pods are unschedulable or crash looping", adjustment.clusterName, adjustment.current)
+	}
+	if len(reductions) > 0 && s.eventHandlers.RecheckHandler != nil {
+		s.eventHandlers.RecheckHandler(qualifiedName, recovery)
+	}
+	return tunedPreferences(rsp, clusterNames, reductions)
+}
+
+// ScheduleSimulation is the result of simulating the scheduling of
+// an RSP against the current state of member clusters.
+type ScheduleSimulation struct {
+	CurrentReplicas   map[string]int64
+	EstimatedCapacity map[string]int64
+	Replicas          map[string]int64
+}
+
+// SimulateSchedule computes the replicas that would be scheduled to
+// each of the given clusters for an RSP against the given state of the
+// federation without updating any resources. Since the simulation has
+// no history, capacity estimates and weight reductions only reflect
+// the current state of member clusters.
+func SimulateSchedule(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName, clusterNames []string, inputs *SchedulingInputs) (*ScheduleSimulation, error) {
+	rsp, _, err := scheduledPreferences(rsp, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	scheduler := &ReplicaScheduler{
+		capacity: newCapacityTracker(),
+		weights:  newWeightTuner(),
+	}
+	return scheduler.computeSchedule(rsp, qualifiedName, clusterNames, inputs)
+}
+
+// ListWorkloadPods lists the pods matching the selector of the given
+// workload (e.g. Deployment or ReplicaSet).
+func ListWorkloadPods(client genericclient.Client, unstructuredObj *unstructured.Unstructured) (*corev1.PodList, error) {
+	selectorLabels, ok, err := unstructured.NestedStringMap(unstructuredObj.Object, "spec", "selector", "matchLabels")
+	if !ok {
+		return nil, errors.New("missing selector on object")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving selector from object")
 	}
 
-	currentReplicasPerCluster, estimatedCapacity, err := clustersReplicaState(clusterNames, key, objectGetter, podsGetter)
+	podList := &corev1.PodList{}
+	err = client.List(context.Background(), podList, unstructuredObj.GetNamespace(), crclient.MatchingLabels(selectorLabels))
 	if err != nil {
 		return nil, err
 	}
+	return podList, nil
+}
 
+func scheduleReplicas(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, key string, clusterNames []string, clusterCosts map[string]int64, currentReplicasPerCluster map[string]int64, estimatedCapacity map[string]int64) (map[string]int64, error) {
 	// TODO: Move this to API defaulting logic
 	if len(rsp.Spec.Clusters) == 0 {
-		rsp.Spec.Clusters = map[string]fedschedulingv1a1.ClusterPreferences{
-			"*": {Weight: 1},
-		}
+		rsp.Spec.Clusters = defaultClusterPreferences()
 	}
 
-	plnr := planner.NewPlanner(rsp)
+	plnr := planner.NewPlanner(rsp, clusterCosts)
 	return schedule(plnr, key, clusterNames, currentReplicasPerCluster, estimatedCapacity)
 }
 
+// defaultClusterPreferences returns the cluster preferences of an RSP
+// that does not specify any, distributing replicas evenly among all
+// clusters.
+func defaultClusterPreferences() map[string]fedschedulingv1a1.ClusterPreferences {
+	return map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1},
+	}
+}
+
+// antiAffinityClusters returns the names of the clusters in which the
+// workloads named by the cluster anti-affinity of the RSP are
+// scheduled.
+func (s *ReplicaScheduler) antiAffinityClusters(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, namespace string) (sets.String, error) {
+	excludedClusters := sets.String{}
+	for _, term := range rsp.Spec.ClusterAntiAffinity {
+		plugin, ok := s.plugins.Get(term.TargetKind)
+		if !ok {
+			return nil, errors.Errorf("Cluster anti-affinity target kind %q is not enabled for scheduling", term.TargetKind)
+		}
+		key := ctlutil.QualifiedName{Namespace: namespace, Name: term.Name}.String()
+		scheduledClusters, err := plugin.(*Plugin).ScheduledClusters(key)
+		if err != nil {
+			return nil, err
+		}
+		excludedClusters = excludedClusters.Union(scheduledClusters)
+	}
+	return excludedClusters, nil
+}
+
+// clusterAllowlists returns the ClusterAllowlists that limit the
+// clusters replicas are scheduled to, or nil if the ClusterAllowlists
+// feature is disabled.
+func (s *ReplicaScheduler) clusterAllowlists() []*fedv1b1.ClusterAllowlist {
+	if s.clusterAllowlistStore == nil {
+		return nil
+	}
+	allowlists := []*fedv1b1.ClusterAllowlist{}
+	for _, obj := range s.clusterAllowlistStore.List() {
+		allowlists = append(allowlists, obj.(*fedv1b1.ClusterAllowlist))
+	}
+	return allowlists
+}
+
+// allowedClusterNames returns the given cluster names excluding the
+// clusters that are not listed by a ClusterAllowlist for the given
+// namespace, since the sync controller would not propagate replicas to
+// them.
+func allowedClusterNames(allowlists []*fedv1b1.ClusterAllowlist, namespace string, clusterNames []string) []string {
+	allowedClusters := ctlutil.AllowedClusters(allowlists, namespace)
+	return WithoutClusters(clusterNames, sets.NewString(clusterNames...).Difference(allowedClusters))
+}
+
+// WithoutClusters returns the given cluster names excluding those in
+// the excluded set.
+func WithoutClusters(clusterNames []string, excludedClusters sets.String) []string {
+	filteredNames := []string{}
+	for _, clusterName := range clusterNames {
+		if !excludedClusters.Has(clusterName) {
+			filteredNames = append(filteredNames, clusterName)
+		}
+	}
+	return filteredNames
+}
+
+// stabilizationWindow returns the period for which estimated cluster
+// capacity should continue to be honored for the given RSP. A window
+// of 0 disables stabilization.
+func stabilizationWindow(rsp *fedschedulingv1a1.ReplicaSchedulingPreference) (time.Duration, error) {
+	if rsp.Spec.StabilizationWindowSeconds == nil {
+		return DefaultStabilizationWindow, nil
+	}
+	seconds := *rsp.Spec.StabilizationWindowSeconds
+	if seconds < 0 {
+		return 0, errors.Errorf("spec.stabilizationWindowSeconds must be greater than or equal to 0, got %d", seconds)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
 func schedule(planner *planner.Planner, key string, clusterNames []string, currentReplicasPerCluster map[string]int64, estimatedCapacity map[string]int64) (map[string]int64, error) {
 	scheduleResult, overflow, err := planner.Plan(clusterNames, currentReplicasPerCluster, estimatedCapacity, key)
 	if err != nil {
@@ -307,19 +666,21 @@ func schedule(planner *planner.Planner, key string, clusterNames []string, curre
 }
 
 // clustersReplicaState returns information about the scheduling state of the pods running in the federated clusters.
+// The percentage of pods that are unschedulable or crash looping is only reported for clusters with failing pods.
 func clustersReplicaState(
 	clusterNames []string,
 	key string,
 	objectGetter func(clusterName string, key string) (interface{}, bool, error),
-	podsGetter func(clusterName string, obj *unstructured.Unstructured) (*corev1.PodList, error)) (currentReplicasPerCluster map[string]int64, estimatedCapacity map[string]int64, err error) {
+	podsGetter func(clusterName string, obj *unstructured.Unstructured) (*corev1.PodList, error)) (currentReplicasPerCluster map[string]int64, estimatedCapacity map[string]int64, failingPercentage map[string]int64, err error) {
 
 	currentReplicasPerCluster = make(map[string]int64)
 	estimatedCapacity = make(map[string]int64)
+	failingPercentage = make(map[string]int64)
 
 	for _, clusterName := range clusterNames {
 		obj, exists, err := objectGetter(clusterName, key)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 		if !exists {
 			continue
@@ -328,14 +689,14 @@ func clustersReplicaState(
 		unstructuredObj := obj.(*unstructured.Unstructured)
 		replicas, ok, err := unstructured.NestedInt64(unstructuredObj.Object, "spec", "replicas")
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "Error retrieving 'replicas' field")
+			return nil, nil, nil, errors.Wrap(err, "Error retrieving 'replicas' field")
 		}
 		if !ok {
 			replicas = int64(0)
 		}
-		readyReplicas, ok, err := unstructured.NestedInt64(unstructuredObj.Object, "status", "readyreplicas")
+		readyReplicas, ok, err := unstructured.NestedInt64(unstructuredObj.Object, "status", "readyReplicas")
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "Error retrieving 'readyreplicas' field")
+			return nil, nil, nil, errors.Wrap(err, "Error retrieving 'readyReplicas' field")
 		}
 		if !ok {
 			readyReplicas = int64(0)
@@ -347,7 +708,7 @@ func clustersReplicaState(
 			currentReplicasPerCluster[clusterName] = int64(0)
 			podList, err := podsGetter(clusterName, unstructuredObj)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 
 			podStatus := podanalyzer.AnalyzePods(podList, time.Now())
@@ -356,7 +717,11 @@ func clustersReplicaState(
 			if unschedulable > 0 {
 				estimatedCapacity[clusterName] = replicas - unschedulable
 			}
+			failing := podStatus.Unschedulable + podStatus.CrashLooping
+			if failing > 0 {
+				failingPercentage[clusterName] = int64(failing * 100 / podStatus.Total)
+			}
 		}
 	}
-	return currentReplicasPerCluster, estimatedCapacity, nil
+	return currentReplicasPerCluster, estimatedCapacity, failingPercentage, nil
 }
@@ -0,0 +1,296 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/health"
+)
+
+func TestSimulateSchedule(t *testing.T) {
+	newDeployment := func(replicas, readyReplicas int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": replicas},
+			"status": map[string]interface{}{"readyReplicas": readyReplicas},
+		}}
+	}
+	deployments := map[string]*unstructured.Unstructured{
+		"cluster1": newDeployment(6, 6),
+		"cluster2": newDeployment(5, 2),
+	}
+	objectGetter := func(clusterName, key string) (interface{}, bool, error) {
+		deployment, ok := deployments[clusterName]
+		if !ok {
+			return nil, false, nil
+		}
+		return deployment, true, nil
+	}
+
+	// Two pods of the deployment in cluster2 are running and three
+	// have been unschedulable for longer than the threshold.
+	runningPod := corev1.Pod{Status: corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+	}}
+	unschedulablePod := corev1.Pod{Status: corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{{
+			Type:               corev1.PodScheduled,
+			Status:             corev1.ConditionFalse,
+			Reason:             corev1.PodReasonUnschedulable,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+		}},
+	}}
+	podsGetter := func(clusterName string, obj *unstructured.Unstructured) (*corev1.PodList, error) {
+		if clusterName != "cluster2" {
+			return &corev1.PodList{}, nil
+		}
+		return &corev1.PodList{Items: []corev1.Pod{runningPod, runningPod, unschedulablePod, unschedulablePod, unschedulablePod}}, nil
+	}
+
+	rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			TargetKind:    "FederatedDeployment",
+			TotalReplicas: 10,
+		},
+	}
+	qualifiedName := ctlutil.QualifiedName{Namespace: "ns", Name: "web"}
+	inputs := &SchedulingInputs{
+		ObjectGetter: objectGetter,
+		PodsGetter:   podsGetter,
+	}
+	simulation, err := SimulateSchedule(rsp, qualifiedName, []string{"cluster1", "cluster2"}, inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedCurrent := map[string]int64{"cluster1": 6, "cluster2": 2}
+	if !reflect.DeepEqual(simulation.CurrentReplicas, expectedCurrent) {
+		t.Errorf("Expected current replicas %v, got %v", expectedCurrent, simulation.CurrentReplicas)
+	}
+	expectedCapacity := map[string]int64{"cluster2": 2}
+	if !reflect.DeepEqual(simulation.EstimatedCapacity, expectedCapacity) {
+		t.Errorf("Expected capacity %v, got %v", expectedCapacity, simulation.EstimatedCapacity)
+	}
+	// The replicas cluster2 lacks the capacity for are scheduled to
+	// cluster1.
+	expectedReplicas := map[string]int64{"cluster1": 8, "cluster2": 2}
+	if !reflect.DeepEqual(simulation.Replicas, expectedReplicas) {
+		t.Errorf("Expected replicas %v, got %v", expectedReplicas, simulation.Replicas)
+	}
+	if len(rsp.Spec.Clusters) != 0 {
+		t.Errorf("Expected the simulated RSP to be unchanged, got clusters %v", rsp.Spec.Clusters)
+	}
+}
+
+// simulatedDeployment returns a deployment as observed in a member
+// cluster.
+func simulatedDeployment(replicas, readyReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"replicas": replicas},
+		"status": map[string]interface{}{"readyReplicas": readyReplicas},
+	}}
+}
+
+// simulationInputs returns the inputs of a simulation against the
+// given clusters in which the given deployments exist. No pods are
+// listed for the deployments.
+func simulationInputs(clusters []*fedv1b1.KubeFedCluster, deployments map[string]*unstructured.Unstructured) *SchedulingInputs {
+	return &SchedulingInputs{
+		Clusters: clusters,
+		ObjectGetter: func(clusterName, key string) (interface{}, bool, error) {
+			deployment, ok := deployments[clusterName]
+			if !ok {
+				return nil, false, nil
+			}
+			return deployment, true, nil
+		},
+		PodsGetter: func(clusterName string, obj *unstructured.Unstructured) (*corev1.PodList, error) {
+			return &corev1.PodList{}, nil
+		},
+	}
+}
+
+func simulatedRSP(totalReplicas int32) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	return &fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			TargetKind:    "FederatedDeployment",
+			TotalReplicas: totalReplicas,
+			Rebalance:     true,
+		},
+	}
+}
+
+// checkSimulatedReplicas verifies the replicas of a simulation, with
+// clusters missing from either map having no replicas.
+func checkSimulatedReplicas(t *testing.T, expected, actual map[string]int64) {
+	t.Helper()
+	clusterNames := sets.StringKeySet(expected).Union(sets.StringKeySet(actual))
+	for _, clusterName := range clusterNames.List() {
+		if expected[clusterName] != actual[clusterName] {
+			t.Errorf("Expected replicas %v, got %v", expected, actual)
+			return
+		}
+	}
+}
+
+func TestSimulateScheduleAppliesClusterAffinity(t *testing.T) {
+	inputs := simulationInputs(affinityTestClusters(), nil)
+	inputs.Placement = &ctlutil.GenericPlacement{}
+	inputs.Placement.Spec.Placement.RequiredClusterSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: "ns", Name: "web"}
+	simulation, err := SimulateSchedule(simulatedRSP(4), qualifiedName, []string{"eu-1", "eu-2", "us-1"}, inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkSimulatedReplicas(t, map[string]int64{"eu-1": 2, "eu-2": 2}, simulation.Replicas)
+}
+
+func TestSimulateScheduleHoldsCordonedClusters(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		faultDomainCluster("cluster1", true, nil),
+		faultDomainCluster("cluster2", true, nil),
+	}
+	clusters[1].Spec.Unschedulable = true
+	inputs := simulationInputs(clusters, map[string]*unstructured.Unstructured{
+		"cluster2": simulatedDeployment(1, 1),
+	})
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: "ns", Name: "web"}
+	simulation, err := SimulateSchedule(simulatedRSP(6), qualifiedName, []string{"cluster1", "cluster2"}, inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkSimulatedReplicas(t, map[string]int64{"cluster1": 5, "cluster2": 1}, simulation.Replicas)
+}
+
+func TestSimulateScheduleHoldsClustersOfFailedDomains(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		faultDomainCluster("east-a", false, &fedv1b1.ClusterFaultDomain{Provider: "aws", Region: "us-east-1"}),
+		faultDomainCluster("east-b", true, &fedv1b1.ClusterFaultDomain{Provider: "aws", Region: "us-east-1"}),
+		faultDomainCluster("west-a", true, &fedv1b1.ClusterFaultDomain{Provider: "aws", Region: "us-west-2"}),
+	}
+	inputs := simulationInputs(clusters, map[string]*unstructured.Unstructured{
+		"east-b": simulatedDeployment(1, 1),
+	})
+	rsp := simulatedRSP(6)
+	rsp.Spec.FailoverDomain = fedschedulingv1a1.RegionFailoverDomain
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: "ns", Name: "web"}
+	simulation, err := SimulateSchedule(rsp, qualifiedName, SchedulingClusterNames(clusters), inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkSimulatedReplicas(t, map[string]int64{"east-b": 1, "west-a": 5}, simulation.Replicas)
+}
+
+func TestSimulateScheduleTunesWeights(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		faultDomainCluster("cluster1", true, nil),
+		faultDomainCluster("cluster2", true, nil),
+	}
+	inputs := simulationInputs(clusters, map[string]*unstructured.Unstructured{
+		"cluster1": simulatedDeployment(4, 4),
+		"cluster2": simulatedDeployment(4, 2),
+	})
+	// Half of the pods of the deployment in cluster2 are crash
+	// looping.
+	runningPod := corev1.Pod{Status: corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+	}}
+	crashLoopingPod := corev1.Pod{Status: corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		ContainerStatuses: []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}},
+	}}
+	inputs.PodsGetter = func(clusterName string, obj *unstructured.Unstructured) (*corev1.PodList, error) {
+		return &corev1.PodList{Items: []corev1.Pod{runningPod, runningPod, crashLoopingPod, crashLoopingPod}}, nil
+	}
+	rsp := simulatedRSP(6)
+	rsp.Spec.WeightAutoTuning = &fedschedulingv1a1.WeightAutoTuning{}
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: "ns", Name: "web"}
+	simulation, err := SimulateSchedule(rsp, qualifiedName, []string{"cluster1", "cluster2"}, inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The weight of cluster2 is reduced by half.
+	checkSimulatedReplicas(t, map[string]int64{"cluster1": 4, "cluster2": 2}, simulation.Replicas)
+}
+
+func TestSimulateScheduleLimitsDegradedClusters(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		faultDomainCluster("cluster1", true, nil),
+		faultDomainCluster("cluster2", true, nil),
+	}
+	inputs := simulationInputs(clusters, map[string]*unstructured.Unstructured{
+		"cluster2": simulatedDeployment(3, 1),
+	})
+	inputs.HealthInterpreter = health.InterpreterFunc(func(obj *unstructured.Unstructured) (health.Health, error) {
+		replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		readyReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		if readyReplicas < replicas {
+			return health.Degraded, nil
+		}
+		return health.Healthy, nil
+	})
+
+	qualifiedName := ctlutil.QualifiedName{Namespace: "ns", Name: "web"}
+	simulation, err := SimulateSchedule(simulatedRSP(6), qualifiedName, []string{"cluster1", "cluster2"}, inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkSimulatedReplicas(t, map[string]int64{"cluster1": 5, "cluster2": 1}, simulation.Replicas)
+}
+
+func TestSimulateScheduleIncludesEdgeClusters(t *testing.T) {
+	edge := faultDomainCluster("edge", false, nil)
+	edge.Spec.ConnectivityProfile = fedv1b1.ConnectivityProfileEdge
+	clusters := []*fedv1b1.KubeFedCluster{
+		faultDomainCluster("cluster1", true, nil),
+		faultDomainCluster("down", false, nil),
+		edge,
+	}
+	inputs := simulationInputs(clusters, nil)
+
+	clusterNames := SchedulingClusterNames(clusters)
+	expectedNames := []string{"cluster1", "edge"}
+	if !reflect.DeepEqual(clusterNames, expectedNames) {
+		t.Fatalf("Expected cluster names %v, got %v", expectedNames, clusterNames)
+	}
+	qualifiedName := ctlutil.QualifiedName{Namespace: "ns", Name: "web"}
+	simulation, err := SimulateSchedule(simulatedRSP(4), qualifiedName, clusterNames, inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The edge cluster keeps its share of replicas while unreachable.
+	checkSimulatedReplicas(t, map[string]int64{"cluster1": 2, "edge": 2}, simulation.Replicas)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+const (
+	// scheduleRampInterval is the interval at which an RSP is
+	// rescheduled while the preferences of a schedule window ramp.
+	scheduleRampInterval = time.Minute
+
+	// maxScheduleRecheckDelay bounds the delay until an RSP with
+	// schedule windows is rescheduled so that a change to the offset
+	// of a time zone is not missed.
+	maxScheduleRecheckDelay = time.Hour
+)
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// scheduleInterval is an occurrence of a schedule window.
+type scheduleInterval struct {
+	start time.Time
+	end   time.Time
+}
+
+// scheduledPreferences returns a copy of the given RSP whose cluster
+// preferences are those that apply at the given time according to its
+// schedule windows, along with the delay after which the preferences
+// should next be evaluated. An RSP without schedule windows is
+// returned unchanged with no delay.
+func scheduledPreferences(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, now time.Time) (*fedschedulingv1a1.ReplicaSchedulingPreference, time.Duration, error) {
+	if len(rsp.Spec.Schedules) == 0 {
+		return rsp, 0, nil
+	}
+
+	recheckDelay := maxScheduleRecheckDelay
+	var activeWindow *fedschedulingv1a1.ScheduleWindow
+	var rampFraction float64
+	for i := range rsp.Spec.Schedules {
+		window := &rsp.Spec.Schedules[i]
+		intervals, err := windowIntervals(window, now)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "invalid schedule window %d", i)
+		}
+		ramp := time.Duration(window.RampMinutes) * time.Minute
+		for _, interval := range intervals {
+			for _, boundary := range interval.boundaries(ramp) {
+				if delay := boundary.Sub(now); delay > 0 && delay < recheckDelay {
+					recheckDelay = delay
+				}
+			}
+			if activeWindow != nil || now.Before(interval.start) || !now.Before(interval.end) {
+				continue
+			}
+			activeWindow = window
+			rampFraction = interval.rampFraction(now, ramp)
+			if rampFraction < 1 && recheckDelay > scheduleRampInterval {
+				recheckDelay = scheduleRampInterval
+			}
+		}
+	}
+
+	scheduled := rsp.DeepCopy()
+	if activeWindow != nil {
+		clusters := rsp.Spec.Clusters
+		if len(clusters) == 0 {
+			clusters = defaultClusterPreferences()
+		}
+		scheduled.Spec.Clusters = rampedPreferences(clusters, activeWindow.Clusters, rampFraction)
+	}
+	return scheduled, recheckDelay, nil
+}
+
+// windowIntervals returns the occurrences of the given schedule window
+// that start between the day before and a week after the given time.
+func windowIntervals(window *fedschedulingv1a1.ScheduleWindow, now time.Time) ([]scheduleInterval, error) {
+	location := time.UTC
+	if len(window.TimeZone) > 0 {
+		var err error
+		location, err = time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid time zone %q", window.TimeZone)
+		}
+	}
+	start, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid start %q", window.Start)
+	}
+	end, err := time.Parse("15:04", window.End)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid end %q", window.End)
+	}
+	days := make(map[time.Weekday]bool)
+	for _, day := range window.Days {
+		weekday, ok := weekdays[day]
+		if !ok {
+			return nil, errors.Errorf("invalid day %q", day)
+		}
+		days[weekday] = true
+	}
+
+	localNow := now.In(location)
+	year, month, day := localNow.Date()
+	intervals := []scheduleInterval{}
+	for offset := -1; offset <= 7; offset++ {
+		interval := scheduleInterval{
+			start: time.Date(year, month, day+offset, start.Hour(), start.Minute(), 0, 0, location),
+			end:   time.Date(year, month, day+offset, end.Hour(), end.Minute(), 0, 0, location),
+		}
+		if len(days) > 0 && !days[interval.start.Weekday()] {
+			continue
+		}
+		if !interval.end.After(interval.start) {
+			interval.end = time.Date(year, month, day+offset+1, end.Hour(), end.Minute(), 0, 0, location)
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals, nil
+}
+
+// boundaries returns the times at which the preferences applying
+// during the interval change.
+func (i scheduleInterval) boundaries(ramp time.Duration) []time.Time {
+	if ramp <= 0 {
+		return []time.Time{i.start, i.end}
+	}
+	return []time.Time{i.start, i.start.Add(ramp), i.end.Add(-ramp), i.end}
+}
+
+// rampFraction returns the fraction, between 0 and 1, to which the
+// preferences of the window apply at the given time within the
+// interval.
+func (i scheduleInterval) rampFraction(now time.Time, ramp time.Duration) float64 {
+	if ramp <= 0 {
+		return 1
+	}
+	elapsed := now.Sub(i.start)
+	if remaining := i.end.Sub(now); remaining < elapsed {
+		elapsed = remaining
+	}
+	if elapsed >= ramp {
+		return 1
+	}
+	return float64(elapsed) / float64(ramp)
+}
+
+// rampedPreferences returns the cluster preferences that result from
+// shifting the given fraction of the way from the given preferences to
+// the preferences of a schedule window. Weights and minimum replicas
+// are interpolated, and while ramping a cluster is bounded by the
+// larger of the two maximum replicas.
+func rampedPreferences(from, to map[string]fedschedulingv1a1.ClusterPreferences, fraction float64) map[string]fedschedulingv1a1.ClusterPreferences {
+	if fraction >= 1 {
+		return to
+	}
+	percent := int64(fraction * 100)
+
+	result := make(map[string]fedschedulingv1a1.ClusterPreferences)
+	clusterNames := make(map[string]bool)
+	for clusterName := range from {
+		clusterNames[clusterName] = true
+	}
+	for clusterName := range to {
+		clusterNames[clusterName] = true
+	}
+	for clusterName := range clusterNames {
+		fromPreferences := clusterPreferences(from, clusterName)
+		toPreferences := clusterPreferences(to, clusterName)
+		preferences := fedschedulingv1a1.ClusterPreferences{
+			// Scaling both weights preserves the precision of
+			// their ratio.
+			Weight:      fromPreferences.Weight*(100-percent) + toPreferences.Weight*percent,
+			MinReplicas: (fromPreferences.MinReplicas*(100-percent) + toPreferences.MinReplicas*percent) / 100,
+		}
+		if fromPreferences.MaxReplicas != nil && toPreferences.MaxReplicas != nil {
+			maxReplicas := *fromPreferences.MaxReplicas
+			if *toPreferences.MaxReplicas > maxReplicas {
+				maxReplicas = *toPreferences.MaxReplicas
+			}
+			preferences.MaxReplicas = &maxReplicas
+		}
+		result[clusterName] = preferences
+	}
+	return result
+}
+
+// clusterPreferences returns the preferences for the named cluster,
+// falling back to the preferences for all clusters.
+func clusterPreferences(preferences map[string]fedschedulingv1a1.ClusterPreferences, clusterName string) fedschedulingv1a1.ClusterPreferences {
+	if clusterPreferences, ok := preferences[clusterName]; ok {
+		return clusterPreferences
+	}
+	return preferences["*"]
+}
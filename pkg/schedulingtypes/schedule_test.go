@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestScheduledPreferences(t *testing.T) {
+	dayClusters := map[string]fedschedulingv1a1.ClusterPreferences{
+		"europe":  {Weight: 1},
+		"america": {Weight: 1},
+	}
+	europeClusters := map[string]fedschedulingv1a1.ClusterPreferences{
+		"europe": {Weight: 1},
+	}
+
+	// 2020-03-02 is a Monday.
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2020, time.March, 2, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		window           fedschedulingv1a1.ScheduleWindow
+		now              time.Time
+		expectedClusters map[string]fedschedulingv1a1.ClusterPreferences
+		expectedDelay    time.Duration
+	}{
+		"Before the window": {
+			window:           fedschedulingv1a1.ScheduleWindow{Start: "08:00", End: "18:00", Clusters: europeClusters},
+			now:              monday(7, 0),
+			expectedClusters: dayClusters,
+			expectedDelay:    time.Hour,
+		},
+		"Within the window": {
+			window:           fedschedulingv1a1.ScheduleWindow{Start: "08:00", End: "18:00", Clusters: europeClusters},
+			now:              monday(17, 30),
+			expectedClusters: europeClusters,
+			expectedDelay:    30 * time.Minute,
+		},
+		"Within a window ending on the following day": {
+			window:           fedschedulingv1a1.ScheduleWindow{Start: "22:00", End: "06:00", Clusters: europeClusters},
+			now:              monday(5, 0),
+			expectedClusters: europeClusters,
+			expectedDelay:    time.Hour,
+		},
+		"Outside the days of the window": {
+			window:           fedschedulingv1a1.ScheduleWindow{Days: []string{"Sat", "Sun"}, Start: "08:00", End: "18:00", Clusters: europeClusters},
+			now:              monday(12, 0),
+			expectedClusters: dayClusters,
+			expectedDelay:    time.Hour,
+		},
+		"Within the window in a different time zone": {
+			window:           fedschedulingv1a1.ScheduleWindow{Start: "08:00", End: "18:00", TimeZone: "America/New_York", Clusters: europeClusters},
+			now:              monday(14, 0),
+			expectedClusters: europeClusters,
+			expectedDelay:    time.Hour,
+		},
+		"Ramping up": {
+			window: fedschedulingv1a1.ScheduleWindow{Start: "08:00", End: "18:00", RampMinutes: 60, Clusters: europeClusters},
+			now:    monday(8, 15),
+			expectedClusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"europe":  {Weight: 100},
+				"america": {Weight: 75},
+			},
+			expectedDelay: time.Minute,
+		},
+		"Ramping down": {
+			window: fedschedulingv1a1.ScheduleWindow{Start: "08:00", End: "18:00", RampMinutes: 60, Clusters: europeClusters},
+			now:    monday(17, 30),
+			expectedClusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"europe":  {Weight: 100},
+				"america": {Weight: 50},
+			},
+			expectedDelay: time.Minute,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+				Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+					Clusters:  dayClusters,
+					Schedules: []fedschedulingv1a1.ScheduleWindow{tc.window},
+				},
+			}
+			scheduled, delay, err := scheduledPreferences(rsp, tc.now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(scheduled.Spec.Clusters, tc.expectedClusters) {
+				t.Errorf("Expected cluster preferences %v, got %v", tc.expectedClusters, scheduled.Spec.Clusters)
+			}
+			if delay != tc.expectedDelay {
+				t.Errorf("Expected delay %v, got %v", tc.expectedDelay, delay)
+			}
+		})
+	}
+}
+
+func TestScheduledPreferencesInvalidWindow(t *testing.T) {
+	windows := map[string]fedschedulingv1a1.ScheduleWindow{
+		"Invalid start":     {Start: "8am", End: "18:00"},
+		"Invalid end":       {Start: "08:00", End: "25:00"},
+		"Invalid day":       {Days: []string{"Monday"}, Start: "08:00", End: "18:00"},
+		"Invalid time zone": {Start: "08:00", End: "18:00", TimeZone: "Europe/Nowhere"},
+	}
+	for testName, window := range windows {
+		t.Run(testName, func(t *testing.T) {
+			rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+				Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+					Schedules: []fedschedulingv1a1.ScheduleWindow{window},
+				},
+			}
+			if _, _, err := scheduledPreferences(rsp, time.Now()); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+const (
+	// DefaultMaxWeightReductionPercentage is the maximum percentage by
+	// which the weight of a cluster is reduced if an RSP does not
+	// specify spec.weightAutoTuning.maxReductionPercentage.
+	DefaultMaxWeightReductionPercentage = 50
+
+	// DefaultWeightRecoveryPeriod is the period for which the weight of
+	// a cluster remains reduced after last observing failing pods if an
+	// RSP does not specify spec.weightAutoTuning.recoverySeconds.
+	DefaultWeightRecoveryPeriod = 10 * time.Minute
+)
+
+type weightReduction struct {
+	percentage   int64
+	lastObserved time.Time
+}
+
+// weightAdjustment describes a change to the weight reduction of a
+// cluster.
+type weightAdjustment struct {
+	clusterName string
+	previous    int64
+	current     int64
+}
+
+// weightTuner remembers the weight reductions of the clusters of a
+// scheduled workload. The weight of a cluster is reduced by the
+// percentage of the pods of the workload in that cluster that are
+// failing, and the reduction is retained for a recovery period after
+// the cluster stops reporting failing pods so that replicas are not
+// immediately moved back to it.
+type weightTuner struct {
+	sync.Mutex
+	reductions map[string]map[string]weightReduction
+}
+
+func newWeightTuner() *weightTuner {
+	return &weightTuner{
+		reductions: make(map[string]map[string]weightReduction),
+	}
+}
+
+// tune records the percentage of failing pods observed for the
+// clusters of the workload with the given key, and returns the weight
+// reduction percentage of each cluster along with the reductions that
+// changed since the workload was last tuned.
+func (t *weightTuner) tune(key string, observedFailures map[string]int64, maxReduction int64, recovery time.Duration, now time.Time) (map[string]int64, []weightAdjustment) {
+	t.Lock()
+	defer t.Unlock()
+
+	reductions, ok := t.reductions[key]
+	if !ok {
+		reductions = make(map[string]weightReduction)
+	}
+	previous := make(map[string]int64, len(reductions))
+	for clusterName, reduction := range reductions {
+		previous[clusterName] = reduction.percentage
+	}
+
+	for clusterName, failures := range observedFailures {
+		if failures <= 0 {
+			continue
+		}
+		if failures > maxReduction {
+			failures = maxReduction
+		}
+		reductions[clusterName] = weightReduction{
+			percentage:   failures,
+			lastObserved: now,
+		}
+	}
+
+	result := make(map[string]int64)
+	for clusterName, reduction := range reductions {
+		if now.Sub(reduction.lastObserved) >= recovery {
+			delete(reductions, clusterName)
+			continue
+		}
+		result[clusterName] = reduction.percentage
+	}
+
+	if len(reductions) == 0 {
+		delete(t.reductions, key)
+	} else {
+		t.reductions[key] = reductions
+	}
+
+	adjustments := []weightAdjustment{}
+	for clusterName, percentage := range result {
+		if previous[clusterName] != percentage {
+			adjustments = append(adjustments, weightAdjustment{clusterName, previous[clusterName], percentage})
+		}
+	}
+	for clusterName, percentage := range previous {
+		if _, ok := result[clusterName]; !ok {
+			adjustments = append(adjustments, weightAdjustment{clusterName, percentage, 0})
+		}
+	}
+	sort.Slice(adjustments, func(i, j int) bool {
+		return adjustments[i].clusterName < adjustments[j].clusterName
+	})
+	return result, adjustments
+}
+
+// forget discards the weight reductions recorded for the workload
+// with the given key.
+func (t *weightTuner) forget(key string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.reductions, key)
+}
+
+// maxWeightReduction returns the maximum percentage by which the
+// weight of a cluster may be reduced for the given RSP.
+func maxWeightReduction(tuning *fedschedulingv1a1.WeightAutoTuning) int64 {
+	if tuning.MaxReductionPercentage == nil {
+		return DefaultMaxWeightReductionPercentage
+	}
+	return int64(*tuning.MaxReductionPercentage)
+}
+
+// weightRecoveryPeriod returns the period for which the weight of a
+// cluster remains reduced for the given RSP.
+func weightRecoveryPeriod(tuning *fedschedulingv1a1.WeightAutoTuning) time.Duration {
+	if tuning.RecoverySeconds == nil {
+		return DefaultWeightRecoveryPeriod
+	}
+	return time.Duration(*tuning.RecoverySeconds) * time.Second
+}
+
+// tunedPreferences returns a copy of the given RSP whose cluster
+// weights are reduced by the given percentages. All weights are scaled
+// by 100 so that the reductions of small weights are not lost to
+// integer truncation without changing their proportions.
+func tunedPreferences(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusterNames []string, reductions map[string]int64) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	if len(reductions) == 0 {
+		return rsp
+	}
+	rsp = rsp.DeepCopy()
+	if len(rsp.Spec.Clusters) == 0 {
+		rsp.Spec.Clusters = defaultClusterPreferences()
+	}
+
+	clusters := make(map[string]fedschedulingv1a1.ClusterPreferences, len(rsp.Spec.Clusters))
+	for name, preferences := range rsp.Spec.Clusters {
+		preferences.Weight *= 100
+		clusters[name] = preferences
+	}
+	for _, clusterName := range clusterNames {
+		percentage, ok := reductions[clusterName]
+		if !ok {
+			continue
+		}
+		preferences, ok := rsp.Spec.Clusters[clusterName]
+		if !ok {
+			preferences, ok = rsp.Spec.Clusters["*"]
+			if !ok {
+				continue
+			}
+		}
+		preferences.Weight *= 100 - percentage
+		clusters[clusterName] = preferences
+	}
+	rsp.Spec.Clusters = clusters
+	return rsp
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestWeightTunerTune(t *testing.T) {
+	key := "ns/name"
+	recovery := time.Minute
+	start := time.Now()
+
+	tuner := newWeightTuner()
+
+	steps := []struct {
+		description string
+		elapsed     time.Duration
+		observed    map[string]int64
+		expected    map[string]int64
+		adjustments []weightAdjustment
+	}{
+		{
+			description: "Observed failures reduce the weight",
+			observed:    map[string]int64{"cluster1": 20},
+			expected:    map[string]int64{"cluster1": 20},
+			adjustments: []weightAdjustment{{"cluster1", 0, 20}},
+		},
+		{
+			description: "Reductions are capped",
+			elapsed:     10 * time.Second,
+			observed:    map[string]int64{"cluster2": 80},
+			expected:    map[string]int64{"cluster1": 20, "cluster2": 50},
+			adjustments: []weightAdjustment{{"cluster2", 0, 50}},
+		},
+		{
+			description: "Unchanged reductions are not reported as adjustments",
+			elapsed:     30 * time.Second,
+			observed:    map[string]int64{"cluster1": 20},
+			expected:    map[string]int64{"cluster1": 20, "cluster2": 50},
+			adjustments: []weightAdjustment{},
+		},
+		{
+			description: "Reductions are lifted once the recovery period has elapsed",
+			elapsed:     80 * time.Second,
+			observed:    map[string]int64{},
+			expected:    map[string]int64{"cluster1": 20},
+			adjustments: []weightAdjustment{{"cluster2", 50, 0}},
+		},
+		{
+			description: "All reductions are eventually lifted",
+			elapsed:     100 * time.Second,
+			observed:    map[string]int64{},
+			expected:    map[string]int64{},
+			adjustments: []weightAdjustment{{"cluster1", 20, 0}},
+		},
+	}
+	for _, step := range steps {
+		actual, adjustments := tuner.tune(key, step.observed, 50, recovery, start.Add(step.elapsed))
+		if !reflect.DeepEqual(step.expected, actual) {
+			t.Fatalf("%s: expected %v, got %v", step.description, step.expected, actual)
+		}
+		if !reflect.DeepEqual(step.adjustments, adjustments) {
+			t.Fatalf("%s: expected adjustments %v, got %v", step.description, step.adjustments, adjustments)
+		}
+	}
+	if _, ok := tuner.reductions[key]; ok {
+		t.Fatalf("Expected lifted reductions to be discarded")
+	}
+}
+
+func TestTunedPreferences(t *testing.T) {
+	rsp := &fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			Clusters: map[string]fedschedulingv1a1.ClusterPreferences{
+				"cluster1": {Weight: 2},
+				"*":        {Weight: 1, MinReplicas: 1},
+			},
+		},
+	}
+	reductions := map[string]int64{"cluster1": 50, "cluster2": 25}
+
+	tuned := tunedPreferences(rsp, []string{"cluster1", "cluster2", "cluster3"}, reductions)
+
+	expected := map[string]fedschedulingv1a1.ClusterPreferences{
+		"cluster1": {Weight: 100},
+		"cluster2": {Weight: 75, MinReplicas: 1},
+		"*":        {Weight: 100, MinReplicas: 1},
+	}
+	if !reflect.DeepEqual(expected, tuned.Spec.Clusters) {
+		t.Fatalf("Expected %v, got %v", expected, tuned.Spec.Clusters)
+	}
+	if rsp.Spec.Clusters["cluster1"].Weight != 2 {
+		t.Fatalf("Expected the preferences of the given RSP to be unchanged")
+	}
+	if tunedPreferences(rsp, []string{"cluster1"}, nil) != rsp {
+		t.Fatalf("Expected the given RSP to be returned without reductions")
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulation provides member clusters backed by in-process API
+// servers so that the behavior of the control plane can be exercised
+// without provisioning real clusters.
+package simulation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	// ClusterNamePrefix is the prefix of the names of simulated
+	// clusters.
+	ClusterNamePrefix = "simulated-"
+
+	// The API servers of simulated clusters do not authenticate
+	// requests, but the cluster controller requires a token.
+	simulatedToken = "simulated"
+)
+
+// ClusterName returns the name of the simulated cluster with the given
+// index.
+func ClusterName(index int) string {
+	return fmt.Sprintf("%s%d", ClusterNamePrefix, index)
+}
+
+// Clusters is a set of simulated member clusters joined to a KubeFed
+// control plane.
+type Clusters struct {
+	client       genericclient.Client
+	fedNamespace string
+	environments map[string]*envtest.Environment
+}
+
+// StartClusters starts the given number of simulated clusters and joins
+// them to the control plane in the host cluster. The etcd and
+// kube-apiserver binaries used to run the clusters are located via the
+// KUBEBUILDER_ASSETS environment variable.
+func StartClusters(hostConfig *rest.Config, fedNamespace string, count int) (*Clusters, error) {
+	client, err := genericclient.New(rest.AddUserAgent(hostConfig, "simulated-clusters"))
+	if err != nil {
+		return nil, err
+	}
+	c := &Clusters{
+		client:       client,
+		fedNamespace: fedNamespace,
+		environments: make(map[string]*envtest.Environment),
+	}
+	for i := 0; i < count; i++ {
+		name := ClusterName(i)
+		if err := c.start(name); err != nil {
+			c.Stop()
+			return nil, errors.Wrapf(err, "failed to start simulated cluster %q", name)
+		}
+		klog.Infof("Started simulated cluster %q", name)
+	}
+	return c, nil
+}
+
+func (c *Clusters) start(name string) error {
+	env := &envtest.Environment{}
+	config, err := env.Start()
+	if err != nil {
+		return err
+	}
+	c.environments[name] = env
+
+	// Mirror kubefedctl join by creating the KubeFed namespace in the
+	// member cluster.
+	clusterClient, err := kubeclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.fedNamespace,
+		},
+	}
+	_, err = clusterClient.CoreV1().Namespaces().Create(namespace)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.fedNamespace,
+			Name:      secretName(name),
+		},
+		Data: map[string][]byte{
+			util.TokenKey: []byte(simulatedToken),
+		},
+	}
+	if err := c.createOrUpdate(secret, &corev1.Secret{}); err != nil {
+		return err
+	}
+
+	fedCluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.fedNamespace,
+			Name:      name,
+		},
+		Spec: fedv1b1.KubeFedClusterSpec{
+			APIEndpoint: env.ControlPlane.APIURL().String(),
+			SecretRef: fedv1b1.LocalSecretReference{
+				Name: secret.Name,
+			},
+		},
+	}
+	return c.createOrUpdate(fedCluster, &fedv1b1.KubeFedCluster{})
+}
+
+type object interface {
+	runtime.Object
+	metav1.Object
+}
+
+// createOrUpdate creates the given object in the host cluster or, if it
+// already exists, replaces it. existing is used to retrieve the
+// current state of the object.
+func (c *Clusters) createOrUpdate(obj, existing object) error {
+	err := c.client.Get(context.TODO(), existing, obj.GetNamespace(), obj.GetName())
+	switch {
+	case apierrors.IsNotFound(err):
+		return c.client.Create(context.TODO(), obj)
+	case err != nil:
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return c.client.Update(context.TODO(), obj)
+}
+
+// Stop unjoins and stops the simulated clusters.
+func (c *Clusters) Stop() {
+	for name, env := range c.environments {
+		err := c.client.Delete(context.TODO(), &fedv1b1.KubeFedCluster{}, c.fedNamespace, name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to remove simulated cluster %q: %v", name, err)
+		}
+		err = c.client.Delete(context.TODO(), &corev1.Secret{}, c.fedNamespace, secretName(name))
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to remove the secret of simulated cluster %q: %v", name, err)
+		}
+		if err := env.Stop(); err != nil {
+			klog.Errorf("Failed to stop simulated cluster %q: %v", name, err)
+		}
+		delete(c.environments, name)
+	}
+}
+
+func secretName(clusterName string) string {
+	return clusterName + "-token"
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/client/generic/scheme"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// fakeClient adapts a controller-runtime fake client to the generic
+// client interface.
+type fakeClient struct {
+	client client.Client
+}
+
+func newFakeClient(objs ...runtime.Object) *fakeClient {
+	return &fakeClient{client: fake.NewFakeClientWithScheme(scheme.Scheme, objs...)}
+}
+
+func (c *fakeClient) Create(ctx context.Context, obj runtime.Object) error {
+	return c.client.Create(ctx, obj)
+}
+
+func (c *fakeClient) Get(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	return c.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj)
+}
+
+func (c *fakeClient) Update(ctx context.Context, obj runtime.Object) error {
+	return c.client.Update(ctx, obj)
+}
+
+func (c *fakeClient) Delete(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	accessor := obj.(metav1.Object)
+	accessor.SetNamespace(namespace)
+	accessor.SetName(name)
+	return c.client.Delete(ctx, obj)
+}
+
+func (c *fakeClient) List(ctx context.Context, obj runtime.Object, namespace string, opts ...client.ListOption) error {
+	return c.client.List(ctx, obj, append(opts, client.InNamespace(namespace))...)
+}
+
+func (c *fakeClient) UpdateStatus(ctx context.Context, obj runtime.Object) error {
+	return c.client.Status().Update(ctx, obj)
+}
+
+func (c *fakeClient) Patch(ctx context.Context, obj runtime.Object, patchType types.PatchType, data []byte) error {
+	return errors.New("patch is not supported by the fake client")
+}
+
+func TestClusterNames(t *testing.T) {
+	if name := ClusterName(2); name != "simulated-2" {
+		t.Errorf("Expected cluster name %q, got %q", "simulated-2", name)
+	}
+	if name := secretName(ClusterName(0)); name != "simulated-0-token" {
+		t.Errorf("Expected secret name %q, got %q", "simulated-0-token", name)
+	}
+}
+
+func TestCreateOrUpdate(t *testing.T) {
+	const namespace = "kube-federation-system"
+	newSecret := func(token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "simulated-0-token",
+			},
+			Data: map[string][]byte{
+				util.TokenKey: []byte(token),
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		existing []runtime.Object
+	}{
+		"Missing object is created": {},
+		"Existing object is replaced": {
+			existing: []runtime.Object{newSecret("stale")},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			c := &Clusters{
+				client:       newFakeClient(tc.existing...),
+				fedNamespace: namespace,
+			}
+			if err := c.createOrUpdate(newSecret(simulatedToken), &corev1.Secret{}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			secret := &corev1.Secret{}
+			if err := c.client.Get(context.TODO(), secret, namespace, "simulated-0-token"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expectedData := map[string][]byte{util.TokenKey: []byte(simulatedToken)}
+			if !reflect.DeepEqual(secret.Data, expectedData) {
+				t.Errorf("Expected secret data %v, got %v", expectedData, secret.Data)
+			}
+		})
+	}
+}
+
+func TestStop(t *testing.T) {
+	const namespace = "kube-federation-system"
+	joinedName := ClusterName(0)
+	objs := []runtime.Object{
+		&fedv1b1.KubeFedCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: joinedName},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secretName(joinedName)},
+		},
+	}
+	// Stopping an environment that uses an existing cluster is a
+	// no-op, which avoids the need to run API servers.
+	useExistingCluster := true
+	c := &Clusters{
+		client:       newFakeClient(objs...),
+		fedNamespace: namespace,
+		environments: map[string]*envtest.Environment{
+			joinedName: {UseExistingCluster: &useExistingCluster},
+			// A cluster that failed to start before it was joined.
+			ClusterName(1): {UseExistingCluster: &useExistingCluster},
+		},
+	}
+
+	c.Stop()
+
+	if len(c.environments) != 0 {
+		t.Errorf("Expected no remaining simulated clusters, got %d", len(c.environments))
+	}
+	err := c.client.Get(context.TODO(), &fedv1b1.KubeFedCluster{}, namespace, joinedName)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the KubeFedCluster to be removed, got error: %v", err)
+	}
+	err = c.client.Get(context.TODO(), &corev1.Secret{}, namespace, secretName(joinedName))
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the secret to be removed, got error: %v", err)
+	}
+}
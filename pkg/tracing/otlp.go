@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog"
+)
+
+const (
+	tracesPath = "/v1/traces"
+
+	queueSize     = 2048
+	maxBatchSize  = 512
+	flushInterval = 5 * time.Second
+	exportTimeout = 10 * time.Second
+
+	// OTLP span kind and status codes.
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+// Config configures the export of spans.
+type Config struct {
+	// Endpoint is the base URL of an OTLP/HTTP receiver,
+	// e.g. http://otel-collector:4318.
+	Endpoint string
+	// ServiceName identifies the process emitting spans.
+	ServiceName string
+	// SampleRatio is the fraction of traces that are exported.
+	SampleRatio float64
+}
+
+var (
+	exporterLock   sync.RWMutex
+	globalExporter *otlpExporter
+)
+
+func currentExporter() *otlpExporter {
+	exporterLock.RLock()
+	defer exporterLock.RUnlock()
+	return globalExporter
+}
+
+// Start enables tracing and exports completed spans to an OTLP/HTTP
+// receiver until the stop channel is closed.
+func Start(config Config, stopChan <-chan struct{}) error {
+	if len(config.Endpoint) == 0 {
+		return errors.New("an endpoint is required to export traces")
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return errors.Errorf("sample ratio %v must be between 0 and 1", config.SampleRatio)
+	}
+	exporter := &otlpExporter{
+		url:         strings.TrimSuffix(config.Endpoint, "/") + tracesPath,
+		serviceName: config.ServiceName,
+		sampleRatio: config.SampleRatio,
+		queue:       make(chan *Span, queueSize),
+		client:      &http.Client{Timeout: exportTimeout},
+	}
+
+	exporterLock.Lock()
+	globalExporter = exporter
+	exporterLock.Unlock()
+
+	go exporter.run(stopChan)
+	return nil
+}
+
+type otlpExporter struct {
+	url         string
+	serviceName string
+	sampleRatio float64
+	queue       chan *Span
+	client      *http.Client
+}
+
+func (e *otlpExporter) sample() bool {
+	return e.sampleRatio >= 1 || rand.Float64() < e.sampleRatio
+}
+
+// enqueue queues a span for export. Spans are dropped rather than
+// blocking the caller if the queue is full.
+func (e *otlpExporter) enqueue(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		klog.V(4).Infof("Dropping span %q: export queue is full", span.name)
+	}
+}
+
+func (e *otlpExporter) run(stopChan <-chan struct{}) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := []*Span{}
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) < maxBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-stopChan:
+			exporterLock.Lock()
+			if globalExporter == e {
+				globalExporter = nil
+			}
+			exporterLock.Unlock()
+			e.export(append(batch, e.drain()...))
+			return
+		}
+		e.export(batch)
+		batch = []*Span{}
+	}
+}
+
+// drain returns the spans remaining in the queue.
+func (e *otlpExporter) drain() []*Span {
+	spans := []*Span{}
+	for {
+		select {
+		case span := <-e.queue:
+			spans = append(spans, span)
+		default:
+			return spans
+		}
+	}
+}
+
+func (e *otlpExporter) export(spans []*Span) {
+	if len(spans) == 0 {
+		return
+	}
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		klog.Errorf("Failed to encode %d spans: %v", len(spans), err)
+		return
+	}
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		klog.V(2).Infof("Failed to export %d spans to %q: %v", len(spans), e.url, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		klog.V(2).Infof("Failed to export %d spans to %q: unexpected status %q", len(spans), e.url, resp.Status)
+	}
+}
+
+// The following types encode an OTLP ExportTraceServiceRequest as
+// JSON.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            spanStatus `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type spanStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *otlpExporter) request(spans []*Span) exportRequest {
+	data := make([]spanData, 0, len(spans))
+	for _, span := range spans {
+		data = append(data, span.data())
+	}
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{stringKeyValue("service.name", e.serviceName)},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "sigs.k8s.io/kubefed"},
+				Spans: data,
+			}},
+		}},
+	}
+}
+
+func (s *Span) data() spanData {
+	s.Lock()
+	defer s.Unlock()
+
+	attributes := make([]keyValue, 0, len(s.attributes))
+	for _, attribute := range s.attributes {
+		attributes = append(attributes, stringKeyValue(attribute.Key, attribute.Value))
+	}
+	status := spanStatus{Code: statusCodeOK}
+	if s.failed {
+		status = spanStatus{Code: statusCodeError, Message: s.errMessage}
+	}
+	return spanData{
+		TraceID:           s.traceID,
+		SpanID:            s.spanID,
+		ParentSpanID:      s.parentSpanID,
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        attributes,
+		Status:            status,
+	}
+}
+
+func stringKeyValue(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: value}}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	mathrand "math/rand"
+	"sync"
+	"time"
+)
+
+// Attribute is a key/value pair describing a span.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// String returns an attribute with the given key and value.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span records the duration of an operation. A nil span is valid and
+// all of its methods are no-ops, which allows instrumented code to
+// remain unaware of whether tracing is enabled or the trace was
+// sampled.
+type Span struct {
+	sync.Mutex
+
+	traceID      string
+	spanID       string
+	parentSpanID string
+	name         string
+	start        time.Time
+	end          time.Time
+	attributes   []Attribute
+	errMessage   string
+	failed       bool
+}
+
+// StartSpan starts a new trace with a root span of the given name. nil
+// is returned if tracing is not enabled or the trace is not sampled.
+func StartSpan(name string, attributes ...Attribute) *Span {
+	exporter := currentExporter()
+	if exporter == nil || !exporter.sample() {
+		return nil
+	}
+	return newSpan(newID(16), "", name, attributes)
+}
+
+// StartChild starts a span of the given name as a child of the span.
+func (s *Span) StartChild(name string, attributes ...Attribute) *Span {
+	if s == nil {
+		return nil
+	}
+	return newSpan(s.traceID, s.spanID, name, attributes)
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.attributes = append(s.attributes, attributes...)
+}
+
+// RecordError marks the span as failed with the given error.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.failed = true
+	s.errMessage = err.Error()
+}
+
+// SetFailed marks the span as failed without an error message.
+func (s *Span) SetFailed() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.failed = true
+}
+
+// End completes the span and queues it for export.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	s.end = time.Now()
+	s.Unlock()
+
+	exporter := currentExporter()
+	if exporter != nil {
+		exporter.enqueue(s)
+	}
+}
+
+func newSpan(traceID, parentSpanID, name string, attributes []Attribute) *Span {
+	return &Span{
+		traceID:      traceID,
+		spanID:       newID(8),
+		parentSpanID: parentSpanID,
+		name:         name,
+		start:        time.Now(),
+		attributes:   attributes,
+	}
+}
+
+// newID returns a random hex-encoded identifier of the given number
+// of bytes.
+func newID(length int) string {
+	id := make([]byte, length)
+	if _, err := rand.Read(id); err != nil {
+		// Fall back to a weaker source rather than fail to trace.
+		_, _ = mathrand.Read(id)
+	}
+	return hex.EncodeToString(id)
+}
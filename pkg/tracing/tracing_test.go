@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestNilSpan(t *testing.T) {
+	// Tracing is not enabled, so spans are not recorded.
+	span := StartSpan("reconcile")
+	assert.Nil(t, span)
+
+	child := span.StartChild("create")
+	child.SetAttributes(String("cluster", "cluster1"))
+	child.RecordError(errors.New("failed"))
+	child.End()
+	span.End()
+}
+
+func TestExport(t *testing.T) {
+	requests := make(chan exportRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, tracesPath, r.URL.Path)
+		request := exportRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests <- request
+	}))
+	defer server.Close()
+
+	stopChan := make(chan struct{})
+	err := Start(Config{Endpoint: server.URL, ServiceName: "test", SampleRatio: 1}, stopChan)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	span := StartSpan("reconcile", String("qualifiedName", "ns/name"))
+	if span == nil {
+		t.Fatal("Expected a span to be started")
+	}
+	child := span.StartChild("create", String("cluster", "cluster1"))
+	child.RecordError(errors.New("failed"))
+	child.End()
+	span.End()
+	close(stopChan)
+
+	var request exportRequest
+	select {
+	case request = <-requests:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Timed out waiting for spans to be exported")
+	}
+
+	if len(request.ResourceSpans) != 1 || len(request.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Expected spans for a single resource and scope, got %+v", request)
+	}
+	assert.Equal(t, "test", request.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	childData, rootData := spans[0], spans[1]
+	assert.Equal(t, rootData.TraceID, childData.TraceID)
+	assert.Equal(t, rootData.SpanID, childData.ParentSpanID)
+	assert.Empty(t, rootData.ParentSpanID)
+	assert.Equal(t, statusCodeOK, rootData.Status.Code)
+	assert.Equal(t, statusCodeError, childData.Status.Code)
+	assert.Equal(t, "failed", childData.Status.Message)
+}
@@ -24,9 +24,22 @@ import (
 	"github.com/openshift/generic-admission-server/pkg/cmd/server"
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/autoenablepolicy"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/blueprint"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/blueprintinstance"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/clusterallowlist"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/clustergroup"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/clusterjoinrequest"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/faultinjection"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/federatedapplication"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/federatedresource"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/federatedtemplate"
 	"sigs.k8s.io/kubefed/pkg/controller/webhook/federatedtypeconfig"
 	"sigs.k8s.io/kubefed/pkg/controller/webhook/kubefedcluster"
 	"sigs.k8s.io/kubefed/pkg/controller/webhook/kubefedconfig"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/kubefedinstance"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/maintenancewindow"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/namespaceprofile"
 	"sigs.k8s.io/kubefed/pkg/version"
 )
 
@@ -35,6 +48,19 @@ func NewWebhookCommand(stopChan <-chan struct{}) *cobra.Command {
 		&federatedtypeconfig.FederatedTypeConfigAdmissionHook{},
 		&kubefedcluster.KubeFedClusterAdmissionHook{},
 		&kubefedconfig.KubeFedConfigAdmissionHook{},
+		&clusterallowlist.ClusterAllowlistAdmissionHook{},
+		&clustergroup.ClusterGroupAdmissionHook{},
+		&clusterjoinrequest.ClusterJoinRequestAdmissionHook{},
+		&faultinjection.FaultInjectionAdmissionHook{},
+		&federatedapplication.FederatedApplicationAdmissionHook{},
+		&federatedtemplate.FederatedTemplateAdmissionHook{},
+		&kubefedinstance.KubeFedInstanceAdmissionHook{},
+		&maintenancewindow.MaintenanceWindowAdmissionHook{},
+		&namespaceprofile.NamespaceProfileAdmissionHook{},
+		&autoenablepolicy.AutoEnablePolicyAdmissionHook{},
+		&blueprint.BlueprintAdmissionHook{},
+		&blueprintinstance.BlueprintInstanceAdmissionHook{},
+		&federatedresource.FederatedResourceAdmissionHook{},
 	}
 
 	cmd := server.NewCommandStartAdmissionServer(os.Stdout, os.Stderr, stopChan, admissionHooks...)
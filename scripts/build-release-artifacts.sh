@@ -46,20 +46,28 @@ ASSETS_FILE="kubefed-${RELEASE_TAG}-asset-files.txt"
 pushd "${ROOT_DIR}"
   rm -f "${ROOT_DIR}/${ASSETS_FILE}"
   # Build release artifacts for kubefedctl
-  arch=amd64
-  for host_os in linux darwin; do
-    make bin/kubefedctl-${host_os}-${arch}
+  for platform in ${KUBEFEDCTL_RELEASE_PLATFORMS}; do
+    make bin/kubefedctl-${platform}
     pushd "${ROOT_DIR}/bin"
-      TAR_FILENAME="kubefedctl-${RELEASE_VERSION}-${host_os}-${arch}.tgz"
-      BINARY_FILENAME="kubefedctl-${host_os}-${arch}"
+      TAR_FILENAME="kubefedctl-${RELEASE_VERSION}-${platform}.tgz"
+      BINARY_FILENAME="kubefedctl-${platform}"
+      ARCHIVED_FILENAME="kubefedctl"
+      if [[ "${platform}" == windows-* ]]; then
+        ARCHIVED_FILENAME="kubefedctl.exe"
+      fi
       # The binary is built with a platform suffix, but should be archived without it.
-      tar cvzf "${TAR_FILENAME}" --transform="flags=r;s|${BINARY_FILENAME}|kubefedctl|" "${BINARY_FILENAME}"
+      tar cvzf "${TAR_FILENAME}" --transform="flags=r;s|${BINARY_FILENAME}|${ARCHIVED_FILENAME}|" "${BINARY_FILENAME}"
       sha256sum "${TAR_FILENAME}" > "${TAR_FILENAME}.sha"
       mv "${TAR_FILENAME}" "${TAR_FILENAME}.sha" "${ROOT_DIR}/"
       echo -e "${TAR_FILENAME}\n${TAR_FILENAME}.sha" >> "${ROOT_DIR}/${ASSETS_FILE}"
     popd
   done
 
+  # Generate the krew plugin manifest for the kubefedctl archives
+  KREW_MANIFEST="kubectl-kubefed-${RELEASE_VERSION}.yaml"
+  "${ROOT_DIR}/scripts/generate-krew-manifest.sh" "${RELEASE_TAG}" > "${ROOT_DIR}/${KREW_MANIFEST}"
+  echo "${KREW_MANIFEST}" >> "${ROOT_DIR}/${ASSETS_FILE}"
+
   # Build release artifacts for the helm chart
   pushd "${ROOT_DIR}/charts"
     # Update the image tag for the chart
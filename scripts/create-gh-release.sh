@@ -65,6 +65,8 @@ $(sed -E "1,/${regex}/d ; /${regex}/,\$d" CHANGELOG.md)
 ### kubefedctl, command line tool to join clusters, enable type federation, and convert resources to their federated equivalents
 See asset links below for \`kubefedctl-x.x.x-<os>-<arch>.tgz\`
 
+Install as a kubectl plugin with \`kubectl krew install --manifest-url=<url of kubectl-kubefed-x.x.x.yaml>\`
+
 ### Helm chart, to deploy federation as per user guide instructions
 See asset link below for \`kubefed-x.x.x.tgz\`
 
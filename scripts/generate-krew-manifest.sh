@@ -0,0 +1,79 @@
+#!/usr/bin/env bash
+
+# Copyright 2020 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# This script outputs the krew plugin manifest that installs kubefedctl
+# as `kubectl kubefed`. It expects the kubefedctl release archives and
+# their checksums to have been built by build-release-artifacts.sh.
+#
+# Reference:  https://krew.sigs.k8s.io/docs/developer-guide/plugin-manifest/
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source "$(dirname "${BASH_SOURCE}")/util.sh"
+
+RELEASE_TAG="${1-}"
+if [[ ! "${RELEASE_TAG}" =~ ${RELEASE_TAG_REGEX} ]]; then
+  >&2 echo "usage: $0 <release tag of the form v[0-9]+.[0-9]+.[0-9]+(-(alpha|beta|rc)\.?[0-9]+)?>"
+  exit 1
+fi
+
+ROOT_DIR="$(cd "$(dirname "$0")/.." ; pwd)"
+RELEASE_VERSION="${RELEASE_TAG:1:${#RELEASE_TAG}-1}"
+GITHUB_REPO="${GITHUB_REPO:-kubernetes-sigs/kubefed}"
+DOWNLOAD_URL="https://github.com/${GITHUB_REPO}/releases/download/${RELEASE_TAG}"
+
+cat <<MANIFEST
+apiVersion: krew.googlecontainertools.github.com/v1alpha2
+kind: Plugin
+metadata:
+  name: kubefed
+spec:
+  version: ${RELEASE_TAG}
+  homepage: https://github.com/${GITHUB_REPO}
+  shortDescription: Manage a Kubernetes Cluster Federation (KubeFed) control plane
+  description: |
+    Joins clusters to a KubeFed control plane, enables the federation
+    of API types and converts resources to their federated equivalents.
+    This plugin provides the kubefedctl command line tool.
+  platforms:
+MANIFEST
+
+for platform in ${KUBEFEDCTL_RELEASE_PLATFORMS}; do
+  host_os="${platform%-*}"
+  arch="${platform#*-}"
+  TAR_FILENAME="kubefedctl-${RELEASE_VERSION}-${platform}.tgz"
+  if [[ ! -f "${ROOT_DIR}/${TAR_FILENAME}.sha" ]]; then
+    >&2 echo "Checksum file ${TAR_FILENAME}.sha not found. Please run ${ROOT_DIR}/scripts/build-release-artifacts.sh first."
+    exit 1
+  fi
+  sha256="$(cut -d ' ' -f 1 "${ROOT_DIR}/${TAR_FILENAME}.sha")"
+  bin="kubefedctl"
+  if [[ "${host_os}" == "windows" ]]; then
+    bin="kubefedctl.exe"
+  fi
+
+  cat <<PLATFORM
+  - selector:
+      matchLabels:
+        os: ${host_os}
+        arch: ${arch}
+    uri: ${DOWNLOAD_URL}/${TAR_FILENAME}
+    sha256: ${sha256}
+    bin: ${bin}
+PLATFORM
+done
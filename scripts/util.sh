@@ -23,6 +23,10 @@
 # version.
 export RELEASE_TAG_REGEX="^v[0-9]+\.[0-9]+\.[0-9]+(-(alpha|beta|rc)\.?[0-9]+)?$"
 
+# KUBEFEDCTL_RELEASE_PLATFORMS contains the <os>-<arch> platforms for which
+# kubefedctl is released.
+export KUBEFEDCTL_RELEASE_PLATFORMS="linux-amd64 darwin-amd64 darwin-arm64 windows-amd64"
+
 
 # Utility Functions
 #
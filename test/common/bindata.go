@@ -18,10 +18,12 @@ limitations under the License.
 // sources:
 // test/common/fixtures/clusterroles.rbac.authorization.k8s.io.yaml
 // test/common/fixtures/configmaps.yaml
+// test/common/fixtures/daemonsets.apps.yaml
 // test/common/fixtures/deployments.apps.yaml
 // test/common/fixtures/ingresses.extensions.yaml
 // test/common/fixtures/jobs.batch.yaml
 // test/common/fixtures/namespaces.yaml
+// test/common/fixtures/persistentvolumeclaims.yaml
 // test/common/fixtures/replicasets.apps.yaml
 // test/common/fixtures/secrets.yaml
 // test/common/fixtures/serviceaccounts.yaml
@@ -29,10 +31,12 @@ limitations under the License.
 // config/kubefedconfig.yaml
 // config/enabletypedirectives/clusterroles.rbac.authorization.k8s.io.yaml
 // config/enabletypedirectives/configmaps.yaml
+// config/enabletypedirectives/daemonsets.apps.yaml
 // config/enabletypedirectives/deployments.apps.yaml
 // config/enabletypedirectives/ingresses.extensions.yaml
 // config/enabletypedirectives/jobs.batch.yaml
 // config/enabletypedirectives/namespaces.yaml
+// config/enabletypedirectives/persistentvolumeclaims.yaml
 // config/enabletypedirectives/replicasets.apps.yaml
 // config/enabletypedirectives/secrets.yaml
 // config/enabletypedirectives/serviceaccounts.yaml
@@ -47,6 +51,7 @@ import (
 	"strings"
 	"time"
 )
+
 type asset struct {
 	bytes []byte
 	info  os.FileInfo
@@ -141,6 +146,44 @@ func testCommonFixturesConfigmapsYaml() (*asset, error) {
 	return a, nil
 }
 
+var _testCommonFixturesDaemonsetsAppsYaml = []byte(`kind: fixture
+template:
+  spec:
+    selector:
+      matchLabels:
+        foo: bar
+    template:
+      metadata:
+        labels:
+          foo: bar
+      spec:
+        terminationGracePeriodSeconds: 0
+        containers:
+          - name: busybox
+            image: busybox
+            command: ["/bin/sh", "-c", "trap : TERM INT; (while true; do sleep 1000; done) & wait"]
+overrides:
+  - clusterOverrides:
+    - path: /spec/template/spec/nodeSelector
+      value:
+        kubernetes.io/os: linux
+`)
+
+func testCommonFixturesDaemonsetsAppsYamlBytes() ([]byte, error) {
+	return _testCommonFixturesDaemonsetsAppsYaml, nil
+}
+
+func testCommonFixturesDaemonsetsAppsYaml() (*asset, error) {
+	bytes, err := testCommonFixturesDaemonsetsAppsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "test/common/fixtures/daemonsets.apps.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _testCommonFixturesDeploymentsAppsYaml = []byte(`kind: fixture
 template:
   spec:
@@ -268,6 +311,31 @@ func testCommonFixturesNamespacesYaml() (*asset, error) {
 	return a, nil
 }
 
+var _testCommonFixturesPersistentvolumeclaimsYaml = []byte(`kind: fixture
+template:
+  spec:
+    accessModes:
+      - ReadWriteOnce
+    resources:
+      requests:
+        storage: 1Gi
+`)
+
+func testCommonFixturesPersistentvolumeclaimsYamlBytes() ([]byte, error) {
+	return _testCommonFixturesPersistentvolumeclaimsYaml, nil
+}
+
+func testCommonFixturesPersistentvolumeclaimsYaml() (*asset, error) {
+	bytes, err := testCommonFixturesPersistentvolumeclaimsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "test/common/fixtures/persistentvolumeclaims.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _testCommonFixturesReplicasetsAppsYaml = []byte(`kind: fixture
 template:
   spec:
@@ -467,6 +535,27 @@ func configEnabletypedirectivesConfigmapsYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configEnabletypedirectivesDaemonsetsAppsYaml = []byte(`apiVersion: core.kubefed.io/v1beta1
+kind: EnableTypeDirective
+metadata:
+  name: daemonsets.apps
+`)
+
+func configEnabletypedirectivesDaemonsetsAppsYamlBytes() ([]byte, error) {
+	return _configEnabletypedirectivesDaemonsetsAppsYaml, nil
+}
+
+func configEnabletypedirectivesDaemonsetsAppsYaml() (*asset, error) {
+	bytes, err := configEnabletypedirectivesDaemonsetsAppsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/enabletypedirectives/daemonsets.apps.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configEnabletypedirectivesDeploymentsAppsYaml = []byte(`apiVersion: core.kubefed.io/v1beta1
 kind: EnableTypeDirective
 metadata:
@@ -551,6 +640,27 @@ func configEnabletypedirectivesNamespacesYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configEnabletypedirectivesPersistentvolumeclaimsYaml = []byte(`apiVersion: core.kubefed.io/v1beta1
+kind: EnableTypeDirective
+metadata:
+  name: persistentvolumeclaims
+`)
+
+func configEnabletypedirectivesPersistentvolumeclaimsYamlBytes() ([]byte, error) {
+	return _configEnabletypedirectivesPersistentvolumeclaimsYaml, nil
+}
+
+func configEnabletypedirectivesPersistentvolumeclaimsYaml() (*asset, error) {
+	bytes, err := configEnabletypedirectivesPersistentvolumeclaimsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/enabletypedirectives/persistentvolumeclaims.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configEnabletypedirectivesReplicasetsAppsYaml = []byte(`apiVersion: core.kubefed.io/v1beta1
 kind: EnableTypeDirective
 metadata:
@@ -689,10 +799,12 @@ func AssetNames() []string {
 var _bindata = map[string]func() (*asset, error){
 	"test/common/fixtures/clusterroles.rbac.authorization.k8s.io.yaml":        testCommonFixturesClusterrolesRbacAuthorizationK8sIoYaml,
 	"test/common/fixtures/configmaps.yaml":                                    testCommonFixturesConfigmapsYaml,
+	"test/common/fixtures/daemonsets.apps.yaml":                               testCommonFixturesDaemonsetsAppsYaml,
 	"test/common/fixtures/deployments.apps.yaml":                              testCommonFixturesDeploymentsAppsYaml,
 	"test/common/fixtures/ingresses.extensions.yaml":                          testCommonFixturesIngressesExtensionsYaml,
 	"test/common/fixtures/jobs.batch.yaml":                                    testCommonFixturesJobsBatchYaml,
 	"test/common/fixtures/namespaces.yaml":                                    testCommonFixturesNamespacesYaml,
+	"test/common/fixtures/persistentvolumeclaims.yaml":                        testCommonFixturesPersistentvolumeclaimsYaml,
 	"test/common/fixtures/replicasets.apps.yaml":                              testCommonFixturesReplicasetsAppsYaml,
 	"test/common/fixtures/secrets.yaml":                                       testCommonFixturesSecretsYaml,
 	"test/common/fixtures/serviceaccounts.yaml":                               testCommonFixturesServiceaccountsYaml,
@@ -700,10 +812,12 @@ var _bindata = map[string]func() (*asset, error){
 	"config/kubefedconfig.yaml":                                               configKubefedconfigYaml,
 	"config/enabletypedirectives/clusterroles.rbac.authorization.k8s.io.yaml": configEnabletypedirectivesClusterrolesRbacAuthorizationK8sIoYaml,
 	"config/enabletypedirectives/configmaps.yaml":                             configEnabletypedirectivesConfigmapsYaml,
+	"config/enabletypedirectives/daemonsets.apps.yaml":                        configEnabletypedirectivesDaemonsetsAppsYaml,
 	"config/enabletypedirectives/deployments.apps.yaml":                       configEnabletypedirectivesDeploymentsAppsYaml,
 	"config/enabletypedirectives/ingresses.extensions.yaml":                   configEnabletypedirectivesIngressesExtensionsYaml,
 	"config/enabletypedirectives/jobs.batch.yaml":                             configEnabletypedirectivesJobsBatchYaml,
 	"config/enabletypedirectives/namespaces.yaml":                             configEnabletypedirectivesNamespacesYaml,
+	"config/enabletypedirectives/persistentvolumeclaims.yaml":                 configEnabletypedirectivesPersistentvolumeclaimsYaml,
 	"config/enabletypedirectives/replicasets.apps.yaml":                       configEnabletypedirectivesReplicasetsAppsYaml,
 	"config/enabletypedirectives/secrets.yaml":                                configEnabletypedirectivesSecretsYaml,
 	"config/enabletypedirectives/serviceaccounts.yaml":                        configEnabletypedirectivesServiceaccountsYaml,
@@ -714,11 +828,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"}
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
@@ -754,15 +870,17 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"config": &bintree{nil, map[string]*bintree{
 		"enabletypedirectives": &bintree{nil, map[string]*bintree{
 			"clusterroles.rbac.authorization.k8s.io.yaml": &bintree{configEnabletypedirectivesClusterrolesRbacAuthorizationK8sIoYaml, map[string]*bintree{}},
-			"configmaps.yaml":                             &bintree{configEnabletypedirectivesConfigmapsYaml, map[string]*bintree{}},
-			"deployments.apps.yaml":                       &bintree{configEnabletypedirectivesDeploymentsAppsYaml, map[string]*bintree{}},
-			"ingresses.extensions.yaml":                   &bintree{configEnabletypedirectivesIngressesExtensionsYaml, map[string]*bintree{}},
-			"jobs.batch.yaml":                             &bintree{configEnabletypedirectivesJobsBatchYaml, map[string]*bintree{}},
-			"namespaces.yaml":                             &bintree{configEnabletypedirectivesNamespacesYaml, map[string]*bintree{}},
-			"replicasets.apps.yaml":                       &bintree{configEnabletypedirectivesReplicasetsAppsYaml, map[string]*bintree{}},
-			"secrets.yaml":                                &bintree{configEnabletypedirectivesSecretsYaml, map[string]*bintree{}},
-			"serviceaccounts.yaml":                        &bintree{configEnabletypedirectivesServiceaccountsYaml, map[string]*bintree{}},
-			"services.yaml":                               &bintree{configEnabletypedirectivesServicesYaml, map[string]*bintree{}},
+			"configmaps.yaml":             &bintree{configEnabletypedirectivesConfigmapsYaml, map[string]*bintree{}},
+			"daemonsets.apps.yaml":        &bintree{configEnabletypedirectivesDaemonsetsAppsYaml, map[string]*bintree{}},
+			"deployments.apps.yaml":       &bintree{configEnabletypedirectivesDeploymentsAppsYaml, map[string]*bintree{}},
+			"ingresses.extensions.yaml":   &bintree{configEnabletypedirectivesIngressesExtensionsYaml, map[string]*bintree{}},
+			"jobs.batch.yaml":             &bintree{configEnabletypedirectivesJobsBatchYaml, map[string]*bintree{}},
+			"namespaces.yaml":             &bintree{configEnabletypedirectivesNamespacesYaml, map[string]*bintree{}},
+			"persistentvolumeclaims.yaml": &bintree{configEnabletypedirectivesPersistentvolumeclaimsYaml, map[string]*bintree{}},
+			"replicasets.apps.yaml":       &bintree{configEnabletypedirectivesReplicasetsAppsYaml, map[string]*bintree{}},
+			"secrets.yaml":                &bintree{configEnabletypedirectivesSecretsYaml, map[string]*bintree{}},
+			"serviceaccounts.yaml":        &bintree{configEnabletypedirectivesServiceaccountsYaml, map[string]*bintree{}},
+			"services.yaml":               &bintree{configEnabletypedirectivesServicesYaml, map[string]*bintree{}},
 		}},
 		"kubefedconfig.yaml": &bintree{configKubefedconfigYaml, map[string]*bintree{}},
 	}},
@@ -770,15 +888,17 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"common": &bintree{nil, map[string]*bintree{
 			"fixtures": &bintree{nil, map[string]*bintree{
 				"clusterroles.rbac.authorization.k8s.io.yaml": &bintree{testCommonFixturesClusterrolesRbacAuthorizationK8sIoYaml, map[string]*bintree{}},
-				"configmaps.yaml":                             &bintree{testCommonFixturesConfigmapsYaml, map[string]*bintree{}},
-				"deployments.apps.yaml":                       &bintree{testCommonFixturesDeploymentsAppsYaml, map[string]*bintree{}},
-				"ingresses.extensions.yaml":                   &bintree{testCommonFixturesIngressesExtensionsYaml, map[string]*bintree{}},
-				"jobs.batch.yaml":                             &bintree{testCommonFixturesJobsBatchYaml, map[string]*bintree{}},
-				"namespaces.yaml":                             &bintree{testCommonFixturesNamespacesYaml, map[string]*bintree{}},
-				"replicasets.apps.yaml":                       &bintree{testCommonFixturesReplicasetsAppsYaml, map[string]*bintree{}},
-				"secrets.yaml":                                &bintree{testCommonFixturesSecretsYaml, map[string]*bintree{}},
-				"serviceaccounts.yaml":                        &bintree{testCommonFixturesServiceaccountsYaml, map[string]*bintree{}},
-				"services.yaml":                               &bintree{testCommonFixturesServicesYaml, map[string]*bintree{}},
+				"configmaps.yaml":             &bintree{testCommonFixturesConfigmapsYaml, map[string]*bintree{}},
+				"daemonsets.apps.yaml":        &bintree{testCommonFixturesDaemonsetsAppsYaml, map[string]*bintree{}},
+				"deployments.apps.yaml":       &bintree{testCommonFixturesDeploymentsAppsYaml, map[string]*bintree{}},
+				"ingresses.extensions.yaml":   &bintree{testCommonFixturesIngressesExtensionsYaml, map[string]*bintree{}},
+				"jobs.batch.yaml":             &bintree{testCommonFixturesJobsBatchYaml, map[string]*bintree{}},
+				"namespaces.yaml":             &bintree{testCommonFixturesNamespacesYaml, map[string]*bintree{}},
+				"persistentvolumeclaims.yaml": &bintree{testCommonFixturesPersistentvolumeclaimsYaml, map[string]*bintree{}},
+				"replicasets.apps.yaml":       &bintree{testCommonFixturesReplicasetsAppsYaml, map[string]*bintree{}},
+				"secrets.yaml":                &bintree{testCommonFixturesSecretsYaml, map[string]*bintree{}},
+				"serviceaccounts.yaml":        &bintree{testCommonFixturesServiceaccountsYaml, map[string]*bintree{}},
+				"services.yaml":               &bintree{testCommonFixturesServicesYaml, map[string]*bintree{}},
 			}},
 		}},
 	}},
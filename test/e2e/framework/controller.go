@@ -110,7 +110,7 @@ func NewClusterControllerFixture(tl common.TestLogger, config *util.ControllerCo
 	f := &ControllerFixture{
 		stopChan: make(chan struct{}),
 	}
-	clusterHealthCheckConfig := &util.ClusterHealthCheckConfig{Period: 1 * time.Second, FailureThreshold: 1}
+	clusterHealthCheckConfig := &util.ClusterHealthCheckConfig{Period: 1 * time.Second, FailureThreshold: 1, EdgeFailureThreshold: 1}
 	err := kubefedcluster.StartClusterController(config, clusterHealthCheckConfig, f.stopChan)
 	if err != nil {
 		tl.Fatalf("Error starting cluster controller: %v", err)
@@ -143,6 +143,14 @@ func (r *testVersionedResource) FederatedName() util.QualifiedName {
 	return r.federatedName
 }
 
+func (r *testVersionedResource) TargetName() util.QualifiedName {
+	return r.federatedName
+}
+
+func (r *testVersionedResource) TargetNameForCluster(clusterName string) util.QualifiedName {
+	return util.QualifiedNameForCluster(clusterName, r.federatedName)
+}
+
 func (r *testVersionedResource) Object() *unstructured.Unstructured {
 	return r.object
 }